@@ -0,0 +1,191 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	notesv1 "notes-service/pkg/proto/notes/v1"
+
+	"google.golang.org/grpc/credentials"
+)
+
+// tokenRefreshSkew за сколько до истечения токен получается заново
+const tokenRefreshSkew = 30 * time.Second
+
+// authServiceURI суффикс URI методов AuthService в GetRequestMetadata: им токен не передается,
+// иначе вход через loginSource вызывал бы сам себя
+var authServiceURI = "/" + notesv1.AuthService_ServiceDesc.ServiceName
+
+// token токен доступа и время его истечения (нулевое - бессрочный)
+type token struct {
+	value     string
+	expiresAt time.Time
+}
+
+// tokenSource получает новый токен доступа
+type tokenSource interface {
+	Token(ctx context.Context) (token, error)
+}
+
+// tokenCredentials передает токен источника в заголовке authorization каждого вызова
+// (credentials.PerRPCCredentials). Токен кэшируется и получается заново незадолго до истечения
+type tokenCredentials struct {
+	source tokenSource
+	secure bool // Передавать токен только по TLS
+
+	mu      sync.Mutex
+	current token
+}
+
+var _ credentials.PerRPCCredentials = (*tokenCredentials)(nil)
+
+// GetRequestMetadata возвращает заголовок authorization для вызова
+func (c *tokenCredentials) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	if len(uri) > 0 && strings.HasSuffix(uri[0], authServiceURI) {
+		return nil, nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.current.value == "" || (!c.current.expiresAt.IsZero() && time.Until(c.current.expiresAt) < tokenRefreshSkew) {
+		next, err := c.source.Token(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get access token: %w", err)
+		}
+		c.current = next
+	}
+	return map[string]string{"authorization": "Bearer " + c.current.value}, nil
+}
+
+// RequireTransportSecurity запрещает передавать токен без TLS, если соединение защищено
+func (c *tokenCredentials) RequireTransportSecurity() bool {
+	return c.secure
+}
+
+// staticSource постоянный токен из AUTH_TOKEN
+type staticSource string
+
+// Token возвращает постоянный токен
+func (s staticSource) Token(context.Context) (token, error) {
+	return token{value: string(s)}, nil
+}
+
+// loginSource получает токены входом по паролю (AuthService.Login) и обновляет их по refresh токену
+// Если refresh токен истек или отозван, выполняется повторный вход
+type loginSource struct {
+	client   notesv1.AuthServiceClient
+	username string
+	password string
+
+	refreshToken string
+	refreshUntil time.Time
+}
+
+// Token возвращает новый access токен
+func (s *loginSource) Token(ctx context.Context) (token, error) {
+	if s.refreshToken != "" && time.Until(s.refreshUntil) > tokenRefreshSkew {
+		tokens, err := s.client.RefreshToken(ctx, &notesv1.RefreshTokenRequest{RefreshToken: s.refreshToken})
+		if err == nil {
+			log.Println("Refreshed access token")
+			return s.save(tokens), nil
+		}
+		log.Printf("Failed to refresh access token, logging in again: %v", err)
+	}
+
+	tokens, err := s.client.Login(ctx, &notesv1.LoginRequest{Username: s.username, Password: s.password})
+	if err != nil {
+		return token{}, fmt.Errorf("login failed: %w", err)
+	}
+	log.Printf("Logged in as %s", tokens.GetUserId())
+	return s.save(tokens), nil
+}
+
+// save запоминает refresh токен и возвращает access токен ответа
+func (s *loginSource) save(tokens *notesv1.AuthTokens) token {
+	s.refreshToken = tokens.GetRefreshToken()
+	s.refreshUntil = tokens.GetRefreshTokenExpiresAt().AsTime()
+	return token{value: tokens.GetAccessToken(), expiresAt: tokens.GetAccessTokenExpiresAt().AsTime()}
+}
+
+// clientCredentialsSource получает токены у сервера авторизации OAuth2 (grant_type=client_credentials)
+type clientCredentialsSource struct {
+	tokenURL     string
+	clientID     string
+	clientSecret string
+	scope        string
+	httpClient   *http.Client
+}
+
+// Token запрашивает новый токен
+func (s *clientCredentialsSource) Token(ctx context.Context) (token, error) {
+	form := url.Values{"grant_type": {"client_credentials"}}
+	if s.scope != "" {
+		form.Set("scope", s.scope)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return token{}, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetBasicAuth(url.QueryEscape(s.clientID), url.QueryEscape(s.clientSecret))
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return token{}, err
+	}
+	defer resp.Body.Close()
+
+	var body struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+		Error       string `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return token{}, fmt.Errorf("invalid token response (status %d): %w", resp.StatusCode, err)
+	}
+	if resp.StatusCode != http.StatusOK || body.AccessToken == "" {
+		return token{}, fmt.Errorf("token request failed: status %d %s", resp.StatusCode, body.Error)
+	}
+
+	log.Println("Received access token from OAuth2 server")
+	result := token{value: body.AccessToken}
+	if body.ExpiresIn > 0 {
+		result.expiresAt = time.Now().Add(time.Duration(body.ExpiresIn) * time.Second)
+	}
+	return result, nil
+}
+
+// newTokenSource выбирает источник токенов по переменным окружения:
+// OAUTH_TOKEN_URL, OAUTH_CLIENT_ID, OAUTH_CLIENT_SECRET и OAUTH_SCOPE - OAuth2 client credentials,
+// AUTH_USERNAME и AUTH_PASSWORD - вход через AuthService.Login, иначе постоянный токен AUTH_TOKEN
+// Клиент AuthService для входа задается после создания соединения (см. loginSource.client)
+func newTokenSource() tokenSource {
+	if tokenURL := os.Getenv("OAUTH_TOKEN_URL"); tokenURL != "" {
+		log.Printf("Using OAuth2 client credentials from %s", tokenURL)
+		return &clientCredentialsSource{
+			tokenURL:     tokenURL,
+			clientID:     os.Getenv("OAUTH_CLIENT_ID"),
+			clientSecret: os.Getenv("OAUTH_CLIENT_SECRET"),
+			scope:        os.Getenv("OAUTH_SCOPE"),
+			httpClient:   &http.Client{Timeout: 10 * time.Second},
+		}
+	}
+	if username := os.Getenv("AUTH_USERNAME"); username != "" {
+		log.Printf("Using password login as %s", username)
+		return &loginSource{username: username, password: os.Getenv("AUTH_PASSWORD")}
+	}
+
+	value := os.Getenv("AUTH_TOKEN")
+	if value == "" {
+		value = defaultToken
+	}
+	return staticSource(value)
+}
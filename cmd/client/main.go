@@ -7,13 +7,15 @@ import (
 	"os"
 	"time"
 
+	"notes-service/internal/compression"
+	"notes-service/internal/tlsconfig"
 	_ "notes-service/pkg/proto/notes/v1" // Явный импорт для регистрации proto типов
 	notesv1 "notes-service/pkg/proto/notes/v1"
 
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
-	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/anypb"
@@ -31,36 +33,39 @@ func main() {
 		address = defaultAddress
 	}
 
-	// Получаем токен авторизации из переменной окружения или используем значение по умолчанию
-	token := os.Getenv("AUTH_TOKEN")
-	if token == "" {
-		token = defaultToken
-	}
+	// Источник токенов авторизации: постоянный токен, вход по паролю или OAuth2 (см. newTokenSource)
+	source := newTokenSource()
+	transport := transportCredentials()
 
 	log.Printf("Connecting to gRPC server at %s...", address)
 
-	// Создаем соединение с сервером
+	// Создаем соединение с сервером: токен добавляется к каждому вызову и обновляется до истечения,
+	// запросы сжимаются алгоритмом из GRPC_COMPRESSION
 	conn, err := grpc.NewClient(
 		address,
-		grpc.WithTransportCredentials(insecure.NewCredentials()), // Для plaintext соединения
+		grpc.WithTransportCredentials(transport),
+		grpc.WithPerRPCCredentials(&tokenCredentials{
+			source: source,
+			secure: transport.Info().SecurityProtocol == "tls",
+		}),
+		grpc.WithDefaultCallOptions(callCompression()...),
 	)
 	if err != nil {
 		log.Fatalf("Failed to create client: %v", err)
 	}
 	defer conn.Close()
+	if login, ok := source.(*loginSource); ok {
+		login.client = notesv1.NewAuthServiceClient(conn)
+	}
 
 	log.Println("Connected successfully!")
 
 	// Создаем клиент для NotesService
 	client := notesv1.NewNotesServiceClient(conn)
 
-	// Создаем контекст с метаданными для авторизации
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	// Добавляем метаданные с токеном авторизации
-	ctx = metadata.AppendToOutgoingContext(ctx, "authorization", fmt.Sprintf("Bearer %s", token))
-
 	// Выбираем, какой тест запустить через переменную окружения или аргумент
 	testType := os.Getenv("TEST_TYPE")
 	if testType == "" && len(os.Args) > 1 {
@@ -77,6 +82,9 @@ func main() {
 	case "upload-empty", "metrics-empty":
 		// Тестируем client-side streaming с пустым стримом
 		testUploadMetricsEmpty(ctx, client)
+	case "stream-metrics", "metrics-windows":
+		// Тестируем bidirectional streaming метрик со статистикой по окнам
+		testStreamMetrics(ctx, client)
 	case "chat", "bidirectional", "bidi":
 		// Тестируем bidirectional streaming - асинхронный чат
 		testChat(ctx, client)
@@ -89,12 +97,51 @@ func main() {
 	default:
 		// По умолчанию тестируем streaming
 		log.Println("No TEST_TYPE specified, testing streaming by default")
-		log.Println("Available test types: streaming, upload/metrics/client-streaming, chat/bidirectional/bidi, error, success")
+		log.Println("Available test types: streaming, upload/metrics/client-streaming, stream-metrics, chat/bidirectional/bidi, error, success")
 		log.Println("Usage: TEST_TYPE=streaming go run . OR go run . streaming")
 		testSubscribeToEvents(ctx, client)
 	}
 }
 
+// callCompression сжимает запросы алгоритмом из переменной окружения GRPC_COMPRESSION:
+// gzip (по умолчанию), zstd или none; сервер отвечает тем же алгоритмом, если он включен в server.compression
+func callCompression() []grpc.CallOption {
+	name := os.Getenv("GRPC_COMPRESSION")
+	switch name {
+	case "none":
+		return nil
+	case "":
+		name = compression.Gzip
+	}
+	if _, err := compression.ParseList([]string{name}); err != nil {
+		log.Fatalf("Invalid GRPC_COMPRESSION: %v", err)
+	}
+	compression.RegisterGRPC(name)
+	return []grpc.CallOption{grpc.UseCompressor(name)}
+}
+
+// transportCredentials возвращает учетные данные подключения к серверу из переменных окружения
+// TLS_CA_FILE (центр сертификата сервера), TLS_CERT_FILE и TLS_KEY_FILE (сертификат клиента для mTLS),
+// TLS_SERVER_NAME (имя в сертификате сервера); без них соединение plaintext
+func transportCredentials() credentials.TransportCredentials {
+	cfg := tlsconfig.Config{
+		CAFile:         os.Getenv("TLS_CA_FILE"),
+		ClientCertFile: os.Getenv("TLS_CERT_FILE"),
+		ClientKeyFile:  os.Getenv("TLS_KEY_FILE"),
+		ServerName:     os.Getenv("TLS_SERVER_NAME"),
+	}
+	if cfg == (tlsconfig.Config{}) {
+		return insecure.NewCredentials()
+	}
+
+	tlsCfg, err := cfg.Client()
+	if err != nil {
+		log.Fatalf("Failed to load TLS settings: %v", err)
+	}
+	log.Printf("Using TLS (client certificate: %v)", cfg.ClientCertFile != "")
+	return credentials.NewTLS(tlsCfg)
+}
+
 // testErrorHandling тестирует обработку детализированных ошибок
 func testErrorHandling(ctx context.Context, client notesv1.NotesServiceClient) {
 	log.Println("\n=== Testing Rich Error Handling ===")
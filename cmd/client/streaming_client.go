@@ -3,12 +3,11 @@ package main
 import (
 	"context"
 	"fmt"
-	"io"
 	"log"
-	"sync"
 	"time"
 
 	notesv1 "notes-service/pkg/proto/notes/v1"
+	"notes-service/pkg/streamutil"
 
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
@@ -36,16 +35,7 @@ func testSubscribeToEvents(ctx context.Context, client notesv1.NotesServiceClien
 	noteCreatedCount := 0
 
 	// Читаем сообщения из стрима
-	for {
-		resp, err := stream.Recv()
-		if err == io.EOF {
-			log.Println("\n📡 Stream closed by server (EOF)")
-			break
-		}
-		if err != nil {
-			log.Fatalf("Error receiving event: %v", err)
-		}
-
+	err = streamutil.RecvLoop(streamCtx, stream, func(resp *notesv1.EventResponse) error {
 		eventCount++
 
 		// Обрабатываем разные типы событий
@@ -83,10 +73,45 @@ func testSubscribeToEvents(ctx context.Context, client notesv1.NotesServiceClien
 				log.Printf("   Unknown payload type")
 			}
 
+		case *notesv1.EventResponse_NoteReminderDue:
+			log.Printf("\n⏰ Reminder for note %s: %s", event.NoteReminderDue.GetNote().GetId(), event.NoteReminderDue.GetNote().GetTitle())
+			if event.NoteReminderDue.RemindAt != nil {
+				log.Printf("   Remind at: %v", event.NoteReminderDue.RemindAt.AsTime())
+			}
+
+		case *notesv1.EventResponse_NoteUpdated:
+			log.Printf("\n✏️  Note updated: %s (%s)", event.NoteUpdated.GetNote().GetId(), event.NoteUpdated.GetNote().GetTitle())
+
+		case *notesv1.EventResponse_NoteArchived:
+			log.Printf("\n🗄️  Note archived: %s (%s)", event.NoteArchived.GetNote().GetId(), event.NoteArchived.GetNote().GetTitle())
+
+		case *notesv1.EventResponse_NoteDeleted:
+			log.Printf("\n🗑️  Note deleted: %s", event.NoteDeleted.GetNoteId())
+
+		case *notesv1.EventResponse_NoteShared:
+			share := event.NoteShared.GetShare()
+			log.Printf("\n🤝 Note %s shared with %s (%s)", share.GetNoteId(), share.GetUserId(), share.GetPermission())
+
+		case *notesv1.EventResponse_ExportCompleted:
+			op := event.ExportCompleted.GetOperation()
+			if op.GetError() != nil {
+				log.Printf("\n📦 Export %s failed: %s", op.GetId(), op.GetError().GetMessage())
+			} else {
+				log.Printf("\n📦 Export %s completed: %d notes -> %s", op.GetId(), op.GetExportedNotes(), op.GetLocation())
+			}
+
+		case *notesv1.EventResponse_GoAway:
+			log.Printf("\n👋 Server closed the stream: %s (resume_token=%s)", event.GoAway.GetReason(), event.GoAway.GetResumeToken())
+
 		default:
 			log.Printf("⚠️  Unknown event type: %T", event)
 		}
+		return nil
+	})
+	if err != nil {
+		log.Fatalf("Error receiving event: %v", err)
 	}
+	log.Println("\n📡 Stream closed by server (EOF)")
 
 	log.Printf("\n=== Stream Statistics ===")
 	log.Printf("Total events received: %d", eventCount)
@@ -112,7 +137,7 @@ func testUploadMetrics(ctx context.Context, client notesv1.NotesServiceClient) {
 	for i, value := range metrics {
 		metric := &notesv1.MetricRequest{
 			Value: value,
-			Name:  fmt.Sprintf("metric_%d", i+1),
+			Name:  fmt.Sprintf("metric_%d", i%2+1),
 		}
 
 		if err := stream.Send(metric); err != nil {
@@ -137,6 +162,10 @@ func testUploadMetrics(ctx context.Context, client notesv1.NotesServiceClient) {
 	log.Printf("   Sum:     %.2f", summary.Sum)
 	log.Printf("   Average: %.2f", summary.Average)
 	log.Printf("   Count:   %d", summary.Count)
+	for _, m := range summary.GetMetrics() {
+		log.Printf("   %s: count=%d, sum=%.2f, avg=%.2f, min=%.2f, max=%.2f, p95=%.2f",
+			m.GetName(), m.GetCount(), m.GetSum(), m.GetAverage(), m.GetMin(), m.GetMax(), m.GetP95())
+	}
 
 	// Проверяем корректность вычислений
 	expectedSum := 10.5 + 20.3 + 15.7 + 30.2 + 25.1
@@ -150,6 +179,62 @@ func testUploadMetrics(ctx context.Context, client notesv1.NotesServiceClient) {
 	}
 }
 
+// testStreamMetrics тестирует bidirectional streaming метрик со статистикой по окнам
+func testStreamMetrics(ctx context.Context, client notesv1.NotesServiceClient) {
+	log.Println("\n=== Testing Bidirectional Streaming: StreamMetrics ===")
+
+	stream, err := client.StreamMetrics(ctx)
+	if err != nil {
+		log.Fatalf("Failed to create stream: %v", err)
+	}
+
+	group, groupCtx := streamutil.WithContext(ctx)
+
+	// Статистика окон приходит, пока клиент отправляет метрики
+	group.Go(func() error {
+		return streamutil.RecvLoop(groupCtx, stream, func(resp *notesv1.StreamMetricsResponse) error {
+			kind := "Window"
+			if resp.GetFinal() {
+				kind = "Final"
+			}
+			log.Printf("📊 %s summary %s - %s: count=%d, sum=%.2f", kind,
+				resp.GetWindowStart().AsTime().Format(time.TimeOnly), resp.GetWindowEnd().AsTime().Format(time.TimeOnly),
+				resp.GetSummary().GetCount(), resp.GetSummary().GetSum())
+			for _, m := range resp.GetSummary().GetMetrics() {
+				log.Printf("   %s: count=%d, avg=%.2f, min=%.2f, max=%.2f, p95=%.2f",
+					m.GetName(), m.GetCount(), m.GetAverage(), m.GetMin(), m.GetMax(), m.GetP95())
+			}
+			return nil
+		})
+	})
+
+	// После закрытия отправки сервер присылает итоговую статистику и завершает стрим
+	group.Go(func() error {
+		if err := streamutil.SendWithTimeout(groupCtx, stream, &notesv1.StreamMetricsRequest{
+			Payload: &notesv1.StreamMetricsRequest_Options{Options: &notesv1.StreamMetricsOptions{WindowSeconds: 1}},
+		}, sendTimeout); err != nil {
+			return fmt.Errorf("failed to send options: %w", err)
+		}
+
+		for i := range 12 {
+			metric := &notesv1.MetricRequest{Name: fmt.Sprintf("metric_%d", i%3+1), Value: float64(i)}
+			if err := streamutil.SendWithTimeout(groupCtx, stream, &notesv1.StreamMetricsRequest{
+				Payload: &notesv1.StreamMetricsRequest_Metric{Metric: metric},
+			}, sendTimeout); err != nil {
+				return fmt.Errorf("failed to send metric: %w", err)
+			}
+			log.Printf("📤 Sent metric: %s = %.2f", metric.Name, metric.Value)
+			time.Sleep(250 * time.Millisecond)
+		}
+		return stream.CloseSend()
+	})
+
+	if err := group.Wait(); err != nil {
+		log.Fatalf("StreamMetrics failed: %v", err)
+	}
+	log.Println("\n✅ StreamMetrics completed")
+}
+
 // testUploadMetricsEmpty тестирует client-side streaming с пустым стримом
 func testUploadMetricsEmpty(ctx context.Context, client notesv1.NotesServiceClient) {
 	log.Println("\n=== Testing Client-Side Streaming: UploadMetrics (Empty Stream) ===")
@@ -183,7 +268,13 @@ func testUploadMetricsEmpty(ctx context.Context, client notesv1.NotesServiceClie
 	}
 }
 
-// testChat тестирует bidirectional streaming - асинхронный чат
+// sendTimeout время ожидания отправки одного сообщения стрима
+const sendTimeout = 10 * time.Second
+
+// chatRoom комната, в которую входит testChat
+const chatRoom = "client-demo"
+
+// testChat тестирует bidirectional streaming - чат в комнате
 func testChat(ctx context.Context, client notesv1.NotesServiceClient) {
 	log.Println("\n=== Testing Bidirectional Streaming: Chat ===")
 	log.Println("Starting chat...")
@@ -200,8 +291,7 @@ func testChat(ctx context.Context, client notesv1.NotesServiceClient) {
 
 	log.Println("✅ Successfully created chat stream")
 
-	errChan := make(chan error, 2)
-	var wg sync.WaitGroup
+	group, groupCtx := streamutil.WithContext(chatCtx)
 
 	receivedCount := 0
 	sentCount := 0
@@ -209,20 +299,8 @@ func testChat(ctx context.Context, client notesv1.NotesServiceClient) {
 	notificationCount := 0
 
 	// Горутина для чтения сообщений от сервера
-	wg.Add(1)
-	go func() {
-		defer wg.Done()
-		for {
-			msg, err := stream.Recv()
-			if err == io.EOF {
-				log.Println("📡 Server closed stream (EOF)")
-				return
-			}
-			if err != nil {
-				errChan <- fmt.Errorf("error receiving message: %w", err)
-				return
-			}
-
+	group.Go(func() error {
+		err := streamutil.RecvLoop(groupCtx, stream, func(msg *notesv1.ChatMessage) error {
 			receivedCount++
 
 			correlationID := msg.GetCorrelationId()
@@ -258,31 +336,51 @@ func testChat(ctx context.Context, client notesv1.NotesServiceClient) {
 				// Обработка ошибки без разрыва соединения - продолжаем работу
 				// Можно добавить логику обработки конкретных типов ошибок
 
+			case *notesv1.ChatMessage_JoinRoom:
+				log.Printf("🚪 Joined room %s: correlation_id=%s, participants=%v", msg.GetRoomId(), correlationID, content.JoinRoom.GetParticipants())
+
+			case *notesv1.ChatMessage_LeaveRoom:
+				log.Printf("🚪 Left room %s: correlation_id=%s", msg.GetRoomId(), correlationID)
+
+			case *notesv1.ChatMessage_PresenceUpdate:
+				log.Printf("👥 Presence in room %s: user_id=%s, state=%v",
+					msg.GetRoomId(), content.PresenceUpdate.GetUserId(), content.PresenceUpdate.GetState())
+
+			case *notesv1.ChatMessage_TypingIndicator:
+				log.Printf("⌨️ Typing in room %s: user_id=%s, typing=%v",
+					msg.GetRoomId(), content.TypingIndicator.GetUserId(), content.TypingIndicator.GetTyping())
+
 			case nil:
 				// Content не установлен
 				log.Printf("⚠️ Received message without content: correlation_id=%s", correlationID)
 			}
-
-			// Проверка отмены контекста
-			select {
-			case <-chatCtx.Done():
-				log.Println("Context cancelled in receive goroutine")
-				return
-			default:
-			}
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("error receiving message: %w", err)
 		}
-	}()
+		log.Println("📡 Server closed stream (EOF)")
+		return nil
+	})
 
 	// Горутина для отправки сообщений на сервер
-	wg.Add(1)
-	go func() {
-		defer wg.Done()
+	group.Go(func() error {
+		// Сообщения доставляются только участникам комнаты, поэтому сначала входим в нее
+		if err := streamutil.SendWithTimeout(groupCtx, stream, &notesv1.ChatMessage{
+			CorrelationId: "client-join",
+			RoomId:        chatRoom,
+			Content:       &notesv1.ChatMessage_JoinRoom{JoinRoom: &notesv1.ChatJoinRoom{}},
+		}, sendTimeout); err != nil {
+			return fmt.Errorf("error joining room: %w", err)
+		}
+
 		// Включаем одно пустое сообщение для тестирования валидации ошибок
 		messages := []string{"Hello", "How are you?", "", "Test message"}
 		for i, text := range messages {
 			correlationID := fmt.Sprintf("client-msg-%d", i+1)
 			msg := &notesv1.ChatMessage{
 				CorrelationId: correlationID,
+				RoomId:        chatRoom,
 				Content: &notesv1.ChatMessage_TextMessage{
 					TextMessage: &notesv1.ChatTextMessage{
 						Text:      text,
@@ -291,9 +389,8 @@ func testChat(ctx context.Context, client notesv1.NotesServiceClient) {
 				},
 			}
 
-			if err := stream.Send(msg); err != nil {
-				errChan <- fmt.Errorf("error sending message: %w", err)
-				return
+			if err := streamutil.SendWithTimeout(groupCtx, stream, msg, sendTimeout); err != nil {
+				return fmt.Errorf("error sending message: %w", err)
 			}
 
 			sentCount++
@@ -309,10 +406,8 @@ func testChat(ctx context.Context, client notesv1.NotesServiceClient) {
 			time.Sleep(2 * time.Second)
 
 			// Проверка отмены контекста
-			select {
-			case <-chatCtx.Done():
-				return
-			default:
+			if groupCtx.Err() != nil {
+				return nil
 			}
 		}
 
@@ -322,20 +417,14 @@ func testChat(ctx context.Context, client notesv1.NotesServiceClient) {
 
 		// Закрываем отправку
 		if err := stream.CloseSend(); err != nil {
-			errChan <- fmt.Errorf("error closing send stream: %w", err)
-			return
+			return fmt.Errorf("error closing send stream: %w", err)
 		}
 		log.Println("📤 Closed client send stream")
-	}()
-
-	// Ожидание завершения горутин
-	go func() {
-		wg.Wait()
-		close(errChan)
-	}()
+		return nil
+	})
 
-	// Обработка ошибок
-	if err, ok := <-errChan; ok {
+	// Ожидание завершения горутин и обработка ошибок
+	if err := group.Wait(); err != nil {
 		log.Printf("❌ Chat error: %v", err)
 	} else {
 		log.Println("\n=== Chat Statistics ===")
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"notes-service/internal/e2e"
+)
+
+// Smoke test одной командой: запускает сервер с временной конфигурацией на свободных портах,
+// проверяет REST, gRPC, WebSocket и streaming методы и завершается с кодом 1 при ошибке
+//
+// Использование: go run ./cmd/e2e [-timeout 1m] [-v]
+func main() {
+	timeout := flag.Duration("timeout", time.Minute, "Максимальное время выполнения сценария")
+	verbose := flag.Bool("v", false, "Выводить логи сервера")
+	flag.Parse()
+
+	// Логи сервера скрыты, чтобы результат шагов не терялся среди них
+	if !*verbose {
+		log.SetOutput(io.Discard)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	ctx, cancel := context.WithTimeout(ctx, *timeout)
+	defer cancel()
+
+	if err := e2e.Run(ctx, os.Stdout); err != nil {
+		fmt.Fprintf(os.Stderr, "E2E failed: %v\n", err)
+		cancel()
+		os.Exit(1)
+	}
+	fmt.Println("E2E passed")
+}
@@ -0,0 +1,207 @@
+// replay воспроизводит запросы, записанные сервером (секция recorder конфигурации),
+// на другом экземпляре сервера с сохранением интервалов между запросами
+//
+//	go run ./cmd/replay -dir ./data/recordings -addr localhost:50052 -speed 2
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"notes-service/internal/recorder"
+	_ "notes-service/pkg/proto/notes/v1" // Регистрация proto типов для разбора записей
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+func main() {
+	dir := flag.String("dir", "./data/recordings", "каталог с записанными запросами")
+	addr := flag.String("addr", "localhost:50051", "адрес gRPC сервера для воспроизведения")
+	speed := flag.Float64("speed", 1, "скорость воспроизведения относительно записи (0 - без пауз)")
+	concurrency := flag.Int("concurrency", 16, "максимальное количество одновременных запросов")
+	token := flag.String("token", "my-secret-token", "токен авторизации по умолчанию")
+	tokens := flag.String("tokens", "", "токены пользователей записей: user=token,user2=token2")
+	timeout := flag.Duration("timeout", 10*time.Second, "таймаут одного запроса")
+	flag.Parse()
+
+	records, err := recorder.ReadDir(*dir)
+	if err != nil {
+		log.Fatalf("Failed to read recordings: %v", err)
+	}
+	if len(records) == 0 {
+		log.Fatalf("No recorded requests in %s", *dir)
+	}
+
+	userTokens, err := parseTokens(*tokens)
+	if err != nil {
+		log.Fatalf("Invalid -tokens: %v", err)
+	}
+
+	conn, err := grpc.NewClient(*addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		log.Fatalf("Failed to create client: %v", err)
+	}
+	defer conn.Close()
+
+	log.Printf("Replaying %d requests to %s (speed=%g)", len(records), *addr, *speed)
+
+	var (
+		mu        sync.Mutex
+		codeCount = make(map[codes.Code]int)
+		latencies = make([]time.Duration, 0, len(records))
+		wg        sync.WaitGroup
+		sem       = make(chan struct{}, max(*concurrency, 1))
+	)
+
+	start := time.Now()
+	first := records[0].Time
+	for _, record := range records {
+		// Сохраняем интервалы между запросами с поправкой на скорость
+		if *speed > 0 {
+			offset := time.Duration(float64(record.Time.Sub(first)) / *speed)
+			time.Sleep(time.Until(start.Add(offset)))
+		}
+
+		sem <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			authToken := *token
+			if userToken, ok := userTokens[record.User]; ok {
+				authToken = userToken
+			}
+
+			elapsed, err := replay(conn, record, authToken, *timeout)
+
+			mu.Lock()
+			defer mu.Unlock()
+			codeCount[status.Code(err)]++
+			if err == nil {
+				latencies = append(latencies, elapsed)
+			} else if status.Code(err) == codes.Unknown {
+				log.Printf("Request %s failed: %v", record.Method, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	printSummary(len(records), time.Since(start), codeCount, latencies)
+}
+
+// replay отправляет записанный запрос и возвращает время его выполнения
+func replay(conn *grpc.ClientConn, record recorder.Record, token string, timeout time.Duration) (time.Duration, error) {
+	method, err := findMethod(record.Method)
+	if err != nil {
+		return 0, err
+	}
+
+	req, err := newMessage(method.Input())
+	if err != nil {
+		return 0, err
+	}
+	if err := protojson.Unmarshal(record.Request, req); err != nil {
+		return 0, fmt.Errorf("invalid request: %w", err)
+	}
+	resp, err := newMessage(method.Output())
+	if err != nil {
+		return 0, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	ctx = metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+token)
+
+	started := time.Now()
+	err = conn.Invoke(ctx, record.Method, req, resp)
+	return time.Since(started), err
+}
+
+// findMethod находит дескриптор метода по полному имени вида /notes.v1.NotesService/CreateNote
+func findMethod(fullMethod string) (protoreflect.MethodDescriptor, error) {
+	service, method, ok := strings.Cut(strings.TrimPrefix(fullMethod, "/"), "/")
+	if !ok {
+		return nil, fmt.Errorf("invalid method %q", fullMethod)
+	}
+
+	desc, err := protoregistry.GlobalFiles.FindDescriptorByName(protoreflect.FullName(service))
+	if err != nil {
+		return nil, fmt.Errorf("unknown service %s: %w", service, err)
+	}
+	serviceDesc, ok := desc.(protoreflect.ServiceDescriptor)
+	if !ok {
+		return nil, fmt.Errorf("%s is not a service", service)
+	}
+
+	methodDesc := serviceDesc.Methods().ByName(protoreflect.Name(method))
+	if methodDesc == nil {
+		return nil, fmt.Errorf("unknown method %s", fullMethod)
+	}
+	return methodDesc, nil
+}
+
+func newMessage(desc protoreflect.MessageDescriptor) (proto.Message, error) {
+	messageType, err := protoregistry.GlobalTypes.FindMessageByName(desc.FullName())
+	if err != nil {
+		return nil, err
+	}
+	return messageType.New().Interface(), nil
+}
+
+// parseTokens разбирает соответствие пользователей токенам вида user=token,user2=token2
+func parseTokens(value string) (map[string]string, error) {
+	tokens := make(map[string]string)
+	if value == "" {
+		return tokens, nil
+	}
+
+	for _, pair := range strings.Split(value, ",") {
+		user, token, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if !ok || user == "" || token == "" {
+			return nil, fmt.Errorf("expected user=token, got %q", pair)
+		}
+		tokens[user] = token
+	}
+	return tokens, nil
+}
+
+// printSummary выводит количество ответов по кодам и задержки успешных запросов
+func printSummary(total int, elapsed time.Duration, codeCount map[codes.Code]int, latencies []time.Duration) {
+	log.Printf("Replayed %d requests in %v", total, elapsed.Round(time.Millisecond))
+
+	codeList := make([]codes.Code, 0, len(codeCount))
+	for code := range codeCount {
+		codeList = append(codeList, code)
+	}
+	slices.Sort(codeList)
+	for _, code := range codeList {
+		log.Printf("  %-20s %d", code, codeCount[code])
+	}
+
+	if len(latencies) == 0 {
+		return
+	}
+	slices.Sort(latencies)
+	percentile := func(p float64) time.Duration {
+		return latencies[int(float64(len(latencies)-1)*p)]
+	}
+	log.Printf("Latency: p50=%v p95=%v p99=%v max=%v",
+		percentile(0.5), percentile(0.95), percentile(0.99), latencies[len(latencies)-1])
+}
@@ -2,31 +2,73 @@ package main
 
 import (
 	"embed"
+	"fmt"
 	"log"
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	"notes-service/internal/config"
+	"notes-service/internal/repository"
+	"notes-service/internal/repository/memory"
 	"notes-service/internal/server"
-)
 
-const configFile = "config.yml"
+	"github.com/spf13/pflag"
+)
 
 //go:embed swagger-specs/*
 var swaggerSpecs embed.FS
 
+// Флаги командной строки
+var (
+	configFile = pflag.String("config", "config.yml", "Путь к файлу конфигурации")
+	selfTest   = pflag.Bool("selftest", false, "Проверить сервер после запуска и завершиться: код 0 - проверка прошла, 1 - нет")
+)
+
+// Флаги, переопределяющие настройки конфигурации; заданные флаги важнее переменных окружения и config.yml
+func init() {
+	pflag.Int("grpc-port", 0, "Порт gRPC сервера (server.port_grpc)")
+	pflag.Int("http-port", 0, "Порт HTTP Gateway (server.port_http)")
+	pflag.String("log-level", "", "Уровень логов запросов: debug, info, warn или error (logger.level)")
+	pflag.String("storage-driver", "", "Хранилище заметок: memory (storage.driver)")
+}
+
+// configFlags ключи настроек, к которым привязаны флаги (имя флага - ключ)
+var configFlags = map[string]string{
+	"grpc-port":      "server.port_grpc",
+	"http-port":      "server.port_http",
+	"log-level":      "logger.level",
+	"storage-driver": "storage.driver",
+}
+
 func main() {
-	// Загружаем конфигурацию из файла
-	appConfig, err := config.InitConfig[config.Config](configFile)
+	pflag.Parse()
+
+	// Загружаем конфигурацию из файла; флаги привязаны к настройкам через viper.BindPFlag
+	opts := make([]config.Option, 0, len(configFlags))
+	for name, key := range configFlags {
+		opts = append(opts, config.WithFlag(key, pflag.Lookup(name)))
+	}
+	appConfig, err := config.InitConfig[config.Config](*configFile, opts...)
 	if err != nil {
 		log.Fatalf("Error initializing config: %v", err)
 	}
 
 	log.Printf("Starting Notes Service")
 
+	noteRepo, err := newNoteRepository(appConfig.Storage)
+	if err != nil {
+		log.Fatalf("Failed to create note repository: %v", err)
+	}
+
 	// Создаем и инициализируем сервер
-	srv, err := server.NewServer(appConfig, swaggerSpecs)
+	// Production компоненты задаются явно; при встраивании сервера и в тестах их можно заменить
+	// Шина событий создается по секции events конфигурации (в памяти процесса или NATS)
+	srv, err := server.NewServer(appConfig, swaggerSpecs,
+		server.WithRepository(noteRepo),
+		server.WithClock(time.Now),
+	)
 	if err != nil {
 		log.Fatalf("Failed to create server: %v", err)
 	}
@@ -43,16 +85,54 @@ func main() {
 	// Запускаем сервер (gRPC и HTTP Gateway) и получаем канал ошибок
 	errChan := srv.Start()
 
+	// Изменения config.yml применяются без перезапуска (server.watch_config), SIGHUP перечитывает файл сразу
+	watcher := config.NewWatcher(*configFile, appConfig, opts...)
+	watcher.OnChange(srv)
+	if appConfig.Server.WatchConfig {
+		if err := watcher.Watch(srv.Ctx); err != nil {
+			log.Printf("⚠️  Config changes are not watched: %v", err)
+		}
+	}
+	hupChan := make(chan os.Signal, 1)
+	signal.Notify(hupChan, syscall.SIGHUP)
+
 	// Канал для graceful shutdown
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM, syscall.SIGINT)
 
-	// Ожидание сигнала завершения или ошибки
-	select {
-	case err := <-errChan:
-		log.Fatalf("Server error: %v", err)
-	case sig := <-sigChan:
-		log.Printf("Received signal: %v", sig)
+	// Проверка сервера через его порты: с --selftest сервер завершается с ее результатом,
+	// с selftest.on_startup продолжает работу, если проверка прошла
+	var selfTestDone chan error
+	if *selfTest || appConfig.SelfTest != nil && appConfig.SelfTest.OnStartup {
+		selfTestDone = make(chan error, 1)
+		go func() { selfTestDone <- srv.SelfTest(srv.Ctx, os.Stdout) }()
+	}
+
+	// Ожидание сигнала завершения, ошибки или результата проверки
+	exitCode := 0
+	for running := true; running; {
+		select {
+		case err := <-errChan:
+			log.Fatalf("Server error: %v", err)
+		case sig := <-sigChan:
+			log.Printf("Received signal: %v", sig)
+			running = false
+		case <-hupChan:
+			log.Printf("Received SIGHUP, reloading %s", *configFile)
+			if err := watcher.Reload(); err != nil {
+				log.Printf("[Config] Failed to apply %s: %v", *configFile, err)
+			}
+		case err := <-selfTestDone:
+			selfTestDone = nil
+			if err != nil {
+				log.Printf("Self-test failed: %v", err)
+				exitCode = 1
+				running = false
+				break
+			}
+			log.Println("Self-test passed")
+			running = !*selfTest
+		}
 	}
 
 	// Выполняем graceful shutdown
@@ -61,4 +141,22 @@ func main() {
 	}
 
 	log.Println("Notes Service stopped")
+	if exitCode != 0 {
+		srv.Cancel()
+		os.Exit(exitCode)
+	}
+}
+
+// newNoteRepository создает хранилище заметок по storage.driver
+func newNoteRepository(cfg *config.ConfigStorage) (repository.NoteRepository, error) {
+	driver := ""
+	if cfg != nil {
+		driver = cfg.Driver
+	}
+	switch driver {
+	case "", "memory":
+		return memory.NewRepository(), nil
+	default:
+		return nil, fmt.Errorf("unknown storage.driver %q (expected memory)", driver)
+	}
 }
@@ -0,0 +1,39 @@
+// specmeta записывает метаданные OpenAPI спецификации (время генерации и хэш proto)
+// рядом с notes.swagger.json. Запускается после генерации кода (task generate:gateway),
+// чтобы сервер мог обнаружить устаревшую встроенную спецификацию
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"log"
+	"os"
+	"time"
+
+	"notes-service/internal/api/swagger"
+	notesv1 "notes-service/pkg/proto/notes/v1"
+)
+
+func main() {
+	out := flag.String("out", "pkg/api/notes/v1/"+swagger.MetaFileName, "путь к файлу метаданных")
+	flag.Parse()
+
+	protoHash, err := swagger.ProtoHash(notesv1.File_proto_notes_v1_notes_proto)
+	if err != nil {
+		log.Fatalf("Failed to hash proto descriptors: %v", err)
+	}
+
+	data, err := json.MarshalIndent(swagger.SpecMeta{
+		GeneratedAt: time.Now().UTC().Truncate(time.Second),
+		ProtoHash:   protoHash,
+	}, "", "  ")
+	if err != nil {
+		log.Fatalf("Failed to encode spec metadata: %v", err)
+	}
+
+	if err := os.WriteFile(*out, append(data, '\n'), 0o644); err != nil {
+		log.Fatalf("Failed to write %s: %v", *out, err)
+	}
+
+	log.Printf("Wrote %s (%s)", *out, protoHash)
+}
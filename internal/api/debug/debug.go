@@ -0,0 +1,180 @@
+// Package debug реализует отладочный порт сервера для операторов: профили pprof, дамп стеков
+// горутин, метрики среды выполнения Go (runtime/metrics) и gRPC сервис channelz с состоянием
+// каналов, серверов и сокетов gRPC. Порт включается отдельно (секция debug) и не требует токена,
+// поэтому не должен быть доступен снаружи
+package debug
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"net/http/pprof"
+	"runtime/metrics"
+	rpprof "runtime/pprof"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/admin"
+	"google.golang.org/grpc/reflection"
+)
+
+// readHeaderTimeout время чтения заголовков запроса к отладочному порту
+// Ответы pprof (profile, trace) пишутся долго, поэтому общего таймаута записи нет
+const readHeaderTimeout = 10 * time.Second
+
+// index описание отладочных эндпоинтов для GET /
+const index = `Debug endpoints:
+  /debug/pprof/            Go pprof profiles (heap, goroutine, profile?seconds=30, trace?seconds=5, ...)
+  /debug/goroutines        Stack traces of all goroutines
+  /debug/runtime           Go runtime metrics (runtime/metrics) as JSON
+  grpc.channelz.v1.Channelz  gRPC channelz over plaintext HTTP/2 on this port (grpcurl -plaintext)
+`
+
+// Server отладочный HTTP сервер; gRPC запросы (HTTP/2 без TLS) обслуживаются channelz
+type Server struct {
+	http *http.Server
+	grpc *grpc.Server
+}
+
+// NewServer создает отладочный сервер на адресе addr, например localhost:6060
+func NewServer(addr string) (*Server, error) {
+	grpcServer := grpc.NewServer()
+	if _, err := admin.Register(grpcServer); err != nil {
+		return nil, fmt.Errorf("failed to register channelz service: %w", err)
+	}
+	reflection.Register(grpcServer)
+
+	// grpcurl и другие gRPC клиенты подключаются по HTTP/2 без TLS (prior knowledge)
+	var protocols http.Protocols
+	protocols.SetHTTP1(true)
+	protocols.SetUnencryptedHTTP2(true)
+
+	return &Server{
+		http: &http.Server{
+			Addr:              addr,
+			Handler:           newHandler(grpcServer),
+			Protocols:         &protocols,
+			ReadHeaderTimeout: readHeaderTimeout,
+		},
+		grpc: grpcServer,
+	}, nil
+}
+
+// Addr возвращает адрес отладочного сервера
+func (s *Server) Addr() string {
+	return s.http.Addr
+}
+
+// ListenAndServe принимает подключения, пока сервер не остановлен Close
+func (s *Server) ListenAndServe() error {
+	if err := s.http.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// Close закрывает порт и все подключения, в том числе незавершенные профили
+func (s *Server) Close() error {
+	s.grpc.Stop()
+	return s.http.Close()
+}
+
+// newHandler направляет gRPC запросы в grpcServer, остальные - отладочным эндпоинтам
+func newHandler(grpcServer *grpc.Server) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /{$}", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		fmt.Fprint(w, index)
+	})
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	mux.HandleFunc("GET /debug/goroutines", goroutines)
+	mux.HandleFunc("GET /debug/runtime", runtimeMetrics)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.ProtoMajor == 2 && strings.HasPrefix(r.Header.Get("Content-Type"), "application/grpc") {
+			grpcServer.ServeHTTP(w, r)
+			return
+		}
+		mux.ServeHTTP(w, r)
+	})
+}
+
+// goroutines пишет стеки всех горутин с причиной и временем ожидания, как паника
+// Помогает найти зависшие стримы: обработчик стрима виден по имени метода в стеке
+func goroutines(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	_ = rpprof.Lookup("goroutine").WriteTo(w, 2)
+}
+
+// histogram сводка гистограммы runtime/metrics: количество значений и границы непустых корзин
+type histogram struct {
+	Count uint64  `json:"count"`
+	Min   float64 `json:"min"`
+	Max   float64 `json:"max"`
+}
+
+// runtimeMetrics возвращает все метрики среды выполнения Go (память, сборщик мусора, планировщик)
+// объектом "имя метрики" -> значение; гистограммы сводятся к количеству и диапазону значений
+func runtimeMetrics(w http.ResponseWriter, _ *http.Request) {
+	descriptions := metrics.All()
+	samples := make([]metrics.Sample, len(descriptions))
+	for i, description := range descriptions {
+		samples[i].Name = description.Name
+	}
+	metrics.Read(samples)
+
+	values := make(map[string]any, len(samples))
+	for _, sample := range samples {
+		switch sample.Value.Kind() {
+		case metrics.KindUint64:
+			values[sample.Name] = sample.Value.Uint64()
+		case metrics.KindFloat64:
+			values[sample.Name] = sample.Value.Float64()
+		case metrics.KindFloat64Histogram:
+			values[sample.Name] = summarize(sample.Value.Float64Histogram())
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	_ = encoder.Encode(values)
+}
+
+// summarize возвращает количество значений гистограммы и границы ее непустых корзин
+// Бесконечные границы крайних корзин заменяются соседними, чтобы значение было представимо в JSON
+func summarize(h *metrics.Float64Histogram) histogram {
+	var result histogram
+	first, last := -1, -1
+	for i, count := range h.Counts {
+		if count == 0 {
+			continue
+		}
+		result.Count += count
+		if first < 0 {
+			first = i
+		}
+		last = i
+	}
+	if first < 0 {
+		return result
+	}
+	result.Min = finite(h.Buckets[first], h.Buckets[first+1])
+	result.Max = finite(h.Buckets[last+1], h.Buckets[last])
+	return result
+}
+
+// finite возвращает bound или fallback, если bound бесконечна
+func finite(bound, fallback float64) float64 {
+	if !math.IsInf(bound, 0) {
+		return bound
+	}
+	return fallback
+}
@@ -0,0 +1,77 @@
+package debug
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	channelzpb "google.golang.org/grpc/channelz/grpc_channelz_v1"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+func TestServer(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	server, err := NewServer(listener.Addr().String())
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	go func() { _ = server.http.Serve(listener) }()
+	t.Cleanup(func() { _ = server.Close() })
+	baseURL := "http://" + listener.Addr().String()
+
+	body := get(t, baseURL+"/debug/goroutines")
+	if !strings.Contains(body, "goroutine ") || !strings.Contains(body, "TestServer") {
+		t.Errorf("Expected stack traces with the test goroutine, got:\n%.500s", body)
+	}
+
+	var runtime map[string]any
+	if err := json.Unmarshal([]byte(get(t, baseURL+"/debug/runtime")), &runtime); err != nil {
+		t.Fatalf("Expected JSON runtime metrics, got: %v", err)
+	}
+	if goroutines, ok := runtime["/sched/goroutines:goroutines"].(float64); !ok || goroutines < 1 {
+		t.Errorf("Expected goroutine count in runtime metrics, got %v", runtime["/sched/goroutines:goroutines"])
+	}
+
+	if body := get(t, baseURL+"/debug/pprof/"); !strings.Contains(body, "heap") {
+		t.Errorf("Expected pprof index, got:\n%.500s", body)
+	}
+
+	// channelz отвечает на том же порту по HTTP/2 без TLS и видит собственный сервер
+	conn, err := grpc.NewClient(listener.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	defer conn.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	resp, err := channelzpb.NewChannelzClient(conn).GetTopChannels(ctx, &channelzpb.GetTopChannelsRequest{})
+	if err != nil {
+		t.Fatalf("Expected channelz response, got: %v", err)
+	}
+	if len(resp.GetChannel()) == 0 {
+		t.Error("Expected the client channel in channelz top channels")
+	}
+}
+
+func get(t *testing.T, url string) string {
+	t.Helper()
+	resp, err := http.Get(url)
+	if err != nil {
+		t.Fatalf("GET %s: %v", url, err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil || resp.StatusCode != http.StatusOK {
+		t.Fatalf("GET %s: HTTP %d, %v", url, resp.StatusCode, err)
+	}
+	return string(body)
+}
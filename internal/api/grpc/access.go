@@ -0,0 +1,70 @@
+package grpc
+
+import (
+	"errors"
+	"fmt"
+
+	"notes-service/internal/auth"
+	"notes-service/internal/repository/memory"
+	notesService "notes-service/internal/service/notes"
+)
+
+// AccessPolicy определяет ответ на обращение к существующей заметке другого пользователя,
+// к которой у вызывающего нет доступа
+type AccessPolicy int
+
+const (
+	// AccessPolicyNotFound скрывает существование чужой заметки: клиент получает NotFound,
+	// неотличимый от ответа для несуществующей заметки (по умолчанию)
+	AccessPolicyNotFound AccessPolicy = iota
+
+	// AccessPolicyPermissionDenied возвращает PermissionDenied: удобнее при отладке,
+	// но позволяет перебором ID узнать о существовании чужих заметок
+	AccessPolicyPermissionDenied
+)
+
+// ParseAccessPolicy разбирает значение политики из конфигурации
+// Пустая строка соответствует политике по умолчанию
+func ParseAccessPolicy(s string) (AccessPolicy, error) {
+	switch s {
+	case "", "not_found":
+		return AccessPolicyNotFound, nil
+	case "permission_denied":
+		return AccessPolicyPermissionDenied, nil
+	default:
+		return 0, fmt.Errorf("unknown access policy %q (expected not_found or permission_denied)", s)
+	}
+}
+
+// String возвращает значение политики в формате конфигурации
+func (p AccessPolicy) String() string {
+	if p == AccessPolicyPermissionDenied {
+		return "permission_denied"
+	}
+	return "not_found"
+}
+
+// WithAccessPolicy задает ответ на обращение к чужой заметке
+func WithAccessPolicy(policy AccessPolicy) HandlerOption {
+	return func(h *Handler) {
+		h.accessPolicy = policy
+	}
+}
+
+// authorize применяет политику доступа к ошибке сервиса
+// Все ответы хэндлера проходят через authorize, поэтому чужая заметка
+// обрабатывается одинаково во всех методах, включая элементы пакетных операций
+func (h *Handler) authorize(err error) error {
+	if !errors.Is(err, notesService.ErrNoteAccessDenied) {
+		return err
+	}
+	if h.accessPolicy == AccessPolicyPermissionDenied {
+		return fmt.Errorf("%w: %v", auth.ErrPermissionDenied, err)
+	}
+	return memory.ErrNoteNotFound
+}
+
+// statusError конвертирует ошибку сервиса в gRPC статус с учетом политики доступа
+func (h *Handler) statusError(err error) error {
+	return handleError(h.authorize(err))
+}
@@ -0,0 +1,122 @@
+package grpc
+
+import (
+	"context"
+	"time"
+
+	"notes-service/internal/converter"
+	"notes-service/internal/pipeline"
+	"notes-service/internal/repository"
+	"notes-service/internal/service/apikeys"
+	"notes-service/internal/service/audit"
+	notesv1 "notes-service/pkg/proto/notes/v1"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// errAPIKeysDisabled ответ AdminService, если провайдер apikey не включен
+var errAPIKeysDisabled = status.Error(codes.FailedPrecondition, "api keys are disabled: auth provider apikey is not configured")
+
+// errAuditDisabled ответ ListAuditEvents, если журнал аудита не включен
+var errAuditDisabled = status.Error(codes.FailedPrecondition, "audit log is disabled: set audit.enabled")
+
+// AdminHandler реализует gRPC сервер для AdminService
+type AdminHandler struct {
+	notesv1.UnimplementedAdminServiceServer
+
+	apiKeys   *apikeys.Service   // nil, если ключи API не включены
+	pipelines *pipeline.Registry // Действующие цепочки интерцепторов и middleware
+	audit     *audit.Service     // nil, если журнал аудита не включен
+}
+
+// NewAdminHandler создает хэндлер AdminService
+// Если apiKeys == nil, методы ключей API отвечают FailedPrecondition; так же ListAuditEvents без auditLog
+func NewAdminHandler(apiKeys *apikeys.Service, pipelines *pipeline.Registry, auditLog *audit.Service) *AdminHandler {
+	return &AdminHandler{apiKeys: apiKeys, pipelines: pipelines, audit: auditLog}
+}
+
+// CreateAPIKey создает ключ API (только для администратора)
+func (h *AdminHandler) CreateAPIKey(ctx context.Context, req *notesv1.CreateAPIKeyRequest) (*notesv1.CreateAPIKeyResponse, error) {
+	if h.apiKeys == nil {
+		return nil, errAPIKeysDisabled
+	}
+
+	key, secret, err := h.apiKeys.Create(ctx, apikeys.CreateInput{
+		Name:           req.GetName(),
+		UserID:         req.GetUserId(),
+		Roles:          req.GetRoles(),
+		TTL:            time.Duration(req.GetTtlSeconds()) * time.Second,
+		RateLimitRPS:   req.GetRateLimitRps(),
+		RateLimitBurst: int(req.GetRateLimitBurst()),
+	})
+	if err != nil {
+		return nil, handleError(err)
+	}
+
+	return &notesv1.CreateAPIKeyResponse{ApiKey: converter.APIKeyToProto(key), Key: secret}, nil
+}
+
+// RevokeAPIKey отзывает ключ API (только для администратора)
+func (h *AdminHandler) RevokeAPIKey(ctx context.Context, req *notesv1.RevokeAPIKeyRequest) (*notesv1.APIKey, error) {
+	if h.apiKeys == nil {
+		return nil, errAPIKeysDisabled
+	}
+
+	key, err := h.apiKeys.Revoke(ctx, req.GetId())
+	if err != nil {
+		return nil, handleError(err)
+	}
+
+	return converter.APIKeyToProto(key), nil
+}
+
+// ListAPIKeys возвращает все ключи API (только для администратора)
+func (h *AdminHandler) ListAPIKeys(ctx context.Context, _ *notesv1.ListAPIKeysRequest) (*notesv1.ListAPIKeysResponse, error) {
+	if h.apiKeys == nil {
+		return nil, errAPIKeysDisabled
+	}
+
+	keys, err := h.apiKeys.List(ctx)
+	if err != nil {
+		return nil, handleError(err)
+	}
+
+	return &notesv1.ListAPIKeysResponse{ApiKeys: converter.APIKeysToProto(keys)}, nil
+}
+
+// GetPipeline возвращает действующие цепочки интерцепторов и middleware (только для администратора)
+func (h *AdminHandler) GetPipeline(_ context.Context, _ *notesv1.GetPipelineRequest) (*notesv1.GetPipelineResponse, error) {
+	return &notesv1.GetPipelineResponse{Chains: converter.PipelineChainsToProto(h.pipelines.Chains())}, nil
+}
+
+// ListAuditEvents возвращает записи журнала аудита, начиная с последней (только для администратора)
+func (h *AdminHandler) ListAuditEvents(ctx context.Context, req *notesv1.ListAuditEventsRequest) (*notesv1.ListAuditEventsResponse, error) {
+	if h.audit == nil {
+		return nil, errAuditDisabled
+	}
+
+	filter := repository.AuditFilter{
+		UserID:   req.GetUserId(),
+		NoteID:   req.GetNoteId(),
+		Action:   req.GetAction(),
+		From:     timeOrZero(req.GetFrom()),
+		To:       timeOrZero(req.GetTo()),
+		BeforeID: req.GetBeforeId(),
+		Limit:    int(req.GetLimit()),
+	}
+	if filter.Limit == 0 {
+		filter.Limit = audit.DefaultListLimit
+	}
+	records, err := h.audit.List(ctx, filter)
+	if err != nil {
+		return nil, handleError(err)
+	}
+
+	resp := &notesv1.ListAuditEventsResponse{Events: converter.AuditRecordsToProto(records)}
+	// Полная страница означает, что могут быть более ранние записи
+	if len(records) == filter.Limit {
+		resp.NextBeforeId = records[len(records)-1].ID
+	}
+	return resp, nil
+}
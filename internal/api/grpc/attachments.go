@@ -0,0 +1,154 @@
+package grpc
+
+import (
+	"errors"
+	"io"
+	"log"
+
+	"notes-service/internal/converter"
+	svc "notes-service/internal/service"
+	"notes-service/internal/tenant"
+	notesv1 "notes-service/pkg/proto/notes/v1"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// attachmentChunkSize размер части вложения в одном сообщении DownloadAttachment
+const attachmentChunkSize = 64 << 10
+
+// UploadAttachment обрабатывает client-side streaming - загрузку вложения заметки
+// Первое сообщение должно содержать метаданные, остальные - части содержимого
+func (h *Handler) UploadAttachment(stream notesv1.NotesService_UploadAttachmentServer) error {
+	if h.attachmentService == nil {
+		return status.Error(codes.Unimplemented, "attachment storage is not configured")
+	}
+	if err := checkFeature(stream.Context(), tenant.FeatureAttachments); err != nil {
+		return err
+	}
+
+	first, err := stream.Recv()
+	if err == io.EOF {
+		return status.Error(codes.InvalidArgument, "attachment metadata is required")
+	}
+	if err != nil {
+		return err
+	}
+
+	meta := first.GetMetadata()
+	if meta == nil {
+		return status.Error(codes.InvalidArgument, "first message must contain attachment metadata")
+	}
+
+	log.Printf("Receiving attachment %q for note %s", meta.GetFileName(), meta.GetNoteId())
+
+	attachment, err := h.attachmentService.Upload(stream.Context(), svc.UploadAttachmentInput{
+		NoteID:      meta.GetNoteId(),
+		FileName:    meta.GetFileName(),
+		ContentType: meta.GetContentType(),
+	}, &chunkReader{h: h, next: func() ([]byte, error) {
+		chunk, err := stream.Recv()
+		if err != nil {
+			return nil, err
+		}
+		if chunk.GetMetadata() != nil {
+			return nil, status.Error(codes.InvalidArgument, "attachment metadata must be sent only once")
+		}
+		return chunk.GetData(), nil
+	}})
+	if err != nil {
+		// Ошибки чтения стрима уже являются статусами gRPC
+		if _, ok := status.FromError(err); ok {
+			return err
+		}
+		return h.statusError(err)
+	}
+
+	log.Printf("Stored attachment %s (%d bytes) for note %s", attachment.ID, attachment.Size, attachment.NoteID)
+
+	return stream.SendAndClose(converter.AttachmentToProto(attachment))
+}
+
+// chunkReader представляет данные client-side стрима как io.Reader
+// next получает из стрима содержимое следующего сообщения
+type chunkReader struct {
+	next func() ([]byte, error)
+	h    *Handler
+	buf  []byte
+}
+
+// Read читает данные текущей части, получая следующую из стрима по мере необходимости
+func (r *chunkReader) Read(p []byte) (int, error) {
+	for len(r.buf) == 0 {
+		// Прерываем загрузку при shutdown сервера
+		select {
+		case <-r.h.serverCtx.Done():
+			return 0, status.Error(codes.Unavailable, "server is shutting down")
+		default:
+		}
+
+		data, err := r.next()
+		if err != nil {
+			return 0, err
+		}
+		r.buf = data
+	}
+
+	n := copy(p, r.buf)
+	r.buf = r.buf[n:]
+	return n, nil
+}
+
+// DownloadAttachment обрабатывает server-side streaming - скачивание вложения заметки
+// Первое сообщение содержит метаданные, остальные - части содержимого
+func (h *Handler) DownloadAttachment(req *notesv1.DownloadAttachmentRequest, stream notesv1.NotesService_DownloadAttachmentServer) error {
+	if h.attachmentService == nil {
+		return status.Error(codes.Unimplemented, "attachment storage is not configured")
+	}
+	if err := checkFeature(stream.Context(), tenant.FeatureAttachments); err != nil {
+		return err
+	}
+
+	ctx := stream.Context()
+	attachment, data, err := h.attachmentService.Download(ctx, req.GetNoteId(), req.GetId())
+	if err != nil {
+		return h.statusError(err)
+	}
+	defer data.Close()
+
+	if err := stream.Send(&notesv1.DownloadAttachmentResponse{
+		Payload: &notesv1.DownloadAttachmentResponse_Attachment{Attachment: converter.AttachmentToProto(attachment)},
+	}); err != nil {
+		return err
+	}
+
+	buf := make([]byte, attachmentChunkSize)
+	for {
+		select {
+		case <-ctx.Done():
+			log.Printf("Client disconnected during attachment download %s", attachment.ID)
+			return ctx.Err()
+		case <-h.serverCtx.Done():
+			log.Printf("Server shutdown during attachment download %s", attachment.ID)
+			return status.Error(codes.Unavailable, "server is shutting down")
+		default:
+		}
+
+		n, err := data.Read(buf)
+		if n > 0 {
+			// Send сериализует сообщение синхронно, поэтому буфер можно переиспользовать
+			if sendErr := stream.Send(&notesv1.DownloadAttachmentResponse{
+				Payload: &notesv1.DownloadAttachmentResponse_Data{Data: buf[:n]},
+			}); sendErr != nil {
+				return sendErr
+			}
+		}
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			log.Printf("Error reading attachment %s: %v", attachment.ID, err)
+			return status.Error(codes.Internal, "failed to read attachment")
+		}
+	}
+}
@@ -0,0 +1,165 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+
+	"notes-service/internal/auth"
+	notesv1 "notes-service/pkg/proto/notes/v1"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// errLoginDisabled ответ AuthService, если секция auth.sessions не настроена
+var errLoginDisabled = status.Error(codes.FailedPrecondition, "login is disabled: auth provider session is not configured")
+
+// errStreamTicketsDisabled ответ IssueStreamTicket, если билеты стримов не настроены
+var errStreamTicketsDisabled = status.Error(codes.FailedPrecondition, "stream tickets are disabled")
+
+// AuthHandler реализует gRPC сервер для AuthService
+type AuthHandler struct {
+	notesv1.UnimplementedAuthServiceServer
+
+	sessions *auth.Sessions      // nil, если вход по паролю не настроен
+	tickets  *auth.StreamTickets // nil, если билеты стримов не выдаются
+}
+
+// NewAuthHandler создает хэндлер AuthService
+// Если sessions == nil, методы сессий отвечают FailedPrecondition, если tickets == nil - IssueStreamTicket
+func NewAuthHandler(sessions *auth.Sessions, tickets *auth.StreamTickets) *AuthHandler {
+	return &AuthHandler{sessions: sessions, tickets: tickets}
+}
+
+// Login проверяет имя пользователя и пароль и открывает сессию
+func (h *AuthHandler) Login(ctx context.Context, req *notesv1.LoginRequest) (*notesv1.AuthTokens, error) {
+	if h.sessions == nil {
+		return nil, errLoginDisabled
+	}
+
+	tokens, err := h.sessions.Login(ctx, req.GetUsername(), req.GetPassword())
+	if errors.Is(err, auth.ErrInvalidCredentials) {
+		log.Printf("Failed login attempt for user %q", req.GetUsername())
+		return nil, status.Error(codes.Unauthenticated, "invalid username or password")
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to open session: %v", err)
+	}
+
+	log.Printf("User %s logged in", tokens.Principal.UserID)
+	return authTokensToProto(tokens), nil
+}
+
+// RefreshToken выдает новую пару токенов по refresh токену из запроса или из metadata x-refresh-token
+func (h *AuthHandler) RefreshToken(ctx context.Context, req *notesv1.RefreshTokenRequest) (*notesv1.AuthTokens, error) {
+	if h.sessions == nil {
+		return nil, errLoginDisabled
+	}
+
+	refreshToken := refreshTokenFromRequest(ctx, req.GetRefreshToken())
+	if refreshToken == "" {
+		return nil, status.Error(codes.InvalidArgument, "refresh_token is required")
+	}
+
+	tokens, err := h.sessions.Refresh(refreshToken)
+	if errors.Is(err, auth.ErrInvalidToken) {
+		log.Printf("Refresh rejected: %v", err)
+		return nil, status.Error(codes.Unauthenticated, "invalid refresh token")
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to refresh session: %v", err)
+	}
+
+	return authTokensToProto(tokens), nil
+}
+
+// Logout закрывает сессию refresh токена и сессию access токена запроса (если он выдан Login)
+func (h *AuthHandler) Logout(ctx context.Context, req *notesv1.LogoutRequest) (*notesv1.LogoutResponse, error) {
+	if h.sessions == nil {
+		return nil, errLoginDisabled
+	}
+
+	principal, _ := auth.FromContext(ctx)
+	refreshToken := refreshTokenFromRequest(ctx, req.GetRefreshToken())
+	if principal.SessionID == "" && refreshToken == "" {
+		return nil, status.Error(codes.InvalidArgument, "refresh_token or a session access token is required")
+	}
+
+	h.sessions.Logout(principal, refreshToken)
+	return &notesv1.LogoutResponse{}, nil
+}
+
+// IssueStreamTicket выдает пользователю запроса билет на открытие стримингового метода
+func (h *AuthHandler) IssueStreamTicket(ctx context.Context, req *notesv1.IssueStreamTicketRequest) (*notesv1.StreamTicket, error) {
+	if h.tickets == nil {
+		return nil, errStreamTicketsDisabled
+	}
+
+	principal, ok := auth.FromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "authorization header not provided")
+	}
+	if !isStreamingMethod(req.GetMethod()) {
+		return nil, status.Errorf(codes.InvalidArgument, "method %q is not a streaming method", req.GetMethod())
+	}
+
+	ticket, err := h.tickets.Issue(principal, req.GetMethod())
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to issue stream ticket: %v", err)
+	}
+	return &notesv1.StreamTicket{
+		Ticket:    ticket.Ticket,
+		ExpiresAt: timestamppb.New(ticket.ExpiresAt),
+	}, nil
+}
+
+// isStreamingMethod проверяет, что fullMethod ("/notes.v1.NotesService/SubscribeToEvents") -
+// стриминговый метод сервисов из proto
+func isStreamingMethod(fullMethod string) bool {
+	serviceName, methodName, ok := strings.Cut(strings.TrimPrefix(fullMethod, "/"), "/")
+	if !ok || !strings.HasPrefix(fullMethod, "/") {
+		return false
+	}
+	service := notesv1.File_proto_notes_v1_notes_proto.Services().ByName(protoreflect.FullName(serviceName).Name())
+	if service == nil || service.FullName() != protoreflect.FullName(serviceName) {
+		return false
+	}
+	method := service.Methods().ByName(protoreflect.Name(methodName))
+	return method != nil && (method.IsStreamingServer() || method.IsStreamingClient())
+}
+
+// refreshTokenFromRequest возвращает refresh токен из запроса или из metadata x-refresh-token
+// Для HTTP запросов Gateway передает в x-refresh-token cookie, выставленную при входе
+func refreshTokenFromRequest(ctx context.Context, token string) string {
+	if token != "" {
+		return token
+	}
+
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+
+	if values := md.Get("x-refresh-token"); len(values) > 0 {
+		return values[0]
+	}
+
+	return ""
+}
+
+// authTokensToProto конвертирует токены сессии в proto
+func authTokensToProto(tokens auth.Tokens) *notesv1.AuthTokens {
+	return &notesv1.AuthTokens{
+		AccessToken:           tokens.AccessToken,
+		AccessTokenExpiresAt:  timestamppb.New(tokens.AccessTokenExpiresAt),
+		RefreshToken:          tokens.RefreshToken,
+		RefreshTokenExpiresAt: timestamppb.New(tokens.RefreshTokenExpiresAt),
+		TokenType:             "Bearer",
+		UserId:                tokens.Principal.UserID,
+	}
+}
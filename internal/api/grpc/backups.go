@@ -0,0 +1,58 @@
+package grpc
+
+import (
+	"context"
+
+	"notes-service/internal/auth"
+	"notes-service/internal/converter"
+	"notes-service/internal/model"
+	"notes-service/internal/service/backups"
+	notesv1 "notes-service/pkg/proto/notes/v1"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// WithBackupManager подключает резервное копирование (RestoreBackup, состояние в GetServerInfo)
+func WithBackupManager(backupManager *backups.Manager) HandlerOption {
+	return func(h *Handler) {
+		h.backupManager = backupManager
+	}
+}
+
+// RestoreBackup восстанавливает заметки из резервной копии и возвращает отчет о внесенных изменениях
+func (h *Handler) RestoreBackup(ctx context.Context, req *notesv1.RestoreBackupRequest) (*notesv1.RestoreBackupResponse, error) {
+	if h.backupManager == nil {
+		return nil, status.Error(codes.Unimplemented, "backups are not configured")
+	}
+
+	report, err := h.backupManager.Restore(ctx, backups.RestoreInput{
+		Backup:   req.GetBackup(),
+		Strategy: model.RestoreConflictStrategy(req.GetConflictStrategy()),
+		DryRun:   req.GetDryRun(),
+	})
+	if err != nil {
+		return nil, h.statusError(err)
+	}
+
+	return converter.RestoreReportToProto(report), nil
+}
+
+// backupStatus возвращает состояние резервного копирования для GetServerInfo
+// Адреса копий раскрывают устройство хранилища, поэтому состояние видят только администраторы
+func (h *Handler) backupStatus(ctx context.Context) *notesv1.BackupStatus {
+	if h.backupManager == nil {
+		return nil
+	}
+	principal, ok := auth.FromContext(ctx)
+	if !ok || !principal.HasRole(auth.RoleAdmin) {
+		return nil
+	}
+
+	s := h.backupManager.Status()
+	protoStatus := converter.BackupStatusToProto(s)
+	if s.LastError != nil {
+		protoStatus.LastError = status.Convert(h.statusError(s.LastError)).Proto()
+	}
+	return protoStatus
+}
@@ -0,0 +1,93 @@
+package grpc
+
+import (
+	"encoding/base64"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	notesv1 "notes-service/pkg/proto/notes/v1"
+)
+
+// defaultHeartbeatInterval интервал health-check сообщений SubscribeToEvents по умолчанию
+const defaultHeartbeatInterval = 30 * time.Second
+
+// goAwaySendTimeout время ожидания отправки go_away при остановке сервера
+const goAwaySendTimeout = 5 * time.Second
+
+// resumeTokenPrefix версия формата resume_token
+const resumeTokenPrefix = "v1:"
+
+// WithEventHeartbeatInterval задает интервал health-check сообщений SubscribeToEvents
+// (по умолчанию 30 секунд; значения не больше нуля оставляют интервал по умолчанию)
+func WithEventHeartbeatInterval(interval time.Duration) HandlerOption {
+	return func(h *Handler) {
+		h.SetEventHeartbeatInterval(interval)
+	}
+}
+
+// WithPooledMessages включает переиспользование proto заметок событий SubscribeToEvents из пула
+// (по умолчанию выключено). Нельзя включать со stats handler'ами, читающими сообщение после
+// отправки (трассировка otelgrpc): они увидят заметку, уже возвращенную в пул
+func WithPooledMessages(enabled bool) HandlerOption {
+	return func(h *Handler) {
+		h.pooledMessages = enabled
+	}
+}
+
+// SetEventHeartbeatInterval меняет интервал health-check сообщений для новых подписок SubscribeToEvents
+// (например, после перечитывания конфигурации); значения не больше нуля игнорируются
+func (h *Handler) SetEventHeartbeatInterval(interval time.Duration) {
+	if interval > 0 {
+		h.heartbeatInterval.Store(int64(interval))
+	}
+}
+
+// encodeResumeToken возвращает resume_token для переподключения к стриму событий
+// Токен содержит время последнего обработанного события: события с меньшим номером могут
+// прийти с других реплик, а время публикации общее для всех реплик
+func encodeResumeToken(t time.Time) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(resumeTokenPrefix + strconv.FormatInt(t.UnixNano(), 10)))
+}
+
+// decodeResumeToken разбирает resume_token, выданный encodeResumeToken
+func decodeResumeToken(token string) (time.Time, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil || !strings.HasPrefix(string(raw), resumeTokenPrefix) {
+		return time.Time{}, status.Error(codes.InvalidArgument, "invalid resume_token")
+	}
+	nanos, err := strconv.ParseInt(strings.TrimPrefix(string(raw), resumeTokenPrefix), 10, 64)
+	if err != nil {
+		return time.Time{}, status.Error(codes.InvalidArgument, "invalid resume_token")
+	}
+	return time.Unix(0, nanos), nil
+}
+
+// healthCheckEvent возвращает health-check сообщение стрима событий
+func healthCheckEvent(message string) *notesv1.EventResponse {
+	return &notesv1.EventResponse{
+		Event: &notesv1.EventResponse_HealthCheck{
+			HealthCheck: &notesv1.HealthCheck{
+				Message:   message,
+				Timestamp: timestamppb.Now(),
+			},
+		},
+	}
+}
+
+// goAwayEvent возвращает последнее сообщение стрима событий перед его закрытием сервером
+func goAwayEvent(reason string, resumeAt time.Time, lastEventID uint64) *notesv1.EventResponse {
+	return &notesv1.EventResponse{
+		Event: &notesv1.EventResponse_GoAway{
+			GoAway: &notesv1.StreamGoAway{
+				Reason:      reason,
+				ResumeToken: encodeResumeToken(resumeAt),
+				LastEventId: lastEventID,
+			},
+		},
+	}
+}
@@ -0,0 +1,158 @@
+package grpc
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+
+	"notes-service/internal/converter"
+	"notes-service/internal/model"
+	svc "notes-service/internal/service"
+	notesv1 "notes-service/pkg/proto/notes/v1"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// exportBatchSize количество заметок, читаемых из хранилища за один раз при выгрузке
+	exportBatchSize = 500
+	// maxImportErrors количество ошибок отклоненных заметок, возвращаемых ImportNotes
+	maxImportErrors = 100
+)
+
+// ExportNotes обрабатывает server-side streaming - выгрузку заметок пользователя
+// Файл выгрузки передается частями до attachmentChunkSize байт
+func (h *Handler) ExportNotes(req *notesv1.ExportNotesRequest, stream notesv1.NotesService_ExportNotesServer) error {
+	format, err := converter.ExportFormatFromProto(req.GetFormat())
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	ctx := stream.Context()
+	out := bufio.NewWriterSize(&exportStreamWriter{stream: stream}, attachmentChunkSize)
+	writer, err := converter.NewNoteWriter(format, out)
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	var count int
+	err = h.noteService.ForEach(ctx, exportBatchSize, func(note model.Note) error {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-h.serverCtx.Done():
+			return status.Error(codes.Unavailable, "server is shutting down")
+		default:
+		}
+
+		count++
+		return writer.Write(note)
+	})
+	if err == nil {
+		err = writer.Close()
+	}
+	if err == nil {
+		err = out.Flush()
+	}
+	if err != nil {
+		// Ошибки отправки и отмены уже являются статусами gRPC
+		if _, ok := status.FromError(err); ok {
+			return err
+		}
+		return h.statusError(err)
+	}
+
+	log.Printf("Exported %d notes (format=%s)", count, format)
+
+	return nil
+}
+
+// exportStreamWriter отправляет записываемые данные сообщениями ExportNotesResponse
+type exportStreamWriter struct {
+	stream notesv1.NotesService_ExportNotesServer
+}
+
+// Write отправляет p одним сообщением, Send сериализует его синхронно
+func (w *exportStreamWriter) Write(p []byte) (int, error) {
+	if err := w.stream.Send(&notesv1.ExportNotesResponse{Data: p}); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// ImportNotes обрабатывает client-side streaming - загрузку заметок из файла выгрузки
+// Каждая заметка создается заново от имени вызывающего пользователя; заметки, не прошедшие
+// валидацию, пропускаются, а ошибка формата файла прерывает загрузку
+func (h *Handler) ImportNotes(stream notesv1.NotesService_ImportNotesServer) error {
+	first, err := stream.Recv()
+	if err == io.EOF {
+		return status.Error(codes.InvalidArgument, "import format is required")
+	}
+	if err != nil {
+		return err
+	}
+
+	if _, ok := first.GetPayload().(*notesv1.ImportNotesRequest_Format); !ok {
+		return status.Error(codes.InvalidArgument, "first message must contain import format")
+	}
+	format, err := converter.ExportFormatFromProto(first.GetFormat())
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	reader, err := converter.NewNoteReader(format, &chunkReader{h: h, next: func() ([]byte, error) {
+		chunk, err := stream.Recv()
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := chunk.GetPayload().(*notesv1.ImportNotesRequest_Format); ok {
+			return nil, status.Error(codes.InvalidArgument, "import format must be sent only once")
+		}
+		return chunk.GetData(), nil
+	}})
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	ctx := stream.Context()
+	resp := &notesv1.ImportNotesResponse{}
+	for {
+		note, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			if _, ok := status.FromError(err); ok {
+				return err
+			}
+			return status.Errorf(codes.InvalidArgument, "%v (imported %d notes before the error)", err, resp.GetImported())
+		}
+
+		_, err = h.noteService.Create(ctx, svc.CreateNoteInput{
+			Title:   note.Title,
+			Content: note.Content,
+			Tags:    note.Tags,
+
+			RemindAt: note.RemindAt,
+
+			IsE2E:            note.IsE2E,
+			E2EScheme:        note.E2EScheme,
+			ContentEncrypted: note.ContentEncrypted,
+		})
+		if err != nil {
+			resp.Failed++
+			if len(resp.Errors) < maxImportErrors {
+				resp.Errors = append(resp.Errors, fmt.Sprintf("note %q: %v", note.Title, err))
+			}
+			continue
+		}
+		resp.Imported++
+	}
+
+	log.Printf("Imported %d notes, rejected %d (format=%s)", resp.GetImported(), resp.GetFailed(), format)
+
+	return stream.SendAndClose(resp)
+}
@@ -0,0 +1,64 @@
+package grpc
+
+import (
+	"context"
+
+	"notes-service/internal/converter"
+	"notes-service/internal/model"
+	"notes-service/internal/service/exports"
+	notesv1 "notes-service/pkg/proto/notes/v1"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// WithExportManager подключает выгрузку заметок в хранилище (ExportToDestination, GetExportOperation)
+func WithExportManager(exportManager *exports.Manager) HandlerOption {
+	return func(h *Handler) {
+		h.exportManager = exportManager
+	}
+}
+
+// ExportToDestination запускает выгрузку заметок пользователя в хранилище
+// Возвращает операцию в состоянии RUNNING, не дожидаясь завершения выгрузки
+func (h *Handler) ExportToDestination(ctx context.Context, req *notesv1.ExportToDestinationRequest) (*notesv1.ExportOperation, error) {
+	if h.exportManager == nil {
+		return nil, status.Error(codes.Unimplemented, "export destination is not configured")
+	}
+
+	archive, err := converter.ExportArchiveFromProto(req.GetArchive())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	op, err := h.exportManager.Start(ctx, archive)
+	if err != nil {
+		return nil, h.statusError(err)
+	}
+
+	return h.exportOperationToProto(op), nil
+}
+
+// GetExportOperation возвращает состояние и прогресс операции выгрузки
+func (h *Handler) GetExportOperation(ctx context.Context, req *notesv1.GetExportOperationRequest) (*notesv1.ExportOperation, error) {
+	if h.exportManager == nil {
+		return nil, status.Error(codes.Unimplemented, "export destination is not configured")
+	}
+
+	op, err := h.exportManager.Get(ctx, req.GetId())
+	if err != nil {
+		return nil, h.statusError(err)
+	}
+
+	return h.exportOperationToProto(op), nil
+}
+
+// exportOperationToProto конвертирует операцию выгрузки, ошибка операции конвертируется
+// в google.rpc.Status так же, как ошибки запросов
+func (h *Handler) exportOperationToProto(op model.ExportOperation) *notesv1.ExportOperation {
+	protoOp := converter.ExportOperationToProto(op)
+	if op.Err != nil {
+		protoOp.Error = status.Convert(h.statusError(op.Err)).Proto()
+	}
+	return protoOp
+}
@@ -8,66 +8,141 @@ import (
 	"log"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"notes-service/internal/api/grpc/interceptors"
+	"notes-service/internal/auth"
+	"notes-service/internal/collation"
 	"notes-service/internal/converter"
+	"notes-service/internal/diff"
 	"notes-service/internal/model"
+	"notes-service/internal/repository"
+	"notes-service/internal/repository/attachments"
+	"notes-service/internal/repository/degraded"
 	"notes-service/internal/repository/memory"
 	svc "notes-service/internal/service"
+	"notes-service/internal/service/backups"
+	"notes-service/internal/service/chat"
+	"notes-service/internal/service/exports"
+	"notes-service/internal/service/keys"
+	"notes-service/internal/service/metrics"
+	notesService "notes-service/internal/service/notes"
+	"notes-service/internal/service/searches"
+	"notes-service/internal/service/stats"
+	"notes-service/internal/service/suggest"
+	"notes-service/internal/service/usage"
+	"notes-service/internal/service/webhooks"
+	"notes-service/internal/tenant"
 	notesv1 "notes-service/pkg/proto/notes/v1"
+	"notes-service/pkg/streamutil"
 
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 // eventServiceProvider интерфейс для доступа к EventService
 type eventServiceProvider interface {
-	GetEventService() interface {
-		Subscribe() chan model.Note
-		Unsubscribe(chan model.Note)
-		Publish(model.Note)
-	}
+	GetEventService() notesService.EventBus
 }
 
 // Handler реализует gRPC сервер для NotesService
 type Handler struct {
 	notesv1.UnimplementedNotesServiceServer
 
-	noteService svc.NoteService
-	serverCtx   context.Context // Контекст сервера, отменяется при graceful shutdown
+	noteService       svc.NoteService
+	statsService      *stats.Service        // Статистика поверх noteService
+	attachmentService svc.AttachmentService // nil, если хранилище вложений не настроено
+	serverCtx         context.Context       // Контекст сервера, отменяется при graceful shutdown
+	accessPolicy      AccessPolicy          // Ответ на обращение к чужой заметке
+	exportManager     *exports.Manager      // nil, если хранилище выгрузок не настроено
+	keyRotation       *keys.Manager         // nil, если шифрование не настроено
+	webhookService    *webhooks.Service     // nil, если вебхуки не подключены
+	searchService     *searches.Service     // nil, если сохраненные поиски не подключены
+	suggestIndex      *suggest.Index        // nil, если подсказки заголовков не подключены
+	backupManager     *backups.Manager      // nil, если резервное копирование не настроено
+	usageCollector    *usage.Collector      // nil, если сбор статистики использования выключен
+	metricStore       *metrics.Store        // nil, если хранилище метрик выключено
+	chatHub           *chat.Hub             // Комнаты Chat
+	heartbeatInterval atomic.Int64          // Интервал health-check сообщений SubscribeToEvents (time.Duration)
+	pooledMessages    bool                  // Переиспользовать proto заметки событий (см. WithPooledMessages)
+}
+
+// HandlerOption настраивает дополнительные зависимости хэндлера
+type HandlerOption func(*Handler)
+
+// WithAttachmentService подключает сервис вложений (UploadAttachment, DownloadAttachment)
+func WithAttachmentService(attachmentService svc.AttachmentService) HandlerOption {
+	return func(h *Handler) {
+		h.attachmentService = attachmentService
+	}
 }
 
 // NewHandler создает новый экземпляр gRPC хэндлера
 // serverCtx - контекст сервера, который отменяется при shutdown для корректного завершения стримов
-func NewHandler(noteService svc.NoteService, serverCtx context.Context) *Handler {
-	return &Handler{
-		noteService: noteService,
-		serverCtx:   serverCtx,
+func NewHandler(noteService svc.NoteService, serverCtx context.Context, opts ...HandlerOption) *Handler {
+	h := &Handler{
+		noteService:  noteService,
+		statsService: stats.NewService(noteService),
+		serverCtx:    serverCtx,
+		chatHub:      chat.NewHub(),
+	}
+	h.heartbeatInterval.Store(int64(defaultHeartbeatInterval))
+	for _, opt := range opts {
+		opt(h)
 	}
+
+	return h
 }
 
 // CreateNote создает новую заметку
 func (h *Handler) CreateNote(ctx context.Context, req *notesv1.CreateNoteRequest) (*notesv1.CreateNoteResponse, error) {
+	ctx, warnings := svc.WithWarnings(ctx)
+
 	// Вызываем бизнес-логику
-	note, err := h.noteService.Create(ctx, req.GetTitle(), req.GetContent())
+	note, err := h.noteService.Create(ctx, svc.CreateNoteInput{
+		Title:   req.GetTitle(),
+		Content: req.GetContent(),
+		Tags:    req.GetTags(),
+
+		IsE2E:            req.GetIsE2E(),
+		E2EScheme:        req.GetE2EScheme(),
+		ContentEncrypted: req.GetContentEncrypted(),
+		RemindAt:         timeOrZero(req.GetRemindAt()),
+
+		IdempotencyKey: idempotencyKey(ctx, req.GetIdempotencyKey()),
+	})
 	if err != nil {
-		return nil, handleError(err)
+		return nil, h.statusError(err)
 	}
 
 	// Конвертируем domain модель в proto
 	protoNote := converter.ModelToProto(note)
 
 	return &notesv1.CreateNoteResponse{
-		Note: protoNote,
+		Note:     protoNote,
+		Warnings: converter.WarningsToProto(warnings.List()),
 	}, nil
 }
 
 // GetNote возвращает заметку по её UUID
 func (h *Handler) GetNote(ctx context.Context, req *notesv1.GetNoteRequest) (*notesv1.GetNoteResponse, error) {
+	ctx, warnings := svc.WithWarnings(withNotePassphrase(ctx))
+
+	fields, err := newNoteFields(req.GetReadMask())
+	if err != nil {
+		return nil, err
+	}
+
 	// Вызываем бизнес-логику
 	note, err := h.noteService.Get(ctx, req.GetId())
 	if err != nil {
+		err = h.authorize(err)
+
 		// Если заметка не найдена, возвращаем детализированную ошибку
 		if errors.Is(err, memory.ErrNoteNotFound) {
 			st := status.New(codes.NotFound, "note not found")
@@ -82,46 +157,165 @@ func (h *Handler) GetNote(ctx context.Context, req *notesv1.GetNoteRequest) (*no
 			}
 			return nil, st.Err()
 		}
-		return nil, handleError(err)
+		return nil, h.statusError(err)
+	}
+	// Подсказки показываются только владельцу, поэтому учитываются только его просмотры
+	if principal, ok := auth.FromContext(ctx); ok && h.suggestIndex != nil && principal.UserID == note.OwnerID {
+		h.suggestIndex.RecordView(note.ID)
 	}
 
 	// Конвертируем domain модель в proto
 	protoNote := converter.ModelToProto(note)
+	fields.apply(protoNote)
 
 	return &notesv1.GetNoteResponse{
-		Note: protoNote,
+		Note:     protoNote,
+		Warnings: converter.WarningsToProto(warnings.List()),
 	}, nil
 }
 
 // ListNotes возвращает список всех заметок
 func (h *Handler) ListNotes(ctx context.Context, req *notesv1.ListNotesRequest) (*notesv1.ListNotesResponse, error) {
+	ctx, warnings := svc.WithWarnings(ctx)
+
+	fields, err := newNoteFields(req.GetReadMask())
+	if err != nil {
+		return nil, err
+	}
+
+	// Язык сортировки из запроса, иначе - предпочтение пользователя из Accept-Language
+	titleCollation := req.GetTitleCollation()
+	if titleCollation == "" {
+		titleCollation = collation.PreferredLocale(acceptLanguage(ctx))
+	}
+
 	// Вызываем бизнес-логику
-	notes, err := h.noteService.List(ctx)
+	// Номера NoteOrder в proto совпадают со значениями svc.ListOrder
+	notes, err := h.noteService.List(ctx, svc.ListOptions{
+		TitleCollation:  titleCollation,
+		Order:           svc.ListOrder(req.GetOrderBy()),
+		MinWordCount:    int(req.GetMinWordCount()),
+		MaxWordCount:    int(req.GetMaxWordCount()),
+		IncludeArchived: req.GetIncludeArchived(),
+	})
 	if err != nil {
-		return nil, handleError(err)
+		return nil, h.statusError(err)
 	}
 
 	// Конвертируем domain модели в proto
 	protoNotes := converter.ModelsToProtos(notes)
+	fields.apply(protoNotes...)
 
 	return &notesv1.ListNotesResponse{
-		Notes: protoNotes,
+		Notes:    protoNotes,
+		Warnings: converter.WarningsToProto(warnings.List()),
 	}, nil
 }
 
+// StreamNotes обрабатывает server-side streaming - поток заметок в порядке возрастания ID
+// Хранилище читается порциями по batch_size, поэтому память не зависит от количества заметок
+func (h *Handler) StreamNotes(req *notesv1.StreamNotesRequest, stream notesv1.NotesService_StreamNotesServer) error {
+	ctx := stream.Context()
+
+	var count int
+	err := h.noteService.ForEachAfter(ctx, req.GetCursor(), int(req.GetBatchSize()), func(note model.Note) error {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-h.serverCtx.Done():
+			return status.Error(codes.Unavailable, "server is shutting down")
+		default:
+		}
+
+		count++
+		return stream.Send(converter.ModelToProto(note))
+	})
+	if err != nil {
+		// Ошибки отправки и отмены уже являются статусами gRPC
+		if _, ok := status.FromError(err); ok {
+			return err
+		}
+		return h.statusError(err)
+	}
+
+	log.Printf("Streamed %d notes (cursor=%q)", count, req.GetCursor())
+
+	return nil
+}
+
+// acceptLanguage возвращает заголовок Accept-Language из метаданных запроса
+// Для HTTP запросов заголовок передается в metadata через grpc-gateway
+func acceptLanguage(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+
+	if values := md.Get("accept-language"); len(values) > 0 {
+		return values[0]
+	}
+
+	return ""
+}
+
+// idempotencyKey возвращает ключ идемпотентности из запроса или из метаданных x-idempotency-key
+// Для HTTP запросов заголовок X-Idempotency-Key передается в metadata через grpc-gateway
+func idempotencyKey(ctx context.Context, key string) string {
+	if key != "" {
+		return key
+	}
+
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+
+	if values := md.Get("x-idempotency-key"); len(values) > 0 {
+		return values[0]
+	}
+
+	return ""
+}
+
+// notePassphraseHeader метаданные с парольной фразой защищенной заметки
+// Для HTTP запросов заголовок X-Note-Passphrase передается в metadata через grpc-gateway
+const notePassphraseHeader = "x-note-passphrase"
+
+// withNotePassphrase передает сервису парольную фразу заметки из метаданных x-note-passphrase
+func withNotePassphrase(ctx context.Context) context.Context {
+	if values := metadata.ValueFromIncomingContext(ctx, notePassphraseHeader); len(values) > 0 {
+		return svc.WithNotePassphrase(ctx, values[0])
+	}
+	return ctx
+}
+
 // UpdateNote обновляет существующую заметку
 func (h *Handler) UpdateNote(ctx context.Context, req *notesv1.UpdateNoteRequest) (*notesv1.UpdateNoteResponse, error) {
+	ctx, warnings := svc.WithWarnings(withNotePassphrase(ctx))
+
 	// Вызываем бизнес-логику
-	note, err := h.noteService.Update(ctx, req.GetId(), req.GetTitle(), req.GetContent())
+	note, err := h.noteService.Update(ctx, svc.UpdateNoteInput{
+		ID:         req.GetId(),
+		Title:      req.GetTitle(),
+		Content:    req.GetContent(),
+		Tags:       req.GetTags(),
+		Version:    req.GetVersion(),
+		UpdateMask: req.GetUpdateMask().GetPaths(),
+		Force:      req.GetForce(),
+
+		ContentEncrypted: req.GetContentEncrypted(),
+		RemindAt:         timeOrZero(req.GetRemindAt()),
+	})
 	if err != nil {
-		return nil, handleError(err)
+		return nil, h.statusError(err)
 	}
 
 	// Конвертируем domain модель в proto
 	protoNote := converter.ModelToProto(note)
 
 	return &notesv1.UpdateNoteResponse{
-		Note: protoNote,
+		Note:     protoNote,
+		Warnings: converter.WarningsToProto(warnings.List()),
 	}, nil
 }
 
@@ -130,335 +324,871 @@ func (h *Handler) DeleteNote(ctx context.Context, req *notesv1.DeleteNoteRequest
 	// Вызываем бизнес-логику
 	err := h.noteService.Delete(ctx, req.GetId())
 	if err != nil {
-		return nil, handleError(err)
+		return nil, h.statusError(err)
 	}
 
 	return &notesv1.DeleteNoteResponse{}, nil
 }
 
-// SubscribeToEvents подписывается на события создания заметок (server-side streaming)
-func (h *Handler) SubscribeToEvents(req *notesv1.SubscribeToEventsRequest, stream notesv1.NotesService_SubscribeToEventsServer) error {
-	// 1. Получаем EventService из noteService через интерфейс
-	provider, ok := h.noteService.(eventServiceProvider)
-	if !ok {
-		return status.Errorf(codes.Internal, "event service not available")
+// PinNote закрепляет заметку
+func (h *Handler) PinNote(ctx context.Context, req *notesv1.PinNoteRequest) (*notesv1.PinNoteResponse, error) {
+	note, err := h.noteService.Pin(ctx, req.GetId())
+	if err != nil {
+		return nil, h.statusError(err)
 	}
 
-	eventService := provider.GetEventService()
-	eventCh := eventService.Subscribe()
-	defer eventService.Unsubscribe(eventCh)
+	return &notesv1.PinNoteResponse{
+		Note: converter.ModelToProto(note),
+	}, nil
+}
 
-	// 2. Отправить приветственное сообщение (health-check) сразу после подключения
-	if err := stream.Send(&notesv1.EventResponse{
-		Event: &notesv1.EventResponse_HealthCheck{
-			HealthCheck: &notesv1.HealthCheck{
-				Message:   "Connected to events stream",
-				Timestamp: timestamppb.Now(),
-			},
-		},
-	}); err != nil {
-		return err
+// UnpinNote открепляет заметку
+func (h *Handler) UnpinNote(ctx context.Context, req *notesv1.UnpinNoteRequest) (*notesv1.UnpinNoteResponse, error) {
+	note, err := h.noteService.Unpin(ctx, req.GetId())
+	if err != nil {
+		return nil, h.statusError(err)
 	}
 
-	// 3. Запустить горутину для периодических health-check сообщений
-	ctx := stream.Context()
-	ticker := time.NewTicker(30 * time.Second) // Отправляем health-check каждые 30 секунд
-	defer ticker.Stop()
+	return &notesv1.UnpinNoteResponse{
+		Note: converter.ModelToProto(note),
+	}, nil
+}
 
-	healthCheckErrChan := make(chan error, 1)
-	go func() {
-		for {
-			select {
-			case <-ticker.C:
-				if err := stream.Send(&notesv1.EventResponse{
-					Event: &notesv1.EventResponse_HealthCheck{
-						HealthCheck: &notesv1.HealthCheck{
-							Message:   "Health check",
-							Timestamp: timestamppb.Now(),
-						},
-					},
-				}); err != nil {
-					healthCheckErrChan <- err
-					return
-				}
-			case <-ctx.Done():
-				// Клиент отключился
-				log.Printf("Client disconnected from events stream (health check goroutine)")
-				return
-			case <-h.serverCtx.Done():
-				// Сервер завершает работу
-				log.Printf("Server shutdown during events stream (health check goroutine)")
-				return
-			}
-		}
-	}()
+// ArchiveNote перемещает заметку в архив
+func (h *Handler) ArchiveNote(ctx context.Context, req *notesv1.ArchiveNoteRequest) (*notesv1.ArchiveNoteResponse, error) {
+	note, err := h.noteService.Archive(ctx, req.GetId())
+	if err != nil {
+		return nil, h.statusError(err)
+	}
 
-	// 4. Основной цикл обработки событий
-	// Проверяем оба контекста:
-	// - ctx (stream.Context()) - отменяется при отключении клиента
-	// - h.serverCtx - отменяется при shutdown сервера
-	for {
-		select {
-		case note := <-eventCh:
-			// note уже имеет тип model.Note из канала
-			// Конвертируем в proto и отправляем событие
-			// Используем полную заметку (более информативный вариант)
-			protoNote := converter.ModelToProto(note)
-			if err := stream.Send(&notesv1.EventResponse{
-				Event: &notesv1.EventResponse_NoteCreated{
-					NoteCreated: &notesv1.NoteCreatedEvent{
-						Payload: &notesv1.NoteCreatedEvent_Note{
-							Note: protoNote,
-						},
-					},
-				},
-			}); err != nil {
-				return err
-			}
+	return &notesv1.ArchiveNoteResponse{
+		Note: converter.ModelToProto(note),
+	}, nil
+}
 
-		case err := <-healthCheckErrChan:
-			return err
-		case <-ctx.Done():
-			// Клиент отключился
-			log.Printf("Client disconnected from events stream")
-			return nil
-		case <-h.serverCtx.Done():
-			// Сервер завершает работу (graceful shutdown)
-			log.Printf("Server shutdown during events stream")
-			return h.serverCtx.Err()
-		}
+// UnarchiveNote возвращает заметку из архива
+func (h *Handler) UnarchiveNote(ctx context.Context, req *notesv1.UnarchiveNoteRequest) (*notesv1.UnarchiveNoteResponse, error) {
+	note, err := h.noteService.Unarchive(ctx, req.GetId())
+	if err != nil {
+		return nil, h.statusError(err)
 	}
+
+	return &notesv1.UnarchiveNoteResponse{
+		Note: converter.ModelToProto(note),
+	}, nil
 }
 
-// UploadMetrics обрабатывает client-side streaming - загрузку метрик
-func (h *Handler) UploadMetrics(stream notesv1.NotesService_UploadMetricsServer) error {
-	var sum float64
-	var count int64
+// LockNote захватывает блокировку заметки для монопольного редактирования
+func (h *Handler) LockNote(ctx context.Context, req *notesv1.LockNoteRequest) (*notesv1.LockNoteResponse, error) {
+	ttl := time.Duration(req.GetTtlSeconds()) * time.Second
+	lock, err := h.noteService.Lock(ctx, req.GetId(), ttl, req.GetForce())
+	if err != nil {
+		return nil, h.statusError(err)
+	}
 
-	ctx := stream.Context()
-	log.Println("Starting to receive metrics stream...")
+	return &notesv1.LockNoteResponse{
+		Lock: converter.NoteLockToProto(lock),
+	}, nil
+}
 
-	// Читаем метрики из стрима до io.EOF
-	// Проверяем оба контекста:
-	// - ctx (stream.Context()) - отменяется при отключении клиента
-	// - h.serverCtx - отменяется при shutdown сервера
-	for {
-		// Проверяем контекст сервера перед Recv
-		select {
-		case <-ctx.Done():
-			// Клиент отключился
-			log.Printf("Client disconnected from metrics stream")
-			return ctx.Err()
-		case <-h.serverCtx.Done():
-			// Сервер завершает работу (graceful shutdown)
-			log.Printf("Server shutdown during metrics stream")
-			return h.serverCtx.Err()
-		default:
-			// Продолжаем работу
-		}
+// UnlockNote снимает блокировку заметки
+func (h *Handler) UnlockNote(ctx context.Context, req *notesv1.UnlockNoteRequest) (*notesv1.UnlockNoteResponse, error) {
+	if err := h.noteService.Unlock(ctx, req.GetId(), req.GetForce()); err != nil {
+		return nil, h.statusError(err)
+	}
 
-		metric, err := stream.Recv()
-		if err == io.EOF {
-			// Клиент завершил отправку, вычисляем результат
-			average := float64(0)
-			if count > 0 {
-				average = sum / float64(count)
-			}
+	return &notesv1.UnlockNoteResponse{}, nil
+}
 
-			log.Printf("Received all metrics: count=%d, sum=%.2f, average=%.2f", count, sum, average)
+// SetNotePassphrase защищает заметку парольной фразой, меняет ее или снимает защиту
+func (h *Handler) SetNotePassphrase(ctx context.Context, req *notesv1.SetNotePassphraseRequest) (*notesv1.SetNotePassphraseResponse, error) {
+	note, err := h.noteService.SetPassphrase(withNotePassphrase(ctx), req.GetId(), req.GetPassphrase())
+	if err != nil {
+		return nil, h.statusError(err)
+	}
 
-			// Отправляем финальный ответ
-			if err := stream.SendAndClose(&notesv1.SummaryResponse{
-				Sum:     sum,
-				Average: average,
-				Count:   count,
-			}); err != nil {
-				log.Printf("Error sending summary response: %v", err)
-				return err
-			}
+	return &notesv1.SetNotePassphraseResponse{
+		Note: converter.ModelToProto(note),
+	}, nil
+}
 
-			log.Println("Successfully sent summary response")
-			return nil
-		}
-		if err != nil {
-			log.Printf("Error receiving metric: %v", err)
-			return err
+// BatchCreateNotes создает несколько заметок за один запрос
+func (h *Handler) BatchCreateNotes(ctx context.Context, req *notesv1.BatchCreateNotesRequest) (*notesv1.BatchCreateNotesResponse, error) {
+	notes := make([]model.Note, len(req.GetNotes()))
+	for i, item := range req.GetNotes() {
+		notes[i] = model.Note{
+			Title:            item.GetTitle(),
+			Content:          item.GetContent(),
+			Tags:             item.GetTags(),
+			IsE2E:            item.GetIsE2E(),
+			E2EScheme:        item.GetE2EScheme(),
+			ContentEncrypted: item.GetContentEncrypted(),
+			RemindAt:         timeOrZero(item.GetRemindAt()),
 		}
+	}
 
-		// Накопление данных
-		sum += metric.GetValue()
-		count++
-
-		log.Printf("Received metric: name=%s, value=%.2f (count=%d)",
-			metric.GetName(), metric.GetValue(), count)
+	// Вызываем бизнес-логику
+	results, err := h.noteService.BatchCreate(ctx, notes, req.GetAtomic())
+	if err != nil {
+		return nil, h.statusError(err)
 	}
-}
 
-// Chat обрабатывает bidirectional streaming - асинхронный чат с correlation ID
-func (h *Handler) Chat(stream notesv1.NotesService_ChatServer) error {
-	ctx := stream.Context()
-	errChan := make(chan error, 2)
-	var wg sync.WaitGroup
+	return &notesv1.BatchCreateNotesResponse{
+		Results: h.batchResultsToProto(results, true),
+	}, nil
+}
 
-	log.Println("Chat stream established")
+// BatchGetNotes возвращает несколько заметок по списку UUID
+func (h *Handler) BatchGetNotes(ctx context.Context, req *notesv1.BatchGetNotesRequest) (*notesv1.BatchGetNotesResponse, error) {
+	ctx, warnings := svc.WithWarnings(ctx)
 
-	// Горутина для чтения сообщений от клиента
-	wg.Add(1)
-	go func() {
-		defer wg.Done()
-		for {
-			msg, err := stream.Recv()
-			if err == io.EOF {
-				log.Println("Client closed send stream")
-				return
-			}
-			if err != nil {
-				errChan <- fmt.Errorf("error receiving message: %w", err)
-				return
-			}
+	// Вызываем бизнес-логику
+	results, err := h.noteService.BatchGet(ctx, req.GetIds())
+	if err != nil {
+		return nil, h.statusError(err)
+	}
 
-			correlationID := msg.GetCorrelationId()
+	return &notesv1.BatchGetNotesResponse{
+		Results:  h.batchResultsToProto(results, true),
+		Warnings: converter.WarningsToProto(warnings.List()),
+	}, nil
+}
 
-			// Обработка входящего сообщения через one-of
-			switch content := msg.GetContent().(type) {
-			case *notesv1.ChatMessage_TextMessage:
-				// Получено текстовое сообщение
-				text := content.TextMessage.GetText()
-				log.Printf("📥 Received text message: correlation_id=%s, text=%s",
-					correlationID, text)
+// BatchDeleteNotes удаляет несколько заметок за один запрос
+func (h *Handler) BatchDeleteNotes(ctx context.Context, req *notesv1.BatchDeleteNotesRequest) (*notesv1.BatchDeleteNotesResponse, error) {
+	// Вызываем бизнес-логику
+	results, err := h.noteService.BatchDelete(ctx, req.GetIds(), req.GetAtomic())
+	if err != nil {
+		return nil, h.statusError(err)
+	}
 
-				// Валидация: если текст пустой, отправляем бизнесовую ошибку через one-of
-				if strings.TrimSpace(text) == "" {
-					errorResponse := &notesv1.ChatMessage{
-						CorrelationId: correlationID,
-						Content: &notesv1.ChatMessage_Error{
-							Error: &notesv1.ChatError{
-								Code:    notesv1.ChatErrorCode_CHAT_ERROR_CODE_VALIDATION_ERROR,
-								Message: "Message text cannot be empty",
-								Details: "The text field must contain at least one non-whitespace character",
-							},
-						},
-					}
+	return &notesv1.BatchDeleteNotesResponse{
+		Results: h.batchResultsToProto(results, false),
+	}, nil
+}
 
-					if err := stream.Send(errorResponse); err != nil {
-						errChan <- fmt.Errorf("error sending validation error: %w", err)
-						return
-					}
+// batchResultsToProto конвертирует результаты пакетной операции в proto
+// Ошибки элементов конвертируются тем же statusError, что и ошибки одиночных запросов
+func (h *Handler) batchResultsToProto(results []model.BatchResult, withNote bool) []*notesv1.BatchNoteResult {
+	protoResults := make([]*notesv1.BatchNoteResult, len(results))
+	for i, result := range results {
+		protoResult := &notesv1.BatchNoteResult{
+			Id:     result.ID,
+			Status: status.Convert(h.statusError(result.Err)).Proto(),
+		}
+		if withNote && result.Err == nil {
+			protoResult.Note = converter.ModelToProto(result.Note)
+		}
+		protoResults[i] = protoResult
+	}
 
-					log.Printf("📤 Sent validation error: correlation_id=%s", correlationID)
-					continue // Продолжаем работу, соединение не разрывается
-				}
+	return protoResults
+}
 
-				// Отправить подтверждение с тем же correlation_id через text_message
-				response := &notesv1.ChatMessage{
-					CorrelationId: correlationID,
-					Content: &notesv1.ChatMessage_TextMessage{
-						TextMessage: &notesv1.ChatTextMessage{
-							Text:      fmt.Sprintf("Acknowledged: %s", text),
-							Timestamp: timestamppb.Now(),
-						},
-					},
-				}
+// ListNoteRevisions возвращает историю изменений заметки
+func (h *Handler) ListNoteRevisions(ctx context.Context, req *notesv1.ListNoteRevisionsRequest) (*notesv1.ListNoteRevisionsResponse, error) {
+	// Вызываем бизнес-логику
+	revisions, err := h.noteService.ListRevisions(withNotePassphrase(ctx), req.GetId())
+	if err != nil {
+		return nil, h.statusError(err)
+	}
 
-				if err := stream.Send(response); err != nil {
-					errChan <- fmt.Errorf("error sending acknowledgment: %w", err)
-					return
-				}
+	return &notesv1.ListNoteRevisionsResponse{
+		Revisions: converter.RevisionsToProtos(revisions),
+	}, nil
+}
 
-				log.Printf("📤 Sent acknowledgment: correlation_id=%s", correlationID)
+// GetNoteRevision возвращает конкретную ревизию заметки
+func (h *Handler) GetNoteRevision(ctx context.Context, req *notesv1.GetNoteRevisionRequest) (*notesv1.GetNoteRevisionResponse, error) {
+	// Вызываем бизнес-логику
+	revision, err := h.noteService.GetRevision(withNotePassphrase(ctx), req.GetId(), req.GetRevision())
+	if err != nil {
+		return nil, h.statusError(err)
+	}
 
-			case *notesv1.ChatMessage_Error:
-				// Получена ошибка от клиента (если клиент отправляет ошибки)
-				log.Printf("📥 Received error from client: correlation_id=%s, code=%v, message=%s",
-					correlationID, content.Error.GetCode(), content.Error.GetMessage())
-				// Можно обработать ошибку от клиента, но обычно клиент не отправляет ошибки
+	return &notesv1.GetNoteRevisionResponse{
+		Revision: converter.RevisionToProto(revision),
+	}, nil
+}
 
-			case nil:
-				// Content не установлен (старое сообщение или ошибка десериализации)
-				log.Printf("⚠️ Received message without content: correlation_id=%s", correlationID)
-				errorResponse := &notesv1.ChatMessage{
-					CorrelationId: correlationID,
-					Content: &notesv1.ChatMessage_Error{
-						Error: &notesv1.ChatError{
-							Code:    notesv1.ChatErrorCode_CHAT_ERROR_CODE_INVALID_MESSAGE,
-							Message: "Message content is missing",
-							Details: "The message must contain either text_message or error",
-						},
-					},
-				}
+// DiffNoteRevisions возвращает построчные различия содержимого двух ревизий заметки
+func (h *Handler) DiffNoteRevisions(ctx context.Context, req *notesv1.DiffNoteRevisionsRequest) (*notesv1.DiffNoteRevisionsResponse, error) {
+	contextLines := notesService.DefaultDiffContextLines
+	if req.ContextLines != nil {
+		contextLines = int(req.GetContextLines())
+	}
 
-				if err := stream.Send(errorResponse); err != nil {
-					errChan <- fmt.Errorf("error sending invalid message error: %w", err)
-					return
-				}
-			}
+	// Вызываем бизнес-логику
+	result, err := h.noteService.DiffRevisions(withNotePassphrase(ctx), svc.DiffRevisionsInput{
+		ID:           req.GetId(),
+		FromRevision: req.GetFromRevision(),
+		ToRevision:   req.GetToRevision(),
+		ContextLines: contextLines,
+	})
+	if err != nil {
+		return nil, h.statusError(err)
+	}
 
-			// Проверка отмены контекста
-			// Проверяем оба контекста:
-			// - ctx (stream.Context()) - отменяется при отключении клиента
-			// - h.serverCtx - отменяется при shutdown сервера
-			select {
-			case <-ctx.Done():
-				log.Printf("Client disconnected in receive goroutine")
-				return
-			case <-h.serverCtx.Done():
-				log.Printf("Server shutdown in receive goroutine")
-				return
-			default:
-				// Продолжаем работу
-			}
-		}
-	}()
+	resp := converter.RevisionDiffToProto(result)
+	if req.GetFormat() == notesv1.DiffFormat_DIFF_FORMAT_UNIFIED {
+		resp.Hunks = nil
+		resp.UnifiedDiff = diff.Unified(
+			fmt.Sprintf("%s/revisions/%d", result.NoteID, result.FromRevision),
+			fmt.Sprintf("%s/revisions/%d", result.NoteID, result.ToRevision),
+			result.Hunks,
+		)
+	}
+	return resp, nil
+}
 
-	// Горутина для отправки независимых уведомлений
-	wg.Add(1)
-	go func() {
-		defer wg.Done()
-		ticker := time.NewTicker(5 * time.Second)
-		defer ticker.Stop()
-		notificationCounter := int64(0)
+// ListNotesByTag возвращает заметки с указанным тегом
+func (h *Handler) ListNotesByTag(ctx context.Context, req *notesv1.ListNotesByTagRequest) (*notesv1.ListNotesByTagResponse, error) {
+	ctx, warnings := svc.WithWarnings(ctx)
 
-		for {
-			select {
-			case <-ticker.C:
-				notificationCounter++
-				notification := &notesv1.ChatMessage{
-					CorrelationId: fmt.Sprintf("notification-%d", notificationCounter),
-					Content: &notesv1.ChatMessage_TextMessage{
-						TextMessage: &notesv1.ChatTextMessage{
-							Text:      fmt.Sprintf("Server notification #%d", notificationCounter),
-							Timestamp: timestamppb.Now(),
-						},
-					},
-				}
+	// Вызываем бизнес-логику
+	notes, err := h.noteService.ListByTag(ctx, req.GetTag())
+	if err != nil {
+		return nil, h.statusError(err)
+	}
 
-				if err := stream.Send(notification); err != nil {
-					errChan <- fmt.Errorf("error sending notification: %w", err)
-					return
-				}
+	return &notesv1.ListNotesByTagResponse{
+		Notes:    converter.ModelsToProtos(notes),
+		Warnings: converter.WarningsToProto(warnings.List()),
+	}, nil
+}
 
-				log.Printf("📤 Sent notification: correlation_id=%s", notification.GetCorrelationId())
+// ListTags возвращает все теги с количеством заметок
+func (h *Handler) ListTags(ctx context.Context, req *notesv1.ListTagsRequest) (*notesv1.ListTagsResponse, error) {
+	ctx, warnings := svc.WithWarnings(ctx)
 
-			case <-ctx.Done():
-				// Клиент отключился
-				log.Printf("Client disconnected in send goroutine")
-				return
-			case <-h.serverCtx.Done():
-				// Сервер завершает работу (graceful shutdown)
-				log.Printf("Server shutdown in send goroutine")
-				return
+	// Вызываем бизнес-логику
+	counts, err := h.noteService.ListTags(ctx)
+	if err != nil {
+		return nil, h.statusError(err)
+	}
+
+	return &notesv1.ListTagsResponse{
+		Tags:     converter.TagCountsToProto(counts),
+		Warnings: converter.WarningsToProto(warnings.List()),
+	}, nil
+}
+
+// ShareNote предоставляет пользователю доступ к заметке вызывающего пользователя
+func (h *Handler) ShareNote(ctx context.Context, req *notesv1.ShareNoteRequest) (*notesv1.ShareNoteResponse, error) {
+	// Вызываем бизнес-логику
+	share, err := h.noteService.Share(ctx, req.GetNoteId(), req.GetUserId(), converter.SharePermissionFromProto(req.GetPermission()))
+	if err != nil {
+		return nil, h.statusError(err)
+	}
+
+	return &notesv1.ShareNoteResponse{
+		Share: converter.ShareToProto(share),
+	}, nil
+}
+
+// UnshareNote отзывает доступ пользователя к заметке вызывающего пользователя
+func (h *Handler) UnshareNote(ctx context.Context, req *notesv1.UnshareNoteRequest) (*notesv1.UnshareNoteResponse, error) {
+	// Вызываем бизнес-логику
+	if err := h.noteService.Unshare(ctx, req.GetNoteId(), req.GetUserId()); err != nil {
+		return nil, h.statusError(err)
+	}
+
+	return &notesv1.UnshareNoteResponse{}, nil
+}
+
+// ListSharedNotes возвращает заметки других пользователей, доступные вызывающему пользователю
+func (h *Handler) ListSharedNotes(ctx context.Context, req *notesv1.ListSharedNotesRequest) (*notesv1.ListSharedNotesResponse, error) {
+	// Вызываем бизнес-логику
+	notes, err := h.noteService.ListShared(ctx)
+	if err != nil {
+		return nil, h.statusError(err)
+	}
+
+	return &notesv1.ListSharedNotesResponse{
+		Notes: converter.SharedNotesToProto(notes),
+	}, nil
+}
+
+// GetServerInfo возвращает возможности сервера
+// Состояние резервного копирования возвращается только администраторам
+func (h *Handler) GetServerInfo(ctx context.Context, req *notesv1.GetServerInfoRequest) (*notesv1.GetServerInfoResponse, error) {
+	return &notesv1.GetServerInfoResponse{
+		E2ESchemes: model.SupportedE2ESchemes(),
+		Backup:     h.backupStatus(ctx),
+	}, nil
+}
+
+// AdminListAllNotes возвращает заметки всех пользователей (только для роли admin)
+func (h *Handler) AdminListAllNotes(ctx context.Context, req *notesv1.AdminListAllNotesRequest) (*notesv1.AdminListAllNotesResponse, error) {
+	// Вызываем бизнес-логику, роль проверяется в сервисе
+	notes, err := h.noteService.ListAll(ctx)
+	if err != nil {
+		return nil, h.statusError(err)
+	}
+
+	return &notesv1.AdminListAllNotesResponse{
+		Notes: converter.ModelsToProtos(notes),
+	}, nil
+}
+
+// SubscribeToEvents подписывается на события заметок (server-side streaming)
+// С since_event_id, since_timestamp или resume_token сначала отправляются пропущенные события из журнала
+// При остановке сервера стрим завершается сообщением StreamGoAway с resume_token для переподключения
+func (h *Handler) SubscribeToEvents(req *notesv1.SubscribeToEventsRequest, stream notesv1.NotesService_SubscribeToEventsServer) error {
+	if err := checkFeature(stream.Context(), tenant.FeatureEvents); err != nil {
+		return err
+	}
+
+	// 1. Получаем EventService из noteService через интерфейс
+	provider, ok := h.noteService.(eventServiceProvider)
+	if !ok {
+		return status.Errorf(codes.Internal, "event service not available")
+	}
+
+	// Пустой фильтр - все типы событий
+	eventTypes := make(map[notesService.EventType]bool, len(req.GetEventTypes()))
+	for _, eventType := range req.GetEventTypes() {
+		eventTypes[eventTypeFromProto(eventType)] = true
+	}
+
+	// Переподключившийся клиент сначала получает пропущенные события из журнала
+	// resumeAt - время, после которого клиенту еще не отправлены события (для resume_token)
+	eventService := provider.GetEventService()
+	var (
+		eventCh  chan notesService.Event
+		missed   []notesService.Event
+		resumeAt = time.Now()
+		err      error
+	)
+	switch since := req.GetSince().(type) {
+	case *notesv1.SubscribeToEventsRequest_SinceEventId:
+		eventCh, missed, err = eventService.SubscribeAfter(since.SinceEventId)
+	case *notesv1.SubscribeToEventsRequest_SinceTimestamp:
+		resumeAt = since.SinceTimestamp.AsTime()
+		eventCh, missed, err = eventService.SubscribeSince(resumeAt)
+	case *notesv1.SubscribeToEventsRequest_ResumeToken:
+		if resumeAt, err = decodeResumeToken(since.ResumeToken); err != nil {
+			return err
+		}
+		eventCh, missed, err = eventService.SubscribeSince(resumeAt)
+	default:
+		eventCh = eventService.Subscribe()
+	}
+	if err != nil {
+		return h.statusError(err)
+	}
+	defer eventService.Unsubscribe(eventCh)
+
+	// 2. Отправить приветственное сообщение (health-check) сразу после подключения
+	if err := stream.Send(healthCheckEvent("Connected to events stream")); err != nil {
+		return err
+	}
+
+	ctx := stream.Context()
+	principal, _ := auth.FromContext(ctx)
+	var lastEventID uint64
+	send := func(event notesService.Event) error {
+		lastEventID = event.ID
+		resumeAt = event.Time
+
+		// Пользователь получает события только о своих заметках и открытых ему,
+		// анонимный подписчик - только о заметках без владельца (сервер без аутентификации)
+		if !event.AddressedTo(principal.UserID) {
+			return nil
+		}
+		if len(eventTypes) > 0 && !eventTypes[event.Type] {
+			return nil
+		}
+
+		// Конвертируем в proto и отправляем событие
+		// Используем полную заметку (более информативный вариант), кроме содержимого защищенной:
+		// стрим событий не принимает парольную фразу
+		note := notesService.RedactNote(event.Note)
+		var protoNote *notesv1.Note
+		if h.pooledMessages {
+			// stream.Send сериализует сообщение синхронно, поэтому без stats handler'ов proto заметку
+			// можно вернуть в пул сразу после отправки
+			var release func()
+			protoNote, release = converter.ModelToProtoPooled(note)
+			defer release()
+		} else {
+			protoNote = converter.ModelToProto(note)
+		}
+		resp := h.eventToProto(event, protoNote)
+		resp.EventId = event.ID
+		resp.EventTime = timestamppb.New(event.Time)
+		return stream.Send(resp)
+	}
+
+	for _, event := range missed {
+		if err := send(event); err != nil {
+			return err
+		}
+	}
+
+	// 3. Периодические health-check сообщения отправляются из основного цикла:
+	// stream.Send нельзя вызывать из нескольких горутин одновременно
+	heartbeatInterval := time.Duration(h.heartbeatInterval.Load())
+	if req.GetDisableHeartbeats() {
+		heartbeatInterval = 0
+	}
+	heartbeats, stopHeartbeats := streamutil.Heartbeat(heartbeatInterval)
+	defer stopHeartbeats()
+
+	// 4. Основной цикл обработки событий
+	// Проверяем оба контекста:
+	// - ctx (stream.Context()) - отменяется при отключении клиента
+	// - h.serverCtx - отменяется при shutdown сервера
+	for {
+		select {
+		case event, ok := <-eventCh:
+			// Канал закрыт: клиент не успевал получать события и был отключен от EventService
+			if !ok {
+				return status.Errorf(codes.Aborted,
+					"events stream fell behind; resubscribe with since_event_id=%d to receive missed events", lastEventID)
+			}
+			if err := send(event); err != nil {
+				return err
 			}
+
+		case <-heartbeats:
+			if err := stream.Send(healthCheckEvent("Health check")); err != nil {
+				return err
+			}
+		case <-ctx.Done():
+			// Стрим превысил лимит длительности (server.stream_lifetimes): как при остановке сервера,
+			// клиент получает go_away и переподключается с resume_token
+			if err := interceptors.StreamLifetimeExceeded(ctx); err != nil {
+				log.Printf("Events stream lifetime exceeded: %v", err)
+				goAway := goAwayEvent(status.Convert(err).Message(), resumeAt, lastEventID)
+				if err := streamutil.SendWithTimeout(context.Background(), stream, goAway, goAwaySendTimeout); err != nil {
+					log.Printf("Failed to send go away to events stream: %v", err)
+				}
+				return err
+			}
+			// Клиент отключился
+			log.Printf("Client disconnected from events stream")
+			return nil
+		case <-h.serverCtx.Done():
+			// Сервер завершает работу (graceful shutdown): клиент переподключится к другой реплике с resume_token
+			// Клиент, который не читает стрим, не задерживает остановку дольше goAwaySendTimeout
+			log.Printf("Server shutdown during events stream")
+			goAway := goAwayEvent("server is shutting down", resumeAt, lastEventID)
+			if err := streamutil.SendWithTimeout(ctx, stream, goAway, goAwaySendTimeout); err != nil {
+				log.Printf("Failed to send go away to events stream: %v", err)
+			}
+			return h.serverCtx.Err()
 		}
-	}()
+	}
+}
 
-	// Ожидание завершения горутин или ошибки
-	go func() {
-		wg.Wait()
-		close(errChan)
-	}()
+// eventToProto конвертирует событие заметки в сообщение стрима SubscribeToEvents
+func (h *Handler) eventToProto(event notesService.Event, protoNote *notesv1.Note) *notesv1.EventResponse {
+	switch event.Type {
+	case notesService.EventNoteReminderDue:
+		return &notesv1.EventResponse{
+			Event: &notesv1.EventResponse_NoteReminderDue{
+				NoteReminderDue: &notesv1.NoteReminderDue{
+					Note:     protoNote,
+					RemindAt: protoNote.GetRemindAt(),
+				},
+			},
+		}
+	case notesService.EventExportCompleted:
+		return &notesv1.EventResponse{
+			Event: &notesv1.EventResponse_ExportCompleted{
+				ExportCompleted: &notesv1.ExportCompletedEvent{
+					Operation: h.exportOperationToProto(event.Export),
+				},
+			},
+		}
+	case notesService.EventNoteUpdated:
+		return &notesv1.EventResponse{
+			Event: &notesv1.EventResponse_NoteUpdated{
+				NoteUpdated: &notesv1.NoteUpdatedEvent{Note: protoNote},
+			},
+		}
+	case notesService.EventNoteDeleted:
+		return &notesv1.EventResponse{
+			Event: &notesv1.EventResponse_NoteDeleted{
+				NoteDeleted: &notesv1.NoteDeletedEvent{NoteId: event.Note.ID},
+			},
+		}
+	case notesService.EventNoteArchived:
+		return &notesv1.EventResponse{
+			Event: &notesv1.EventResponse_NoteArchived{
+				NoteArchived: &notesv1.NoteArchivedEvent{Note: protoNote},
+			},
+		}
+	case notesService.EventNoteShared:
+		return &notesv1.EventResponse{
+			Event: &notesv1.EventResponse_NoteShared{
+				NoteShared: &notesv1.NoteSharedEvent{
+					Note:  protoNote,
+					Share: converter.ShareToProto(event.Share),
+				},
+			},
+		}
+	case notesService.EventSavedSearchMatched:
+		return &notesv1.EventResponse{
+			Event: &notesv1.EventResponse_SavedSearchMatched{
+				SavedSearchMatched: &notesv1.SavedSearchMatchedEvent{
+					SavedSearch: converter.SavedSearchToProto(event.Search),
+					Note:        protoNote,
+				},
+			},
+		}
+	case notesService.EventQuotaWarning:
+		return &notesv1.EventResponse{
+			Event: &notesv1.EventResponse_QuotaWarning{
+				QuotaWarning: &notesv1.QuotaWarningEvent{
+					Used:             int32(event.Quota.Used),
+					Limit:            int32(event.Quota.Limit),
+					ThresholdPercent: int32(event.Quota.ThresholdPercent),
+					Note:             protoNote,
+				},
+			},
+		}
+	}
+
+	return &notesv1.EventResponse{
+		Event: &notesv1.EventResponse_NoteCreated{
+			NoteCreated: &notesv1.NoteCreatedEvent{
+				Payload: &notesv1.NoteCreatedEvent_Note{
+					Note: protoNote,
+				},
+			},
+		},
+	}
+}
+
+// eventTypeFromProto конвертирует тип события из фильтра SubscribeToEvents
+// Значения проверены валидацией запроса (defined_only, not_in: 0)
+func eventTypeFromProto(eventType notesv1.EventType) notesService.EventType {
+	switch eventType {
+	case notesv1.EventType_EVENT_TYPE_NOTE_UPDATED:
+		return notesService.EventNoteUpdated
+	case notesv1.EventType_EVENT_TYPE_NOTE_DELETED:
+		return notesService.EventNoteDeleted
+	case notesv1.EventType_EVENT_TYPE_NOTE_SHARED:
+		return notesService.EventNoteShared
+	case notesv1.EventType_EVENT_TYPE_NOTE_ARCHIVED:
+		return notesService.EventNoteArchived
+	case notesv1.EventType_EVENT_TYPE_NOTE_REMINDER_DUE:
+		return notesService.EventNoteReminderDue
+	case notesv1.EventType_EVENT_TYPE_EXPORT_COMPLETED:
+		return notesService.EventExportCompleted
+	case notesv1.EventType_EVENT_TYPE_SAVED_SEARCH_MATCHED:
+		return notesService.EventSavedSearchMatched
+	case notesv1.EventType_EVENT_TYPE_QUOTA_WARNING:
+		return notesService.EventQuotaWarning
+	default:
+		return notesService.EventNoteCreated
+	}
+}
+
+// UploadMetrics обрабатывает client-side streaming - загрузку метрик
+// Статистика считается как по всем метрикам, так и по каждому названию
+func (h *Handler) UploadMetrics(stream notesv1.NotesService_UploadMetricsServer) error {
+	aggregator := metrics.NewAggregator()
+	buffer := h.newMetricBuffer()
+
+	ctx := stream.Context()
+	log.Println("Starting to receive metrics stream...")
+
+	// Читаем метрики из стрима до io.EOF
+	// Перед каждым Recv проверяются оба контекста:
+	// - ctx (stream.Context()) - отменяется при отключении клиента
+	// - h.serverCtx - отменяется при shutdown сервера
+	recvCtx, cancel := streamutil.Merge(ctx, h.serverCtx)
+	defer cancel()
+	err := streamutil.RecvLoop(recvCtx, stream, func(metric *notesv1.MetricRequest) error {
+		// Накопление данных
+		if err := aggregator.Add(metric.GetName(), metric.GetValue()); err != nil {
+			return h.statusError(err)
+		}
+		if err := buffer.add(ctx, metric); err != nil {
+			return h.statusError(err)
+		}
+
+		log.Printf("Received metric: name=%s, value=%.2f (count=%d)",
+			metric.GetName(), metric.GetValue(), aggregator.Count())
+		return nil
+	})
+	if err != nil {
+		return h.streamError(ctx, "metrics", err)
+	}
+
+	// Клиент завершил отправку, сохраняем оставшиеся значения и вычисляем результат
+	if err := buffer.flush(ctx); err != nil {
+		return h.statusError(err)
+	}
+	summary := aggregator.Summary()
+	log.Printf("Received all metrics: count=%d, sum=%.2f, average=%.2f, names=%d",
+		summary.Count, summary.Sum, summary.Average, len(summary.Metrics))
+
+	// Отправляем финальный ответ
+	if err := stream.SendAndClose(converter.MetricsSummaryToProto(summary)); err != nil {
+		log.Printf("Error sending summary response: %v", err)
+		return err
+	}
+
+	log.Println("Successfully sent summary response")
+	return nil
+}
+
+// streamError записывает в лог причину завершения стрима name и возвращает ошибку для клиента:
+// отключение клиента (ctx - контекст стрима), остановку сервера или ошибку чтения и обработки
+func (h *Handler) streamError(ctx context.Context, name string, err error) error {
+	switch {
+	case h.serverCtx.Err() != nil:
+		log.Printf("Server shutdown during %s stream", name)
+		return h.serverCtx.Err()
+	case ctx.Err() != nil:
+		log.Printf("Client disconnected from %s stream", name)
+		return ctx.Err()
+	default:
+		log.Printf("Error in %s stream: %v", name, err)
+		return err
+	}
+}
+
+// Chat обрабатывает bidirectional streaming - обмен сообщениями в комнатах с correlation ID
+// Клиент входит в комнаты сообщениями join_room/leave_room и получает сообщения участников своих комнат,
+// в том числе свои собственные с исходным correlation_id (подтверждение доставки)
+func (h *Handler) Chat(stream notesv1.NotesService_ChatServer) error {
+	principal, _ := auth.FromContext(stream.Context())
+	participant := h.chatHub.Connect(principal.UserID)
+	defer participant.Close()
+
+	// Горутины чтения и отправки завершаются при отключении клиента (stream.Context())
+	// и при shutdown сервера (h.serverCtx), ошибка одной из них завершает стрим
+	ctx, cancel := streamutil.Merge(stream.Context(), h.serverCtx)
+	defer cancel()
+	group, ctx := streamutil.WithContext(ctx)
+
+	// Ответы, сообщения комнат и уведомления отправляются из разных горутин,
+	// а stream.Send нельзя вызывать конкурентно
+	var sendMu sync.Mutex
+	send := func(msg *notesv1.ChatMessage) error {
+		sendMu.Lock()
+		defer sendMu.Unlock()
+		return stream.Send(msg)
+	}
+
+	log.Println("Chat stream established")
+
+	// receiver читает сообщения клиента, отвечая на сообщения сверх лимита стрима
+	receiver := streamutil.RecvFunc[*notesv1.ChatMessage](func() (*notesv1.ChatMessage, error) {
+		for {
+			msg, err := stream.Recv()
+			if err == io.EOF {
+				log.Println("Client closed send stream")
+				return nil, err
+			}
+
+			// Превышен лимит сообщений стрима: сообщение отброшено, отвечаем бизнесовой ошибкой
+			// и продолжаем работу, соединение не разрывается
+			var rateLimitErr *interceptors.StreamRateLimitError
+			if errors.As(err, &rateLimitErr) {
+				dropped, _ := rateLimitErr.Message.(*notesv1.ChatMessage)
+				errorResponse := chatError(dropped, notesv1.ChatErrorCode_CHAT_ERROR_CODE_RATE_LIMIT,
+					"Too many messages, the message was dropped", rateLimitErr.Error())
+
+				if err := send(errorResponse); err != nil {
+					return nil, fmt.Errorf("error sending rate limit error: %w", err)
+				}
+
+				log.Printf("📤 Sent rate limit error: correlation_id=%s", dropped.GetCorrelationId())
+				continue
+			}
+			if err != nil {
+				return nil, fmt.Errorf("error receiving message: %w", err)
+			}
+			return msg, nil
+		}
+	})
+
+	// Горутина для чтения сообщений от клиента
+	// Участник, отключенный от комнат, завершает только чтение: горутина отправки завершит стрим
+	group.Go(func() error {
+		return streamutil.RecvLoop(ctx, receiver, func(msg *notesv1.ChatMessage) error {
+			correlationID := msg.GetCorrelationId()
+			roomID := msg.GetRoomId()
+
+			// Обработка входящего сообщения через one-of
+			// Ответ (подтверждение join/leave или бизнесовая ошибка) отправляется только отправителю
+			var response *notesv1.ChatMessage
+			switch content := msg.GetContent().(type) {
+			case *notesv1.ChatMessage_TextMessage:
+				// Получено текстовое сообщение
+				text := content.TextMessage.GetText()
+				log.Printf("📥 Received text message: correlation_id=%s, room_id=%s, text=%s",
+					correlationID, roomID, text)
+
+				// Валидация: если текст пустой, отправляем бизнесовую ошибку через one-of
+				if strings.TrimSpace(text) == "" {
+					response = chatError(msg, notesv1.ChatErrorCode_CHAT_ERROR_CODE_VALIDATION_ERROR,
+						"Message text cannot be empty",
+						"The text field must contain at least one non-whitespace character")
+					break
+				}
+
+				// Сообщение получают все участники комнаты, включая отправителя (см. горутину отправки)
+				_, err := participant.Send(roomID, correlationID, text)
+				switch {
+				case errors.Is(err, chat.ErrNotInRoom):
+					response = chatError(msg, notesv1.ChatErrorCode_CHAT_ERROR_CODE_NOT_IN_ROOM,
+						"Join the room before sending messages to it",
+						fmt.Sprintf("The stream is not a participant of room %q", roomID))
+				case err != nil:
+					// Участник отключен: горутина отправки завершит стрим
+					return streamutil.ErrStop
+				}
+
+			case *notesv1.ChatMessage_JoinRoom:
+				err := participant.Join(roomID)
+				switch {
+				case errors.Is(err, chat.ErrInvalidRoom):
+					response = chatError(msg, notesv1.ChatErrorCode_CHAT_ERROR_CODE_VALIDATION_ERROR,
+						"Invalid room_id", err.Error())
+				case errors.Is(err, chat.ErrTooManyRooms):
+					response = chatError(msg, notesv1.ChatErrorCode_CHAT_ERROR_CODE_TOO_MANY_ROOMS,
+						"Too many rooms joined", "Leave one of the rooms before joining another")
+				case err != nil:
+					return streamutil.ErrStop
+				default:
+					// Подтверждение содержит пользователей комнаты, дальше присутствие меняют PresenceUpdate
+					participants, err := participant.Participants(roomID)
+					if err != nil {
+						return streamutil.ErrStop
+					}
+					log.Printf("📥 Joined room: correlation_id=%s, room_id=%s, participants=%d", correlationID, roomID, len(participants))
+					response = &notesv1.ChatMessage{
+						CorrelationId: correlationID,
+						RoomId:        roomID,
+						Content: &notesv1.ChatMessage_JoinRoom{
+							JoinRoom: &notesv1.ChatJoinRoom{Participants: participants},
+						},
+					}
+				}
+
+			case *notesv1.ChatMessage_LeaveRoom:
+				err := participant.Leave(roomID)
+				switch {
+				case errors.Is(err, chat.ErrNotInRoom):
+					response = chatError(msg, notesv1.ChatErrorCode_CHAT_ERROR_CODE_NOT_IN_ROOM,
+						"Not a participant of the room",
+						fmt.Sprintf("The stream is not a participant of room %q", roomID))
+				case err != nil:
+					return streamutil.ErrStop
+				default:
+					log.Printf("📥 Left room: correlation_id=%s, room_id=%s", correlationID, roomID)
+					response = msg
+				}
+
+			case *notesv1.ChatMessage_TypingIndicator:
+				// Набор текста пересылается остальным участникам комнаты без подтверждения
+				err := participant.Typing(roomID, content.TypingIndicator.GetTyping())
+				switch {
+				case errors.Is(err, chat.ErrNotInRoom):
+					response = chatError(msg, notesv1.ChatErrorCode_CHAT_ERROR_CODE_NOT_IN_ROOM,
+						"Join the room before sending typing indicators to it",
+						fmt.Sprintf("The stream is not a participant of room %q", roomID))
+				case err != nil:
+					return streamutil.ErrStop
+				}
+
+			case *notesv1.ChatMessage_PresenceUpdate:
+				response = chatError(msg, notesv1.ChatErrorCode_CHAT_ERROR_CODE_INVALID_MESSAGE,
+					"Presence updates are sent by the server",
+					"Use join_room and leave_room to change presence in a room")
+
+			case *notesv1.ChatMessage_Error:
+				// Получена ошибка от клиента (если клиент отправляет ошибки)
+				log.Printf("📥 Received error from client: correlation_id=%s, code=%v, message=%s",
+					correlationID, content.Error.GetCode(), content.Error.GetMessage())
+				// Можно обработать ошибку от клиента, но обычно клиент не отправляет ошибки
+
+			case nil:
+				// Content не установлен (старое сообщение или ошибка десериализации)
+				log.Printf("⚠️ Received message without content: correlation_id=%s", correlationID)
+				response = chatError(msg, notesv1.ChatErrorCode_CHAT_ERROR_CODE_INVALID_MESSAGE,
+					"Message content is missing",
+					"The message must contain text_message, join_room, leave_room or typing_indicator")
+			}
+
+			if response != nil {
+				if err := send(response); err != nil {
+					return fmt.Errorf("error sending response: %w", err)
+				}
+				if response.GetError() != nil {
+					log.Printf("📤 Sent chat error: correlation_id=%s, code=%v", correlationID, response.GetError().GetCode())
+				}
+			}
+			return nil
+		})
+	})
+
+	// Горутина для отправки сообщений комнат и независимых уведомлений
+	group.Go(func() error {
+		notifications, stopNotifications := streamutil.Heartbeat(5 * time.Second)
+		defer stopNotifications()
+		notificationCounter := int64(0)
+
+		for {
+			select {
+			case msg, ok := <-participant.Messages():
+				if !ok {
+					if participant.Dropped() {
+						// Клиент не успевает читать сообщения комнат: завершаем стрим, а не теряем их молча
+						return status.Error(codes.ResourceExhausted, "chat stream is too slow, room messages were dropped")
+					}
+					return nil
+				}
+
+				if err := send(converter.ChatMessageToProto(msg)); err != nil {
+					return fmt.Errorf("error sending room message: %w", err)
+				}
+
+			case <-notifications:
+				notificationCounter++
+				notification := &notesv1.ChatMessage{
+					CorrelationId: fmt.Sprintf("notification-%d", notificationCounter),
+					Content: &notesv1.ChatMessage_TextMessage{
+						TextMessage: &notesv1.ChatTextMessage{
+							Text:      fmt.Sprintf("Server notification #%d", notificationCounter),
+							Timestamp: timestamppb.Now(),
+						},
+					},
+				}
+
+				if err := send(notification); err != nil {
+					return fmt.Errorf("error sending notification: %w", err)
+				}
+
+				log.Printf("📤 Sent notification: correlation_id=%s", notification.GetCorrelationId())
+
+			case <-ctx.Done():
+				// Сервер завершает работу (graceful shutdown): ошибка завершает стрим,
+				// не дожидаясь горутины чтения, заблокированной в Recv
+				if h.serverCtx.Err() != nil {
+					log.Printf("Server shutdown in send goroutine")
+					return h.serverCtx.Err()
+				}
+				// Стрим превысил лимит длительности или простоя (server.stream_lifetimes)
+				if err := interceptors.StreamLifetimeExceeded(ctx); err != nil {
+					return err
+				}
+				// Клиент отключился или горутина чтения завершилась с ошибкой
+				log.Printf("Client disconnected in send goroutine")
+				return nil
+			}
+		}
+	})
 
 	// Вернуть первую ошибку, если она есть, или nil при нормальном завершении
-	if err, ok := <-errChan; ok {
+	if err := group.Wait(); err != nil {
 		log.Printf("Chat stream error: %v", err)
 		return err
 	}
@@ -467,6 +1197,37 @@ func (h *Handler) Chat(stream notesv1.NotesService_ChatServer) error {
 	return nil
 }
 
+// chatError создает бизнесовую ошибку чата в ответ на сообщение msg (с его correlation_id и room_id)
+func chatError(msg *notesv1.ChatMessage, code notesv1.ChatErrorCode, message, details string) *notesv1.ChatMessage {
+	return &notesv1.ChatMessage{
+		CorrelationId: msg.GetCorrelationId(),
+		RoomId:        msg.GetRoomId(),
+		Content: &notesv1.ChatMessage_Error{
+			Error: &notesv1.ChatError{
+				Code:    code,
+				Message: message,
+				Details: details,
+			},
+		},
+	}
+}
+
+// checkFeature возвращает PermissionDenied, если функциональность отключена для тенанта запроса
+func checkFeature(ctx context.Context, feature string) error {
+	if settings, ok := tenant.FromContext(ctx); ok && !settings.FeatureEnabled(feature) {
+		return status.Errorf(codes.PermissionDenied, "feature %q is disabled for this tenant", feature)
+	}
+	return nil
+}
+
+// timeOrZero конвертирует необязательный Timestamp запроса, nil соответствует нулевому времени
+func timeOrZero(ts *timestamppb.Timestamp) time.Time {
+	if ts == nil {
+		return time.Time{}
+	}
+	return ts.AsTime()
+}
+
 // handleError конвертирует внутренние ошибки в gRPC статусы с детализацией
 func handleError(err error) error {
 	if err == nil {
@@ -484,6 +1245,339 @@ func handleError(err error) error {
 		return st.Err()
 	}
 
+	if errors.Is(err, memory.ErrVersionConflict) {
+		st := status.New(codes.Aborted, "note was modified concurrently")
+		errorDetails := &notesv1.ErrorDetails{
+			Reason:            fmt.Sprintf("Update rejected: %v. Re-read the note and retry", err),
+			InternalErrorCode: "VERSION_CONFLICT",
+		}
+		st, _ = st.WithDetails(errorDetails)
+		return st.Err()
+	}
+
+	if errors.Is(err, repository.ErrUnavailable) {
+		st := status.New(codes.Unavailable, "note storage is unavailable")
+		errorDetails := &notesv1.ErrorDetails{
+			Reason:            "The note storage is unavailable and the service is read-only; retry the request later",
+			InternalErrorCode: "REPOSITORY_UNAVAILABLE",
+		}
+		st, _ = st.WithDetails(errorDetails)
+		var unavailableErr *degraded.UnavailableError
+		if errors.As(err, &unavailableErr) {
+			st, _ = st.WithDetails(&errdetails.RetryInfo{RetryDelay: durationpb.New(unavailableErr.RetryAfter)})
+		}
+		return st.Err()
+	}
+
+	if errors.Is(err, notesService.ErrAtomicBatchNotSupported) {
+		st := status.New(codes.FailedPrecondition, "atomic batch not supported")
+		errorDetails := &notesv1.ErrorDetails{
+			Reason:            "The configured repository cannot execute batch operations atomically; retry with atomic=false",
+			InternalErrorCode: "ATOMIC_BATCH_NOT_SUPPORTED",
+		}
+		st, _ = st.WithDetails(errorDetails)
+		return st.Err()
+	}
+
+	if errors.Is(err, memory.ErrRevisionNotFound) {
+		st := status.New(codes.NotFound, "revision not found")
+		errorDetails := &notesv1.ErrorDetails{
+			Reason:            "The requested revision was not found in the note history",
+			InternalErrorCode: "REVISION_NOT_FOUND",
+		}
+		st, _ = st.WithDetails(errorDetails)
+		return st.Err()
+	}
+
+	if errors.Is(err, notesService.ErrEncryptedDiff) {
+		st := status.New(codes.FailedPrecondition, err.Error())
+		errorDetails := &notesv1.ErrorDetails{
+			Reason:            "The note is end-to-end encrypted; download both revisions and compare them on the client",
+			InternalErrorCode: "ENCRYPTED_CONTENT",
+		}
+		st, _ = st.WithDetails(errorDetails)
+		return st.Err()
+	}
+
+	if errors.Is(err, notesService.ErrDiffTooLarge) {
+		st := status.New(codes.ResourceExhausted, err.Error())
+		errorDetails := &notesv1.ErrorDetails{
+			Reason:            "The revisions are too long or differ in too many lines to be compared on the server; compare them on the client",
+			InternalErrorCode: "DIFF_TOO_LARGE",
+		}
+		st, _ = st.WithDetails(errorDetails)
+		return st.Err()
+	}
+
+	if errors.Is(err, attachments.ErrAttachmentNotFound) {
+		st := status.New(codes.NotFound, "attachment not found")
+		errorDetails := &notesv1.ErrorDetails{
+			Reason:            "The requested attachment was not found in the attachment storage",
+			InternalErrorCode: "ATTACHMENT_NOT_FOUND",
+		}
+		st, _ = st.WithDetails(errorDetails)
+		return st.Err()
+	}
+
+	if errors.Is(err, exports.ErrExportNotFound) {
+		st := status.New(codes.NotFound, err.Error())
+		errorDetails := &notesv1.ErrorDetails{
+			Reason:            "The export operation does not exist, has expired or was started by another user",
+			InternalErrorCode: "EXPORT_NOT_FOUND",
+		}
+		st, _ = st.WithDetails(errorDetails)
+		return st.Err()
+	}
+
+	if errors.Is(err, keys.ErrRotationNotFound) {
+		st := status.New(codes.NotFound, err.Error())
+		errorDetails := &notesv1.ErrorDetails{
+			Reason:            "The key rotation operation does not exist or has expired",
+			InternalErrorCode: "KEY_ROTATION_NOT_FOUND",
+		}
+		st, _ = st.WithDetails(errorDetails)
+		return st.Err()
+	}
+
+	if errors.Is(err, keys.ErrRotationInProgress) {
+		st := status.New(codes.FailedPrecondition, err.Error())
+		errorDetails := &notesv1.ErrorDetails{
+			Reason:            "Another key rotation is running; wait for it to complete",
+			InternalErrorCode: "KEY_ROTATION_IN_PROGRESS",
+		}
+		st, _ = st.WithDetails(errorDetails)
+		return st.Err()
+	}
+
+	if errors.Is(err, memory.ErrWebhookNotFound) {
+		st := status.New(codes.NotFound, err.Error())
+		errorDetails := &notesv1.ErrorDetails{
+			Reason:            "The webhook does not exist or was registered by another user",
+			InternalErrorCode: "WEBHOOK_NOT_FOUND",
+		}
+		st, _ = st.WithDetails(errorDetails)
+		return st.Err()
+	}
+
+	if errors.Is(err, memory.ErrSavedSearchNotFound) {
+		st := status.New(codes.NotFound, err.Error())
+		errorDetails := &notesv1.ErrorDetails{
+			Reason:            "The saved search does not exist or was saved by another user",
+			InternalErrorCode: "SAVED_SEARCH_NOT_FOUND",
+		}
+		st, _ = st.WithDetails(errorDetails)
+		return st.Err()
+	}
+
+	if errors.Is(err, backups.ErrBackupNotFound) {
+		st := status.New(codes.NotFound, err.Error())
+		errorDetails := &notesv1.ErrorDetails{
+			Reason:            "The backup does not exist or was removed by the retention policy",
+			InternalErrorCode: "BACKUP_NOT_FOUND",
+		}
+		st, _ = st.WithDetails(errorDetails)
+		return st.Err()
+	}
+
+	if errors.Is(err, backups.ErrRestoreConflict) {
+		st := status.New(codes.FailedPrecondition, err.Error())
+		errorDetails := &notesv1.ErrorDetails{
+			Reason:            "Notes from the backup already exist; choose the SKIP or OVERWRITE conflict strategy",
+			InternalErrorCode: "RESTORE_CONFLICT",
+		}
+		st, _ = st.WithDetails(errorDetails)
+		return st.Err()
+	}
+
+	if errors.Is(err, backups.ErrInvalidBackup) {
+		st := status.New(codes.DataLoss, err.Error())
+		errorDetails := &notesv1.ErrorDetails{
+			Reason:            "The backup archive is corrupted or has an unsupported format",
+			InternalErrorCode: "INVALID_BACKUP",
+		}
+		st, _ = st.WithDetails(errorDetails)
+		return st.Err()
+	}
+
+	if errors.Is(err, metrics.ErrTooManyMetrics) {
+		st := status.New(codes.ResourceExhausted, err.Error())
+		errorDetails := &notesv1.ErrorDetails{
+			Reason:            fmt.Sprintf("A metrics stream accepts at most %d distinct metric names", metrics.MaxNames),
+			InternalErrorCode: "TOO_MANY_METRICS",
+		}
+		st, _ = st.WithDetails(errorDetails)
+		return st.Err()
+	}
+
+	if errors.Is(err, webhooks.ErrTooManyWebhooks) {
+		st := status.New(codes.ResourceExhausted, err.Error())
+		errorDetails := &notesv1.ErrorDetails{
+			Reason:            "The user has registered the maximum number of webhooks; delete unused ones first",
+			InternalErrorCode: "TOO_MANY_WEBHOOKS",
+		}
+		st, _ = st.WithDetails(errorDetails)
+		return st.Err()
+	}
+
+	if errors.Is(err, searches.ErrTooManySavedSearches) {
+		st := status.New(codes.ResourceExhausted, err.Error())
+		errorDetails := &notesv1.ErrorDetails{
+			Reason:            "The user has saved the maximum number of searches; delete unused ones first",
+			InternalErrorCode: "TOO_MANY_SAVED_SEARCHES",
+		}
+		st, _ = st.WithDetails(errorDetails)
+		return st.Err()
+	}
+
+	if errors.Is(err, memory.ErrShareNotFound) {
+		st := status.New(codes.NotFound, err.Error())
+		errorDetails := &notesv1.ErrorDetails{
+			Reason:            "The user does not have access to the note",
+			InternalErrorCode: "SHARE_NOT_FOUND",
+		}
+		st, _ = st.WithDetails(errorDetails)
+		return st.Err()
+	}
+
+	if errors.Is(err, memory.ErrUserNotFound) {
+		st := status.New(codes.NotFound, err.Error())
+		errorDetails := &notesv1.ErrorDetails{
+			Reason:            "The user does not exist or is not visible to the caller",
+			InternalErrorCode: "USER_NOT_FOUND",
+		}
+		st, _ = st.WithDetails(errorDetails)
+		return st.Err()
+	}
+
+	if errors.Is(err, memory.ErrUserExists) {
+		st := status.New(codes.AlreadyExists, err.Error())
+		errorDetails := &notesv1.ErrorDetails{
+			Reason:            "A user with the same id or username already exists",
+			InternalErrorCode: "USER_ALREADY_EXISTS",
+		}
+		st, _ = st.WithDetails(errorDetails)
+		return st.Err()
+	}
+
+	if errors.Is(err, memory.ErrAPIKeyNotFound) {
+		st := status.New(codes.NotFound, err.Error())
+		errorDetails := &notesv1.ErrorDetails{
+			Reason:            "The api key does not exist",
+			InternalErrorCode: "API_KEY_NOT_FOUND",
+		}
+		st, _ = st.WithDetails(errorDetails)
+		return st.Err()
+	}
+
+	if errors.Is(err, notesService.ErrEventLogTruncated) {
+		st := status.New(codes.OutOfRange, err.Error())
+		errorDetails := &notesv1.ErrorDetails{
+			Reason:            "Some of the requested events are no longer available; reload the notes and subscribe without since",
+			InternalErrorCode: "EVENT_LOG_TRUNCATED",
+		}
+		st, _ = st.WithDetails(errorDetails)
+		return st.Err()
+	}
+
+	if errors.Is(err, notesService.ErrPassphraseRequired) {
+		st := status.New(codes.PermissionDenied, err.Error())
+		errorDetails := &notesv1.ErrorDetails{
+			Reason:            "The note is protected by a passphrase; pass it in the x-note-passphrase metadata (X-Note-Passphrase header)",
+			InternalErrorCode: "NOTE_PASSPHRASE_REQUIRED",
+		}
+		st, _ = st.WithDetails(errorDetails)
+		return st.Err()
+	}
+
+	if errors.Is(err, notesService.ErrPassphraseMismatch) {
+		st := status.New(codes.PermissionDenied, err.Error())
+		errorDetails := &notesv1.ErrorDetails{
+			Reason: fmt.Sprintf("The note passphrase is wrong; attempts are locked for %s after %d wrong passphrases in a row",
+				notesService.PassphraseLockout, notesService.MaxPassphraseFailures),
+			InternalErrorCode: "NOTE_PASSPHRASE_MISMATCH",
+		}
+		st, _ = st.WithDetails(errorDetails)
+		return st.Err()
+	}
+
+	var passphraseLockedErr *notesService.PassphraseLockedError
+	if errors.As(err, &passphraseLockedErr) {
+		st := status.New(codes.ResourceExhausted, "note passphrase attempts are locked")
+		errorDetails := &notesv1.ErrorDetails{
+			Reason: fmt.Sprintf("Too many wrong passphrases; retry after %s",
+				passphraseLockedErr.Until.UTC().Format(time.RFC3339)),
+			InternalErrorCode: "NOTE_PASSPHRASE_LOCKED",
+			NoteId:            passphraseLockedErr.NoteID,
+		}
+		st, _ = st.WithDetails(errorDetails)
+		st, _ = st.WithDetails(&errdetails.RetryInfo{RetryDelay: durationpb.New(time.Until(passphraseLockedErr.Until))})
+		return st.Err()
+	}
+
+	var lockedErr *notesService.LockedError
+	if errors.As(err, &lockedErr) {
+		lock := lockedErr.Lock
+		st := status.New(codes.FailedPrecondition, "note is locked")
+		errorDetails := &notesv1.ErrorDetails{
+			Reason: fmt.Sprintf("Note is locked by %s until %s; retry after the lock expires or ask the holder to unlock it",
+				lock.HolderID, lock.ExpiresAt.UTC().Format(time.RFC3339)),
+			InternalErrorCode: "NOTE_LOCKED",
+			NoteId:            lock.NoteID,
+		}
+		st, _ = st.WithDetails(errorDetails)
+		return st.Err()
+	}
+
+	if errors.Is(err, auth.ErrPermissionDenied) {
+		st := status.New(codes.PermissionDenied, err.Error())
+		errorDetails := &notesv1.ErrorDetails{
+			Reason:            "The caller is not allowed to perform this operation",
+			InternalErrorCode: "PERMISSION_DENIED",
+		}
+		st, _ = st.WithDetails(errorDetails)
+		return st.Err()
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) {
+		st := status.New(codes.DeadlineExceeded, "deadline exceeded")
+		errorDetails := &notesv1.ErrorDetails{
+			Reason:            "The request did not complete before its deadline; retry with a longer deadline or narrow the request",
+			InternalErrorCode: "DEADLINE_EXCEEDED",
+		}
+		st, _ = st.WithDetails(errorDetails)
+		return st.Err()
+	}
+
+	if errors.Is(err, notesService.ErrIdempotencyKeyReused) {
+		st := status.New(codes.FailedPrecondition, err.Error())
+		errorDetails := &notesv1.ErrorDetails{
+			Reason:            "The idempotency key was already used to create a note with different data",
+			InternalErrorCode: "IDEMPOTENCY_KEY_REUSED",
+		}
+		st, _ = st.WithDetails(errorDetails)
+		return st.Err()
+	}
+
+	if errors.Is(err, notesService.ErrNoteQuotaExceeded) {
+		st := status.New(codes.ResourceExhausted, err.Error())
+		errorDetails := &notesv1.ErrorDetails{
+			Reason:            "The tenant has reached its note quota",
+			InternalErrorCode: "NOTE_QUOTA_EXCEEDED",
+		}
+		st, _ = st.WithDetails(errorDetails)
+		return st.Err()
+	}
+
+	if errors.Is(err, notesService.ErrAttachmentTooLarge) {
+		st := status.New(codes.ResourceExhausted, err.Error())
+		errorDetails := &notesv1.ErrorDetails{
+			Reason:            "The uploaded attachment exceeds the configured maximum size",
+			InternalErrorCode: "ATTACHMENT_TOO_LARGE",
+		}
+		st, _ = st.WithDetails(errorDetails)
+		return st.Err()
+	}
+
 	// Проверяем ошибки валидации (содержат "cannot be empty")
 	errMsg := strings.ToLower(err.Error())
 	if strings.Contains(errMsg, "cannot be empty") || strings.Contains(errMsg, "invalid") {
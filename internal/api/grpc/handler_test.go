@@ -3,30 +3,63 @@ package grpc
 import (
 	"context"
 	"errors"
+	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
 
+	"notes-service/internal/auth"
+	"notes-service/internal/diff"
 	"notes-service/internal/model"
+	"notes-service/internal/repository/degraded"
 	"notes-service/internal/repository/memory"
+	svc "notes-service/internal/service"
+	"notes-service/internal/service/backups"
+	"notes-service/internal/service/exports"
+	notesService "notes-service/internal/service/notes"
+	"notes-service/internal/service/usage"
 	notesv1 "notes-service/pkg/proto/notes/v1"
 )
 
 // mockNoteService - мок сервиса для тестирования handler
 type mockNoteService struct {
-	createFunc func(ctx context.Context, title, content string) (model.Note, error)
-	getFunc    func(ctx context.Context, id string) (model.Note, error)
-	listFunc   func(ctx context.Context) ([]model.Note, error)
-	updateFunc func(ctx context.Context, id, title, content string) (model.Note, error)
-	deleteFunc func(ctx context.Context, id string) error
+	createFunc       func(ctx context.Context, input svc.CreateNoteInput) (model.Note, error)
+	getFunc          func(ctx context.Context, id string) (model.Note, error)
+	listFunc         func(ctx context.Context, opts svc.ListOptions) ([]model.Note, error)
+	forEachFunc      func(ctx context.Context, batchSize int, fn func(model.Note) error) error
+	forEachAfterFunc func(ctx context.Context, after string, batchSize int, fn func(model.Note) error) error
+	updateFunc       func(ctx context.Context, input svc.UpdateNoteInput) (model.Note, error)
+	deleteFunc       func(ctx context.Context, id string) error
+
+	batchCreateFunc func(ctx context.Context, notes []model.Note, atomic bool) ([]model.BatchResult, error)
+	batchGetFunc    func(ctx context.Context, ids []string) ([]model.BatchResult, error)
+	batchDeleteFunc func(ctx context.Context, ids []string, atomic bool) ([]model.BatchResult, error)
+
+	listRevisionsFunc func(ctx context.Context, id string) ([]model.NoteRevision, error)
+	getRevisionFunc   func(ctx context.Context, id string, revision int64) (model.NoteRevision, error)
+	diffRevisionsFunc func(ctx context.Context, input svc.DiffRevisionsInput) (svc.RevisionDiff, error)
+
+	listByTagFunc func(ctx context.Context, tag string) ([]model.Note, error)
+	listTagsFunc  func(ctx context.Context) ([]model.TagCount, error)
+
+	listAllFunc func(ctx context.Context) ([]model.Note, error)
+
+	shareFunc      func(ctx context.Context, noteID, userID string, permission model.SharePermission) (model.Share, error)
+	unshareFunc    func(ctx context.Context, noteID, userID string) error
+	listSharedFunc func(ctx context.Context) ([]model.SharedNote, error)
 }
 
-func (m *mockNoteService) Create(ctx context.Context, title, content string) (model.Note, error) {
+func (m *mockNoteService) Create(ctx context.Context, input svc.CreateNoteInput) (model.Note, error) {
 	if m.createFunc != nil {
-		return m.createFunc(ctx, title, content)
+		return m.createFunc(ctx, input)
 	}
 	return model.Note{}, nil
 }
@@ -38,16 +71,58 @@ func (m *mockNoteService) Get(ctx context.Context, id string) (model.Note, error
 	return model.Note{}, nil
 }
 
-func (m *mockNoteService) List(ctx context.Context) ([]model.Note, error) {
+func (m *mockNoteService) List(ctx context.Context, opts svc.ListOptions) ([]model.Note, error) {
 	if m.listFunc != nil {
-		return m.listFunc(ctx)
+		return m.listFunc(ctx, opts)
 	}
 	return nil, nil
 }
 
-func (m *mockNoteService) Update(ctx context.Context, id, title, content string) (model.Note, error) {
+func (m *mockNoteService) ForEach(ctx context.Context, batchSize int, fn func(model.Note) error) error {
+	if m.forEachFunc != nil {
+		return m.forEachFunc(ctx, batchSize, fn)
+	}
+	return nil
+}
+
+func (m *mockNoteService) ForEachAfter(ctx context.Context, after string, batchSize int, fn func(model.Note) error) error {
+	if m.forEachAfterFunc != nil {
+		return m.forEachAfterFunc(ctx, after, batchSize, fn)
+	}
+	return nil
+}
+
+func (m *mockNoteService) Pin(ctx context.Context, id string) (model.Note, error) {
+	return model.Note{}, nil
+}
+
+func (m *mockNoteService) Unpin(ctx context.Context, id string) (model.Note, error) {
+	return model.Note{}, nil
+}
+
+func (m *mockNoteService) Archive(ctx context.Context, id string) (model.Note, error) {
+	return model.Note{}, nil
+}
+
+func (m *mockNoteService) Unarchive(ctx context.Context, id string) (model.Note, error) {
+	return model.Note{}, nil
+}
+
+func (m *mockNoteService) Lock(ctx context.Context, id string, ttl time.Duration, force bool) (model.NoteLock, error) {
+	return model.NoteLock{}, nil
+}
+
+func (m *mockNoteService) Unlock(ctx context.Context, id string, force bool) error {
+	return nil
+}
+
+func (m *mockNoteService) SetPassphrase(ctx context.Context, id, passphrase string) (model.Note, error) {
+	return model.Note{}, nil
+}
+
+func (m *mockNoteService) Update(ctx context.Context, input svc.UpdateNoteInput) (model.Note, error) {
 	if m.updateFunc != nil {
-		return m.updateFunc(ctx, id, title, content)
+		return m.updateFunc(ctx, input)
 	}
 	return model.Note{}, nil
 }
@@ -59,6 +134,90 @@ func (m *mockNoteService) Delete(ctx context.Context, id string) error {
 	return nil
 }
 
+func (m *mockNoteService) BatchCreate(ctx context.Context, notes []model.Note, atomic bool) ([]model.BatchResult, error) {
+	if m.batchCreateFunc != nil {
+		return m.batchCreateFunc(ctx, notes, atomic)
+	}
+	return nil, nil
+}
+
+func (m *mockNoteService) BatchGet(ctx context.Context, ids []string) ([]model.BatchResult, error) {
+	if m.batchGetFunc != nil {
+		return m.batchGetFunc(ctx, ids)
+	}
+	return nil, nil
+}
+
+func (m *mockNoteService) BatchDelete(ctx context.Context, ids []string, atomic bool) ([]model.BatchResult, error) {
+	if m.batchDeleteFunc != nil {
+		return m.batchDeleteFunc(ctx, ids, atomic)
+	}
+	return nil, nil
+}
+
+func (m *mockNoteService) ListRevisions(ctx context.Context, id string) ([]model.NoteRevision, error) {
+	if m.listRevisionsFunc != nil {
+		return m.listRevisionsFunc(ctx, id)
+	}
+	return nil, nil
+}
+
+func (m *mockNoteService) GetRevision(ctx context.Context, id string, revision int64) (model.NoteRevision, error) {
+	if m.getRevisionFunc != nil {
+		return m.getRevisionFunc(ctx, id, revision)
+	}
+	return model.NoteRevision{}, nil
+}
+
+func (m *mockNoteService) DiffRevisions(ctx context.Context, input svc.DiffRevisionsInput) (svc.RevisionDiff, error) {
+	if m.diffRevisionsFunc != nil {
+		return m.diffRevisionsFunc(ctx, input)
+	}
+	return svc.RevisionDiff{}, nil
+}
+
+func (m *mockNoteService) ListByTag(ctx context.Context, tag string) ([]model.Note, error) {
+	if m.listByTagFunc != nil {
+		return m.listByTagFunc(ctx, tag)
+	}
+	return nil, nil
+}
+
+func (m *mockNoteService) ListTags(ctx context.Context) ([]model.TagCount, error) {
+	if m.listTagsFunc != nil {
+		return m.listTagsFunc(ctx)
+	}
+	return nil, nil
+}
+
+func (m *mockNoteService) ListAll(ctx context.Context) ([]model.Note, error) {
+	if m.listAllFunc != nil {
+		return m.listAllFunc(ctx)
+	}
+	return nil, nil
+}
+
+func (m *mockNoteService) Share(ctx context.Context, noteID, userID string, permission model.SharePermission) (model.Share, error) {
+	if m.shareFunc != nil {
+		return m.shareFunc(ctx, noteID, userID, permission)
+	}
+	return model.Share{}, nil
+}
+
+func (m *mockNoteService) Unshare(ctx context.Context, noteID, userID string) error {
+	if m.unshareFunc != nil {
+		return m.unshareFunc(ctx, noteID, userID)
+	}
+	return nil
+}
+
+func (m *mockNoteService) ListShared(ctx context.Context) ([]model.SharedNote, error) {
+	if m.listSharedFunc != nil {
+		return m.listSharedFunc(ctx)
+	}
+	return nil, nil
+}
+
 func TestGetNote_NotFoundWithDetails(t *testing.T) {
 	// Arrange
 	ctx := context.Background()
@@ -127,6 +286,38 @@ func TestGetNote_Success(t *testing.T) {
 	assert.Equal(t, expectedNote.Content, resp.Note.Content, "Expected note content to match")
 }
 
+func TestGetNote_ReadMask(t *testing.T) {
+	// Arrange
+	mockService := &mockNoteService{
+		getFunc: func(ctx context.Context, id string) (model.Note, error) {
+			return model.Note{ID: id, Title: "Title", Content: "one two three", WordCount: 3, ReadingTime: time.Second}, nil
+		},
+	}
+	handler := NewHandler(mockService, context.Background())
+	ctx := context.Background()
+
+	// Act & Assert: без маски вычисляемые поля не возвращаются
+	resp, err := handler.GetNote(ctx, &notesv1.GetNoteRequest{Id: "note-1"})
+	require.NoError(t, err)
+	assert.Equal(t, "one two three", resp.Note.Content)
+	assert.Zero(t, resp.Note.WordCount, "Expected computed fields to be hidden without read_mask")
+	assert.Nil(t, resp.Note.ReadingTime)
+
+	// С маской возвращаются ровно перечисленные поля
+	resp, err = handler.GetNote(ctx, &notesv1.GetNoteRequest{
+		Id:       "note-1",
+		ReadMask: &fieldmaskpb.FieldMask{Paths: []string{"id", "word_count", "reading_time"}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "note-1", resp.Note.Id)
+	assert.Empty(t, resp.Note.Content, "Expected fields outside read_mask to be cleared")
+	assert.Equal(t, int32(3), resp.Note.WordCount)
+	assert.Equal(t, time.Second, resp.Note.ReadingTime.AsDuration())
+
+	_, err = handler.GetNote(ctx, &notesv1.GetNoteRequest{Id: "note-1", ReadMask: &fieldmaskpb.FieldMask{Paths: []string{"words"}}})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
 func TestHandleError_NotFound(t *testing.T) {
 	// Arrange
 	err := memory.ErrNoteNotFound
@@ -195,3 +386,625 @@ func TestHandleError_InternalError(t *testing.T) {
 	assert.Contains(t, errorDetails.Reason, "internal error occurred", "Expected reason to contain internal error message")
 	assert.Equal(t, "INTERNAL_ERROR", errorDetails.InternalErrorCode, "Expected internal error code to be 'INTERNAL_ERROR'")
 }
+
+func TestGetNoteRevision_NotFound(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+
+	mockService := &mockNoteService{
+		getRevisionFunc: func(ctx context.Context, id string, revision int64) (model.NoteRevision, error) {
+			return model.NoteRevision{}, memory.ErrRevisionNotFound
+		},
+	}
+
+	handler := NewHandler(mockService, context.Background())
+
+	// Act
+	_, err := handler.GetNoteRevision(ctx, &notesv1.GetNoteRevisionRequest{Id: "note-id", Revision: 42})
+
+	// Assert
+	require.Error(t, err, "Expected error for non-existent revision")
+
+	st := status.Convert(err)
+	assert.Equal(t, codes.NotFound, st.Code(), "Expected NotFound status code")
+
+	require.Len(t, st.Details(), 1, "Expected exactly one detail in error")
+
+	errorDetails, ok := st.Details()[0].(*notesv1.ErrorDetails)
+	require.True(t, ok, "Expected detail to be of type ErrorDetails")
+	assert.Equal(t, "REVISION_NOT_FOUND", errorDetails.InternalErrorCode, "Expected internal error code to be 'REVISION_NOT_FOUND'")
+}
+
+func TestDiffNoteRevisions_Formats(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+
+	var gotInput svc.DiffRevisionsInput
+	mockService := &mockNoteService{
+		diffRevisionsFunc: func(ctx context.Context, input svc.DiffRevisionsInput) (svc.RevisionDiff, error) {
+			gotInput = input
+			lines, err := diff.Lines([]string{"a", "b"}, []string{"a", "c"}, 0)
+			require.NoError(t, err)
+			return svc.RevisionDiff{
+				NoteID:       input.ID,
+				FromRevision: input.FromRevision,
+				ToRevision:   input.ToRevision,
+				Hunks:        diff.Hunks(lines, input.ContextLines),
+				LinesAdded:   1,
+				LinesRemoved: 1,
+			}, nil
+		},
+	}
+
+	handler := NewHandler(mockService, context.Background())
+
+	// Act: структурированные блоки с контекстом по умолчанию
+	resp, err := handler.DiffNoteRevisions(ctx, &notesv1.DiffNoteRevisionsRequest{Id: "note-id", FromRevision: 1, ToRevision: 2})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, 3, gotInput.ContextLines, "Expected default context lines")
+	require.Len(t, resp.GetHunks(), 1)
+	kinds := []notesv1.DiffLineKind{}
+	for _, line := range resp.GetHunks()[0].GetLines() {
+		kinds = append(kinds, line.GetKind())
+	}
+	assert.Equal(t, []notesv1.DiffLineKind{
+		notesv1.DiffLineKind_DIFF_LINE_KIND_CONTEXT,
+		notesv1.DiffLineKind_DIFF_LINE_KIND_REMOVED,
+		notesv1.DiffLineKind_DIFF_LINE_KIND_ADDED,
+	}, kinds)
+	assert.Empty(t, resp.GetUnifiedDiff())
+
+	// Act: текст diff -u без контекста
+	contextLines := int32(0)
+	resp, err = handler.DiffNoteRevisions(ctx, &notesv1.DiffNoteRevisionsRequest{
+		Id: "note-id", FromRevision: 1, ToRevision: 2, ContextLines: &contextLines, Format: notesv1.DiffFormat_DIFF_FORMAT_UNIFIED,
+	})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, 0, gotInput.ContextLines, "Expected explicit zero context lines")
+	assert.Empty(t, resp.GetHunks())
+	assert.Equal(t, "--- note-id/revisions/1\n+++ note-id/revisions/2\n@@ -2 +2 @@\n-b\n+c\n", resp.GetUnifiedDiff())
+	assert.Equal(t, int32(1), resp.GetLinesAdded())
+}
+
+func TestHandleError_VersionConflict(t *testing.T) {
+	// Arrange
+	err := fmt.Errorf("%w: expected version 1, current version 2", memory.ErrVersionConflict)
+
+	// Act
+	grpcErr := handleError(err)
+
+	// Assert
+	require.Error(t, grpcErr, "Expected error")
+
+	st := status.Convert(grpcErr)
+	assert.Equal(t, codes.Aborted, st.Code(), "Expected Aborted status code")
+
+	require.Len(t, st.Details(), 1, "Expected exactly one detail in error")
+
+	errorDetails, ok := st.Details()[0].(*notesv1.ErrorDetails)
+	require.True(t, ok, "Expected detail to be of type ErrorDetails")
+
+	assert.Contains(t, errorDetails.Reason, "current version 2", "Expected reason to contain the current version")
+	assert.Equal(t, "VERSION_CONFLICT", errorDetails.InternalErrorCode, "Expected internal error code to be 'VERSION_CONFLICT'")
+}
+
+func TestHandleError_NoteLocked(t *testing.T) {
+	// Arrange
+	expiresAt := time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC)
+	err := fmt.Errorf("update: %w", &notesService.LockedError{Lock: model.NoteLock{NoteID: "note-1", HolderID: "bob", ExpiresAt: expiresAt}})
+
+	// Act
+	grpcErr := handleError(err)
+
+	// Assert
+	st := status.Convert(grpcErr)
+	assert.Equal(t, codes.FailedPrecondition, st.Code(), "Expected FailedPrecondition status code")
+	require.Len(t, st.Details(), 1, "Expected exactly one detail in error")
+
+	errorDetails, ok := st.Details()[0].(*notesv1.ErrorDetails)
+	require.True(t, ok, "Expected detail to be of type ErrorDetails")
+	assert.Equal(t, "NOTE_LOCKED", errorDetails.InternalErrorCode)
+	assert.Equal(t, "note-1", errorDetails.NoteId)
+	assert.Contains(t, errorDetails.Reason, "bob", "Expected reason to name the lock holder")
+	assert.Contains(t, errorDetails.Reason, "2030-01-02T03:04:05Z", "Expected reason to contain lock expiry")
+}
+
+func TestHandleError_RepositoryUnavailable(t *testing.T) {
+	// Arrange
+	err := fmt.Errorf("update: %w", &degraded.UnavailableError{Since: time.Now(), RetryAfter: 5 * time.Second})
+
+	// Act
+	grpcErr := handleError(err)
+
+	// Assert
+	st := status.Convert(grpcErr)
+	assert.Equal(t, codes.Unavailable, st.Code(), "Expected Unavailable status code")
+	require.Len(t, st.Details(), 2, "Expected ErrorDetails and RetryInfo in error")
+
+	errorDetails, ok := st.Details()[0].(*notesv1.ErrorDetails)
+	require.True(t, ok, "Expected detail to be of type ErrorDetails")
+	assert.Equal(t, "REPOSITORY_UNAVAILABLE", errorDetails.InternalErrorCode)
+
+	retryInfo, ok := st.Details()[1].(*errdetails.RetryInfo)
+	require.True(t, ok, "Expected detail to be of type RetryInfo")
+	assert.Equal(t, 5*time.Second, retryInfo.RetryDelay.AsDuration())
+}
+
+func TestHandleError_ExportNotFound(t *testing.T) {
+	// Act
+	grpcErr := handleError(exports.ErrExportNotFound)
+
+	// Assert
+	st := status.Convert(grpcErr)
+	assert.Equal(t, codes.NotFound, st.Code(), "Expected NotFound status code")
+	require.Len(t, st.Details(), 1, "Expected exactly one detail in error")
+
+	errorDetails, ok := st.Details()[0].(*notesv1.ErrorDetails)
+	require.True(t, ok, "Expected detail to be of type ErrorDetails")
+	assert.Equal(t, "EXPORT_NOT_FOUND", errorDetails.InternalErrorCode)
+}
+
+func TestHandleError_EventLogTruncated(t *testing.T) {
+	// Act
+	grpcErr := handleError(fmt.Errorf("%w: event 1 is outside of the log", notesService.ErrEventLogTruncated))
+
+	// Assert
+	st := status.Convert(grpcErr)
+	assert.Equal(t, codes.OutOfRange, st.Code(), "Expected OutOfRange status code")
+	require.Len(t, st.Details(), 1, "Expected exactly one detail in error")
+
+	errorDetails, ok := st.Details()[0].(*notesv1.ErrorDetails)
+	require.True(t, ok, "Expected detail to be of type ErrorDetails")
+	assert.Equal(t, "EVENT_LOG_TRUNCATED", errorDetails.InternalErrorCode)
+}
+
+func TestExportToDestination_NotConfigured(t *testing.T) {
+	// Arrange
+	handler := NewHandler(&mockNoteService{}, context.Background())
+
+	// Act
+	_, err := handler.ExportToDestination(context.Background(), &notesv1.ExportToDestinationRequest{
+		Archive: notesv1.ExportArchive_EXPORT_ARCHIVE_NDJSON,
+	})
+
+	// Assert
+	assert.Equal(t, codes.Unimplemented, status.Code(err), "Expected Unimplemented without export destination")
+}
+
+func TestRestoreBackup_NotConfigured(t *testing.T) {
+	// Arrange
+	handler := NewHandler(&mockNoteService{}, context.Background())
+
+	// Act
+	_, err := handler.RestoreBackup(context.Background(), &notesv1.RestoreBackupRequest{DryRun: true})
+	info, infoErr := handler.GetServerInfo(context.Background(), &notesv1.GetServerInfoRequest{})
+
+	// Assert
+	assert.Equal(t, codes.Unimplemented, status.Code(err), "Expected Unimplemented without backup destination")
+	require.NoError(t, infoErr)
+	assert.Nil(t, info.GetBackup(), "Expected no backup status without backup destination")
+}
+
+func TestGetUsageStats(t *testing.T) {
+	// Arrange
+	admin := auth.NewContext(context.Background(), auth.Principal{UserID: "admin", Roles: []string{auth.RoleAdmin}})
+	user := auth.NewContext(context.Background(), auth.Principal{UserID: "demo", Roles: []string{auth.RoleUser}})
+	collector := usage.NewCollector()
+	collector.RecordCall(notesv1.NotesService_CreateNote_FullMethodName, false)
+	collector.RecordCall(notesv1.NotesService_CreateNote_FullMethodName, true)
+	collector.RecordFeature("notes.e2e")
+
+	disabled := NewHandler(&mockNoteService{}, context.Background())
+	handler := NewHandler(&mockNoteService{}, context.Background(), WithUsageCollector(collector))
+
+	// Act
+	_, disabledErr := disabled.GetUsageStats(admin, &notesv1.GetUsageStatsRequest{})
+	_, userErr := handler.GetUsageStats(user, &notesv1.GetUsageStatsRequest{})
+	resp, err := handler.GetUsageStats(admin, &notesv1.GetUsageStatsRequest{})
+
+	// Assert
+	assert.Equal(t, codes.Unimplemented, status.Code(disabledErr), "Expected Unimplemented when usage stats are disabled")
+	assert.Equal(t, codes.PermissionDenied, status.Code(userErr), "Expected PermissionDenied for non-admin")
+	require.NoError(t, err)
+	require.Len(t, resp.GetMethods(), 1)
+	assert.Equal(t, notesv1.NotesService_CreateNote_FullMethodName, resp.GetMethods()[0].GetMethod())
+	assert.Equal(t, int64(2), resp.GetMethods()[0].GetCalls())
+	assert.Equal(t, int64(1), resp.GetMethods()[0].GetErrors())
+	require.Len(t, resp.GetFeatures(), 1)
+	assert.Equal(t, "notes.e2e", resp.GetFeatures()[0].GetFeature())
+	assert.False(t, resp.GetReporting().GetEnabled(), "Expected reporting to be disabled without endpoint")
+	assert.NotEmpty(t, resp.GetInstallationId())
+}
+
+func TestHandleError_RestoreConflict(t *testing.T) {
+	// Act
+	grpcErr := handleError(fmt.Errorf("%w: 2 of 3 notes", backups.ErrRestoreConflict))
+
+	// Assert
+	st := status.Convert(grpcErr)
+	assert.Equal(t, codes.FailedPrecondition, st.Code(), "Expected FailedPrecondition status code")
+	require.Len(t, st.Details(), 1, "Expected exactly one detail in error")
+
+	errorDetails, ok := st.Details()[0].(*notesv1.ErrorDetails)
+	require.True(t, ok, "Expected detail to be of type ErrorDetails")
+	assert.Equal(t, "RESTORE_CONFLICT", errorDetails.InternalErrorCode)
+}
+
+func TestBatchGetNotes_PartialFailure(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+
+	mockService := &mockNoteService{
+		batchGetFunc: func(ctx context.Context, ids []string) ([]model.BatchResult, error) {
+			return []model.BatchResult{
+				{ID: ids[0], Note: model.Note{ID: ids[0], Title: "Found"}},
+				{ID: ids[1], Err: memory.ErrNoteNotFound},
+			}, nil
+		},
+	}
+
+	handler := NewHandler(mockService, context.Background())
+
+	// Act
+	resp, err := handler.BatchGetNotes(ctx, &notesv1.BatchGetNotesRequest{Ids: []string{"found-id", "missing-id"}})
+
+	// Assert
+	require.NoError(t, err, "Expected partial failure to be reported per item")
+	require.Len(t, resp.Results, 2, "Expected one result per requested ID")
+
+	assert.Equal(t, int32(codes.OK), resp.Results[0].GetStatus().GetCode(), "Expected OK status for found note")
+	assert.Equal(t, "Found", resp.Results[0].GetNote().GetTitle(), "Expected found note in result")
+
+	assert.Equal(t, int32(codes.NotFound), resp.Results[1].GetStatus().GetCode(), "Expected NotFound status for missing note")
+	assert.Nil(t, resp.Results[1].GetNote(), "Expected no note for failed item")
+	assert.NotEmpty(t, resp.Results[1].GetStatus().GetDetails(), "Expected ErrorDetails in item status")
+}
+
+func TestAccessPolicy_ForeignNote(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     []HandlerOption
+		wantCode codes.Code
+	}{
+		{name: "default hides note", wantCode: codes.NotFound},
+		{name: "not_found", opts: []HandlerOption{WithAccessPolicy(AccessPolicyNotFound)}, wantCode: codes.NotFound},
+		{name: "permission_denied", opts: []HandlerOption{WithAccessPolicy(AccessPolicyPermissionDenied)}, wantCode: codes.PermissionDenied},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			ctx := context.Background()
+			mockService := &mockNoteService{
+				getFunc: func(ctx context.Context, id string) (model.Note, error) {
+					return model.Note{}, notesService.ErrNoteAccessDenied
+				},
+				deleteFunc: func(ctx context.Context, id string) error {
+					return notesService.ErrNoteAccessDenied
+				},
+				batchGetFunc: func(ctx context.Context, ids []string) ([]model.BatchResult, error) {
+					return []model.BatchResult{{ID: ids[0], Err: notesService.ErrNoteAccessDenied}}, nil
+				},
+			}
+			handler := NewHandler(mockService, context.Background(), tt.opts...)
+
+			// Act
+			_, getErr := handler.GetNote(ctx, &notesv1.GetNoteRequest{Id: "foreign-id"})
+			_, deleteErr := handler.DeleteNote(ctx, &notesv1.DeleteNoteRequest{Id: "foreign-id"})
+			batchResp, batchErr := handler.BatchGetNotes(ctx, &notesv1.BatchGetNotesRequest{Ids: []string{"foreign-id"}})
+
+			// Assert
+			assert.Equal(t, tt.wantCode, status.Code(getErr), "GetNote")
+			assert.Equal(t, tt.wantCode, status.Code(deleteErr), "DeleteNote")
+			require.NoError(t, batchErr)
+			require.Len(t, batchResp.Results, 1)
+			assert.Equal(t, int32(tt.wantCode), batchResp.Results[0].GetStatus().GetCode(), "BatchGetNotes item")
+		})
+	}
+}
+
+func TestAccessPolicy_NotFoundIsIndistinguishable(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	foreignErr := notesService.ErrNoteAccessDenied
+	mockService := &mockNoteService{
+		getFunc: func(ctx context.Context, id string) (model.Note, error) {
+			if id == "foreign-id" {
+				return model.Note{}, foreignErr
+			}
+			return model.Note{}, memory.ErrNoteNotFound
+		},
+	}
+	handler := NewHandler(mockService, context.Background())
+
+	// Act
+	_, foreign := handler.GetNote(ctx, &notesv1.GetNoteRequest{Id: "foreign-id"})
+	_, missing := handler.GetNote(ctx, &notesv1.GetNoteRequest{Id: "missing-id"})
+
+	// Assert: ответы отличаются только ID заметки
+	foreignSt, missingSt := status.Convert(foreign), status.Convert(missing)
+	assert.Equal(t, missingSt.Code(), foreignSt.Code())
+	assert.Equal(t, missingSt.Message(), foreignSt.Message())
+	require.Len(t, foreignSt.Details(), 1)
+	assert.NotContains(t, foreignSt.Details()[0].(*notesv1.ErrorDetails).GetReason(), foreignErr.Error())
+}
+
+func TestParseAccessPolicy(t *testing.T) {
+	for input, want := range map[string]AccessPolicy{
+		"":                  AccessPolicyNotFound,
+		"not_found":         AccessPolicyNotFound,
+		"permission_denied": AccessPolicyPermissionDenied,
+	} {
+		got, err := ParseAccessPolicy(input)
+		require.NoError(t, err, input)
+		assert.Equal(t, want, got, input)
+	}
+
+	_, err := ParseAccessPolicy("forbidden")
+	assert.Error(t, err)
+}
+
+func TestListNotes_TitleCollationFromAcceptLanguage(t *testing.T) {
+	// Arrange
+	var gotOpts svc.ListOptions
+	mockService := &mockNoteService{
+		listFunc: func(ctx context.Context, opts svc.ListOptions) ([]model.Note, error) {
+			gotOpts = opts
+			return nil, nil
+		},
+	}
+	handler := NewHandler(mockService, context.Background())
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("accept-language", "ru-RU,ru;q=0.9"))
+
+	// Act
+	_, err := handler.ListNotes(ctx, &notesv1.ListNotesRequest{})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, "ru-RU", gotOpts.TitleCollation, "Expected collation from Accept-Language")
+
+	// Явно заданный язык в запросе имеет приоритет
+	_, err = handler.ListNotes(ctx, &notesv1.ListNotesRequest{TitleCollation: "en"})
+	require.NoError(t, err)
+	assert.Equal(t, "en", gotOpts.TitleCollation, "Expected collation from request field")
+}
+
+func TestAttachments_NotConfigured(t *testing.T) {
+	// Arrange
+	handler := NewHandler(&mockNoteService{}, context.Background())
+
+	// Act
+	uploadErr := handler.UploadAttachment(nil)
+	downloadErr := handler.DownloadAttachment(&notesv1.DownloadAttachmentRequest{}, nil)
+
+	// Assert
+	assert.Equal(t, codes.Unimplemented, status.Code(uploadErr), "Expected Unimplemented without attachment storage")
+	assert.Equal(t, codes.Unimplemented, status.Code(downloadErr), "Expected Unimplemented without attachment storage")
+}
+
+func TestAdminListAllNotes_PermissionDenied(t *testing.T) {
+	// Arrange
+	mockService := &mockNoteService{
+		listAllFunc: func(ctx context.Context) ([]model.Note, error) {
+			return nil, auth.ErrPermissionDenied
+		},
+	}
+
+	handler := NewHandler(mockService, context.Background())
+
+	// Act
+	_, err := handler.AdminListAllNotes(context.Background(), &notesv1.AdminListAllNotesRequest{})
+
+	// Assert
+	require.Error(t, err)
+
+	st := status.Convert(err)
+	assert.Equal(t, codes.PermissionDenied, st.Code(), "Expected PermissionDenied status code")
+
+	require.Len(t, st.Details(), 1, "Expected exactly one detail in error")
+	errorDetails, ok := st.Details()[0].(*notesv1.ErrorDetails)
+	require.True(t, ok, "Expected detail to be of type ErrorDetails")
+	assert.Equal(t, "PERMISSION_DENIED", errorDetails.InternalErrorCode)
+}
+
+// eventsNoteService - мок сервиса с журналом событий для SubscribeToEvents
+type eventsNoteService struct {
+	mockNoteService
+	events *notesService.EventService
+}
+
+func (s *eventsNoteService) GetEventService() notesService.EventBus {
+	return s.events
+}
+
+// eventStream - серверная часть стрима SubscribeToEvents, передающая сообщения в канал
+type eventStream struct {
+	notesv1.NotesService_SubscribeToEventsServer
+	ctx  context.Context
+	sent chan *notesv1.EventResponse
+}
+
+func (s *eventStream) Context() context.Context {
+	return s.ctx
+}
+
+// Send копирует сообщение: заметка события возвращается в пул после отправки
+func (s *eventStream) Send(resp *notesv1.EventResponse) error {
+	s.sent <- proto.Clone(resp).(*notesv1.EventResponse)
+	return nil
+}
+
+// subscribe запускает SubscribeToEvents и возвращает стрим и канал с результатом вызова
+func subscribe(handler *Handler, req *notesv1.SubscribeToEventsRequest) (*eventStream, <-chan error) {
+	return subscribeAs(context.Background(), handler, req)
+}
+
+// subscribeAs запускает SubscribeToEvents с контекстом стрима ctx (например, с пользователем)
+func subscribeAs(ctx context.Context, handler *Handler, req *notesv1.SubscribeToEventsRequest) (*eventStream, <-chan error) {
+	stream := &eventStream{ctx: ctx, sent: make(chan *notesv1.EventResponse, 16)}
+	done := make(chan error, 1)
+	go func() {
+		done <- handler.SubscribeToEvents(req, stream)
+	}()
+	return stream, done
+}
+
+// receive возвращает следующее сообщение стрима
+func receive(t *testing.T, stream *eventStream) *notesv1.EventResponse {
+	t.Helper()
+	select {
+	case resp := <-stream.sent:
+		return resp
+	case <-time.After(time.Second):
+		t.Fatal("Timed out waiting for events stream message")
+		return nil
+	}
+}
+
+func TestSubscribeToEvents_GoAwayAndResume(t *testing.T) {
+	// Arrange
+	events := notesService.NewEventService()
+	service := &eventsNoteService{events: events}
+	serverCtx, shutdown := context.WithCancel(context.Background())
+	handler := NewHandler(service, serverCtx, WithEventHeartbeatInterval(10*time.Millisecond), WithPooledMessages(true))
+
+	// Act: health-check с настроенным интервалом, событие и остановка сервера
+	stream, done := subscribe(handler, &notesv1.SubscribeToEventsRequest{})
+	require.NotNil(t, receive(t, stream).GetHealthCheck(), "Expected welcome message")
+	require.Equal(t, "Health check", receive(t, stream).GetHealthCheck().GetMessage())
+
+	events.Publish(notesService.Event{Type: notesService.EventNoteCreated, Note: model.Note{ID: "first"}})
+	var first *notesv1.EventResponse
+	for first == nil {
+		if resp := receive(t, stream); resp.GetNoteCreated() != nil {
+			first = resp
+		}
+	}
+	shutdown()
+
+	var goAway *notesv1.StreamGoAway
+	for goAway == nil {
+		goAway = receive(t, stream).GetGoAway()
+	}
+	require.ErrorIs(t, <-done, context.Canceled)
+
+	// Assert: переподключение с resume_token без health-check получает только новые события
+	assert.Equal(t, first.GetEventId(), goAway.GetLastEventId())
+	require.NotEmpty(t, goAway.GetResumeToken())
+	events.Publish(notesService.Event{Type: notesService.EventNoteCreated, Note: model.Note{ID: "second"}})
+
+	handler = NewHandler(service, context.Background(), WithEventHeartbeatInterval(10*time.Millisecond))
+	stream, _ = subscribe(handler, &notesv1.SubscribeToEventsRequest{
+		Since:             &notesv1.SubscribeToEventsRequest_ResumeToken{ResumeToken: goAway.GetResumeToken()},
+		DisableHeartbeats: true,
+	})
+	require.NotNil(t, receive(t, stream).GetHealthCheck(), "Expected welcome message")
+	assert.Equal(t, "second", receive(t, stream).GetNoteCreated().GetNote().GetId())
+	select {
+	case resp := <-stream.sent:
+		t.Errorf("Expected no health-check with disable_heartbeats, got %v", resp)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	_, done = subscribe(handler, &notesv1.SubscribeToEventsRequest{
+		Since: &notesv1.SubscribeToEventsRequest_ResumeToken{ResumeToken: "garbage"},
+	})
+	assert.Equal(t, codes.InvalidArgument, status.Code(<-done))
+}
+
+// notesStream - серверная часть стрима StreamNotes, сохраняющая отправленные заметки
+type notesStream struct {
+	notesv1.NotesService_StreamNotesServer
+	ctx  context.Context
+	sent []*notesv1.Note
+}
+
+func (s *notesStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *notesStream) Send(note *notesv1.Note) error {
+	s.sent = append(s.sent, proto.Clone(note).(*notesv1.Note))
+	return nil
+}
+
+// exportStream - серверная часть стрима ExportNotes, собирающая файл выгрузки
+type exportStream struct {
+	notesv1.NotesService_ExportNotesServer
+	ctx  context.Context
+	data []byte
+}
+
+func (s *exportStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *exportStream) Send(resp *notesv1.ExportNotesResponse) error {
+	s.data = append(s.data, resp.GetData()...)
+	return nil
+}
+
+func TestStreamAndExportNotes_RedactProtected(t *testing.T) {
+	// Arrange
+	service := notesService.NewNoteService(memory.NewRepository())
+	alice := auth.NewContext(context.Background(), auth.Principal{UserID: "alice"})
+	note, err := service.Create(alice, svc.CreateNoteInput{Title: "Diary", Content: "Secret diary"})
+	require.NoError(t, err)
+	_, err = service.Create(alice, svc.CreateNoteInput{Title: "Groceries", Content: "Milk"})
+	require.NoError(t, err)
+	_, err = service.SetPassphrase(alice, note.ID, "correct horse")
+	require.NoError(t, err)
+	handler := NewHandler(service, context.Background())
+	ctx := svc.WithNotePassphrase(alice, "correct horse")
+
+	// Act
+	notes := &notesStream{ctx: ctx}
+	require.NoError(t, handler.StreamNotes(&notesv1.StreamNotesRequest{}, notes))
+	export := &exportStream{ctx: ctx}
+	require.NoError(t, handler.ExportNotes(&notesv1.ExportNotesRequest{Format: notesv1.ExportFormat_EXPORT_FORMAT_JSONL}, export))
+
+	// Assert: стримы, как и ListNotes, не выдают содержимое защищенных заметок
+	require.Len(t, notes.sent, 2)
+	contents := map[string]string{}
+	for _, sent := range notes.sent {
+		contents[sent.GetTitle()] = sent.GetContent()
+	}
+	assert.Equal(t, map[string]string{"Diary": "", "Groceries": "Milk"}, contents)
+	assert.Contains(t, string(export.data), "Milk")
+	assert.NotContains(t, string(export.data), "Secret diary")
+}
+
+func TestSubscribeToEvents_AddressedAndRedacted(t *testing.T) {
+	// Arrange
+	events := notesService.NewEventService()
+	handler := NewHandler(&eventsNoteService{events: events}, context.Background())
+	alice := auth.NewContext(context.Background(), auth.Principal{UserID: "alice"})
+	req := &notesv1.SubscribeToEventsRequest{DisableHeartbeats: true}
+
+	anonymous, _ := subscribeAs(context.Background(), handler, req)
+	owner, _ := subscribeAs(alice, handler, req)
+	require.NotNil(t, receive(t, anonymous).GetHealthCheck(), "Expected welcome message")
+	require.NotNil(t, receive(t, owner).GetHealthCheck(), "Expected welcome message")
+
+	// Act
+	events.Publish(notesService.Event{Type: notesService.EventNoteCreated, Note: model.Note{
+		ID: "diary", OwnerID: "alice", Content: "Secret", PassphraseHash: "$argon2id$hash",
+	}})
+	events.Publish(notesService.Event{Type: notesService.EventNoteCreated, Note: model.Note{ID: "public"}})
+
+	// Assert: анонимный подписчик не получает события пользователей, владелец - без содержимого защищенной заметки
+	assert.Equal(t, "public", receive(t, anonymous).GetNoteCreated().GetNote().GetId())
+	created := receive(t, owner).GetNoteCreated().GetNote()
+	assert.Equal(t, "diary", created.GetId())
+	assert.Empty(t, created.GetContent())
+	select {
+	case resp := <-owner.sent:
+		t.Errorf("Expected no events of notes without an owner for alice, got %v", resp)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
@@ -0,0 +1,53 @@
+package interceptors
+
+import (
+	"context"
+
+	"notes-service/internal/auth"
+	"notes-service/internal/telemetry"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// APIKeyHeader метаданные с ключом API - альтернатива заголовку authorization
+const APIKeyHeader = "x-api-key"
+
+// APIKeyLimiter лимиты запросов ключей API
+type APIKeyLimiter interface {
+	// Allow расходует запрос из бюджета ключа keyID и сообщает, укладывается ли запрос в лимит
+	Allow(ctx context.Context, keyID string) bool
+}
+
+// APIKeyRateLimitUnaryInterceptor ограничивает скорость запросов, аутентифицированных ключом API,
+// лимитом этого ключа. Вызывается после AuthInterceptor, запросы с токенами не ограничиваются
+func APIKeyRateLimitUnaryInterceptor(limiter APIKeyLimiter) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if err := allowAPIKey(ctx, limiter, info.FullMethod); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// APIKeyRateLimitStreamInterceptor расходует бюджет ключа API при открытии стрима
+func APIKeyRateLimitStreamInterceptor(limiter APIKeyLimiter) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := allowAPIKey(ss.Context(), limiter, info.FullMethod); err != nil {
+			return err
+		}
+		return handler(srv, ss)
+	}
+}
+
+// allowAPIKey проверяет лимит ключа API запроса
+func allowAPIKey(ctx context.Context, limiter APIKeyLimiter, method string) error {
+	principal, ok := auth.FromContext(ctx)
+	if !ok || principal.APIKeyID == "" || limiter.Allow(ctx, principal.APIKeyID) {
+		return nil
+	}
+	logf(ctx, "Rate limit exceeded for api key %s (method: %s)", principal.APIKeyID, method)
+	rateLimited(ctx, telemetry.LimiterAPIKey)
+	return status.Errorf(codes.ResourceExhausted, "api key rate limit exceeded")
+}
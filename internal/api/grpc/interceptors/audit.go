@@ -0,0 +1,79 @@
+package interceptors
+
+import (
+	"context"
+
+	"notes-service/internal/model"
+	"notes-service/internal/service/audit"
+	notesv1 "notes-service/pkg/proto/notes/v1"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// AuditUnaryInterceptor записывает в журнал аудита создание, изменение и удаление заметок и изменение
+// доступа к ним: метод, заметку, пользователя, время и результат (код статуса gRPC).
+// Пакетные методы записывают каждую заметку отдельно с ее собственным статусом.
+// Стоит после Auth, чтобы знать пользователя, и до Policy, чтобы в журнал попадали и отклоненные запросы
+func AuditUnaryInterceptor(auditLog *audit.Service) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		resp, err := handler(ctx, req)
+		for _, record := range auditRecords(req, resp, err) {
+			record.Method = info.FullMethod
+			record.RequestID = RequestIDFromContext(ctx)
+			auditLog.Record(ctx, record)
+		}
+		return resp, err
+	}
+}
+
+// auditRecords возвращает записи аудита запроса req с ответом resp и ошибкой err
+// Запросы, не изменяющие заметки, не записываются
+func auditRecords(req, resp interface{}, err error) []model.AuditRecord {
+	outcome := status.Code(err).String()
+	switch r := req.(type) {
+	case *notesv1.CreateNoteRequest:
+		// ID назначает сервер, поэтому он известен только после успешного создания
+		created, _ := resp.(*notesv1.CreateNoteResponse)
+		return []model.AuditRecord{{Action: model.AuditActionCreate, NoteID: created.GetNote().GetId(), Outcome: outcome}}
+	case *notesv1.UpdateNoteRequest:
+		return []model.AuditRecord{{Action: model.AuditActionUpdate, NoteID: r.GetId(), Outcome: outcome}}
+	case *notesv1.DeleteNoteRequest:
+		return []model.AuditRecord{{Action: model.AuditActionDelete, NoteID: r.GetId(), Outcome: outcome}}
+	case *notesv1.ShareNoteRequest:
+		return []model.AuditRecord{{Action: model.AuditActionShare, NoteID: r.GetNoteId(), TargetUserID: r.GetUserId(), Outcome: outcome}}
+	case *notesv1.UnshareNoteRequest:
+		return []model.AuditRecord{{Action: model.AuditActionUnshare, NoteID: r.GetNoteId(), TargetUserID: r.GetUserId(), Outcome: outcome}}
+	case *notesv1.BatchCreateNotesRequest:
+		if err != nil {
+			return []model.AuditRecord{{Action: model.AuditActionCreate, Outcome: outcome}}
+		}
+		batch, _ := resp.(*notesv1.BatchCreateNotesResponse)
+		return batchAuditRecords(model.AuditActionCreate, batch.GetResults())
+	case *notesv1.BatchDeleteNotesRequest:
+		if err != nil {
+			records := make([]model.AuditRecord, 0, len(r.GetIds()))
+			for _, id := range r.GetIds() {
+				records = append(records, model.AuditRecord{Action: model.AuditActionDelete, NoteID: id, Outcome: outcome})
+			}
+			return records
+		}
+		batch, _ := resp.(*notesv1.BatchDeleteNotesResponse)
+		return batchAuditRecords(model.AuditActionDelete, batch.GetResults())
+	}
+	return nil
+}
+
+// batchAuditRecords возвращает записи аудита заметок пакетного запроса со статусами из результатов
+func batchAuditRecords(action string, results []*notesv1.BatchNoteResult) []model.AuditRecord {
+	records := make([]model.AuditRecord, 0, len(results))
+	for _, result := range results {
+		records = append(records, model.AuditRecord{
+			Action:  action,
+			NoteID:  result.GetId(),
+			Outcome: codes.Code(result.GetStatus().GetCode()).String(),
+		})
+	}
+	return records
+}
@@ -0,0 +1,61 @@
+package interceptors
+
+import (
+	"context"
+	"testing"
+
+	"notes-service/internal/auth"
+	"notes-service/internal/model"
+	"notes-service/internal/repository"
+	"notes-service/internal/repository/memory"
+	"notes-service/internal/service/audit"
+	notesv1 "notes-service/pkg/proto/notes/v1"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestAuditUnaryInterceptor(t *testing.T) {
+	repo := memory.NewAuditRepository(0)
+	interceptor := AuditUnaryInterceptor(audit.NewService(repo))
+	ctx := context.WithValue(auth.NewContext(context.Background(), auth.Principal{UserID: "alice"}), requestIDKey{}, "req-1")
+	call := func(method string, req, resp any, err error) {
+		info := &grpc.UnaryServerInfo{FullMethod: "/notes.v1.NotesService/" + method}
+		_, _ = interceptor(ctx, req, info, func(context.Context, any) (any, error) { return resp, err })
+	}
+
+	call("CreateNote", &notesv1.CreateNoteRequest{Title: "a"}, &notesv1.CreateNoteResponse{Note: &notesv1.Note{Id: "n1"}}, nil)
+	call("GetNote", &notesv1.GetNoteRequest{Id: "n1"}, &notesv1.GetNoteResponse{}, nil)
+	call("ShareNote", &notesv1.ShareNoteRequest{NoteId: "n1", UserId: "bob"}, &notesv1.ShareNoteResponse{}, nil)
+	call("DeleteNote", &notesv1.DeleteNoteRequest{Id: "n2"}, nil, status.Error(codes.NotFound, "note not found"))
+	call("BatchDeleteNotes", &notesv1.BatchDeleteNotesRequest{Ids: []string{"n1", "n3"}}, &notesv1.BatchDeleteNotesResponse{Results: []*notesv1.BatchNoteResult{
+		{Id: "n1", Status: status.New(codes.OK, "").Proto()},
+		{Id: "n3", Status: status.New(codes.NotFound, "note not found").Proto()},
+	}}, nil)
+
+	records, err := repo.List(context.Background(), repository.AuditFilter{})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	// Чтение не записывается, пакетный запрос записывается по заметкам; записи от последней к первой
+	want := []model.AuditRecord{
+		{Action: model.AuditActionDelete, Method: "/notes.v1.NotesService/BatchDeleteNotes", NoteID: "n3", Outcome: "NotFound"},
+		{Action: model.AuditActionDelete, Method: "/notes.v1.NotesService/BatchDeleteNotes", NoteID: "n1", Outcome: "OK"},
+		{Action: model.AuditActionDelete, Method: "/notes.v1.NotesService/DeleteNote", NoteID: "n2", Outcome: "NotFound"},
+		{Action: model.AuditActionShare, Method: "/notes.v1.NotesService/ShareNote", NoteID: "n1", TargetUserID: "bob", Outcome: "OK"},
+		{Action: model.AuditActionCreate, Method: "/notes.v1.NotesService/CreateNote", NoteID: "n1", Outcome: "OK"},
+	}
+	if len(records) != len(want) {
+		t.Fatalf("Expected %d audit records, got %d: %+v", len(want), len(records), records)
+	}
+	for i, record := range records {
+		if record.UserID != "alice" || record.RequestID != "req-1" || record.Time.IsZero() {
+			t.Errorf("Expected user, request id and time in record %d, got %+v", i, record)
+		}
+		record.ID, record.Time, record.UserID, record.RequestID = 0, want[i].Time, "", ""
+		if record != want[i] {
+			t.Errorf("Record %d: expected %+v, got %+v", i, want[i], record)
+		}
+	}
+}
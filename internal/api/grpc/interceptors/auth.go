@@ -2,8 +2,14 @@ package interceptors
 
 import (
 	"context"
+	"errors"
+	"maps"
+	"slices"
+	"strconv"
 	"strings"
 
+	"notes-service/internal/auth"
+
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
@@ -13,42 +19,192 @@ import (
 const (
 	// authorizationHeader - имя заголовка для авторизации в metadata
 	authorizationHeader = "authorization"
-	// expectedToken - ожидаемый токен (хардкод для задания)
-	expectedToken = "my-secret-token"
+	// reflectionMethodPrefix - методы gRPC reflection доступны без авторизации (grpcurl/grpcui)
+	reflectionMethodPrefix = "/grpc.reflection."
+	// StreamTicketHeader - метаданные с билетом стрима (AuthService.IssueStreamTicket),
+	// Gateway передает в них параметр ticket из URL
+	StreamTicketHeader = "x-stream-ticket"
 )
 
-// AuthUnaryInterceptor проверяет наличие и валидность токена авторизации в metadata запроса.
-// Токен должен быть передан в заголовке "authorization" в формате "Bearer <token>".
-// Если токен отсутствует или невалиден, возвращается ошибка с кодом Unauthenticated.
-func AuthUnaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+// AuthInterceptor проверяет токен авторизации из metadata запроса через auth.Authenticator.
+// Токен должен быть передан в заголовке "authorization" в формате "Bearer <token>",
+// ключ API - так же или в заголовке "x-api-key".
+// Если токен отсутствует или невалиден, возвращается ошибка с кодом Unauthenticated,
+// если проверить токен не удалось (провайдер недоступен) - Unavailable.
+// Пользователь, которому принадлежит токен, передается дальше через контекст (auth.FromContext).
+// Публичные методы (вход, обновление токенов) вызываются и без действительного токена.
+// Стрим без токена открывается по билету из "x-stream-ticket", если билеты включены (WithStreamTickets).
+type AuthInterceptor struct {
+	authenticator  auth.Authenticator
+	publicMethods  map[string]bool
+	publicServices map[string]bool // Сервисы, все методы которых доступны без токена ("/grpc.health.v1.Health/*")
+	tickets        *auth.StreamTickets
+}
+
+// AuthOption настраивает AuthInterceptor
+type AuthOption func(*AuthInterceptor)
+
+// WithPublicMethods делает методы доступными без токена: если токен передан и действителен,
+// пользователь все равно передается в контекст (например, чтобы Logout закрыл сессию access токена)
+// Метод задается полным именем ("/notes.v1.AuthService/Login"), все методы сервиса - "/<сервис>/*"
+// Опции складываются: методы из нескольких WithPublicMethods доступны все
+func WithPublicMethods(methods []string) AuthOption {
+	return func(a *AuthInterceptor) {
+		for _, method := range methods {
+			if service, ok := strings.CutSuffix(method, "/*"); ok {
+				a.publicServices[service] = true
+				continue
+			}
+			a.publicMethods[method] = true
+		}
+	}
+}
+
+// WithStreamTickets включает открытие стримов по билетам tickets
+func WithStreamTickets(tickets *auth.StreamTickets) AuthOption {
+	return func(a *AuthInterceptor) {
+		a.tickets = tickets
+	}
+}
+
+// NewAuthInterceptor создает интерцептор аутентификации
+// Без WithPublicMethods токен требуется для всех методов, кроме gRPC reflection
+func NewAuthInterceptor(authenticator auth.Authenticator, opts ...AuthOption) *AuthInterceptor {
+	a := &AuthInterceptor{
+		authenticator:  authenticator,
+		publicMethods:  make(map[string]bool),
+		publicServices: make(map[string]bool),
+	}
+	for _, opt := range opts {
+		opt(a)
+	}
+	return a
+}
+
+// Settings возвращает настройки интерцептора для описания цепочки (AdminService.GetPipeline)
+func (a *AuthInterceptor) Settings() map[string]string {
+	public := slices.Collect(maps.Keys(a.publicMethods))
+	for service := range a.publicServices {
+		public = append(public, service+"/*")
+	}
+	slices.Sort(public)
+	return map[string]string{
+		"public_methods": strings.Join(public, ","),
+		"stream_tickets": strconv.FormatBool(a.tickets != nil),
+	}
+}
+
+// isPublic проверяет, доступен ли метод без токена
+func (a *AuthInterceptor) isPublic(fullMethod string) bool {
+	if a.publicMethods[fullMethod] {
+		return true
+	}
+	service, _, ok := strings.Cut(strings.TrimPrefix(fullMethod, "/"), "/")
+	return ok && a.publicServices["/"+service]
+}
+
+// Unary проверяет токен unary запроса
+func (a *AuthInterceptor) Unary(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	principal, err := a.authenticate(ctx)
+	if err != nil {
+		if a.isPublic(info.FullMethod) {
+			return handler(ctx, req)
+		}
+		return nil, err
+	}
+
+	// Токен валиден, пропускаем запрос дальше к хендлеру
+	return handler(auth.NewContext(ctx, principal), req)
+}
+
+// Stream проверяет токен при установлении стрима и передает пользователя в контекст стрима
+func (a *AuthInterceptor) Stream(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if strings.HasPrefix(info.FullMethod, reflectionMethodPrefix) {
+		return handler(srv, ss)
+	}
+
+	principal, err := a.authenticate(ss.Context())
+	if status.Code(err) == codes.Unauthenticated {
+		if p, ok, ticketErr := a.redeemTicket(ss.Context(), info.FullMethod); ok {
+			principal, err = p, ticketErr
+		}
+	}
+	if err != nil {
+		if a.isPublic(info.FullMethod) {
+			return handler(srv, ss)
+		}
+		return err
+	}
+
+	return handler(srv, &contextServerStream{
+		ServerStream: ss,
+		ctx:          auth.NewContext(ss.Context(), principal),
+	})
+}
+
+// contextServerStream подменяет контекст стрима (например, контекстом с пользователем)
+type contextServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+// Context возвращает подмененный контекст стрима
+func (s *contextServerStream) Context() context.Context {
+	return s.ctx
+}
+
+// redeemTicket открывает стрим fullMethod по билету из metadata, если токена в запросе нет
+// ok == false, если билеты выключены или билет не передан
+func (a *AuthInterceptor) redeemTicket(ctx context.Context, fullMethod string) (principal auth.Principal, ok bool, err error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	tickets := md.Get(StreamTicketHeader)
+	if a.tickets == nil || len(tickets) == 0 || tickets[0] == "" || len(md.Get(authorizationHeader)) > 0 {
+		return auth.Principal{}, false, nil
+	}
+
+	principal, err = a.tickets.Redeem(tickets[0], fullMethod)
+	if err != nil {
+		logf(ctx, "Stream ticket rejected for %s: %v", fullMethod, err)
+		return auth.Principal{}, true, status.Errorf(codes.Unauthenticated, "invalid stream ticket")
+	}
+	return principal, true, nil
+}
+
+// authenticate извлекает токен из metadata и возвращает его владельца
+func (a *AuthInterceptor) authenticate(ctx context.Context) (auth.Principal, error) {
 	// Извлекаем metadata из контекста
 	md, ok := metadata.FromIncomingContext(ctx)
 	if !ok {
-		return nil, status.Errorf(codes.Unauthenticated, "metadata not provided")
+		return auth.Principal{}, status.Errorf(codes.Unauthenticated, "metadata not provided")
 	}
 
-	// Получаем значение заголовка authorization
+	// Получаем значение заголовка authorization, без него - ключ API из x-api-key
 	authHeaders := md.Get(authorizationHeader)
-	if len(authHeaders) == 0 {
-		return nil, status.Errorf(codes.Unauthenticated, "authorization header not provided")
+	apiKeys := md.Get(APIKeyHeader)
+	if len(authHeaders) == 0 && (len(apiKeys) == 0 || apiKeys[0] == "") {
+		return auth.Principal{}, status.Errorf(codes.Unauthenticated, "authorization header not provided")
 	}
 
-	// Берем первое значение заголовка
-	authHeader := authHeaders[0]
-
-	// Проверяем формат токена (должен начинаться с "Bearer ")
-	if !strings.HasPrefix(authHeader, "Bearer ") {
-		return nil, status.Errorf(codes.Unauthenticated, "invalid authorization header format")
+	// Берем первое значение заголовка и проверяем формат "Bearer <token>"
+	var token string
+	if len(authHeaders) > 0 {
+		var ok bool
+		if token, ok = auth.ParseBearer(authHeaders[0]); !ok {
+			return auth.Principal{}, status.Errorf(codes.Unauthenticated, "invalid authorization header format")
+		}
+	} else {
+		token = apiKeys[0]
 	}
 
-	// Извлекаем токен (часть после "Bearer ")
-	token := strings.TrimPrefix(authHeader, "Bearer ")
-
-	// Сравниваем токен с ожидаемым значением
-	if token != expectedToken {
-		return nil, status.Errorf(codes.Unauthenticated, "invalid token")
+	// Ищем владельца токена у провайдеров аутентификации
+	principal, err := a.authenticator.Authenticate(ctx, token)
+	if errors.Is(err, auth.ErrInvalidToken) {
+		return auth.Principal{}, status.Errorf(codes.Unauthenticated, "invalid token")
+	}
+	if err != nil {
+		logf(ctx, "Authentication failed: %v", err)
+		return auth.Principal{}, status.Errorf(codes.Unavailable, "authentication is temporarily unavailable")
 	}
 
-	// Токен валиден, пропускаем запрос дальше к хендлеру
-	return handler(ctx, req)
+	return principal, nil
 }
@@ -0,0 +1,45 @@
+package interceptors
+
+import (
+	"context"
+	"testing"
+
+	"notes-service/internal/auth"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestAuthInterceptor_PublicMethods(t *testing.T) {
+	interceptor := NewAuthInterceptor(auth.DemoTokens(),
+		WithPublicMethods([]string{"/notes.v1.AuthService/Login"}),
+		WithPublicMethods([]string{"/grpc.health.v1.Health/*"}),
+	)
+	handler := func(ctx context.Context, _ any) (any, error) {
+		principal, _ := auth.FromContext(ctx)
+		return principal.UserID, nil
+	}
+	call := func(ctx context.Context, method string) (any, error) {
+		return interceptor.Unary(ctx, nil, &grpc.UnaryServerInfo{FullMethod: method}, handler)
+	}
+
+	anonymous := metadata.NewIncomingContext(context.Background(), metadata.MD{})
+	for _, method := range []string{"/notes.v1.AuthService/Login", "/grpc.health.v1.Health/Check", "/grpc.health.v1.Health/Watch"} {
+		if _, err := call(anonymous, method); err != nil {
+			t.Errorf("Expected %s without a token to succeed, got: %v", method, err)
+		}
+	}
+	for _, method := range []string{"/notes.v1.AuthService/Logout", "/notes.v1.NotesService/GetNote", "/grpc.health.v1.HealthX/Check"} {
+		if _, err := call(anonymous, method); status.Code(err) != codes.Unauthenticated {
+			t.Errorf("Expected Unauthenticated for %s without a token, got: %v", method, err)
+		}
+	}
+
+	// Действительный токен публичного метода все равно передается в контекст
+	authorized := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer my-secret-token"))
+	if user, err := call(authorized, "/grpc.health.v1.Health/Check"); err != nil || user != "demo" {
+		t.Errorf("Expected demo user in public method context, got %v, %v", user, err)
+	}
+}
@@ -0,0 +1,96 @@
+package interceptors
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"notes-service/internal/auth"
+
+	"google.golang.org/grpc"
+)
+
+// impliedRoles роли, которые включает роль: admin может все, что writer, writer - все, что reader
+var impliedRoles = map[string][]string{
+	auth.RoleAdmin:  {auth.RoleWriter},
+	auth.RoleWriter: {auth.RoleReader},
+}
+
+// AuthzInterceptor проверяет роли пользователя по карте методов из конфигурации (секция authz)
+// Роли берутся из токена (утверждение roles, роли статических токенов, пользователей и ключей API)
+// с учетом иерархии admin > writer > reader. Пользователь без ролей reader, writer и admin
+// получает роли по умолчанию. Методы вне карты проверяются только политиками из proto.
+// Вызывается после AuthInterceptor: пользователь уже в контексте
+type AuthzInterceptor struct {
+	methods      map[string][]string
+	defaultRoles []string
+}
+
+// NewAuthzInterceptor создает интерцептор проверки ролей
+// methods - роли, любая из которых разрешает вызов, по полным именам методов ("/notes.v1.NotesService/DeleteNote")
+func NewAuthzInterceptor(methods map[string][]string, defaultRoles ...string) *AuthzInterceptor {
+	return &AuthzInterceptor{methods: methods, defaultRoles: defaultRoles}
+}
+
+// Settings возвращает настройки интерцептора для описания цепочки (AdminService.GetPipeline)
+func (a *AuthzInterceptor) Settings() map[string]string {
+	settings := map[string]string{"default_roles": strings.Join(a.defaultRoles, ",")}
+	for method, roles := range a.methods {
+		settings[method] = strings.Join(roles, ",")
+	}
+	return settings
+}
+
+// Unary проверяет роли для unary запроса
+func (a *AuthzInterceptor) Unary(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if err := a.authorize(ctx, info.FullMethod); err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+// Stream проверяет роли при установлении стрима
+func (a *AuthzInterceptor) Stream(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if err := a.authorize(ss.Context(), info.FullMethod); err != nil {
+		return err
+	}
+	return handler(srv, ss)
+}
+
+// authorize проверяет, что у пользователя есть одна из ролей метода
+// Ошибка совпадает с ответом хэндлера на auth.ErrPermissionDenied
+func (a *AuthzInterceptor) authorize(ctx context.Context, fullMethod string) error {
+	required, ok := a.methods[fullMethod]
+	if !ok {
+		return nil
+	}
+	principal, ok := auth.FromContext(ctx)
+	if !ok {
+		return permissionDenied()
+	}
+
+	roles := a.effectiveRoles(principal)
+	if slices.ContainsFunc(required, func(role string) bool { return slices.Contains(roles, role) }) {
+		return nil
+	}
+	return permissionDenied()
+}
+
+// effectiveRoles возвращает роли пользователя вместе с включенными в них по иерархии
+func (a *AuthzInterceptor) effectiveRoles(principal auth.Principal) []string {
+	roles := slices.Clone(principal.Roles)
+	if !slices.ContainsFunc(roles, func(role string) bool {
+		return role == auth.RoleReader || role == auth.RoleWriter || role == auth.RoleAdmin
+	}) {
+		roles = append(roles, a.defaultRoles...)
+	}
+
+	for i := 0; i < len(roles); i++ {
+		for _, implied := range impliedRoles[roles[i]] {
+			if !slices.Contains(roles, implied) {
+				roles = append(roles, implied)
+			}
+		}
+	}
+	return roles
+}
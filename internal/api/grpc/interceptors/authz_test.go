@@ -0,0 +1,47 @@
+package interceptors
+
+import (
+	"context"
+	"testing"
+
+	"notes-service/internal/auth"
+	notesv1 "notes-service/pkg/proto/notes/v1"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestAuthzInterceptor(t *testing.T) {
+	interceptor := NewAuthzInterceptor(map[string][]string{
+		notesv1.NotesService_DeleteNote_FullMethodName:        {auth.RoleWriter},
+		notesv1.NotesService_GetNote_FullMethodName:           {auth.RoleReader},
+		notesv1.NotesService_AdminListAllNotes_FullMethodName: {auth.RoleAdmin},
+	}, auth.RoleReader)
+	handler := func(context.Context, any) (any, error) { return "ok", nil }
+
+	tests := []struct {
+		name    string
+		roles   []string
+		method  string
+		allowed bool
+	}{
+		{"default reader reads", []string{auth.RoleUser}, notesv1.NotesService_GetNote_FullMethodName, true},
+		{"default reader cannot delete", []string{auth.RoleUser}, notesv1.NotesService_DeleteNote_FullMethodName, false},
+		{"writer deletes", []string{auth.RoleUser, auth.RoleWriter}, notesv1.NotesService_DeleteNote_FullMethodName, true},
+		{"writer reads", []string{auth.RoleWriter}, notesv1.NotesService_GetNote_FullMethodName, true},
+		{"writer is not admin", []string{auth.RoleWriter}, notesv1.NotesService_AdminListAllNotes_FullMethodName, false},
+		{"admin deletes", []string{auth.RoleAdmin}, notesv1.NotesService_DeleteNote_FullMethodName, true},
+		{"method without roles", nil, notesv1.NotesService_ListTags_FullMethodName, true},
+	}
+	for _, tt := range tests {
+		ctx := auth.NewContext(context.Background(), auth.Principal{UserID: "user-1", Roles: tt.roles})
+		_, err := interceptor.Unary(ctx, nil, &grpc.UnaryServerInfo{FullMethod: tt.method}, handler)
+		if tt.allowed && err != nil {
+			t.Errorf("%s: expected call to succeed, got: %v", tt.name, err)
+		}
+		if !tt.allowed && status.Code(err) != codes.PermissionDenied {
+			t.Errorf("%s: expected PermissionDenied, got: %v", tt.name, err)
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package interceptors
+
+import (
+	"context"
+	"errors"
+
+	"notes-service/internal/repository"
+	notesv1 "notes-service/pkg/proto/notes/v1"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// ConsistencyTokenHeader метаданные с токеном согласованности: в ответе на изменение заметок
+// сервер возвращает токен, а клиент передает его в следующих запросах, чтобы увидеть свои записи
+const ConsistencyTokenHeader = "x-consistency-token"
+
+// consistencyWriteMethods методы, изменяющие хранилище заметок: в ответе на них возвращается токен
+var consistencyWriteMethods = map[string]bool{
+	notesv1.NotesService_CreateNote_FullMethodName:        true,
+	notesv1.NotesService_UpdateNote_FullMethodName:        true,
+	notesv1.NotesService_DeleteNote_FullMethodName:        true,
+	notesv1.NotesService_PinNote_FullMethodName:           true,
+	notesv1.NotesService_UnpinNote_FullMethodName:         true,
+	notesv1.NotesService_ArchiveNote_FullMethodName:       true,
+	notesv1.NotesService_UnarchiveNote_FullMethodName:     true,
+	notesv1.NotesService_LockNote_FullMethodName:          true,
+	notesv1.NotesService_UnlockNote_FullMethodName:        true,
+	notesv1.NotesService_SetNotePassphrase_FullMethodName: true,
+	notesv1.NotesService_BatchCreateNotes_FullMethodName:  true,
+	notesv1.NotesService_BatchDeleteNotes_FullMethodName:  true,
+	notesv1.NotesService_RestoreBackup_FullMethodName:     true,
+	notesv1.NotesService_ImportNotes_FullMethodName:       true,
+}
+
+// ConsistencyUnaryInterceptor обеспечивает чтение своих записей (read-your-writes) между репликами:
+// запрос с токеном в метаданных x-consistency-token выполняется только после того, как хранилище
+// увидит запись с этим токеном, а успешный ответ на изменение заметок содержит новый токен в заголовке
+func ConsistencyUnaryInterceptor(tracker repository.ConsistencyTracker) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if err := waitForToken(ctx, tracker); err != nil {
+			return nil, err
+		}
+
+		resp, err := handler(ctx, req)
+		if err == nil && consistencyWriteMethods[info.FullMethod] {
+			if token := consistencyToken(ctx, tracker); token != "" {
+				_ = grpc.SetHeader(ctx, metadata.Pairs(ConsistencyTokenHeader, token))
+			}
+		}
+		return resp, err
+	}
+}
+
+// ConsistencyStreamInterceptor ждет токен из метаданных до начала стрима (StreamNotes, ExportNotes)
+// Заголовки стрима уже отправлены к его завершению, поэтому токен ImportNotes возвращается в трейлере
+func ConsistencyStreamInterceptor(tracker repository.ConsistencyTracker) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := waitForToken(ss.Context(), tracker); err != nil {
+			return err
+		}
+
+		err := handler(srv, ss)
+		if err == nil && consistencyWriteMethods[info.FullMethod] {
+			if token := consistencyToken(ss.Context(), tracker); token != "" {
+				ss.SetTrailer(metadata.Pairs(ConsistencyTokenHeader, token))
+			}
+		}
+		return err
+	}
+}
+
+// waitForToken ждет, пока хранилище не увидит запись с токеном из метаданных запроса
+func waitForToken(ctx context.Context, tracker repository.ConsistencyTracker) error {
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get(ConsistencyTokenHeader)
+	if len(values) == 0 || values[0] == "" {
+		return nil
+	}
+
+	err := tracker.WaitForToken(ctx, values[0])
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, repository.ErrInvalidConsistencyToken):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, repository.ErrConsistencyLag), errors.Is(err, repository.ErrUnavailable):
+		return status.Error(codes.Unavailable, err.Error())
+	default:
+		return status.FromContextError(err).Err()
+	}
+}
+
+// consistencyToken возвращает токен хранилища после записи, пустую строку при ошибке
+// Запись уже выполнена, поэтому ошибка получения токена не влияет на ответ
+func consistencyToken(ctx context.Context, tracker repository.ConsistencyTracker) string {
+	token, err := tracker.ConsistencyToken(ctx)
+	if err != nil {
+		logf(ctx, "Failed to get consistency token: %v", err)
+		return ""
+	}
+	return token
+}
@@ -0,0 +1,150 @@
+package interceptors
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	notesv1 "notes-service/pkg/proto/notes/v1"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// streamLifetimeGrace время, за которое обработчик должен завершиться после истечения лимита стрима
+const streamLifetimeGrace = 5 * time.Second
+
+// StreamLifetime ограничения времени жизни одного стрима
+type StreamLifetime struct {
+	MaxDuration time.Duration // Максимальная длительность стрима, 0 - без ограничения
+	IdleTimeout time.Duration // Максимальное время без сообщений клиента, 0 - без ограничения (только стримы с сообщениями клиента)
+}
+
+// streamLifetimeExceeded причина отмены контекста стрима при истечении лимита
+type streamLifetimeExceeded struct {
+	err error // Статус для клиента
+}
+
+func (e *streamLifetimeExceeded) Error() string {
+	return e.err.Error()
+}
+
+// StreamLifetimeExceeded возвращает статус для клиента, если контекст стрима (или производный от него)
+// отменен из-за истечения лимита времени жизни, иначе nil. Обработчик может отправить клиенту
+// сообщение для переподключения (go_away в SubscribeToEvents) и вернуть эту ошибку
+func StreamLifetimeExceeded(ctx context.Context) error {
+	var exceeded *streamLifetimeExceeded
+	if errors.As(context.Cause(ctx), &exceeded) {
+		return exceeded.err
+	}
+	return nil
+}
+
+// lifetimeServerStream стрим с контекстом, который отменяется при истечении лимита,
+// и уведомлением о входящих сообщениях для таймаута простоя
+type lifetimeServerStream struct {
+	grpc.ServerStream
+	ctx      context.Context
+	received chan struct{}
+}
+
+func (s *lifetimeServerStream) Context() context.Context {
+	return s.ctx
+}
+
+// RecvMsg принимает сообщение и сбрасывает таймаут простоя
+func (s *lifetimeServerStream) RecvMsg(m any) error {
+	err := s.ServerStream.RecvMsg(m)
+	if err == nil || errors.As(err, new(*StreamRateLimitError)) {
+		select {
+		case s.received <- struct{}{}:
+		default:
+		}
+	}
+	return err
+}
+
+// NewStreamLifetimeInterceptor ограничивает время жизни стримов методов из limits
+// (ключ - полное имя метода, например "/notes.v1.NotesService/SubscribeToEvents"): общую длительность
+// и время без сообщений клиента. При истечении лимита контекст стрима отменяется с причиной,
+// доступной через StreamLifetimeExceeded, а стрим завершается со статусом UNAVAILABLE
+// (STREAM_MAX_DURATION или STREAM_IDLE_TIMEOUT): клиент переподключается, как после перезапуска сервера.
+// Обработчик, который не завершился за streamLifetimeGrace (например, ждет сообщения в Recv),
+// не задерживает ответ: стрим закрывается без него
+func NewStreamLifetimeInterceptor(limits map[string]StreamLifetime) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		limit, ok := limits[info.FullMethod]
+		if !info.IsClientStream {
+			limit.IdleTimeout = 0
+		}
+		if !ok || (limit.MaxDuration <= 0 && limit.IdleTimeout <= 0) {
+			return handler(srv, ss)
+		}
+
+		ctx, cancel := context.WithCancelCause(ss.Context())
+		defer cancel(nil)
+		stream := &lifetimeServerStream{ServerStream: ss, ctx: ctx, received: make(chan struct{}, 1)}
+
+		// Обработчик выполняется в отдельной горутине, поэтому паника перехватывается здесь:
+		// RecoveryStreamInterceptor ее уже не увидит
+		done := make(chan error, 1)
+		go func() {
+			defer func() {
+				if r := recover(); r != nil {
+					done <- recovered(ctx, info.FullMethod, r)
+				}
+			}()
+			done <- handler(srv, stream)
+		}()
+
+		var maxDuration, idle <-chan time.Time
+		if limit.MaxDuration > 0 {
+			timer := time.NewTimer(limit.MaxDuration)
+			defer timer.Stop()
+			maxDuration = timer.C
+		}
+		var idleTimer *time.Timer
+		if limit.IdleTimeout > 0 {
+			idleTimer = time.NewTimer(limit.IdleTimeout)
+			defer idleTimer.Stop()
+			idle = idleTimer.C
+		}
+
+		var exceeded error
+		for exceeded == nil {
+			select {
+			case err := <-done:
+				return err
+			case <-stream.received:
+				if idleTimer != nil {
+					idleTimer.Reset(limit.IdleTimeout)
+				}
+			case <-maxDuration:
+				exceeded = lifetimeError("STREAM_MAX_DURATION",
+					fmt.Sprintf("stream exceeded the maximum duration of %s; reconnect to continue", limit.MaxDuration))
+			case <-idle:
+				exceeded = lifetimeError("STREAM_IDLE_TIMEOUT",
+					fmt.Sprintf("stream received no messages for %s; reconnect to continue", limit.IdleTimeout))
+			}
+		}
+
+		cancel(&streamLifetimeExceeded{err: exceeded})
+		select {
+		case <-done:
+		case <-time.After(streamLifetimeGrace):
+		}
+		return exceeded
+	}
+}
+
+// lifetimeError создает статус завершения стрима по лимиту с подсказкой о переподключении
+func lifetimeError(internalCode, message string) error {
+	st := status.New(codes.Unavailable, message)
+	st, _ = st.WithDetails(&notesv1.ErrorDetails{
+		Reason:            "The stream reached its lifetime limit; reconnect to continue (SubscribeToEvents resumes with the resume_token from go_away)",
+		InternalErrorCode: internalCode,
+	})
+	return st.Err()
+}
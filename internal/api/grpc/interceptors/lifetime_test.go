@@ -0,0 +1,81 @@
+package interceptors
+
+import (
+	"testing"
+	"time"
+
+	notesv1 "notes-service/pkg/proto/notes/v1"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestStreamLifetimeInterceptor_IdleTimeout(t *testing.T) {
+	const method = "/notes.v1.NotesService/Chat"
+	interceptor := NewStreamLifetimeInterceptor(map[string]StreamLifetime{
+		method: {IdleTimeout: 50 * time.Millisecond},
+	})
+	info := &grpc.StreamServerInfo{FullMethod: method, IsClientStream: true, IsServerStream: true}
+
+	// Сообщения чаще таймаута простоя продлевают стрим, после них обработчик ждет отмены контекста
+	var lifetimeErr error
+	err := interceptor(nil, &fakeServerStream{}, info, func(_ any, stream grpc.ServerStream) error {
+		for range 4 {
+			time.Sleep(20 * time.Millisecond)
+			if err := stream.RecvMsg(&notesv1.ChatMessage{}); err != nil {
+				return err
+			}
+		}
+		if err := StreamLifetimeExceeded(stream.Context()); err != nil {
+			t.Error("Expected messages to reset the idle timeout")
+		}
+		<-stream.Context().Done()
+		lifetimeErr = StreamLifetimeExceeded(stream.Context())
+		return nil
+	})
+
+	if status.Code(err) != codes.Unavailable {
+		t.Fatalf("Expected Unavailable after the idle timeout, got: %v", err)
+	}
+	if lifetimeErr == nil || lifetimeErr.Error() != err.Error() {
+		t.Errorf("Expected the handler to see the same status in the stream context, got %v", lifetimeErr)
+	}
+	var details *notesv1.ErrorDetails
+	for _, detail := range status.Convert(err).Details() {
+		details, _ = detail.(*notesv1.ErrorDetails)
+	}
+	if details.GetInternalErrorCode() != "STREAM_IDLE_TIMEOUT" {
+		t.Errorf("Expected STREAM_IDLE_TIMEOUT details, got %v", details)
+	}
+}
+
+func TestStreamLifetimeInterceptor_MaxDuration(t *testing.T) {
+	const method = "/notes.v1.NotesService/SubscribeToEvents"
+	interceptor := NewStreamLifetimeInterceptor(map[string]StreamLifetime{
+		method: {MaxDuration: 20 * time.Millisecond, IdleTimeout: time.Millisecond},
+	})
+	info := &grpc.StreamServerInfo{FullMethod: method, IsServerStream: true}
+
+	// Таймаут простоя не применяется к стримам без сообщений клиента
+	started := time.Now()
+	err := interceptor(nil, &fakeServerStream{}, info, func(_ any, stream grpc.ServerStream) error {
+		<-stream.Context().Done()
+		return StreamLifetimeExceeded(stream.Context())
+	})
+	if status.Code(err) != codes.Unavailable || time.Since(started) < 20*time.Millisecond {
+		t.Fatalf("Expected Unavailable after the maximum duration, got %v in %s", err, time.Since(started))
+	}
+
+	// Стрим метода без лимита не ограничивается
+	err = interceptor(nil, &fakeServerStream{}, &grpc.StreamServerInfo{FullMethod: "/notes.v1.NotesService/Chat"},
+		func(_ any, stream grpc.ServerStream) error {
+			if _, ok := stream.(*lifetimeServerStream); ok {
+				t.Error("Expected the original stream for a method without limits")
+			}
+			return nil
+		})
+	if err != nil {
+		t.Errorf("Expected no error, got: %v", err)
+	}
+}
@@ -2,11 +2,18 @@ package interceptors
 
 import (
 	"context"
-	"log"
+	"math/rand/v2"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
 
+	"notes-service/internal/recorder"
+
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
 )
 
 // LoggerUnaryInterceptor перехватывает запросы и логирует информацию о них:
@@ -15,7 +22,7 @@ import (
 // - конец запроса (статус ответа + затраченное время)
 func LoggerUnaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
 	// Логируем начало запроса
-	log.Printf("Incoming request: %s", info.FullMethod)
+	logf(ctx, "Incoming request: %s", info.FullMethod)
 
 	// Засекаем время начала выполнения
 	start := time.Now()
@@ -31,16 +38,89 @@ func LoggerUnaryInterceptor(ctx context.Context, req interface{}, info *grpc.Una
 		// Извлекаем статус из ошибки
 		st, ok := status.FromError(err)
 		if ok {
-			log.Printf("Request %s failed with status %s: %v (duration: %v)",
+			logf(ctx, "Request %s failed with status %s: %v (duration: %v)",
 				info.FullMethod, st.Code(), st.Message(), duration)
 		} else {
-			log.Printf("Request %s failed with error: %v (duration: %v)",
+			logf(ctx, "Request %s failed with error: %v (duration: %v)",
 				info.FullMethod, err, duration)
 		}
 	} else {
-		log.Printf("Request %s completed successfully (duration: %v)",
+		logf(ctx, "Request %s completed successfully (duration: %v)",
 			info.FullMethod, duration)
 	}
 
 	return resp, err
 }
+
+// secretFields поля с учетными данными, которые не попадают в лог тел запросов при любой настройке
+var secretFields = []string{"password", "passphrase", "access_token", "refresh_token", "secret", "key"}
+
+// PayloadLogging настройки логирования тел запросов и ответов
+type PayloadLogging struct {
+	SampleRate   float64  // Доля запросов, тела которых логируются (0-1)
+	RedactFields []string // Имена полей proto, значения которых заменяются заглушкой той же длины
+}
+
+// PayloadLogger логирует запросы, как LoggerUnaryInterceptor, и тела выбранной доли запросов
+// и ответов в protojson. Значения полей из RedactFields и полей с учетными данными заменяются
+type PayloadLogger struct {
+	sampleRate float64
+	redact     map[string]bool
+	random     func() float64 // Случайное число в [0, 1) для выбора запросов
+}
+
+// NewPayloadLogger создает логирование запросов с телами
+func NewPayloadLogger(logging PayloadLogging) *PayloadLogger {
+	l := &PayloadLogger{
+		sampleRate: logging.SampleRate,
+		redact:     make(map[string]bool, len(logging.RedactFields)+len(secretFields)),
+		random:     rand.Float64,
+	}
+	for _, field := range slices.Concat(logging.RedactFields, secretFields) {
+		l.redact[field] = true
+	}
+	return l
+}
+
+// Settings возвращает настройки для описания цепочки интерцепторов
+func (l *PayloadLogger) Settings() map[string]string {
+	redact := make([]string, 0, len(l.redact))
+	for field := range l.redact {
+		redact = append(redact, field)
+	}
+	slices.Sort(redact)
+	return map[string]string{
+		"payload_sample_rate":   strconv.FormatFloat(l.sampleRate, 'f', -1, 64),
+		"payload_redact_fields": strings.Join(redact, ","),
+	}
+}
+
+// Unary логирует запрос и, если запрос попал в выборку, его тело и тело успешного ответа
+func (l *PayloadLogger) Unary(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if l.sampleRate <= 0 || l.random() >= l.sampleRate {
+		return LoggerUnaryInterceptor(ctx, req, info, handler)
+	}
+
+	// Тела логируются между началом и завершением запроса
+	return LoggerUnaryInterceptor(ctx, req, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		logf(ctx, "Request payload: %s", l.payload(req))
+		resp, err := handler(ctx, req)
+		if err == nil {
+			logf(ctx, "Response payload: %s", l.payload(resp))
+		}
+		return resp, err
+	})
+}
+
+// payload возвращает тело сообщения в protojson со скрытыми значениями полей
+func (l *PayloadLogger) payload(msg interface{}) string {
+	m, ok := msg.(proto.Message)
+	if !ok {
+		return "<non-proto message>"
+	}
+	data, err := protojson.Marshal(recorder.Sanitize(m, l.redact))
+	if err != nil {
+		return "<marshal error: " + err.Error() + ">"
+	}
+	return string(data)
+}
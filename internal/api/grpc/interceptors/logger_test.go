@@ -0,0 +1,45 @@
+package interceptors
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"strings"
+	"testing"
+
+	notesv1 "notes-service/pkg/proto/notes/v1"
+
+	"google.golang.org/grpc"
+)
+
+func TestPayloadLogger(t *testing.T) {
+	var out bytes.Buffer
+	previous := log.Writer()
+	log.SetOutput(&out)
+	t.Cleanup(func() { log.SetOutput(previous) })
+
+	logger := NewPayloadLogger(PayloadLogging{SampleRate: 0.5, RedactFields: []string{"content"}})
+	info := &grpc.UnaryServerInfo{FullMethod: "/notes.v1.AuthService/Login"}
+	handler := func(context.Context, any) (any, error) {
+		return &notesv1.CreateNoteResponse{Note: &notesv1.Note{Id: "n1", Title: "Title", Content: "secret text"}}, nil
+	}
+
+	// Запрос вне выборки логируется без тел
+	logger.random = func() float64 { return 0.5 }
+	_, _ = logger.Unary(context.Background(), &notesv1.LoginRequest{Username: "alice", Password: "hunter2"}, info, handler)
+	if strings.Contains(out.String(), "payload") {
+		t.Errorf("Expected no payloads for request outside the sample, got:\n%s", out.String())
+	}
+
+	logger.random = func() float64 { return 0.1 }
+	_, _ = logger.Unary(context.Background(), &notesv1.LoginRequest{Username: "alice", Password: "hunter2"}, info, handler)
+	logged := out.String()
+	for _, want := range []string{`"username":"alice"`, `"password":"xxxxxxx"`, `"title":"Title"`, `"content":"xxxxxxxxxxx"`} {
+		if !strings.Contains(logged, want) {
+			t.Errorf("Expected %s in log, got:\n%s", want, logged)
+		}
+	}
+	if strings.Contains(logged, "hunter2") || strings.Contains(logged, "secret text") {
+		t.Errorf("Expected redacted values to be hidden, got:\n%s", logged)
+	}
+}
@@ -0,0 +1,58 @@
+package interceptors
+
+import (
+	"context"
+	"time"
+
+	"notes-service/internal/telemetry"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// metricsKey ключ набора метрик в контексте запроса
+type metricsKey struct{}
+
+// MetricsUnaryInterceptor учитывает запросы и время выполнения unary методов в registry
+// Панику учитывает как Internal (ее перехватывает Recovery снаружи) и передает registry
+// лимитам запросов в контексте, чтобы они учитывали отклоненные запросы
+func MetricsUnaryInterceptor(registry *telemetry.Registry) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		start := time.Now()
+		// Паника учитывается с кодом Internal, который вернет клиенту Recovery
+		defer func() {
+			if r := recover(); r != nil {
+				registry.ObserveUnary(info.FullMethod, codes.Internal.String(), time.Since(start))
+				panic(r)
+			}
+		}()
+		resp, err := handler(context.WithValue(ctx, metricsKey{}, registry), req)
+		registry.ObserveUnary(info.FullMethod, status.Code(err).String(), time.Since(start))
+		return resp, err
+	}
+}
+
+// MetricsStreamInterceptor учитывает открытые и завершенные стримы в registry
+func MetricsStreamInterceptor(registry *telemetry.Registry) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		registry.StreamOpened(info.FullMethod)
+		defer func() {
+			if r := recover(); r != nil {
+				registry.StreamClosed(info.FullMethod, codes.Internal.String())
+				panic(r)
+			}
+		}()
+		ctx := context.WithValue(ss.Context(), metricsKey{}, registry)
+		err := handler(srv, &contextServerStream{ServerStream: ss, ctx: ctx})
+		registry.StreamClosed(info.FullMethod, status.Code(err).String())
+		return err
+	}
+}
+
+// rateLimited учитывает запрос, отклоненный лимитом limiter, если метрики включены
+func rateLimited(ctx context.Context, limiter string) {
+	if registry, ok := ctx.Value(metricsKey{}).(*telemetry.Registry); ok {
+		registry.RateLimited(telemetry.LayerGRPC, limiter)
+	}
+}
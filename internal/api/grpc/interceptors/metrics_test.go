@@ -0,0 +1,47 @@
+package interceptors
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"notes-service/internal/auth"
+	"notes-service/internal/telemetry"
+
+	"google.golang.org/grpc"
+)
+
+func TestMetricsUnaryInterceptor(t *testing.T) {
+	registry := telemetry.NewRegistry()
+	metrics := MetricsUnaryInterceptor(registry)
+	limit := RateLimitUnaryInterceptor(NewRateLimiter(RateLimit{RequestsPerSecond: 0.001, Burst: 1}))
+	info := &grpc.UnaryServerInfo{FullMethod: "/notes.v1.NotesService/ListNotes"}
+	ctx := auth.NewContext(context.Background(), auth.Principal{UserID: "user-1"})
+
+	// Второй запрос отклоняется лимитом: он учитывается и как запрос с кодом ResourceExhausted
+	for range 2 {
+		_, _ = metrics(ctx, nil, info, func(ctx context.Context, req any) (any, error) {
+			return limit(ctx, req, info, func(context.Context, any) (any, error) { return "ok", nil })
+		})
+	}
+
+	out := scrapeMetrics(registry)
+	for _, want := range []string{
+		`notes_grpc_requests_total{code="OK",method="ListNotes",service="notes.v1.NotesService"} 1`,
+		`notes_grpc_requests_total{code="ResourceExhausted",method="ListNotes",service="notes.v1.NotesService"} 1`,
+		`notes_rate_limited_total{layer="grpc",limiter="client"} 1`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Expected %s in metrics, got:\n%s", want, out)
+		}
+	}
+}
+
+// scrapeMetrics возвращает ответ /metrics реестра
+func scrapeMetrics(registry *telemetry.Registry) string {
+	rec := httptest.NewRecorder()
+	registry.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	return rec.Body.String()
+}
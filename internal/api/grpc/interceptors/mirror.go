@@ -0,0 +1,32 @@
+package interceptors
+
+import (
+	"context"
+
+	"notes-service/internal/mirror"
+
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+)
+
+// MirrorUnaryInterceptor дублирует выбранную долю запросов чтения во второй сервис (см. mirror.Mirror)
+// Ответ клиенту не ждет второй сервис и не зависит от результата сравнения
+func MirrorUnaryInterceptor(m *mirror.Mirror) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		resp, err := handler(ctx, req)
+		if !m.Sampled(info.FullMethod) {
+			return resp, err
+		}
+
+		msg, ok := req.(proto.Message)
+		if !ok {
+			return resp, err
+		}
+		call := mirror.Call{Method: info.FullMethod, RequestID: RequestIDFromContext(ctx), Request: msg, Err: err}
+		if err == nil {
+			call.Response, _ = resp.(proto.Message)
+		}
+		m.Submit(ctx, call)
+		return resp, err
+	}
+}
@@ -0,0 +1,219 @@
+package interceptors
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"log"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"notes-service/internal/auth"
+	notesv1 "notes-service/pkg/proto/notes/v1"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// deprecationHeader метаданные ответа устаревшего метода (заголовок Deprecation, RFC 9745)
+const deprecationHeader = "deprecation"
+
+// MethodPolicy политика вызова метода из опции (notes.v1.policy) в proto
+type MethodPolicy struct {
+	RequiresAuth bool             // Требуется действительный токен
+	Roles        []string         // Роли, любая из которых разрешает вызов; пусто - любой пользователь
+	RateLimit    *StreamRateLimit // Лимит входящих сообщений стрима, nil - без лимита
+	Timeout      time.Duration    // Максимальное время unary запроса, 0 - без ограничения
+	Idempotent   bool             // Повторный вызов безопасен
+	Deprecated   bool             // Метод устарел
+}
+
+// MethodPolicies политики методов по полным именам ("/notes.v1.NotesService/GetNote")
+type MethodPolicies map[string]MethodPolicy
+
+// LoadMethodPolicies читает политики всех методов сервисов files из их дескрипторов
+// Метод без опции получает политику по умолчанию: требуется токен, без ролей и ограничений
+func LoadMethodPolicies(files ...protoreflect.FileDescriptor) MethodPolicies {
+	policies := make(MethodPolicies)
+	for _, file := range files {
+		services := file.Services()
+		for i := 0; i < services.Len(); i++ {
+			methods := services.Get(i).Methods()
+			for j := 0; j < methods.Len(); j++ {
+				method := methods.Get(j)
+				fullMethod := "/" + string(method.Parent().FullName()) + "/" + string(method.Name())
+				policies[fullMethod] = methodPolicy(method)
+			}
+		}
+	}
+	return policies
+}
+
+// methodPolicy преобразует опцию метода в MethodPolicy
+func methodPolicy(method protoreflect.MethodDescriptor) MethodPolicy {
+	policy := MethodPolicy{RequiresAuth: true}
+	opts := method.Options()
+	if opts == nil || !proto.HasExtension(opts, notesv1.E_Policy) {
+		return policy
+	}
+
+	option := proto.GetExtension(opts, notesv1.E_Policy).(*notesv1.MethodPolicy)
+	if option.RequiresAuth != nil {
+		policy.RequiresAuth = option.GetRequiresAuth()
+	}
+	policy.Roles = option.GetRoles()
+	if limit := option.GetRateLimit(); limit.GetMessagesPerSecond() > 0 {
+		policy.RateLimit = &StreamRateLimit{MessagesPerSecond: limit.GetMessagesPerSecond(), Burst: int(limit.GetBurst())}
+	}
+	if timeout := option.GetTimeout(); timeout != nil {
+		policy.Timeout = timeout.AsDuration()
+	}
+	policy.Idempotent = option.GetIdempotent()
+	policy.Deprecated = option.GetDeprecated()
+	return policy
+}
+
+// PublicMethods возвращает методы, доступные без токена, в порядке имен
+func (p MethodPolicies) PublicMethods() []string {
+	var methods []string
+	for method, policy := range p {
+		if !policy.RequiresAuth {
+			methods = append(methods, method)
+		}
+	}
+	slices.Sort(methods)
+	return methods
+}
+
+// StreamRateLimits возвращает лимиты стримов из политик
+func (p MethodPolicies) StreamRateLimits() map[string]StreamRateLimit {
+	limits := make(map[string]StreamRateLimit)
+	for method, policy := range p {
+		if policy.RateLimit != nil {
+			limits[method] = *policy.RateLimit
+		}
+	}
+	return limits
+}
+
+// serviceConfigName имя метода в service config gRPC
+type serviceConfigName struct {
+	Service string `json:"service"`
+	Method  string `json:"method"`
+}
+
+// RetryServiceConfig возвращает service config gRPC клиента, повторяющий идемпотентные методы
+// при UNAVAILABLE (например, при перезапуске сервера или в режиме деградации хранилища)
+func (p MethodPolicies) RetryServiceConfig() string {
+	var names []serviceConfigName
+	for method, policy := range p {
+		service, name, ok := strings.Cut(strings.TrimPrefix(method, "/"), "/")
+		if policy.Idempotent && ok {
+			names = append(names, serviceConfigName{Service: service, Method: name})
+		}
+	}
+	slices.SortFunc(names, func(a, b serviceConfigName) int {
+		return cmp.Or(strings.Compare(a.Service, b.Service), strings.Compare(a.Method, b.Method))
+	})
+
+	methodConfig := []any{}
+	if len(names) > 0 {
+		methodConfig = append(methodConfig, map[string]any{
+			"name": names,
+			"retryPolicy": map[string]any{
+				"maxAttempts":          3,
+				"initialBackoff":       "0.1s",
+				"maxBackoff":           "1s",
+				"backoffMultiplier":    2,
+				"retryableStatusCodes": []string{"UNAVAILABLE"},
+			},
+		})
+	}
+	data, _ := json.Marshal(map[string]any{"methodConfig": methodConfig})
+	return string(data)
+}
+
+// PolicyUnaryInterceptor применяет политики методов к unary запросам: проверяет роли
+// пользователя, ограничивает время выполнения и отмечает вызовы устаревших методов
+// Вызывается после AuthInterceptor: пользователь уже в контексте
+func PolicyUnaryInterceptor(policies MethodPolicies) grpc.UnaryServerInterceptor {
+	deprecated := &deprecationLog{}
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		policy, ok := policies[info.FullMethod]
+		if !ok {
+			return handler(ctx, req)
+		}
+		if err := checkRoles(ctx, policy); err != nil {
+			return nil, err
+		}
+		if policy.Deprecated {
+			deprecated.record(info.FullMethod)
+			_ = grpc.SetHeader(ctx, metadata.Pairs(deprecationHeader, "true"))
+		}
+		if policy.Timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, policy.Timeout)
+			defer cancel()
+		}
+		return handler(ctx, req)
+	}
+}
+
+// PolicyStreamInterceptor применяет политики методов к стримам: проверяет роли пользователя
+// и отмечает вызовы устаревших методов. Время стримов не ограничивается
+func PolicyStreamInterceptor(policies MethodPolicies) grpc.StreamServerInterceptor {
+	deprecated := &deprecationLog{}
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		policy, ok := policies[info.FullMethod]
+		if !ok {
+			return handler(srv, ss)
+		}
+		if err := checkRoles(ss.Context(), policy); err != nil {
+			return err
+		}
+		if policy.Deprecated {
+			deprecated.record(info.FullMethod)
+			_ = ss.SetHeader(metadata.Pairs(deprecationHeader, "true"))
+		}
+		return handler(srv, ss)
+	}
+}
+
+// checkRoles проверяет, что у пользователя есть одна из ролей политики
+func checkRoles(ctx context.Context, policy MethodPolicy) error {
+	if len(policy.Roles) == 0 {
+		return nil
+	}
+	principal, ok := auth.FromContext(ctx)
+	if ok && slices.ContainsFunc(policy.Roles, principal.HasRole) {
+		return nil
+	}
+	return permissionDenied()
+}
+
+// permissionDenied возвращает ошибку недостаточных прав в формате ответа хэндлера на auth.ErrPermissionDenied
+func permissionDenied() error {
+	st := status.New(codes.PermissionDenied, auth.ErrPermissionDenied.Error())
+	st, _ = st.WithDetails(&notesv1.ErrorDetails{
+		Reason:            "The caller is not allowed to perform this operation",
+		InternalErrorCode: "PERMISSION_DENIED",
+	})
+	return st.Err()
+}
+
+// deprecationLog записывает в лог первый вызов каждого устаревшего метода
+type deprecationLog struct {
+	logged sync.Map
+}
+
+func (d *deprecationLog) record(method string) {
+	if _, loaded := d.logged.LoadOrStore(method, true); !loaded {
+		log.Printf("⚠️  Deprecated method called: %s", method)
+	}
+}
@@ -0,0 +1,78 @@
+package interceptors
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"testing"
+	"time"
+
+	"notes-service/internal/auth"
+	notesv1 "notes-service/pkg/proto/notes/v1"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestLoadMethodPolicies(t *testing.T) {
+	policies := LoadMethodPolicies(notesv1.File_proto_notes_v1_notes_proto)
+
+	if public := policies.PublicMethods(); !slices.Equal(public, []string{
+		notesv1.AuthService_Login_FullMethodName,
+		notesv1.AuthService_Logout_FullMethodName,
+		notesv1.AuthService_RefreshToken_FullMethodName,
+	}) {
+		t.Errorf("Unexpected public methods: %v", public)
+	}
+
+	restore := policies[notesv1.NotesService_RestoreBackup_FullMethodName]
+	if !restore.RequiresAuth || !slices.Equal(restore.Roles, []string{"admin"}) || restore.Timeout != 10*time.Minute {
+		t.Errorf("Unexpected RestoreBackup policy: %+v", restore)
+	}
+	if get := policies[notesv1.NotesService_GetNote_FullMethodName]; !get.RequiresAuth || !get.Idempotent || len(get.Roles) > 0 {
+		t.Errorf("Unexpected GetNote policy: %+v", get)
+	}
+	if limit := policies.StreamRateLimits()[notesv1.NotesService_Chat_FullMethodName]; limit != (StreamRateLimit{MessagesPerSecond: 10, Burst: 20}) {
+		t.Errorf("Unexpected Chat rate limit: %+v", limit)
+	}
+
+	var config struct {
+		MethodConfig []struct {
+			Name []serviceConfigName `json:"name"`
+		} `json:"methodConfig"`
+	}
+	if err := json.Unmarshal([]byte(policies.RetryServiceConfig()), &config); err != nil || len(config.MethodConfig) != 1 {
+		t.Fatalf("Unexpected retry service config: %v", err)
+	}
+	if !slices.Contains(config.MethodConfig[0].Name, serviceConfigName{Service: "notes.v1.NotesService", Method: "GetNote"}) {
+		t.Error("Expected GetNote in retry service config")
+	}
+}
+
+func TestPolicyUnaryInterceptor(t *testing.T) {
+	const method = "/notes.v1.NotesService/RestoreBackup"
+	interceptor := PolicyUnaryInterceptor(MethodPolicies{
+		method: {RequiresAuth: true, Roles: []string{"admin"}, Timeout: time.Second},
+	})
+	info := &grpc.UnaryServerInfo{FullMethod: method}
+
+	var deadline time.Time
+	handler := func(ctx context.Context, _ any) (any, error) {
+		deadline, _ = ctx.Deadline()
+		return "ok", nil
+	}
+
+	ctx := auth.NewContext(context.Background(), auth.Principal{UserID: "user-1"})
+	if _, err := interceptor(ctx, nil, info, handler); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("Expected PermissionDenied without role, got: %v", err)
+	}
+
+	ctx = auth.NewContext(context.Background(), auth.Principal{UserID: "admin-1", Roles: []string{"admin"}})
+	if _, err := interceptor(ctx, nil, info, handler); err != nil {
+		t.Fatalf("Expected call with admin role to succeed, got: %v", err)
+	}
+	if deadline.IsZero() || time.Until(deadline) > time.Second {
+		t.Errorf("Expected policy timeout in handler context, got deadline %v", deadline)
+	}
+}
@@ -0,0 +1,266 @@
+package interceptors
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"notes-service/internal/auth"
+	"notes-service/internal/telemetry"
+	notesv1 "notes-service/pkg/proto/notes/v1"
+
+	"golang.org/x/time/rate"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// StreamRateLimit лимит входящих сообщений одного стрима (token bucket)
+type StreamRateLimit struct {
+	MessagesPerSecond float64 // Скорость пополнения бюджета, сообщений в секунду
+	Burst             int     // Размер бюджета: столько сообщений можно отправить подряд
+}
+
+// StreamRateLimitError возвращается из RecvMsg, когда клиент превысил лимит сообщений стрима
+// Сообщение уже прочитано и отбрасывается; стрим остается открытым, и обработчик решает,
+// ответить ли бизнес-ошибкой (Chat) или завершить стрим, вернув эту ошибку (ResourceExhausted)
+type StreamRateLimitError struct {
+	Method  string
+	Limit   StreamRateLimit
+	Message any // Отброшенное сообщение, например для correlation_id в ответе
+}
+
+func (e *StreamRateLimitError) Error() string {
+	return fmt.Sprintf("stream message rate limit exceeded for %s: %g messages per second (burst %d)",
+		e.Method, e.Limit.MessagesPerSecond, e.Limit.Burst)
+}
+
+// GRPCStatus позволяет вернуть ошибку из обработчика как есть со статусом ResourceExhausted
+func (e *StreamRateLimitError) GRPCStatus() *status.Status {
+	return status.New(codes.ResourceExhausted, e.Error())
+}
+
+// rateLimitedServerStream ограничивает скорость входящих сообщений стрима
+type rateLimitedServerStream struct {
+	grpc.ServerStream
+	method  string
+	limit   StreamRateLimit
+	limiter *rate.Limiter
+}
+
+// RecvMsg принимает сообщение и проверяет бюджет стрима
+func (s *rateLimitedServerStream) RecvMsg(m any) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+	if !s.limiter.Allow() {
+		rateLimited(s.Context(), telemetry.LimiterStreamMessages)
+		return &StreamRateLimitError{Method: s.method, Limit: s.limit, Message: m}
+	}
+	return nil
+}
+
+// NewStreamRateLimitInterceptor ограничивает скорость входящих сообщений каждого стрима
+// методов из limits (ключ - полное имя метода, например "/notes.v1.NotesService/Chat")
+// Бюджет выделяется каждому стриму отдельно, стримы методов без лимита (или с нулевой скоростью)
+// не ограничиваются
+func NewStreamRateLimitInterceptor(limits map[string]StreamRateLimit) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		limit, ok := limits[info.FullMethod]
+		if !ok || !info.IsClientStream || limit.MessagesPerSecond <= 0 {
+			return handler(srv, ss)
+		}
+
+		return handler(srv, &rateLimitedServerStream{
+			ServerStream: ss,
+			method:       info.FullMethod,
+			limit:        limit,
+			limiter:      rate.NewLimiter(rate.Limit(limit.MessagesPerSecond), max(limit.Burst, 1)),
+		})
+	}
+}
+
+// Способы группировки запросов для RateLimit
+const (
+	RateLimitKeyUser = "user" // Аутентифицированный пользователь, без пользователя - адрес клиента
+	RateLimitKeyPeer = "peer" // IP адрес клиента (все запросы через Gateway делят один бюджет)
+)
+
+// rateLimitSweepInterval интервал удаления бюджетов клиентов, которые полностью восстановились
+const rateLimitSweepInterval = time.Minute
+
+// RateLimit лимит запросов одного клиента (token bucket)
+type RateLimit struct {
+	RequestsPerSecond float64 // Скорость пополнения бюджета, запросов в секунду
+	Burst             int     // Размер бюджета: столько запросов можно выполнить подряд
+	Key               string  // RateLimitKeyUser (по умолчанию) или RateLimitKeyPeer
+}
+
+// RateLimiter хранит бюджеты запросов клиентов gRPC сервера
+// Gateway ограничивает HTTP запросы своим лимитом, RateLimiter - все запросы, включая прямые gRPC клиенты
+type RateLimiter struct {
+	limit RateLimit
+	now   func() time.Time
+
+	mu        sync.Mutex
+	limiters  map[string]*rate.Limiter
+	lastSweep time.Time
+}
+
+// NewRateLimiter создает лимиты запросов клиентов; при нулевой скорости запросы не ограничиваются
+func NewRateLimiter(limit RateLimit) *RateLimiter {
+	if limit.Key == "" {
+		limit.Key = RateLimitKeyUser
+	}
+	if limit.Burst <= 0 {
+		limit.Burst = max(int(limit.RequestsPerSecond), 1)
+	}
+	return &RateLimiter{
+		limit:    limit,
+		now:      time.Now,
+		limiters: make(map[string]*rate.Limiter),
+	}
+}
+
+// Settings описывает лимит для AdminService.GetPipeline
+func (l *RateLimiter) Settings() map[string]string {
+	limit := l.current()
+	return map[string]string{
+		"rps":   strconv.FormatFloat(limit.RequestsPerSecond, 'f', -1, 64),
+		"burst": strconv.Itoa(limit.Burst),
+		"key":   limit.Key,
+	}
+}
+
+// SetLimit меняет скорость и бюджет для следующих запросов всех клиентов (0 - без ограничения),
+// накопленные бюджеты клиентов сохраняются; ключ клиентов не меняется
+func (l *RateLimiter) SetLimit(requestsPerSecond float64, burst int) {
+	if burst <= 0 {
+		burst = max(int(requestsPerSecond), 1)
+	}
+	now := l.now()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.limit.RequestsPerSecond = requestsPerSecond
+	l.limit.Burst = burst
+	for _, limiter := range l.limiters {
+		limiter.SetLimitAt(now, rate.Limit(requestsPerSecond))
+		limiter.SetBurstAt(now, burst)
+	}
+}
+
+// current возвращает действующий лимит
+func (l *RateLimiter) current() RateLimit {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.limit
+}
+
+// RateLimitUnaryInterceptor ограничивает скорость unary запросов каждого клиента
+// Вызывается после AuthInterceptor, чтобы различать пользователей за одним адресом (например, Gateway)
+func RateLimitUnaryInterceptor(l *RateLimiter) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if err := l.allow(ctx, info.FullMethod); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// RateLimitStreamInterceptor расходует бюджет клиента при открытии стрима; сообщения стрима
+// ограничивает NewStreamRateLimitInterceptor
+func RateLimitStreamInterceptor(l *RateLimiter) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := l.allow(ss.Context(), info.FullMethod); err != nil {
+			return err
+		}
+		return handler(srv, ss)
+	}
+}
+
+// allow расходует запрос из бюджета клиента запроса или возвращает ResourceExhausted с RetryInfo
+func (l *RateLimiter) allow(ctx context.Context, method string) error {
+	limit := l.current()
+	if limit.RequestsPerSecond <= 0 {
+		return nil
+	}
+
+	key := l.key(ctx, limit.Key)
+	delay := l.reserve(key)
+	if delay <= 0 {
+		return nil
+	}
+
+	logf(ctx, "Rate limit exceeded for %s (method: %s)", key, method)
+	rateLimited(ctx, telemetry.LimiterClient)
+	st := status.New(codes.ResourceExhausted, "rate limit exceeded")
+	st, _ = st.WithDetails(
+		&notesv1.ErrorDetails{
+			Reason: fmt.Sprintf("Too many requests: the limit is %g requests per second (burst %d); retry after %s",
+				limit.RequestsPerSecond, limit.Burst, delay.Round(time.Millisecond)),
+			InternalErrorCode: "RATE_LIMIT_EXCEEDED",
+		},
+		&errdetails.RetryInfo{RetryDelay: durationpb.New(delay)},
+	)
+	return st.Err()
+}
+
+// key возвращает клиента запроса: пользователя (для RateLimitKeyUser) или IP адрес
+func (l *RateLimiter) key(ctx context.Context, key string) string {
+	if key == RateLimitKeyUser {
+		if principal, ok := auth.FromContext(ctx); ok {
+			return "user:" + principal.UserID
+		}
+	}
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return "peer:unknown"
+	}
+	// Порт не учитывается: клиент открывает новые соединения с разных портов
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		host = p.Addr.String()
+	}
+	return "peer:" + host
+}
+
+// reserve расходует запрос из бюджета клиента key и возвращает 0 или время до следующего
+// разрешенного запроса (тогда бюджет не расходуется)
+func (l *RateLimiter) reserve(key string) time.Duration {
+	now := l.now()
+
+	l.mu.Lock()
+	if now.Sub(l.lastSweep) >= rateLimitSweepInterval {
+		l.sweep(now)
+	}
+	limiter, ok := l.limiters[key]
+	if !ok {
+		limiter = rate.NewLimiter(rate.Limit(l.limit.RequestsPerSecond), l.limit.Burst)
+		l.limiters[key] = limiter
+	}
+	l.mu.Unlock()
+
+	reservation := limiter.ReserveN(now, 1)
+	delay := reservation.DelayFrom(now)
+	if delay > 0 {
+		reservation.CancelAt(now)
+	}
+	return delay
+}
+
+// sweep удаляет бюджеты, восстановившиеся полностью: они не отличаются от новых (вызывается под mu)
+func (l *RateLimiter) sweep(now time.Time) {
+	l.lastSweep = now
+	for key, limiter := range l.limiters {
+		if limiter.TokensAt(now) >= float64(l.limit.Burst) {
+			delete(l.limiters, key)
+		}
+	}
+}
@@ -0,0 +1,168 @@
+package interceptors
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strconv"
+	"testing"
+	"time"
+
+	"notes-service/internal/auth"
+	notesv1 "notes-service/pkg/proto/notes/v1"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// fakeServerStream отдает входящие сообщения чата с возрастающим correlation_id
+type fakeServerStream struct {
+	grpc.ServerStream
+	received int
+}
+
+func (s *fakeServerStream) Context() context.Context { return context.Background() }
+
+func (s *fakeServerStream) RecvMsg(m any) error {
+	s.received++
+	m.(*notesv1.ChatMessage).CorrelationId = strconv.Itoa(s.received)
+	return nil
+}
+
+func TestStreamRateLimitInterceptor(t *testing.T) {
+	const method = "/notes.v1.NotesService/Chat"
+	interceptor := NewStreamRateLimitInterceptor(map[string]StreamRateLimit{
+		method: {MessagesPerSecond: 0.001, Burst: 2},
+	})
+	info := &grpc.StreamServerInfo{FullMethod: method, IsClientStream: true, IsServerStream: true}
+
+	var errs []error
+	err := interceptor(nil, &fakeServerStream{}, info, func(_ any, stream grpc.ServerStream) error {
+		for range 4 {
+			errs = append(errs, stream.RecvMsg(&notesv1.ChatMessage{}))
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	// Бюджет в 2 сообщения исчерпан, следующие сообщения отбрасываются, но стрим продолжает читать
+	if errs[0] != nil || errs[1] != nil {
+		t.Fatalf("Expected burst messages to pass, got %v", errs[:2])
+	}
+	var rateLimitErr *StreamRateLimitError
+	if !errors.As(errs[2], &rateLimitErr) || errs[3] == nil {
+		t.Fatalf("Expected StreamRateLimitError after burst, got %v", errs[2:])
+	}
+	if got := rateLimitErr.Message.(*notesv1.ChatMessage).GetCorrelationId(); got != "3" {
+		t.Errorf("Expected dropped message 3, got %q", got)
+	}
+	if status.Code(errs[2]) != codes.ResourceExhausted {
+		t.Errorf("Expected ResourceExhausted status, got %v", status.Code(errs[2]))
+	}
+}
+
+func TestStreamRateLimitInterceptor_UnlimitedMethod(t *testing.T) {
+	interceptor := NewStreamRateLimitInterceptor(map[string]StreamRateLimit{
+		"/notes.v1.NotesService/Chat": {MessagesPerSecond: 0.001, Burst: 1},
+	})
+	info := &grpc.StreamServerInfo{FullMethod: "/notes.v1.NotesService/UploadMetrics", IsClientStream: true}
+
+	err := interceptor(nil, &fakeServerStream{}, info, func(_ any, stream grpc.ServerStream) error {
+		for range 10 {
+			if err := stream.RecvMsg(&notesv1.ChatMessage{}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Errorf("Expected method without limit to be unrestricted, got: %v", err)
+	}
+}
+
+func TestRateLimitUnaryInterceptor(t *testing.T) {
+	limiter := NewRateLimiter(RateLimit{RequestsPerSecond: 1, Burst: 2})
+	now := time.Now()
+	limiter.now = func() time.Time { return now }
+	interceptor := RateLimitUnaryInterceptor(limiter)
+	info := &grpc.UnaryServerInfo{FullMethod: "/notes.v1.NotesService/ListNotes"}
+	call := func(ctx context.Context) error {
+		_, err := interceptor(ctx, nil, info, func(context.Context, any) (any, error) { return "ok", nil })
+		return err
+	}
+
+	gateway := peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 40001}})
+	alice := auth.NewContext(gateway, auth.Principal{UserID: "alice"})
+	bob := auth.NewContext(gateway, auth.Principal{UserID: "bob"})
+
+	// Бюджет пользователя расходуется независимо от адреса, ошибка содержит время до повтора
+	for range 2 {
+		if err := call(alice); err != nil {
+			t.Fatalf("Expected request within burst, got: %v", err)
+		}
+	}
+	err := call(alice)
+	if status.Code(err) != codes.ResourceExhausted {
+		t.Fatalf("Expected ResourceExhausted after burst, got: %v", err)
+	}
+	var retry *errdetails.RetryInfo
+	for _, detail := range status.Convert(err).Details() {
+		if info, ok := detail.(*errdetails.RetryInfo); ok {
+			retry = info
+		}
+	}
+	if retry == nil || retry.GetRetryDelay().AsDuration() != time.Second {
+		t.Errorf("Expected RetryInfo with 1s delay, got %v", retry)
+	}
+	if err := call(bob); err != nil {
+		t.Errorf("Expected another user to have own budget, got: %v", err)
+	}
+
+	// Запросы без пользователя делят бюджет IP адреса независимо от порта
+	other := peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 40002}})
+	call(gateway)
+	call(gateway)
+	if err := call(other); status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("Expected peer budget shared across ports, got: %v", err)
+	}
+
+	// Отклоненный запрос не расходует бюджет, полностью восстановленные бюджеты удаляются
+	now = now.Add(time.Second)
+	if err := call(alice); err != nil {
+		t.Errorf("Expected request after refill, got: %v", err)
+	}
+	now = now.Add(rateLimitSweepInterval)
+	call(bob)
+	if len(limiter.limiters) != 1 {
+		t.Errorf("Expected only the active budget after sweep, got %d", len(limiter.limiters))
+	}
+}
+
+func TestRateLimiter_SetLimit(t *testing.T) {
+	limiter := NewRateLimiter(RateLimit{RequestsPerSecond: 1, Burst: 1})
+	now := time.Now()
+	limiter.now = func() time.Time { return now }
+	alice := auth.NewContext(context.Background(), auth.Principal{UserID: "alice"})
+
+	if err := limiter.allow(alice, "/notes.v1.NotesService/ListNotes"); err != nil {
+		t.Fatalf("Expected request within burst, got: %v", err)
+	}
+	if err := limiter.allow(alice, "/notes.v1.NotesService/ListNotes"); status.Code(err) != codes.ResourceExhausted {
+		t.Fatalf("Expected ResourceExhausted after burst, got: %v", err)
+	}
+
+	// Новый лимит действует и для уже известных клиентов
+	limiter.SetLimit(0, 0)
+	if err := limiter.allow(alice, "/notes.v1.NotesService/ListNotes"); err != nil {
+		t.Errorf("Expected no limit after SetLimit(0), got: %v", err)
+	}
+	limiter.SetLimit(10, 5)
+	if got := limiter.Settings(); got["rps"] != "10" || got["burst"] != "5" || got["key"] != RateLimitKeyUser {
+		t.Errorf("Unexpected settings after SetLimit: %v", got)
+	}
+}
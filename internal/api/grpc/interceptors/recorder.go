@@ -0,0 +1,40 @@
+package interceptors
+
+import (
+	"context"
+	"strings"
+
+	"notes-service/internal/auth"
+	"notes-service/internal/recorder"
+	notesv1 "notes-service/pkg/proto/notes/v1"
+
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+)
+
+// authServicePrefix префикс полных имен методов AuthService
+var authServicePrefix = "/" + notesv1.AuthService_ServiceDesc.ServiceName + "/"
+
+// RecorderUnaryInterceptor записывает очищенные unary запросы для воспроизведения через cmd/replay
+// Ошибка записи не влияет на обработку запроса
+// Запросы AuthService, CreateUser, RegisterWebhook и SetNotePassphrase содержат пароли, refresh токены,
+// ключи подписи и парольные фразы и не записываются
+func RecorderUnaryInterceptor(rec *recorder.Recorder) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if strings.HasPrefix(info.FullMethod, authServicePrefix) ||
+			info.FullMethod == notesv1.UserService_CreateUser_FullMethodName ||
+			info.FullMethod == notesv1.NotesService_RegisterWebhook_FullMethodName ||
+			info.FullMethod == notesv1.NotesService_SetNotePassphrase_FullMethodName {
+			return handler(ctx, req)
+		}
+
+		if msg, ok := req.(proto.Message); ok {
+			principal, _ := auth.FromContext(ctx)
+			if err := rec.Record(info.FullMethod, principal.UserID, msg); err != nil {
+				logf(ctx, "Failed to record request %s: %v", info.FullMethod, err)
+			}
+		}
+
+		return handler(ctx, req)
+	}
+}
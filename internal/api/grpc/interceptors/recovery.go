@@ -0,0 +1,55 @@
+package interceptors
+
+import (
+	"context"
+	"runtime/debug"
+
+	notesv1 "notes-service/pkg/proto/notes/v1"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// RecoveryUnaryInterceptor перехватывает панику в обработке запроса: записывает в лог стек
+// и возвращает клиенту Internal вместо завершения процесса. Стоит первым в цепочке,
+// чтобы перехватывать панику и в остальных интерцепторах, в том числе в RequestID и Metrics
+func RecoveryUnaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = recovered(ctx, info.FullMethod, r)
+		}
+	}()
+	return handler(ctx, req)
+}
+
+// RecoveryStreamInterceptor перехватывает панику в обработке стрима, как RecoveryUnaryInterceptor
+// Стрим завершается со статусом Internal
+func RecoveryStreamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = recovered(ss.Context(), info.FullMethod, r)
+		}
+	}()
+	return handler(srv, ss)
+}
+
+// recovered записывает панику в лог и возвращает ошибку для клиента
+// Значение паники не передается клиенту: оно может содержать внутренние данные
+// Идентификатор запроса из паники, прошедшей через RequestID, добавляется в лог и ErrorDetails
+func recovered(ctx context.Context, method string, r interface{}) error {
+	details := &notesv1.ErrorDetails{
+		Reason:            "The server failed to process the request",
+		InternalErrorCode: "INTERNAL_ERROR",
+	}
+	if p, ok := r.(requestPanic); ok {
+		ctx = context.WithValue(ctx, requestIDKey{}, p.requestID)
+		details.RequestId = p.requestID
+		r = p.value
+	}
+	logf(ctx, "Panic in %s: %v\n%s", method, r, debug.Stack())
+
+	st := status.New(codes.Internal, "internal error")
+	st, _ = st.WithDetails(details)
+	return st.Err()
+}
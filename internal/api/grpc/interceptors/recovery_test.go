@@ -0,0 +1,87 @@
+package interceptors
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"notes-service/internal/telemetry"
+	notesv1 "notes-service/pkg/proto/notes/v1"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestRecoveryInterceptors_ConvertPanicToInternal(t *testing.T) {
+	check := func(name string, err error) {
+		t.Helper()
+		st := status.Convert(err)
+		if st.Code() != codes.Internal {
+			t.Fatalf("%s: expected Internal, got %v", name, err)
+		}
+		if len(st.Details()) != 1 || st.Details()[0].(*notesv1.ErrorDetails).GetInternalErrorCode() != "INTERNAL_ERROR" {
+			t.Errorf("%s: expected INTERNAL_ERROR details, got %v", name, st.Details())
+		}
+	}
+
+	_, err := RecoveryUnaryInterceptor(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: "/notes.v1.NotesService/GetNote"},
+		func(context.Context, any) (any, error) { panic("nil map") })
+	check("unary", err)
+
+	err = RecoveryStreamInterceptor(nil, &fakeServerStream{}, &grpc.StreamServerInfo{FullMethod: "/notes.v1.NotesService/Chat"},
+		func(any, grpc.ServerStream) error { panic("closed channel") })
+	check("stream", err)
+
+	// Без паники ответ хендлера не меняется
+	resp, err := RecoveryUnaryInterceptor(context.Background(), nil, &grpc.UnaryServerInfo{},
+		func(context.Context, any) (any, error) { return "ok", nil })
+	if resp != "ok" || err != nil {
+		t.Errorf("Expected handler response, got %v, %v", resp, err)
+	}
+}
+
+func TestRecoveryInterceptors_PanicInOuterInterceptor(t *testing.T) {
+	registry := telemetry.NewRegistry()
+	chain := []grpc.UnaryServerInterceptor{RecoveryUnaryInterceptor, RequestIDUnaryInterceptor, MetricsUnaryInterceptor(registry)}
+	info := &grpc.UnaryServerInfo{FullMethod: "/notes.v1.NotesService/GetNote"}
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(RequestIDHeader, "abc-123"))
+
+	// Паника в интерцепторе сразу за Metrics, до хендлера
+	panicking := func(context.Context, any, *grpc.UnaryServerInfo, grpc.UnaryHandler) (any, error) {
+		panic("outer interceptor")
+	}
+	_, err := chainUnary(append(chain, panicking)...)(ctx, nil, info, func(context.Context, any) (any, error) {
+		t.Fatal("handler must not be called")
+		return nil, nil
+	})
+	st := status.Convert(err)
+	if st.Code() != codes.Internal {
+		t.Fatalf("Expected Internal, got %v", err)
+	}
+	details := st.Details()[0].(*notesv1.ErrorDetails)
+	if details.GetInternalErrorCode() != "INTERNAL_ERROR" || details.GetRequestId() != "abc-123" {
+		t.Errorf("Expected INTERNAL_ERROR details with request id, got %v", details)
+	}
+
+	out := scrapeMetrics(registry)
+	want := `notes_grpc_requests_total{code="Internal",method="GetNote",service="notes.v1.NotesService"} 1`
+	if !strings.Contains(out, want) {
+		t.Errorf("Expected %s in metrics, got:\n%s", want, out)
+	}
+}
+
+// chainUnary объединяет интерцепторы в порядке выполнения, как grpc.ChainUnaryInterceptor
+func chainUnary(interceptors ...grpc.UnaryServerInterceptor) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		next := handler
+		for i := len(interceptors) - 1; i >= 0; i-- {
+			interceptor, inner := interceptors[i], next
+			next = func(ctx context.Context, req any) (any, error) {
+				return interceptor(ctx, req, info, inner)
+			}
+		}
+		return next(ctx, req)
+	}
+}
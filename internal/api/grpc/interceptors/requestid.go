@@ -0,0 +1,146 @@
+package interceptors
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"log/slog"
+	"strings"
+	"unicode"
+
+	"notes-service/internal/logging"
+	notesv1 "notes-service/pkg/proto/notes/v1"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+// RequestIDHeader метаданные с идентификатором запроса (заголовок X-Request-Id в HTTP Gateway)
+const RequestIDHeader = "x-request-id"
+
+// maxRequestIDLength максимальная длина идентификатора запроса от клиента
+const maxRequestIDLength = 128
+
+// requestIDKey ключ идентификатора запроса в контексте
+type requestIDKey struct{}
+
+// RequestIDFromContext возвращает идентификатор запроса, назначенный RequestIDUnaryInterceptor
+// или RequestIDStreamInterceptor, или пустую строку
+func RequestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// RequestIDUnaryInterceptor берет идентификатор запроса из метаданных x-request-id клиента
+// или создает новый (UUID), сохраняет его в контексте для логов, возвращает клиенту в заголовке
+// ответа x-request-id и добавляет в ErrorDetails ошибки. Стоит сразу после Recovery,
+// чтобы идентификатор был в логах всех интерцепторов и в ошибках, в том числе после паники
+func RequestIDUnaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	ctx, id := withRequestID(ctx)
+	defer repanicWithRequestID(id)
+	if err := grpc.SetHeader(ctx, metadata.Pairs(RequestIDHeader, id)); err != nil {
+		logf(ctx, "Failed to set request id header: %v", err)
+	}
+	resp, err := handler(ctx, req)
+	return resp, errorWithRequestID(err, id)
+}
+
+// RequestIDStreamInterceptor назначает идентификатор стриму, как RequestIDUnaryInterceptor
+func RequestIDStreamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, id := withRequestID(ss.Context())
+	defer repanicWithRequestID(id)
+	if err := ss.SetHeader(metadata.Pairs(RequestIDHeader, id)); err != nil {
+		logf(ctx, "Failed to set request id header: %v", err)
+	}
+	err := handler(srv, &contextServerStream{ServerStream: ss, ctx: ctx})
+	return errorWithRequestID(err, id)
+}
+
+// requestPanic паника в обработке запроса с идентификатором запроса
+// Recovery стоит снаружи RequestID и получает идентификатор для лога и ErrorDetails из нее
+type requestPanic struct {
+	requestID string
+	value     interface{}
+}
+
+// repanicWithRequestID продолжает панику обработки запроса id, добавив к ней идентификатор
+// Вызывается через defer: стек исходной паники сохраняется
+func repanicWithRequestID(id string) {
+	if r := recover(); r != nil {
+		if _, ok := r.(requestPanic); !ok {
+			r = requestPanic{requestID: id, value: r}
+		}
+		panic(r)
+	}
+}
+
+// withRequestID сохраняет в контексте идентификатор из метаданных клиента или новый,
+// если клиент его не передал или передал слишком длинный или с непечатаемыми символами
+func withRequestID(ctx context.Context) (context.Context, string) {
+	var id string
+	if values := metadata.ValueFromIncomingContext(ctx, RequestIDHeader); len(values) > 0 {
+		id = strings.TrimSpace(values[0])
+	}
+	if id == "" || len(id) > maxRequestIDLength || strings.IndexFunc(id, func(r rune) bool { return r > unicode.MaxASCII || !unicode.IsPrint(r) }) >= 0 {
+		id = uuid.NewString()
+	}
+	return context.WithValue(ctx, requestIDKey{}, id), id
+}
+
+// errorWithRequestID добавляет идентификатор запроса в ErrorDetails статуса ошибки
+// (в существующие детали или новыми ErrorDetails); ошибки без статуса получают статус, как в gRPC сервере:
+// ошибки контекста - Canceled или DeadlineExceeded, остальные - Unknown
+func errorWithRequestID(err error, id string) error {
+	if err == nil {
+		return nil
+	}
+	converted, ok := status.FromError(err)
+	if !ok {
+		converted = status.FromContextError(err)
+	}
+	st := converted.Proto()
+	for i, detail := range st.GetDetails() {
+		var details notesv1.ErrorDetails
+		if !detail.MessageIs(&details) || detail.UnmarshalTo(&details) != nil {
+			continue
+		}
+		details.RequestId = id
+		if updated, err := anypb.New(&details); err == nil {
+			st.Details[i] = updated
+		}
+		return status.FromProto(st).Err()
+	}
+
+	detail, err := anypb.New(&notesv1.ErrorDetails{RequestId: id})
+	if err != nil {
+		return status.FromProto(st).Err()
+	}
+	st.Details = append(st.Details, detail)
+	return status.FromProto(st).Err()
+}
+
+// logf записывает в лог сообщение интерцептора с идентификатором запроса из ctx (уровень info)
+func logf(ctx context.Context, format string, args ...any) {
+	if logging.Enabled(slog.LevelInfo) {
+		printf(ctx, format, args...)
+	}
+}
+
+// debugf записывает в лог подробности запроса, например сообщения стримов (уровень debug)
+func debugf(ctx context.Context, format string, args ...any) {
+	if logging.Enabled(slog.LevelDebug) {
+		printf(ctx, format, args...)
+	}
+}
+
+// printf записывает сообщение с префиксом request_id без проверки уровня
+func printf(ctx context.Context, format string, args ...any) {
+	if id := RequestIDFromContext(ctx); id != "" {
+		log.Printf("[request_id=%s] %s", id, fmt.Sprintf(format, args...))
+		return
+	}
+	log.Printf(format, args...)
+}
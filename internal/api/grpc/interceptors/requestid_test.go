@@ -0,0 +1,64 @@
+package interceptors
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	notesv1 "notes-service/pkg/proto/notes/v1"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestRequestIDUnaryInterceptor(t *testing.T) {
+	info := &grpc.UnaryServerInfo{FullMethod: "/notes.v1.NotesService/GetNote"}
+	call := func(ctx context.Context, handlerErr error) (string, error) {
+		var id string
+		_, err := RequestIDUnaryInterceptor(ctx, nil, info, func(ctx context.Context, _ any) (any, error) {
+			id = RequestIDFromContext(ctx)
+			return nil, handlerErr
+		})
+		return id, err
+	}
+
+	// Идентификатор клиента сохраняется, недопустимый заменяется новым
+	incoming := metadata.NewIncomingContext(context.Background(), metadata.Pairs(RequestIDHeader, "req-42"))
+	if id, _ := call(incoming, nil); id != "req-42" {
+		t.Errorf("Expected the client request id, got %q", id)
+	}
+	for _, value := range []string{"", strings.Repeat("a", maxRequestIDLength+1), "bad\nid"} {
+		ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(RequestIDHeader, value))
+		if id, _ := call(ctx, nil); id == "" || id == value {
+			t.Errorf("Expected a generated request id instead of %q, got %q", value, id)
+		}
+	}
+
+	// Идентификатор добавляется в существующие ErrorDetails, остальные детали сохраняются
+	st, _ := status.New(codes.NotFound, "note not found").WithDetails(
+		&errdetails.ResourceInfo{ResourceName: "note-1"},
+		&notesv1.ErrorDetails{Reason: "missing", InternalErrorCode: "NOTE_NOT_FOUND"},
+	)
+	_, err := call(incoming, st.Err())
+	details := status.Convert(err).Details()
+	if status.Code(err) != codes.NotFound || len(details) != 2 {
+		t.Fatalf("Expected NotFound with two details, got %v", err)
+	}
+	if got, ok := details[1].(*notesv1.ErrorDetails); !ok || got.GetRequestId() != "req-42" || got.GetInternalErrorCode() != "NOTE_NOT_FOUND" {
+		t.Errorf("Expected request id in ErrorDetails, got %v", details[1])
+	}
+
+	// Ошибка без статуса получает ErrorDetails только с идентификатором, ошибка контекста - свой код
+	_, err = call(incoming, errors.New("boom"))
+	details = status.Convert(err).Details()
+	if status.Code(err) != codes.Unknown || len(details) != 1 || details[0].(*notesv1.ErrorDetails).GetRequestId() != "req-42" {
+		t.Errorf("Expected Unknown with request id details, got %v %v", err, details)
+	}
+	if _, err := call(incoming, context.DeadlineExceeded); status.Code(err) != codes.DeadlineExceeded {
+		t.Errorf("Expected DeadlineExceeded for a context error, got %v", err)
+	}
+}
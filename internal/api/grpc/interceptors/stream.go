@@ -2,13 +2,12 @@ package interceptors
 
 import (
 	"io"
-	"log"
 
 	"google.golang.org/grpc"
 )
 
 // wrappedServerStream оборачивает grpc.ServerStream для переопределения методов
-// и логирования каждого сообщения в стриме
+// и логирования каждого сообщения в стриме (на уровне debug, ошибки - на уровне info)
 type wrappedServerStream struct {
 	grpc.ServerStream
 }
@@ -17,25 +16,25 @@ type wrappedServerStream struct {
 func (w *wrappedServerStream) RecvMsg(m interface{}) error {
 	err := w.ServerStream.RecvMsg(m)
 	if err != nil && err != io.EOF {
-		log.Printf("📥 Stream RecvMsg error: %v", err)
+		logf(w.Context(), "📥 Stream RecvMsg error: %v", err)
 		return err
 	}
 	if err == nil {
-		log.Printf("📥 Stream RecvMsg: received message of type %T", m)
+		debugf(w.Context(), "📥 Stream RecvMsg: received message of type %T", m)
 	} else {
-		log.Printf("📥 Stream RecvMsg: received EOF (stream closed)")
+		debugf(w.Context(), "📥 Stream RecvMsg: received EOF (stream closed)")
 	}
 	return err
 }
 
 // SendMsg переопределяет метод для логирования исходящих сообщений
 func (w *wrappedServerStream) SendMsg(m interface{}) error {
-	log.Printf("📤 Stream SendMsg: sending message of type %T", m)
+	debugf(w.Context(), "📤 Stream SendMsg: sending message of type %T", m)
 	err := w.ServerStream.SendMsg(m)
 	if err != nil {
-		log.Printf("📤 Stream SendMsg error: %v", err)
+		logf(w.Context(), "📤 Stream SendMsg error: %v", err)
 	} else {
-		log.Printf("📤 Stream SendMsg: message sent successfully")
+		debugf(w.Context(), "📤 Stream SendMsg: message sent successfully")
 	}
 	return err
 }
@@ -43,7 +42,7 @@ func (w *wrappedServerStream) SendMsg(m interface{}) error {
 // StreamInterceptor логирует каждое сообщение в стриме
 // Вызывается при установлении стримингового соединения
 func StreamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
-	log.Printf("🔌 Stream connection established: %s", info.FullMethod)
+	logf(ss.Context(), "🔌 Stream connection established: %s", info.FullMethod)
 
 	// Оборачиваем ServerStream для логирования каждого сообщения
 	wrapped := &wrappedServerStream{
@@ -53,9 +52,9 @@ func StreamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamS
 	// Вызываем обработчик с обернутым стримом
 	err := handler(srv, wrapped)
 	if err != nil {
-		log.Printf("❌ Stream handler error: %v (method: %s)", err, info.FullMethod)
+		logf(ss.Context(), "❌ Stream handler error: %v (method: %s)", err, info.FullMethod)
 	} else {
-		log.Printf("✅ Stream completed successfully: %s", info.FullMethod)
+		logf(ss.Context(), "✅ Stream completed successfully: %s", info.FullMethod)
 	}
 
 	return err
@@ -0,0 +1,76 @@
+package interceptors
+
+import (
+	"context"
+
+	"notes-service/internal/auth"
+	"notes-service/internal/telemetry"
+	"notes-service/internal/tenant"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// TenantInterceptor определяет настройки тенанта запроса и применяет его лимит запросов.
+// Должен выполняться после Auth: тенант - аутентифицированный пользователь из контекста.
+// Настройки передаются дальше через контекст (tenant.FromContext) для проверки квот и флагов.
+type TenantInterceptor struct {
+	resolver *tenant.Resolver
+	limiters *tenant.Limiters
+}
+
+// NewTenantInterceptor создает интерцептор настроек тенантов
+func NewTenantInterceptor(resolver *tenant.Resolver) *TenantInterceptor {
+	return &TenantInterceptor{
+		resolver: resolver,
+		limiters: tenant.NewLimiters(),
+	}
+}
+
+// Unary применяет настройки тенанта к unary запросу
+func (t *TenantInterceptor) Unary(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	ctx, err := t.apply(ctx, info.FullMethod)
+	if err != nil {
+		return nil, err
+	}
+
+	return handler(ctx, req)
+}
+
+// Stream применяет настройки тенанта при установлении стрима
+// Лимит запросов учитывает стрим целиком, а не отдельные сообщения
+func (t *TenantInterceptor) Stream(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, err := t.apply(ss.Context(), info.FullMethod)
+	if err != nil {
+		return err
+	}
+	if ctx == ss.Context() {
+		return handler(srv, ss)
+	}
+
+	return handler(srv, &contextServerStream{ServerStream: ss, ctx: ctx})
+}
+
+// apply определяет настройки тенанта и проверяет его лимит запросов
+// Запросы без аутентифицированного пользователя (gRPC reflection) пропускаются без изменений
+func (t *TenantInterceptor) apply(ctx context.Context, method string) (context.Context, error) {
+	principal, ok := auth.FromContext(ctx)
+	if !ok {
+		return ctx, nil
+	}
+
+	settings, err := t.resolver.Resolve(ctx, principal.UserID)
+	if err != nil {
+		logf(ctx, "Failed to resolve settings for tenant %s: %v", principal.UserID, err)
+		return nil, status.Errorf(codes.Unavailable, "failed to resolve tenant settings")
+	}
+
+	if !t.limiters.Allow(principal.UserID, settings) {
+		logf(ctx, "Rate limit exceeded for tenant %s (method: %s)", principal.UserID, method)
+		rateLimited(ctx, telemetry.LimiterTenant)
+		return nil, status.Errorf(codes.ResourceExhausted, "tenant rate limit exceeded")
+	}
+
+	return tenant.NewContext(ctx, settings), nil
+}
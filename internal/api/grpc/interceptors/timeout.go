@@ -0,0 +1,61 @@
+package interceptors
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	notesv1 "notes-service/pkg/proto/notes/v1"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// TimeoutUnaryInterceptor задает дедлайн запросам методов из timeouts (ключ - полное имя метода,
+// например "/notes.v1.NotesService/ListNotes"), если клиент не передал свой. Запрос, не уложившийся
+// в дедлайн (в том числе клиента или таймаут политики метода), завершается со статусом
+// DEADLINE_EXCEEDED и ErrorDetails (DEADLINE_EXCEEDED), даже если хэндлер вернул другую ошибку
+func TimeoutUnaryInterceptor(timeouts map[string]time.Duration) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		var timeout time.Duration
+		if _, ok := ctx.Deadline(); !ok && timeouts[info.FullMethod] > 0 {
+			timeout = timeouts[info.FullMethod]
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+		}
+
+		resp, err := handler(ctx, req)
+		if err != nil && deadlineExceeded(ctx, err) {
+			logf(ctx, "Request %s exceeded its deadline: %v", info.FullMethod, err)
+			return nil, deadlineError(timeout)
+		}
+		return resp, err
+	}
+}
+
+// deadlineExceeded сообщает, завершился ли запрос из-за дедлайна: истек контекст запроса
+// или ошибка хэндлера вызвана дедлайном контекста, производного от него (таймаут политики метода)
+func deadlineExceeded(ctx context.Context, err error) bool {
+	return errors.Is(ctx.Err(), context.DeadlineExceeded) ||
+		errors.Is(err, context.DeadlineExceeded) ||
+		status.Code(err) == codes.DeadlineExceeded
+}
+
+// deadlineError создает статус запроса, не уложившегося в дедлайн; timeout - дедлайн сервера
+// (0, если действовал дедлайн клиента или политики метода)
+func deadlineError(timeout time.Duration) error {
+	reason := "The request did not complete before its deadline; retry with a longer deadline or narrow the request"
+	if timeout > 0 {
+		reason = fmt.Sprintf("The request did not complete within the server default deadline of %s; "+
+			"pass a longer deadline or narrow the request", timeout)
+	}
+	st := status.New(codes.DeadlineExceeded, "deadline exceeded")
+	st, _ = st.WithDetails(&notesv1.ErrorDetails{
+		Reason:            reason,
+		InternalErrorCode: "DEADLINE_EXCEEDED",
+	})
+	return st.Err()
+}
@@ -0,0 +1,59 @@
+package interceptors
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	notesv1 "notes-service/pkg/proto/notes/v1"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestTimeoutUnaryInterceptor(t *testing.T) {
+	const method = "/notes.v1.NotesService/ListNotes"
+	interceptor := TimeoutUnaryInterceptor(map[string]time.Duration{method: 20 * time.Millisecond})
+	info := &grpc.UnaryServerInfo{FullMethod: method}
+
+	// Без дедлайна клиента действует дедлайн метода, ошибка хэндлера заменяется на DeadlineExceeded
+	started := time.Now()
+	_, err := interceptor(context.Background(), nil, info, func(ctx context.Context, _ any) (any, error) {
+		<-ctx.Done()
+		return nil, status.Error(codes.Internal, "internal error")
+	})
+	if status.Code(err) != codes.DeadlineExceeded || time.Since(started) < 20*time.Millisecond {
+		t.Fatalf("Expected DeadlineExceeded after the method deadline, got %v in %s", err, time.Since(started))
+	}
+	details, _ := status.Convert(err).Details()[0].(*notesv1.ErrorDetails)
+	if details.GetInternalErrorCode() != "DEADLINE_EXCEEDED" {
+		t.Errorf("Expected DEADLINE_EXCEEDED details, got %v", details)
+	}
+
+	// Дедлайн клиента не заменяется дедлайном метода
+	clientCtx, cancel := context.WithTimeout(context.Background(), time.Hour)
+	defer cancel()
+	clientDeadline, _ := clientCtx.Deadline()
+	_, err = interceptor(clientCtx, nil, info, func(ctx context.Context, _ any) (any, error) {
+		if deadline, _ := ctx.Deadline(); !deadline.Equal(clientDeadline) {
+			t.Errorf("Expected the client deadline %s, got %s", clientDeadline, deadline)
+		}
+		return "ok", nil
+	})
+	if err != nil {
+		t.Errorf("Expected no error, got: %v", err)
+	}
+
+	// Метод без дедлайна не ограничивается, остальные ошибки не меняются
+	_, err = interceptor(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: "/notes.v1.NotesService/GetNote"},
+		func(ctx context.Context, _ any) (any, error) {
+			if _, ok := ctx.Deadline(); ok {
+				t.Error("Expected no deadline for a method without a timeout")
+			}
+			return nil, status.Error(codes.NotFound, "note not found")
+		})
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("Expected NotFound unchanged, got: %v", err)
+	}
+}
@@ -0,0 +1,111 @@
+package interceptors
+
+import (
+	"context"
+	"strings"
+
+	"notes-service/internal/service/usage"
+	notesv1 "notes-service/pkg/proto/notes/v1"
+
+	"google.golang.org/grpc"
+)
+
+// UsageUnaryInterceptor учитывает вызовы методов и функции, которые использует запрос
+// Учитываются только имена методов и функций, данные запросов не сохраняются
+func UsageUnaryInterceptor(collector *usage.Collector) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		resp, err := handler(ctx, req)
+		collector.RecordCall(info.FullMethod, err != nil)
+		if err == nil {
+			recordFeatures(collector, req)
+		}
+		return resp, err
+	}
+}
+
+// UsageStreamInterceptor учитывает вызовы стриминговых методов и функции входящих сообщений
+func UsageStreamInterceptor(collector *usage.Collector) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		err := handler(srv, &usageServerStream{ServerStream: ss, collector: collector})
+		collector.RecordCall(info.FullMethod, err != nil)
+		return err
+	}
+}
+
+// usageServerStream учитывает функции каждого полученного сообщения стрима
+type usageServerStream struct {
+	grpc.ServerStream
+	collector *usage.Collector
+}
+
+func (s *usageServerStream) RecvMsg(m interface{}) error {
+	err := s.ServerStream.RecvMsg(m)
+	if err == nil {
+		recordFeatures(s.collector, m)
+	}
+	return err
+}
+
+// recordFeatures учитывает функции, которые использует сообщение
+func recordFeatures(collector *usage.Collector, msg interface{}) {
+	for _, feature := range usageFeatures(msg) {
+		collector.RecordFeature(feature)
+	}
+}
+
+// usageFeatures возвращает имена функций, которые использует сообщение (только по наличию полей)
+func usageFeatures(msg interface{}) []string {
+	var features []string
+	switch m := msg.(type) {
+	case *notesv1.CreateNoteRequest:
+		features = appendIf(features, m.GetIsE2E(), "notes.e2e")
+		features = appendIf(features, len(m.GetTags()) > 0, "notes.tags")
+		features = appendIf(features, m.GetRemindAt() != nil, "notes.reminders")
+		features = appendIf(features, m.GetIdempotencyKey() != "", "notes.idempotency_key")
+	case *notesv1.UpdateNoteRequest:
+		features = appendIf(features, m.GetUpdateMask() != nil, "update.field_mask")
+		features = appendIf(features, m.GetVersion() > 0, "update.version_check")
+		features = appendIf(features, m.GetForce(), "update.force")
+		features = appendIf(features, m.GetRemindAt() != nil, "notes.reminders")
+	case *notesv1.GetNoteRequest:
+		features = appendIf(features, m.GetReadMask() != nil, "notes.read_mask")
+	case *notesv1.ListNotesRequest:
+		features = appendIf(features, m.GetTitleCollation() != "", "list.collation")
+		features = appendIf(features, m.GetOrderBy() != notesv1.NoteOrder_NOTE_ORDER_UNSPECIFIED, "list.order_by_length")
+		features = appendIf(features, m.GetMinWordCount() > 0 || m.GetMaxWordCount() > 0, "list.filter_by_length")
+		features = appendIf(features, m.GetReadMask() != nil, "notes.read_mask")
+	case *notesv1.SubscribeToEventsRequest:
+		features = appendIf(features, len(m.GetEventTypes()) > 0, "events.filter")
+		features = appendIf(features, m.GetSince() != nil, "events.replay")
+	case *notesv1.ExportNotesRequest:
+		features = append(features, "export."+formatFeature(m.GetFormat()))
+	case *notesv1.ImportNotesRequest:
+		// Формат передается только в первом сообщении, остальные содержат данные
+		_, first := m.GetPayload().(*notesv1.ImportNotesRequest_Format)
+		features = appendIf(features, first, "import."+formatFeature(m.GetFormat()))
+	case *notesv1.StreamMetricsRequest:
+		features = appendIf(features, m.GetOptions().GetWindowSeconds() > 0, "metrics.windows")
+	case *notesv1.ChatMessage:
+		switch m.GetContent().(type) {
+		case *notesv1.ChatMessage_TextMessage:
+			features = append(features, "chat.text")
+		case *notesv1.ChatMessage_JoinRoom:
+			features = append(features, "chat.rooms")
+		case *notesv1.ChatMessage_TypingIndicator:
+			features = append(features, "chat.typing")
+		}
+	}
+	return features
+}
+
+func appendIf(features []string, ok bool, feature string) []string {
+	if ok {
+		return append(features, feature)
+	}
+	return features
+}
+
+// formatFeature возвращает имя формата выгрузки в нижнем регистре без префикса (jsonl, csv)
+func formatFeature(format notesv1.ExportFormat) string {
+	return strings.ToLower(strings.TrimPrefix(format.String(), "EXPORT_FORMAT_"))
+}
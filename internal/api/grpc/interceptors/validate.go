@@ -33,3 +33,29 @@ func ValidateUnaryInterceptor(ctx context.Context, req interface{}, info *grpc.U
 
 	return handler(ctx, req)
 }
+
+// validatingServerStream оборачивает grpc.ServerStream для валидации входящих сообщений
+type validatingServerStream struct {
+	grpc.ServerStream
+}
+
+// RecvMsg валидирует каждое полученное сообщение стрима
+func (w *validatingServerStream) RecvMsg(m interface{}) error {
+	if err := w.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+
+	if msg, ok := m.(proto.Message); ok {
+		if err := validator.Validate(msg); err != nil {
+			return status.Errorf(codes.InvalidArgument, "validation failed: %v", err)
+		}
+	}
+
+	return nil
+}
+
+// ValidateStreamInterceptor валидирует каждое входящее сообщение стрима по правилам из proto
+// Для server-side streaming валидируется единственный запрос клиента
+func ValidateStreamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	return handler(srv, &validatingServerStream{ServerStream: ss})
+}
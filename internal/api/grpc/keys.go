@@ -0,0 +1,59 @@
+package grpc
+
+import (
+	"context"
+
+	"notes-service/internal/converter"
+	"notes-service/internal/model"
+	"notes-service/internal/service/keys"
+	notesv1 "notes-service/pkg/proto/notes/v1"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// WithKeyRotation подключает смену ключей шифрования (RotateKeys, GetKeyRotationOperation)
+func WithKeyRotation(keyRotation *keys.Manager) HandlerOption {
+	return func(h *Handler) {
+		h.keyRotation = keyRotation
+	}
+}
+
+// RotateKeys запускает смену ключей шифрования заметок
+// Возвращает операцию в состоянии RUNNING, не дожидаясь перешифрования заметок
+func (h *Handler) RotateKeys(ctx context.Context, req *notesv1.RotateKeysRequest) (*notesv1.KeyRotationOperation, error) {
+	if h.keyRotation == nil {
+		return nil, status.Error(codes.Unimplemented, "encryption is not configured")
+	}
+
+	op, err := h.keyRotation.Start(ctx, req.GetOwnerId())
+	if err != nil {
+		return nil, h.statusError(err)
+	}
+
+	return h.keyRotationOperationToProto(op), nil
+}
+
+// GetKeyRotationOperation возвращает состояние и прогресс операции смены ключей
+func (h *Handler) GetKeyRotationOperation(ctx context.Context, req *notesv1.GetKeyRotationOperationRequest) (*notesv1.KeyRotationOperation, error) {
+	if h.keyRotation == nil {
+		return nil, status.Error(codes.Unimplemented, "encryption is not configured")
+	}
+
+	op, err := h.keyRotation.Get(ctx, req.GetId())
+	if err != nil {
+		return nil, h.statusError(err)
+	}
+
+	return h.keyRotationOperationToProto(op), nil
+}
+
+// keyRotationOperationToProto конвертирует операцию смены ключей, ошибка операции
+// конвертируется в google.rpc.Status так же, как ошибки запросов
+func (h *Handler) keyRotationOperationToProto(op model.KeyRotationOperation) *notesv1.KeyRotationOperation {
+	protoOp := converter.KeyRotationOperationToProto(op)
+	if op.Err != nil {
+		protoOp.Error = status.Convert(h.statusError(op.Err)).Proto()
+	}
+	return protoOp
+}
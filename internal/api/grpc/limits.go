@@ -0,0 +1,101 @@
+package grpc
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/keepalive"
+)
+
+// Limits ограничения gRPC сервера: размер сообщений, количество стримов соединения и keepalive
+// Нулевые значения означают значения gRPC по умолчанию (4 МБ входящего сообщения, без ограничения
+// исходящего и количества стримов, пинги раз в 2 часа, соединения без ограничения возраста)
+type Limits struct {
+	MaxRecvMsgSize       int    // Максимальный размер входящего сообщения в байтах
+	MaxSendMsgSize       int    // Максимальный размер исходящего сообщения в байтах
+	MaxConcurrentStreams uint32 // Одновременные стримы (и unary вызовы) одного соединения
+
+	// Keepalive - пинги сервера и ротация соединений
+	Keepalive keepalive.ServerParameters
+	// KeepaliveEnforcement - как часто клиенты могут отправлять пинги, чаще - соединение закрывается
+	KeepaliveEnforcement keepalive.EnforcementPolicy
+}
+
+// DefaultLimits возвращает ограничения сервера без WithLimits
+func DefaultLimits() Limits {
+	return Limits{
+		// Ограничиваем количество одновременных стримов для защиты сервера от перегрузки
+		MaxConcurrentStreams: 25,
+		// KeepAlive параметры для защиты от зависших соединений
+		Keepalive: keepalive.ServerParameters{
+			MaxConnectionIdle:     30 * time.Minute, // Закрытие неактивных соединений через 30 минут
+			MaxConnectionAge:      1 * time.Hour,    // Максимальное время жизни соединения (ротация)
+			MaxConnectionAgeGrace: 5 * time.Second,  // Ожидание завершения активных запросов перед закрытием
+			Time:                  10 * time.Minute, // Время между пингами (рекомендуется 5-10 минут для backend-to-backend)
+			Timeout:               20 * time.Second, // Время ожидания ответа на ping
+		},
+	}
+}
+
+// Validate проверяет ограничения и возвращает все найденные ошибки сразу:
+// отрицательные значения, размеры больше 2 ГБ или меньше части вложения и бессмысленные сочетания keepalive
+func (l Limits) Validate() error {
+	var errs []error
+	check := func(ok bool, format string, args ...any) {
+		if !ok {
+			errs = append(errs, fmt.Errorf(format, args...))
+		}
+	}
+
+	check(l.MaxRecvMsgSize >= 0 && l.MaxRecvMsgSize <= math.MaxInt32, "max receive message size must be between 0 and %d bytes, got %d", math.MaxInt32, l.MaxRecvMsgSize)
+	check(l.MaxSendMsgSize >= 0 && l.MaxSendMsgSize <= math.MaxInt32, "max send message size must be between 0 and %d bytes, got %d", math.MaxInt32, l.MaxSendMsgSize)
+	// Вложения загружаются и скачиваются частями по attachmentChunkSize байт
+	check(l.MaxRecvMsgSize == 0 || l.MaxRecvMsgSize > attachmentChunkSize,
+		"max receive message size (%d bytes) must exceed attachment chunk size (%d bytes)", l.MaxRecvMsgSize, attachmentChunkSize)
+	check(l.MaxSendMsgSize == 0 || l.MaxSendMsgSize > attachmentChunkSize,
+		"max send message size (%d bytes) must exceed attachment chunk size (%d bytes)", l.MaxSendMsgSize, attachmentChunkSize)
+
+	ka := l.Keepalive
+	for name, value := range map[string]time.Duration{
+		"keepalive time":           ka.Time,
+		"keepalive timeout":        ka.Timeout,
+		"max connection idle":      ka.MaxConnectionIdle,
+		"max connection age":       ka.MaxConnectionAge,
+		"max connection age grace": ka.MaxConnectionAgeGrace,
+		"keepalive min time":       l.KeepaliveEnforcement.MinTime,
+	} {
+		check(value >= 0, "%s must not be negative, got %v", name, value)
+	}
+	// Пинг, ожидающий ответа дольше интервала, перекрывается следующим
+	check(ka.Time <= 0 || ka.Timeout <= 0 || ka.Timeout < ka.Time,
+		"keepalive timeout (%v) must be shorter than keepalive time (%v)", ka.Timeout, ka.Time)
+	// Без ограничения возраста соединения ожидание перед его закрытием не используется
+	check(ka.MaxConnectionAgeGrace <= 0 || ka.MaxConnectionAge > 0,
+		"max connection age grace (%v) requires max connection age", ka.MaxConnectionAgeGrace)
+	// Соединение закрывается по возрасту раньше, чем успевает простоять idle
+	check(ka.MaxConnectionIdle <= 0 || ka.MaxConnectionAge <= 0 || ka.MaxConnectionIdle < ka.MaxConnectionAge,
+		"max connection idle (%v) must be shorter than max connection age (%v)", ka.MaxConnectionIdle, ka.MaxConnectionAge)
+
+	return errors.Join(errs...)
+}
+
+// serverOptions возвращает опции gRPC сервера для ограничений
+func (l Limits) serverOptions() []grpc.ServerOption {
+	opts := []grpc.ServerOption{
+		grpc.KeepaliveParams(l.Keepalive),
+		grpc.KeepaliveEnforcementPolicy(l.KeepaliveEnforcement),
+	}
+	if l.MaxConcurrentStreams > 0 {
+		opts = append(opts, grpc.MaxConcurrentStreams(l.MaxConcurrentStreams))
+	}
+	if l.MaxRecvMsgSize > 0 {
+		opts = append(opts, grpc.MaxRecvMsgSize(l.MaxRecvMsgSize))
+	}
+	if l.MaxSendMsgSize > 0 {
+		opts = append(opts, grpc.MaxSendMsgSize(l.MaxSendMsgSize))
+	}
+	return opts
+}
@@ -0,0 +1,37 @@
+package grpc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLimits_Validate(t *testing.T) {
+	require.NoError(t, DefaultLimits().Validate())
+	require.NoError(t, Limits{}.Validate(), "zero limits mean gRPC defaults")
+
+	limits := DefaultLimits()
+	limits.MaxRecvMsgSize = 1 << 10
+	limits.Keepalive.Timeout = limits.Keepalive.Time
+	limits.Keepalive.MaxConnectionAge = 0
+	limits.KeepaliveEnforcement.MinTime = -time.Second
+
+	err := limits.Validate()
+	require.Error(t, err)
+	// Все ошибки возвращаются сразу
+	assert.Contains(t, err.Error(), "max receive message size (1024 bytes) must exceed attachment chunk size")
+	assert.Contains(t, err.Error(), "keepalive timeout (10m0s) must be shorter than keepalive time (10m0s)")
+	assert.Contains(t, err.Error(), "max connection age grace (5s) requires max connection age")
+	assert.Contains(t, err.Error(), "keepalive min time must not be negative")
+}
+
+func TestLimits_ValidateIdleLongerThanAge(t *testing.T) {
+	limits := DefaultLimits()
+	limits.Keepalive.MaxConnectionIdle = 2 * time.Hour
+
+	err := limits.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "max connection idle (2h0m0s) must be shorter than max connection age (1h0m0s)")
+}
@@ -0,0 +1,188 @@
+package grpc
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"notes-service/internal/converter"
+	"notes-service/internal/model"
+	"notes-service/internal/service/metrics"
+	notesv1 "notes-service/pkg/proto/notes/v1"
+	"notes-service/pkg/streamutil"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// metricsFlushSize количество значений стрима, после которого они сохраняются в хранилище метрик
+const metricsFlushSize = 500
+
+// WithMetricStore подключает хранилище значений метрик (QueryMetrics)
+// Без него значения UploadMetrics и StreamMetrics не сохраняются
+func WithMetricStore(store *metrics.Store) HandlerOption {
+	return func(h *Handler) {
+		h.metricStore = store
+	}
+}
+
+// QueryMetrics возвращает значения метрики вызывающего пользователя за период или их агрегат
+func (h *Handler) QueryMetrics(ctx context.Context, req *notesv1.QueryMetricsRequest) (*notesv1.QueryMetricsResponse, error) {
+	if h.metricStore == nil {
+		return nil, status.Error(codes.Unimplemented, "metrics storage is disabled")
+	}
+
+	result, err := h.metricStore.Query(ctx, converter.MetricQueryFromProto(req))
+	if err != nil {
+		return nil, h.statusError(err)
+	}
+	return converter.MetricQueryResultToProto(result), nil
+}
+
+// metricBuffer накапливает значения стрима метрик и сохраняет их порциями по metricsFlushSize
+type metricBuffer struct {
+	store  *metrics.Store // nil - значения не сохраняются
+	points []model.MetricPoint
+}
+
+func (h *Handler) newMetricBuffer() *metricBuffer {
+	return &metricBuffer{store: h.metricStore}
+}
+
+// add добавляет значение; значению без времени назначается время получения
+func (b *metricBuffer) add(ctx context.Context, metric *notesv1.MetricRequest) error {
+	if b.store == nil {
+		return nil
+	}
+	point := converter.MetricPointFromProto(metric)
+	if point.Time.IsZero() {
+		point.Time = time.Now()
+	}
+	b.points = append(b.points, point)
+	if len(b.points) >= metricsFlushSize {
+		return b.flush(ctx)
+	}
+	return nil
+}
+
+// flush сохраняет накопленные значения
+func (b *metricBuffer) flush(ctx context.Context) error {
+	if len(b.points) == 0 {
+		return nil
+	}
+	err := b.store.Record(ctx, b.points)
+	b.points = b.points[:0]
+	return err
+}
+
+// StreamMetrics обрабатывает bidirectional streaming - загрузку метрик с промежуточной статистикой
+// С window_seconds статистика каждого окна (окна отсчитываются от сообщения с параметрами)
+// отправляется по его окончании, окна без метрик пропускаются. После закрытия отправки клиентом сервер отправляет
+// статистику незавершенного окна и итоговую статистику стрима (final) и завершает стрим
+func (h *Handler) StreamMetrics(stream notesv1.NotesService_StreamMetricsServer) error {
+	ctx := stream.Context()
+	total := metrics.NewAggregator()
+	window := metrics.NewAggregator()
+	buffer := h.newMetricBuffer()
+
+	// Recv блокируется, поэтому сообщения читаются в отдельной горутине,
+	// а отправка статистики окон выполняется только в этой
+	received := streamutil.RecvChan(ctx, stream)
+
+	started := time.Now()
+	windowStart := started
+	var ticks <-chan time.Time
+
+	// sendWindow отправляет статистику окна, если в нем были метрики, и начинает следующее окно
+	sendWindow := func(end time.Time) error {
+		defer func() {
+			window.Reset()
+			windowStart = end
+		}()
+		if window.Count() == 0 {
+			return nil
+		}
+		return stream.Send(&notesv1.StreamMetricsResponse{
+			Summary:     converter.MetricsSummaryToProto(window.Summary()),
+			WindowStart: timestamppb.New(windowStart),
+			WindowEnd:   timestamppb.New(end),
+		})
+	}
+
+	first := true
+	for {
+		var (
+			msg streamutil.Received[*notesv1.StreamMetricsRequest]
+			ok  bool
+		)
+		select {
+		case <-ctx.Done():
+			log.Printf("Client disconnected from metrics stream")
+			return ctx.Err()
+		case <-h.serverCtx.Done():
+			log.Printf("Server shutdown during metrics stream")
+			return h.serverCtx.Err()
+		case now := <-ticks:
+			if err := sendWindow(now); err != nil {
+				return err
+			}
+			continue
+		case msg, ok = <-received:
+		}
+		isFirst := first
+		first = false
+
+		// Канал закрыт: клиент завершил отправку (io.EOF)
+		if !ok {
+			if err := buffer.flush(ctx); err != nil {
+				return h.statusError(err)
+			}
+			end := time.Now()
+			if ticks != nil {
+				if err := sendWindow(end); err != nil {
+					return err
+				}
+			}
+			summary := total.Summary()
+			log.Printf("Metrics stream completed: count=%d, names=%d", summary.Count, len(summary.Metrics))
+			return stream.Send(&notesv1.StreamMetricsResponse{
+				Summary:     converter.MetricsSummaryToProto(summary),
+				WindowStart: timestamppb.New(started),
+				WindowEnd:   timestamppb.New(end),
+				Final:       true,
+			})
+		}
+		if msg.Err != nil {
+			log.Printf("Error receiving metric: %v", msg.Err)
+			return msg.Err
+		}
+
+		switch payload := msg.Msg.GetPayload().(type) {
+		case *notesv1.StreamMetricsRequest_Options:
+			if !isFirst {
+				return status.Error(codes.InvalidArgument, "options must be sent in the first message")
+			}
+			if seconds := payload.Options.GetWindowSeconds(); seconds > 0 {
+				var stopTicks func()
+				ticks, stopTicks = streamutil.Heartbeat(time.Duration(seconds) * time.Second)
+				defer stopTicks()
+				windowStart = time.Now()
+				log.Printf("Metrics stream window: %ds", seconds)
+			}
+		case *notesv1.StreamMetricsRequest_Metric:
+			metric := payload.Metric
+			if err := total.Add(metric.GetName(), metric.GetValue()); err != nil {
+				return h.statusError(err)
+			}
+			if err := window.Add(metric.GetName(), metric.GetValue()); err != nil {
+				return h.statusError(err)
+			}
+			if err := buffer.add(ctx, metric); err != nil {
+				return h.statusError(err)
+			}
+		default:
+			return status.Error(codes.InvalidArgument, "message must contain options or metric")
+		}
+	}
+}
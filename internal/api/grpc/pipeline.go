@@ -0,0 +1,107 @@
+package grpc
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"notes-service/internal/api/grpc/interceptors"
+	"notes-service/internal/mirror"
+	"notes-service/internal/pipeline"
+
+	"google.golang.org/grpc"
+)
+
+// unaryChain собирает unary интерцепторы вместе с описанием цепочки для AdminService.GetPipeline
+type unaryChain struct {
+	interceptors []grpc.UnaryServerInterceptor
+	stages       []pipeline.Stage
+}
+
+// add добавляет интерцептор name в конец цепочки
+func (c *unaryChain) add(name string, settings map[string]string, interceptor grpc.UnaryServerInterceptor) {
+	c.interceptors = append(c.interceptors, interceptor)
+	c.stages = append(c.stages, pipeline.Stage{Name: name, Settings: settings})
+}
+
+// streamChain собирает стриминговые интерцепторы вместе с описанием цепочки
+type streamChain struct {
+	interceptors []grpc.StreamServerInterceptor
+	stages       []pipeline.Stage
+}
+
+// add добавляет интерцептор name в конец цепочки
+func (c *streamChain) add(name string, settings map[string]string, interceptor grpc.StreamServerInterceptor) {
+	c.interceptors = append(c.interceptors, interceptor)
+	c.stages = append(c.stages, pipeline.Stage{Name: name, Settings: settings})
+}
+
+// policySettings описывает политики методов из proto: роли и таймауты по методам
+func policySettings(policies interceptors.MethodPolicies) map[string]string {
+	settings := make(map[string]string)
+	for method, policy := range policies {
+		var values []string
+		if len(policy.Roles) > 0 {
+			values = append(values, "roles="+strings.Join(policy.Roles, "|"))
+		}
+		if policy.Timeout > 0 {
+			values = append(values, "timeout="+policy.Timeout.String())
+		}
+		if policy.Deprecated {
+			values = append(values, "deprecated")
+		}
+		if len(values) > 0 {
+			settings[method] = strings.Join(values, ",")
+		}
+	}
+	return settings
+}
+
+// streamRateLimitSettings описывает лимиты входящих сообщений стримов по методам
+func streamRateLimitSettings(limits map[string]interceptors.StreamRateLimit) map[string]string {
+	settings := make(map[string]string, len(limits))
+	for method, limit := range limits {
+		if limit.MessagesPerSecond <= 0 {
+			settings[method] = "unlimited"
+			continue
+		}
+		settings[method] = fmt.Sprintf("%g/s burst %d", limit.MessagesPerSecond, limit.Burst)
+	}
+	return settings
+}
+
+// streamLifetimeSettings описывает лимиты времени жизни стримов по методам
+func streamLifetimeSettings(limits map[string]interceptors.StreamLifetime) map[string]string {
+	settings := make(map[string]string, len(limits))
+	for method, limit := range limits {
+		var values []string
+		if limit.MaxDuration > 0 {
+			values = append(values, "max_duration="+limit.MaxDuration.String())
+		}
+		if limit.IdleTimeout > 0 {
+			values = append(values, "idle_timeout="+limit.IdleTimeout.String())
+		}
+		if len(values) > 0 {
+			settings[method] = strings.Join(values, ",")
+		}
+	}
+	return settings
+}
+
+// methodTimeoutSettings описывает дедлайны unary запросов по методам
+func methodTimeoutSettings(timeouts map[string]time.Duration) map[string]string {
+	settings := make(map[string]string, len(timeouts))
+	for method, timeout := range timeouts {
+		settings[method] = timeout.String()
+	}
+	return settings
+}
+
+// mirrorSettings описывает дублируемые методы и долю дублируемых запросов
+func mirrorSettings(m *mirror.Mirror) map[string]string {
+	settings := make(map[string]string)
+	for _, method := range m.Methods() {
+		settings[method] = fmt.Sprintf("%g%%", m.Percent())
+	}
+	return settings
+}
@@ -0,0 +1,56 @@
+package grpc
+
+import (
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+
+	notesv1 "notes-service/pkg/proto/notes/v1"
+)
+
+// computedNoteFields вычисляемые поля заметки: без read_mask они не возвращаются
+var computedNoteFields = map[protoreflect.Name]bool{
+	"word_count":   true,
+	"reading_time": true,
+}
+
+// noteFields поля заметки, возвращаемые клиенту по read_mask запроса
+type noteFields map[protoreflect.Name]bool
+
+// newNoteFields проверяет read_mask и возвращает набор полей
+// Без маски возвращаются все поля, кроме вычисляемых
+func newNoteFields(mask *fieldmaskpb.FieldMask) (noteFields, error) {
+	descriptor := (&notesv1.Note{}).ProtoReflect().Descriptor().Fields()
+	fields := make(noteFields)
+	if mask == nil {
+		for i := 0; i < descriptor.Len(); i++ {
+			if name := descriptor.Get(i).Name(); !computedNoteFields[name] {
+				fields[name] = true
+			}
+		}
+		return fields, nil
+	}
+
+	for _, path := range mask.GetPaths() {
+		field := descriptor.ByName(protoreflect.Name(path))
+		if field == nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid read_mask path %q", path)
+		}
+		fields[field.Name()] = true
+	}
+	return fields, nil
+}
+
+// apply очищает в заметках поля, не входящие в набор
+func (f noteFields) apply(notes ...*notesv1.Note) {
+	for _, note := range notes {
+		msg := note.ProtoReflect()
+		descriptor := msg.Descriptor().Fields()
+		for i := 0; i < descriptor.Len(); i++ {
+			if field := descriptor.Get(i); !f[field.Name()] {
+				msg.Clear(field)
+			}
+		}
+	}
+}
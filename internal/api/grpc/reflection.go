@@ -0,0 +1,81 @@
+package grpc
+
+import (
+	"log"
+	"slices"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/reflection"
+	reflectionv1 "google.golang.org/grpc/reflection/grpc_reflection_v1"
+	reflectionv1alpha "google.golang.org/grpc/reflection/grpc_reflection_v1alpha"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+// registerReflection регистрирует gRPC reflection (v1 и v1alpha для старых клиентов)
+// Пустой services - все сервисы сервера, иначе только перечисленные
+func registerReflection(server *grpc.Server, services []string) {
+	if len(services) == 0 {
+		reflection.Register(server)
+		log.Println("Enabled gRPC reflection")
+		return
+	}
+
+	filtered := reflectionFilter{server: server, services: services}
+	opts := reflection.ServerOptions{
+		Services:           filtered,
+		DescriptorResolver: filtered,
+		ExtensionResolver:  protoregistry.GlobalTypes,
+	}
+	reflectionv1.RegisterServerReflectionServer(server, reflection.NewServerV1(opts))
+	reflectionv1alpha.RegisterServerReflectionServer(server, reflection.NewServer(opts))
+	log.Printf("Enabled gRPC reflection for %s", strings.Join(services, ", "))
+}
+
+// reflectionFilter показывает через reflection только сервисы services (и сам reflection): остальные
+// не попадают в список сервисов и не находятся по имени сервиса или метода
+// Файлы описаний выдаются целиком, поэтому сервисы из одного proto файла с разрешенным видны в нем
+type reflectionFilter struct {
+	server   *grpc.Server
+	services []string
+}
+
+// GetServiceInfo возвращает разрешенные сервисы сервера
+func (f reflectionFilter) GetServiceInfo() map[string]grpc.ServiceInfo {
+	info := f.server.GetServiceInfo()
+	for name := range info {
+		if !f.allowed(name) {
+			delete(info, name)
+		}
+	}
+	return info
+}
+
+// FindFileByPath ищет файл описаний в protoregistry.GlobalFiles
+func (f reflectionFilter) FindFileByPath(path string) (protoreflect.FileDescriptor, error) {
+	return protoregistry.GlobalFiles.FindFileByPath(path)
+}
+
+// FindDescriptorByName ищет описание по имени; сервисы вне списка и их методы не находятся
+func (f reflectionFilter) FindDescriptorByName(name protoreflect.FullName) (protoreflect.Descriptor, error) {
+	desc, err := protoregistry.GlobalFiles.FindDescriptorByName(name)
+	if err != nil {
+		return nil, err
+	}
+	service, ok := desc.(protoreflect.ServiceDescriptor)
+	if method, isMethod := desc.(protoreflect.MethodDescriptor); isMethod {
+		service, ok = method.Parent().(protoreflect.ServiceDescriptor)
+	}
+	if ok && !f.allowed(string(service.FullName())) {
+		return nil, protoregistry.NotFound
+	}
+	return desc, nil
+}
+
+func (f reflectionFilter) allowed(service string) bool {
+	return strings.HasPrefix(service, "grpc.reflection.") || slices.Contains(f.services, service)
+}
+
+var _ protodesc.Resolver = reflectionFilter{}
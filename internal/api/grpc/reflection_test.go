@@ -0,0 +1,35 @@
+package grpc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/reflect/protoregistry"
+
+	notesv1 "notes-service/pkg/proto/notes/v1"
+)
+
+func TestReflectionFilter(t *testing.T) {
+	server := grpc.NewServer()
+	notesv1.RegisterNotesServiceServer(server, notesv1.UnimplementedNotesServiceServer{})
+	notesv1.RegisterAdminServiceServer(server, notesv1.UnimplementedAdminServiceServer{})
+	registerReflection(server, []string{"notes.v1.NotesService"})
+
+	filter := reflectionFilter{server: server, services: []string{"notes.v1.NotesService"}}
+	info := filter.GetServiceInfo()
+	assert.Contains(t, info, "notes.v1.NotesService")
+	assert.Contains(t, info, "grpc.reflection.v1.ServerReflection")
+	assert.NotContains(t, info, "notes.v1.AdminService")
+
+	_, err := filter.FindDescriptorByName("notes.v1.NotesService.GetNote")
+	require.NoError(t, err)
+	_, err = filter.FindDescriptorByName("notes.v1.AdminService")
+	assert.ErrorIs(t, err, protoregistry.NotFound)
+	_, err = filter.FindDescriptorByName("notes.v1.AdminService.GetPipeline")
+	assert.ErrorIs(t, err, protoregistry.NotFound)
+	// Сообщения доступны: они нужны для описания методов разрешенных сервисов
+	_, err = filter.FindDescriptorByName("notes.v1.Note")
+	require.NoError(t, err)
+}
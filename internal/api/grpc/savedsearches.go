@@ -0,0 +1,101 @@
+package grpc
+
+import (
+	"context"
+
+	"notes-service/internal/collation"
+	"notes-service/internal/converter"
+	svc "notes-service/internal/service"
+	"notes-service/internal/service/searches"
+	notesv1 "notes-service/pkg/proto/notes/v1"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// WithSavedSearchService подключает сохраненные поиски (SaveSearch, ListSavedSearches,
+// DeleteSavedSearch, ExecuteSavedSearch)
+func WithSavedSearchService(searchService *searches.Service) HandlerOption {
+	return func(h *Handler) {
+		h.searchService = searchService
+	}
+}
+
+// errSavedSearchesDisabled ответ RPC сохраненных поисков, если сервис поисков не подключен
+var errSavedSearchesDisabled = status.Error(codes.Unimplemented, "saved searches are not configured")
+
+// SaveSearch сохраняет поиск вызывающего пользователя
+func (h *Handler) SaveSearch(ctx context.Context, req *notesv1.SaveSearchRequest) (*notesv1.SavedSearch, error) {
+	if h.searchService == nil {
+		return nil, errSavedSearchesDisabled
+	}
+
+	search, err := h.searchService.Save(ctx, req.GetName(), req.GetQuery())
+	if err != nil {
+		return nil, h.statusError(err)
+	}
+
+	return converter.SavedSearchToProto(search), nil
+}
+
+// ListSavedSearches возвращает сохраненные поиски вызывающего пользователя
+func (h *Handler) ListSavedSearches(ctx context.Context, _ *notesv1.ListSavedSearchesRequest) (*notesv1.ListSavedSearchesResponse, error) {
+	if h.searchService == nil {
+		return nil, errSavedSearchesDisabled
+	}
+
+	list, err := h.searchService.List(ctx)
+	if err != nil {
+		return nil, h.statusError(err)
+	}
+
+	return &notesv1.ListSavedSearchesResponse{SavedSearches: converter.SavedSearchesToProto(list)}, nil
+}
+
+// DeleteSavedSearch удаляет сохраненный поиск вызывающего пользователя
+func (h *Handler) DeleteSavedSearch(ctx context.Context, req *notesv1.DeleteSavedSearchRequest) (*notesv1.DeleteSavedSearchResponse, error) {
+	if h.searchService == nil {
+		return nil, errSavedSearchesDisabled
+	}
+
+	if err := h.searchService.Delete(ctx, req.GetId()); err != nil {
+		return nil, h.statusError(err)
+	}
+
+	return &notesv1.DeleteSavedSearchResponse{}, nil
+}
+
+// ExecuteSavedSearch возвращает заметки вызывающего пользователя, подходящие под сохраненный поиск
+func (h *Handler) ExecuteSavedSearch(ctx context.Context, req *notesv1.ExecuteSavedSearchRequest) (*notesv1.ExecuteSavedSearchResponse, error) {
+	if h.searchService == nil {
+		return nil, errSavedSearchesDisabled
+	}
+	ctx, warnings := svc.WithWarnings(ctx)
+
+	fields, err := newNoteFields(req.GetReadMask())
+	if err != nil {
+		return nil, err
+	}
+
+	// Порядок заметок как в ListNotes
+	titleCollation := req.GetTitleCollation()
+	if titleCollation == "" {
+		titleCollation = collation.PreferredLocale(acceptLanguage(ctx))
+	}
+	search, notes, err := h.searchService.Execute(ctx, req.GetId(), svc.ListOptions{
+		TitleCollation: titleCollation,
+		Order:          svc.ListOrder(req.GetOrderBy()),
+	})
+	if err != nil {
+		return nil, h.statusError(err)
+	}
+
+	protoNotes := converter.ModelsToProtos(notes)
+	fields.apply(protoNotes...)
+
+	return &notesv1.ExecuteSavedSearchResponse{
+		SavedSearch: converter.SavedSearchToProto(search),
+		Notes:       protoNotes,
+		Warnings:    converter.WarningsToProto(warnings.List()),
+	}, nil
+}
@@ -1,61 +1,441 @@
 package grpc
 
 import (
+	"crypto/tls"
+	"fmt"
 	"log"
+	"maps"
+	"strings"
 	"time"
 
 	"notes-service/internal/api/grpc/interceptors"
+	"notes-service/internal/auth"
+	"notes-service/internal/compression"
+	"notes-service/internal/mirror"
+	"notes-service/internal/pipeline"
+	"notes-service/internal/recorder"
+	"notes-service/internal/repository"
+	"notes-service/internal/repository/memory"
+	"notes-service/internal/service/apikeys"
+	"notes-service/internal/service/audit"
+	"notes-service/internal/service/usage"
+	"notes-service/internal/service/users"
+	"notes-service/internal/telemetry"
+	"notes-service/internal/tenant"
 	notesv1 "notes-service/pkg/proto/notes/v1"
 
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/otel/trace"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/keepalive"
-	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/credentials"
 )
 
+// ServerOption настраивает дополнительные возможности gRPC сервера
+type ServerOption func(*serverOptions)
+
+type serverOptions struct {
+	tls                *tls.Config
+	authenticator      auth.Authenticator
+	sessions           *auth.Sessions
+	streamTickets      *auth.StreamTickets
+	authz              *interceptors.AuthzInterceptor
+	publicMethods      []string
+	pipelines          *pipeline.Registry
+	users              *users.Service
+	apiKeys            *apikeys.Service
+	recorder           *recorder.Recorder
+	payloadLogger      *interceptors.PayloadLogger
+	usage              *usage.Collector
+	audit              *audit.Service
+	consistency        repository.ConsistencyTracker
+	streamRateLimits   map[string]interceptors.StreamRateLimit
+	streamLifetimes    map[string]interceptors.StreamLifetime
+	methodTimeouts     map[string]time.Duration
+	rateLimiter        *interceptors.RateLimiter
+	mirror             *mirror.Mirror
+	metrics            *telemetry.Registry
+	tracing            trace.TracerProvider
+	compression        []string
+	limits             Limits
+	reflection         bool
+	reflectionServices []string
+	unaryInterceptors  []grpc.UnaryServerInterceptor
+	streamInterceptors []grpc.StreamServerInterceptor
+}
+
+// WithTLS включает TLS для подключений к серверу (без опции сервер принимает plaintext соединения)
+// С ClientCAs и ClientAuth в config сервер требует сертификат клиента (mTLS, см. tlsconfig.Config.Server)
+func WithTLS(config *tls.Config) ServerOption {
+	return func(o *serverOptions) {
+		o.tls = config
+	}
+}
+
+// WithAuthenticator задает проверку токенов авторизации (по умолчанию auth.DemoTokens)
+func WithAuthenticator(authenticator auth.Authenticator) ServerOption {
+	return func(o *serverOptions) {
+		o.authenticator = authenticator
+	}
+}
+
+// WithSessions включает вход по паролю через AuthService (без опции методы AuthService
+// отвечают FailedPrecondition). Токены сессий проверяет authenticator из WithAuthenticator,
+// поэтому sessions должен входить в его цепочку
+func WithSessions(sessions *auth.Sessions) ServerOption {
+	return func(o *serverOptions) {
+		o.sessions = sessions
+	}
+}
+
+// WithStreamTickets включает билеты стримов: AuthService.IssueStreamTicket выдает их, а стримы
+// открываются по билету из metadata x-stream-ticket (без опции IssueStreamTicket отвечает FailedPrecondition)
+func WithStreamTickets(tickets *auth.StreamTickets) ServerOption {
+	return func(o *serverOptions) {
+		o.streamTickets = tickets
+	}
+}
+
+// WithAuthz включает проверку ролей методов по конфигурации (без опции роли проверяются
+// только политиками из proto)
+func WithAuthz(authz *interceptors.AuthzInterceptor) ServerOption {
+	return func(o *serverOptions) {
+		o.authz = authz
+	}
+}
+
+// WithPublicMethods делает методы доступными без токена в дополнение к методам с requires_auth: false
+// в proto (например, будущий health check). Метод задается полным именем ("/grpc.health.v1.Health/Check"),
+// все методы сервиса - "/<сервис>/*"
+func WithPublicMethods(methods []string) ServerOption {
+	return func(o *serverOptions) {
+		o.publicMethods = append(o.publicMethods, methods...)
+	}
+}
+
+// WithPipelineRegistry задает реестр, в котором сервер регистрирует свои цепочки интерцепторов
+// (по умолчанию собственный реестр сервера); общий реестр с Gateway показывает в
+// AdminService.GetPipeline и его middleware
+func WithPipelineRegistry(registry *pipeline.Registry) ServerOption {
+	return func(o *serverOptions) {
+		o.pipelines = registry
+	}
+}
+
+// WithUserService задает сервис пользователей для UserService
+// (по умолчанию пустое хранилище в памяти)
+func WithUserService(users *users.Service) ServerOption {
+	return func(o *serverOptions) {
+		o.users = users
+	}
+}
+
+// WithAPIKeys включает управление ключами API через AdminService и лимиты запросов ключей
+// (без опции методы ключей отвечают FailedPrecondition). Ключи проверяет authenticator
+// из WithAuthenticator, поэтому apiKeys должен входить в его цепочку
+func WithAPIKeys(apiKeys *apikeys.Service) ServerOption {
+	return func(o *serverOptions) {
+		o.apiKeys = apiKeys
+	}
+}
+
+// WithRecorder включает запись unary запросов для воспроизведения через cmd/replay
+func WithRecorder(rec *recorder.Recorder) ServerOption {
+	return func(o *serverOptions) {
+		o.recorder = rec
+	}
+}
+
+// WithPayloadLogging включает логирование тел части unary запросов и ответов логгером запросов
+// (без опции логируются только метод, статус и время выполнения)
+func WithPayloadLogging(logger *interceptors.PayloadLogger) ServerOption {
+	return func(o *serverOptions) {
+		o.payloadLogger = logger
+	}
+}
+
+// WithUsageStats включает учет вызовов методов и используемых функций в collector
+// (без опции статистика не собирается)
+func WithUsageStats(collector *usage.Collector) ServerOption {
+	return func(o *serverOptions) {
+		o.usage = collector
+	}
+}
+
+// WithAudit включает запись изменений заметок и доступа к ним в журнал аудита auditLog и
+// AdminService.ListAuditEvents (без опции журнал не ведется, ListAuditEvents отвечает FailedPrecondition)
+func WithAudit(auditLog *audit.Service) ServerOption {
+	return func(o *serverOptions) {
+		o.audit = auditLog
+	}
+}
+
+// WithConsistencyTracker включает токены согласованности x-consistency-token для чтения своих записей
+// между репликами (без опции токены не выдаются, а переданные клиентом не проверяются)
+func WithConsistencyTracker(tracker repository.ConsistencyTracker) ServerOption {
+	return func(o *serverOptions) {
+		o.consistency = tracker
+	}
+}
+
+// WithStreamRateLimits ограничивает скорость входящих сообщений стримов по методам
+// (ключ - полное имя метода). Лимиты перекрывают rate_limit из политики метода в proto,
+// лимит с нулевой скоростью снимает ограничение. Chat отвечает на превышение бизнес-ошибкой
+// RATE_LIMIT, остальные методы завершают стрим со статусом ResourceExhausted
+func WithStreamRateLimits(limits map[string]interceptors.StreamRateLimit) ServerOption {
+	return func(o *serverOptions) {
+		o.streamRateLimits = limits
+	}
+}
+
+// WithStreamLifetimes ограничивает длительность стримов и время без сообщений клиента по методам
+// (ключ - полное имя метода). Стрим, превысивший лимит, завершается со статусом UNAVAILABLE
+func WithStreamLifetimes(limits map[string]interceptors.StreamLifetime) ServerOption {
+	return func(o *serverOptions) {
+		o.streamLifetimes = limits
+	}
+}
+
+// WithMethodTimeouts задает дедлайн unary запросов по методам (ключ - полное имя метода),
+// если клиент не передал свой
+func WithMethodTimeouts(timeouts map[string]time.Duration) ServerOption {
+	return func(o *serverOptions) {
+		o.methodTimeouts = timeouts
+	}
+}
+
+// WithRateLimiter ограничивает скорость запросов каждого клиента (пользователя или адреса)
+func WithRateLimiter(limiter *interceptors.RateLimiter) ServerOption {
+	return func(o *serverOptions) {
+		o.rateLimiter = limiter
+	}
+}
+
+// WithMirror включает дублирование части запросов чтения во второй сервис для сравнения ответов
+func WithMirror(m *mirror.Mirror) ServerOption {
+	return func(o *serverOptions) {
+		o.mirror = m
+	}
+}
+
+// WithMetrics включает учет запросов, времени выполнения, открытых стримов и отклонений лимитами
+// в registry для /metrics (без опции метрики gRPC сервера не собираются)
+func WithMetrics(registry *telemetry.Registry) ServerOption {
+	return func(o *serverOptions) {
+		o.metrics = registry
+	}
+}
+
+// WithTracing записывает запросы в трассы OpenTelemetry провайдера provider; контекст трассы
+// клиента (заголовок traceparent) продолжается (без опции запросы не трассируются)
+func WithTracing(provider trace.TracerProvider) ServerOption {
+	return func(o *serverOptions) {
+		o.tracing = provider
+	}
+}
+
+// WithCompression регистрирует компрессоры names (compression.Gzip, compression.Zstd): сервер принимает
+// сжатые ими запросы и сжимает ответы алгоритмом запроса (без опции сжатые запросы отклоняются с Unimplemented)
+func WithCompression(names []string) ServerOption {
+	return func(o *serverOptions) {
+		o.compression = names
+	}
+}
+
+// WithLimits задает размер сообщений, количество одновременных стримов соединения и keepalive
+// (без опции - DefaultLimits); limits проверяются Limits.Validate при запуске
+func WithLimits(limits Limits) ServerOption {
+	return func(o *serverOptions) {
+		o.limits = limits
+	}
+}
+
+// WithReflection включает gRPC reflection (grpcurl, grpcui) для сервисов services (пусто - для всех)
+// Без опции reflection выключен
+func WithReflection(services []string) ServerOption {
+	return func(o *serverOptions) {
+		o.reflection = true
+		o.reflectionServices = services
+	}
+}
+
+// WithInterceptors добавляет интерцепторы после встроенных: запросы в них уже
+// провалидированы и авторизованы, пользователь доступен через auth.FromContext
+func WithInterceptors(unary []grpc.UnaryServerInterceptor, stream []grpc.StreamServerInterceptor) ServerOption {
+	return func(o *serverOptions) {
+		o.unaryInterceptors = append(o.unaryInterceptors, unary...)
+		o.streamInterceptors = append(o.streamInterceptors, stream...)
+	}
+}
+
 // NewServer создает и настраивает gRPC сервер с интерцепторами и конфигурацией
-func NewServer(handler notesv1.NotesServiceServer) *grpc.Server {
+// tenantResolver определяет настройки тенантов (лимиты, квоты, флаги функциональности)
+func NewServer(handler notesv1.NotesServiceServer, tenantResolver *tenant.Resolver, opts ...ServerOption) *grpc.Server {
+	options := serverOptions{limits: DefaultLimits()}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	if options.authenticator == nil {
+		options.authenticator = auth.DemoTokens()
+	}
+	if options.users == nil {
+		options.users = users.NewService(memory.NewUserRepository())
+	}
+	// Политики методов (доступ без токена, роли, лимиты, таймауты) объявлены в proto опцией (notes.v1.policy)
+	// Вход и обновление токенов доступны без токена, Logout - и по одному refresh токену
+	policies := interceptors.LoadMethodPolicies(notesv1.File_proto_notes_v1_notes_proto)
+	authInterceptor := interceptors.NewAuthInterceptor(options.authenticator,
+		interceptors.WithPublicMethods(policies.PublicMethods()),
+		interceptors.WithPublicMethods(options.publicMethods),
+		interceptors.WithStreamTickets(options.streamTickets),
+	)
+	// Лимиты стримов из конфигурации перекрывают лимиты из proto
+	streamRateLimits := policies.StreamRateLimits()
+	maps.Copy(streamRateLimits, options.streamRateLimits)
+	tenantInterceptor := interceptors.NewTenantInterceptor(tenantResolver)
+
+	var unary unaryChain
+	// Превращает панику в Internal, не останавливая процесс; стоит первым, чтобы перехватывать панику во всех интерцепторах
+	unary.add("recovery", nil, interceptors.RecoveryUnaryInterceptor)
+	// Назначает запросу идентификатор x-request-id для логов, заголовка ответа и ErrorDetails
+	unary.add("request_id", nil, interceptors.RequestIDUnaryInterceptor)
+	if options.metrics != nil {
+		// Учитывает запросы по методам и кодам статуса, время выполнения и отклонения лимитами
+		unary.add("metrics", nil, interceptors.MetricsUnaryInterceptor(options.metrics))
+	}
+	if options.payloadLogger != nil {
+		// Логирует все запросы и время выполнения, а часть запросов - вместе с телами
+		unary.add("logger", options.payloadLogger.Settings(), options.payloadLogger.Unary)
+	} else {
+		unary.add("logger", nil, interceptors.LoggerUnaryInterceptor) // Логирует все запросы и время выполнения
+	}
+	if options.usage != nil {
+		// Учитываются и отклоненные запросы: они попадают в счетчик ошибок метода
+		unary.add("usage", nil, interceptors.UsageUnaryInterceptor(options.usage))
+	}
+	// Задает дедлайн запросам без дедлайна клиента и возвращает DeadlineExceeded с ErrorDetails
+	unary.add("timeout", methodTimeoutSettings(options.methodTimeouts), interceptors.TimeoutUnaryInterceptor(options.methodTimeouts))
+	unary.add("validate", nil, interceptors.ValidateUnaryInterceptor)    // Валидирует запросы по правилам из proto
+	unary.add("auth", authInterceptor.Settings(), authInterceptor.Unary) // Проверяет авторизацию токена
+	if options.rateLimiter != nil {
+		// Ограничивает скорость запросов пользователя или адреса клиента
+		unary.add("rate_limit", options.rateLimiter.Settings(), interceptors.RateLimitUnaryInterceptor(options.rateLimiter))
+	}
+	if options.audit != nil {
+		// Записывает изменения заметок и доступа к ним, в том числе отклоненные политикой и ролями
+		unary.add("audit", nil, interceptors.AuditUnaryInterceptor(options.audit))
+	}
+	// Проверяет роли пользователя и ограничивает время запроса по политике метода
+	unary.add("policy", policySettings(policies), interceptors.PolicyUnaryInterceptor(policies))
+	if options.authz != nil {
+		// Проверяет роли пользователя по карте методов из конфигурации
+		unary.add("authz", options.authz.Settings(), options.authz.Unary)
+	}
+	if options.apiKeys != nil {
+		// Ограничивает скорость запросов с ключом API лимитом ключа
+		unary.add("api_key_rate_limit", nil, interceptors.APIKeyRateLimitUnaryInterceptor(options.apiKeys))
+	}
+	if options.recorder != nil {
+		// Записываются только авторизованные запросы, вместе с пользователем
+		unary.add("recorder", nil, interceptors.RecorderUnaryInterceptor(options.recorder))
+	}
+	unary.add("tenant", nil, tenantInterceptor.Unary) // Применяет настройки тенанта
+	if options.consistency != nil {
+		// Ждет токен согласованности запроса и возвращает новый токен после изменения заметок
+		unary.add("consistency", nil, interceptors.ConsistencyUnaryInterceptor(options.consistency))
+	}
+	if options.mirror != nil {
+		// Дублирует долю запросов чтения во второй сервис после ответа основного
+		unary.add("mirror", mirrorSettings(options.mirror), interceptors.MirrorUnaryInterceptor(options.mirror))
+	}
+	for i, interceptor := range options.unaryInterceptors {
+		unary.add(fmt.Sprintf("custom_%d", i+1), nil, interceptor)
+	}
+
+	var stream streamChain
+	stream.add("recovery", nil, interceptors.RecoveryStreamInterceptor)
+	stream.add("request_id", nil, interceptors.RequestIDStreamInterceptor)
+	if options.metrics != nil {
+		stream.add("metrics", nil, interceptors.MetricsStreamInterceptor(options.metrics))
+	}
+	stream.add("logger", nil, interceptors.StreamInterceptor) // Логирует каждое сообщение в стримах (RecvMsg/SendMsg)
+	if options.usage != nil {
+		stream.add("usage", nil, interceptors.UsageStreamInterceptor(options.usage))
+	}
+	stream.add("validate", nil, interceptors.ValidateStreamInterceptor)    // Валидирует входящие сообщения стримов
+	stream.add("auth", authInterceptor.Settings(), authInterceptor.Stream) // Проверяет авторизацию токена и передает пользователя в стрим
+	if options.rateLimiter != nil {
+		stream.add("rate_limit", options.rateLimiter.Settings(), interceptors.RateLimitStreamInterceptor(options.rateLimiter))
+	}
+	stream.add("policy", policySettings(policies), interceptors.PolicyStreamInterceptor(policies)) // Проверяет роли по политике метода
+	if options.authz != nil {
+		stream.add("authz", options.authz.Settings(), options.authz.Stream)
+	}
+	if options.apiKeys != nil {
+		stream.add("api_key_rate_limit", nil, interceptors.APIKeyRateLimitStreamInterceptor(options.apiKeys))
+	}
+	stream.add("tenant", nil, tenantInterceptor.Stream) // Применяет настройки тенанта
+	// Завершает стримы, превысившие длительность или время без сообщений клиента (без лимитов ничего не ограничивает)
+	stream.add("stream_lifetime", streamLifetimeSettings(options.streamLifetimes), interceptors.NewStreamLifetimeInterceptor(options.streamLifetimes))
+	// Ограничивает скорость входящих сообщений стрима (без лимитов ничего не ограничивает)
+	stream.add("stream_rate_limit", streamRateLimitSettings(streamRateLimits), interceptors.NewStreamRateLimitInterceptor(streamRateLimits))
+	if options.consistency != nil {
+		stream.add("consistency", nil, interceptors.ConsistencyStreamInterceptor(options.consistency))
+	}
+	for i, interceptor := range options.streamInterceptors {
+		stream.add(fmt.Sprintf("custom_%d", i+1), nil, interceptor)
+	}
+
+	// Цепочки доступны администратору через AdminService.GetPipeline
+	if options.pipelines == nil {
+		options.pipelines = pipeline.NewRegistry()
+	}
+	options.pipelines.Set(pipeline.Chain{Name: pipeline.ChainGRPCUnary, Stages: unary.stages})
+	options.pipelines.Set(pipeline.Chain{Name: pipeline.ChainGRPCStream, Stages: stream.stages})
+
 	// Создание gRPC сервера с интерцепторами и конфигурацией
-	// Порядок интерцепторов важен:
-	// 1. Logger - логирует все запросы (включая заблокированные)
-	// 2. Validate - валидирует запросы по правилам из proto
-	// 3. Auth - проверяет авторизацию и блокирует неавторизованные запросы
-	// MaxConcurrentStreams: ограничивает количество одновременных стримов до 25
-	// для защиты сервера от перегрузки и контроля использования ресурсов
-	grpcServer := grpc.NewServer(
-		// Ограничиваем количество одновременных стримов
-		grpc.MaxConcurrentStreams(25),
-		// KeepAlive параметры для защиты от зависших соединений
-		// Time: время между пингами для проверки активности соединения
-		// Timeout: время ожидания ответа на ping перед разрывом соединения
-		// MaxConnectionIdle: время, после которого неактивное соединение будет закрыто
-		// MaxConnectionAge: максимальное время жизни соединения (ротация для профилактики деградации)
-		// MaxConnectionAgeGrace: время ожидания завершения активных запросов перед закрытием
-		grpc.KeepaliveParams(keepalive.ServerParameters{
-			MaxConnectionIdle:     30 * time.Minute, // Закрытие неактивных соединений через 30 минут
-			MaxConnectionAge:      1 * time.Hour,    // Максимальное время жизни соединения (ротация)
-			MaxConnectionAgeGrace: 5 * time.Second,  // Ожидание завершения активных запросов перед закрытием
-			Time:                  10 * time.Minute, // Время между пингами (рекомендуется 5-10 минут для backend-to-backend)
-			Timeout:               20 * time.Second, // Время ожидания ответа на ping
-		}),
-		// Интерцепторы: Logger → Validate → Auth
-		grpc.ChainUnaryInterceptor(
-			interceptors.LoggerUnaryInterceptor,   // Логирует все запросы и время выполнения
-			interceptors.ValidateUnaryInterceptor, // Валидирует запросы по правилам из proto
-			interceptors.AuthUnaryInterceptor,     // Проверяет авторизацию токена
-		),
-		// Стриминговые интерцепторы: логирование каждого сообщения в стриме
-		grpc.ChainStreamInterceptor(
-			interceptors.StreamInterceptor, // Логирует каждое сообщение в стримах (RecvMsg/SendMsg)
-		),
+	// Порядок интерцепторов важен и задается порядком add выше (звенья в скобках - если включены):
+	// unary: recovery → request_id → (metrics) → logger → (usage) → timeout → validate → auth → (rate_limit) →
+	//   (audit) → policy → (authz) → (api_key_rate_limit) → (recorder) → tenant → (consistency) → (mirror) → custom_N
+	// stream: recovery → request_id → (metrics) → logger → (usage) → validate → auth → (rate_limit) → policy →
+	//   (authz) → (api_key_rate_limit) → tenant → stream_lifetime → stream_rate_limit → (consistency) → custom_N
+	// Действующие цепочки записываются в лог и доступны через AdminService.GetPipeline
+	// Ограничения сообщений, стримов и keepalive (без WithLimits - DefaultLimits)
+	grpcOpts := append(options.limits.serverOptions(),
+		grpc.ChainUnaryInterceptor(unary.interceptors...),
+		grpc.ChainStreamInterceptor(stream.interceptors...),
 	)
+	if options.tls != nil {
+		grpcOpts = append(grpcOpts, grpc.Creds(credentials.NewTLS(options.tls)))
+	}
+	// Span запроса начинается до интерцепторов, поэтому в трассу попадают и отклоненные ими запросы
+	if options.tracing != nil {
+		grpcOpts = append(grpcOpts, grpc.StatsHandler(otelgrpc.NewServerHandler(
+			otelgrpc.WithTracerProvider(options.tracing),
+			otelgrpc.WithPropagators(telemetry.Propagator),
+		)))
+	}
+	// Компрессоры регистрируются в gRPC глобально, до создания сервера
+	if len(options.compression) > 0 {
+		compression.RegisterGRPC(options.compression...)
+		log.Printf("Enabled gRPC compression: %s", strings.Join(options.compression, ", "))
+	}
+	grpcServer := grpc.NewServer(grpcOpts...)
 
 	// Регистрация сервиса
 	notesv1.RegisterNotesServiceServer(grpcServer, handler)
 	log.Println("Registered NotesService")
+	notesv1.RegisterAuthServiceServer(grpcServer, NewAuthHandler(options.sessions, options.streamTickets))
+	log.Println("Registered AuthService")
+	notesv1.RegisterUserServiceServer(grpcServer, NewUserHandler(options.users))
+	log.Println("Registered UserService")
+	notesv1.RegisterAdminServiceServer(grpcServer, NewAdminHandler(options.apiKeys, options.pipelines, options.audit))
+	log.Println("Registered AdminService")
 
 	// Настройка reflection (для grpcurl/grpcui)
-	reflection.Register(grpcServer)
-	log.Println("Enabled gRPC reflection")
+	if options.reflection {
+		registerReflection(grpcServer, options.reflectionServices)
+	}
 
 	return grpcServer
 }
@@ -0,0 +1,29 @@
+package grpc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"notes-service/internal/pipeline"
+	"notes-service/internal/telemetry"
+	notesv1 "notes-service/pkg/proto/notes/v1"
+)
+
+func TestNewServer_RecoveryIsOutermost(t *testing.T) {
+	registry := pipeline.NewRegistry()
+	NewServer(notesv1.UnimplementedNotesServiceServer{}, nil,
+		WithPipelineRegistry(registry),
+		WithMetrics(telemetry.NewRegistry()),
+	)
+
+	chains := registry.Chains()
+	require.Len(t, chains, 2)
+	for _, chain := range chains {
+		require.NotEmpty(t, chain.Stages, chain.Name)
+		assert.Equal(t, "recovery", chain.Stages[0].Name, chain.Name)
+		assert.Equal(t, "request_id", chain.Stages[1].Name, chain.Name)
+		assert.Equal(t, "metrics", chain.Stages[2].Name, chain.Name)
+	}
+}
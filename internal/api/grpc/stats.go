@@ -0,0 +1,32 @@
+package grpc
+
+import (
+	"context"
+
+	"notes-service/internal/converter"
+	notesv1 "notes-service/pkg/proto/notes/v1"
+)
+
+// GetNoteStats возвращает статистику заметки: слова, символы, время чтения и изменение последней правки
+func (h *Handler) GetNoteStats(ctx context.Context, req *notesv1.GetNoteStatsRequest) (*notesv1.GetNoteStatsResponse, error) {
+	stats, err := h.statsService.NoteStats(withNotePassphrase(ctx), req.GetId())
+	if err != nil {
+		return nil, h.statusError(err)
+	}
+
+	return &notesv1.GetNoteStatsResponse{
+		Stats: converter.NoteStatsToProto(stats),
+	}, nil
+}
+
+// GetAccountStats возвращает сводную статистику заметок пользователя
+func (h *Handler) GetAccountStats(ctx context.Context, _ *notesv1.GetAccountStatsRequest) (*notesv1.GetAccountStatsResponse, error) {
+	stats, err := h.statsService.AccountStats(ctx)
+	if err != nil {
+		return nil, h.statusError(err)
+	}
+
+	return &notesv1.GetAccountStatsResponse{
+		Stats: converter.AccountStatsToProto(stats),
+	}, nil
+}
@@ -0,0 +1,36 @@
+package grpc
+
+import (
+	"context"
+
+	"notes-service/internal/converter"
+	"notes-service/internal/service/suggest"
+	notesv1 "notes-service/pkg/proto/notes/v1"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// WithSuggestIndex подключает подсказки заголовков (SuggestNotes); GetNote учитывает просмотры в ранге подсказок
+func WithSuggestIndex(index *suggest.Index) HandlerOption {
+	return func(h *Handler) {
+		h.suggestIndex = index
+	}
+}
+
+// errSuggestionsDisabled ответ SuggestNotes, если индекс подсказок не подключен
+var errSuggestionsDisabled = status.Error(codes.Unimplemented, "note suggestions are not configured")
+
+// SuggestNotes возвращает заметки вызывающего пользователя, заголовок которых начинается с префикса
+func (h *Handler) SuggestNotes(ctx context.Context, req *notesv1.SuggestNotesRequest) (*notesv1.SuggestNotesResponse, error) {
+	if h.suggestIndex == nil {
+		return nil, errSuggestionsDisabled
+	}
+
+	suggestions, err := h.suggestIndex.Suggest(ctx, req.GetPrefix(), int(req.GetLimit()))
+	if err != nil {
+		return nil, h.statusError(err)
+	}
+
+	return &notesv1.SuggestNotesResponse{Suggestions: converter.NoteSuggestionsToProto(suggestions)}, nil
+}
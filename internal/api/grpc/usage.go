@@ -0,0 +1,39 @@
+package grpc
+
+import (
+	"context"
+
+	"notes-service/internal/converter"
+	"notes-service/internal/service/usage"
+	notesv1 "notes-service/pkg/proto/notes/v1"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// WithUsageCollector подключает статистику использования (GetUsageStats)
+// Учет вызовов выполняют интерцепторы, см. WithUsageStats
+func WithUsageCollector(collector *usage.Collector) HandlerOption {
+	return func(h *Handler) {
+		h.usageCollector = collector
+	}
+}
+
+// GetUsageStats возвращает обезличенную статистику использования реплики
+func (h *Handler) GetUsageStats(ctx context.Context, req *notesv1.GetUsageStatsRequest) (*notesv1.GetUsageStatsResponse, error) {
+	if h.usageCollector == nil {
+		return nil, status.Error(codes.Unimplemented, "usage stats are disabled")
+	}
+
+	stats, err := h.usageCollector.Stats(ctx)
+	if err != nil {
+		return nil, h.statusError(err)
+	}
+
+	resp := converter.UsageStatsToProto(stats)
+	if stats.Reporting.LastError != nil {
+		// Ошибка отправки относится к внешнему адресу, а не к запросу
+		resp.Reporting.LastError = status.New(codes.Unavailable, stats.Reporting.LastError.Error()).Proto()
+	}
+	return resp, nil
+}
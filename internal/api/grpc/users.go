@@ -0,0 +1,56 @@
+package grpc
+
+import (
+	"context"
+
+	"notes-service/internal/converter"
+	"notes-service/internal/service/users"
+	notesv1 "notes-service/pkg/proto/notes/v1"
+)
+
+// UserHandler реализует gRPC сервер для UserService
+type UserHandler struct {
+	notesv1.UnimplementedUserServiceServer
+
+	users *users.Service
+}
+
+// NewUserHandler создает хэндлер UserService
+func NewUserHandler(users *users.Service) *UserHandler {
+	return &UserHandler{users: users}
+}
+
+// CreateUser создает пользователя (только для администратора)
+func (h *UserHandler) CreateUser(ctx context.Context, req *notesv1.CreateUserRequest) (*notesv1.User, error) {
+	user, err := h.users.Create(ctx, users.CreateUserInput{
+		ID:       req.GetId(),
+		Username: req.GetUsername(),
+		Password: req.GetPassword(),
+		Roles:    req.GetRoles(),
+	})
+	if err != nil {
+		return nil, handleError(err)
+	}
+
+	return converter.UserToProto(user), nil
+}
+
+// GetUser возвращает пользователя по ID
+func (h *UserHandler) GetUser(ctx context.Context, req *notesv1.GetUserRequest) (*notesv1.User, error) {
+	user, err := h.users.Get(ctx, req.GetId())
+	if err != nil {
+		return nil, handleError(err)
+	}
+
+	return converter.UserToProto(user), nil
+}
+
+// ListUsers возвращает всех пользователей (только для администратора)
+func (h *UserHandler) ListUsers(ctx context.Context, _ *notesv1.ListUsersRequest) (*notesv1.ListUsersResponse, error) {
+	list, err := h.users.List(ctx)
+	if err != nil {
+		return nil, handleError(err)
+	}
+
+	return &notesv1.ListUsersResponse{Users: converter.UsersToProto(list)}, nil
+}
@@ -0,0 +1,87 @@
+package grpc
+
+import (
+	"context"
+
+	"notes-service/internal/converter"
+	notesService "notes-service/internal/service/notes"
+	"notes-service/internal/service/webhooks"
+	notesv1 "notes-service/pkg/proto/notes/v1"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// WithWebhookService подключает вебхуки (RegisterWebhook, ListWebhooks, DeleteWebhook, ListWebhookDeadLetters)
+func WithWebhookService(webhookService *webhooks.Service) HandlerOption {
+	return func(h *Handler) {
+		h.webhookService = webhookService
+	}
+}
+
+// errWebhooksDisabled ответ RPC вебхуков, если сервис вебхуков не подключен
+var errWebhooksDisabled = status.Error(codes.Unimplemented, "webhooks are not configured")
+
+// RegisterWebhook регистрирует вебхук вызывающего пользователя
+func (h *Handler) RegisterWebhook(ctx context.Context, req *notesv1.RegisterWebhookRequest) (*notesv1.Webhook, error) {
+	if h.webhookService == nil {
+		return nil, errWebhooksDisabled
+	}
+
+	eventTypes := make([]notesService.EventType, 0, len(req.GetEventTypes()))
+	for _, eventType := range req.GetEventTypes() {
+		eventTypes = append(eventTypes, eventTypeFromProto(eventType))
+	}
+
+	webhook, err := h.webhookService.Register(ctx, webhooks.RegisterInput{
+		URL:        req.GetUrl(),
+		Secret:     req.GetSecret(),
+		EventTypes: eventTypes,
+	})
+	if err != nil {
+		return nil, h.statusError(err)
+	}
+
+	return converter.WebhookToProto(webhook), nil
+}
+
+// ListWebhooks возвращает вебхуки вызывающего пользователя
+func (h *Handler) ListWebhooks(ctx context.Context, _ *notesv1.ListWebhooksRequest) (*notesv1.ListWebhooksResponse, error) {
+	if h.webhookService == nil {
+		return nil, errWebhooksDisabled
+	}
+
+	list, err := h.webhookService.List(ctx)
+	if err != nil {
+		return nil, h.statusError(err)
+	}
+
+	return &notesv1.ListWebhooksResponse{Webhooks: converter.WebhooksToProto(list)}, nil
+}
+
+// DeleteWebhook удаляет вебхук вызывающего пользователя
+func (h *Handler) DeleteWebhook(ctx context.Context, req *notesv1.DeleteWebhookRequest) (*notesv1.DeleteWebhookResponse, error) {
+	if h.webhookService == nil {
+		return nil, errWebhooksDisabled
+	}
+
+	if err := h.webhookService.Delete(ctx, req.GetId()); err != nil {
+		return nil, h.statusError(err)
+	}
+
+	return &notesv1.DeleteWebhookResponse{}, nil
+}
+
+// ListWebhookDeadLetters возвращает недоставленные события вебхуков вызывающего пользователя
+func (h *Handler) ListWebhookDeadLetters(ctx context.Context, req *notesv1.ListWebhookDeadLettersRequest) (*notesv1.ListWebhookDeadLettersResponse, error) {
+	if h.webhookService == nil {
+		return nil, errWebhooksDisabled
+	}
+
+	letters, err := h.webhookService.ListDeadLetters(ctx, req.GetWebhookId())
+	if err != nil {
+		return nil, h.statusError(err)
+	}
+
+	return &notesv1.ListWebhookDeadLettersResponse{DeadLetters: converter.WebhookDeadLettersToProto(letters)}, nil
+}
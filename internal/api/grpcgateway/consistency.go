@@ -0,0 +1,27 @@
+package grpcgateway
+
+import (
+	"context"
+	"net/http"
+
+	"notes-service/internal/api/grpc/interceptors"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/protobuf/proto"
+)
+
+// consistencyTokenHeader HTTP заголовок токена согласованности (метаданные x-consistency-token)
+const consistencyTokenHeader = "X-Consistency-Token"
+
+// forwardConsistencyToken возвращает токен согласованности из ответа на изменение заметок
+// в заголовке X-Consistency-Token, который клиент передает в следующих запросах
+func forwardConsistencyToken(ctx context.Context, w http.ResponseWriter, _ proto.Message) error {
+	md, ok := runtime.ServerMetadataFromContext(ctx)
+	if !ok {
+		return nil
+	}
+	if values := md.HeaderMD.Get(interceptors.ConsistencyTokenHeader); len(values) > 0 {
+		w.Header().Set(consistencyTokenHeader, values[0])
+	}
+	return nil
+}
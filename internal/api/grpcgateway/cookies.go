@@ -0,0 +1,58 @@
+package grpcgateway
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"notes-service/internal/api/http/middleware"
+	notesv1 "notes-service/pkg/proto/notes/v1"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// authCookiePath путь cookie с токенами: браузер отправляет их только в запросах к API
+const authCookiePath = "/api/"
+
+// forwardAuthCookies сохраняет токены ответов Login и RefreshToken в HttpOnly cookie
+// и удаляет их в ответе Logout, чтобы браузерные клиенты не хранили токены в JavaScript.
+// SameSite=Strict: cookie не отправляются в запросах с других сайтов (защита от CSRF).
+// secure выставляет атрибут Secure (для Gateway за HTTPS)
+func forwardAuthCookies(secure bool) func(context.Context, http.ResponseWriter, proto.Message) error {
+	return func(_ context.Context, w http.ResponseWriter, resp proto.Message) error {
+		switch resp := resp.(type) {
+		case *notesv1.AuthTokens:
+			http.SetCookie(w, authCookie(middleware.AccessTokenCookie, resp.GetAccessToken(), resp.GetAccessTokenExpiresAt().AsTime(), secure))
+			http.SetCookie(w, authCookie(middleware.RefreshTokenCookie, resp.GetRefreshToken(), resp.GetRefreshTokenExpiresAt().AsTime(), secure))
+		case *notesv1.LogoutResponse:
+			for _, name := range []string{middleware.AccessTokenCookie, middleware.RefreshTokenCookie} {
+				cookie := authCookie(name, "", time.Time{}, secure)
+				cookie.MaxAge = -1
+				http.SetCookie(w, cookie)
+			}
+		}
+		return nil
+	}
+}
+
+// authCookie создает cookie с токеном, которая истекает вместе с ним
+func authCookie(name, value string, expires time.Time, secure bool) *http.Cookie {
+	return &http.Cookie{
+		Name:     name,
+		Value:    value,
+		Path:     authCookiePath,
+		Expires:  expires,
+		HttpOnly: true,
+		Secure:   secure,
+		SameSite: http.SameSiteStrictMode,
+	}
+}
+
+// cookieValue возвращает значение cookie запроса или пустую строку
+func cookieValue(req *http.Request, name string) string {
+	cookie, err := req.Cookie(name)
+	if err != nil {
+		return ""
+	}
+	return cookie.Value
+}
@@ -0,0 +1,206 @@
+package grpcgateway
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"errors"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// drainRetryAfter значение Retry-After (секунды) для запросов, пришедших во время остановки
+	drainRetryAfter = 5
+	// wsCloseServiceRestart код закрытия WebSocket 1012 (Service Restart, RFC 6455 / IANA)
+	wsCloseServiceRestart = 1012
+	// wsCloseReason причина закрытия WebSocket при остановке сервера
+	wsCloseReason = "server restarting"
+	// wsCloseWriteTimeout время на отправку кадра закрытия клиенту
+	wsCloseWriteTimeout = time.Second
+)
+
+// drainer переводит Gateway в режим остановки
+// После drain новые запросы получают 503 с Retry-After, а открытые WebSocket стримы
+// отменяются, и их соединения закрываются кадром Close 1012 "server restarting"
+// вместо простого разрыва TCP соединения
+type drainer struct {
+	ctx      context.Context // Контекст Gateway, его отмена означает начало остановки
+	draining atomic.Bool
+
+	mu      sync.Mutex
+	sockets map[*wsConn]struct{}
+	closed  chan struct{} // Сигнал о закрытии очередного соединения для wait
+}
+
+func newDrainer(ctx context.Context) *drainer {
+	return &drainer{
+		ctx:     ctx,
+		sockets: make(map[*wsConn]struct{}),
+		closed:  make(chan struct{}, 1),
+	}
+}
+
+// Middleware отклоняет запросы во время остановки и отслеживает WebSocket соединения
+// Должен быть самым внешним слоем, снаружи WebSocket proxy
+func (d *drainer) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if d.stopping() {
+			w.Header().Set("Retry-After", strconv.Itoa(drainRetryAfter))
+			w.Header().Set("Connection", "close")
+			http.Error(w, "server is restarting", http.StatusServiceUnavailable)
+			return
+		}
+
+		if !isWebSocketUpgrade(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		// Контекст WebSocket запроса отменяется при остановке, чтобы завершить проксируемый стрим
+		ctx, cancel := context.WithCancel(r.Context())
+		defer cancel()
+		next.ServeHTTP(&wsResponseWriter{ResponseWriter: w, drainer: d, cancel: cancel}, r.WithContext(ctx))
+	})
+}
+
+// stopping сообщает, началась ли остановка
+// Отмена контекста проверяется напрямую: стримы могут завершиться раньше, чем вызван drain
+func (d *drainer) stopping() bool {
+	return d.draining.Load() || d.ctx.Err() != nil
+}
+
+// drain включает режим остановки и отменяет открытые WebSocket стримы
+func (d *drainer) drain() {
+	d.draining.Store(true)
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for conn := range d.sockets {
+		conn.cancel()
+	}
+}
+
+// wait ожидает закрытия WebSocket соединений до отмены ctx, оставшиеся соединения закрываются принудительно
+func (d *drainer) wait(ctx context.Context) {
+	for {
+		d.mu.Lock()
+		remaining := make([]*wsConn, 0, len(d.sockets))
+		for conn := range d.sockets {
+			remaining = append(remaining, conn)
+		}
+		d.mu.Unlock()
+
+		if len(remaining) == 0 {
+			return
+		}
+
+		select {
+		case <-d.closed:
+		case <-ctx.Done():
+			for _, conn := range remaining {
+				_ = conn.Close()
+			}
+			return
+		}
+	}
+}
+
+func (d *drainer) track(conn *wsConn) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.sockets[conn] = struct{}{}
+}
+
+func (d *drainer) untrack(conn *wsConn) {
+	d.mu.Lock()
+	delete(d.sockets, conn)
+	d.mu.Unlock()
+
+	select {
+	case d.closed <- struct{}{}:
+	default:
+	}
+}
+
+// isWebSocketUpgrade проверяет, что запрос открывает WebSocket соединение
+func isWebSocketUpgrade(r *http.Request) bool {
+	return strings.EqualFold(r.Header.Get("Upgrade"), "websocket") &&
+		strings.Contains(strings.ToLower(r.Header.Get("Connection")), "upgrade")
+}
+
+// wsResponseWriter перехватывает Hijack, чтобы отслеживать соединение WebSocket proxy
+type wsResponseWriter struct {
+	http.ResponseWriter
+	drainer *drainer
+	cancel  context.CancelFunc
+}
+
+// Hijack передает WebSocket proxy соединение, отслеживаемое drainer
+// Таймауты HTTP сервера остаются на соединении после Hijack и прервали бы стрим, поэтому снимаются
+func (w *wsResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errors.New("response does not implement http.Hijacker")
+	}
+
+	conn, rw, err := hijacker.Hijack()
+	if err != nil {
+		return nil, nil, err
+	}
+	_ = conn.SetDeadline(time.Time{})
+
+	ws := &wsConn{Conn: conn, drainer: w.drainer, cancel: w.cancel}
+	w.drainer.track(ws)
+	return ws, rw, nil
+}
+
+// wsConn соединение WebSocket, которое при остановке сервера перед закрытием отправляет кадр Close
+// WebSocket proxy закрывает соединение после завершения стрима, когда запись в него уже закончена
+type wsConn struct {
+	net.Conn
+	drainer *drainer
+	cancel  context.CancelFunc
+
+	writeMu   sync.Mutex // Кадр закрытия не должен перемешаться с кадрами proxy
+	closeOnce sync.Once
+	closeErr  error
+}
+
+func (c *wsConn) Write(p []byte) (int, error) {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	return c.Conn.Write(p)
+}
+
+func (c *wsConn) Close() error {
+	c.closeOnce.Do(func() {
+		if c.drainer.stopping() {
+			c.writeMu.Lock()
+			_ = c.Conn.SetWriteDeadline(time.Now().Add(wsCloseWriteTimeout))
+			_, _ = c.Conn.Write(closeFrame(wsCloseServiceRestart, wsCloseReason))
+			c.writeMu.Unlock()
+		}
+		c.closeErr = c.Conn.Close()
+		c.drainer.untrack(c)
+	})
+	return c.closeErr
+}
+
+// closeFrame возвращает кадр Close сервера (без маски) с кодом и причиной закрытия
+func closeFrame(code uint16, reason string) []byte {
+	// Размер тела управляющего кадра не превышает 125 байт (RFC 6455, 5.5)
+	payload := make([]byte, 2, 2+len(reason))
+	binary.BigEndian.PutUint16(payload, code)
+	payload = append(payload, reason...)
+	if len(payload) > 125 {
+		payload = payload[:125]
+	}
+
+	return append([]byte{0x88, byte(len(payload))}, payload...)
+}
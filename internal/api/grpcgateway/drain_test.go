@@ -0,0 +1,91 @@
+package grpcgateway
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestDrainer_RejectsRequestsWhileDraining(t *testing.T) {
+	d := newDrainer(context.Background())
+	handler := d.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/notes/v1", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d before drain, want 200", rec.Code)
+	}
+
+	d.drain()
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/notes/v1", nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("status = %d while draining, want 503", rec.Code)
+	}
+	if rec.Header().Get("Retry-After") == "" {
+		t.Error("expected Retry-After header")
+	}
+}
+
+func TestDrainer_ClosesWebSocketWithCloseFrame(t *testing.T) {
+	d := newDrainer(context.Background())
+
+	// Обработчик имитирует WebSocket proxy: захватывает соединение и закрывает его после завершения стрима
+	handler := d.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, rw, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			t.Errorf("Hijack: %v", err)
+			return
+		}
+		defer conn.Close()
+
+		rw.WriteString("HTTP/1.1 101 Switching Protocols\r\nUpgrade: websocket\r\nConnection: Upgrade\r\n\r\n")
+		rw.Flush()
+		<-r.Context().Done()
+	}))
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	conn, err := net.Dial("tcp", server.Listener.Addr().String())
+	if err != nil {
+		t.Fatalf("Dial: %v", err)
+	}
+	defer conn.Close()
+
+	conn.Write([]byte("GET /api/v1/notes.v1.NotesService/SubscribeToEvents HTTP/1.1\r\nHost: test\r\nUpgrade: websocket\r\nConnection: Upgrade\r\n\r\n"))
+	reader := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(reader, nil)
+	if err != nil {
+		t.Fatalf("ReadResponse: %v", err)
+	}
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		t.Fatalf("status = %d, want 101", resp.StatusCode)
+	}
+
+	d.drain()
+
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	frame, err := io.ReadAll(reader)
+	if err != nil {
+		t.Fatalf("ReadAll: %v", err)
+	}
+	if !bytes.Equal(frame, closeFrame(wsCloseServiceRestart, wsCloseReason)) {
+		t.Errorf("unexpected close frame: %x", frame)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	d.wait(ctx)
+	if ctx.Err() != nil {
+		t.Error("expected tracked connection to be released")
+	}
+}
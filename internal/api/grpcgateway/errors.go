@@ -0,0 +1,107 @@
+package grpcgateway
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	notesv1 "notes-service/pkg/proto/notes/v1"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	spb "google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/grpc/status"
+)
+
+// errorHTTPStatuses HTTP статусы ошибок с internal_error_code, для которых статус по gRPC коду
+// (runtime.HTTPStatusFromCode) неточен: например, FailedPrecondition по умолчанию - 400
+var errorHTTPStatuses = map[string]int{
+	"NOTE_LOCKED":            http.StatusLocked,
+	"RESTORE_CONFLICT":       http.StatusConflict,
+	"IDEMPOTENCY_KEY_REUSED": http.StatusUnprocessableEntity,
+	"ATTACHMENT_TOO_LARGE":   http.StatusRequestEntityTooLarge,
+}
+
+// errorBody тело ответа Gateway с ошибкой
+// Поля ErrorDetails вынесены на верхний уровень, чтобы клиенту не нужно было разбирать details;
+// code и details сохраняют формат ошибок grpc-gateway по умолчанию (google.rpc.Status)
+type errorBody struct {
+	Code              int32             `json:"code"`                // gRPC код ошибки
+	Message           string            `json:"message"`             // Сообщение статуса
+	Reason            string            `json:"reason"`              // ErrorDetails.reason
+	InternalErrorCode string            `json:"internal_error_code"` // ErrorDetails.internal_error_code
+	NoteID            string            `json:"note_id"`             // ErrorDetails.note_id
+	RequestID         string            `json:"request_id"`          // ErrorDetails.request_id или заголовок X-Request-Id
+	Details           []json.RawMessage `json:"details"`             // Все детали статуса, включая ErrorDetails и RetryInfo
+}
+
+// errorHandler отвечает на ошибки Gateway телом errorBody
+// Заголовки и трейлеры ответа передаются так же, как в runtime.DefaultHTTPErrorHandler;
+// HTTP статус берется из errorHTTPStatuses по internal_error_code, иначе по gRPC коду
+func errorHandler(ctx context.Context, mux *runtime.ServeMux, marshaler runtime.Marshaler, w http.ResponseWriter, r *http.Request, err error) {
+	if httpStatus, ok := errorHTTPStatus(err); ok {
+		err = &runtime.HTTPStatusError{HTTPStatus: httpStatus, Err: err}
+	}
+	runtime.DefaultHTTPErrorHandler(ctx, mux, &errorMarshaler{Marshaler: marshaler, requestID: r.Header.Get(requestIDHeader)}, w, r, err)
+}
+
+// errorHTTPStatus возвращает HTTP статус из errorHTTPStatuses для ошибки с ErrorDetails
+// Ошибки маршрутизации Gateway (runtime.HTTPStatusError) уже содержат статус и не меняются
+func errorHTTPStatus(err error) (int, bool) {
+	var httpErr *runtime.HTTPStatusError
+	if errors.As(err, &httpErr) {
+		return 0, false
+	}
+	details := errorDetails(status.Convert(err).Proto())
+	httpStatus, ok := errorHTTPStatuses[details.GetInternalErrorCode()]
+	return httpStatus, ok
+}
+
+// errorDetails возвращает первую деталь ErrorDetails статуса (nil, если ее нет)
+func errorDetails(st *spb.Status) *notesv1.ErrorDetails {
+	for _, detail := range st.GetDetails() {
+		details := &notesv1.ErrorDetails{}
+		if detail.UnmarshalTo(details) == nil {
+			return details
+		}
+	}
+	return nil
+}
+
+// errorMarshaler заменяет google.rpc.Status в ответе с ошибкой на errorBody, остальное передает Marshaler
+// requestID - идентификатор запроса клиента для ошибок без ErrorDetails (например, ошибок маршрутизации)
+type errorMarshaler struct {
+	runtime.Marshaler
+	requestID string
+}
+
+// Marshal сериализует статус ошибки как errorBody, детали - исходным Marshaler (с полем @type)
+func (m *errorMarshaler) Marshal(v any) ([]byte, error) {
+	st, ok := v.(*spb.Status)
+	if !ok {
+		return m.Marshaler.Marshal(v)
+	}
+
+	body := errorBody{
+		Code:      st.GetCode(),
+		Message:   st.GetMessage(),
+		RequestID: m.requestID,
+		Details:   []json.RawMessage{},
+	}
+	if details := errorDetails(st); details != nil {
+		body.Reason = details.GetReason()
+		body.InternalErrorCode = details.GetInternalErrorCode()
+		body.NoteID = details.GetNoteId()
+		if id := details.GetRequestId(); id != "" {
+			body.RequestID = id
+		}
+	}
+	for _, detail := range st.GetDetails() {
+		buf, err := m.Marshaler.Marshal(detail)
+		if err != nil {
+			return nil, err
+		}
+		body.Details = append(body.Details, buf)
+	}
+	return json.Marshal(body)
+}
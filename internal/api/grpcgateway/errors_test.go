@@ -0,0 +1,100 @@
+package grpcgateway
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	notesv1 "notes-service/pkg/proto/notes/v1"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestErrorHandler(t *testing.T) {
+	mux := runtime.NewServeMux()
+	marshaler := &runtime.JSONPb{}
+
+	st, err := status.New(codes.NotFound, "note not found").WithDetails(&notesv1.ErrorDetails{
+		Reason:            "Note with ID 42 was searched but not found in DB",
+		InternalErrorCode: "NOTE_NOT_FOUND",
+		NoteId:            "42",
+		RequestId:         "req-1",
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	recorder := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "/notes/v1/42", nil)
+	req.Header.Set(requestIDHeader, "client-id")
+	errorHandler(context.Background(), mux, marshaler, recorder, req, st.Err())
+
+	if recorder.Code != http.StatusNotFound {
+		t.Errorf("Expected status %d, got %d", http.StatusNotFound, recorder.Code)
+	}
+	var body map[string]any
+	if err := json.Unmarshal(recorder.Body.Bytes(), &body); err != nil {
+		t.Fatalf("Expected JSON body, got %q: %v", recorder.Body.String(), err)
+	}
+	want := map[string]any{
+		"code":                float64(codes.NotFound),
+		"message":             "note not found",
+		"reason":              "Note with ID 42 was searched but not found in DB",
+		"internal_error_code": "NOTE_NOT_FOUND",
+		"note_id":             "42",
+		// Идентификатор из ErrorDetails приоритетнее заголовка запроса
+		"request_id": "req-1",
+	}
+	for key, value := range want {
+		if body[key] != value {
+			t.Errorf("Expected %s = %v, got %v", key, value, body[key])
+		}
+	}
+	details, _ := body["details"].([]any)
+	if len(details) != 1 || details[0].(map[string]any)["@type"] != "type.googleapis.com/notes.v1.ErrorDetails" {
+		t.Errorf("Expected ErrorDetails in details, got %v", body["details"])
+	}
+}
+
+func TestErrorHandler_HTTPStatus(t *testing.T) {
+	mux := runtime.NewServeMux()
+	marshaler := &runtime.JSONPb{}
+
+	// internal_error_code из errorHTTPStatuses уточняет статус FailedPrecondition (400 по умолчанию)
+	locked, _ := status.New(codes.FailedPrecondition, "note is locked").WithDetails(&notesv1.ErrorDetails{
+		InternalErrorCode: "NOTE_LOCKED",
+	})
+	recorder := httptest.NewRecorder()
+	errorHandler(context.Background(), mux, marshaler, recorder, httptest.NewRequest("PUT", "/notes/v1/42", nil), locked.Err())
+	if recorder.Code != http.StatusLocked {
+		t.Errorf("Expected status %d, got %d", http.StatusLocked, recorder.Code)
+	}
+
+	// Ошибки без ErrorDetails получают статус по gRPC коду и request_id из заголовка запроса
+	recorder = httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "/notes/v1", nil)
+	req.Header.Set(requestIDHeader, "client-id")
+	errorHandler(context.Background(), mux, marshaler, recorder, req, status.Error(codes.Unavailable, "connection refused"))
+	if recorder.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected status %d, got %d", http.StatusServiceUnavailable, recorder.Code)
+	}
+	var body errorBody
+	if err := json.Unmarshal(recorder.Body.Bytes(), &body); err != nil {
+		t.Fatalf("Expected JSON body, got %q: %v", recorder.Body.String(), err)
+	}
+	if body.RequestID != "client-id" || body.InternalErrorCode != "" || len(body.Details) != 0 {
+		t.Errorf("Unexpected body %+v", body)
+	}
+
+	// Статус ошибок маршрутизации Gateway сохраняется
+	recorder = httptest.NewRecorder()
+	routingErr := &runtime.HTTPStatusError{HTTPStatus: http.StatusMethodNotAllowed, Err: status.Error(codes.Unimplemented, "Method Not Allowed")}
+	errorHandler(context.Background(), mux, marshaler, recorder, httptest.NewRequest("PATCH", "/notes/v1", nil), routingErr)
+	if recorder.Code != http.StatusMethodNotAllowed {
+		t.Errorf("Expected status %d, got %d", http.StatusMethodNotAllowed, recorder.Code)
+	}
+}
@@ -2,60 +2,166 @@ package grpcgateway
 
 import (
 	"context"
+	"crypto/tls"
+	"errors"
 	"fmt"
 	"log"
+	"net"
 	"net/http"
+	"slices"
+	"strconv"
 	"strings"
+	"time"
 
+	"notes-service/internal/api/grpc/interceptors"
 	"notes-service/internal/api/http/middleware"
+	"notes-service/internal/auth"
+	"notes-service/internal/compression"
 	"notes-service/internal/config"
+	"notes-service/internal/egress"
+	"notes-service/internal/pipeline"
+	"notes-service/internal/telemetry"
 	notesv1 "notes-service/pkg/proto/notes/v1"
 
 	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
 	"github.com/rs/cors"
 	"github.com/tmc/grpc-websocket-proxy/wsproxy"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
-	"google.golang.org/grpc/metadata"
 )
 
-// Setup настраивает и запускает HTTP Gateway сервер
-// Если mux == nil, создается новый http.ServeMux, иначе используется переданный
-func Setup(ctx context.Context, grpcAddr string, httpAddr string, cfg *config.ConfigGateway, mux *http.ServeMux) error {
+// gatewayShutdownTimeout время ожидания завершения активных HTTP запросов и WebSocket стримов,
+// если server.graceful_shutdown_timeout не задан
+const gatewayShutdownTimeout = 5 * time.Second
+
+// SetupOptions параметры HTTP Gateway
+type SetupOptions struct {
+	GRPCAddr     string       // Адрес gRPC сервера, к которому Gateway проксирует запросы
+	HTTPAddr     string       // Адрес HTTP сервера Gateway
+	HTTPListener net.Listener // Соединения общего с gRPC порта (см. portmux), nil - Gateway сам слушает HTTPAddr
+	GRPCTLS      *tls.Config  // TLS подключения к gRPC серверу (nil - без TLS)
+	GRPCServer   *grpc.Server // gRPC сервер для вызовов gRPC-Web (nil - gRPC-Web выключен)
+
+	// Server - таймауты HTTP сервера и время ожидания активных запросов при остановке
+	Server *config.ConfigServer
+	// Gateway - настройки секции gateway
+	Gateway *config.ConfigGateway
+	// Live - CORS и лимит запросов, которые можно менять без перезапуска (nil - неизменные настройки из Gateway)
+	Live *LiveSettings
+
+	Mux           *http.ServeMux      // Общий mux сервера (nil - создается новый)
+	Authenticator auth.Authenticator  // Проверка токенов запросов к /api/ до проксирования (тот же, что у gRPC сервера)
+	StreamTickets *auth.StreamTickets // Билеты стримов из параметра URL ticket (nil - билеты не принимаются)
+	Egress        *egress.Policy      // Политика подключений к upstream сервисам из Gateway.Upstreams
+	Pipelines     *pipeline.Registry  // Реестр цепочек для AdminService.GetPipeline
+
+	// Metrics учитывает запросы по маршрутам и отклонения лимитом Gateway (nil - метрики HTTP не собираются)
+	Metrics *telemetry.Registry
+	// Tracing записывает запросы в трассы и передает их контекст gRPC серверу и upstream сервисам (nil - без трассировки)
+	Tracing trace.TracerProvider
+}
+
+// Setup настраивает и запускает HTTP Gateway сервер с параметрами opts
+// Цепочка middleware регистрируется в opts.Pipelines для AdminService.GetPipeline
+// Работает до отмены ctx, после чего останавливает сервер (см. shutdownGateway) и возвращает nil
+func Setup(ctx context.Context, opts SetupOptions) error {
+	cfg, live, mux := opts.Gateway, opts.Live, opts.Mux
+
 	// Создаем обычный http.ServeMux если не передан
 	if mux == nil {
 		mux = http.NewServeMux()
 	}
+	if live == nil {
+		live = NewLiveSettings(cfg)
+	}
+
+	// Заголовки из gateway.forward_headers и gateway.response_headers
+	headers, err := newHeaderMapping(cfg)
+	if err != nil {
+		return err
+	}
+	encodings, err := compression.ParseList(cfg.Compression)
+	if err != nil {
+		return fmt.Errorf("invalid gateway.compression: %w", err)
+	}
 
 	// Создаем runtime.ServeMux для HTTP Gateway с настройкой передачи метаданных
 	// Передаем HTTP заголовки (особенно Authorization) в gRPC metadata
 	gwMux := runtime.NewServeMux(
-		runtime.WithMetadata(func(ctx context.Context, req *http.Request) metadata.MD {
-			md := metadata.New(nil)
-			// Передача заголовка authorization из HTTP в gRPC metadata
-			// Это необходимо для работы Auth интерцептора на gRPC сервере
-			if auth := req.Header.Get("Authorization"); auth != "" {
-				md.Set("authorization", auth)
-			}
-			return md
-		}),
+		// Шаблон пути из proto - маршрут запроса в метриках HTTP, у streaming методов сняты таймауты
+		runtime.WithMiddlewares(routeMiddleware(streamingRoutes(notesv1.File_proto_notes_v1_notes_proto))),
+		// Встроенные заголовки и gateway.forward_headers передаются в gRPC metadata (см. headerMapping.metadata)
+		runtime.WithMetadata(headers.metadata),
+		// Идентификатор запроса возвращается в заголовке X-Request-Id, метаданные из gateway.response_headers -
+		// в заголовках и трейлерах с заданными именами
+		runtime.WithOutgoingHeaderMatcher(headers.outgoingHeader),
+		runtime.WithOutgoingTrailerMatcher(headers.outgoingTrailer),
+		// Ошибки возвращаются телом с полями ErrorDetails (reason, note_id, request_id) на верхнем уровне
+		runtime.WithErrorHandler(errorHandler),
+		// Предупреждения ответов мутаций дублируются в HTTP заголовок Warning
+		runtime.WithForwardResponseOption(forwardWarnings),
+		// Токен согласованности ответа на изменение заметок - в заголовок X-Consistency-Token
+		runtime.WithForwardResponseOption(forwardConsistencyToken),
+		// Токены Login и RefreshToken сохраняются в HttpOnly cookie, Logout их удаляет
+		runtime.WithForwardResponseOption(forwardAuthCookies(cfg.AuthCookieSecure)),
 	)
 
-	// Настройка опций для Gateway
-	opts := []grpc.DialOption{
-		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	// Политики методов из proto: публичные пути Auth middleware и повторы идемпотентных методов
+	policies := interceptors.LoadMethodPolicies(notesv1.File_proto_notes_v1_notes_proto)
+
+	// Настройка опций подключения Gateway к локальному gRPC серверу
+	// С TLS сервера Gateway подключается по TLS (с сертификатом клиента, если сервер требует mTLS)
+	creds := insecure.NewCredentials()
+	if opts.GRPCTLS != nil {
+		creds = credentials.NewTLS(opts.GRPCTLS)
+	}
+	dialOpts := []grpc.DialOption{
+		grpc.WithTransportCredentials(creds),
+		grpc.WithDefaultServiceConfig(policies.RetryServiceConfig()),
+	}
+	// Gateway принимает ответы того размера, который сервер может отправить (по умолчанию клиент gRPC принимает до 4 МБ)
+	if maxSize := opts.Server.Limits.MaxSendMsgSizeKB << 10; maxSize > 0 {
+		dialOpts = append(dialOpts, grpc.WithDefaultCallOptions(grpc.MaxCallRecvMsgSize(maxSize)))
+	}
+	// Контекст трассы HTTP запроса передается в gRPC метаданных (traceparent)
+	var tracingOpts []grpc.DialOption
+	if opts.Tracing != nil {
+		tracingOpts = append(tracingOpts, grpc.WithStatsHandler(otelgrpc.NewClientHandler(
+			otelgrpc.WithTracerProvider(opts.Tracing),
+			otelgrpc.WithPropagators(telemetry.Propagator),
+		)))
+		dialOpts = append(dialOpts, tracingOpts...)
 	}
 
-	// Регистрация хендлеров NotesService на runtime.ServeMux
-	err := notesv1.RegisterNotesServiceHandlerFromEndpoint(
-		ctx,
-		gwMux,
-		grpcAddr, // Адрес gRPC сервера (например, "localhost:50051")
-		opts,
-	)
-	if err != nil {
+	// Регистрация хендлеров NotesService, AuthService, UserService и AdminService (локальный gRPC сервер) и дополнительных
+	// upstream сервисов из конфигурации на общем runtime.ServeMux
+	localList := []config.ConfigUpstream{{
+		Name:    notesv1.NotesService_ServiceDesc.ServiceName,
+		Address: opts.GRPCAddr, // Адрес gRPC сервера (например, "localhost:50051")
+	}, {
+		Name:    notesv1.AuthService_ServiceDesc.ServiceName,
+		Address: opts.GRPCAddr,
+	}, {
+		Name:    notesv1.UserService_ServiceDesc.ServiceName,
+		Address: opts.GRPCAddr,
+	}, {
+		Name:    notesv1.AdminService_ServiceDesc.ServiceName,
+		Address: opts.GRPCAddr,
+	}}
+	if err := registerUpstreams(ctx, gwMux, localList, dialOpts); err != nil {
 		return fmt.Errorf("failed to register gateway: %w", err)
 	}
+	for _, upstream := range cfg.Upstreams {
+		upstreamOpts := append(opts.Egress.GRPCDialOptions(upstream.Address), tracingOpts...)
+		if err := registerUpstreams(ctx, gwMux, []config.ConfigUpstream{upstream}, upstreamOpts); err != nil {
+			return fmt.Errorf("failed to register gateway: %w", err)
+		}
+	}
 
 	// Добавляем gateway handler на общий mux с префиксом /api/v1/
 	// http.ServeMux автоматически обрабатывает более специфичные пути первыми,
@@ -65,17 +171,75 @@ func Setup(ctx context.Context, grpcAddr string, httpAddr string, cfg *config.Co
 	mux.Handle("/api/v1/", http.StripPrefix("/api/v1", gwMux))
 
 	// Применение middleware (в обратном порядке выполнения):
-	// 1. WebSocket Proxy (для streaming методов - самый внешний слой)
-	// 2. CORS (обработка CORS заголовков)
-	// 3. Logging (логирует все запросы)
-	// 4. Rate Limiting (ограничивает количество запросов)
+	// 0. Tracing (span запроса и контекст трассы клиента, если трассировка включена),
+	//    Metrics (учитывает все запросы, включая отклоненные остальными middleware, если метрики включены)
+	// 1. Drain (503 во время остановки, закрытие WebSocket соединений - самый внешний слой)
+	// 2. CORS (обработка CORS заголовков, в том числе у ответов 401)
+	// 3. Auth (проверка токена до проксирования и до WebSocket upgrade)
+	// 4. Compress (сжатие ответов по Accept-Encoding, если gateway.compression задан),
+	//    WebSocket Proxy (для streaming методов)
+	// 5. Logging (логирует все запросы)
+	// 6. Rate Limiting (ограничивает количество запросов)
+	// 7. gRPC-Web (вызовы методов из браузера на том же порту, если gateway.grpc_web включен)
 	var handler http.Handler = mux
-	handler = middleware.RateLimit(handler, cfg.RateLimitRPS, cfg.RateLimitBurst)
+	if opts.Metrics != nil {
+		handler = middleware.Routes(mux)
+	}
+	var stages []pipeline.Stage
+	// Вызовы gRPC-Web определяются по Content-Type и выполняются на gRPC сервере мимо runtime.ServeMux,
+	// под тем же rate limiting, логированием, CORS и остановкой, что и REST запросы
+	grpcWeb := cfg.GRPCWeb && opts.GRPCServer != nil
+	if grpcWeb {
+		handler = newGRPCWebHandler(handler, opts.GRPCServer, headers, notesv1.File_proto_notes_v1_notes_proto)
+		stages = append(stages, pipeline.Stage{Name: "grpc_web"})
+	}
+	stages = append(stages, pipeline.Stage{Name: "rate_limit", Settings: rateLimitSettings(cfg)})
+	handler = middleware.RateLimit(handler, live.limiter)
 	handler = middleware.Logging(handler)
-	c := setupCORS(cfg)
-	handler = c.Handler(handler)
-	// WebSocket proxy должен быть последним (самым внешним), чтобы корректно обрабатывать upgrade
+	stages = append(stages, pipeline.Stage{Name: "logging"})
+	// WebSocket proxy должен быть снаружи Logging, чтобы корректно обрабатывать upgrade
+	// (CORS и Auth не оборачивают ResponseWriter, Compress не оборачивает его у upgrade - они не мешают Hijack)
 	handler = setupWebSocketProxy(handler)
+	stages = append(stages, pipeline.Stage{Name: "websocket_proxy"})
+	// Сжатие снаружи WebSocket proxy: ответы стримов, которые он читает изнутри, не сжимаются
+	if len(encodings) > 0 {
+		handler = middleware.Compress(handler, encodings, cfg.CompressionMinSize)
+		stages = append(stages, pipeline.Stage{Name: "compress", Settings: map[string]string{
+			"encodings": strings.Join(encodings, ","),
+			"min_size":  strconv.Itoa(cfg.CompressionMinSize),
+		}})
+	}
+	if opts.Authenticator != nil {
+		publicPaths := publicHTTPPaths(policies, notesv1.File_proto_notes_v1_notes_proto)
+		handler = middleware.Auth(handler, opts.Authenticator, opts.StreamTickets, "/api/", publicPaths...)
+		stages = append(stages, pipeline.Stage{Name: "auth", Settings: map[string]string{
+			"public_paths":   strings.Join(publicPaths, ","),
+			"stream_tickets": strconv.FormatBool(opts.StreamTickets != nil),
+		}})
+	}
+	handler = live.corsMiddleware(handler)
+	stages = append(stages, pipeline.Stage{Name: "cors", Settings: corsSettings(cfg)})
+	drainer := newDrainer(ctx)
+	handler = drainer.Middleware(handler)
+	stages = append(stages, pipeline.Stage{Name: "drain", Settings: map[string]string{
+		"shutdown_drain_seconds": strconv.Itoa(cfg.ShutdownDrainSeconds),
+	}})
+	if opts.Metrics != nil {
+		handler = middleware.Metrics(handler, opts.Metrics)
+		stages = append(stages, pipeline.Stage{Name: "metrics"})
+	}
+	if opts.Tracing != nil {
+		handler = otelhttp.NewHandler(handler, "HTTP Gateway",
+			otelhttp.WithTracerProvider(opts.Tracing),
+			otelhttp.WithPropagators(telemetry.Propagator),
+			otelhttp.WithSpanNameFormatter(func(_ string, r *http.Request) string { return r.Method }),
+		)
+		stages = append(stages, pipeline.Stage{Name: "tracing"})
+	}
+	// Middleware добавлялись изнутри наружу, а выполняются снаружи внутрь
+	slices.Reverse(stages)
+	opts.Pipelines.Set(pipeline.Chain{Name: pipeline.ChainHTTP, Stages: stages})
+	live.attach(opts.Pipelines)
 
 	// Запуск HTTP сервера Gateway
 	// Swagger UI доступен по адресу /swagger/ (если добавлен через ServeSwagger)
@@ -85,23 +249,93 @@ func Setup(ctx context.Context, grpcAddr string, httpAddr string, cfg *config.Co
 	// - GET /api/v1/notes/v1/{id} - получение заметки
 	// - PUT /api/v1/notes/v1/{id} - обновление заметки
 	// - DELETE /api/v1/notes/v1/{id} - удаление заметки
+	// - POST /api/v1/auth/v1/login, /api/v1/auth/v1/token:refresh, /api/v1/auth/v1/logout - сессии
 	// WebSocket эндпоинты доступны для streaming методов:
 	// - /api/v1/notes.v1.NotesService/SubscribeToEvents (server-side streaming)
 	// - /api/v1/notes.v1.NotesService/UploadMetrics (client-side streaming)
-	// - /api/v1/notes.v1.NotesService/Chat (bidirectional streaming)
-	log.Printf("HTTP Gateway server listening on %s", httpAddr)
+	// - /api/v1/notes.v1.NotesService/StreamMetrics, /api/v1/notes.v1.NotesService/Chat (bidirectional streaming)
+	// gRPC-Web вызовы доступны без префикса, по полному имени метода: POST /notes.v1.NotesService/CreateNote
+	log.Printf("HTTP Gateway server listening on %s", opts.HTTPAddr)
 	log.Printf("API endpoints available at /api/v1/")
-	log.Printf("CORS enabled for origins: %s", cfg.CORSAllowedOrigins)
+	log.Printf("CORS enabled for origins: %s", strings.Join(cfg.CORSAllowedOrigins, ", "))
 	log.Printf("WebSocket proxy enabled for streaming methods")
-	return http.ListenAndServe(httpAddr, handler)
+	if grpcWeb {
+		log.Printf("gRPC-Web enabled for %s", notesv1.File_proto_notes_v1_notes_proto.Package())
+	}
+
+	httpServer := newHTTPServer(opts.HTTPAddr, handler, opts.Server)
+	shutdownTimeout := time.Duration(opts.Server.GracefulShutdownTimeout) * time.Second
+	if shutdownTimeout <= 0 {
+		shutdownTimeout = gatewayShutdownTimeout
+	}
+	shutdownDone := make(chan struct{})
+	go func() {
+		defer close(shutdownDone)
+		<-ctx.Done()
+		shutdownGateway(httpServer, drainer, time.Duration(cfg.ShutdownDrainSeconds)*time.Second, shutdownTimeout)
+	}()
+
+	if opts.HTTPListener != nil {
+		err = httpServer.Serve(opts.HTTPListener)
+	} else {
+		err = httpServer.ListenAndServe()
+	}
+	if !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	<-shutdownDone
+
+	return nil
+}
+
+// routeMiddleware сохраняет шаблон пути метода из proto как маршрут запроса для middleware.Metrics
+// и имени span запроса ("GET /api/v1/notes/v1/{id=*}" вместо "GET")
+// С маршрутов streaming (см. streamingRoutes) снимаются таймауты чтения и записи HTTP сервера
+func routeMiddleware(streaming map[string]bool) runtime.Middleware {
+	return func(next runtime.HandlerFunc) runtime.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request, pathParams map[string]string) {
+			if pattern, ok := runtime.HTTPPattern(r.Context()); ok {
+				route := "/api/v1" + pattern.String()
+				middleware.SetRoute(r.Context(), route)
+				span := trace.SpanFromContext(r.Context())
+				span.SetName(r.Method + " " + route)
+				span.SetAttributes(attribute.String("http.route", route))
+				if streaming[r.Method+" "+pattern.String()] {
+					liftDeadlines(w)
+				}
+			}
+			next(w, r, pathParams)
+		}
+	}
+}
+
+// shutdownGateway останавливает HTTP Gateway
+// В течение drainPeriod сервер еще принимает соединения, но отвечает 503 с Retry-After,
+// чтобы балансировщик и клиенты успели переключиться. WebSocket стримы закрываются сразу,
+// активные запросы завершаются в течение timeout
+func shutdownGateway(httpServer *http.Server, drainer *drainer, drainPeriod, timeout time.Duration) {
+	log.Printf("Draining HTTP Gateway: new requests get 503, WebSocket streams are closed")
+	drainer.drain()
+	time.Sleep(drainPeriod)
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	if err := httpServer.Shutdown(ctx); err != nil {
+		log.Printf("HTTP Gateway shutdown: %v", err)
+	}
+	// Shutdown не отслеживает соединения после Hijack (WebSocket)
+	drainer.wait(ctx)
+
+	log.Println("HTTP Gateway stopped")
 }
 
 // setupCORS настраивает CORS middleware используя конфигурацию
 func setupCORS(cfg *config.ConfigGateway) *cors.Cors {
-	origins := strings.Split(cfg.CORSAllowedOrigins, ",")
 	// Убираем пробелы из origins
-	for i := range origins {
-		origins[i] = strings.TrimSpace(origins[i])
+	origins := make([]string, len(cfg.CORSAllowedOrigins))
+	for i, origin := range cfg.CORSAllowedOrigins {
+		origins[i] = strings.TrimSpace(origin)
 	}
 
 	maxAge := cfg.CORSMaxAge
@@ -116,7 +350,19 @@ func setupCORS(cfg *config.ConfigGateway) *cors.Cors {
 			"Content-Type",
 			"Authorization",
 			"X-Requested-With",
+			"X-Idempotency-Key",
+			"X-Consistency-Token",
+			"X-API-Key",
+			"X-Request-Id",
+			"X-Note-Passphrase",
+			// Заголовки клиентов gRPC-Web
+			"X-Grpc-Web",
+			"X-User-Agent",
+			"Grpc-Timeout",
 		},
+		// Браузерный клиент читает токен согласованности из ответа на изменение заметок
+		// и идентификатор запроса для обращения в поддержку
+		ExposedHeaders:   []string{"X-Consistency-Token", "X-Request-Id", "Grpc-Status", "Grpc-Message"},
 		AllowCredentials: true,
 		MaxAge:           maxAge,
 	})
@@ -131,7 +377,7 @@ func setupCORS(cfg *config.ConfigGateway) *cors.Cors {
 // Streaming методы доступны через WebSocket с префиксом /api/v1/:
 // - /api/v1/notes.v1.NotesService/SubscribeToEvents (server-side streaming)
 // - /api/v1/notes.v1.NotesService/UploadMetrics (client-side streaming)
-// - /api/v1/notes.v1.NotesService/Chat (bidirectional streaming)
+// - /api/v1/notes.v1.NotesService/StreamMetrics, /api/v1/notes.v1.NotesService/Chat (bidirectional streaming)
 func setupWebSocketProxy(handler http.Handler) http.Handler {
 	// wsproxy.WebsocketProxy автоматически обрабатывает WebSocket upgrade
 	// для всех streaming методов gRPC через gRPC-Gateway
@@ -140,5 +386,20 @@ func setupWebSocketProxy(handler http.Handler) http.Handler {
 	// 2. Устанавливает WebSocket соединение
 	// 3. Конвертирует WebSocket в gRPC стрим
 	// 4. Проксирует данные между WebSocket и gRPC стримом
-	return wsproxy.WebsocketProxy(handler)
+	// Токен берется из Sec-WebSocket-Protocol или из cookie сессии (cookie приоритетнее)
+	return wsproxy.WebsocketProxy(handler,
+		wsproxy.WithRequestMutator(emptyGetBody),
+		wsproxy.WithTokenCookieName(middleware.AccessTokenCookie),
+	)
+}
+
+// emptyGetBody убирает тело у GET запросов, проксируемых из WebSocket
+// wsproxy передает сообщения клиента в тело запроса, которое закрывается только вместе
+// с соединением, а Gateway вычитывает тело GET запроса до конца перед вызовом метода,
+// поэтому server-side streaming методы (StreamNotes) без этого не запускались бы
+func emptyGetBody(_ *http.Request, outgoing *http.Request) *http.Request {
+	if outgoing.Method == http.MethodGet {
+		outgoing.Body = http.NoBody
+	}
+	return outgoing
 }
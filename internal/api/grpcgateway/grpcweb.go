@@ -0,0 +1,68 @@
+package grpcgateway
+
+import (
+	"net/http"
+
+	"notes-service/internal/api/http/middleware"
+
+	"github.com/improbable-eng/grpc-web/go/grpcweb"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// grpcWebHandler принимает вызовы gRPC-Web (POST /notes.v1.NotesService/CreateNote с Content-Type
+// application/grpc-web*) на HTTP порту и выполняет их на gRPC сервере в том же процессе через
+// grpcweb.WrapServer, остальные запросы передает next
+// Браузер не может отправлять HTTP/2 трейлеры, поэтому сообщения запроса читаются из тела,
+// а статус пишется кадром трейлеров в тело ответа: server-side streaming работает как обычно,
+// client-side и bidirectional стримы - в полудуплексе (все сообщения клиента, затем ответы сервера)
+type grpcWebHandler struct {
+	next      http.Handler
+	server    *grpcweb.WrappedGrpcServer
+	headers   *headerMapping
+	streaming map[string]bool // "/notes.v1.NotesService/StreamNotes" -> метод со стримом
+}
+
+// newGRPCWebHandler создает обработчик gRPC-Web для сервисов server
+// Метаданные запроса берутся из заголовков так же, как для REST запросов Gateway (см. headerMapping.metadata),
+// а стримы определяются по методам сервисов из files
+func newGRPCWebHandler(next http.Handler, server *grpc.Server, headers *headerMapping, files ...protoreflect.FileDescriptor) *grpcWebHandler {
+	streaming := make(map[string]bool)
+	for _, file := range files {
+		services := file.Services()
+		for i := 0; i < services.Len(); i++ {
+			service := services.Get(i)
+			for j := 0; j < service.Methods().Len(); j++ {
+				method := service.Methods().Get(j)
+				if method.IsStreamingServer() || method.IsStreamingClient() {
+					streaming["/"+string(service.FullName())+"/"+string(method.Name())] = true
+				}
+			}
+		}
+	}
+	return &grpcWebHandler{next: next, server: grpcweb.WrapServer(server), headers: headers, streaming: streaming}
+}
+
+// ServeHTTP выполняет вызов gRPC-Web на gRPC сервере
+// Ответ всегда имеет статус 200, код gRPC передается в кадре трейлеров (grpc-status, grpc-message,
+// grpc-status-details-bin с ErrorDetails), как в gRPC. CORS проверяет middleware Gateway, поэтому
+// вызывается HandleGrpcWebRequest, а не ServeHTTP обертки со своими CORS заголовками
+func (h *grpcWebHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !h.server.IsGrpcWebRequest(r) {
+		h.next.ServeHTTP(w, r)
+		return
+	}
+
+	// Путь - полное имя метода, неизвестные методы gRPC сервер отклоняет с Unimplemented
+	middleware.SetRoute(r.Context(), r.URL.Path)
+	if h.streaming[r.URL.Path] {
+		liftDeadlines(w)
+	}
+
+	// gRPC сервер получает заголовки запроса как метаданные; встроенные сопоставления
+	// (access токен из cookie, билет стрима из URL) добавляются к ним
+	for key, values := range h.headers.metadata(r.Context(), r) {
+		r.Header[http.CanonicalHeaderKey(key)] = values
+	}
+	h.server.HandleGrpcWebRequest(w, r)
+}
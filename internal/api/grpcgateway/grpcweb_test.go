@@ -0,0 +1,192 @@
+package grpcgateway
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"notes-service/internal/config"
+	notesv1 "notes-service/pkg/proto/notes/v1"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// grpcWebNotesServer отвечает на GetNote и StreamNotes для проверки обработчика gRPC-Web
+type grpcWebNotesServer struct {
+	notesv1.UnimplementedNotesServiceServer
+}
+
+func (grpcWebNotesServer) GetNote(ctx context.Context, req *notesv1.GetNoteRequest) (*notesv1.GetNoteResponse, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if len(md.Get("authorization")) == 0 {
+		return nil, status.Error(codes.Unauthenticated, "authorization header not provided")
+	}
+	if req.GetId() == "missing" {
+		st, _ := status.New(codes.NotFound, "note not found").WithDetails(&notesv1.ErrorDetails{NoteId: "missing"})
+		return nil, st.Err()
+	}
+	_ = grpc.SetHeader(ctx, metadata.Pairs("x-request-id", "req-1"))
+	return &notesv1.GetNoteResponse{Note: &notesv1.Note{Id: req.GetId(), Title: "Title"}}, nil
+}
+
+func (grpcWebNotesServer) StreamNotes(_ *notesv1.StreamNotesRequest, stream grpc.ServerStreamingServer[notesv1.Note]) error {
+	for _, id := range []string{"1", "2"} {
+		if err := stream.Send(&notesv1.Note{Id: id}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func newGRPCWebTestProxy(t *testing.T) *grpcWebHandler {
+	t.Helper()
+
+	server := grpc.NewServer()
+	notesv1.RegisterNotesServiceServer(server, grpcWebNotesServer{})
+	t.Cleanup(server.Stop)
+
+	headers, err := newHeaderMapping(&config.ConfigGateway{})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	next := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusTeapot) })
+	return newGRPCWebHandler(next, server, headers, notesv1.File_proto_notes_v1_notes_proto)
+}
+
+// Заголовок кадра gRPC-Web: флаги (1 байт) и длина сообщения (4 байта, big-endian); флаг кадра трейлеров
+const (
+	grpcWebFrameHeaderSize      = 5
+	grpcWebTrailerFlag     byte = 0x80
+)
+
+// grpcWebFrame кадр gRPC-Web с сообщением msg
+func grpcWebFrame(t *testing.T, msg proto.Message) []byte {
+	t.Helper()
+	buf, err := proto.Marshal(msg)
+	if err != nil {
+		t.Fatalf("Failed to marshal: %v", err)
+	}
+	frame := make([]byte, grpcWebFrameHeaderSize, grpcWebFrameHeaderSize+len(buf))
+	binary.BigEndian.PutUint32(frame[1:], uint32(len(buf)))
+	return append(frame, buf...)
+}
+
+// parseGRPCWebResponse разбирает тело ответа на сообщения и трейлеры
+func parseGRPCWebResponse(t *testing.T, body []byte) ([][]byte, string) {
+	t.Helper()
+	var messages [][]byte
+	var trailer string
+	for len(body) > 0 {
+		flag, size := body[0], binary.BigEndian.Uint32(body[1:grpcWebFrameHeaderSize])
+		payload := body[grpcWebFrameHeaderSize : grpcWebFrameHeaderSize+size]
+		body = body[grpcWebFrameHeaderSize+size:]
+		if flag&grpcWebTrailerFlag != 0 {
+			trailer = string(payload)
+			continue
+		}
+		messages = append(messages, payload)
+	}
+	return messages, trailer
+}
+
+func TestGRPCWebHandler_Unary(t *testing.T) {
+	proxy := newGRPCWebTestProxy(t)
+
+	req := httptest.NewRequest("POST", "/notes.v1.NotesService/GetNote", bytes.NewReader(grpcWebFrame(t, &notesv1.GetNoteRequest{Id: "42"})))
+	req.Header.Set("Content-Type", "application/grpc-web+proto")
+	req.Header.Set("Authorization", "Bearer token")
+	recorder := httptest.NewRecorder()
+	proxy.ServeHTTP(recorder, req)
+
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", recorder.Code)
+	}
+	if got := recorder.Header().Get("Content-Type"); got != "application/grpc-web+proto" {
+		t.Errorf("Expected Content-Type application/grpc-web+proto, got %q", got)
+	}
+	if got := recorder.Header().Get("X-Request-Id"); got != "req-1" {
+		t.Errorf("Expected X-Request-Id req-1, got %q", got)
+	}
+	messages, trailer := parseGRPCWebResponse(t, recorder.Body.Bytes())
+	if len(messages) != 1 {
+		t.Fatalf("Expected 1 message, got %d", len(messages))
+	}
+	var resp notesv1.GetNoteResponse
+	if err := proto.Unmarshal(messages[0], &resp); err != nil || resp.GetNote().GetId() != "42" {
+		t.Errorf("Unexpected response %v: %v", &resp, err)
+	}
+	if !strings.Contains(trailer, "grpc-status: 0\r\n") {
+		t.Errorf("Expected grpc-status 0 in trailer, got %q", trailer)
+	}
+}
+
+func TestGRPCWebHandler_ErrorDetails(t *testing.T) {
+	proxy := newGRPCWebTestProxy(t)
+
+	// grpc-web-text: кадры запроса и ответа в base64
+	body := base64.StdEncoding.EncodeToString(grpcWebFrame(t, &notesv1.GetNoteRequest{Id: "missing"}))
+	req := httptest.NewRequest("POST", "/notes.v1.NotesService/GetNote", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/grpc-web-text")
+	req.Header.Set("Authorization", "Bearer token")
+	recorder := httptest.NewRecorder()
+	proxy.ServeHTTP(recorder, req)
+
+	// Ответ без сообщений - trailers-only: статус передается заголовками, а не кадром трейлеров
+	if recorder.Body.Len() != 0 {
+		t.Errorf("Expected empty body, got %q", recorder.Body.String())
+	}
+	if got := recorder.Header().Get("Grpc-Status"); got != "5" {
+		t.Errorf("Expected NotFound status, got %q", got)
+	}
+	if got := recorder.Header().Get("Grpc-Message"); got != "note not found" {
+		t.Errorf("Expected grpc-message note not found, got %q", got)
+	}
+	if recorder.Header().Get("Grpc-Status-Details-Bin") == "" {
+		t.Error("Expected ErrorDetails in grpc-status-details-bin")
+	}
+}
+
+func TestGRPCWebHandler_ServerStreaming(t *testing.T) {
+	proxy := newGRPCWebTestProxy(t)
+
+	req := httptest.NewRequest("POST", "/notes.v1.NotesService/StreamNotes", bytes.NewReader(grpcWebFrame(t, &notesv1.StreamNotesRequest{})))
+	req.Header.Set("Content-Type", "application/grpc-web+proto")
+	recorder := httptest.NewRecorder()
+	proxy.ServeHTTP(recorder, req)
+
+	messages, trailer := parseGRPCWebResponse(t, recorder.Body.Bytes())
+	if len(messages) != 2 {
+		t.Fatalf("Expected 2 messages, got %d", len(messages))
+	}
+	if !strings.Contains(trailer, "grpc-status: 0\r\n") {
+		t.Errorf("Expected grpc-status 0 in trailer, got %q", trailer)
+	}
+}
+
+func TestGRPCWebHandler_PassesOtherRequests(t *testing.T) {
+	proxy := newGRPCWebTestProxy(t)
+
+	recorder := httptest.NewRecorder()
+	proxy.ServeHTTP(recorder, httptest.NewRequest("POST", "/notes/v1", strings.NewReader("{}")))
+	if recorder.Code != http.StatusTeapot {
+		t.Errorf("Expected REST request to reach next handler, got %d", recorder.Code)
+	}
+
+	// Неизвестный метод gRPC-Web получает Unimplemented, а не ответ REST Gateway
+	req := httptest.NewRequest("POST", "/unknown.v1.Service/Method", bytes.NewReader(grpcWebFrame(t, &notesv1.GetNoteRequest{})))
+	req.Header.Set("Content-Type", "application/grpc-web+proto")
+	recorder = httptest.NewRecorder()
+	proxy.ServeHTTP(recorder, req)
+	if got := recorder.Header().Get("Grpc-Status"); got != "12" {
+		t.Errorf("Expected Unimplemented status, got %q", got)
+	}
+}
@@ -0,0 +1,175 @@
+package grpcgateway
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"notes-service/internal/api/grpc/interceptors"
+	"notes-service/internal/api/http/middleware"
+	"notes-service/internal/config"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/grpc/metadata"
+)
+
+// requestIDHeader HTTP заголовок идентификатора запроса (метаданные x-request-id)
+const requestIDHeader = "X-Request-Id"
+
+// metadataKey допустимый ключ метаданных gRPC, он же допустимое имя HTTP заголовка без учета регистра
+var metadataKey = regexp.MustCompile(`^[0-9a-z._-]+$`)
+
+// hopByHopHeaders заголовки соединения HTTP, которые не относятся к запросу и не передаются в метаданных
+var hopByHopHeaders = map[string]bool{
+	"connection":        true,
+	"keep-alive":        true,
+	"proxy-connection":  true,
+	"te":                true,
+	"trailer":           true,
+	"transfer-encoding": true,
+	"upgrade":           true,
+	"host":              true,
+	"content-length":    true,
+	"content-type":      true,
+}
+
+// headerMapping передача заголовков между HTTP Gateway и метаданными gRPC, дополнительная
+// к встроенной (Authorization, X-API-Key, Accept-Language, X-Request-Id и т.д.)
+type headerMapping struct {
+	forward  []string          // Заголовки запроса, передаваемые в метаданных с тем же именем
+	response map[string]string // Ключ метаданных ответа -> заголовок HTTP ответа
+}
+
+// newHeaderMapping разбирает gateway.forward_headers ("X-Tenant-Id,X-Correlation-Id") и
+// gateway.response_headers ("x-quota-remaining,x-trace=X-Trace-Id": ключ метаданных и, через =, имя заголовка)
+// Ключи grpc-* зарезервированы gRPC, а заголовки соединения не относятся к запросу, поэтому отклоняются
+func newHeaderMapping(cfg *config.ConfigGateway) (*headerMapping, error) {
+	m := &headerMapping{response: map[string]string{interceptors.RequestIDHeader: requestIDHeader}}
+
+	for _, name := range trimList(cfg.ForwardHeaders) {
+		if err := checkHeaderName(name); err != nil {
+			return nil, fmt.Errorf("invalid gateway forward header %q: %w", name, err)
+		}
+		m.forward = append(m.forward, http.CanonicalHeaderKey(name))
+	}
+
+	for _, entry := range trimList(cfg.ResponseHeaders) {
+		key, header, found := strings.Cut(entry, "=")
+		key, header = strings.TrimSpace(key), strings.TrimSpace(header)
+		if !found {
+			header = key
+		}
+		if err := checkHeaderName(key); err != nil {
+			return nil, fmt.Errorf("invalid gateway response header %q: %w", entry, err)
+		}
+		if err := checkHeaderName(header); err != nil {
+			return nil, fmt.Errorf("invalid gateway response header %q: %w", entry, err)
+		}
+		m.response[strings.ToLower(key)] = http.CanonicalHeaderKey(header)
+	}
+	return m, nil
+}
+
+// checkHeaderName проверяет, что name можно передать и в HTTP заголовке, и в метаданных gRPC
+func checkHeaderName(name string) error {
+	lower := strings.ToLower(name)
+	switch {
+	case !metadataKey.MatchString(lower):
+		return errors.New("only letters, digits, '-', '_' and '.' are allowed")
+	case strings.HasPrefix(lower, "grpc-"):
+		return errors.New("grpc- metadata is reserved by gRPC")
+	case hopByHopHeaders[lower]:
+		return errors.New("connection header cannot be mapped")
+	}
+	return nil
+}
+
+// trimList убирает пробелы вокруг элементов списка и пропускает пустые элементы
+func trimList(list []string) []string {
+	var items []string
+	for _, item := range list {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// metadata передает gRPC серверу в метаданных заголовки запроса Gateway: встроенные (Authorization или
+// access токен из cookie, X-API-Key, Accept-Language, X-Request-Id и т.д.) и из forward_headers
+func (m *headerMapping) metadata(_ context.Context, req *http.Request) metadata.MD {
+	md := metadata.New(nil)
+	// Заголовки из конфигурации передаются первыми, встроенные ниже имеют приоритет
+	m.incoming(req, md)
+	// Передача заголовка authorization из HTTP в gRPC metadata
+	// Это необходимо для работы Auth интерцептора на gRPC сервере
+	// Без заголовка используется access токен из cookie, выставленной при входе
+	if auth := req.Header.Get("Authorization"); auth != "" {
+		md.Set("authorization", auth)
+	} else if token := cookieValue(req, middleware.AccessTokenCookie); token != "" {
+		md.Set("authorization", "Bearer "+token)
+	}
+	// Ключ API сервисного клиента - альтернатива заголовку Authorization
+	if key := req.Header.Get(middleware.APIKeyHeader); key != "" {
+		md.Set(interceptors.APIKeyHeader, key)
+	}
+	// Билет стрима из URL: браузер не может задать заголовки WebSocket и EventSource
+	if ticket := req.URL.Query().Get(middleware.StreamTicketParam); ticket != "" {
+		md.Set(interceptors.StreamTicketHeader, ticket)
+	}
+	// Refresh токен из cookie для RefreshToken и Logout без тела запроса
+	if token := cookieValue(req, middleware.RefreshTokenCookie); token != "" {
+		md.Set("x-refresh-token", token)
+	}
+	// Передача предпочитаемого языка для сортировки заметок по заголовку
+	if lang := req.Header.Get("Accept-Language"); lang != "" {
+		md.Set("accept-language", lang)
+	}
+	// Передача ключа идемпотентности CreateNote для безопасных повторов запросов
+	if key := req.Header.Get("X-Idempotency-Key"); key != "" {
+		md.Set("x-idempotency-key", key)
+	}
+	// Токен согласованности: запрос выполняется после записи, которая его выдала
+	if token := req.Header.Get(consistencyTokenHeader); token != "" {
+		md.Set(interceptors.ConsistencyTokenHeader, token)
+	}
+	// Парольная фраза защищенной заметки для GetNote, UpdateNote и SetNotePassphrase
+	if passphrase := req.Header.Get("X-Note-Passphrase"); passphrase != "" {
+		md.Set("x-note-passphrase", passphrase)
+	}
+	// Идентификатор запроса клиента или прокси: без него gRPC сервер назначит новый
+	if id := req.Header.Get(requestIDHeader); id != "" {
+		md.Set(interceptors.RequestIDHeader, id)
+	}
+	return md
+}
+
+// incoming добавляет в md значения заголовков forward_headers из запроса req
+func (m *headerMapping) incoming(req *http.Request, md metadata.MD) {
+	for _, name := range m.forward {
+		if values := req.Header.Values(name); len(values) > 0 {
+			md.Set(strings.ToLower(name), values...)
+		}
+	}
+}
+
+// outgoingHeader возвращает заголовки ответа gRPC из response_headers (и x-request-id) в HTTP заголовках
+// под заданными именами, в том числе в ответах с ошибкой; остальные - с префиксом Grpc-Metadata-, как по умолчанию
+func (m *headerMapping) outgoingHeader(key string) (string, bool) {
+	if header, ok := m.response[key]; ok {
+		return header, true
+	}
+	return runtime.MetadataHeaderPrefix + key, true
+}
+
+// outgoingTrailer возвращает трейлеры ответа gRPC из response_headers в HTTP трейлерах под заданными
+// именами; остальные - с префиксом Grpc-Trailer-. Трейлеры отправляются клиентам с заголовком TE: trailers
+func (m *headerMapping) outgoingTrailer(key string) (string, bool) {
+	if header, ok := m.response[key]; ok {
+		return header, true
+	}
+	return runtime.MetadataTrailerPrefix + key, true
+}
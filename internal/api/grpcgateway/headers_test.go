@@ -0,0 +1,70 @@
+package grpcgateway
+
+import (
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"notes-service/internal/config"
+
+	"google.golang.org/grpc/metadata"
+)
+
+func TestHeaderMapping(t *testing.T) {
+	headers, err := newHeaderMapping(&config.ConfigGateway{
+		ForwardHeaders:  []string{"X-Tenant-Id", " x-correlation-id", ""},
+		ResponseHeaders: []string{"x-quota-remaining", "x-trace = X-Trace-Id"},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	req := httptest.NewRequest("GET", "/api/v1/notes/v1", nil)
+	req.Header.Set("X-Tenant-Id", "acme")
+	req.Header.Add("X-Correlation-Id", "a")
+	req.Header.Add("X-Correlation-Id", "b")
+	req.Header.Set("X-Other", "ignored")
+	md := metadata.New(nil)
+	headers.incoming(req, md)
+
+	if got := md.Get("x-tenant-id"); !slices.Equal(got, []string{"acme"}) {
+		t.Errorf("x-tenant-id = %v, want [acme]", got)
+	}
+	if got := md.Get("x-correlation-id"); !slices.Equal(got, []string{"a", "b"}) {
+		t.Errorf("x-correlation-id = %v, want [a b]", got)
+	}
+	if len(md) != 2 {
+		t.Errorf("Expected only configured headers in metadata, got %v", md)
+	}
+
+	for _, tt := range []struct {
+		key, header, trailer string
+	}{
+		{key: "x-request-id", header: "X-Request-Id", trailer: "X-Request-Id"},
+		{key: "x-quota-remaining", header: "X-Quota-Remaining", trailer: "X-Quota-Remaining"},
+		{key: "x-trace", header: "X-Trace-Id", trailer: "X-Trace-Id"},
+		{key: "x-other", header: "Grpc-Metadata-x-other", trailer: "Grpc-Trailer-x-other"},
+	} {
+		if header, _ := headers.outgoingHeader(tt.key); header != tt.header {
+			t.Errorf("outgoingHeader(%q) = %q, want %q", tt.key, header, tt.header)
+		}
+		if trailer, _ := headers.outgoingTrailer(tt.key); trailer != tt.trailer {
+			t.Errorf("outgoingTrailer(%q) = %q, want %q", tt.key, trailer, tt.trailer)
+		}
+	}
+}
+
+func TestHeaderMapping_RejectsInvalidNames(t *testing.T) {
+	for _, cfg := range []config.ConfigGateway{
+		{ForwardHeaders: []string{"X-Tenant Id"}},
+		{ForwardHeaders: []string{"grpc-timeout"}},
+		{ForwardHeaders: []string{"Connection"}},
+		{ResponseHeaders: []string{"x-quota=Bad Header"}},
+		{ResponseHeaders: []string{"grpc-status"}},
+		{ResponseHeaders: []string{"x-quota=Transfer-Encoding"}},
+	} {
+		if _, err := newHeaderMapping(&cfg); err == nil {
+			t.Errorf("Expected error for %+v", cfg)
+		}
+	}
+}
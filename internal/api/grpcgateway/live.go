@@ -0,0 +1,76 @@
+package grpcgateway
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"notes-service/internal/api/http/middleware"
+	"notes-service/internal/config"
+	"notes-service/internal/pipeline"
+
+	"github.com/rs/cors"
+)
+
+// LiveSettings настройки Gateway, которые меняются без перезапуска: разрешенные источники CORS
+// (и остальные параметры CORS) и лимит запросов. Setup подключает их к middleware,
+// Apply заменяет для следующих запросов
+type LiveSettings struct {
+	cors    atomic.Pointer[cors.Cors]
+	limiter *middleware.RateLimiter
+
+	mu        sync.Mutex
+	pipelines *pipeline.Registry // Цепочка http, в которой обновляются настройки звеньев (после Setup)
+}
+
+// NewLiveSettings создает настройки по конфигурации Gateway
+func NewLiveSettings(cfg *config.ConfigGateway) *LiveSettings {
+	l := &LiveSettings{limiter: middleware.NewRateLimiter(cfg.RateLimitRPS, cfg.RateLimitBurst)}
+	l.cors.Store(setupCORS(cfg))
+	return l
+}
+
+// Apply применяет CORS и лимит запросов из cfg; запросы, которые уже выполняются, не затрагиваются
+func (l *LiveSettings) Apply(cfg *config.ConfigGateway) {
+	l.cors.Store(setupCORS(cfg))
+	l.limiter.Set(cfg.RateLimitRPS, cfg.RateLimitBurst)
+
+	l.mu.Lock()
+	pipelines := l.pipelines
+	l.mu.Unlock()
+	if pipelines != nil {
+		pipelines.UpdateSettings(pipeline.ChainHTTP, "rate_limit", rateLimitSettings(cfg))
+		pipelines.UpdateSettings(pipeline.ChainHTTP, "cors", corsSettings(cfg))
+	}
+}
+
+// attach запоминает реестр, в котором Setup зарегистрировал цепочку http
+func (l *LiveSettings) attach(pipelines *pipeline.Registry) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.pipelines = pipelines
+}
+
+// corsMiddleware применяет текущие настройки CORS
+func (l *LiveSettings) corsMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		l.cors.Load().ServeHTTP(w, r, next.ServeHTTP)
+	})
+}
+
+// rateLimitSettings описывает звено rate_limit для AdminService.GetPipeline
+func rateLimitSettings(cfg *config.ConfigGateway) map[string]string {
+	return map[string]string{
+		"rps":   strconv.Itoa(cfg.RateLimitRPS),
+		"burst": strconv.Itoa(cfg.RateLimitBurst),
+	}
+}
+
+// corsSettings описывает звено cors для AdminService.GetPipeline
+func corsSettings(cfg *config.ConfigGateway) map[string]string {
+	return map[string]string{
+		"allowed_origins": strings.Join(cfg.CORSAllowedOrigins, ","),
+	}
+}
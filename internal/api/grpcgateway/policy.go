@@ -0,0 +1,73 @@
+package grpcgateway
+
+import (
+	"log"
+	"net/http"
+	"strings"
+
+	"notes-service/internal/api/grpc/interceptors"
+
+	"google.golang.org/genproto/googleapis/api/annotations"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// apiPrefix префикс REST путей Gateway
+const apiPrefix = "/api/v1"
+
+// publicHTTPPaths возвращает REST пути методов, доступных без токена по политике из proto (requires_auth: false)
+// Auth middleware сравнивает путь целиком, поэтому пути с параметрами ({id}) пропускаются
+func publicHTTPPaths(policies interceptors.MethodPolicies, files ...protoreflect.FileDescriptor) []string {
+	var paths []string
+	for _, file := range files {
+		services := file.Services()
+		for i := 0; i < services.Len(); i++ {
+			methods := services.Get(i).Methods()
+			for j := 0; j < methods.Len(); j++ {
+				method := methods.Get(j)
+				fullMethod := "/" + string(method.Parent().FullName()) + "/" + string(method.Name())
+				if policy, ok := policies[fullMethod]; !ok || policy.RequiresAuth {
+					continue
+				}
+
+				path := httpPath(method)
+				if path == "" || strings.Contains(path, "{") {
+					log.Printf("⚠️  Public method %s has no fixed REST path, Gateway requires a token for it", fullMethod)
+					continue
+				}
+				paths = append(paths, apiPrefix+path)
+			}
+		}
+	}
+	return paths
+}
+
+// httpPath возвращает путь из опции google.api.http метода, пустую строку без опции
+func httpPath(method protoreflect.MethodDescriptor) string {
+	_, path := httpRoute(method)
+	return path
+}
+
+// httpRoute возвращает HTTP метод и путь из опции google.api.http метода, пустые строки без опции
+func httpRoute(method protoreflect.MethodDescriptor) (verb, path string) {
+	opts := method.Options()
+	if opts == nil || !proto.HasExtension(opts, annotations.E_Http) {
+		return "", ""
+	}
+	rule := proto.GetExtension(opts, annotations.E_Http).(*annotations.HttpRule)
+	switch pattern := rule.GetPattern().(type) {
+	case *annotations.HttpRule_Get:
+		return http.MethodGet, pattern.Get
+	case *annotations.HttpRule_Post:
+		return http.MethodPost, pattern.Post
+	case *annotations.HttpRule_Put:
+		return http.MethodPut, pattern.Put
+	case *annotations.HttpRule_Delete:
+		return http.MethodDelete, pattern.Delete
+	case *annotations.HttpRule_Patch:
+		return http.MethodPatch, pattern.Patch
+	case *annotations.HttpRule_Custom:
+		return pattern.Custom.GetKind(), pattern.Custom.GetPath()
+	}
+	return "", ""
+}
@@ -0,0 +1,62 @@
+package grpcgateway
+
+import (
+	"net/http"
+	"regexp"
+	"time"
+
+	"notes-service/internal/config"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// pathParam параметр пути из proto ({id}), в шаблоне runtime.Pattern он записывается как {id=*}
+var pathParam = regexp.MustCompile(`\{([^}=]+)\}`)
+
+// newHTTPServer создает HTTP сервер Gateway с таймаутами из секции server (0 - без ограничения)
+// ReadTimeout и WriteTimeout ограничивают обычные запросы; streaming методы и WebSocket
+// соединения снимают их для своего соединения (см. liftDeadlines и wsResponseWriter.Hijack)
+func newHTTPServer(addr string, handler http.Handler, cfg *config.ConfigServer) *http.Server {
+	return &http.Server{
+		Addr:              addr,
+		Handler:           handler,
+		ReadTimeout:       time.Duration(cfg.HTTPReadTimeout) * time.Second,
+		WriteTimeout:      time.Duration(cfg.HTTPWriteTimeout) * time.Second,
+		IdleTimeout:       time.Duration(cfg.HTTPIdleTimeout) * time.Second,
+		ReadHeaderTimeout: time.Duration(cfg.HTTPReadHeaderTimeout) * time.Second,
+	}
+}
+
+// streamingRoutes возвращает маршруты server-side streaming методов с REST путем
+// в виде "GET /notes/v1/{note_id=*}/attachments/{id=*}", как метод и runtime.HTTPPattern запроса
+func streamingRoutes(files ...protoreflect.FileDescriptor) map[string]bool {
+	routes := make(map[string]bool)
+	for _, file := range files {
+		services := file.Services()
+		for i := 0; i < services.Len(); i++ {
+			methods := services.Get(i).Methods()
+			for j := 0; j < methods.Len(); j++ {
+				method := methods.Get(j)
+				if !method.IsStreamingServer() {
+					continue
+				}
+				verb, path := httpRoute(method)
+				if path == "" {
+					continue
+				}
+				routes[verb+" "+pathParam.ReplaceAllString(path, "{$1=*}")] = true
+			}
+		}
+	}
+	return routes
+}
+
+// liftDeadlines снимает таймауты чтения и записи HTTP сервера с соединения запроса:
+// ответ streaming метода (выгрузка, вложение, поток заметок) пишется дольше WriteTimeout,
+// а истечение ReadTimeout во время ответа отменило бы контекст запроса
+// Ошибка означает, что ResponseWriter не дает доступа к соединению, и таймауты остаются
+func liftDeadlines(w http.ResponseWriter) {
+	controller := http.NewResponseController(w)
+	_ = controller.SetReadDeadline(time.Time{})
+	_ = controller.SetWriteDeadline(time.Time{})
+}
@@ -0,0 +1,71 @@
+package grpcgateway
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	notesv1 "notes-service/pkg/proto/notes/v1"
+)
+
+func TestStreamingRoutes(t *testing.T) {
+	routes := streamingRoutes(notesv1.File_proto_notes_v1_notes_proto)
+
+	for _, route := range []string{
+		"GET /notes/v1:stream",
+		"GET /notes/v1/notes:export",
+		"GET /notes/v1/{note_id=*}/attachments/{id=*}",
+	} {
+		if !routes[route] {
+			t.Errorf("expected streaming route %q in %v", route, routes)
+		}
+	}
+	if routes["GET /notes/v1"] || routes["GET /notes/v1/{id=*}"] {
+		t.Errorf("unary routes must not be streaming: %v", routes)
+	}
+}
+
+func TestLiftDeadlines(t *testing.T) {
+	const writeTimeout = 100 * time.Millisecond
+
+	// Ответ пишется дольше WriteTimeout: без liftDeadlines соединение закрывается до его отправки
+	respond := func(lift bool) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if lift {
+				liftDeadlines(w)
+			}
+			time.Sleep(3 * writeTimeout)
+			_, _ = io.WriteString(w, "done")
+		})
+	}
+
+	for _, tt := range []struct {
+		name string
+		lift bool
+		want bool
+	}{
+		{name: "unary", lift: false, want: false},
+		{name: "streaming", lift: true, want: true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewUnstartedServer(respond(tt.lift))
+			server.Config.WriteTimeout = writeTimeout
+			server.Start()
+			defer server.Close()
+
+			resp, err := http.Get(server.URL)
+			if err == nil {
+				body, _ := io.ReadAll(resp.Body)
+				resp.Body.Close()
+				if string(body) != "done" {
+					err = io.ErrUnexpectedEOF
+				}
+			}
+			if got := err == nil; got != tt.want {
+				t.Errorf("response received = %v (err: %v), want %v", got, err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package grpcgateway
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+
+	"notes-service/internal/config"
+	notesv1 "notes-service/pkg/proto/notes/v1"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/grpc"
+)
+
+// RegisterFunc регистрирует HTTP обработчики gRPC сервиса на runtime.ServeMux
+// Совпадает с сигнатурой сгенерированных функций Register<Service>HandlerFromEndpoint
+type RegisterFunc func(ctx context.Context, mux *runtime.ServeMux, endpoint string, opts []grpc.DialOption) error
+
+var (
+	upstreamsMu sync.RWMutex
+	upstreams   = map[string]RegisterFunc{
+		notesv1.NotesService_ServiceDesc.ServiceName: notesv1.RegisterNotesServiceHandlerFromEndpoint,
+		notesv1.AuthService_ServiceDesc.ServiceName:  notesv1.RegisterAuthServiceHandlerFromEndpoint,
+		notesv1.UserService_ServiceDesc.ServiceName:  notesv1.RegisterUserServiceHandlerFromEndpoint,
+		notesv1.AdminService_ServiceDesc.ServiceName: notesv1.RegisterAdminServiceHandlerFromEndpoint,
+	}
+)
+
+// RegisterUpstream делает gRPC сервис доступным для подключения к Gateway через конфигурацию
+// name - полное имя сервиса (например, "notes.v1.NotesService"), используемое в gateway.upstreams
+// Вызывается из init() пакетов, в которые сгенерирован код grpc-gateway сервиса
+func RegisterUpstream(name string, fn RegisterFunc) {
+	upstreamsMu.Lock()
+	defer upstreamsMu.Unlock()
+
+	if _, exists := upstreams[name]; exists {
+		panic(fmt.Sprintf("grpcgateway: upstream %q registered twice", name))
+	}
+	upstreams[name] = fn
+}
+
+// registerUpstreams регистрирует обработчики всех upstream сервисов на общем mux,
+// поэтому auth, CORS и rate limiting Gateway применяются ко всем сервисам одинаково
+func registerUpstreams(ctx context.Context, mux *runtime.ServeMux, list []config.ConfigUpstream, opts []grpc.DialOption) error {
+	upstreamsMu.RLock()
+	defer upstreamsMu.RUnlock()
+
+	for _, upstream := range list {
+		register, ok := upstreams[upstream.Name]
+		if !ok {
+			return fmt.Errorf("unknown upstream service %q (registered: %v)", upstream.Name, upstreamNames())
+		}
+
+		if err := register(ctx, mux, upstream.Address, opts); err != nil {
+			return fmt.Errorf("failed to register upstream %s at %s: %w", upstream.Name, upstream.Address, err)
+		}
+
+		log.Printf("Registered gateway upstream %s -> %s", upstream.Name, upstream.Address)
+	}
+
+	return nil
+}
+
+// upstreamNames возвращает отсортированные имена зарегистрированных сервисов
+// Вызывается под блокировкой upstreamsMu
+func upstreamNames() []string {
+	names := make([]string, 0, len(upstreams))
+	for name := range upstreams {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
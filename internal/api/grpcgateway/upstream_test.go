@@ -0,0 +1,37 @@
+package grpcgateway
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"notes-service/internal/config"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/grpc"
+)
+
+func TestRegisterUpstreams(t *testing.T) {
+	var gotEndpoint string
+	RegisterUpstream("test.v1.TestService", func(ctx context.Context, mux *runtime.ServeMux, endpoint string, opts []grpc.DialOption) error {
+		gotEndpoint = endpoint
+		return nil
+	})
+
+	err := registerUpstreams(context.Background(), runtime.NewServeMux(), []config.ConfigUpstream{
+		{Name: "test.v1.TestService", Address: "test:50051"},
+	}, nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if gotEndpoint != "test:50051" {
+		t.Errorf("Expected endpoint test:50051, got %q", gotEndpoint)
+	}
+
+	err = registerUpstreams(context.Background(), runtime.NewServeMux(), []config.ConfigUpstream{
+		{Name: "unknown.v1.Service", Address: "unknown:50051"},
+	}, nil)
+	if err == nil || !strings.Contains(err.Error(), "unknown upstream service") {
+		t.Errorf("Expected unknown upstream error, got: %v", err)
+	}
+}
@@ -0,0 +1,46 @@
+package grpcgateway
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	notesv1 "notes-service/pkg/proto/notes/v1"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// warningCode код предупреждения HTTP "299 Miscellaneous Persistent Warning" (RFC 7234, раздел 5.5)
+const warningCode = "299"
+
+// warningsResponse ответ с предупреждениями (CreateNoteResponse, UpdateNoteResponse)
+type warningsResponse interface {
+	GetWarnings() []*notesv1.Warning
+}
+
+// forwardWarnings дублирует предупреждения ответа в HTTP заголовки Warning, по одному на предупреждение:
+// Warning: 299 - "TAGS_NORMALIZED tags: tags were normalized to [\"go\"]"
+// Тело ответа не меняется, предупреждения в нем остаются в поле warnings
+func forwardWarnings(_ context.Context, w http.ResponseWriter, resp proto.Message) error {
+	withWarnings, ok := resp.(warningsResponse)
+	if !ok {
+		return nil
+	}
+
+	for _, warning := range withWarnings.GetWarnings() {
+		w.Header().Add("Warning", warningHeader(warning))
+	}
+	return nil
+}
+
+// warningHeader форматирует предупреждение как значение заголовка Warning
+func warningHeader(warning *notesv1.Warning) string {
+	text := warning.GetCode()
+	if field := warning.GetField(); field != "" {
+		text += " " + field
+	}
+	text += ": " + warning.GetMessage()
+
+	// warn-text - quoted-string, strconv.QuoteToASCII экранирует кавычки и не-ASCII символы
+	return warningCode + " - " + strconv.QuoteToASCII(text)
+}
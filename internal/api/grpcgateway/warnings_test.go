@@ -0,0 +1,45 @@
+package grpcgateway
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	notesv1 "notes-service/pkg/proto/notes/v1"
+)
+
+func TestForwardWarnings(t *testing.T) {
+	recorder := httptest.NewRecorder()
+	resp := &notesv1.CreateNoteResponse{Warnings: []*notesv1.Warning{
+		{Code: "WHITESPACE_TRIMMED", Message: "title leading and trailing whitespace was removed", Field: "title"},
+		{Code: "TAGS_NORMALIZED", Message: `tags were normalized to ["гоу"]`, Field: "tags"},
+	}}
+
+	if err := forwardWarnings(context.Background(), recorder, resp); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	got := recorder.Header().Values("Warning")
+	want := []string{
+		`299 - "WHITESPACE_TRIMMED title: title leading and trailing whitespace was removed"`,
+		// Не-ASCII символы экранируются, чтобы значение заголовка оставалось ASCII
+		`299 - "TAGS_NORMALIZED tags: tags were normalized to [\"\u0433\u043e\u0443\"]"`,
+	}
+	if len(got) != len(want) {
+		t.Fatalf("Expected %d Warning headers, got %v", len(want), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Expected header %s, got %s", want[i], got[i])
+		}
+	}
+
+	// Ответы без предупреждений заголовок не получают
+	recorder = httptest.NewRecorder()
+	if err := forwardWarnings(context.Background(), recorder, &notesv1.GetNoteResponse{}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(recorder.Header().Values("Warning")) != 0 {
+		t.Errorf("Expected no Warning header, got %v", recorder.Header().Values("Warning"))
+	}
+}
@@ -0,0 +1,114 @@
+package middleware
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"slices"
+	"strings"
+
+	"notes-service/internal/auth"
+
+	"google.golang.org/grpc/codes"
+)
+
+// Cookie с токенами сессий, выставляемые Gateway в ответах Login и RefreshToken
+const (
+	AccessTokenCookie  = "notes_access_token"
+	RefreshTokenCookie = "notes_refresh_token"
+)
+
+// APIKeyHeader заголовок с ключом API - альтернатива заголовку Authorization
+const APIKeyHeader = "X-API-Key"
+
+// StreamTicketParam параметр URL с билетом стрима (AuthService.IssueStreamTicket)
+const StreamTicketParam = "ticket"
+
+// Auth проверяет токен запросов к путям с префиксом prefix до проксирования в gRPC:
+// REST запросы - по заголовку Authorization, X-API-Key или cookie AccessTokenCookie, WebSocket upgrade -
+// также по Sec-WebSocket-Protocol ("Bearer, <token>"), поэтому соединение без действительного
+// токена не открывается. Без токена принимается билет стрима из параметра URL StreamTicketParam, если
+// tickets != nil: здесь проверяются только подпись и срок, метод и однократность проверяет gRPC сервер.
+// Preflight запросы CORS (OPTIONS) и пути из publicPaths пропускаются без проверки.
+// Ответ об ошибке повторяет формат ошибок Gateway: {"code": ..., "message": ...}
+func Auth(next http.Handler, authenticator auth.Authenticator, tickets *auth.StreamTickets, prefix string, publicPaths ...string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodOptions || !strings.HasPrefix(r.URL.Path, prefix) || slices.Contains(publicPaths, r.URL.Path) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		token, ok := requestToken(r)
+		if ticket := r.URL.Query().Get(StreamTicketParam); !ok && ticket != "" && tickets != nil {
+			if err := tickets.Verify(ticket); err != nil {
+				log.Printf("[HTTP] Invalid stream ticket for %s from %s: %v", r.URL.Path, r.RemoteAddr, err)
+				writeAuthError(w, r, http.StatusUnauthorized, codes.Unauthenticated, "invalid stream ticket")
+				return
+			}
+			next.ServeHTTP(w, r)
+			return
+		}
+		if !ok {
+			writeAuthError(w, r, http.StatusUnauthorized, codes.Unauthenticated, "authorization header not provided")
+			return
+		}
+
+		if _, err := authenticator.Authenticate(r.Context(), token); err != nil {
+			if errors.Is(err, auth.ErrInvalidToken) {
+				log.Printf("[HTTP] Invalid token for %s from %s", r.URL.Path, r.RemoteAddr)
+				writeAuthError(w, r, http.StatusUnauthorized, codes.Unauthenticated, "invalid token")
+				return
+			}
+			log.Printf("[HTTP] Authentication failed for %s: %v", r.URL.Path, err)
+			writeAuthError(w, r, http.StatusServiceUnavailable, codes.Unavailable, "authentication is temporarily unavailable")
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// requestToken извлекает токен из заголовка Authorization, ключ API из X-API-Key, токен из cookie сессии или, для WebSocket upgrade,
+// из Sec-WebSocket-Protocol: браузеры не позволяют задать Authorization для WebSocket.
+// Для WebSocket cookie проверяется первой, так как WebSocket proxy передает в gRPC именно ее
+func requestToken(r *http.Request) (string, bool) {
+	if header := r.Header.Get("Authorization"); header != "" {
+		return auth.ParseBearer(header)
+	}
+	if key := r.Header.Get(APIKeyHeader); key != "" {
+		return key, true
+	}
+
+	if cookie, err := r.Cookie(AccessTokenCookie); err == nil && cookie.Value != "" {
+		return cookie.Value, true
+	}
+
+	if strings.EqualFold(r.Header.Get("Upgrade"), "websocket") {
+		protocol, ok := strings.CutPrefix(r.Header.Get("Sec-WebSocket-Protocol"), "Bearer,")
+		if ok {
+			token := strings.TrimSpace(protocol)
+			return token, token != ""
+		}
+	}
+	return "", false
+}
+
+// writeAuthError отвечает ошибкой аутентификации в формате ошибок Gateway (поля ErrorDetails пустые,
+// request_id - из заголовка X-Request-Id: запрос не доходит до gRPC сервера, который назначает идентификатор)
+func writeAuthError(w http.ResponseWriter, r *http.Request, httpStatus int, code codes.Code, message string) {
+	if httpStatus == http.StatusUnauthorized {
+		w.Header().Set("WWW-Authenticate", "Bearer")
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(httpStatus)
+	_ = json.NewEncoder(w).Encode(map[string]any{
+		"code":                code,
+		"message":             message,
+		"reason":              "",
+		"internal_error_code": "",
+		"note_id":             "",
+		"request_id":          r.Header.Get("X-Request-Id"),
+		"details":             []any{},
+	})
+}
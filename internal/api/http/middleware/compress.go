@@ -0,0 +1,184 @@
+package middleware
+
+import (
+	"log"
+	"mime"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"notes-service/internal/compression"
+)
+
+// incompressibleTypes типы содержимого, которые уже сжаты и не выигрывают от повторного сжатия
+var incompressibleTypes = []string{"image/", "video/", "audio/", "application/zip", "application/gzip", "application/zstd"}
+
+// Compress сжимает ответы алгоритмом из encodings (в порядке предпочтения), который клиент принимает
+// по Accept-Encoding. Ответ короче minSize байт отправляется без сжатия; ответы streaming методов
+// сжимаются с первого Flush, чтобы сообщения стрима не задерживались
+// WebSocket upgrade и ответы, уже имеющие Content-Encoding, не изменяются
+func Compress(next http.Handler, encodings []string, minSize int) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		encoding := negotiateEncoding(r.Header.Get("Accept-Encoding"), encodings)
+		w.Header().Add("Vary", "Accept-Encoding")
+		if encoding == "" || r.Method == http.MethodHead || r.Header.Get("Upgrade") != "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		cw := &compressWriter{ResponseWriter: w, encoding: encoding, minSize: minSize, statusCode: http.StatusOK}
+		defer cw.finish()
+		next.ServeHTTP(cw, r)
+	})
+}
+
+// negotiateEncoding выбирает первый алгоритм из encodings, который клиент принимает (q > 0)
+func negotiateEncoding(acceptEncoding string, encodings []string) string {
+	accepted := make(map[string]bool)
+	for _, part := range strings.Split(acceptEncoding, ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		if q, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if value, err := strconv.ParseFloat(q, 64); err == nil && value == 0 {
+				continue
+			}
+		}
+		accepted[strings.ToLower(strings.TrimSpace(name))] = true
+	}
+	for _, encoding := range encodings {
+		if accepted[encoding] || accepted["*"] {
+			return encoding
+		}
+	}
+	return ""
+}
+
+// compressWriter накапливает начало ответа до minSize байт и решает, сжимать ли его
+type compressWriter struct {
+	http.ResponseWriter
+	encoding   string
+	minSize    int
+	statusCode int
+
+	buf         []byte
+	wroteHeader bool // Заголовок ответа передан клиенту
+	passthrough bool // Ответ передается без сжатия
+	writer      compression.Writer
+}
+
+// WriteHeader откладывает отправку статуса до решения о сжатии
+func (cw *compressWriter) WriteHeader(code int) {
+	if cw.wroteHeader {
+		return
+	}
+	cw.statusCode = code
+	// Информационные ответы (103 Early Hints) не завершают заголовок
+	if code < http.StatusOK {
+		cw.ResponseWriter.WriteHeader(code)
+	}
+}
+
+// Write пишет данные в поток сжатия или в буфер, пока ответ короче minSize
+func (cw *compressWriter) Write(p []byte) (int, error) {
+	switch {
+	case cw.passthrough:
+		return cw.ResponseWriter.Write(p)
+	case cw.writer != nil:
+		return cw.writer.Write(p)
+	}
+
+	if !cw.compressible() {
+		if err := cw.start(false); err != nil {
+			return 0, err
+		}
+		return cw.ResponseWriter.Write(p)
+	}
+	cw.buf = append(cw.buf, p...)
+	if len(cw.buf) >= cw.minSize {
+		if err := cw.start(true); err != nil {
+			return 0, err
+		}
+	}
+	return len(p), nil
+}
+
+// Flush отправляет накопленные данные клиенту: ответ streaming метода сжимается сразу
+func (cw *compressWriter) Flush() {
+	if !cw.wroteHeader {
+		if err := cw.start(cw.compressible() && len(cw.buf) > 0); err != nil {
+			log.Printf("[HTTP] Failed to start %s response compression: %v", cw.encoding, err)
+			return
+		}
+	}
+	if cw.writer != nil {
+		if err := cw.writer.Flush(); err != nil {
+			return
+		}
+	}
+	if flusher, ok := cw.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Unwrap возвращает исходный ResponseWriter для http.ResponseController
+func (cw *compressWriter) Unwrap() http.ResponseWriter {
+	return cw.ResponseWriter
+}
+
+// compressible проверяет по статусу и заголовкам ответа, что его имеет смысл сжимать
+func (cw *compressWriter) compressible() bool {
+	header := cw.Header()
+	if header.Get("Content-Encoding") != "" || header.Get("Content-Range") != "" {
+		return false
+	}
+	if cw.statusCode < http.StatusOK || cw.statusCode == http.StatusNoContent || cw.statusCode == http.StatusNotModified {
+		return false
+	}
+	mediaType, _, _ := mime.ParseMediaType(header.Get("Content-Type"))
+	return !slices.ContainsFunc(incompressibleTypes, func(prefix string) bool {
+		return strings.HasPrefix(mediaType, prefix)
+	})
+}
+
+// start отправляет заголовок ответа и накопленные данные, со сжатием или без
+func (cw *compressWriter) start(compress bool) error {
+	cw.wroteHeader = true
+	if compress {
+		writer, err := compression.NewWriter(cw.encoding, cw.ResponseWriter)
+		if err != nil {
+			compress = false
+		} else {
+			cw.writer = writer
+			cw.Header().Set("Content-Encoding", cw.encoding)
+			cw.Header().Del("Content-Length")
+		}
+	}
+	cw.passthrough = !compress
+	cw.ResponseWriter.WriteHeader(cw.statusCode)
+
+	buf := cw.buf
+	cw.buf = nil
+	if len(buf) == 0 {
+		return nil
+	}
+	if cw.writer != nil {
+		_, err := cw.writer.Write(buf)
+		return err
+	}
+	_, err := cw.ResponseWriter.Write(buf)
+	return err
+}
+
+// finish завершает ответ после обработчика: короткий ответ отправляется без сжатия, поток сжатия закрывается
+func (cw *compressWriter) finish() {
+	if !cw.wroteHeader {
+		if err := cw.start(false); err != nil {
+			return
+		}
+	}
+	if cw.writer != nil {
+		if err := cw.writer.Close(); err != nil {
+			log.Printf("[HTTP] Failed to finish %s response: %v", cw.encoding, err)
+		}
+	}
+}
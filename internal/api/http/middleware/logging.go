@@ -1,9 +1,15 @@
 package middleware
 
 import (
+	"bufio"
+	"errors"
 	"log"
+	"log/slog"
+	"net"
 	"net/http"
 	"time"
+
+	"notes-service/internal/logging"
 )
 
 // responseWriter обертка для ResponseWriter для логирования статуса ответа
@@ -17,9 +23,39 @@ func (rw *responseWriter) WriteHeader(code int) {
 	rw.ResponseWriter.WriteHeader(code)
 }
 
-// Logging логирует все HTTP запросы с информацией о времени выполнения
+// Flush отправляет клиенту буферизованные данные, без него Gateway не может отдавать стримы
+func (rw *responseWriter) Flush() {
+	if flusher, ok := rw.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Hijack передает соединение WebSocket proxy, когда обертка стоит снаружи него (Metrics)
+// Запрос учитывается с кодом 101 Switching Protocols
+func (rw *responseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := rw.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errors.New("response does not implement http.Hijacker")
+	}
+	conn, buf, err := hijacker.Hijack()
+	if err == nil {
+		rw.statusCode = http.StatusSwitchingProtocols
+	}
+	return conn, buf, err
+}
+
+// Unwrap возвращает исходный ResponseWriter для http.ResponseController
+func (rw *responseWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
+
+// Logging логирует все HTTP запросы с информацией о времени выполнения (уровень info, см. logging.SetLevel)
 func Logging(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !logging.Enabled(slog.LevelInfo) {
+			next.ServeHTTP(w, r)
+			return
+		}
 		start := time.Now()
 
 		// Логирование запроса
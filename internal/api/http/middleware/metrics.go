@@ -0,0 +1,63 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"notes-service/internal/telemetry"
+)
+
+// unmatchedRoute маршрут запросов, для которых не найден обработчик (например, 404)
+const unmatchedRoute = "unmatched"
+
+// metricsKey ключ состояния Metrics в контексте запроса
+type metricsKey struct{}
+
+// requestMetrics состояние запроса, которое заполняют вложенные обработчики
+type requestMetrics struct {
+	registry *telemetry.Registry
+	route    string
+}
+
+// Metrics учитывает HTTP запросы по методу, маршруту и коду ответа и время их выполнения в registry
+// Маршрут - шаблон пути (SetRoute, Routes), а не сам путь, чтобы ID заметок не размножали метрики
+func Metrics(next http.Handler, registry *telemetry.Registry) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		state := &requestMetrics{registry: registry}
+		ww := &responseWriter{ResponseWriter: w, statusCode: http.StatusOK}
+
+		next.ServeHTTP(ww, r.WithContext(context.WithValue(r.Context(), metricsKey{}, state)))
+
+		route := state.route
+		if route == "" {
+			route = unmatchedRoute
+		}
+		registry.ObserveHTTP(r.Method, route, ww.statusCode, time.Since(start))
+	})
+}
+
+// SetRoute сохраняет для Metrics шаблон маршрута запроса, например "/api/v1/notes/v1/{id=*}"
+func SetRoute(ctx context.Context, route string) {
+	if state, ok := ctx.Value(metricsKey{}).(*requestMetrics); ok {
+		state.route = route
+	}
+}
+
+// Routes сохраняет для Metrics шаблон маршрута mux, если вложенный обработчик не сохранил более точный
+func Routes(mux *http.ServeMux) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mux.ServeHTTP(w, r)
+		if state, ok := r.Context().Value(metricsKey{}).(*requestMetrics); ok && state.route == "" && r.Pattern != "" {
+			state.route = r.Pattern
+		}
+	})
+}
+
+// rateLimited учитывает запрос, отклоненный лимитом Gateway, если метрики включены
+func rateLimited(ctx context.Context) {
+	if state, ok := ctx.Value(metricsKey{}).(*requestMetrics); ok {
+		state.registry.RateLimited(telemetry.LayerHTTP, telemetry.LimiterGateway)
+	}
+}
@@ -7,22 +7,41 @@ import (
 	"golang.org/x/time/rate"
 )
 
-// RateLimit ограничивает количество запросов (rate limiting)
-// rps - запросов в секунду, burst - разрешает кратковременные всплески
-func RateLimit(next http.Handler, rps int, burst int) http.Handler {
-	// Значения по умолчанию если не указаны
+// RateLimiter общий лимит запросов Gateway; Set меняет его без перезапуска
+type RateLimiter struct {
+	limiter *rate.Limiter
+}
+
+// NewRateLimiter создает лимит rps запросов в секунду с кратковременными всплесками до burst запросов
+func NewRateLimiter(rps int, burst int) *RateLimiter {
+	rps, burst = rateLimitDefaults(rps, burst)
+	return &RateLimiter{limiter: rate.NewLimiter(rate.Limit(rps), burst)}
+}
+
+// Set меняет лимит для следующих запросов
+func (l *RateLimiter) Set(rps int, burst int) {
+	rps, burst = rateLimitDefaults(rps, burst)
+	l.limiter.SetLimit(rate.Limit(rps))
+	l.limiter.SetBurst(burst)
+}
+
+// rateLimitDefaults подставляет значения по умолчанию, если они не указаны
+func rateLimitDefaults(rps int, burst int) (int, int) {
 	if rps <= 0 {
 		rps = 100
 	}
 	if burst <= 0 {
 		burst = 10
 	}
+	return rps, burst
+}
 
-	limiter := rate.NewLimiter(rate.Limit(rps), burst)
-
+// RateLimit ограничивает количество запросов (rate limiting) лимитом limiter
+func RateLimit(next http.Handler, limiter *RateLimiter) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		if !limiter.Allow() {
+		if !limiter.limiter.Allow() {
 			log.Printf("[HTTP] Rate limit exceeded for %s from %s", r.URL.Path, r.RemoteAddr)
+			rateLimited(r.Context())
 			http.Error(w, "Too Many Requests", http.StatusTooManyRequests)
 			return
 		}
@@ -0,0 +1,539 @@
+package swagger
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"buf.build/gen/go/bufbuild/protovalidate/protocolbuffers/go/buf/validate"
+	"google.golang.org/genproto/googleapis/api/annotations"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// Examples примеры тел запросов: operationId операции swagger.json -> JSON тело запроса
+type Examples map[string]json.RawMessage
+
+// exampleTime время в примерах полей google.protobuf.Timestamp (в будущем, чтобы проходить проверки gt_now)
+const exampleTime = "2030-01-01T09:00:00Z"
+
+// maxExampleDepth глубина вложенных сообщений, после которой поля-сообщения не заполняются
+const maxExampleDepth = 5
+
+// BuildExamples строит примеры тел запросов для HTTP привязок (google.api.http) методов сервисов files
+// Значения полей - значения proto по умолчанию, измененные так, чтобы проходить правила buf.validate:
+// строки получают заполнитель нужной длины или формата (uuid, email, uri), числа - ближайшее допустимое
+// значение, перечисления - первое допустимое значение, повторяемые поля - min_items элементов.
+// Подсказки example из правил имеют приоритет. Правила pattern и CEL выражения не учитываются
+//
+// Ключи совпадают с operationId protoc-gen-openapiv2: Service_Method, для дополнительных привязок
+// к имени добавляется номер привязки начиная с 2 (NotesService_UpdateNote2)
+func BuildExamples(files ...protoreflect.FileDescriptor) (Examples, error) {
+	examples := make(Examples)
+	for _, file := range files {
+		services := file.Services()
+		for i := 0; i < services.Len(); i++ {
+			service := services.Get(i)
+			methods := service.Methods()
+			for j := 0; j < methods.Len(); j++ {
+				method := methods.Get(j)
+				rule, ok := proto.GetExtension(method.Options(), annotations.E_Http).(*annotations.HttpRule)
+				if !ok || rule == nil {
+					continue
+				}
+				bindings := append([]*annotations.HttpRule{rule}, rule.GetAdditionalBindings()...)
+				for k, binding := range bindings {
+					body, ok := bodyExample(method.Input(), binding)
+					if !ok {
+						continue
+					}
+					data, err := json.Marshal(body)
+					if err != nil {
+						return nil, fmt.Errorf("failed to marshal example of %s: %w", method.FullName(), err)
+					}
+					operationID := string(service.Name()) + "_" + string(method.Name())
+					if k > 0 {
+						operationID += strconv.Itoa(k + 1)
+					}
+					examples[operationID] = data
+				}
+			}
+		}
+	}
+	return examples, nil
+}
+
+// bodyExample возвращает пример тела запроса привязки; false - у привязки нет тела
+// body "*" - сообщение запроса без параметров пути, иначе - поле сообщения с этим именем
+func bodyExample(input protoreflect.MessageDescriptor, binding *annotations.HttpRule) (any, bool) {
+	switch body := binding.GetBody(); body {
+	case "":
+		return nil, false
+	case "*":
+		return messageExample(input, pathParams(binding), 0), true
+	default:
+		field := input.Fields().ByName(protoreflect.Name(body))
+		if field == nil {
+			return nil, false
+		}
+		value, ok := fieldExample(field, 0)
+		return value, ok
+	}
+}
+
+// pathParams возвращает имена полей верхнего уровня из шаблона пути привязки ({id}, {note_id=*})
+func pathParams(binding *annotations.HttpRule) map[protoreflect.Name]bool {
+	var path string
+	switch pattern := binding.GetPattern().(type) {
+	case *annotations.HttpRule_Get:
+		path = pattern.Get
+	case *annotations.HttpRule_Put:
+		path = pattern.Put
+	case *annotations.HttpRule_Post:
+		path = pattern.Post
+	case *annotations.HttpRule_Delete:
+		path = pattern.Delete
+	case *annotations.HttpRule_Patch:
+		path = pattern.Patch
+	case *annotations.HttpRule_Custom:
+		path = pattern.Custom.GetPath()
+	}
+
+	params := make(map[protoreflect.Name]bool)
+	for _, segment := range strings.Split(path, "{")[1:] {
+		name, _, _ := strings.Cut(segment, "}")
+		name, _, _ = strings.Cut(name, "=")
+		name, _, _ = strings.Cut(name, ".")
+		params[protoreflect.Name(name)] = true
+	}
+	return params
+}
+
+// object JSON объект, сохраняющий порядок полей proto
+type object []member
+
+type member struct {
+	name  string
+	value any
+}
+
+func (o object) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, m := range o {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		name, err := json.Marshal(m.name)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(m.value)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(name)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// messageExample возвращает пример сообщения без полей skip
+// Из каждого oneof заполняется только первое поле
+func messageExample(msg protoreflect.MessageDescriptor, skip map[protoreflect.Name]bool, depth int) object {
+	example := object{}
+	oneofs := make(map[protoreflect.FullName]bool)
+	fields := msg.Fields()
+	for i := 0; i < fields.Len(); i++ {
+		field := fields.Get(i)
+		if skip[field.Name()] {
+			continue
+		}
+		if oneof := field.ContainingOneof(); oneof != nil && !oneof.IsSynthetic() {
+			if oneofs[oneof.FullName()] {
+				continue
+			}
+			oneofs[oneof.FullName()] = true
+		}
+		if value, ok := fieldExample(field, depth); ok {
+			example = append(example, member{name: string(field.Name()), value: value})
+		}
+	}
+	return example
+}
+
+// fieldExample возвращает пример значения поля; false - поле не заполняется
+func fieldExample(field protoreflect.FieldDescriptor, depth int) (any, bool) {
+	rules := fieldRules(field)
+	switch {
+	case field.IsMap():
+		key, _ := singularExample(field.MapKey(), rules.GetMap().GetKeys(), 0, depth)
+		value, ok := singularExample(field.MapValue(), rules.GetMap().GetValues(), 0, depth)
+		if !ok {
+			return nil, false
+		}
+		return object{{name: fmt.Sprint(key), value: value}}, true
+	case field.IsList():
+		count := max(rules.GetRepeated().GetMinItems(), 1)
+		if rules.GetRepeated().HasMaxItems() {
+			count = min(count, rules.GetRepeated().GetMaxItems())
+		}
+		items := make([]any, 0, count)
+		for i := range int(count) {
+			item, ok := singularExample(field, rules.GetRepeated().GetItems(), i, depth)
+			if !ok {
+				return nil, false
+			}
+			items = append(items, item)
+		}
+		return items, true
+	default:
+		return singularExample(field, rules, 0, depth)
+	}
+}
+
+// fieldRules возвращает правила buf.validate поля (nil, если правил нет)
+func fieldRules(field protoreflect.FieldDescriptor) *validate.FieldRules {
+	rules, _ := proto.GetExtension(field.Options(), validate.E_Field).(*validate.FieldRules)
+	return rules
+}
+
+// singularExample возвращает пример одного значения поля (элемента списка с номером index)
+func singularExample(field protoreflect.FieldDescriptor, rules *validate.FieldRules, index, depth int) (any, bool) {
+	switch field.Kind() {
+	case protoreflect.StringKind:
+		return stringExample(field, rules.GetString(), index), true
+	case protoreflect.BytesKind:
+		return bytesExample(rules.GetBytes()), true
+	case protoreflect.BoolKind:
+		if len(rules.GetBool().GetExample()) > 0 {
+			return rules.GetBool().GetExample()[0], true
+		}
+		return rules.GetBool().GetConst(), true
+	case protoreflect.EnumKind:
+		return enumExample(field.Enum(), rules.GetEnum()), true
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		return messageFieldExample(field, rules, depth)
+	default:
+		return numberExample(field.Kind(), rules, index), true
+	}
+}
+
+// messageFieldExample возвращает пример поля-сообщения
+// Well-known типы заполняются только для обязательных полей, рекурсивные сообщения ограничены глубиной
+func messageFieldExample(field protoreflect.FieldDescriptor, rules *validate.FieldRules, depth int) (any, bool) {
+	msg := field.Message()
+	required := rules.GetRequired()
+	switch msg.FullName() {
+	case "google.protobuf.Timestamp":
+		return exampleTime, required || rules.GetTimestamp() != nil
+	case "google.protobuf.Duration":
+		return "60s", required || rules.GetDuration() != nil
+	case "google.protobuf.FieldMask":
+		return "", required
+	}
+	if strings.HasPrefix(string(msg.FullName()), "google.") {
+		return object{}, required
+	}
+	if depth >= maxExampleDepth {
+		return object{}, required
+	}
+	return messageExample(msg, nil, depth+1), true
+}
+
+// stringExample возвращает строку, удовлетворяющую правилам: формат, префикс/суффикс и длина
+func stringExample(field protoreflect.FieldDescriptor, rules *validate.StringRules, index int) string {
+	switch {
+	case len(rules.GetExample()) > 0:
+		return rules.GetExample()[0]
+	case rules.HasConst():
+		return rules.GetConst()
+	case len(rules.GetIn()) > 0:
+		return rules.GetIn()[0]
+	case rules.GetUuid():
+		return fmt.Sprintf("00000000-0000-4000-8000-%012d", index+1)
+	case rules.GetTuuid():
+		return fmt.Sprintf("000000000000400080000%011d", index+1)
+	case rules.GetEmail():
+		return fmt.Sprintf("user%d@example.com", index+1)
+	case rules.GetUri(), rules.GetUriRef():
+		return "https://example.com/" + strings.ReplaceAll(string(field.Name()), "_", "-")
+	case rules.GetHostname():
+		return "example.com"
+	case rules.GetIp(), rules.GetIpv4():
+		return fmt.Sprintf("192.0.2.%d", index+1)
+	case rules.GetIpv6():
+		return fmt.Sprintf("2001:db8::%d", index+1)
+	case rules.GetHostAndPort():
+		return "example.com:443"
+	}
+
+	// Заполнитель по имени поля: "example title", элементы списка нумеруются
+	value := "example " + strings.ReplaceAll(string(field.Name()), "_", " ")
+	if index > 0 {
+		value += " " + strconv.Itoa(index+1)
+	}
+	value = rules.GetPrefix() + value + rules.GetContains() + rules.GetSuffix()
+
+	minLen, maxLen := rules.GetMinLen(), rules.GetMaxLen()
+	if rules.HasLen() {
+		minLen, maxLen = rules.GetLen(), rules.GetLen()
+	}
+	if length := uint64(len([]rune(value))); length < minLen {
+		value += strings.Repeat("x", int(minLen-length))
+	}
+	if rules.HasMaxLen() || rules.HasLen() {
+		if runes := []rune(value); uint64(len(runes)) > maxLen {
+			value = string(runes[:maxLen])
+		}
+	}
+	return value
+}
+
+// bytesExample возвращает base64 значения bytes (по умолчанию пустое)
+func bytesExample(rules *validate.BytesRules) string {
+	value := rules.GetConst()
+	switch {
+	case len(rules.GetExample()) > 0:
+		value = rules.GetExample()[0]
+	case rules.HasLen():
+		value = bytes.Repeat([]byte("x"), int(rules.GetLen()))
+	case rules.GetMinLen() > 0:
+		value = bytes.Repeat([]byte("x"), int(rules.GetMinLen()))
+	}
+	return base64.StdEncoding.EncodeToString(value)
+}
+
+// enumExample возвращает имя значения перечисления: значение по умолчанию,
+// если правила его допускают, иначе первое допустимое значение
+func enumExample(enum protoreflect.EnumDescriptor, rules *validate.EnumRules) string {
+	values := enum.Values()
+	number := protoreflect.EnumNumber(rules.GetConst())
+	switch {
+	case len(rules.GetExample()) > 0:
+		number = protoreflect.EnumNumber(rules.GetExample()[0])
+	case rules.HasConst():
+	case len(rules.GetIn()) > 0:
+		number = protoreflect.EnumNumber(rules.GetIn()[0])
+	default:
+		number = values.Get(0).Number()
+		for i := 0; i < values.Len(); i++ {
+			if !containsNumber(rules.GetNotIn(), values.Get(i).Number()) {
+				number = values.Get(i).Number()
+				break
+			}
+		}
+	}
+	if value := values.ByNumber(number); value != nil {
+		return string(value.Name())
+	}
+	return strconv.Itoa(int(number))
+}
+
+func containsNumber(numbers []int32, number protoreflect.EnumNumber) bool {
+	for _, n := range numbers {
+		if protoreflect.EnumNumber(n) == number {
+			return true
+		}
+	}
+	return false
+}
+
+// numberExample возвращает число, ближайшее к 0 (к index для элементов списка),
+// которое проходит правила const, in, gt/gte и lt/lte
+// 64-битные целые возвращаются строкой, как их кодирует protojson
+func numberExample(kind protoreflect.Kind, rules *validate.FieldRules, index int) any {
+	value := float64(index)
+	if numeric := numberRules(rules); numeric != nil {
+		get := func(name protoreflect.Name) (float64, bool) {
+			field := numeric.Descriptor().Fields().ByName(name)
+			if field == nil || !numeric.Has(field) {
+				return 0, false
+			}
+			if field.IsList() {
+				return toFloat(numeric.Get(field).List().Get(0)), true
+			}
+			return toFloat(numeric.Get(field)), true
+		}
+		if example, ok := get("example"); ok {
+			value = example
+		} else if constant, ok := get("const"); ok {
+			value = constant
+		} else if in, ok := get("in"); ok {
+			value = in
+		} else {
+			if gt, ok := get("gt"); ok && value <= gt {
+				value = gt + 1
+			}
+			if gte, ok := get("gte"); ok && value < gte {
+				value = gte
+			}
+			if lt, ok := get("lt"); ok && value >= lt {
+				value = lt - 1
+			}
+			if lte, ok := get("lte"); ok && value > lte {
+				value = lte
+			}
+		}
+	}
+
+	switch kind {
+	case protoreflect.FloatKind, protoreflect.DoubleKind:
+		return value
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind,
+		protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return strconv.FormatInt(int64(value), 10)
+	default:
+		return int64(value)
+	}
+}
+
+// numberRules возвращает заданные правила числового поля (Int32Rules, DoubleRules и т.д.)
+func numberRules(rules *validate.FieldRules) protoreflect.Message {
+	if rules == nil {
+		return nil
+	}
+	msg := rules.ProtoReflect()
+	field := msg.WhichOneof(msg.Descriptor().Oneofs().ByName("type"))
+	if field == nil || field.Message() == nil {
+		return nil
+	}
+	return msg.Get(field).Message()
+}
+
+func toFloat(value protoreflect.Value) float64 {
+	switch v := value.Interface().(type) {
+	case int32:
+		return float64(v)
+	case int64:
+		return float64(v)
+	case uint32:
+		return float64(v)
+	case uint64:
+		return float64(v)
+	case float32:
+		return float64(v)
+	case float64:
+		return v
+	default:
+		return 0
+	}
+}
+
+// injectExamples добавляет примеры в схемы тел запросов операций спецификации, чтобы
+// "Try it out" Swagger UI начинался с них. Схема с $ref оборачивается в allOf, так как
+// поля рядом с $ref игнорируются. Порядок путей и операций сохраняется
+func injectExamples(spec []byte, examples Examples) ([]byte, error) {
+	return editMember(spec, "paths", func(paths json.RawMessage) (json.RawMessage, error) {
+		return editMembers(paths, func(_ string, operations json.RawMessage) (json.RawMessage, error) {
+			return editMembers(operations, func(_ string, operation json.RawMessage) (json.RawMessage, error) {
+				return injectOperationExample(operation, examples)
+			})
+		})
+	})
+}
+
+// injectOperationExample добавляет пример в схему параметра body операции
+func injectOperationExample(operation json.RawMessage, examples Examples) (json.RawMessage, error) {
+	var op map[string]json.RawMessage
+	if err := json.Unmarshal(operation, &op); err != nil {
+		// Не операция (например, общие parameters пути)
+		return operation, nil
+	}
+	var operationID string
+	if err := json.Unmarshal(op["operationId"], &operationID); err != nil || examples[operationID] == nil {
+		return operation, nil
+	}
+	var params []map[string]json.RawMessage
+	if err := json.Unmarshal(op["parameters"], &params); err != nil {
+		return operation, nil
+	}
+	for _, param := range params {
+		if string(param["in"]) != `"body"` || param["schema"] == nil {
+			continue
+		}
+		schema, err := json.Marshal(map[string]any{
+			"allOf":   []json.RawMessage{param["schema"]},
+			"example": examples[operationID],
+		})
+		if err != nil {
+			return nil, err
+		}
+		param["schema"] = schema
+	}
+	data, err := json.Marshal(params)
+	if err != nil {
+		return nil, err
+	}
+	op["parameters"] = data
+	return json.Marshal(op)
+}
+
+// editMember заменяет значение ключа name JSON объекта data результатом edit
+func editMember(data json.RawMessage, name string, edit func(json.RawMessage) (json.RawMessage, error)) (json.RawMessage, error) {
+	return editMembers(data, func(key string, value json.RawMessage) (json.RawMessage, error) {
+		if key != name {
+			return value, nil
+		}
+		return edit(value)
+	})
+}
+
+// editMembers заменяет значения всех ключей JSON объекта data результатами edit, сохраняя порядок ключей
+func editMembers(data json.RawMessage, edit func(string, json.RawMessage) (json.RawMessage, error)) (json.RawMessage, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if token, err := dec.Token(); err != nil || token != json.Delim('{') {
+		return nil, fmt.Errorf("expected JSON object")
+	}
+
+	var result object
+	for dec.More() {
+		token, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		key, _ := token.(string)
+		var value json.RawMessage
+		if err := dec.Decode(&value); err != nil {
+			return nil, err
+		}
+		if value, err = edit(key, value); err != nil {
+			return nil, err
+		}
+		result = append(result, member{name: key, value: value})
+	}
+	return json.Marshal(result)
+}
+
+// serveExamples добавляет маршруты примеров тел запросов
+//
+// Создает следующие маршруты:
+// - GET /swagger/examples - примеры всех операций (operationId -> тело запроса)
+// - GET /swagger/examples/{operation} - пример тела запроса операции
+func serveExamples(mux *http.ServeMux, examples Examples) {
+	mux.HandleFunc("GET /swagger/examples", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		if err := json.NewEncoder(w).Encode(examples); err != nil {
+			log.Printf("Failed to encode swagger examples: %v", err)
+		}
+	})
+
+	mux.HandleFunc("GET /swagger/examples/{operation}", func(w http.ResponseWriter, r *http.Request) {
+		example, ok := examples[r.PathValue("operation")]
+		if !ok {
+			http.Error(w, "Example not found", http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		w.Write(example)
+	})
+
+	log.Printf("Swagger request examples of %d operations available at /swagger/examples/{operation}", len(examples))
+}
@@ -0,0 +1,148 @@
+package swagger
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+
+	notesv1 "notes-service/pkg/proto/notes/v1"
+
+	"buf.build/go/protovalidate"
+	"google.golang.org/genproto/googleapis/api/annotations"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+func TestBuildExamples_PassValidation(t *testing.T) {
+	validator, err := protovalidate.New()
+	if err != nil {
+		t.Fatalf("protovalidate.New: %v", err)
+	}
+
+	// Пример всего сообщения запроса (вместе с параметрами пути) проходит правила buf.validate
+	services := notesv1.File_proto_notes_v1_notes_proto.Services()
+	for i := 0; i < services.Len(); i++ {
+		methods := services.Get(i).Methods()
+		for j := 0; j < methods.Len(); j++ {
+			method := methods.Get(j)
+			if !proto.HasExtension(method.Options(), annotations.E_Http) {
+				continue
+			}
+			t.Run(string(method.Name()), func(t *testing.T) {
+				data, err := json.Marshal(messageExample(method.Input(), nil, 0))
+				if err != nil {
+					t.Fatalf("marshal: %v", err)
+				}
+				req := dynamicpb.NewMessage(method.Input())
+				if err := protojson.Unmarshal(data, req); err != nil {
+					t.Fatalf("example %s is not a valid %s: %v", data, method.Input().FullName(), err)
+				}
+				if err := validator.Validate(req); err != nil {
+					t.Errorf("example %s fails validation: %v", data, err)
+				}
+			})
+		}
+	}
+}
+
+func TestBuildExamples_MatchSpec(t *testing.T) {
+	examples, err := BuildExamples(notesv1.File_proto_notes_v1_notes_proto)
+	if err != nil {
+		t.Fatalf("BuildExamples: %v", err)
+	}
+
+	var createNote map[string]any
+	if err := json.Unmarshal(examples["NotesService_CreateNote"], &createNote); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if createNote["title"] != "example title" || createNote["content"] != "example content" {
+		t.Errorf("unexpected CreateNote example: %v", createNote)
+	}
+	// Параметр пути не входит в тело запроса
+	if strings.Contains(string(examples["NotesService_UpdateNote2"]), `"id"`) {
+		t.Errorf("UpdateNote2 example contains path parameter: %s", examples["NotesService_UpdateNote2"])
+	}
+
+	// Примеры есть у всех операций спецификации с телом запроса и только у них
+	data, err := os.ReadFile("../../../pkg/api/notes/v1/notes.swagger.json")
+	if err != nil {
+		t.Fatalf("read spec: %v", err)
+	}
+	var spec struct {
+		Paths map[string]map[string]struct {
+			OperationID string `json:"operationId"`
+			Parameters  []struct {
+				In string `json:"in"`
+			} `json:"parameters"`
+		} `json:"paths"`
+	}
+	if err := json.Unmarshal(data, &spec); err != nil {
+		t.Fatalf("unmarshal spec: %v", err)
+	}
+	var want []string
+	for _, operations := range spec.Paths {
+		for _, op := range operations {
+			for _, param := range op.Parameters {
+				if param.In == "body" {
+					want = append(want, op.OperationID)
+				}
+			}
+		}
+	}
+	var got []string
+	for operationID := range examples {
+		got = append(got, operationID)
+	}
+	slices.Sort(want)
+	slices.Sort(got)
+	if !slices.Equal(got, want) {
+		t.Errorf("operations = %v, want %v", got, want)
+	}
+}
+
+func TestServeSwagger_Examples(t *testing.T) {
+	spec := `{"swagger":"2.0","paths":{` +
+		`"/b":{"post":{"operationId":"S_B","parameters":[{"name":"body","in":"body","schema":{"$ref":"#/definitions/B"}}]}},` +
+		`"/a":{"get":{"operationId":"S_A"}}}}`
+	examples := Examples{"S_B": json.RawMessage(`{"name":"example name"}`)}
+
+	injected, err := injectExamples([]byte(spec), examples)
+	if err != nil {
+		t.Fatalf("injectExamples: %v", err)
+	}
+	// Порядок путей сохраняется, схема тела оборачивается в allOf с примером
+	if strings.Index(string(injected), `"/b"`) > strings.Index(string(injected), `"/a"`) {
+		t.Errorf("paths order changed: %s", injected)
+	}
+	want := `"schema":{"allOf":[{"$ref":"#/definitions/B"}],"example":{"name":"example name"}}`
+	if !strings.Contains(string(injected), want) {
+		t.Errorf("spec %s does not contain %s", injected, want)
+	}
+
+	mux := http.NewServeMux()
+	serveExamples(mux, examples)
+	tests := []struct {
+		path     string
+		wantCode int
+		wantBody string
+	}{
+		{path: "/swagger/examples/S_B", wantCode: http.StatusOK, wantBody: `{"name":"example name"}`},
+		{path: "/swagger/examples", wantCode: http.StatusOK, wantBody: `{"S_B":{"name":"example name"}}`},
+		{path: "/swagger/examples/S_A", wantCode: http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.path, nil))
+		if rec.Code != tt.wantCode {
+			t.Errorf("%s: status = %d, want %d", tt.path, rec.Code, tt.wantCode)
+		}
+		if tt.wantBody != "" && strings.TrimSpace(rec.Body.String()) != tt.wantBody {
+			t.Errorf("%s: body = %s, want %s", tt.path, rec.Body.String(), tt.wantBody)
+		}
+	}
+}
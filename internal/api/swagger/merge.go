@@ -0,0 +1,141 @@
+package swagger
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// ServiceSpec swagger 2.0 спецификация одного сервиса для MergeSpecs
+type ServiceSpec struct {
+	Name       string // Полное имя сервиса, становится тегом его операций
+	Spec       []byte // Содержимое swagger.json
+	PathPrefix string // Префикс путей сервиса (добавляется к basePath спецификации)
+}
+
+// definitionRefPrefix префикс ссылок на определения в swagger 2.0
+const definitionRefPrefix = "#/definitions/"
+
+// MergeSpecs объединяет спецификации нескольких сервисов в одну
+//
+// - пути получают префикс PathPrefix и basePath исходной спецификации
+// - операции помечаются тегом с именем сервиса, чтобы Swagger UI группировал их по сервисам
+// - одинаковые определения (definitions) сохраняются один раз; разные определения с
+// одинаковым именем переименовываются в <сервис>_<имя> вместе со ссылками на них
+//
+// Одинаковые метод и путь в двух сервисах считаются ошибкой
+func MergeSpecs(title string, specs []ServiceSpec) ([]byte, error) {
+	paths := make(map[string]map[string]any)
+	definitions := make(map[string]any)
+	securityDefinitions := make(map[string]any)
+	tags := make([]any, 0, len(specs))
+
+	for _, service := range specs {
+		var spec map[string]any
+		if err := json.Unmarshal(service.Spec, &spec); err != nil {
+			return nil, fmt.Errorf("invalid swagger spec of %s: %w", service.Name, err)
+		}
+
+		// Конфликтующие определения переименовываются до слияния путей, чтобы обновить ссылки
+		renames := make(map[string]string)
+		serviceDefinitions, _ := spec["definitions"].(map[string]any)
+		for name, definition := range serviceDefinitions {
+			if existing, ok := definitions[name]; ok && !reflect.DeepEqual(existing, definition) {
+				renames[name] = definitionPrefix(service.Name) + "_" + name
+			}
+		}
+		if len(renames) > 0 {
+			spec = renameRefs(spec, renames).(map[string]any)
+			serviceDefinitions, _ = spec["definitions"].(map[string]any)
+		}
+		for name, definition := range serviceDefinitions {
+			if renamed, ok := renames[name]; ok {
+				name = renamed
+			}
+			definitions[name] = definition
+		}
+
+		for name, definition := range asMap(spec["securityDefinitions"]) {
+			if _, ok := securityDefinitions[name]; !ok {
+				securityDefinitions[name] = definition
+			}
+		}
+
+		basePath, _ := spec["basePath"].(string)
+		prefix := strings.TrimSuffix(service.PathPrefix, "/") + strings.TrimSuffix(basePath, "/")
+		for path, item := range asMap(spec["paths"]) {
+			fullPath := prefix + path
+			merged, ok := paths[fullPath]
+			if !ok {
+				merged = make(map[string]any)
+				paths[fullPath] = merged
+			}
+			for method, operation := range asMap(item) {
+				if _, exists := merged[method]; exists {
+					return nil, fmt.Errorf("duplicate operation %s %s in %s", strings.ToUpper(method), fullPath, service.Name)
+				}
+				if op, ok := operation.(map[string]any); ok {
+					op["tags"] = []any{service.Name}
+				}
+				merged[method] = operation
+			}
+		}
+
+		tag := map[string]any{"name": service.Name}
+		if info := asMap(spec["info"]); info["title"] != nil {
+			tag["description"] = info["title"]
+		}
+		tags = append(tags, tag)
+	}
+
+	merged := map[string]any{
+		"swagger":     "2.0",
+		"info":        map[string]any{"title": title, "version": "version not set"},
+		"tags":        tags,
+		"consumes":    []string{"application/json"},
+		"produces":    []string{"application/json"},
+		"paths":       paths,
+		"definitions": definitions,
+	}
+	if len(securityDefinitions) > 0 {
+		merged["securityDefinitions"] = securityDefinitions
+	}
+
+	return json.Marshal(merged)
+}
+
+// renameRefs заменяет ссылки $ref на переименованные определения и ключи самих определений
+func renameRefs(value any, renames map[string]string) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, item := range v {
+			if ref, ok := item.(string); ok && key == "$ref" && strings.HasPrefix(ref, definitionRefPrefix) {
+				if renamed, ok := renames[strings.TrimPrefix(ref, definitionRefPrefix)]; ok {
+					v[key] = definitionRefPrefix + renamed
+				}
+				continue
+			}
+			v[key] = renameRefs(item, renames)
+		}
+		return v
+	case []any:
+		for i, item := range v {
+			v[i] = renameRefs(item, renames)
+		}
+		return v
+	default:
+		return value
+	}
+}
+
+// definitionPrefix возвращает префикс определений сервиса: имя без точек, например billingv1BillingService
+func definitionPrefix(service string) string {
+	return strings.ReplaceAll(service, ".", "")
+}
+
+// asMap приводит значение JSON объекта к map, для других значений возвращает nil
+func asMap(value any) map[string]any {
+	m, _ := value.(map[string]any)
+	return m
+}
@@ -0,0 +1,87 @@
+package swagger
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+const notesSpec = `{
+  "swagger": "2.0",
+  "info": {"title": "Notes"},
+  "paths": {
+    "/notes/v1": {"get": {"operationId": "NotesService_ListNotes", "tags": ["NotesService"],
+      "responses": {"200": {"schema": {"$ref": "#/definitions/v1Note"}}}}}
+  },
+  "definitions": {
+    "v1Note": {"type": "object", "properties": {"id": {"type": "string"}}},
+    "rpcStatus": {"type": "object", "properties": {"code": {"type": "integer"}}}
+  }
+}`
+
+const billingSpec = `{
+  "swagger": "2.0",
+  "info": {"title": "Billing"},
+  "basePath": "/billing",
+  "paths": {
+    "/v1/invoices": {"get": {"operationId": "BillingService_ListInvoices", "tags": ["BillingService"],
+      "responses": {"200": {"schema": {"$ref": "#/definitions/v1Note"}}}}}
+  },
+  "definitions": {
+    "v1Note": {"type": "object", "properties": {"amount": {"type": "integer"}}},
+    "rpcStatus": {"type": "object", "properties": {"code": {"type": "integer"}}}
+  }
+}`
+
+func TestMergeSpecs(t *testing.T) {
+	data, err := MergeSpecs("All", []ServiceSpec{
+		{Name: "notes.v1.NotesService", Spec: []byte(notesSpec)},
+		{Name: "billing.v1.BillingService", Spec: []byte(billingSpec), PathPrefix: "/ext"},
+	})
+	if err != nil {
+		t.Fatalf("MergeSpecs: %v", err)
+	}
+
+	var merged struct {
+		Paths       map[string]map[string]map[string]any `json:"paths"`
+		Definitions map[string]any                       `json:"definitions"`
+		Tags        []map[string]any                     `json:"tags"`
+	}
+	if err := json.Unmarshal(data, &merged); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+
+	// Пути upstream сервиса получают префикс и basePath
+	invoices, ok := merged.Paths["/ext/billing/v1/invoices"]["get"]
+	if !ok {
+		t.Fatalf("expected prefixed billing path, got paths %v", merged.Paths)
+	}
+	if tags := invoices["tags"].([]any); len(tags) != 1 || tags[0] != "billing.v1.BillingService" {
+		t.Errorf("expected operation tagged by service, got %v", tags)
+	}
+
+	// Одинаковые определения не дублируются, разные с одним именем переименовываются
+	if len(merged.Definitions) != 3 {
+		t.Errorf("expected 3 definitions, got %d", len(merged.Definitions))
+	}
+	if _, ok := merged.Definitions["billingv1BillingService_v1Note"]; !ok {
+		t.Errorf("expected conflicting definition to be renamed, got %v", merged.Definitions)
+	}
+	if !strings.Contains(string(data), `"#/definitions/billingv1BillingService_v1Note"`) {
+		t.Error("expected billing refs to point to the renamed definition")
+	}
+
+	if len(merged.Tags) != 2 || merged.Tags[1]["description"] != "Billing" {
+		t.Errorf("unexpected tags: %v", merged.Tags)
+	}
+}
+
+func TestMergeSpecs_DuplicateOperation(t *testing.T) {
+	_, err := MergeSpecs("All", []ServiceSpec{
+		{Name: "a.Service", Spec: []byte(notesSpec)},
+		{Name: "b.Service", Spec: []byte(notesSpec)},
+	})
+	if err == nil {
+		t.Fatal("expected error for duplicate operation")
+	}
+}
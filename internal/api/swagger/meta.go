@@ -0,0 +1,99 @@
+package swagger
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"log"
+	"net/http"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// MetaFileName имя файла метаданных спецификации рядом с notes.swagger.json
+const MetaFileName = "notes.swagger.meta.json"
+
+// SpecMeta метаданные сгенерированной спецификации (записываются cmd/specmeta)
+type SpecMeta struct {
+	GeneratedAt time.Time `json:"generated_at"` // Время генерации спецификации
+	ProtoHash   string    `json:"proto_hash"`   // Хэш proto дескрипторов, из которых сгенерирована спецификация
+}
+
+// metaResponse ответ GET /swagger/meta
+type metaResponse struct {
+	GeneratedAt      *time.Time `json:"generated_at,omitempty"`
+	ProtoHash        string     `json:"proto_hash,omitempty"`
+	CurrentProtoHash string     `json:"current_proto_hash"`
+	ServiceVersion   string     `json:"service_version"`
+	Stale            bool       `json:"stale"` // Спецификация не соответствует скомпилированным proto
+}
+
+// ProtoHash возвращает хэш proto файлов по их дескрипторам
+// Дескрипторы сериализуются детерминированно, поэтому хэш меняется только вместе с proto
+func ProtoHash(files ...protoreflect.FileDescriptor) (string, error) {
+	h := sha256.New()
+	for _, file := range files {
+		data, err := proto.MarshalOptions{Deterministic: true}.Marshal(protodesc.ToFileDescriptorProto(file))
+		if err != nil {
+			return "", fmt.Errorf("failed to marshal descriptor %s: %w", file.Path(), err)
+		}
+		h.Write(data)
+	}
+	return "sha256:" + hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// ReadSpecMeta читает метаданные спецификации из swaggerSpecs
+func ReadSpecMeta(swaggerSpecs fs.FS) (SpecMeta, error) {
+	var data []byte
+	var err error
+	for _, path := range []string{MetaFileName, "swagger-specs/" + MetaFileName} {
+		if data, err = fs.ReadFile(swaggerSpecs, path); err == nil {
+			break
+		}
+	}
+	if err != nil {
+		return SpecMeta{}, err
+	}
+
+	var meta SpecMeta
+	if err := json.Unmarshal(data, &meta); err != nil {
+		return SpecMeta{}, fmt.Errorf("invalid %s: %w", MetaFileName, err)
+	}
+	return meta, nil
+}
+
+// ServeSpecMeta добавляет маршрут GET /swagger/meta с метаданными встроенной спецификации
+// и предупреждает в логе, если спецификация устарела относительно protoHash
+// protoHash - ProtoHash скомпилированных в сервер proto, version - версия сервиса
+func ServeSpecMeta(mux *http.ServeMux, swaggerSpecs fs.FS, protoHash, version string) {
+	resp := metaResponse{CurrentProtoHash: protoHash, ServiceVersion: version}
+
+	meta, err := ReadSpecMeta(swaggerSpecs)
+	switch {
+	case err != nil:
+		resp.Stale = true
+		log.Printf("⚠️  Warning: swagger spec metadata is not available (%v), the embedded spec may be stale", err)
+	case meta.ProtoHash != protoHash:
+		resp.Stale = true
+		log.Printf("⚠️  Warning: embedded swagger spec is stale: generated from %s at %s, compiled proto is %s",
+			meta.ProtoHash, meta.GeneratedAt.Format(time.RFC3339), protoHash)
+	}
+	if err == nil {
+		resp.GeneratedAt = &meta.GeneratedAt
+		resp.ProtoHash = meta.ProtoHash
+	}
+
+	mux.HandleFunc("GET /swagger/meta", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		if err := json.NewEncoder(w).Encode(resp); err != nil {
+			log.Printf("Failed to encode swagger meta: %v", err)
+		}
+	})
+
+	log.Println("Swagger spec metadata available at /swagger/meta")
+}
@@ -0,0 +1,67 @@
+package swagger
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	notesv1 "notes-service/pkg/proto/notes/v1"
+)
+
+func TestProtoHash_Stable(t *testing.T) {
+	first, err := ProtoHash(notesv1.File_proto_notes_v1_notes_proto)
+	if err != nil {
+		t.Fatalf("ProtoHash: %v", err)
+	}
+	second, err := ProtoHash(notesv1.File_proto_notes_v1_notes_proto)
+	if err != nil {
+		t.Fatalf("ProtoHash: %v", err)
+	}
+	if first != second {
+		t.Errorf("hash is not stable: %s != %s", first, second)
+	}
+}
+
+func TestServeSpecMeta(t *testing.T) {
+	generatedAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	metaJSON, _ := json.Marshal(SpecMeta{GeneratedAt: generatedAt, ProtoHash: "sha256:abc"})
+	specs := fstest.MapFS{MetaFileName: {Data: metaJSON}}
+
+	tests := []struct {
+		name      string
+		protoHash string
+		specs     fstest.MapFS
+		wantStale bool
+	}{
+		{name: "up to date", protoHash: "sha256:abc", specs: specs},
+		{name: "stale", protoHash: "sha256:def", specs: specs, wantStale: true},
+		{name: "no metadata", protoHash: "sha256:abc", specs: fstest.MapFS{}, wantStale: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mux := http.NewServeMux()
+			ServeSpecMeta(mux, tt.specs, tt.protoHash, "v1.0.0")
+
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/swagger/meta", nil))
+			if rec.Code != http.StatusOK {
+				t.Fatalf("status = %d, want 200", rec.Code)
+			}
+
+			var resp metaResponse
+			if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+				t.Fatalf("decode: %v", err)
+			}
+			if resp.Stale != tt.wantStale {
+				t.Errorf("stale = %v, want %v", resp.Stale, tt.wantStale)
+			}
+			if resp.ServiceVersion != "v1.0.0" || resp.CurrentProtoHash != tt.protoHash {
+				t.Errorf("unexpected response: %+v", resp)
+			}
+		})
+	}
+}
@@ -2,23 +2,34 @@ package swagger
 
 import (
 	"embed"
+	"encoding/json"
 	"io/fs"
 	"log"
 	"net/http"
+	"os"
+	"sort"
+	"strings"
 )
 
 //go:embed embed/*
 var swaggerContent embed.FS
 
+// notesServiceName имя основного сервиса в списке спецификаций и объединенной спецификации
+const notesServiceName = "notes.v1.NotesService"
+
 // ServeSwagger добавляет маршруты для Swagger UI и swagger.json в указанный mux
 // swaggerSpecs - embedded файловая система со swagger.json файлом (например, из pkg/api/notes/v1/)
+// upstreamSpecs - соответствие полного имени дополнительного сервиса Gateway пути к его swagger.json на диске
+// examples - примеры тел запросов основного сервиса (см. BuildExamples), добавляются в схемы swagger.json
 // Эта функция может быть переиспользована в разных проектах
 //
 // Создает следующие маршруты:
 // - GET /swagger/ - статические файлы Swagger UI (dist/, index.html)
-// - GET /swagger.json - основной swagger.json файл из swaggerSpecs
+// - GET /swagger.json - основной swagger.json; при наличии upstreamSpecs - объединенная спецификация всех сервисов
 // - GET /swagger/specs/ - дополнительные swagger.json файлы из swaggerSpecs
-func ServeSwagger(mux *http.ServeMux, swaggerSpecs embed.FS) {
+// - GET /swagger/upstreams/{name}, GET /swagger/urls.json - см. serveUpstreamSpecs
+// - GET /swagger/examples/{operation} - см. serveExamples
+func ServeSwagger(mux *http.ServeMux, swaggerSpecs embed.FS, upstreamSpecs map[string]string, examples Examples) {
 	// Получаем встроенные файлы Swagger UI
 	swaggerUI, err := fs.Sub(swaggerContent, "embed")
 	if err != nil {
@@ -45,8 +56,25 @@ func ServeSwagger(mux *http.ServeMux, swaggerSpecs embed.FS) {
 		swaggerStaticsHandler.ServeHTTP(w, r)
 	})
 
+	// Спецификация основного сервиса, при наличии дополнительных сервисов - объединенная
+	notesJSON, notesErr := readMainSpec(swaggerSpecs)
+	if notesErr == nil && len(examples) > 0 {
+		if withExamples, err := injectExamples(notesJSON, examples); err != nil {
+			log.Printf("⚠️  Failed to add request examples to swagger spec: %v", err)
+		} else {
+			notesJSON = withExamples
+		}
+	}
+	swaggerJSON, merged := notesJSON, false
+	if notesErr == nil && len(upstreamSpecs) > 0 {
+		if mergedJSON, err := mergeUpstreamSpecs(notesJSON, upstreamSpecs); err != nil {
+			log.Printf("⚠️  Failed to merge swagger specs, serving %s spec only: %v", notesServiceName, err)
+		} else {
+			swaggerJSON, merged = mergedJSON, true
+		}
+	}
+
 	// Основной эндпоинт для swagger.json (для обратной совместимости с index.html)
-	// Ищем notes.swagger.json в корне swaggerSpecs
 	// Функция-обработчик для swagger.json (поддерживает GET и OPTIONS для CORS)
 	swaggerJSONHandler := func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Access-Control-Allow-Origin", "*")
@@ -58,40 +86,9 @@ func ServeSwagger(mux *http.ServeMux, swaggerSpecs embed.FS) {
 			return
 		}
 
-		// Пытаемся найти swagger.json файл в swaggerSpecs
-		// Пробуем разные варианты путей
-		var swaggerJSON []byte
-		var err error
-
-		paths := []string{"notes.swagger.json", "swagger-specs/notes.swagger.json"}
-		for _, path := range paths {
-			swaggerJSON, err = swaggerSpecs.ReadFile(path)
-			if err == nil {
-				break
-			}
-		}
-
-		if err != nil {
-			// Если не найден, пробуем найти первый .json файл в любом месте
-			entries, err := fs.ReadDir(swaggerSpecs, ".")
-			if err != nil {
-				http.Error(w, "Swagger specs not found", http.StatusNotFound)
-				return
-			}
-			found := false
-			for _, entry := range entries {
-				if !entry.IsDir() && len(entry.Name()) > 5 && entry.Name()[len(entry.Name())-5:] == ".json" {
-					swaggerJSON, err = swaggerSpecs.ReadFile(entry.Name())
-					if err == nil {
-						found = true
-						break
-					}
-				}
-			}
-			if !found {
-				http.Error(w, "Swagger JSON not found", http.StatusNotFound)
-				return
-			}
+		if notesErr != nil {
+			http.Error(w, "Swagger JSON not found", http.StatusNotFound)
+			return
 		}
 
 		w.Header().Set("Content-Type", "application/json; charset=utf-8")
@@ -102,7 +99,115 @@ func ServeSwagger(mux *http.ServeMux, swaggerSpecs embed.FS) {
 	mux.HandleFunc("GET /swagger.json", swaggerJSONHandler)
 	mux.HandleFunc("OPTIONS /swagger.json", swaggerJSONHandler)
 
+	serveUpstreamSpecs(mux, notesJSON, upstreamSpecs, merged)
+	serveExamples(mux, examples)
+
 	log.Println("Swagger UI enabled at /swagger/")
-	log.Println("Swagger JSON available at /swagger.json")
+	if merged {
+		log.Printf("Swagger JSON of all %d services available at /swagger.json", len(upstreamSpecs)+1)
+	} else {
+		log.Println("Swagger JSON available at /swagger.json")
+	}
 	log.Println("Swagger specs available at /swagger/specs/")
 }
+
+// readMainSpec ищет основной swagger.json в swaggerSpecs
+// Пробует известные пути, затем берет первый .json файл в корне
+func readMainSpec(swaggerSpecs fs.FS) ([]byte, error) {
+	for _, path := range []string{"notes.swagger.json", "swagger-specs/notes.swagger.json"} {
+		if swaggerJSON, err := fs.ReadFile(swaggerSpecs, path); err == nil {
+			return swaggerJSON, nil
+		}
+	}
+
+	entries, err := fs.ReadDir(swaggerSpecs, ".")
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.HasSuffix(entry.Name(), ".json") && entry.Name() != MetaFileName {
+			return fs.ReadFile(swaggerSpecs, entry.Name())
+		}
+	}
+
+	return nil, fs.ErrNotExist
+}
+
+// mergeUpstreamSpecs объединяет спецификацию основного сервиса со спецификациями upstream сервисов
+func mergeUpstreamSpecs(notesJSON []byte, upstreamSpecs map[string]string) ([]byte, error) {
+	specs := []ServiceSpec{{Name: notesServiceName, Spec: notesJSON}}
+	for _, name := range sortedNames(upstreamSpecs) {
+		spec, err := os.ReadFile(upstreamSpecs[name])
+		if err != nil {
+			return nil, err
+		}
+		specs = append(specs, ServiceSpec{Name: name, Spec: spec})
+	}
+
+	return MergeSpecs("Notes service API", specs)
+}
+
+// specURL элемент списка спецификаций Swagger UI (параметр urls)
+type specURL struct {
+	URL  string `json:"url"`
+	Name string `json:"name"`
+}
+
+// serveUpstreamSpecs добавляет спецификации дополнительных сервисов Gateway
+// specs - соответствие полного имени сервиса пути к его swagger.json на диске
+// merged - /swagger.json содержит объединенную спецификацию, тогда спецификация
+// основного сервиса отдельно доступна по /swagger/notes.json
+//
+// Создает следующие маршруты:
+// - GET /swagger/upstreams/{name} - swagger.json сервиса name
+// - GET /swagger/urls.json - список всех спецификаций для выпадающего списка Swagger UI
+func serveUpstreamSpecs(mux *http.ServeMux, notesJSON []byte, specs map[string]string, merged bool) {
+	urls := []specURL{{URL: "/swagger.json", Name: notesServiceName}}
+	if merged {
+		urls = []specURL{
+			{URL: "/swagger.json", Name: "All services"},
+			{URL: "/swagger/notes.json", Name: notesServiceName},
+		}
+
+		mux.HandleFunc("GET /swagger/notes.json", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json; charset=utf-8")
+			w.Write(notesJSON)
+		})
+	}
+
+	names := sortedNames(specs)
+	for _, name := range names {
+		urls = append(urls, specURL{URL: "/swagger/upstreams/" + name, Name: name})
+	}
+
+	mux.HandleFunc("GET /swagger/upstreams/{name}", func(w http.ResponseWriter, r *http.Request) {
+		specPath, ok := specs[r.PathValue("name")]
+		if !ok {
+			http.Error(w, "Swagger spec not found", http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		http.ServeFile(w, r, specPath)
+	})
+
+	mux.HandleFunc("GET /swagger/urls.json", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		if err := json.NewEncoder(w).Encode(urls); err != nil {
+			log.Printf("Failed to encode swagger urls: %v", err)
+		}
+	})
+
+	for _, name := range names {
+		log.Printf("Swagger spec for upstream %s available at /swagger/upstreams/%s", name, name)
+	}
+}
+
+// sortedNames возвращает имена сервисов в порядке возрастания
+func sortedNames(specs map[string]string) []string {
+	names := make([]string, 0, len(specs))
+	for name := range specs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
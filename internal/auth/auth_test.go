@@ -0,0 +1,435 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// signHS256 выпускает JWT с подписью HS256
+func signHS256(t *testing.T, secret []byte, claims map[string]any) string {
+	t.Helper()
+	input := jwtSigningInput(t, "HS256", claims)
+	mac := hmac.New(crypto.SHA256.New, secret)
+	mac.Write([]byte(input))
+	return input + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func jwtSigningInput(t *testing.T, alg string, claims map[string]any) string {
+	t.Helper()
+	header, err := json.Marshal(map[string]string{"alg": alg, "typ": "JWT"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+}
+
+func TestChain_FallsThroughUnrecognizedTokens(t *testing.T) {
+	failing := authenticatorFunc(func(context.Context, string) (Principal, error) {
+		return Principal{}, errors.New("endpoint unavailable")
+	})
+	chain := Chain{DemoTokens(), failing, StaticTokens{"other": {UserID: "bob"}}}
+
+	principal, err := chain.Authenticate(context.Background(), "my-secret-token")
+	if err != nil || principal.UserID != "demo" {
+		t.Errorf("Expected demo from the first provider, got %+v, %v", principal, err)
+	}
+
+	// Недоступный провайдер не мешает следующему принять токен
+	principal, err = chain.Authenticate(context.Background(), "other")
+	if err != nil || principal.UserID != "bob" {
+		t.Errorf("Expected bob from the last provider, got %+v, %v", principal, err)
+	}
+
+	// Если токен не принял никто, возвращается ошибка недоступного провайдера, а не ErrInvalidToken
+	if _, err := chain.Authenticate(context.Background(), "unknown"); err == nil || errors.Is(err, ErrInvalidToken) {
+		t.Errorf("Expected provider failure, got %v", err)
+	}
+	if _, err := (Chain{DemoTokens()}).Authenticate(context.Background(), "unknown"); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("Expected ErrInvalidToken, got %v", err)
+	}
+}
+
+func TestParseBearer(t *testing.T) {
+	if token, ok := ParseBearer("Bearer abc"); !ok || token != "abc" {
+		t.Errorf("Expected abc, got %q, %v", token, ok)
+	}
+	for _, header := range []string{"", "Bearer ", "Basic abc", "bearer abc"} {
+		if _, ok := ParseBearer(header); ok {
+			t.Errorf("Expected %q to be rejected", header)
+		}
+	}
+}
+
+func TestJWTAuthenticator_HMAC(t *testing.T) {
+	secret := []byte("test-secret")
+	now := time.Unix(1_700_000_000, 0)
+	authenticator, err := NewJWTAuthenticator(JWTConfig{
+		HMACSecret: secret,
+		Issuer:     "https://issuer.example",
+		Audience:   "notes",
+		Claims:     ClaimMapping{RolesClaim: "groups"},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	authenticator.now = func() time.Time { return now }
+
+	valid := map[string]any{
+		"sub":    "alice",
+		"iss":    "https://issuer.example",
+		"aud":    []string{"notes", "billing"},
+		"exp":    now.Add(time.Hour).Unix(),
+		"groups": []string{"admin"},
+	}
+	principal, err := authenticator.Authenticate(context.Background(), signHS256(t, secret, valid))
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if principal.UserID != "alice" || !slices.Equal(principal.Roles, []string{RoleUser, RoleAdmin}) {
+		t.Errorf("Expected alice with user and admin roles, got %+v", principal)
+	}
+
+	with := func(key string, value any) map[string]any {
+		claims := make(map[string]any, len(valid))
+		for k, v := range valid {
+			claims[k] = v
+		}
+		if value == nil {
+			delete(claims, key)
+		} else {
+			claims[key] = value
+		}
+		return claims
+	}
+	rejected := map[string]string{
+		"expired":        signHS256(t, secret, with("exp", now.Add(-time.Minute).Unix())),
+		"not yet valid":  signHS256(t, secret, with("nbf", now.Add(time.Minute).Unix())),
+		"wrong issuer":   signHS256(t, secret, with("iss", "https://evil.example")),
+		"wrong audience": signHS256(t, secret, with("aud", "billing")),
+		"no subject":     signHS256(t, secret, with("sub", nil)),
+		"wrong secret":   signHS256(t, []byte("other-secret"), valid),
+		"alg none":       jwtSigningInput(t, "none", valid) + ".",
+		"opaque token":   "my-secret-token",
+	}
+	for name, token := range rejected {
+		if _, err := authenticator.Authenticate(context.Background(), token); !errors.Is(err, ErrInvalidToken) {
+			t.Errorf("%s: expected ErrInvalidToken, got %v", name, err)
+		}
+	}
+}
+
+func TestJWTAuthenticator_ECDSA(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	authenticator, err := NewJWTAuthenticator(JWTConfig{
+		PublicKeyPEM: pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}),
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	input := jwtSigningInput(t, "ES256", map[string]any{"sub": "bob", "roles": "reader writer"})
+	digest := sha256.Sum256([]byte(input))
+	r, s, err := ecdsa.Sign(rand.Reader, key, digest[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	signature := make([]byte, 64)
+	r.FillBytes(signature[:32])
+	s.FillBytes(signature[32:])
+
+	principal, err := authenticator.Authenticate(context.Background(), input+"."+base64.RawURLEncoding.EncodeToString(signature))
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if principal.UserID != "bob" || !slices.Equal(principal.Roles, []string{RoleUser, "reader", "writer"}) {
+		t.Errorf("Expected bob with roles from space separated claim, got %+v", principal)
+	}
+
+	// Токен HS256, подписанный открытым ключом как секретом, не принимается
+	forged := signHS256(t, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), map[string]any{"sub": "mallory"})
+	if _, err := authenticator.Authenticate(context.Background(), forged); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("Expected ErrInvalidToken for algorithm confusion, got %v", err)
+	}
+
+	// ES384 не соответствует кривой P-256 ключа, даже если подпись проверяется математически
+	input = jwtSigningInput(t, "ES384", map[string]any{"sub": "mallory"})
+	digest384 := sha512.Sum384([]byte(input))
+	r, s, err = ecdsa.Sign(rand.Reader, key, digest384[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	signature = make([]byte, 96)
+	r.FillBytes(signature[:48])
+	s.FillBytes(signature[48:])
+	if _, err := authenticator.Authenticate(context.Background(), input+"."+base64.RawURLEncoding.EncodeToString(signature)); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("Expected ErrInvalidToken for ES384 with P-256 key, got %v", err)
+	}
+}
+
+func TestIntrospectionAuthenticator_CachesResults(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if user, password, _ := r.BasicAuth(); user != "notes" || password != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch r.PostFormValue("token") {
+		case "active-token":
+			fmt.Fprint(w, `{"active": true, "sub": "carol", "roles": ["admin"], "aud": "notes"}`)
+		case "other-audience":
+			fmt.Fprint(w, `{"active": true, "sub": "carol", "aud": "billing"}`)
+		default:
+			fmt.Fprint(w, `{"active": false}`)
+		}
+	}))
+	defer server.Close()
+
+	now := time.Unix(1_700_000_000, 0)
+	authenticator, err := NewIntrospectionAuthenticator(IntrospectionConfig{
+		URL:          server.URL,
+		ClientID:     "notes",
+		ClientSecret: "secret",
+		Audience:     "notes",
+		CacheTTL:     time.Minute,
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	authenticator.now = func() time.Time { return now }
+
+	for range 3 {
+		principal, err := authenticator.Authenticate(context.Background(), "active-token")
+		if err != nil || principal.UserID != "carol" || !principal.HasRole(RoleAdmin) {
+			t.Fatalf("Expected admin carol, got %+v, %v", principal, err)
+		}
+		if _, err := authenticator.Authenticate(context.Background(), "revoked-token"); !errors.Is(err, ErrInvalidToken) {
+			t.Fatalf("Expected ErrInvalidToken for inactive token, got %v", err)
+		}
+	}
+	if got := requests.Load(); got != 2 {
+		t.Errorf("Expected 2 introspection requests thanks to the cache, got %d", got)
+	}
+	if _, err := authenticator.Authenticate(context.Background(), "other-audience"); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("Expected ErrInvalidToken for another audience, got %v", err)
+	}
+
+	// После CacheTTL токен проверяется заново
+	now = now.Add(time.Minute)
+	if _, err := authenticator.Authenticate(context.Background(), "active-token"); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if got := requests.Load(); got != 4 {
+		t.Errorf("Expected a new introspection request after cache ttl, got %d requests", got)
+	}
+
+	// Ошибка endpoint не означает недействительный токен и не кэшируется
+	server.Close()
+	if _, err := authenticator.Authenticate(context.Background(), "unknown-token"); err == nil || errors.Is(err, ErrInvalidToken) {
+		t.Errorf("Expected introspection failure, got %v", err)
+	}
+}
+
+func TestSessions_LoginRefreshLogout(t *testing.T) {
+	sessions, err := NewSessions(SessionsConfig{
+		Users: []User{
+			{Username: "alice", Password: "secret", Roles: []string{RoleAdmin}},
+			// sha256("hunter2")
+			{Username: "bob", Password: "sha256:f52fbd32b2b3b86ff88ef6c490628285f482af15ddcb29541f94bcf526a3f6c7", UserID: "user-bob"},
+		},
+		AccessTokenTTL:  time.Minute,
+		RefreshTokenTTL: time.Hour,
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	now := time.Unix(1_700_000_000, 0)
+	sessions.now = func() time.Time { return now }
+
+	if _, err := sessions.Login(context.Background(), "alice", "wrong"); !errors.Is(err, ErrInvalidCredentials) {
+		t.Errorf("Expected ErrInvalidCredentials for wrong password, got %v", err)
+	}
+	if _, err := sessions.Login(context.Background(), "mallory", ""); !errors.Is(err, ErrInvalidCredentials) {
+		t.Errorf("Expected ErrInvalidCredentials for unknown user, got %v", err)
+	}
+	if tokens, err := sessions.Login(context.Background(), "bob", "hunter2"); err != nil || tokens.Principal.UserID != "user-bob" {
+		t.Errorf("Expected login with sha256 password, got %+v, %v", tokens, err)
+	}
+
+	tokens, err := sessions.Login(context.Background(), "alice", "secret")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	principal, err := sessions.Authenticate(context.Background(), tokens.AccessToken)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if principal.UserID != "alice" || !principal.HasRole(RoleAdmin) || principal.SessionID == "" {
+		t.Errorf("Expected admin alice with session, got %+v", principal)
+	}
+
+	// Обновление выдает новый refresh токен, старый больше не принимается
+	refreshed, err := sessions.Refresh(tokens.RefreshToken)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if refreshed.RefreshToken == tokens.RefreshToken || !refreshed.RefreshTokenExpiresAt.Equal(tokens.RefreshTokenExpiresAt) {
+		t.Errorf("Expected rotated refresh token with the same session expiry, got %+v", refreshed)
+	}
+
+	// Повторное предъявление использованного refresh токена закрывает сессию
+	if _, err := sessions.Refresh(tokens.RefreshToken); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("Expected ErrInvalidToken for reused refresh token, got %v", err)
+	}
+	if _, err := sessions.Refresh(refreshed.RefreshToken); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("Expected session to be revoked after refresh token reuse, got %v", err)
+	}
+	if _, err := sessions.Authenticate(context.Background(), refreshed.AccessToken); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("Expected access token of revoked session to be rejected, got %v", err)
+	}
+
+	// Logout по access токену отзывает токены сессии
+	tokens, err = sessions.Login(context.Background(), "alice", "secret")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	principal, err = sessions.Authenticate(context.Background(), tokens.AccessToken)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	sessions.Logout(principal, "")
+	if _, err := sessions.Authenticate(context.Background(), tokens.AccessToken); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("Expected revoked access token to be rejected, got %v", err)
+	}
+	if _, err := sessions.Refresh(tokens.RefreshToken); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("Expected refresh token of closed session to be rejected, got %v", err)
+	}
+
+	// Сессия не продлевается обновлением токенов
+	tokens, err = sessions.Login(context.Background(), "alice", "secret")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	now = now.Add(time.Hour)
+	if _, err := sessions.Authenticate(context.Background(), tokens.AccessToken); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("Expected expired access token to be rejected, got %v", err)
+	}
+	if _, err := sessions.Refresh(tokens.RefreshToken); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("Expected expired session to be rejected, got %v", err)
+	}
+}
+
+func TestSessions_RejectsForeignTokens(t *testing.T) {
+	sessions, err := NewSessions(SessionsConfig{
+		Users:      []User{{Username: "alice", Password: "secret"}},
+		SigningKey: []byte("session-key"),
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	valid := map[string]any{"iss": sessionIssuer, "sub": "alice", "sid": "s1", "exp": time.Now().Add(time.Hour).Unix()}
+	if _, err := sessions.Authenticate(context.Background(), signHS256(t, []byte("session-key"), valid)); err != nil {
+		t.Errorf("Expected token signed with the session key to be accepted, got %v", err)
+	}
+
+	rejected := map[string]string{
+		"other key":    signHS256(t, []byte("other-key"), valid),
+		"other issuer": signHS256(t, []byte("session-key"), map[string]any{"iss": "idp", "sub": "alice", "sid": "s1"}),
+		"no session":   signHS256(t, []byte("session-key"), map[string]any{"iss": sessionIssuer, "sub": "alice"}),
+		"static token": "my-secret-token",
+	}
+	for name, token := range rejected {
+		if _, err := sessions.Authenticate(context.Background(), token); !errors.Is(err, ErrInvalidToken) {
+			t.Errorf("%s: expected ErrInvalidToken, got %v", name, err)
+		}
+	}
+
+	if _, err := NewSessions(SessionsConfig{Users: []User{{Username: "alice", Password: "sha256:zz"}}}); err == nil {
+		t.Error("Expected error for invalid password hash")
+	}
+}
+
+func TestStreamTickets_IssueRedeem(t *testing.T) {
+	tickets, err := NewStreamTickets(StreamTicketsConfig{SigningKey: []byte("ticket-key")})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	now := time.Now()
+	tickets.now = func() time.Time { return now }
+
+	const method = "/notes.v1.NotesService/SubscribeToEvents"
+	ticket, err := tickets.Issue(Principal{UserID: "alice", Roles: []string{RoleUser}, APIKeyID: "k1"}, method)
+	if err != nil {
+		t.Fatalf("Issue: %v", err)
+	}
+	if !ticket.ExpiresAt.Equal(now.Add(DefaultStreamTicketTTL)) {
+		t.Errorf("Expected ticket to expire after %v, got %v", DefaultStreamTicketTTL, ticket.ExpiresAt)
+	}
+	if err := tickets.Verify(ticket.Ticket); err != nil {
+		t.Errorf("Verify: %v", err)
+	}
+
+	// Билет другого метода не открывает стрим и не расходуется
+	if _, err := tickets.Redeem(ticket.Ticket, "/notes.v1.NotesService/Chat"); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("Expected ErrInvalidToken for another method, got %v", err)
+	}
+	principal, err := tickets.Redeem(ticket.Ticket, method)
+	if err != nil || principal.UserID != "alice" || principal.APIKeyID != "k1" || !principal.HasRole(RoleUser) {
+		t.Fatalf("Expected alice from the ticket, got %+v, %v", principal, err)
+	}
+	if _, err := tickets.Redeem(ticket.Ticket, method); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("Expected ErrInvalidToken for a used ticket, got %v", err)
+	}
+
+	// Истекший билет, билет другого ключа и поддельная подпись не принимаются
+	expired, _ := tickets.Issue(Principal{UserID: "alice"}, method)
+	other, _ := NewStreamTickets(StreamTicketsConfig{SigningKey: []byte("other-key")})
+	foreign, _ := other.Issue(Principal{UserID: "alice"}, method)
+	now = now.Add(2 * DefaultStreamTicketTTL)
+	for name, ticket := range map[string]string{
+		"expired":   expired.Ticket,
+		"other key": foreign.Ticket,
+		"garbage":   "not-a-ticket",
+	} {
+		if err := tickets.Verify(ticket); !errors.Is(err, ErrInvalidToken) {
+			t.Errorf("%s: expected ErrInvalidToken, got %v", name, err)
+		}
+	}
+}
+
+// authenticatorFunc адаптер функции к Authenticator
+type authenticatorFunc func(ctx context.Context, token string) (Principal, error)
+
+func (f authenticatorFunc) Authenticate(ctx context.Context, token string) (Principal, error) {
+	return f(ctx, token)
+}
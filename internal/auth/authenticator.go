@@ -0,0 +1,52 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"strings"
+)
+
+// ErrInvalidToken возвращается, когда токен не распознан или недействителен
+// Остальные ошибки Authenticator означают, что проверить токен не удалось (например,
+// сервер авторизации недоступен), и запрос можно повторить
+var ErrInvalidToken = errors.New("invalid token")
+
+// Authenticator проверяет токен доступа и возвращает пользователя, которому он выдан
+// Используется gRPC интерцепторами (unary и стримы) и HTTP Gateway (REST и WebSocket upgrade)
+type Authenticator interface {
+	Authenticate(ctx context.Context, token string) (Principal, error)
+}
+
+// Chain проверяет токен провайдерами по порядку: токен принимает первый провайдер,
+// который его распознал. Ошибка провайдера, отличная от ErrInvalidToken, возвращается,
+// только если токен не принял ни один из следующих провайдеров
+type Chain []Authenticator
+
+// Authenticate возвращает пользователя первого провайдера, принявшего токен
+func (c Chain) Authenticate(ctx context.Context, token string) (Principal, error) {
+	var failure error
+	for _, authenticator := range c {
+		principal, err := authenticator.Authenticate(ctx, token)
+		if err == nil {
+			return principal, nil
+		}
+		if failure == nil && !errors.Is(err, ErrInvalidToken) {
+			failure = err
+		}
+	}
+
+	if failure != nil {
+		return Principal{}, failure
+	}
+	return Principal{}, ErrInvalidToken
+}
+
+// ParseBearer извлекает токен из значения заголовка Authorization в формате "Bearer <token>"
+func ParseBearer(header string) (string, bool) {
+	token, ok := strings.CutPrefix(header, "Bearer ")
+	if !ok {
+		return "", false
+	}
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
@@ -0,0 +1,62 @@
+package auth
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// ClaimMapping задает, из каких утверждений токена берутся пользователь и роли
+type ClaimMapping struct {
+	UserClaim  string // Утверждение с идентификатором пользователя (по умолчанию "sub")
+	RolesClaim string // Утверждение с ролями: массив строк или строка через пробел (по умолчанию "roles")
+}
+
+// principal возвращает пользователя из утверждений токена
+// Роль RoleUser есть у любого аутентифицированного пользователя, остальные роли берутся из токена
+func (m ClaimMapping) principal(claims map[string]any) (Principal, error) {
+	userClaim := m.UserClaim
+	if userClaim == "" {
+		userClaim = "sub"
+	}
+	rolesClaim := m.RolesClaim
+	if rolesClaim == "" {
+		rolesClaim = "roles"
+	}
+
+	userID, _ := claims[userClaim].(string)
+	if userID == "" {
+		return Principal{}, fmt.Errorf("%w: claim %q is missing", ErrInvalidToken, userClaim)
+	}
+
+	roles := []string{RoleUser}
+	var claimed []string
+	switch value := claims[rolesClaim].(type) {
+	case string:
+		claimed = strings.Fields(value)
+	case []any:
+		for _, role := range value {
+			if role, ok := role.(string); ok {
+				claimed = append(claimed, role)
+			}
+		}
+	}
+	for _, role := range claimed {
+		if !slices.Contains(roles, role) {
+			roles = append(roles, role)
+		}
+	}
+
+	return Principal{UserID: userID, Roles: roles}, nil
+}
+
+// hasAudience проверяет, что утверждение aud (строка или массив строк) содержит audience
+func hasAudience(claims map[string]any, audience string) bool {
+	switch value := claims["aud"].(type) {
+	case string:
+		return value == audience
+	case []any:
+		return slices.Contains(value, any(audience))
+	}
+	return false
+}
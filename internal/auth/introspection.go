@@ -0,0 +1,190 @@
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultIntrospectionCacheTTL время кэширования результата проверки токена
+	DefaultIntrospectionCacheTTL = time.Minute
+
+	// DefaultIntrospectionCacheSize максимальное количество токенов в кэше
+	DefaultIntrospectionCacheSize = 10000
+
+	// DefaultIntrospectionTimeout время ожидания ответа сервера авторизации
+	DefaultIntrospectionTimeout = 5 * time.Second
+)
+
+// IntrospectionConfig параметры проверки токенов через OAuth 2.0 Token Introspection (RFC 7662)
+type IntrospectionConfig struct {
+	URL          string        // Адрес introspection endpoint сервера авторизации
+	ClientID     string        // Идентификатор сервиса для HTTP Basic аутентификации на endpoint
+	ClientSecret string        // Секрет сервиса
+	Audience     string        // Значение, которое должно быть в aud ответа (пусто - не проверяется)
+	CacheTTL     time.Duration // Время кэширования результата (по умолчанию DefaultIntrospectionCacheTTL)
+	CacheSize    int           // Размер кэша (по умолчанию DefaultIntrospectionCacheSize)
+	Claims       ClaimMapping
+
+	// HTTPClient отправляет запросы на endpoint (по умолчанию клиент с таймаутом DefaultIntrospectionTimeout)
+	HTTPClient *http.Client
+}
+
+// IntrospectionAuthenticator проверяет непрозрачные токены запросом к серверу авторизации (OIDC)
+// Результаты, в том числе отказы, кэшируются на CacheTTL, но не дольше срока действия токена,
+// поэтому отозванный токен перестает приниматься не позже чем через CacheTTL
+type IntrospectionAuthenticator struct {
+	cfg    IntrospectionConfig
+	client *http.Client
+	now    func() time.Time
+
+	mu    sync.Mutex
+	cache map[[sha256.Size]byte]introspectionResult // Ключ - хэш токена, сами токены не хранятся
+}
+
+// introspectionResult кэшированный результат проверки токена
+type introspectionResult struct {
+	principal Principal
+	err       error // ErrInvalidToken для неактивного токена
+	expires   time.Time
+}
+
+// NewIntrospectionAuthenticator создает проверку токенов через introspection endpoint
+func NewIntrospectionAuthenticator(cfg IntrospectionConfig) (*IntrospectionAuthenticator, error) {
+	endpoint, err := url.Parse(cfg.URL)
+	if err != nil || endpoint.Host == "" || (endpoint.Scheme != "http" && endpoint.Scheme != "https") {
+		return nil, fmt.Errorf("invalid introspection url %q", cfg.URL)
+	}
+	if cfg.CacheTTL <= 0 {
+		cfg.CacheTTL = DefaultIntrospectionCacheTTL
+	}
+	if cfg.CacheSize <= 0 {
+		cfg.CacheSize = DefaultIntrospectionCacheSize
+	}
+	client := cfg.HTTPClient
+	if client == nil {
+		client = &http.Client{Timeout: DefaultIntrospectionTimeout}
+	}
+
+	return &IntrospectionAuthenticator{
+		cfg:    cfg,
+		client: client,
+		now:    time.Now,
+		cache:  make(map[[sha256.Size]byte]introspectionResult),
+	}, nil
+}
+
+// Authenticate возвращает пользователя активного токена
+// Недоступность сервера авторизации возвращается как ошибка, отличная от ErrInvalidToken, и не кэшируется
+func (a *IntrospectionAuthenticator) Authenticate(ctx context.Context, token string) (Principal, error) {
+	key := sha256.Sum256([]byte(token))
+	if result, ok := a.cached(key); ok {
+		return result.principal, result.err
+	}
+
+	claims, err := a.introspect(ctx, token)
+	if err != nil {
+		return Principal{}, err
+	}
+
+	result := introspectionResult{expires: a.now().Add(a.cfg.CacheTTL)}
+	if exp, ok := claims["exp"].(float64); ok {
+		result.expires = minTime(result.expires, time.Unix(int64(exp), 0))
+	}
+	switch {
+	case claims["active"] != true:
+		result.err = ErrInvalidToken
+	case a.cfg.Audience != "" && !hasAudience(claims, a.cfg.Audience):
+		result.err = fmt.Errorf("%w: unexpected audience", ErrInvalidToken)
+	default:
+		result.principal, result.err = a.cfg.Claims.principal(claims)
+	}
+
+	a.store(key, result)
+	return result.principal, result.err
+}
+
+// introspect отправляет токен на introspection endpoint и возвращает утверждения ответа
+func (a *IntrospectionAuthenticator) introspect(ctx context.Context, token string) (map[string]any, error) {
+	form := url.Values{"token": {token}, "token_type_hint": {"access_token"}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, a.cfg.URL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if a.cfg.ClientID != "" {
+		req.SetBasicAuth(url.QueryEscape(a.cfg.ClientID), url.QueryEscape(a.cfg.ClientSecret))
+	}
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("token introspection failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("token introspection failed: endpoint returned %s", resp.Status)
+	}
+
+	var claims map[string]any
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&claims); err != nil {
+		return nil, fmt.Errorf("token introspection failed: invalid response: %w", err)
+	}
+	return claims, nil
+}
+
+// cached возвращает действующий результат проверки токена из кэша
+func (a *IntrospectionAuthenticator) cached(key [sha256.Size]byte) (introspectionResult, bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	result, ok := a.cache[key]
+	if !ok {
+		return introspectionResult{}, false
+	}
+	if !a.now().Before(result.expires) {
+		delete(a.cache, key)
+		return introspectionResult{}, false
+	}
+	return result, true
+}
+
+// store кэширует результат проверки токена
+// Заполненный кэш сначала очищается от истекших записей, затем от произвольных
+func (a *IntrospectionAuthenticator) store(key [sha256.Size]byte, result introspectionResult) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if len(a.cache) >= a.cfg.CacheSize {
+		now := a.now()
+		for k, cached := range a.cache {
+			if !now.Before(cached.expires) {
+				delete(a.cache, k)
+			}
+		}
+		for k := range a.cache {
+			if len(a.cache) < a.cfg.CacheSize {
+				break
+			}
+			delete(a.cache, k)
+		}
+	}
+	a.cache[key] = result
+}
+
+// minTime возвращает более раннее из двух времен
+func minTime(a, b time.Time) time.Time {
+	if b.Before(a) {
+		return b
+	}
+	return a
+}
@@ -0,0 +1,152 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// JWTConfig параметры проверки JWT
+// Нужен ровно один ключ: HMACSecret для HS256/384/512 или PublicKeyPEM для RS* и ES*
+type JWTConfig struct {
+	HMACSecret   []byte        // Общий секрет HMAC
+	PublicKeyPEM []byte        // Открытый ключ (PKIX) или сертификат в PEM
+	Issuer       string        // Ожидаемое значение iss (пусто - не проверяется)
+	Audience     string        // Значение, которое должно быть в aud (пусто - не проверяется)
+	Leeway       time.Duration // Допустимое расхождение часов при проверке exp и nbf
+	Claims       ClaimMapping
+}
+
+// JWTAuthenticator проверяет подпись и срок действия JWT без обращения к серверу авторизации
+type JWTAuthenticator struct {
+	cfg       JWTConfig
+	publicKey crypto.PublicKey // nil для HMAC
+	methods   []string         // Алгоритмы, допустимые для настроенного ключа
+	now       func() time.Time
+}
+
+// ecdsaMethods алгоритм подписи ES* для каждой кривой: ключ P-256 принимает только ES256 и т.д.
+var ecdsaMethods = map[elliptic.Curve]string{
+	elliptic.P256(): jwt.SigningMethodES256.Alg(),
+	elliptic.P384(): jwt.SigningMethodES384.Alg(),
+	elliptic.P521(): jwt.SigningMethodES512.Alg(),
+}
+
+// NewJWTAuthenticator создает проверку JWT с ключом из cfg
+// Допустимые алгоритмы определяются ключом: HS* для секрета, RS* для RSA и один ES* для кривой ECDSA
+func NewJWTAuthenticator(cfg JWTConfig) (*JWTAuthenticator, error) {
+	a := &JWTAuthenticator{cfg: cfg, now: time.Now}
+
+	switch {
+	case len(cfg.HMACSecret) > 0 && len(cfg.PublicKeyPEM) > 0:
+		return nil, errors.New("jwt: configure either hmac secret or public key, not both")
+	case len(cfg.HMACSecret) > 0:
+		a.methods = []string{jwt.SigningMethodHS256.Alg(), jwt.SigningMethodHS384.Alg(), jwt.SigningMethodHS512.Alg()}
+		return a, nil
+	case len(cfg.PublicKeyPEM) > 0:
+		key, err := parsePublicKey(cfg.PublicKeyPEM)
+		if err != nil {
+			return nil, err
+		}
+		switch key := key.(type) {
+		case *rsa.PublicKey:
+			a.methods = []string{jwt.SigningMethodRS256.Alg(), jwt.SigningMethodRS384.Alg(), jwt.SigningMethodRS512.Alg()}
+		case *ecdsa.PublicKey:
+			method, ok := ecdsaMethods[key.Curve]
+			if !ok {
+				return nil, fmt.Errorf("jwt: unsupported ecdsa curve %s", key.Curve.Params().Name)
+			}
+			a.methods = []string{method}
+		}
+		a.publicKey = key
+		return a, nil
+	default:
+		return nil, errors.New("jwt: hmac secret or public key is required")
+	}
+}
+
+// Authenticate проверяет подпись, iss, aud, exp и nbf и возвращает пользователя из утверждений
+// Токены, не похожие на JWT, отклоняются с ErrInvalidToken, чтобы их проверил следующий провайдер
+func (a *JWTAuthenticator) Authenticate(_ context.Context, token string) (Principal, error) {
+	claims, err := a.claims(token)
+	if err != nil {
+		return Principal{}, err
+	}
+	return a.cfg.Claims.principal(claims)
+}
+
+// claims проверяет токен и возвращает его утверждения
+// Алгоритм ограничен допустимыми для ключа (WithValidMethods), "none" не принимается
+func (a *JWTAuthenticator) claims(token string) (map[string]any, error) {
+	opts := []jwt.ParserOption{
+		jwt.WithValidMethods(a.methods),
+		jwt.WithLeeway(a.cfg.Leeway),
+		jwt.WithTimeFunc(a.now),
+	}
+	if a.cfg.Issuer != "" {
+		opts = append(opts, jwt.WithIssuer(a.cfg.Issuer))
+	}
+	if a.cfg.Audience != "" {
+		opts = append(opts, jwt.WithAudience(a.cfg.Audience))
+	}
+
+	claims := jwt.MapClaims{}
+	if _, err := jwt.ParseWithClaims(token, claims, a.key, opts...); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidToken, err)
+	}
+	return claims, nil
+}
+
+// key возвращает ключ проверки подписи токена
+// Алгоритм из заголовка повторно сверяется с ключом: HS* не проверяется открытым ключом как секретом,
+// а ES* - ключом другой кривой
+func (a *JWTAuthenticator) key(token *jwt.Token) (any, error) {
+	if !slices.Contains(a.methods, token.Method.Alg()) {
+		return nil, fmt.Errorf("algorithm %s does not match the configured key", token.Method.Alg())
+	}
+	if a.publicKey == nil {
+		return a.cfg.HMACSecret, nil
+	}
+	return a.publicKey, nil
+}
+
+// parsePublicKey читает открытый ключ RSA или ECDSA из PEM (PUBLIC KEY или CERTIFICATE)
+func parsePublicKey(data []byte) (crypto.PublicKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("jwt: public key is not PEM encoded")
+	}
+
+	var key crypto.PublicKey
+	switch block.Type {
+	case "CERTIFICATE":
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("jwt: %w", err)
+		}
+		key = cert.PublicKey
+	default:
+		parsed, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("jwt: %w", err)
+		}
+		key = parsed
+	}
+
+	switch key.(type) {
+	case *rsa.PublicKey, *ecdsa.PublicKey:
+		return key, nil
+	default:
+		return nil, fmt.Errorf("jwt: unsupported public key type %T", key)
+	}
+}
@@ -0,0 +1,50 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"slices"
+)
+
+// Роли пользователей
+const (
+	RoleUser  = "user"  // Работа со своими заметками
+	RoleAdmin = "admin" // Доступ к заметкам всех пользователей
+
+	// Роли проверки методов по конфигурации (authz): admin включает writer, writer - reader
+	RoleReader = "reader" // Чтение заметок
+	RoleWriter = "writer" // Создание, изменение и удаление заметок
+)
+
+// ErrPermissionDenied возвращается, когда у пользователя нет прав на операцию
+var ErrPermissionDenied = errors.New("permission denied")
+
+// Principal аутентифицированный пользователь запроса
+type Principal struct {
+	UserID string   // Идентификатор пользователя (владельца заметок)
+	Roles  []string // Роли пользователя
+
+	// SessionID сессия Login, которой выдан токен (пусто для токенов остальных провайдеров)
+	SessionID string
+
+	// APIKeyID ключ API, которым аутентифицирован запрос (пусто для токенов остальных провайдеров)
+	APIKeyID string
+}
+
+// HasRole проверяет, есть ли у пользователя роль role
+func (p Principal) HasRole(role string) bool {
+	return slices.Contains(p.Roles, role)
+}
+
+type principalKey struct{}
+
+// NewContext возвращает контекст с аутентифицированным пользователем
+func NewContext(ctx context.Context, principal Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, principal)
+}
+
+// FromContext возвращает пользователя запроса, если запрос аутентифицирован
+func FromContext(ctx context.Context) (Principal, bool) {
+	principal, ok := ctx.Value(principalKey{}).(Principal)
+	return principal, ok
+}
@@ -0,0 +1,372 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	// DefaultAccessTokenTTL время действия access токена сессии
+	DefaultAccessTokenTTL = 15 * time.Minute
+
+	// DefaultRefreshTokenTTL время жизни сессии (и ее refresh токенов) с момента входа
+	DefaultRefreshTokenTTL = 7 * 24 * time.Hour
+
+	// sessionIssuer значение iss access токенов сессий
+	sessionIssuer = "notes-service"
+
+	// sessionSecretSize размер случайных идентификатора сессии и секрета refresh токена в байтах
+	sessionSecretSize = 32
+
+	// sessionSweepInterval как часто из памяти удаляются истекшие сессии и записи об отзыве
+	sessionSweepInterval = time.Minute
+
+	// passwordSHA256Prefix префикс пароля, заданного SHA-256 хэшем в hex
+	passwordSHA256Prefix = "sha256:"
+)
+
+// ErrInvalidCredentials возвращается Login при неизвестном пользователе или неверном пароле
+var ErrInvalidCredentials = errors.New("invalid username or password")
+
+// User пользователь, которому разрешен вход по паролю
+type User struct {
+	Username string   // Имя для входа
+	Password string   // Пароль открытым текстом или "sha256:<hex>"
+	UserID   string   // Идентификатор пользователя (по умолчанию Username)
+	Roles    []string // Роли пользователя (RoleUser добавляется всегда)
+}
+
+// UserStore проверяет пароли пользователей при входе через Sessions
+type UserStore interface {
+	// VerifyPassword возвращает пользователя с именем username, если пароль верен
+	// Неизвестный пользователь и неверный пароль возвращают ErrInvalidCredentials
+	VerifyPassword(ctx context.Context, username, password string) (Principal, error)
+}
+
+// StaticUsers пользователи из конфигурации, реализует UserStore
+type StaticUsers map[string]User
+
+var _ UserStore = StaticUsers(nil)
+
+// NewStaticUsers проверяет список пользователей и возвращает его как UserStore
+func NewStaticUsers(users []User) (StaticUsers, error) {
+	result := make(StaticUsers, len(users))
+	for _, user := range users {
+		if user.Username == "" || user.Password == "" {
+			return nil, errors.New("sessions: user requires username and password")
+		}
+		if _, exists := result[user.Username]; exists {
+			return nil, fmt.Errorf("sessions: duplicate user %q", user.Username)
+		}
+		if hash, ok := strings.CutPrefix(user.Password, passwordSHA256Prefix); ok {
+			if decoded, err := hex.DecodeString(hash); err != nil || len(decoded) != sha256.Size {
+				return nil, fmt.Errorf("sessions: invalid sha256 password hash of user %q", user.Username)
+			}
+		}
+		if user.UserID == "" {
+			user.UserID = user.Username
+		}
+		result[user.Username] = user
+	}
+	return result, nil
+}
+
+// VerifyPassword проверяет пароль пользователя username
+func (u StaticUsers) VerifyPassword(_ context.Context, username, password string) (Principal, error) {
+	user, ok := u[username]
+	// Пароль сравнивается и для неизвестного пользователя, чтобы время ответа не выдавало имена
+	if !checkPassword(user.Password, password) || !ok {
+		return Principal{}, ErrInvalidCredentials
+	}
+	return ClaimMapping{}.principal(map[string]any{"sub": user.UserID, "roles": toAny(user.Roles)})
+}
+
+// SessionsConfig параметры сессий Login
+type SessionsConfig struct {
+	Users           []User        // Пользователи, если Store не задан
+	Store           UserStore     // Хранилище пользователей (например, сервис пользователей)
+	SigningKey      []byte        // Ключ подписи access токенов (пусто - случайный, токены не переживают перезапуск)
+	AccessTokenTTL  time.Duration // По умолчанию DefaultAccessTokenTTL
+	RefreshTokenTTL time.Duration // По умолчанию DefaultRefreshTokenTTL
+}
+
+// Tokens токены открытой или обновленной сессии
+type Tokens struct {
+	AccessToken           string
+	AccessTokenExpiresAt  time.Time
+	RefreshToken          string
+	RefreshTokenExpiresAt time.Time
+	Principal             Principal
+}
+
+// Sessions выдает токены по паролю и проверяет их как провайдер аутентификации
+//
+// Access токен - короткоживущий JWT (HS256) с идентификатором сессии в утверждении sid,
+// проверяется без обращения к хранилищу сессий. Refresh токен - непрозрачная строка
+// "<sid>.<секрет>", хранится только хэш секрета. Каждое обновление выдает новый refresh токен,
+// повторное предъявление уже использованного считается утечкой и закрывает сессию.
+//
+// Закрытые сессии попадают в список отзыва, который хранится, пока не истекут выданные
+// сессии access токены. Сессии и список отзыва хранятся в памяти процесса
+type Sessions struct {
+	cfg      SessionsConfig
+	users    UserStore
+	verifier *JWTAuthenticator
+	now      func() time.Time
+
+	mu        sync.Mutex
+	sessions  map[string]*session
+	revoked   map[string]time.Time // sid закрытой сессии -> время истечения последнего выданного access токена
+	lastSweep time.Time
+}
+
+// session открытая сессия
+type session struct {
+	principal   Principal
+	refreshHash [sha256.Size]byte // Хэш секрета действующего refresh токена
+	expires     time.Time
+}
+
+// NewSessions создает хранилище сессий для пользователей cfg.Store или, если оно не задано, cfg.Users
+func NewSessions(cfg SessionsConfig) (*Sessions, error) {
+	if cfg.AccessTokenTTL <= 0 {
+		cfg.AccessTokenTTL = DefaultAccessTokenTTL
+	}
+	if cfg.RefreshTokenTTL <= 0 {
+		cfg.RefreshTokenTTL = DefaultRefreshTokenTTL
+	}
+	if len(cfg.SigningKey) == 0 {
+		cfg.SigningKey = make([]byte, sessionSecretSize)
+		if _, err := rand.Read(cfg.SigningKey); err != nil {
+			return nil, err
+		}
+	}
+
+	users := cfg.Store
+	if users == nil {
+		static, err := NewStaticUsers(cfg.Users)
+		if err != nil {
+			return nil, err
+		}
+		users = static
+	}
+
+	verifier, err := NewJWTAuthenticator(JWTConfig{HMACSecret: cfg.SigningKey, Issuer: sessionIssuer})
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Sessions{
+		cfg:      cfg,
+		users:    users,
+		verifier: verifier,
+		now:      time.Now,
+		sessions: make(map[string]*session),
+		revoked:  make(map[string]time.Time),
+	}
+	verifier.now = func() time.Time { return s.now() }
+	return s, nil
+}
+
+// Login проверяет пароль пользователя и открывает сессию
+func (s *Sessions) Login(ctx context.Context, username, password string) (Tokens, error) {
+	principal, err := s.users.VerifyPassword(ctx, username, password)
+	if err != nil {
+		return Tokens{}, err
+	}
+
+	sid, err := randomString()
+	if err != nil {
+		return Tokens{}, err
+	}
+	principal.SessionID = sid
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	s.sweepLocked(now)
+	sess := &session{principal: principal, expires: now.Add(s.cfg.RefreshTokenTTL)}
+	tokens, err := s.issueLocked(sid, sess, now)
+	if err != nil {
+		return Tokens{}, err
+	}
+	s.sessions[sid] = sess
+	return tokens, nil
+}
+
+// Refresh выдает новую пару токенов сессии refreshToken
+// Срок жизни сессии не продлевается: после RefreshTokenTTL с момента входа нужен новый Login
+func (s *Sessions) Refresh(refreshToken string) (Tokens, error) {
+	sid, secret, ok := strings.Cut(refreshToken, ".")
+	if !ok {
+		return Tokens{}, ErrInvalidToken
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	sess, ok := s.sessions[sid]
+	if !ok {
+		return Tokens{}, ErrInvalidToken
+	}
+	if !now.Before(sess.expires) {
+		delete(s.sessions, sid)
+		return Tokens{}, fmt.Errorf("%w: session expired", ErrInvalidToken)
+	}
+	if hash := sha256.Sum256([]byte(secret)); subtle.ConstantTimeCompare(hash[:], sess.refreshHash[:]) != 1 {
+		s.revokeLocked(sid, now)
+		return Tokens{}, fmt.Errorf("%w: refresh token reused, session revoked", ErrInvalidToken)
+	}
+
+	return s.issueLocked(sid, sess, now)
+}
+
+// Logout закрывает сессию refreshToken и сессию, которой выдан access токен principal
+// Неизвестные и уже закрытые сессии пропускаются, поэтому повторный Logout не является ошибкой
+func (s *Sessions) Logout(principal Principal, refreshToken string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	if sid, secret, ok := strings.Cut(refreshToken, "."); ok {
+		hash := sha256.Sum256([]byte(secret))
+		if sess, ok := s.sessions[sid]; ok && subtle.ConstantTimeCompare(hash[:], sess.refreshHash[:]) == 1 {
+			s.revokeLocked(sid, now)
+		}
+	}
+	if principal.SessionID != "" {
+		s.revokeLocked(principal.SessionID, now)
+	}
+}
+
+// Authenticate проверяет access токен сессии: подпись, срок действия и отсутствие в списке отзыва
+func (s *Sessions) Authenticate(_ context.Context, token string) (Principal, error) {
+	claims, err := s.verifier.claims(token)
+	if err != nil {
+		return Principal{}, err
+	}
+	sid, _ := claims["sid"].(string)
+	if sid == "" {
+		return Principal{}, fmt.Errorf("%w: claim \"sid\" is missing", ErrInvalidToken)
+	}
+	principal, err := ClaimMapping{}.principal(claims)
+	if err != nil {
+		return Principal{}, err
+	}
+	principal.SessionID = sid
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, revoked := s.revoked[sid]; revoked {
+		return Principal{}, fmt.Errorf("%w: session revoked", ErrInvalidToken)
+	}
+	return principal, nil
+}
+
+// AccessTokenTTL возвращает время действия access токенов
+func (s *Sessions) AccessTokenTTL() time.Duration {
+	return s.cfg.AccessTokenTTL
+}
+
+// issueLocked выдает access токен и новый refresh токен сессии
+// Предыдущий refresh токен сессии перестает приниматься
+func (s *Sessions) issueLocked(sid string, sess *session, now time.Time) (Tokens, error) {
+	secret, err := randomString()
+	if err != nil {
+		return Tokens{}, err
+	}
+
+	accessExpires := now.Add(s.cfg.AccessTokenTTL)
+	access, err := s.sign(map[string]any{
+		"iss":   sessionIssuer,
+		"sub":   sess.principal.UserID,
+		"roles": sess.principal.Roles,
+		"sid":   sid,
+		"iat":   now.Unix(),
+		"exp":   accessExpires.Unix(),
+	})
+	if err != nil {
+		return Tokens{}, err
+	}
+
+	sess.refreshHash = sha256.Sum256([]byte(secret))
+	return Tokens{
+		AccessToken:           access,
+		AccessTokenExpiresAt:  accessExpires,
+		RefreshToken:          sid + "." + secret,
+		RefreshTokenExpiresAt: sess.expires,
+		Principal:             sess.principal,
+	}, nil
+}
+
+// revokeLocked закрывает сессию и добавляет ее в список отзыва на время действия access токенов
+func (s *Sessions) revokeLocked(sid string, now time.Time) {
+	delete(s.sessions, sid)
+	s.revoked[sid] = now.Add(s.cfg.AccessTokenTTL)
+}
+
+// sweepLocked удаляет истекшие сессии и записи об отзыве не чаще раза в sessionSweepInterval
+func (s *Sessions) sweepLocked(now time.Time) {
+	if now.Sub(s.lastSweep) < sessionSweepInterval {
+		return
+	}
+	s.lastSweep = now
+
+	for sid, sess := range s.sessions {
+		if !now.Before(sess.expires) {
+			delete(s.sessions, sid)
+		}
+	}
+	for sid, until := range s.revoked {
+		if !now.Before(until) {
+			delete(s.revoked, sid)
+		}
+	}
+}
+
+// sign подписывает утверждения access токена ключом сессий (HS256)
+func (s *Sessions) sign(claims map[string]any) (string, error) {
+	return jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims(claims)).SignedString(s.cfg.SigningKey)
+}
+
+// checkPassword сравнивает пароль с сохраненным значением за постоянное время
+func checkPassword(stored, password string) bool {
+	if hash, ok := strings.CutPrefix(stored, passwordSHA256Prefix); ok {
+		want, _ := hex.DecodeString(hash)
+		got := sha256.Sum256([]byte(password))
+		return subtle.ConstantTimeCompare(want, got[:]) == 1
+	}
+	return subtle.ConstantTimeCompare([]byte(stored), []byte(password)) == 1
+}
+
+// randomString возвращает случайную строку из sessionSecretSize байт в base64url
+func randomString() (string, error) {
+	buf := make([]byte, sessionSecretSize)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// toAny конвертирует роли в формат утверждений, декодированных из JSON
+func toAny(values []string) []any {
+	result := make([]any, len(values))
+	for i, value := range values {
+		result[i] = value
+	}
+	return result
+}
@@ -0,0 +1,24 @@
+package auth
+
+import "context"
+
+// StaticTokens проверяет токены по фиксированному списку из конфигурации
+// Ключ - токен, значение - пользователь, которому он выдан
+type StaticTokens map[string]Principal
+
+// DemoTokens возвращает демонстрационные токены, принимаемые без секции auth в конфигурации
+func DemoTokens() StaticTokens {
+	return StaticTokens{
+		"my-secret-token": {UserID: "demo", Roles: []string{RoleUser}},
+		"my-admin-token":  {UserID: "admin", Roles: []string{RoleUser, RoleAdmin}},
+	}
+}
+
+// Authenticate возвращает пользователя токена
+func (t StaticTokens) Authenticate(_ context.Context, token string) (Principal, error) {
+	principal, ok := t[token]
+	if !ok {
+		return Principal{}, ErrInvalidToken
+	}
+	return principal, nil
+}
@@ -0,0 +1,176 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultStreamTicketTTL время действия билета стрима
+const DefaultStreamTicketTTL = time.Minute
+
+// StreamTicketsConfig параметры билетов стримов
+type StreamTicketsConfig struct {
+	SigningKey []byte        // Ключ подписи билетов (пусто - случайный, билеты принимает только выдавший процесс)
+	TTL        time.Duration // По умолчанию DefaultStreamTicketTTL
+}
+
+// StreamTicket выданный билет стрима
+type StreamTicket struct {
+	Ticket    string
+	ExpiresAt time.Time
+}
+
+// StreamTickets выдает и проверяет короткоживущие билеты для открытия стримов из браузера
+//
+// Браузер не может задать заголовок Authorization для WebSocket и EventSource, поэтому клиент
+// обменивает свой токен на билет (AuthService.IssueStreamTicket) и передает его в URL параметром
+// ticket. Билет - подписанная HMAC-SHA256 строка "<утверждения>.<подпись>" в base64url с пользователем,
+// методом стрима и временем истечения: долгоживущий токен не попадает в URL и логи прокси.
+//
+// Билет одноразовый: использованные билеты хранятся в памяти процесса до истечения,
+// поэтому при нескольких репликах один билет может открыть по стриму на каждой реплике
+type StreamTickets struct {
+	cfg StreamTicketsConfig
+	now func() time.Time
+
+	mu        sync.Mutex
+	redeemed  map[string]time.Time // Идентификатор использованного билета -> время его истечения
+	lastSweep time.Time
+}
+
+// ticketClaims утверждения билета стрима
+type ticketClaims struct {
+	ID        string   `json:"tid"`
+	UserID    string   `json:"sub"`
+	Roles     []string `json:"roles,omitempty"`
+	SessionID string   `json:"sid,omitempty"`
+	APIKeyID  string   `json:"kid,omitempty"`
+	Method    string   `json:"method"`
+	ExpiresAt int64    `json:"exp"`
+}
+
+// NewStreamTickets создает выдачу билетов стримов
+func NewStreamTickets(cfg StreamTicketsConfig) (*StreamTickets, error) {
+	if cfg.TTL <= 0 {
+		cfg.TTL = DefaultStreamTicketTTL
+	}
+	if len(cfg.SigningKey) == 0 {
+		cfg.SigningKey = make([]byte, sessionSecretSize)
+		if _, err := rand.Read(cfg.SigningKey); err != nil {
+			return nil, err
+		}
+	}
+	return &StreamTickets{cfg: cfg, now: time.Now, redeemed: make(map[string]time.Time)}, nil
+}
+
+// Issue выдает пользователю principal билет на открытие стрима method ("/notes.v1.NotesService/SubscribeToEvents")
+func (t *StreamTickets) Issue(principal Principal, method string) (StreamTicket, error) {
+	id, err := randomString()
+	if err != nil {
+		return StreamTicket{}, err
+	}
+	expiresAt := t.now().Add(t.cfg.TTL)
+	payload, err := json.Marshal(ticketClaims{
+		ID:        id,
+		UserID:    principal.UserID,
+		Roles:     principal.Roles,
+		SessionID: principal.SessionID,
+		APIKeyID:  principal.APIKeyID,
+		Method:    method,
+		ExpiresAt: expiresAt.Unix(),
+	})
+	if err != nil {
+		return StreamTicket{}, err
+	}
+
+	input := base64.RawURLEncoding.EncodeToString(payload)
+	return StreamTicket{Ticket: input + "." + t.signature(input), ExpiresAt: expiresAt}, nil
+}
+
+// Verify проверяет подпись и срок действия билета, не расходуя его
+// Используется Gateway, чтобы не открывать WebSocket соединение с недействительным билетом
+func (t *StreamTickets) Verify(ticket string) error {
+	_, err := t.parse(ticket)
+	return err
+}
+
+// Redeem проверяет билет на открытие стрима method и отмечает его использованным
+// Возвращает пользователя, которому выдан билет
+func (t *StreamTickets) Redeem(ticket, method string) (Principal, error) {
+	claims, err := t.parse(ticket)
+	if err != nil {
+		return Principal{}, err
+	}
+	if claims.Method != method {
+		return Principal{}, fmt.Errorf("%w: ticket is issued for another method", ErrInvalidToken)
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	now := t.now()
+	t.sweepLocked(now)
+	if _, used := t.redeemed[claims.ID]; used {
+		return Principal{}, fmt.Errorf("%w: ticket is already used", ErrInvalidToken)
+	}
+	t.redeemed[claims.ID] = time.Unix(claims.ExpiresAt, 0)
+
+	return Principal{
+		UserID:    claims.UserID,
+		Roles:     claims.Roles,
+		SessionID: claims.SessionID,
+		APIKeyID:  claims.APIKeyID,
+	}, nil
+}
+
+// TTL возвращает время действия билетов
+func (t *StreamTickets) TTL() time.Duration {
+	return t.cfg.TTL
+}
+
+// parse проверяет подпись и срок действия билета и возвращает его утверждения
+func (t *StreamTickets) parse(ticket string) (ticketClaims, error) {
+	input, signature, ok := strings.Cut(ticket, ".")
+	if !ok || !hmac.Equal([]byte(signature), []byte(t.signature(input))) {
+		return ticketClaims{}, fmt.Errorf("%w: invalid ticket signature", ErrInvalidToken)
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(input)
+	if err != nil {
+		return ticketClaims{}, fmt.Errorf("%w: malformed ticket", ErrInvalidToken)
+	}
+
+	var claims ticketClaims
+	if err := json.Unmarshal(payload, &claims); err != nil || claims.ID == "" || claims.UserID == "" {
+		return ticketClaims{}, fmt.Errorf("%w: malformed ticket", ErrInvalidToken)
+	}
+	if !t.now().Before(time.Unix(claims.ExpiresAt, 0)) {
+		return ticketClaims{}, fmt.Errorf("%w: ticket expired", ErrInvalidToken)
+	}
+	return claims, nil
+}
+
+// signature возвращает подпись утверждений билета в base64url
+func (t *StreamTickets) signature(input string) string {
+	mac := hmac.New(sha256.New, t.cfg.SigningKey)
+	mac.Write([]byte(input))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// sweepLocked удаляет истекшие использованные билеты не чаще раза в sessionSweepInterval
+func (t *StreamTickets) sweepLocked(now time.Time) {
+	if now.Sub(t.lastSweep) < sessionSweepInterval {
+		return
+	}
+	t.lastSweep = now
+	for id, expires := range t.redeemed {
+		if !now.Before(expires) {
+			delete(t.redeemed, id)
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package buildinfo
+
+import "runtime/debug"
+
+// version задается при сборке:
+// go build -ldflags "-X notes-service/internal/buildinfo.version=v1.2.3" ./cmd/server
+var version string
+
+// Version возвращает версию сервиса: значение из -ldflags, версию модуля
+// или ревизию VCS из информации о сборке. Если ничего не известно, возвращает "dev"
+func Version() string {
+	if version != "" {
+		return version
+	}
+
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "dev"
+	}
+	if v := info.Main.Version; v != "" && v != "(devel)" {
+		return v
+	}
+
+	var revision string
+	var modified bool
+	for _, setting := range info.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			revision = setting.Value
+		case "vcs.modified":
+			modified = setting.Value == "true"
+		}
+	}
+	if revision == "" {
+		return "dev"
+	}
+	if len(revision) > 12 {
+		revision = revision[:12]
+	}
+	if modified {
+		revision += "-dirty"
+	}
+
+	return revision
+}
@@ -0,0 +1,41 @@
+package collation
+
+import (
+	"fmt"
+	"strings"
+
+	"golang.org/x/text/collate"
+	"golang.org/x/text/language"
+
+	"notes-service/internal/model"
+	"notes-service/internal/repository"
+)
+
+// TitleComparator возвращает компаратор заметок по заголовку с учетом правил языка locale
+// (BCP 47, например "ru" или "en-US"). При равных заголовках порядок определяется по ID
+// Компаратор не потокобезопасен: для каждой сортировки создается новый экземпляр
+func TitleComparator(locale string) (repository.NoteComparator, error) {
+	tag, err := language.Parse(strings.TrimSpace(locale))
+	if err != nil {
+		return nil, fmt.Errorf("invalid collation locale %q: %w", locale, err)
+	}
+
+	collator := collate.New(tag, collate.IgnoreCase)
+
+	return func(a, b model.Note) int {
+		if c := collator.CompareString(a.Title, b.Title); c != 0 {
+			return c
+		}
+		return strings.Compare(a.ID, b.ID)
+	}, nil
+}
+
+// PreferredLocale возвращает наиболее предпочтительный язык из заголовка Accept-Language
+// Если заголовок пуст или некорректен, возвращается пустая строка
+func PreferredLocale(acceptLanguage string) string {
+	tags, _, err := language.ParseAcceptLanguage(acceptLanguage)
+	if err != nil || len(tags) == 0 {
+		return ""
+	}
+	return tags[0].String()
+}
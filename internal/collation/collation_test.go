@@ -0,0 +1,71 @@
+package collation
+
+import (
+	"slices"
+	"testing"
+
+	"notes-service/internal/model"
+)
+
+func titles(notes []model.Note) []string {
+	result := make([]string, 0, len(notes))
+	for _, note := range notes {
+		result = append(result, note.Title)
+	}
+	return result
+}
+
+func TestTitleComparator_Russian(t *testing.T) {
+	cmp, err := TitleComparator("ru")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	notes := []model.Note{
+		{ID: "1", Title: "ёлка"},
+		{ID: "2", Title: "Жук"},
+		{ID: "3", Title: "арбуз"},
+		{ID: "4", Title: "Елена"},
+	}
+	slices.SortFunc(notes, cmp)
+
+	// Побайтовая сортировка поставила бы "Елена" и "Жук" перед "арбуз", а "ёлка" в конец
+	expected := []string{"арбуз", "Елена", "ёлка", "Жук"}
+	if got := titles(notes); !slices.Equal(got, expected) {
+		t.Errorf("Expected order %v, got %v", expected, got)
+	}
+}
+
+func TestTitleComparator_TieBreakByID(t *testing.T) {
+	cmp, err := TitleComparator("en")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if cmp(model.Note{ID: "a", Title: "Same"}, model.Note{ID: "b", Title: "same"}) >= 0 {
+		t.Error("Expected notes with equal titles to be ordered by ID")
+	}
+}
+
+func TestTitleComparator_InvalidLocale(t *testing.T) {
+	if _, err := TitleComparator("not a locale!"); err == nil {
+		t.Error("Expected error for invalid locale")
+	}
+}
+
+func TestPreferredLocale(t *testing.T) {
+	tests := []struct {
+		header   string
+		expected string
+	}{
+		{header: "ru-RU,ru;q=0.9,en;q=0.8", expected: "ru-RU"},
+		{header: "en;q=0.5,de", expected: "de"},
+		{header: "", expected: ""},
+	}
+
+	for _, tt := range tests {
+		if got := PreferredLocale(tt.header); got != tt.expected {
+			t.Errorf("PreferredLocale(%q) = %q, expected %q", tt.header, got, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,136 @@
+// Package compression сжимает ответы gRPC сервера и HTTP Gateway: компрессоры gzip и zstd
+// регистрируются в gRPC (grpc-encoding) и создают потоки сжатия для Content-Encoding HTTP
+package compression
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/klauspost/compress/zstd"
+	"google.golang.org/grpc/encoding"
+)
+
+// Имена алгоритмов сжатия: совпадают в grpc-encoding и Content-Encoding
+const (
+	Gzip = "gzip"
+	Zstd = "zstd"
+)
+
+// zstdMaxWindow максимальное окно zstd запроса (у сообщений gRPC по умолчанию не больше 4 МБ)
+const zstdMaxWindow = 8 << 20
+
+// Writer поток сжатия: Flush отправляет сжатые данные, не дожидаясь конца потока (для стримов)
+type Writer interface {
+	io.WriteCloser
+	Flush() error
+}
+
+// ParseList проверяет список алгоритмов (["zstd", "gzip"]) и приводит имена к нижнему регистру, сохраняя порядок
+// Пустой список означает, что сжатие выключено; неизвестный алгоритм - ошибка конфигурации
+func ParseList(list []string) ([]string, error) {
+	var names []string
+	for _, name := range list {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		if name != Gzip && name != Zstd {
+			return nil, fmt.Errorf("unknown compression %q (supported: %s, %s)", name, Gzip, Zstd)
+		}
+		names = append(names, name)
+	}
+	return names, nil
+}
+
+// NewWriter создает поток сжатия name в w
+func NewWriter(name string, w io.Writer) (Writer, error) {
+	switch name {
+	case Gzip:
+		return gzip.NewWriter(w), nil
+	case Zstd:
+		return zstd.NewWriter(w, zstd.WithEncoderConcurrency(1))
+	default:
+		return nil, fmt.Errorf("unknown compression %q", name)
+	}
+}
+
+// RegisterGRPC регистрирует компрессоры names в gRPC: сервер принимает сжатые ими запросы и сжимает
+// ответы тем же алгоритмом, клиент может выбрать их через grpc.UseCompressor
+// Регистрация глобальная, поэтому вызывается при запуске, до создания серверов и соединений
+func RegisterGRPC(names ...string) {
+	for _, name := range names {
+		encoding.RegisterCompressor(&grpcCompressor{name: name})
+	}
+}
+
+// grpcCompressor компрессор gRPC поверх NewWriter; декомпрессоры переиспользуются через пул
+type grpcCompressor struct {
+	name    string
+	readers sync.Pool
+}
+
+// Compress возвращает поток сжатия сообщения
+func (c *grpcCompressor) Compress(w io.Writer) (io.WriteCloser, error) {
+	return NewWriter(c.name, w)
+}
+
+// Decompress возвращает поток распаковки сообщения
+func (c *grpcCompressor) Decompress(r io.Reader) (io.Reader, error) {
+	switch c.name {
+	case Gzip:
+		if reader, ok := c.readers.Get().(*gzipReader); ok {
+			if err := reader.Reset(r); err != nil {
+				c.readers.Put(reader)
+				return nil, err
+			}
+			return reader, nil
+		}
+		reader, err := gzip.NewReader(r)
+		if err != nil {
+			return nil, err
+		}
+		return &gzipReader{Reader: reader, pool: &c.readers}, nil
+	default:
+		// Окно ограничено, чтобы небольшое сжатое сообщение не требовало большого буфера распаковки
+		decoder, err := zstd.NewReader(r, zstd.WithDecoderConcurrency(1), zstd.WithDecoderMaxWindow(zstdMaxWindow))
+		if err != nil {
+			return nil, err
+		}
+		return &zstdReader{decoder: decoder}, nil
+	}
+}
+
+// Name возвращает имя алгоритма для grpc-encoding
+func (c *grpcCompressor) Name() string {
+	return c.name
+}
+
+// gzipReader возвращает себя в пул после чтения сообщения до конца
+type gzipReader struct {
+	*gzip.Reader
+	pool *sync.Pool
+}
+
+func (r *gzipReader) Read(p []byte) (int, error) {
+	n, err := r.Reader.Read(p)
+	if err == io.EOF {
+		r.pool.Put(r)
+	}
+	return n, err
+}
+
+// zstdReader освобождает декодер после чтения сообщения до конца
+type zstdReader struct {
+	decoder *zstd.Decoder
+}
+
+func (r *zstdReader) Read(p []byte) (int, error) {
+	n, err := r.decoder.Read(p)
+	if err == io.EOF {
+		r.decoder.Close()
+	}
+	return n, err
+}
@@ -0,0 +1,69 @@
+package compression
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"google.golang.org/grpc/encoding"
+)
+
+func TestParseList(t *testing.T) {
+	names, err := ParseList([]string{" ZSTD", "gzip", ""})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if strings.Join(names, ",") != "zstd,gzip" {
+		t.Errorf("Expected [zstd gzip], got %v", names)
+	}
+
+	if names, err := ParseList(nil); err != nil || len(names) != 0 {
+		t.Errorf("Expected empty list, got %v, %v", names, err)
+	}
+	if _, err := ParseList([]string{"gzip", "br"}); err == nil {
+		t.Error("Expected error for unknown compression")
+	}
+}
+
+func TestRegisterGRPC(t *testing.T) {
+	RegisterGRPC(Gzip, Zstd)
+	message := []byte(strings.Repeat("note content ", 1000))
+
+	for _, name := range []string{Gzip, Zstd} {
+		compressor := encoding.GetCompressor(name)
+		if compressor == nil {
+			t.Fatalf("Expected %s compressor to be registered", name)
+		}
+
+		// Сообщения распаковываются несколько раз подряд: декомпрессоры переиспользуются
+		for i := 0; i < 3; i++ {
+			var compressed bytes.Buffer
+			writer, err := compressor.Compress(&compressed)
+			if err != nil {
+				t.Fatalf("%s: Compress: %v", name, err)
+			}
+			if _, err := writer.Write(message); err != nil {
+				t.Fatalf("%s: Write: %v", name, err)
+			}
+			if err := writer.Close(); err != nil {
+				t.Fatalf("%s: Close: %v", name, err)
+			}
+			if compressed.Len() >= len(message) {
+				t.Errorf("%s: expected compressed message to be smaller than %d bytes, got %d", name, len(message), compressed.Len())
+			}
+
+			reader, err := compressor.Decompress(&compressed)
+			if err != nil {
+				t.Fatalf("%s: Decompress: %v", name, err)
+			}
+			got, err := io.ReadAll(reader)
+			if err != nil {
+				t.Fatalf("%s: ReadAll: %v", name, err)
+			}
+			if !bytes.Equal(got, message) {
+				t.Errorf("%s: decompressed message does not match", name)
+			}
+		}
+	}
+}
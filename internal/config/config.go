@@ -1,16 +1,33 @@
 package config
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"reflect"
 	"regexp"
-	"strconv"
+	"slices"
 	"strings"
+	"time"
 
+	"github.com/go-viper/mapstructure/v2"
+	"github.com/spf13/pflag"
 	"github.com/spf13/viper"
 )
 
+// Option параметр чтения конфигурации (InitConfig, NewWatcher)
+type Option func(v *viper.Viper) error
+
+// WithFlag связывает настройку key с флагом командной строки (viper.BindPFlag):
+// заданный флаг важнее переменных окружения и файла, незаданный не меняет настройку
+func WithFlag(key string, flag *pflag.Flag) Option {
+	return func(v *viper.Viper) error {
+		return v.BindPFlag(key, flag)
+	}
+}
+
 // expandEnvWithDefaults расширяет переменные окружения с поддержкой дефолтных значений
 // Формат: ${VAR:-default}
 func expandEnvWithDefaults(s string) string {
@@ -42,7 +59,11 @@ func expandEnvWithDefaults(s string) string {
 
 // InitConfig читает конфигурационный файл и возвращает экземпляр конфигурации
 // Использует generic для работы с произвольным типом конфигурации
-func InitConfig[C any](configFile string) (*C, error) {
+// Переменные окружения и секреты подставляются в строки файла при разборе в поля C (decodeHook),
+// поэтому значение получает тип поля: строка остается строкой, "30s" становится time.Duration,
+// "a,b" - []string. Неизвестные ключи файла - ошибка; если C реализует Validate() error,
+// конфигурация проверяется им (вместе со значениями флагов из WithFlag)
+func InitConfig[C any](configFile string, opts ...Option) (*C, error) {
 	v := viper.New()
 	ext := strings.TrimLeft(filepath.Ext(configFile), ".")
 
@@ -52,32 +73,125 @@ func InitConfig[C any](configFile string) (*C, error) {
 	if err != nil {
 		return nil, fmt.Errorf("v.ReadInConfig: %w", err)
 	}
-
-	// Заменяем переменные окружения формата ${VAR:-default} на их значения
-	for _, k := range v.AllKeys() {
-		value := v.GetString(k)
-		if value == "" {
-			continue
-		}
-		// Используем кастомную функцию для поддержки дефолтных значений
-		expanded := expandEnvWithDefaults(value)
-
-		// Пытаемся определить тип значения и установить его правильно
-		// Если значение выглядит как число или boolean, пытаемся распарсить
-		if expanded == "true" || expanded == "false" {
-			boolValue, _ := strconv.ParseBool(expanded)
-			v.Set(k, boolValue)
-		} else if intValue, err := strconv.Atoi(expanded); err == nil {
-			v.Set(k, intValue)
-		} else {
-			v.Set(k, expanded)
+	for _, opt := range opts {
+		if err := opt(v); err != nil {
+			return nil, err
 		}
 	}
 
+	ctx, cancel := context.WithTimeout(context.Background(), secretsTimeout)
+	defer cancel()
+
+	// Ключи файла без поля в C (опечатки, устаревшие настройки) собираются в metadata.Unused
 	cfg := new(C)
-	if err := v.Unmarshal(cfg); err != nil {
-		return nil, fmt.Errorf("v.Unmarshal: %w", err)
+	var metadata mapstructure.Metadata
+	decodeErr := v.Unmarshal(cfg,
+		viper.DecodeHook(decodeHook(newSecretResolver(ctx))),
+		func(dc *mapstructure.DecoderConfig) { dc.Metadata = &metadata },
+	)
+
+	// Все ошибки конфигурации возвращаются сразу, чтобы исправить их за один запуск
+	var errs []error
+	if decodeErr != nil {
+		errs = append(errs, decodeErrors(decodeErr)...)
+	}
+	slices.Sort(metadata.Unused)
+	for _, key := range metadata.Unused {
+		errs = append(errs, fmt.Errorf("%s: unknown key", key))
+	}
+	// Конфигурация с ошибками разбора заполнена не полностью, ее проверка дала бы лишние ошибки
+	if decodeErr == nil {
+		if err := validate(cfg); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if err := errors.Join(errs...); err != nil {
+		return nil, fmt.Errorf("invalid config %s:\n%w", configFile, err)
 	}
 
 	return cfg, nil
 }
+
+// decodeHook подготавливает значения файла к разбору в поля конфигурации:
+// в строках заменяет ${VAR:-default} значениями переменных окружения, затем ссылки ${secret:...}
+// (в том числе пришедшие из переменных окружения) - значениями секретов, и преобразует
+// строки в time.Duration и []string. В остальные типы строки разбираются mapstructure
+func decodeHook(secrets *secretResolver) mapstructure.DecodeHookFunc {
+	expand := func(from, _ reflect.Type, data any) (any, error) {
+		if from.Kind() != reflect.String {
+			return data, nil
+		}
+		return secrets.resolve(expandEnvWithDefaults(data.(string)))
+	}
+	return mapstructure.ComposeDecodeHookFunc(expand, durationHook, stringSliceHook)
+}
+
+// durationHook разбирает time.Duration из строки с единицами ("30s", "1m30s")
+// Число без единиц - ошибка (кроме 0): иначе 30 означало бы 30 наносекунд
+func durationHook(from, to reflect.Type, data any) (any, error) {
+	if to != reflect.TypeFor[time.Duration]() {
+		return data, nil
+	}
+	switch from.Kind() {
+	case reflect.String:
+		value := strings.TrimSpace(data.(string))
+		if value == "" || value == "0" {
+			return time.Duration(0), nil
+		}
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid duration %q (use units, e.g. 30s, 5m or 1h)", value)
+		}
+		return d, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		if reflect.ValueOf(data).IsZero() {
+			return time.Duration(0), nil
+		}
+		return nil, fmt.Errorf("invalid duration %v (use units, e.g. 30s, 5m or 1h)", data)
+	}
+	return data, nil
+}
+
+// stringSliceHook разбирает []string из строки со значениями через запятую, например из переменной
+// окружения; пробелы вокруг значений и пустые значения отбрасываются. В файле список можно задать и списком YAML
+func stringSliceHook(from, to reflect.Type, data any) (any, error) {
+	if from.Kind() != reflect.String || to.Kind() != reflect.Slice || to.Elem().Kind() != reflect.String {
+		return data, nil
+	}
+	var values []string
+	for _, value := range strings.Split(data.(string), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values, nil
+}
+
+// decodeErrors разворачивает ошибку разбора конфигурации в ошибки отдельных настроек "ключ: ошибка"
+func decodeErrors(err error) []error {
+	switch e := err.(type) {
+	case interface{ Unwrap() []error }:
+		var errs []error
+		for _, inner := range e.Unwrap() {
+			errs = append(errs, decodeErrors(inner)...)
+		}
+		return errs
+	case *mapstructure.DecodeError:
+		return []error{fmt.Errorf("%s: %w", e.Name(), e.Unwrap())}
+	}
+	// Обертка mapstructure "decoding failed due to the following error(s)"
+	if inner := errors.Unwrap(err); inner != nil {
+		return decodeErrors(inner)
+	}
+	return []error{err}
+}
+
+// validate проверяет конфигурацию методом Validate, если тип конфигурации его реализует
+func validate(cfg any) error {
+	if validator, ok := cfg.(interface{ Validate() error }); ok {
+		return validator.Validate()
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type typedConfig struct {
+	Interval time.Duration `mapstructure:"interval"`
+	Timeout  time.Duration `mapstructure:"timeout"`
+	Origins  []string      `mapstructure:"origins"`
+	Methods  []string      `mapstructure:"methods"`
+	Key      string        `mapstructure:"key"`
+	Port     int           `mapstructure:"port"`
+}
+
+func loadTyped(t *testing.T, content string) (*typedConfig, error) {
+	t.Helper()
+	file := filepath.Join(t.TempDir(), "config.yml")
+	require.NoError(t, os.WriteFile(file, []byte(content), 0o600))
+	return InitConfig[typedConfig](file)
+}
+
+func TestInitConfig_Types(t *testing.T) {
+	t.Setenv("TEST_ORIGINS", "http://a.example, http://b.example,")
+	t.Setenv("TEST_KEY", "0123")
+	t.Setenv("TEST_PORT", "8080")
+
+	cfg, err := loadTyped(t, "interval: ${TEST_INTERVAL:-1m30s}\ntimeout: 0\n"+
+		"origins: ${TEST_ORIGINS:-}\nmethods:\n  - getnote\n  - listnotes\n"+
+		"key: ${TEST_KEY:-}\nport: ${TEST_PORT:-50051}\n")
+	require.NoError(t, err)
+	assert.Equal(t, 90*time.Second, cfg.Interval)
+	assert.Zero(t, cfg.Timeout)
+	// Список из переменной окружения через запятую и список YAML
+	assert.Equal(t, []string{"http://a.example", "http://b.example"}, cfg.Origins)
+	assert.Equal(t, []string{"getnote", "listnotes"}, cfg.Methods)
+	// Значение переменной получает тип поля: строка с ведущим нулем не становится числом
+	assert.Equal(t, "0123", cfg.Key)
+	assert.Equal(t, 8080, cfg.Port)
+
+	cfg, err = loadTyped(t, "origins: ${TEST_EMPTY:-}\n")
+	require.NoError(t, err)
+	assert.Empty(t, cfg.Origins)
+}
+
+func TestInitConfig_TypeErrors(t *testing.T) {
+	_, err := loadTyped(t, "interval: 30\ntimeout: 5 minutes\nport: ${TEST_PORT:-http}\n")
+	require.Error(t, err)
+	// Все ошибки возвращаются вместе, с именем настройки
+	assert.ErrorContains(t, err, "interval: invalid duration 30 (use units, e.g. 30s, 5m or 1h)")
+	assert.ErrorContains(t, err, `timeout: invalid duration "5 minutes"`)
+	assert.ErrorContains(t, err, "port: cannot parse value as 'int'")
+}
+
+func TestInitConfig_CompressionAndNoProxyLists(t *testing.T) {
+	// Значения через запятую из переменных окружения в config.yml проекта
+	t.Setenv("SERVER_COMPRESSION", "zstd, gzip")
+	t.Setenv("NO_PROXY", ".corp,10.0.0.0/8")
+	cfg, err := InitConfig[Config]("../../config.yml")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"zstd", "gzip"}, cfg.Server.Compression)
+	assert.Equal(t, []string{"zstd", "gzip"}, cfg.Gateway.Compression) // значение по умолчанию
+	assert.Equal(t, []string{".corp", "10.0.0.0/8"}, cfg.Egress.NoProxy)
+
+	// Списки YAML
+	file := filepath.Join(t.TempDir(), "config.yml")
+	require.NoError(t, os.WriteFile(file, []byte("server:\n  port_grpc: 50051\n  port_http: 8080\n  compression: [gzip]\n"+
+		"gateway:\n  compression:\n    - gzip\n    - zstd\n"+
+		"egress:\n  no_proxy:\n    - .svc.cluster.local\n    - 192.168.0.0/16\n"), 0o600))
+	cfg, err = InitConfig[Config](file)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"gzip"}, cfg.Server.Compression)
+	assert.Equal(t, []string{"gzip", "zstd"}, cfg.Gateway.Compression)
+	assert.Equal(t, []string{".svc.cluster.local", "192.168.0.0/16"}, cfg.Egress.NoProxy)
+}
@@ -0,0 +1,235 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	vault "github.com/hashicorp/vault/api"
+)
+
+// secretsTimeout время на получение всех секретов конфигурации
+const secretsTimeout = 30 * time.Second
+
+// secretRef ссылка на секрет в значении настройки: ${secret:<провайдер>:<ссылка>}
+// Ссылка может быть частью значения, например postgres://notes:${secret:vault:db/notes#password}@db/notes
+var secretRef = regexp.MustCompile(`\$\{secret:([a-z0-9_-]+):([^}]+)\}`)
+
+// SecretProvider возвращает значение секрета по ссылке ref (часть после имени провайдера)
+type SecretProvider interface {
+	Secret(ctx context.Context, ref string) (string, error)
+}
+
+// SecretProviderFunc позволяет использовать функцию как SecretProvider
+type SecretProviderFunc func(ctx context.Context, ref string) (string, error)
+
+// Secret вызывает f
+func (f SecretProviderFunc) Secret(ctx context.Context, ref string) (string, error) {
+	return f(ctx, ref)
+}
+
+var (
+	secretProvidersMu sync.RWMutex
+	// secretProviders провайдеры секретов по имени; параметры подключения берутся из переменных
+	// окружения при обращении к секрету, поэтому конфигурация без ссылок их не требует
+	secretProviders = map[string]SecretProvider{
+		"vault": SecretProviderFunc(vaultSecret),
+		"aws":   SecretProviderFunc(awsSecret),
+		"sops":  SecretProviderFunc(sopsSecret),
+	}
+)
+
+// RegisterSecretProvider добавляет или заменяет провайдер секретов name
+func RegisterSecretProvider(name string, provider SecretProvider) {
+	secretProvidersMu.Lock()
+	defer secretProvidersMu.Unlock()
+	secretProviders[name] = provider
+}
+
+// secretResolver подставляет секреты в значения одной загрузки конфигурации
+// Одинаковые ссылки запрашиваются у провайдера один раз
+type secretResolver struct {
+	ctx   context.Context
+	cache map[string]string
+}
+
+func newSecretResolver(ctx context.Context) *secretResolver {
+	return &secretResolver{ctx: ctx, cache: make(map[string]string)}
+}
+
+// resolve заменяет ссылки ${secret:...} в value значениями секретов
+// Ошибка содержит ссылку, но не значение секрета
+func (r *secretResolver) resolve(value string) (string, error) {
+	if !strings.Contains(value, "${secret:") {
+		return value, nil
+	}
+	var errs []error
+	resolved := secretRef.ReplaceAllStringFunc(value, func(match string) string {
+		if secret, ok := r.cache[match]; ok {
+			return secret
+		}
+		parts := secretRef.FindStringSubmatch(match)
+		name, ref := parts[1], parts[2]
+
+		secretProvidersMu.RLock()
+		provider, ok := secretProviders[name]
+		secretProvidersMu.RUnlock()
+		if !ok {
+			errs = append(errs, fmt.Errorf("unknown secret provider %q in %s (expected vault, aws or sops)", name, match))
+			return match
+		}
+		secret, err := provider.Secret(r.ctx, ref)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", match, err))
+			return match
+		}
+		r.cache[match] = secret
+		return secret
+	})
+	return resolved, errors.Join(errs...)
+}
+
+// splitSecretRef разделяет ссылку на путь секрета и поле: path#field
+func splitSecretRef(ref string) (path, field string) {
+	path, field, _ = strings.Cut(ref, "#")
+	return path, field
+}
+
+// vaultSecret читает поле секрета KV v2 из HashiCorp Vault: <mount>/<path>#<поле>
+// Адрес, токен, пространство имен и TLS берет клиент Vault из VAULT_ADDR, VAULT_TOKEN, VAULT_NAMESPACE
+// и VAULT_CACERT; без VAULT_TOKEN используется ~/.vault-token
+func vaultSecret(ctx context.Context, ref string) (string, error) {
+	path, field := splitSecretRef(ref)
+	mount, secretPath, ok := strings.Cut(strings.Trim(path, "/"), "/")
+	if !ok || field == "" {
+		return "", errors.New("expected vault:<mount>/<path>#<field>")
+	}
+	if os.Getenv("VAULT_ADDR") == "" {
+		return "", errors.New("VAULT_ADDR is not set")
+	}
+	cfg := vault.DefaultConfig()
+	if cfg.Error != nil {
+		return "", fmt.Errorf("vault config: %w", cfg.Error)
+	}
+	client, err := vault.NewClient(cfg)
+	if err != nil {
+		return "", fmt.Errorf("vault client: %w", err)
+	}
+	if client.Token() == "" {
+		if home, err := os.UserHomeDir(); err == nil {
+			data, _ := os.ReadFile(filepath.Join(home, ".vault-token"))
+			client.SetToken(strings.TrimSpace(string(data)))
+		}
+	}
+	if client.Token() == "" {
+		return "", errors.New("VAULT_TOKEN is not set")
+	}
+
+	secret, err := client.KVv2(mount).Get(ctx, secretPath)
+	if err != nil {
+		return "", err
+	}
+	value, ok := secret.Data[field]
+	if !ok {
+		return "", fmt.Errorf("field %q not found", field)
+	}
+	if s, ok := value.(string); ok {
+		return s, nil
+	}
+	// Числа и вложенные значения возвращаются в виде JSON
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return "", fmt.Errorf("field %q: %w", field, err)
+	}
+	return string(raw), nil
+}
+
+// awsSecret читает секрет AWS Secrets Manager: <имя или ARN>[#поле JSON]
+// Регион, учетные данные и адрес сервиса берутся стандартной цепочкой AWS SDK: AWS_REGION,
+// AWS_ACCESS_KEY_ID/AWS_SECRET_ACCESS_KEY, AWS_PROFILE и ~/.aws, роль задачи ECS, IRSA или EC2;
+// AWS_ENDPOINT_URL_SECRETS_MANAGER (AWS_ENDPOINT_URL) заменяет адрес сервиса, например для LocalStack
+func awsSecret(ctx context.Context, ref string) (string, error) {
+	id, field := splitSecretRef(ref)
+	cfg, err := awsconfig.LoadDefaultConfig(ctx)
+	if err != nil {
+		return "", fmt.Errorf("load AWS config: %w", err)
+	}
+	if cfg.Region == "" {
+		return "", errors.New("AWS_REGION is not set")
+	}
+	out, err := secretsmanager.NewFromConfig(cfg).GetSecretValue(ctx, &secretsmanager.GetSecretValueInput{
+		SecretId: aws.String(id),
+	})
+	if err != nil {
+		return "", err
+	}
+	value := string(out.SecretBinary)
+	if out.SecretString != nil {
+		value = *out.SecretString
+	}
+	if field == "" {
+		return value, nil
+	}
+	// Секрет из нескольких значений хранится как JSON объект
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(value), &fields); err != nil {
+		return "", fmt.Errorf("secret is not a JSON object, field %q cannot be extracted", field)
+	}
+	raw, ok := fields[field]
+	if !ok {
+		return "", fmt.Errorf("field %q not found", field)
+	}
+	return jsonString(raw), nil
+}
+
+// sopsSecret расшифровывает файл SOPS утилитой sops: <файл>[#путь.к.полю]
+// Без поля возвращается весь расшифрованный файл; ключи расшифровки sops берет из своего окружения
+// (SOPS_AGE_KEY_FILE, AWS KMS, GPG)
+func sopsSecret(ctx context.Context, ref string) (string, error) {
+	file, field := splitSecretRef(ref)
+	args := []string{"--decrypt"}
+	if field != "" {
+		var extract strings.Builder
+		for _, key := range strings.Split(field, ".") {
+			fmt.Fprintf(&extract, "[%q]", key)
+		}
+		args = append(args, "--extract", extract.String())
+	}
+	args = append(args, file)
+
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "sops", args...)
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil {
+		if message := strings.TrimSpace(stderr.String()); message != "" {
+			return "", fmt.Errorf("sops: %w: %s", err, message)
+		}
+		return "", fmt.Errorf("sops: %w", err)
+	}
+	if field != "" {
+		return strings.TrimRight(string(output), "\n"), nil
+	}
+	return string(output), nil
+}
+
+// jsonString возвращает строку JSON без кавычек, остальные значения - как есть
+func jsonString(raw json.RawMessage) string {
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return s
+	}
+	return string(raw)
+}
@@ -0,0 +1,139 @@
+package config
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVaultSecret(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "vault-token" {
+			http.Error(w, `{"errors":["permission denied"]}`, http.StatusForbidden)
+			return
+		}
+		if r.URL.Path != "/v1/secret/data/notes/auth" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = io.WriteString(w, `{"data":{"data":{"signing_key":"s3cr3t","ttl":900},"metadata":{"version":3}}}`)
+	}))
+	defer server.Close()
+	t.Setenv("VAULT_ADDR", server.URL)
+	t.Setenv("VAULT_TOKEN", "vault-token")
+
+	value, err := vaultSecret(context.Background(), "secret/notes/auth#signing_key")
+	require.NoError(t, err)
+	assert.Equal(t, "s3cr3t", value)
+
+	value, err = vaultSecret(context.Background(), "secret/notes/auth#ttl")
+	require.NoError(t, err)
+	assert.Equal(t, "900", value)
+
+	_, err = vaultSecret(context.Background(), "secret/notes/auth#missing")
+	assert.ErrorContains(t, err, `field "missing" not found`)
+
+	_, err = vaultSecret(context.Background(), "secret/notes/auth")
+	assert.ErrorContains(t, err, "expected vault:<mount>/<path>#<field>")
+
+	t.Setenv("VAULT_TOKEN", "wrong")
+	_, err = vaultSecret(context.Background(), "secret/notes/auth#signing_key")
+	assert.ErrorContains(t, err, "permission denied")
+}
+
+func TestAWSSecret(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "secretsmanager.GetSecretValue", r.Header.Get("X-Amz-Target"))
+		assert.Contains(t, r.Header.Get("Authorization"), "/eu-west-1/secretsmanager/aws4_request")
+		assert.Equal(t, "session", r.Header.Get("X-Amz-Security-Token"))
+		body, _ := io.ReadAll(r.Body)
+		switch string(body) {
+		case `{"SecretId":"notes/db"}`:
+			_, _ = io.WriteString(w, `{"Name":"notes/db","SecretString":"{\"username\":\"notes\",\"password\":\"p@ss\"}"}`)
+		case `{"SecretId":"notes/plain"}`:
+			_, _ = io.WriteString(w, `{"Name":"notes/plain","SecretString":"plain-value"}`)
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = io.WriteString(w, `{"__type":"ResourceNotFoundException","message":"Secrets Manager can't find the specified secret."}`)
+		}
+	}))
+	defer server.Close()
+	t.Setenv("AWS_REGION", "eu-west-1")
+	t.Setenv("AWS_ACCESS_KEY_ID", "AKIDEXAMPLE")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "secret")
+	t.Setenv("AWS_SESSION_TOKEN", "session")
+	t.Setenv("AWS_ENDPOINT_URL_SECRETS_MANAGER", server.URL)
+
+	value, err := awsSecret(context.Background(), "notes/db#password")
+	require.NoError(t, err)
+	assert.Equal(t, "p@ss", value)
+
+	value, err = awsSecret(context.Background(), "notes/plain")
+	require.NoError(t, err)
+	assert.Equal(t, "plain-value", value)
+
+	_, err = awsSecret(context.Background(), "notes/plain#password")
+	assert.ErrorContains(t, err, "not a JSON object")
+
+	_, err = awsSecret(context.Background(), "notes/missing")
+	assert.ErrorContains(t, err, "ResourceNotFoundException")
+}
+
+func TestSopsSecret(t *testing.T) {
+	// Вместо sops - скрипт, который печатает аргументы
+	bin := t.TempDir()
+	script := "#!/bin/sh\necho \"$@\"\n"
+	require.NoError(t, os.WriteFile(filepath.Join(bin, "sops"), []byte(script), 0o755))
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	value, err := sopsSecret(context.Background(), "secrets.enc.yml#auth.session.signing_key")
+	require.NoError(t, err)
+	assert.Equal(t, `--decrypt --extract ["auth"]["session"]["signing_key"] secrets.enc.yml`, value)
+
+	value, err = sopsSecret(context.Background(), "key.pem.enc")
+	require.NoError(t, err)
+	assert.Equal(t, "--decrypt key.pem.enc\n", value)
+}
+
+func TestInitConfig_Secrets(t *testing.T) {
+	requests := 0
+	RegisterSecretProvider("test", SecretProviderFunc(func(_ context.Context, ref string) (string, error) {
+		requests++
+		if ref == "missing" {
+			return "", os.ErrNotExist
+		}
+		return strings.ToUpper(ref), nil
+	}))
+
+	dir := t.TempDir()
+	file := filepath.Join(dir, "config.yml")
+	content := "server:\n  port_grpc: 50051\n  port_http: 8080\ngateway:\n  grpc_web: true\n" +
+		"metrics:\n  postgres_url: postgres://notes:${secret:test:password}@db/notes\n" +
+		"auth:\n  sessions:\n    signing_key: ${AUTH_SESSION_SIGNING_KEY:-}\n" +
+		"  stream_tickets:\n    signing_key: ${secret:test:password}\n"
+	require.NoError(t, os.WriteFile(file, []byte(content), 0o600))
+	t.Setenv("AUTH_SESSION_SIGNING_KEY", "${secret:test:0123}")
+
+	cfg, err := InitConfig[Config](file)
+	require.NoError(t, err)
+	assert.Equal(t, "postgres://notes:PASSWORD@db/notes", cfg.Metrics.PostgresURL)
+	assert.Equal(t, "PASSWORD", cfg.Auth.StreamTickets.SigningKey)
+	// Секрет из переменной окружения остается строкой
+	assert.Equal(t, "0123", cfg.Auth.Sessions.SigningKey)
+	assert.Equal(t, 2, requests, "same reference must be requested once")
+
+	content += "debug:\n  addr: ${secret:test:missing}\n  enabled: ${secret:nope:x}\n"
+	require.NoError(t, os.WriteFile(file, []byte(content), 0o600))
+	_, err = InitConfig[Config](file)
+	require.Error(t, err)
+	assert.ErrorContains(t, err, "debug.addr: ${secret:test:missing}: file does not exist")
+	assert.ErrorContains(t, err, `unknown secret provider "nope"`)
+}
@@ -1,28 +1,154 @@
 package config
 
+import "time"
+
 // ConfigLogger настройки логирования
 type ConfigLogger struct {
-	Level string `mapstructure:"level"`
+	Level               string   `mapstructure:"level"`
+	PayloadSampleRate   float64  `mapstructure:"payload_sample_rate"`   // Доля unary запросов, тела которых логируются (0 - не логируются, 1 - все)
+	PayloadRedactFields []string `mapstructure:"payload_redact_fields"` // Поля, заменяемые в логе заглушкой той же длины
 }
 
+// Режимы окружения server.environment
+const (
+	EnvironmentDevelopment = "development"
+	EnvironmentStaging     = "staging"
+	EnvironmentProduction  = "production"
+)
+
 // ConfigServer настройки сервера
 type ConfigServer struct {
-	UseReflection           bool `mapstructure:"use_reflection"`
-	PortGRPC                int  `mapstructure:"port_grpc"`
-	PortHTTP                int  `mapstructure:"port_http"`
-	HTTPReadTimeout         int  `mapstructure:"http_read_timeout"`
-	HTTPWriteTimeout        int  `mapstructure:"http_write_timeout"`
-	HTTPIdleTimeout         int  `mapstructure:"http_idle_timeout"`
-	HTTPReadHeaderTimeout   int  `mapstructure:"http_read_header_timeout"`
-	GracefulShutdownTimeout int  `mapstructure:"graceful_shutdown_timeout"`
+	// Environment - режим окружения: development, staging или production (в production reflection выключен)
+	Environment   string `mapstructure:"environment"`
+	UseReflection bool   `mapstructure:"use_reflection"`
+	// ReflectionServices - сервисы, доступные через reflection (пусто - все)
+	ReflectionServices []string `mapstructure:"reflection_services"`
+	PortGRPC           int      `mapstructure:"port_grpc"`
+	PortHTTP           int      `mapstructure:"port_http"`
+	// SinglePort - gRPC и HTTP Gateway на одном порту port_http (соединения различаются по первым байтам)
+	SinglePort bool `mapstructure:"single_port"`
+	// WatchConfig - перечитывать config.yml при изменении и применять настройки, которые меняются без перезапуска
+	WatchConfig bool `mapstructure:"watch_config"`
+	// Таймауты HTTP Gateway в секундах (0 - без ограничения); таймауты чтения и записи
+	// не действуют на streaming методы и WebSocket соединения
+	HTTPReadTimeout         int      `mapstructure:"http_read_timeout"`
+	HTTPWriteTimeout        int      `mapstructure:"http_write_timeout"`
+	HTTPIdleTimeout         int      `mapstructure:"http_idle_timeout"`
+	HTTPReadHeaderTimeout   int      `mapstructure:"http_read_header_timeout"`
+	GracefulShutdownTimeout int      `mapstructure:"graceful_shutdown_timeout"` // Ожидание активных запросов gRPC и Gateway при остановке
+	IdempotencyTTLSeconds   int      `mapstructure:"idempotency_ttl_seconds"`   // Время хранения ключей идемпотентности CreateNote
+	AccessDeniedPolicy      string   `mapstructure:"access_denied_policy"`      // Ответ на обращение к чужой заметке: not_found или permission_denied
+	EventLogSize            int      `mapstructure:"event_log_size"`            // Количество последних событий для повторной доставки SubscribeToEvents
+	PublicMethods           []string `mapstructure:"public_methods"`            // Методы gRPC без токена ("/<сервис>/<метод>" или "/<сервис>/*")
+	Compression             []string `mapstructure:"compression"`               // Алгоритмы сжатия сообщений gRPC: gzip, zstd (пусто - без сжатия)
+
+	// TLS - TLS и mTLS gRPC сервера и подключения к нему Gateway (без сертификата - plaintext)
+	TLS ConfigServerTLS `mapstructure:"tls"`
+
+	// StreamRateLimits - лимиты входящих сообщений одного стрима по методам
+	// Ключ - имя метода NotesService в нижнем регистре (например, "chat", "uploadmetrics")
+	StreamRateLimits map[string]ConfigStreamRateLimit `mapstructure:"stream_rate_limits"`
+
+	// StreamLifetimes - длительность стримов и время без сообщений клиента по методам (ключи как в stream_rate_limits)
+	StreamLifetimes map[string]ConfigStreamLifetime `mapstructure:"stream_lifetimes"`
+
+	// MethodTimeoutsSeconds - дедлайн unary запросов по методам, если клиент не передал свой
+	// (ключи - имена unary методов NotesService в нижнем регистре, 0 - без дедлайна)
+	MethodTimeoutsSeconds map[string]int `mapstructure:"method_timeouts_seconds"`
+
+	// RateLimit - лимит запросов одного клиента gRPC сервера, включая запросы через Gateway
+	RateLimit ConfigServerRateLimit `mapstructure:"rate_limit"`
+
+	// Limits - размер сообщений, одновременные стримы соединения и keepalive gRPC сервера
+	Limits ConfigServerLimits `mapstructure:"limits"`
+}
+
+// ConfigServerLimits ограничения gRPC сервера (0 - значение gRPC по умолчанию или без ограничения)
+type ConfigServerLimits struct {
+	MaxRecvMsgSizeKB     int `mapstructure:"max_recv_msg_size_kb"`   // Входящее сообщение (0 - 4 МБ)
+	MaxSendMsgSizeKB     int `mapstructure:"max_send_msg_size_kb"`   // Исходящее сообщение (0 - без ограничения)
+	MaxConcurrentStreams int `mapstructure:"max_concurrent_streams"` // Одновременные стримы и unary вызовы одного соединения
+
+	// Keepalive в секундах: пинги сервера и ротация соединений
+	KeepaliveTimeSeconds         int `mapstructure:"keepalive_time_seconds"`           // Интервал пингов неактивного соединения (0 - 2 часа)
+	KeepaliveTimeoutSeconds      int `mapstructure:"keepalive_timeout_seconds"`        // Ожидание ответа на пинг (0 - 20 секунд)
+	MaxConnectionIdleSeconds     int `mapstructure:"max_connection_idle_seconds"`      // Закрытие соединения без запросов
+	MaxConnectionAgeSeconds      int `mapstructure:"max_connection_age_seconds"`       // Максимальное время жизни соединения
+	MaxConnectionAgeGraceSeconds int `mapstructure:"max_connection_age_grace_seconds"` // Ожидание активных запросов после max_connection_age
+
+	// Пинги клиентов: чаще keepalive_min_time_seconds (0 - 5 минут) соединение закрывается с too_many_pings
+	KeepaliveMinTimeSeconds      int  `mapstructure:"keepalive_min_time_seconds"`
+	KeepalivePermitWithoutStream bool `mapstructure:"keepalive_permit_without_stream"` // Разрешить пинги без активных запросов
+}
+
+// ConfigServerRateLimit лимит запросов клиента gRPC сервера (token bucket)
+type ConfigServerRateLimit struct {
+	RequestsPerSecond float64 `mapstructure:"requests_per_second"` // 0 - без ограничения
+	Burst             int     `mapstructure:"burst"`               // Сколько запросов можно выполнить подряд
+	Key               string  `mapstructure:"key"`                 // user (пользователь, без него - IP адрес) или peer (IP адрес)
+}
+
+// ConfigServerTLS настройки TLS gRPC сервера
+type ConfigServerTLS struct {
+	CertFile     string `mapstructure:"cert_file"`      // PEM с сертификатом сервера
+	KeyFile      string `mapstructure:"key_file"`       // PEM с ключом сертификата сервера
+	ClientCAFile string `mapstructure:"client_ca_file"` // PEM с центрами сертификатов клиентов: задан - требуется mTLS
+	MinVersion   string `mapstructure:"min_version"`    // 1.2 (по умолчанию) или 1.3
+
+	// Подключение Gateway к gRPC серверу
+	CAFile         string `mapstructure:"ca_file"`          // PEM с центрами сертификата сервера (пусто - системные)
+	ClientCertFile string `mapstructure:"client_cert_file"` // Сертификат Gateway для mTLS (пусто - cert_file)
+	ClientKeyFile  string `mapstructure:"client_key_file"`  // Ключ сертификата Gateway (пусто - key_file)
+	ServerName     string `mapstructure:"server_name"`      // Имя в сертификате сервера
+}
+
+// ConfigStreamRateLimit лимит входящих сообщений стрима (token bucket)
+type ConfigStreamRateLimit struct {
+	MessagesPerSecond float64 `mapstructure:"messages_per_second"` // 0 - без ограничения
+	Burst             int     `mapstructure:"burst"`               // Сколько сообщений можно отправить подряд
+}
+
+// ConfigStreamLifetime ограничения времени жизни стрима
+type ConfigStreamLifetime struct {
+	MaxDurationSeconds int `mapstructure:"max_duration_seconds"` // 0 - без ограничения
+	IdleTimeoutSeconds int `mapstructure:"idle_timeout_seconds"` // Время без сообщений клиента, 0 - без ограничения
 }
 
 // ConfigGateway настройки HTTP Gateway
 type ConfigGateway struct {
-	CORSAllowedOrigins string `mapstructure:"cors_allowed_origins"`
-	CORSMaxAge         int    `mapstructure:"cors_max_age"`
-	RateLimitRPS       int    `mapstructure:"rate_limit_rps"`
-	RateLimitBurst     int    `mapstructure:"rate_limit_burst"`
+	CORSAllowedOrigins []string         `mapstructure:"cors_allowed_origins"`
+	CORSMaxAge         int              `mapstructure:"cors_max_age"`
+	RateLimitRPS       int              `mapstructure:"rate_limit_rps"`
+	RateLimitBurst     int              `mapstructure:"rate_limit_burst"`
+	Upstreams          []ConfigUpstream `mapstructure:"upstreams"`
+
+	// ShutdownDrainSeconds - сколько секунд при остановке отвечать 503 на новые запросы перед закрытием порта
+	ShutdownDrainSeconds int `mapstructure:"shutdown_drain_seconds"`
+
+	// AuthCookieSecure - выставлять атрибут Secure cookie с токенами сессий (Gateway за HTTPS)
+	AuthCookieSecure bool `mapstructure:"auth_cookie_secure"`
+
+	// ForwardHeaders - заголовки HTTP запроса, передаваемые gRPC серверу в метаданных
+	// с тем же именем в нижнем регистре, дополнительно к встроенным (Authorization, X-Request-Id и т.д.)
+	ForwardHeaders []string `mapstructure:"forward_headers"`
+	// ResponseHeaders - ключи метаданных ответа gRPC, возвращаемые в HTTP заголовках и трейлерах
+	// без префикса Grpc-Metadata-: "ключ" или "ключ=Имя-Заголовка"
+	ResponseHeaders []string `mapstructure:"response_headers"`
+
+	// GRPCWeb - принимать вызовы gRPC-Web (Content-Type application/grpc-web*) на HTTP порту
+	GRPCWeb bool `mapstructure:"grpc_web"`
+
+	// Compression - алгоритмы сжатия ответов в порядке предпочтения: zstd, gzip (пусто - без сжатия)
+	Compression []string `mapstructure:"compression"`
+	// CompressionMinSize - ответы короче этого размера в байтах не сжимаются
+	CompressionMinSize int `mapstructure:"compression_min_size"`
+}
+
+// ConfigUpstream дополнительный gRPC сервис, проксируемый через Gateway
+type ConfigUpstream struct {
+	Name        string `mapstructure:"name"`         // Полное имя сервиса (например, "billing.v1.BillingService")
+	Address     string `mapstructure:"address"`      // Адрес gRPC сервера (host:port)
+	SwaggerSpec string `mapstructure:"swagger_spec"` // Путь к swagger.json сервиса (опционально)
 }
 
 // ConfigSwagger настройки Swagger UI сервера
@@ -30,10 +156,310 @@ type ConfigSwagger struct {
 	Enabled bool `mapstructure:"enabled"`
 }
 
+// ConfigStorage настройки хранилища заметок
+type ConfigStorage struct {
+	Driver string `mapstructure:"driver"` // memory (пусто - memory)
+}
+
+// ConfigAttachments настройки хранилища вложений заметок
+type ConfigAttachments struct {
+	Storage        string `mapstructure:"storage"` // filesystem, s3 или пусто (вложения отключены)
+	Dir            string `mapstructure:"dir"`     // Каталог для storage = filesystem
+	MaxSizeMB      int    `mapstructure:"max_size_mb"`
+	S3Endpoint     string `mapstructure:"s3_endpoint"`
+	S3Region       string `mapstructure:"s3_region"`
+	S3Bucket       string `mapstructure:"s3_bucket"`
+	S3Prefix       string `mapstructure:"s3_prefix"`
+	S3AccessKey    string `mapstructure:"s3_access_key"`
+	S3SecretKey    string `mapstructure:"s3_secret_key"`
+	S3UsePathStyle bool   `mapstructure:"s3_use_path_style"`
+}
+
+// ConfigExports настройки хранилища выгрузок заметок (ExportToDestination)
+type ConfigExports struct {
+	Destination    string `mapstructure:"destination"` // filesystem, s3 или пусто (выгрузка отключена)
+	Dir            string `mapstructure:"dir"`         // Каталог для destination = filesystem
+	S3Endpoint     string `mapstructure:"s3_endpoint"`
+	S3Region       string `mapstructure:"s3_region"`
+	S3Bucket       string `mapstructure:"s3_bucket"`
+	S3Prefix       string `mapstructure:"s3_prefix"`
+	S3AccessKey    string `mapstructure:"s3_access_key"`
+	S3SecretKey    string `mapstructure:"s3_secret_key"`
+	S3UsePathStyle bool   `mapstructure:"s3_use_path_style"`
+}
+
+// ConfigBackups настройки резервного копирования хранилища (RestoreBackup)
+type ConfigBackups struct {
+	Destination     string `mapstructure:"destination"`      // filesystem, s3 или пусто (копирование отключено)
+	Dir             string `mapstructure:"dir"`              // Каталог для destination = filesystem
+	IntervalMinutes int    `mapstructure:"interval_minutes"` // Интервал копирования (0 - раз в час)
+	Keep            int    `mapstructure:"keep"`             // Количество хранимых копий (0 - 24)
+	S3Endpoint      string `mapstructure:"s3_endpoint"`
+	S3Region        string `mapstructure:"s3_region"`
+	S3Bucket        string `mapstructure:"s3_bucket"`
+	S3Prefix        string `mapstructure:"s3_prefix"`
+	S3AccessKey     string `mapstructure:"s3_access_key"`
+	S3SecretKey     string `mapstructure:"s3_secret_key"`
+	S3UsePathStyle  bool   `mapstructure:"s3_use_path_style"`
+}
+
+// ConfigDegraded настройки режима деградации при недоступности хранилища заметок
+type ConfigDegraded struct {
+	Enabled                 bool `mapstructure:"enabled"`
+	CheckIntervalSeconds    int  `mapstructure:"check_interval_seconds"`    // Интервал проверки хранилища (0 - 5 секунд)
+	FailureThreshold        int  `mapstructure:"failure_threshold"`         // Неудачных проверок подряд до включения режима (0 - 3)
+	SnapshotIntervalSeconds int  `mapstructure:"snapshot_interval_seconds"` // Интервал полного обновления снимка (0 - минута)
+}
+
+// ConfigMirror настройки дублирования запросов чтения во второе хранилище заметок
+// Второе хранилище передается через server.WithMirrorRepository
+type ConfigMirror struct {
+	Enabled        bool     `mapstructure:"enabled"`
+	Percent        float64  `mapstructure:"percent"`         // Доля дублируемых запросов в процентах (0-100)
+	Methods        []string `mapstructure:"methods"`         // Методы чтения в нижнем регистре (например, getnote, listnotes)
+	TimeoutSeconds int      `mapstructure:"timeout_seconds"` // Время выполнения запроса во втором хранилище (0 - 5 секунд)
+	MaxInFlight    int      `mapstructure:"max_in_flight"`   // Одновременно выполняемых запросов, лишние не дублируются (0 - 16)
+}
+
+// ConfigMetrics настройки хранилища значений метрик, загруженных UploadMetrics и StreamMetrics (QueryMetrics)
+type ConfigMetrics struct {
+	Storage     string `mapstructure:"storage"`      // memory, postgres или пусто (значения не сохраняются)
+	PostgresURL string `mapstructure:"postgres_url"` // Адрес PostgreSQL для storage = postgres
+}
+
+// ConfigTelemetry метрики сервера на /metrics: запросы gRPC и Gateway, стримы, лимиты и хранилище заметок
+type ConfigTelemetry struct {
+	Enabled bool `mapstructure:"enabled"` // Сбор метрик (false - /metrics отдает только метрики остальных компонентов)
+}
+
+// ConfigTracing настройки трассировки OpenTelemetry
+type ConfigTracing struct {
+	OTLPEndpoint string  `mapstructure:"otlp_endpoint"` // Адрес OTLP/gRPC коллектора (host:port), пусто - трассировка выключена
+	ServiceName  string  `mapstructure:"service_name"`  // Имя сервиса в трассах
+	SampleRatio  float64 `mapstructure:"sample_ratio"`  // Доля записываемых новых трасс (0-1)
+}
+
+// ConfigEncryption настройки шифрования содержимого заметок в хранилище (AES-GCM)
+type ConfigEncryption struct {
+	Key          string   `mapstructure:"key"`           // Мастер-ключ в base64 (16, 24 или 32 байта), пусто - шифрование выключено
+	PreviousKeys []string `mapstructure:"previous_keys"` // Прежние ключи в base64 для чтения после смены ключа
+}
+
+// ConfigUsage настройки обезличенной статистики использования (GetUsageStats)
+type ConfigUsage struct {
+	Enabled               bool   `mapstructure:"enabled"`                 // Сбор статистики (false - ничего не учитывается и не отправляется)
+	Endpoint              string `mapstructure:"endpoint"`                // Адрес для отправки статистики (пусто - только локально)
+	ReportIntervalMinutes int    `mapstructure:"report_interval_minutes"` // Интервал отправки (0 - раз в сутки)
+}
+
+// ConfigAudit настройки журнала аудита (ListAuditEvents)
+type ConfigAudit struct {
+	Enabled       bool `mapstructure:"enabled"`        // Запись изменений заметок и доступа к ним
+	RetentionDays int  `mapstructure:"retention_days"` // Срок хранения записей (0 - без ограничения по времени)
+	MaxRecords    int  `mapstructure:"max_records"`    // Максимальное количество хранимых записей (0 - 100000)
+}
+
+// ConfigWebhooks настройки доставки событий вебхукам (RegisterWebhook)
+type ConfigWebhooks struct {
+	MaxAttempts          int  `mapstructure:"max_attempts"`           // Количество попыток доставки события
+	TimeoutSeconds       int  `mapstructure:"timeout_seconds"`        // Время ожидания ответа вебхука
+	AllowPrivateNetworks bool `mapstructure:"allow_private_networks"` // Разрешить адреса внутренних сетей (loopback, 10.0.0.0/8 и т.п.)
+}
+
+// ConfigEgress настройки исходящих подключений: вебхуки, OIDC, S3, NATS, Redis, PostgreSQL,
+// отправка статистики и upstream сервисы Gateway
+type ConfigEgress struct {
+	HTTPProxy    string                    `mapstructure:"http_proxy"`    // Прокси для http:// адресов
+	HTTPSProxy   string                    `mapstructure:"https_proxy"`   // Прокси для https:// адресов и TCP подключений (CONNECT)
+	NoProxy      []string                  `mapstructure:"no_proxy"`      // Адреса без прокси (как в NO_PROXY)
+	AllowedHosts []string                  `mapstructure:"allowed_hosts"` // Разрешенные адреса (пусто - все)
+	Destinations []ConfigEgressDestination `mapstructure:"destinations"`  // Таймауты и TLS отдельных адресов
+}
+
+// ConfigEgressDestination настройки подключений к адресам, подходящим под шаблон host
+type ConfigEgressDestination struct {
+	Host           string          `mapstructure:"host"`            // host, *.domain, IP или CIDR, с :port или без
+	TimeoutSeconds int             `mapstructure:"timeout_seconds"` // Время HTTP запроса или подключения по TCP (0 - таймаут клиента)
+	TLS            ConfigEgressTLS `mapstructure:"tls"`
+}
+
+// ConfigEgressTLS настройки TLS подключений к адресу
+type ConfigEgressTLS struct {
+	Enabled            bool   `mapstructure:"enabled"`              // TLS для gRPC upstream (HTTP - по схеме адреса, PostgreSQL - по sslmode)
+	CAFile             string `mapstructure:"ca_file"`              // PEM с сертификатами удостоверяющих центров
+	CertFile           string `mapstructure:"cert_file"`            // PEM с сертификатом клиента
+	KeyFile            string `mapstructure:"key_file"`             // PEM с ключом клиента
+	ServerName         string `mapstructure:"server_name"`          // Имя сервера для проверки сертификата
+	InsecureSkipVerify bool   `mapstructure:"insecure_skip_verify"` // Не проверять сертификат сервера
+	MinVersion         string `mapstructure:"min_version"`          // 1.2 (по умолчанию) или 1.3
+}
+
+// ConfigTenants настройки тенантов: значения по умолчанию и переопределения по тенанту
+type ConfigTenants struct {
+	CacheTTLSeconds int                     `mapstructure:"cache_ttl_seconds"` // Время кэширования настроек тенанта
+	Defaults        ConfigTenant            `mapstructure:"defaults"`
+	Overrides       map[string]ConfigTenant `mapstructure:"overrides"` // Ключ - ID тенанта (в нижнем регистре)
+}
+
+// ConfigTenant настройки тенанта, незаданные поля наследуются из defaults
+type ConfigTenant struct {
+	RateLimitRPS   *int            `mapstructure:"rate_limit_rps"`
+	RateLimitBurst *int            `mapstructure:"rate_limit_burst"`
+	MaxNotes       *int            `mapstructure:"max_notes"`
+	Features       map[string]bool `mapstructure:"features"`
+
+	QuotaWarningPercents []int `mapstructure:"quota_warning_percents"` // Мягкие пороги квоты в процентах
+}
+
+// ConfigRecorder настройки записи запросов для воспроизведения через cmd/replay
+type ConfigRecorder struct {
+	Enabled        bool     `mapstructure:"enabled"`
+	Dir            string   `mapstructure:"dir"`             // Каталог файлов записи
+	MaxSegments    int      `mapstructure:"max_segments"`    // Количество файлов кольцевого буфера
+	SegmentRecords int      `mapstructure:"segment_records"` // Количество запросов в одном файле
+	RedactFields   []string `mapstructure:"redact_fields"`   // Поля, заменяемые заглушкой той же длины
+}
+
+// ConfigDebug настройки отладочного порта: pprof, стеки горутин, метрики среды выполнения и channelz
+type ConfigDebug struct {
+	Enabled bool   `mapstructure:"enabled"` // Открыть отладочный порт
+	Addr    string `mapstructure:"addr"`    // Адрес порта (host:port), по умолчанию только localhost
+}
+
+// ConfigSelfTest настройки проверки запущенного сервера через его порты (internal/selftest)
+type ConfigSelfTest struct {
+	Token          string `mapstructure:"token"`           // Токен пользователя, от имени которого выполняется проверка
+	TimeoutSeconds int    `mapstructure:"timeout_seconds"` // Время на готовность сервера и все шаги проверки
+	OnStartup      bool   `mapstructure:"on_startup"`      // Проверять после каждого запуска и завершаться при ошибке
+}
+
+// ConfigEvents настройки доставки событий SubscribeToEvents между репликами сервера
+type ConfigEvents struct {
+	Broker      string `mapstructure:"broker"`       // memory (в пределах процесса, по умолчанию), nats или redis
+	NATSURL     string `mapstructure:"nats_url"`     // Адрес NATS для broker = nats
+	NATSSubject string `mapstructure:"nats_subject"` // Тема событий, общая для всех реплик
+
+	RedisURL           string `mapstructure:"redis_url"`            // Адрес Redis для broker = redis
+	RedisChannelPrefix string `mapstructure:"redis_channel_prefix"` // Префикс каналов событий (канал на каждый тип события)
+}
+
+// ConfigStreaming настройки server-side стримов
+type ConfigStreaming struct {
+	// HeartbeatInterval - интервал health-check сообщений SubscribeToEvents, с единицами ("30s", "1m")
+	// Настройки с единицей в имени (*_seconds, *_minutes) остаются числами: перевод их в time.Duration
+	// изменил бы смысл значений в существующих config.yml и переменных окружения (30 стало бы ошибкой),
+	// поэтому time.Duration используют только настройки без единицы в имени
+	HeartbeatInterval time.Duration `mapstructure:"heartbeat_interval"`
+
+	// DisableMessagePool отключает переиспользование proto заметок событий SubscribeToEvents
+	// При включенной трассировке пул отключается всегда
+	DisableMessagePool bool `mapstructure:"disable_message_pool"`
+}
+
+// ConfigAuth настройки аутентификации запросов gRPC и HTTP Gateway
+type ConfigAuth struct {
+	Providers     []string            `mapstructure:"providers"`     // Провайдеры в порядке проверки: session, static, jwt, oidc, apikey
+	StaticTokens  []ConfigStaticToken `mapstructure:"static_tokens"` // Токены провайдера static
+	Sessions      ConfigSessions      `mapstructure:"sessions"`
+	JWT           ConfigJWT           `mapstructure:"jwt"`
+	OIDC          ConfigOIDC          `mapstructure:"oidc"`
+	APIKeys       ConfigAPIKeys       `mapstructure:"api_keys"`
+	StreamTickets ConfigStreamTickets `mapstructure:"stream_tickets"`
+}
+
+// ConfigAuthz проверка ролей методов: роли reader, writer и admin из токена и карта методов
+type ConfigAuthz struct {
+	DefaultRoles []string `mapstructure:"default_roles"` // Роли для токенов без ролей reader, writer и admin
+
+	// Methods - роли, любая из которых разрешает вызов, по методам
+	// Ключ - имя метода NotesService в нижнем регистре (например, "deletenote")
+	Methods map[string][]string `mapstructure:"methods"`
+}
+
+// ConfigStreamTickets настройки билетов стримов AuthService.IssueStreamTicket
+type ConfigStreamTickets struct {
+	SigningKey string `mapstructure:"signing_key"` // Ключ подписи билетов (пусто - случайный при запуске)
+	TTLSeconds int    `mapstructure:"ttl_seconds"` // Время действия билета
+}
+
+// ConfigAPIKeys настройки провайдера apikey: ключи API, которые создает администратор через AdminService
+type ConfigAPIKeys struct {
+	RateLimitRPS   float64 `mapstructure:"rate_limit_rps"`   // Лимит запросов ключа в секунду, если при создании ключа он не задан
+	RateLimitBurst int     `mapstructure:"rate_limit_burst"` // Размер бюджета запросов ключа по умолчанию
+}
+
+// ConfigSessions настройки провайдера session: вход по паролю через AuthService
+type ConfigSessions struct {
+	SigningKey             string       `mapstructure:"signing_key"`               // Ключ подписи access токенов (пусто - случайный при запуске)
+	AccessTokenTTLSeconds  int          `mapstructure:"access_token_ttl_seconds"`  // Время действия access токена
+	RefreshTokenTTLSeconds int          `mapstructure:"refresh_token_ttl_seconds"` // Время жизни сессии с момента входа
+	Users                  []ConfigUser `mapstructure:"users"`                     // Добавляются в хранилище пользователей при запуске
+}
+
+// ConfigUser пользователь, которому разрешен вход по паролю
+type ConfigUser struct {
+	Username string   `mapstructure:"username"`
+	Password string   `mapstructure:"password"` // Пароль открытым текстом или "sha256:<hex>"
+	UserID   string   `mapstructure:"user_id"`  // По умолчанию username
+	Roles    []string `mapstructure:"roles"`
+}
+
+// ConfigStaticToken токен провайдера static и пользователь, которому он выдан
+type ConfigStaticToken struct {
+	Token  string   `mapstructure:"token"`
+	UserID string   `mapstructure:"user_id"`
+	Roles  []string `mapstructure:"roles"`
+}
+
+// ConfigJWT настройки провайдера jwt (нужен hmac_secret или public_key_file)
+type ConfigJWT struct {
+	HMACSecret    string `mapstructure:"hmac_secret"`     // Секрет для HS256/384/512
+	PublicKeyFile string `mapstructure:"public_key_file"` // PEM с открытым ключом или сертификатом для RS* и ES*
+	Issuer        string `mapstructure:"issuer"`          // Ожидаемый iss (пусто - не проверяется)
+	Audience      string `mapstructure:"audience"`        // Ожидаемое значение в aud (пусто - не проверяется)
+	LeewaySeconds int    `mapstructure:"leeway_seconds"`  // Допустимое расхождение часов для exp и nbf
+	UserClaim     string `mapstructure:"user_claim"`      // Утверждение с ID пользователя (по умолчанию sub)
+	RolesClaim    string `mapstructure:"roles_claim"`     // Утверждение с ролями (по умолчанию roles)
+}
+
+// ConfigOIDC настройки провайдера oidc (OAuth 2.0 Token Introspection, RFC 7662)
+type ConfigOIDC struct {
+	IntrospectionURL string `mapstructure:"introspection_url"`
+	ClientID         string `mapstructure:"client_id"`
+	ClientSecret     string `mapstructure:"client_secret"`
+	Audience         string `mapstructure:"audience"`          // Ожидаемое значение в aud (пусто - не проверяется)
+	CacheTTLSeconds  int    `mapstructure:"cache_ttl_seconds"` // Время кэширования результата проверки токена
+	CacheSize        int    `mapstructure:"cache_size"`        // Максимальное количество токенов в кэше
+	UserClaim        string `mapstructure:"user_claim"`        // Утверждение с ID пользователя (по умолчанию sub)
+	RolesClaim       string `mapstructure:"roles_claim"`       // Утверждение с ролями (по умолчанию roles)
+}
+
 // Config основная структура конфигурации
 type Config struct {
-	Logger  *ConfigLogger  `mapstructure:"logger"`
-	Server  *ConfigServer  `mapstructure:"server"`
-	Gateway *ConfigGateway `mapstructure:"gateway"`
-	Swagger *ConfigSwagger `mapstructure:"swagger"`
+	Logger      *ConfigLogger      `mapstructure:"logger"`
+	Server      *ConfigServer      `mapstructure:"server"`
+	Gateway     *ConfigGateway     `mapstructure:"gateway"`
+	Swagger     *ConfigSwagger     `mapstructure:"swagger"`
+	Storage     *ConfigStorage     `mapstructure:"storage"`
+	Attachments *ConfigAttachments `mapstructure:"attachments"`
+	Exports     *ConfigExports     `mapstructure:"exports"`
+	Backups     *ConfigBackups     `mapstructure:"backups"`
+	Degraded    *ConfigDegraded    `mapstructure:"degraded"`
+	Mirror      *ConfigMirror      `mapstructure:"mirror"`
+	Metrics     *ConfigMetrics     `mapstructure:"metrics"`
+	Telemetry   *ConfigTelemetry   `mapstructure:"telemetry"`
+	Tracing     *ConfigTracing     `mapstructure:"tracing"`
+	Encryption  *ConfigEncryption  `mapstructure:"encryption"`
+	Webhooks    *ConfigWebhooks    `mapstructure:"webhooks"`
+	Usage       *ConfigUsage       `mapstructure:"usage"`
+	Audit       *ConfigAudit       `mapstructure:"audit"`
+	Egress      *ConfigEgress      `mapstructure:"egress"`
+	Tenants     *ConfigTenants     `mapstructure:"tenants"`
+	Recorder    *ConfigRecorder    `mapstructure:"recorder"`
+	SelfTest    *ConfigSelfTest    `mapstructure:"selftest"`
+	Debug       *ConfigDebug       `mapstructure:"debug"`
+	Events      *ConfigEvents      `mapstructure:"events"`
+	Streaming   *ConfigStreaming   `mapstructure:"streaming"`
+	Auth        *ConfigAuth        `mapstructure:"auth"`
+	Authz       *ConfigAuthz       `mapstructure:"authz"`
 }
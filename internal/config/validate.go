@@ -0,0 +1,135 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// durationSuffixes окончания имен настроек с длительностью: такие настройки не могут быть отрицательными
+var durationSuffixes = []string{"_timeout", "_seconds", "_minutes", "_days", "_interval"}
+
+// Validate проверяет прочитанную конфигурацию: обязательные секции, порты и их пересечения,
+// отрицательные таймауты и интервалы. Возвращает все найденные ошибки сразу
+// Настройки отдельных компонентов (TLS, лимиты, провайдеры) проверяются при инициализации сервера
+func (c *Config) Validate() error {
+	var errs []error
+	if c.Server == nil {
+		errs = append(errs, errors.New("server: section is required"))
+	}
+	if c.Gateway == nil {
+		errs = append(errs, errors.New("gateway: section is required"))
+	}
+	if c.Server != nil {
+		errs = append(errs, c.validatePorts()...)
+	}
+	errs = append(errs, negativeDurations(reflect.ValueOf(c).Elem(), "")...)
+	return errors.Join(errs...)
+}
+
+// validatePorts проверяет диапазон портов сервера и что gRPC, HTTP и отладочный порты не совпадают
+func (c *Config) validatePorts() []error {
+	var errs []error
+	ports := make(map[int]string)
+	addPort := func(name string, port int) {
+		if port < 1 || port > 65535 {
+			errs = append(errs, fmt.Errorf("%s: must be between 1 and 65535, got %d", name, port))
+			return
+		}
+		if other, ok := ports[port]; ok {
+			errs = append(errs, fmt.Errorf("%s: port %d is already used by %s", name, port, other))
+			return
+		}
+		ports[port] = name
+	}
+
+	addPort("server.port_http", c.Server.PortHTTP)
+	if c.Server.SinglePort {
+		// gRPC принимается на port_http, port_grpc не открывается
+		if c.Server.PortGRPC < 0 || c.Server.PortGRPC > 65535 {
+			errs = append(errs, fmt.Errorf("server.port_grpc: must be between 0 and 65535, got %d", c.Server.PortGRPC))
+		}
+	} else {
+		addPort("server.port_grpc", c.Server.PortGRPC)
+	}
+
+	if c.Debug != nil && c.Debug.Enabled && c.Debug.Addr != "" {
+		_, port, err := net.SplitHostPort(c.Debug.Addr)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("debug.addr: %w", err))
+		} else if n, err := strconv.Atoi(port); err != nil {
+			errs = append(errs, fmt.Errorf("debug.addr: invalid port %q", port))
+		} else if n != 0 {
+			addPort("debug.addr", n)
+		}
+	}
+	return errs
+}
+
+// negativeDurations возвращает ошибки для отрицательных длительностей в v: полей time.Duration
+// и целых полей, имя которых оканчивается на durationSuffixes (в том числе значений map и элементов срезов)
+// path - путь к v в конфигурации из тегов mapstructure
+func negativeDurations(v reflect.Value, path string) []error {
+	var errs []error
+	switch v.Kind() {
+	case reflect.Pointer:
+		if !v.IsNil() {
+			errs = append(errs, negativeDurations(v.Elem(), path)...)
+		}
+	case reflect.Struct:
+		for i := range v.NumField() {
+			field := v.Type().Field(i)
+			name, _, _ := strings.Cut(field.Tag.Get("mapstructure"), ",")
+			if !field.IsExported() || name == "" || name == "-" {
+				continue
+			}
+			errs = append(errs, negativeDurations(v.Field(i), joinPath(path, name))...)
+		}
+	case reflect.Slice:
+		for i := range v.Len() {
+			errs = append(errs, negativeDurations(v.Index(i), fmt.Sprintf("%s[%d]", path, i))...)
+		}
+	case reflect.Map:
+		iter := v.MapRange()
+		for iter.Next() {
+			key := fmt.Sprint(iter.Key().Interface())
+			if isDurationName(path) && iter.Value().CanInt() && iter.Value().Int() < 0 {
+				errs = append(errs, fmt.Errorf("%s.%s: must not be negative, got %d", path, key, iter.Value().Int()))
+				continue
+			}
+			errs = append(errs, negativeDurations(iter.Value(), joinPath(path, key))...)
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if v.Int() >= 0 {
+			break
+		}
+		if v.Type() == reflect.TypeFor[time.Duration]() {
+			errs = append(errs, fmt.Errorf("%s: must not be negative, got %s", path, time.Duration(v.Int())))
+		} else if isDurationName(path) {
+			errs = append(errs, fmt.Errorf("%s: must not be negative, got %d", path, v.Int()))
+		}
+	}
+	return errs
+}
+
+// isDurationName сообщает, задает ли настройка path длительность по окончанию имени
+func isDurationName(path string) bool {
+	for _, suffix := range durationSuffixes {
+		if strings.HasSuffix(path, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// joinPath добавляет имя настройки к пути секции
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
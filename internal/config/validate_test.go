@@ -0,0 +1,105 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func validConfig() *Config {
+	return &Config{
+		Server:  &ConfigServer{PortGRPC: 50051, PortHTTP: 8080},
+		Gateway: &ConfigGateway{},
+	}
+}
+
+func TestConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(c *Config)
+		errs   []string
+	}{
+		{name: "valid", modify: func(c *Config) {}},
+		{
+			name:   "missing sections",
+			modify: func(c *Config) { c.Server, c.Gateway = nil, nil },
+			errs:   []string{"server: section is required", "gateway: section is required"},
+		},
+		{
+			name: "port ranges",
+			modify: func(c *Config) {
+				c.Server.PortGRPC = 0
+				c.Server.PortHTTP = 70000
+			},
+			errs: []string{
+				"server.port_http: must be between 1 and 65535, got 70000",
+				"server.port_grpc: must be between 1 and 65535, got 0",
+			},
+		},
+		{
+			name:   "conflicting ports",
+			modify: func(c *Config) { c.Server.PortGRPC = 8080 },
+			errs:   []string{"server.port_grpc: port 8080 is already used by server.port_http"},
+		},
+		{
+			name: "single port",
+			modify: func(c *Config) {
+				c.Server.SinglePort = true
+				c.Server.PortGRPC = 8080
+			},
+		},
+		{
+			name:   "debug port",
+			modify: func(c *Config) { c.Debug = &ConfigDebug{Enabled: true, Addr: "localhost:50051"} },
+			errs:   []string{"debug.addr: port 50051 is already used by server.port_grpc"},
+		},
+		{
+			name: "negative durations",
+			modify: func(c *Config) {
+				c.Server.HTTPReadTimeout = -1
+				c.Server.MethodTimeoutsSeconds = map[string]int{"getnote": -5}
+				c.Server.StreamLifetimes = map[string]ConfigStreamLifetime{"chat": {IdleTimeoutSeconds: -3}}
+				c.Streaming = &ConfigStreaming{HeartbeatInterval: -time.Second}
+			},
+			errs: []string{
+				"server.http_read_timeout: must not be negative, got -1",
+				"server.method_timeouts_seconds.getnote: must not be negative, got -5",
+				"server.stream_lifetimes.chat.idle_timeout_seconds: must not be negative, got -3",
+				"streaming.heartbeat_interval: must not be negative, got -1s",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validConfig()
+			tt.modify(cfg)
+			err := cfg.Validate()
+			if len(tt.errs) == 0 {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			for _, msg := range tt.errs {
+				assert.Contains(t, err.Error(), msg)
+			}
+		})
+	}
+}
+
+func TestInitConfig_UnknownKeys(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "config.yml")
+	content := "server:\n  port_grpc: 50051\n  port_http: 50051\n  prot_http: 8080\ngateway:\n  cors_allowed_origin: '*'\n"
+	require.NoError(t, os.WriteFile(file, []byte(content), 0o600))
+
+	_, err := InitConfig[Config](file)
+	require.Error(t, err)
+	// Все ошибки возвращаются вместе
+	assert.Contains(t, err.Error(), "gateway.cors_allowed_origin: unknown key")
+	assert.Contains(t, err.Error(), "server.prot_http: unknown key")
+	assert.Contains(t, err.Error(), "server.port_grpc: port 50051 is already used by server.port_http")
+}
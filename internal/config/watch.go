@@ -0,0 +1,153 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// watchDebounce задержка перечитывания после изменения файла: редактор записывает файл
+// несколькими операциями, конфигурация перечитывается один раз после последней
+const watchDebounce = 200 * time.Millisecond
+
+// ChangeHook получает конфигурацию после изменения файла; previous - действовавшая до изменения
+// Хуки вызываются по очереди в порядке регистрации, ошибка одного не мешает остальным
+type ChangeHook[C any] interface {
+	ConfigChanged(previous, current *C) error
+}
+
+// ChangeHookFunc позволяет использовать функцию как ChangeHook
+type ChangeHookFunc[C any] func(previous, current *C) error
+
+// ConfigChanged вызывает f
+func (f ChangeHookFunc[C]) ConfigChanged(previous, current *C) error {
+	return f(previous, current)
+}
+
+// Watcher перечитывает файл конфигурации при его изменении и передает новую конфигурацию хукам
+// Файл с ошибкой не применяется: действует предыдущая конфигурация
+type Watcher[C any] struct {
+	file string
+
+	reload sync.Mutex // Перечитывания выполняются по одному
+
+	opts []Option // Параметры чтения файла, как при запуске (например, флаги командной строки)
+
+	mu      sync.Mutex
+	current *C
+	hooks   []ChangeHook[C]
+}
+
+// NewWatcher создает наблюдение за configFile; current - конфигурация, прочитанная при запуске
+// с теми же opts: флаги WithFlag остаются важнее файла и после его изменения
+func NewWatcher[C any](configFile string, current *C, opts ...Option) *Watcher[C] {
+	return &Watcher[C]{file: configFile, current: current, opts: opts}
+}
+
+// OnChange регистрирует хук изменения конфигурации
+func (w *Watcher[C]) OnChange(hook ChangeHook[C]) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.hooks = append(w.hooks, hook)
+}
+
+// Current возвращает последнюю примененную конфигурацию
+func (w *Watcher[C]) Current() *C {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.current
+}
+
+// Reload перечитывает файл и вызывает хуки, если конфигурация изменилась
+// Возвращает ошибку чтения файла или объединенные ошибки хуков
+func (w *Watcher[C]) Reload() error {
+	w.reload.Lock()
+	defer w.reload.Unlock()
+
+	cfg, err := InitConfig[C](w.file, w.opts...)
+	if err != nil {
+		return err
+	}
+
+	w.mu.Lock()
+	previous := w.current
+	if reflect.DeepEqual(previous, cfg) {
+		w.mu.Unlock()
+		return nil
+	}
+	w.current = cfg
+	hooks := slices.Clone(w.hooks)
+	w.mu.Unlock()
+
+	log.Printf("[Config] %s changed, applying", w.file)
+	var errs []error
+	for _, hook := range hooks {
+		if err := hook.ConfigChanged(previous, cfg); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Watch следит за файлом до отмены ctx и применяет изменения через Reload; ошибки записываются в лог
+// Наблюдается каталог файла: редакторы и ConfigMap Kubernetes заменяют файл переименованием
+func (w *Watcher[C]) Watch(ctx context.Context) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("fsnotify.NewWatcher: %w", err)
+	}
+	file := filepath.Clean(w.file)
+	if err := watcher.Add(filepath.Dir(file)); err != nil {
+		_ = watcher.Close()
+		return fmt.Errorf("watch %s: %w", filepath.Dir(file), err)
+	}
+	realFile, _ := filepath.EvalSymlinks(file)
+
+	go func() {
+		defer watcher.Close()
+		var debounce *time.Timer
+		var fire <-chan time.Time
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+				// Изменился сам файл или цель символической ссылки на него (ConfigMap)
+				current, _ := filepath.EvalSymlinks(file)
+				if filepath.Clean(event.Name) != file && (current == "" || current == realFile) {
+					continue
+				}
+				realFile = current
+				if debounce == nil {
+					debounce = time.NewTimer(watchDebounce)
+				} else {
+					debounce.Reset(watchDebounce)
+				}
+				fire = debounce.C
+			case err, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+				log.Printf("[Config] Watcher error: %v", err)
+			case <-fire:
+				fire = nil
+				if err := w.Reload(); err != nil {
+					log.Printf("[Config] Failed to apply %s: %v", w.file, err)
+				}
+			}
+		}
+	}()
+	log.Printf("[Config] Watching %s for changes", w.file)
+	return nil
+}
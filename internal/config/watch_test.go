@@ -0,0 +1,110 @@
+package config
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type watchedConfig struct {
+	Logger *ConfigLogger `mapstructure:"logger"`
+}
+
+func writeConfig(t *testing.T, file, level string) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(file, []byte("logger:\n  level: "+level+"\n"), 0o600))
+}
+
+func TestWatcher_Reload(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "config.yml")
+	writeConfig(t, file, "info")
+	initial, err := InitConfig[watchedConfig](file)
+	require.NoError(t, err)
+
+	watcher := NewWatcher(file, initial)
+	var calls [][2]string
+	watcher.OnChange(ChangeHookFunc[watchedConfig](func(previous, current *watchedConfig) error {
+		calls = append(calls, [2]string{previous.Logger.Level, current.Logger.Level})
+		return nil
+	}))
+
+	// Файл не изменился - хуки не вызываются
+	require.NoError(t, watcher.Reload())
+	assert.Empty(t, calls)
+
+	writeConfig(t, file, "debug")
+	require.NoError(t, watcher.Reload())
+	assert.Equal(t, [][2]string{{"info", "debug"}}, calls)
+	assert.Equal(t, "debug", watcher.Current().Logger.Level)
+
+	// Файл с ошибкой не применяется
+	require.NoError(t, os.WriteFile(file, []byte("logger: ["), 0o600))
+	require.Error(t, watcher.Reload())
+	assert.Equal(t, "debug", watcher.Current().Logger.Level)
+}
+
+func TestWatcher_Watch(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "config.yml")
+	writeConfig(t, file, "info")
+	initial, err := InitConfig[watchedConfig](file)
+	require.NoError(t, err)
+
+	watcher := NewWatcher(file, initial)
+	changed := make(chan string, 1)
+	watcher.OnChange(ChangeHookFunc[watchedConfig](func(_, current *watchedConfig) error {
+		changed <- current.Logger.Level
+		return nil
+	}))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	require.NoError(t, watcher.Watch(ctx))
+
+	// Замена файла переименованием, как при сохранении в редакторе
+	tmp := file + ".tmp"
+	writeConfig(t, tmp, "warn")
+	require.NoError(t, os.Rename(tmp, file))
+
+	select {
+	case level := <-changed:
+		assert.Equal(t, "warn", level)
+	case <-time.After(5 * time.Second):
+		t.Fatal("config change was not applied")
+	}
+}
+
+func TestWatcher_Flags(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "config.yml")
+	writeConfig(t, file, "info")
+
+	// Незаданный флаг не меняет настройку
+	flags := pflag.NewFlagSet("server", pflag.ContinueOnError)
+	flags.String("log-level", "", "")
+	cfg, err := InitConfig[watchedConfig](file, WithFlag("logger.level", flags.Lookup("log-level")))
+	require.NoError(t, err)
+	assert.Equal(t, "info", cfg.Logger.Level)
+
+	require.NoError(t, flags.Parse([]string{"--log-level", "debug"}))
+	opts := []Option{WithFlag("logger.level", flags.Lookup("log-level"))}
+	initial, err := InitConfig[watchedConfig](file, opts...)
+	require.NoError(t, err)
+	assert.Equal(t, "debug", initial.Logger.Level)
+
+	watcher := NewWatcher(file, initial, opts...)
+	calls := 0
+	watcher.OnChange(ChangeHookFunc[watchedConfig](func(_, _ *watchedConfig) error {
+		calls++
+		return nil
+	}))
+
+	// Флаг важнее файла: изменение уровня в файле не применяется
+	writeConfig(t, file, "warn")
+	require.NoError(t, watcher.Reload())
+	assert.Equal(t, "debug", watcher.Current().Logger.Level)
+	assert.Zero(t, calls)
+}
@@ -0,0 +1,31 @@
+package converter
+
+import (
+	"notes-service/internal/model"
+	notesv1 "notes-service/pkg/proto/notes/v1"
+)
+
+// APIKeyToProto конвертирует domain модель APIKey в proto (без хэша секрета)
+func APIKeyToProto(key model.APIKey) *notesv1.APIKey {
+	return &notesv1.APIKey{
+		Id:             key.ID,
+		Name:           key.Name,
+		UserId:         key.UserID,
+		Roles:          key.Roles,
+		CreatedBy:      key.CreatedBy,
+		CreatedAt:      optionalTimestamp(key.CreatedAt),
+		ExpiresAt:      optionalTimestamp(key.ExpiresAt),
+		RevokedAt:      optionalTimestamp(key.RevokedAt),
+		RateLimitRps:   key.RateLimitRPS,
+		RateLimitBurst: int32(key.RateLimitBurst),
+	}
+}
+
+// APIKeysToProto конвертирует слайс ключей API в слайс proto
+func APIKeysToProto(keys []model.APIKey) []*notesv1.APIKey {
+	result := make([]*notesv1.APIKey, len(keys))
+	for i, key := range keys {
+		result[i] = APIKeyToProto(key)
+	}
+	return result
+}
@@ -0,0 +1,25 @@
+package converter
+
+import (
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"notes-service/internal/model"
+	notesv1 "notes-service/pkg/proto/notes/v1"
+)
+
+// AttachmentToProto конвертирует domain модель Attachment в proto
+func AttachmentToProto(attachment model.Attachment) *notesv1.Attachment {
+	var createdAt *timestamppb.Timestamp
+	if !attachment.CreatedAt.IsZero() {
+		createdAt = timestamppb.New(attachment.CreatedAt)
+	}
+
+	return &notesv1.Attachment{
+		Id:          attachment.ID,
+		NoteId:      attachment.NoteID,
+		FileName:    attachment.FileName,
+		ContentType: attachment.ContentType,
+		SizeBytes:   attachment.Size,
+		Sha256:      attachment.SHA256,
+		CreatedAt:   createdAt,
+	}
+}
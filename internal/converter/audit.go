@@ -0,0 +1,32 @@
+package converter
+
+import (
+	"notes-service/internal/model"
+	notesv1 "notes-service/pkg/proto/notes/v1"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// AuditRecordToProto конвертирует domain модель AuditRecord в proto
+func AuditRecordToProto(record model.AuditRecord) *notesv1.AuditEvent {
+	return &notesv1.AuditEvent{
+		Id:           record.ID,
+		Time:         timestamppb.New(record.Time),
+		Action:       record.Action,
+		Method:       record.Method,
+		NoteId:       record.NoteID,
+		UserId:       record.UserID,
+		TargetUserId: record.TargetUserID,
+		RequestId:    record.RequestID,
+		Outcome:      record.Outcome,
+	}
+}
+
+// AuditRecordsToProto конвертирует слайс записей аудита в слайс proto
+func AuditRecordsToProto(records []model.AuditRecord) []*notesv1.AuditEvent {
+	result := make([]*notesv1.AuditEvent, len(records))
+	for i, record := range records {
+		result[i] = AuditRecordToProto(record)
+	}
+	return result
+}
@@ -0,0 +1,50 @@
+package converter
+
+import (
+	"time"
+
+	"notes-service/internal/model"
+	notesv1 "notes-service/pkg/proto/notes/v1"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// BackupStatusToProto конвертирует состояние резервного копирования в proto
+// Ошибка последнего копирования не конвертируется: ее статус формирует хэндлер
+func BackupStatusToProto(s model.BackupStatus) *notesv1.BackupStatus {
+	return &notesv1.BackupStatus{
+		LastBackup:          s.LastBackup.Name,
+		LastBackupLocation:  s.LastBackup.Location,
+		LastBackupTime:      optionalTimestamp(s.LastBackup.CreatedAt),
+		LastBackupSizeBytes: s.LastBackup.Size,
+		LastBackupNotes:     s.LastBackup.Notes,
+		LastAttemptTime:     optionalTimestamp(s.LastAttemptAt),
+		NextBackupTime:      optionalTimestamp(s.NextBackupAt),
+		Retained:            int32(s.Retained),
+		Succeeded:           s.Succeeded,
+		Failed:              s.Failed,
+	}
+}
+
+// RestoreReportToProto конвертирует результат восстановления из резервной копии в proto
+func RestoreReportToProto(r model.RestoreReport) *notesv1.RestoreBackupResponse {
+	return &notesv1.RestoreBackupResponse{
+		Backup:      r.Backup,
+		DryRun:      r.DryRun,
+		Notes:       r.Notes,
+		Created:     r.Created,
+		Overwritten: r.Overwritten,
+		Skipped:     r.Skipped,
+		Revisions:   r.Revisions,
+		Shares:      r.Shares,
+		Conflicts:   r.Conflicts,
+	}
+}
+
+// optionalTimestamp конвертирует время, нулевое время - в отсутствующее поле
+func optionalTimestamp(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+	return timestamppb.New(t)
+}
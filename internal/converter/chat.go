@@ -0,0 +1,48 @@
+package converter
+
+import (
+	"notes-service/internal/service/chat"
+	notesv1 "notes-service/pkg/proto/notes/v1"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// ChatMessageToProto конвертирует сообщение комнаты (текст, присутствие или набор текста) в proto
+func ChatMessageToProto(msg chat.Message) *notesv1.ChatMessage {
+	protoMsg := &notesv1.ChatMessage{
+		CorrelationId: msg.CorrelationID,
+		RoomId:        msg.RoomID,
+	}
+
+	switch msg.Kind {
+	case chat.KindPresence:
+		state := notesv1.PresenceState_PRESENCE_STATE_JOINED
+		if msg.Presence == chat.PresenceLeft {
+			state = notesv1.PresenceState_PRESENCE_STATE_LEFT
+		}
+		protoMsg.Content = &notesv1.ChatMessage_PresenceUpdate{
+			PresenceUpdate: &notesv1.PresenceUpdate{
+				UserId:    msg.SenderID,
+				State:     state,
+				Timestamp: timestamppb.New(msg.Time),
+			},
+		}
+	case chat.KindTyping:
+		protoMsg.Content = &notesv1.ChatMessage_TypingIndicator{
+			TypingIndicator: &notesv1.TypingIndicator{
+				Typing:    msg.Typing,
+				UserId:    msg.SenderID,
+				Timestamp: timestamppb.New(msg.Time),
+			},
+		}
+	default:
+		protoMsg.Content = &notesv1.ChatMessage_TextMessage{
+			TextMessage: &notesv1.ChatTextMessage{
+				Text:      msg.Text,
+				Timestamp: timestamppb.New(msg.Time),
+				SenderId:  msg.SenderID,
+			},
+		}
+	}
+	return protoMsg
+}
@@ -0,0 +1,306 @@
+package converter
+
+import (
+	"bufio"
+	"encoding/base64"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"notes-service/internal/model"
+	notesv1 "notes-service/pkg/proto/notes/v1"
+)
+
+// ExportFormat формат выгрузки и загрузки заметок
+type ExportFormat string
+
+// Поддерживаемые форматы выгрузки
+const (
+	FormatJSONLines ExportFormat = "jsonl"    // Одна заметка в формате JSON на строку (без потерь)
+	FormatMarkdown  ExportFormat = "markdown" // Документ для чтения человеком (только выгрузка)
+	FormatCSV       ExportFormat = "csv"      // Таблица с заголовком (без потерь)
+)
+
+// csvHeader колонки выгрузки в CSV
+var csvHeader = []string{
+	"id", "title", "content", "tags", "created_at", "updated_at", "is_e2e", "e2e_scheme", "content_encrypted",
+}
+
+// ExportFormatFromProto конвертирует proto enum в формат выгрузки
+func ExportFormatFromProto(format notesv1.ExportFormat) (ExportFormat, error) {
+	switch format {
+	case notesv1.ExportFormat_EXPORT_FORMAT_JSONL:
+		return FormatJSONLines, nil
+	case notesv1.ExportFormat_EXPORT_FORMAT_MARKDOWN:
+		return FormatMarkdown, nil
+	case notesv1.ExportFormat_EXPORT_FORMAT_CSV:
+		return FormatCSV, nil
+	default:
+		return "", fmt.Errorf("invalid export format %s", format)
+	}
+}
+
+// NoteWriter последовательно записывает заметки в выбранном формате
+type NoteWriter interface {
+	// Write записывает заметку
+	Write(note model.Note) error
+	// Close дописывает буферизованные данные, нижележащий io.Writer не закрывается
+	Close() error
+}
+
+// NoteReader последовательно читает заметки, в конце данных возвращает io.EOF
+type NoteReader interface {
+	Read() (model.Note, error)
+}
+
+// NewNoteWriter создает NoteWriter для формата format поверх w
+func NewNoteWriter(format ExportFormat, w io.Writer) (NoteWriter, error) {
+	switch format {
+	case FormatJSONLines:
+		bw := bufio.NewWriter(w)
+		return &jsonLinesWriter{w: bw, enc: json.NewEncoder(bw)}, nil
+	case FormatMarkdown:
+		return &markdownWriter{w: bufio.NewWriter(w)}, nil
+	case FormatCSV:
+		return &csvWriter{w: csv.NewWriter(w)}, nil
+	default:
+		return nil, fmt.Errorf("invalid export format %q", format)
+	}
+}
+
+// NewNoteReader создает NoteReader для формата format поверх r
+// Markdown предназначен для чтения человеком и не загружается обратно
+func NewNoteReader(format ExportFormat, r io.Reader) (NoteReader, error) {
+	switch format {
+	case FormatJSONLines:
+		return &jsonLinesReader{dec: json.NewDecoder(r)}, nil
+	case FormatCSV:
+		cr := csv.NewReader(r)
+		cr.FieldsPerRecord = len(csvHeader)
+		return &csvReader{r: cr}, nil
+	default:
+		return nil, fmt.Errorf("invalid import format %q: only jsonl and csv can be imported", format)
+	}
+}
+
+// noteRecord представление заметки в JSON Lines
+type noteRecord struct {
+	ID               string    `json:"id"`
+	Title            string    `json:"title"`
+	Content          string    `json:"content,omitempty"`
+	Tags             []string  `json:"tags,omitempty"`
+	CreatedAt        time.Time `json:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at"`
+	RemindAt         time.Time `json:"remind_at,omitzero"`
+	IsE2E            bool      `json:"is_e2e,omitempty"`
+	E2EScheme        string    `json:"e2e_scheme,omitempty"`
+	ContentEncrypted []byte    `json:"content_encrypted,omitempty"`
+}
+
+type jsonLinesWriter struct {
+	w   *bufio.Writer
+	enc *json.Encoder
+}
+
+// Write записывает заметку одной строкой JSON
+func (w *jsonLinesWriter) Write(note model.Note) error {
+	return w.enc.Encode(noteRecord{
+		ID:               note.ID,
+		Title:            note.Title,
+		Content:          note.Content,
+		Tags:             note.Tags,
+		CreatedAt:        note.CreatedAt,
+		UpdatedAt:        note.UpdatedAt,
+		RemindAt:         note.RemindAt,
+		IsE2E:            note.IsE2E,
+		E2EScheme:        note.E2EScheme,
+		ContentEncrypted: note.ContentEncrypted,
+	})
+}
+
+// Close дописывает буферизованные строки
+func (w *jsonLinesWriter) Close() error {
+	return w.w.Flush()
+}
+
+type jsonLinesReader struct {
+	dec *json.Decoder
+}
+
+// Read читает следующую заметку
+func (r *jsonLinesReader) Read() (model.Note, error) {
+	var record noteRecord
+	if err := r.dec.Decode(&record); err != nil {
+		if errors.Is(err, io.EOF) {
+			return model.Note{}, io.EOF
+		}
+		return model.Note{}, fmt.Errorf("invalid jsonl record: %w", err)
+	}
+
+	return model.Note{
+		ID:               record.ID,
+		Title:            record.Title,
+		Content:          record.Content,
+		Tags:             record.Tags,
+		CreatedAt:        record.CreatedAt,
+		UpdatedAt:        record.UpdatedAt,
+		RemindAt:         record.RemindAt,
+		IsE2E:            record.IsE2E,
+		E2EScheme:        record.E2EScheme,
+		ContentEncrypted: record.ContentEncrypted,
+	}, nil
+}
+
+type markdownWriter struct {
+	w       *bufio.Writer
+	written bool
+}
+
+// Write записывает заметку разделом документа: заголовок, теги и содержание
+// Зашифрованные заметки выгружаются без содержимого. Ошибки записи bufio.Writer
+// запоминаются и возвращаются из Close
+func (w *markdownWriter) Write(note model.Note) error {
+	if w.written {
+		w.w.WriteString("\n---\n\n")
+	}
+	w.written = true
+
+	fmt.Fprintf(w.w, "# %s\n\n", note.Title)
+	fmt.Fprintf(w.w, "_Updated: %s_\n\n", note.UpdatedAt.UTC().Format(time.RFC3339))
+	if len(note.Tags) > 0 {
+		fmt.Fprintf(w.w, "Tags: %s\n\n", strings.Join(note.Tags, ", "))
+	}
+
+	if note.IsE2E {
+		fmt.Fprintf(w.w, "_Encrypted note (%s)_\n", note.E2EScheme)
+	} else if note.Content != "" {
+		w.w.WriteString(note.Content)
+		w.w.WriteString("\n")
+	}
+
+	return nil
+}
+
+// Close дописывает буферизованный документ
+func (w *markdownWriter) Close() error {
+	return w.w.Flush()
+}
+
+type csvWriter struct {
+	w             *csv.Writer
+	headerWritten bool
+}
+
+// Write записывает заметку строкой таблицы, перед первой заметкой записывается заголовок
+// Теги разделяются переводом строки, зашифрованное содержимое кодируется в base64
+func (w *csvWriter) Write(note model.Note) error {
+	if !w.headerWritten {
+		if err := w.w.Write(csvHeader); err != nil {
+			return err
+		}
+		w.headerWritten = true
+	}
+
+	var encrypted string
+	if len(note.ContentEncrypted) > 0 {
+		encrypted = base64.StdEncoding.EncodeToString(note.ContentEncrypted)
+	}
+
+	return w.w.Write([]string{
+		note.ID,
+		note.Title,
+		note.Content,
+		strings.Join(note.Tags, "\n"),
+		note.CreatedAt.UTC().Format(time.RFC3339Nano),
+		note.UpdatedAt.UTC().Format(time.RFC3339Nano),
+		strconv.FormatBool(note.IsE2E),
+		note.E2EScheme,
+		encrypted,
+	})
+}
+
+// Close дописывает буферизованные строки
+func (w *csvWriter) Close() error {
+	if !w.headerWritten {
+		if err := w.w.Write(csvHeader); err != nil {
+			return err
+		}
+	}
+	w.w.Flush()
+	return w.w.Error()
+}
+
+type csvReader struct {
+	r          *csv.Reader
+	headerRead bool
+}
+
+// Read читает следующую заметку, пропуская строку заголовка
+func (r *csvReader) Read() (model.Note, error) {
+	if !r.headerRead {
+		header, err := r.r.Read()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return model.Note{}, io.EOF
+			}
+			return model.Note{}, fmt.Errorf("invalid csv header: %w", err)
+		}
+		if strings.Join(header, ",") != strings.Join(csvHeader, ",") {
+			return model.Note{}, fmt.Errorf("invalid csv header: expected %s", strings.Join(csvHeader, ","))
+		}
+		r.headerRead = true
+	}
+
+	record, err := r.r.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return model.Note{}, io.EOF
+		}
+		return model.Note{}, fmt.Errorf("invalid csv record: %w", err)
+	}
+
+	note := model.Note{
+		ID:        record[0],
+		Title:     record[1],
+		Content:   record[2],
+		E2EScheme: record[7],
+	}
+	if record[3] != "" {
+		note.Tags = strings.Split(record[3], "\n")
+	}
+	if note.CreatedAt, err = parseCSVTime(record[4]); err != nil {
+		return model.Note{}, err
+	}
+	if note.UpdatedAt, err = parseCSVTime(record[5]); err != nil {
+		return model.Note{}, err
+	}
+	if record[6] != "" {
+		if note.IsE2E, err = strconv.ParseBool(record[6]); err != nil {
+			return model.Note{}, fmt.Errorf("invalid csv is_e2e value %q", record[6])
+		}
+	}
+	if record[8] != "" {
+		if note.ContentEncrypted, err = base64.StdEncoding.DecodeString(record[8]); err != nil {
+			return model.Note{}, fmt.Errorf("invalid csv content_encrypted: %w", err)
+		}
+	}
+
+	return note, nil
+}
+
+// parseCSVTime разбирает время в RFC 3339, пустое значение означает нулевое время
+func parseCSVTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	t, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid csv time %q", value)
+	}
+	return t, nil
+}
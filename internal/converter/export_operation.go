@@ -0,0 +1,56 @@
+package converter
+
+import (
+	"fmt"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"notes-service/internal/model"
+	notesv1 "notes-service/pkg/proto/notes/v1"
+)
+
+// ExportArchiveFromProto конвертирует proto enum в формат файла выгрузки
+func ExportArchiveFromProto(archive notesv1.ExportArchive) (model.ExportArchive, error) {
+	switch archive {
+	case notesv1.ExportArchive_EXPORT_ARCHIVE_NDJSON:
+		return model.ExportArchiveNDJSON, nil
+	case notesv1.ExportArchive_EXPORT_ARCHIVE_ZIP:
+		return model.ExportArchiveZIP, nil
+	default:
+		return "", fmt.Errorf("invalid export archive %s", archive)
+	}
+}
+
+// ExportArchiveToProto конвертирует формат файла выгрузки в proto enum
+func ExportArchiveToProto(archive model.ExportArchive) notesv1.ExportArchive {
+	switch archive {
+	case model.ExportArchiveNDJSON:
+		return notesv1.ExportArchive_EXPORT_ARCHIVE_NDJSON
+	case model.ExportArchiveZIP:
+		return notesv1.ExportArchive_EXPORT_ARCHIVE_ZIP
+	default:
+		return notesv1.ExportArchive_EXPORT_ARCHIVE_UNSPECIFIED
+	}
+}
+
+// ExportOperationToProto конвертирует операцию выгрузки в proto
+// Ошибка операции не заполняется: ее конвертирует в google.rpc.Status вызывающий
+func ExportOperationToProto(op model.ExportOperation) *notesv1.ExportOperation {
+	var createdAt, completedAt *timestamppb.Timestamp
+	if !op.CreatedAt.IsZero() {
+		createdAt = timestamppb.New(op.CreatedAt)
+	}
+	if !op.CompletedAt.IsZero() {
+		completedAt = timestamppb.New(op.CompletedAt)
+	}
+
+	return &notesv1.ExportOperation{
+		Id:            op.ID,
+		State:         notesv1.ExportOperationState(op.State),
+		Archive:       ExportArchiveToProto(op.Archive),
+		ExportedNotes: op.ExportedNotes,
+		TotalNotes:    op.TotalNotes,
+		Location:      op.Location,
+		CreatedAt:     createdAt,
+		CompletedAt:   completedAt,
+	}
+}
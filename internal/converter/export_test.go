@@ -0,0 +1,123 @@
+package converter
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"notes-service/internal/model"
+)
+
+func exportTestNotes() []model.Note {
+	created := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	return []model.Note{
+		{
+			ID:        "note-1",
+			Title:     "Shopping, list",
+			Content:   "Milk\n\"Bread\"\nEggs",
+			Tags:      []string{"home", "todo list"},
+			CreatedAt: created,
+			UpdatedAt: created.Add(time.Hour),
+		},
+		{
+			ID:               "note-2",
+			Title:            "Secret",
+			Tags:             []string{"private"},
+			CreatedAt:        created,
+			UpdatedAt:        created,
+			IsE2E:            true,
+			E2EScheme:        model.E2ESchemeAES256GCM,
+			ContentEncrypted: []byte{0x00, 0x01, 0xfe, 0xff},
+		},
+	}
+}
+
+func TestNoteWriterReader_RoundTrip(t *testing.T) {
+	for _, format := range []ExportFormat{FormatJSONLines, FormatCSV} {
+		t.Run(string(format), func(t *testing.T) {
+			var buf bytes.Buffer
+			writer, err := NewNoteWriter(format, &buf)
+			if err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+
+			notes := exportTestNotes()
+			for _, note := range notes {
+				if err := writer.Write(note); err != nil {
+					t.Fatalf("Expected no error, got: %v", err)
+				}
+			}
+			if err := writer.Close(); err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+
+			reader, err := NewNoteReader(format, &buf)
+			if err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+
+			for i, want := range notes {
+				got, err := reader.Read()
+				if err != nil {
+					t.Fatalf("Note %d: expected no error, got: %v", i, err)
+				}
+				if got.ID != want.ID || got.Title != want.Title || got.Content != want.Content {
+					t.Errorf("Note %d: expected %+v, got %+v", i, want, got)
+				}
+				if strings.Join(got.Tags, ",") != strings.Join(want.Tags, ",") {
+					t.Errorf("Note %d: expected tags %v, got %v", i, want.Tags, got.Tags)
+				}
+				if !got.UpdatedAt.Equal(want.UpdatedAt) {
+					t.Errorf("Note %d: expected updated_at %v, got %v", i, want.UpdatedAt, got.UpdatedAt)
+				}
+				if got.IsE2E != want.IsE2E || got.E2EScheme != want.E2EScheme || !bytes.Equal(got.ContentEncrypted, want.ContentEncrypted) {
+					t.Errorf("Note %d: expected e2e fields to survive round trip, got %+v", i, got)
+				}
+			}
+
+			if _, err := reader.Read(); !errors.Is(err, io.EOF) {
+				t.Errorf("Expected io.EOF after last note, got: %v", err)
+			}
+		})
+	}
+}
+
+func TestNoteWriter_Markdown(t *testing.T) {
+	var buf bytes.Buffer
+	writer, err := NewNoteWriter(FormatMarkdown, &buf)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	for _, note := range exportTestNotes() {
+		if err := writer.Write(note); err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	doc := buf.String()
+	for _, want := range []string{"# Shopping, list", "Tags: home, todo list", "Milk", "_Encrypted note (aes-256-gcm)_"} {
+		if !strings.Contains(doc, want) {
+			t.Errorf("Expected markdown to contain %q, got:\n%s", want, doc)
+		}
+	}
+
+	if _, err := NewNoteReader(FormatMarkdown, &buf); err == nil {
+		t.Error("Expected markdown import to be rejected")
+	}
+}
+
+func TestNoteReader_CSVInvalidHeader(t *testing.T) {
+	reader, err := NewNoteReader(FormatCSV, strings.NewReader("a,b,c,d,e,f,g,h,i\n"))
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if _, err := reader.Read(); err == nil || !strings.Contains(err.Error(), "invalid csv header") {
+		t.Errorf("Expected invalid csv header error, got: %v", err)
+	}
+}
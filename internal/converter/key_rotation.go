@@ -0,0 +1,34 @@
+package converter
+
+import (
+	"notes-service/internal/model"
+	notesv1 "notes-service/pkg/proto/notes/v1"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// KeyRotationOperationToProto конвертирует операцию смены ключей в proto
+// Ошибка операции не конвертируется: ее статус формирует хэндлер
+func KeyRotationOperationToProto(op model.KeyRotationOperation) *notesv1.KeyRotationOperation {
+	var createdAt, completedAt *timestamppb.Timestamp
+	if !op.CreatedAt.IsZero() {
+		createdAt = timestamppb.New(op.CreatedAt)
+	}
+	if !op.CompletedAt.IsZero() {
+		completedAt = timestamppb.New(op.CompletedAt)
+	}
+
+	return &notesv1.KeyRotationOperation{
+		Id:               op.ID,
+		State:            notesv1.KeyRotationState(op.State),
+		OwnerId:          op.OwnerID,
+		StartedBy:        op.StartedBy,
+		RotatedKeys:      op.RotatedKeys,
+		RewrappedKeys:    op.RewrappedKeys,
+		TotalNotes:       op.TotalNotes,
+		ProcessedNotes:   op.ProcessedNotes,
+		ReencryptedNotes: op.ReencryptedNotes,
+		CreatedAt:        createdAt,
+		CompletedAt:      completedAt,
+	}
+}
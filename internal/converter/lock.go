@@ -0,0 +1,17 @@
+package converter
+
+import (
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"notes-service/internal/model"
+	notesv1 "notes-service/pkg/proto/notes/v1"
+)
+
+// NoteLockToProto конвертирует domain модель NoteLock в proto
+func NoteLockToProto(lock model.NoteLock) *notesv1.NoteLock {
+	return &notesv1.NoteLock{
+		NoteId:     lock.NoteID,
+		HolderId:   lock.HolderID,
+		AcquiredAt: timestamppb.New(lock.AcquiredAt),
+		ExpiresAt:  timestamppb.New(lock.ExpiresAt),
+	}
+}
@@ -0,0 +1,96 @@
+package converter
+
+import (
+	"notes-service/internal/model"
+	notesv1 "notes-service/pkg/proto/notes/v1"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// MetricsSummaryToProto конвертирует статистику загруженных метрик в proto
+func MetricsSummaryToProto(s model.MetricsSummary) *notesv1.SummaryResponse {
+	metrics := make([]*notesv1.MetricSummary, 0, len(s.Metrics))
+	for _, m := range s.Metrics {
+		metrics = append(metrics, &notesv1.MetricSummary{
+			Name:    m.Name,
+			Count:   m.Count,
+			Sum:     m.Sum,
+			Average: m.Average,
+			Min:     m.Min,
+			Max:     m.Max,
+			P95:     m.P95,
+		})
+	}
+
+	return &notesv1.SummaryResponse{
+		Sum:     s.Sum,
+		Average: s.Average,
+		Count:   s.Count,
+		Metrics: metrics,
+	}
+}
+
+// MetricPointFromProto конвертирует загруженное значение метрики в модель (время не задано - нулевое)
+func MetricPointFromProto(m *notesv1.MetricRequest) model.MetricPoint {
+	point := model.MetricPoint{
+		Name:  m.GetName(),
+		Value: m.GetValue(),
+	}
+	if m.GetTime() != nil {
+		point.Time = m.GetTime().AsTime()
+	}
+	return point
+}
+
+// MetricQueryFromProto конвертирует запрос QueryMetrics в модель
+func MetricQueryFromProto(req *notesv1.QueryMetricsRequest) model.MetricQuery {
+	query := model.MetricQuery{
+		Name:        req.GetName(),
+		Aggregation: metricAggregationFromProto(req.GetAggregation()),
+	}
+	if req.GetFrom() != nil {
+		query.From = req.GetFrom().AsTime()
+	}
+	if req.GetTo() != nil {
+		query.To = req.GetTo().AsTime()
+	}
+	return query
+}
+
+// metricAggregationFromProto конвертирует агрегат QueryMetrics в модель
+func metricAggregationFromProto(aggregation notesv1.MetricAggregation) model.MetricAggregation {
+	switch aggregation {
+	case notesv1.MetricAggregation_METRIC_AGGREGATION_COUNT:
+		return model.MetricAggregationCount
+	case notesv1.MetricAggregation_METRIC_AGGREGATION_SUM:
+		return model.MetricAggregationSum
+	case notesv1.MetricAggregation_METRIC_AGGREGATION_AVG:
+		return model.MetricAggregationAvg
+	case notesv1.MetricAggregation_METRIC_AGGREGATION_MIN:
+		return model.MetricAggregationMin
+	case notesv1.MetricAggregation_METRIC_AGGREGATION_MAX:
+		return model.MetricAggregationMax
+	case notesv1.MetricAggregation_METRIC_AGGREGATION_P95:
+		return model.MetricAggregationP95
+	default:
+		return model.MetricAggregationNone
+	}
+}
+
+// MetricQueryResultToProto конвертирует результат выборки значений метрики в proto
+func MetricQueryResultToProto(r model.MetricQueryResult) *notesv1.QueryMetricsResponse {
+	points := make([]*notesv1.MetricPoint, 0, len(r.Points))
+	for _, point := range r.Points {
+		points = append(points, &notesv1.MetricPoint{
+			Value: point.Value,
+			Time:  timestamppb.New(point.Time),
+		})
+	}
+
+	return &notesv1.QueryMetricsResponse{
+		Points:    points,
+		Truncated: r.Truncated,
+		Value:     r.Value,
+		Count:     r.Count,
+	}
+}
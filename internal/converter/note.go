@@ -3,6 +3,7 @@ package converter
 import (
 	"time"
 
+	"google.golang.org/protobuf/types/known/durationpb"
 	"google.golang.org/protobuf/types/known/timestamppb"
 	"notes-service/internal/model"
 	notesv1 "notes-service/pkg/proto/notes/v1"
@@ -14,13 +15,16 @@ func ProtoToModel(protoNote *notesv1.Note) model.Note {
 		return model.Note{}
 	}
 
-	var createdAt, updatedAt time.Time
+	var createdAt, updatedAt, remindAt time.Time
 	if protoNote.GetCreatedAt() != nil {
 		createdAt = protoNote.GetCreatedAt().AsTime()
 	}
 	if protoNote.GetUpdatedAt() != nil {
 		updatedAt = protoNote.GetUpdatedAt().AsTime()
 	}
+	if protoNote.GetRemindAt() != nil {
+		remindAt = protoNote.GetRemindAt().AsTime()
+	}
 
 	return model.Note{
 		ID:        protoNote.GetId(),
@@ -28,18 +32,38 @@ func ProtoToModel(protoNote *notesv1.Note) model.Note {
 		Content:   protoNote.GetContent(),
 		CreatedAt: createdAt,
 		UpdatedAt: updatedAt,
+		Version:   protoNote.GetVersion(),
+		Tags:      protoNote.GetTags(),
+		OwnerID:   protoNote.GetOwnerId(),
+		Pinned:    protoNote.GetPinned(),
+		Archived:  protoNote.GetArchived(),
+		RemindAt:  remindAt,
+
+		WordCount:   int(protoNote.GetWordCount()),
+		ReadingTime: protoNote.GetReadingTime().AsDuration(),
+
+		IsE2E:            protoNote.GetIsE2E(),
+		E2EScheme:        protoNote.GetE2EScheme(),
+		ContentEncrypted: protoNote.GetContentEncrypted(),
 	}
 }
 
 // ModelToProto конвертирует domain модель Note в proto
 func ModelToProto(note model.Note) *notesv1.Note {
-	var createdAt, updatedAt *timestamppb.Timestamp
+	var createdAt, updatedAt, remindAt *timestamppb.Timestamp
 	if !note.CreatedAt.IsZero() {
 		createdAt = timestamppb.New(note.CreatedAt)
 	}
 	if !note.UpdatedAt.IsZero() {
 		updatedAt = timestamppb.New(note.UpdatedAt)
 	}
+	if !note.RemindAt.IsZero() {
+		remindAt = timestamppb.New(note.RemindAt)
+	}
+	var readingTime *durationpb.Duration
+	if note.ReadingTime != 0 {
+		readingTime = durationpb.New(note.ReadingTime)
+	}
 
 	return &notesv1.Note{
 		Id:        note.ID,
@@ -47,6 +71,22 @@ func ModelToProto(note model.Note) *notesv1.Note {
 		Content:   note.Content,
 		CreatedAt: createdAt,
 		UpdatedAt: updatedAt,
+		Version:   note.Version,
+		Tags:      note.Tags,
+		OwnerId:   note.OwnerID,
+		Pinned:    note.Pinned,
+		Archived:  note.Archived,
+		RemindAt:  remindAt,
+
+		WordCount:   int32(note.WordCount),
+		ReadingTime: readingTime,
+
+		IsE2E:            note.IsE2E,
+		E2EScheme:        note.E2EScheme,
+		ContentEncrypted: note.ContentEncrypted,
+		EncryptionKeyId:  note.KeyID,
+
+		PassphraseProtected: note.PassphraseProtected(),
 	}
 }
 
@@ -56,6 +96,11 @@ func ModelsToProtos(notes []model.Note) []*notesv1.Note {
 		return nil
 	}
 
+	// Блочное размещение сокращает количество аллокаций для больших списков (ListNotes)
+	if poolingEnabled {
+		return modelsToProtosArena(notes)
+	}
+
 	protoNotes := make([]*notesv1.Note, len(notes))
 	for i, note := range notes {
 		protoNotes[i] = ModelToProto(note)
@@ -63,3 +108,12 @@ func ModelsToProtos(notes []model.Note) []*notesv1.Note {
 
 	return protoNotes
 }
+
+// TagCountsToProto конвертирует счетчики тегов в proto
+func TagCountsToProto(counts []model.TagCount) []*notesv1.TagCount {
+	result := make([]*notesv1.TagCount, len(counts))
+	for i, count := range counts {
+		result[i] = &notesv1.TagCount{Tag: count.Tag, Count: int64(count.Count)}
+	}
+	return result
+}
@@ -0,0 +1,102 @@
+package converter
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"notes-service/internal/model"
+	notesv1 "notes-service/pkg/proto/notes/v1"
+)
+
+func makeNotes(n int) []model.Note {
+	now := time.Now()
+	notes := make([]model.Note, n)
+	for i := range notes {
+		notes[i] = model.Note{
+			ID:        fmt.Sprintf("note-%d", i),
+			Title:     fmt.Sprintf("Title %d", i),
+			Content:   "Benchmark content for allocation measurements",
+			CreatedAt: now,
+			UpdatedAt: now,
+			Version:   1,
+		}
+	}
+	return notes
+}
+
+func TestModelsToProtos_MatchesModelToProto(t *testing.T) {
+	notes := makeNotes(3)
+	notes[1].UpdatedAt = time.Time{}
+
+	protoNotes := ModelsToProtos(notes)
+	if len(protoNotes) != len(notes) {
+		t.Fatalf("Expected %d notes, got %d", len(notes), len(protoNotes))
+	}
+
+	for i, note := range notes {
+		expected := ModelToProto(note)
+		got := protoNotes[i]
+		if got.GetId() != expected.GetId() || got.GetTitle() != expected.GetTitle() || got.GetVersion() != expected.GetVersion() {
+			t.Errorf("Note %d mismatch: expected %v, got %v", i, expected, got)
+		}
+		if (got.GetUpdatedAt() == nil) != (expected.GetUpdatedAt() == nil) {
+			t.Errorf("Note %d: expected nil UpdatedAt to be preserved", i)
+		}
+		if got.GetCreatedAt().AsTime() != expected.GetCreatedAt().AsTime() {
+			t.Errorf("Note %d: CreatedAt mismatch", i)
+		}
+	}
+}
+
+func TestModelToProtoPooled_ReleaseResetsMessage(t *testing.T) {
+	note := makeNotes(1)[0]
+
+	protoNote, release := ModelToProtoPooled(note)
+	if protoNote.GetId() != note.ID {
+		t.Fatalf("Expected ID %q, got %q", note.ID, protoNote.GetId())
+	}
+
+	release()
+
+	if poolingEnabled && protoNote.GetId() != "" {
+		t.Errorf("Expected pooled message to be reset after release, got ID %q", protoNote.GetId())
+	}
+}
+
+// BenchmarkModelsToProtos измеряет аллокации при конвертации ответа ListNotes
+// Сравнение с отключенным пулом: go test -bench . -benchmem -tags noconvpool ./internal/converter/
+func BenchmarkModelsToProtos(b *testing.B) {
+	for _, n := range []int{10, 1000} {
+		notes := makeNotes(n)
+		b.Run(fmt.Sprintf("notes=%d", n), func(b *testing.B) {
+			b.ReportAllocs()
+			for b.Loop() {
+				_ = ModelsToProtos(notes)
+			}
+		})
+	}
+}
+
+// BenchmarkModelToProto измеряет аллокации при конвертации одной заметки (GetNote, события)
+func BenchmarkModelToProto(b *testing.B) {
+	note := makeNotes(1)[0]
+
+	b.Run("plain", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			_ = ModelToProto(note)
+		}
+	})
+
+	b.Run("pooled", func(b *testing.B) {
+		b.ReportAllocs()
+		var sink *notesv1.Note
+		for b.Loop() {
+			protoNote, release := ModelToProtoPooled(note)
+			sink = protoNote
+			release()
+		}
+		_ = sink
+	})
+}
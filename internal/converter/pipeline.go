@@ -0,0 +1,19 @@
+package converter
+
+import (
+	"notes-service/internal/pipeline"
+	notesv1 "notes-service/pkg/proto/notes/v1"
+)
+
+// PipelineChainsToProto конвертирует цепочки интерцепторов и middleware в proto
+func PipelineChainsToProto(chains []pipeline.Chain) []*notesv1.PipelineChain {
+	result := make([]*notesv1.PipelineChain, 0, len(chains))
+	for _, chain := range chains {
+		stages := make([]*notesv1.PipelineStage, 0, len(chain.Stages))
+		for _, stage := range chain.Stages {
+			stages = append(stages, &notesv1.PipelineStage{Name: stage.Name, Settings: stage.Settings})
+		}
+		result = append(result, &notesv1.PipelineChain{Name: chain.Name, Stages: stages})
+	}
+	return result
+}
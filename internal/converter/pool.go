@@ -0,0 +1,120 @@
+package converter
+
+import (
+	"sync"
+	"time"
+
+	"notes-service/internal/model"
+	notesv1 "notes-service/pkg/proto/notes/v1"
+
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// noteBlock объединяет proto заметку, её временные метки и время чтения в одну аллокацию
+// Вместо пяти отдельных аллокаций (Note + 3 Timestamp + Duration) выполняется одна
+type noteBlock struct {
+	note        notesv1.Note
+	createdAt   timestamppb.Timestamp
+	updatedAt   timestamppb.Timestamp
+	remindAt    timestamppb.Timestamp
+	readingTime durationpb.Duration
+}
+
+// fill заполняет блок данными доменной модели без дополнительных аллокаций
+func (b *noteBlock) fill(note model.Note) *notesv1.Note {
+	b.note.Id = note.ID
+	b.note.Title = note.Title
+	b.note.Content = note.Content
+	b.note.Version = note.Version
+	b.note.Tags = note.Tags
+	b.note.OwnerId = note.OwnerID
+	b.note.Pinned = note.Pinned
+	b.note.Archived = note.Archived
+	b.note.IsE2E = note.IsE2E
+	b.note.E2EScheme = note.E2EScheme
+	b.note.ContentEncrypted = note.ContentEncrypted
+	b.note.EncryptionKeyId = note.KeyID
+	b.note.PassphraseProtected = note.PassphraseProtected()
+	b.note.WordCount = int32(note.WordCount)
+	b.note.ReadingTime = setDuration(&b.readingTime, note.ReadingTime)
+	b.note.CreatedAt = setTimestamp(&b.createdAt, note.CreatedAt)
+	b.note.UpdatedAt = setTimestamp(&b.updatedAt, note.UpdatedAt)
+	b.note.RemindAt = setTimestamp(&b.remindAt, note.RemindAt)
+	return &b.note
+}
+
+// reset очищает блок перед возвратом в пул, чтобы не удерживать строки заметки
+func (b *noteBlock) reset() {
+	b.note.Reset()
+	b.createdAt.Reset()
+	b.updatedAt.Reset()
+	b.remindAt.Reset()
+	b.readingTime.Reset()
+}
+
+// setTimestamp заполняет Timestamp на месте (аналог timestamppb.New без аллокации)
+// Для нулевого времени возвращает nil, как и ModelToProto
+func setTimestamp(ts *timestamppb.Timestamp, t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+	ts.Seconds = t.Unix()
+	ts.Nanos = int32(t.Nanosecond())
+	return ts
+}
+
+// setDuration заполняет Duration на месте (аналог durationpb.New без аллокации)
+// Для нулевой длительности возвращает nil, как и ModelToProto
+func setDuration(pd *durationpb.Duration, d time.Duration) *durationpb.Duration {
+	if d == 0 {
+		return nil
+	}
+	pd.Seconds = int64(d / time.Second)
+	pd.Nanos = int32(d % time.Second)
+	return pd
+}
+
+var noteBlockPool = sync.Pool{
+	New: func() any {
+		return new(noteBlock)
+	},
+}
+
+// ModelToProtoPooled конвертирует domain модель Note в proto, переиспользуя объекты из sync.Pool
+// Возвращенное сообщение валидно только до вызова release, поэтому функция подходит
+// для стримов, где stream.Send сериализует сообщение синхронно:
+//
+//	protoNote, release := converter.ModelToProtoPooled(note)
+//	err := stream.Send(...protoNote...)
+//	release()
+//
+// Для unary ответов пул использовать нельзя: сообщение сериализуется уже после возврата из хэндлера.
+// gRPC разрешает stats handler'ам читать сообщение лениво после SendMsg, поэтому при подключении
+// таких обработчиков (трейсинг otelgrpc) пул использовать нельзя: сервер отключает его для стримов
+// при включенной трассировке или настройке streaming.disable_message_pool, а сборка с тегом
+// noconvpool - во всем бинарнике.
+func ModelToProtoPooled(note model.Note) (*notesv1.Note, func()) {
+	if !poolingEnabled {
+		return ModelToProto(note), func() {}
+	}
+
+	b := noteBlockPool.Get().(*noteBlock)
+	return b.fill(note), func() {
+		b.reset()
+		noteBlockPool.Put(b)
+	}
+}
+
+// modelsToProtosArena конвертирует слайс заметок, размещая все сообщения в одном блоке памяти
+// Количество аллокаций не зависит от числа заметок (блок + слайс указателей)
+// Цена: пока жива хотя бы одна заметка из ответа, весь блок остается в памяти
+func modelsToProtosArena(notes []model.Note) []*notesv1.Note {
+	blocks := make([]noteBlock, len(notes))
+	protoNotes := make([]*notesv1.Note, len(notes))
+	for i, note := range notes {
+		protoNotes[i] = blocks[i].fill(note)
+	}
+
+	return protoNotes
+}
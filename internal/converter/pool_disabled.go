@@ -0,0 +1,6 @@
+//go:build noconvpool
+
+package converter
+
+// poolingEnabled отключен тегом сборки noconvpool: каждое сообщение аллоцируется отдельно
+const poolingEnabled = false
@@ -0,0 +1,7 @@
+//go:build !noconvpool
+
+package converter
+
+// poolingEnabled включает пулинг и блочное размещение proto сообщений при конвертации
+// Отключается сборкой с тегом noconvpool: go build -tags noconvpool ./...
+const poolingEnabled = true
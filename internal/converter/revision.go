@@ -0,0 +1,82 @@
+package converter
+
+import (
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"notes-service/internal/diff"
+	"notes-service/internal/model"
+	svc "notes-service/internal/service"
+	notesv1 "notes-service/pkg/proto/notes/v1"
+)
+
+// RevisionToProto конвертирует domain модель NoteRevision в proto
+func RevisionToProto(revision model.NoteRevision) *notesv1.NoteRevision {
+	var createdAt *timestamppb.Timestamp
+	if !revision.CreatedAt.IsZero() {
+		createdAt = timestamppb.New(revision.CreatedAt)
+	}
+
+	return &notesv1.NoteRevision{
+		NoteId:    revision.NoteID,
+		Revision:  revision.Revision,
+		Title:     revision.Title,
+		Content:   revision.Content,
+		CreatedAt: createdAt,
+
+		ContentEncrypted: revision.ContentEncrypted,
+	}
+}
+
+// RevisionsToProtos конвертирует слайс domain моделей ревизий в слайс proto
+func RevisionsToProtos(revisions []model.NoteRevision) []*notesv1.NoteRevision {
+	if revisions == nil {
+		return nil
+	}
+
+	protoRevisions := make([]*notesv1.NoteRevision, len(revisions))
+	for i, revision := range revisions {
+		protoRevisions[i] = RevisionToProto(revision)
+	}
+
+	return protoRevisions
+}
+
+// RevisionDiffToProto конвертирует различия ревизий в proto (блоки в структурированном виде)
+func RevisionDiffToProto(d svc.RevisionDiff) *notesv1.DiffNoteRevisionsResponse {
+	hunks := make([]*notesv1.DiffHunk, len(d.Hunks))
+	for i, hunk := range d.Hunks {
+		lines := make([]*notesv1.DiffLine, len(hunk.Lines))
+		for j, line := range hunk.Lines {
+			lines[j] = &notesv1.DiffLine{Kind: diffLineKind(line.Op), Text: line.Text}
+		}
+		hunks[i] = &notesv1.DiffHunk{
+			FromStart: int32(hunk.FromStart),
+			FromLines: int32(hunk.FromLines),
+			ToStart:   int32(hunk.ToStart),
+			ToLines:   int32(hunk.ToLines),
+			Lines:     lines,
+		}
+	}
+
+	return &notesv1.DiffNoteRevisionsResponse{
+		NoteId:       d.NoteID,
+		FromRevision: d.FromRevision,
+		ToRevision:   d.ToRevision,
+		FromTitle:    d.FromTitle,
+		ToTitle:      d.ToTitle,
+		Hunks:        hunks,
+		LinesAdded:   int32(d.LinesAdded),
+		LinesRemoved: int32(d.LinesRemoved),
+	}
+}
+
+// diffLineKind конвертирует вид строки диффа в proto enum
+func diffLineKind(op diff.Op) notesv1.DiffLineKind {
+	switch op {
+	case diff.Insert:
+		return notesv1.DiffLineKind_DIFF_LINE_KIND_ADDED
+	case diff.Delete:
+		return notesv1.DiffLineKind_DIFF_LINE_KIND_REMOVED
+	default:
+		return notesv1.DiffLineKind_DIFF_LINE_KIND_CONTEXT
+	}
+}
@@ -0,0 +1,28 @@
+package converter
+
+import (
+	"notes-service/internal/model"
+	notesv1 "notes-service/pkg/proto/notes/v1"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// SavedSearchToProto конвертирует сохраненный поиск в proto
+func SavedSearchToProto(search model.SavedSearch) *notesv1.SavedSearch {
+	return &notesv1.SavedSearch{
+		Id:        search.ID,
+		Name:      search.Name,
+		Query:     search.Query,
+		CreatedAt: timestamppb.New(search.CreatedAt),
+		UpdatedAt: timestamppb.New(search.UpdatedAt),
+	}
+}
+
+// SavedSearchesToProto конвертирует список сохраненных поисков в proto
+func SavedSearchesToProto(searches []model.SavedSearch) []*notesv1.SavedSearch {
+	result := make([]*notesv1.SavedSearch, 0, len(searches))
+	for _, search := range searches {
+		result = append(result, SavedSearchToProto(search))
+	}
+	return result
+}
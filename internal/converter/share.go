@@ -0,0 +1,46 @@
+package converter
+
+import (
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"notes-service/internal/model"
+	notesv1 "notes-service/pkg/proto/notes/v1"
+)
+
+// SharePermissionToProto конвертирует уровень доступа в proto enum
+// Значения model.SharePermission совпадают с номерами SharePermission в proto
+func SharePermissionToProto(permission model.SharePermission) notesv1.SharePermission {
+	return notesv1.SharePermission(permission)
+}
+
+// SharePermissionFromProto конвертирует proto enum в уровень доступа
+func SharePermissionFromProto(permission notesv1.SharePermission) model.SharePermission {
+	return model.SharePermission(permission)
+}
+
+// ShareToProto конвертирует domain модель Share в proto
+func ShareToProto(share model.Share) *notesv1.Share {
+	var createdAt *timestamppb.Timestamp
+	if !share.CreatedAt.IsZero() {
+		createdAt = timestamppb.New(share.CreatedAt)
+	}
+
+	return &notesv1.Share{
+		NoteId:     share.NoteID,
+		OwnerId:    share.OwnerID,
+		UserId:     share.UserID,
+		Permission: SharePermissionToProto(share.Permission),
+		CreatedAt:  createdAt,
+	}
+}
+
+// SharedNotesToProto конвертирует слайс доступных заметок в слайс proto
+func SharedNotesToProto(notes []model.SharedNote) []*notesv1.SharedNote {
+	result := make([]*notesv1.SharedNote, len(notes))
+	for i, shared := range notes {
+		result[i] = &notesv1.SharedNote{
+			Note:       ModelToProto(shared.Note),
+			Permission: SharePermissionToProto(shared.Permission),
+		}
+	}
+	return result
+}
@@ -0,0 +1,38 @@
+package converter
+
+import (
+	"notes-service/internal/model"
+	notesv1 "notes-service/pkg/proto/notes/v1"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// NoteStatsToProto конвертирует статистику заметки в proto
+func NoteStatsToProto(stats model.NoteStats) *notesv1.NoteStats {
+	protoStats := &notesv1.NoteStats{
+		NoteId:             stats.NoteID,
+		WordCount:          stats.WordCount,
+		CharacterCount:     stats.CharacterCount,
+		ReadingTimeSeconds: int64(stats.ReadingTime.Seconds()),
+		UpdatedAt:          timestamppb.New(stats.UpdatedAt),
+		IsE2E:              stats.IsE2E,
+	}
+	if stats.LastEdit != nil {
+		protoStats.LastEdit = &notesv1.NoteEditDelta{
+			Revision:        stats.LastEdit.Revision,
+			WordsDelta:      stats.LastEdit.WordsDelta,
+			CharactersDelta: stats.LastEdit.CharactersDelta,
+		}
+	}
+	return protoStats
+}
+
+// AccountStatsToProto конвертирует сводную статистику пользователя в proto
+func AccountStatsToProto(stats model.AccountStats) *notesv1.AccountStats {
+	return &notesv1.AccountStats{
+		TotalNotes:      stats.TotalNotes,
+		TotalWords:      stats.TotalWords,
+		TotalCharacters: stats.TotalCharacters,
+		NotesPerTag:     TagCountsToProto(stats.NotesPerTag),
+	}
+}
@@ -0,0 +1,22 @@
+package converter
+
+import (
+	"notes-service/internal/model"
+	notesv1 "notes-service/pkg/proto/notes/v1"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// NoteSuggestionsToProto конвертирует подсказки заголовков заметок в proto
+func NoteSuggestionsToProto(suggestions []model.NoteSuggestion) []*notesv1.NoteSuggestion {
+	result := make([]*notesv1.NoteSuggestion, len(suggestions))
+	for i, suggestion := range suggestions {
+		result[i] = &notesv1.NoteSuggestion{
+			Id:        suggestion.NoteID,
+			Title:     suggestion.Title,
+			UpdatedAt: timestamppb.New(suggestion.UpdatedAt),
+			Views:     suggestion.Views,
+		}
+	}
+	return result
+}
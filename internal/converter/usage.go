@@ -0,0 +1,33 @@
+package converter
+
+import (
+	"notes-service/internal/model"
+	notesv1 "notes-service/pkg/proto/notes/v1"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// UsageStatsToProto конвертирует статистику использования в proto
+// Ошибка последней отправки не конвертируется: ее статус формирует хэндлер
+func UsageStatsToProto(s model.UsageStats) *notesv1.GetUsageStatsResponse {
+	methods := make([]*notesv1.MethodUsage, 0, len(s.Methods))
+	for _, m := range s.Methods {
+		methods = append(methods, &notesv1.MethodUsage{Method: m.Method, Calls: m.Calls, Errors: m.Errors})
+	}
+	features := make([]*notesv1.FeatureUsage, 0, len(s.Features))
+	for _, f := range s.Features {
+		features = append(features, &notesv1.FeatureUsage{Feature: f.Feature, Count: f.Count})
+	}
+
+	return &notesv1.GetUsageStatsResponse{
+		InstallationId: s.InstallationID,
+		Since:          timestamppb.New(s.Since),
+		Methods:        methods,
+		Features:       features,
+		Reporting: &notesv1.UsageReporting{
+			Enabled:        s.Reporting.Enabled,
+			LastReportTime: optionalTimestamp(s.Reporting.LastReportAt),
+			Reports:        s.Reporting.Reports,
+		},
+	}
+}
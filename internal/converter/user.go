@@ -0,0 +1,32 @@
+package converter
+
+import (
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"notes-service/internal/model"
+	notesv1 "notes-service/pkg/proto/notes/v1"
+)
+
+// UserToProto конвертирует domain модель User в proto (без хэша пароля)
+func UserToProto(user model.User) *notesv1.User {
+	var createdAt *timestamppb.Timestamp
+	if !user.CreatedAt.IsZero() {
+		createdAt = timestamppb.New(user.CreatedAt)
+	}
+
+	return &notesv1.User{
+		Id:          user.ID,
+		Username:    user.Username,
+		Roles:       user.Roles,
+		CreatedAt:   createdAt,
+		HasPassword: user.PasswordHash != "",
+	}
+}
+
+// UsersToProto конвертирует слайс пользователей в слайс proto
+func UsersToProto(users []model.User) []*notesv1.User {
+	result := make([]*notesv1.User, len(users))
+	for i, user := range users {
+		result[i] = UserToProto(user)
+	}
+	return result
+}
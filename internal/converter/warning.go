@@ -0,0 +1,23 @@
+package converter
+
+import (
+	"notes-service/internal/model"
+	notesv1 "notes-service/pkg/proto/notes/v1"
+)
+
+// WarningsToProto конвертирует предупреждения сервиса в proto
+func WarningsToProto(warnings []model.Warning) []*notesv1.Warning {
+	if len(warnings) == 0 {
+		return nil
+	}
+
+	result := make([]*notesv1.Warning, len(warnings))
+	for i, w := range warnings {
+		result[i] = &notesv1.Warning{
+			Code:    w.Code,
+			Message: w.Message,
+			Field:   w.Field,
+		}
+	}
+	return result
+}
@@ -0,0 +1,64 @@
+package converter
+
+import (
+	"notes-service/internal/model"
+	notesv1 "notes-service/pkg/proto/notes/v1"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// eventTypesByName типы событий proto по именам, которые хранятся в вебхуках (notes.EventType.String)
+var eventTypesByName = map[string]notesv1.EventType{
+	"note_created":         notesv1.EventType_EVENT_TYPE_NOTE_CREATED,
+	"note_updated":         notesv1.EventType_EVENT_TYPE_NOTE_UPDATED,
+	"note_deleted":         notesv1.EventType_EVENT_TYPE_NOTE_DELETED,
+	"note_shared":          notesv1.EventType_EVENT_TYPE_NOTE_SHARED,
+	"note_reminder_due":    notesv1.EventType_EVENT_TYPE_NOTE_REMINDER_DUE,
+	"export_completed":     notesv1.EventType_EVENT_TYPE_EXPORT_COMPLETED,
+	"saved_search_matched": notesv1.EventType_EVENT_TYPE_SAVED_SEARCH_MATCHED,
+	"quota_warning":        notesv1.EventType_EVENT_TYPE_QUOTA_WARNING,
+	"note_archived":        notesv1.EventType_EVENT_TYPE_NOTE_ARCHIVED,
+}
+
+// WebhookToProto конвертирует вебхук в proto (ключ подписи - если он заполнен)
+func WebhookToProto(webhook model.Webhook) *notesv1.Webhook {
+	eventTypes := make([]notesv1.EventType, 0, len(webhook.EventTypes))
+	for _, name := range webhook.EventTypes {
+		eventTypes = append(eventTypes, eventTypesByName[name])
+	}
+
+	return &notesv1.Webhook{
+		Id:         webhook.ID,
+		Url:        webhook.URL,
+		EventTypes: eventTypes,
+		Secret:     webhook.Secret,
+		CreatedAt:  timestamppb.New(webhook.CreatedAt),
+	}
+}
+
+// WebhooksToProto конвертирует список вебхуков в proto
+func WebhooksToProto(webhooks []model.Webhook) []*notesv1.Webhook {
+	result := make([]*notesv1.Webhook, 0, len(webhooks))
+	for _, webhook := range webhooks {
+		result = append(result, WebhookToProto(webhook))
+	}
+	return result
+}
+
+// WebhookDeadLettersToProto конвертирует недоставленные события в proto
+func WebhookDeadLettersToProto(letters []model.WebhookDeadLetter) []*notesv1.WebhookDeadLetter {
+	result := make([]*notesv1.WebhookDeadLetter, 0, len(letters))
+	for _, letter := range letters {
+		result = append(result, &notesv1.WebhookDeadLetter{
+			Id:        letter.ID,
+			WebhookId: letter.WebhookID,
+			Url:       letter.URL,
+			EventType: eventTypesByName[letter.EventType],
+			Payload:   string(letter.Payload),
+			Attempts:  int32(letter.Attempts),
+			LastError: letter.LastError,
+			FailedAt:  timestamppb.New(letter.FailedAt),
+		})
+	}
+	return result
+}
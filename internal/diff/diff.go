@@ -0,0 +1,256 @@
+// Package diff сравнивает тексты построчно (алгоритм Майерса) и группирует изменения
+// в блоки (hunks) с контекстом, как diff -u
+package diff
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrTooManyChanges возвращается, когда тексты различаются больше, чем на допустимое количество строк
+var ErrTooManyChanges = errors.New("too many changed lines to compute diff")
+
+// Op вид строки диффа
+type Op int
+
+const (
+	Equal  Op = iota // Строка есть в обоих текстах (контекст)
+	Insert           // Строка добавлена во втором тексте
+	Delete           // Строка удалена из первого текста
+)
+
+// Line строка диффа
+type Line struct {
+	Op   Op
+	Text string
+}
+
+// Hunk блок изменений с окружающими строками контекста
+// Start - номер первой строки блока (с 1); для пустого диапазона - номер строки перед ним, как в diff -u
+type Hunk struct {
+	FromStart int
+	FromLines int
+	ToStart   int
+	ToLines   int
+	Lines     []Line
+}
+
+// Split разбивает текст на строки; завершающий перевод строки не образует пустую строку
+func Split(text string) []string {
+	if text == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+}
+
+// Lines сравнивает строки from и to и возвращает кратчайшую последовательность правок
+// maxEdits ограничивает количество добавленных и удаленных строк (0 - без ограничения):
+// сложность алгоритма растет с количеством правок, поэтому для сильно различающихся
+// текстов возвращается ErrTooManyChanges
+func Lines(from, to []string, maxEdits int) ([]Line, error) {
+	// Общие начало и конец не участвуют в поиске
+	prefix := 0
+	for prefix < len(from) && prefix < len(to) && from[prefix] == to[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(from)-prefix && suffix < len(to)-prefix &&
+		from[len(from)-1-suffix] == to[len(to)-1-suffix] {
+		suffix++
+	}
+
+	middle, err := myers(from[prefix:len(from)-suffix], to[prefix:len(to)-suffix], maxEdits)
+	if err != nil {
+		return nil, err
+	}
+
+	lines := make([]Line, 0, prefix+len(middle)+suffix)
+	for _, text := range from[:prefix] {
+		lines = append(lines, Line{Op: Equal, Text: text})
+	}
+	lines = append(lines, middle...)
+	for _, text := range from[len(from)-suffix:] {
+		lines = append(lines, Line{Op: Equal, Text: text})
+	}
+	return lines, nil
+}
+
+// myers находит кратчайший путь правок из a в b
+// trace[d] хранит самые дальние x диагоналей -d..d после шага d для восстановления пути
+func myers(a, b []string, maxEdits int) ([]Line, error) {
+	n, m := len(a), len(b)
+	maxD := n + m
+	if maxEdits > 0 && maxEdits < maxD {
+		maxD = maxEdits
+	}
+
+	offset := maxD + 1
+	v := make([]int, 2*maxD+3)
+	var trace [][]int
+	for d := 0; d <= maxD; d++ {
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1] // Шаг вниз: вставка строки b
+			} else {
+				x = v[offset+k-1] + 1 // Шаг вправо: удаление строки a
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[offset+k] = x
+
+			if x >= n && y >= m {
+				trace = append(trace, append([]int(nil), v[offset-d:offset+d+1]...))
+				return backtrack(a, b, trace), nil
+			}
+		}
+		trace = append(trace, append([]int(nil), v[offset-d:offset+d+1]...))
+	}
+	return nil, ErrTooManyChanges
+}
+
+// backtrack восстанавливает правки по trace, проходя путь от конца к началу
+func backtrack(a, b []string, trace [][]int) []Line {
+	x, y := len(a), len(b)
+	lines := make([]Line, 0, x+y)
+	for d := len(trace) - 1; d > 0; d-- {
+		prev := trace[d-1] // Диагонали -(d-1)..d-1, индекс k+d-1
+		k := x - y
+
+		var prevK int
+		if k == -d || (k != d && prev[k-1+d-1] < prev[k+1+d-1]) {
+			prevK = k + 1
+		} else {
+			prevK = k - 1
+		}
+		prevX := prev[prevK+d-1]
+		prevY := prevX - prevK
+
+		// Путь шага d: одна правка из (prevX, prevY), затем диагональ общих строк до (x, y)
+		insert := prevK == k+1
+		midX := prevX
+		if !insert {
+			midX++
+		}
+		for x > midX {
+			lines = append(lines, Line{Op: Equal, Text: a[x-1]})
+			x--
+			y--
+		}
+		if insert {
+			lines = append(lines, Line{Op: Insert, Text: b[prevY]})
+		} else {
+			lines = append(lines, Line{Op: Delete, Text: a[prevX]})
+		}
+		x, y = prevX, prevY
+	}
+	for x > 0 {
+		lines = append(lines, Line{Op: Equal, Text: a[x-1]})
+		x--
+	}
+
+	for i, j := 0, len(lines)-1; i < j; i, j = i+1, j-1 {
+		lines[i], lines[j] = lines[j], lines[i]
+	}
+	return lines
+}
+
+// Hunks группирует изменения в блоки с context строками контекста до и после
+// Блоки, между которыми не больше 2*context общих строк, объединяются
+func Hunks(lines []Line, context int) []Hunk {
+	context = max(context, 0)
+
+	// Строка попадает в блок, если до ближайшего изменения не больше context строк
+	include := make([]bool, len(lines))
+	last := -1
+	for i, line := range lines {
+		if line.Op != Equal {
+			last = i
+		}
+		include[i] = last >= 0 && i-last <= context
+	}
+	last = -1
+	for i := len(lines) - 1; i >= 0; i-- {
+		if lines[i].Op != Equal {
+			last = i
+		}
+		include[i] = include[i] || (last >= 0 && last-i <= context)
+	}
+
+	var hunks []Hunk
+	var current *Hunk
+	fromLine, toLine := 1, 1
+	for i, line := range lines {
+		if !include[i] {
+			current = nil
+		} else {
+			if current == nil {
+				hunks = append(hunks, Hunk{FromStart: fromLine, ToStart: toLine})
+				current = &hunks[len(hunks)-1]
+			}
+			current.Lines = append(current.Lines, line)
+			if line.Op != Insert {
+				current.FromLines++
+			}
+			if line.Op != Delete {
+				current.ToLines++
+			}
+		}
+
+		if line.Op != Insert {
+			fromLine++
+		}
+		if line.Op != Delete {
+			toLine++
+		}
+	}
+
+	for i := range hunks {
+		if hunks[i].FromLines == 0 {
+			hunks[i].FromStart--
+		}
+		if hunks[i].ToLines == 0 {
+			hunks[i].ToStart--
+		}
+	}
+	return hunks
+}
+
+// Unified форматирует блоки в формате diff -u с заголовками fromName и toName
+// Для текстов без различий возвращается пустая строка
+func Unified(fromName, toName string, hunks []Hunk) string {
+	if len(hunks) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "--- %s\n+++ %s\n", fromName, toName)
+	for _, hunk := range hunks {
+		fmt.Fprintf(&b, "@@ -%s +%s @@\n", unifiedRange(hunk.FromStart, hunk.FromLines), unifiedRange(hunk.ToStart, hunk.ToLines))
+		for _, line := range hunk.Lines {
+			switch line.Op {
+			case Insert:
+				b.WriteByte('+')
+			case Delete:
+				b.WriteByte('-')
+			default:
+				b.WriteByte(' ')
+			}
+			b.WriteString(line.Text)
+			b.WriteByte('\n')
+		}
+	}
+	return b.String()
+}
+
+// unifiedRange форматирует диапазон строк блока: количество 1 опускается
+func unifiedRange(start, count int) string {
+	if count == 1 {
+		return fmt.Sprint(start)
+	}
+	return fmt.Sprintf("%d,%d", start, count)
+}
@@ -0,0 +1,153 @@
+package diff
+
+import (
+	"errors"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+// apply восстанавливает оба текста по правкам
+func apply(lines []Line) (from, to []string) {
+	for _, line := range lines {
+		if line.Op != Insert {
+			from = append(from, line.Text)
+		}
+		if line.Op != Delete {
+			to = append(to, line.Text)
+		}
+	}
+	return from, to
+}
+
+func edits(lines []Line) int {
+	count := 0
+	for _, line := range lines {
+		if line.Op != Equal {
+			count++
+		}
+	}
+	return count
+}
+
+func TestLines_ShortestEditScript(t *testing.T) {
+	tests := []struct {
+		name     string
+		from, to string
+		edits    int
+	}{
+		{name: "equal", from: "a\nb\nc", to: "a\nb\nc", edits: 0},
+		{name: "empty to text", from: "", to: "a\nb", edits: 2},
+		{name: "text to empty", from: "a\nb", to: "", edits: 2},
+		{name: "replace middle", from: "a\nb\nc", to: "a\nx\nc", edits: 2},
+		{name: "insert and delete", from: "a\nb\nc\nd", to: "b\nc\ne\nd", edits: 2},
+		{name: "classic", from: "a\nb\nc\na\nb\nb\na", to: "c\nb\na\nb\na\nc", edits: 5},
+		{name: "trailing newline", from: "a\nb\n", to: "a\nb", edits: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			from, to := Split(tt.from), Split(tt.to)
+			lines, err := Lines(from, to, 0)
+			if err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+			gotFrom, gotTo := apply(lines)
+			if strings.Join(gotFrom, "\n") != strings.Join(from, "\n") || strings.Join(gotTo, "\n") != strings.Join(to, "\n") {
+				t.Errorf("Diff does not reproduce the texts: %+v", lines)
+			}
+			if got := edits(lines); got != tt.edits {
+				t.Errorf("Expected %d edits, got %d: %+v", tt.edits, got, lines)
+			}
+		})
+	}
+}
+
+func TestLines_RandomTextsRoundTrip(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	alphabet := []string{"a", "b", "c", "d"}
+	random := func() []string {
+		lines := make([]string, rng.Intn(30))
+		for i := range lines {
+			lines[i] = alphabet[rng.Intn(len(alphabet))]
+		}
+		return lines
+	}
+
+	for i := 0; i < 200; i++ {
+		from, to := random(), random()
+		lines, err := Lines(from, to, 0)
+		if err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+		gotFrom, gotTo := apply(lines)
+		if strings.Join(gotFrom, ",") != strings.Join(from, ",") || strings.Join(gotTo, ",") != strings.Join(to, ",") {
+			t.Fatalf("Diff of %v and %v does not reproduce the texts: %+v", from, to, lines)
+		}
+	}
+}
+
+func TestLines_MaxEdits(t *testing.T) {
+	from := Split("a\nb\nc\nd")
+	to := Split("w\nx\ny\nz")
+	if _, err := Lines(from, to, 7); !errors.Is(err, ErrTooManyChanges) {
+		t.Errorf("Expected ErrTooManyChanges, got: %v", err)
+	}
+	if _, err := Lines(from, to, 8); err != nil {
+		t.Errorf("Expected diff within the limit, got: %v", err)
+	}
+	// Общие начало и конец не считаются правками
+	if _, err := Lines(Split("a\nb\nc"), Split("a\nx\nc"), 2); err != nil {
+		t.Errorf("Expected no error, got: %v", err)
+	}
+}
+
+func TestHunksAndUnified(t *testing.T) {
+	from := Split("1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12")
+	to := Split("1\n2\nthree\n4\n5\n6\n7\n8\n9\n10\n11\n12\n13")
+
+	lines, err := Lines(from, to, 0)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	hunks := Hunks(lines, 2)
+	if len(hunks) != 2 {
+		t.Fatalf("Expected 2 hunks, got %d: %+v", len(hunks), hunks)
+	}
+	if h := hunks[0]; h.FromStart != 1 || h.FromLines != 5 || h.ToStart != 1 || h.ToLines != 5 {
+		t.Errorf("Unexpected first hunk range: %+v", h)
+	}
+
+	want := `--- a
++++ b
+@@ -1,5 +1,5 @@
+ 1
+ 2
+-3
++three
+ 4
+ 5
+@@ -11,2 +11,3 @@
+ 11
+ 12
++13
+`
+	if got := Unified("a", "b", hunks); got != want {
+		t.Errorf("Unexpected unified diff:\n%s\nwant:\n%s", got, want)
+	}
+
+	// Близкие изменения объединяются в один блок
+	if merged := Hunks(lines, 5); len(merged) != 1 {
+		t.Errorf("Expected hunks to merge with 5 context lines, got %d", len(merged))
+	}
+
+	// Пустой диапазон указывает на строку перед ним
+	added := Hunks([]Line{{Op: Insert, Text: "x"}}, 3)
+	if got := Unified("a", "b", added); got != "--- a\n+++ b\n@@ -0,0 +1 @@\n+x\n" {
+		t.Errorf("Unexpected unified diff for added line: %q", got)
+	}
+
+	if Unified("a", "b", nil) != "" {
+		t.Error("Expected empty unified diff for equal texts")
+	}
+}
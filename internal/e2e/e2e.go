@@ -0,0 +1,198 @@
+// Package e2e запускает полный сервер заметок с временной конфигурацией и прогоняет
+// сценарий проверки REST, gRPC, WebSocket и streaming методов (smoke test одной командой)
+package e2e
+
+import (
+	"context"
+	"embed"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"notes-service/internal/config"
+	"notes-service/internal/server"
+	notesv1 "notes-service/pkg/proto/notes/v1"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// Token токен пользователя, от имени которого выполняется сценарий
+const Token = "my-secret-token"
+
+// Учетные данные того же пользователя для входа через AuthService
+const (
+	username = "demo"
+	password = "demo-password"
+)
+
+// readyPollInterval интервал проверки готовности сервера после запуска
+const readyPollInterval = 50 * time.Millisecond
+
+// configTemplate временная конфигурация сервера: свободные порты, каталоги во временной директории,
+// Swagger UI и запись запросов выключены
+const configTemplate = `server:
+  port_grpc: %d
+  port_http: %d
+  graceful_shutdown_timeout: 5
+  access_denied_policy: not_found
+  stream_rate_limits:
+    chat:
+      messages_per_second: 10
+      burst: 20
+gateway:
+  cors_allowed_origins: http://localhost
+  rate_limit_rps: 100
+  rate_limit_burst: 100
+  shutdown_drain_seconds: 0
+swagger:
+  enabled: false
+attachments:
+  storage: filesystem
+  dir: %s
+  max_size_mb: 1
+exports:
+  destination: filesystem
+  dir: %s
+metrics:
+  storage: memory
+auth:
+  providers: session,static
+  static_tokens:
+    - token: ` + Token + `
+      user_id: demo
+      roles: [user]
+  sessions:
+    users:
+      - username: ` + username + `
+        password: ` + password + `
+        user_id: demo
+        roles: [user]
+`
+
+// Run запускает сервер, выполняет сценарий и останавливает сервер
+// Ход выполнения пишется в out, возвращается ошибка первого неуспешного шага
+// opts передаются серверу (например, чтобы проверить сценарий с другим хранилищем)
+func Run(ctx context.Context, out io.Writer, opts ...server.Option) error {
+	dir, err := os.MkdirTemp("", "notes-e2e-*")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+
+	cfg, err := writeConfig(dir)
+	if err != nil {
+		return err
+	}
+
+	srv, err := server.NewServer(cfg, embed.FS{}, opts...)
+	if err != nil {
+		return fmt.Errorf("failed to create server: %w", err)
+	}
+	defer srv.Cancel()
+
+	if err := srv.Initialize(); err != nil {
+		return fmt.Errorf("failed to initialize server: %w", err)
+	}
+	errChan := srv.Start()
+	defer func() {
+		if err := srv.Shutdown(); err != nil {
+			fmt.Fprintf(out, "⚠️  Server shutdown: %v\n", err)
+		}
+	}()
+
+	// Ошибка запуска серверов прерывает сценарий
+	runCtx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+	go func() {
+		select {
+		case err := <-errChan:
+			cancel(err)
+		case <-runCtx.Done():
+		}
+	}()
+
+	grpcAddr := "127.0.0.1:" + strconv.Itoa(cfg.Server.PortGRPC)
+	conn, err := grpc.NewClient(grpcAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	e := &env{
+		client:   notesv1.NewNotesServiceClient(conn),
+		http:     &http.Client{Timeout: 10 * time.Second},
+		baseURL:  "http://127.0.0.1:" + strconv.Itoa(cfg.Server.PortHTTP),
+		grpcAddr: grpcAddr,
+	}
+	if err := e.waitReady(runCtx); err != nil {
+		return fmt.Errorf("server is not ready: %w", err)
+	}
+
+	for _, s := range scenario {
+		started := time.Now()
+		if err := s.run(runCtx, e); err != nil {
+			if cause := context.Cause(runCtx); cause != nil {
+				err = fmt.Errorf("%w (%v)", err, cause)
+			}
+			fmt.Fprintf(out, "❌ %s: %v\n", s.name, err)
+			return fmt.Errorf("step %q failed: %w", s.name, err)
+		}
+		fmt.Fprintf(out, "✅ %s (%s)\n", s.name, time.Since(started).Round(time.Millisecond))
+	}
+	return nil
+}
+
+// writeConfig записывает временный config.yml в dir и загружает его как обычную конфигурацию
+func writeConfig(dir string) (*config.Config, error) {
+	grpcPort, err := freePort()
+	if err != nil {
+		return nil, err
+	}
+	httpPort, err := freePort()
+	if err != nil {
+		return nil, err
+	}
+
+	content := fmt.Sprintf(configTemplate, grpcPort, httpPort,
+		filepath.Join(dir, "attachments"), filepath.Join(dir, "exports"))
+	path := filepath.Join(dir, "config.yml")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		return nil, err
+	}
+
+	return config.InitConfig[config.Config](path)
+}
+
+// freePort возвращает свободный TCP порт
+func freePort() (int, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return 0, err
+	}
+	defer listener.Close()
+	return listener.Addr().(*net.TCPAddr).Port, nil
+}
+
+// waitReady ожидает, пока Gateway начнет отвечать и проксировать запросы в gRPC сервер
+func (e *env) waitReady(ctx context.Context) error {
+	ticker := time.NewTicker(readyPollInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := e.doJSON(ctx, http.MethodGet, "/api/v1/notes/v1/server-info", nil, nil); err == nil {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return context.Cause(ctx)
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package e2e
+
+import (
+	"bytes"
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"notes-service/internal/repository/memory"
+	"notes-service/internal/server"
+
+	"google.golang.org/grpc"
+)
+
+func TestRun(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping end-to-end scenario in short mode")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	var out bytes.Buffer
+	if err := Run(ctx, &out); err != nil {
+		t.Fatalf("Expected scenario to pass, got: %v\n%s", err, out.String())
+	}
+	if got := bytes.Count(out.Bytes(), []byte("✅")); got != len(scenario) {
+		t.Errorf("Expected %d passed steps, got %d:\n%s", len(scenario), got, out.String())
+	}
+}
+
+func TestRun_WithServerOptions(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping end-to-end scenario in short mode")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	// Компоненты сервера подменяются без изменения Initialize
+	var unary, stream atomic.Int64
+	opts := []server.Option{
+		server.WithRepository(memory.NewRepository()),
+		server.WithInterceptors(
+			[]grpc.UnaryServerInterceptor{func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+				unary.Add(1)
+				return handler(ctx, req)
+			}},
+			[]grpc.StreamServerInterceptor{func(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+				stream.Add(1)
+				return handler(srv, ss)
+			}},
+		),
+	}
+
+	var out bytes.Buffer
+	if err := Run(ctx, &out, opts...); err != nil {
+		t.Fatalf("Expected scenario to pass, got: %v\n%s", err, out.String())
+	}
+	if unary.Load() == 0 || stream.Load() == 0 {
+		t.Errorf("Expected custom interceptors to see requests, got unary=%d stream=%d", unary.Load(), stream.Load())
+	}
+}
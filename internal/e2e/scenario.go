@@ -0,0 +1,581 @@
+package e2e
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"net/http/cookiejar"
+	"strings"
+	"time"
+
+	"notes-service/internal/selftest"
+	notesv1 "notes-service/pkg/proto/notes/v1"
+
+	"github.com/gorilla/websocket"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// eventTimeout время ожидания события или ответа в стриме
+const eventTimeout = 5 * time.Second
+
+// env клиенты сервера и состояние, передаваемое между шагами сценария
+type env struct {
+	client   notesv1.NotesServiceClient
+	http     *http.Client
+	baseURL  string
+	grpcAddr string
+
+	restNoteID string // Заметка, созданная через REST
+	grpcNoteID string // Заметка, созданная через gRPC во время подписки на события
+}
+
+// step шаг сценария
+type step struct {
+	name string
+	run  func(ctx context.Context, e *env) error
+}
+
+// scenario шаги выполняются по порядку, следующие шаги используют заметки, созданные предыдущими
+var scenario = []step{
+	{"REST: create note", restCreateNote},
+	{"REST: get note", restGetNote},
+	{"REST: reject request without token", restUnauthenticated},
+	{"REST: login, refresh and logout with cookies", restSession},
+	{"gRPC: update note", grpcUpdateNote},
+	{"gRPC: list notes", grpcListNotes},
+	{"Server streaming: SubscribeToEvents", streamSubscribeToEvents},
+	{"Client streaming: UploadMetrics", streamUploadMetrics},
+	{"gRPC: query uploaded metrics", grpcQueryMetrics},
+	{"Bidirectional streaming: StreamMetrics", streamMetrics},
+	{"Bidirectional streaming: Chat", streamChat},
+	{"WebSocket: StreamNotes", websocketStreamNotes},
+	{"Self-test: server --selftest scenario", runSelfTest},
+	{"gRPC: delete note", grpcDeleteNote},
+}
+
+// noteJSON заметка в JSON ответах Gateway
+type noteJSON struct {
+	ID      string `json:"id"`
+	Title   string `json:"title"`
+	Content string `json:"content"`
+}
+
+func restCreateNote(ctx context.Context, e *env) error {
+	var resp struct {
+		Note noteJSON `json:"note"`
+	}
+	body := map[string]string{"title": "E2E REST note", "content": "Created through the HTTP gateway"}
+	if err := e.doJSON(ctx, http.MethodPost, "/api/v1/notes/v1", body, &resp); err != nil {
+		return err
+	}
+	if resp.Note.ID == "" {
+		return errors.New("response has no note id")
+	}
+	e.restNoteID = resp.Note.ID
+	return nil
+}
+
+func restGetNote(ctx context.Context, e *env) error {
+	var resp struct {
+		Note noteJSON `json:"note"`
+	}
+	if err := e.doJSON(ctx, http.MethodGet, "/api/v1/notes/v1/"+e.restNoteID, nil, &resp); err != nil {
+		return err
+	}
+	if resp.Note.Title != "E2E REST note" {
+		return fmt.Errorf("expected title %q, got %q", "E2E REST note", resp.Note.Title)
+	}
+	return nil
+}
+
+func restUnauthenticated(ctx context.Context, e *env) error {
+	code, err := e.request(ctx, http.MethodGet, "/api/v1/notes/v1", "", nil, nil)
+	if err != nil {
+		return err
+	}
+	if code != http.StatusUnauthorized {
+		return fmt.Errorf("expected HTTP %d, got %d", http.StatusUnauthorized, code)
+	}
+	return nil
+}
+
+func restSession(ctx context.Context, e *env) error {
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		return err
+	}
+	client := &http.Client{Timeout: e.http.Timeout, Jar: jar}
+	post := func(path string, body any, out any) (int, error) {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return 0, err
+		}
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.baseURL+path, bytes.NewReader(data))
+		if err != nil {
+			return 0, err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := client.Do(req)
+		if err != nil {
+			return 0, err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode == http.StatusOK && out != nil {
+			return resp.StatusCode, json.NewDecoder(resp.Body).Decode(out)
+		}
+		return resp.StatusCode, nil
+	}
+	var tokens struct {
+		AccessToken string `json:"accessToken"`
+	}
+
+	if code, err := post("/api/v1/auth/v1/login", map[string]string{"username": username, "password": "wrong"}, nil); err != nil || code != http.StatusUnauthorized {
+		return fmt.Errorf("login with wrong password: expected HTTP %d, got %d (%v)", http.StatusUnauthorized, code, err)
+	}
+	if code, err := post("/api/v1/auth/v1/login", map[string]string{"username": username, "password": password}, &tokens); err != nil || code != http.StatusOK {
+		return fmt.Errorf("login: HTTP %d (%v)", code, err)
+	}
+	if tokens.AccessToken == "" {
+		return errors.New("login response has no access token")
+	}
+
+	// Запрос без Authorization аутентифицируется cookie сессии
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, e.baseURL+"/api/v1/notes/v1", nil)
+	if err != nil {
+		return err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("request with session cookie: HTTP %d", resp.StatusCode)
+	}
+
+	// Refresh токен берется из cookie
+	previous := tokens.AccessToken
+	if code, err := post("/api/v1/auth/v1/token:refresh", struct{}{}, &tokens); err != nil || code != http.StatusOK {
+		return fmt.Errorf("refresh: HTTP %d (%v)", code, err)
+	}
+	if code, err := e.request(ctx, http.MethodGet, "/api/v1/notes/v1", tokens.AccessToken, nil, nil); err != nil || code != http.StatusOK {
+		return fmt.Errorf("request with refreshed access token: HTTP %d (%v)", code, err)
+	}
+
+	// После Logout токены сессии отозваны, в том числе выданный до обновления
+	if code, err := post("/api/v1/auth/v1/logout", struct{}{}, nil); err != nil || code != http.StatusOK {
+		return fmt.Errorf("logout: HTTP %d (%v)", code, err)
+	}
+	for _, token := range []string{previous, tokens.AccessToken} {
+		code, err := e.request(ctx, http.MethodGet, "/api/v1/notes/v1", token, nil, nil)
+		if err != nil {
+			return err
+		}
+		if code != http.StatusUnauthorized {
+			return fmt.Errorf("access token after logout: expected HTTP %d, got %d", http.StatusUnauthorized, code)
+		}
+	}
+	return nil
+}
+
+func grpcUpdateNote(ctx context.Context, e *env) error {
+	ctx = withToken(ctx)
+	const content = "Updated through gRPC"
+	if _, err := e.client.UpdateNote(ctx, &notesv1.UpdateNoteRequest{Id: e.restNoteID, Content: content}); err != nil {
+		return err
+	}
+
+	resp, err := e.client.GetNote(ctx, &notesv1.GetNoteRequest{Id: e.restNoteID})
+	if err != nil {
+		return err
+	}
+	if resp.GetNote().GetContent() != content {
+		return fmt.Errorf("expected content %q, got %q", content, resp.GetNote().GetContent())
+	}
+	return nil
+}
+
+func grpcListNotes(ctx context.Context, e *env) error {
+	resp, err := e.client.ListNotes(withToken(ctx), &notesv1.ListNotesRequest{})
+	if err != nil {
+		return err
+	}
+	for _, note := range resp.GetNotes() {
+		if note.GetId() == e.restNoteID {
+			return nil
+		}
+	}
+	return fmt.Errorf("note %s is missing from %d listed notes", e.restNoteID, len(resp.GetNotes()))
+}
+
+func streamSubscribeToEvents(ctx context.Context, e *env) error {
+	ctx, cancel := context.WithTimeout(withToken(ctx), eventTimeout)
+	defer cancel()
+
+	stream, err := e.client.SubscribeToEvents(ctx, &notesv1.SubscribeToEventsRequest{})
+	if err != nil {
+		return err
+	}
+	// Приветственное сообщение подтверждает, что подписка зарегистрирована
+	welcome, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+	if welcome.GetHealthCheck() == nil {
+		return fmt.Errorf("expected welcome health check, got %T", welcome.GetEvent())
+	}
+
+	created, err := e.client.CreateNote(ctx, &notesv1.CreateNoteRequest{Title: "E2E gRPC note", Content: "Created while subscribed to events"})
+	if err != nil {
+		return err
+	}
+	e.grpcNoteID = created.GetNote().GetId()
+
+	for {
+		resp, err := stream.Recv()
+		if err != nil {
+			return fmt.Errorf("no NoteCreated event for note %s: %w", e.grpcNoteID, err)
+		}
+		event := resp.GetNoteCreated()
+		if event.GetNoteId() == e.grpcNoteID || event.GetNote().GetId() == e.grpcNoteID {
+			return nil
+		}
+	}
+}
+
+func streamUploadMetrics(ctx context.Context, e *env) error {
+	stream, err := e.client.UploadMetrics(withToken(ctx))
+	if err != nil {
+		return err
+	}
+	for i, value := range []float64{1, 2, 3, 4} {
+		if err := stream.Send(&notesv1.MetricRequest{Name: fmt.Sprintf("e2e_%d", i%2), Value: value}); err != nil {
+			return err
+		}
+	}
+
+	summary, err := stream.CloseAndRecv()
+	if err != nil {
+		return err
+	}
+	if summary.GetCount() != 4 || summary.GetSum() != 10 || summary.GetAverage() != 2.5 {
+		return fmt.Errorf("expected count=4 sum=10 average=2.5, got %v", summary)
+	}
+
+	// Значения 1 и 3 относятся к e2e_0, 2 и 4 - к e2e_1
+	metrics := summary.GetMetrics()
+	if len(metrics) != 2 || metrics[0].GetName() != "e2e_0" || metrics[0].GetSum() != 4 || metrics[0].GetMax() != 3 ||
+		metrics[1].GetName() != "e2e_1" || metrics[1].GetMin() != 2 || metrics[1].GetP95() != 4 {
+		return fmt.Errorf("expected per-metric summaries for e2e_0 and e2e_1, got %v", metrics)
+	}
+	return nil
+}
+
+func grpcQueryMetrics(ctx context.Context, e *env) error {
+	// Значения e2e_0 (1 и 3) сохранены шагом UploadMetrics
+	resp, err := e.client.QueryMetrics(withToken(ctx), &notesv1.QueryMetricsRequest{Name: "e2e_0"})
+	if err != nil {
+		return err
+	}
+	if len(resp.GetPoints()) != 2 || resp.GetPoints()[0].GetValue() != 1 || resp.GetPoints()[1].GetValue() != 3 {
+		return fmt.Errorf("expected uploaded values 1 and 3, got %v", resp.GetPoints())
+	}
+
+	resp, err = e.client.QueryMetrics(withToken(ctx), &notesv1.QueryMetricsRequest{
+		Name:        "e2e_0",
+		Aggregation: notesv1.MetricAggregation_METRIC_AGGREGATION_SUM,
+	})
+	if err != nil {
+		return err
+	}
+	if resp.GetValue() != 4 || resp.GetCount() != 2 || len(resp.GetPoints()) != 0 {
+		return fmt.Errorf("expected sum=4 count=2 without points, got %v", resp)
+	}
+	return nil
+}
+
+func streamMetrics(ctx context.Context, e *env) error {
+	ctx, cancel := context.WithTimeout(withToken(ctx), eventTimeout)
+	defer cancel()
+
+	stream, err := e.client.StreamMetrics(ctx)
+	if err != nil {
+		return err
+	}
+	metric := func(value float64) *notesv1.StreamMetricsRequest {
+		return &notesv1.StreamMetricsRequest{
+			Payload: &notesv1.StreamMetricsRequest_Metric{Metric: &notesv1.MetricRequest{Name: "e2e", Value: value}},
+		}
+	}
+
+	if err := stream.Send(&notesv1.StreamMetricsRequest{
+		Payload: &notesv1.StreamMetricsRequest_Options{Options: &notesv1.StreamMetricsOptions{WindowSeconds: 1}},
+	}); err != nil {
+		return err
+	}
+	if err := errors.Join(stream.Send(metric(1)), stream.Send(metric(2))); err != nil {
+		return err
+	}
+
+	// Первое окно закрывается сервером, пока стрим открыт
+	window, err := stream.Recv()
+	if err != nil {
+		return fmt.Errorf("no window summary: %w", err)
+	}
+	if window.GetFinal() || window.GetSummary().GetCount() != 2 {
+		return fmt.Errorf("expected window summary of 2 metrics, got %v", window)
+	}
+
+	if err := errors.Join(stream.Send(metric(3)), stream.CloseSend()); err != nil {
+		return err
+	}
+	// Незавершенное окно отправляется при закрытии отправки, перед итоговой статистикой
+	var windowed int64 = 2
+	for {
+		resp, err := stream.Recv()
+		if err != nil {
+			return fmt.Errorf("no final summary: %w", err)
+		}
+		if !resp.GetFinal() {
+			windowed += resp.GetSummary().GetCount()
+			continue
+		}
+		if resp.GetSummary().GetCount() != 3 || resp.GetSummary().GetSum() != 6 || windowed != 3 {
+			return fmt.Errorf("expected final summary of 3 metrics covered by windows, got %v (windowed %d)", resp, windowed)
+		}
+		return nil
+	}
+}
+
+func streamChat(ctx context.Context, e *env) error {
+	ctx, cancel := context.WithTimeout(withToken(ctx), eventTimeout)
+	defer cancel()
+
+	// Два стрима в одной комнате: сообщения одного получают оба
+	const room = "e2e-room"
+	sender, err := joinChatRoom(ctx, e, room)
+	if err != nil {
+		return err
+	}
+	listener, err := joinChatRoom(ctx, e, room)
+	if err != nil {
+		return err
+	}
+
+	// Пустой текст - бизнесовая ошибка, соединение после нее не разрывается
+	messages := map[string]string{"e2e-1": "Hello", "e2e-2": " ", "e2e-3": "Bye"}
+	for _, id := range []string{"e2e-1", "e2e-2", "e2e-3"} {
+		if err := sender.Send(&notesv1.ChatMessage{
+			CorrelationId: id,
+			RoomId:        room,
+			Content:       &notesv1.ChatMessage_TextMessage{TextMessage: &notesv1.ChatTextMessage{Text: messages[id]}},
+		}); err != nil {
+			return err
+		}
+	}
+
+	// Отправитель получает ошибку валидации и свои сообщения с исходными correlation_id
+	pending := maps.Clone(messages)
+	for len(pending) > 0 {
+		resp, err := recvChat(sender)
+		if err != nil {
+			return fmt.Errorf("no replies for %d messages: %w", len(pending), err)
+		}
+		text, ok := pending[resp.GetCorrelationId()]
+		if !ok {
+			continue
+		}
+		delete(pending, resp.GetCorrelationId())
+
+		if strings.TrimSpace(text) == "" {
+			if resp.GetError().GetCode() != notesv1.ChatErrorCode_CHAT_ERROR_CODE_VALIDATION_ERROR {
+				return fmt.Errorf("expected validation error for %s, got %v", resp.GetCorrelationId(), resp)
+			}
+		} else if resp.GetTextMessage().GetText() != text || resp.GetRoomId() != room {
+			return fmt.Errorf("expected own message %q in %s for %s, got %v", text, room, resp.GetCorrelationId(), resp)
+		}
+	}
+
+	// Второй участник получает только сообщения, прошедшие валидацию, с автором
+	for _, text := range []string{"Hello", "Bye"} {
+		resp, err := recvChat(listener)
+		if err != nil {
+			return fmt.Errorf("no room message %q: %w", text, err)
+		}
+		if resp.GetTextMessage().GetText() != text || resp.GetTextMessage().GetSenderId() != username {
+			return fmt.Errorf("expected %q from %s in the room, got %v", text, username, resp)
+		}
+	}
+
+	if err := sender.CloseSend(); err != nil {
+		return err
+	}
+	return listener.CloseSend()
+}
+
+// joinChatRoom открывает стрим Chat и входит в комнату room, дожидаясь подтверждения
+func joinChatRoom(ctx context.Context, e *env, room string) (notesv1.NotesService_ChatClient, error) {
+	stream, err := e.client.Chat(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := stream.Send(&notesv1.ChatMessage{
+		CorrelationId: "e2e-join",
+		RoomId:        room,
+		Content:       &notesv1.ChatMessage_JoinRoom{JoinRoom: &notesv1.ChatJoinRoom{}},
+	}); err != nil {
+		return nil, err
+	}
+
+	resp, err := recvChat(stream)
+	if err != nil {
+		return nil, err
+	}
+	if resp.GetJoinRoom() == nil || resp.GetRoomId() != room {
+		return nil, fmt.Errorf("expected join confirmation for %s, got %v", room, resp)
+	}
+	return stream, nil
+}
+
+// recvChat возвращает следующее сообщение стрима Chat, пропуская независимые уведомления сервера
+func recvChat(stream notesv1.NotesService_ChatClient) (*notesv1.ChatMessage, error) {
+	for {
+		resp, err := stream.Recv()
+		if err != nil {
+			return nil, err
+		}
+		if !strings.HasPrefix(resp.GetCorrelationId(), "notification-") {
+			return resp, nil
+		}
+	}
+}
+
+func websocketStreamNotes(ctx context.Context, e *env) error {
+	ctx, cancel := context.WithTimeout(ctx, eventTimeout)
+	defer cancel()
+
+	// Токен передается в Sec-WebSocket-Protocol: браузеры не позволяют задать заголовок Authorization
+	dialer := websocket.Dialer{Subprotocols: []string{"Bearer", Token}}
+	url := "ws" + strings.TrimPrefix(e.baseURL, "http") + "/api/v1/notes/v1:stream"
+	conn, resp, err := dialer.DialContext(ctx, url, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetReadDeadline(deadline)
+	}
+
+	// Каждое сообщение - строка NDJSON Gateway: {"result": <Note>} или {"error": <Status>}
+	want := map[string]bool{e.restNoteID: true, e.grpcNoteID: true}
+	for len(want) > 0 {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			return fmt.Errorf("stream ended before notes %v were received: %w", keys(want), err)
+		}
+		var message struct {
+			Result *noteJSON       `json:"result"`
+			Error  json.RawMessage `json:"error"`
+		}
+		if err := json.Unmarshal(data, &message); err != nil {
+			return fmt.Errorf("invalid stream message %q: %w", data, err)
+		}
+		if message.Error != nil {
+			return fmt.Errorf("stream error: %s", message.Error)
+		}
+		if message.Result != nil {
+			delete(want, message.Result.ID)
+		}
+	}
+	return nil
+}
+
+func runSelfTest(ctx context.Context, e *env) error {
+	var out bytes.Buffer
+	if err := selftest.Run(ctx, selftest.Target{GRPCAddr: e.grpcAddr, HTTPURL: e.baseURL, Token: Token}, &out); err != nil {
+		return fmt.Errorf("%w\n%s", err, out.String())
+	}
+	return nil
+}
+
+func grpcDeleteNote(ctx context.Context, e *env) error {
+	ctx = withToken(ctx)
+	if _, err := e.client.DeleteNote(ctx, &notesv1.DeleteNoteRequest{Id: e.restNoteID}); err != nil {
+		return err
+	}
+
+	_, err := e.client.GetNote(ctx, &notesv1.GetNoteRequest{Id: e.restNoteID})
+	if status.Code(err) != codes.NotFound {
+		return fmt.Errorf("expected NotFound for deleted note, got %v", err)
+	}
+	return nil
+}
+
+// withToken добавляет токен сценария в метаданные gRPC запроса
+func withToken(ctx context.Context) context.Context {
+	return metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+Token)
+}
+
+// doJSON выполняет запрос к Gateway с токеном сценария и ожидает ответ 200
+func (e *env) doJSON(ctx context.Context, method, path string, body, out any) error {
+	code, err := e.request(ctx, method, path, Token, body, out)
+	if err != nil {
+		return err
+	}
+	if code != http.StatusOK {
+		return fmt.Errorf("%s %s: HTTP %d", method, path, code)
+	}
+	return nil
+}
+
+// request выполняет запрос к Gateway и декодирует JSON ответ 200 в out
+// Пустой token отправляет запрос без заголовка Authorization
+func (e *env) request(ctx context.Context, method, path, token string, body, out any) (int, error) {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return 0, err
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, e.baseURL+path, reader)
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := e.http.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusOK && out != nil {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			return resp.StatusCode, fmt.Errorf("%s %s: invalid response: %w", method, path, err)
+		}
+	}
+	return resp.StatusCode, nil
+}
+
+// keys возвращает ключи множества для сообщений об ошибках
+func keys(set map[string]bool) []string {
+	result := make([]string, 0, len(set))
+	for key := range set {
+		result = append(result, key)
+	}
+	return result
+}
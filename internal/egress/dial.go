@@ -0,0 +1,135 @@
+package egress
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// DialContext устанавливает TCP соединение с address (host:port) по правилам политики:
+// адрес должен быть разрешен, таймаут подключения берется из настроек адреса, а при заданном
+// HTTPS прокси соединение устанавливается через него туннелем CONNECT
+// Сигнатура совпадает с net.Dialer.DialContext
+func (p *Policy) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	return p.dial(ctx, network, address, address)
+}
+
+// dial применяет политику к адресу address, а напрямую подключается к resolved
+// (для gRPC это адрес, полученный резолвером из address); через прокси туннель открывается к address
+func (p *Policy) dial(ctx context.Context, network, address, resolved string) (net.Conn, error) {
+	if err := p.Allow(address); err != nil {
+		return nil, err
+	}
+
+	dialer := &net.Dialer{}
+	if dest := p.destination(address); dest != nil && dest.timeout > 0 {
+		dialer.Timeout = dest.timeout
+	}
+	proxy := p.proxyFor("https", address)
+	if proxy == nil {
+		return dialer.DialContext(ctx, network, resolved)
+	}
+	return dialProxy(ctx, dialer, proxy, address)
+}
+
+// dialProxy открывает туннель к address через HTTP прокси запросом CONNECT
+func dialProxy(ctx context.Context, dialer *net.Dialer, proxy *url.URL, address string) (net.Conn, error) {
+	conn, err := dialer.DialContext(ctx, "tcp", proxyAddress(proxy))
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to proxy %s: %w", proxy.Host, err)
+	}
+	if proxy.Scheme == "https" {
+		conn = tls.Client(conn, &tls.Config{ServerName: proxy.Hostname(), MinVersion: tls.VersionTLS12})
+	}
+
+	// Прерывание ctx закрывает туннель, пока прокси не ответил
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+	stop := context.AfterFunc(ctx, func() {
+		_ = conn.SetDeadline(time.Now())
+	})
+	tunnel, err := connect(conn, proxy, address)
+	if !stop() && err == nil {
+		err = ctx.Err()
+	}
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	_ = conn.SetDeadline(time.Time{})
+	return tunnel, nil
+}
+
+// connect отправляет CONNECT и читает ответ прокси
+func connect(conn net.Conn, proxy *url.URL, address string) (net.Conn, error) {
+	req := &http.Request{
+		Method: http.MethodConnect,
+		URL:    &url.URL{Opaque: address},
+		Host:   address,
+		Header: make(http.Header),
+	}
+	if user := proxy.User; user != nil {
+		password, _ := user.Password()
+		token := base64.StdEncoding.EncodeToString([]byte(user.Username() + ":" + password))
+		req.Header.Set("Proxy-Authorization", "Basic "+token)
+	}
+	if err := req.Write(conn); err != nil {
+		return nil, err
+	}
+
+	reader := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(reader, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read proxy response: %w", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("proxy %s refused to connect to %s: %s", proxy.Host, address, resp.Status)
+	}
+	// Сервер мог начать отвечать (например, INFO у NATS) в том же пакете, что и прокси
+	if reader.Buffered() > 0 {
+		return &bufferedConn{Conn: conn, reader: reader}, nil
+	}
+	return conn, nil
+}
+
+// bufferedConn соединение, часть входящих данных которого уже прочитана в reader
+type bufferedConn struct {
+	net.Conn
+	reader *bufio.Reader
+}
+
+func (c *bufferedConn) Read(b []byte) (int, error) {
+	return c.reader.Read(b)
+}
+
+// GRPCDialOptions возвращает опции подключения к gRPC серверу address (host:port) по правилам политики
+// По TLS сервер подключается, если в настройках адреса включен TLS (TLSConfig.Enabled)
+// Собственный прокси gRPC (по переменным окружения) с этими опциями не используется
+func (p *Policy) GRPCDialOptions(address string) []grpc.DialOption {
+	creds := insecure.NewCredentials()
+	if dest := p.destination(address); dest != nil && dest.tlsEnabled {
+		config := &tls.Config{MinVersion: tls.VersionTLS12}
+		if dest.tls != nil {
+			config = dest.tls.Clone()
+		}
+		creds = credentials.NewTLS(config)
+	}
+	return []grpc.DialOption{
+		grpc.WithTransportCredentials(creds),
+		grpc.WithContextDialer(func(ctx context.Context, resolved string) (net.Conn, error) {
+			return p.dial(ctx, "tcp", address, resolved)
+		}),
+	}
+}
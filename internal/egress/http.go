@@ -0,0 +1,209 @@
+package egress
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"syscall"
+	"time"
+)
+
+// clientOptions параметры HTTP клиента
+type clientOptions struct {
+	timeout         time.Duration
+	privateDisabled bool
+	noRedirects     bool
+}
+
+// ClientOption настраивает HTTP клиент политики
+type ClientOption func(*clientOptions)
+
+// WithTimeout задает время HTTP запроса вместе с чтением ответа (0 - без ограничения)
+// Таймаут адреса из Destination заменяет его
+func WithTimeout(timeout time.Duration) ClientOption {
+	return func(o *clientOptions) {
+		o.timeout = timeout
+	}
+}
+
+// WithoutPrivateNetworks запрещает подключения к адресам внутренних сетей (loopback,
+// частные и link-local адреса). При прямом подключении адрес проверяется после разрешения
+// имени, поэтому DNS запись не обходит проверку; через прокси имя разрешается перед запросом
+func WithoutPrivateNetworks() ClientOption {
+	return func(o *clientOptions) {
+		o.privateDisabled = true
+	}
+}
+
+// WithoutRedirects отключает переход по перенаправлениям: возвращается сам ответ 3xx
+func WithoutRedirects() ClientOption {
+	return func(o *clientOptions) {
+		o.noRedirects = true
+	}
+}
+
+// HTTPClient создает HTTP клиент, запросы которого проходят проверки политики:
+// адрес запроса (и каждого перенаправления) должен быть разрешен, запрос идет через прокси
+// для его схемы, таймаут и TLS берутся из настроек адреса
+func (p *Policy) HTTPClient(opts ...ClientOption) *http.Client {
+	var o clientOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	t := &transport{policy: p, options: o, base: p.newTransport(nil, o)}
+	if p != nil {
+		t.destinations = make([]*http.Transport, len(p.destinations))
+		for i, dest := range p.destinations {
+			if dest.tls != nil {
+				t.destinations[i] = p.newTransport(dest.tls, o)
+			}
+		}
+	}
+
+	client := &http.Client{Transport: t}
+	if o.noRedirects {
+		client.CheckRedirect = func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		}
+	}
+	return client
+}
+
+// newTransport создает транспорт с прокси политики и настройками TLS tlsConfig
+func (p *Policy) newTransport(tlsConfig *tls.Config, o clientOptions) *http.Transport {
+	dialer := &net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second}
+	privateDialer := dialer
+	if o.privateDisabled {
+		privateDialer = &net.Dialer{Timeout: dialer.Timeout, KeepAlive: dialer.KeepAlive, Control: rejectPrivate}
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = func(req *http.Request) (*url.URL, error) {
+		return p.proxyFor(req.URL.Scheme, canonicalAddress(req.URL)), nil
+	}
+	transport.DialContext = func(ctx context.Context, network, address string) (net.Conn, error) {
+		// Прокси может находиться во внутренней сети: проверяется адрес назначения (см. RoundTrip)
+		if p.isProxy(address) {
+			return dialer.DialContext(ctx, network, address)
+		}
+		return privateDialer.DialContext(ctx, network, address)
+	}
+	if tlsConfig != nil {
+		transport.TLSClientConfig = tlsConfig
+	}
+	return transport
+}
+
+// transport применяет политику к запросам и передает их транспорту адреса
+type transport struct {
+	policy       *Policy
+	options      clientOptions
+	base         *http.Transport
+	destinations []*http.Transport // Транспорты адресов с настройками TLS, nil - base
+}
+
+// RoundTrip проверяет адрес запроса и выполняет его с таймаутом адреса или клиента
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	address := canonicalAddress(req.URL)
+	if err := t.policy.Allow(address); err != nil {
+		closeBody(req)
+		return nil, err
+	}
+	if t.options.privateDisabled && t.policy.proxyFor(req.URL.Scheme, address) != nil {
+		if err := checkResolved(req.Context(), req.URL.Hostname()); err != nil {
+			closeBody(req)
+			return nil, err
+		}
+	}
+
+	rt, timeout := t.base, t.options.timeout
+	if i := t.policy.destinationIndex(address); i >= 0 {
+		if t.destinations[i] != nil {
+			rt = t.destinations[i]
+		}
+		if dest := t.policy.destinations[i]; dest.timeout > 0 {
+			timeout = dest.timeout
+		}
+	}
+	if timeout <= 0 {
+		return rt.RoundTrip(req)
+	}
+
+	// Таймаут охватывает и чтение тела ответа, поэтому контекст отменяется при его закрытии
+	ctx, cancel := context.WithTimeout(req.Context(), timeout)
+	resp, err := rt.RoundTrip(req.WithContext(ctx))
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	resp.Body = &cancelBody{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
+}
+
+// cancelBody отменяет контекст запроса при закрытии тела ответа
+type cancelBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
+
+// closeBody закрывает тело отклоненного запроса, как того требует http.RoundTripper
+func closeBody(req *http.Request) {
+	if req.Body != nil {
+		req.Body.Close()
+	}
+}
+
+// canonicalAddress возвращает host:port адреса с портом по умолчанию для схемы
+func canonicalAddress(u *url.URL) string {
+	if u.Port() != "" {
+		return u.Host
+	}
+	if u.Scheme == "http" {
+		return net.JoinHostPort(u.Hostname(), "80")
+	}
+	return net.JoinHostPort(u.Hostname(), "443")
+}
+
+// rejectPrivate отклоняет подключение к адресу внутренней сети (net.Dialer.Control)
+func rejectPrivate(_, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	if ip := net.ParseIP(host); ip == nil || isPrivate(ip) {
+		return fmt.Errorf("%w: %s", ErrPrivateAddress, host)
+	}
+	return nil
+}
+
+// checkResolved разрешает имя host и отклоняет его, если хотя бы один адрес относится к внутренней сети
+// Используется для запросов через прокси, когда подключается сам прокси
+func checkResolved(ctx context.Context, host string) error {
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		return err
+	}
+	for _, addr := range addrs {
+		if isPrivate(addr.IP) {
+			return fmt.Errorf("%w: %s", ErrPrivateAddress, host)
+		}
+	}
+	return nil
+}
+
+// isPrivate сообщает, что ip - адрес внутренней сети
+func isPrivate(ip net.IP) bool {
+	return ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() || ip.IsUnspecified()
+}
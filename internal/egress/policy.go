@@ -0,0 +1,372 @@
+// Package egress задает общую политику исходящих подключений сервера: прокси (HTTP_PROXY,
+// HTTPS_PROXY, NO_PROXY), список разрешенных адресов, таймауты и настройки TLS по адресам.
+// Политику применяют фабрики клиентов: Policy.HTTPClient для HTTP интеграций (вебхуки, OIDC,
+// S3, отправка статистики), Policy.DialContext для TCP протоколов (NATS, Redis, PostgreSQL)
+// и Policy.GRPCDialOptions для gRPC upstream сервисов Gateway
+package egress
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrNotAllowed адрес не входит в список разрешенных
+	ErrNotAllowed = errors.New("destination is not allowed by egress policy")
+
+	// ErrPrivateAddress адрес относится к внутренней сети, а клиенту они запрещены (см. WithoutPrivateNetworks)
+	ErrPrivateAddress = errors.New("destination resolves to a private network")
+)
+
+// Config параметры политики исходящих подключений
+type Config struct {
+	HTTPProxy    string        // Прокси для http:// адресов (пусто - без прокси)
+	HTTPSProxy   string        // Прокси для https:// адресов и TCP подключений (туннель CONNECT)
+	NoProxy      []string      // Адреса без прокси в формате элементов NO_PROXY
+	AllowedHosts []string      // Разрешенные адреса (пусто - все): host, *.domain, IP или CIDR, с :port или без
+	Destinations []Destination // Настройки отдельных адресов, применяется первая подходящая
+}
+
+// Destination настройки подключений к адресам, подходящим под шаблон Host
+type Destination struct {
+	Host    string        // Шаблон адреса в формате AllowedHosts
+	Timeout time.Duration // Время HTTP запроса или установки TCP соединения (0 - таймаут клиента)
+	TLS     TLSConfig
+}
+
+// TLSConfig настройки TLS подключений к адресу
+// HTTP клиенты выбирают TLS по схеме адреса, PostgreSQL - по sslmode, gRPC upstream - по Enabled
+type TLSConfig struct {
+	Enabled            bool   // Подключаться к gRPC upstream по TLS
+	CAFile             string // PEM с сертификатами удостоверяющих центров вместо системных
+	CertFile           string // PEM с сертификатом клиента (mTLS)
+	KeyFile            string // PEM с ключом сертификата клиента
+	ServerName         string // Имя сервера для проверки сертификата вместо имени из адреса
+	InsecureSkipVerify bool   // Не проверять сертификат сервера
+	MinVersion         string // Минимальная версия TLS: 1.2 (по умолчанию) или 1.3
+}
+
+// ConfigFromEnvironment возвращает конфигурацию с прокси из переменных окружения
+// HTTP_PROXY, HTTPS_PROXY и NO_PROXY (или их вариантов в нижнем регистре) без других ограничений
+func ConfigFromEnvironment() Config {
+	return Config{
+		HTTPProxy:  getenv("HTTP_PROXY", "http_proxy"),
+		HTTPSProxy: getenv("HTTPS_PROXY", "https_proxy"),
+		NoProxy:    strings.Split(getenv("NO_PROXY", "no_proxy"), ","),
+	}
+}
+
+// getenv возвращает первую непустую переменную окружения из names
+func getenv(names ...string) string {
+	for _, name := range names {
+		if value := os.Getenv(name); value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// Policy политика исходящих подключений
+// Методы nil Policy подключаются напрямую, без прокси и ограничений
+type Policy struct {
+	httpProxy    *url.URL
+	httpsProxy   *url.URL
+	noProxy      []hostPattern
+	allowed      []hostPattern // Пусто - разрешены все адреса
+	destinations []destination
+}
+
+// destination настройки адреса с загруженными сертификатами
+type destination struct {
+	pattern    hostPattern
+	timeout    time.Duration
+	tls        *tls.Config // nil - настройки TLS по умолчанию
+	tlsEnabled bool
+}
+
+// New проверяет конфигурацию и создает политику
+// Файлы сертификатов читаются сразу, чтобы ошибка в них обнаруживалась при запуске
+func New(cfg Config) (*Policy, error) {
+	p := &Policy{}
+
+	var err error
+	if p.httpProxy, err = parseProxy(cfg.HTTPProxy); err != nil {
+		return nil, err
+	}
+	if p.httpsProxy, err = parseProxy(cfg.HTTPSProxy); err != nil {
+		return nil, err
+	}
+	for _, entry := range cfg.NoProxy {
+		if entry = strings.TrimSpace(entry); entry == "" {
+			continue
+		}
+		pattern, err := parseHostPattern(entry)
+		if err != nil {
+			return nil, fmt.Errorf("invalid no_proxy entry: %w", err)
+		}
+		// Как в NO_PROXY: example.com относится и к поддоменам
+		pattern.subdomains = true
+		p.noProxy = append(p.noProxy, pattern)
+	}
+	for _, entry := range cfg.AllowedHosts {
+		if entry = strings.TrimSpace(entry); entry == "" {
+			continue
+		}
+		pattern, err := parseHostPattern(entry)
+		if err != nil {
+			return nil, fmt.Errorf("invalid allowed host: %w", err)
+		}
+		p.allowed = append(p.allowed, pattern)
+	}
+	for _, dest := range cfg.Destinations {
+		pattern, err := parseHostPattern(dest.Host)
+		if err != nil {
+			return nil, fmt.Errorf("invalid egress destination: %w", err)
+		}
+		tlsConfig, err := dest.TLS.load()
+		if err != nil {
+			return nil, fmt.Errorf("egress destination %s: %w", dest.Host, err)
+		}
+		p.destinations = append(p.destinations, destination{
+			pattern:    pattern,
+			timeout:    dest.Timeout,
+			tls:        tlsConfig,
+			tlsEnabled: dest.TLS.Enabled,
+		})
+	}
+	return p, nil
+}
+
+// parseProxy разбирает адрес прокси; адрес без схемы считается http://
+func parseProxy(raw string) (*url.URL, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return nil, nil
+	}
+	if !strings.Contains(raw, "://") {
+		raw = "http://" + raw
+	}
+	proxy, err := url.Parse(raw)
+	if err != nil || proxy.Host == "" || (proxy.Scheme != "http" && proxy.Scheme != "https") {
+		return nil, fmt.Errorf("invalid proxy url %q (expected http:// or https://)", raw)
+	}
+	return proxy, nil
+}
+
+// load загружает сертификаты и возвращает настройки TLS, nil - настройки по умолчанию
+func (c TLSConfig) load() (*tls.Config, error) {
+	if c.CAFile == "" && c.CertFile == "" && c.KeyFile == "" && c.ServerName == "" &&
+		!c.InsecureSkipVerify && c.MinVersion == "" {
+		return nil, nil
+	}
+
+	config := &tls.Config{
+		ServerName:         c.ServerName,
+		InsecureSkipVerify: c.InsecureSkipVerify,
+		MinVersion:         tls.VersionTLS12,
+	}
+	switch c.MinVersion {
+	case "", "1.2":
+	case "1.3":
+		config.MinVersion = tls.VersionTLS13
+	default:
+		return nil, fmt.Errorf("unsupported tls min_version %q (expected 1.2 or 1.3)", c.MinVersion)
+	}
+	if c.CAFile != "" {
+		data, err := os.ReadFile(c.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read tls ca_file: %w", err)
+		}
+		config.RootCAs = x509.NewCertPool()
+		if !config.RootCAs.AppendCertsFromPEM(data) {
+			return nil, fmt.Errorf("no certificates found in tls ca_file %s", c.CAFile)
+		}
+	}
+	if c.CertFile != "" || c.KeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(c.CertFile, c.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load tls client certificate: %w", err)
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+	return config, nil
+}
+
+// Allow проверяет, что адрес (host или host:port) входит в список разрешенных
+// Имена сравниваются с шаблонами как есть, без разрешения в IP адреса
+func (p *Policy) Allow(address string) error {
+	if p == nil || len(p.allowed) == 0 {
+		return nil
+	}
+	host, port := splitHostPort(address)
+	for _, pattern := range p.allowed {
+		if pattern.match(host, port) {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s", ErrNotAllowed, address)
+}
+
+// TLSConfig возвращает копию настроек TLS для адреса, nil - настройки по умолчанию
+func (p *Policy) TLSConfig(address string) *tls.Config {
+	if dest := p.destination(address); dest != nil && dest.tls != nil {
+		return dest.tls.Clone()
+	}
+	return nil
+}
+
+// destination возвращает настройки первого подходящего под адрес шаблона, nil - таких нет
+func (p *Policy) destination(address string) *destination {
+	if i := p.destinationIndex(address); i >= 0 {
+		return &p.destinations[i]
+	}
+	return nil
+}
+
+// destinationIndex возвращает индекс первого подходящего под адрес шаблона, -1 - таких нет
+func (p *Policy) destinationIndex(address string) int {
+	if p == nil {
+		return -1
+	}
+	host, port := splitHostPort(address)
+	for i := range p.destinations {
+		if p.destinations[i].pattern.match(host, port) {
+			return i
+		}
+	}
+	return -1
+}
+
+// proxyFor возвращает прокси для адреса со схемой scheme, nil - подключаться напрямую
+// Адреса loopback, как и в net/http, не проксируются
+func (p *Policy) proxyFor(scheme, address string) *url.URL {
+	if p == nil {
+		return nil
+	}
+	proxy := p.httpsProxy
+	if scheme == "http" {
+		proxy = p.httpProxy
+	}
+	if proxy == nil {
+		return nil
+	}
+
+	host, port := splitHostPort(address)
+	if host == "localhost" {
+		return nil
+	}
+	if ip, err := netip.ParseAddr(host); err == nil && ip.IsLoopback() {
+		return nil
+	}
+	for _, pattern := range p.noProxy {
+		if pattern.match(host, port) {
+			return nil
+		}
+	}
+	return proxy
+}
+
+// isProxy сообщает, что address - адрес одного из прокси политики
+func (p *Policy) isProxy(address string) bool {
+	if p == nil {
+		return false
+	}
+	for _, proxy := range []*url.URL{p.httpProxy, p.httpsProxy} {
+		if proxy != nil && proxyAddress(proxy) == address {
+			return true
+		}
+	}
+	return false
+}
+
+// proxyAddress возвращает host:port прокси с портом по умолчанию для схемы
+func proxyAddress(proxy *url.URL) string {
+	if proxy.Port() != "" {
+		return proxy.Host
+	}
+	if proxy.Scheme == "https" {
+		return net.JoinHostPort(proxy.Hostname(), "443")
+	}
+	return net.JoinHostPort(proxy.Hostname(), "80")
+}
+
+// hostPattern шаблон адреса: имя, *.domain, IP или CIDR, с портом или без
+type hostPattern struct {
+	any        bool         // *: любой адрес
+	host       string       // Имя или IP в нижнем регистре (пусто для CIDR)
+	wildcard   bool         // *.domain или .domain: только поддомены host
+	subdomains bool         // Имя подходит вместе с поддоменами (NO_PROXY)
+	prefix     netip.Prefix // CIDR (IsValid - шаблон задан подсетью)
+	port       string       // Пусто - любой порт
+}
+
+// parseHostPattern разбирает шаблон адреса
+func parseHostPattern(raw string) (hostPattern, error) {
+	raw = strings.ToLower(strings.TrimSpace(raw))
+	var pattern hostPattern
+	if raw == "" {
+		return pattern, errors.New("empty host pattern")
+	}
+	if raw == "*" {
+		pattern.any = true
+		return pattern, nil
+	}
+	if prefix, err := netip.ParsePrefix(raw); err == nil {
+		pattern.prefix = prefix.Masked()
+		return pattern, nil
+	}
+
+	host := raw
+	if h, port, err := net.SplitHostPort(raw); err == nil {
+		if _, err := netip.ParseAddr(raw); err != nil {
+			host, pattern.port = h, port
+		}
+	}
+	host = strings.Trim(host, "[]")
+	switch {
+	case strings.HasPrefix(host, "*."):
+		pattern.wildcard, host = true, host[2:]
+	case strings.HasPrefix(host, "."):
+		pattern.wildcard, host = true, host[1:]
+	}
+	if host == "" || strings.ContainsAny(host, "*/ ") {
+		return pattern, fmt.Errorf("invalid host pattern %q", raw)
+	}
+	pattern.host = host
+	return pattern, nil
+}
+
+// match проверяет адрес host (без порта) и port (пусто - порт неизвестен)
+func (h hostPattern) match(host, port string) bool {
+	if h.any {
+		return true
+	}
+	if h.port != "" && h.port != port {
+		return false
+	}
+	host = strings.ToLower(host)
+	if h.prefix.IsValid() {
+		ip, err := netip.ParseAddr(host)
+		return err == nil && h.prefix.Contains(ip.Unmap())
+	}
+	if host == h.host {
+		return !h.wildcard
+	}
+	return (h.wildcard || h.subdomains) && strings.HasSuffix(host, "."+h.host)
+}
+
+// splitHostPort разделяет адрес на имя и порт; адрес без порта возвращается целиком
+func splitHostPort(address string) (host, port string) {
+	if h, p, err := net.SplitHostPort(address); err == nil {
+		return h, p
+	}
+	return strings.Trim(address, "[]"), ""
+}
@@ -0,0 +1,247 @@
+package nats
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"notes-service/internal/model"
+	"notes-service/internal/service/notes"
+)
+
+// fakeServer минимальный сервер NATS: рассылает PUB подписчикам темы, кроме отправителя с echo=false
+type fakeServer struct {
+	listener net.Listener
+
+	mu            sync.Mutex
+	clients       map[*fakeClient]bool
+	subscriptions int // Количество выполненных SUB
+}
+
+type fakeClient struct {
+	conn    net.Conn
+	echo    bool
+	subject string
+	sid     string
+	writeMu sync.Mutex
+}
+
+func newFakeServer(t *testing.T) *fakeServer {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	s := &fakeServer{listener: listener, clients: make(map[*fakeClient]bool)}
+	t.Cleanup(func() {
+		listener.Close()
+		s.dropClients()
+	})
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(&fakeClient{conn: conn, echo: true})
+		}
+	}()
+	return s
+}
+
+func (s *fakeServer) url() string {
+	return "nats://" + s.listener.Addr().String()
+}
+
+func (s *fakeServer) serve(c *fakeClient) {
+	defer func() {
+		s.mu.Lock()
+		delete(s.clients, c)
+		s.mu.Unlock()
+		c.conn.Close()
+	}()
+
+	reader := bufio.NewReader(c.conn)
+	c.write(`INFO {"server_id":"fake","proto":1,"max_payload":1048576}` + "\r\n")
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		op, args, _ := strings.Cut(strings.TrimRight(line, "\r\n"), " ")
+		switch strings.ToUpper(op) {
+		case "CONNECT":
+			var options struct {
+				Echo bool `json:"echo"`
+			}
+			if err := json.Unmarshal([]byte(args), &options); err != nil {
+				return
+			}
+			c.echo = options.Echo
+		case "PING":
+			c.write("PONG\r\n")
+		case "SUB":
+			fields := strings.Fields(args)
+			s.mu.Lock()
+			c.subject, c.sid = fields[0], fields[1]
+			s.clients[c] = true
+			s.subscriptions++
+			s.mu.Unlock()
+		case "PUB":
+			fields := strings.Fields(args)
+			size, _ := strconv.Atoi(fields[1])
+			payload := make([]byte, size+2)
+			if _, err := io.ReadFull(reader, payload); err != nil {
+				return
+			}
+			s.route(c, fields[0], payload[:size])
+		}
+	}
+}
+
+func (s *fakeServer) route(from *fakeClient, subject string, payload []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for c := range s.clients {
+		if c.subject != subject || (c == from && !from.echo) {
+			continue
+		}
+		c.write(fmt.Sprintf("MSG %s %s %d\r\n%s\r\n", subject, c.sid, len(payload), payload))
+	}
+}
+
+func (s *fakeServer) subscribers() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.clients)
+}
+
+func (s *fakeServer) subscribed() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.subscriptions
+}
+
+// dropClients разрывает все соединения, как при перезапуске сервера NATS
+func (s *fakeServer) dropClients() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for c := range s.clients {
+		c.conn.Close()
+	}
+}
+
+func (c *fakeClient) write(data string) {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	_, _ = c.conn.Write([]byte(data))
+}
+
+func newTestBus(t *testing.T, server *fakeServer) *Bus {
+	t.Helper()
+	bus, err := NewBus(context.Background(), Config{URL: server.url(), Name: "test"}, notes.NewEventService())
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	t.Cleanup(func() { bus.Close() })
+	return bus
+}
+
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("Timed out waiting for %s", what)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func receive(t *testing.T, ch chan notes.Event) notes.Event {
+	t.Helper()
+	select {
+	case event := <-ch:
+		return event
+	case <-time.After(5 * time.Second):
+		t.Fatal("Timed out waiting for event")
+		return notes.Event{}
+	}
+}
+
+func TestBus_FansOutEventsToReplicas(t *testing.T) {
+	server := newFakeServer(t)
+	replicaA := newTestBus(t, server)
+	replicaB := newTestBus(t, server)
+	waitFor(t, "subscriptions", func() bool { return server.subscribers() == 2 })
+
+	chA := replicaA.Subscribe()
+	defer replicaA.Unsubscribe(chA)
+	chB := replicaB.Subscribe()
+	defer replicaB.Unsubscribe(chB)
+
+	note := model.Note{ID: "note-1", Title: "Hello", OwnerID: "alice", Tags: []string{"work"}}
+	replicaA.Publish(notes.Event{Type: notes.EventNoteCreated, Note: note})
+
+	local := receive(t, chA)
+	remote := receive(t, chB)
+	for name, event := range map[string]notes.Event{"local": local, "remote": remote} {
+		if event.Type != notes.EventNoteCreated || event.Note.ID != note.ID || event.Note.OwnerID != "alice" {
+			t.Errorf("Expected %s NoteCreated for note-1, got %+v", name, event)
+		}
+		if event.ID != 1 {
+			t.Errorf("Expected %s event ID 1 from the replica's own log, got %d", name, event.ID)
+		}
+	}
+
+	// Сервер не возвращает издателю его событие, поэтому на своей реплике оно доставлено один раз
+	select {
+	case event := <-chA:
+		t.Errorf("Expected no duplicate on publishing replica, got %+v", event)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestBus_ReconnectsAfterConnectionLoss(t *testing.T) {
+	server := newFakeServer(t)
+	replicaA := newTestBus(t, server)
+	replicaB := newTestBus(t, server)
+	waitFor(t, "subscriptions", func() bool { return server.subscribers() == 2 })
+
+	server.dropClients()
+	waitFor(t, "resubscription", func() bool {
+		return server.subscribed() == 4 && server.subscribers() == 2
+	})
+
+	ch := replicaB.Subscribe()
+	defer replicaB.Unsubscribe(ch)
+
+	failed := model.ExportOperation{ID: "export-1", OwnerID: "alice", State: model.ExportStateFailed, Err: errors.New("disk full")}
+	replicaA.Publish(notes.Event{Type: notes.EventExportCompleted, Export: failed})
+
+	event := receive(t, ch)
+	if event.Type != notes.EventExportCompleted || event.Export.ID != "export-1" {
+		t.Fatalf("Expected ExportCompleted for export-1, got %+v", event)
+	}
+	if event.Export.Err == nil || event.Export.Err.Error() != "disk full" {
+		t.Errorf("Expected export error to survive the round trip, got %v", event.Export.Err)
+	}
+}
+
+func TestNewBus_InvalidConfig(t *testing.T) {
+	for _, url := range []string{"", "http://localhost:4222", "nats://"} {
+		if _, err := NewBus(context.Background(), Config{URL: url}, notes.NewEventService()); err == nil {
+			t.Errorf("Expected error for url %q", url)
+		}
+	}
+}
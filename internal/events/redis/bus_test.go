@@ -0,0 +1,309 @@
+package redis
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"notes-service/internal/model"
+	"notes-service/internal/service/notes"
+)
+
+// fakeServer минимальный сервер Redis: AUTH, CLIENT SETNAME, PING, PSUBSCRIBE и PUBLISH
+type fakeServer struct {
+	listener net.Listener
+	password string
+
+	mu            sync.Mutex
+	clients       map[*fakeClient]bool
+	channels      []string // Каналы всех PUBLISH по порядку
+	subscriptions int      // Количество выполненных PSUBSCRIBE
+}
+
+type fakeClient struct {
+	conn    net.Conn
+	authed  bool
+	pattern string
+	writeMu sync.Mutex
+}
+
+func newFakeServer(t *testing.T, password string) *fakeServer {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	s := &fakeServer{listener: listener, password: password, clients: make(map[*fakeClient]bool)}
+	t.Cleanup(func() {
+		listener.Close()
+		s.dropClients()
+	})
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			c := &fakeClient{conn: conn, authed: password == ""}
+			s.mu.Lock()
+			s.clients[c] = true
+			s.mu.Unlock()
+			go s.serve(c)
+		}
+	}()
+	return s
+}
+
+func (s *fakeServer) url() string {
+	if s.password != "" {
+		return "redis://:" + s.password + "@" + s.listener.Addr().String()
+	}
+	return "redis://" + s.listener.Addr().String()
+}
+
+func (s *fakeServer) serve(c *fakeClient) {
+	defer func() {
+		s.mu.Lock()
+		delete(s.clients, c)
+		s.mu.Unlock()
+		c.conn.Close()
+	}()
+
+	reader := bufio.NewReader(c.conn)
+	for {
+		args, err := readCommand(reader)
+		if err != nil {
+			return
+		}
+		command := strings.ToUpper(args[0])
+		if !c.authed && command != "AUTH" {
+			c.write("-NOAUTH Authentication required.\r\n")
+			continue
+		}
+		switch command {
+		case "AUTH":
+			if args[len(args)-1] != s.password {
+				c.write("-WRONGPASS invalid username-password pair\r\n")
+				continue
+			}
+			c.authed = true
+			c.write("+OK\r\n")
+		case "CLIENT":
+			c.write("+OK\r\n")
+		case "PING":
+			if c.pattern != "" {
+				c.write("*2\r\n$4\r\npong\r\n$0\r\n\r\n")
+			} else {
+				c.write("+PONG\r\n")
+			}
+		case "PSUBSCRIBE":
+			s.mu.Lock()
+			c.pattern = args[1]
+			s.subscriptions++
+			s.mu.Unlock()
+			c.write(fmt.Sprintf("*3\r\n$10\r\npsubscribe\r\n%s:1\r\n", bulk(args[1])))
+		case "PUBLISH":
+			c.write(fmt.Sprintf(":%d\r\n", s.route(args[1], args[2])))
+		default:
+			c.write("-ERR unknown command\r\n")
+		}
+	}
+}
+
+// route рассылает сообщение подписчикам, шаблон которых совпадает с каналом (в том числе издателю)
+func (s *fakeServer) route(channel, payload string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.channels = append(s.channels, channel)
+
+	delivered := 0
+	for c := range s.clients {
+		if c.pattern == "" {
+			continue
+		}
+		if ok, _ := path.Match(strings.ReplaceAll(c.pattern, `\`, ``), channel); !ok {
+			continue
+		}
+		c.write("*4\r\n$8\r\npmessage\r\n" + bulk(c.pattern) + bulk(channel) + bulk(payload))
+		delivered++
+	}
+	return delivered
+}
+
+func (s *fakeServer) subscribed() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.subscriptions
+}
+
+func (s *fakeServer) published() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.channels...)
+}
+
+// dropClients разрывает все соединения, как при перезапуске сервера Redis
+func (s *fakeServer) dropClients() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for c := range s.clients {
+		c.conn.Close()
+	}
+}
+
+func (c *fakeClient) write(data string) {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	_, _ = c.conn.Write([]byte(data))
+}
+
+func bulk(value string) string {
+	return "$" + strconv.Itoa(len(value)) + "\r\n" + value + "\r\n"
+}
+
+// readCommand читает команду клиента (массив bulk строк)
+func readCommand(reader *bufio.Reader) ([]string, error) {
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	count, err := strconv.Atoi(strings.TrimRight(line, "\r\n")[1:])
+	if err != nil {
+		return nil, err
+	}
+	args := make([]string, count)
+	for i := range args {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		size, err := strconv.Atoi(strings.TrimRight(line, "\r\n")[1:])
+		if err != nil {
+			return nil, err
+		}
+		data := make([]byte, size+2)
+		if _, err := io.ReadFull(reader, data); err != nil {
+			return nil, err
+		}
+		args[i] = string(data[:size])
+	}
+	return args, nil
+}
+
+func newTestBus(t *testing.T, server *fakeServer) *Bus {
+	t.Helper()
+	bus, err := NewBus(context.Background(), Config{URL: server.url(), Name: "test"}, notes.NewEventService())
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	t.Cleanup(func() { bus.Close() })
+	return bus
+}
+
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("Timed out waiting for %s", what)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func receive(t *testing.T, ch chan notes.Event) notes.Event {
+	t.Helper()
+	select {
+	case event := <-ch:
+		return event
+	case <-time.After(5 * time.Second):
+		t.Fatal("Timed out waiting for event")
+		return notes.Event{}
+	}
+}
+
+func TestBus_FansOutEventsToReplicas(t *testing.T) {
+	server := newFakeServer(t, "secret")
+	replicaA := newTestBus(t, server)
+	replicaB := newTestBus(t, server)
+
+	chA := replicaA.Subscribe()
+	defer replicaA.Unsubscribe(chA)
+	chB := replicaB.Subscribe()
+	defer replicaB.Unsubscribe(chB)
+
+	note := model.Note{ID: "note-1", Title: "Hello", OwnerID: "alice", Tags: []string{"work"}}
+	replicaA.Publish(notes.Event{Type: notes.EventNoteCreated, Note: note})
+	replicaA.Publish(notes.Event{Type: notes.EventNoteDeleted, Note: model.Note{ID: "note-1", OwnerID: "alice"}})
+
+	for _, ch := range []chan notes.Event{chA, chB} {
+		created := receive(t, ch)
+		if created.Type != notes.EventNoteCreated || created.Note.Title != "Hello" || created.ID != 1 {
+			t.Errorf("Expected NoteCreated with ID 1 from the replica's own log, got %+v", created)
+		}
+		if deleted := receive(t, ch); deleted.Type != notes.EventNoteDeleted || deleted.ID != 2 {
+			t.Errorf("Expected NoteDeleted with ID 2, got %+v", deleted)
+		}
+	}
+
+	// Redis возвращает издателю его сообщения, но реплика их пропускает
+	select {
+	case event := <-chA:
+		t.Errorf("Expected no duplicate on publishing replica, got %+v", event)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	want := []string{"notes.events.note_created", "notes.events.note_deleted"}
+	if got := server.published(); strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("Expected events in per type channels %v, got %v", want, got)
+	}
+}
+
+func TestBus_ReconnectsAfterConnectionLoss(t *testing.T) {
+	server := newFakeServer(t, "")
+	replicaA := newTestBus(t, server)
+	replicaB := newTestBus(t, server)
+
+	server.dropClients()
+	waitFor(t, "resubscription", func() bool {
+		return server.subscribed() == 4
+	})
+
+	ch := replicaB.Subscribe()
+	defer replicaB.Unsubscribe(ch)
+
+	failed := model.ExportOperation{ID: "export-1", OwnerID: "alice", State: model.ExportStateFailed, Err: errors.New("disk full")}
+	replicaA.Publish(notes.Event{Type: notes.EventExportCompleted, Export: failed})
+
+	event := receive(t, ch)
+	if event.Type != notes.EventExportCompleted || event.Export.ID != "export-1" {
+		t.Fatalf("Expected ExportCompleted for export-1, got %+v", event)
+	}
+	if event.Export.Err == nil || event.Export.Err.Error() != "disk full" {
+		t.Errorf("Expected export error to survive the round trip, got %v", event.Export.Err)
+	}
+}
+
+func TestNewBus_InvalidConfig(t *testing.T) {
+	for _, url := range []string{"", "http://localhost:6379", "redis://", "redis://user@localhost:6379"} {
+		if _, err := NewBus(context.Background(), Config{URL: url}, notes.NewEventService()); err == nil {
+			t.Errorf("Expected error for url %q", url)
+		}
+	}
+
+	server := newFakeServer(t, "secret")
+	wrong := "redis://:wrong@" + server.listener.Addr().String()
+	if _, err := NewBus(context.Background(), Config{URL: wrong}, notes.NewEventService()); err == nil {
+		t.Error("Expected error for wrong password")
+	}
+}
@@ -0,0 +1,61 @@
+// Package events содержит общий для брокеров (NATS, Redis) формат пересылки событий
+// заметок между репликами сервера
+package events
+
+import (
+	"encoding/json"
+	"errors"
+
+	"notes-service/internal/model"
+	"notes-service/internal/service/notes"
+)
+
+// wireEvent событие в сообщении брокера
+// ID и время не пересылаются: их назначает журнал реплики, получившей событие
+type wireEvent struct {
+	Type        notes.EventType       `json:"type"`
+	Note        model.Note            `json:"note"`
+	Export      model.ExportOperation `json:"export"`
+	ExportError string                `json:"export_error,omitempty"` // ExportOperation.Err не сериализуется в JSON
+	Share       model.Share           `json:"share"`
+	Search      model.SavedSearch     `json:"search"`
+	Quota       model.QuotaUsage      `json:"quota"`
+}
+
+// Encode сериализует событие для пересылки другим репликам
+func Encode(event notes.Event) ([]byte, error) {
+	msg := wireEvent{
+		Type:   event.Type,
+		Note:   event.Note,
+		Export: event.Export,
+		Share:  event.Share,
+		Search: event.Search,
+		Quota:  event.Quota,
+	}
+	if msg.Export.Err != nil {
+		msg.ExportError = msg.Export.Err.Error()
+		msg.Export.Err = nil
+	}
+	return json.Marshal(msg)
+}
+
+// Decode восстанавливает событие другой реплики
+func Decode(data []byte) (notes.Event, error) {
+	var msg wireEvent
+	if err := json.Unmarshal(data, &msg); err != nil {
+		return notes.Event{}, err
+	}
+
+	event := notes.Event{
+		Type:   msg.Type,
+		Note:   msg.Note,
+		Export: msg.Export,
+		Share:  msg.Share,
+		Search: msg.Search,
+		Quota:  msg.Quota,
+	}
+	if msg.ExportError != "" {
+		event.Export.Err = errors.New(msg.ExportError)
+	}
+	return event, nil
+}
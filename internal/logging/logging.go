@@ -0,0 +1,43 @@
+// Package logging хранит уровень логов запросов (logger.level), который можно менять без перезапуска:
+// info - логи запросов gRPC и HTTP, debug - еще и каждое сообщение стримов, warn и error - без логов запросов
+package logging
+
+import (
+	"fmt"
+	"log/slog"
+)
+
+// level текущий уровень; нулевое значение - slog.LevelInfo
+var level slog.LevelVar
+
+// SetLevel задает уровень по имени: debug, info, warn или error (пусто - info)
+func SetLevel(name string) error {
+	parsed, err := ParseLevel(name)
+	if err != nil {
+		return err
+	}
+	level.Set(parsed)
+	return nil
+}
+
+// ParseLevel разбирает имя уровня без учета регистра (пусто - info)
+func ParseLevel(name string) (slog.Level, error) {
+	if name == "" {
+		return slog.LevelInfo, nil
+	}
+	var parsed slog.Level
+	if err := parsed.UnmarshalText([]byte(name)); err != nil {
+		return 0, fmt.Errorf("unknown log level %q (expected debug, info, warn or error)", name)
+	}
+	return parsed, nil
+}
+
+// Level возвращает текущий уровень
+func Level() slog.Level {
+	return level.Level()
+}
+
+// Enabled сообщает, пишутся ли в лог сообщения уровня l
+func Enabled(l slog.Level) bool {
+	return l >= level.Level()
+}
@@ -0,0 +1,57 @@
+package mirror
+
+import (
+	"fmt"
+	"strconv"
+
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// maxDiffFields количество различающихся полей, сохраняемых для одного расхождения
+const maxDiffFields = 10
+
+// diffOutcomes сравнивает результаты основного и второго сервиса
+// Ошибки с одинаковым кодом совпадают: текст ошибок может различаться между хранилищами
+func diffOutcomes(primary proto.Message, primaryErr error, secondary proto.Message, secondaryErr error) []string {
+	primaryCode, secondaryCode := status.Code(primaryErr), status.Code(secondaryErr)
+	if primaryCode != secondaryCode {
+		return []string{fmt.Sprintf("status: %s != %s", primaryCode, secondaryCode)}
+	}
+	if primaryErr != nil {
+		return nil
+	}
+	if primary == nil || secondary == nil {
+		if primary == nil && secondary == nil {
+			return nil
+		}
+		return []string{"response: missing"}
+	}
+	return diffMessages("", primary.ProtoReflect(), secondary.ProtoReflect(), nil)
+}
+
+// diffMessages добавляет к diffs пути полей, значения которых различаются
+// Вложенные сообщения и списки сообщений одинаковой длины сравниваются по полям
+func diffMessages(prefix string, a, b protoreflect.Message, diffs []string) []string {
+	fields := a.Descriptor().Fields()
+	for i := 0; i < fields.Len() && len(diffs) < maxDiffFields; i++ {
+		fd := fields.Get(i)
+		path := prefix + string(fd.Name())
+		av, bv := a.Get(fd), b.Get(fd)
+		switch {
+		case av.Equal(bv):
+		case fd.IsList() && fd.Message() != nil && av.List().Len() == bv.List().Len():
+			for j := 0; j < av.List().Len() && len(diffs) < maxDiffFields; j++ {
+				diffs = diffMessages(path+"["+strconv.Itoa(j)+"].", av.List().Get(j).Message(), bv.List().Get(j).Message(), diffs)
+			}
+		case fd.IsList():
+			diffs = append(diffs, fmt.Sprintf("%s: %d != %d items", path, av.List().Len(), bv.List().Len()))
+		case fd.Message() != nil && !fd.IsMap() && a.Has(fd) && b.Has(fd):
+			diffs = diffMessages(path+".", av.Message(), bv.Message(), diffs)
+		default:
+			diffs = append(diffs, path)
+		}
+	}
+	return diffs
+}
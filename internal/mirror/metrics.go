@@ -0,0 +1,35 @@
+package mirror
+
+import (
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Описания метрик дублирования запросов
+var (
+	samplePercentDesc = prometheus.NewDesc("notes_mirror_sample_percent",
+		"Percent of read requests mirrored to the secondary service.", nil, nil)
+	requestsDesc = prometheus.NewDesc("notes_mirror_requests_total",
+		"Mirrored read requests by method and comparison result.", []string{"method", "result"}, nil)
+)
+
+// Describe передает описания метрик дублирования (Mirror реализует prometheus.Collector)
+func (m *Mirror) Describe(ch chan<- *prometheus.Desc) {
+	ch <- samplePercentDesc
+	ch <- requestsDesc
+}
+
+// Collect передает долю дублируемых запросов и счетчики дублированных запросов по методам
+func (m *Mirror) Collect(ch chan<- prometheus.Metric) {
+	ch <- prometheus.MustNewConstMetric(samplePercentDesc, prometheus.GaugeValue, m.percent)
+
+	results := []string{ResultMatch, ResultDiverged, ResultFailed, ResultDropped}
+	for _, method := range m.Methods() {
+		counts := m.Results(method)
+		name := method[strings.LastIndex(method, "/")+1:]
+		for _, result := range results {
+			ch <- prometheus.MustNewConstMetric(requestsDesc, prometheus.CounterValue, float64(counts[result]), name, result)
+		}
+	}
+}
@@ -0,0 +1,286 @@
+// Package mirror дублирует часть запросов чтения во вторую реализацию NotesService (например, на новом
+// хранилище) и сравнивает ответы, чтобы до переключения хранилища увидеть, где они расходятся
+package mirror
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math/rand/v2"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	notesv1 "notes-service/pkg/proto/notes/v1"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// DefaultTimeout время выполнения дублированного запроса по умолчанию
+	DefaultTimeout = 5 * time.Second
+
+	// DefaultMaxInFlight количество одновременно выполняемых дублированных запросов по умолчанию
+	DefaultMaxInFlight = 16
+
+	// maxSamples количество хранимых последних расхождений
+	maxSamples = 20
+)
+
+// Результаты дублированного запроса
+const (
+	ResultMatch    = "match"    // Ответы совпали
+	ResultDiverged = "diverged" // Ответы или статусы различаются
+	ResultFailed   = "failed"   // Второй сервис не ответил за отведенное время или запаниковал
+	ResultDropped  = "dropped"  // Запрос не дублирован: достигнут лимит одновременных запросов
+)
+
+// ReadMethods методы, которые можно дублировать: они только читают хранилище заметок
+// Запись не дублируется, чтобы не изменить данные второго хранилища повторно
+var ReadMethods = []string{
+	notesv1.NotesService_GetNote_FullMethodName,
+	notesv1.NotesService_ListNotes_FullMethodName,
+	notesv1.NotesService_BatchGetNotes_FullMethodName,
+	notesv1.NotesService_ListNotesByTag_FullMethodName,
+	notesv1.NotesService_ListTags_FullMethodName,
+	notesv1.NotesService_ListSharedNotes_FullMethodName,
+}
+
+// Call выполненный запрос, который дублируется во второй сервис
+type Call struct {
+	Method    string        // Полное имя метода, например /notes.v1.NotesService/GetNote
+	RequestID string        // Идентификатор запроса для логов
+	Request   proto.Message // Запрос
+	Response  proto.Message // Ответ основного сервиса (nil при ошибке)
+	Err       error         // Ошибка основного сервиса
+}
+
+// Divergence расхождение ответов основного и второго сервиса
+// Содержит только пути полей и коды статусов: значения полей могут содержать данные пользователей
+type Divergence struct {
+	Time      time.Time
+	Method    string
+	RequestID string
+	Fields    []string // Различающиеся поля, например "notes[2].title" или "status: OK != NotFound"
+}
+
+// Mirror дублирует запросы чтения во второй сервис и считает совпадения и расхождения ответов
+type Mirror struct {
+	secondary notesv1.NotesServiceServer
+	handlers  map[string]grpc.MethodHandler
+	percent   float64
+	timeout   time.Duration
+	inFlight  chan struct{}
+	sample    func() float64 // Случайное число в [0, 1)
+	now       func() time.Time
+	wg        sync.WaitGroup
+
+	mu      sync.Mutex
+	results map[string]map[string]int64 // Счетчики результатов по полному имени метода
+	samples []Divergence                // Последние расхождения, не более maxSamples
+}
+
+// Option настраивает дублирование запросов
+type Option func(*Mirror)
+
+// WithTimeout задает время выполнения дублированного запроса (по умолчанию DefaultTimeout)
+func WithTimeout(timeout time.Duration) Option {
+	return func(m *Mirror) {
+		if timeout > 0 {
+			m.timeout = timeout
+		}
+	}
+}
+
+// WithMaxInFlight задает количество одновременно выполняемых дублированных запросов
+// (по умолчанию DefaultMaxInFlight); запросы сверх лимита не дублируются
+func WithMaxInFlight(limit int) Option {
+	return func(m *Mirror) {
+		if limit > 0 {
+			m.inFlight = make(chan struct{}, limit)
+		}
+	}
+}
+
+// WithClock задает источник времени расхождений (по умолчанию time.Now)
+func WithClock(now func() time.Time) Option {
+	return func(m *Mirror) {
+		m.now = now
+	}
+}
+
+// New создает дублирование percent процентов (0-100) запросов методов methods (полные имена из ReadMethods)
+// в сервис secondary
+func New(secondary notesv1.NotesServiceServer, methods []string, percent float64, opts ...Option) (*Mirror, error) {
+	if percent < 0 || percent > 100 {
+		return nil, fmt.Errorf("mirror percent must be between 0 and 100, got %g", percent)
+	}
+	m := &Mirror{
+		secondary: secondary,
+		handlers:  make(map[string]grpc.MethodHandler, len(methods)),
+		percent:   percent,
+		timeout:   DefaultTimeout,
+		inFlight:  make(chan struct{}, DefaultMaxInFlight),
+		sample:    rand.Float64,
+		now:       time.Now,
+		results:   make(map[string]map[string]int64),
+	}
+	for _, method := range methods {
+		if !slices.Contains(ReadMethods, method) {
+			return nil, fmt.Errorf("method %s cannot be mirrored: only read methods are supported", method)
+		}
+		desc := notesv1.NotesService_ServiceDesc
+		index := slices.IndexFunc(desc.Methods, func(md grpc.MethodDesc) bool {
+			return "/"+desc.ServiceName+"/"+md.MethodName == method
+		})
+		m.handlers[method] = desc.Methods[index].Handler
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m, nil
+}
+
+// Methods возвращает дублируемые методы
+func (m *Mirror) Methods() []string {
+	methods := make([]string, 0, len(m.handlers))
+	for method := range m.handlers {
+		methods = append(methods, method)
+	}
+	slices.Sort(methods)
+	return methods
+}
+
+// Percent возвращает процент дублируемых запросов
+func (m *Mirror) Percent() float64 {
+	return m.percent
+}
+
+// Sampled сообщает, нужно ли дублировать очередной запрос метода
+func (m *Mirror) Sampled(method string) bool {
+	_, ok := m.handlers[method]
+	return ok && m.sample()*100 < m.percent
+}
+
+// Submit асинхронно выполняет запрос во втором сервисе и сравнивает ответ с ответом основного
+// Запрос и ответ копируются до возврата, поэтому вызывающий может продолжать их использовать.
+// Во втором сервисе запрос выполняется от имени того же пользователя и с теми же метаданными,
+// но без отмены вместе с исходным запросом; заголовки и трейлеры второго сервиса отбрасываются
+func (m *Mirror) Submit(ctx context.Context, call Call) {
+	handler, ok := m.handlers[call.Method]
+	if !ok {
+		return
+	}
+	select {
+	case m.inFlight <- struct{}{}:
+	default:
+		m.count(call.Method, ResultDropped)
+		return
+	}
+
+	call.Request = proto.Clone(call.Request)
+	if call.Response != nil {
+		call.Response = proto.Clone(call.Response)
+	}
+	ctx = grpc.NewContextWithServerTransportStream(context.WithoutCancel(ctx), discardStream{method: call.Method})
+
+	m.wg.Add(1)
+	go func() {
+		defer m.wg.Done()
+		defer func() { <-m.inFlight }()
+
+		ctx, cancel := context.WithTimeout(ctx, m.timeout)
+		defer cancel()
+		m.compare(ctx, handler, call)
+	}()
+}
+
+// Wait ожидает завершения дублированных запросов (при остановке сервера)
+func (m *Mirror) Wait() {
+	m.wg.Wait()
+}
+
+// Divergences возвращает последние расхождения, от старых к новым
+func (m *Mirror) Divergences() []Divergence {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return slices.Clone(m.samples)
+}
+
+// Results возвращает количество дублированных запросов метода по результатам (Result*)
+func (m *Mirror) Results(method string) map[string]int64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	results := make(map[string]int64, len(m.results[method]))
+	for result, count := range m.results[method] {
+		results[result] = count
+	}
+	return results
+}
+
+// compare выполняет запрос во втором сервисе и учитывает результат сравнения
+func (m *Mirror) compare(ctx context.Context, handler grpc.MethodHandler, call Call) {
+	resp, err := m.invoke(ctx, handler, call.Request)
+	if err != nil && ctx.Err() != nil {
+		m.count(call.Method, ResultFailed)
+		log.Printf("Mirror request %s (request %s) did not complete: %v", call.Method, call.RequestID, err)
+		return
+	}
+
+	fields := diffOutcomes(call.Response, call.Err, resp, err)
+	if len(fields) == 0 {
+		m.count(call.Method, ResultMatch)
+		return
+	}
+
+	m.count(call.Method, ResultDiverged)
+	m.mu.Lock()
+	m.samples = append(m.samples, Divergence{Time: m.now(), Method: call.Method, RequestID: call.RequestID, Fields: fields})
+	if len(m.samples) > maxSamples {
+		m.samples = slices.Delete(m.samples, 0, len(m.samples)-maxSamples)
+	}
+	m.mu.Unlock()
+	log.Printf("Mirror divergence in %s (request %s): %s", call.Method, call.RequestID, strings.Join(fields, ", "))
+}
+
+// invoke вызывает метод второго сервиса; паника второго сервиса возвращается как ошибка
+func (m *Mirror) invoke(ctx context.Context, handler grpc.MethodHandler, req proto.Message) (resp proto.Message, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("mirror handler panic: %v", r)
+		}
+	}()
+
+	out, err := handler(m.secondary, ctx, func(v any) error {
+		proto.Merge(v.(proto.Message), req)
+		return nil
+	}, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, _ = out.(proto.Message)
+	return resp, nil
+}
+
+// count увеличивает счетчик результата метода
+func (m *Mirror) count(method, result string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.results[method] == nil {
+		m.results[method] = make(map[string]int64)
+	}
+	m.results[method][result]++
+}
+
+// discardStream принимает заголовки и трейлеры второго сервиса, не отправляя их клиенту
+type discardStream struct {
+	method string
+}
+
+func (s discardStream) Method() string             { return s.method }
+func (discardStream) SetHeader(metadata.MD) error  { return nil }
+func (discardStream) SendHeader(metadata.MD) error { return nil }
+func (discardStream) SetTrailer(metadata.MD) error { return nil }
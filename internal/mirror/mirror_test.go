@@ -0,0 +1,122 @@
+package mirror
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	notesv1 "notes-service/pkg/proto/notes/v1"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// secondaryServer второй сервис, возвращающий заметки из notes по ID
+type secondaryServer struct {
+	notesv1.UnimplementedNotesServiceServer
+	notes map[string]*notesv1.Note
+	delay time.Duration
+}
+
+func (s *secondaryServer) GetNote(ctx context.Context, req *notesv1.GetNoteRequest) (*notesv1.GetNoteResponse, error) {
+	// Заголовки второго сервиса не должны доходить до клиента исходного запроса
+	if err := grpc.SetHeader(ctx, nil); err != nil {
+		return nil, err
+	}
+	select {
+	case <-time.After(s.delay):
+	case <-ctx.Done():
+		return nil, status.FromContextError(ctx.Err()).Err()
+	}
+	note, ok := s.notes[req.GetId()]
+	if !ok {
+		return nil, status.Error(codes.NotFound, "note not found")
+	}
+	return &notesv1.GetNoteResponse{Note: note}, nil
+}
+
+func TestMirror_Submit(t *testing.T) {
+	secondary := &secondaryServer{notes: map[string]*notesv1.Note{
+		"same":  {Id: "same", Title: "Title", Tags: []string{"a"}},
+		"other": {Id: "other", Title: "Changed", Tags: []string{"a", "b"}},
+	}}
+	m, err := New(secondary, []string{notesv1.NotesService_GetNote_FullMethodName}, 100, WithTimeout(50*time.Millisecond))
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	get := func(id string, primary *notesv1.Note, primaryErr error) {
+		call := Call{Method: notesv1.NotesService_GetNote_FullMethodName, RequestID: "req-" + id,
+			Request: &notesv1.GetNoteRequest{Id: id}, Err: primaryErr}
+		if primary != nil {
+			call.Response = &notesv1.GetNoteResponse{Note: primary}
+		}
+		m.Submit(context.Background(), call)
+		m.Wait()
+	}
+	get("same", &notesv1.Note{Id: "same", Title: "Title", Tags: []string{"a"}}, nil)
+	get("other", &notesv1.Note{Id: "other", Title: "Original", Tags: []string{"a"}}, nil)
+	get("missing", &notesv1.Note{Id: "missing"}, nil)
+	get("missing", nil, status.Error(codes.NotFound, "note not found"))
+
+	results := m.Results(notesv1.NotesService_GetNote_FullMethodName)
+	if results[ResultMatch] != 2 || results[ResultDiverged] != 2 {
+		t.Errorf("Expected 2 matches and 2 divergences, got %v", results)
+	}
+	divergences := m.Divergences()
+	if len(divergences) != 2 {
+		t.Fatalf("Expected 2 divergences, got %+v", divergences)
+	}
+	if got := strings.Join(divergences[0].Fields, ","); got != "note.title,note.tags: 1 != 2 items" || divergences[0].RequestID != "req-other" {
+		t.Errorf("Expected title and tags divergence of req-other, got %+v", divergences[0])
+	}
+	if got := strings.Join(divergences[1].Fields, ","); got != "status: OK != NotFound" {
+		t.Errorf("Expected status divergence, got %q", got)
+	}
+
+	// Второй сервис, не ответивший вовремя, не считается расхождением
+	secondary.delay = time.Second
+	get("same", &notesv1.Note{Id: "same", Title: "Title", Tags: []string{"a"}}, nil)
+	if got := m.Results(notesv1.NotesService_GetNote_FullMethodName)[ResultFailed]; got != 1 {
+		t.Errorf("Expected 1 failed request, got %d", got)
+	}
+
+	want := `# HELP notes_mirror_requests_total Mirrored read requests by method and comparison result.
+# TYPE notes_mirror_requests_total counter
+notes_mirror_requests_total{method="GetNote",result="diverged"} 2
+notes_mirror_requests_total{method="GetNote",result="dropped"} 0
+notes_mirror_requests_total{method="GetNote",result="failed"} 1
+notes_mirror_requests_total{method="GetNote",result="match"} 2
+`
+	if err := testutil.CollectAndCompare(m, strings.NewReader(want), "notes_mirror_requests_total"); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestMirror_Sampled(t *testing.T) {
+	if _, err := New(&secondaryServer{}, []string{notesv1.NotesService_DeleteNote_FullMethodName}, 100); err == nil {
+		t.Error("Expected error for a write method")
+	}
+	if _, err := New(&secondaryServer{}, nil, 101); err == nil {
+		t.Error("Expected error for percent above 100")
+	}
+
+	m, err := New(&secondaryServer{}, []string{notesv1.NotesService_GetNote_FullMethodName}, 25)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	m.sample = func() float64 { return 0.2 }
+	if !m.Sampled(notesv1.NotesService_GetNote_FullMethodName) {
+		t.Error("Expected GetNote to be sampled below the percent")
+	}
+	if m.Sampled(notesv1.NotesService_ListNotes_FullMethodName) {
+		t.Error("Expected methods outside the list not to be sampled")
+	}
+	m.sample = func() float64 { return 0.3 }
+	if m.Sampled(notesv1.NotesService_GetNote_FullMethodName) {
+		t.Error("Expected GetNote not to be sampled above the percent")
+	}
+}
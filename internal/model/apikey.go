@@ -0,0 +1,26 @@
+package model
+
+import "time"
+
+// APIKey ключ API для сервисных клиентов: альтернатива токенам пользователей
+// Секрет ключа выдается один раз при создании, в хранилище остается только его хэш
+type APIKey struct {
+	ID         string    // Открытая часть ключа, по которой он находится в хранилище
+	Name       string    // Описание ключа (например, имя интеграции)
+	UserID     string    // Пользователь, от имени которого выполняются запросы с ключом
+	Roles      []string  // Роли запросов с ключом
+	SecretHash []byte    // SHA-256 секрета ключа
+	CreatedBy  string    // Администратор, создавший ключ
+	CreatedAt  time.Time // Время создания
+	ExpiresAt  time.Time // Время истечения (нулевое - бессрочный ключ)
+	RevokedAt  time.Time // Время отзыва (нулевое - ключ не отозван)
+
+	// Лимит запросов с ключом (token bucket); нулевая скорость - лимит по умолчанию из конфигурации
+	RateLimitRPS   float64
+	RateLimitBurst int
+}
+
+// Active проверяет, что ключ не отозван и не истек к моменту now
+func (k APIKey) Active(now time.Time) bool {
+	return k.RevokedAt.IsZero() && (k.ExpiresAt.IsZero() || now.Before(k.ExpiresAt))
+}
@@ -0,0 +1,14 @@
+package model
+
+import "time"
+
+// Attachment представляет файл, прикрепленный к заметке
+type Attachment struct {
+	ID          string    // UUID вложения
+	NoteID      string    // UUID заметки, к которой прикреплен файл
+	FileName    string    // Исходное имя файла
+	ContentType string    // MIME тип содержимого
+	Size        int64     // Размер в байтах
+	SHA256      string    // Контрольная сумма содержимого (hex)
+	CreatedAt   time.Time // Дата загрузки
+}
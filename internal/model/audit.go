@@ -0,0 +1,41 @@
+package model
+
+import "time"
+
+// Операции, попадающие в журнал аудита (AuditRecord.Action)
+const (
+	AuditActionCreate           = "create"            // Создание заметки (CreateNote, BatchCreateNotes)
+	AuditActionGet              = "get"               // Чтение защищенной заметки (GetNote)
+	AuditActionUpdate           = "update"            // Изменение заметки (UpdateNote)
+	AuditActionDelete           = "delete"            // Удаление заметки (DeleteNote, BatchDeleteNotes)
+	AuditActionShare            = "share"             // Предоставление доступа к заметке (ShareNote)
+	AuditActionUnshare          = "unshare"           // Отзыв доступа к заметке (UnshareNote)
+	AuditActionRevisions        = "revisions"         // Чтение истории изменений защищенной заметки
+	AuditActionSetPassphrase    = "set_passphrase"    // Установка или смена парольной фразы
+	AuditActionRemovePassphrase = "remove_passphrase" // Снятие защиты
+	AuditActionPin              = "pin"               // Закрепление или открепление защищенной заметки
+	AuditActionArchive          = "archive"           // Архивирование или возврат из архива защищенной заметки
+)
+
+// Результаты проверки парольной фразы (AuditRecord.Outcome)
+// Для изменяющих RPC результат - код статуса gRPC (OK, NotFound, PermissionDenied и т.д.)
+const (
+	AuditOutcomeGranted   = "granted"    // Фраза верна, доступ разрешен
+	AuditOutcomeMissing   = "missing"    // Фраза не передана
+	AuditOutcomeInvalid   = "invalid"    // Неверная фраза
+	AuditOutcomeLockedOut = "locked_out" // Попытки заблокированы после серии неверных фраз
+)
+
+// AuditRecord запись журнала аудита: изменение заметки через RPC или доступ к заметке,
+// защищенной парольной фразой
+type AuditRecord struct {
+	ID           int64     // Порядковый номер записи (назначается хранилищем)
+	Time         time.Time // Время операции
+	Action       string    // Операция (AuditAction*)
+	Method       string    // Полное имя gRPC метода (пусто для проверок парольной фразы)
+	NoteID       string    // UUID заметки (пусто, если заметка не была создана)
+	UserID       string    // Вызывающий пользователь (пусто для внутренних вызовов)
+	TargetUserID string    // Пользователь, которому предоставлен или у которого отозван доступ
+	RequestID    string    // Идентификатор запроса (x-request-id)
+	Outcome      string    // Результат (AuditOutcome* или код статуса gRPC)
+}
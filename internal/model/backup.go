@@ -0,0 +1,50 @@
+package model
+
+import "time"
+
+// Backup резервная копия хранилища (заметки, ревизии и доступы)
+type Backup struct {
+	Name      string    // Имя архива в хранилище резервных копий
+	Location  string    // Адрес архива: путь к файлу или s3://bucket/key
+	Size      int64     // Размер архива в байтах
+	Notes     int64     // Количество заметок
+	Revisions int64     // Количество ревизий
+	Shares    int64     // Количество доступов
+	CreatedAt time.Time // Время начала копирования
+}
+
+// BackupStatus состояние резервного копирования
+type BackupStatus struct {
+	LastBackup    Backup        // Последняя успешная копия (пустая, если копий еще не было)
+	LastAttemptAt time.Time     // Время последнего запуска копирования
+	LastDuration  time.Duration // Длительность последнего копирования
+	LastError     error         // Ошибка последнего копирования (nil, если оно успешно)
+	NextBackupAt  time.Time     // Время следующего копирования по расписанию
+	Retained      int           // Количество копий в хранилище после применения политики хранения
+	Succeeded     int64         // Количество успешных копирований с запуска сервера
+	Failed        int64         // Количество неудачных копирований с запуска сервера
+}
+
+// RestoreConflictStrategy поведение при восстановлении заметки, которая уже есть в хранилище
+// Значения совпадают с номерами BackupConflictStrategy в proto
+type RestoreConflictStrategy int
+
+const (
+	RestoreConflictSkip      RestoreConflictStrategy = iota + 1 // Оставить существующую заметку
+	RestoreConflictOverwrite                                    // Заменить заметку копией вместе с ревизиями и доступами
+	RestoreConflictFail                                         // Отменить восстановление целиком
+)
+
+// RestoreReport результат восстановления из резервной копии
+// При пробном запуске (DryRun) счетчики описывают изменения, которые были бы внесены
+type RestoreReport struct {
+	Backup      string   // Имя восстановленного архива
+	DryRun      bool     // Хранилище не изменялось
+	Notes       int64    // Количество заметок в копии
+	Created     int64    // Восстановлено отсутствовавших заметок
+	Overwritten int64    // Заменено существующих заметок
+	Skipped     int64    // Пропущено существующих заметок
+	Revisions   int64    // Восстановлено ревизий
+	Shares      int64    // Восстановлено доступов
+	Conflicts   []string // ID заметок из копии, которые уже есть в хранилище (не больше 100)
+}
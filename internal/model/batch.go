@@ -0,0 +1,9 @@
+package model
+
+// BatchResult результат операции над одной заметкой в пакетном запросе
+// Err == nil означает успех; при ошибке Note может быть пустой
+type BatchResult struct {
+	ID   string // UUID заметки
+	Note Note   // Заметка (для create/get при успехе)
+	Err  error  // Ошибка операции над заметкой
+}
@@ -0,0 +1,23 @@
+package model
+
+import "slices"
+
+// Схемы сквозного (end-to-end) шифрования заметок
+// Сервер не расшифровывает заметки: схема сообщает клиентам формат content_encrypted
+const (
+	E2ESchemeXChaCha20Poly1305 = "xchacha20-poly1305"
+	E2ESchemeAES256GCM         = "aes-256-gcm"
+)
+
+// supportedE2ESchemes схемы, которые принимает сервер (в порядке предпочтения)
+var supportedE2ESchemes = []string{E2ESchemeXChaCha20Poly1305, E2ESchemeAES256GCM}
+
+// SupportedE2ESchemes возвращает поддерживаемые схемы сквозного шифрования
+func SupportedE2ESchemes() []string {
+	return slices.Clone(supportedE2ESchemes)
+}
+
+// IsSupportedE2EScheme проверяет, поддерживается ли схема сквозного шифрования
+func IsSupportedE2EScheme(scheme string) bool {
+	return slices.Contains(supportedE2ESchemes, scheme)
+}
@@ -0,0 +1,40 @@
+package model
+
+import "time"
+
+// ExportArchive формат файла выгрузки в хранилище
+type ExportArchive string
+
+const (
+	ExportArchiveNDJSON ExportArchive = "ndjson" // JSON Lines: одна заметка на строку
+	ExportArchiveZIP    ExportArchive = "zip"    // ZIP архив с файлом notes.jsonl
+)
+
+// ExportState состояние операции выгрузки
+// Значения совпадают с номерами ExportOperationState в proto
+type ExportState int
+
+const (
+	ExportStateRunning   ExportState = iota + 1 // Выгрузка выполняется
+	ExportStateSucceeded                        // Файл выгрузки сохранен
+	ExportStateFailed                           // Выгрузка завершилась ошибкой
+)
+
+// ExportOperation длительная операция выгрузки заметок пользователя в хранилище
+type ExportOperation struct {
+	ID            string        // ID операции
+	OwnerID       string        // Пользователь, запустивший выгрузку
+	Archive       ExportArchive // Формат файла выгрузки
+	State         ExportState   // Состояние
+	ExportedNotes int64         // Количество выгруженных заметок
+	TotalNotes    int64         // Количество заметок на момент запуска
+	Location      string        // Адрес файла выгрузки после успешного завершения
+	Err           error         // Ошибка выгрузки (для ExportStateFailed)
+	CreatedAt     time.Time     // Время запуска
+	CompletedAt   time.Time     // Время завершения
+}
+
+// Done сообщает, завершена ли операция
+func (op ExportOperation) Done() bool {
+	return op.State == ExportStateSucceeded || op.State == ExportStateFailed
+}
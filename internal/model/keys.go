@@ -0,0 +1,43 @@
+package model
+
+import "time"
+
+// DataKey ключ данных владельца для шифрования содержимого его заметок в хранилище
+// Ключ хранится только зашифрованным мастер-ключом (см. encrypted.DataKeys)
+type DataKey struct {
+	ID        string    // ID ключа, записывается в шифротекст и в Note.KeyID
+	OwnerID   string    // Владелец заметок, чье содержимое шифруется ключом
+	Wrapped   string    // Ключ, зашифрованный мастер-ключом
+	CreatedAt time.Time // Время создания; текущий ключ владельца - созданный последним
+}
+
+// KeyRotationState состояние операции смены ключей
+// Значения совпадают с номерами KeyRotationState в proto
+type KeyRotationState int
+
+const (
+	KeyRotationStateRunning   KeyRotationState = iota + 1 // Заметки перешифровываются
+	KeyRotationStateSucceeded                             // Все заметки зашифрованы новыми ключами
+	KeyRotationStateFailed                                // Смена ключей завершилась ошибкой
+)
+
+// KeyRotationOperation длительная операция смены ключей шифрования заметок
+type KeyRotationOperation struct {
+	ID               string           // ID операции
+	OwnerID          string           // Владелец, ключ которого сменяется (пусто - все владельцы)
+	StartedBy        string           // Администратор, запустивший операцию
+	State            KeyRotationState // Состояние
+	RotatedKeys      int64            // Количество созданных ключей данных
+	RewrappedKeys    int64            // Количество ключей данных, перешифрованных текущим мастер-ключом
+	TotalNotes       int64            // Количество заметок на момент запуска
+	ProcessedNotes   int64            // Количество проверенных заметок
+	ReencryptedNotes int64            // Количество перешифрованных заметок
+	Err              error            // Ошибка (для KeyRotationStateFailed)
+	CreatedAt        time.Time        // Время запуска
+	CompletedAt      time.Time        // Время завершения
+}
+
+// Done проверяет, завершена ли операция
+func (op KeyRotationOperation) Done() bool {
+	return op.State == KeyRotationStateSucceeded || op.State == KeyRotationStateFailed
+}
@@ -0,0 +1,16 @@
+package model
+
+import "time"
+
+// NoteLock блокировка заметки для монопольного редактирования (аренда с TTL)
+type NoteLock struct {
+	NoteID     string    // UUID заметки
+	HolderID   string    // Пользователь, удерживающий блокировку
+	AcquiredAt time.Time // Время захвата или последнего продления
+	ExpiresAt  time.Time // Время истечения аренды
+}
+
+// Expired сообщает, истекла ли аренда блокировки к моменту now
+func (l NoteLock) Expired(now time.Time) bool {
+	return !now.Before(l.ExpiresAt)
+}
@@ -0,0 +1,59 @@
+package model
+
+import "time"
+
+// MetricsSummary статистика загруженных метрик: общая и по каждому названию
+type MetricsSummary struct {
+	Count   int64           // Количество значений всех метрик
+	Sum     float64         // Сумма значений всех метрик
+	Average float64         // Среднее значение всех метрик (0 без значений)
+	Metrics []MetricSummary // Статистика каждой метрики, по названию
+}
+
+// MetricSummary статистика значений одной метрики (UploadMetrics, StreamMetrics)
+type MetricSummary struct {
+	Name    string  // Название метрики (пусто для значений без названия)
+	Count   int64   // Количество значений
+	Sum     float64 // Сумма значений
+	Average float64 // Среднее значение
+	Min     float64 // Минимальное значение
+	Max     float64 // Максимальное значение
+	P95     float64 // 95-й перцентиль (по ближайшему рангу)
+}
+
+// MetricPoint значение метрики, загруженное пользователем (UploadMetrics, StreamMetrics)
+type MetricPoint struct {
+	OwnerID string    // Пользователь, загрузивший значение
+	Name    string    // Название метрики (пусто для значений без названия)
+	Value   float64   // Значение
+	Time    time.Time // Время значения (по умолчанию время получения сервером)
+}
+
+// MetricAggregation агрегат значений метрики в QueryMetrics
+type MetricAggregation int
+
+const (
+	MetricAggregationNone  MetricAggregation = iota // Значения без агрегации
+	MetricAggregationCount                          // Количество значений
+	MetricAggregationSum                            // Сумма
+	MetricAggregationAvg                            // Среднее
+	MetricAggregationMin                            // Минимум
+	MetricAggregationMax                            // Максимум
+	MetricAggregationP95                            // 95-й перцентиль
+)
+
+// MetricQuery выборка значений метрики пользователя за период
+type MetricQuery struct {
+	Name        string
+	From        time.Time // Начало периода включительно (нулевое время - без ограничения)
+	To          time.Time // Конец периода не включительно (нулевое время - без ограничения)
+	Aggregation MetricAggregation
+}
+
+// MetricQueryResult результат выборки значений метрики
+type MetricQueryResult struct {
+	Points    []MetricPoint // Значения в порядке времени (только без агрегации)
+	Truncated bool          // Значений больше, чем возвращено в Points
+	Value     float64       // Агрегат (для MetricAggregationNone - 0)
+	Count     int64         // Количество значений за период
+}
@@ -2,8 +2,12 @@ package model
 
 import (
 	"errors"
+	"fmt"
 	"strings"
 	"time"
+	"unicode"
+
+	"github.com/cespare/xxhash/v2"
 )
 
 // Note представляет заметку (доменная модель)
@@ -13,6 +17,27 @@ type Note struct {
 	Content   string    // Содержание заметки
 	CreatedAt time.Time // Дата создания
 	UpdatedAt time.Time // Дата последнего обновления
+	Version   int64     // Версия заметки для оптимистичной блокировки
+	Tags      []string  // Теги заметки в каноническом виде (см. NormalizeTags)
+	OwnerID   string    // Идентификатор пользователя-владельца
+	Pinned    bool      // Заметка закреплена и выводится в начале списка
+	Archived  bool      // Заметка в архиве и не выводится в списке без ListOptions.IncludeArchived
+	RemindAt  time.Time // Время напоминания (нулевое - напоминания нет)
+	KeyID     string    // ID ключа, которым содержимое зашифровано в хранилище (пусто без шифрования)
+
+	// PassphraseHash - хэш парольной фразы заметки (argon2id в формате PHC), пусто - заметка не защищена
+	// Хэш не покидает сервер: клиенту сообщается только признак защиты (см. PassphraseProtected)
+	PassphraseHash string
+
+	// Вычисляемые поля: пересчитываются сервисом при создании и изменении содержания (см. UpdateTextStats)
+	WordCount   int           // Количество слов в содержании
+	ReadingTime time.Duration // Оценка времени чтения содержания
+
+	// Сквозное шифрование: содержимое зашифровано клиентом и хранится как есть,
+	// Content у таких заметок пуст, а заметка не попадает во вторичные индексы
+	IsE2E            bool   // Заметка зашифрована на клиенте
+	E2EScheme        string // Схема шифрования (см. SupportedE2ESchemes)
+	ContentEncrypted []byte // Зашифрованное содержимое
 }
 
 // Validate проверяет валидность заметки
@@ -20,10 +45,90 @@ func (n *Note) Validate() error {
 	if strings.TrimSpace(n.Title) == "" {
 		return errors.New("title cannot be empty")
 	}
+
+	if !n.IsE2E {
+		if len(n.ContentEncrypted) > 0 {
+			return errors.New("invalid note: content_encrypted requires is_e2e")
+		}
+		return nil
+	}
+
+	if !IsSupportedE2EScheme(n.E2EScheme) {
+		return fmt.Errorf("invalid e2e scheme %q", n.E2EScheme)
+	}
+	if len(n.ContentEncrypted) == 0 {
+		return errors.New("content_encrypted cannot be empty for e2e note")
+	}
+	if n.Content != "" {
+		return errors.New("invalid note: e2e note must not have plaintext content")
+	}
 	return nil
 }
 
+// ReadingWordsPerMinute средняя скорость чтения, по которой оценивается время чтения заметки
+const ReadingWordsPerMinute = 200
+
+// UpdateTextStats пересчитывает количество слов и время чтения содержания
+// Содержимое e2e заметок серверу недоступно, для них оба значения нулевые
+func (n *Note) UpdateTextStats() {
+	n.WordCount = CountWords(n.Content)
+	n.ReadingTime = ReadingTime(n.WordCount)
+}
+
+// ReadingTime оценивает время чтения wordCount слов с точностью до секунды (с округлением вверх)
+func ReadingTime(wordCount int) time.Duration {
+	d := time.Duration(wordCount) * time.Minute / ReadingWordsPerMinute
+	return (d + time.Second - 1).Truncate(time.Second)
+}
+
+// CountWords считает слова текста: последовательности символов, разделенные пробельными символами
+func CountWords(s string) int {
+	count, inWord := 0, false
+	for _, r := range s {
+		if unicode.IsSpace(r) {
+			inWord = false
+			continue
+		}
+		if !inWord {
+			count++
+			inWord = true
+		}
+	}
+	return count
+}
+
+// PassphraseProtected сообщает, защищена ли заметка парольной фразой
+func (n *Note) PassphraseProtected() bool {
+	return n.PassphraseHash != ""
+}
+
 // IsEmpty проверяет, пуста ли заметка
 func (n *Note) IsEmpty() bool {
 	return n.ID == "" && n.Title == "" && n.Content == ""
 }
+
+// ContentHash возвращает xxHash канонического представления изменяемых полей заметки
+// (title, content, теги, время напоминания и зашифрованное содержимое)
+// Используется для обнаружения обновлений, которые ничего не меняют
+func (n *Note) ContentHash() uint64 {
+	d := xxhash.New()
+	// Разделитель \x00 исключает коллизии вида "ab"+"c" и "a"+"bc"
+	_, _ = d.WriteString(n.Title)
+	_, _ = d.WriteString("\x00")
+	_, _ = d.WriteString(n.Content)
+	for _, tag := range n.Tags {
+		_, _ = d.WriteString("\x00")
+		_, _ = d.WriteString(tag)
+	}
+	if !n.RemindAt.IsZero() {
+		_, _ = d.WriteString("\x02")
+		_, _ = d.WriteString(n.RemindAt.UTC().Format(time.RFC3339Nano))
+	}
+	if n.IsE2E {
+		_, _ = d.WriteString("\x01")
+		_, _ = d.WriteString(n.E2EScheme)
+		_, _ = d.WriteString("\x00")
+		_, _ = d.Write(n.ContentEncrypted)
+	}
+	return d.Sum64()
+}
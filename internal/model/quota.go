@@ -0,0 +1,8 @@
+package model
+
+// QuotaUsage использование квоты заметок тенанта
+type QuotaUsage struct {
+	Used             int // Заметок тенанта
+	Limit            int // Квота заметок (max_notes)
+	ThresholdPercent int // Пересеченный мягкий порог в процентах
+}
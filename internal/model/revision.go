@@ -0,0 +1,27 @@
+package model
+
+import "time"
+
+// NoteRevision представляет сохраненное состояние заметки (доменная модель)
+type NoteRevision struct {
+	NoteID    string    // UUID заметки
+	Revision  int64     // Номер ревизии (начиная с 1)
+	Title     string    // Заголовок на момент ревизии
+	Content   string    // Содержание на момент ревизии
+	CreatedAt time.Time // Время создания ревизии
+
+	ContentEncrypted []byte // Зашифрованное содержимое на момент ревизии (для e2e заметок)
+}
+
+// NewRevision создает ревизию из текущего состояния заметки
+// Номер ревизии назначается репозиторием при сохранении
+func NewRevision(note Note) NoteRevision {
+	return NoteRevision{
+		NoteID:    note.ID,
+		Title:     note.Title,
+		Content:   note.Content,
+		CreatedAt: note.UpdatedAt,
+
+		ContentEncrypted: note.ContentEncrypted,
+	}
+}
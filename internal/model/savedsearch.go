@@ -0,0 +1,13 @@
+package model
+
+import "time"
+
+// SavedSearch сохраненный поиск (умная папка): именованное выражение фильтра заметок пользователя
+type SavedSearch struct {
+	ID        string    // ID поиска
+	OwnerID   string    // Пользователь, сохранивший поиск (поиск выполняется по его заметкам)
+	Name      string    // Имя поиска, уникальное у пользователя
+	Query     string    // Выражение фильтра (см. searches.Parse)
+	CreatedAt time.Time // Время создания
+	UpdatedAt time.Time // Время последнего изменения выражения
+}
@@ -0,0 +1,37 @@
+package model
+
+import "time"
+
+// SharePermission уровень доступа к заметке, которой поделился владелец
+type SharePermission int
+
+// Уровни доступа: запись включает чтение
+const (
+	SharePermissionRead  SharePermission = 1 // Чтение заметки
+	SharePermissionWrite SharePermission = 2 // Чтение и изменение заметки
+)
+
+// Valid проверяет, что уровень доступа известен
+func (p SharePermission) Valid() bool {
+	return p == SharePermissionRead || p == SharePermissionWrite
+}
+
+// Allows проверяет, достаточно ли уровня доступа p для операции с уровнем required
+func (p SharePermission) Allows(required SharePermission) bool {
+	return p.Valid() && p >= required
+}
+
+// Share доступ пользователя к чужой заметке (доменная модель)
+type Share struct {
+	NoteID     string          // UUID заметки
+	OwnerID    string          // Владелец заметки, предоставивший доступ
+	UserID     string          // Пользователь, получивший доступ
+	Permission SharePermission // Уровень доступа
+	CreatedAt  time.Time       // Время предоставления доступа
+}
+
+// SharedNote заметка, доступная пользователю по Share
+type SharedNote struct {
+	Note       Note
+	Permission SharePermission
+}
@@ -0,0 +1,29 @@
+package model
+
+import "time"
+
+// NoteStats статистика содержимого заметки
+type NoteStats struct {
+	NoteID         string
+	WordCount      int64
+	CharacterCount int64
+	ReadingTime    time.Duration
+	UpdatedAt      time.Time
+	LastEdit       *NoteEditDelta // nil, если заметку не правили после создания
+	IsE2E          bool           // Содержимое зашифровано на клиенте, счетчики не вычисляются
+}
+
+// NoteEditDelta изменение содержания последней правкой относительно предыдущей ревизии
+type NoteEditDelta struct {
+	Revision        int64
+	WordsDelta      int64
+	CharactersDelta int64
+}
+
+// AccountStats сводная статистика заметок пользователя
+type AccountStats struct {
+	TotalNotes      int64
+	TotalWords      int64
+	TotalCharacters int64
+	NotesPerTag     []TagCount
+}
@@ -0,0 +1,11 @@
+package model
+
+import "time"
+
+// NoteSuggestion подсказка заголовка заметки для автодополнения
+type NoteSuggestion struct {
+	NoteID    string
+	Title     string
+	UpdatedAt time.Time
+	Views     int64 // Количество открытий заметки с запуска сервера
+}
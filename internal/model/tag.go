@@ -0,0 +1,32 @@
+package model
+
+import (
+	"slices"
+	"strings"
+)
+
+// TagCount количество заметок с тегом
+type TagCount struct {
+	Tag   string // Тег
+	Count int    // Количество заметок с тегом
+}
+
+// NormalizeTag приводит тег к каноническому виду (без пробелов по краям, в нижнем регистре)
+func NormalizeTag(tag string) string {
+	return strings.ToLower(strings.TrimSpace(tag))
+}
+
+// NormalizeTags приводит теги к каноническому виду, удаляет пустые и повторяющиеся
+// и сортирует результат. Для пустого входа возвращает nil
+func NormalizeTags(tags []string) []string {
+	var result []string
+	for _, tag := range tags {
+		if tag = NormalizeTag(tag); tag != "" {
+			result = append(result, tag)
+		}
+	}
+
+	slices.Sort(result)
+
+	return slices.Compact(result)
+}
@@ -0,0 +1,34 @@
+package model
+
+import "time"
+
+// UsageStats агрегированная статистика использования сервиса с момента запуска реплики
+// Содержит только счетчики вызовов методов и функций: ни пользователей, ни данных запросов
+type UsageStats struct {
+	InstallationID string         // Случайный ID реплики, созданный при запуске
+	Since          time.Time      // Начало сбора статистики
+	Methods        []MethodUsage  // Вызовы gRPC методов, по имени метода
+	Features       []FeatureUsage // Использование функций, по имени функции
+	Reporting      UsageReporting // Отправка статистики на внешний адрес
+}
+
+// MethodUsage количество вызовов gRPC метода
+type MethodUsage struct {
+	Method string // Полное имя метода (/notes.v1.NotesService/CreateNote)
+	Calls  int64  // Количество вызовов
+	Errors int64  // Количество вызовов, завершившихся ошибкой
+}
+
+// FeatureUsage количество использований функции (например, e2e заметки или маска обновления)
+type FeatureUsage struct {
+	Feature string
+	Count   int64
+}
+
+// UsageReporting состояние отправки статистики на внешний адрес
+type UsageReporting struct {
+	Enabled      bool      // Адрес задан в конфигурации
+	LastReportAt time.Time // Время последней успешной отправки
+	LastError    error     // Ошибка последней отправки (nil, если она успешна)
+	Reports      int64     // Количество успешных отправок
+}
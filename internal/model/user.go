@@ -0,0 +1,13 @@
+package model
+
+import "time"
+
+// User пользователь сервиса (доменная модель)
+// На ID пользователя ссылаются владельцы заметок и доступы к ним
+type User struct {
+	ID           string    // Идентификатор пользователя (владельца заметок)
+	Username     string    // Имя для входа по паролю (пусто, если вход по паролю не разрешен)
+	PasswordHash string    // Хэш пароля (пусто, если вход по паролю не разрешен)
+	Roles        []string  // Роли пользователя
+	CreatedAt    time.Time // Время создания
+}
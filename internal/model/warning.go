@@ -0,0 +1,26 @@
+package model
+
+// Коды предупреждений (Warning.Code)
+const (
+	// WarningWhitespaceTrimmed - у значения удалены пробелы в начале и конце
+	WarningWhitespaceTrimmed = "WHITESPACE_TRIMMED"
+
+	// WarningTagsNormalized - теги приведены к нижнему регистру, пустые и повторяющиеся удалены
+	WarningTagsNormalized = "TAGS_NORMALIZED"
+
+	// WarningRemindAtInPast - время напоминания уже прошло, напоминание сработает сразу
+	WarningRemindAtInPast = "REMIND_AT_IN_PAST"
+
+	// WarningStaleRead - хранилище недоступно, данные прочитаны из снимка и могут быть устаревшими
+	WarningStaleRead = "STALE_READ"
+
+	// WarningQuotaSoftLimit - использование квоты заметок достигло мягкого порога тенанта
+	WarningQuotaSoftLimit = "QUOTA_SOFT_LIMIT"
+)
+
+// Warning некритичное замечание к запросу: запрос выполнен, но часть значений изменена
+type Warning struct {
+	Code    string // Код предупреждения (Warning*)
+	Message string // Описание для человека
+	Field   string // Поле запроса, к которому относится предупреждение
+}
@@ -0,0 +1,34 @@
+package model
+
+import (
+	"slices"
+	"time"
+)
+
+// Webhook адрес, на который отправляются события заметок владельца
+type Webhook struct {
+	ID         string    // ID вебхука
+	OwnerID    string    // Пользователь, зарегистрировавший вебхук (получает события своих заметок)
+	URL        string    // Адрес для POST запросов с событиями (http или https)
+	Secret     string    // Ключ HMAC подписи запросов
+	EventTypes []string  // Имена типов событий (например, note_created), пусто - все события
+	CreatedAt  time.Time // Время регистрации
+}
+
+// Accepts проверяет, нужно ли отправлять вебхуку событие типа eventType
+func (w Webhook) Accepts(eventType string) bool {
+	return len(w.EventTypes) == 0 || slices.Contains(w.EventTypes, eventType)
+}
+
+// WebhookDeadLetter событие, которое не удалось доставить вебхуку за все попытки
+type WebhookDeadLetter struct {
+	ID        string    // ID доставки (заголовок X-Webhook-Delivery)
+	WebhookID string    // Вебхук
+	OwnerID   string    // Владелец вебхука
+	URL       string    // Адрес вебхука на момент доставки
+	EventType string    // Имя типа события
+	Payload   []byte    // Тело запроса (JSON)
+	Attempts  int       // Количество выполненных попыток
+	LastError string    // Ошибка последней попытки
+	FailedAt  time.Time // Время последней попытки
+}
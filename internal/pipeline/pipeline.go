@@ -0,0 +1,154 @@
+package pipeline
+
+import (
+	"fmt"
+	"log"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// Имена цепочек
+const (
+	ChainGRPCUnary  = "grpc_unary"  // Unary интерцепторы gRPC сервера
+	ChainGRPCStream = "grpc_stream" // Стриминговые интерцепторы gRPC сервера
+	ChainHTTP       = "http"        // Middleware HTTP Gateway
+)
+
+// Stage звено цепочки: интерцептор или middleware с его настройками
+type Stage struct {
+	Name     string
+	Settings map[string]string // Значения конфигурации, влияющие на работу звена
+}
+
+// Chain цепочка интерцепторов или middleware в порядке выполнения
+type Chain struct {
+	Name   string
+	Stages []Stage
+}
+
+// Registry хранит действующие цепочки сервера для AdminService.GetPipeline
+// Замена уже зарегистрированной цепочки записывает в лог ее изменения
+type Registry struct {
+	mu     sync.RWMutex
+	chains map[string]Chain
+}
+
+// NewRegistry создает пустой реестр цепочек
+func NewRegistry() *Registry {
+	return &Registry{chains: make(map[string]Chain)}
+}
+
+// Set регистрирует цепочку chain вместо цепочки с тем же именем
+func (r *Registry) Set(chain Chain) {
+	r.mu.Lock()
+	previous, replaced := r.chains[chain.Name]
+	r.chains[chain.Name] = chain
+	r.mu.Unlock()
+
+	if !replaced {
+		log.Printf("Pipeline %s: %s", chain.Name, chain)
+		return
+	}
+	for _, change := range Diff(previous, chain) {
+		log.Printf("Pipeline %s changed: %s", chain.Name, change)
+	}
+}
+
+// UpdateSettings заменяет настройки звена stage цепочки chain, например после перечитывания
+// конфигурации; изменения записываются в лог, как при Set. Без такого звена ничего не меняется
+func (r *Registry) UpdateSettings(chain, stage string, settings map[string]string) {
+	r.mu.RLock()
+	current, ok := r.chains[chain]
+	r.mu.RUnlock()
+	if !ok {
+		return
+	}
+	index := slices.IndexFunc(current.Stages, func(s Stage) bool { return s.Name == stage })
+	if index < 0 {
+		return
+	}
+	updated := Chain{Name: current.Name, Stages: slices.Clone(current.Stages)}
+	updated.Stages[index].Settings = settings
+	r.Set(updated)
+}
+
+// Chains возвращает зарегистрированные цепочки в порядке имен
+func (r *Registry) Chains() []Chain {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return slices.SortedFunc(maps.Values(r.chains), func(a, b Chain) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+}
+
+// String возвращает звенья цепочки через стрелку
+func (c Chain) String() string {
+	return strings.Join(stageNames(c), " → ")
+}
+
+// Diff возвращает изменения цепочки: добавленные и удаленные звенья, смену порядка
+// и изменения настроек звеньев, которые есть в обеих цепочках
+func Diff(before, after Chain) []string {
+	var changes []string
+	oldNames := stageNames(before)
+	newNames := stageNames(after)
+	for _, name := range oldNames {
+		if !slices.Contains(newNames, name) {
+			changes = append(changes, "- "+name)
+		}
+	}
+	for _, name := range newNames {
+		if !slices.Contains(oldNames, name) {
+			changes = append(changes, "+ "+name)
+		}
+	}
+
+	common := func(names []string, other []string) []string {
+		return slices.DeleteFunc(slices.Clone(names), func(name string) bool { return !slices.Contains(other, name) })
+	}
+	if !slices.Equal(common(oldNames, newNames), common(newNames, oldNames)) {
+		changes = append(changes, fmt.Sprintf("order: %s ⇒ %s", before, after))
+	}
+
+	for _, stage := range after.Stages {
+		index := slices.IndexFunc(before.Stages, func(s Stage) bool { return s.Name == stage.Name })
+		if index < 0 {
+			continue
+		}
+		settings := before.Stages[index].Settings
+		for _, key := range slices.Sorted(maps.Keys(merge(settings, stage.Settings))) {
+			oldValue, hadOld := settings[key]
+			newValue, hasNew := stage.Settings[key]
+			switch {
+			case !hadOld:
+				changes = append(changes, fmt.Sprintf("%s.%s: %q", stage.Name, key, newValue))
+			case !hasNew:
+				changes = append(changes, fmt.Sprintf("%s.%s: %q ⇒ removed", stage.Name, key, oldValue))
+			case oldValue != newValue:
+				changes = append(changes, fmt.Sprintf("%s.%s: %q ⇒ %q", stage.Name, key, oldValue, newValue))
+			}
+		}
+	}
+	return changes
+}
+
+// stageNames возвращает имена звеньев цепочки по порядку
+func stageNames(chain Chain) []string {
+	names := make([]string, len(chain.Stages))
+	for i, stage := range chain.Stages {
+		names[i] = stage.Name
+	}
+	return names
+}
+
+// merge возвращает объединение ключей двух настроек
+func merge(a, b map[string]string) map[string]string {
+	result := maps.Clone(a)
+	if result == nil {
+		result = make(map[string]string)
+	}
+	maps.Copy(result, b)
+	return result
+}
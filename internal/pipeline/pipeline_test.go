@@ -0,0 +1,72 @@
+package pipeline
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	before := Chain{Name: ChainGRPCUnary, Stages: []Stage{
+		{Name: "logger"},
+		{Name: "auth", Settings: map[string]string{"public_methods": "/a/Login", "stream_tickets": "true"}},
+		{Name: "recorder"},
+		{Name: "tenant"},
+	}}
+	after := Chain{Name: ChainGRPCUnary, Stages: []Stage{
+		{Name: "logger"},
+		{Name: "tenant"},
+		{Name: "auth", Settings: map[string]string{"public_methods": "/a/Login,/b/*", "authz": "on"}},
+		{Name: "consistency"},
+	}}
+
+	want := []string{
+		"- recorder",
+		"+ consistency",
+		"order: logger → auth → recorder → tenant ⇒ logger → tenant → auth → consistency",
+		`auth.authz: "on"`,
+		`auth.public_methods: "/a/Login" ⇒ "/a/Login,/b/*"`,
+		`auth.stream_tickets: "true" ⇒ removed`,
+	}
+	if got := Diff(before, after); !slices.Equal(got, want) {
+		t.Errorf("Diff() =\n%q\nwant\n%q", got, want)
+	}
+	if got := Diff(after, after); len(got) != 0 {
+		t.Errorf("Expected no changes for the same chain, got %q", got)
+	}
+}
+
+func TestRegistry_Chains(t *testing.T) {
+	registry := NewRegistry()
+	registry.Set(Chain{Name: ChainHTTP, Stages: []Stage{{Name: "drain"}}})
+	registry.Set(Chain{Name: ChainGRPCUnary, Stages: []Stage{{Name: "logger"}}})
+	registry.Set(Chain{Name: ChainHTTP, Stages: []Stage{{Name: "drain"}, {Name: "cors"}}})
+
+	chains := registry.Chains()
+	if len(chains) != 2 || chains[0].Name != ChainGRPCUnary || chains[1].Name != ChainHTTP {
+		t.Fatalf("Expected chains in name order, got %+v", chains)
+	}
+	if chains[1].String() != "drain → cors" {
+		t.Errorf("Expected the replaced http chain, got %s", chains[1])
+	}
+}
+
+func TestRegistry_UpdateSettings(t *testing.T) {
+	registry := NewRegistry()
+	registry.Set(Chain{Name: ChainHTTP, Stages: []Stage{
+		{Name: "cors", Settings: map[string]string{"allowed_origins": "a"}},
+		{Name: "rate_limit", Settings: map[string]string{"rps": "100"}},
+	}})
+
+	registry.UpdateSettings(ChainHTTP, "rate_limit", map[string]string{"rps": "200"})
+	registry.UpdateSettings(ChainHTTP, "unknown", map[string]string{"x": "y"})
+	registry.UpdateSettings(ChainGRPCUnary, "rate_limit", map[string]string{"rps": "1"})
+
+	chains := registry.Chains()
+	if len(chains) != 1 {
+		t.Fatalf("Expected only the http chain, got %+v", chains)
+	}
+	stages := chains[0].Stages
+	if stages[1].Settings["rps"] != "200" || stages[0].Settings["allowed_origins"] != "a" || len(stages) != 2 {
+		t.Errorf("Unexpected stages after UpdateSettings: %+v", stages)
+	}
+}
@@ -0,0 +1,173 @@
+// Package portmux разделяет соединения одного порта между gRPC сервером и HTTP Gateway
+// по первым байтам соединения: TLS handshake (gRPC с TLS) и preface HTTP/2 (gRPC без TLS)
+// передаются gRPC, остальные (HTTP/1.x, включая WebSocket и gRPC-Web) - HTTP
+package portmux
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"log"
+	"net"
+	"sync"
+	"time"
+)
+
+// http2Preface начало каждого соединения HTTP/2 без TLS (RFC 9113, 3.4)
+const http2Preface = "PRI * HTTP/2.0\r\n\r\nSM\r\n\r\n"
+
+// tlsHandshake тип первой записи TLS соединения (ClientHello)
+const tlsHandshake = 0x16
+
+// sniffTimeout время, за которое клиент должен отправить первые байты соединения
+const sniffTimeout = 10 * time.Second
+
+// Mux распределяет соединения общего listener между listener'ами GRPC и HTTP
+// Общий listener закрывается, когда закрыты оба: серверы закрывают свои при остановке
+type Mux struct {
+	root net.Listener
+	grpc *listener
+	http *listener
+
+	mu     sync.Mutex
+	closed int // Количество закрытых listener'ов GRPC и HTTP
+}
+
+// New создает Mux поверх root; соединения распределяются после запуска Serve
+func New(root net.Listener) *Mux {
+	m := &Mux{root: root}
+	m.grpc = newListener(m)
+	m.http = newListener(m)
+	return m
+}
+
+// GRPC возвращает listener соединений gRPC (для grpc.Server.Serve)
+func (m *Mux) GRPC() net.Listener {
+	return m.grpc
+}
+
+// HTTP возвращает listener соединений HTTP/1.x (для http.Server.Serve)
+func (m *Mux) HTTP() net.Listener {
+	return m.http
+}
+
+// Serve принимает соединения root и распределяет их, пока root не закрыт
+func (m *Mux) Serve() error {
+	for {
+		conn, err := m.root.Accept()
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return nil
+			}
+			var netErr net.Error
+			if errors.As(err, &netErr) && netErr.Timeout() {
+				continue
+			}
+			return err
+		}
+		go m.route(conn)
+	}
+}
+
+// route определяет протокол соединения по первым байтам и передает его listener'у
+func (m *Mux) route(conn net.Conn) {
+	reader := bufio.NewReaderSize(conn, len(http2Preface))
+	_ = conn.SetReadDeadline(time.Now().Add(sniffTimeout))
+	isGRPC, err := sniff(reader)
+	_ = conn.SetReadDeadline(time.Time{})
+	if err != nil {
+		_ = conn.Close()
+		return
+	}
+
+	target := m.http
+	if isGRPC {
+		target = m.grpc
+	}
+	target.deliver(&sniffedConn{Conn: conn, reader: reader})
+}
+
+// sniff читает начало соединения без его потребления: TLS и HTTP/2 - gRPC
+// Байты сравниваются по одному, чтобы короткий HTTP/1.x запрос не ждал полного preface
+func sniff(reader *bufio.Reader) (bool, error) {
+	for n := 1; n <= len(http2Preface); n++ {
+		head, err := reader.Peek(n)
+		if err != nil {
+			return false, err
+		}
+		if n == 1 && head[0] == tlsHandshake {
+			return true, nil
+		}
+		if !bytes.HasPrefix([]byte(http2Preface), head) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// closeChild закрывает root после закрытия обоих listener'ов
+func (m *Mux) closeChild() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.closed++
+	if m.closed == 2 {
+		if err := m.root.Close(); err != nil && !errors.Is(err, net.ErrClosed) {
+			log.Printf("[PortMux] Failed to close listener: %v", err)
+		}
+	}
+}
+
+// listener выдает соединения одного протокола
+type listener struct {
+	mux   *Mux
+	conns chan net.Conn
+	done  chan struct{}
+	once  sync.Once
+}
+
+func newListener(m *Mux) *listener {
+	return &listener{mux: m, conns: make(chan net.Conn), done: make(chan struct{})}
+}
+
+// Accept ожидает следующее соединение протокола
+func (l *listener) Accept() (net.Conn, error) {
+	select {
+	case conn := <-l.conns:
+		return conn, nil
+	case <-l.done:
+		return nil, net.ErrClosed
+	}
+}
+
+// Close прекращает прием соединений протокола; ожидающие соединения закрываются
+func (l *listener) Close() error {
+	l.once.Do(func() {
+		close(l.done)
+		l.mux.closeChild()
+	})
+	return nil
+}
+
+// Addr возвращает адрес общего listener
+func (l *listener) Addr() net.Addr {
+	return l.mux.root.Addr()
+}
+
+// deliver передает соединение серверу или закрывает его, если listener закрыт
+func (l *listener) deliver(conn net.Conn) {
+	select {
+	case l.conns <- conn:
+	case <-l.done:
+		_ = conn.Close()
+	}
+}
+
+// sniffedConn соединение, начало которого уже прочитано в reader
+type sniffedConn struct {
+	net.Conn
+	reader *bufio.Reader
+}
+
+func (c *sniffedConn) Read(p []byte) (int, error) {
+	return c.reader.Read(p)
+}
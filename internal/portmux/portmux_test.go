@@ -0,0 +1,90 @@
+package portmux
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+func TestMux_ServesGRPCAndHTTP(t *testing.T) {
+	root, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	m := New(root)
+	served := make(chan error, 1)
+	go func() { served <- m.Serve() }()
+
+	grpcServer := grpc.NewServer()
+	healthpb.RegisterHealthServer(grpcServer, health.NewServer())
+	go func() { _ = grpcServer.Serve(m.GRPC()) }()
+
+	httpServer := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = io.WriteString(w, "http")
+	})}
+	go func() { _ = httpServer.Serve(m.HTTP()) }()
+
+	addr := root.Addr().String()
+	resp, err := http.Get("http://" + addr + "/")
+	if err != nil {
+		t.Fatalf("HTTP request failed: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if string(body) != "http" {
+		t.Errorf("Expected HTTP handler response, got %q", body)
+	}
+
+	conn, err := grpc.NewClient(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	defer conn.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	check, err := healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{})
+	if err != nil {
+		t.Fatalf("gRPC request failed: %v", err)
+	}
+	if check.GetStatus() != healthpb.HealthCheckResponse_SERVING {
+		t.Errorf("Expected SERVING, got %v", check.GetStatus())
+	}
+
+	// Общий порт закрывается после остановки обоих серверов
+	grpcServer.Stop()
+	_ = httpServer.Close()
+	select {
+	case err := <-served:
+		if err != nil {
+			t.Errorf("Expected Serve to return nil, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Serve did not return after both listeners were closed")
+	}
+}
+
+func TestSniff(t *testing.T) {
+	cases := map[string]bool{
+		http2Preface + "\x00\x00":              true,
+		"\x16\x03\x01\x02\x00":                 true,
+		"GET / HTTP/1.1\r\nHost: x\r\n\r\n":    false,
+		"POST /notes.v1.NotesService/GetNote ": false,
+		"PUT / HTTP/1.0\r\n\r\n":               false,
+	}
+	for input, want := range cases {
+		got, err := sniff(bufio.NewReaderSize(strings.NewReader(input), len(http2Preface)))
+		if err != nil || got != want {
+			t.Errorf("sniff(%q) = %v, %v, want %v", input, got, err, want)
+		}
+	}
+}
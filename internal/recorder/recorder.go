@@ -0,0 +1,227 @@
+package recorder
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// DefaultMaxSegments количество файлов кольцевого буфера по умолчанию
+	DefaultMaxSegments = 10
+	// DefaultSegmentRecords количество запросов в одном файле по умолчанию
+	DefaultSegmentRecords = 10000
+)
+
+// segmentPattern шаблон имени файла сегмента, номер определяет порядок воспроизведения
+const segmentPattern = "requests-%08d.jsonl"
+
+// Record записанный запрос: одна строка JSON в файле сегмента
+type Record struct {
+	Time    time.Time       `json:"time"`
+	Method  string          `json:"method"`         // Полное имя метода, например /notes.v1.NotesService/CreateNote
+	User    string          `json:"user,omitempty"` // Пользователь запроса; токен не записывается
+	Request json.RawMessage `json:"request"`        // Запрос в protojson после очистки (см. Sanitize)
+}
+
+// Options параметры записи
+type Options struct {
+	Dir            string   // Каталог сегментов
+	MaxSegments    int      // Количество хранимых сегментов, старые удаляются
+	SegmentRecords int      // Количество запросов в сегменте
+	RedactFields   []string // Имена полей, значения которых заменяются заглушкой той же длины
+}
+
+// Recorder записывает запросы на диск в кольцевой буфер из сегментов
+// Хранятся последние MaxSegments сегментов по SegmentRecords запросов
+type Recorder struct {
+	opts   Options
+	redact map[string]bool
+
+	mu      sync.Mutex
+	segment int // Номер текущего сегмента
+	count   int // Количество запросов в текущем сегменте
+	file    *os.File
+}
+
+// New создает Recorder; запись продолжается после последнего существующего сегмента
+func New(opts Options) (*Recorder, error) {
+	if opts.MaxSegments <= 0 {
+		opts.MaxSegments = DefaultMaxSegments
+	}
+	if opts.SegmentRecords <= 0 {
+		opts.SegmentRecords = DefaultSegmentRecords
+	}
+
+	if err := os.MkdirAll(opts.Dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create recorder dir: %w", err)
+	}
+
+	segments, err := listSegments(opts.Dir)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &Recorder{opts: opts, redact: make(map[string]bool, len(opts.RedactFields))}
+	for _, field := range opts.RedactFields {
+		r.redact[field] = true
+	}
+	if len(segments) > 0 {
+		r.segment = segments[len(segments)-1] + 1
+	}
+
+	if err := r.openSegment(); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// Record очищает и записывает запрос req метода method
+func (r *Recorder) Record(method, user string, req proto.Message) error {
+	data, err := protojson.Marshal(Sanitize(req, r.redact))
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	line, err := json.Marshal(Record{Time: time.Now(), Method: method, User: user, Request: data})
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.file == nil {
+		return os.ErrClosed
+	}
+	if _, err := r.file.Write(append(line, '\n')); err != nil {
+		return err
+	}
+
+	r.count++
+	if r.count >= r.opts.SegmentRecords {
+		return r.rotate()
+	}
+
+	return nil
+}
+
+// Close закрывает текущий сегмент
+func (r *Recorder) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.file == nil {
+		return nil
+	}
+	err := r.file.Close()
+	r.file = nil
+	return err
+}
+
+// rotate начинает новый сегмент и удаляет сегменты, вышедшие за пределы буфера. Вызывается под r.mu
+func (r *Recorder) rotate() error {
+	if err := r.file.Close(); err != nil {
+		return err
+	}
+
+	r.segment++
+	r.count = 0
+	if err := r.openSegment(); err != nil {
+		return err
+	}
+
+	segments, err := listSegments(r.opts.Dir)
+	if err != nil {
+		return err
+	}
+	for _, segment := range segments {
+		if segment <= r.segment-r.opts.MaxSegments {
+			if err := os.Remove(segmentPath(r.opts.Dir, segment)); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// openSegment открывает файл текущего сегмента на дозапись
+func (r *Recorder) openSegment() error {
+	file, err := os.OpenFile(segmentPath(r.opts.Dir, r.segment), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to open recorder segment: %w", err)
+	}
+	r.file = file
+	return nil
+}
+
+// ReadDir читает записанные запросы из всех сегментов каталога в порядке записи
+func ReadDir(dir string) ([]Record, error) {
+	segments, err := listSegments(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var records []Record
+	for _, segment := range segments {
+		file, err := os.Open(segmentPath(dir, segment))
+		if err != nil {
+			return nil, err
+		}
+
+		scanner := bufio.NewScanner(file)
+		scanner.Buffer(make([]byte, 64*1024), 16<<20)
+		for line := 1; scanner.Scan(); line++ {
+			var record Record
+			if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+				file.Close()
+				return nil, fmt.Errorf("invalid record %s:%d: %w", filepath.Base(file.Name()), line, err)
+			}
+			records = append(records, record)
+		}
+		err = scanner.Err()
+		file.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return records, nil
+}
+
+// listSegments возвращает номера сегментов каталога по возрастанию
+func listSegments(dir string) ([]int, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var segments []int
+	for _, entry := range entries {
+		var segment int
+		if entry.IsDir() || !strings.HasPrefix(entry.Name(), "requests-") {
+			continue
+		}
+		if _, err := fmt.Sscanf(entry.Name(), segmentPattern, &segment); err == nil {
+			segments = append(segments, segment)
+		}
+	}
+	slices.Sort(segments)
+
+	return segments, nil
+}
+
+func segmentPath(dir string, segment int) string {
+	return filepath.Join(dir, fmt.Sprintf(segmentPattern, segment))
+}
@@ -0,0 +1,88 @@
+package recorder
+
+import (
+	"bytes"
+	"testing"
+
+	notesv1 "notes-service/pkg/proto/notes/v1"
+
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+func TestRecorder_RingBuffer(t *testing.T) {
+	dir := t.TempDir()
+	rec, err := New(Options{Dir: dir, MaxSegments: 2, SegmentRecords: 3})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	for i := 0; i < 10; i++ {
+		req := &notesv1.GetNoteRequest{Id: string(rune('a' + i))}
+		if err := rec.Record("/notes.v1.NotesService/GetNote", "demo", req); err != nil {
+			t.Fatalf("Record: %v", err)
+		}
+	}
+	if err := rec.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	segments, err := listSegments(dir)
+	if err != nil {
+		t.Fatalf("listSegments: %v", err)
+	}
+	if len(segments) != 2 {
+		t.Errorf("expected 2 segments, got %v", segments)
+	}
+
+	// Остаются последний заполненный сегмент (g, h, i) и текущий (j)
+	records, err := ReadDir(dir)
+	if err != nil {
+		t.Fatalf("ReadDir: %v", err)
+	}
+	if len(records) != 4 {
+		t.Fatalf("expected 4 records, got %d", len(records))
+	}
+
+	var req notesv1.GetNoteRequest
+	if err := protojson.Unmarshal(records[0].Request, &req); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if req.GetId() != "g" || records[0].User != "demo" {
+		t.Errorf("unexpected first record: %+v", records[0])
+	}
+
+	// Новый Recorder продолжает нумерацию сегментов
+	rec, err = New(Options{Dir: dir, MaxSegments: 2, SegmentRecords: 3})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	defer rec.Close()
+	if rec.segment != segments[len(segments)-1]+1 {
+		t.Errorf("expected segment %d, got %d", segments[len(segments)-1]+1, rec.segment)
+	}
+}
+
+func TestSanitize(t *testing.T) {
+	req := &notesv1.BatchCreateNotesRequest{Notes: []*notesv1.CreateNoteRequest{{
+		Title:            "Secret plans",
+		Content:          "Top secret content",
+		ContentEncrypted: []byte{1, 2, 3},
+	}}}
+
+	redact := map[string]bool{"content": true, "content_encrypted": true}
+	sanitized := Sanitize(req, redact).(*notesv1.BatchCreateNotesRequest)
+
+	note := sanitized.GetNotes()[0]
+	if note.GetTitle() != "Secret plans" {
+		t.Errorf("expected title to be kept, got %q", note.GetTitle())
+	}
+	if note.GetContent() != "xxxxxxxxxxxxxxxxxx" {
+		t.Errorf("expected content placeholder of the same length, got %q", note.GetContent())
+	}
+	if !bytes.Equal(note.GetContentEncrypted(), []byte{0, 0, 0}) {
+		t.Errorf("expected zeroed bytes, got %v", note.GetContentEncrypted())
+	}
+	if req.GetNotes()[0].GetContent() != "Top secret content" {
+		t.Error("expected original request to stay unchanged")
+	}
+}
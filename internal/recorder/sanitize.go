@@ -0,0 +1,64 @@
+package recorder
+
+import (
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// DefaultRedactFields поля с пользовательскими данными, которые не попадают в записи
+var DefaultRedactFields = []string{"content", "content_encrypted", "data"}
+
+// Sanitize возвращает копию msg, в которой значения полей из redact (по имени поля в proto)
+// заменены заглушками той же длины: форма трафика сохраняется, содержимое - нет
+func Sanitize(msg proto.Message, redact map[string]bool) proto.Message {
+	clone := proto.Clone(msg)
+	sanitize(clone.ProtoReflect(), redact)
+	return clone
+}
+
+func sanitize(msg protoreflect.Message, redact map[string]bool) {
+	msg.Range(func(fd protoreflect.FieldDescriptor, value protoreflect.Value) bool {
+		switch {
+		case fd.IsMap():
+			if fd.MapValue().Message() != nil {
+				value.Map().Range(func(_ protoreflect.MapKey, item protoreflect.Value) bool {
+					sanitize(item.Message(), redact)
+					return true
+				})
+			}
+		case fd.Message() != nil:
+			if fd.IsList() {
+				list := value.List()
+				for i := 0; i < list.Len(); i++ {
+					sanitize(list.Get(i).Message(), redact)
+				}
+			} else {
+				sanitize(value.Message(), redact)
+			}
+		case redact[string(fd.Name())]:
+			if fd.IsList() {
+				list := value.List()
+				for i := 0; i < list.Len(); i++ {
+					list.Set(i, placeholder(fd, list.Get(i)))
+				}
+			} else {
+				msg.Set(fd, placeholder(fd, value))
+			}
+		}
+		return true
+	})
+}
+
+// placeholder возвращает заглушку той же длины для строковых и байтовых значений
+func placeholder(fd protoreflect.FieldDescriptor, value protoreflect.Value) protoreflect.Value {
+	switch fd.Kind() {
+	case protoreflect.StringKind:
+		return protoreflect.ValueOfString(strings.Repeat("x", len(value.String())))
+	case protoreflect.BytesKind:
+		return protoreflect.ValueOfBytes(make([]byte, len(value.Bytes())))
+	default:
+		return value
+	}
+}
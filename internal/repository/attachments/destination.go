@@ -0,0 +1,68 @@
+package attachments
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// Destination хранилище файлов выгрузки заметок (ExportToDestination) и резервных копий
+// Использует те же бэкенды, что и вложения: локальный каталог или бакет S3/MinIO
+type Destination struct {
+	store    blobStore
+	location func(key string) string
+}
+
+// NewFilesystemDestination создает хранилище выгрузок в локальном каталоге dir
+func NewFilesystemDestination(dir string) (*Destination, error) {
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create export directory %s: %w", dir, err)
+	}
+	return &Destination{
+		store: &fsStore{dir: dir},
+		location: func(key string) string {
+			return filepath.Join(dir, filepath.FromSlash(key))
+		},
+	}, nil
+}
+
+// NewS3Destination создает хранилище выгрузок в бакете S3
+func NewS3Destination(cfg S3Config) (*Destination, error) {
+	store, err := newS3Store(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &Destination{
+		store: store,
+		location: func(key string) string {
+			return "s3://" + store.cfg.Bucket + "/" + store.cfg.Prefix + key
+		},
+	}, nil
+}
+
+// Put сохраняет файл размером size байт под ключом key и возвращает его адрес:
+// путь к файлу или s3://bucket/key
+func (d *Destination) Put(ctx context.Context, key string, data io.Reader, size int64, contentType string) (string, error) {
+	if err := d.store.put(ctx, key, data, size, contentType); err != nil {
+		return "", err
+	}
+	return d.location(key), nil
+}
+
+// Get открывает файл key на чтение, для отсутствующего файла возвращает ErrAttachmentNotFound
+// Вызывающая сторона обязана закрыть поток
+func (d *Destination) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	return d.store.get(ctx, key)
+}
+
+// List возвращает ключи файлов, начинающиеся с prefix, в порядке возрастания
+func (d *Destination) List(ctx context.Context, prefix string) ([]string, error) {
+	return d.store.list(ctx, prefix)
+}
+
+// Delete удаляет файл key
+func (d *Destination) Delete(ctx context.Context, key string) error {
+	return d.store.delete(ctx, key)
+}
@@ -0,0 +1,101 @@
+package attachments
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"notes-service/internal/repository"
+)
+
+// fsStore хранит объекты в файлах внутри каталога dir
+type fsStore struct {
+	dir string
+}
+
+// NewFilesystemRepository создает хранилище вложений в локальном каталоге dir
+// Каталог создается, если не существует
+func NewFilesystemRepository(dir string) (repository.AttachmentRepository, error) {
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create attachments directory %s: %w", dir, err)
+	}
+	return &repo{store: &fsStore{dir: dir}}, nil
+}
+
+// put записывает объект во временный файл и атомарно переименовывает его,
+// поэтому читатели никогда не видят частично записанный файл
+func (s *fsStore) put(ctx context.Context, key string, data io.Reader, size int64, contentType string) error {
+	target := filepath.Join(s.dir, filepath.FromSlash(key))
+	if err := os.MkdirAll(filepath.Dir(target), 0o750); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(target), ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) // Без эффекта после успешного переименования
+
+	written, err := io.Copy(tmp, data)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	if written != size {
+		return fmt.Errorf("size mismatch: expected %d bytes, got %d", size, written)
+	}
+
+	return os.Rename(tmp.Name(), target)
+}
+
+// get открывает файл объекта на чтение
+func (s *fsStore) get(ctx context.Context, key string) (io.ReadCloser, error) {
+	file, err := os.Open(filepath.Join(s.dir, filepath.FromSlash(key)))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, ErrAttachmentNotFound
+	}
+	return file, err
+}
+
+// deletePrefix удаляет каталог заметки целиком (prefix имеет вид "<note_id>/")
+func (s *fsStore) deletePrefix(ctx context.Context, prefix string) error {
+	return os.RemoveAll(filepath.Join(s.dir, filepath.FromSlash(prefix)))
+}
+
+// list обходит каталог и отбирает файлы с ключами, начинающимися с prefix
+// Временные файлы незавершенной записи (.upload-*) пропускаются
+func (s *fsStore) list(ctx context.Context, prefix string) ([]string, error) {
+	var keys []string
+	err := filepath.WalkDir(s.dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() || strings.HasPrefix(entry.Name(), ".upload-") {
+			return err
+		}
+		rel, err := filepath.Rel(s.dir, path)
+		if err != nil {
+			return err
+		}
+		if key := filepath.ToSlash(rel); strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+		return nil
+	})
+	slices.Sort(keys)
+	return keys, err
+}
+
+// delete удаляет файл объекта
+func (s *fsStore) delete(ctx context.Context, key string) error {
+	err := os.Remove(filepath.Join(s.dir, filepath.FromSlash(key)))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,127 @@
+package attachments
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"time"
+
+	"notes-service/internal/model"
+	"notes-service/internal/repository"
+)
+
+// ErrAttachmentNotFound возвращается, когда вложение не найдено
+var ErrAttachmentNotFound = errors.New("attachment not found")
+
+var _ repository.AttachmentRepository = (*repo)(nil)
+
+// blobStore хранилище объектов по ключу (файловая система или S3)
+type blobStore interface {
+	// put сохраняет объект размером size байт
+	put(ctx context.Context, key string, data io.Reader, size int64, contentType string) error
+
+	// get открывает объект на чтение, для отсутствующего объекта возвращает ErrAttachmentNotFound
+	get(ctx context.Context, key string) (io.ReadCloser, error)
+
+	// deletePrefix удаляет все объекты с ключами, начинающимися с prefix
+	deletePrefix(ctx context.Context, prefix string) error
+
+	// list возвращает ключи объектов, начинающиеся с prefix, в порядке возрастания
+	list(ctx context.Context, prefix string) ([]string, error)
+
+	// delete удаляет объект; отсутствующий объект не считается ошибкой
+	delete(ctx context.Context, key string) error
+}
+
+// repo реализует AttachmentRepository поверх blobStore
+// Раскладка ключей: <note_id>/<id> - содержимое, <note_id>/<id>.json - метаданные
+type repo struct {
+	store blobStore
+}
+
+// metadata метаданные вложения, сохраняемые рядом с содержимым
+type metadata struct {
+	ID          string    `json:"id"`
+	NoteID      string    `json:"note_id"`
+	FileName    string    `json:"file_name"`
+	ContentType string    `json:"content_type"`
+	Size        int64     `json:"size"`
+	SHA256      string    `json:"sha256"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// Save сохраняет содержимое, затем метаданные: вложение без метаданных считается незагруженным
+func (r *repo) Save(ctx context.Context, attachment model.Attachment, data io.Reader) error {
+	dataKey, err := objectKey(attachment.NoteID, attachment.ID)
+	if err != nil {
+		return err
+	}
+
+	if err := r.store.put(ctx, dataKey, data, attachment.Size, attachment.ContentType); err != nil {
+		return fmt.Errorf("failed to store attachment data: %w", err)
+	}
+
+	meta, err := json.Marshal(metadata(attachment))
+	if err != nil {
+		return fmt.Errorf("failed to encode attachment metadata: %w", err)
+	}
+
+	if err := r.store.put(ctx, dataKey+".json", bytes.NewReader(meta), int64(len(meta)), "application/json"); err != nil {
+		return fmt.Errorf("failed to store attachment metadata: %w", err)
+	}
+
+	return nil
+}
+
+// Open возвращает метаданные вложения заметки и поток его содержимого
+func (r *repo) Open(ctx context.Context, noteID, id string) (model.Attachment, io.ReadCloser, error) {
+	dataKey, err := objectKey(noteID, id)
+	if err != nil {
+		return model.Attachment{}, nil, err
+	}
+
+	metaReader, err := r.store.get(ctx, dataKey+".json")
+	if err != nil {
+		return model.Attachment{}, nil, err
+	}
+	defer metaReader.Close()
+
+	var meta metadata
+	if err := json.NewDecoder(metaReader).Decode(&meta); err != nil {
+		return model.Attachment{}, nil, fmt.Errorf("failed to decode attachment metadata: %w", err)
+	}
+
+	data, err := r.store.get(ctx, dataKey)
+	if err != nil {
+		return model.Attachment{}, nil, err
+	}
+
+	return model.Attachment(meta), data, nil
+}
+
+// DeleteByNoteID удаляет все вложения заметки
+func (r *repo) DeleteByNoteID(ctx context.Context, noteID string) error {
+	if !validKeyPart(noteID) {
+		return fmt.Errorf("invalid note id %q", noteID)
+	}
+	return r.store.deletePrefix(ctx, noteID+"/")
+}
+
+// objectKey формирует ключ содержимого вложения
+// Части ключа проверяются, чтобы ID не мог выйти за пределы хранилища (например, "../")
+func objectKey(noteID, id string) (string, error) {
+	if !validKeyPart(noteID) || !validKeyPart(id) {
+		return "", fmt.Errorf("invalid attachment key %q/%q", noteID, id)
+	}
+	return path.Join(noteID, id), nil
+}
+
+// validKeyPart проверяет, что строка может быть одним сегментом ключа
+func validKeyPart(part string) bool {
+	return part != "" && part != "." && part != ".." && !strings.ContainsAny(part, `/\`)
+}
@@ -0,0 +1,271 @@
+package attachments
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	"github.com/aws/smithy-go/encoding/httpbinding"
+
+	"notes-service/internal/repository"
+)
+
+const (
+	// unsignedPayload позволяет не хешировать тело запроса перед отправкой (потоковая загрузка)
+	unsignedPayload = "UNSIGNED-PAYLOAD"
+	// emptyPayloadHash SHA-256 пустого тела
+	emptyPayloadHash = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+)
+
+// S3Config параметры подключения к S3-совместимому хранилищу (AWS S3, MinIO)
+type S3Config struct {
+	Endpoint     string // Адрес хранилища, по умолчанию https://s3.<region>.amazonaws.com
+	Region       string // Регион (для MinIO обычно us-east-1)
+	Bucket       string // Имя бакета
+	Prefix       string // Префикс ключей вложений внутри бакета
+	AccessKey    string // Ключ доступа
+	SecretKey    string // Секретный ключ
+	UsePathStyle bool   // Адресация <endpoint>/<bucket>/<key> вместо <bucket>.<endpoint>/<key>
+
+	// HTTPClient отправляет запросы к хранилищу (по умолчанию http.Client без таймаута)
+	HTTPClient *http.Client
+}
+
+// s3Store хранит объекты в бакете S3 через REST API с подписью SigV4 (signer из AWS SDK)
+type s3Store struct {
+	cfg      S3Config
+	endpoint *url.URL
+	client   *http.Client
+	signer   *v4.Signer
+	now      func() time.Time
+}
+
+// NewS3Repository создает хранилище вложений в бакете S3
+func NewS3Repository(cfg S3Config) (repository.AttachmentRepository, error) {
+	store, err := newS3Store(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &repo{store: store}, nil
+}
+
+// newS3Store проверяет конфигурацию и создает клиент бакета
+func newS3Store(cfg S3Config) (*s3Store, error) {
+	if cfg.Bucket == "" {
+		return nil, errors.New("s3 bucket is not configured")
+	}
+	if cfg.Region == "" {
+		cfg.Region = "us-east-1"
+	}
+	if cfg.Endpoint == "" {
+		cfg.Endpoint = "https://s3." + cfg.Region + ".amazonaws.com"
+	}
+
+	endpoint, err := url.Parse(cfg.Endpoint)
+	if err != nil || endpoint.Host == "" {
+		return nil, fmt.Errorf("invalid s3 endpoint %q", cfg.Endpoint)
+	}
+
+	client := cfg.HTTPClient
+	if client == nil {
+		client = &http.Client{}
+	}
+
+	return &s3Store{
+		cfg:      cfg,
+		endpoint: endpoint,
+		client:   client,
+		signer: v4.NewSigner(func(o *v4.SignerOptions) {
+			// Путь объекта уже закодирован в newRawRequest, S3 не кодирует его повторно
+			o.DisableURIPathEscaping = true
+		}),
+		now: time.Now,
+	}, nil
+}
+
+// put загружает объект запросом PutObject
+func (s *s3Store) put(ctx context.Context, key string, data io.Reader, size int64, contentType string) error {
+	req, err := s.newRequest(ctx, http.MethodPut, key, nil, io.NopCloser(data))
+	if err != nil {
+		return err
+	}
+	req.ContentLength = size
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	resp, err := s.do(req, unsignedPayload)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+// get скачивает объект запросом GetObject
+func (s *s3Store) get(ctx context.Context, key string) (io.ReadCloser, error) {
+	req, err := s.newRequest(ctx, http.MethodGet, key, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.do(req, emptyPayloadHash)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+// listResult ответ ListObjectsV2
+type listResult struct {
+	Contents []struct {
+		Key string `xml:"Key"`
+	} `xml:"Contents"`
+	IsTruncated           bool   `xml:"IsTruncated"`
+	NextContinuationToken string `xml:"NextContinuationToken"`
+}
+
+// deletePrefix перечисляет объекты с префиксом (ListObjectsV2) и удаляет их по одному
+func (s *s3Store) deletePrefix(ctx context.Context, prefix string) error {
+	return s.listObjects(ctx, prefix, func(fullKey string) error {
+		return s.deleteObject(ctx, fullKey)
+	})
+}
+
+// list перечисляет объекты с префиксом (ListObjectsV2) и возвращает их ключи без префикса бакета
+func (s *s3Store) list(ctx context.Context, prefix string) ([]string, error) {
+	var keys []string
+	err := s.listObjects(ctx, prefix, func(fullKey string) error {
+		keys = append(keys, strings.TrimPrefix(fullKey, s.cfg.Prefix))
+		return nil
+	})
+	return keys, err
+}
+
+// delete удаляет объект запросом DeleteObject
+func (s *s3Store) delete(ctx context.Context, key string) error {
+	return s.deleteObject(ctx, s.cfg.Prefix+key)
+}
+
+// listObjects вызывает fn для полного ключа каждого объекта с префиксом, запрашивая список страницами
+// S3 возвращает ключи в порядке возрастания
+func (s *s3Store) listObjects(ctx context.Context, prefix string, fn func(fullKey string) error) error {
+	token := ""
+	for {
+		query := url.Values{"list-type": {"2"}, "prefix": {s.cfg.Prefix + prefix}}
+		if token != "" {
+			query.Set("continuation-token", token)
+		}
+
+		req, err := s.newRequest(ctx, http.MethodGet, "", query, nil)
+		if err != nil {
+			return err
+		}
+		resp, err := s.do(req, emptyPayloadHash)
+		if err != nil {
+			return err
+		}
+
+		var result listResult
+		err = xml.NewDecoder(resp.Body).Decode(&result)
+		resp.Body.Close()
+		if err != nil {
+			return fmt.Errorf("failed to decode s3 list response: %w", err)
+		}
+
+		for _, object := range result.Contents {
+			if err := fn(object.Key); err != nil {
+				return err
+			}
+		}
+
+		if !result.IsTruncated {
+			return nil
+		}
+		token = result.NextContinuationToken
+	}
+}
+
+// deleteObject удаляет объект по полному ключу (с префиксом)
+func (s *s3Store) deleteObject(ctx context.Context, fullKey string) error {
+	req, err := s.newRawRequest(ctx, http.MethodDelete, fullKey, nil, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := s.do(req, emptyPayloadHash)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+// newRequest создает запрос к объекту key (без префикса) или к бакету, если key пуст
+func (s *s3Store) newRequest(ctx context.Context, method, key string, query url.Values, body io.ReadCloser) (*http.Request, error) {
+	if key != "" {
+		key = s.cfg.Prefix + key
+	}
+	return s.newRawRequest(ctx, method, key, query, body)
+}
+
+// newRawRequest создает запрос к объекту с полным ключом
+func (s *s3Store) newRawRequest(ctx context.Context, method, fullKey string, query url.Values, body io.ReadCloser) (*http.Request, error) {
+	u := *s.endpoint
+	objectPath := "/" + httpbinding.EscapePath(fullKey, false)
+	if s.cfg.UsePathStyle {
+		objectPath = "/" + s.cfg.Bucket
+		if fullKey != "" {
+			objectPath += "/" + httpbinding.EscapePath(fullKey, false)
+		}
+	} else {
+		u.Host = s.cfg.Bucket + "." + u.Host
+	}
+	u.Path = strings.TrimSuffix(u.Path, "/")
+	u.RawPath = u.Path + objectPath
+	u.Path, _ = url.PathUnescape(u.RawPath)
+	u.RawQuery = strings.ReplaceAll(query.Encode(), "+", "%20")
+
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), body)
+	if err != nil {
+		return nil, err
+	}
+	return req, nil
+}
+
+// sign подписывает запрос; payloadHash - SHA-256 тела или unsignedPayload
+func (s *s3Store) sign(req *http.Request, payloadHash string) error {
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	creds := aws.Credentials{AccessKeyID: s.cfg.AccessKey, SecretAccessKey: s.cfg.SecretKey}
+	return s.signer.SignHTTP(req.Context(), creds, req, payloadHash, "s3", s.cfg.Region, s.now())
+}
+
+// do подписывает и выполняет запрос, преобразуя ответы с ошибкой в error
+func (s *s3Store) do(req *http.Request, payloadHash string) (*http.Response, error) {
+	if err := s.sign(req, payloadHash); err != nil {
+		return nil, fmt.Errorf("failed to sign s3 request: %w", err)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("s3 request failed: %w", err)
+	}
+
+	if resp.StatusCode == http.StatusNotFound {
+		resp.Body.Close()
+		return nil, ErrAttachmentNotFound
+	}
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		resp.Body.Close()
+		return nil, fmt.Errorf("s3 %s %s: status %d: %s", req.Method, req.URL.Path, resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,55 @@
+package degraded
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Описания метрик состояния хранилища и снимка
+var (
+	degradedDesc = prometheus.NewDesc("notes_repository_degraded",
+		"Whether the note repository is unavailable and reads are served from the snapshot.", nil, nil)
+	degradedSinceDesc = prometheus.NewDesc("notes_repository_degraded_since_timestamp_seconds",
+		"Time the current degraded mode started, 0 when the repository is available.", nil, nil)
+	snapshotAtDesc = prometheus.NewDesc("notes_repository_snapshot_timestamp_seconds",
+		"Time of the last full refresh of the note snapshot.", nil, nil)
+	snapshotNotesDesc = prometheus.NewDesc("notes_repository_snapshot_notes",
+		"Notes in the snapshot.", nil, nil)
+	transitionsDesc = prometheus.NewDesc("notes_repository_degraded_transitions_total",
+		"Times the note repository entered degraded mode.", nil, nil)
+	healthChecksDesc = prometheus.NewDesc("notes_repository_health_checks_total",
+		"Note repository health checks by result.", []string{"result"}, nil)
+)
+
+// Describe передает описания метрик режима деградации (Monitor реализует prometheus.Collector)
+func (m *Monitor) Describe(ch chan<- *prometheus.Desc) {
+	for _, desc := range []*prometheus.Desc{degradedDesc, degradedSinceDesc, snapshotAtDesc, snapshotNotesDesc, transitionsDesc, healthChecksDesc} {
+		ch <- desc
+	}
+}
+
+// Collect передает метрики состояния хранилища и снимка по текущему состоянию (Status)
+func (m *Monitor) Collect(ch chan<- prometheus.Metric) {
+	status := m.Status()
+
+	degraded := 0.0
+	if status.Degraded {
+		degraded = 1
+	}
+	ch <- prometheus.MustNewConstMetric(degradedDesc, prometheus.GaugeValue, degraded)
+	ch <- prometheus.MustNewConstMetric(degradedSinceDesc, prometheus.GaugeValue, unixSeconds(status.Since))
+	ch <- prometheus.MustNewConstMetric(snapshotAtDesc, prometheus.GaugeValue, unixSeconds(status.SnapshotAt))
+	ch <- prometheus.MustNewConstMetric(snapshotNotesDesc, prometheus.GaugeValue, float64(status.SnapshotNotes))
+	ch <- prometheus.MustNewConstMetric(transitionsDesc, prometheus.CounterValue, float64(status.Transitions))
+	ch <- prometheus.MustNewConstMetric(healthChecksDesc, prometheus.CounterValue, float64(status.ChecksOK), "success")
+	ch <- prometheus.MustNewConstMetric(healthChecksDesc, prometheus.CounterValue, float64(status.ChecksFailed), "failure")
+}
+
+// unixSeconds возвращает время в секундах Unix, для нулевого времени - 0
+func unixSeconds(t time.Time) float64 {
+	if t.IsZero() {
+		return 0
+	}
+	return float64(t.UnixNano()) / float64(time.Second)
+}
@@ -0,0 +1,342 @@
+// Package degraded переводит хранилище заметок в режим деградации, пока оно недоступно:
+// чтение выполняется из снимка заметок в памяти с предупреждением STALE_READ,
+// запись отклоняется ошибкой UnavailableError. Режим включается и выключается
+// по результатам периодических проверок хранилища (см. Monitor.Run)
+package degraded
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"notes-service/internal/model"
+	"notes-service/internal/repository"
+	"notes-service/internal/repository/memory"
+	svc "notes-service/internal/service"
+)
+
+const (
+	// DefaultCheckInterval интервал проверки хранилища по умолчанию
+	DefaultCheckInterval = 5 * time.Second
+
+	// DefaultFailureThreshold количество неудачных проверок подряд, после которого включается режим деградации
+	DefaultFailureThreshold = 3
+
+	// DefaultSnapshotInterval интервал обновления снимка заметок по умолчанию
+	DefaultSnapshotInterval = time.Minute
+)
+
+// UnavailableError операция отклонена, потому что хранилище недоступно
+type UnavailableError struct {
+	Since      time.Time     // Начало режима деградации
+	RetryAfter time.Duration // Через сколько повторить запрос (интервал проверки хранилища)
+}
+
+func (e *UnavailableError) Error() string {
+	return fmt.Sprintf("repository is unavailable since %s", e.Since.UTC().Format(time.RFC3339))
+}
+
+// Unwrap позволяет проверять ошибку через errors.Is(err, repository.ErrUnavailable)
+func (e *UnavailableError) Unwrap() error {
+	return repository.ErrUnavailable
+}
+
+// Status состояние хранилища и снимка
+type Status struct {
+	Degraded      bool      // Хранилище недоступно, чтение выполняется из снимка
+	Since         time.Time // Начало режима деградации (нулевое время, если хранилище доступно)
+	LastCheckAt   time.Time // Время последней проверки
+	LastError     error     // Ошибка последней неудачной проверки или операции
+	SnapshotAt    time.Time // Время последнего полного обновления снимка
+	SnapshotNotes int       // Количество заметок в снимке
+	Transitions   int64     // Сколько раз включался режим деградации
+	ChecksOK      int64     // Успешные проверки
+	ChecksFailed  int64     // Неудачные проверки
+}
+
+// Monitor следит за доступностью хранилища заметок и хранит снимок заметок для режима деградации
+// Снимок полностью обновляется из хранилища раз в интервал снимка и дополняется
+// результатами успешных операций между обновлениями
+type Monitor struct {
+	inner            repository.NoteRepository
+	checkInterval    time.Duration
+	snapshotInterval time.Duration
+	failureThreshold int
+	now              func() time.Time
+
+	mu            sync.RWMutex
+	snapshot      repository.NoteRepository // Заметки всех владельцев (memory), владелец учитывается при чтении
+	snapshotAt    time.Time
+	snapshotNotes int
+	degraded      bool
+	since         time.Time
+	failures      int // Неудачные проверки подряд
+	lastCheckAt   time.Time
+	lastError     error
+	transitions   int64
+	checksOK      int64
+	checksFailed  int64
+}
+
+// Option настраивает Monitor
+type Option func(*Monitor)
+
+// WithCheckInterval задает интервал проверки хранилища (по умолчанию DefaultCheckInterval)
+// Интервал также ограничивает время одной проверки и передается клиентам как время до повтора записи
+func WithCheckInterval(interval time.Duration) Option {
+	return func(m *Monitor) {
+		if interval > 0 {
+			m.checkInterval = interval
+		}
+	}
+}
+
+// WithFailureThreshold задает количество неудачных проверок подряд, после которого
+// включается режим деградации (по умолчанию DefaultFailureThreshold)
+// Операция, вернувшая repository.ErrUnavailable, включает режим сразу
+func WithFailureThreshold(threshold int) Option {
+	return func(m *Monitor) {
+		if threshold > 0 {
+			m.failureThreshold = threshold
+		}
+	}
+}
+
+// WithSnapshotInterval задает интервал полного обновления снимка (по умолчанию DefaultSnapshotInterval)
+func WithSnapshotInterval(interval time.Duration) Option {
+	return func(m *Monitor) {
+		if interval > 0 {
+			m.snapshotInterval = interval
+		}
+	}
+}
+
+// WithClock задает источник времени (по умолчанию time.Now)
+func WithClock(now func() time.Time) Option {
+	return func(m *Monitor) {
+		m.now = now
+	}
+}
+
+// NewMonitor создает монитор хранилища inner. Снимок пуст до первой проверки (см. Run)
+func NewMonitor(inner repository.NoteRepository, opts ...Option) *Monitor {
+	m := &Monitor{
+		inner:            inner,
+		checkInterval:    DefaultCheckInterval,
+		snapshotInterval: DefaultSnapshotInterval,
+		failureThreshold: DefaultFailureThreshold,
+		now:              time.Now,
+		snapshot:         memory.NewRepository(),
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
+}
+
+// Repository возвращает хранилище заметок, переходящее в режим деградации вместе с монитором
+// Атомарные пакетные операции доступны, если их поддерживает вложенное хранилище
+func (m *Monitor) Repository() repository.NoteRepository {
+	r := &repo{m: m}
+	if batch, ok := m.inner.(repository.BatchNoteRepository); ok {
+		return &batchRepo{repo: r, batch: batch}
+	}
+	return r
+}
+
+// Run проверяет хранилище раз в интервал проверки, пока не отменен ctx
+// Первая проверка выполняется сразу и заполняет снимок
+func (m *Monitor) Run(ctx context.Context) error {
+	ticker := time.NewTicker(m.checkInterval)
+	defer ticker.Stop()
+
+	for {
+		m.Check(ctx)
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Check проверяет хранилище и обновляет снимок, если он устарел или хранилище было недоступно
+// Хранилище проверяется через repository.HealthChecker, а без него - чтением снимка
+func (m *Monitor) Check(ctx context.Context) error {
+	checkCtx, cancel := context.WithTimeout(ctx, m.checkInterval)
+	defer cancel()
+
+	var err error
+	if checker, ok := m.inner.(repository.HealthChecker); ok {
+		err = checker.Ping(checkCtx)
+	}
+	if err == nil && m.snapshotDue() {
+		err = m.refresh(checkCtx)
+	}
+	if err != nil && ctx.Err() != nil {
+		// Проверка прервана остановкой сервера, а не недоступностью хранилища
+		return err
+	}
+	m.recordCheck(err)
+	return err
+}
+
+// snapshotDue сообщает, нужно ли полностью обновить снимок
+// В режиме деградации снимок обновляется при каждой проверке: это и есть проверка доступности
+func (m *Monitor) snapshotDue() bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.degraded || m.snapshotAt.IsZero() || m.now().Sub(m.snapshotAt) >= m.snapshotInterval
+}
+
+// refresh заменяет снимок заметками всех владельцев из хранилища
+func (m *Monitor) refresh(ctx context.Context) error {
+	notes, err := m.inner.List(repository.WithoutOwner(ctx))
+	if err != nil {
+		return err
+	}
+
+	snapshot := memory.NewRepository()
+	restorer := snapshot.(repository.NoteRestorer)
+	for _, note := range notes {
+		if err := restorer.Restore(ctx, note); err != nil {
+			return err
+		}
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.snapshot = snapshot
+	m.snapshotAt = m.now()
+	m.snapshotNotes = len(notes)
+	return nil
+}
+
+// recordCheck учитывает результат проверки и включает или выключает режим деградации
+func (m *Monitor) recordCheck(err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.lastCheckAt = m.now()
+	if err == nil {
+		m.checksOK++
+		m.failures = 0
+		if m.degraded {
+			log.Printf("✅ Note repository is available again after %s, leaving degraded mode",
+				m.now().Sub(m.since).Round(time.Second))
+			m.degraded = false
+			m.since = time.Time{}
+		}
+		return
+	}
+
+	m.checksFailed++
+	m.failures++
+	m.lastError = err
+	if !m.degraded && m.failures >= m.failureThreshold {
+		m.enter(err)
+	}
+}
+
+// enter включает режим деградации; вызывается под m.mu
+func (m *Monitor) enter(err error) {
+	m.degraded = true
+	m.since = m.now()
+	m.transitions++
+	log.Printf("⚠️  Note repository is unavailable (%v), entering degraded mode: reads are served from the snapshot of %s, writes are rejected",
+		err, m.snapshotAt.UTC().Format(time.RFC3339))
+}
+
+// failed сообщает, что операция не выполнена из-за недоступности хранилища,
+// и в этом случае сразу включает режим деградации
+func (m *Monitor) failed(err error) bool {
+	if !errors.Is(err, repository.ErrUnavailable) {
+		return false
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.lastError = err
+	if !m.degraded {
+		m.enter(err)
+	}
+	return true
+}
+
+// Status возвращает состояние хранилища и снимка
+func (m *Monitor) Status() Status {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return Status{
+		Degraded:      m.degraded,
+		Since:         m.since,
+		LastCheckAt:   m.lastCheckAt,
+		LastError:     m.lastError,
+		SnapshotAt:    m.snapshotAt,
+		SnapshotNotes: m.snapshotNotes,
+		Transitions:   m.transitions,
+		ChecksOK:      m.checksOK,
+		ChecksFailed:  m.checksFailed,
+	}
+}
+
+// current возвращает снимок для чтения в режиме деградации и добавляет к запросу предупреждение STALE_READ
+// Если снимок еще ни разу не заполнялся, чтение отклоняется, как и запись
+func (m *Monitor) current(ctx context.Context) (repository.NoteRepository, error) {
+	m.mu.RLock()
+	snapshot, snapshotAt := m.snapshot, m.snapshotAt
+	m.mu.RUnlock()
+
+	if snapshotAt.IsZero() {
+		return nil, m.unavailable()
+	}
+	svc.AddWarnings(ctx, model.Warning{
+		Code:    model.WarningStaleRead,
+		Message: fmt.Sprintf("note storage is unavailable, data may be stale: served from the snapshot of %s", snapshotAt.UTC().Format(time.RFC3339)),
+	})
+	return snapshot, nil
+}
+
+// isDegraded сообщает, включен ли режим деградации
+func (m *Monitor) isDegraded() bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.degraded
+}
+
+// unavailable возвращает ошибку операции, отклоненной в режиме деградации
+func (m *Monitor) unavailable() error {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return &UnavailableError{Since: m.since, RetryAfter: m.checkInterval}
+}
+
+// remember сохраняет в снимок результат успешной операции
+func (m *Monitor) remember(ctx context.Context, notes ...model.Note) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	ctx = repository.WithoutOwner(ctx)
+	for _, note := range notes {
+		if _, err := m.snapshot.GetByID(ctx, note.ID); err != nil {
+			m.snapshotNotes++
+		}
+		m.snapshot.(repository.NoteRestorer).Restore(ctx, note)
+	}
+}
+
+// forget удаляет из снимка удаленные заметки
+func (m *Monitor) forget(ctx context.Context, ids ...string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	ctx = repository.WithoutOwner(ctx)
+	for _, id := range ids {
+		if err := m.snapshot.Delete(ctx, id); err == nil {
+			m.snapshotNotes--
+		}
+	}
+}
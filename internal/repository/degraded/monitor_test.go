@@ -0,0 +1,171 @@
+package degraded
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"notes-service/internal/model"
+	"notes-service/internal/repository"
+	"notes-service/internal/repository/memory"
+	svc "notes-service/internal/service"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+// flakyRepository хранилище в памяти, которое можно сделать недоступным
+type flakyRepository struct {
+	repository.NoteRepository
+	down bool
+}
+
+func (r *flakyRepository) Ping(ctx context.Context) error {
+	if r.down {
+		return repository.ErrUnavailable
+	}
+	return nil
+}
+
+func (r *flakyRepository) GetByID(ctx context.Context, id string) (model.Note, error) {
+	if r.down {
+		return model.Note{}, repository.ErrUnavailable
+	}
+	return r.NoteRepository.GetByID(ctx, id)
+}
+
+func (r *flakyRepository) List(ctx context.Context) ([]model.Note, error) {
+	if r.down {
+		return nil, repository.ErrUnavailable
+	}
+	return r.NoteRepository.List(ctx)
+}
+
+func (r *flakyRepository) Create(ctx context.Context, note model.Note) (model.Note, error) {
+	if r.down {
+		return model.Note{}, repository.ErrUnavailable
+	}
+	return r.NoteRepository.Create(ctx, note)
+}
+
+func TestMonitor_EntersAndLeavesDegradedMode(t *testing.T) {
+	ctx := repository.WithOwner(context.Background(), "alice")
+	inner := &flakyRepository{NoteRepository: memory.NewRepository()}
+	m := NewMonitor(inner, WithFailureThreshold(2), WithCheckInterval(time.Second))
+	r := m.Repository()
+
+	created, err := r.Create(ctx, model.Note{Title: "Before outage"})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if err := m.Check(ctx); err != nil {
+		t.Fatalf("Expected successful check, got: %v", err)
+	}
+
+	inner.down = true
+	m.Check(ctx)
+	if m.Status().Degraded {
+		t.Fatal("Expected degraded mode only after the failure threshold")
+	}
+	m.Check(ctx)
+	status := m.Status()
+	if !status.Degraded || status.Transitions != 1 || status.ChecksFailed != 2 {
+		t.Fatalf("Expected degraded mode after 2 failed checks, got %+v", status)
+	}
+
+	// Чтение выполняется из снимка с предупреждением
+	readCtx, warnings := svc.WithWarnings(ctx)
+	note, err := r.GetByID(readCtx, created.ID)
+	if err != nil {
+		t.Fatalf("Expected read from the snapshot, got: %v", err)
+	}
+	if note.Title != "Before outage" {
+		t.Errorf("Expected note from the snapshot, got %+v", note)
+	}
+	if list := warnings.List(); len(list) != 1 || list[0].Code != model.WarningStaleRead {
+		t.Errorf("Expected STALE_READ warning, got %+v", list)
+	}
+
+	// Запись отклоняется с временем до повтора
+	_, err = r.Create(ctx, model.Note{Title: "During outage"})
+	var unavailableErr *UnavailableError
+	if !errors.As(err, &unavailableErr) || !errors.Is(err, repository.ErrUnavailable) {
+		t.Fatalf("Expected UnavailableError, got: %v", err)
+	}
+	if unavailableErr.RetryAfter != time.Second {
+		t.Errorf("Expected retry after the check interval, got %s", unavailableErr.RetryAfter)
+	}
+
+	inner.down = false
+	if err := m.Check(ctx); err != nil {
+		t.Fatalf("Expected successful check, got: %v", err)
+	}
+	if m.Status().Degraded {
+		t.Fatal("Expected degraded mode to end after a successful check")
+	}
+	readCtx, warnings = svc.WithWarnings(ctx)
+	if _, err := r.GetByID(readCtx, created.ID); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if list := warnings.List(); len(list) != 0 {
+		t.Errorf("Expected no warnings after recovery, got %+v", list)
+	}
+}
+
+func TestMonitor_UnavailableOperationEntersImmediately(t *testing.T) {
+	ctx := repository.WithOwner(context.Background(), "alice")
+	inner := &flakyRepository{NoteRepository: memory.NewRepository()}
+	m := NewMonitor(inner)
+	r := m.Repository()
+
+	// Снимок еще не заполнен: чтение отклоняется, как и запись
+	inner.down = true
+	if _, err := r.List(ctx); !errors.Is(err, repository.ErrUnavailable) {
+		t.Fatalf("Expected ErrUnavailable without a snapshot, got: %v", err)
+	}
+	if !m.Status().Degraded {
+		t.Fatal("Expected degraded mode after an unavailable operation")
+	}
+
+	inner.down = false
+	if err := m.Check(ctx); err != nil {
+		t.Fatalf("Expected successful check, got: %v", err)
+	}
+	created, err := r.Create(ctx, model.Note{Title: "Written through"})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	// Заметка, созданная после обновления снимка, тоже читается в режиме деградации
+	inner.down = true
+	notes, err := r.List(ctx)
+	if err != nil {
+		t.Fatalf("Expected read from the snapshot, got: %v", err)
+	}
+	if len(notes) != 1 || notes[0].ID != created.ID {
+		t.Errorf("Expected the created note in the snapshot, got %+v", notes)
+	}
+}
+
+func TestMonitor_Collect(t *testing.T) {
+	inner := &flakyRepository{NoteRepository: memory.NewRepository(), down: true}
+	m := NewMonitor(inner, WithFailureThreshold(1))
+	m.Check(context.Background())
+
+	want := `# HELP notes_repository_degraded Whether the note repository is unavailable and reads are served from the snapshot.
+# TYPE notes_repository_degraded gauge
+notes_repository_degraded 1
+# HELP notes_repository_degraded_transitions_total Times the note repository entered degraded mode.
+# TYPE notes_repository_degraded_transitions_total counter
+notes_repository_degraded_transitions_total 1
+# HELP notes_repository_health_checks_total Note repository health checks by result.
+# TYPE notes_repository_health_checks_total counter
+notes_repository_health_checks_total{result="failure"} 1
+notes_repository_health_checks_total{result="success"} 0
+`
+	if err := testutil.CollectAndCompare(m, strings.NewReader(want), "notes_repository_degraded",
+		"notes_repository_degraded_transitions_total", "notes_repository_health_checks_total"); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,271 @@
+package degraded
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"notes-service/internal/model"
+	"notes-service/internal/repository"
+)
+
+var (
+	_ repository.NoteRepository      = (*repo)(nil)
+	_ repository.NoteIterator        = (*repo)(nil)
+	_ repository.SortedNoteLister    = (*repo)(nil)
+	_ repository.TagIndex            = (*repo)(nil)
+	_ repository.NotePinner          = (*repo)(nil)
+	_ repository.BatchNoteRepository = (*batchRepo)(nil)
+)
+
+// repo передает операции вложенному хранилищу монитора, пока оно доступно
+// В режиме деградации (или если операция вернула repository.ErrUnavailable) чтение выполняется
+// из снимка, а запись возвращает UnavailableError
+type repo struct {
+	m *Monitor
+}
+
+// batchRepo добавляет атомарные пакетные операции, если их поддерживает вложенное хранилище
+type batchRepo struct {
+	*repo
+	batch repository.BatchNoteRepository
+}
+
+// read выполняет чтение из хранилища, а в режиме деградации - из снимка
+func read[T any](ctx context.Context, m *Monitor, fromRepository func() (T, error), fromSnapshot func(repository.NoteRepository) (T, error)) (T, error) {
+	if !m.isDegraded() {
+		result, err := fromRepository()
+		if !m.failed(err) {
+			return result, err
+		}
+	}
+
+	snapshot, err := m.current(ctx)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	return fromSnapshot(snapshot)
+}
+
+// write выполняет запись в хранилище и сохраняет ее результат в снимок
+// В режиме деградации запись отклоняется без обращения к хранилищу
+func write[T any](m *Monitor, fn func() (T, error), remember func(T)) (T, error) {
+	var zero T
+	if m.isDegraded() {
+		return zero, m.unavailable()
+	}
+	result, err := fn()
+	if m.failed(err) {
+		return zero, m.unavailable()
+	}
+	if err != nil {
+		return zero, err
+	}
+	remember(result)
+	return result, nil
+}
+
+// Create создает заметку в хранилище
+func (r *repo) Create(ctx context.Context, note model.Note) (model.Note, error) {
+	return write(r.m, func() (model.Note, error) {
+		return r.m.inner.Create(ctx, note)
+	}, func(created model.Note) {
+		r.m.remember(ctx, created)
+	})
+}
+
+// GetByID возвращает заметку по ID
+func (r *repo) GetByID(ctx context.Context, id string) (model.Note, error) {
+	return read(ctx, r.m, func() (model.Note, error) {
+		return r.m.inner.GetByID(ctx, id)
+	}, func(snapshot repository.NoteRepository) (model.Note, error) {
+		return snapshot.GetByID(ctx, id)
+	})
+}
+
+// List возвращает все заметки
+func (r *repo) List(ctx context.Context) ([]model.Note, error) {
+	return read(ctx, r.m, func() ([]model.Note, error) {
+		return r.m.inner.List(ctx)
+	}, func(snapshot repository.NoteRepository) ([]model.Note, error) {
+		return snapshot.List(ctx)
+	})
+}
+
+// Update обновляет заметку в хранилище
+func (r *repo) Update(ctx context.Context, note model.Note) (model.Note, error) {
+	return write(r.m, func() (model.Note, error) {
+		return r.m.inner.Update(ctx, note)
+	}, func(updated model.Note) {
+		r.m.remember(ctx, updated)
+	})
+}
+
+// Delete удаляет заметку из хранилища
+func (r *repo) Delete(ctx context.Context, id string) error {
+	_, err := write(r.m, func() (struct{}, error) {
+		return struct{}{}, r.m.inner.Delete(ctx, id)
+	}, func(struct{}) {
+		r.m.forget(ctx, id)
+	})
+	return err
+}
+
+// ForEach обходит заметки в порядке возрастания ID
+func (r *repo) ForEach(ctx context.Context, batchSize int, fn func(model.Note) error) error {
+	return r.ForEachAfter(ctx, "", batchSize, fn)
+}
+
+// ForEachAfter обходит заметки с ID больше after
+// Если хранилище стало недоступно во время обхода, обход не продолжается из снимка:
+// часть заметок уже передана fn, и снимок может их повторить или пропустить
+func (r *repo) ForEachAfter(ctx context.Context, after string, batchSize int, fn func(model.Note) error) error {
+	visited := false
+	visit := func(note model.Note) error {
+		visited = true
+		return fn(note)
+	}
+	_, err := read(ctx, r.m, func() (struct{}, error) {
+		if iterator, ok := r.m.inner.(repository.NoteIterator); ok {
+			return struct{}{}, iterator.ForEachAfter(ctx, after, batchSize, visit)
+		}
+		notes, err := r.m.inner.List(ctx)
+		if err != nil {
+			return struct{}{}, err
+		}
+		slices.SortFunc(notes, func(a, b model.Note) int {
+			return strings.Compare(a.ID, b.ID)
+		})
+		for _, note := range notes {
+			if note.ID <= after {
+				continue
+			}
+			if err := visit(note); err != nil {
+				return struct{}{}, err
+			}
+		}
+		return struct{}{}, nil
+	}, func(snapshot repository.NoteRepository) (struct{}, error) {
+		if visited {
+			return struct{}{}, r.m.unavailable()
+		}
+		return struct{}{}, snapshot.(repository.NoteIterator).ForEachAfter(ctx, after, batchSize, fn)
+	})
+	return err
+}
+
+// ListSorted возвращает заметки, упорядоченные компаратором cmp
+func (r *repo) ListSorted(ctx context.Context, cmp repository.NoteComparator) ([]model.Note, error) {
+	return read(ctx, r.m, func() ([]model.Note, error) {
+		if lister, ok := r.m.inner.(repository.SortedNoteLister); ok {
+			return lister.ListSorted(ctx, cmp)
+		}
+		notes, err := r.m.inner.List(ctx)
+		if err != nil {
+			return nil, err
+		}
+		slices.SortStableFunc(notes, cmp)
+		return notes, nil
+	}, func(snapshot repository.NoteRepository) ([]model.Note, error) {
+		return snapshot.(repository.SortedNoteLister).ListSorted(ctx, cmp)
+	})
+}
+
+// ListByTag возвращает заметки с тегом tag в порядке возрастания ID
+func (r *repo) ListByTag(ctx context.Context, tag string) ([]model.Note, error) {
+	return read(ctx, r.m, func() ([]model.Note, error) {
+		if index, ok := r.m.inner.(repository.TagIndex); ok {
+			return index.ListByTag(ctx, tag)
+		}
+		notes, err := r.m.inner.List(ctx)
+		if err != nil {
+			return nil, err
+		}
+		notes = slices.DeleteFunc(notes, func(note model.Note) bool {
+			_, found := slices.BinarySearch(note.Tags, tag)
+			return !found || note.IsE2E
+		})
+		slices.SortFunc(notes, func(a, b model.Note) int {
+			return strings.Compare(a.ID, b.ID)
+		})
+		return notes, nil
+	}, func(snapshot repository.NoteRepository) ([]model.Note, error) {
+		return snapshot.(repository.TagIndex).ListByTag(ctx, tag)
+	})
+}
+
+// ListTags возвращает все теги с количеством заметок, упорядоченные по тегу
+func (r *repo) ListTags(ctx context.Context) ([]model.TagCount, error) {
+	return read(ctx, r.m, func() ([]model.TagCount, error) {
+		if index, ok := r.m.inner.(repository.TagIndex); ok {
+			return index.ListTags(ctx)
+		}
+		notes, err := r.m.inner.List(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return countTags(notes), nil
+	}, func(snapshot repository.NoteRepository) ([]model.TagCount, error) {
+		return snapshot.(repository.TagIndex).ListTags(ctx)
+	})
+}
+
+// countTags считает заметки по тегам (без e2e заметок), упорядочивая по тегу
+func countTags(notes []model.Note) []model.TagCount {
+	counts := make(map[string]int)
+	for _, note := range notes {
+		if note.IsE2E {
+			continue
+		}
+		for _, tag := range note.Tags {
+			counts[tag]++
+		}
+	}
+
+	result := make([]model.TagCount, 0, len(counts))
+	for tag, count := range counts {
+		result = append(result, model.TagCount{Tag: tag, Count: count})
+	}
+	slices.SortFunc(result, func(a, b model.TagCount) int {
+		return strings.Compare(a.Tag, b.Tag)
+	})
+	return result
+}
+
+// SetPinned закрепляет или открепляет заметку
+// Если хранилище не поддерживает закрепление, заметка обновляется через Update
+func (r *repo) SetPinned(ctx context.Context, id string, pinned bool) (model.Note, error) {
+	return write(r.m, func() (model.Note, error) {
+		if pinner, ok := r.m.inner.(repository.NotePinner); ok {
+			return pinner.SetPinned(ctx, id, pinned)
+		}
+		note, err := r.m.inner.GetByID(ctx, id)
+		if err != nil || note.Pinned == pinned {
+			return note, err
+		}
+		note.Pinned = pinned
+		return r.m.inner.Update(ctx, note)
+	}, func(note model.Note) {
+		r.m.remember(ctx, note)
+	})
+}
+
+// CreateBatch атомарно создает заметки в хранилище
+func (r *batchRepo) CreateBatch(ctx context.Context, notes []model.Note) ([]model.Note, error) {
+	return write(r.m, func() ([]model.Note, error) {
+		return r.batch.CreateBatch(ctx, notes)
+	}, func(created []model.Note) {
+		r.m.remember(ctx, created...)
+	})
+}
+
+// DeleteBatch атомарно удаляет заметки из хранилища
+func (r *batchRepo) DeleteBatch(ctx context.Context, ids []string) error {
+	_, err := write(r.m, func() (struct{}, error) {
+		return struct{}{}, r.batch.DeleteBatch(ctx, ids)
+	}, func(struct{}) {
+		r.m.forget(ctx, ids...)
+	})
+	return err
+}
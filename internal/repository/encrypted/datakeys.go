@@ -0,0 +1,265 @@
+package encrypted
+
+import (
+	"context"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"notes-service/internal/model"
+	"notes-service/internal/repository"
+	"notes-service/internal/repository/memory"
+)
+
+const (
+	// dataKeyEnvelopePrefix признак значения, зашифрованного ключом данных владельца:
+	// enc:v2:<ID ключа данных>:<base64(nonce || шифротекст)>
+	dataKeyEnvelopePrefix = "enc:v2:"
+
+	// dataKeySize размер ключа данных (AES-256)
+	dataKeySize = 32
+
+	// dataKeyIDPrefix префикс ID ключей данных, отличает их от ID мастер-ключей в Note.KeyID
+	dataKeyIDPrefix = "dk-"
+)
+
+// DataKeys ключи данных владельцев заметок (envelope encryption)
+//
+// Содержимое заметок каждого владельца шифруется его текущим ключом данных, а ключи данных
+// хранятся зашифрованными основным мастер-ключом Keyring. Смена мастер-ключа требует
+// перешифровать только ключи данных (Rewrap), смена ключа данных владельца (Rotate) -
+// его заметки (см. Rotator). Прежние ключи данных не удаляются, ими расшифровываются
+// заметки и ревизии, записанные до смены. Расшифрованные ключи кэшируются в памяти процесса
+type DataKeys struct {
+	master *Keyring
+	repo   repository.DataKeyRepository
+	now    func() time.Time
+
+	mu      sync.Mutex
+	aeads   map[string]cipher.AEAD // ID ключа данных -> расшифрованный ключ
+	current map[string]string      // Владелец -> ID текущего ключа данных
+	loaded  bool                   // current заполнен из хранилища
+}
+
+// NewDataKeys создает ключи данных, хранящиеся в repo и зашифрованные мастер-ключами master
+func NewDataKeys(master *Keyring, repo repository.DataKeyRepository) *DataKeys {
+	return &DataKeys{
+		master:  master,
+		repo:    repo,
+		now:     time.Now,
+		aeads:   make(map[string]cipher.AEAD),
+		current: make(map[string]string),
+	}
+}
+
+// CurrentKeyID возвращает ID текущего ключа данных владельца (пусто, если ключа еще нет)
+func (k *DataKeys) CurrentKeyID(ctx context.Context, ownerID string) (string, error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if err := k.loadLocked(ctx); err != nil {
+		return "", err
+	}
+	return k.current[ownerID], nil
+}
+
+// Rotate создает новые текущие ключи данных владельца ownerID или, если он пуст, всех
+// владельцев, у которых уже есть ключ. Возвращает количество созданных ключей
+// Записанное прежними ключами содержимое остается читаемым до перешифрования
+func (k *DataKeys) Rotate(ctx context.Context, ownerID string) (int, error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if err := k.loadLocked(ctx); err != nil {
+		return 0, err
+	}
+
+	owners := []string{ownerID}
+	if ownerID == "" {
+		owners = make([]string, 0, len(k.current))
+		for owner := range k.current {
+			owners = append(owners, owner)
+		}
+		slices.Sort(owners)
+	}
+
+	for i, owner := range owners {
+		if _, _, err := k.createLocked(ctx, owner); err != nil {
+			return i, err
+		}
+	}
+	return len(owners), nil
+}
+
+// Rewrap перешифровывает основным мастер-ключом ключи данных, зашифрованные прежними
+// мастер-ключами. После этого прежние мастер-ключи можно убрать из конфигурации
+// (если не осталось заметок enc:v1). Возвращает количество перешифрованных ключей
+func (k *DataKeys) Rewrap(ctx context.Context) (int, error) {
+	keys, err := k.repo.List(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	rewrapped := 0
+	for _, key := range keys {
+		if id, _ := envelopeKeyID(key.Wrapped, envelopePrefix); id == k.master.primary {
+			continue
+		}
+
+		raw, err := k.master.open(key.Wrapped, wrapAAD(key.ID, key.OwnerID))
+		if err != nil {
+			return rewrapped, fmt.Errorf("data key %s: %w", key.ID, err)
+		}
+		if key.Wrapped, err = k.master.seal(raw, wrapAAD(key.ID, key.OwnerID)); err != nil {
+			return rewrapped, err
+		}
+		if err := k.repo.Update(ctx, key); err != nil {
+			return rewrapped, err
+		}
+		rewrapped++
+	}
+	return rewrapped, nil
+}
+
+// seal шифрует plaintext текущим ключом данных владельца (создает ключ, если его нет)
+// Возвращает шифротекст и ID ключа
+func (k *DataKeys) seal(ctx context.Context, ownerID, plaintext, aad string) (string, string, error) {
+	id, aead, err := k.currentKey(ctx, ownerID)
+	if err != nil {
+		return "", "", err
+	}
+
+	sealed, err := sealEnvelope(dataKeyEnvelopePrefix, id, aead, plaintext, aad)
+	if err != nil {
+		return "", "", err
+	}
+	return sealed, id, nil
+}
+
+// open расшифровывает значение, записанное seal или мастер-ключом до появления ключей данных
+// Возвращает открытый текст и ID ключа (пусто для значения, записанного открытым текстом)
+func (k *DataKeys) open(ctx context.Context, value, aad string) (string, string, error) {
+	envelope, ok := strings.CutPrefix(value, dataKeyEnvelopePrefix)
+	if !ok {
+		// enc:v1 (мастер-ключ) или открытый текст
+		id, _ := envelopeKeyID(value, envelopePrefix)
+		plaintext, err := k.master.open(value, aad)
+		return plaintext, id, err
+	}
+
+	id, encoded, ok := strings.Cut(envelope, ":")
+	if !ok {
+		return "", "", ErrDecrypt
+	}
+	aead, err := k.key(ctx, id)
+	if err != nil {
+		return "", "", err
+	}
+	plaintext, err := openEnvelope(aead, encoded, aad)
+	return plaintext, id, err
+}
+
+// currentKey возвращает текущий ключ данных владельца, создавая его при первом использовании
+func (k *DataKeys) currentKey(ctx context.Context, ownerID string) (string, cipher.AEAD, error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if err := k.loadLocked(ctx); err != nil {
+		return "", nil, err
+	}
+	if id, ok := k.current[ownerID]; ok {
+		aead, err := k.keyLocked(ctx, id)
+		return id, aead, err
+	}
+	return k.createLocked(ctx, ownerID)
+}
+
+// key возвращает расшифрованный ключ данных id
+func (k *DataKeys) key(ctx context.Context, id string) (cipher.AEAD, error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	return k.keyLocked(ctx, id)
+}
+
+// keyLocked возвращает ключ данных из кэша или расшифровывает его мастер-ключом
+func (k *DataKeys) keyLocked(ctx context.Context, id string) (cipher.AEAD, error) {
+	if aead, ok := k.aeads[id]; ok {
+		return aead, nil
+	}
+
+	key, err := k.repo.Get(ctx, id)
+	if errors.Is(err, memory.ErrDataKeyNotFound) {
+		return nil, fmt.Errorf("%w: unknown data key %s", ErrDecrypt, id)
+	}
+	if err != nil {
+		return nil, err
+	}
+	raw, err := k.master.open(key.Wrapped, wrapAAD(key.ID, key.OwnerID))
+	if err != nil {
+		return nil, fmt.Errorf("data key %s: %w", id, err)
+	}
+	aead, err := newAEAD([]byte(raw))
+	if err != nil {
+		return nil, err
+	}
+	k.aeads[id] = aead
+	return aead, nil
+}
+
+// createLocked создает новый текущий ключ данных владельца
+func (k *DataKeys) createLocked(ctx context.Context, ownerID string) (string, cipher.AEAD, error) {
+	raw := make([]byte, dataKeySize)
+	if _, err := rand.Read(raw); err != nil {
+		return "", nil, err
+	}
+	suffix := make([]byte, 8)
+	if _, err := rand.Read(suffix); err != nil {
+		return "", nil, err
+	}
+	id := dataKeyIDPrefix + hex.EncodeToString(suffix)
+
+	wrapped, err := k.master.seal(string(raw), wrapAAD(id, ownerID))
+	if err != nil {
+		return "", nil, err
+	}
+	aead, err := newAEAD(raw)
+	if err != nil {
+		return "", nil, err
+	}
+	if err := k.repo.Create(ctx, model.DataKey{ID: id, OwnerID: ownerID, Wrapped: wrapped, CreatedAt: k.now()}); err != nil {
+		return "", nil, err
+	}
+
+	k.aeads[id] = aead
+	k.current[ownerID] = id
+	return id, aead, nil
+}
+
+// loadLocked определяет текущие ключи владельцев по хранилищу при первом обращении
+func (k *DataKeys) loadLocked(ctx context.Context) error {
+	if k.loaded {
+		return nil
+	}
+
+	keys, err := k.repo.List(ctx)
+	if err != nil {
+		return err
+	}
+	// Ключи упорядочены по времени создания, текущим становится последний ключ владельца
+	for _, key := range keys {
+		k.current[key.OwnerID] = key.ID
+	}
+	k.loaded = true
+	return nil
+}
+
+// wrapAAD привязывает зашифрованный ключ данных к его ID и владельцу
+func wrapAAD(id, ownerID string) string {
+	return "data-key:" + id + ":" + ownerID
+}
@@ -0,0 +1,133 @@
+package encrypted
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// envelopePrefix признак зашифрованного значения: enc:v1:<ID ключа>:<base64(nonce || шифротекст)>
+// Значения без префикса считаются открытым текстом, записанным до включения шифрования
+const envelopePrefix = "enc:v1:"
+
+// ErrDecrypt возвращается, когда значение не удалось расшифровать: неизвестный ключ,
+// поврежденные данные или значение, перенесенное из другой заметки
+var ErrDecrypt = errors.New("failed to decrypt note content")
+
+// Keyring мастер-ключи AES-GCM: основным ключом шифруются ключи данных владельцев (DataKeys),
+// а прежними ключами расшифровываются значения, записанные до смены мастер-ключа.
+// До появления ключей данных мастер-ключом шифровалось само содержимое заметок (enc:v1)
+type Keyring struct {
+	primary string // ID ключа для шифрования
+	aeads   map[string]cipher.AEAD
+}
+
+// NewKeyring создает набор ключей из основного ключа и ключей, использовавшихся до него
+// Ключи должны быть длиной 16, 24 или 32 байта (AES-128, AES-192, AES-256)
+func NewKeyring(primary []byte, previous ...[]byte) (*Keyring, error) {
+	k := &Keyring{aeads: make(map[string]cipher.AEAD, len(previous)+1)}
+
+	for i, key := range append([][]byte{primary}, previous...) {
+		aead, err := newAEAD(key)
+		if err != nil {
+			return nil, err
+		}
+
+		id := keyID(key)
+		if i == 0 {
+			k.primary = id
+		}
+		k.aeads[id] = aead
+	}
+
+	return k, nil
+}
+
+// ParseKey декодирует ключ, заданный в конфигурации в base64
+func ParseKey(encoded string) ([]byte, error) {
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil {
+		return nil, fmt.Errorf("invalid encryption key: %w", err)
+	}
+	return key, nil
+}
+
+// keyID возвращает короткий идентификатор ключа, по которому выбирается ключ для расшифровки
+func keyID(key []byte) string {
+	sum := sha256.Sum256(key)
+	return hex.EncodeToString(sum[:4])
+}
+
+// seal шифрует plaintext основным ключом, aad привязывает шифротекст к записи (ID заметки)
+func (k *Keyring) seal(plaintext, aad string) (string, error) {
+	return sealEnvelope(envelopePrefix, k.primary, k.aeads[k.primary], plaintext, aad)
+}
+
+// open расшифровывает значение, записанное seal; открытый текст возвращается без изменений
+func (k *Keyring) open(value, aad string) (string, error) {
+	envelope, ok := strings.CutPrefix(value, envelopePrefix)
+	if !ok {
+		return value, nil
+	}
+
+	id, encoded, ok := strings.Cut(envelope, ":")
+	if !ok {
+		return "", ErrDecrypt
+	}
+	aead, ok := k.aeads[id]
+	if !ok {
+		return "", fmt.Errorf("%w: unknown key %s", ErrDecrypt, id)
+	}
+	return openEnvelope(aead, encoded, aad)
+}
+
+// sealEnvelope шифрует plaintext ключом aead и возвращает <prefix><id>:<base64(nonce || шифротекст)>
+func sealEnvelope(prefix, id string, aead cipher.AEAD, plaintext, aad string) (string, error) {
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(plaintext)+aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	sealed := aead.Seal(nonce, nonce, []byte(plaintext), []byte(aad))
+
+	return prefix + id + ":" + base64.RawStdEncoding.EncodeToString(sealed), nil
+}
+
+// openEnvelope расшифровывает base64(nonce || шифротекст) ключом aead
+func openEnvelope(aead cipher.AEAD, encoded, aad string) (string, error) {
+	sealed, err := base64.RawStdEncoding.DecodeString(encoded)
+	if err != nil || len(sealed) < aead.NonceSize() {
+		return "", ErrDecrypt
+	}
+	nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+
+	plaintext, err := aead.Open(nil, nonce, ciphertext, []byte(aad))
+	if err != nil {
+		return "", ErrDecrypt
+	}
+	return string(plaintext), nil
+}
+
+// envelopeKeyID возвращает ID ключа, которым зашифровано значение с префиксом prefix
+func envelopeKeyID(value, prefix string) (string, bool) {
+	envelope, ok := strings.CutPrefix(value, prefix)
+	if !ok {
+		return "", false
+	}
+	id, _, ok := strings.Cut(envelope, ":")
+	return id, ok
+}
+
+// newAEAD создает AES-GCM для ключа key
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("invalid encryption key: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,332 @@
+package encrypted
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+
+	"notes-service/internal/model"
+	"notes-service/internal/repository"
+	"notes-service/internal/repository/memory"
+
+	"github.com/google/uuid"
+)
+
+var (
+	_ repository.NoteRepository      = (*repo)(nil)
+	_ repository.NoteIterator        = (*repo)(nil)
+	_ repository.SortedNoteLister    = (*repo)(nil)
+	_ repository.TagIndex            = (*repo)(nil)
+	_ repository.NotePinner          = (*repo)(nil)
+	_ repository.BatchNoteRepository = (*batchRepo)(nil)
+)
+
+// repo шифрует содержимое заметок (Content) перед записью во вложенное хранилище
+// и расшифровывает его при чтении. Заголовок, теги и метаданные хранятся открыто,
+// чтобы сортировка, индекс тегов и фильтры хранилища продолжали работать
+//
+// Содержимое шифруется текущим ключом данных владельца заметки (см. DataKeys), ID ключа
+// сохраняется в Note.KeyID. Шифротекст привязан к ID заметки, поэтому содержимое нельзя
+// незаметно перенести в другую заметку. Содержимое e2e заметок уже зашифровано клиентом
+// и не изменяется
+type repo struct {
+	inner repository.NoteRepository
+	keys  *DataKeys
+}
+
+// batchRepo добавляет атомарные пакетные операции, если их поддерживает вложенное хранилище
+// Без них сервис отклоняет атомарные пакетные запросы, как и для вложенного хранилища
+type batchRepo struct {
+	*repo
+	batch repository.BatchNoteRepository
+}
+
+// NewRepository оборачивает хранилище заметок шифрованием содержимого ключами данных keys
+// Остальные опциональные расширения (NoteIterator, SortedNoteLister, TagIndex, NotePinner)
+// доступны всегда: если вложенное хранилище их не реализует, они выполняются через List и Update
+func NewRepository(inner repository.NoteRepository, keys *DataKeys) repository.NoteRepository {
+	r := &repo{inner: inner, keys: keys}
+	if batch, ok := inner.(repository.BatchNoteRepository); ok {
+		return &batchRepo{repo: r, batch: batch}
+	}
+	return r
+}
+
+// encrypt возвращает заметку с зашифрованным содержимым
+// ID назначается здесь, если его еще нет, потому что шифротекст привязан к ID
+func (r *repo) encrypt(ctx context.Context, note model.Note) (model.Note, error) {
+	if note.ID == "" {
+		note.ID = uuid.New().String()
+	}
+	note.KeyID = ""
+	if note.Content == "" {
+		return note, nil
+	}
+
+	ownerID, err := r.owner(ctx, note)
+	if err != nil {
+		return model.Note{}, err
+	}
+	sealed, keyID, err := r.keys.seal(ctx, ownerID, note.Content, note.ID)
+	if err != nil {
+		return model.Note{}, err
+	}
+	note.Content = sealed
+	note.KeyID = keyID
+	return note, nil
+}
+
+// owner возвращает владельца, ключом которого шифруется заметка: владельца из контекста
+// (им хранилище подменяет владельца заметки), владельца из самой заметки или, для обновления
+// без владельца, владельца сохраненной заметки
+func (r *repo) owner(ctx context.Context, note model.Note) (string, error) {
+	if ownerID, ok := repository.OwnerFromContext(ctx); ok {
+		return ownerID, nil
+	}
+	if note.OwnerID != "" {
+		return note.OwnerID, nil
+	}
+
+	stored, err := r.inner.GetByID(repository.WithoutOwner(ctx), note.ID)
+	if errors.Is(err, memory.ErrNoteNotFound) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return stored.OwnerID, nil
+}
+
+// decrypt возвращает заметку с расшифрованным содержимым и ID ключа, которым оно было зашифровано
+func (r *repo) decrypt(ctx context.Context, note model.Note) (model.Note, error) {
+	content, keyID, err := r.keys.open(ctx, note.Content, note.ID)
+	if err != nil {
+		return model.Note{}, err
+	}
+	note.Content = content
+	note.KeyID = keyID
+	return note, nil
+}
+
+// decryptAll расшифровывает заметки на месте
+func (r *repo) decryptAll(ctx context.Context, notes []model.Note) ([]model.Note, error) {
+	for i, note := range notes {
+		decrypted, err := r.decrypt(ctx, note)
+		if err != nil {
+			return nil, err
+		}
+		notes[i] = decrypted
+	}
+	return notes, nil
+}
+
+// Create шифрует содержимое и создает заметку во вложенном хранилище
+func (r *repo) Create(ctx context.Context, note model.Note) (model.Note, error) {
+	encrypted, err := r.encrypt(ctx, note)
+	if err != nil {
+		return model.Note{}, err
+	}
+
+	created, err := r.inner.Create(ctx, encrypted)
+	if err != nil {
+		return model.Note{}, err
+	}
+	return r.decrypt(ctx, created)
+}
+
+// GetByID возвращает заметку с расшифрованным содержимым
+func (r *repo) GetByID(ctx context.Context, id string) (model.Note, error) {
+	note, err := r.inner.GetByID(ctx, id)
+	if err != nil {
+		return model.Note{}, err
+	}
+	return r.decrypt(ctx, note)
+}
+
+// List возвращает все заметки с расшифрованным содержимым
+func (r *repo) List(ctx context.Context) ([]model.Note, error) {
+	notes, err := r.inner.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return r.decryptAll(ctx, notes)
+}
+
+// Update шифрует содержимое и обновляет заметку во вложенном хранилище
+func (r *repo) Update(ctx context.Context, note model.Note) (model.Note, error) {
+	encrypted, err := r.encrypt(ctx, note)
+	if err != nil {
+		return model.Note{}, err
+	}
+
+	updated, err := r.inner.Update(ctx, encrypted)
+	if err != nil {
+		return model.Note{}, err
+	}
+	return r.decrypt(ctx, updated)
+}
+
+// Delete удаляет заметку из вложенного хранилища
+func (r *repo) Delete(ctx context.Context, id string) error {
+	return r.inner.Delete(ctx, id)
+}
+
+// ForEach обходит заметки в порядке возрастания ID, расшифровывая содержимое
+func (r *repo) ForEach(ctx context.Context, batchSize int, fn func(model.Note) error) error {
+	return r.ForEachAfter(ctx, "", batchSize, fn)
+}
+
+// ForEachAfter обходит заметки с ID больше after, как ForEach
+func (r *repo) ForEachAfter(ctx context.Context, after string, batchSize int, fn func(model.Note) error) error {
+	decrypted := func(note model.Note) error {
+		note, err := r.decrypt(ctx, note)
+		if err != nil {
+			return err
+		}
+		return fn(note)
+	}
+
+	if iterator, ok := r.inner.(repository.NoteIterator); ok {
+		return iterator.ForEachAfter(ctx, after, batchSize, decrypted)
+	}
+
+	notes, err := r.inner.List(ctx)
+	if err != nil {
+		return err
+	}
+	slices.SortFunc(notes, func(a, b model.Note) int {
+		return strings.Compare(a.ID, b.ID)
+	})
+	for _, note := range notes {
+		if note.ID <= after {
+			continue
+		}
+		if err := decrypted(note); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ListSorted возвращает заметки, упорядоченные компаратором cmp
+// Компаратор вложенного хранилища получает заметки с зашифрованным содержимым,
+// поэтому сравнивать по содержимому нельзя (сервис сравнивает по заголовку)
+func (r *repo) ListSorted(ctx context.Context, cmp repository.NoteComparator) ([]model.Note, error) {
+	if lister, ok := r.inner.(repository.SortedNoteLister); ok {
+		notes, err := lister.ListSorted(ctx, cmp)
+		if err != nil {
+			return nil, err
+		}
+		return r.decryptAll(ctx, notes)
+	}
+
+	notes, err := r.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	slices.SortStableFunc(notes, cmp)
+	return notes, nil
+}
+
+// ListByTag возвращает заметки с тегом tag в порядке возрастания ID
+func (r *repo) ListByTag(ctx context.Context, tag string) ([]model.Note, error) {
+	if index, ok := r.inner.(repository.TagIndex); ok {
+		notes, err := index.ListByTag(ctx, tag)
+		if err != nil {
+			return nil, err
+		}
+		return r.decryptAll(ctx, notes)
+	}
+
+	notes, err := r.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	notes = slices.DeleteFunc(notes, func(note model.Note) bool {
+		_, found := slices.BinarySearch(note.Tags, tag)
+		return !found || note.IsE2E
+	})
+	slices.SortFunc(notes, func(a, b model.Note) int {
+		return strings.Compare(a.ID, b.ID)
+	})
+	return notes, nil
+}
+
+// ListTags возвращает все теги с количеством заметок, упорядоченные по тегу
+// Теги не шифруются, поэтому запрос передается вложенному хранилищу без изменений
+func (r *repo) ListTags(ctx context.Context) ([]model.TagCount, error) {
+	if index, ok := r.inner.(repository.TagIndex); ok {
+		return index.ListTags(ctx)
+	}
+
+	notes, err := r.inner.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	counts := make(map[string]int)
+	for _, note := range notes {
+		if note.IsE2E {
+			continue
+		}
+		for _, tag := range note.Tags {
+			counts[tag]++
+		}
+	}
+
+	result := make([]model.TagCount, 0, len(counts))
+	for tag, count := range counts {
+		result = append(result, model.TagCount{Tag: tag, Count: count})
+	}
+	slices.SortFunc(result, func(a, b model.TagCount) int {
+		return strings.Compare(a.Tag, b.Tag)
+	})
+	return result, nil
+}
+
+// SetPinned закрепляет или открепляет заметку
+// Если вложенное хранилище не поддерживает закрепление, заметка обновляется через Update
+func (r *repo) SetPinned(ctx context.Context, id string, pinned bool) (model.Note, error) {
+	if pinner, ok := r.inner.(repository.NotePinner); ok {
+		note, err := pinner.SetPinned(ctx, id, pinned)
+		if err != nil {
+			return model.Note{}, err
+		}
+		return r.decrypt(ctx, note)
+	}
+
+	note, err := r.inner.GetByID(ctx, id)
+	if err != nil {
+		return model.Note{}, err
+	}
+	if note.Pinned != pinned {
+		// Содержимое уже зашифровано, заметка записывается во вложенное хранилище как есть
+		note.Pinned = pinned
+		if note, err = r.inner.Update(ctx, note); err != nil {
+			return model.Note{}, err
+		}
+	}
+	return r.decrypt(ctx, note)
+}
+
+// CreateBatch шифрует содержимое и атомарно создает заметки во вложенном хранилище
+func (r *batchRepo) CreateBatch(ctx context.Context, notes []model.Note) ([]model.Note, error) {
+	encrypted := make([]model.Note, len(notes))
+	for i, note := range notes {
+		var err error
+		if encrypted[i], err = r.encrypt(ctx, note); err != nil {
+			return nil, err
+		}
+	}
+
+	created, err := r.batch.CreateBatch(ctx, encrypted)
+	if err != nil {
+		return nil, err
+	}
+	return r.decryptAll(ctx, created)
+}
+
+// DeleteBatch атомарно удаляет заметки из вложенного хранилища
+func (r *batchRepo) DeleteBatch(ctx context.Context, ids []string) error {
+	return r.batch.DeleteBatch(ctx, ids)
+}
@@ -0,0 +1,190 @@
+package encrypted
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"notes-service/internal/model"
+	"notes-service/internal/repository"
+	"notes-service/internal/repository/memory"
+	"notes-service/internal/repository/repositorytest"
+)
+
+// newTestKeyring создает набор ключей из основного ключа и прежних ключей, заполненных байтами fill
+func newTestKeyring(t *testing.T, fill byte, previous ...byte) *Keyring {
+	t.Helper()
+
+	var old [][]byte
+	for _, b := range previous {
+		old = append(old, bytes.Repeat([]byte{b}, 32))
+	}
+	keys, err := NewKeyring(bytes.Repeat([]byte{fill}, 32), old...)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	return keys
+}
+
+// newTestKeys создает ключи данных в хранилище repo, зашифрованные мастер-ключами newTestKeyring
+func newTestKeys(t *testing.T, repo repository.DataKeyRepository, fill byte, previous ...byte) *DataKeys {
+	t.Helper()
+	return NewDataKeys(newTestKeyring(t, fill, previous...), repo)
+}
+
+func TestRepository_EncryptsContentAtRest(t *testing.T) {
+	ctx := context.Background()
+	inner := memory.NewRepository()
+	r := NewRepository(inner, newTestKeys(t, memory.NewDataKeyRepository(), 1))
+
+	created, err := r.Create(repository.WithOwner(ctx, "alice"), model.Note{Title: "Secret", Content: "launch codes", Tags: []string{"ops"}})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if created.ID == "" || created.Content != "launch codes" {
+		t.Fatalf("Expected created note with plaintext content, got %+v", created)
+	}
+
+	stored, err := inner.GetByID(ctx, created.ID)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if !strings.HasPrefix(stored.Content, dataKeyEnvelopePrefix) || strings.Contains(stored.Content, "launch") {
+		t.Errorf("Expected encrypted content in inner repository, got %q", stored.Content)
+	}
+	if created.KeyID == "" || stored.KeyID != created.KeyID {
+		t.Errorf("Expected data key ID %q recorded in the stored note, got %q", created.KeyID, stored.KeyID)
+	}
+	if stored.Title != "Secret" {
+		t.Errorf("Expected plaintext title, got %q", stored.Title)
+	}
+
+	got, err := r.GetByID(ctx, created.ID)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if got.Content != "launch codes" {
+		t.Errorf("Expected decrypted content, got %q", got.Content)
+	}
+
+	// Опциональные расширения вложенного хранилища возвращают расшифрованные заметки
+	byTag, err := r.(repository.TagIndex).ListByTag(ctx, "ops")
+	if err != nil || len(byTag) != 1 || byTag[0].Content != "launch codes" {
+		t.Errorf("Expected decrypted note from ListByTag, got %+v (%v)", byTag, err)
+	}
+	if _, ok := r.(repository.BatchNoteRepository); !ok {
+		t.Error("Expected atomic batch support of the memory repository to be preserved")
+	}
+}
+
+func TestRepository_RejectsContentMovedBetweenNotes(t *testing.T) {
+	ctx := context.Background()
+	inner := memory.NewRepository()
+	r := NewRepository(inner, newTestKeys(t, memory.NewDataKeyRepository(), 1))
+
+	first, err := r.Create(ctx, model.Note{Title: "First", Content: "first secret"})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	second, err := r.Create(ctx, model.Note{Title: "Second", Content: "second secret"})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	// Шифротекст первой заметки, подмененный в хранилище, не расшифровывается во второй
+	stored, _ := inner.GetByID(ctx, first.ID)
+	victim, _ := inner.GetByID(ctx, second.ID)
+	victim.Content = stored.Content
+	if _, err := inner.Update(ctx, victim); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if _, err := r.GetByID(ctx, second.ID); !errors.Is(err, ErrDecrypt) {
+		t.Errorf("Expected ErrDecrypt, got: %v", err)
+	}
+}
+
+func TestRepository_MasterKeyRotationAndPlaintext(t *testing.T) {
+	ctx := context.Background()
+	inner := memory.NewRepository()
+	dataKeys := memory.NewDataKeyRepository()
+
+	legacy, err := inner.Create(ctx, model.Note{Title: "Legacy", Content: "written before encryption"})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	// Заметка, зашифрованная мастер-ключом до появления ключей данных
+	v1 := model.Note{ID: "note-v1", Title: "V1"}
+	if v1.Content, err = newTestKeyring(t, 1).seal("master key", v1.ID); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if _, err := inner.Create(ctx, v1); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	old, err := NewRepository(inner, newTestKeys(t, dataKeys, 1)).Create(ctx, model.Note{Title: "Old", Content: "old key", OwnerID: "alice"})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	rotated := NewRepository(inner, newTestKeys(t, dataKeys, 2, 1))
+	notes, err := rotated.List(ctx)
+	if err != nil {
+		t.Fatalf("Expected notes readable after master key rotation, got: %v", err)
+	}
+	contents := map[string]string{}
+	for _, note := range notes {
+		contents[note.ID] = note.Content
+	}
+	if contents[legacy.ID] != "written before encryption" || contents[v1.ID] != "master key" || contents[old.ID] != "old key" {
+		t.Errorf("Expected plaintext, v1 and data key notes to be readable, got %v", contents)
+	}
+
+	// Без прежнего мастер-ключа ключ данных не расшифровывается
+	if _, err := NewRepository(inner, newTestKeys(t, dataKeys, 2)).GetByID(ctx, old.ID); !errors.Is(err, ErrDecrypt) {
+		t.Errorf("Expected ErrDecrypt without previous master key, got: %v", err)
+	}
+}
+
+func TestRevisionRepository_EncryptsContent(t *testing.T) {
+	ctx := context.Background()
+	inner := memory.NewRevisionRepository()
+	r := NewRevisionRepository(inner, memory.NewRepository(), newTestKeys(t, memory.NewDataKeyRepository(), 1))
+
+	added, err := r.Add(ctx, model.NoteRevision{NoteID: "note-1", Title: "Title", Content: "draft"})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if added.Content != "draft" {
+		t.Errorf("Expected plaintext content, got %q", added.Content)
+	}
+
+	stored, err := inner.Get(ctx, "note-1", added.Revision)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if strings.Contains(stored.Content, "draft") {
+		t.Errorf("Expected encrypted revision content, got %q", stored.Content)
+	}
+
+	revisions, err := r.List(ctx, "note-1")
+	if err != nil || len(revisions) != 1 || revisions[0].Content != "draft" {
+		t.Errorf("Expected decrypted revision, got %+v (%v)", revisions, err)
+	}
+}
+
+func TestNewKeyring_InvalidKey(t *testing.T) {
+	if _, err := NewKeyring([]byte("short")); err == nil {
+		t.Error("Expected error for key of invalid length")
+	}
+	if _, err := ParseKey("not base64!"); err == nil {
+		t.Error("Expected error for key that is not base64")
+	}
+}
+
+func TestRepository_Conformance(t *testing.T) {
+	repositorytest.TestNoteRepository(t, func(t *testing.T) repository.NoteRepository {
+		return NewRepository(memory.NewRepository(), newTestKeys(t, memory.NewDataKeyRepository(), 1))
+	})
+}
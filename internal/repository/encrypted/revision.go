@@ -0,0 +1,99 @@
+package encrypted
+
+import (
+	"context"
+	"errors"
+
+	"notes-service/internal/model"
+	"notes-service/internal/repository"
+	"notes-service/internal/repository/memory"
+)
+
+// revisionRepo шифрует содержимое ревизий, иначе история изменений хранила бы
+// открытый текст зашифрованных заметок
+//
+// Ревизия шифруется текущим ключом данных владельца заметки. При смене ключа ревизии
+// не перешифровываются: прежние ключи данных сохраняются и по-прежнему их расшифровывают
+type revisionRepo struct {
+	inner repository.RevisionRepository
+	notes repository.NoteRepository // Хранилище заметок для определения владельца ревизии
+	keys  *DataKeys
+}
+
+// NewRevisionRepository оборачивает хранилище ревизий шифрованием содержимого ключами данных keys
+// Владелец ревизии определяется по заметке в notes (вложенном хранилище заметок)
+func NewRevisionRepository(inner repository.RevisionRepository, notes repository.NoteRepository, keys *DataKeys) repository.RevisionRepository {
+	return &revisionRepo{inner: inner, notes: notes, keys: keys}
+}
+
+// owner возвращает владельца заметки ревизии; для удаленной заметки - владельца из контекста
+func (r *revisionRepo) owner(ctx context.Context, noteID string) (string, error) {
+	note, err := r.notes.GetByID(repository.WithoutOwner(ctx), noteID)
+	if errors.Is(err, memory.ErrNoteNotFound) {
+		ownerID, _ := repository.OwnerFromContext(ctx)
+		return ownerID, nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return note.OwnerID, nil
+}
+
+// decrypt возвращает ревизию с расшифрованным содержимым
+func (r *revisionRepo) decrypt(ctx context.Context, revision model.NoteRevision) (model.NoteRevision, error) {
+	content, _, err := r.keys.open(ctx, revision.Content, revision.NoteID)
+	if err != nil {
+		return model.NoteRevision{}, err
+	}
+	revision.Content = content
+	return revision, nil
+}
+
+// Add шифрует содержимое и сохраняет ревизию
+func (r *revisionRepo) Add(ctx context.Context, revision model.NoteRevision) (model.NoteRevision, error) {
+	if revision.Content != "" {
+		ownerID, err := r.owner(ctx, revision.NoteID)
+		if err != nil {
+			return model.NoteRevision{}, err
+		}
+		sealed, _, err := r.keys.seal(ctx, ownerID, revision.Content, revision.NoteID)
+		if err != nil {
+			return model.NoteRevision{}, err
+		}
+		revision.Content = sealed
+	}
+
+	added, err := r.inner.Add(ctx, revision)
+	if err != nil {
+		return model.NoteRevision{}, err
+	}
+	return r.decrypt(ctx, added)
+}
+
+// List возвращает ревизии заметки с расшифрованным содержимым
+func (r *revisionRepo) List(ctx context.Context, noteID string) ([]model.NoteRevision, error) {
+	revisions, err := r.inner.List(ctx, noteID)
+	if err != nil {
+		return nil, err
+	}
+	for i, revision := range revisions {
+		if revisions[i], err = r.decrypt(ctx, revision); err != nil {
+			return nil, err
+		}
+	}
+	return revisions, nil
+}
+
+// Get возвращает ревизию заметки с расшифрованным содержимым
+func (r *revisionRepo) Get(ctx context.Context, noteID string, revision int64) (model.NoteRevision, error) {
+	stored, err := r.inner.Get(ctx, noteID, revision)
+	if err != nil {
+		return model.NoteRevision{}, err
+	}
+	return r.decrypt(ctx, stored)
+}
+
+// DeleteByNoteID удаляет историю изменений заметки
+func (r *revisionRepo) DeleteByNoteID(ctx context.Context, noteID string) error {
+	return r.inner.DeleteByNoteID(ctx, noteID)
+}
@@ -0,0 +1,139 @@
+package encrypted
+
+import (
+	"context"
+	"errors"
+
+	"notes-service/internal/model"
+	"notes-service/internal/repository"
+	"notes-service/internal/repository/memory"
+)
+
+// rotationBatchSize количество заметок между сообщениями о прогрессе перешифрования
+const rotationBatchSize = 100
+
+// Rotator сменяет ключи данных владельцев и перешифровывает их заметки
+// Работает с вложенным хранилищем напрямую: содержимое читается и записывается зашифрованным
+type Rotator struct {
+	inner repository.NoteRepository
+	keys  *DataKeys
+}
+
+// NewRotator создает смену ключей для заметок вложенного хранилища inner (не обернутого NewRepository)
+func NewRotator(inner repository.NoteRepository, keys *DataKeys) *Rotator {
+	return &Rotator{inner: inner, keys: keys}
+}
+
+// RotateKeys перешифровывает ключи данных текущим мастер-ключом и создает новые ключи данных
+// владельца ownerID (пусто - всех владельцев). Новые записи сразу шифруются новыми ключами,
+// существующие заметки перешифровывает Reencrypt
+func (r *Rotator) RotateKeys(ctx context.Context, ownerID string) (rotated, rewrapped int, err error) {
+	if rewrapped, err = r.keys.Rewrap(ctx); err != nil {
+		return 0, rewrapped, err
+	}
+	rotated, err = r.keys.Rotate(ctx, ownerID)
+	return rotated, rewrapped, err
+}
+
+// Reencrypt перешифровывает текущими ключами данных заметки владельца ownerID (пусто - все заметки),
+// зашифрованные прежними ключами, мастер-ключом (enc:v1) или записанные открытым текстом.
+// progress вызывается каждые rotationBatchSize заметок и по завершении
+//
+// Время обновления и версия заметок сохраняются, если хранилище реализует NoteRewriter;
+// иначе заметка записывается через Update. Заметки, удаленные или измененные во время
+// перешифрования, пропускаются: изменение уже записано текущим ключом
+func (r *Rotator) Reencrypt(ctx context.Context, ownerID string, progress func(processed, reencrypted, total int64)) error {
+	scope := repository.WithoutOwner(ctx)
+	if ownerID != "" {
+		scope = repository.WithOwner(ctx, ownerID)
+	}
+
+	// ID собираются заранее, чтобы не перезаписывать заметки во время обхода хранилища
+	ids, err := r.noteIDs(scope)
+	if err != nil {
+		return err
+	}
+
+	total := int64(len(ids))
+	var processed, reencrypted int64
+	for _, id := range ids {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		changed, err := r.reencrypt(scope, id)
+		if err != nil {
+			return err
+		}
+		processed++
+		if changed {
+			reencrypted++
+		}
+		if processed%rotationBatchSize == 0 {
+			progress(processed, reencrypted, total)
+		}
+	}
+	progress(processed, reencrypted, total)
+	return nil
+}
+
+// reencrypt перешифровывает заметку id, если она зашифрована не текущим ключом ее владельца
+func (r *Rotator) reencrypt(ctx context.Context, id string) (bool, error) {
+	note, err := r.inner.GetByID(ctx, id)
+	if errors.Is(err, memory.ErrNoteNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if note.Content == "" {
+		return false, nil
+	}
+
+	current, err := r.keys.CurrentKeyID(ctx, note.OwnerID)
+	if err != nil {
+		return false, err
+	}
+	if keyID, ok := envelopeKeyID(note.Content, dataKeyEnvelopePrefix); ok && keyID == current {
+		return false, nil
+	}
+
+	plaintext, _, err := r.keys.open(ctx, note.Content, note.ID)
+	if err != nil {
+		return false, err
+	}
+	if note.Content, note.KeyID, err = r.keys.seal(ctx, note.OwnerID, plaintext, note.ID); err != nil {
+		return false, err
+	}
+
+	if rewriter, ok := r.inner.(repository.NoteRewriter); ok {
+		err = rewriter.Rewrite(ctx, note)
+	} else {
+		_, err = r.inner.Update(ctx, note)
+	}
+	if errors.Is(err, memory.ErrNoteNotFound) || errors.Is(err, memory.ErrVersionConflict) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// noteIDs возвращает ID заметок, видимых в контексте ctx
+func (r *Rotator) noteIDs(ctx context.Context) ([]string, error) {
+	var ids []string
+	if iterator, ok := r.inner.(repository.NoteIterator); ok {
+		err := iterator.ForEach(ctx, rotationBatchSize, func(note model.Note) error {
+			ids = append(ids, note.ID)
+			return nil
+		})
+		return ids, err
+	}
+
+	notes, err := r.inner.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, note := range notes {
+		ids = append(ids, note.ID)
+	}
+	return ids, nil
+}
@@ -0,0 +1,103 @@
+package encrypted
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"notes-service/internal/model"
+	"notes-service/internal/repository"
+	"notes-service/internal/repository/memory"
+)
+
+func TestRotator_ReencryptsOwnerNotes(t *testing.T) {
+	ctx := context.Background()
+	inner := memory.NewRepository()
+	keys := newTestKeys(t, memory.NewDataKeyRepository(), 1)
+	r := NewRepository(inner, keys)
+
+	alice, err := r.Create(repository.WithOwner(ctx, "alice"), model.Note{Title: "Alice", Content: "alice secret"})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	bob, err := r.Create(repository.WithOwner(ctx, "bob"), model.Note{Title: "Bob", Content: "bob secret"})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	rotator := NewRotator(inner, keys)
+	rotated, rewrapped, err := rotator.RotateKeys(ctx, "alice")
+	if err != nil || rotated != 1 || rewrapped != 0 {
+		t.Fatalf("Expected one rotated key, got rotated=%d rewrapped=%d (%v)", rotated, rewrapped, err)
+	}
+
+	var processed, reencrypted, total int64
+	err = rotator.Reencrypt(ctx, "alice", func(p, r, n int64) { processed, reencrypted, total = p, r, n })
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if processed != 1 || reencrypted != 1 || total != 1 {
+		t.Errorf("Expected progress 1/1/1 for alice's notes, got %d/%d/%d", processed, reencrypted, total)
+	}
+
+	stored, _ := inner.GetByID(ctx, alice.ID)
+	current, _ := keys.CurrentKeyID(ctx, "alice")
+	if stored.KeyID == alice.KeyID || stored.KeyID != current || !strings.HasPrefix(stored.Content, dataKeyEnvelopePrefix+current+":") {
+		t.Errorf("Expected alice's note re-encrypted with key %s, got %q (%s)", current, stored.Content, stored.KeyID)
+	}
+	if stored.Version != alice.Version || !stored.UpdatedAt.Equal(alice.UpdatedAt) {
+		t.Errorf("Expected version and update time to be preserved, got %d %v", stored.Version, stored.UpdatedAt)
+	}
+	if got, err := r.GetByID(ctx, alice.ID); err != nil || got.Content != "alice secret" {
+		t.Errorf("Expected decrypted content after rotation, got %q (%v)", got.Content, err)
+	}
+
+	if other, _ := inner.GetByID(ctx, bob.ID); other.KeyID != bob.KeyID {
+		t.Errorf("Expected bob's note to keep key %s, got %s", bob.KeyID, other.KeyID)
+	}
+
+	// Повторное перешифрование ничего не меняет
+	if err := rotator.Reencrypt(ctx, "", func(p, r, n int64) { processed, reencrypted, total = p, r, n }); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if processed != 2 || reencrypted != 0 || total != 2 {
+		t.Errorf("Expected nothing to re-encrypt, got %d/%d/%d", processed, reencrypted, total)
+	}
+}
+
+func TestRotator_RewrapsKeysAndMigratesMasterKeyNotes(t *testing.T) {
+	ctx := context.Background()
+	inner := memory.NewRepository()
+	dataKeys := memory.NewDataKeyRepository()
+
+	note, err := NewRepository(inner, newTestKeys(t, dataKeys, 1)).Create(ctx, model.Note{Title: "Note", Content: "data key", OwnerID: "alice"})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	legacy := model.Note{ID: "legacy", Title: "Legacy", OwnerID: "alice"}
+	if legacy.Content, err = newTestKeyring(t, 1).seal("master key", legacy.ID); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if _, err := inner.Create(ctx, legacy); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	// Новый мастер-ключ: ключи данных перешифровываются, заметки enc:v1 - переводятся на ключи данных
+	keys := newTestKeys(t, dataKeys, 2, 1)
+	rotator := NewRotator(inner, keys)
+	if _, rewrapped, err := rotator.RotateKeys(ctx, ""); err != nil || rewrapped != 1 {
+		t.Fatalf("Expected one rewrapped key, got %d (%v)", rewrapped, err)
+	}
+	if err := rotator.Reencrypt(ctx, "", func(int64, int64, int64) {}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	// Прежний мастер-ключ больше не нужен
+	r := NewRepository(inner, newTestKeys(t, dataKeys, 2))
+	for id, want := range map[string]string{note.ID: "data key", legacy.ID: "master key"} {
+		got, err := r.GetByID(ctx, id)
+		if err != nil || got.Content != want || !strings.HasPrefix(got.KeyID, dataKeyIDPrefix) {
+			t.Errorf("Expected %q readable with the new master key only, got %+v (%v)", want, got, err)
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"notes-service/internal/model"
+	"notes-service/internal/repository"
+)
+
+var (
+	// ErrAPIKeyNotFound возвращается, когда ключ API не найден
+	ErrAPIKeyNotFound = errors.New("api key not found")
+
+	// ErrAPIKeyExists возвращается при создании ключа с занятой открытой частью
+	ErrAPIKeyExists = errors.New("api key already exists")
+)
+
+var _ repository.APIKeyRepository = (*apiKeyRepo)(nil)
+
+type apiKeyRepo struct {
+	mu    sync.RWMutex
+	keys  map[string]model.APIKey // ID -> ключ
+	order []string                // ID в порядке создания
+}
+
+// NewAPIKeyRepository создает новый экземпляр in-memory репозитория ключей API
+func NewAPIKeyRepository() repository.APIKeyRepository {
+	return &apiKeyRepo{keys: make(map[string]model.APIKey)}
+}
+
+// Create сохраняет новый ключ
+func (r *apiKeyRepo) Create(ctx context.Context, key model.APIKey) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.keys[key.ID]; exists {
+		return fmt.Errorf("%w: id %q", ErrAPIKeyExists, key.ID)
+	}
+	r.keys[key.ID] = cloneAPIKey(key)
+	r.order = append(r.order, key.ID)
+	return nil
+}
+
+// GetByID возвращает ключ по открытой части
+func (r *apiKeyRepo) GetByID(ctx context.Context, id string) (model.APIKey, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	key, ok := r.keys[id]
+	if !ok {
+		return model.APIKey{}, ErrAPIKeyNotFound
+	}
+	return cloneAPIKey(key), nil
+}
+
+// List возвращает все ключи в порядке создания
+func (r *apiKeyRepo) List(ctx context.Context) ([]model.APIKey, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	keys := make([]model.APIKey, 0, len(r.order))
+	for _, id := range r.order {
+		keys = append(keys, cloneAPIKey(r.keys[id]))
+	}
+	return keys, nil
+}
+
+// Revoke отмечает ключ отозванным
+func (r *apiKeyRepo) Revoke(ctx context.Context, id string, at time.Time) (model.APIKey, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key, ok := r.keys[id]
+	if !ok {
+		return model.APIKey{}, ErrAPIKeyNotFound
+	}
+	if key.RevokedAt.IsZero() {
+		key.RevokedAt = at
+		r.keys[id] = key
+	}
+	return cloneAPIKey(key), nil
+}
+
+// cloneAPIKey копирует срезы ключа, чтобы вызывающий код не изменял хранимую запись
+func cloneAPIKey(key model.APIKey) model.APIKey {
+	key.Roles = slices.Clone(key.Roles)
+	key.SecretHash = slices.Clone(key.SecretHash)
+	return key
+}
@@ -0,0 +1,92 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"notes-service/internal/model"
+	"notes-service/internal/repository"
+)
+
+// DefaultMaxAuditRecords количество записей журнала аудита, хранимых по умолчанию
+const DefaultMaxAuditRecords = 100000
+
+var _ repository.AuditRepository = (*auditRepo)(nil)
+
+type auditRepo struct {
+	mu         sync.RWMutex
+	records    []model.AuditRecord // Записи в порядке добавления (по возрастанию ID)
+	nextID     int64
+	maxRecords int
+}
+
+// NewAuditRepository создает новый экземпляр in-memory журнала аудита, хранящий не больше
+// maxRecords последних записей (0 - DefaultMaxAuditRecords)
+func NewAuditRepository(maxRecords int) repository.AuditRepository {
+	if maxRecords <= 0 {
+		maxRecords = DefaultMaxAuditRecords
+	}
+	return &auditRepo{nextID: 1, maxRecords: maxRecords}
+}
+
+// Append сохраняет запись, вытесняя самые старые записи сверх maxRecords
+func (r *auditRepo) Append(ctx context.Context, record model.AuditRecord) (model.AuditRecord, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	record.ID = r.nextID
+	r.nextID++
+	r.records = append(r.records, record)
+	if len(r.records) > r.maxRecords {
+		r.records = slices.Delete(r.records, 0, len(r.records)-r.maxRecords)
+	}
+	return record, nil
+}
+
+// List возвращает записи, подходящие под filter, начиная с последней
+func (r *auditRepo) List(ctx context.Context, filter repository.AuditFilter) ([]model.AuditRecord, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var records []model.AuditRecord
+	for i := len(r.records) - 1; i >= 0; i-- {
+		if filter.Limit > 0 && len(records) == filter.Limit {
+			break
+		}
+		if record := r.records[i]; matchAudit(record, filter) {
+			records = append(records, record)
+		}
+	}
+	return records, nil
+}
+
+// DeleteBefore удаляет записи старше before
+func (r *auditRepo) DeleteBefore(ctx context.Context, before time.Time) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	n := len(r.records)
+	r.records = slices.DeleteFunc(r.records, func(record model.AuditRecord) bool { return record.Time.Before(before) })
+	return n - len(r.records), nil
+}
+
+// matchAudit проверяет, подходит ли запись под условия выборки
+func matchAudit(record model.AuditRecord, filter repository.AuditFilter) bool {
+	switch {
+	case filter.BeforeID > 0 && record.ID >= filter.BeforeID:
+		return false
+	case filter.UserID != "" && record.UserID != filter.UserID:
+		return false
+	case filter.NoteID != "" && record.NoteID != filter.NoteID:
+		return false
+	case filter.Action != "" && record.Action != filter.Action:
+		return false
+	case !filter.From.IsZero() && record.Time.Before(filter.From):
+		return false
+	case !filter.To.IsZero() && !record.Time.Before(filter.To):
+		return false
+	}
+	return true
+}
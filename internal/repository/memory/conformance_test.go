@@ -0,0 +1,15 @@
+package memory_test
+
+import (
+	"testing"
+
+	"notes-service/internal/repository"
+	"notes-service/internal/repository/memory"
+	"notes-service/internal/repository/repositorytest"
+)
+
+func TestRepository_Conformance(t *testing.T) {
+	repositorytest.TestNoteRepository(t, func(*testing.T) repository.NoteRepository {
+		return memory.NewRepository()
+	})
+}
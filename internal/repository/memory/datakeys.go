@@ -0,0 +1,84 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"notes-service/internal/model"
+	"notes-service/internal/repository"
+)
+
+// ErrDataKeyNotFound возвращается, когда ключ данных не найден
+var ErrDataKeyNotFound = errors.New("data key not found")
+
+var _ repository.DataKeyRepository = (*dataKeyRepo)(nil)
+
+type dataKeyRepo struct {
+	mu   sync.RWMutex
+	keys map[string]model.DataKey
+	ids  []string // ID ключей в порядке создания
+}
+
+// NewDataKeyRepository создает новый экземпляр in-memory репозитория ключей данных
+func NewDataKeyRepository() repository.DataKeyRepository {
+	return &dataKeyRepo{keys: make(map[string]model.DataKey)}
+}
+
+// Create сохраняет новый ключ
+func (r *dataKeyRepo) Create(ctx context.Context, key model.DataKey) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.keys[key.ID]; exists {
+		return fmt.Errorf("data key %s already exists", key.ID)
+	}
+	if key.CreatedAt.IsZero() {
+		key.CreatedAt = time.Now()
+	}
+	r.keys[key.ID] = key
+	r.ids = append(r.ids, key.ID)
+
+	return nil
+}
+
+// Get возвращает ключ по ID
+func (r *dataKeyRepo) Get(ctx context.Context, id string) (model.DataKey, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	key, ok := r.keys[id]
+	if !ok {
+		return model.DataKey{}, ErrDataKeyNotFound
+	}
+	return key, nil
+}
+
+// Update заменяет зашифрованное значение ключа
+func (r *dataKeyRepo) Update(ctx context.Context, key model.DataKey) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.keys[key.ID]
+	if !ok {
+		return ErrDataKeyNotFound
+	}
+	stored.Wrapped = key.Wrapped
+	r.keys[key.ID] = stored
+
+	return nil
+}
+
+// List возвращает все ключи в порядке создания
+func (r *dataKeyRepo) List(ctx context.Context) ([]model.DataKey, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	keys := make([]model.DataKey, 0, len(r.ids))
+	for _, id := range r.ids {
+		keys = append(keys, r.keys[id])
+	}
+	return keys, nil
+}
@@ -0,0 +1,83 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"notes-service/internal/model"
+	"notes-service/internal/repository"
+)
+
+// maxMetricPoints количество значений, хранимых для одной метрики пользователя
+const maxMetricPoints = 100000
+
+var _ repository.MetricRepository = (*metricRepo)(nil)
+
+// metricKey метрика пользователя
+type metricKey struct {
+	ownerID string
+	name    string
+}
+
+type metricRepo struct {
+	mu     sync.RWMutex
+	series map[metricKey][]model.MetricPoint // Значения в порядке времени
+}
+
+// NewMetricRepository создает новый экземпляр in-memory репозитория значений метрик
+func NewMetricRepository() repository.MetricRepository {
+	return &metricRepo{series: make(map[metricKey][]model.MetricPoint)}
+}
+
+// Append сохраняет значения метрик, вытесняя самые старые значения метрики сверх maxMetricPoints
+func (r *metricRepo) Append(ctx context.Context, points []model.MetricPoint) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, point := range points {
+		key := metricKey{ownerID: point.OwnerID, name: point.Name}
+		series := r.series[key]
+		// Значения обычно приходят по порядку, поэтому вставка почти всегда в конец
+		i := len(series)
+		for i > 0 && series[i-1].Time.After(point.Time) {
+			i--
+		}
+		series = slices.Insert(series, i, point)
+		if len(series) > maxMetricPoints {
+			series = slices.Delete(series, 0, len(series)-maxMetricPoints)
+		}
+		r.series[key] = series
+	}
+
+	return nil
+}
+
+// ForEach передает fn значения метрики пользователя за период [from, to) в порядке времени
+func (r *metricRepo) ForEach(ctx context.Context, ownerID, name string, from, to time.Time, fn func(model.MetricPoint) error) error {
+	r.mu.RLock()
+	series := r.series[metricKey{ownerID: ownerID, name: name}]
+	start, _ := slices.BinarySearchFunc(series, from, comparePointTime)
+	end := len(series)
+	if !to.IsZero() {
+		end, _ = slices.BinarySearchFunc(series, to, comparePointTime)
+	}
+	points := slices.Clone(series[start:max(start, end)])
+	r.mu.RUnlock()
+
+	for _, point := range points {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := fn(point); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// comparePointTime сравнивает время значения с t для бинарного поиска первого значения не раньше t
+func comparePointTime(point model.MetricPoint, t time.Time) int {
+	return point.Time.Compare(t)
+}
@@ -3,6 +3,10 @@ package memory
 import (
 	"context"
 	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 
@@ -15,17 +19,34 @@ import (
 // ErrNoteNotFound возвращается, когда заметка не найдена
 var ErrNoteNotFound = errors.New("note not found")
 
-var _ repository.NoteRepository = (*repo)(nil)
+// ErrVersionConflict возвращается, когда заметка была изменена после чтения клиентом
+var ErrVersionConflict = errors.New("note version conflict")
+
+var (
+	_ repository.NoteRepository      = (*repo)(nil)
+	_ repository.BatchNoteRepository = (*repo)(nil)
+	_ repository.NoteIterator        = (*repo)(nil)
+	_ repository.SortedNoteLister    = (*repo)(nil)
+	_ repository.TagIndex            = (*repo)(nil)
+	_ repository.NotePinner          = (*repo)(nil)
+	_ repository.NoteRewriter        = (*repo)(nil)
+	_ repository.NoteRestorer        = (*repo)(nil)
+	_ repository.ConsistencyTracker  = (*repo)(nil)
+)
 
 type repo struct {
 	mu    sync.RWMutex
 	notes map[string]model.Note
+	ids   []string                       // Отсортированные ID заметок для постраничного обхода (ForEach)
+	tags  map[string]map[string]struct{} // Вторичный индекс: тег -> множество ID заметок
+	seq   uint64                         // Номер последней записи (токен согласованности)
 }
 
 // NewRepository создает новый экземпляр in-memory репозитория на основе map
 func NewRepository() repository.NoteRepository {
 	return &repo{
 		notes: make(map[string]model.Note),
+		tags:  make(map[string]map[string]struct{}),
 	}
 }
 
@@ -34,6 +55,35 @@ func (r *repo) Create(ctx context.Context, note model.Note) (model.Note, error)
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
+	return r.create(withOwner(ctx, note)), nil
+}
+
+// withOwner назначает заметке владельца из контекста, если операции ограничены владельцем
+func withOwner(ctx context.Context, note model.Note) model.Note {
+	if ownerID, ok := repository.OwnerFromContext(ctx); ok {
+		note.OwnerID = ownerID
+	}
+	return note
+}
+
+// visible проверяет, доступна ли заметка владельцу из контекста
+func visible(ctx context.Context, note model.Note) bool {
+	ownerID, ok := repository.OwnerFromContext(ctx)
+	return !ok || note.OwnerID == ownerID
+}
+
+// lookup возвращает заметку, если она существует и доступна владельцу из контекста
+// Вызывается под блокировкой
+func (r *repo) lookup(ctx context.Context, id string) (model.Note, bool) {
+	note, exists := r.notes[id]
+	if !exists || !visible(ctx, note) {
+		return model.Note{}, false
+	}
+	return note, true
+}
+
+// create сохраняет заметку, вызывается под блокировкой
+func (r *repo) create(note model.Note) model.Note {
 	// Генерируем UUID если не передан
 	if note.ID == "" {
 		note.ID = uuid.New().String()
@@ -45,11 +95,66 @@ func (r *repo) Create(ctx context.Context, note model.Note) (model.Note, error)
 		note.CreatedAt = now
 	}
 	note.UpdatedAt = now
+	note.Version = 1
 
-	// Сохраняем заметку
+	// Сохраняем заметку и поддерживаем отсортированный индекс ID
+	if _, exists := r.notes[note.ID]; !exists {
+		pos, _ := slices.BinarySearch(r.ids, note.ID)
+		r.ids = slices.Insert(r.ids, pos, note.ID)
+	}
+	r.store(note)
+
+	return note
+}
+
+// store сохраняет заметку и обновляет индекс тегов, вызывается под блокировкой
+// Теги и зашифрованное содержимое копируются, чтобы вызывающий код не мог изменить
+// хранилище через общий слайс. Зашифрованные (e2e) заметки не индексируются
+func (r *repo) store(note model.Note) {
+	if previous, exists := r.notes[note.ID]; exists {
+		r.unindexTags(previous)
+	}
+
+	note.Tags = slices.Clone(note.Tags)
+	note.ContentEncrypted = slices.Clone(note.ContentEncrypted)
 	r.notes[note.ID] = note
+	r.seq++
 
-	return note, nil
+	if note.IsE2E {
+		return
+	}
+
+	for _, tag := range note.Tags {
+		ids, ok := r.tags[tag]
+		if !ok {
+			ids = make(map[string]struct{})
+			r.tags[tag] = ids
+		}
+		ids[note.ID] = struct{}{}
+	}
+}
+
+// remove удаляет заметку вместе с записями индексов, вызывается под блокировкой
+func (r *repo) remove(id string) {
+	if note, exists := r.notes[id]; exists {
+		r.unindexTags(note)
+	}
+
+	delete(r.notes, id)
+	if pos, found := slices.BinarySearch(r.ids, id); found {
+		r.ids = slices.Delete(r.ids, pos, pos+1)
+	}
+	r.seq++
+}
+
+// unindexTags удаляет заметку из индекса тегов, вызывается под блокировкой
+func (r *repo) unindexTags(note model.Note) {
+	for _, tag := range note.Tags {
+		delete(r.tags[tag], note.ID)
+		if len(r.tags[tag]) == 0 {
+			delete(r.tags, tag)
+		}
+	}
 }
 
 // GetByID возвращает заметку по её ID
@@ -57,7 +162,7 @@ func (r *repo) GetByID(ctx context.Context, id string) (model.Note, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
-	note, exists := r.notes[id]
+	note, exists := r.lookup(ctx, id)
 	if !exists {
 		return model.Note{}, ErrNoteNotFound
 	}
@@ -72,9 +177,23 @@ func (r *repo) List(ctx context.Context) ([]model.Note, error) {
 
 	notes := make([]model.Note, 0, len(r.notes))
 	for _, note := range r.notes {
-		notes = append(notes, note)
+		if visible(ctx, note) {
+			notes = append(notes, note)
+		}
+	}
+
+	return notes, nil
+}
+
+// ListSorted возвращает все заметки, упорядоченные компаратором cmp
+func (r *repo) ListSorted(ctx context.Context, cmp repository.NoteComparator) ([]model.Note, error) {
+	notes, err := r.List(ctx)
+	if err != nil {
+		return nil, err
 	}
 
+	slices.SortStableFunc(notes, cmp)
+
 	return notes, nil
 }
 
@@ -84,16 +203,81 @@ func (r *repo) Update(ctx context.Context, note model.Note) (model.Note, error)
 	defer r.mu.Unlock()
 
 	// Проверяем существование заметки
-	_, exists := r.notes[note.ID]
+	stored, exists := r.lookup(ctx, note.ID)
 	if !exists {
 		return model.Note{}, ErrNoteNotFound
 	}
 
-	// Обновляем временную метку
+	// Оптимистичная блокировка: отклоняем обновление устаревшей версии
+	if note.Version != 0 && note.Version != stored.Version {
+		return model.Note{}, fmt.Errorf("%w: expected version %d, current version %d",
+			ErrVersionConflict, note.Version, stored.Version)
+	}
+
+	// Обновляем временную метку и версию, владелец заметки не меняется
 	note.UpdatedAt = time.Now()
+	note.Version = stored.Version + 1
+	note.OwnerID = stored.OwnerID
 
 	// Сохраняем обновленную заметку
-	r.notes[note.ID] = note
+	r.store(note)
+
+	return note, nil
+}
+
+// Rewrite заменяет сохраненную заметку, не меняя время обновления и версию
+func (r *repo) Rewrite(ctx context.Context, note model.Note) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, exists := r.lookup(ctx, note.ID)
+	if !exists {
+		return ErrNoteNotFound
+	}
+	if note.Version != stored.Version {
+		return fmt.Errorf("%w: expected version %d, current version %d",
+			ErrVersionConflict, note.Version, stored.Version)
+	}
+
+	note.OwnerID = stored.OwnerID
+	note.CreatedAt = stored.CreatedAt
+	note.UpdatedAt = stored.UpdatedAt
+	r.store(note)
+
+	return nil
+}
+
+// Restore сохраняет заметку из резервной копии вместе с ее временем и версией
+// Владелец из контекста не учитывается: заметка сохраняется с владельцем из копии
+func (r *repo) Restore(ctx context.Context, note model.Note) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.notes[note.ID]; !exists {
+		pos, _ := slices.BinarySearch(r.ids, note.ID)
+		r.ids = slices.Insert(r.ids, pos, note.ID)
+	}
+	r.store(note)
+
+	return nil
+}
+
+// SetPinned закрепляет или открепляет заметку, не меняя время обновления
+func (r *repo) SetPinned(ctx context.Context, id string, pinned bool) (model.Note, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	note, exists := r.lookup(ctx, id)
+	if !exists {
+		return model.Note{}, ErrNoteNotFound
+	}
+	if note.Pinned == pinned {
+		return note, nil
+	}
+
+	note.Pinned = pinned
+	note.Version++
+	r.store(note)
 
 	return note, nil
 }
@@ -104,12 +288,172 @@ func (r *repo) Delete(ctx context.Context, id string) error {
 	defer r.mu.Unlock()
 
 	// Проверяем существование заметки
-	_, exists := r.notes[id]
+	_, exists := r.lookup(ctx, id)
 	if !exists {
 		return ErrNoteNotFound
 	}
 
-	delete(r.notes, id)
+	r.remove(id)
+
+	return nil
+}
+
+// CreateBatch создает все заметки под одной блокировкой (все или ничего)
+func (r *repo) CreateBatch(ctx context.Context, notes []model.Note) ([]model.Note, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	created := make([]model.Note, len(notes))
+	for i, note := range notes {
+		created[i] = r.create(withOwner(ctx, note))
+	}
+
+	return created, nil
+}
+
+// DeleteBatch удаляет все заметки под одной блокировкой
+// Если хотя бы одна заметка не найдена, не удаляется ни одна
+func (r *repo) DeleteBatch(ctx context.Context, ids []string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, id := range ids {
+		if _, exists := r.lookup(ctx, id); !exists {
+			return fmt.Errorf("%w: %s", ErrNoteNotFound, id)
+		}
+	}
+
+	for _, id := range ids {
+		r.remove(id)
+	}
+
+	return nil
+}
+
+// ForEach обходит заметки в порядке возрастания ID порциями по batchSize
+// Блокировка удерживается только на время копирования порции, поэтому медленный
+// потребитель (стрим клиенту) не блокирует запись, а память не зависит от размера хранилища
+func (r *repo) ForEach(ctx context.Context, batchSize int, fn func(model.Note) error) error {
+	return r.ForEachAfter(ctx, "", batchSize, fn)
+}
+
+// ForEachAfter обходит заметки с ID больше after, как ForEach
+func (r *repo) ForEachAfter(ctx context.Context, after string, batchSize int, fn func(model.Note) error) error {
+	if batchSize <= 0 {
+		batchSize = repository.DefaultIteratorBatchSize
+	}
+
+	batch := make([]model.Note, 0, batchSize)
+	cursor := after
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		batch = r.nextBatch(ctx, batch[:0], cursor)
+		if len(batch) == 0 {
+			return nil
+		}
+
+		for _, note := range batch {
+			if err := fn(note); err != nil {
+				return err
+			}
+		}
+
+		cursor = batch[len(batch)-1].ID
+	}
+}
 
+// nextBatch копирует в batch следующие доступные заметки с ID больше cursor
+func (r *repo) nextBatch(ctx context.Context, batch []model.Note, cursor string) []model.Note {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	// Ищем первую позицию строго после курсора: заметки, удаленные между порциями, просто пропускаются
+	pos, found := slices.BinarySearch(r.ids, cursor)
+	if found {
+		pos++
+	}
+
+	for ; pos < len(r.ids) && len(batch) < cap(batch); pos++ {
+		if note := r.notes[r.ids[pos]]; visible(ctx, note) {
+			batch = append(batch, note)
+		}
+	}
+
+	return batch
+}
+
+// ListByTag возвращает заметки с тегом tag в порядке возрастания ID
+func (r *repo) ListByTag(ctx context.Context, tag string) ([]model.Note, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	ids := r.tags[tag]
+	notes := make([]model.Note, 0, len(ids))
+	for id := range ids {
+		if note := r.notes[id]; visible(ctx, note) {
+			notes = append(notes, note)
+		}
+	}
+	slices.SortFunc(notes, func(a, b model.Note) int {
+		return strings.Compare(a.ID, b.ID)
+	})
+
+	return notes, nil
+}
+
+// ListTags возвращает все теги с количеством заметок, упорядоченные по тегу
+func (r *repo) ListTags(ctx context.Context) ([]model.TagCount, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	counts := make([]model.TagCount, 0, len(r.tags))
+	_, scoped := repository.OwnerFromContext(ctx)
+	for tag, ids := range r.tags {
+		count := len(ids)
+		if scoped {
+			count = 0
+			for id := range ids {
+				if visible(ctx, r.notes[id]) {
+					count++
+				}
+			}
+		}
+		if count > 0 {
+			counts = append(counts, model.TagCount{Tag: tag, Count: count})
+		}
+	}
+	slices.SortFunc(counts, func(a, b model.TagCount) int {
+		return strings.Compare(a.Tag, b.Tag)
+	})
+
+	return counts, nil
+}
+
+// ConsistencyToken возвращает номер последней записи
+func (r *repo) ConsistencyToken(ctx context.Context) (string, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return strconv.FormatUint(r.seq, 10), nil
+}
+
+// WaitForToken проверяет, что запись с номером token уже выполнена
+// Запись в памяти видна сразу, поэтому ждать нечего: токен с большим номером
+// выдан другим экземпляром хранилища (другой репликой или до перезапуска)
+func (r *repo) WaitForToken(ctx context.Context, token string) error {
+	seq, err := strconv.ParseUint(token, 10, 64)
+	if err != nil {
+		return fmt.Errorf("%w: %q", repository.ErrInvalidConsistencyToken, token)
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if seq > r.seq {
+		return fmt.Errorf("%w: token %d, last write %d", repository.ErrConsistencyLag, seq, r.seq)
+	}
 	return nil
 }
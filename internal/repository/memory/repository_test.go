@@ -0,0 +1,287 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime"
+	"testing"
+
+	"notes-service/internal/model"
+	"notes-service/internal/repository"
+)
+
+// fillRepository создает n заметок с предсказуемыми ID
+func fillRepository(t testing.TB, n int) repository.NoteRepository {
+	t.Helper()
+
+	r := NewRepository()
+	batchRepo := r.(repository.BatchNoteRepository)
+
+	notes := make([]model.Note, n)
+	for i := range notes {
+		notes[i] = model.Note{
+			ID:      fmt.Sprintf("note-%06d", i),
+			Title:   "Title",
+			Content: "Content",
+		}
+	}
+
+	if _, err := batchRepo.CreateBatch(context.Background(), notes); err != nil {
+		t.Fatalf("Failed to fill repository: %v", err)
+	}
+
+	return r
+}
+
+func TestForEach_VisitsAllNotesInOrder(t *testing.T) {
+	const total = 100_000
+	r := fillRepository(t, total)
+
+	count := 0
+	prev := ""
+	err := r.(repository.NoteIterator).ForEach(context.Background(), 500, func(note model.Note) error {
+		if note.ID <= prev {
+			t.Fatalf("Expected ascending order, got %q after %q", note.ID, prev)
+		}
+		prev = note.ID
+		count++
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if count != total {
+		t.Errorf("Expected %d notes, got %d", total, count)
+	}
+}
+
+func TestForEach_MemoryDoesNotGrowWithDataset(t *testing.T) {
+	const total = 100_000
+	r := fillRepository(t, total)
+	iterator := r.(repository.NoteIterator)
+
+	var before, after runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+
+	err := iterator.ForEach(context.Background(), 100, func(note model.Note) error {
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	runtime.ReadMemStats(&after)
+
+	// Обход выделяет только буфер одной порции, а не копию всех 100k заметок (~10MB)
+	allocated := after.TotalAlloc - before.TotalAlloc
+	if allocated > 1<<20 {
+		t.Errorf("Expected ForEach to allocate less than 1MB, allocated %d bytes", allocated)
+	}
+}
+
+func TestForEach_StopsOnCallbackError(t *testing.T) {
+	r := fillRepository(t, 1000)
+	errStop := errors.New("stop")
+
+	count := 0
+	err := r.(repository.NoteIterator).ForEach(context.Background(), 100, func(note model.Note) error {
+		count++
+		if count == 150 {
+			return errStop
+		}
+		return nil
+	})
+
+	if !errors.Is(err, errStop) {
+		t.Errorf("Expected callback error, got: %v", err)
+	}
+
+	if count != 150 {
+		t.Errorf("Expected iteration to stop after 150 notes, got %d", count)
+	}
+}
+
+func TestForEach_ContextCancelled(t *testing.T) {
+	r := fillRepository(t, 1000)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	count := 0
+	err := r.(repository.NoteIterator).ForEach(ctx, 100, func(note model.Note) error {
+		count++
+		if count == 100 {
+			cancel()
+		}
+		return nil
+	})
+
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got: %v", err)
+	}
+}
+
+func TestForEach_SkipsNotesDeletedDuringIteration(t *testing.T) {
+	r := fillRepository(t, 300)
+	ctx := context.Background()
+
+	seen := make(map[string]bool)
+	err := r.(repository.NoteIterator).ForEach(ctx, 100, func(note model.Note) error {
+		seen[note.ID] = true
+		if note.ID == "note-000050" {
+			// Удаляем заметку из следующей порции
+			if err := r.Delete(ctx, "note-000150"); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if seen["note-000150"] {
+		t.Error("Expected deleted note to be skipped")
+	}
+
+	if len(seen) != 299 {
+		t.Errorf("Expected 299 notes, got %d", len(seen))
+	}
+}
+
+func TestTagIndex_TracksUpdatesAndDeletes(t *testing.T) {
+	ctx := context.Background()
+	r := NewRepository()
+	index := r.(repository.TagIndex)
+
+	first, _ := r.Create(ctx, model.Note{ID: "a", Title: "A", Tags: []string{"go", "work"}})
+	if _, err := r.Create(ctx, model.Note{ID: "b", Title: "B", Tags: []string{"work"}}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	// Замена тегов: "go" должен исчезнуть из индекса
+	first.Tags = []string{"home"}
+	if _, err := r.Update(ctx, first); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if err := r.Delete(ctx, "b"); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	counts, err := index.ListTags(ctx)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(counts) != 1 || counts[0] != (model.TagCount{Tag: "home", Count: 1}) {
+		t.Errorf("Expected only tag 'home' with 1 note, got %v", counts)
+	}
+
+	notes, err := index.ListByTag(ctx, "work")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(notes) != 0 {
+		t.Errorf("Expected no notes with tag 'work', got %d", len(notes))
+	}
+}
+
+func TestOwnerScope_IsolatesNotes(t *testing.T) {
+	r := NewRepository()
+	alice := repository.WithOwner(context.Background(), "alice")
+	bob := repository.WithOwner(context.Background(), "bob")
+
+	note, err := r.Create(alice, model.Note{Title: "Alice note", Tags: []string{"work"}})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if note.OwnerID != "alice" {
+		t.Errorf("Expected owner alice, got %q", note.OwnerID)
+	}
+
+	if _, err := r.GetByID(bob, note.ID); !errors.Is(err, ErrNoteNotFound) {
+		t.Errorf("Expected ErrNoteNotFound for another owner, got: %v", err)
+	}
+	if _, err := r.Update(bob, model.Note{ID: note.ID, Title: "Hijacked"}); !errors.Is(err, ErrNoteNotFound) {
+		t.Errorf("Expected ErrNoteNotFound on update by another owner, got: %v", err)
+	}
+	if err := r.Delete(bob, note.ID); !errors.Is(err, ErrNoteNotFound) {
+		t.Errorf("Expected ErrNoteNotFound on delete by another owner, got: %v", err)
+	}
+
+	if notes, _ := r.List(bob); len(notes) != 0 {
+		t.Errorf("Expected no notes for bob, got %d", len(notes))
+	}
+	if tags, _ := r.(repository.TagIndex).ListTags(bob); len(tags) != 0 {
+		t.Errorf("Expected no tags for bob, got %v", tags)
+	}
+
+	// Без владельца в контексте доступны все заметки
+	if notes, _ := r.List(context.Background()); len(notes) != 1 {
+		t.Errorf("Expected 1 note without owner scope, got %d", len(notes))
+	}
+
+	updated, err := r.Update(alice, model.Note{ID: note.ID, Title: "Updated"})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if updated.OwnerID != "alice" {
+		t.Errorf("Expected owner to be preserved on update, got %q", updated.OwnerID)
+	}
+}
+
+func TestForEachAfter_ResumesFromCursor(t *testing.T) {
+	r := fillRepository(t, 300)
+
+	var ids []string
+	err := r.(repository.NoteIterator).ForEachAfter(context.Background(), "note-000199", 64, func(note model.Note) error {
+		ids = append(ids, note.ID)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if len(ids) != 100 {
+		t.Fatalf("Expected 100 notes after cursor, got %d", len(ids))
+	}
+	if ids[0] != "note-000200" || ids[len(ids)-1] != "note-000299" {
+		t.Errorf("Expected notes note-000200..note-000299, got %s..%s", ids[0], ids[len(ids)-1])
+	}
+}
+
+func TestConsistencyToken_AdvancesOnWrites(t *testing.T) {
+	ctx := context.Background()
+	r := NewRepository()
+	tracker := r.(repository.ConsistencyTracker)
+
+	before, err := tracker.ConsistencyToken(ctx)
+	if err != nil {
+		t.Fatalf("ConsistencyToken: %v", err)
+	}
+	note, err := r.Create(ctx, model.Note{Title: "Title"})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if err := r.Delete(ctx, note.ID); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	after, err := tracker.ConsistencyToken(ctx)
+	if err != nil {
+		t.Fatalf("ConsistencyToken: %v", err)
+	}
+	if before == after {
+		t.Fatalf("Expected token to change after writes, got %q", after)
+	}
+
+	if err := tracker.WaitForToken(ctx, after); err != nil {
+		t.Errorf("WaitForToken(own token) = %v, want nil", err)
+	}
+	// Токен другого экземпляра хранилища, записи которого этот экземпляр не видел
+	if err := tracker.WaitForToken(ctx, "1000"); !errors.Is(err, repository.ErrConsistencyLag) {
+		t.Errorf("WaitForToken(future token) = %v, want ErrConsistencyLag", err)
+	}
+	if err := tracker.WaitForToken(ctx, "not-a-token"); !errors.Is(err, repository.ErrInvalidConsistencyToken) {
+		t.Errorf("WaitForToken(garbage) = %v, want ErrInvalidConsistencyToken", err)
+	}
+}
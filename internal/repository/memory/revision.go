@@ -0,0 +1,79 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"notes-service/internal/model"
+	"notes-service/internal/repository"
+)
+
+// ErrRevisionNotFound возвращается, когда ревизия заметки не найдена
+var ErrRevisionNotFound = errors.New("revision not found")
+
+var _ repository.RevisionRepository = (*revisionRepo)(nil)
+
+type revisionRepo struct {
+	mu        sync.RWMutex
+	revisions map[string][]model.NoteRevision
+}
+
+// NewRevisionRepository создает новый экземпляр in-memory репозитория ревизий
+func NewRevisionRepository() repository.RevisionRepository {
+	return &revisionRepo{
+		revisions: make(map[string][]model.NoteRevision),
+	}
+}
+
+// Add сохраняет новую ревизию и возвращает её с назначенным номером
+func (r *revisionRepo) Add(ctx context.Context, revision model.NoteRevision) (model.NoteRevision, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// Номер ревизии - следующий за последним сохраненным
+	revision.Revision = int64(len(r.revisions[revision.NoteID])) + 1
+	if revision.CreatedAt.IsZero() {
+		revision.CreatedAt = time.Now()
+	}
+
+	r.revisions[revision.NoteID] = append(r.revisions[revision.NoteID], revision)
+
+	return revision, nil
+}
+
+// List возвращает все ревизии заметки в порядке возрастания номера
+func (r *revisionRepo) List(ctx context.Context, noteID string) ([]model.NoteRevision, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	stored := r.revisions[noteID]
+	revisions := make([]model.NoteRevision, len(stored))
+	copy(revisions, stored)
+
+	return revisions, nil
+}
+
+// Get возвращает ревизию заметки по её номеру
+func (r *revisionRepo) Get(ctx context.Context, noteID string, revision int64) (model.NoteRevision, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	stored := r.revisions[noteID]
+	if revision < 1 || revision > int64(len(stored)) {
+		return model.NoteRevision{}, ErrRevisionNotFound
+	}
+
+	return stored[revision-1], nil
+}
+
+// DeleteByNoteID удаляет всю историю изменений заметки
+func (r *revisionRepo) DeleteByNoteID(ctx context.Context, noteID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.revisions, noteID)
+
+	return nil
+}
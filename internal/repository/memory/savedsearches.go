@@ -0,0 +1,88 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+
+	"notes-service/internal/model"
+	"notes-service/internal/repository"
+)
+
+// ErrSavedSearchNotFound возвращается, когда сохраненный поиск не найден
+var ErrSavedSearchNotFound = errors.New("saved search not found")
+
+var _ repository.SavedSearchRepository = (*savedSearchRepo)(nil)
+
+type savedSearchRepo struct {
+	mu       sync.RWMutex
+	searches map[string]model.SavedSearch
+	owners   map[string][]string // Владелец -> ID поисков в порядке создания
+}
+
+// NewSavedSearchRepository создает новый экземпляр in-memory репозитория сохраненных поисков
+func NewSavedSearchRepository() repository.SavedSearchRepository {
+	return &savedSearchRepo{
+		searches: make(map[string]model.SavedSearch),
+		owners:   make(map[string][]string),
+	}
+}
+
+// Save сохраняет поиск, заменяя поиск с тем же ID
+func (r *savedSearchRepo) Save(ctx context.Context, search model.SavedSearch) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.searches[search.ID]; !exists {
+		r.owners[search.OwnerID] = append(r.owners[search.OwnerID], search.ID)
+	}
+	r.searches[search.ID] = search
+
+	return nil
+}
+
+// Get возвращает поиск по ID
+func (r *savedSearchRepo) Get(ctx context.Context, id string) (model.SavedSearch, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	search, ok := r.searches[id]
+	if !ok {
+		return model.SavedSearch{}, ErrSavedSearchNotFound
+	}
+	return search, nil
+}
+
+// Delete удаляет поиск
+func (r *savedSearchRepo) Delete(ctx context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	search, ok := r.searches[id]
+	if !ok {
+		return ErrSavedSearchNotFound
+	}
+	delete(r.searches, id)
+
+	ids := slices.DeleteFunc(r.owners[search.OwnerID], func(other string) bool { return other == id })
+	if len(ids) == 0 {
+		delete(r.owners, search.OwnerID)
+	} else {
+		r.owners[search.OwnerID] = ids
+	}
+
+	return nil
+}
+
+// ListByOwner возвращает поиски пользователя в порядке создания
+func (r *savedSearchRepo) ListByOwner(ctx context.Context, ownerID string) ([]model.SavedSearch, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	searches := make([]model.SavedSearch, 0, len(r.owners[ownerID]))
+	for _, id := range r.owners[ownerID] {
+		searches = append(searches, r.searches[id])
+	}
+	return searches, nil
+}
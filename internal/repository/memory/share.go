@@ -0,0 +1,123 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"notes-service/internal/model"
+	"notes-service/internal/repository"
+)
+
+// ErrShareNotFound возвращается, когда у пользователя нет доступа к заметке
+var ErrShareNotFound = errors.New("share not found")
+
+var _ repository.ShareRepository = (*shareRepo)(nil)
+
+type shareRepo struct {
+	mu     sync.RWMutex
+	shares map[string]map[string]model.Share // noteID -> userID -> доступ
+}
+
+// NewShareRepository создает новый экземпляр in-memory репозитория доступов
+func NewShareRepository() repository.ShareRepository {
+	return &shareRepo{
+		shares: make(map[string]map[string]model.Share),
+	}
+}
+
+// Save сохраняет доступ, заменяя существующий доступ пользователя к заметке
+func (r *shareRepo) Save(ctx context.Context, share model.Share) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if share.CreatedAt.IsZero() {
+		share.CreatedAt = time.Now()
+	}
+
+	users, ok := r.shares[share.NoteID]
+	if !ok {
+		users = make(map[string]model.Share)
+		r.shares[share.NoteID] = users
+	}
+	users[share.UserID] = share
+
+	return nil
+}
+
+// Get возвращает доступ пользователя userID к заметке
+func (r *shareRepo) Get(ctx context.Context, noteID, userID string) (model.Share, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	share, ok := r.shares[noteID][userID]
+	if !ok {
+		return model.Share{}, ErrShareNotFound
+	}
+
+	return share, nil
+}
+
+// Delete отзывает доступ пользователя userID к заметке
+func (r *shareRepo) Delete(ctx context.Context, noteID, userID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.shares[noteID][userID]; !ok {
+		return ErrShareNotFound
+	}
+
+	delete(r.shares[noteID], userID)
+	if len(r.shares[noteID]) == 0 {
+		delete(r.shares, noteID)
+	}
+
+	return nil
+}
+
+// ListByUser возвращает доступы пользователя userID, упорядоченные по ID заметки
+func (r *shareRepo) ListByUser(ctx context.Context, userID string) ([]model.Share, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var shares []model.Share
+	for _, users := range r.shares {
+		if share, ok := users[userID]; ok {
+			shares = append(shares, share)
+		}
+	}
+	slices.SortFunc(shares, func(a, b model.Share) int {
+		return strings.Compare(a.NoteID, b.NoteID)
+	})
+
+	return shares, nil
+}
+
+// ListByNoteID возвращает доступы к заметке, упорядоченные по ID пользователя
+func (r *shareRepo) ListByNoteID(ctx context.Context, noteID string) ([]model.Share, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var shares []model.Share
+	for _, share := range r.shares[noteID] {
+		shares = append(shares, share)
+	}
+	slices.SortFunc(shares, func(a, b model.Share) int {
+		return strings.Compare(a.UserID, b.UserID)
+	})
+
+	return shares, nil
+}
+
+// DeleteByNoteID отзывает все доступы к заметке
+func (r *shareRepo) DeleteByNoteID(ctx context.Context, noteID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.shares, noteID)
+
+	return nil
+}
@@ -0,0 +1,105 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"notes-service/internal/model"
+	"notes-service/internal/repository"
+)
+
+var (
+	// ErrUserNotFound возвращается, когда пользователь не найден
+	ErrUserNotFound = errors.New("user not found")
+
+	// ErrUserExists возвращается при создании пользователя с занятым ID или именем для входа
+	ErrUserExists = errors.New("user already exists")
+)
+
+var _ repository.UserRepository = (*userRepo)(nil)
+
+type userRepo struct {
+	mu         sync.RWMutex
+	users      map[string]model.User // ID -> пользователь
+	byUsername map[string]string     // Имя для входа -> ID
+}
+
+// NewUserRepository создает новый экземпляр in-memory репозитория пользователей
+func NewUserRepository() repository.UserRepository {
+	return &userRepo{
+		users:      make(map[string]model.User),
+		byUsername: make(map[string]string),
+	}
+}
+
+// Create сохраняет нового пользователя
+func (r *userRepo) Create(ctx context.Context, user model.User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.users[user.ID]; exists {
+		return fmt.Errorf("%w: id %q", ErrUserExists, user.ID)
+	}
+	if _, exists := r.byUsername[user.Username]; exists && user.Username != "" {
+		return fmt.Errorf("%w: username %q", ErrUserExists, user.Username)
+	}
+	if user.CreatedAt.IsZero() {
+		user.CreatedAt = time.Now()
+	}
+	user.Roles = slices.Clone(user.Roles)
+
+	r.users[user.ID] = user
+	if user.Username != "" {
+		r.byUsername[user.Username] = user.ID
+	}
+
+	return nil
+}
+
+// GetByID возвращает пользователя по ID
+func (r *userRepo) GetByID(ctx context.Context, id string) (model.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	user, ok := r.users[id]
+	if !ok {
+		return model.User{}, ErrUserNotFound
+	}
+	user.Roles = slices.Clone(user.Roles)
+
+	return user, nil
+}
+
+// GetByUsername возвращает пользователя по имени для входа
+func (r *userRepo) GetByUsername(ctx context.Context, username string) (model.User, error) {
+	r.mu.RLock()
+	id, ok := r.byUsername[username]
+	r.mu.RUnlock()
+	if !ok || username == "" {
+		return model.User{}, ErrUserNotFound
+	}
+
+	return r.GetByID(ctx, id)
+}
+
+// List возвращает всех пользователей, упорядоченных по ID
+func (r *userRepo) List(ctx context.Context) ([]model.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	users := make([]model.User, 0, len(r.users))
+	for _, user := range r.users {
+		user.Roles = slices.Clone(user.Roles)
+		users = append(users, user)
+	}
+	slices.SortFunc(users, func(a, b model.User) int {
+		return strings.Compare(a.ID, b.ID)
+	})
+
+	return users, nil
+}
@@ -0,0 +1,124 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+
+	"notes-service/internal/model"
+	"notes-service/internal/repository"
+)
+
+// ErrWebhookNotFound возвращается, когда вебхук не найден
+var ErrWebhookNotFound = errors.New("webhook not found")
+
+// maxDeadLetters количество недоставленных событий, хранимых для одного пользователя
+const maxDeadLetters = 1000
+
+var _ repository.WebhookRepository = (*webhookRepo)(nil)
+
+type webhookRepo struct {
+	mu          sync.RWMutex
+	webhooks    map[string]model.Webhook
+	owners      map[string][]string                  // Владелец -> ID вебхуков в порядке регистрации
+	deadLetters map[string][]model.WebhookDeadLetter // Владелец -> события в порядке добавления
+}
+
+// NewWebhookRepository создает новый экземпляр in-memory репозитория вебхуков
+func NewWebhookRepository() repository.WebhookRepository {
+	return &webhookRepo{
+		webhooks:    make(map[string]model.Webhook),
+		owners:      make(map[string][]string),
+		deadLetters: make(map[string][]model.WebhookDeadLetter),
+	}
+}
+
+// Create сохраняет новый вебхук
+func (r *webhookRepo) Create(ctx context.Context, webhook model.Webhook) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.webhooks[webhook.ID]; exists {
+		return fmt.Errorf("webhook %s already exists", webhook.ID)
+	}
+	webhook.EventTypes = slices.Clone(webhook.EventTypes)
+	r.webhooks[webhook.ID] = webhook
+	r.owners[webhook.OwnerID] = append(r.owners[webhook.OwnerID], webhook.ID)
+
+	return nil
+}
+
+// Get возвращает вебхук по ID
+func (r *webhookRepo) Get(ctx context.Context, id string) (model.Webhook, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	webhook, ok := r.webhooks[id]
+	if !ok {
+		return model.Webhook{}, ErrWebhookNotFound
+	}
+	webhook.EventTypes = slices.Clone(webhook.EventTypes)
+	return webhook, nil
+}
+
+// Delete удаляет вебхук
+func (r *webhookRepo) Delete(ctx context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	webhook, ok := r.webhooks[id]
+	if !ok {
+		return ErrWebhookNotFound
+	}
+	delete(r.webhooks, id)
+
+	ids := slices.DeleteFunc(r.owners[webhook.OwnerID], func(other string) bool { return other == id })
+	if len(ids) == 0 {
+		delete(r.owners, webhook.OwnerID)
+	} else {
+		r.owners[webhook.OwnerID] = ids
+	}
+
+	return nil
+}
+
+// ListByOwner возвращает вебхуки пользователя в порядке регистрации
+func (r *webhookRepo) ListByOwner(ctx context.Context, ownerID string) ([]model.Webhook, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	webhooks := make([]model.Webhook, 0, len(r.owners[ownerID]))
+	for _, id := range r.owners[ownerID] {
+		webhook := r.webhooks[id]
+		webhook.EventTypes = slices.Clone(webhook.EventTypes)
+		webhooks = append(webhooks, webhook)
+	}
+	return webhooks, nil
+}
+
+// AddDeadLetter сохраняет недоставленное событие, вытесняя самое старое событие пользователя
+// сверх maxDeadLetters
+func (r *webhookRepo) AddDeadLetter(ctx context.Context, letter model.WebhookDeadLetter) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	letters := append(r.deadLetters[letter.OwnerID], letter)
+	if len(letters) > maxDeadLetters {
+		letters = slices.Delete(letters, 0, len(letters)-maxDeadLetters)
+	}
+	r.deadLetters[letter.OwnerID] = letters
+
+	return nil
+}
+
+// ListDeadLetters возвращает недоставленные события пользователя, начиная с последнего
+func (r *webhookRepo) ListDeadLetters(ctx context.Context, ownerID string) ([]model.WebhookDeadLetter, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	letters := slices.Clone(r.deadLetters[ownerID])
+	slices.Reverse(letters)
+	return letters, nil
+}
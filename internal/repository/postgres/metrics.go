@@ -0,0 +1,131 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"notes-service/internal/model"
+	"notes-service/internal/repository"
+)
+
+// insertBatchSize количество значений в одном INSERT (4 параметра на значение, не более 65535 параметров)
+const insertBatchSize = 1000
+
+// metricSchema создает таблицу значений метрик и индекс для выборки метрики пользователя по времени
+var metricSchema = []string{
+	`CREATE TABLE IF NOT EXISTS metric_points (
+		owner_id text NOT NULL,
+		name text NOT NULL,
+		value double precision NOT NULL,
+		recorded_at timestamptz NOT NULL
+	)`,
+	`CREATE INDEX IF NOT EXISTS metric_points_owner_name_recorded_at_idx ON metric_points (owner_id, name, recorded_at)`,
+}
+
+var _ repository.MetricRepository = (*metricRepo)(nil)
+
+type metricRepo struct {
+	pool *pgxpool.Pool
+
+	mu       sync.Mutex
+	migrated bool
+}
+
+// NewMetricRepository создает хранилище значений метрик в таблице metric_points
+// Таблица создается при первом обращении, если ее нет. Значения не вытесняются
+func NewMetricRepository(pool *pgxpool.Pool) repository.MetricRepository {
+	return &metricRepo{pool: pool}
+}
+
+// migrate создает таблицу при первом обращении (и повторно, если прошлая попытка не удалась)
+func (r *metricRepo) migrate(ctx context.Context) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.migrated {
+		return nil
+	}
+	for _, statement := range metricSchema {
+		if _, err := r.pool.Exec(ctx, statement); err != nil {
+			return fmt.Errorf("failed to create metric_points table: %w", wrapError(err))
+		}
+	}
+	r.migrated = true
+	return nil
+}
+
+// Append сохраняет значения метрик порциями по insertBatchSize
+// Порции записываются отдельными запросами: при ошибке часть значений может остаться сохраненной
+func (r *metricRepo) Append(ctx context.Context, points []model.MetricPoint) error {
+	if len(points) == 0 {
+		return nil
+	}
+	if err := r.migrate(ctx); err != nil {
+		return err
+	}
+
+	for start := 0; start < len(points); start += insertBatchSize {
+		batch := points[start:min(start+insertBatchSize, len(points))]
+
+		var sql strings.Builder
+		sql.WriteString("INSERT INTO metric_points (owner_id, name, value, recorded_at) VALUES ")
+		args := make([]any, 0, len(batch)*4)
+		for i, point := range batch {
+			if i > 0 {
+				sql.WriteString(", ")
+			}
+			n := len(args)
+			fmt.Fprintf(&sql, "($%d, $%d, $%d, $%d)", n+1, n+2, n+3, n+4)
+			args = append(args, point.OwnerID, point.Name, point.Value, point.Time.UTC())
+		}
+		if _, err := r.pool.Exec(ctx, sql.String(), args...); err != nil {
+			return wrapError(err)
+		}
+	}
+	return nil
+}
+
+// ForEach передает fn значения метрики пользователя за период [from, to) в порядке времени
+// Время хранится с точностью до микросекунды
+func (r *metricRepo) ForEach(ctx context.Context, ownerID, name string, from, to time.Time, fn func(model.MetricPoint) error) error {
+	if err := r.migrate(ctx); err != nil {
+		return err
+	}
+
+	sql := `SELECT value, recorded_at FROM metric_points WHERE owner_id = $1 AND name = $2`
+	args := []any{ownerID, name}
+	if !from.IsZero() {
+		args = append(args, from.UTC())
+		sql += " AND recorded_at >= $" + strconv.Itoa(len(args))
+	}
+	if !to.IsZero() {
+		args = append(args, to.UTC())
+		sql += " AND recorded_at < $" + strconv.Itoa(len(args))
+	}
+	sql += " ORDER BY recorded_at"
+
+	rows, err := r.pool.Query(ctx, sql, args...)
+	if err != nil {
+		return wrapError(err)
+	}
+	var (
+		value      float64
+		recordedAt time.Time
+		fnErr      error
+	)
+	_, err = pgx.ForEachRow(rows, []any{&value, &recordedAt}, func() error {
+		fnErr = fn(model.MetricPoint{OwnerID: ownerID, Name: name, Value: value, Time: recordedAt.UTC()})
+		return fnErr
+	})
+	if fnErr != nil {
+		return fnErr
+	}
+	return wrapError(err)
+}
@@ -0,0 +1,480 @@
+package postgres
+
+import (
+	"bufio"
+	"context"
+	"crypto/hmac"
+	"crypto/pbkdf2"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"notes-service/internal/model"
+	"notes-service/internal/repository"
+)
+
+const (
+	// sslRequestCode код запроса TLS перед стартовым сообщением
+	sslRequestCode = 80877103
+
+	// scramMechanism механизм SASL, который предлагает сервер
+	scramMechanism = "SCRAM-SHA-256"
+
+	// timestamptzFormat текстовый формат timestamptz в параметрах и результатах
+	timestamptzFormat = "2006-01-02 15:04:05.999999999Z07:00:00"
+)
+
+// fakeServer минимальный сервер PostgreSQL: SSLRequest (отказ), SCRAM-SHA-256 и запросы хранилища метрик
+// в extended query protocol с текстовыми параметрами и результатами
+type fakeServer struct {
+	listener net.Listener
+	password string
+
+	mu      sync.Mutex
+	rows    []model.MetricPoint
+	queries []string
+	fail    string // Запрос с этим префиксом завершается ошибкой 42P01
+	drop    bool   // Следующий запрос обрывает соединение
+}
+
+func newFakeServer(t *testing.T, password string) *fakeServer {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	s := &fakeServer{listener: listener, password: password}
+	t.Cleanup(func() { listener.Close() })
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn)
+		}
+	}()
+	return s
+}
+
+func (s *fakeServer) url(password string) string {
+	return "postgres://notes:" + password + "@" + s.listener.Addr().String() + "/notes?sslmode=prefer"
+}
+
+func (s *fakeServer) serve(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+
+	startup, err := readStartup(r)
+	if err != nil {
+		return
+	}
+	if binary.BigEndian.Uint32(startup) == sslRequestCode {
+		conn.Write([]byte{'N'})
+		if _, err := readStartup(r); err != nil {
+			return
+		}
+	}
+	if !s.authenticate(conn, r) {
+		return
+	}
+	writeMessage(conn, 'S', []byte("server_version\x0016.0\x00"))
+	writeMessage(conn, 'Z', []byte{'I'})
+
+	var sql string
+	var args []string
+	for {
+		kind, payload, err := readMessage(r)
+		if err != nil {
+			return
+		}
+		switch kind {
+		case 'P':
+			sql, _, _ = strings.Cut(string(payload[1:]), "\x00")
+		case 'B':
+			args = parseBind(payload)
+		case 'S':
+			if !s.execute(conn, sql, args) {
+				return
+			}
+		case 'Q':
+			// Запросы без параметров (создание таблицы) и проверка соединения пулом ("-- ping")
+			query, _, _ := strings.Cut(string(payload), "\x00")
+			if !s.simpleQuery(conn, query) {
+				return
+			}
+		case 'X':
+			return
+		}
+	}
+}
+
+// authenticate проверяет пароль по SCRAM-SHA-256
+func (s *fakeServer) authenticate(conn net.Conn, r *bufio.Reader) bool {
+	writeMessage(conn, 'R', append(binary.BigEndian.AppendUint32(nil, 10), scramMechanism+"\x00\x00"...))
+
+	_, payload, err := readMessage(r)
+	if err != nil {
+		return false
+	}
+	mechanism, rest, _ := strings.Cut(string(payload), "\x00")
+	if mechanism != scramMechanism {
+		return false
+	}
+	clientFirstBare := strings.TrimPrefix(rest[4:], "n,,")
+	clientNonce := scramAttributes(clientFirstBare)["r"]
+
+	salt := []byte("fake-salt")
+	serverFirst := "r=" + clientNonce + "server-nonce,s=" + base64.StdEncoding.EncodeToString(salt) + ",i=4096"
+	writeMessage(conn, 'R', append(binary.BigEndian.AppendUint32(nil, 11), serverFirst...))
+
+	_, payload, err = readMessage(r)
+	if err != nil {
+		return false
+	}
+	clientFinal := string(payload)
+	withoutProof, proof64, _ := strings.Cut(clientFinal, ",p=")
+	proof, _ := base64.StdEncoding.DecodeString(proof64)
+
+	salted, _ := pbkdf2.Key(sha256.New, s.password, salt, 4096, sha256.Size)
+	storedKey := sha256.Sum256(hmacSHA256(salted, "Client Key"))
+	authMessage := clientFirstBare + "," + serverFirst + "," + withoutProof
+	signature := hmacSHA256(storedKey[:], authMessage)
+	if len(proof) != len(signature) {
+		writeError(conn, "28P01", `password authentication failed for user "notes"`)
+		return false
+	}
+	clientKey := make([]byte, len(proof))
+	for i := range proof {
+		clientKey[i] = proof[i] ^ signature[i]
+	}
+	if recovered := sha256.Sum256(clientKey); !hmac.Equal(recovered[:], storedKey[:]) {
+		writeError(conn, "28P01", `password authentication failed for user "notes"`)
+		return false
+	}
+
+	serverSignature := hmacSHA256(hmacSHA256(salted, "Server Key"), authMessage)
+	writeMessage(conn, 'R', append(binary.BigEndian.AppendUint32(nil, 12), "v="+base64.StdEncoding.EncodeToString(serverSignature)...))
+	writeMessage(conn, 'R', binary.BigEndian.AppendUint32(nil, 0))
+	return true
+}
+
+// simpleQuery отвечает на запрос без параметров; false - соединение нужно закрыть
+func (s *fakeServer) simpleQuery(conn net.Conn, sql string) bool {
+	if !strings.HasPrefix(sql, "CREATE") {
+		writeMessage(conn, 'I', nil)
+		writeMessage(conn, 'Z', []byte{'I'})
+		return true
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.drop {
+		s.drop = false
+		return false
+	}
+	s.queries = append(s.queries, sql)
+	writeMessage(conn, 'C', []byte("CREATE TABLE\x00"))
+	writeMessage(conn, 'Z', []byte{'I'})
+	return true
+}
+
+// execute отвечает на запрос; false - соединение нужно закрыть
+func (s *fakeServer) execute(conn net.Conn, sql string, args []string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.drop {
+		s.drop = false
+		return false
+	}
+	s.queries = append(s.queries, sql)
+	writeMessage(conn, '1', nil)
+	writeMessage(conn, '2', nil)
+
+	switch {
+	case s.fail != "" && strings.HasPrefix(sql, s.fail):
+		writeError(conn, "42P01", `relation "metric_points" does not exist`)
+	case strings.HasPrefix(sql, "INSERT"):
+		for i := 0; i+4 <= len(args); i += 4 {
+			value, _ := strconv.ParseFloat(args[i+2], 64)
+			recordedAt, _ := time.Parse(timestamptzFormat, args[i+3])
+			s.rows = append(s.rows, model.MetricPoint{OwnerID: args[i], Name: args[i+1], Value: value, Time: recordedAt})
+		}
+		writeMessage(conn, 'n', nil)
+		writeMessage(conn, 'C', []byte("INSERT 0 "+strconv.Itoa(len(args)/4)+"\x00"))
+	case strings.HasPrefix(sql, "SELECT value"):
+		var from, to time.Time
+		next := 2
+		if strings.Contains(sql, "recorded_at >=") {
+			from, _ = time.Parse(timestamptzFormat, args[next])
+			next++
+		}
+		if strings.Contains(sql, "recorded_at <") {
+			to, _ = time.Parse(timestamptzFormat, args[next])
+		}
+		writeRowDescription(conn, field{"value", 701}, field{"recorded_at", 1184})
+		rows := slices.Clone(s.rows)
+		slices.SortStableFunc(rows, func(a, b model.MetricPoint) int { return a.Time.Compare(b.Time) })
+		for _, row := range rows {
+			if row.OwnerID != args[0] || row.Name != args[1] || row.Time.Before(from) || (!to.IsZero() && !row.Time.Before(to)) {
+				continue
+			}
+			writeDataRow(conn, strconv.FormatFloat(row.Value, 'g', -1, 64), row.Time.UTC().Format(timestamptzFormat))
+		}
+		writeMessage(conn, 'C', []byte("SELECT\x00"))
+	default:
+		writeMessage(conn, 'n', nil)
+		writeMessage(conn, 'C', []byte("SELECT 0\x00"))
+	}
+	writeMessage(conn, 'Z', []byte{'I'})
+	return true
+}
+
+func readStartup(r *bufio.Reader) ([]byte, error) {
+	var length [4]byte
+	if _, err := io.ReadFull(r, length[:]); err != nil {
+		return nil, err
+	}
+	payload := make([]byte, binary.BigEndian.Uint32(length[:])-4)
+	_, err := io.ReadFull(r, payload)
+	return payload, err
+}
+
+func readMessage(r *bufio.Reader) (byte, []byte, error) {
+	var header [5]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return 0, nil, err
+	}
+	payload := make([]byte, binary.BigEndian.Uint32(header[1:])-4)
+	_, err := io.ReadFull(r, payload)
+	return header[0], payload, err
+}
+
+func writeMessage(conn net.Conn, kind byte, payload []byte) {
+	msg := append([]byte{kind}, binary.BigEndian.AppendUint32(nil, uint32(len(payload)+4))...)
+	conn.Write(append(msg, payload...))
+}
+
+func writeError(conn net.Conn, code, message string) {
+	writeMessage(conn, 'E', []byte("SERROR\x00C"+code+"\x00M"+message+"\x00\x00"))
+}
+
+// field столбец результата: имя и OID типа
+type field struct {
+	name string
+	oid  uint32
+}
+
+func writeRowDescription(conn net.Conn, fields ...field) {
+	payload := binary.BigEndian.AppendUint16(nil, uint16(len(fields)))
+	for _, f := range fields {
+		payload = append(payload, f.name+"\x00"...)
+		payload = binary.BigEndian.AppendUint32(payload, 0) // Таблица
+		payload = binary.BigEndian.AppendUint16(payload, 0) // Номер столбца
+		payload = binary.BigEndian.AppendUint32(payload, f.oid)
+		payload = binary.BigEndian.AppendUint16(payload, 0xFFFF) // Размер типа
+		payload = binary.BigEndian.AppendUint32(payload, 0xFFFFFFFF)
+		payload = binary.BigEndian.AppendUint16(payload, 0) // Текстовый формат
+	}
+	writeMessage(conn, 'T', payload)
+}
+
+func writeDataRow(conn net.Conn, values ...string) {
+	payload := binary.BigEndian.AppendUint16(nil, uint16(len(values)))
+	for _, value := range values {
+		payload = binary.BigEndian.AppendUint32(payload, uint32(len(value)))
+		payload = append(payload, value...)
+	}
+	writeMessage(conn, 'D', payload)
+}
+
+// scramAttributes разбирает атрибуты сообщения SCRAM (a=value,b=value)
+func scramAttributes(message string) map[string]string {
+	attributes := make(map[string]string)
+	for _, part := range strings.Split(message, ",") {
+		if key, value, ok := strings.Cut(part, "="); ok {
+			attributes[key] = value
+		}
+	}
+	return attributes
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// parseBind возвращает текстовые параметры сообщения Bind
+func parseBind(payload []byte) []string {
+	_, rest, _ := strings.Cut(string(payload), "\x00") // Портал
+	_, rest, _ = strings.Cut(rest, "\x00")             // Запрос
+	data := []byte(rest)
+	formats := int(binary.BigEndian.Uint16(data))
+	data = data[2+2*formats:]
+	count := int(binary.BigEndian.Uint16(data))
+	data = data[2:]
+	args := make([]string, count)
+	for i := range args {
+		length := int(binary.BigEndian.Uint32(data))
+		args[i], data = string(data[4:4+length]), data[4+length:]
+	}
+	return args
+}
+
+func newTestPool(t *testing.T, rawURL string) *pgxpool.Pool {
+	t.Helper()
+	pool, err := NewPool(rawURL)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	t.Cleanup(pool.Close)
+	return pool
+}
+
+func TestMetricRepository_AppendAndForEach(t *testing.T) {
+	ctx := context.Background()
+	server := newFakeServer(t, "secret")
+	r := NewMetricRepository(newTestPool(t, server.url("secret")))
+
+	start := time.Date(2030, 1, 1, 9, 0, 0, 0, time.UTC)
+	points := []model.MetricPoint{
+		{OwnerID: "alice", Name: "latency", Value: 1.5, Time: start.Add(2 * time.Second)},
+		{OwnerID: "alice", Name: "latency", Value: 0.25, Time: start},
+		{OwnerID: "alice", Name: "latency", Value: 7, Time: start.Add(time.Minute)},
+		{OwnerID: "bob", Name: "latency", Value: 100, Time: start},
+	}
+	if err := r.Append(ctx, points); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	var got []model.MetricPoint
+	err := r.ForEach(ctx, "alice", "latency", start, start.Add(time.Minute), func(point model.MetricPoint) error {
+		got = append(got, point)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	want := []model.MetricPoint{points[1], points[0]}
+	if !slices.EqualFunc(got, want, func(a, b model.MetricPoint) bool {
+		return a.OwnerID == b.OwnerID && a.Name == b.Name && a.Value == b.Value && a.Time.Equal(b.Time)
+	}) {
+		t.Errorf("Expected %+v, got %+v", want, got)
+	}
+
+	// Таблица создается один раз
+	creates := 0
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	for _, sql := range server.queries {
+		if strings.HasPrefix(sql, "CREATE") {
+			creates++
+		}
+	}
+	if creates != len(metricSchema) {
+		t.Errorf("Expected %d schema statements, got %d", len(metricSchema), creates)
+	}
+}
+
+func TestMetricRepository_Errors(t *testing.T) {
+	ctx := context.Background()
+	server := newFakeServer(t, "secret")
+	point := []model.MetricPoint{{OwnerID: "alice", Name: "latency", Value: 1, Time: time.Now()}}
+
+	// Неверный пароль - ошибка сервера, а не недоступность
+	wrong := NewMetricRepository(newTestPool(t, server.url("wrong")))
+	err := wrong.Append(ctx, point)
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) || pgErr.Code != "28P01" || errors.Is(err, repository.ErrUnavailable) {
+		t.Fatalf("Expected authentication error, got: %v", err)
+	}
+
+	r := NewMetricRepository(newTestPool(t, server.url("secret")))
+
+	// Ошибка запроса возвращается как есть, соединение можно использовать дальше
+	server.mu.Lock()
+	server.fail = "INSERT"
+	server.mu.Unlock()
+	if err := r.Append(ctx, point); !errors.As(err, &pgErr) || pgErr.Code != "42P01" {
+		t.Fatalf("Expected query error, got: %v", err)
+	}
+	server.mu.Lock()
+	server.fail = ""
+	server.mu.Unlock()
+	if err := r.Append(ctx, point); err != nil {
+		t.Fatalf("Expected no error after query error, got: %v", err)
+	}
+
+	// Ошибка функции строки возвращается как есть
+	errStop := errors.New("stop")
+	if err := r.ForEach(ctx, "alice", "latency", time.Time{}, time.Time{}, func(model.MetricPoint) error { return errStop }); !errors.Is(err, errStop) {
+		t.Fatalf("Expected row error, got: %v", err)
+	}
+
+	// Обрыв соединения - недоступность, следующий запрос подключается заново
+	server.mu.Lock()
+	server.drop = true
+	server.mu.Unlock()
+	if err := r.Append(ctx, point); !errors.Is(err, repository.ErrUnavailable) {
+		t.Fatalf("Expected ErrUnavailable after connection loss, got: %v", err)
+	}
+	if err := r.Append(ctx, point); err != nil {
+		t.Fatalf("Expected reconnect, got: %v", err)
+	}
+
+	// Сервер не отвечает - недоступность
+	stopped := NewMetricRepository(newTestPool(t, "postgres://notes@127.0.0.1:1/notes?sslmode=disable"))
+	if err := stopped.Append(ctx, point); !errors.Is(err, repository.ErrUnavailable) {
+		t.Fatalf("Expected ErrUnavailable for unreachable server, got: %v", err)
+	}
+}
+
+func TestNewPool_InvalidURL(t *testing.T) {
+	for _, rawURL := range []string{"mysql://localhost/db", "postgres:///db", "postgres://localhost/db?sslmode=bogus"} {
+		if _, err := NewPool(rawURL); err == nil {
+			t.Errorf("Expected error for %q", rawURL)
+		}
+	}
+}
+
+func TestNewPool_TLSConfig(t *testing.T) {
+	base := func(string) *tls.Config {
+		return &tls.Config{MinVersion: tls.VersionTLS13, ServerName: "db.internal"}
+	}
+
+	// sslmode определяет проверку сертификата, настройки адреса - остальное
+	pool, err := NewPool("postgres://notes@db.example.com/notes?sslmode=require", WithTLSConfig(base))
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	defer pool.Close()
+	tlsConfig := pool.Config().ConnConfig.TLSConfig
+	if tlsConfig == nil || !tlsConfig.InsecureSkipVerify || tlsConfig.MinVersion != tls.VersionTLS13 || tlsConfig.ServerName != "db.internal" {
+		t.Errorf("Expected address settings with require verification, got %+v", tlsConfig)
+	}
+
+	// Без TLS настройки адреса не применяются
+	plain, err := NewPool("postgres://notes@db.example.com/notes?sslmode=disable", WithTLSConfig(base))
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	defer plain.Close()
+	if plain.Config().ConnConfig.TLSConfig != nil {
+		t.Error("Expected no TLS for sslmode=disable")
+	}
+}
@@ -2,11 +2,47 @@ package repository
 
 import (
 	"context"
+	"errors"
+	"io"
+	"time"
 
 	"notes-service/internal/model"
 )
 
+// ErrUnavailable хранилище временно недоступно (нет соединения, истек таймаут подключения)
+// Реализации оборачивают им такие ошибки, чтобы сервер перешел в режим деградации (см. degraded)
+var ErrUnavailable = errors.New("repository is unavailable")
+
+// ErrInvalidConsistencyToken токен согласованности не выдан этим хранилищем или поврежден
+var ErrInvalidConsistencyToken = errors.New("invalid consistency token")
+
+// ErrConsistencyLag хранилище (например, реплика базы данных) еще не содержит записи, указанной токеном
+// Клиент может повторить чтение позже или на другой реплике
+var ErrConsistencyLag = errors.New("repository has not caught up with the consistency token")
+
+type ownerKey struct{}
+
+// WithOwner ограничивает операции хранилища заметками владельца ownerID
+// Заметки других владельцев для такого контекста не существуют (ErrNoteNotFound)
+func WithOwner(ctx context.Context, ownerID string) context.Context {
+	return context.WithValue(ctx, ownerKey{}, ownerID)
+}
+
+// WithoutOwner снимает ограничение по владельцу, заданное WithOwner
+// Используется для внутренних проверок, например существования чужой заметки
+func WithoutOwner(ctx context.Context) context.Context {
+	return context.WithValue(ctx, ownerKey{}, nil)
+}
+
+// OwnerFromContext возвращает владельца, которым ограничены операции хранилища
+// Если владелец не задан, операции выполняются над всеми заметками
+func OwnerFromContext(ctx context.Context) (string, bool) {
+	ownerID, ok := ctx.Value(ownerKey{}).(string)
+	return ownerID, ok
+}
+
 // NoteRepository интерфейс для работы с заметками в хранилище
+// Все методы учитывают владельца из контекста (см. WithOwner)
 type NoteRepository interface {
 	// Create создает новую заметку и возвращает созданную заметку с ID
 	Create(ctx context.Context, note model.Note) (model.Note, error)
@@ -18,8 +54,274 @@ type NoteRepository interface {
 	List(ctx context.Context) ([]model.Note, error)
 
 	// Update обновляет существующую заметку и возвращает обновленную заметку
+	// Если note.Version не равен 0 и не совпадает с сохраненной версией, возвращается ошибка конфликта версий
 	Update(ctx context.Context, note model.Note) (model.Note, error)
 
 	// Delete удаляет заметку по ID
 	Delete(ctx context.Context, id string) error
 }
+
+// RevisionRepository интерфейс для хранения истории изменений заметок
+type RevisionRepository interface {
+	// Add сохраняет новую ревизию и возвращает её с назначенным номером
+	Add(ctx context.Context, revision model.NoteRevision) (model.NoteRevision, error)
+
+	// List возвращает все ревизии заметки в порядке возрастания номера
+	List(ctx context.Context, noteID string) ([]model.NoteRevision, error)
+
+	// Get возвращает ревизию заметки по её номеру
+	Get(ctx context.Context, noteID string, revision int64) (model.NoteRevision, error)
+
+	// DeleteByNoteID удаляет всю историю изменений заметки
+	DeleteByNoteID(ctx context.Context, noteID string) error
+}
+
+// ShareRepository интерфейс для хранения доступов пользователей к чужим заметкам
+type ShareRepository interface {
+	// Save сохраняет доступ, заменяя существующий доступ пользователя к заметке
+	Save(ctx context.Context, share model.Share) error
+
+	// Get возвращает доступ пользователя userID к заметке
+	Get(ctx context.Context, noteID, userID string) (model.Share, error)
+
+	// Delete отзывает доступ пользователя userID к заметке
+	Delete(ctx context.Context, noteID, userID string) error
+
+	// ListByUser возвращает доступы пользователя userID к чужим заметкам
+	ListByUser(ctx context.Context, userID string) ([]model.Share, error)
+
+	// ListByNoteID возвращает все доступы к заметке в порядке возрастания ID пользователя
+	ListByNoteID(ctx context.Context, noteID string) ([]model.Share, error)
+
+	// DeleteByNoteID отзывает все доступы к заметке
+	DeleteByNoteID(ctx context.Context, noteID string) error
+}
+
+// UserRepository интерфейс для хранения пользователей
+type UserRepository interface {
+	// Create сохраняет нового пользователя
+	// Возвращает ошибку, если пользователь с таким ID или именем для входа уже существует
+	Create(ctx context.Context, user model.User) error
+
+	// GetByID возвращает пользователя по ID
+	GetByID(ctx context.Context, id string) (model.User, error)
+
+	// GetByUsername возвращает пользователя по имени для входа
+	GetByUsername(ctx context.Context, username string) (model.User, error)
+
+	// List возвращает всех пользователей в порядке возрастания ID
+	List(ctx context.Context) ([]model.User, error)
+}
+
+// APIKeyRepository интерфейс для хранения ключей API
+// Отозванные ключи не удаляются, чтобы их можно было найти в списке ключей
+type APIKeyRepository interface {
+	// Create сохраняет новый ключ
+	Create(ctx context.Context, key model.APIKey) error
+
+	// GetByID возвращает ключ по открытой части
+	GetByID(ctx context.Context, id string) (model.APIKey, error)
+
+	// List возвращает все ключи в порядке создания
+	List(ctx context.Context) ([]model.APIKey, error)
+
+	// Revoke отмечает ключ отозванным в момент at и возвращает его
+	// Повторный отзыв не меняет время отзыва
+	Revoke(ctx context.Context, id string, at time.Time) (model.APIKey, error)
+}
+
+// DataKeyRepository интерфейс для хранения ключей данных владельцев заметок
+// Ключи не удаляются: ими расшифровываются заметки и ревизии, записанные до смены ключа
+type DataKeyRepository interface {
+	// Create сохраняет новый ключ
+	Create(ctx context.Context, key model.DataKey) error
+
+	// Get возвращает ключ по ID
+	Get(ctx context.Context, id string) (model.DataKey, error)
+
+	// Update заменяет зашифрованное значение ключа (после смены мастер-ключа)
+	Update(ctx context.Context, key model.DataKey) error
+
+	// List возвращает все ключи в порядке создания
+	List(ctx context.Context) ([]model.DataKey, error)
+}
+
+// WebhookRepository интерфейс для хранения вебхуков и событий, которые не удалось им доставить
+type WebhookRepository interface {
+	// Create сохраняет новый вебхук
+	Create(ctx context.Context, webhook model.Webhook) error
+
+	// Get возвращает вебхук по ID
+	Get(ctx context.Context, id string) (model.Webhook, error)
+
+	// Delete удаляет вебхук; недоставленные события вебхука сохраняются
+	Delete(ctx context.Context, id string) error
+
+	// ListByOwner возвращает вебхуки пользователя в порядке регистрации
+	ListByOwner(ctx context.Context, ownerID string) ([]model.Webhook, error)
+
+	// AddDeadLetter сохраняет недоставленное событие
+	// Хранилище может вытеснять самые старые события пользователя
+	AddDeadLetter(ctx context.Context, letter model.WebhookDeadLetter) error
+
+	// ListDeadLetters возвращает недоставленные события вебхуков пользователя, начиная с последнего
+	ListDeadLetters(ctx context.Context, ownerID string) ([]model.WebhookDeadLetter, error)
+}
+
+// SavedSearchRepository интерфейс для хранения сохраненных поисков пользователей
+type SavedSearchRepository interface {
+	// Save сохраняет поиск, заменяя поиск с тем же ID
+	Save(ctx context.Context, search model.SavedSearch) error
+
+	// Get возвращает поиск по ID
+	Get(ctx context.Context, id string) (model.SavedSearch, error)
+
+	// Delete удаляет поиск
+	Delete(ctx context.Context, id string) error
+
+	// ListByOwner возвращает поиски пользователя в порядке создания
+	ListByOwner(ctx context.Context, ownerID string) ([]model.SavedSearch, error)
+}
+
+// MetricRepository интерфейс для хранения значений метрик, загруженных пользователями
+type MetricRepository interface {
+	// Append сохраняет значения метрик
+	// Хранилище может вытеснять самые старые значения
+	Append(ctx context.Context, points []model.MetricPoint) error
+
+	// ForEach передает fn значения метрики name пользователя ownerID со временем в [from, to)
+	// в порядке времени. Нулевые from и to не ограничивают период
+	ForEach(ctx context.Context, ownerID, name string, from, to time.Time, fn func(model.MetricPoint) error) error
+}
+
+// AuditFilter условия выборки записей журнала аудита; пустые поля не ограничивают выборку
+type AuditFilter struct {
+	UserID   string    // Вызывающий пользователь
+	NoteID   string    // UUID заметки
+	Action   string    // Операция (model.AuditAction*)
+	From     time.Time // Записи не раньше From
+	To       time.Time // Записи раньше To
+	BeforeID int64     // Записи с ID меньше BeforeID (продолжение выборки)
+	Limit    int       // Максимальное количество записей (0 - без ограничения)
+}
+
+// AuditRepository интерфейс для хранения журнала аудита
+type AuditRepository interface {
+	// Append сохраняет запись и возвращает ее с назначенным порядковым номером
+	// Хранилище может вытеснять самые старые записи
+	Append(ctx context.Context, record model.AuditRecord) (model.AuditRecord, error)
+
+	// List возвращает записи, подходящие под filter, начиная с последней
+	List(ctx context.Context, filter AuditFilter) ([]model.AuditRecord, error)
+
+	// DeleteBefore удаляет записи старше before и возвращает количество удаленных записей
+	DeleteBefore(ctx context.Context, before time.Time) (int, error)
+}
+
+// BatchNoteRepository опциональное расширение NoteRepository для атомарных пакетных операций
+// Реализуется хранилищами, поддерживающими транзакции (в SQL - одна транзакция на пакет)
+// Если хранилище не реализует интерфейс, атомарные пакетные запросы отклоняются сервисом
+type BatchNoteRepository interface {
+	// CreateBatch создает все заметки или ни одной
+	CreateBatch(ctx context.Context, notes []model.Note) ([]model.Note, error)
+
+	// DeleteBatch удаляет все заметки или ни одной (если хотя бы одна не найдена)
+	DeleteBatch(ctx context.Context, ids []string) error
+}
+
+// DefaultIteratorBatchSize размер порции по умолчанию при обходе хранилища через NoteIterator
+const DefaultIteratorBatchSize = 100
+
+// NoteIterator опциональное расширение NoteRepository для обхода заметок без материализации
+// всего списка в памяти. Используется потоковыми методами (стриминг и экспорт заметок)
+type NoteIterator interface {
+	// ForEach вызывает fn для каждой заметки, читая хранилище порциями по batchSize
+	// Обход прекращается при первой ошибке fn или отмене контекста
+	ForEach(ctx context.Context, batchSize int, fn func(model.Note) error) error
+
+	// ForEachAfter как ForEach, но начинает обход с заметки, следующей за ID after (курсор)
+	// Пустой after означает обход с начала
+	ForEachAfter(ctx context.Context, after string, batchSize int, fn func(model.Note) error) error
+}
+
+// NoteComparator сравнивает две заметки для сортировки
+// Возвращает отрицательное число, если a < b, ноль при равенстве и положительное, если a > b
+type NoteComparator func(a, b model.Note) int
+
+// SortedNoteLister опциональное расширение NoteRepository для сортировки на стороне хранилища
+// Компаратор подключается извне (например, сравнение заголовков с учетом языка)
+type SortedNoteLister interface {
+	// ListSorted возвращает все заметки, упорядоченные компаратором cmp
+	ListSorted(ctx context.Context, cmp NoteComparator) ([]model.Note, error)
+}
+
+// NotePinner опциональное расширение NoteRepository для закрепления заметок
+// Закрепление не считается изменением содержимого: UpdatedAt не меняется, версия увеличивается
+// Если хранилище не реализует интерфейс, сервис закрепляет заметку через Update
+type NotePinner interface {
+	// SetPinned закрепляет (pinned = true) или открепляет заметку и возвращает ее
+	SetPinned(ctx context.Context, id string, pinned bool) (model.Note, error)
+}
+
+// NoteRewriter опциональное расширение NoteRepository для перезаписи хранимого представления
+// заметки без изменения ее метаданных (например, при повторном шифровании содержимого)
+// Если хранилище не реализует интерфейс, заметка перезаписывается через Update
+type NoteRewriter interface {
+	// Rewrite заменяет сохраненную заметку note.ID, не меняя UpdatedAt и версию
+	// Если версия заметки изменилась с note.Version, возвращается ошибка конфликта версий
+	Rewrite(ctx context.Context, note model.Note) error
+}
+
+// NoteRestorer опциональное расширение NoteRepository для восстановления заметок из резервной копии
+// Если хранилище не реализует интерфейс, заметка пересоздается через Delete и Create,
+// а время обновления и версия назначаются заново
+type NoteRestorer interface {
+	// Restore сохраняет заметку как есть (ID, владелец, время и версия), заменяя существующую
+	Restore(ctx context.Context, note model.Note) error
+}
+
+// TagIndex опциональное расширение NoteRepository со вторичным индексом по тегам
+// Теги заметок хранятся в каноническом виде (model.NormalizeTags)
+// Если хранилище не реализует интерфейс, сервис отбирает заметки полным просмотром
+type TagIndex interface {
+	// ListByTag возвращает заметки с тегом tag в порядке возрастания ID
+	ListByTag(ctx context.Context, tag string) ([]model.Note, error)
+
+	// ListTags возвращает все теги с количеством заметок, упорядоченные по тегу
+	ListTags(ctx context.Context) ([]model.TagCount, error)
+}
+
+// ConsistencyTracker опциональное расширение NoteRepository для чтения своих записей (read-your-writes)
+// между репликами сервера: после записи клиент получает токен позиции хранилища (LSN, номер записи),
+// а чтение с этим токеном выполняется только после того, как хранилище увидит эту позицию
+// Если хранилище не реализует интерфейс, токены не выдаются и не проверяются
+type ConsistencyTracker interface {
+	// ConsistencyToken возвращает непрозрачный токен позиции последней записи, видимой хранилищу
+	ConsistencyToken(ctx context.Context) (string, error)
+
+	// WaitForToken ждет, пока хранилище не увидит запись с токеном token, не дольше ctx
+	// Возвращает ErrInvalidConsistencyToken для чужого токена и ErrConsistencyLag, если позиция не достигнута
+	WaitForToken(ctx context.Context, token string) error
+}
+
+// HealthChecker опциональное расширение хранилища для проверки доступности
+// Если хранилище не реализует интерфейс, доступность проверяется чтением всех заметок
+type HealthChecker interface {
+	// Ping возвращает ошибку, если хранилище недоступно
+	Ping(ctx context.Context) error
+}
+
+// AttachmentRepository интерфейс для хранения вложений заметок
+// Содержимое передается потоком, реализация не должна загружать файл в память целиком
+type AttachmentRepository interface {
+	// Save сохраняет содержимое вложения размером attachment.Size байт
+	// Метаданные (ID, размер, контрольная сумма) должны быть заполнены вызывающей стороной
+	Save(ctx context.Context, attachment model.Attachment, data io.Reader) error
+
+	// Open возвращает метаданные вложения заметки и поток его содержимого
+	// Вызывающая сторона обязана закрыть поток
+	Open(ctx context.Context, noteID, id string) (model.Attachment, io.ReadCloser, error)
+
+	// DeleteByNoteID удаляет все вложения заметки
+	DeleteByNoteID(ctx context.Context, noteID string) error
+}
@@ -0,0 +1,418 @@
+// Package repositorytest содержит набор проверок контракта repository.NoteRepository
+// для новых хранилищ заметок в internal/repository (SQLite, Redis): хранилище доказывает
+// совместимость с сервисом одним вызовом TestNoteRepository из своего теста
+//
+//	func TestRepository_Conformance(t *testing.T) {
+//		repositorytest.TestNoteRepository(t, func(t *testing.T) repository.NoteRepository {
+//			return NewRepository()
+//		})
+//	}
+//
+// Кроме обязательных методов проверяются опциональные расширения, которые реализует хранилище:
+// NoteIterator (постраничный обход и отмена), BatchNoteRepository, NotePinner и TagIndex
+package repositorytest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"notes-service/internal/model"
+	"notes-service/internal/repository"
+	"notes-service/internal/repository/memory"
+)
+
+// timeTolerance допустимое расхождение времени после сохранения (хранилища округляют время, например до микросекунд)
+const timeTolerance = time.Millisecond
+
+// Factory создает пустое хранилище для одной проверки
+// Ресурсы хранилища (соединения, временные каталоги) освобождаются через t.Cleanup
+type Factory func(t *testing.T) repository.NoteRepository
+
+// TestNoteRepository проверяет хранилище, созданное newRepository, на соответствие контракту:
+//   - Create назначает ID (если не задан), время создания и изменения, версию 1 и владельца из контекста;
+//   - отсутствующая или чужая заметка - ошибка memory.ErrNoteNotFound, устаревшая версия - memory.ErrVersionConflict;
+//   - Update увеличивает версию и время изменения и не меняет владельца;
+//   - удаление окончательное: удаленная заметка не читается, не попадает в List и не удаляется повторно;
+//   - операции ограничены владельцем из контекста (repository.WithOwner);
+//   - хранилище безопасно для одновременного использования
+func TestNoteRepository(t *testing.T, newRepository Factory) {
+	t.Run("Create", func(t *testing.T) { testCreate(t, newRepository(t)) })
+	t.Run("NotFound", func(t *testing.T) { testNotFound(t, newRepository(t)) })
+	t.Run("Update", func(t *testing.T) { testUpdate(t, newRepository(t)) })
+	t.Run("Delete", func(t *testing.T) { testDelete(t, newRepository(t)) })
+	t.Run("OwnerScope", func(t *testing.T) { testOwnerScope(t, newRepository(t)) })
+	t.Run("Concurrency", func(t *testing.T) { testConcurrency(t, newRepository(t)) })
+
+	optional := newRepository(t)
+	if _, ok := optional.(repository.NoteIterator); ok {
+		t.Run("Iterator", func(t *testing.T) { testIterator(t, newRepository(t).(repository.NoteIterator)) })
+	}
+	if _, ok := optional.(repository.BatchNoteRepository); ok {
+		t.Run("Batch", func(t *testing.T) { testBatch(t, newRepository(t)) })
+	}
+	if _, ok := optional.(repository.NotePinner); ok {
+		t.Run("Pinner", func(t *testing.T) { testPinner(t, newRepository(t)) })
+	}
+	if _, ok := optional.(repository.TagIndex); ok {
+		t.Run("TagIndex", func(t *testing.T) { testTagIndex(t, newRepository(t)) })
+	}
+}
+
+// ownerContext возвращает контекст, ограниченный заметками владельца ownerID
+func ownerContext(ownerID string) context.Context {
+	return repository.WithOwner(context.Background(), ownerID)
+}
+
+// create создает заметку и останавливает проверку при ошибке
+func create(t *testing.T, repo repository.NoteRepository, ctx context.Context, note model.Note) model.Note {
+	t.Helper()
+	created, err := repo.Create(ctx, note)
+	if err != nil {
+		t.Fatalf("Create(%q) error: %v", note.Title, err)
+	}
+	return created
+}
+
+// sameTime сравнивает время с учетом округления в хранилище
+func sameTime(a, b time.Time) bool {
+	return a.Sub(b).Abs() < timeTolerance
+}
+
+func testCreate(t *testing.T, repo repository.NoteRepository) {
+	ctx := ownerContext("alice")
+	before := time.Now().Add(-timeTolerance)
+
+	note := create(t, repo, ctx, model.Note{Title: "First", Content: "Content", Tags: []string{"go", "work"}})
+	if note.ID == "" {
+		t.Errorf("Expected Create to assign an ID")
+	}
+	if note.Version != 1 {
+		t.Errorf("Expected version 1, got %d", note.Version)
+	}
+	if note.OwnerID != "alice" {
+		t.Errorf("Expected owner from context, got %q", note.OwnerID)
+	}
+	if note.CreatedAt.Before(before) || note.UpdatedAt.Before(before) {
+		t.Errorf("Expected creation timestamps to be set, got created %v, updated %v", note.CreatedAt, note.UpdatedAt)
+	}
+
+	stored, err := repo.GetByID(ctx, note.ID)
+	if err != nil {
+		t.Fatalf("GetByID error: %v", err)
+	}
+	if stored.Title != "First" || stored.Content != "Content" || !slices.Equal(stored.Tags, []string{"go", "work"}) ||
+		stored.Version != 1 || !sameTime(stored.CreatedAt, note.CreatedAt) || !sameTime(stored.UpdatedAt, note.UpdatedAt) {
+		t.Errorf("Expected stored note to match created note, got %+v, created %+v", stored, note)
+	}
+
+	// Заданные ID и время создания сохраняются (восстановление, импорт)
+	createdAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	imported := create(t, repo, ctx, model.Note{ID: "imported-note", Title: "Imported", CreatedAt: createdAt})
+	if imported.ID != "imported-note" || !sameTime(imported.CreatedAt, createdAt) {
+		t.Errorf("Expected given ID and creation time to be kept, got %q, %v", imported.ID, imported.CreatedAt)
+	}
+
+	// Изменение возвращенной заметки не меняет хранилище
+	note.Tags[0] = "changed"
+	if stored, _ := repo.GetByID(ctx, note.ID); stored.Tags[0] != "go" {
+		t.Errorf("Expected stored tags not to share memory with the caller, got %v", stored.Tags)
+	}
+}
+
+func testNotFound(t *testing.T, repo repository.NoteRepository) {
+	ctx := ownerContext("alice")
+	if _, err := repo.GetByID(ctx, "missing"); !errors.Is(err, memory.ErrNoteNotFound) {
+		t.Errorf("Expected ErrNoteNotFound from GetByID, got: %v", err)
+	}
+	if _, err := repo.Update(ctx, model.Note{ID: "missing", Title: "Missing"}); !errors.Is(err, memory.ErrNoteNotFound) {
+		t.Errorf("Expected ErrNoteNotFound from Update, got: %v", err)
+	}
+	if err := repo.Delete(ctx, "missing"); !errors.Is(err, memory.ErrNoteNotFound) {
+		t.Errorf("Expected ErrNoteNotFound from Delete, got: %v", err)
+	}
+	if notes, err := repo.List(ctx); err != nil || len(notes) != 0 {
+		t.Errorf("Expected empty list, got %d notes, %v", len(notes), err)
+	}
+}
+
+func testUpdate(t *testing.T, repo repository.NoteRepository) {
+	ctx := ownerContext("alice")
+	note := create(t, repo, ctx, model.Note{Title: "Draft"})
+
+	change := note
+	change.Title = "Final"
+	change.OwnerID = "mallory"
+	updated, err := repo.Update(ctx, change)
+	if err != nil {
+		t.Fatalf("Update error: %v", err)
+	}
+	if updated.Version != note.Version+1 {
+		t.Errorf("Expected version %d, got %d", note.Version+1, updated.Version)
+	}
+	if updated.UpdatedAt.Before(note.UpdatedAt) {
+		t.Errorf("Expected update time not to go back, got %v before %v", updated.UpdatedAt, note.UpdatedAt)
+	}
+	if updated.OwnerID != "alice" || !sameTime(updated.CreatedAt, note.CreatedAt) {
+		t.Errorf("Expected owner and creation time to be kept, got %q, %v", updated.OwnerID, updated.CreatedAt)
+	}
+	if stored, _ := repo.GetByID(ctx, note.ID); stored.Title != "Final" || stored.Version != updated.Version {
+		t.Errorf("Expected stored update, got %+v", stored)
+	}
+
+	// Обновление устаревшей версии отклоняется, нулевая версия не проверяется
+	if _, err := repo.Update(ctx, change); !errors.Is(err, memory.ErrVersionConflict) {
+		t.Errorf("Expected ErrVersionConflict for stale version, got: %v", err)
+	}
+	change.Version = 0
+	if _, err := repo.Update(ctx, change); err != nil {
+		t.Errorf("Expected update without version to succeed, got: %v", err)
+	}
+}
+
+func testDelete(t *testing.T, repo repository.NoteRepository) {
+	ctx := ownerContext("alice")
+	kept := create(t, repo, ctx, model.Note{Title: "Kept"})
+	deleted := create(t, repo, ctx, model.Note{Title: "Deleted"})
+
+	if err := repo.Delete(ctx, deleted.ID); err != nil {
+		t.Fatalf("Delete error: %v", err)
+	}
+	if _, err := repo.GetByID(ctx, deleted.ID); !errors.Is(err, memory.ErrNoteNotFound) {
+		t.Errorf("Expected deleted note to be gone, got: %v", err)
+	}
+	if err := repo.Delete(ctx, deleted.ID); !errors.Is(err, memory.ErrNoteNotFound) {
+		t.Errorf("Expected ErrNoteNotFound for repeated delete, got: %v", err)
+	}
+	if _, err := repo.Update(ctx, deleted); !errors.Is(err, memory.ErrNoteNotFound) {
+		t.Errorf("Expected ErrNoteNotFound when updating deleted note, got: %v", err)
+	}
+
+	notes, err := repo.List(ctx)
+	if err != nil {
+		t.Fatalf("List error: %v", err)
+	}
+	if len(notes) != 1 || notes[0].ID != kept.ID {
+		t.Errorf("Expected only the kept note in list, got %+v", notes)
+	}
+}
+
+func testOwnerScope(t *testing.T, repo repository.NoteRepository) {
+	alice, bob := ownerContext("alice"), ownerContext("bob")
+	note := create(t, repo, alice, model.Note{Title: "Alice"})
+	create(t, repo, bob, model.Note{Title: "Bob"})
+
+	if _, err := repo.GetByID(bob, note.ID); !errors.Is(err, memory.ErrNoteNotFound) {
+		t.Errorf("Expected other owner's note to be not found, got: %v", err)
+	}
+	if _, err := repo.Update(bob, model.Note{ID: note.ID, Title: "Hijacked"}); !errors.Is(err, memory.ErrNoteNotFound) {
+		t.Errorf("Expected ErrNoteNotFound when updating other owner's note, got: %v", err)
+	}
+	if err := repo.Delete(bob, note.ID); !errors.Is(err, memory.ErrNoteNotFound) {
+		t.Errorf("Expected ErrNoteNotFound when deleting other owner's note, got: %v", err)
+	}
+
+	notes, err := repo.List(bob)
+	if err != nil {
+		t.Fatalf("List error: %v", err)
+	}
+	if len(notes) != 1 || notes[0].OwnerID != "bob" {
+		t.Errorf("Expected only bob's note, got %+v", notes)
+	}
+	if stored, err := repo.GetByID(alice, note.ID); err != nil || stored.Title != "Alice" {
+		t.Errorf("Expected alice's note unchanged, got %+v, %v", stored, err)
+	}
+}
+
+func testConcurrency(t *testing.T, repo repository.NoteRepository) {
+	const workers, perWorker = 8, 25
+	ctx := ownerContext("alice")
+
+	var wg sync.WaitGroup
+	errs := make(chan error, workers)
+	for w := range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range perWorker {
+				note, err := repo.Create(ctx, model.Note{Title: fmt.Sprintf("Note %d-%d", w, i)})
+				if err == nil {
+					note.Title += " updated"
+					_, err = repo.Update(ctx, note)
+				}
+				if err == nil {
+					_, err = repo.List(ctx)
+				}
+				if err != nil {
+					errs <- err
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Errorf("Concurrent operation error: %v", err)
+	}
+
+	notes, err := repo.List(ctx)
+	if err != nil {
+		t.Fatalf("List error: %v", err)
+	}
+	ids := make(map[string]bool, len(notes))
+	for _, note := range notes {
+		if note.Version != 2 || !strings.HasSuffix(note.Title, " updated") {
+			t.Errorf("Expected every note to be updated once, got %+v", note)
+		}
+		ids[note.ID] = true
+	}
+	if len(ids) != workers*perWorker {
+		t.Errorf("Expected %d unique notes, got %d", workers*perWorker, len(ids))
+	}
+}
+
+func testIterator(t *testing.T, repo repository.NoteIterator) {
+	notes := repo.(repository.NoteRepository)
+	ctx := ownerContext("alice")
+	var want []string
+	for i := range 10 {
+		want = append(want, create(t, notes, ctx, model.Note{ID: fmt.Sprintf("note-%02d", i), Title: "Note"}).ID)
+	}
+	create(t, notes, ownerContext("bob"), model.Note{ID: "note-05-bob", Title: "Bob"})
+
+	collect := func(after string, batchSize int) []string {
+		t.Helper()
+		var got []string
+		err := repo.ForEachAfter(ctx, after, batchSize, func(note model.Note) error {
+			got = append(got, note.ID)
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("ForEachAfter(%q, %d) error: %v", after, batchSize, err)
+		}
+		return got
+	}
+
+	// Порядок по возрастанию ID при любом размере порции, только заметки владельца
+	for _, batchSize := range []int{0, 1, 3, 100} {
+		if got := collect("", batchSize); !slices.Equal(got, want) {
+			t.Errorf("Expected %v with batch size %d, got %v", want, batchSize, got)
+		}
+	}
+	// Курсор: обход продолжается со следующей заметки, в том числе после удаленной
+	if got := collect("note-04", 2); !slices.Equal(got, want[5:]) {
+		t.Errorf("Expected %v after cursor, got %v", want[5:], got)
+	}
+	if err := notes.Delete(ctx, "note-07"); err != nil {
+		t.Fatalf("Delete error: %v", err)
+	}
+	if got := collect("note-07", 2); !slices.Equal(got, want[8:]) {
+		t.Errorf("Expected %v after deleted cursor, got %v", want[8:], got)
+	}
+
+	// Ошибка fn прекращает обход и возвращается как есть
+	stop := errors.New("stop")
+	visited := 0
+	err := repo.ForEach(ctx, 2, func(model.Note) error {
+		visited++
+		if visited == 3 {
+			return stop
+		}
+		return nil
+	})
+	if !errors.Is(err, stop) || visited != 3 {
+		t.Errorf("Expected iteration to stop on callback error, got %v after %d notes", err, visited)
+	}
+
+	// Отмена контекста прекращает обход
+	cancelled, cancel := context.WithCancel(ctx)
+	visited = 0
+	err = repo.ForEach(cancelled, 1, func(model.Note) error {
+		visited++
+		cancel()
+		return nil
+	})
+	if !errors.Is(err, context.Canceled) || visited != 1 {
+		t.Errorf("Expected context.Canceled after the first note, got %v after %d notes", err, visited)
+	}
+}
+
+func testBatch(t *testing.T, repo repository.NoteRepository) {
+	batchRepo := repo.(repository.BatchNoteRepository)
+	ctx := ownerContext("alice")
+
+	created, err := batchRepo.CreateBatch(ctx, []model.Note{{Title: "One"}, {Title: "Two"}})
+	if err != nil {
+		t.Fatalf("CreateBatch error: %v", err)
+	}
+	if len(created) != 2 || created[0].ID == "" || created[0].ID == created[1].ID || created[1].OwnerID != "alice" {
+		t.Fatalf("Expected two notes with distinct IDs, got %+v", created)
+	}
+
+	// Пакет с отсутствующей заметкой не удаляет ни одной
+	if err := batchRepo.DeleteBatch(ctx, []string{created[0].ID, "missing"}); !errors.Is(err, memory.ErrNoteNotFound) {
+		t.Errorf("Expected ErrNoteNotFound for batch with missing note, got: %v", err)
+	}
+	if _, err := repo.GetByID(ctx, created[0].ID); err != nil {
+		t.Errorf("Expected failed batch to keep notes, got: %v", err)
+	}
+
+	if err := batchRepo.DeleteBatch(ctx, []string{created[0].ID, created[1].ID}); err != nil {
+		t.Fatalf("DeleteBatch error: %v", err)
+	}
+	if notes, _ := repo.List(ctx); len(notes) != 0 {
+		t.Errorf("Expected batch delete to remove all notes, got %d", len(notes))
+	}
+}
+
+func testPinner(t *testing.T, repo repository.NoteRepository) {
+	ctx := ownerContext("alice")
+	note := create(t, repo, ctx, model.Note{Title: "Pinned"})
+
+	pinned, err := repo.(repository.NotePinner).SetPinned(ctx, note.ID, true)
+	if err != nil {
+		t.Fatalf("SetPinned error: %v", err)
+	}
+	if !pinned.Pinned || pinned.Version != note.Version+1 || !sameTime(pinned.UpdatedAt, note.UpdatedAt) {
+		t.Errorf("Expected pin to bump version and keep update time, got %+v", pinned)
+	}
+	if _, err := repo.(repository.NotePinner).SetPinned(ctx, "missing", true); !errors.Is(err, memory.ErrNoteNotFound) {
+		t.Errorf("Expected ErrNoteNotFound, got: %v", err)
+	}
+}
+
+func testTagIndex(t *testing.T, repo repository.NoteRepository) {
+	index := repo.(repository.TagIndex)
+	ctx := ownerContext("alice")
+	second := create(t, repo, ctx, model.Note{ID: "b", Title: "Second", Tags: []string{"go"}})
+	create(t, repo, ctx, model.Note{ID: "a", Title: "First", Tags: []string{"go", "work"}})
+	create(t, repo, ownerContext("bob"), model.Note{ID: "c", Title: "Bob", Tags: []string{"go"}})
+
+	second.Tags = []string{"work"}
+	if _, err := repo.Update(ctx, second); err != nil {
+		t.Fatalf("Update error: %v", err)
+	}
+
+	notes, err := index.ListByTag(ctx, "go")
+	if err != nil {
+		t.Fatalf("ListByTag error: %v", err)
+	}
+	if len(notes) != 1 || notes[0].ID != "a" {
+		t.Errorf("Expected only note a with tag go, got %+v", notes)
+	}
+
+	tags, err := index.ListTags(ctx)
+	if err != nil {
+		t.Fatalf("ListTags error: %v", err)
+	}
+	want := []model.TagCount{{Tag: "go", Count: 1}, {Tag: "work", Count: 2}}
+	if !slices.Equal(tags, want) {
+		t.Errorf("Expected %v, got %v", want, tags)
+	}
+}
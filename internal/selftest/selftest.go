@@ -0,0 +1,290 @@
+// Package selftest проверяет уже запущенный сервер заметок через его собственные порты: создание,
+// чтение, список и удаление заметки по gRPC, доставку события подписчику и JSON запрос через HTTP Gateway.
+// Используется как проверка контейнера после запуска и после развертывания (server --selftest)
+package selftest
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	notesv1 "notes-service/pkg/proto/notes/v1"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// eventTimeout время ожидания события о заметке проверки
+	eventTimeout = 5 * time.Second
+
+	// readyPollInterval интервал проверки /readyz до начала сценария
+	readyPollInterval = 100 * time.Millisecond
+
+	// cleanupTimeout время удаления заметки проверки, если сценарий прервался
+	cleanupTimeout = 5 * time.Second
+
+	// noteTitle заголовок заметки проверки
+	noteTitle = "Self-test note"
+)
+
+// ErrNoToken возвращается, когда не задан токен пользователя проверки
+var ErrNoToken = errors.New("self-test token is not configured")
+
+// Target адреса и учетные данные проверяемого сервера
+type Target struct {
+	GRPCAddr string      // Адрес gRPC сервера, например localhost:50051
+	HTTPURL  string      // Адрес HTTP Gateway, например http://localhost:8080
+	TLS      *tls.Config // TLS подключения к gRPC серверу (nil - без TLS)
+	Token    string      // Токен пользователя, от имени которого создается заметка
+}
+
+// env клиенты сервера и состояние, передаваемое между шагами
+type env struct {
+	client  notesv1.NotesServiceClient
+	http    *http.Client
+	baseURL string
+	token   string
+
+	noteID string // Заметка, созданная проверкой
+}
+
+// step шаг проверки
+type step struct {
+	name string
+	run  func(ctx context.Context, e *env) error
+}
+
+// scenario шаги выполняются по порядку над одной заметкой
+var scenario = []step{
+	{"gRPC: create note", createNote},
+	{"gRPC: get note", getNote},
+	{"gRPC: list notes", listNotes},
+	{"Events: NoteUpdated round-trip", eventRoundTrip},
+	{"Gateway: get note as JSON", gatewayGetNote},
+	{"gRPC: delete note", deleteNote},
+}
+
+// Run ожидает готовности сервера (/readyz) и выполняет проверку
+// Результат каждого шага пишется в out, возвращается ошибка первого неуспешного шага.
+// Заметка проверки удаляется и при ошибке, чтобы повторные проверки не оставляли данных
+func Run(ctx context.Context, target Target, out io.Writer) error {
+	if target.Token == "" {
+		return ErrNoToken
+	}
+	creds := insecure.NewCredentials()
+	if target.TLS != nil {
+		creds = credentials.NewTLS(target.TLS)
+	}
+	// Запросы ожидают подключения к gRPC серверу, если он еще не начал принимать соединения
+	conn, err := grpc.NewClient(target.GRPCAddr, grpc.WithTransportCredentials(creds),
+		grpc.WithDefaultCallOptions(grpc.WaitForReady(true)))
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	e := &env{
+		client:  notesv1.NewNotesServiceClient(conn),
+		http:    &http.Client{Timeout: 10 * time.Second},
+		baseURL: target.HTTPURL,
+		token:   target.Token,
+	}
+	if err := e.waitReady(ctx); err != nil {
+		fmt.Fprintf(out, "❌ Server ready: %v\n", err)
+		return fmt.Errorf("server is not ready: %w", err)
+	}
+	defer e.cleanup(ctx)
+
+	for _, s := range scenario {
+		started := time.Now()
+		if err := s.run(ctx, e); err != nil {
+			fmt.Fprintf(out, "❌ %s: %v\n", s.name, err)
+			return fmt.Errorf("step %q failed: %w", s.name, err)
+		}
+		fmt.Fprintf(out, "✅ %s (%s)\n", s.name, time.Since(started).Round(time.Millisecond))
+	}
+	return nil
+}
+
+func createNote(ctx context.Context, e *env) error {
+	resp, err := e.client.CreateNote(e.withToken(ctx), &notesv1.CreateNoteRequest{
+		Title:   noteTitle,
+		Content: "Created by the startup self-test and deleted right after it",
+	})
+	if err != nil {
+		return err
+	}
+	if resp.GetNote().GetId() == "" {
+		return errors.New("response has no note id")
+	}
+	e.noteID = resp.GetNote().GetId()
+	return nil
+}
+
+func getNote(ctx context.Context, e *env) error {
+	resp, err := e.client.GetNote(e.withToken(ctx), &notesv1.GetNoteRequest{Id: e.noteID})
+	if err != nil {
+		return err
+	}
+	if resp.GetNote().GetTitle() != noteTitle {
+		return fmt.Errorf("expected title %q, got %q", noteTitle, resp.GetNote().GetTitle())
+	}
+	return nil
+}
+
+func listNotes(ctx context.Context, e *env) error {
+	resp, err := e.client.ListNotes(e.withToken(ctx), &notesv1.ListNotesRequest{})
+	if err != nil {
+		return err
+	}
+	for _, note := range resp.GetNotes() {
+		if note.GetId() == e.noteID {
+			return nil
+		}
+	}
+	return fmt.Errorf("note %s is missing from %d listed notes", e.noteID, len(resp.GetNotes()))
+}
+
+func eventRoundTrip(ctx context.Context, e *env) error {
+	ctx, cancel := context.WithTimeout(e.withToken(ctx), eventTimeout)
+	defer cancel()
+
+	stream, err := e.client.SubscribeToEvents(ctx, &notesv1.SubscribeToEventsRequest{})
+	if err != nil {
+		return err
+	}
+	// Приветственное сообщение подтверждает, что подписка зарегистрирована
+	welcome, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+	if welcome.GetHealthCheck() == nil {
+		return fmt.Errorf("expected welcome health check, got %T", welcome.GetEvent())
+	}
+
+	if _, err := e.client.UpdateNote(ctx, &notesv1.UpdateNoteRequest{Id: e.noteID, Content: "Updated by the startup self-test"}); err != nil {
+		return err
+	}
+	for {
+		resp, err := stream.Recv()
+		if err != nil {
+			return fmt.Errorf("no NoteUpdated event for note %s: %w", e.noteID, err)
+		}
+		if resp.GetNoteUpdated().GetNote().GetId() == e.noteID {
+			return nil
+		}
+	}
+}
+
+func gatewayGetNote(ctx context.Context, e *env) error {
+	var resp struct {
+		Note struct {
+			ID    string `json:"id"`
+			Title string `json:"title"`
+		} `json:"note"`
+	}
+	if err := e.getJSON(ctx, "/api/v1/notes/v1/"+e.noteID, &resp); err != nil {
+		return err
+	}
+	if resp.Note.ID != e.noteID || resp.Note.Title != noteTitle {
+		return fmt.Errorf("expected note %s titled %q, got %s titled %q", e.noteID, noteTitle, resp.Note.ID, resp.Note.Title)
+	}
+	return nil
+}
+
+func deleteNote(ctx context.Context, e *env) error {
+	ctx = e.withToken(ctx)
+	id := e.noteID
+	if _, err := e.client.DeleteNote(ctx, &notesv1.DeleteNoteRequest{Id: id}); err != nil {
+		return err
+	}
+	e.noteID = ""
+
+	_, err := e.client.GetNote(ctx, &notesv1.GetNoteRequest{Id: id})
+	if status.Code(err) != codes.NotFound {
+		return fmt.Errorf("expected NotFound for deleted note, got %v", err)
+	}
+	return nil
+}
+
+// cleanup удаляет заметку проверки, если сценарий прервался до шага удаления
+func (e *env) cleanup(ctx context.Context) {
+	if e.noteID == "" {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), cleanupTimeout)
+	defer cancel()
+	_, _ = e.client.DeleteNote(e.withToken(ctx), &notesv1.DeleteNoteRequest{Id: e.noteID})
+}
+
+// waitReady ожидает, пока /readyz ответит 200: gRPC сервер, Gateway и хранилище готовы
+func (e *env) waitReady(ctx context.Context) error {
+	ticker := time.NewTicker(readyPollInterval)
+	defer ticker.Stop()
+
+	var lastErr error
+	for {
+		if lastErr = e.ready(ctx); lastErr == nil {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%w (last check: %v)", ctx.Err(), lastErr)
+		case <-ticker.C:
+		}
+	}
+}
+
+// ready выполняет одну проверку /readyz
+func (e *env) ready(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, e.baseURL+"/readyz", nil)
+	if err != nil {
+		return err
+	}
+	resp, err := e.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GET /readyz: HTTP %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// getJSON выполняет GET запрос к Gateway с токеном проверки и декодирует JSON ответ 200 в out
+func (e *env) getJSON(ctx context.Context, path string, out any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, e.baseURL+path, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+e.token)
+
+	resp, err := e.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GET %s: HTTP %d", path, resp.StatusCode)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("GET %s: invalid response: %w", path, err)
+	}
+	return nil
+}
+
+// withToken добавляет токен проверки в метаданные gRPC запроса
+func (e *env) withToken(ctx context.Context) context.Context {
+	return metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+e.token)
+}
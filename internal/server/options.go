@@ -0,0 +1,73 @@
+package server
+
+import (
+	"time"
+
+	"notes-service/internal/repository"
+	notesService "notes-service/internal/service/notes"
+
+	"google.golang.org/grpc"
+)
+
+// Option заменяет компонент, который Initialize иначе создает по умолчанию
+// Так встраивающий код и тесты подменяют хранилище, шину событий или время без копирования Initialize
+type Option func(*options)
+
+type options struct {
+	noteRepository     repository.NoteRepository
+	mirrorRepository   repository.NoteRepository
+	dataKeyRepository  repository.DataKeyRepository
+	eventService       notesService.EventBus
+	clock              func() time.Time
+	unaryInterceptors  []grpc.UnaryServerInterceptor
+	streamInterceptors []grpc.StreamServerInterceptor
+}
+
+// WithRepository задает хранилище заметок (по умолчанию in-memory)
+// Шифрование содержимого из секции encryption применяется и к переданному хранилищу
+func WithRepository(noteRepository repository.NoteRepository) Option {
+	return func(o *options) {
+		o.noteRepository = noteRepository
+	}
+}
+
+// WithMirrorRepository задает второе хранилище заметок, в которое секция mirror дублирует запросы чтения
+// Шифрование содержимого из секции encryption применяется и ко второму хранилищу
+func WithMirrorRepository(noteRepository repository.NoteRepository) Option {
+	return func(o *options) {
+		o.mirrorRepository = noteRepository
+	}
+}
+
+// WithDataKeyRepository задает хранилище ключей данных владельцев (по умолчанию in-memory)
+// Ключи данных должны храниться так же долго, как заметки: без них содержимое не расшифровать
+func WithDataKeyRepository(dataKeyRepository repository.DataKeyRepository) Option {
+	return func(o *options) {
+		o.dataKeyRepository = dataKeyRepository
+	}
+}
+
+// WithEventBus задает сервис событий, общий для сервиса заметок, напоминаний и выгрузок
+// По умолчанию создается журнал размером server.event_log_size с часами из WithClock,
+// подключенный к брокеру из секции events (NATS или Redis) (переданная шина секцию events не учитывает)
+func WithEventBus(eventService notesService.EventBus) Option {
+	return func(o *options) {
+		o.eventService = eventService
+	}
+}
+
+// WithClock задает источник текущего времени для сервиса заметок, событий по умолчанию,
+// планировщика напоминаний и выгрузок (по умолчанию time.Now)
+func WithClock(now func() time.Time) Option {
+	return func(o *options) {
+		o.clock = now
+	}
+}
+
+// WithInterceptors добавляет gRPC интерцепторы после встроенных (см. grpc.WithInterceptors)
+func WithInterceptors(unary []grpc.UnaryServerInterceptor, stream []grpc.StreamServerInterceptor) Option {
+	return func(o *options) {
+		o.unaryInterceptors = append(o.unaryInterceptors, unary...)
+		o.streamInterceptors = append(o.streamInterceptors, stream...)
+	}
+}
@@ -0,0 +1,78 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"notes-service/internal/api/grpc/interceptors"
+	"notes-service/internal/config"
+	"notes-service/internal/logging"
+	"notes-service/internal/pipeline"
+)
+
+// ConfigChanged применяет настройки, которые меняются без перезапуска (config.ChangeHook):
+// logger.level, server.rate_limit (скорость и бюджет), CORS и лимит запросов Gateway,
+// streaming.heartbeat_interval. Остальные изменения действуют после перезапуска сервера
+// Ошибочные значения не применяются, остальные настройки применяются
+func (s *Server) ConfigChanged(previous, current *config.Config) error {
+	var errs []error
+
+	if current.Logger != nil {
+		if err := logging.SetLevel(current.Logger.Level); err != nil {
+			errs = append(errs, fmt.Errorf("invalid logger.level: %w", err))
+		} else if previous.Logger == nil || previous.Logger.Level != current.Logger.Level {
+			log.Printf("[Config] Log level set to %s", logging.Level())
+		}
+	}
+
+	if current.Server != nil {
+		if err := s.applyRateLimit(current.Server.RateLimit); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if current.Gateway != nil && s.gatewayLive != nil {
+		s.gatewayLive.Apply(current.Gateway)
+	}
+
+	if current.Streaming != nil && current.Streaming.HeartbeatInterval != 0 && s.noteHandler != nil {
+		if interval := current.Streaming.HeartbeatInterval; interval < time.Second {
+			errs = append(errs, fmt.Errorf("streaming.heartbeat_interval must be at least 1s (use units, e.g. 30s), got %s", interval))
+		} else {
+			s.noteHandler.SetEventHeartbeatInterval(interval)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// applyRateLimit меняет скорость и бюджет лимита запросов gRPC сервера
+// Включение выключенного при запуске лимита и смена ключа клиентов требуют перезапуска
+func (s *Server) applyRateLimit(cfg config.ConfigServerRateLimit) error {
+	if s.rateLimiter == nil {
+		if cfg.RequestsPerSecond > 0 {
+			log.Println("[Config] server.rate_limit was disabled at startup, restart the server to enable it")
+		}
+		return nil
+	}
+	if cfg.RequestsPerSecond < 0 {
+		return fmt.Errorf("server.rate_limit.requests_per_second must not be negative, got %g", cfg.RequestsPerSecond)
+	}
+	key := cfg.Key
+	if key == "" {
+		key = interceptors.RateLimitKeyUser
+	}
+	if current := s.rateLimiter.Settings()["key"]; key != current {
+		log.Printf("[Config] server.rate_limit.key change to %q requires a restart, keeping %q", key, current)
+	}
+
+	s.rateLimiter.SetLimit(cfg.RequestsPerSecond, cfg.Burst)
+	if s.Pipelines != nil {
+		settings := s.rateLimiter.Settings()
+		s.Pipelines.UpdateSettings(pipeline.ChainGRPCUnary, "rate_limit", settings)
+		s.Pipelines.UpdateSettings(pipeline.ChainGRPCStream, "rate_limit", settings)
+	}
+	return nil
+}
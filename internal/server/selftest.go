@@ -0,0 +1,46 @@
+package server
+
+import (
+	"context"
+	"io"
+	"net"
+	"time"
+
+	"notes-service/internal/selftest"
+)
+
+// defaultSelfTestTimeout время проверки, если selftest.timeout_seconds не задан
+const defaultSelfTestTimeout = 30 * time.Second
+
+// SelfTest проверяет запущенный сервер через его собственные порты (см. internal/selftest)
+// Вызывается после Start; результат шагов пишется в out
+func (s *Server) SelfTest(ctx context.Context, out io.Writer) error {
+	target := selftest.Target{
+		GRPCAddr: localAddr(s.GRPCAddr),
+		HTTPURL:  "http://" + localAddr(s.HTTPAddr),
+		TLS:      s.GatewayTLS,
+	}
+	timeout := defaultSelfTestTimeout
+	if cfg := s.Config.SelfTest; cfg != nil {
+		target.Token = cfg.Token
+		if cfg.TimeoutSeconds > 0 {
+			timeout = time.Duration(cfg.TimeoutSeconds) * time.Second
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	return selftest.Run(ctx, target, out)
+}
+
+// localAddr заменяет адрес прослушивания всех интерфейсов (0.0.0.0:port, :port) на localhost:port
+func localAddr(addr string) string {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return addr
+	}
+	if ip := net.ParseIP(host); host == "" || ip != nil && ip.IsUnspecified() {
+		host = "localhost"
+	}
+	return net.JoinHostPort(host, port)
+}
@@ -1,23 +1,70 @@
 package server
 
 import (
+	"cmp"
 	"context"
+	"crypto/tls"
 	"embed"
+	"errors"
 	"fmt"
 	"log"
+	"math"
 	"net"
 	"net/http"
+	"os"
+	"slices"
 	"strconv"
+	"strings"
 	"time"
 
+	"notes-service/internal/api/debug"
 	grpcapi "notes-service/internal/api/grpc"
+	"notes-service/internal/api/grpc/interceptors"
 	"notes-service/internal/api/grpcgateway"
 	"notes-service/internal/api/swagger"
+	"notes-service/internal/auth"
+	"notes-service/internal/buildinfo"
+	"notes-service/internal/compression"
 	"notes-service/internal/config"
+	"notes-service/internal/egress"
+	"notes-service/internal/events/nats"
+	"notes-service/internal/events/redis"
+	"notes-service/internal/logging"
+	"notes-service/internal/mirror"
+	"notes-service/internal/model"
+	"notes-service/internal/pipeline"
+	"notes-service/internal/portmux"
+	"notes-service/internal/recorder"
+	"notes-service/internal/repository"
+	"notes-service/internal/repository/attachments"
+	"notes-service/internal/repository/degraded"
+	"notes-service/internal/repository/encrypted"
 	"notes-service/internal/repository/memory"
+	"notes-service/internal/repository/postgres"
+	"notes-service/internal/service/apikeys"
+	"notes-service/internal/service/audit"
+	"notes-service/internal/service/backups"
+	"notes-service/internal/service/exports"
+	"notes-service/internal/service/keys"
+	"notes-service/internal/service/metrics"
 	notesService "notes-service/internal/service/notes"
+	"notes-service/internal/service/reminders"
+	"notes-service/internal/service/searches"
+	"notes-service/internal/service/suggest"
+	"notes-service/internal/service/usage"
+	"notes-service/internal/service/users"
+	"notes-service/internal/service/webhooks"
+	"notes-service/internal/telemetry"
+	"notes-service/internal/tenant"
+	"notes-service/internal/tlsconfig"
+	notesv1 "notes-service/pkg/proto/notes/v1"
 
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/keepalive"
 )
 
 // Server представляет сервер приложения с gRPC и HTTP Gateway
@@ -27,11 +74,16 @@ type Server struct {
 	HTTPAddr      string
 	GatewayCtx    context.Context
 	GatewayCancel context.CancelFunc
+	gatewayDone   chan struct{} // Закрывается после остановки Gateway
+	httpListener  net.Listener  // Соединения Gateway с общего порта (nil - Gateway слушает HTTPAddr)
+	portMux       *portmux.Mux  // Распределение соединений общего порта (nil, если single_port выключен)
 
 	// gRPC компоненты
 	GRPCServer *grpc.Server
 	GRPCAddr   string
 	Listener   net.Listener
+	GRPCTLS    *tls.Config // TLS gRPC сервера (nil - plaintext)
+	GatewayTLS *tls.Config // TLS подключения Gateway к gRPC серверу (nil - plaintext)
 
 	// Контекст сервера для graceful shutdown стримов
 	// Этот контекст отменяется при shutdown для корректного завершения стримов
@@ -43,40 +95,130 @@ type Server struct {
 
 	// Swagger спецификации
 	SwaggerSpecs embed.FS
+
+	// Запись запросов (nil, если выключена)
+	Recorder *recorder.Recorder
+
+	// Планировщик напоминаний заметок
+	Reminders *reminders.Scheduler
+
+	// Фоновая очистка истекших блокировок заметок
+	Locks notesService.LockSweeper
+
+	// Индекс подсказок заголовков заметок (SuggestNotes)
+	Suggestions *suggest.Index
+
+	// Выгрузки заметок в хранилище (nil, если хранилище не настроено)
+	Exports *exports.Manager
+
+	// Резервное копирование хранилища (nil, если хранилище копий не настроено)
+	Backups *backups.Manager
+
+	// Режим деградации хранилища заметок (nil, если выключен)
+	Degraded *degraded.Monitor
+
+	// Дублирование запросов чтения во второе хранилище заметок (nil, если выключено)
+	Mirror *mirror.Mirror
+
+	// Метрики gRPC сервера, Gateway и хранилища заметок для /metrics (nil, если выключены)
+	Telemetry *telemetry.Registry
+
+	// Трассировка OpenTelemetry с отправкой в OTLP коллектор (nil, если выключена)
+	Tracing *sdktrace.TracerProvider
+
+	// Пул соединений с PostgreSQL хранилища метрик (nil, если метрики хранятся не в PostgreSQL)
+	metricsDB *pgxpool.Pool
+
+	// Смена ключей шифрования заметок (nil, если шифрование не настроено)
+	KeyRotation *keys.Manager
+
+	// Вебхуки пользователей и доставка им событий
+	Webhooks *webhooks.Service
+
+	// Статистика использования (nil, если сбор выключен)
+	Usage *usage.Collector
+
+	// Журнал аудита изменений заметок и доступа к ним (nil, если журнал выключен)
+	Audit *audit.Service
+
+	// Отладочный порт: pprof, стеки горутин, метрики среды выполнения и channelz (nil, если выключен)
+	Debug *debug.Server
+
+	// Политика исходящих подключений: прокси, разрешенные адреса, таймауты и TLS
+	Egress *egress.Policy
+
+	// Шина событий NATS или Redis (nil, если события доставляются в пределах процесса)
+	EventBroker EventBroker
+
+	// Проверка токенов, общая для gRPC интерцепторов и HTTP Gateway
+	Authenticator auth.Authenticator
+
+	// Сессии AuthService (nil, если провайдер session не включен)
+	Sessions *auth.Sessions
+
+	// Ключи API AdminService (nil, если провайдер apikey не включен)
+	APIKeys *apikeys.Service
+
+	// Билеты стримов AuthService.IssueStreamTicket, общие для gRPC интерцептора и HTTP Gateway
+	StreamTickets *auth.StreamTickets
+
+	// Действующие цепочки gRPC интерцепторов и middleware Gateway (AdminService.GetPipeline)
+	Pipelines *pipeline.Registry
+
+	// Пользователи сервиса: владельцы заметок, получатели доступов и учетные записи входа по паролю
+	Users *users.Service
+
+	// Компоненты, настройки которых ConfigChanged меняет без перезапуска
+	noteHandler *grpcapi.Handler
+	rateLimiter *interceptors.RateLimiter // nil, если server.rate_limit выключен
+	gatewayLive *grpcgateway.LiveSettings
+
+	// Компоненты, заданные через Option вместо создаваемых по умолчанию
+	options options
 }
 
 // NewServer создает и инициализирует новый экземпляр сервера
-func NewServer(cfg *config.Config, swaggerSpecs embed.FS) (*Server, error) {
-	// Получаем порты из конфига с дефолтными значениями
-	grpcPort := cfg.Server.PortGRPC
-	httpPort := cfg.Server.PortHTTP
-
-	if grpcPort == 0 {
-		grpcPort = 50051
-		log.Printf("⚠️  Warning: PortGRPC is 0, using default 50051")
-	}
-	if httpPort == 0 {
-		httpPort = 8080
-		log.Printf("⚠️  Warning: PortHTTP is 0, using default 8080")
+// opts заменяют компоненты, которые Initialize создает по умолчанию
+func NewServer(cfg *config.Config, swaggerSpecs embed.FS, opts ...Option) (*Server, error) {
+	var serverOptions options
+	for _, opt := range opts {
+		opt(&serverOptions)
 	}
 
-	log.Printf("📋 Config loaded: gRPC port=%d, HTTP port=%d", grpcPort, httpPort)
-
-	// Проверка конфигурации Swagger
-	if cfg.Swagger == nil {
-		log.Printf("⚠️  Warning: Swagger config is nil")
-	} else {
-		log.Printf("📋 Swagger config: enabled=%v", cfg.Swagger.Enabled)
+	// Конфигурацию, созданную без config.InitConfig (при встраивании сервера), проверяем здесь
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config:\n%w", err)
 	}
+	grpcPort := cfg.Server.PortGRPC
+	httpPort := cfg.Server.PortHTTP
+	log.Printf("📋 Config loaded: gRPC port=%d, HTTP port=%d", grpcPort, httpPort)
 
 	grpcAddr := "0.0.0.0:" + strconv.Itoa(grpcPort)
 	httpAddr := "0.0.0.0:" + strconv.Itoa(httpPort)
 
+	// Сертификаты загружаются до открытия порта: ошибка в них не оставляет занятый порт
+	grpcTLS, gatewayTLS, err := newServerTLS(cfg.Server.TLS)
+	if err != nil {
+		return nil, err
+	}
+
+	// С single_port gRPC и HTTP Gateway принимают соединения на порту HTTP, порт gRPC не открывается
+	if cfg.Server.SinglePort {
+		grpcAddr = httpAddr
+	}
+
 	// Создаем listener для gRPC
 	listener, err := net.Listen("tcp", grpcAddr)
 	if err != nil {
 		return nil, fmt.Errorf("failed to listen on %s: %w", grpcAddr, err)
 	}
+	var portMux *portmux.Mux
+	var httpListener net.Listener
+	if cfg.Server.SinglePort {
+		portMux = portmux.New(listener)
+		listener, httpListener = portMux.GRPC(), portMux.HTTP()
+		log.Printf("📋 gRPC and HTTP Gateway share port %d", httpPort)
+	}
 
 	// Создаем контекст сервера для graceful shutdown стримов
 	// Этот контекст будет отменен при получении сигнала shutdown
@@ -97,31 +239,1199 @@ func NewServer(cfg *config.Config, swaggerSpecs embed.FS) (*Server, error) {
 		GatewayCancel: gatewayCancel,
 		GRPCAddr:      grpcAddr,
 		Listener:      listener,
+		httpListener:  httpListener,
+		portMux:       portMux,
+		GRPCTLS:       grpcTLS,
+		GatewayTLS:    gatewayTLS,
 		Ctx:           serverCtx,
 		Cancel:        serverCancel,
 		Config:        cfg,
 		SwaggerSpecs:  swaggerSpecs,
+		Pipelines:     pipeline.NewRegistry(),
+		options:       serverOptions,
 	}, nil
 }
 
 // Initialize инициализирует компоненты сервера (Repository → Service → Handler)
 func (s *Server) Initialize() error {
+	if s.Config.Logger != nil {
+		if err := logging.SetLevel(s.Config.Logger.Level); err != nil {
+			return fmt.Errorf("invalid logger.level: %w", err)
+		}
+	}
+
 	// Инициализация компонентов (DI): Repository → Service → Handler
-	noteRepo := memory.NewRepository()
-	log.Println("Initialized in-memory repository (map-based)")
+	noteRepo := s.options.noteRepository
+	if noteRepo == nil {
+		noteRepo = memory.NewRepository()
+		log.Println("Initialized in-memory repository (map-based)")
+	} else {
+		log.Printf("Using provided note repository (%T)", noteRepo)
+	}
 
-	noteSvc := notesService.NewNoteService(noteRepo)
+	clock := s.options.clock
+	if clock == nil {
+		clock = time.Now
+	}
+
+	egressPolicy, err := newEgressPolicy(s.Config.Egress)
+	if err != nil {
+		return err
+	}
+	s.Egress = egressPolicy
+
+	s.Tracing, err = newTracerProvider(s.Ctx, s.Config.Tracing, egressPolicy)
+	if err != nil {
+		return err
+	}
+	if s.Tracing != nil {
+		// Spans сервиса заметок и хранилища берутся из глобального провайдера
+		otel.SetTracerProvider(s.Tracing)
+	}
+
+	revisionRepo := memory.NewRevisionRepository()
+	log.Println("Initialized in-memory revision repository")
+
+	// Резервные копии содержат хранимое представление заметок и ревизий:
+	// при включенном шифровании содержимое остается в них зашифрованным
+	storedNoteRepo, storedRevisionRepo := noteRepo, revisionRepo
+
+	// Пока хранилище недоступно, чтение выполняется из снимка, а запись отклоняется
+	s.Degraded = newDegradedMonitor(s.Config.Degraded, noteRepo, clock)
+	if s.Degraded != nil {
+		noteRepo = s.Degraded.Repository()
+		log.Println("Enabled degraded mode for the note repository")
+	}
+
+	masterKeys, err := newKeyring(s.Config.Encryption)
+	if err != nil {
+		return err
+	}
+	var dataKeys *encrypted.DataKeys
+	if masterKeys != nil {
+		// Ключи данных владельцев зашифрованы мастер-ключами; смена ключей работает
+		// с вложенным хранилищем, в котором содержимое остается зашифрованным
+		dataKeyRepo := s.options.dataKeyRepository
+		if dataKeyRepo == nil {
+			dataKeyRepo = memory.NewDataKeyRepository()
+		}
+		dataKeys = encrypted.NewDataKeys(masterKeys, dataKeyRepo)
+		s.KeyRotation = keys.NewManager(s.Ctx, encrypted.NewRotator(storedNoteRepo, dataKeys), keys.WithClock(clock))
+		revisionRepo = encrypted.NewRevisionRepository(revisionRepo, noteRepo, dataKeys)
+		noteRepo = encrypted.NewRepository(noteRepo, dataKeys)
+		log.Println("Enabled note content encryption (AES-GCM, per-owner data keys)")
+	}
+
+	// Время операций учитывается вместе с режимом деградации и шифрованием, как его видит сервис
+	if s.Config.Telemetry != nil && s.Config.Telemetry.Enabled {
+		s.Telemetry = telemetry.NewRegistry()
+		log.Println("Enabled server metrics")
+	}
+	if s.Telemetry != nil || s.Tracing != nil {
+		noteRepo = telemetry.NewNoteRepository(noteRepo, s.Telemetry)
+	}
+
+	shareRepo := memory.NewShareRepository()
+	log.Println("Initialized in-memory share repository")
+
+	s.Users = users.NewService(memory.NewUserRepository(), users.WithClock(clock))
+	if err := seedUsers(s.Ctx, s.Users, s.Config.Auth); err != nil {
+		return err
+	}
+	log.Println("Initialized in-memory user repository")
+
+	// Планировщик напоминаний публикует события в тот же EventService, что и сервис заметок
+	eventService := s.options.eventService
+	if eventService == nil {
+		local := notesService.NewEventService(
+			notesService.WithEventLogSize(s.Config.Server.EventLogSize),
+			notesService.WithEventClock(clock),
+		)
+		broker, err := newEventBroker(s.Ctx, s.Config.Events, local, s.Egress)
+		if err != nil {
+			return err
+		}
+		eventService = local
+		if broker != nil {
+			s.EventBroker = broker
+			eventService = broker
+		}
+	}
+	s.Reminders = reminders.NewScheduler(noteRepo, eventService, reminders.WithClock(clock))
+	log.Println("Initialized reminder scheduler")
+
+	s.Webhooks = newWebhookService(s.Config.Webhooks, eventService, s.Egress, clock)
+	log.Println("Initialized webhook service")
+
+	// Совпадения сохраненных поисков проверяются при публикации событий сервисом заметок
+	searchRepo := memory.NewSavedSearchRepository()
+	log.Println("Initialized in-memory saved search repository")
+
+	noteOpts := []notesService.Option{
+		notesService.WithRevisionRepository(revisionRepo),
+		notesService.WithShareRepository(shareRepo),
+		notesService.WithUserDirectory(s.Users),
+		notesService.WithEventService(searches.NewBus(eventService, searchRepo)),
+		notesService.WithReminderScheduler(s.Reminders),
+		notesService.WithClock(clock),
+	}
+	if ttl := s.Config.Server.IdempotencyTTLSeconds; ttl > 0 {
+		noteOpts = append(noteOpts, notesService.WithIdempotencyTTL(time.Duration(ttl)*time.Second))
+	}
+	// Проверки парольных фраз записываются в тот же журнал, что и изменения заметок
+	s.Audit = newAuditService(s.Config.Audit, clock)
+	if s.Audit != nil {
+		noteOpts = append(noteOpts, notesService.WithAuditLog(s.Audit))
+		log.Printf("Initialized in-memory audit log (retention=%d days)", s.Config.Audit.RetentionDays)
+	} else {
+		log.Println("Audit log is disabled")
+	}
+	accessPolicy, err := grpcapi.ParseAccessPolicy(s.Config.Server.AccessDeniedPolicy)
+	if err != nil {
+		return err
+	}
+	handlerOpts := []grpcapi.HandlerOption{grpcapi.WithAccessPolicy(accessPolicy), grpcapi.WithWebhookService(s.Webhooks)}
+	if streaming := s.Config.Streaming; streaming != nil && streaming.HeartbeatInterval != 0 {
+		// Число без единиц разбирается как наносекунды
+		if streaming.HeartbeatInterval < time.Second {
+			return fmt.Errorf("streaming.heartbeat_interval must be at least 1s (use units, e.g. 30s), got %s", streaming.HeartbeatInterval)
+		}
+		handlerOpts = append(handlerOpts, grpcapi.WithEventHeartbeatInterval(streaming.HeartbeatInterval))
+	}
+	// Stats handler трассировки читает отправленные сообщения лениво, поэтому с ней пул не используется
+	pooledMessages := s.Tracing == nil && (s.Config.Streaming == nil || !s.Config.Streaming.DisableMessagePool)
+	handlerOpts = append(handlerOpts, grpcapi.WithPooledMessages(pooledMessages))
+	if s.KeyRotation != nil {
+		handlerOpts = append(handlerOpts, grpcapi.WithKeyRotation(s.KeyRotation))
+	}
+
+	attachmentRepo, err := newAttachmentRepository(s.Config.Attachments, s.Egress)
+	if err != nil {
+		return err
+	}
+	if attachmentRepo != nil {
+		maxSize := int64(s.Config.Attachments.MaxSizeMB) << 20
+		attachmentSvc := notesService.NewAttachmentService(noteRepo, attachmentRepo, maxSize)
+		noteOpts = append(noteOpts, notesService.WithAttachmentRepository(attachmentRepo))
+		handlerOpts = append(handlerOpts, grpcapi.WithAttachmentService(attachmentSvc))
+		log.Printf("Initialized attachment service (storage=%s)", s.Config.Attachments.Storage)
+	} else {
+		log.Printf("⚠️  Attachment storage is not configured, attachment RPCs are disabled")
+	}
+
+	noteSvc := notesService.NewNoteService(noteRepo, noteOpts...)
+	s.Locks = noteSvc.(notesService.LockSweeper)
 	log.Println("Initialized note service")
 
-	noteHandler := grpcapi.NewHandler(noteSvc, s.Ctx)
+	handlerOpts = append(handlerOpts, grpcapi.WithSavedSearchService(searches.NewService(searchRepo, noteSvc, searches.WithClock(clock))))
+
+	s.Suggestions = suggest.NewIndex(noteRepo, eventService, suggest.WithClock(clock))
+	handlerOpts = append(handlerOpts, grpcapi.WithSuggestIndex(s.Suggestions))
+
+	exportDestination, err := newExportDestination(s.Config.Exports, s.Egress)
+	if err != nil {
+		return err
+	}
+	if exportDestination != nil {
+		s.Exports = exports.NewManager(s.Ctx, noteSvc, exportDestination, eventService, exports.WithClock(clock))
+		handlerOpts = append(handlerOpts, grpcapi.WithExportManager(s.Exports))
+		log.Printf("Initialized export manager (destination=%s)", s.Config.Exports.Destination)
+	} else {
+		log.Printf("⚠️  Export destination is not configured, ExportToDestination is disabled")
+	}
+
+	backupDestination, err := newBackupDestination(s.Config.Backups, s.Egress)
+	if err != nil {
+		return err
+	}
+	if backupDestination != nil {
+		s.Backups = backups.NewManager(storedNoteRepo, storedRevisionRepo, shareRepo, backupDestination,
+			backups.WithInterval(time.Duration(s.Config.Backups.IntervalMinutes)*time.Minute),
+			backups.WithKeep(s.Config.Backups.Keep),
+			backups.WithReminderScheduler(s.Reminders),
+			backups.WithClock(clock),
+		)
+		handlerOpts = append(handlerOpts, grpcapi.WithBackupManager(s.Backups))
+		log.Printf("Initialized backup manager (destination=%s)", s.Config.Backups.Destination)
+	} else {
+		log.Printf("⚠️  Backup destination is not configured, scheduled backups are disabled")
+	}
+
+	metricRepo, err := s.newMetricRepository(s.Config.Metrics)
+	if err != nil {
+		return err
+	}
+	if metricRepo != nil {
+		handlerOpts = append(handlerOpts, grpcapi.WithMetricStore(metrics.NewStore(metricRepo, metrics.WithStoreClock(clock))))
+		log.Printf("Initialized metric storage (storage=%s)", s.Config.Metrics.Storage)
+	} else {
+		log.Printf("⚠️  Metric storage is not configured, uploaded metrics are not stored and QueryMetrics is disabled")
+	}
+
+	s.Usage = newUsageCollector(s.Config.Usage, s.Egress, clock)
+	if s.Usage != nil {
+		handlerOpts = append(handlerOpts, grpcapi.WithUsageCollector(s.Usage))
+		log.Printf("Initialized usage stats (remote reporting=%v)", s.Config.Usage.Endpoint != "")
+	} else {
+		log.Println("Usage stats are disabled")
+	}
+
+	noteHandler := grpcapi.NewHandler(noteSvc, s.Ctx, handlerOpts...)
+	s.noteHandler = noteHandler
 	log.Println("Initialized gRPC handler with server context for graceful shutdown")
 
+	s.Mirror, err = s.newMirror(s.Config.Mirror, shareRepo, dataKeys, accessPolicy, clock)
+	if err != nil {
+		return err
+	}
+
+	streamRateLimits, err := newStreamRateLimits(s.Config.Server.StreamRateLimits)
+	if err != nil {
+		return err
+	}
+	streamLifetimes, err := newStreamLifetimes(s.Config.Server.StreamLifetimes)
+	if err != nil {
+		return err
+	}
+	methodTimeouts, err := newMethodTimeouts(s.Config.Server.MethodTimeoutsSeconds)
+	if err != nil {
+		return err
+	}
+	rateLimiter, err := newRateLimiter(s.Config.Server.RateLimit)
+	if err != nil {
+		return err
+	}
+	s.rateLimiter = rateLimiter
+	s.gatewayLive = grpcgateway.NewLiveSettings(s.Config.Gateway)
+	publicMethods, err := parsePublicMethods(s.Config.Server.PublicMethods)
+	if err != nil {
+		return err
+	}
+	s.Authenticator, s.Sessions, s.APIKeys, err = newAuthenticator(s.Config.Auth, s.Users, s.Egress)
+	if err != nil {
+		return err
+	}
+	s.StreamTickets, err = newStreamTickets(s.Config.Auth)
+	if err != nil {
+		return err
+	}
+	serverOpts := []grpcapi.ServerOption{
+		grpcapi.WithAuthenticator(s.Authenticator),
+		grpcapi.WithSessions(s.Sessions),
+		grpcapi.WithAPIKeys(s.APIKeys),
+		grpcapi.WithStreamTickets(s.StreamTickets),
+		grpcapi.WithPipelineRegistry(s.Pipelines),
+		grpcapi.WithUserService(s.Users),
+		grpcapi.WithStreamRateLimits(streamRateLimits),
+		grpcapi.WithStreamLifetimes(streamLifetimes),
+		grpcapi.WithMethodTimeouts(methodTimeouts),
+		grpcapi.WithPublicMethods(publicMethods),
+		grpcapi.WithInterceptors(s.options.unaryInterceptors, s.options.streamInterceptors),
+	}
+	if s.GRPCTLS != nil {
+		serverOpts = append(serverOpts, grpcapi.WithTLS(s.GRPCTLS))
+	}
+	if s.Usage != nil {
+		serverOpts = append(serverOpts, grpcapi.WithUsageStats(s.Usage))
+	}
+	if s.Audit != nil {
+		serverOpts = append(serverOpts, grpcapi.WithAudit(s.Audit))
+	}
+	if s.Mirror != nil {
+		serverOpts = append(serverOpts, grpcapi.WithMirror(s.Mirror))
+	}
+	if s.Telemetry != nil {
+		serverOpts = append(serverOpts, grpcapi.WithMetrics(s.Telemetry))
+	}
+	if s.Tracing != nil {
+		serverOpts = append(serverOpts, grpcapi.WithTracing(s.Tracing))
+	}
+	if rateLimiter != nil {
+		serverOpts = append(serverOpts, grpcapi.WithRateLimiter(rateLimiter))
+	}
+	if s.Config.Authz != nil {
+		authz, err := newAuthz(s.Config.Authz)
+		if err != nil {
+			return err
+		}
+		serverOpts = append(serverOpts, grpcapi.WithAuthz(authz))
+	}
+	// Токены согласованности выдает само хранилище (позиция записи), а не обертки над ним
+	if tracker, ok := storedNoteRepo.(repository.ConsistencyTracker); ok {
+		serverOpts = append(serverOpts, grpcapi.WithConsistencyTracker(tracker))
+		log.Println("Enabled consistency tokens for read-your-writes")
+	}
+	payloadLogger, err := newPayloadLogger(s.Config.Logger)
+	if err != nil {
+		return err
+	}
+	if payloadLogger != nil {
+		serverOpts = append(serverOpts, grpcapi.WithPayloadLogging(payloadLogger))
+		log.Printf("⚠️  Payload logging is enabled (sample rate %s)", payloadLogger.Settings()["payload_sample_rate"])
+	}
+	rec, err := newRecorder(s.Config.Recorder)
+	if err != nil {
+		return err
+	}
+	if rec != nil {
+		s.Recorder = rec
+		serverOpts = append(serverOpts, grpcapi.WithRecorder(rec))
+		log.Printf("⚠️  Request recording is enabled (dir=%s)", s.Config.Recorder.Dir)
+	}
+	compressors, err := compression.ParseList(s.Config.Server.Compression)
+	if err != nil {
+		return fmt.Errorf("invalid server.compression: %w", err)
+	}
+	if len(compressors) > 0 {
+		serverOpts = append(serverOpts, grpcapi.WithCompression(compressors))
+	}
+	reflectionServices, err := newReflectionServices(s.Config.Server)
+	if err != nil {
+		return err
+	}
+	if reflectionServices != nil {
+		serverOpts = append(serverOpts, grpcapi.WithReflection(reflectionServices))
+	}
+	limits, err := newServerLimits(s.Config.Server.Limits)
+	if err != nil {
+		return fmt.Errorf("invalid server.limits: %w", err)
+	}
+	serverOpts = append(serverOpts, grpcapi.WithLimits(limits))
+	s.Debug, err = newDebugServer(s.Config.Debug)
+	if err != nil {
+		return err
+	}
+
 	// Создание gRPC сервера с интерцепторами и конфигурацией
-	s.GRPCServer = grpcapi.NewServer(noteHandler)
+	s.GRPCServer = grpcapi.NewServer(noteHandler, newTenantResolver(s.Config.Tenants), serverOpts...)
+	// Опечатка в имени сервиса не должна молча скрывать его из reflection
+	registered := s.GRPCServer.GetServiceInfo()
+	for _, name := range reflectionServices {
+		if _, ok := registered[name]; !ok {
+			return fmt.Errorf("invalid server.reflection_services: unknown service %q", name)
+		}
+	}
+
+	s.serveStatus()
 
 	return nil
 }
 
+// newServerTLS загружает настройки TLS gRPC сервера и подключения к нему Gateway
+// Без сертификата сервера возвращает nil: сервер и Gateway работают без TLS
+func newServerTLS(cfg config.ConfigServerTLS) (*tls.Config, *tls.Config, error) {
+	tlsCfg := tlsconfig.Config{
+		CertFile:       cfg.CertFile,
+		KeyFile:        cfg.KeyFile,
+		ClientCAFile:   cfg.ClientCAFile,
+		MinVersion:     cfg.MinVersion,
+		CAFile:         cfg.CAFile,
+		ClientCertFile: cfg.ClientCertFile,
+		ClientKeyFile:  cfg.ClientKeyFile,
+		ServerName:     cfg.ServerName,
+	}
+	if !tlsCfg.Enabled() {
+		log.Println("⚠️  gRPC server TLS is disabled, connections are plaintext")
+		return nil, nil, nil
+	}
+
+	serverTLS, err := tlsCfg.Server()
+	if err != nil {
+		return nil, nil, err
+	}
+	gatewayTLS, err := tlsCfg.Client()
+	if err != nil {
+		return nil, nil, err
+	}
+	log.Printf("Enabled gRPC server TLS (mTLS=%v, min version=%s)", cfg.ClientCAFile != "", cmp.Or(cfg.MinVersion, "1.2"))
+	return serverTLS, gatewayTLS, nil
+}
+
+// newReflectionServices возвращает сервисы gRPC reflection по конфигурации: nil - reflection выключен,
+// пустой список - доступны все сервисы. В окружении production reflection выключен всегда
+func newReflectionServices(cfg *config.ConfigServer) ([]string, error) {
+	environment := cmp.Or(cfg.Environment, config.EnvironmentDevelopment)
+	switch environment {
+	case config.EnvironmentDevelopment, config.EnvironmentStaging, config.EnvironmentProduction:
+	default:
+		return nil, fmt.Errorf("invalid server.environment %q (supported: %s, %s, %s)", environment,
+			config.EnvironmentDevelopment, config.EnvironmentStaging, config.EnvironmentProduction)
+	}
+	if !cfg.UseReflection {
+		return nil, nil
+	}
+	if environment == config.EnvironmentProduction {
+		log.Println("⚠️  gRPC reflection is disabled in production environment")
+		return nil, nil
+	}
+
+	services := []string{}
+	for _, name := range cfg.ReflectionServices {
+		if name = strings.TrimSpace(name); name != "" {
+			services = append(services, name)
+		}
+	}
+	return services, nil
+}
+
+// newServerLimits конвертирует ограничения gRPC сервера из конфигурации и проверяет их
+func newServerLimits(cfg config.ConfigServerLimits) (grpcapi.Limits, error) {
+	if cfg.MaxConcurrentStreams < 0 || int64(cfg.MaxConcurrentStreams) > math.MaxUint32 {
+		return grpcapi.Limits{}, fmt.Errorf("max_concurrent_streams must be between 0 and %d, got %d", uint32(math.MaxUint32), cfg.MaxConcurrentStreams)
+	}
+	limits := grpcapi.Limits{
+		MaxRecvMsgSize:       cfg.MaxRecvMsgSizeKB << 10,
+		MaxSendMsgSize:       cfg.MaxSendMsgSizeKB << 10,
+		MaxConcurrentStreams: uint32(cfg.MaxConcurrentStreams),
+		Keepalive: keepalive.ServerParameters{
+			Time:                  time.Duration(cfg.KeepaliveTimeSeconds) * time.Second,
+			Timeout:               time.Duration(cfg.KeepaliveTimeoutSeconds) * time.Second,
+			MaxConnectionIdle:     time.Duration(cfg.MaxConnectionIdleSeconds) * time.Second,
+			MaxConnectionAge:      time.Duration(cfg.MaxConnectionAgeSeconds) * time.Second,
+			MaxConnectionAgeGrace: time.Duration(cfg.MaxConnectionAgeGraceSeconds) * time.Second,
+		},
+		KeepaliveEnforcement: keepalive.EnforcementPolicy{
+			MinTime:             time.Duration(cfg.KeepaliveMinTimeSeconds) * time.Second,
+			PermitWithoutStream: cfg.KeepalivePermitWithoutStream,
+		},
+	}
+	if err := limits.Validate(); err != nil {
+		return grpcapi.Limits{}, err
+	}
+	log.Printf("gRPC server limits: max streams=%d, max recv=%dKB, max send=%dKB, keepalive=%v/%v, max connection age=%v",
+		cfg.MaxConcurrentStreams, cfg.MaxRecvMsgSizeKB, cfg.MaxSendMsgSizeKB, limits.Keepalive.Time, limits.Keepalive.Timeout, limits.Keepalive.MaxConnectionAge)
+	return limits, nil
+}
+
+// newTenantResolver создает резолвер настроек тенантов из секции tenants конфигурации
+// Без секции всем тенантам назначаются настройки без ограничений
+func newTenantResolver(cfg *config.ConfigTenants) *tenant.Resolver {
+	if cfg == nil {
+		return tenant.NewResolver(tenant.Settings{}, nil, 0)
+	}
+
+	overrides := make(tenant.StaticSource, len(cfg.Overrides))
+	for tenantID, override := range cfg.Overrides {
+		overrides[tenantID] = tenantOverride(override)
+	}
+	log.Printf("Initialized tenant settings (%d overrides)", len(overrides))
+
+	defaults := tenant.Settings{}.Apply(tenantOverride(cfg.Defaults))
+	return tenant.NewResolver(defaults, overrides, time.Duration(cfg.CacheTTLSeconds)*time.Second)
+}
+
+// tenantOverride конвертирует настройки тенанта из конфигурации
+func tenantOverride(cfg config.ConfigTenant) tenant.Override {
+	return tenant.Override{
+		RateLimitRPS:   cfg.RateLimitRPS,
+		RateLimitBurst: cfg.RateLimitBurst,
+		MaxNotes:       cfg.MaxNotes,
+		Features:       cfg.Features,
+
+		QuotaWarningPercents: cfg.QuotaWarningPercents,
+	}
+}
+
+// newAttachmentRepository создает хранилище вложений согласно конфигурации
+// Возвращает nil, если хранилище не настроено
+func newAttachmentRepository(cfg *config.ConfigAttachments, egressPolicy *egress.Policy) (repository.AttachmentRepository, error) {
+	if cfg == nil {
+		return nil, nil
+	}
+
+	switch cfg.Storage {
+	case "":
+		return nil, nil
+	case "filesystem":
+		return attachments.NewFilesystemRepository(cfg.Dir)
+	case "s3":
+		return attachments.NewS3Repository(attachments.S3Config{
+			Endpoint:     cfg.S3Endpoint,
+			Region:       cfg.S3Region,
+			Bucket:       cfg.S3Bucket,
+			Prefix:       cfg.S3Prefix,
+			AccessKey:    cfg.S3AccessKey,
+			SecretKey:    cfg.S3SecretKey,
+			UsePathStyle: cfg.S3UsePathStyle,
+			HTTPClient:   egressPolicy.HTTPClient(),
+		})
+	default:
+		return nil, fmt.Errorf("unknown attachments storage %q", cfg.Storage)
+	}
+}
+
+// newExportDestination создает хранилище выгрузок согласно конфигурации
+// Возвращает nil, если хранилище не настроено
+func newExportDestination(cfg *config.ConfigExports, egressPolicy *egress.Policy) (exports.Destination, error) {
+	if cfg == nil {
+		return nil, nil
+	}
+
+	switch cfg.Destination {
+	case "":
+		return nil, nil
+	case "filesystem":
+		return attachments.NewFilesystemDestination(cfg.Dir)
+	case "s3":
+		return attachments.NewS3Destination(attachments.S3Config{
+			Endpoint:     cfg.S3Endpoint,
+			Region:       cfg.S3Region,
+			Bucket:       cfg.S3Bucket,
+			Prefix:       cfg.S3Prefix,
+			AccessKey:    cfg.S3AccessKey,
+			SecretKey:    cfg.S3SecretKey,
+			UsePathStyle: cfg.S3UsePathStyle,
+			HTTPClient:   egressPolicy.HTTPClient(),
+		})
+	default:
+		return nil, fmt.Errorf("unknown exports destination %q", cfg.Destination)
+	}
+}
+
+// newMetricRepository создает хранилище значений метрик согласно конфигурации
+// Возвращает nil, если хранилище не настроено
+func (s *Server) newMetricRepository(cfg *config.ConfigMetrics) (repository.MetricRepository, error) {
+	if cfg == nil {
+		return nil, nil
+	}
+
+	switch cfg.Storage {
+	case "":
+		return nil, nil
+	case "memory":
+		return memory.NewMetricRepository(), nil
+	case "postgres":
+		if cfg.PostgresURL == "" {
+			return nil, errors.New("metrics.postgres_url is required for metrics storage postgres")
+		}
+		pool, err := postgres.NewPool(cfg.PostgresURL,
+			postgres.WithDialer(s.Egress.DialContext),
+			postgres.WithTLSConfig(s.Egress.TLSConfig),
+		)
+		if err != nil {
+			return nil, err
+		}
+		s.metricsDB = pool
+		return postgres.NewMetricRepository(pool), nil
+	default:
+		return nil, fmt.Errorf("unknown metrics storage %q", cfg.Storage)
+	}
+}
+
+// newBackupDestination создает хранилище резервных копий согласно конфигурации
+// Возвращает nil, если хранилище не настроено
+func newBackupDestination(cfg *config.ConfigBackups, egressPolicy *egress.Policy) (backups.Destination, error) {
+	if cfg == nil {
+		return nil, nil
+	}
+
+	switch cfg.Destination {
+	case "":
+		return nil, nil
+	case "filesystem":
+		return attachments.NewFilesystemDestination(cfg.Dir)
+	case "s3":
+		return attachments.NewS3Destination(attachments.S3Config{
+			Endpoint:     cfg.S3Endpoint,
+			Region:       cfg.S3Region,
+			Bucket:       cfg.S3Bucket,
+			Prefix:       cfg.S3Prefix,
+			AccessKey:    cfg.S3AccessKey,
+			SecretKey:    cfg.S3SecretKey,
+			UsePathStyle: cfg.S3UsePathStyle,
+			HTTPClient:   egressPolicy.HTTPClient(),
+		})
+	default:
+		return nil, fmt.Errorf("unknown backups destination %q", cfg.Destination)
+	}
+}
+
+// seedUsers добавляет в хранилище пользователей из секции auth конфигурации: учетные записи
+// входа по паролю и владельцев статических токенов. Без секции - владельцев auth.DemoTokens
+// Учетные записи добавляются первыми, чтобы владелец токена с тем же ID сохранил пароль
+func seedUsers(ctx context.Context, userSvc *users.Service, cfg *config.ConfigAuth) error {
+	tokens := auth.DemoTokens()
+	if cfg != nil {
+		for _, user := range cfg.Sessions.Users {
+			if user.Username == "" || user.Password == "" {
+				return errors.New("auth.sessions.users: user requires username and password")
+			}
+			input := users.CreateUserInput{ID: user.UserID, Username: user.Username, Roles: user.Roles}
+			if strings.HasPrefix(user.Password, "sha256:") {
+				input.PasswordHash = user.Password
+			} else {
+				input.Password = user.Password
+			}
+			if _, err := userSvc.Ensure(ctx, input); err != nil {
+				return fmt.Errorf("auth.sessions.users: %w", err)
+			}
+		}
+
+		tokens = make(auth.StaticTokens, len(cfg.StaticTokens))
+		for _, token := range cfg.StaticTokens {
+			tokens[token.Token] = auth.Principal{UserID: token.UserID, Roles: token.Roles}
+		}
+	}
+
+	for _, principal := range tokens {
+		if _, err := userSvc.Ensure(ctx, users.CreateUserInput{ID: principal.UserID, Roles: principal.Roles}); err != nil {
+			return fmt.Errorf("auth.static_tokens: %w", err)
+		}
+	}
+	return nil
+}
+
+// newStreamTickets создает билеты стримов по секции auth.stream_tickets
+// Без секции auth билеты подписываются случайным ключом со временем действия по умолчанию
+func newStreamTickets(cfg *config.ConfigAuth) (*auth.StreamTickets, error) {
+	var ticketsCfg auth.StreamTicketsConfig
+	if cfg != nil {
+		ticketsCfg.SigningKey = []byte(cfg.StreamTickets.SigningKey)
+		ticketsCfg.TTL = time.Duration(cfg.StreamTickets.TTLSeconds) * time.Second
+	}
+	return auth.NewStreamTickets(ticketsCfg)
+}
+
+// newAuthenticator создает проверку токенов по секции auth конфигурации
+// Без секции принимаются демонстрационные токены (auth.DemoTokens)
+// Сессии возвращаются отдельно для AuthService, если включен провайдер session, ключи API -
+// для AdminService, если включен провайдер apikey; пароли проверяются по хранилищу пользователей
+// userSvc, а новые пользователи токенов добавляются в него
+func newAuthenticator(cfg *config.ConfigAuth, userSvc *users.Service, egressPolicy *egress.Policy) (auth.Authenticator, *auth.Sessions, *apikeys.Service, error) {
+	if cfg == nil {
+		return userSvc.Provisioning(auth.DemoTokens()), nil, nil, nil
+	}
+
+	var (
+		chain    auth.Chain
+		sessions *auth.Sessions
+		apiKeys  *apikeys.Service
+	)
+	for _, provider := range cfg.Providers {
+		switch provider = strings.TrimSpace(provider); provider {
+		case "":
+			continue
+		case "static":
+			if len(cfg.StaticTokens) == 0 {
+				return nil, nil, nil, errors.New("auth provider static requires auth.static_tokens")
+			}
+			tokens := make(auth.StaticTokens, len(cfg.StaticTokens))
+			for _, token := range cfg.StaticTokens {
+				tokens[token.Token] = auth.Principal{UserID: token.UserID, Roles: token.Roles}
+			}
+			chain = append(chain, tokens)
+		case "session":
+			// Пользователей можно создать и через UserService, поэтому auth.sessions.users необязательна
+			var err error
+			sessions, err = auth.NewSessions(auth.SessionsConfig{
+				Store:           userSvc,
+				SigningKey:      []byte(cfg.Sessions.SigningKey),
+				AccessTokenTTL:  time.Duration(cfg.Sessions.AccessTokenTTLSeconds) * time.Second,
+				RefreshTokenTTL: time.Duration(cfg.Sessions.RefreshTokenTTLSeconds) * time.Second,
+			})
+			if err != nil {
+				return nil, nil, nil, err
+			}
+			chain = append(chain, sessions)
+		case "jwt":
+			jwtConfig := auth.JWTConfig{
+				HMACSecret: []byte(cfg.JWT.HMACSecret),
+				Issuer:     cfg.JWT.Issuer,
+				Audience:   cfg.JWT.Audience,
+				Leeway:     time.Duration(cfg.JWT.LeewaySeconds) * time.Second,
+				Claims:     auth.ClaimMapping{UserClaim: cfg.JWT.UserClaim, RolesClaim: cfg.JWT.RolesClaim},
+			}
+			if cfg.JWT.PublicKeyFile != "" {
+				key, err := os.ReadFile(cfg.JWT.PublicKeyFile)
+				if err != nil {
+					return nil, nil, nil, fmt.Errorf("failed to read jwt public key: %w", err)
+				}
+				jwtConfig.PublicKeyPEM = key
+			}
+			authenticator, err := auth.NewJWTAuthenticator(jwtConfig)
+			if err != nil {
+				return nil, nil, nil, err
+			}
+			chain = append(chain, authenticator)
+		case "oidc":
+			authenticator, err := auth.NewIntrospectionAuthenticator(auth.IntrospectionConfig{
+				URL:          cfg.OIDC.IntrospectionURL,
+				ClientID:     cfg.OIDC.ClientID,
+				ClientSecret: cfg.OIDC.ClientSecret,
+				Audience:     cfg.OIDC.Audience,
+				CacheTTL:     time.Duration(cfg.OIDC.CacheTTLSeconds) * time.Second,
+				CacheSize:    cfg.OIDC.CacheSize,
+				Claims:       auth.ClaimMapping{UserClaim: cfg.OIDC.UserClaim, RolesClaim: cfg.OIDC.RolesClaim},
+				HTTPClient:   egressPolicy.HTTPClient(egress.WithTimeout(auth.DefaultIntrospectionTimeout)),
+			})
+			if err != nil {
+				return nil, nil, nil, err
+			}
+			chain = append(chain, authenticator)
+		case "apikey":
+			// Ключи создает администратор через AdminService, в хранилище остаются только хэши секретов
+			apiKeys = apikeys.NewService(memory.NewAPIKeyRepository(),
+				apikeys.WithDefaultRateLimit(cfg.APIKeys.RateLimitRPS, cfg.APIKeys.RateLimitBurst))
+			chain = append(chain, apiKeys)
+		default:
+			return nil, nil, nil, fmt.Errorf("unknown auth provider %q", provider)
+		}
+		log.Printf("Enabled auth provider %s", provider)
+	}
+
+	if len(chain) == 0 {
+		return nil, nil, nil, errors.New("no auth providers configured in auth.providers")
+	}
+	return userSvc.Provisioning(chain), sessions, apiKeys, nil
+}
+
+// newWebhookService создает сервис вебхуков с хранилищем в памяти
+// Без секции webhooks используются значения по умолчанию, адреса внутренних сетей запрещены
+func newWebhookService(cfg *config.ConfigWebhooks, events notesService.EventBus, egressPolicy *egress.Policy, clock func() time.Time) *webhooks.Service {
+	if cfg == nil {
+		cfg = &config.ConfigWebhooks{}
+	}
+	timeout := webhooks.DefaultTimeout
+	if cfg.TimeoutSeconds > 0 {
+		timeout = time.Duration(cfg.TimeoutSeconds) * time.Second
+	}
+
+	return webhooks.NewService(memory.NewWebhookRepository(), events,
+		webhooks.WithHTTPClient(webhooks.NewHTTPClient(egressPolicy, timeout, cfg.AllowPrivateNetworks)),
+		webhooks.WithRetryPolicy(cfg.MaxAttempts, 0, 0),
+		webhooks.WithClock(clock),
+	)
+}
+
+// newEgressPolicy создает политику исходящих подключений из секции egress конфигурации
+// Без секции прокси берутся из переменных окружения HTTP_PROXY, HTTPS_PROXY и NO_PROXY
+func newEgressPolicy(cfg *config.ConfigEgress) (*egress.Policy, error) {
+	if cfg == nil {
+		return egress.New(egress.ConfigFromEnvironment())
+	}
+
+	policyConfig := egress.Config{
+		HTTPProxy:    cfg.HTTPProxy,
+		HTTPSProxy:   cfg.HTTPSProxy,
+		NoProxy:      cfg.NoProxy,
+		AllowedHosts: cfg.AllowedHosts,
+	}
+	for _, dest := range cfg.Destinations {
+		policyConfig.Destinations = append(policyConfig.Destinations, egress.Destination{
+			Host:    dest.Host,
+			Timeout: time.Duration(dest.TimeoutSeconds) * time.Second,
+			TLS: egress.TLSConfig{
+				Enabled:            dest.TLS.Enabled,
+				CAFile:             dest.TLS.CAFile,
+				CertFile:           dest.TLS.CertFile,
+				KeyFile:            dest.TLS.KeyFile,
+				ServerName:         dest.TLS.ServerName,
+				InsecureSkipVerify: dest.TLS.InsecureSkipVerify,
+				MinVersion:         dest.TLS.MinVersion,
+			},
+		})
+	}
+	policy, err := egress.New(policyConfig)
+	if err != nil {
+		return nil, err
+	}
+	if len(cfg.AllowedHosts) > 0 {
+		log.Printf("Outbound connections are restricted to: %s", strings.Join(cfg.AllowedHosts, ", "))
+	}
+	log.Printf("Initialized egress policy (proxy=%v, destinations=%d)", cfg.HTTPProxy != "" || cfg.HTTPSProxy != "", len(cfg.Destinations))
+	return policy, nil
+}
+
+// newDegradedMonitor создает монитор режима деградации хранилища заметок, nil - режим выключен
+func newDegradedMonitor(cfg *config.ConfigDegraded, noteRepo repository.NoteRepository, clock func() time.Time) *degraded.Monitor {
+	if cfg == nil || !cfg.Enabled {
+		return nil
+	}
+	return degraded.NewMonitor(noteRepo,
+		degraded.WithCheckInterval(time.Duration(cfg.CheckIntervalSeconds)*time.Second),
+		degraded.WithFailureThreshold(cfg.FailureThreshold),
+		degraded.WithSnapshotInterval(time.Duration(cfg.SnapshotIntervalSeconds)*time.Second),
+		degraded.WithClock(clock),
+	)
+}
+
+// newMirror создает дублирование запросов чтения во второе хранилище из секции mirror конфигурации
+// Второй сервис заметок использует общие с основным доступы и пользователей, но не пишет аудит:
+// запросы в нем повторяют уже выполненные и учтенные основным сервисом
+func (s *Server) newMirror(cfg *config.ConfigMirror, shareRepo repository.ShareRepository, dataKeys *encrypted.DataKeys,
+	accessPolicy grpcapi.AccessPolicy, clock func() time.Time) (*mirror.Mirror, error) {
+	if cfg == nil || !cfg.Enabled {
+		return nil, nil
+	}
+	mirrorRepo := s.options.mirrorRepository
+	if mirrorRepo == nil {
+		return nil, errors.New("mirror is enabled but no secondary note repository is provided (server.WithMirrorRepository)")
+	}
+	if dataKeys != nil {
+		mirrorRepo = encrypted.NewRepository(mirrorRepo, dataKeys)
+	}
+
+	var methods []string
+	for _, name := range cfg.Methods {
+		if name = strings.TrimSpace(name); name == "" {
+			continue
+		}
+		index := slices.IndexFunc(mirror.ReadMethods, func(method string) bool {
+			return strings.EqualFold(method[strings.LastIndex(method, "/")+1:], name)
+		})
+		if index < 0 {
+			return nil, fmt.Errorf("unknown read method %q in mirror.methods", name)
+		}
+		methods = append(methods, mirror.ReadMethods[index])
+	}
+
+	secondary := notesService.NewNoteService(mirrorRepo,
+		notesService.WithShareRepository(shareRepo),
+		notesService.WithUserDirectory(s.Users),
+		notesService.WithAuditLog(discardAuditLog{}),
+		notesService.WithClock(clock),
+	)
+	m, err := mirror.New(grpcapi.NewHandler(secondary, s.Ctx, grpcapi.WithAccessPolicy(accessPolicy)), methods, cfg.Percent,
+		mirror.WithTimeout(time.Duration(cfg.TimeoutSeconds)*time.Second),
+		mirror.WithMaxInFlight(cfg.MaxInFlight),
+		mirror.WithClock(clock),
+	)
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("⚠️  Mirroring %g%% of %s to the secondary note repository (%T)", cfg.Percent, strings.Join(m.Methods(), ", "), mirrorRepo)
+	return m, nil
+}
+
+// discardAuditLog не записывает аудит второго сервиса заметок при дублировании запросов
+type discardAuditLog struct{}
+
+func (discardAuditLog) Record(context.Context, model.AuditRecord) {}
+
+// newTracerProvider создает провайдер трасс по секции tracing конфигурации
+// Без секции или адреса коллектора возвращает nil: запросы не трассируются
+// К коллектору сервер подключается по политике исходящих подключений (прокси, TLS по адресу)
+func newTracerProvider(ctx context.Context, cfg *config.ConfigTracing, egressPolicy *egress.Policy) (*sdktrace.TracerProvider, error) {
+	if cfg == nil || cfg.OTLPEndpoint == "" {
+		return nil, nil
+	}
+	provider, err := telemetry.NewTracerProvider(ctx, telemetry.TracingConfig{
+		Endpoint:    cfg.OTLPEndpoint,
+		DialOptions: egressPolicy.GRPCDialOptions(cfg.OTLPEndpoint),
+		ServiceName: cfg.ServiceName,
+		SampleRatio: cfg.SampleRatio,
+	})
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("Enabled OpenTelemetry tracing (OTLP endpoint=%s, sample ratio=%g)", cfg.OTLPEndpoint, cfg.SampleRatio)
+	return provider, nil
+}
+
+// tracerProvider возвращает провайдер трасс как trace.TracerProvider (nil, если трассировка выключена)
+// Типизированный nil *sdktrace.TracerProvider не равен nil интерфейса и включил бы трассировку Gateway
+func (s *Server) tracerProvider() trace.TracerProvider {
+	if s.Tracing == nil {
+		return nil
+	}
+	return s.Tracing
+}
+
+// newAuditService создает журнал аудита из секции audit конфигурации
+// Возвращает nil, если журнал выключен
+func newAuditService(cfg *config.ConfigAudit, clock func() time.Time) *audit.Service {
+	if cfg == nil || !cfg.Enabled {
+		return nil
+	}
+	return audit.NewService(memory.NewAuditRepository(cfg.MaxRecords),
+		audit.WithRetention(time.Duration(cfg.RetentionDays)*24*time.Hour),
+		audit.WithClock(clock),
+	)
+}
+
+// newUsageCollector создает сборщик статистики использования из секции usage конфигурации
+// Возвращает nil без секции, при enabled: false и при заданной переменной окружения DO_NOT_TRACK
+func newUsageCollector(cfg *config.ConfigUsage, egressPolicy *egress.Policy, clock func() time.Time) *usage.Collector {
+	if cfg == nil || !cfg.Enabled || usage.OptedOut(os.Getenv("DO_NOT_TRACK")) {
+		return nil
+	}
+	return usage.NewCollector(
+		usage.WithEndpoint(cfg.Endpoint),
+		usage.WithReportInterval(time.Duration(cfg.ReportIntervalMinutes)*time.Minute),
+		usage.WithHTTPClient(egressPolicy.HTTPClient(egress.WithTimeout(usage.DefaultTimeout))),
+		usage.WithClock(clock),
+	)
+}
+
+// EventBroker шина событий, пересылающая события другим репликам сервера через внешний брокер
+type EventBroker interface {
+	notesService.EventBus
+
+	// Close отключается от брокера; события продолжают доставляться подписчикам этой реплики
+	Close() error
+}
+
+// newEventBroker подключает журнал событий local к брокеру из секции events конфигурации
+// Возвращает nil, если события доставляются в пределах процесса
+func newEventBroker(ctx context.Context, cfg *config.ConfigEvents, local *notesService.EventService, egressPolicy *egress.Policy) (EventBroker, error) {
+	if cfg == nil {
+		return nil, nil
+	}
+
+	switch cfg.Broker {
+	case "", "memory":
+		return nil, nil
+	case "nats":
+		bus, err := nats.NewBus(ctx, nats.Config{
+			URL:     cfg.NATSURL,
+			Subject: cfg.NATSSubject,
+			Name:    "notes-service",
+			Dial:    egressPolicy.DialContext,
+		}, local)
+		if err != nil {
+			return nil, err
+		}
+		log.Printf("Connected event bus to NATS (subject=%s)", cmp.Or(cfg.NATSSubject, nats.DefaultSubject))
+		return bus, nil
+	case "redis":
+		bus, err := redis.NewBus(ctx, redis.Config{
+			URL:           cfg.RedisURL,
+			ChannelPrefix: cfg.RedisChannelPrefix,
+			Name:          "notes-service",
+			Dial:          egressPolicy.DialContext,
+		}, local)
+		if err != nil {
+			return nil, err
+		}
+		log.Printf("Connected event bus to Redis (channels=%s.*)", cmp.Or(cfg.RedisChannelPrefix, redis.DefaultChannelPrefix))
+		return bus, nil
+	default:
+		return nil, fmt.Errorf("unknown events broker %q", cfg.Broker)
+	}
+}
+
+// newStreamRateLimits сопоставляет лимиты стримов из конфигурации полным именам методов NotesService
+// Ключи конфигурации сравниваются без учета регистра; лимиты перекрывают rate_limit из proto,
+// а лимит с нулевой скоростью снимает ограничение метода
+func newStreamRateLimits(cfg map[string]config.ConfigStreamRateLimit) (map[string]interceptors.StreamRateLimit, error) {
+	limits := make(map[string]interceptors.StreamRateLimit, len(cfg))
+	for name, limit := range cfg {
+		method, err := streamingMethod(name, "stream_rate_limits")
+		if err != nil {
+			return nil, err
+		}
+		limits[method] = interceptors.StreamRateLimit{MessagesPerSecond: max(limit.MessagesPerSecond, 0), Burst: limit.Burst}
+		if limit.MessagesPerSecond <= 0 {
+			log.Printf("Stream rate limit for %s is disabled", method)
+			continue
+		}
+		log.Printf("Stream rate limit for %s: %g messages per second (burst %d)", method, limit.MessagesPerSecond, limit.Burst)
+	}
+	return limits, nil
+}
+
+// newStreamLifetimes сопоставляет лимиты времени жизни стримов из конфигурации полным именам методов NotesService
+func newStreamLifetimes(cfg map[string]config.ConfigStreamLifetime) (map[string]interceptors.StreamLifetime, error) {
+	limits := make(map[string]interceptors.StreamLifetime, len(cfg))
+	for name, limit := range cfg {
+		method, err := streamingMethod(name, "stream_lifetimes")
+		if err != nil {
+			return nil, err
+		}
+		lifetime := interceptors.StreamLifetime{
+			MaxDuration: time.Duration(max(limit.MaxDurationSeconds, 0)) * time.Second,
+			IdleTimeout: time.Duration(max(limit.IdleTimeoutSeconds, 0)) * time.Second,
+		}
+		if lifetime.MaxDuration == 0 && lifetime.IdleTimeout == 0 {
+			continue
+		}
+		limits[method] = lifetime
+		log.Printf("Stream lifetime for %s: max duration %s, idle timeout %s", method, lifetime.MaxDuration, lifetime.IdleTimeout)
+	}
+	return limits, nil
+}
+
+// newMethodTimeouts сопоставляет дедлайны unary запросов из конфигурации полным именам методов NotesService
+func newMethodTimeouts(cfg map[string]int) (map[string]time.Duration, error) {
+	timeouts := make(map[string]time.Duration, len(cfg))
+	for name, seconds := range cfg {
+		index := slices.IndexFunc(notesv1.NotesService_ServiceDesc.Methods, func(method grpc.MethodDesc) bool {
+			return strings.EqualFold(method.MethodName, name)
+		})
+		if index < 0 {
+			return nil, fmt.Errorf("unknown unary method %q in method_timeouts_seconds", name)
+		}
+		if seconds <= 0 {
+			continue
+		}
+		method := "/" + notesv1.NotesService_ServiceDesc.ServiceName + "/" + notesv1.NotesService_ServiceDesc.Methods[index].MethodName
+		timeouts[method] = time.Duration(seconds) * time.Second
+		log.Printf("Default deadline for %s: %s", method, timeouts[method])
+	}
+	return timeouts, nil
+}
+
+// newRateLimiter создает лимит запросов клиентов gRPC сервера из server.rate_limit
+// Возвращает nil, если лимит не задан
+func newRateLimiter(cfg config.ConfigServerRateLimit) (*interceptors.RateLimiter, error) {
+	if cfg.RequestsPerSecond <= 0 {
+		log.Println("⚠️  gRPC rate limit is disabled, direct gRPC clients are not limited")
+		return nil, nil
+	}
+	switch cfg.Key {
+	case "", interceptors.RateLimitKeyUser, interceptors.RateLimitKeyPeer:
+	default:
+		return nil, fmt.Errorf("unknown server.rate_limit.key %q (expected user or peer)", cfg.Key)
+	}
+	limiter := interceptors.NewRateLimiter(interceptors.RateLimit{
+		RequestsPerSecond: cfg.RequestsPerSecond,
+		Burst:             cfg.Burst,
+		Key:               cfg.Key,
+	})
+	settings := limiter.Settings()
+	log.Printf("Enabled gRPC rate limit: %s requests per second, burst %s per %s", settings["rps"], settings["burst"], settings["key"])
+	return limiter, nil
+}
+
+// streamingMethod возвращает полное имя стримингового метода NotesService по имени из секции section
+// конфигурации (без учета регистра, например "chat")
+func streamingMethod(name, section string) (string, error) {
+	index := slices.IndexFunc(notesv1.NotesService_ServiceDesc.Streams, func(stream grpc.StreamDesc) bool {
+		return strings.EqualFold(stream.StreamName, name)
+	})
+	if index < 0 {
+		return "", fmt.Errorf("unknown streaming method %q in %s", name, section)
+	}
+	return "/" + notesv1.NotesService_ServiceDesc.ServiceName + "/" + notesv1.NotesService_ServiceDesc.Streams[index].StreamName, nil
+}
+
+// parsePublicMethods разбирает список методов без токена из server.public_methods
+// Методы не сверяются с зарегистрированными сервисами: в списке может быть сервис, добавленный позже
+func parsePublicMethods(values []string) ([]string, error) {
+	var methods []string
+	for _, method := range values {
+		if method = strings.TrimSpace(method); method == "" {
+			continue
+		}
+		service, name, ok := strings.Cut(strings.TrimPrefix(method, "/"), "/")
+		if !strings.HasPrefix(method, "/") || !ok || service == "" || name == "" || strings.Contains(name, "/") {
+			return nil, fmt.Errorf("invalid method %q in server.public_methods: expected /<service>/<method> or /<service>/*", method)
+		}
+		log.Printf("⚠️  Method %s is available without a token", method)
+		methods = append(methods, method)
+	}
+	return methods, nil
+}
+
+// newAuthz создает проверку ролей методов по секции authz конфигурации
+func newAuthz(cfg *config.ConfigAuthz) (*interceptors.AuthzInterceptor, error) {
+	desc := notesv1.NotesService_ServiceDesc
+	names := make([]string, 0, len(desc.Methods)+len(desc.Streams))
+	for _, method := range desc.Methods {
+		names = append(names, method.MethodName)
+	}
+	for _, stream := range desc.Streams {
+		names = append(names, stream.StreamName)
+	}
+
+	methods := make(map[string][]string, len(cfg.Methods))
+	for name, roles := range cfg.Methods {
+		index := slices.IndexFunc(names, func(method string) bool {
+			return strings.EqualFold(method, name)
+		})
+		if index < 0 {
+			return nil, fmt.Errorf("unknown method %q in authz.methods", name)
+		}
+		if len(roles) == 0 {
+			return nil, fmt.Errorf("authz.methods.%s requires at least one role", name)
+		}
+		fullMethod := "/" + desc.ServiceName + "/" + names[index]
+		methods[fullMethod] = roles
+		log.Printf("Authz: %s requires one of roles %v", fullMethod, roles)
+	}
+
+	var defaultRoles []string
+	for _, role := range cfg.DefaultRoles {
+		if role = strings.TrimSpace(role); role != "" {
+			defaultRoles = append(defaultRoles, role)
+		}
+	}
+	return interceptors.NewAuthzInterceptor(methods, defaultRoles...), nil
+}
+
+// newKeyring создает ключи шифрования содержимого заметок из конфигурации
+// Возвращает nil, если ключ не задан
+func newKeyring(cfg *config.ConfigEncryption) (*encrypted.Keyring, error) {
+	if cfg == nil || strings.TrimSpace(cfg.Key) == "" {
+		return nil, nil
+	}
+
+	primary, err := encrypted.ParseKey(cfg.Key)
+	if err != nil {
+		return nil, err
+	}
+
+	var previous [][]byte
+	for _, encoded := range cfg.PreviousKeys {
+		if strings.TrimSpace(encoded) == "" {
+			continue
+		}
+		key, err := encrypted.ParseKey(encoded)
+		if err != nil {
+			return nil, err
+		}
+		previous = append(previous, key)
+	}
+
+	return encrypted.NewKeyring(primary, previous...)
+}
+
+// newDebugServer создает отладочный порт по секции debug, nil - порт выключен
+func newDebugServer(cfg *config.ConfigDebug) (*debug.Server, error) {
+	if cfg == nil || !cfg.Enabled {
+		return nil, nil
+	}
+	addr := cfg.Addr
+	if addr == "" {
+		addr = "localhost:6060"
+	}
+	return debug.NewServer(addr)
+}
+
+// newPayloadLogger создает логирование тел запросов по секции logger, nil - тела не логируются
+func newPayloadLogger(cfg *config.ConfigLogger) (*interceptors.PayloadLogger, error) {
+	if cfg == nil || cfg.PayloadSampleRate == 0 {
+		return nil, nil
+	}
+	if cfg.PayloadSampleRate < 0 || cfg.PayloadSampleRate > 1 {
+		return nil, fmt.Errorf("logger.payload_sample_rate must be between 0 and 1, got %g", cfg.PayloadSampleRate)
+	}
+
+	return interceptors.NewPayloadLogger(interceptors.PayloadLogging{
+		SampleRate:   cfg.PayloadSampleRate,
+		RedactFields: cfg.PayloadRedactFields,
+	}), nil
+}
+
+// newRecorder создает запись запросов по конфигурации, nil - запись выключена
+func newRecorder(cfg *config.ConfigRecorder) (*recorder.Recorder, error) {
+	if cfg == nil || !cfg.Enabled {
+		return nil, nil
+	}
+
+	redactFields := recorder.DefaultRedactFields
+	if len(cfg.RedactFields) > 0 {
+		redactFields = cfg.RedactFields
+	}
+
+	return recorder.New(recorder.Options{
+		Dir:            cfg.Dir,
+		MaxSegments:    cfg.MaxSegments,
+		SegmentRecords: cfg.SegmentRecords,
+		RedactFields:   redactFields,
+	})
+}
+
 // ServeSwagger регистрирует маршруты Swagger UI на HTTP mux
 func (s *Server) ServeSwagger() {
 	if s.Config.Swagger == nil || !s.Config.Swagger.Enabled {
@@ -130,7 +1440,29 @@ func (s *Server) ServeSwagger() {
 	}
 
 	log.Printf("🔧 Initializing Swagger UI...")
-	swagger.ServeSwagger(s.Mux, s.SwaggerSpecs)
+	// Спецификации дополнительных сервисов, проксируемых через Gateway,
+	// объединяются со спецификацией NotesService в /swagger.json
+	upstreamSpecs := make(map[string]string)
+	if s.Config.Gateway != nil {
+		for _, upstream := range s.Config.Gateway.Upstreams {
+			if upstream.SwaggerSpec != "" {
+				upstreamSpecs[upstream.Name] = upstream.SwaggerSpec
+			}
+		}
+	}
+	// Примеры тел запросов строятся по proto и правилам валидации, чтобы "Try it out" начинался с корректных данных
+	examples, err := swagger.BuildExamples(notesv1.File_proto_notes_v1_notes_proto)
+	if err != nil {
+		log.Printf("⚠️  Failed to build swagger request examples: %v", err)
+	}
+	swagger.ServeSwagger(s.Mux, s.SwaggerSpecs, upstreamSpecs, examples)
+
+	// Метаданные спецификации и проверка, что она сгенерирована из текущих proto
+	protoHash, err := swagger.ProtoHash(notesv1.File_proto_notes_v1_notes_proto)
+	if err != nil {
+		log.Printf("⚠️  Failed to hash proto descriptors: %v", err)
+	}
+	swagger.ServeSpecMeta(s.Mux, s.SwaggerSpecs, protoHash, buildinfo.Version())
 
 	// Извлекаем порт из адреса для логирования
 	httpPort := strconv.Itoa(s.Config.Server.PortHTTP)
@@ -144,7 +1476,77 @@ func (s *Server) ServeSwagger() {
 // Start запускает gRPC и HTTP Gateway серверы в горутинах
 // Возвращает канал ошибок для отслеживания ошибок серверов
 func (s *Server) Start() <-chan error {
-	errChan := make(chan error, 2)
+	errChan := make(chan error, 10)
+
+	// Планировщик напоминаний, очистка блокировок, индекс подсказок, доставка вебхуков,
+	// резервное копирование, отправка статистики, очистка журнала аудита и проверка хранилища
+	// останавливаются вместе с контекстом сервера
+	go func() {
+		if err := s.Reminders.Run(s.Ctx); err != nil {
+			errChan <- fmt.Errorf("reminder scheduler error: %w", err)
+		}
+	}()
+	go func() {
+		if err := s.Locks.SweepLocks(s.Ctx); err != nil {
+			errChan <- fmt.Errorf("lock sweeper error: %w", err)
+		}
+	}()
+	go func() {
+		if err := s.Suggestions.Run(s.Ctx); err != nil {
+			errChan <- fmt.Errorf("suggestion index error: %w", err)
+		}
+	}()
+	go func() {
+		if err := s.Webhooks.Run(s.Ctx); err != nil {
+			errChan <- fmt.Errorf("webhook dispatcher error: %w", err)
+		}
+	}()
+	if s.Backups != nil {
+		go func() {
+			if err := s.Backups.Run(s.Ctx); err != nil {
+				errChan <- fmt.Errorf("backup scheduler error: %w", err)
+			}
+		}()
+	}
+	if s.Usage != nil {
+		go func() {
+			if err := s.Usage.Run(s.Ctx); err != nil {
+				errChan <- fmt.Errorf("usage reporter error: %w", err)
+			}
+		}()
+	}
+	if s.Audit != nil {
+		go func() {
+			if err := s.Audit.Run(s.Ctx); err != nil {
+				errChan <- fmt.Errorf("audit retention error: %w", err)
+			}
+		}()
+	}
+	if s.Degraded != nil {
+		go func() {
+			if err := s.Degraded.Run(s.Ctx); err != nil {
+				errChan <- fmt.Errorf("repository health checker error: %w", err)
+			}
+		}()
+	}
+
+	if s.Debug != nil {
+		go func() {
+			log.Printf("⚠️  Debug endpoints (pprof, goroutines, runtime metrics, channelz) listening on %s", s.Debug.Addr())
+			if err := s.Debug.ListenAndServe(); err != nil {
+				errChan <- fmt.Errorf("debug server error: %w", err)
+			}
+		}()
+	}
+
+	// Соединения общего порта распределяются между gRPC сервером и Gateway
+	if s.portMux != nil {
+		go func() {
+			if err := s.portMux.Serve(); err != nil {
+				errChan <- fmt.Errorf("port multiplexer error: %w", err)
+			}
+		}()
+	}
 
 	// Запуск gRPC сервера в горутине
 	go func() {
@@ -162,8 +1564,27 @@ func (s *Server) Start() <-chan error {
 
 	// Запускаем Gateway на том же mux
 	// Gateway доступен с префиксом /api/v1/ (пути из proto: /notes/v1/*)
+	gatewayOpts := grpcgateway.SetupOptions{
+		GRPCAddr:      grpcAddr,
+		HTTPAddr:      s.HTTPAddr,
+		HTTPListener:  s.httpListener,
+		GRPCTLS:       s.GatewayTLS,
+		GRPCServer:    s.GRPCServer,
+		Server:        s.Config.Server,
+		Gateway:       s.Config.Gateway,
+		Live:          s.gatewayLive,
+		Mux:           s.Mux,
+		Authenticator: s.Authenticator,
+		StreamTickets: s.StreamTickets,
+		Egress:        s.Egress,
+		Pipelines:     s.Pipelines,
+		Metrics:       s.Telemetry,
+		Tracing:       s.tracerProvider(),
+	}
+	s.gatewayDone = make(chan struct{})
 	go func() {
-		if err := grpcgateway.Setup(s.GatewayCtx, grpcAddr, s.HTTPAddr, s.Config.Gateway, s.Mux); err != nil {
+		defer close(s.gatewayDone)
+		if err := grpcgateway.Setup(s.GatewayCtx, gatewayOpts); err != nil {
 			errChan <- fmt.Errorf("HTTP Gateway error: %w", err)
 		}
 	}()
@@ -179,11 +1600,13 @@ func (s *Server) Shutdown() error {
 	// Это необходимо для корректного завершения стримов, которые слушают serverCtx
 	// В отличие от unary методов, где контекст автоматически отменяется при GracefulStop(),
 	// в стримах необходимо явно отменить serverCtx, чтобы они корректно завершились
+	// Gateway переводится в режим остановки первым, чтобы WebSocket стримы, завершенные
+	// отменой контекста сервера, закрывались кадром Close "server restarting"
+	s.GatewayCancel() // Отменяем контекст Gateway для остановки HTTP сервера
+
 	log.Println("Cancelling server context to signal streaming methods to stop...")
 	s.Cancel() // Отменяем контекст сервера для завершения стримов
 
-	s.GatewayCancel() // Отменяем контекст Gateway для остановки HTTP сервера
-
 	shutdownTimeout := time.Duration(s.Config.Server.GracefulShutdownTimeout) * time.Second
 	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
 	defer cancel()
@@ -198,11 +1621,65 @@ func (s *Server) Shutdown() error {
 	select {
 	case <-stopped:
 		log.Println("gRPC server stopped gracefully")
-		return nil
 	case <-ctx.Done():
 		log.Println("Graceful shutdown timeout, forcing stop...")
 		s.GRPCServer.Stop()
 		log.Println("gRPC server stopped forcefully")
-		return ctx.Err()
 	}
+
+	// Gateway отвечает 503 на новые запросы и закрывает WebSocket соединения, ожидаем его остановки
+	if s.gatewayDone != nil {
+		select {
+		case <-s.gatewayDone:
+		case <-ctx.Done():
+			log.Println("HTTP Gateway did not stop before shutdown timeout")
+		}
+	}
+
+	// Новых запросов нет, ожидаем сравнения уже дублированных
+	if s.Mirror != nil {
+		s.Mirror.Wait()
+	}
+
+	// Выгрузки и смена ключей прерываются отменой контекста сервера, ожидаем фиксации их результата
+	if s.Exports != nil {
+		s.Exports.Wait()
+	}
+	if s.KeyRotation != nil {
+		s.KeyRotation.Wait()
+	}
+
+	// Шина закрывается после выгрузок, чтобы события об их завершении дошли до других реплик
+	if s.EventBroker != nil {
+		if err := s.EventBroker.Close(); err != nil {
+			log.Printf("Failed to close event bus: %v", err)
+		}
+	}
+
+	if s.metricsDB != nil {
+		s.metricsDB.Close()
+	}
+
+	// Запись закрывается после остановки сервера, когда новых запросов уже нет
+	if s.Recorder != nil {
+		if err := s.Recorder.Close(); err != nil {
+			log.Printf("Failed to close request recorder: %v", err)
+		}
+	}
+
+	// Накопленные spans отправляются в коллектор последними, после завершения всех запросов
+	if s.Tracing != nil {
+		if err := s.Tracing.Shutdown(ctx); err != nil {
+			log.Printf("Failed to flush traces: %v", err)
+		}
+	}
+
+	// Отладочный порт закрывается в конце, чтобы через него можно было разобрать зависшую остановку
+	if s.Debug != nil {
+		if err := s.Debug.Close(); err != nil {
+			log.Printf("Failed to close debug server: %v", err)
+		}
+	}
+
+	return ctx.Err()
 }
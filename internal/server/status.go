@@ -0,0 +1,93 @@
+package server
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"time"
+
+	"notes-service/internal/telemetry"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// readiness ответ /readyz
+type readiness struct {
+	Status     string               `json:"status"` // ready или degraded
+	Repository *repositoryReadiness `json:"repository,omitempty"`
+}
+
+// repositoryReadiness состояние хранилища заметок в ответе /readyz
+type repositoryReadiness struct {
+	Degraded      bool       `json:"degraded"`
+	Since         *time.Time `json:"since,omitempty"`
+	LastCheckAt   *time.Time `json:"last_check_at,omitempty"`
+	LastError     string     `json:"last_error,omitempty"`
+	SnapshotAt    *time.Time `json:"snapshot_at,omitempty"`
+	SnapshotNotes int        `json:"snapshot_notes"`
+}
+
+// serveStatus регистрирует маршруты состояния сервера
+//
+// Создает следующие маршруты:
+// - GET /readyz - готовность сервера и состояние хранилища заметок
+// - GET /metrics - метрики запросов, резервного копирования, хранилища и дублирования запросов в текстовом формате Prometheus (promhttp)
+//
+// В режиме деградации /readyz отвечает 200 со статусом degraded: реплики используют общее
+// хранилище, и исключение их из балансировки лишило бы клиентов чтения из снимка
+func (s *Server) serveStatus() {
+	s.Mux.HandleFunc("GET /readyz", func(w http.ResponseWriter, r *http.Request) {
+		resp := readiness{Status: "ready"}
+		if s.Degraded != nil {
+			status := s.Degraded.Status()
+			if status.Degraded {
+				resp.Status = "degraded"
+			}
+			resp.Repository = &repositoryReadiness{
+				Degraded:      status.Degraded,
+				Since:         optionalTime(status.Since),
+				LastCheckAt:   optionalTime(status.LastCheckAt),
+				SnapshotAt:    optionalTime(status.SnapshotAt),
+				SnapshotNotes: status.SnapshotNotes,
+			}
+			if status.LastError != nil {
+				resp.Repository.LastError = status.LastError.Error()
+			}
+		}
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		if err := json.NewEncoder(w).Encode(resp); err != nil {
+			log.Printf("Failed to encode readiness: %v", err)
+		}
+	})
+
+	var collectors []prometheus.Collector
+	if s.Backups != nil {
+		collectors = append(collectors, s.Backups)
+	}
+	if s.Degraded != nil {
+		collectors = append(collectors, s.Degraded)
+	}
+	if s.Mirror != nil {
+		collectors = append(collectors, s.Mirror)
+	}
+	registry := s.Telemetry
+	if registry == nil {
+		if len(collectors) == 0 {
+			return
+		}
+		// Метрики запросов выключены (telemetry.enabled): /metrics отдает только состояние компонентов
+		registry = telemetry.NewRegistry()
+	}
+	if err := registry.Register(collectors...); err != nil {
+		log.Printf("Failed to register metrics: %v", err)
+	}
+	s.Mux.Handle("GET /metrics", registry.Handler())
+}
+
+// optionalTime возвращает nil для нулевого времени, чтобы оно не попадало в JSON
+func optionalTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
@@ -0,0 +1,266 @@
+// Package apikeys реализует ключи API: альтернативную схему аутентификации для сервисных клиентов
+// Ключ передается вместо токена (Authorization: Bearer или x-api-key) и ограничен собственным лимитом запросов
+package apikeys
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"notes-service/internal/auth"
+	"notes-service/internal/model"
+	"notes-service/internal/repository"
+	"notes-service/internal/repository/memory"
+
+	"golang.org/x/time/rate"
+)
+
+const (
+	// keyPrefix префикс ключей API: по нему ключ отличается от токенов остальных провайдеров
+	keyPrefix = "nsk_"
+
+	// keyIDSize и keySecretSize размеры открытой и секретной частей ключа в байтах
+	keyIDSize     = 8
+	keySecretSize = 32
+
+	// DefaultRateLimitRPS и DefaultRateLimitBurst лимит запросов ключа по умолчанию
+	DefaultRateLimitRPS   = 10
+	DefaultRateLimitBurst = 20
+)
+
+// knownRoles роли, которые можно назначить ключу
+var knownRoles = []string{auth.RoleUser, auth.RoleAdmin, auth.RoleReader, auth.RoleWriter}
+
+// CreateInput параметры создания ключа API
+type CreateInput struct {
+	Name           string        // Описание ключа
+	UserID         string        // Пользователь, от имени которого выполняются запросы
+	Roles          []string      // Роли запросов с ключом (auth.RoleUser добавляется всегда)
+	TTL            time.Duration // Время действия ключа (0 - бессрочный)
+	RateLimitRPS   float64       // Лимит запросов в секунду (0 - по умолчанию)
+	RateLimitBurst int           // Размер бюджета запросов (0 - по умолчанию)
+}
+
+// Service управляет ключами API и проверяет их (реализует auth.Authenticator)
+// Создавать, отзывать и просматривать ключи может только администратор
+type Service struct {
+	repository repository.APIKeyRepository
+	now        func() time.Time
+
+	defaultRPS   float64
+	defaultBurst int
+
+	mu       sync.Mutex
+	limiters map[string]*rate.Limiter // ID ключа -> лимит запросов
+}
+
+// Option настраивает сервис ключей API
+type Option func(*Service)
+
+// WithClock задает источник текущего времени (по умолчанию time.Now)
+func WithClock(now func() time.Time) Option {
+	return func(s *Service) {
+		s.now = now
+	}
+}
+
+// WithDefaultRateLimit задает лимит запросов ключей, созданных без собственного лимита
+// (значения не больше нуля оставляют лимит по умолчанию)
+func WithDefaultRateLimit(rps float64, burst int) Option {
+	return func(s *Service) {
+		if rps > 0 {
+			s.defaultRPS = rps
+		}
+		if burst > 0 {
+			s.defaultBurst = burst
+		}
+	}
+}
+
+var _ auth.Authenticator = (*Service)(nil)
+
+// NewService создает сервис ключей API поверх хранилища repository
+func NewService(repository repository.APIKeyRepository, opts ...Option) *Service {
+	s := &Service{
+		repository:   repository,
+		now:          time.Now,
+		defaultRPS:   DefaultRateLimitRPS,
+		defaultBurst: DefaultRateLimitBurst,
+		limiters:     make(map[string]*rate.Limiter),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Create создает ключ (только для администратора) и возвращает его вместе с секретом
+// Секрет возвращается только здесь: в хранилище сохраняется его хэш
+func (s *Service) Create(ctx context.Context, input CreateInput) (model.APIKey, string, error) {
+	principal, ok := auth.FromContext(ctx)
+	if !ok || !principal.HasRole(auth.RoleAdmin) {
+		return model.APIKey{}, "", auth.ErrPermissionDenied
+	}
+
+	key := model.APIKey{
+		Name:           strings.TrimSpace(input.Name),
+		UserID:         strings.TrimSpace(input.UserID),
+		CreatedBy:      principal.UserID,
+		CreatedAt:      s.now(),
+		RateLimitRPS:   input.RateLimitRPS,
+		RateLimitBurst: input.RateLimitBurst,
+	}
+	if key.UserID == "" {
+		return model.APIKey{}, "", errors.New("api key user id cannot be empty")
+	}
+	if input.TTL < 0 || input.RateLimitRPS < 0 || input.RateLimitBurst < 0 {
+		return model.APIKey{}, "", errors.New("api key ttl and rate limit cannot be negative")
+	}
+	if input.TTL > 0 {
+		key.ExpiresAt = key.CreatedAt.Add(input.TTL)
+	}
+	roles, err := normalizeRoles(input.Roles)
+	if err != nil {
+		return model.APIKey{}, "", err
+	}
+	key.Roles = roles
+
+	id := make([]byte, keyIDSize)
+	secret := make([]byte, keySecretSize)
+	if _, err := rand.Read(id); err != nil {
+		return model.APIKey{}, "", err
+	}
+	if _, err := rand.Read(secret); err != nil {
+		return model.APIKey{}, "", err
+	}
+	key.ID = hex.EncodeToString(id)
+	encodedSecret := base64.RawURLEncoding.EncodeToString(secret)
+	hash := sha256.Sum256([]byte(encodedSecret))
+	key.SecretHash = hash[:]
+
+	if err := s.repository.Create(ctx, key); err != nil {
+		return model.APIKey{}, "", err
+	}
+	log.Printf("API key %s for user %s created by %s", key.ID, key.UserID, principal.UserID)
+	return key, keyPrefix + key.ID + "_" + encodedSecret, nil
+}
+
+// Revoke отзывает ключ (только для администратора): запросы с ним больше не принимаются
+func (s *Service) Revoke(ctx context.Context, id string) (model.APIKey, error) {
+	principal, ok := auth.FromContext(ctx)
+	if !ok || !principal.HasRole(auth.RoleAdmin) {
+		return model.APIKey{}, auth.ErrPermissionDenied
+	}
+
+	key, err := s.repository.Revoke(ctx, id, s.now())
+	if err != nil {
+		return model.APIKey{}, err
+	}
+
+	s.mu.Lock()
+	delete(s.limiters, id)
+	s.mu.Unlock()
+
+	log.Printf("API key %s revoked by %s", id, principal.UserID)
+	return key, nil
+}
+
+// List возвращает все ключи, включая отозванные (только для администратора)
+func (s *Service) List(ctx context.Context) ([]model.APIKey, error) {
+	principal, ok := auth.FromContext(ctx)
+	if !ok || !principal.HasRole(auth.RoleAdmin) {
+		return nil, auth.ErrPermissionDenied
+	}
+
+	return s.repository.List(ctx)
+}
+
+// Authenticate проверяет ключ API и возвращает пользователя, от имени которого он выдан
+// Секрет сравнивается за постоянное время, в том числе для несуществующего ключа
+func (s *Service) Authenticate(ctx context.Context, token string) (auth.Principal, error) {
+	rest, ok := strings.CutPrefix(token, keyPrefix)
+	if !ok {
+		return auth.Principal{}, auth.ErrInvalidToken
+	}
+	id, secret, ok := strings.Cut(rest, "_")
+	if !ok {
+		return auth.Principal{}, auth.ErrInvalidToken
+	}
+
+	key, err := s.repository.GetByID(ctx, id)
+	if err != nil && !errors.Is(err, memory.ErrAPIKeyNotFound) {
+		return auth.Principal{}, fmt.Errorf("failed to get api key: %w", err)
+	}
+	expected := key.SecretHash
+	if len(expected) != sha256.Size {
+		expected = make([]byte, sha256.Size)
+	}
+	hash := sha256.Sum256([]byte(secret))
+	if subtle.ConstantTimeCompare(hash[:], expected) != 1 || err != nil || !key.Active(s.now()) {
+		return auth.Principal{}, auth.ErrInvalidToken
+	}
+
+	return auth.Principal{UserID: key.UserID, Roles: key.Roles, APIKeyID: key.ID}, nil
+}
+
+// Allow расходует запрос из бюджета ключа id и сообщает, укладывается ли запрос в лимит ключа
+// Лимит ключа создается при первом запросе по настройкам ключа из хранилища
+func (s *Service) Allow(ctx context.Context, id string) bool {
+	s.mu.Lock()
+	limiter, ok := s.limiters[id]
+	s.mu.Unlock()
+	if !ok {
+		key, err := s.repository.GetByID(ctx, id)
+		if err != nil {
+			log.Printf("Failed to get api key %s for rate limit: %v", id, err)
+			return true
+		}
+		limiter = s.newLimiter(key)
+
+		s.mu.Lock()
+		if existing, ok := s.limiters[id]; ok {
+			limiter = existing
+		} else {
+			s.limiters[id] = limiter
+		}
+		s.mu.Unlock()
+	}
+	return limiter.Allow()
+}
+
+// newLimiter создает лимит запросов ключа
+func (s *Service) newLimiter(key model.APIKey) *rate.Limiter {
+	rps, burst := key.RateLimitRPS, key.RateLimitBurst
+	if rps <= 0 {
+		rps = s.defaultRPS
+	}
+	if burst <= 0 {
+		burst = s.defaultBurst
+	}
+	return rate.NewLimiter(rate.Limit(rps), burst)
+}
+
+// normalizeRoles проверяет роли, добавляет auth.RoleUser и удаляет повторы
+func normalizeRoles(roles []string) ([]string, error) {
+	result := []string{auth.RoleUser}
+	for _, role := range roles {
+		role = strings.TrimSpace(role)
+		if !slices.Contains(knownRoles, role) {
+			return nil, fmt.Errorf("invalid role %q (expected %s)", role, strings.Join(knownRoles, " or "))
+		}
+		if !slices.Contains(result, role) {
+			result = append(result, role)
+		}
+	}
+	return result, nil
+}
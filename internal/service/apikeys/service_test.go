@@ -0,0 +1,97 @@
+package apikeys
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"notes-service/internal/auth"
+	"notes-service/internal/repository/memory"
+)
+
+func TestService_CreateAuthenticateRevoke(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	service := NewService(memory.NewAPIKeyRepository(), WithClock(func() time.Time { return now }))
+	admin := auth.NewContext(context.Background(), auth.Principal{UserID: "admin", Roles: []string{auth.RoleUser, auth.RoleAdmin}})
+	alice := auth.NewContext(context.Background(), auth.Principal{UserID: "alice", Roles: []string{auth.RoleUser}})
+
+	// Создавать ключи может только администратор
+	if _, _, err := service.Create(alice, CreateInput{UserID: "alice"}); !errors.Is(err, auth.ErrPermissionDenied) {
+		t.Errorf("Expected ErrPermissionDenied for non-admin, got %v", err)
+	}
+
+	key, secret, err := service.Create(admin, CreateInput{Name: "ci", UserID: "svc-ci", TTL: time.Hour})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if !strings.HasPrefix(secret, keyPrefix+key.ID+"_") || strings.Contains(string(key.SecretHash), secret) {
+		t.Errorf("Unexpected key %q for %+v", secret, key)
+	}
+
+	principal, err := service.Authenticate(context.Background(), secret)
+	if err != nil {
+		t.Fatalf("Expected key to be accepted, got: %v", err)
+	}
+	if principal.UserID != "svc-ci" || principal.APIKeyID != key.ID || !slices.Equal(principal.Roles, []string{auth.RoleUser}) {
+		t.Errorf("Unexpected principal: %+v", principal)
+	}
+
+	for _, token := range []string{"my-secret-token", keyPrefix + key.ID + "_wrong", keyPrefix + "unknown_" + strings.Repeat("a", 43)} {
+		if _, err := service.Authenticate(context.Background(), token); !errors.Is(err, auth.ErrInvalidToken) {
+			t.Errorf("Authenticate(%q) = %v, want ErrInvalidToken", token, err)
+		}
+	}
+
+	// Истекший ключ не принимается
+	now = now.Add(2 * time.Hour)
+	if _, err := service.Authenticate(context.Background(), secret); !errors.Is(err, auth.ErrInvalidToken) {
+		t.Errorf("Expected expired key to be rejected, got %v", err)
+	}
+	now = now.Add(-2 * time.Hour)
+
+	revoked, err := service.Revoke(admin, key.ID)
+	if err != nil || revoked.RevokedAt.IsZero() {
+		t.Fatalf("Expected key to be revoked, got %+v, %v", revoked, err)
+	}
+	if _, err := service.Authenticate(context.Background(), secret); !errors.Is(err, auth.ErrInvalidToken) {
+		t.Errorf("Expected revoked key to be rejected, got %v", err)
+	}
+	if _, err := service.Revoke(admin, "missing"); !errors.Is(err, memory.ErrAPIKeyNotFound) {
+		t.Errorf("Expected ErrAPIKeyNotFound, got %v", err)
+	}
+
+	keys, err := service.List(admin)
+	if err != nil || len(keys) != 1 || keys[0].ID != key.ID {
+		t.Errorf("Expected revoked key in list, got %+v, %v", keys, err)
+	}
+}
+
+func TestService_Allow(t *testing.T) {
+	service := NewService(memory.NewAPIKeyRepository(), WithDefaultRateLimit(0.001, 2))
+	admin := auth.NewContext(context.Background(), auth.Principal{UserID: "admin", Roles: []string{auth.RoleAdmin}})
+
+	limited, _, err := service.Create(admin, CreateInput{UserID: "svc-a"})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	custom, _, err := service.Create(admin, CreateInput{UserID: "svc-b", RateLimitRPS: 0.001, RateLimitBurst: 3})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	// Бюджет у каждого ключа свой: лимит по умолчанию у первого, собственный у второго
+	ctx := context.Background()
+	for i, want := range []bool{true, true, false} {
+		if got := service.Allow(ctx, limited.ID); got != want {
+			t.Errorf("Allow(default key) #%d = %v, want %v", i, got, want)
+		}
+	}
+	for i, want := range []bool{true, true, true, false} {
+		if got := service.Allow(ctx, custom.ID); got != want {
+			t.Errorf("Allow(custom key) #%d = %v, want %v", i, got, want)
+		}
+	}
+}
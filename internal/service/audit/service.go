@@ -0,0 +1,133 @@
+// Package audit ведет журнал аудита: кто, когда и с каким результатом изменял заметки и доступ к ним
+// (записи добавляет интерцептор Audit) и обращался к заметкам, защищенным парольной фразой.
+// Записи старше срока хранения удаляются в фоне; просматривать журнал может только администратор
+package audit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"notes-service/internal/auth"
+	"notes-service/internal/model"
+	"notes-service/internal/repository"
+)
+
+const (
+	// DefaultListLimit и MaxListLimit количество записей в ответе List по умолчанию и максимальное
+	DefaultListLimit = 100
+	MaxListLimit     = 1000
+
+	// maxSweepInterval максимальный интервал удаления устаревших записей
+	maxSweepInterval = time.Hour
+)
+
+// Service записывает и выдает записи журнала аудита (реализует notes.AuditLog)
+type Service struct {
+	repository repository.AuditRepository
+	now        func() time.Time
+	retention  time.Duration
+}
+
+// Option настраивает журнал аудита
+type Option func(*Service)
+
+// WithClock задает источник текущего времени (по умолчанию time.Now)
+func WithClock(now func() time.Time) Option {
+	return func(s *Service) {
+		s.now = now
+	}
+}
+
+// WithRetention задает срок хранения записей (по умолчанию записи не удаляются по времени)
+func WithRetention(retention time.Duration) Option {
+	return func(s *Service) {
+		s.retention = retention
+	}
+}
+
+// NewService создает журнал аудита поверх хранилища repository
+func NewService(repository repository.AuditRepository, opts ...Option) *Service {
+	s := &Service{
+		repository: repository,
+		now:        time.Now,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Record сохраняет запись; время и пользователь из контекста заполняются, если не заданы
+// Ошибка хранилища не прерывает операцию, ради которой делается запись, поэтому только логируется
+func (s *Service) Record(ctx context.Context, record model.AuditRecord) {
+	if record.Time.IsZero() {
+		record.Time = s.now()
+	}
+	if record.UserID == "" {
+		if principal, ok := auth.FromContext(ctx); ok {
+			record.UserID = principal.UserID
+		}
+	}
+	if _, err := s.repository.Append(ctx, record); err != nil {
+		log.Printf("Failed to record audit event: action=%s note=%s user=%s outcome=%s: %v",
+			record.Action, record.NoteID, record.UserID, record.Outcome, err)
+	}
+}
+
+// List возвращает записи, подходящие под filter, начиная с последней (только для администратора)
+// Limit 0 заменяется на DefaultListLimit
+func (s *Service) List(ctx context.Context, filter repository.AuditFilter) ([]model.AuditRecord, error) {
+	principal, ok := auth.FromContext(ctx)
+	if !ok || !principal.HasRole(auth.RoleAdmin) {
+		return nil, auth.ErrPermissionDenied
+	}
+
+	if filter.Limit < 0 || filter.Limit > MaxListLimit {
+		return nil, fmt.Errorf("invalid audit limit %d: must be between 0 and %d", filter.Limit, MaxListLimit)
+	}
+	if filter.BeforeID < 0 {
+		return nil, errors.New("invalid audit before id: cannot be negative")
+	}
+	if !filter.From.IsZero() && !filter.To.IsZero() && !filter.From.Before(filter.To) {
+		return nil, errors.New("invalid audit period: from must be before to")
+	}
+	if filter.Limit == 0 {
+		filter.Limit = DefaultListLimit
+	}
+	return s.repository.List(ctx, filter)
+}
+
+// Run удаляет записи старше срока хранения при запуске и затем периодически, пока ctx не отменен
+// Без срока хранения сразу возвращает nil
+func (s *Service) Run(ctx context.Context) error {
+	if s.retention <= 0 {
+		return nil
+	}
+
+	ticker := time.NewTicker(min(s.retention, maxSweepInterval))
+	defer ticker.Stop()
+
+	for {
+		s.sweep(ctx)
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// sweep удаляет записи старше срока хранения
+func (s *Service) sweep(ctx context.Context) {
+	deleted, err := s.repository.DeleteBefore(ctx, s.now().Add(-s.retention))
+	if err != nil {
+		log.Printf("Failed to delete expired audit events: %v", err)
+		return
+	}
+	if deleted > 0 {
+		log.Printf("Deleted %d audit events older than %s", deleted, s.retention)
+	}
+}
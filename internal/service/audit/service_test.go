@@ -0,0 +1,86 @@
+package audit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"notes-service/internal/auth"
+	"notes-service/internal/model"
+	"notes-service/internal/repository"
+	"notes-service/internal/repository/memory"
+)
+
+func TestService_RecordList(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	service := NewService(memory.NewAuditRepository(0), WithClock(func() time.Time { return now }))
+	admin := auth.NewContext(context.Background(), auth.Principal{UserID: "admin", Roles: []string{auth.RoleUser, auth.RoleAdmin}})
+	alice := auth.NewContext(context.Background(), auth.Principal{UserID: "alice", Roles: []string{auth.RoleUser}})
+
+	for i, action := range []string{model.AuditActionCreate, model.AuditActionUpdate, model.AuditActionDelete} {
+		now = now.Add(time.Minute)
+		service.Record(alice, model.AuditRecord{Action: action, NoteID: "note-1", Outcome: "OK"})
+		if i == 0 {
+			service.Record(admin, model.AuditRecord{Action: model.AuditActionCreate, NoteID: "note-2", Outcome: "OK"})
+		}
+	}
+
+	// Просматривать журнал может только администратор
+	if _, err := service.List(alice, repository.AuditFilter{}); !errors.Is(err, auth.ErrPermissionDenied) {
+		t.Errorf("Expected ErrPermissionDenied for non-admin, got %v", err)
+	}
+
+	records, err := service.List(admin, repository.AuditFilter{UserID: "alice", Limit: 2})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(records) != 2 || records[0].Action != model.AuditActionDelete || records[1].Action != model.AuditActionUpdate {
+		t.Fatalf("Expected alice's last two records newest first, got %+v", records)
+	}
+	if records[0].UserID != "alice" || !records[0].Time.Equal(now) {
+		t.Errorf("Expected user and time from context and clock, got %+v", records[0])
+	}
+
+	// Следующая страница продолжается после последней записи предыдущей
+	records, err = service.List(admin, repository.AuditFilter{UserID: "alice", BeforeID: records[1].ID})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(records) != 1 || records[0].Action != model.AuditActionCreate || records[0].NoteID != "note-1" {
+		t.Errorf("Expected alice's first record, got %+v", records)
+	}
+
+	records, err = service.List(admin, repository.AuditFilter{Action: model.AuditActionCreate})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(records) != 2 || records[0].NoteID != "note-2" {
+		t.Errorf("Expected both create records, got %+v", records)
+	}
+
+	if _, err := service.List(admin, repository.AuditFilter{Limit: MaxListLimit + 1}); err == nil {
+		t.Error("Expected error for limit above MaxListLimit")
+	}
+}
+
+func TestService_Retention(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	repo := memory.NewAuditRepository(0)
+	service := NewService(repo, WithRetention(24*time.Hour), WithClock(func() time.Time { return now }))
+
+	service.Record(context.Background(), model.AuditRecord{Action: model.AuditActionCreate, NoteID: "old"})
+	now = now.Add(23 * time.Hour)
+	service.Record(context.Background(), model.AuditRecord{Action: model.AuditActionCreate, NoteID: "new"})
+	now = now.Add(2 * time.Hour)
+
+	service.sweep(context.Background())
+
+	records, err := repo.List(context.Background(), repository.AuditFilter{})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(records) != 1 || records[0].NoteID != "new" {
+		t.Errorf("Expected only the record within retention, got %+v", records)
+	}
+}
@@ -0,0 +1,233 @@
+package backups
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"notes-service/internal/model"
+)
+
+// Раскладка ZIP архива резервной копии
+const (
+	manifestEntry  = "manifest.json"
+	notesEntry     = "notes.jsonl"
+	revisionsEntry = "revisions.jsonl"
+	sharesEntry    = "shares.jsonl"
+
+	// formatVersion версия формата архива; архивы другой версии не восстанавливаются
+	formatVersion = 1
+
+	// maxRecordSize максимальный размер одной записи JSON Lines в архиве
+	maxRecordSize = 8 << 20
+)
+
+// manifest описание содержимого архива
+type manifest struct {
+	FormatVersion int       `json:"format_version"`
+	CreatedAt     time.Time `json:"created_at"`
+	Notes         int64     `json:"notes"`
+	Revisions     int64     `json:"revisions"`
+	Shares        int64     `json:"shares"`
+}
+
+// noteRecord заметка в том виде, в котором она хранится в репозитории
+// Содержимое, зашифрованное в хранилище (key_id), остается зашифрованным и в копии
+type noteRecord struct {
+	ID               string    `json:"id"`
+	Title            string    `json:"title"`
+	Content          string    `json:"content,omitempty"`
+	CreatedAt        time.Time `json:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at"`
+	Version          int64     `json:"version"`
+	Tags             []string  `json:"tags,omitempty"`
+	OwnerID          string    `json:"owner_id"`
+	Pinned           bool      `json:"pinned,omitempty"`
+	Archived         bool      `json:"archived,omitempty"`
+	RemindAt         time.Time `json:"remind_at,omitzero"`
+	KeyID            string    `json:"key_id,omitempty"`
+	IsE2E            bool      `json:"is_e2e,omitempty"`
+	E2EScheme        string    `json:"e2e_scheme,omitempty"`
+	ContentEncrypted []byte    `json:"content_encrypted,omitempty"`
+	WordCount        int       `json:"word_count,omitempty"`
+}
+
+func newNoteRecord(note model.Note) noteRecord {
+	return noteRecord{
+		ID:               note.ID,
+		Title:            note.Title,
+		Content:          note.Content,
+		CreatedAt:        note.CreatedAt,
+		UpdatedAt:        note.UpdatedAt,
+		Version:          note.Version,
+		Tags:             note.Tags,
+		OwnerID:          note.OwnerID,
+		Pinned:           note.Pinned,
+		Archived:         note.Archived,
+		RemindAt:         note.RemindAt,
+		KeyID:            note.KeyID,
+		IsE2E:            note.IsE2E,
+		E2EScheme:        note.E2EScheme,
+		ContentEncrypted: note.ContentEncrypted,
+		WordCount:        note.WordCount,
+	}
+}
+
+func (r noteRecord) model() model.Note {
+	note := model.Note{
+		ID:               r.ID,
+		Title:            r.Title,
+		Content:          r.Content,
+		CreatedAt:        r.CreatedAt,
+		UpdatedAt:        r.UpdatedAt,
+		Version:          r.Version,
+		Tags:             r.Tags,
+		OwnerID:          r.OwnerID,
+		Pinned:           r.Pinned,
+		Archived:         r.Archived,
+		RemindAt:         r.RemindAt,
+		KeyID:            r.KeyID,
+		IsE2E:            r.IsE2E,
+		E2EScheme:        r.E2EScheme,
+		ContentEncrypted: r.ContentEncrypted,
+		WordCount:        r.WordCount,
+		ReadingTime:      model.ReadingTime(r.WordCount),
+	}
+	// Копии, сделанные до появления word_count, не содержат статистику:
+	// она пересчитывается, если содержание в копии не зашифровано
+	if r.WordCount == 0 && r.KeyID == "" {
+		note.UpdateTextStats()
+	}
+	return note
+}
+
+// revisionRecord ревизия заметки
+type revisionRecord struct {
+	NoteID           string    `json:"note_id"`
+	Revision         int64     `json:"revision"`
+	Title            string    `json:"title"`
+	Content          string    `json:"content,omitempty"`
+	CreatedAt        time.Time `json:"created_at"`
+	ContentEncrypted []byte    `json:"content_encrypted,omitempty"`
+}
+
+func newRevisionRecord(revision model.NoteRevision) revisionRecord {
+	return revisionRecord{
+		NoteID:           revision.NoteID,
+		Revision:         revision.Revision,
+		Title:            revision.Title,
+		Content:          revision.Content,
+		CreatedAt:        revision.CreatedAt,
+		ContentEncrypted: revision.ContentEncrypted,
+	}
+}
+
+func (r revisionRecord) model() model.NoteRevision {
+	return model.NoteRevision{
+		NoteID:           r.NoteID,
+		Revision:         r.Revision,
+		Title:            r.Title,
+		Content:          r.Content,
+		CreatedAt:        r.CreatedAt,
+		ContentEncrypted: r.ContentEncrypted,
+	}
+}
+
+// shareRecord доступ пользователя к заметке
+type shareRecord struct {
+	NoteID     string    `json:"note_id"`
+	OwnerID    string    `json:"owner_id"`
+	UserID     string    `json:"user_id"`
+	Permission int       `json:"permission"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+func newShareRecord(share model.Share) shareRecord {
+	return shareRecord{
+		NoteID:     share.NoteID,
+		OwnerID:    share.OwnerID,
+		UserID:     share.UserID,
+		Permission: int(share.Permission),
+		CreatedAt:  share.CreatedAt,
+	}
+}
+
+func (r shareRecord) model() model.Share {
+	return model.Share{
+		NoteID:     r.NoteID,
+		OwnerID:    r.OwnerID,
+		UserID:     r.UserID,
+		Permission: model.SharePermission(r.Permission),
+		CreatedAt:  r.CreatedAt,
+	}
+}
+
+// archiveReader читает записи из открытого ZIP архива резервной копии
+type archiveReader struct {
+	zip      *zip.Reader
+	manifest manifest
+}
+
+// openArchive проверяет манифест архива
+func openArchive(r io.ReaderAt, size int64) (*archiveReader, error) {
+	archive, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidBackup, err)
+	}
+
+	a := &archiveReader{zip: archive}
+	err = a.each(manifestEntry, func(data []byte) error {
+		return json.Unmarshal(data, &a.manifest)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if a.manifest.FormatVersion != formatVersion {
+		return nil, fmt.Errorf("%w: unsupported format version %d", ErrInvalidBackup, a.manifest.FormatVersion)
+	}
+	return a, nil
+}
+
+// each вызывает fn для каждой строки файла name внутри архива
+func (a *archiveReader) each(name string, fn func(data []byte) error) error {
+	file, err := a.zip.Open(name)
+	if err != nil {
+		return fmt.Errorf("%w: %s: %v", ErrInvalidBackup, name, err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxRecordSize)
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		if err := fn(scanner.Bytes()); err != nil {
+			var syntaxErr *json.SyntaxError
+			var typeErr *json.UnmarshalTypeError
+			if errors.As(err, &syntaxErr) || errors.As(err, &typeErr) {
+				return fmt.Errorf("%w: %s: %v", ErrInvalidBackup, name, err)
+			}
+			return err
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("%w: %s: %v", ErrInvalidBackup, name, err)
+	}
+	return nil
+}
+
+// eachRecord декодирует каждую строку файла name в T и вызывает fn
+func eachRecord[T any](a *archiveReader, name string, fn func(T) error) error {
+	return a.each(name, func(data []byte) error {
+		var record T
+		if err := json.Unmarshal(data, &record); err != nil {
+			return err
+		}
+		return fn(record)
+	})
+}
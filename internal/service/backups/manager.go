@@ -0,0 +1,635 @@
+// Package backups создает резервные копии хранилища (заметки, ревизии и доступы) по расписанию
+// и восстанавливает хранилище из них (RestoreBackup)
+package backups
+
+import (
+	"archive/zip"
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"notes-service/internal/auth"
+	"notes-service/internal/model"
+	"notes-service/internal/repository"
+	"notes-service/internal/repository/memory"
+	"notes-service/internal/service/notes"
+)
+
+const (
+	// DefaultInterval интервал резервного копирования по умолчанию
+	DefaultInterval = time.Hour
+
+	// DefaultKeep количество хранимых копий по умолчанию
+	DefaultKeep = 24
+
+	// batchSize количество заметок, читаемых из хранилища за один раз
+	batchSize = 500
+
+	// Имя архива: backup-<время начала копирования в UTC>.zip, имена упорядочены по времени
+	namePrefix = "backup-"
+	nameSuffix = ".zip"
+	nameLayout = "20060102T150405.000Z"
+
+	// maxReportedConflicts максимальное количество ID конфликтующих заметок в отчете восстановления
+	maxReportedConflicts = 100
+)
+
+var (
+	// ErrBackupNotFound возвращается, когда резервной копии с указанным именем нет в хранилище
+	ErrBackupNotFound = errors.New("backup not found")
+
+	// ErrInvalidBackupName возвращается для имени, которое не может быть именем резервной копии
+	ErrInvalidBackupName = errors.New("invalid backup name: expected backup-<time>.zip")
+
+	// ErrInvalidBackup возвращается для поврежденного архива или архива неизвестного формата
+	ErrInvalidBackup = errors.New("invalid backup archive")
+
+	// ErrRestoreConflict возвращается стратегией RestoreConflictFail, если заметки из копии уже есть в хранилище
+	ErrRestoreConflict = errors.New("backup notes already exist in the repository")
+
+	// ErrEmptyRepository возвращается при копировании хранилища без заметок: такая копия не сохраняется,
+	// чтобы после перезапуска с пустым хранилищем в памяти политика хранения не вытеснила копии с данными
+	ErrEmptyRepository = errors.New("repository is empty, nothing to back up")
+)
+
+// Destination хранилище архивов резервных копий (см. attachments.Destination)
+type Destination interface {
+	// Put сохраняет файл размером size байт под ключом key и возвращает его адрес
+	Put(ctx context.Context, key string, data io.Reader, size int64, contentType string) (string, error)
+
+	// Get открывает файл key на чтение
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+
+	// List возвращает ключи файлов, начинающиеся с prefix, в порядке возрастания
+	List(ctx context.Context, prefix string) ([]string, error)
+
+	// Delete удаляет файл key
+	Delete(ctx context.Context, key string) error
+}
+
+// RestoreInput параметры восстановления из резервной копии
+type RestoreInput struct {
+	Backup   string                        // Имя архива (пусто - последняя копия)
+	Strategy model.RestoreConflictStrategy // Поведение для заметок, которые уже есть в хранилище (0 - пропуск)
+	DryRun   bool                          // Только посчитать изменения, не изменяя хранилище
+}
+
+// Manager копирует хранилище по расписанию и восстанавливает его из копий
+// Копируются и восстанавливаются хранимые представления заметок и ревизий: содержимое,
+// зашифрованное в хранилище, остается зашифрованным, а ключи данных в копию не входят
+// Копия не атомарна: заметки, изменяемые во время копирования, попадают в нее в одном из состояний
+type Manager struct {
+	notes       repository.NoteRepository
+	revisions   repository.RevisionRepository
+	shares      repository.ShareRepository
+	destination Destination
+	reminders   notes.ReminderScheduler
+	interval    time.Duration
+	keep        int
+	now         func() time.Time
+
+	// exclusive не дает копированию и восстановлению выполняться одновременно
+	exclusive sync.Mutex
+
+	mu     sync.Mutex
+	status model.BackupStatus
+}
+
+// Option настраивает менеджер резервных копий
+type Option func(*Manager)
+
+// WithInterval задает интервал копирования по расписанию (по умолчанию DefaultInterval)
+func WithInterval(interval time.Duration) Option {
+	return func(m *Manager) {
+		if interval > 0 {
+			m.interval = interval
+		}
+	}
+}
+
+// WithKeep задает количество хранимых копий: после копирования более старые удаляются
+// (по умолчанию DefaultKeep)
+func WithKeep(keep int) Option {
+	return func(m *Manager) {
+		if keep > 0 {
+			m.keep = keep
+		}
+	}
+}
+
+// WithReminderScheduler подключает планировщик напоминаний: напоминания восстановленных заметок
+// планируются сразу, без перезапуска сервера
+func WithReminderScheduler(scheduler notes.ReminderScheduler) Option {
+	return func(m *Manager) {
+		m.reminders = scheduler
+	}
+}
+
+// WithClock задает источник времени копий и расписания (по умолчанию time.Now)
+func WithClock(now func() time.Time) Option {
+	return func(m *Manager) {
+		m.now = now
+	}
+}
+
+// NewManager создает менеджер резервных копий хранилищ заметок, ревизий и доступов
+func NewManager(noteRepo repository.NoteRepository, revisionRepo repository.RevisionRepository, shareRepo repository.ShareRepository, destination Destination, opts ...Option) *Manager {
+	m := &Manager{
+		notes:       noteRepo,
+		revisions:   revisionRepo,
+		shares:      shareRepo,
+		destination: destination,
+		interval:    DefaultInterval,
+		keep:        DefaultKeep,
+		now:         time.Now,
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
+}
+
+// Run копирует хранилище каждые interval, первая копия создается через interval после запуска
+// Блокируется до отмены ctx
+func (m *Manager) Run(ctx context.Context) error {
+	timer := time.NewTimer(m.interval)
+	defer timer.Stop()
+	m.scheduleNext()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-timer.C:
+		}
+
+		if _, err := m.Backup(ctx); errors.Is(err, ErrEmptyRepository) {
+			log.Printf("Skipping scheduled backup: %v", err)
+		}
+		m.scheduleNext()
+		timer.Reset(m.interval)
+	}
+}
+
+// Status возвращает состояние резервного копирования
+func (m *Manager) Status() model.BackupStatus {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.status
+}
+
+// scheduleNext фиксирует время следующего копирования по расписанию
+func (m *Manager) scheduleNext() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.status.NextBackupAt = m.now().Add(m.interval)
+}
+
+// Backup копирует хранилище, сохраняет архив и удаляет копии сверх количества хранимых
+func (m *Manager) Backup(ctx context.Context) (model.Backup, error) {
+	m.exclusive.Lock()
+	defer m.exclusive.Unlock()
+
+	started := m.now()
+	backup, err := m.backup(ctx, started)
+	if errors.Is(err, ErrEmptyRepository) {
+		return model.Backup{}, err
+	}
+
+	retained := -1
+	if err == nil {
+		// Копия уже сохранена, поэтому ошибка удаления старых копий ее не отменяет
+		if retained, err = m.prune(ctx); err != nil {
+			log.Printf("Failed to apply backup retention: %v", err)
+			retained, err = -1, nil
+		}
+	}
+
+	m.mu.Lock()
+	m.status.LastAttemptAt = started
+	m.status.LastDuration = m.now().Sub(started)
+	m.status.LastError = err
+	if err != nil {
+		m.status.Failed++
+	} else {
+		m.status.Succeeded++
+		m.status.LastBackup = backup
+	}
+	if retained >= 0 {
+		m.status.Retained = retained
+	}
+	m.mu.Unlock()
+
+	if err != nil {
+		log.Printf("Backup failed: %v", err)
+		return model.Backup{}, err
+	}
+	log.Printf("Backed up %d notes, %d revisions and %d shares to %s (%d bytes)",
+		backup.Notes, backup.Revisions, backup.Shares, backup.Location, backup.Size)
+	return backup, nil
+}
+
+// backup записывает архив во временный файл и сохраняет его в хранилище
+// Временный файл нужен, потому что хранилище принимает файл известного размера
+func (m *Manager) backup(ctx context.Context, createdAt time.Time) (model.Backup, error) {
+	spool, err := os.CreateTemp("", "backup-*")
+	if err != nil {
+		return model.Backup{}, err
+	}
+	defer os.Remove(spool.Name())
+	defer spool.Close()
+
+	backup := model.Backup{Name: namePrefix + createdAt.UTC().Format(nameLayout) + nameSuffix, CreatedAt: createdAt}
+	if err := m.write(repository.WithoutOwner(ctx), spool, &backup); err != nil {
+		return model.Backup{}, err
+	}
+
+	size, err := spool.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return model.Backup{}, err
+	}
+	if _, err := spool.Seek(0, io.SeekStart); err != nil {
+		return model.Backup{}, err
+	}
+
+	backup.Size = size
+	backup.Location, err = m.destination.Put(ctx, backup.Name, spool, size, "application/zip")
+	if err != nil {
+		return model.Backup{}, fmt.Errorf("failed to store backup: %w", err)
+	}
+	return backup, nil
+}
+
+// write записывает заметки, затем ревизии и доступы скопированных заметок и манифест
+func (m *Manager) write(ctx context.Context, w io.Writer, backup *model.Backup) error {
+	buffered := bufio.NewWriter(w)
+	archive := zip.NewWriter(buffered)
+
+	var ids []string
+	err := writeEntry(archive, notesEntry, func(encoder *json.Encoder) error {
+		return m.forEachNote(ctx, func(note model.Note) error {
+			ids = append(ids, note.ID)
+			return encoder.Encode(newNoteRecord(note))
+		})
+	})
+	if err != nil {
+		return err
+	}
+	if len(ids) == 0 {
+		return ErrEmptyRepository
+	}
+	backup.Notes = int64(len(ids))
+
+	err = writeEntry(archive, revisionsEntry, func(encoder *json.Encoder) error {
+		for _, id := range ids {
+			revisions, err := m.revisions.List(ctx, id)
+			if err != nil {
+				return fmt.Errorf("failed to list revisions of note %s: %w", id, err)
+			}
+			for _, revision := range revisions {
+				if err := encoder.Encode(newRevisionRecord(revision)); err != nil {
+					return err
+				}
+			}
+			backup.Revisions += int64(len(revisions))
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	err = writeEntry(archive, sharesEntry, func(encoder *json.Encoder) error {
+		for _, id := range ids {
+			shares, err := m.shares.ListByNoteID(ctx, id)
+			if err != nil {
+				return fmt.Errorf("failed to list shares of note %s: %w", id, err)
+			}
+			for _, share := range shares {
+				if err := encoder.Encode(newShareRecord(share)); err != nil {
+					return err
+				}
+			}
+			backup.Shares += int64(len(shares))
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	err = writeEntry(archive, manifestEntry, func(encoder *json.Encoder) error {
+		return encoder.Encode(manifest{
+			FormatVersion: formatVersion,
+			CreatedAt:     backup.CreatedAt,
+			Notes:         backup.Notes,
+			Revisions:     backup.Revisions,
+			Shares:        backup.Shares,
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := archive.Close(); err != nil {
+		return err
+	}
+	return buffered.Flush()
+}
+
+// writeEntry создает файл name в архиве и передает fn кодировщик JSON Lines для него
+func writeEntry(archive *zip.Writer, name string, fn func(encoder *json.Encoder) error) error {
+	entry, err := archive.Create(name)
+	if err != nil {
+		return err
+	}
+	return fn(json.NewEncoder(entry))
+}
+
+// forEachNote обходит все заметки хранилища: порциями, если хранилище это поддерживает
+func (m *Manager) forEachNote(ctx context.Context, fn func(model.Note) error) error {
+	if iterator, ok := m.notes.(repository.NoteIterator); ok {
+		return iterator.ForEach(ctx, batchSize, fn)
+	}
+
+	all, err := m.notes.List(ctx)
+	if err != nil {
+		return err
+	}
+	for _, note := range all {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := fn(note); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// list возвращает имена резервных копий в хранилище от старых к новым
+func (m *Manager) list(ctx context.Context) ([]string, error) {
+	keys, err := m.destination.List(ctx, namePrefix)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list backups: %w", err)
+	}
+
+	names := keys[:0]
+	for _, key := range keys {
+		if validName(key) {
+			names = append(names, key)
+		}
+	}
+	slices.Sort(names)
+	return names, nil
+}
+
+// prune удаляет самые старые копии сверх keep и возвращает количество оставшихся
+func (m *Manager) prune(ctx context.Context) (int, error) {
+	names, err := m.list(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	for len(names) > m.keep {
+		if err := m.destination.Delete(ctx, names[0]); err != nil {
+			return 0, fmt.Errorf("failed to delete backup %s: %w", names[0], err)
+		}
+		log.Printf("Deleted backup %s (retention: keep %d)", names[0], m.keep)
+		names = names[1:]
+	}
+	return len(names), nil
+}
+
+// Restore восстанавливает заметки из резервной копии вместе с их ревизиями и доступами
+// Ревизии и доступы восстановленной заметки заменяются сохраненными в копии; заметки,
+// которых нет в копии, не изменяются. Доступно только администраторам
+func (m *Manager) Restore(ctx context.Context, input RestoreInput) (model.RestoreReport, error) {
+	principal, ok := auth.FromContext(ctx)
+	if !ok || !principal.HasRole(auth.RoleAdmin) {
+		return model.RestoreReport{}, auth.ErrPermissionDenied
+	}
+
+	strategy := input.Strategy
+	if strategy == 0 {
+		strategy = model.RestoreConflictSkip
+	}
+	if strategy < model.RestoreConflictSkip || strategy > model.RestoreConflictFail {
+		return model.RestoreReport{}, errors.New("invalid conflict strategy")
+	}
+	if input.Backup != "" && !validName(input.Backup) {
+		return model.RestoreReport{}, ErrInvalidBackupName
+	}
+
+	m.exclusive.Lock()
+	defer m.exclusive.Unlock()
+
+	name, err := m.resolve(ctx, input.Backup)
+	if err != nil {
+		return model.RestoreReport{}, err
+	}
+
+	spool, size, err := m.download(ctx, name)
+	if err != nil {
+		return model.RestoreReport{}, err
+	}
+	defer os.Remove(spool.Name())
+	defer spool.Close()
+
+	archive, err := openArchive(spool, size)
+	if err != nil {
+		return model.RestoreReport{}, err
+	}
+
+	report := model.RestoreReport{Backup: name, DryRun: input.DryRun}
+	restore, err := m.plan(repository.WithoutOwner(ctx), archive, strategy, &report)
+	if err != nil {
+		return model.RestoreReport{}, err
+	}
+	if strategy == model.RestoreConflictFail && len(report.Conflicts) > 0 && !input.DryRun {
+		return model.RestoreReport{}, fmt.Errorf("%w: %d of %d notes", ErrRestoreConflict, report.Overwritten+report.Skipped, report.Notes)
+	}
+
+	if err := m.apply(repository.WithoutOwner(ctx), archive, restore, &report); err != nil {
+		return model.RestoreReport{}, err
+	}
+
+	if input.DryRun {
+		log.Printf("Dry run of restore from %s by %s: %d notes to create, %d to overwrite, %d to skip",
+			name, principal.UserID, report.Created, report.Overwritten, report.Skipped)
+	} else {
+		log.Printf("Restored %s by %s: created %d notes, overwrote %d, skipped %d, restored %d revisions and %d shares",
+			name, principal.UserID, report.Created, report.Overwritten, report.Skipped, report.Revisions, report.Shares)
+	}
+	return report, nil
+}
+
+// resolve проверяет, что копия есть в хранилище; пустое имя означает последнюю копию
+func (m *Manager) resolve(ctx context.Context, name string) (string, error) {
+	names, err := m.list(ctx)
+	if err != nil {
+		return "", err
+	}
+	if name == "" {
+		if len(names) == 0 {
+			return "", ErrBackupNotFound
+		}
+		return names[len(names)-1], nil
+	}
+	if _, found := slices.BinarySearch(names, name); !found {
+		return "", ErrBackupNotFound
+	}
+	return name, nil
+}
+
+// download сохраняет архив во временный файл: для чтения ZIP нужен произвольный доступ
+func (m *Manager) download(ctx context.Context, name string) (*os.File, int64, error) {
+	data, err := m.destination.Get(ctx, name)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to read backup %s: %w", name, err)
+	}
+	defer data.Close()
+
+	spool, err := os.CreateTemp("", "restore-*")
+	if err != nil {
+		return nil, 0, err
+	}
+	size, err := io.Copy(spool, data)
+	if err != nil {
+		spool.Close()
+		os.Remove(spool.Name())
+		return nil, 0, fmt.Errorf("failed to read backup %s: %w", name, err)
+	}
+	return spool, size, nil
+}
+
+// plan определяет, какие заметки копии восстанавливаются, и считает конфликты
+// Возвращает множество ID восстанавливаемых заметок
+func (m *Manager) plan(ctx context.Context, archive *archiveReader, strategy model.RestoreConflictStrategy, report *model.RestoreReport) (map[string]bool, error) {
+	restore := make(map[string]bool)
+	err := eachRecord(archive, notesEntry, func(record noteRecord) error {
+		if record.ID == "" {
+			return fmt.Errorf("%w: note without id", ErrInvalidBackup)
+		}
+		report.Notes++
+
+		_, err := m.notes.GetByID(ctx, record.ID)
+		switch {
+		case errors.Is(err, memory.ErrNoteNotFound):
+			report.Created++
+			restore[record.ID] = true
+			return nil
+		case err != nil:
+			return err
+		}
+
+		if len(report.Conflicts) < maxReportedConflicts {
+			report.Conflicts = append(report.Conflicts, record.ID)
+		}
+		if strategy == model.RestoreConflictOverwrite {
+			report.Overwritten++
+			restore[record.ID] = true
+		} else {
+			report.Skipped++
+		}
+		return nil
+	})
+	return restore, err
+}
+
+// apply восстанавливает отобранные заметки, затем их ревизии и доступы
+// При пробном запуске только считает ревизии и доступы, которые были бы восстановлены
+func (m *Manager) apply(ctx context.Context, archive *archiveReader, restore map[string]bool, report *model.RestoreReport) error {
+	err := eachRecord(archive, notesEntry, func(record noteRecord) error {
+		if !restore[record.ID] || report.DryRun {
+			return nil
+		}
+		return m.restoreNote(ctx, record.model())
+	})
+	if err != nil {
+		return err
+	}
+
+	err = eachRecord(archive, revisionsEntry, func(record revisionRecord) error {
+		if !restore[record.NoteID] {
+			return nil
+		}
+		report.Revisions++
+		if report.DryRun {
+			return nil
+		}
+		if _, err := m.revisions.Add(ctx, record.model()); err != nil {
+			return fmt.Errorf("failed to restore revision %d of note %s: %w", record.Revision, record.NoteID, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return eachRecord(archive, sharesEntry, func(record shareRecord) error {
+		if !restore[record.NoteID] {
+			return nil
+		}
+		report.Shares++
+		if report.DryRun {
+			return nil
+		}
+		if err := m.shares.Save(ctx, record.model()); err != nil {
+			return fmt.Errorf("failed to restore share of note %s: %w", record.NoteID, err)
+		}
+		return nil
+	})
+}
+
+// restoreNote заменяет заметку копией и удаляет ее текущие ревизии и доступы,
+// которые затем восстанавливаются из копии
+func (m *Manager) restoreNote(ctx context.Context, note model.Note) error {
+	if err := m.revisions.DeleteByNoteID(ctx, note.ID); err != nil {
+		return fmt.Errorf("failed to clear revisions of note %s: %w", note.ID, err)
+	}
+	if err := m.shares.DeleteByNoteID(ctx, note.ID); err != nil {
+		return fmt.Errorf("failed to clear shares of note %s: %w", note.ID, err)
+	}
+
+	if restorer, ok := m.notes.(repository.NoteRestorer); ok {
+		if err := restorer.Restore(ctx, note); err != nil {
+			return fmt.Errorf("failed to restore note %s: %w", note.ID, err)
+		}
+	} else {
+		if err := m.notes.Delete(ctx, note.ID); err != nil && !errors.Is(err, memory.ErrNoteNotFound) {
+			return fmt.Errorf("failed to replace note %s: %w", note.ID, err)
+		}
+		if _, err := m.notes.Create(ctx, note); err != nil {
+			return fmt.Errorf("failed to restore note %s: %w", note.ID, err)
+		}
+	}
+
+	if m.reminders != nil {
+		m.reminders.Schedule(note)
+	}
+	return nil
+}
+
+// validName проверяет, что name - имя архива резервной копии, а не произвольный ключ хранилища
+func validName(name string) bool {
+	stamp, ok := strings.CutPrefix(name, namePrefix)
+	if !ok {
+		return false
+	}
+	stamp, ok = strings.CutSuffix(stamp, nameSuffix)
+	if !ok {
+		return false
+	}
+	_, err := time.Parse(nameLayout, stamp)
+	return err == nil
+}
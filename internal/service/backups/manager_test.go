@@ -0,0 +1,213 @@
+package backups
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"notes-service/internal/auth"
+	"notes-service/internal/model"
+	"notes-service/internal/repository"
+	"notes-service/internal/repository/attachments"
+	"notes-service/internal/repository/memory"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+var (
+	admin = auth.NewContext(context.Background(), auth.Principal{UserID: "admin", Roles: []string{auth.RoleUser, auth.RoleAdmin}})
+	alice = auth.NewContext(context.Background(), auth.Principal{UserID: "alice", Roles: []string{auth.RoleUser}})
+)
+
+// testStore хранилища, копируемые менеджером
+type testStore struct {
+	notes     repository.NoteRepository
+	revisions repository.RevisionRepository
+	shares    repository.ShareRepository
+}
+
+func newTestStore() testStore {
+	return testStore{
+		notes:     memory.NewRepository(),
+		revisions: memory.NewRevisionRepository(),
+		shares:    memory.NewShareRepository(),
+	}
+}
+
+func (s testStore) manager(t *testing.T, destination Destination, opts ...Option) *Manager {
+	t.Helper()
+	return NewManager(s.notes, s.revisions, s.shares, destination, opts...)
+}
+
+func newDestination(t *testing.T) Destination {
+	t.Helper()
+	destination, err := attachments.NewFilesystemDestination(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to create destination: %v", err)
+	}
+	return destination
+}
+
+// seed создает заметку владельца с двумя ревизиями и доступом для bob
+func (s testStore) seed(t *testing.T, id, owner, title string) model.Note {
+	t.Helper()
+	ctx := context.Background()
+
+	note, err := s.notes.Create(ctx, model.Note{ID: id, Title: title, Content: "content of " + title, OwnerID: owner, Tags: []string{"work"}})
+	if err != nil {
+		t.Fatalf("Failed to create note: %v", err)
+	}
+	for _, revisionTitle := range []string{title + " v1", title + " v2"} {
+		if _, err := s.revisions.Add(ctx, model.NoteRevision{NoteID: id, Title: revisionTitle}); err != nil {
+			t.Fatalf("Failed to add revision: %v", err)
+		}
+	}
+	if err := s.shares.Save(ctx, model.Share{NoteID: id, OwnerID: owner, UserID: "bob", Permission: model.SharePermissionRead}); err != nil {
+		t.Fatalf("Failed to save share: %v", err)
+	}
+	return note
+}
+
+func TestManager_BackupAndRetention(t *testing.T) {
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	clock := func() time.Time { return now }
+
+	store := newTestStore()
+	destination := newDestination(t)
+	manager := store.manager(t, destination, WithKeep(2), WithClock(clock))
+
+	if _, err := manager.Backup(context.Background()); !errors.Is(err, ErrEmptyRepository) {
+		t.Fatalf("Expected ErrEmptyRepository, got: %v", err)
+	}
+	store.seed(t, "note-1", "alice", "First")
+	store.seed(t, "note-2", "bob", "Second")
+
+	var names []string
+	for range 3 {
+		backup, err := manager.Backup(context.Background())
+		if err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+		if backup.Notes != 2 || backup.Revisions != 4 || backup.Shares != 2 || backup.Size == 0 {
+			t.Errorf("Unexpected backup: %+v", backup)
+		}
+		names = append(names, backup.Name)
+		now = now.Add(time.Hour)
+	}
+	if names[0] != "backup-20260102T030405.000Z.zip" {
+		t.Errorf("Unexpected backup name %q", names[0])
+	}
+
+	// Политика хранения оставляет две последние копии
+	kept, err := destination.List(context.Background(), "")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if strings.Join(kept, ",") != names[1]+","+names[2] {
+		t.Errorf("Expected %v to be kept, got %v", names[1:], kept)
+	}
+
+	status := manager.Status()
+	if status.LastBackup.Name != names[2] || status.Retained != 2 || status.Succeeded != 3 || status.Failed != 0 || status.LastError != nil {
+		t.Errorf("Unexpected status: %+v", status)
+	}
+
+	want := `# HELP notes_backup_last_success_timestamp_seconds Time of the last successful backup.
+# TYPE notes_backup_last_success_timestamp_seconds gauge
+notes_backup_last_success_timestamp_seconds 1.767330245e+09
+# HELP notes_backup_retained Backups kept in the destination after applying the retention policy.
+# TYPE notes_backup_retained gauge
+notes_backup_retained 2
+# HELP notes_backup_runs_total Backup attempts by result.
+# TYPE notes_backup_runs_total counter
+notes_backup_runs_total{result="failure"} 0
+notes_backup_runs_total{result="success"} 3
+`
+	if err := testutil.CollectAndCompare(manager, strings.NewReader(want),
+		"notes_backup_last_success_timestamp_seconds", "notes_backup_retained", "notes_backup_runs_total"); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestManager_Restore(t *testing.T) {
+	ctx := context.Background()
+	source := newTestStore()
+	destination := newDestination(t)
+	original := source.seed(t, "note-1", "alice", "First")
+	source.seed(t, "note-2", "bob", "Second")
+	backup, err := source.manager(t, destination).Backup(ctx)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	// Целевое хранилище уже содержит измененную note-1, а note-2 в нем нет
+	target := newTestStore()
+	if _, err := target.notes.Create(ctx, model.Note{ID: "note-1", Title: "Changed", OwnerID: "alice"}); err != nil {
+		t.Fatalf("Failed to create note: %v", err)
+	}
+	manager := target.manager(t, destination)
+
+	if _, err := manager.Restore(alice, RestoreInput{}); !errors.Is(err, auth.ErrPermissionDenied) {
+		t.Errorf("Expected ErrPermissionDenied for non-admin, got: %v", err)
+	}
+	if _, err := manager.Restore(admin, RestoreInput{Backup: "../secret"}); !errors.Is(err, ErrInvalidBackupName) {
+		t.Errorf("Expected ErrInvalidBackupName, got: %v", err)
+	}
+	if _, err := manager.Restore(admin, RestoreInput{Backup: "backup-20200101T000000.000Z.zip"}); !errors.Is(err, ErrBackupNotFound) {
+		t.Errorf("Expected ErrBackupNotFound, got: %v", err)
+	}
+	if _, err := manager.Restore(admin, RestoreInput{Strategy: model.RestoreConflictFail}); !errors.Is(err, ErrRestoreConflict) {
+		t.Errorf("Expected ErrRestoreConflict, got: %v", err)
+	}
+
+	// Пробный запуск считает изменения, не изменяя хранилище
+	report, err := manager.Restore(admin, RestoreInput{Strategy: model.RestoreConflictOverwrite, DryRun: true})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if report.Backup != backup.Name || report.Notes != 2 || report.Created != 1 || report.Overwritten != 1 ||
+		report.Revisions != 4 || report.Shares != 2 || len(report.Conflicts) != 1 || report.Conflicts[0] != "note-1" {
+		t.Errorf("Unexpected dry run report: %+v", report)
+	}
+	if _, err := target.notes.GetByID(ctx, "note-2"); !errors.Is(err, memory.ErrNoteNotFound) {
+		t.Errorf("Expected dry run not to restore notes, got: %v", err)
+	}
+
+	// По умолчанию существующие заметки пропускаются
+	report, err = manager.Restore(admin, RestoreInput{Backup: backup.Name})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if report.Created != 1 || report.Skipped != 1 || report.Revisions != 2 || report.Shares != 1 {
+		t.Errorf("Unexpected report: %+v", report)
+	}
+	if note, _ := target.notes.GetByID(ctx, "note-1"); note.Title != "Changed" {
+		t.Errorf("Expected skipped note to stay unchanged, got %q", note.Title)
+	}
+	if share, err := target.shares.Get(ctx, "note-2", "bob"); err != nil || share.OwnerID != "bob" {
+		t.Errorf("Expected restored share, got %+v, %v", share, err)
+	}
+
+	report, err = manager.Restore(admin, RestoreInput{Strategy: model.RestoreConflictOverwrite})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if report.Overwritten != 2 || report.Created != 0 {
+		t.Errorf("Unexpected report: %+v", report)
+	}
+
+	// Заметка восстанавливается вместе с временем, версией и ревизиями
+	restored, err := target.notes.GetByID(ctx, "note-1")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if restored.Title != original.Title || restored.Version != original.Version || !restored.UpdatedAt.Equal(original.UpdatedAt) || restored.OwnerID != "alice" {
+		t.Errorf("Expected %+v, got %+v", original, restored)
+	}
+	revisions, err := target.revisions.List(ctx, "note-1")
+	if err != nil || len(revisions) != 2 || revisions[1].Revision != 2 || revisions[1].Title != "First v2" {
+		t.Errorf("Expected restored revisions, got %+v, %v", revisions, err)
+	}
+}
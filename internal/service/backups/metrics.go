@@ -0,0 +1,54 @@
+package backups
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Описания метрик резервного копирования
+var (
+	lastSuccessDesc = prometheus.NewDesc("notes_backup_last_success_timestamp_seconds",
+		"Time of the last successful backup.", nil, nil)
+	lastAttemptDesc = prometheus.NewDesc("notes_backup_last_attempt_timestamp_seconds",
+		"Time of the last backup attempt.", nil, nil)
+	lastDurationDesc = prometheus.NewDesc("notes_backup_last_duration_seconds",
+		"Duration of the last backup attempt.", nil, nil)
+	lastSizeDesc = prometheus.NewDesc("notes_backup_last_size_bytes",
+		"Size of the last successful backup archive.", nil, nil)
+	lastNotesDesc = prometheus.NewDesc("notes_backup_last_notes",
+		"Notes in the last successful backup.", nil, nil)
+	retainedDesc = prometheus.NewDesc("notes_backup_retained",
+		"Backups kept in the destination after applying the retention policy.", nil, nil)
+	runsDesc = prometheus.NewDesc("notes_backup_runs_total",
+		"Backup attempts by result.", []string{"result"}, nil)
+)
+
+// Describe передает описания метрик резервного копирования (Manager реализует prometheus.Collector)
+func (m *Manager) Describe(ch chan<- *prometheus.Desc) {
+	for _, desc := range []*prometheus.Desc{lastSuccessDesc, lastAttemptDesc, lastDurationDesc, lastSizeDesc, lastNotesDesc, retainedDesc, runsDesc} {
+		ch <- desc
+	}
+}
+
+// Collect передает метрики резервного копирования по текущему состоянию (Status)
+func (m *Manager) Collect(ch chan<- prometheus.Metric) {
+	status := m.Status()
+
+	ch <- prometheus.MustNewConstMetric(lastSuccessDesc, prometheus.GaugeValue, unixSeconds(status.LastBackup.CreatedAt))
+	ch <- prometheus.MustNewConstMetric(lastAttemptDesc, prometheus.GaugeValue, unixSeconds(status.LastAttemptAt))
+	ch <- prometheus.MustNewConstMetric(lastDurationDesc, prometheus.GaugeValue, status.LastDuration.Seconds())
+	ch <- prometheus.MustNewConstMetric(lastSizeDesc, prometheus.GaugeValue, float64(status.LastBackup.Size))
+	ch <- prometheus.MustNewConstMetric(lastNotesDesc, prometheus.GaugeValue, float64(status.LastBackup.Notes))
+	ch <- prometheus.MustNewConstMetric(retainedDesc, prometheus.GaugeValue, float64(status.Retained))
+	ch <- prometheus.MustNewConstMetric(runsDesc, prometheus.CounterValue, float64(status.Succeeded), "success")
+	ch <- prometheus.MustNewConstMetric(runsDesc, prometheus.CounterValue, float64(status.Failed), "failure")
+}
+
+// unixSeconds возвращает время в секундах Unix, для нулевого времени - 0
+func unixSeconds(t time.Time) float64 {
+	if t.IsZero() {
+		return 0
+	}
+	return float64(t.UnixNano()) / float64(time.Second)
+}
@@ -0,0 +1,346 @@
+// Package chat реализует комнаты двунаправленного стрима Chat: участники входят в комнаты
+// и получают сообщения, отправленные в эти комнаты другими участниками, а также события
+// присутствия (вход и выход пользователей) и набора текста
+package chat
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	// DefaultMaxRooms количество комнат, в которых одновременно может находиться участник
+	DefaultMaxRooms = 16
+
+	// maxRoomIDLength максимальная длина идентификатора комнаты (в символах)
+	maxRoomIDLength = 128
+
+	// participantBuffer размер очереди сообщений участника
+	participantBuffer = 64
+)
+
+var (
+	// ErrInvalidRoom возвращается для пустого или слишком длинного room_id
+	ErrInvalidRoom = errors.New("invalid room_id: must be 1-128 characters")
+
+	// ErrNotInRoom возвращается при отправке в комнату или выходе из комнаты, в которой участника нет
+	ErrNotInRoom = errors.New("not a participant of the room")
+
+	// ErrTooManyRooms возвращается, когда участник уже находится в максимальном количестве комнат
+	ErrTooManyRooms = errors.New("too many rooms joined")
+
+	// ErrParticipantClosed возвращается после отключения участника
+	ErrParticipantClosed = errors.New("chat participant is closed")
+)
+
+// Kind вид сообщения комнаты
+type Kind int
+
+const (
+	KindText     Kind = iota // Текстовое сообщение
+	KindPresence             // Пользователь вошел в комнату или вышел из нее (Presence)
+	KindTyping               // Пользователь начал или закончил набирать сообщение (Typing)
+)
+
+// Presence изменение присутствия пользователя в комнате
+type Presence int
+
+const (
+	PresenceJoined Presence = iota + 1 // Первый стрим пользователя вошел в комнату
+	PresenceLeft                       // Последний стрим пользователя вышел из комнаты или отключился
+)
+
+// Message сообщение, отправленное в комнату
+type Message struct {
+	Kind          Kind
+	RoomID        string
+	SenderID      string // Пользователь, отправивший сообщение или изменивший присутствие
+	CorrelationID string // correlation_id сообщения отправителя
+	Text          string
+	Time          time.Time
+	Presence      Presence // Для KindPresence
+	Typing        bool     // Для KindTyping: пользователь набирает сообщение
+}
+
+// Hub хранит комнаты и их участников в памяти процесса
+// Участники на разных репликах сервера друг друга не видят
+type Hub struct {
+	now      func() time.Time
+	maxRooms int
+
+	mu    sync.Mutex
+	rooms map[string]*room
+}
+
+// room участники комнаты
+// Пользователь может находиться в комнате несколькими стримами, присутствие считается по пользователям
+type room struct {
+	members map[*Participant]bool
+	users   map[string]int // Количество стримов пользователя в комнате
+}
+
+// Option настраивает Hub
+type Option func(*Hub)
+
+// WithClock задает источник времени сообщений (по умолчанию time.Now)
+func WithClock(now func() time.Time) Option {
+	return func(h *Hub) {
+		h.now = now
+	}
+}
+
+// WithMaxRooms задает количество комнат, в которых одновременно может находиться участник
+// (по умолчанию DefaultMaxRooms)
+func WithMaxRooms(maxRooms int) Option {
+	return func(h *Hub) {
+		if maxRooms > 0 {
+			h.maxRooms = maxRooms
+		}
+	}
+}
+
+// NewHub создает пустой набор комнат
+func NewHub(opts ...Option) *Hub {
+	h := &Hub{
+		now:      time.Now,
+		maxRooms: DefaultMaxRooms,
+		rooms:    make(map[string]*room),
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
+}
+
+// Participant подключение пользователя к чату (один стрим Chat)
+type Participant struct {
+	UserID string
+
+	hub      *Hub
+	messages chan Message
+
+	// Поля ниже защищены hub.mu
+	rooms   map[string]bool
+	closed  bool
+	dropped bool // Очередь переполнилась и участник отключен
+}
+
+// Connect подключает участника userID; комнаты он выбирает через Join
+// После завершения стрима участника нужно отключить через Close
+func (h *Hub) Connect(userID string) *Participant {
+	return &Participant{
+		UserID:   userID,
+		hub:      h,
+		messages: make(chan Message, participantBuffer),
+		rooms:    make(map[string]bool),
+	}
+}
+
+// Rooms возвращает количество комнат, в которых есть хотя бы один участник
+func (h *Hub) Rooms() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return len(h.rooms)
+}
+
+// Messages возвращает очередь сообщений комнат участника, в том числе его собственных
+// Канал закрывается после Close или, если участник не успевает читать сообщения, при переполнении (см. Dropped)
+func (p *Participant) Messages() <-chan Message {
+	return p.messages
+}
+
+// Dropped сообщает, что участник отключен из-за переполнения очереди сообщений
+func (p *Participant) Dropped() bool {
+	p.hub.mu.Lock()
+	defer p.hub.mu.Unlock()
+	return p.dropped
+}
+
+// Join добавляет участника в комнату roomID; повторный вход в ту же комнату ничего не меняет
+func (p *Participant) Join(roomID string) error {
+	if err := validateRoomID(roomID); err != nil {
+		return err
+	}
+
+	h := p.hub
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if p.closed {
+		return ErrParticipantClosed
+	}
+	if p.rooms[roomID] {
+		return nil
+	}
+	if len(p.rooms) >= h.maxRooms {
+		return ErrTooManyRooms
+	}
+
+	r, ok := h.rooms[roomID]
+	if !ok {
+		r = &room{members: make(map[*Participant]bool), users: make(map[string]int)}
+		h.rooms[roomID] = r
+	}
+	r.members[p] = true
+	r.users[p.UserID]++
+	p.rooms[roomID] = true
+
+	if r.users[p.UserID] == 1 {
+		h.broadcast(r, Message{Kind: KindPresence, RoomID: roomID, SenderID: p.UserID, Time: h.now(), Presence: PresenceJoined}, p, false)
+	}
+	return nil
+}
+
+// Participants возвращает пользователей, находящихся в комнате roomID, в порядке возрастания ID
+// Участник должен находиться в комнате
+func (p *Participant) Participants(roomID string) ([]string, error) {
+	h := p.hub
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if p.closed {
+		return nil, ErrParticipantClosed
+	}
+	if !p.rooms[roomID] {
+		return nil, ErrNotInRoom
+	}
+
+	users := make([]string, 0, len(h.rooms[roomID].users))
+	for userID := range h.rooms[roomID].users {
+		users = append(users, userID)
+	}
+	slices.Sort(users)
+	return users, nil
+}
+
+// Leave удаляет участника из комнаты roomID
+func (p *Participant) Leave(roomID string) error {
+	h := p.hub
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if p.closed {
+		return ErrParticipantClosed
+	}
+	if !p.rooms[roomID] {
+		return ErrNotInRoom
+	}
+	h.leave(p, roomID)
+	return nil
+}
+
+// Send отправляет текст всем участникам комнаты roomID, включая отправителя
+// Отправитель должен находиться в комнате
+func (p *Participant) Send(roomID, correlationID, text string) (Message, error) {
+	h := p.hub
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if p.closed {
+		return Message{}, ErrParticipantClosed
+	}
+	if !p.rooms[roomID] {
+		return Message{}, ErrNotInRoom
+	}
+
+	msg := Message{
+		Kind:          KindText,
+		RoomID:        roomID,
+		SenderID:      p.UserID,
+		CorrelationID: correlationID,
+		Text:          text,
+		Time:          h.now(),
+	}
+	h.broadcast(h.rooms[roomID], msg, nil, false)
+	return msg, nil
+}
+
+// Typing сообщает остальным участникам комнаты roomID, что пользователь начал (typing = true)
+// или закончил набирать сообщение. Отправитель должен находиться в комнате
+// Событие необязательное: участники, которые не успевают читать сообщения, его не получают
+func (p *Participant) Typing(roomID string, typing bool) error {
+	h := p.hub
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if p.closed {
+		return ErrParticipantClosed
+	}
+	if !p.rooms[roomID] {
+		return ErrNotInRoom
+	}
+
+	h.broadcast(h.rooms[roomID], Message{Kind: KindTyping, RoomID: roomID, SenderID: p.UserID, Time: h.now(), Typing: typing}, p, true)
+	return nil
+}
+
+// broadcast отправляет сообщение участникам комнаты, кроме except. Вызывается под h.mu
+// Участник с переполненной очередью отключается, как EventService отключает медленного подписчика,
+// а для необязательных сообщений (optional) сообщение ему просто не доставляется
+func (h *Hub) broadcast(r *room, msg Message, except *Participant, optional bool) {
+	for member := range r.members {
+		if member == except {
+			continue
+		}
+		select {
+		case member.messages <- msg:
+		default:
+			if !optional {
+				member.dropped = true
+				h.close(member)
+			}
+		}
+	}
+}
+
+// Close удаляет участника из всех комнат и закрывает его очередь сообщений
+func (p *Participant) Close() {
+	h := p.hub
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.close(p)
+}
+
+// close отключает участника. Вызывается под h.mu
+func (h *Hub) close(p *Participant) {
+	if p.closed {
+		return
+	}
+	// Флаг устанавливается до выхода из комнат: события выхода могут отключить других медленных
+	// участников, и повторное отключение p во время обхода его комнат должно быть пропущено
+	p.closed = true
+	for roomID := range p.rooms {
+		h.leave(p, roomID)
+	}
+	close(p.messages)
+}
+
+// leave удаляет участника из комнаты, а опустевшую комнату - из набора. Вызывается под h.mu
+// Если это был последний стрим пользователя в комнате, остальные участники получают PresenceLeft
+func (h *Hub) leave(p *Participant, roomID string) {
+	delete(p.rooms, roomID)
+	r := h.rooms[roomID]
+	delete(r.members, p)
+	if len(r.members) == 0 {
+		delete(h.rooms, roomID)
+		return
+	}
+
+	r.users[p.UserID]--
+	if r.users[p.UserID] == 0 {
+		delete(r.users, p.UserID)
+		h.broadcast(r, Message{Kind: KindPresence, RoomID: roomID, SenderID: p.UserID, Time: h.now(), Presence: PresenceLeft}, p, false)
+	}
+}
+
+// validateRoomID проверяет идентификатор комнаты
+func validateRoomID(roomID string) error {
+	if strings.TrimSpace(roomID) == "" || utf8.RuneCountInString(roomID) > maxRoomIDLength {
+		return ErrInvalidRoom
+	}
+	return nil
+}
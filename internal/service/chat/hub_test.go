@@ -0,0 +1,230 @@
+package chat
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func receive(t *testing.T, p *Participant) Message {
+	t.Helper()
+	select {
+	case msg, ok := <-p.Messages():
+		if !ok {
+			t.Fatalf("Expected message for %s, channel is closed", p.UserID)
+		}
+		return msg
+	default:
+		t.Fatalf("Expected message for %s, got none", p.UserID)
+		return Message{}
+	}
+}
+
+func expectNone(t *testing.T, p *Participant) {
+	t.Helper()
+	select {
+	case msg := <-p.Messages():
+		t.Errorf("Expected no message for %s, got %+v", p.UserID, msg)
+	default:
+	}
+}
+
+func TestHub_BroadcastsWithinRoom(t *testing.T) {
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	hub := NewHub(WithClock(func() time.Time { return now }))
+
+	alice := hub.Connect("alice")
+	bob := hub.Connect("bob")
+	carol := hub.Connect("carol")
+	defer alice.Close()
+	defer bob.Close()
+	defer carol.Close()
+
+	for _, p := range []*Participant{alice, bob} {
+		if err := p.Join("general"); err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+	}
+	if err := carol.Join("random"); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	// Вошедший раньше участник узнает о входе bob
+	joined := Message{Kind: KindPresence, RoomID: "general", SenderID: "bob", Time: now, Presence: PresenceJoined}
+	if msg := receive(t, alice); msg != joined {
+		t.Errorf("Expected %+v, got %+v", joined, msg)
+	}
+	expectNone(t, bob)
+
+	sent, err := alice.Send("general", "c-1", "Hello")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	want := Message{Kind: KindText, RoomID: "general", SenderID: "alice", CorrelationID: "c-1", Text: "Hello", Time: now}
+	if sent != want {
+		t.Errorf("Expected %+v, got %+v", want, sent)
+	}
+
+	// Сообщение получают все участники комнаты, включая отправителя, и только они
+	for _, p := range []*Participant{alice, bob} {
+		if msg := receive(t, p); msg != want {
+			t.Errorf("Expected %+v for %s, got %+v", want, p.UserID, msg)
+		}
+	}
+	expectNone(t, carol)
+
+	if _, err := carol.Send("general", "c-2", "Hi"); !errors.Is(err, ErrNotInRoom) {
+		t.Errorf("Expected ErrNotInRoom for a room carol has not joined, got: %v", err)
+	}
+
+	// После выхода сообщения комнаты больше не приходят, пустая комната удаляется
+	if err := bob.Leave("general"); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if err := bob.Leave("general"); !errors.Is(err, ErrNotInRoom) {
+		t.Errorf("Expected ErrNotInRoom on second leave, got: %v", err)
+	}
+	left := Message{Kind: KindPresence, RoomID: "general", SenderID: "bob", Time: now, Presence: PresenceLeft}
+	if msg := receive(t, alice); msg != left {
+		t.Errorf("Expected %+v, got %+v", left, msg)
+	}
+	if _, err := alice.Send("general", "c-3", "Anyone?"); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	receive(t, alice)
+	expectNone(t, bob)
+
+	alice.Close()
+	if hub.Rooms() != 1 {
+		t.Errorf("Expected only carol's room to remain, got %d rooms", hub.Rooms())
+	}
+	if _, ok := <-alice.Messages(); ok {
+		t.Error("Expected closed message channel after Close")
+	}
+	if err := alice.Join("general"); !errors.Is(err, ErrParticipantClosed) {
+		t.Errorf("Expected ErrParticipantClosed, got: %v", err)
+	}
+}
+
+func TestHub_PresenceAndTyping(t *testing.T) {
+	hub := NewHub()
+	alice := hub.Connect("alice")
+	aliceTablet := hub.Connect("alice")
+	bob := hub.Connect("bob")
+	defer bob.Close()
+
+	for _, p := range []*Participant{bob, alice} {
+		if err := p.Join("general"); err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+	}
+	if msg := receive(t, bob); msg.Kind != KindPresence || msg.SenderID != "alice" || msg.Presence != PresenceJoined {
+		t.Errorf("Expected alice to join, got %+v", msg)
+	}
+
+	// Второй стрим того же пользователя не меняет его присутствие
+	if err := aliceTablet.Join("general"); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	expectNone(t, bob)
+	users, err := bob.Participants("general")
+	if err != nil || strings.Join(users, ",") != "alice,bob" {
+		t.Errorf("Expected participants alice,bob, got %v, %v", users, err)
+	}
+
+	// Набор текста получают остальные участники, но не сам стрим отправителя
+	if err := alice.Typing("general", true); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if msg := receive(t, bob); msg.Kind != KindTyping || msg.SenderID != "alice" || !msg.Typing {
+		t.Errorf("Expected alice typing, got %+v", msg)
+	}
+	if msg := receive(t, aliceTablet); msg.Kind != KindTyping {
+		t.Errorf("Expected typing on alice's other stream, got %+v", msg)
+	}
+	expectNone(t, alice)
+	if err := alice.Typing("random", true); !errors.Is(err, ErrNotInRoom) {
+		t.Errorf("Expected ErrNotInRoom, got: %v", err)
+	}
+
+	// Пользователь выходит из комнаты, когда отключается его последний стрим
+	alice.Close()
+	expectNone(t, bob)
+	aliceTablet.Close()
+	if msg := receive(t, bob); msg.Kind != KindPresence || msg.SenderID != "alice" || msg.Presence != PresenceLeft {
+		t.Errorf("Expected alice to leave, got %+v", msg)
+	}
+	if users, _ := bob.Participants("general"); strings.Join(users, ",") != "bob" {
+		t.Errorf("Expected only bob to remain, got %v", users)
+	}
+}
+
+func TestHub_RoomValidationAndLimit(t *testing.T) {
+	hub := NewHub(WithMaxRooms(2))
+	p := hub.Connect("alice")
+	defer p.Close()
+
+	for _, roomID := range []string{"", "  ", strings.Repeat("x", maxRoomIDLength+1)} {
+		if err := p.Join(roomID); !errors.Is(err, ErrInvalidRoom) {
+			t.Errorf("Expected ErrInvalidRoom for %q, got: %v", roomID, err)
+		}
+	}
+
+	for _, roomID := range []string{"a", "b", "a"} {
+		if err := p.Join(roomID); err != nil {
+			t.Fatalf("Expected no error joining %q, got: %v", roomID, err)
+		}
+	}
+	if err := p.Join("c"); !errors.Is(err, ErrTooManyRooms) {
+		t.Errorf("Expected ErrTooManyRooms, got: %v", err)
+	}
+}
+
+func TestHub_DropsSlowParticipant(t *testing.T) {
+	hub := NewHub()
+	sender := hub.Connect("alice")
+	slow := hub.Connect("bob")
+	defer sender.Close()
+
+	for _, p := range []*Participant{sender, slow} {
+		if err := p.Join("general"); err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+	}
+
+	// Отправитель читает свои сообщения, а bob - нет
+	for i := 0; i < participantBuffer; i++ {
+		if _, err := sender.Send("general", "", "spam"); err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+		receive(t, sender)
+	}
+
+	// Необязательное событие набора текста не отключает участника с полной очередью
+	if err := sender.Typing("general", true); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if slow.Dropped() {
+		t.Fatal("Expected typing event not to drop the slow participant")
+	}
+
+	if _, err := sender.Send("general", "", "spam"); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	receive(t, sender)
+
+	if !slow.Dropped() {
+		t.Fatal("Expected slow participant to be dropped")
+	}
+	count := 0
+	for range slow.Messages() {
+		count++
+	}
+	if count != participantBuffer {
+		t.Errorf("Expected %d buffered messages before the channel closed, got %d", participantBuffer, count)
+	}
+	if sender.Dropped() {
+		t.Error("Expected sender reading its messages to stay connected")
+	}
+}
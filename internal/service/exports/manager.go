@@ -0,0 +1,292 @@
+package exports
+
+import (
+	"archive/zip"
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"log"
+	"net/url"
+	"os"
+	"sync"
+	"time"
+
+	"notes-service/internal/auth"
+	"notes-service/internal/converter"
+	"notes-service/internal/model"
+	svc "notes-service/internal/service"
+	"notes-service/internal/service/notes"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// batchSize количество заметок, читаемых из хранилища за один раз
+	batchSize = 500
+
+	// retention время хранения завершенных операций для GetExportOperation
+	retention = 24 * time.Hour
+
+	// archiveEntry имя файла заметок внутри ZIP архива
+	archiveEntry = "notes.jsonl"
+)
+
+// ErrExportNotFound возвращается, когда операция выгрузки не найдена или принадлежит другому пользователю
+var ErrExportNotFound = errors.New("export operation not found")
+
+// Destination хранилище файлов выгрузки (см. attachments.Destination)
+type Destination interface {
+	// Put сохраняет файл размером size байт под ключом key и возвращает его адрес
+	Put(ctx context.Context, key string, data io.Reader, size int64, contentType string) (string, error)
+}
+
+// Manager запускает выгрузки заметок в хранилище как длительные операции и хранит их состояние
+// Выгрузка выполняется в фоне и прерывается при остановке сервера (serverCtx)
+type Manager struct {
+	noteService svc.NoteService
+	destination Destination
+	events      notes.EventBus
+	serverCtx   context.Context
+	now         func() time.Time
+
+	mu         sync.Mutex
+	operations map[string]*model.ExportOperation
+	running    sync.WaitGroup
+}
+
+// Option настраивает менеджер выгрузок
+type Option func(*Manager)
+
+// WithClock задает источник времени операций и срока их хранения (по умолчанию time.Now)
+func WithClock(now func() time.Time) Option {
+	return func(m *Manager) {
+		m.now = now
+	}
+}
+
+// NewManager создает менеджер выгрузок
+// Заметки читаются через noteService от имени пользователя, запустившего выгрузку,
+// события о завершении публикуются в events
+func NewManager(serverCtx context.Context, noteService svc.NoteService, destination Destination, events notes.EventBus, opts ...Option) *Manager {
+	m := &Manager{
+		noteService: noteService,
+		destination: destination,
+		events:      events,
+		serverCtx:   serverCtx,
+		now:         time.Now,
+		operations:  make(map[string]*model.ExportOperation),
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
+}
+
+// Start запускает выгрузку заметок вызывающего пользователя и сразу возвращает операцию
+func (m *Manager) Start(ctx context.Context, archive model.ExportArchive) (model.ExportOperation, error) {
+	principal, ok := auth.FromContext(ctx)
+	if !ok {
+		return model.ExportOperation{}, auth.ErrPermissionDenied
+	}
+	if archive != model.ExportArchiveNDJSON && archive != model.ExportArchiveZIP {
+		return model.ExportOperation{}, errors.New("invalid export archive")
+	}
+
+	op := &model.ExportOperation{
+		ID:        uuid.New().String(),
+		OwnerID:   principal.UserID,
+		Archive:   archive,
+		State:     model.ExportStateRunning,
+		CreatedAt: m.now(),
+	}
+
+	m.mu.Lock()
+	m.sweep()
+	m.operations[op.ID] = op
+	snapshot := *op
+	m.mu.Unlock()
+
+	// Выгрузка переживает завершение запроса, но сохраняет пользователя и тенанта из его контекста
+	runCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	stop := context.AfterFunc(m.serverCtx, cancel)
+
+	m.running.Add(1)
+	go func() {
+		defer m.running.Done()
+		defer cancel()
+		defer stop()
+		m.run(runCtx, op.ID)
+	}()
+
+	return snapshot, nil
+}
+
+// Get возвращает состояние операции выгрузки вызывающего пользователя
+func (m *Manager) Get(ctx context.Context, id string) (model.ExportOperation, error) {
+	principal, ok := auth.FromContext(ctx)
+	if !ok {
+		return model.ExportOperation{}, auth.ErrPermissionDenied
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	op, ok := m.operations[id]
+	if !ok || op.OwnerID != principal.UserID {
+		return model.ExportOperation{}, ErrExportNotFound
+	}
+	return *op, nil
+}
+
+// Wait ожидает завершения выполняющихся выгрузок (после отмены serverCtx они прерываются)
+func (m *Manager) Wait() {
+	m.running.Wait()
+}
+
+// run выполняет выгрузку и фиксирует результат операции
+func (m *Manager) run(ctx context.Context, id string) {
+	location, err := m.export(ctx, id)
+
+	m.mu.Lock()
+	op := m.operations[id]
+	op.CompletedAt = m.now()
+	if err != nil {
+		op.State = model.ExportStateFailed
+		op.Err = err
+	} else {
+		op.State = model.ExportStateSucceeded
+		op.Location = location
+	}
+	snapshot := *op
+	m.mu.Unlock()
+
+	if err != nil {
+		log.Printf("Export %s failed: %v", id, err)
+	} else {
+		log.Printf("Exported %d notes to %s (export %s)", snapshot.ExportedNotes, location, id)
+	}
+	m.events.Publish(notes.Event{Type: notes.EventExportCompleted, Export: snapshot})
+}
+
+// export записывает заметки во временный файл и сохраняет его в хранилище
+// Временный файл нужен, потому что хранилище принимает файл известного размера
+func (m *Manager) export(ctx context.Context, id string) (string, error) {
+	var total int64
+	if err := m.noteService.ForEach(ctx, batchSize, func(model.Note) error {
+		total++
+		return nil
+	}); err != nil {
+		return "", err
+	}
+	op := m.update(id, func(op *model.ExportOperation) { op.TotalNotes = total })
+
+	spool, err := os.CreateTemp("", "export-*")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(spool.Name())
+	defer spool.Close()
+
+	if err := m.write(ctx, id, spool, op.Archive); err != nil {
+		return "", err
+	}
+
+	size, err := spool.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return "", err
+	}
+	if _, err := spool.Seek(0, io.SeekStart); err != nil {
+		return "", err
+	}
+
+	return m.destination.Put(ctx, objectKey(op), spool, size, contentType(op.Archive))
+}
+
+// write записывает заметки пользователя в w в формате archive
+func (m *Manager) write(ctx context.Context, id string, w io.Writer, archive model.ExportArchive) error {
+	buffered := bufio.NewWriter(w)
+
+	var out io.Writer = buffered
+	var archiveWriter *zip.Writer
+	if archive == model.ExportArchiveZIP {
+		archiveWriter = zip.NewWriter(buffered)
+		entry, err := archiveWriter.Create(archiveEntry)
+		if err != nil {
+			return err
+		}
+		out = entry
+	}
+
+	writer, err := converter.NewNoteWriter(converter.FormatJSONLines, out)
+	if err != nil {
+		return err
+	}
+
+	var exported int64
+	err = m.noteService.ForEach(ctx, batchSize, func(note model.Note) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := writer.Write(note); err != nil {
+			return err
+		}
+
+		exported++
+		if exported%batchSize == 0 {
+			m.update(id, func(op *model.ExportOperation) { op.ExportedNotes = exported })
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	m.update(id, func(op *model.ExportOperation) { op.ExportedNotes = exported })
+
+	if err := writer.Close(); err != nil {
+		return err
+	}
+	if archiveWriter != nil {
+		if err := archiveWriter.Close(); err != nil {
+			return err
+		}
+	}
+	return buffered.Flush()
+}
+
+// update изменяет операцию под блокировкой и возвращает ее копию
+func (m *Manager) update(id string, fn func(op *model.ExportOperation)) model.ExportOperation {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	op := m.operations[id]
+	fn(op)
+	return *op
+}
+
+// sweep удаляет завершенные операции старше retention. Вызывается под m.mu
+func (m *Manager) sweep() {
+	cutoff := m.now().Add(-retention)
+	for id, op := range m.operations {
+		if op.Done() && op.CompletedAt.Before(cutoff) {
+			delete(m.operations, id)
+		}
+	}
+}
+
+// objectKey возвращает ключ файла выгрузки: exports/<пользователь>/<операция>.<расширение>
+func objectKey(op model.ExportOperation) string {
+	ext := ".jsonl"
+	if op.Archive == model.ExportArchiveZIP {
+		ext = ".zip"
+	}
+	return "exports/" + url.PathEscape(op.OwnerID) + "/" + op.ID + ext
+}
+
+// contentType возвращает MIME тип файла выгрузки
+func contentType(archive model.ExportArchive) string {
+	if archive == model.ExportArchiveZIP {
+		return "application/zip"
+	}
+	return "application/x-ndjson"
+}
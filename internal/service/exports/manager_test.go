@@ -0,0 +1,256 @@
+package exports
+
+import (
+	"archive/zip"
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"notes-service/internal/auth"
+	"notes-service/internal/model"
+	"notes-service/internal/repository/attachments"
+	"notes-service/internal/repository/memory"
+	svc "notes-service/internal/service"
+	"notes-service/internal/service/notes"
+)
+
+// newTestManager создает сервис заметок с заметками alice и bob и менеджер выгрузок в каталог
+func newTestManager(t *testing.T) (*Manager, chan notes.Event, string) {
+	t.Helper()
+
+	events := notes.NewEventService()
+	service := notes.NewNoteService(memory.NewRepository(), notes.WithEventService(events))
+
+	alice := auth.NewContext(context.Background(), auth.Principal{UserID: "alice"})
+	bob := auth.NewContext(context.Background(), auth.Principal{UserID: "bob"})
+	for _, title := range []string{"First", "Second", "Third"} {
+		if _, err := service.Create(alice, svc.CreateNoteInput{Title: title, Content: "Alice's note"}); err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+	}
+	if _, err := service.Create(bob, svc.CreateNoteInput{Title: "Bob", Content: "Bob's note"}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	dir := t.TempDir()
+	destination, err := attachments.NewFilesystemDestination(dir)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	manager := NewManager(context.Background(), service, destination, events)
+
+	ch := events.Subscribe()
+	t.Cleanup(func() { events.Unsubscribe(ch) })
+
+	return manager, ch, dir
+}
+
+// waitCompleted ожидает событие завершения выгрузки
+func waitCompleted(t *testing.T, ch chan notes.Event) model.ExportOperation {
+	t.Helper()
+
+	deadline := time.After(5 * time.Second)
+	for {
+		select {
+		case event := <-ch:
+			if event.Type == notes.EventExportCompleted {
+				return event.Export
+			}
+		case <-deadline:
+			t.Fatal("Expected export completed event")
+			return model.ExportOperation{}
+		}
+	}
+}
+
+// readTitles читает заголовки заметок из JSONL
+func readTitles(t *testing.T, r io.Reader) []string {
+	t.Helper()
+
+	var titles []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		var note struct {
+			Title string `json:"title"`
+		}
+		if err := json.Unmarshal(scanner.Bytes(), &note); err != nil {
+			t.Fatalf("Expected JSON line, got %q: %v", scanner.Text(), err)
+		}
+		titles = append(titles, note.Title)
+	}
+	return titles
+}
+
+func TestManager_ExportNDJSON(t *testing.T) {
+	manager, ch, dir := newTestManager(t)
+	alice := auth.NewContext(context.Background(), auth.Principal{UserID: "alice"})
+
+	op, err := manager.Start(alice, model.ExportArchiveNDJSON)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if op.State != model.ExportStateRunning || op.OwnerID != "alice" {
+		t.Errorf("Expected running operation of alice, got %+v", op)
+	}
+
+	completed := waitCompleted(t, ch)
+	if completed.ID != op.ID || completed.State != model.ExportStateSucceeded {
+		t.Fatalf("Expected succeeded operation %s, got %+v", op.ID, completed)
+	}
+	if completed.ExportedNotes != 3 || completed.TotalNotes != 3 {
+		t.Errorf("Expected 3 of 3 notes exported, got %d of %d", completed.ExportedNotes, completed.TotalNotes)
+	}
+	want := filepath.Join(dir, "exports", "alice", op.ID+".jsonl")
+	if completed.Location != want {
+		t.Errorf("Expected location %s, got %s", want, completed.Location)
+	}
+
+	file, err := os.Open(completed.Location)
+	if err != nil {
+		t.Fatalf("Expected export file, got: %v", err)
+	}
+	defer file.Close()
+	titles := readTitles(t, file)
+	sort.Strings(titles)
+	if strings.Join(titles, ",") != "First,Second,Third" {
+		t.Errorf("Expected only alice's notes, got %v", titles)
+	}
+
+	// Состояние операции доступно через Get после завершения
+	got, err := manager.Get(alice, op.ID)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if got.State != model.ExportStateSucceeded || got.Location != completed.Location {
+		t.Errorf("Expected completed operation from Get, got %+v", got)
+	}
+}
+
+func TestManager_ExportZIP(t *testing.T) {
+	manager, ch, _ := newTestManager(t)
+	bob := auth.NewContext(context.Background(), auth.Principal{UserID: "bob"})
+
+	if _, err := manager.Start(bob, model.ExportArchiveZIP); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	completed := waitCompleted(t, ch)
+	if completed.State != model.ExportStateSucceeded || !strings.HasSuffix(completed.Location, ".zip") {
+		t.Fatalf("Expected succeeded zip export, got %+v", completed)
+	}
+
+	archive, err := zip.OpenReader(completed.Location)
+	if err != nil {
+		t.Fatalf("Expected zip archive, got: %v", err)
+	}
+	defer archive.Close()
+	if len(archive.File) != 1 || archive.File[0].Name != archiveEntry {
+		t.Fatalf("Expected single %s entry, got %d files", archiveEntry, len(archive.File))
+	}
+	entry, err := archive.File[0].Open()
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	defer entry.Close()
+	if titles := readTitles(t, entry); len(titles) != 1 || titles[0] != "Bob" {
+		t.Errorf("Expected bob's note, got %v", titles)
+	}
+}
+
+func TestManager_Get_OwnerIsolation(t *testing.T) {
+	manager, ch, _ := newTestManager(t)
+	alice := auth.NewContext(context.Background(), auth.Principal{UserID: "alice"})
+	bob := auth.NewContext(context.Background(), auth.Principal{UserID: "bob"})
+
+	op, err := manager.Start(alice, model.ExportArchiveNDJSON)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	waitCompleted(t, ch)
+
+	if _, err := manager.Get(bob, op.ID); !errors.Is(err, ErrExportNotFound) {
+		t.Errorf("Expected ErrExportNotFound for another user's export, got: %v", err)
+	}
+	if _, err := manager.Get(alice, "missing"); !errors.Is(err, ErrExportNotFound) {
+		t.Errorf("Expected ErrExportNotFound for missing export, got: %v", err)
+	}
+	if _, err := manager.Start(context.Background(), model.ExportArchiveNDJSON); !errors.Is(err, auth.ErrPermissionDenied) {
+		t.Errorf("Expected ErrPermissionDenied without principal, got: %v", err)
+	}
+}
+
+// failingDestination хранилище, отклоняющее запись
+type failingDestination struct{}
+
+func (failingDestination) Put(context.Context, string, io.Reader, int64, string) (string, error) {
+	return "", errors.New("bucket is not writable")
+}
+
+func TestManager_DestinationFailure(t *testing.T) {
+	manager, ch, _ := newTestManager(t)
+	manager.destination = failingDestination{}
+	alice := auth.NewContext(context.Background(), auth.Principal{UserID: "alice"})
+
+	op, err := manager.Start(alice, model.ExportArchiveNDJSON)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	completed := waitCompleted(t, ch)
+	if completed.State != model.ExportStateFailed || completed.Err == nil || completed.Location != "" {
+		t.Fatalf("Expected failed operation with error, got %+v", completed)
+	}
+
+	manager.Wait()
+	got, err := manager.Get(alice, op.ID)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if got.State != model.ExportStateFailed {
+		t.Errorf("Expected failed state from Get, got %v", got.State)
+	}
+}
+
+func TestManager_ExportRedactsProtected(t *testing.T) {
+	events := notes.NewEventService()
+	service := notes.NewNoteService(memory.NewRepository(), notes.WithEventService(events))
+	alice := auth.NewContext(context.Background(), auth.Principal{UserID: "alice"})
+	note, err := service.Create(alice, svc.CreateNoteInput{Title: "Diary", Content: "Secret diary"})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if _, err := service.SetPassphrase(alice, note.ID, "correct horse"); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	destination, err := attachments.NewFilesystemDestination(t.TempDir())
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	manager := NewManager(context.Background(), service, destination, events)
+	ch := events.Subscribe()
+	defer events.Unsubscribe(ch)
+
+	if _, err := manager.Start(svc.WithNotePassphrase(alice, "correct horse"), model.ExportArchiveNDJSON); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	completed := waitCompleted(t, ch)
+	if completed.State != model.ExportStateSucceeded || completed.ExportedNotes != 1 {
+		t.Fatalf("Expected succeeded operation with 1 note, got %+v", completed)
+	}
+
+	data, err := os.ReadFile(completed.Location)
+	if err != nil {
+		t.Fatalf("Expected export file, got: %v", err)
+	}
+	if !strings.Contains(string(data), "Diary") || strings.Contains(string(data), "Secret diary") {
+		t.Errorf("Expected the protected note without content, got %s", data)
+	}
+}
@@ -0,0 +1,204 @@
+// Package keys запускает смену ключей шифрования заметок как длительную операцию
+package keys
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"notes-service/internal/auth"
+	"notes-service/internal/model"
+
+	"github.com/google/uuid"
+)
+
+// retention время хранения завершенных операций для GetKeyRotationOperation
+const retention = 24 * time.Hour
+
+var (
+	// ErrRotationNotFound возвращается, когда операция смены ключей не найдена или уже удалена
+	ErrRotationNotFound = errors.New("key rotation operation not found")
+
+	// ErrRotationInProgress возвращается при запуске смены ключей, пока выполняется предыдущая
+	ErrRotationInProgress = errors.New("key rotation is already in progress")
+)
+
+// Rotator сменяет ключи данных и перешифровывает заметки (см. encrypted.Rotator)
+type Rotator interface {
+	// RotateKeys создает новые ключи данных владельца ownerID (пусто - всех владельцев)
+	// и перешифровывает ключи данных текущим мастер-ключом
+	RotateKeys(ctx context.Context, ownerID string) (rotated, rewrapped int, err error)
+
+	// Reencrypt перешифровывает заметки владельца ownerID текущими ключами, сообщая прогресс
+	Reencrypt(ctx context.Context, ownerID string, progress func(processed, reencrypted, total int64)) error
+}
+
+// Manager запускает смену ключей как длительные операции и хранит их состояние
+// Одновременно выполняется не больше одной смены ключей; она прерывается при остановке сервера (serverCtx)
+type Manager struct {
+	rotator   Rotator
+	serverCtx context.Context
+	now       func() time.Time
+
+	mu         sync.Mutex
+	operations map[string]*model.KeyRotationOperation
+	active     string // ID выполняющейся операции
+	running    sync.WaitGroup
+}
+
+// Option настраивает менеджер смены ключей
+type Option func(*Manager)
+
+// WithClock задает источник времени операций и срока их хранения (по умолчанию time.Now)
+func WithClock(now func() time.Time) Option {
+	return func(m *Manager) {
+		m.now = now
+	}
+}
+
+// NewManager создает менеджер смены ключей
+func NewManager(serverCtx context.Context, rotator Rotator, opts ...Option) *Manager {
+	m := &Manager{
+		rotator:    rotator,
+		serverCtx:  serverCtx,
+		now:        time.Now,
+		operations: make(map[string]*model.KeyRotationOperation),
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
+}
+
+// Start запускает смену ключей владельца ownerID (пусто - всех владельцев) и сразу возвращает
+// операцию. Доступно только администраторам
+func (m *Manager) Start(ctx context.Context, ownerID string) (model.KeyRotationOperation, error) {
+	principal, ok := auth.FromContext(ctx)
+	if !ok || !principal.HasRole(auth.RoleAdmin) {
+		return model.KeyRotationOperation{}, auth.ErrPermissionDenied
+	}
+
+	op := &model.KeyRotationOperation{
+		ID:        uuid.New().String(),
+		OwnerID:   ownerID,
+		StartedBy: principal.UserID,
+		State:     model.KeyRotationStateRunning,
+		CreatedAt: m.now(),
+	}
+
+	m.mu.Lock()
+	if m.active != "" {
+		m.mu.Unlock()
+		return model.KeyRotationOperation{}, ErrRotationInProgress
+	}
+	m.sweep()
+	m.operations[op.ID] = op
+	m.active = op.ID
+	snapshot := *op
+	m.mu.Unlock()
+
+	// Смена ключей переживает завершение запроса, но сохраняет контекст тенанта
+	runCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	stop := context.AfterFunc(m.serverCtx, cancel)
+
+	m.running.Add(1)
+	go func() {
+		defer m.running.Done()
+		defer cancel()
+		defer stop()
+		m.run(runCtx, op.ID)
+	}()
+
+	return snapshot, nil
+}
+
+// Get возвращает состояние операции смены ключей. Доступно только администраторам
+func (m *Manager) Get(ctx context.Context, id string) (model.KeyRotationOperation, error) {
+	principal, ok := auth.FromContext(ctx)
+	if !ok || !principal.HasRole(auth.RoleAdmin) {
+		return model.KeyRotationOperation{}, auth.ErrPermissionDenied
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	op, ok := m.operations[id]
+	if !ok {
+		return model.KeyRotationOperation{}, ErrRotationNotFound
+	}
+	return *op, nil
+}
+
+// Wait ожидает завершения выполняющейся смены ключей (после отмены serverCtx она прерывается)
+func (m *Manager) Wait() {
+	m.running.Wait()
+}
+
+// run сменяет ключи, перешифровывает заметки и фиксирует результат операции
+func (m *Manager) run(ctx context.Context, id string) {
+	err := m.rotate(ctx, id)
+
+	m.mu.Lock()
+	op := m.operations[id]
+	op.CompletedAt = m.now()
+	if err != nil {
+		op.State = model.KeyRotationStateFailed
+		op.Err = err
+	} else {
+		op.State = model.KeyRotationStateSucceeded
+	}
+	m.active = ""
+	snapshot := *op
+	m.mu.Unlock()
+
+	if err != nil {
+		log.Printf("Key rotation %s failed: %v", id, err)
+		return
+	}
+	log.Printf("Rotated %d data keys, rewrapped %d, re-encrypted %d of %d notes (key rotation %s)",
+		snapshot.RotatedKeys, snapshot.RewrappedKeys, snapshot.ReencryptedNotes, snapshot.TotalNotes, id)
+}
+
+// rotate создает новые ключи и перешифровывает ими заметки
+func (m *Manager) rotate(ctx context.Context, id string) error {
+	op := m.update(id, func(*model.KeyRotationOperation) {})
+
+	rotated, rewrapped, err := m.rotator.RotateKeys(ctx, op.OwnerID)
+	m.update(id, func(op *model.KeyRotationOperation) {
+		op.RotatedKeys = int64(rotated)
+		op.RewrappedKeys = int64(rewrapped)
+	})
+	if err != nil {
+		return err
+	}
+
+	return m.rotator.Reencrypt(ctx, op.OwnerID, func(processed, reencrypted, total int64) {
+		m.update(id, func(op *model.KeyRotationOperation) {
+			op.ProcessedNotes = processed
+			op.ReencryptedNotes = reencrypted
+			op.TotalNotes = total
+		})
+	})
+}
+
+// update изменяет операцию под блокировкой и возвращает ее копию
+func (m *Manager) update(id string, fn func(op *model.KeyRotationOperation)) model.KeyRotationOperation {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	op := m.operations[id]
+	fn(op)
+	return *op
+}
+
+// sweep удаляет завершенные операции старше retention. Вызывается под m.mu
+func (m *Manager) sweep() {
+	cutoff := m.now().Add(-retention)
+	for id, op := range m.operations {
+		if op.Done() && op.CompletedAt.Before(cutoff) {
+			delete(m.operations, id)
+		}
+	}
+}
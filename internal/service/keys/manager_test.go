@@ -0,0 +1,97 @@
+package keys
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"notes-service/internal/auth"
+	"notes-service/internal/model"
+)
+
+// fakeRotator сменяет ключи, ожидая release перед перешифрованием
+type fakeRotator struct {
+	release chan struct{}
+	owner   string
+	err     error
+}
+
+func (r *fakeRotator) RotateKeys(_ context.Context, ownerID string) (int, int, error) {
+	r.owner = ownerID
+	return 2, 1, nil
+}
+
+func (r *fakeRotator) Reencrypt(ctx context.Context, _ string, progress func(processed, reencrypted, total int64)) error {
+	select {
+	case <-r.release:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	progress(3, 2, 3)
+	return r.err
+}
+
+var (
+	admin = auth.NewContext(context.Background(), auth.Principal{UserID: "admin", Roles: []string{auth.RoleUser, auth.RoleAdmin}})
+	alice = auth.NewContext(context.Background(), auth.Principal{UserID: "alice", Roles: []string{auth.RoleUser}})
+)
+
+func TestManager_RotatesKeysInBackground(t *testing.T) {
+	rotator := &fakeRotator{release: make(chan struct{})}
+	manager := NewManager(context.Background(), rotator)
+
+	op, err := manager.Start(admin, "alice")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if op.State != model.KeyRotationStateRunning || op.StartedBy != "admin" || op.OwnerID != "alice" {
+		t.Errorf("Expected running operation started by admin, got %+v", op)
+	}
+
+	// Вторая смена ключей не запускается, пока выполняется первая
+	if _, err := manager.Start(admin, ""); !errors.Is(err, ErrRotationInProgress) {
+		t.Errorf("Expected ErrRotationInProgress, got: %v", err)
+	}
+
+	close(rotator.release)
+	manager.Wait()
+
+	got, err := manager.Get(admin, op.ID)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if got.State != model.KeyRotationStateSucceeded || got.CompletedAt.IsZero() {
+		t.Errorf("Expected succeeded operation, got %+v", got)
+	}
+	if got.RotatedKeys != 2 || got.RewrappedKeys != 1 || got.TotalNotes != 3 || got.ProcessedNotes != 3 || got.ReencryptedNotes != 2 {
+		t.Errorf("Expected progress to be recorded, got %+v", got)
+	}
+	if rotator.owner != "alice" {
+		t.Errorf("Expected rotation of alice's key, got %q", rotator.owner)
+	}
+
+	// После завершения можно запустить следующую смену
+	rotator.err = errors.New("storage unavailable")
+	next, err := manager.Start(admin, "")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	manager.Wait()
+	if failed, _ := manager.Get(admin, next.ID); failed.State != model.KeyRotationStateFailed || failed.Err == nil {
+		t.Errorf("Expected failed operation, got %+v", failed)
+	}
+}
+
+func TestManager_AdminOnly(t *testing.T) {
+	manager := NewManager(context.Background(), &fakeRotator{release: make(chan struct{})})
+
+	if _, err := manager.Start(alice, ""); !errors.Is(err, auth.ErrPermissionDenied) {
+		t.Errorf("Expected ErrPermissionDenied, got: %v", err)
+	}
+	if _, err := manager.Get(alice, "missing"); !errors.Is(err, auth.ErrPermissionDenied) {
+		t.Errorf("Expected ErrPermissionDenied, got: %v", err)
+	}
+	if _, err := manager.Get(admin, "missing"); !errors.Is(err, ErrRotationNotFound) {
+		t.Errorf("Expected ErrRotationNotFound, got: %v", err)
+	}
+}
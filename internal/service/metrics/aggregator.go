@@ -0,0 +1,119 @@
+// Package metrics агрегирует значения метрик, загружаемых клиентами, отдельно по названию метрики
+package metrics
+
+import (
+	"errors"
+	"maps"
+	"math"
+	"math/rand/v2"
+	"slices"
+
+	"notes-service/internal/model"
+)
+
+const (
+	// MaxNames количество разных названий метрик в одном агрегаторе
+	MaxNames = 1000
+
+	// maxSamples количество значений метрики, по которым считается p95; дальше значения
+	// попадают в выборку случайно (reservoir sampling), и p95 становится приближенным
+	maxSamples = 10000
+)
+
+// ErrTooManyMetrics возвращается при добавлении значения метрики сверх MaxNames названий
+var ErrTooManyMetrics = errors.New("too many metric names")
+
+// series значения одной метрики
+type series struct {
+	count   int64
+	sum     float64
+	min     float64
+	max     float64
+	samples []float64
+}
+
+// Aggregator накапливает значения метрик по названиям. Не безопасен для конкурентного использования
+type Aggregator struct {
+	series map[string]*series
+	count  int64
+	sum    float64
+}
+
+// NewAggregator создает пустой агрегатор
+func NewAggregator() *Aggregator {
+	return &Aggregator{series: make(map[string]*series)}
+}
+
+// Add добавляет значение value метрики name
+func (a *Aggregator) Add(name string, value float64) error {
+	s, ok := a.series[name]
+	if !ok {
+		if len(a.series) >= MaxNames {
+			return ErrTooManyMetrics
+		}
+		s = &series{min: value, max: value}
+		a.series[name] = s
+	}
+
+	s.count++
+	s.sum += value
+	s.min = math.Min(s.min, value)
+	s.max = math.Max(s.max, value)
+	if len(s.samples) < maxSamples {
+		s.samples = append(s.samples, value)
+	} else if i := rand.Int64N(s.count); i < maxSamples {
+		s.samples[i] = value
+	}
+
+	a.count++
+	a.sum += value
+	return nil
+}
+
+// Count возвращает количество значений всех метрик
+func (a *Aggregator) Count() int64 {
+	return a.count
+}
+
+// Summary возвращает общую статистику и статистику каждой метрики, упорядоченную по названию
+func (a *Aggregator) Summary() model.MetricsSummary {
+	summary := model.MetricsSummary{
+		Count:   a.count,
+		Sum:     a.sum,
+		Metrics: make([]model.MetricSummary, 0, len(a.series)),
+	}
+	if a.count > 0 {
+		summary.Average = a.sum / float64(a.count)
+	}
+	for _, name := range slices.Sorted(maps.Keys(a.series)) {
+		s := a.series[name]
+		summary.Metrics = append(summary.Metrics, model.MetricSummary{
+			Name:    name,
+			Count:   s.count,
+			Sum:     s.sum,
+			Average: s.sum / float64(s.count),
+			Min:     s.min,
+			Max:     s.max,
+			P95:     percentile(s.samples, 0.95),
+		})
+	}
+	return summary
+}
+
+// Reset удаляет накопленные значения
+func (a *Aggregator) Reset() {
+	clear(a.series)
+	a.count = 0
+	a.sum = 0
+}
+
+// percentile возвращает перцентиль p (0..1] значений по ближайшему рангу
+func percentile(values []float64, p float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := slices.Clone(values)
+	slices.Sort(sorted)
+	rank := int(math.Ceil(p * float64(len(sorted))))
+	return sorted[max(rank, 1)-1]
+}
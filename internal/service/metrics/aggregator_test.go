@@ -0,0 +1,59 @@
+package metrics
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestAggregator_Summary(t *testing.T) {
+	aggregator := NewAggregator()
+	for i := 1; i <= 20; i++ {
+		if err := aggregator.Add("latency", float64(i)); err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+	}
+	for _, value := range []float64{-2, 4} {
+		if err := aggregator.Add("", value); err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+	}
+
+	summary := aggregator.Summary()
+	if summary.Count != 22 || summary.Sum != 212 || summary.Average != 212.0/22 {
+		t.Errorf("Unexpected total: %+v", summary)
+	}
+	if len(summary.Metrics) != 2 {
+		t.Fatalf("Expected 2 metrics, got %+v", summary.Metrics)
+	}
+
+	unnamed, latency := summary.Metrics[0], summary.Metrics[1]
+	if unnamed.Name != "" || unnamed.Count != 2 || unnamed.Min != -2 || unnamed.Max != 4 || unnamed.Average != 1 || unnamed.P95 != 4 {
+		t.Errorf("Unexpected unnamed metric: %+v", unnamed)
+	}
+	// Ближайший ранг 95-го перцентиля из 20 значений - 19-е значение
+	if latency.Name != "latency" || latency.Count != 20 || latency.Sum != 210 || latency.Min != 1 || latency.Max != 20 || latency.P95 != 19 {
+		t.Errorf("Unexpected latency metric: %+v", latency)
+	}
+
+	aggregator.Reset()
+	if summary := aggregator.Summary(); summary.Count != 0 || summary.Average != 0 || len(summary.Metrics) != 0 {
+		t.Errorf("Expected empty summary after reset, got %+v", summary)
+	}
+}
+
+func TestAggregator_TooManyMetrics(t *testing.T) {
+	aggregator := NewAggregator()
+	for i := range MaxNames {
+		if err := aggregator.Add(fmt.Sprintf("metric_%d", i), 1); err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+	}
+
+	if err := aggregator.Add("one_more", 1); !errors.Is(err, ErrTooManyMetrics) {
+		t.Errorf("Expected ErrTooManyMetrics, got: %v", err)
+	}
+	if err := aggregator.Add("metric_0", 2); err != nil {
+		t.Errorf("Expected known metric to be accepted, got: %v", err)
+	}
+}
@@ -0,0 +1,102 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"notes-service/internal/auth"
+	"notes-service/internal/model"
+	"notes-service/internal/repository"
+)
+
+// MaxQueryPoints количество значений, которое Query возвращает без агрегации
+const MaxQueryPoints = 10000
+
+// Store сохраняет значения метрик, загруженные пользователями, и строит по ним выборки
+// Значения принадлежат загрузившему пользователю (без аутентификации - общему пустому владельцу)
+type Store struct {
+	repo repository.MetricRepository
+	now  func() time.Time
+}
+
+// StoreOption настраивает Store
+type StoreOption func(*Store)
+
+// WithStoreClock задает источник времени значений без времени (по умолчанию time.Now)
+func WithStoreClock(now func() time.Time) StoreOption {
+	return func(s *Store) {
+		s.now = now
+	}
+}
+
+// NewStore создает хранилище значений метрик поверх repo
+func NewStore(repo repository.MetricRepository, opts ...StoreOption) *Store {
+	s := &Store{repo: repo, now: time.Now}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Record сохраняет значения метрик вызывающего пользователя
+// Значениям без времени назначается текущее время
+func (s *Store) Record(ctx context.Context, points []model.MetricPoint) error {
+	if len(points) == 0 {
+		return nil
+	}
+	ownerID := ownerFromContext(ctx)
+	now := s.now()
+	for i := range points {
+		points[i].OwnerID = ownerID
+		if points[i].Time.IsZero() {
+			points[i].Time = now
+		}
+	}
+	return s.repo.Append(ctx, points)
+}
+
+// Query возвращает значения метрики вызывающего пользователя за период или их агрегат
+// Без агрегации возвращается не более MaxQueryPoints первых значений
+func (s *Store) Query(ctx context.Context, query model.MetricQuery) (model.MetricQueryResult, error) {
+	var result model.MetricQueryResult
+	aggregator := NewAggregator()
+	err := s.repo.ForEach(ctx, ownerFromContext(ctx), query.Name, query.From, query.To, func(point model.MetricPoint) error {
+		if query.Aggregation == model.MetricAggregationNone {
+			if len(result.Points) < MaxQueryPoints {
+				result.Points = append(result.Points, point)
+			} else {
+				result.Truncated = true
+			}
+		}
+		return aggregator.Add(query.Name, point.Value)
+	})
+	if err != nil {
+		return model.MetricQueryResult{}, err
+	}
+
+	result.Count = aggregator.Count()
+	if summary := aggregator.Summary(); len(summary.Metrics) == 1 {
+		metric := summary.Metrics[0]
+		switch query.Aggregation {
+		case model.MetricAggregationCount:
+			result.Value = float64(metric.Count)
+		case model.MetricAggregationSum:
+			result.Value = metric.Sum
+		case model.MetricAggregationAvg:
+			result.Value = metric.Average
+		case model.MetricAggregationMin:
+			result.Value = metric.Min
+		case model.MetricAggregationMax:
+			result.Value = metric.Max
+		case model.MetricAggregationP95:
+			result.Value = metric.P95
+		}
+	}
+	return result, nil
+}
+
+// ownerFromContext возвращает вызывающего пользователя, пусто - запрос без аутентификации
+func ownerFromContext(ctx context.Context) string {
+	principal, _ := auth.FromContext(ctx)
+	return principal.UserID
+}
@@ -0,0 +1,82 @@
+package metrics
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"notes-service/internal/auth"
+	"notes-service/internal/model"
+	"notes-service/internal/repository/memory"
+)
+
+func TestStore_RecordAndQuery(t *testing.T) {
+	now := time.Date(2030, 1, 1, 9, 0, 0, 0, time.UTC)
+	store := NewStore(memory.NewMetricRepository(), WithStoreClock(func() time.Time { return now }))
+	alice := auth.NewContext(context.Background(), auth.Principal{UserID: "alice"})
+	bob := auth.NewContext(context.Background(), auth.Principal{UserID: "bob"})
+
+	// Значения приходят не по порядку, значение без времени получает текущее время
+	err := store.Record(alice, []model.MetricPoint{
+		{Name: "latency", Value: 30, Time: now.Add(-time.Minute)},
+		{Name: "latency", Value: 10, Time: now.Add(-time.Hour)},
+		{Name: "latency", Value: 20},
+		{Name: "errors", Value: 1},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if err := store.Record(bob, []model.MetricPoint{{Name: "latency", Value: 1000}}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	result, err := store.Query(alice, model.MetricQuery{Name: "latency"})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if result.Count != 3 || len(result.Points) != 3 || result.Truncated {
+		t.Fatalf("Expected 3 points of alice, got %+v", result)
+	}
+	for i, want := range []float64{10, 30, 20} {
+		if result.Points[i].Value != want {
+			t.Errorf("Expected points in time order, got %+v", result.Points)
+			break
+		}
+	}
+
+	// Период [from, to): значение в to не входит
+	tests := []struct {
+		aggregation model.MetricAggregation
+		want        float64
+	}{
+		{model.MetricAggregationCount, 2},
+		{model.MetricAggregationSum, 40},
+		{model.MetricAggregationAvg, 20},
+		{model.MetricAggregationMin, 10},
+		{model.MetricAggregationMax, 30},
+		{model.MetricAggregationP95, 30},
+	}
+	for _, tt := range tests {
+		result, err := store.Query(alice, model.MetricQuery{
+			Name:        "latency",
+			From:        now.Add(-time.Hour),
+			To:          now,
+			Aggregation: tt.aggregation,
+		})
+		if err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+		if result.Value != tt.want || result.Count != 2 || len(result.Points) != 0 {
+			t.Errorf("Aggregation %d: expected value %v of 2 points, got %+v", tt.aggregation, tt.want, result)
+		}
+	}
+
+	// Пустой период
+	result, err = store.Query(bob, model.MetricQuery{Name: "errors", Aggregation: model.MetricAggregationMax})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if result.Count != 0 || result.Value != 0 {
+		t.Errorf("Expected no values of bob, got %+v", result)
+	}
+}
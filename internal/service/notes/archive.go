@@ -0,0 +1,60 @@
+package notes
+
+import (
+	"context"
+	"errors"
+
+	"notes-service/internal/model"
+	"notes-service/internal/telemetry"
+)
+
+// Archive перемещает заметку в архив: архивные заметки не выводятся в List без IncludeArchived
+func (s *service) Archive(ctx context.Context, id string) (_ model.Note, err error) {
+	ctx, span := startSpan(ctx, "Archive", noteIDAttribute(id))
+	defer func() { telemetry.EndSpan(span, err) }()
+	return s.setArchived(ctx, id, true)
+}
+
+// Unarchive возвращает заметку из архива
+func (s *service) Unarchive(ctx context.Context, id string) (_ model.Note, err error) {
+	ctx, span := startSpan(ctx, "Unarchive", noteIDAttribute(id))
+	defer func() { telemetry.EndSpan(span, err) }()
+	return s.setArchived(ctx, id, false)
+}
+
+// setArchived перемещает заметку владельца в архив или возвращает из него
+// Архивирование публикует EventNoteArchived, возврат из архива - EventNoteUpdated
+// Как и закрепление, архивирование не меняет содержимое, не создает ревизию
+// и для защищенной заметки требует парольную фразу
+func (s *service) setArchived(ctx context.Context, id string, archived bool) (model.Note, error) {
+	ctx = ownerScope(ctx)
+	if id == "" {
+		return model.Note{}, errors.New("id cannot be empty")
+	}
+
+	note, err := s.noteRepository.GetByID(ctx, id)
+	if err != nil {
+		return model.Note{}, accessError(ctx, s.noteRepository, id, err)
+	}
+	if err := s.checkPassphrase(ctx, note, model.AuditActionArchive); err != nil {
+		return model.Note{}, err
+	}
+	if err := s.checkLock(ctx, id); err != nil {
+		return model.Note{}, err
+	}
+	if note.Archived == archived {
+		return RedactNote(note), nil
+	}
+
+	note.Archived = archived
+	note, err = s.noteRepository.Update(ctx, note)
+	if err != nil {
+		return model.Note{}, err
+	}
+	eventType := EventNoteUpdated
+	if archived {
+		eventType = EventNoteArchived
+	}
+	s.eventService.Publish(Event{Type: eventType, Note: note})
+	return RedactNote(note), nil
+}
@@ -0,0 +1,67 @@
+package notes
+
+import (
+	"context"
+	"testing"
+
+	"notes-service/internal/repository/memory"
+	svc "notes-service/internal/service"
+)
+
+func TestNoteService_ArchivedNotesHiddenFromList(t *testing.T) {
+	ctx := context.Background()
+	service := NewNoteService(memory.NewRepository())
+
+	var ids []string
+	for _, title := range []string{"Alpha", "Bravo"} {
+		note, err := service.Create(ctx, svc.CreateNoteInput{Title: title})
+		if err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+		ids = append(ids, note.ID)
+	}
+
+	archived, err := service.Archive(ctx, ids[0])
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if !archived.Archived {
+		t.Error("Expected note to be archived")
+	}
+
+	notes, err := service.List(ctx, svc.ListOptions{})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(notes) != 1 || notes[0].ID != ids[1] {
+		t.Errorf("Expected only the active note in the list, got %d notes", len(notes))
+	}
+
+	notes, err = service.List(ctx, svc.ListOptions{IncludeArchived: true})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(notes) != 2 {
+		t.Errorf("Expected archived note with IncludeArchived, got %d notes", len(notes))
+	}
+
+	// Архивная заметка по-прежнему доступна по ID
+	if note, err := service.Get(ctx, ids[0]); err != nil || !note.Archived {
+		t.Errorf("Expected archived note by ID, got %+v, %v", note, err)
+	}
+
+	restored, err := service.Unarchive(ctx, ids[0])
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if restored.Archived {
+		t.Error("Expected note to be restored from the archive")
+	}
+	if notes, _ := service.List(ctx, svc.ListOptions{}); len(notes) != 2 {
+		t.Errorf("Expected restored note in the list, got %d notes", len(notes))
+	}
+
+	if _, err := service.Archive(ctx, ""); err == nil {
+		t.Error("Expected error for empty id")
+	}
+}
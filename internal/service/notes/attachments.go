@@ -0,0 +1,131 @@
+package notes
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"notes-service/internal/model"
+	"notes-service/internal/repository"
+	svc "notes-service/internal/service"
+)
+
+// ErrAttachmentTooLarge возвращается, когда загружаемое вложение превышает допустимый размер
+var ErrAttachmentTooLarge = errors.New("attachment exceeds maximum size")
+
+// DefaultMaxAttachmentSize максимальный размер вложения по умолчанию (10 МБ)
+const DefaultMaxAttachmentSize = 10 << 20
+
+var _ svc.AttachmentService = (*attachmentService)(nil)
+
+type attachmentService struct {
+	noteRepository       repository.NoteRepository
+	attachmentRepository repository.AttachmentRepository
+	maxSize              int64
+}
+
+// NewAttachmentService создает сервис вложений заметок
+// maxSize ограничивает размер одного вложения в байтах (0 - DefaultMaxAttachmentSize)
+func NewAttachmentService(
+	noteRepository repository.NoteRepository,
+	attachmentRepository repository.AttachmentRepository,
+	maxSize int64,
+) svc.AttachmentService {
+	if maxSize <= 0 {
+		maxSize = DefaultMaxAttachmentSize
+	}
+
+	return &attachmentService{
+		noteRepository:       noteRepository,
+		attachmentRepository: attachmentRepository,
+		maxSize:              maxSize,
+	}
+}
+
+// Upload сохраняет содержимое data как вложение заметки
+// Поток сначала записывается во временный файл: так до сохранения известны размер
+// и контрольная сумма, а превышение лимита не оставляет в хранилище частичных файлов
+func (s *attachmentService) Upload(ctx context.Context, input svc.UploadAttachmentInput, data io.Reader) (model.Attachment, error) {
+	ctx = ownerScope(ctx)
+	if input.NoteID == "" {
+		return model.Attachment{}, errors.New("note id cannot be empty")
+	}
+
+	// Сохраняем только имя файла без пути клиента
+	fileName := filepath.Base(strings.TrimSpace(input.FileName))
+	if fileName == "." || fileName == string(filepath.Separator) {
+		return model.Attachment{}, errors.New("file name cannot be empty")
+	}
+
+	// Вложение можно прикрепить только к существующей заметке
+	if _, err := s.noteRepository.GetByID(ctx, input.NoteID); err != nil {
+		return model.Attachment{}, accessError(ctx, s.noteRepository, input.NoteID, err)
+	}
+
+	spool, err := os.CreateTemp("", "attachment-*")
+	if err != nil {
+		return model.Attachment{}, fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	defer func() {
+		spool.Close()
+		os.Remove(spool.Name())
+	}()
+
+	hash := sha256.New()
+	size, err := io.Copy(io.MultiWriter(spool, hash), io.LimitReader(data, s.maxSize+1))
+	if err != nil {
+		return model.Attachment{}, err
+	}
+	if size > s.maxSize {
+		return model.Attachment{}, fmt.Errorf("%w: limit is %d bytes", ErrAttachmentTooLarge, s.maxSize)
+	}
+
+	if _, err := spool.Seek(0, io.SeekStart); err != nil {
+		return model.Attachment{}, err
+	}
+
+	contentType := input.ContentType
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	attachment := model.Attachment{
+		ID:          uuid.New().String(),
+		NoteID:      input.NoteID,
+		FileName:    fileName,
+		ContentType: contentType,
+		Size:        size,
+		SHA256:      hex.EncodeToString(hash.Sum(nil)),
+		CreatedAt:   time.Now(),
+	}
+
+	if err := s.attachmentRepository.Save(ctx, attachment, spool); err != nil {
+		return model.Attachment{}, err
+	}
+
+	return attachment, nil
+}
+
+// Download возвращает метаданные вложения и поток его содержимого
+func (s *attachmentService) Download(ctx context.Context, noteID, id string) (model.Attachment, io.ReadCloser, error) {
+	ctx = ownerScope(ctx)
+	if noteID == "" || id == "" {
+		return model.Attachment{}, nil, errors.New("id cannot be empty")
+	}
+
+	// Вложения доступны только владельцу заметки
+	if _, err := s.noteRepository.GetByID(ctx, noteID); err != nil {
+		return model.Attachment{}, nil, accessError(ctx, s.noteRepository, noteID, err)
+	}
+
+	return s.attachmentRepository.Open(ctx, noteID, id)
+}
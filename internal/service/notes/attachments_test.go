@@ -0,0 +1,113 @@
+package notes
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"notes-service/internal/repository"
+	"notes-service/internal/repository/attachments"
+	"notes-service/internal/repository/memory"
+	svc "notes-service/internal/service"
+)
+
+func newAttachmentTestServices(t *testing.T, maxSize int64) (svc.NoteService, svc.AttachmentService, repository.AttachmentRepository) {
+	t.Helper()
+
+	attachmentRepo, err := attachments.NewFilesystemRepository(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to create attachment repository: %v", err)
+	}
+
+	noteRepo := memory.NewRepository()
+	noteService := NewNoteService(noteRepo, WithAttachmentRepository(attachmentRepo))
+
+	return noteService, NewAttachmentService(noteRepo, attachmentRepo, maxSize), attachmentRepo
+}
+
+func TestAttachmentService_UploadAndDownload(t *testing.T) {
+	ctx := context.Background()
+	noteService, attachmentService, attachmentRepo := newAttachmentTestServices(t, 0)
+
+	note, err := noteService.Create(ctx, svc.CreateNoteInput{Title: "With attachment"})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	content := bytes.Repeat([]byte("chunk"), 1000)
+	attachment, err := attachmentService.Upload(ctx, svc.UploadAttachmentInput{
+		NoteID:   note.ID,
+		FileName: "../../etc/report.txt",
+	}, bytes.NewReader(content))
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	sum := sha256.Sum256(content)
+	if attachment.Size != int64(len(content)) || attachment.SHA256 != hex.EncodeToString(sum[:]) {
+		t.Errorf("Unexpected size or checksum: %+v", attachment)
+	}
+	if attachment.FileName != "report.txt" {
+		t.Errorf("Expected client path to be stripped, got %q", attachment.FileName)
+	}
+	if attachment.ContentType != "application/octet-stream" {
+		t.Errorf("Expected default content type, got %q", attachment.ContentType)
+	}
+
+	_, data, err := attachmentService.Download(ctx, note.ID, attachment.ID)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	downloaded, _ := io.ReadAll(data)
+	data.Close()
+
+	if !bytes.Equal(downloaded, content) {
+		t.Error("Expected downloaded content to match uploaded content")
+	}
+
+	// Удаление заметки удаляет и её вложения
+	if err := noteService.Delete(ctx, note.ID); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if _, _, err := attachmentService.Download(ctx, note.ID, attachment.ID); !errors.Is(err, memory.ErrNoteNotFound) {
+		t.Errorf("Expected ErrNoteNotFound after note deletion, got: %v", err)
+	}
+	if _, _, err := attachmentRepo.Open(ctx, note.ID, attachment.ID); !errors.Is(err, attachments.ErrAttachmentNotFound) {
+		t.Errorf("Expected ErrAttachmentNotFound after note deletion, got: %v", err)
+	}
+}
+
+func TestAttachmentService_UploadTooLarge(t *testing.T) {
+	ctx := context.Background()
+	noteService, attachmentService, _ := newAttachmentTestServices(t, 10)
+
+	note, err := noteService.Create(ctx, svc.CreateNoteInput{Title: "Small limit"})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	_, err = attachmentService.Upload(ctx, svc.UploadAttachmentInput{
+		NoteID:   note.ID,
+		FileName: "big.bin",
+	}, strings.NewReader("more than ten bytes"))
+	if !errors.Is(err, ErrAttachmentTooLarge) {
+		t.Errorf("Expected ErrAttachmentTooLarge, got: %v", err)
+	}
+}
+
+func TestAttachmentService_UploadToMissingNote(t *testing.T) {
+	_, attachmentService, _ := newAttachmentTestServices(t, 0)
+
+	_, err := attachmentService.Upload(context.Background(), svc.UploadAttachmentInput{
+		NoteID:   "missing",
+		FileName: "file.txt",
+	}, strings.NewReader("data"))
+	if !errors.Is(err, memory.ErrNoteNotFound) {
+		t.Errorf("Expected ErrNoteNotFound, got: %v", err)
+	}
+}
@@ -0,0 +1,154 @@
+package notes
+
+import (
+	"context"
+	"errors"
+
+	"notes-service/internal/model"
+	"notes-service/internal/repository"
+	"notes-service/internal/telemetry"
+)
+
+// ErrAtomicBatchNotSupported возвращается, когда атомарный пакетный запрос не поддерживается хранилищем
+var ErrAtomicBatchNotSupported = errors.New("atomic batch operations are not supported by the repository")
+
+// BatchCreate создает несколько заметок
+// При atomic = true заметки создаются все или ни одной: ошибка валидации любой заметки
+// отклоняет весь пакет, а сохранение выполняется одной операцией хранилища
+func (s *service) BatchCreate(ctx context.Context, notes []model.Note, atomic bool) (_ []model.BatchResult, err error) {
+	ctx, span := startSpan(ctx, "BatchCreate")
+	defer func() { telemetry.EndSpan(span, err) }()
+	ctx = ownerScope(ctx)
+	results := make([]model.BatchResult, len(notes))
+	prepared := make([]model.Note, 0, len(notes))
+	for i, input := range notes {
+		note, err := newNote(s.now(), input)
+		if err != nil {
+			if atomic {
+				return nil, err
+			}
+			results[i].Err = err
+			continue
+		}
+		prepared = append(prepared, note)
+	}
+
+	remaining, err := s.remainingQuota(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if atomic {
+		if remaining >= 0 && len(prepared) > remaining {
+			return nil, quotaError(ctx)
+		}
+
+		batchRepo, ok := s.noteRepository.(repository.BatchNoteRepository)
+		if !ok {
+			return nil, ErrAtomicBatchNotSupported
+		}
+
+		created, err := batchRepo.CreateBatch(ctx, prepared)
+		if err != nil {
+			return nil, err
+		}
+
+		for i, note := range created {
+			results[i] = model.BatchResult{ID: note.ID, Note: note, Err: s.afterCreate(ctx, note)}
+		}
+		s.checkSoftQuota(ctx, remaining, created)
+
+		return results, nil
+	}
+
+	// Неатомарный режим: каждая заметка создается независимо, ошибки фиксируются по элементам
+	quotaBefore := remaining
+	var created []model.Note
+	next := 0
+	for i := range results {
+		if results[i].Err != nil {
+			continue
+		}
+
+		note := prepared[next]
+		next++
+
+		// Заметки сверх квоты тенанта не создаются
+		if remaining == 0 {
+			results[i].Err = quotaError(ctx)
+			continue
+		}
+		if remaining > 0 {
+			remaining--
+		}
+
+		createdNote, err := s.noteRepository.Create(ctx, note)
+		if err == nil {
+			created = append(created, createdNote)
+			err = s.afterCreate(ctx, createdNote)
+		}
+		results[i] = model.BatchResult{ID: createdNote.ID, Note: createdNote, Err: err}
+	}
+	s.checkSoftQuota(ctx, quotaBefore, created)
+
+	return results, nil
+}
+
+// BatchGet возвращает несколько заметок, для отсутствующих заметок фиксируется ошибка элемента
+// Защищенные заметки возвращаются без содержимого, как в List
+func (s *service) BatchGet(ctx context.Context, ids []string) (_ []model.BatchResult, err error) {
+	ctx, span := startSpan(ctx, "BatchGet")
+	defer func() { telemetry.EndSpan(span, err) }()
+	results := make([]model.BatchResult, len(ids))
+	for i, id := range ids {
+		note, err := s.get(ctx, id)
+		results[i] = model.BatchResult{ID: id, Note: redactProtected([]model.Note{note})[0], Err: err}
+	}
+
+	return results, nil
+}
+
+// BatchDelete удаляет несколько заметок
+// При atomic = true удаление выполняется одной операцией хранилища: если любая заметка
+// не найдена или защищена, а фраза не подходит, не удаляется ни одна
+func (s *service) BatchDelete(ctx context.Context, ids []string, atomic bool) (_ []model.BatchResult, err error) {
+	ctx, span := startSpan(ctx, "BatchDelete")
+	defer func() { telemetry.EndSpan(span, err) }()
+	ctx = ownerScope(ctx)
+	results := make([]model.BatchResult, len(ids))
+
+	if atomic {
+		for _, id := range ids {
+			if id == "" {
+				return nil, errors.New("id cannot be empty")
+			}
+		}
+
+		batchRepo, ok := s.noteRepository.(repository.BatchNoteRepository)
+		if !ok {
+			return nil, ErrAtomicBatchNotSupported
+		}
+
+		for _, id := range ids {
+			if err := s.checkDelete(ctx, id); err != nil {
+				return nil, err
+			}
+		}
+
+		if err := batchRepo.DeleteBatch(ctx, ids); err != nil {
+			return nil, err
+		}
+
+		for i, id := range ids {
+			results[i] = model.BatchResult{ID: id, Err: s.afterDelete(ctx, id)}
+		}
+
+		return results, nil
+	}
+
+	for i, id := range ids {
+		results[i] = model.BatchResult{ID: id, Err: s.Delete(ctx, id)}
+	}
+
+	return results, nil
+}
@@ -0,0 +1,93 @@
+package notes
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"notes-service/internal/model"
+	"notes-service/internal/repository/memory"
+	svc "notes-service/internal/service"
+)
+
+func TestNoteService_CreateE2E_StoresCiphertextOpaque(t *testing.T) {
+	ctx := context.Background()
+	service := NewNoteService(memory.NewRepository())
+	ciphertext := []byte{0x00, 0xff, ' ', 0x10, ' '}
+
+	note, err := service.Create(ctx, svc.CreateNoteInput{
+		Title:            "Secret",
+		Tags:             []string{"private"},
+		IsE2E:            true,
+		E2EScheme:        model.E2ESchemeXChaCha20Poly1305,
+		ContentEncrypted: ciphertext,
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if !bytes.Equal(note.ContentEncrypted, ciphertext) {
+		t.Errorf("Expected ciphertext to be stored as is, got %v", note.ContentEncrypted)
+	}
+
+	// Зашифрованные заметки не попадают в индекс тегов
+	tagged, err := service.ListByTag(ctx, "private")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(tagged) != 0 {
+		t.Errorf("Expected e2e note to be excluded from tag index, got %d notes", len(tagged))
+	}
+
+	// Обновление шифротекста создает новую версию
+	updated, err := service.Update(ctx, svc.UpdateNoteInput{
+		ID:               note.ID,
+		ContentEncrypted: []byte("new ciphertext"),
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if updated.Version != note.Version+1 {
+		t.Errorf("Expected version %d, got %d", note.Version+1, updated.Version)
+	}
+
+	// Открытый текст в e2e заметке отклоняется
+	if _, err := service.Update(ctx, svc.UpdateNoteInput{ID: note.ID, Content: "plaintext"}); err == nil {
+		t.Error("Expected error when setting plaintext content on e2e note")
+	}
+}
+
+func TestNoteService_CreateE2E_Validation(t *testing.T) {
+	tests := []struct {
+		name  string
+		input svc.CreateNoteInput
+	}{
+		{
+			name:  "unsupported scheme",
+			input: svc.CreateNoteInput{Title: "Secret", IsE2E: true, E2EScheme: "rot13", ContentEncrypted: []byte("x")},
+		},
+		{
+			name:  "missing ciphertext",
+			input: svc.CreateNoteInput{Title: "Secret", IsE2E: true, E2EScheme: model.E2ESchemeAES256GCM},
+		},
+		{
+			name: "plaintext content",
+			input: svc.CreateNoteInput{
+				Title: "Secret", Content: "plaintext", IsE2E: true,
+				E2EScheme: model.E2ESchemeAES256GCM, ContentEncrypted: []byte("x"),
+			},
+		},
+		{
+			name:  "ciphertext without e2e flag",
+			input: svc.CreateNoteInput{Title: "Plain", ContentEncrypted: []byte("x")},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := NewNoteService(memory.NewRepository())
+			if _, err := service.Create(context.Background(), tt.input); err == nil {
+				t.Error("Expected validation error, got nil")
+			}
+		})
+	}
+}
@@ -1,54 +1,264 @@
 package notes
 
 import (
+	"errors"
+	"fmt"
 	"sync"
+	"time"
 
 	"notes-service/internal/model"
 )
 
-// EventService управляет подписчиками на события создания заметок
+const (
+	// DefaultEventLogSize количество последних событий, доступных для повторной доставки
+	DefaultEventLogSize = 1000
+
+	// subscriberBuffer размер очереди событий подписчика
+	subscriberBuffer = 64
+)
+
+// ErrEventLogTruncated возвращается, когда запрошенные для повторной доставки события
+// уже вытеснены из журнала или не могли в него попасть (журнал хранится в памяти
+// и начинается заново после перезапуска сервера)
+var ErrEventLogTruncated = errors.New("requested events are no longer in the event log")
+
+// EventType тип события заметки
+type EventType int
+
+const (
+	EventNoteCreated        EventType = iota // Создана новая заметка
+	EventNoteReminderDue                     // Наступило время напоминания заметки (Note.RemindAt)
+	EventExportCompleted                     // Завершилась выгрузка заметок в хранилище (Export)
+	EventNoteUpdated                         // Изменена заметка, в том числе закреплена или откреплена
+	EventNoteDeleted                         // Удалена заметка (в Note заполнены только ID и OwnerID)
+	EventNoteShared                          // Владелец открыл доступ к заметке (Share)
+	EventSavedSearchMatched                  // Заметка начала подходить под сохраненный поиск владельца (Search)
+	EventQuotaWarning                        // Использование квоты заметок пересекло мягкий порог (Quota)
+	EventNoteArchived                        // Заметка перемещена в архив (возврат из архива - EventNoteUpdated)
+)
+
+// String возвращает имя типа события (например, "note_created")
+func (t EventType) String() string {
+	switch t {
+	case EventNoteCreated:
+		return "note_created"
+	case EventNoteReminderDue:
+		return "note_reminder_due"
+	case EventExportCompleted:
+		return "export_completed"
+	case EventNoteUpdated:
+		return "note_updated"
+	case EventNoteDeleted:
+		return "note_deleted"
+	case EventNoteShared:
+		return "note_shared"
+	case EventSavedSearchMatched:
+		return "saved_search_matched"
+	case EventQuotaWarning:
+		return "quota_warning"
+	case EventNoteArchived:
+		return "note_archived"
+	default:
+		return fmt.Sprintf("event_type_%d", int(t))
+	}
+}
+
+// Event событие заметки, доставляемое подписчикам EventService
+type Event struct {
+	ID     uint64    // Порядковый номер события в журнале, назначается при публикации
+	Time   time.Time // Время публикации
+	Type   EventType
+	Note   model.Note
+	Export model.ExportOperation // Для EventExportCompleted
+	Share  model.Share           // Для EventNoteShared
+	Search model.SavedSearch     // Для EventSavedSearchMatched
+	Quota  model.QuotaUsage      // Для EventQuotaWarning
+
+	// Previous - заметка до изменения для EventNoteUpdated (пустая, если изменилось только закрепление)
+	// Не пересылается другим репликам: нужна только при публикации (см. searches.Service.Bus)
+	Previous model.Note
+}
+
+// OwnerID возвращает пользователя, которому адресовано событие
+func (e Event) OwnerID() string {
+	if e.Type == EventExportCompleted {
+		return e.Export.OwnerID
+	}
+	return e.Note.OwnerID
+}
+
+// AddressedTo сообщает, должен ли пользователь userID получить событие:
+// события получает владелец, а о предоставлении доступа - еще и получивший доступ
+func (e Event) AddressedTo(userID string) bool {
+	if e.Type == EventNoteShared && e.Share.UserID == userID {
+		return true
+	}
+	return e.OwnerID() == userID
+}
+
+// EventBus публикует события заметок и доставляет их подписчикам SubscribeToEvents
+// EventService доставляет события в пределах процесса, nats.Bus и redis.Bus - всем репликам сервера
+type EventBus interface {
+	Publish(event Event)
+	Subscribe() chan Event
+	SubscribeAfter(afterID uint64) (chan Event, []Event, error)
+	SubscribeSince(since time.Time) (chan Event, []Event, error)
+	Unsubscribe(ch chan Event)
+}
+
+var _ EventBus = (*EventService)(nil)
+
+// EventService хранит журнал последних событий заметок и доставляет события подписчикам
+// Журнал - кольцевой буфер в памяти: переподключившийся клиент получает пропущенные
+// события (SubscribeAfter, SubscribeSince), пока они не вытеснены более новыми
 type EventService struct {
-	subscribers map[chan model.Note]bool
-	mu          sync.RWMutex
+	mu          sync.Mutex
+	subscribers map[chan Event]bool
+	now         func() time.Time
+
+	log      []Event   // Кольцевой буфер, log[head] - самое старое событие
+	head     int       // Позиция самого старого события
+	count    int       // Количество событий в журнале
+	lastID   uint64    // ID последнего опубликованного события
+	evicted  uint64    // ID последнего вытесненного события
+	complete time.Time // Журнал содержит все события, опубликованные после этого времени
+}
+
+// EventOption настраивает EventService
+type EventOption func(*EventService)
+
+// WithEventLogSize задает количество событий в журнале (по умолчанию DefaultEventLogSize)
+func WithEventLogSize(size int) EventOption {
+	return func(s *EventService) {
+		if size > 0 {
+			s.log = make([]Event, size)
+		}
+	}
+}
+
+// WithEventClock задает источник времени публикации событий (по умолчанию time.Now)
+func WithEventClock(now func() time.Time) EventOption {
+	return func(s *EventService) {
+		s.now = now
+	}
 }
 
 // NewEventService создает новый экземпляр EventService
-func NewEventService() *EventService {
-	return &EventService{
-		subscribers: make(map[chan model.Note]bool),
+func NewEventService(opts ...EventOption) *EventService {
+	s := &EventService{
+		subscribers: make(map[chan Event]bool),
+		now:         time.Now,
+		log:         make([]Event, DefaultEventLogSize),
+	}
+	for _, opt := range opts {
+		opt(s)
 	}
+	s.complete = s.now()
+	return s
 }
 
 // Subscribe добавляет нового подписчика и возвращает канал для получения событий
-func (s *EventService) Subscribe() chan model.Note {
-	ch := make(chan model.Note, 10) // Буферизованный канал для защиты от backpressure
+// Канал закрывается при Unsubscribe, а также если подписчик не успевает забирать события:
+// тогда пропущенные события можно получить повторной подпиской через SubscribeAfter
+func (s *EventService) Subscribe() chan Event {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.subscribe()
+}
+
+// SubscribeAfter подписывает на события и возвращает события журнала с ID больше afterID
+// Если часть этих событий уже вытеснена или afterID выдан до перезапуска сервера,
+// возвращается ErrEventLogTruncated
+func (s *EventService) SubscribeAfter(afterID uint64) (chan Event, []Event, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
+
+	if afterID < s.evicted || afterID > s.lastID {
+		return nil, nil, fmt.Errorf("%w: event %d is outside of the log (events %d-%d)",
+			ErrEventLogTruncated, afterID, s.evicted+1, s.lastID)
+	}
+	return s.subscribe(), s.replay(func(e Event) bool { return e.ID > afterID }), nil
+}
+
+// SubscribeSince подписывает на события и возвращает события журнала, опубликованные после since
+// Если журнал не содержит всех событий с момента since, возвращается ErrEventLogTruncated
+func (s *EventService) SubscribeSince(since time.Time) (chan Event, []Event, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if since.Before(s.complete) {
+		return nil, nil, fmt.Errorf("%w: the log is complete only since %s",
+			ErrEventLogTruncated, s.complete.UTC().Format(time.RFC3339Nano))
+	}
+	return s.subscribe(), s.replay(func(e Event) bool { return e.Time.After(since) }), nil
+}
+
+// subscribe регистрирует подписчика. Вызывается под s.mu
+func (s *EventService) subscribe() chan Event {
+	ch := make(chan Event, subscriberBuffer)
 	s.subscribers[ch] = true
 	return ch
 }
 
+// replay возвращает события журнала, подходящие под match, от старых к новым. Вызывается под s.mu
+func (s *EventService) replay(match func(Event) bool) []Event {
+	var events []Event
+	for i := range s.count {
+		if event := s.log[(s.head+i)%len(s.log)]; match(event) {
+			events = append(events, event)
+		}
+	}
+	return events
+}
+
 // Unsubscribe удаляет подписчика и закрывает его канал
-func (s *EventService) Unsubscribe(ch chan model.Note) {
+func (s *EventService) Unsubscribe(ch chan Event) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
+	s.unsubscribe(ch)
+}
+
+// unsubscribe удаляет подписчика. Вызывается под s.mu
+func (s *EventService) unsubscribe(ch chan Event) {
 	if _, ok := s.subscribers[ch]; ok {
 		close(ch)
 		delete(s.subscribers, ch)
 	}
 }
 
-// Publish отправляет событие всем подписчикам
-// Если канал подписчика переполнен, событие пропускается (защита от backpressure)
-func (s *EventService) Publish(note model.Note) {
-	s.mu.RLock()
-	defer s.mu.RUnlock()
+// Publish записывает событие в журнал и отправляет его всем подписчикам
+// Подписчик, очередь которого переполнена, отключается (канал закрывается), чтобы
+// не блокировать остальных и не терять события незаметно для клиента
+func (s *EventService) Publish(event Event) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.lastID++
+	event.ID = s.lastID
+	event.Time = s.now()
+	s.append(event)
+
 	for ch := range s.subscribers {
 		select {
-		case ch <- note:
+		case ch <- event:
 			// Событие успешно отправлено
 		default:
-			// Канал переполнен, пропускаем (защита от backpressure)
+			s.unsubscribe(ch)
 		}
 	}
 }
+
+// append добавляет событие в журнал, вытесняя самое старое. Вызывается под s.mu
+func (s *EventService) append(event Event) {
+	if s.count < len(s.log) {
+		s.log[(s.head+s.count)%len(s.log)] = event
+		s.count++
+		return
+	}
+
+	oldest := s.log[s.head]
+	s.evicted = oldest.ID
+	s.complete = oldest.Time
+	s.log[s.head] = event
+	s.head = (s.head + 1) % len(s.log)
+}
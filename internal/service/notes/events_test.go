@@ -0,0 +1,151 @@
+package notes
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"notes-service/internal/auth"
+	"notes-service/internal/model"
+	"notes-service/internal/repository/memory"
+	svc "notes-service/internal/service"
+)
+
+func TestNoteService_PublishesLifecycleEvents(t *testing.T) {
+	events := NewEventService()
+	service := NewNoteService(memory.NewRepository(), WithEventService(events))
+	alice := auth.NewContext(context.Background(), auth.Principal{UserID: "alice"})
+
+	ch := events.Subscribe()
+	defer events.Unsubscribe(ch)
+
+	note, err := service.Create(alice, svc.CreateNoteInput{Title: "Note", Content: "Hello world"})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if _, err := service.Update(alice, svc.UpdateNoteInput{ID: note.ID, Content: "Hello again"}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	// Обновление без изменений не записывается и не публикует событие
+	if _, err := service.Update(alice, svc.UpdateNoteInput{ID: note.ID, Content: "Hello again"}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if _, err := service.Pin(alice, note.ID); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if _, err := service.Share(alice, note.ID, "bob", model.SharePermissionRead); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if _, err := service.Archive(alice, note.ID); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	// Повторное архивирование ничего не меняет и не публикует событие
+	if _, err := service.Archive(alice, note.ID); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if _, err := service.Unarchive(alice, note.ID); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if err := service.Delete(alice, note.ID); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	want := []EventType{EventNoteCreated, EventNoteUpdated, EventNoteUpdated, EventNoteShared,
+		EventNoteArchived, EventNoteUpdated, EventNoteDeleted}
+	for i, wantType := range want {
+		event := <-ch
+		if event.Type != wantType || event.Note.ID != note.ID || event.OwnerID() != "alice" {
+			t.Fatalf("Event %d: expected type %d for alice's note, got type %d note %q owner %q",
+				i, wantType, event.Type, event.Note.ID, event.OwnerID())
+		}
+
+		switch event.Type {
+		case EventNoteUpdated:
+			if i == 2 && !event.Note.Pinned {
+				t.Error("Expected pin event to carry the pinned note")
+			}
+			if i == 5 && event.Note.Archived {
+				t.Error("Expected unarchive event to carry the restored note")
+			}
+		case EventNoteArchived:
+			if !event.Note.Archived {
+				t.Error("Expected archive event to carry the archived note")
+			}
+		case EventNoteShared:
+			// О доступе узнают владелец и получатель, но не посторонние
+			if !event.AddressedTo("alice") || !event.AddressedTo("bob") || event.AddressedTo("carol") {
+				t.Errorf("Expected share event addressed to alice and bob only, got share %+v", event.Share)
+			}
+		}
+	}
+	select {
+	case event := <-ch:
+		t.Errorf("Expected no more events, got type %d", event.Type)
+	default:
+	}
+}
+
+func TestEventService_ReplayFromLog(t *testing.T) {
+	events := NewEventService(WithEventLogSize(3))
+	now := time.Now()
+	events.now = func() time.Time { return now }
+	for i := range 5 {
+		now = now.Add(time.Second)
+		events.Publish(Event{Type: EventNoteCreated, Note: model.Note{ID: strconv.Itoa(i + 1)}})
+	}
+
+	// В журнале остались события 3-5
+	ch, missed, err := events.SubscribeAfter(3)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	defer events.Unsubscribe(ch)
+	if len(missed) != 2 || missed[0].ID != 4 || missed[1].ID != 5 {
+		t.Fatalf("Expected events 4 and 5, got %+v", missed)
+	}
+
+	// Новые события после подписки приходят в канал без пропусков и повторов
+	events.Publish(Event{Type: EventNoteDeleted, Note: model.Note{ID: "6"}})
+	if event := <-ch; event.ID != 6 || event.Note.ID != "6" {
+		t.Errorf("Expected live event 6, got %+v", event)
+	}
+
+	_, missed, err = events.SubscribeSince(now.Add(-time.Second))
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(missed) != 2 || missed[0].ID != 5 {
+		t.Errorf("Expected events 5 and 6 since timestamp, got %+v", missed)
+	}
+
+	// Вытесненные события и номера из будущего (журнал после перезапуска) недоступны
+	for _, afterID := range []uint64{0, 2, 7} {
+		if _, _, err := events.SubscribeAfter(afterID); !errors.Is(err, ErrEventLogTruncated) {
+			t.Errorf("Expected ErrEventLogTruncated after event %d, got: %v", afterID, err)
+		}
+	}
+	if _, _, err := events.SubscribeSince(now.Add(-time.Hour)); !errors.Is(err, ErrEventLogTruncated) {
+		t.Errorf("Expected ErrEventLogTruncated for evicted timestamp, got: %v", err)
+	}
+}
+
+func TestEventService_DisconnectsLaggingSubscriber(t *testing.T) {
+	events := NewEventService()
+	ch := events.Subscribe()
+
+	for range subscriberBuffer + 1 {
+		events.Publish(Event{Type: EventNoteCreated})
+	}
+
+	// Очередь сохраняет доставленные события, затем канал закрывается
+	received := 0
+	for range ch {
+		received++
+	}
+	if received != subscriberBuffer {
+		t.Errorf("Expected %d queued events before disconnect, got %d", subscriberBuffer, received)
+	}
+	events.Unsubscribe(ch) // Повторная отписка не паникует
+}
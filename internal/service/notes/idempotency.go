@@ -0,0 +1,134 @@
+package notes
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"notes-service/internal/model"
+	"notes-service/internal/repository"
+)
+
+// DefaultIdempotencyTTL время, в течение которого повтор CreateNote с тем же ключом
+// возвращает исходную заметку
+const DefaultIdempotencyTTL = 24 * time.Hour
+
+// ErrIdempotencyKeyReused возвращается, если ключ идемпотентности повторно использован
+// с другими данными заметки
+var ErrIdempotencyKeyReused = errors.New("idempotency key reused with different request")
+
+// WithIdempotencyTTL задает время хранения ключей идемпотентности CreateNote
+func WithIdempotencyTTL(ttl time.Duration) Option {
+	return func(s *service) {
+		s.idempotency.ttl = ttl
+	}
+}
+
+// idempotencyEntry результат создания заметки по ключу
+// done закрывается, когда создание завершено; до этого повторы ждут результата
+type idempotencyEntry struct {
+	requestHash uint64
+	note        model.Note
+	err         error
+	expiresAt   time.Time
+	done        chan struct{}
+}
+
+// idempotencyStore хранит соответствие ключ → созданная заметка с TTL
+type idempotencyStore struct {
+	mu        sync.Mutex
+	ttl       time.Duration
+	now       func() time.Time
+	entries   map[string]*idempotencyEntry
+	lastSweep time.Time
+}
+
+func newIdempotencyStore() *idempotencyStore {
+	return &idempotencyStore{
+		ttl:     DefaultIdempotencyTTL,
+		now:     time.Now,
+		entries: make(map[string]*idempotencyEntry),
+	}
+}
+
+// do создает заметку через create один раз для ключа key владельца из ctx
+// Повтор с тем же ключом в пределах TTL возвращает исходную заметку, параллельный повтор
+// ждет завершения первого запроса. Если первый запрос завершился ошибкой, ключ освобождается
+// requestHash защищает от повторного использования ключа с другими данными
+func (st *idempotencyStore) do(ctx context.Context, key string, requestHash uint64, create func() (model.Note, error)) (model.Note, error) {
+	owner, _ := repository.OwnerFromContext(ctx)
+	key = owner + "\x00" + key
+
+	for {
+		st.mu.Lock()
+		now := st.now()
+		st.sweep(now)
+
+		entry, ok := st.entries[key]
+		if ok && entry.isExpired(now) {
+			delete(st.entries, key)
+			ok = false
+		}
+
+		if !ok {
+			entry = &idempotencyEntry{requestHash: requestHash, done: make(chan struct{})}
+			st.entries[key] = entry
+			st.mu.Unlock()
+
+			note, err := create()
+
+			st.mu.Lock()
+			entry.note, entry.err = note, err
+			entry.expiresAt = st.now().Add(st.ttl)
+			if err != nil {
+				delete(st.entries, key)
+			}
+			close(entry.done)
+			st.mu.Unlock()
+
+			return note, err
+		}
+		st.mu.Unlock()
+
+		if entry.requestHash != requestHash {
+			return model.Note{}, ErrIdempotencyKeyReused
+		}
+
+		select {
+		case <-entry.done:
+		case <-ctx.Done():
+			return model.Note{}, ctx.Err()
+		}
+
+		// Первый запрос не создал заметку - пробуем создать ее сами
+		if entry.err != nil {
+			continue
+		}
+		return entry.note, nil
+	}
+}
+
+// isExpired сообщает, истек ли TTL завершенной записи
+func (e *idempotencyEntry) isExpired(now time.Time) bool {
+	select {
+	case <-e.done:
+		return now.After(e.expiresAt)
+	default:
+		return false
+	}
+}
+
+// sweep удаляет истекшие записи не чаще раза в минуту. Вызывается под st.mu
+func (st *idempotencyStore) sweep(now time.Time) {
+	if now.Sub(st.lastSweep) < time.Minute {
+		return
+	}
+	st.lastSweep = now
+
+	for key, entry := range st.entries {
+		if entry.isExpired(now) {
+			delete(st.entries, key)
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package notes
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"notes-service/internal/auth"
+	"notes-service/internal/repository/memory"
+	svc "notes-service/internal/service"
+)
+
+func TestNoteService_CreateWithIdempotencyKey(t *testing.T) {
+	repo := memory.NewRepository()
+	service := NewNoteService(repo)
+	ctx := auth.NewContext(context.Background(), auth.Principal{UserID: "alice"})
+
+	input := svc.CreateNoteInput{Title: "Retried note", Content: "Some content", IdempotencyKey: "key-1"}
+
+	first, err := service.Create(ctx, input)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	second, err := service.Create(ctx, input)
+	if err != nil {
+		t.Fatalf("Expected no error on retry, got: %v", err)
+	}
+	if second.ID != first.ID {
+		t.Errorf("Expected retry to return note %s, got %s", first.ID, second.ID)
+	}
+
+	// Тот же ключ с другими данными отклоняется
+	input.Title = "Another note"
+	if _, err := service.Create(ctx, input); !errors.Is(err, ErrIdempotencyKeyReused) {
+		t.Errorf("Expected ErrIdempotencyKeyReused, got: %v", err)
+	}
+
+	// Ключи разных пользователей независимы
+	bobCtx := auth.NewContext(context.Background(), auth.Principal{UserID: "bob"})
+	input.Title = "Retried note"
+	bobNote, err := service.Create(bobCtx, input)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if bobNote.ID == first.ID {
+		t.Error("Expected a new note for another user")
+	}
+}
+
+func TestNoteService_CreateWithIdempotencyKey_Concurrent(t *testing.T) {
+	repo := memory.NewRepository()
+	service := NewNoteService(repo)
+	ctx := auth.NewContext(context.Background(), auth.Principal{UserID: "alice"})
+
+	input := svc.CreateNoteInput{Title: "Concurrent note", IdempotencyKey: "key-1"}
+
+	var wg sync.WaitGroup
+	ids := make([]string, 10)
+	for i := range ids {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			note, err := service.Create(ctx, input)
+			if err != nil {
+				t.Errorf("Expected no error, got: %v", err)
+				return
+			}
+			ids[i] = note.ID
+		}()
+	}
+	wg.Wait()
+
+	for _, id := range ids[1:] {
+		if id != ids[0] {
+			t.Fatalf("Expected all retries to return one note, got %v", ids)
+		}
+	}
+
+	notes, err := service.List(ctx, svc.ListOptions{})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(notes) != 1 {
+		t.Errorf("Expected 1 note, got %d", len(notes))
+	}
+}
+
+func TestIdempotencyStore_ExpiresKeys(t *testing.T) {
+	store := newIdempotencyStore()
+	now := time.Now()
+	store.now = func() time.Time { return now }
+	store.ttl = time.Hour
+
+	service := NewNoteService(memory.NewRepository()).(*service)
+	service.idempotency = store
+	ctx := auth.NewContext(context.Background(), auth.Principal{UserID: "alice"})
+	input := svc.CreateNoteInput{Title: "Expiring note", IdempotencyKey: "key-1"}
+
+	first, err := service.Create(ctx, input)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	now = now.Add(2 * time.Hour)
+	second, err := service.Create(ctx, input)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if second.ID == first.ID {
+		t.Error("Expected a new note after the key expired")
+	}
+}
@@ -0,0 +1,249 @@
+package notes
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"notes-service/internal/auth"
+	"notes-service/internal/model"
+	"notes-service/internal/repository"
+	"notes-service/internal/telemetry"
+)
+
+const (
+	// DefaultLockTTL время аренды блокировки, если клиент его не указал
+	DefaultLockTTL = 5 * time.Minute
+
+	// MaxLockTTL максимальное время аренды блокировки за один вызов Lock
+	// Дольше блокировку можно удерживать, продлевая ее повторными вызовами
+	MaxLockTTL = time.Hour
+
+	// LockSweepInterval период фоновой очистки истекших блокировок (SweepLocks)
+	LockSweepInterval = time.Minute
+)
+
+// ErrNoteLocked возвращается, когда заметка заблокирована другим пользователем
+var ErrNoteLocked = errors.New("note is locked by another user")
+
+// LockSweeper удаляет истекшие блокировки заметок в фоне (реализуется сервисом NewNoteService)
+type LockSweeper interface {
+	SweepLocks(ctx context.Context) error
+}
+
+// LockedError сообщает о действующей блокировке заметки другим пользователем
+// errors.Is(err, ErrNoteLocked) для такой ошибки возвращает true
+type LockedError struct {
+	Lock model.NoteLock
+}
+
+func (e *LockedError) Error() string {
+	return fmt.Sprintf("note is locked by %s until %s", e.Lock.HolderID, e.Lock.ExpiresAt.UTC().Format(time.RFC3339))
+}
+
+func (e *LockedError) Is(target error) bool {
+	return target == ErrNoteLocked
+}
+
+// Lock захватывает блокировку заметки для вызывающего пользователя на время ttl (0 - DefaultLockTTL)
+// Повторный вызов держателем продлевает аренду. force перехватывает блокировку другого
+// пользователя и доступен только администраторам, в том числе для чужих заметок
+func (s *service) Lock(ctx context.Context, id string, ttl time.Duration, force bool) (_ model.NoteLock, err error) {
+	ctx, span := startSpan(ctx, "Lock", noteIDAttribute(id))
+	defer func() { telemetry.EndSpan(span, err) }()
+	principal, ok := auth.FromContext(ctx)
+	if !ok {
+		return model.NoteLock{}, auth.ErrPermissionDenied
+	}
+	if id == "" {
+		return model.NoteLock{}, errors.New("id cannot be empty")
+	}
+	if ttl < 0 || ttl > MaxLockTTL {
+		return model.NoteLock{}, fmt.Errorf("invalid lock ttl %s: must be between 0 and %s", ttl, MaxLockTTL)
+	}
+	if ttl == 0 {
+		ttl = DefaultLockTTL
+	}
+
+	if err := s.lockAccess(ctx, principal, id, force); err != nil {
+		return model.NoteLock{}, err
+	}
+
+	return s.locks.acquire(id, principal.UserID, ttl, force)
+}
+
+// Unlock снимает блокировку заметки, удерживаемую вызывающим пользователем
+// Снятие отсутствующей или истекшей блокировки не является ошибкой
+// force снимает блокировку другого пользователя и доступен только администраторам
+func (s *service) Unlock(ctx context.Context, id string, force bool) (err error) {
+	ctx, span := startSpan(ctx, "Unlock", noteIDAttribute(id))
+	defer func() { telemetry.EndSpan(span, err) }()
+	principal, ok := auth.FromContext(ctx)
+	if !ok {
+		return auth.ErrPermissionDenied
+	}
+	if id == "" {
+		return errors.New("id cannot be empty")
+	}
+
+	if err := s.lockAccess(ctx, principal, id, force); err != nil {
+		return err
+	}
+
+	return s.locks.release(id, principal.UserID, force)
+}
+
+// lockAccess проверяет, что пользователь может блокировать заметку: владелец и пользователи
+// с доступом на запись, а с force - администратор для любой существующей заметки
+func (s *service) lockAccess(ctx context.Context, principal auth.Principal, id string, force bool) error {
+	if !force {
+		_, _, err := s.writableNote(ownerScope(ctx), id)
+		return err
+	}
+
+	if !principal.HasRole(auth.RoleAdmin) {
+		return auth.ErrPermissionDenied
+	}
+	_, err := s.noteRepository.GetByID(repository.WithoutOwner(ctx), id)
+	return err
+}
+
+// SweepLocks раз в LockSweepInterval удаляет истекшие блокировки, пока ctx не отменен
+// Истекшая блокировка не действует и без очистки, очистка освобождает память брошенных блокировок,
+// к заметкам которых больше не обращаются
+func (s *service) SweepLocks(ctx context.Context) error {
+	ticker := time.NewTicker(LockSweepInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			s.locks.sweepExpired()
+		}
+	}
+}
+
+var _ LockSweeper = (*service)(nil)
+
+// checkLock возвращает LockedError, если заметку заблокировал не вызывающий пользователь
+// Внутренние вызовы без пользователя блокировки не учитывают. Проверяется всеми изменениями
+// заметки: Update, Delete, BatchDelete, закреплением, архивированием и сменой парольной фразы
+func (s *service) checkLock(ctx context.Context, id string) error {
+	principal, ok := auth.FromContext(ctx)
+	if !ok {
+		return nil
+	}
+	return s.locks.check(id, principal.UserID)
+}
+
+// lockStore хранит блокировки заметок в памяти
+// Истекшие блокировки не действуют с момента истечения и удаляются при обращении
+// к заметке или периодической очисткой (SweepLocks)
+type lockStore struct {
+	mu        sync.Mutex
+	now       func() time.Time
+	locks     map[string]model.NoteLock
+	lastSweep time.Time
+}
+
+func newLockStore() *lockStore {
+	return &lockStore{
+		now:   time.Now,
+		locks: make(map[string]model.NoteLock),
+	}
+}
+
+// acquire захватывает или продлевает блокировку заметки noteID для holder
+func (st *lockStore) acquire(noteID, holder string, ttl time.Duration, force bool) (model.NoteLock, error) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	now := st.now()
+	if current, ok := st.active(noteID, now); ok && current.HolderID != holder && !force {
+		return model.NoteLock{}, &LockedError{Lock: current}
+	}
+
+	lock := model.NoteLock{
+		NoteID:     noteID,
+		HolderID:   holder,
+		AcquiredAt: now,
+		ExpiresAt:  now.Add(ttl),
+	}
+	st.locks[noteID] = lock
+	return lock, nil
+}
+
+// release снимает блокировку holder; с force - блокировку любого пользователя
+func (st *lockStore) release(noteID, holder string, force bool) error {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	current, ok := st.active(noteID, st.now())
+	if !ok {
+		return nil
+	}
+	if current.HolderID != holder && !force {
+		return &LockedError{Lock: current}
+	}
+
+	delete(st.locks, noteID)
+	return nil
+}
+
+// check возвращает LockedError, если заметка заблокирована не пользователем holder
+func (st *lockStore) check(noteID, holder string) error {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	if current, ok := st.active(noteID, st.now()); ok && current.HolderID != holder {
+		return &LockedError{Lock: current}
+	}
+	return nil
+}
+
+// drop удаляет блокировку удаленной заметки
+func (st *lockStore) drop(noteID string) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	delete(st.locks, noteID)
+}
+
+// active возвращает действующую блокировку заметки, удаляя истекшую. Вызывается под st.mu
+func (st *lockStore) active(noteID string, now time.Time) (model.NoteLock, bool) {
+	st.sweep(now)
+
+	lock, ok := st.locks[noteID]
+	if !ok {
+		return model.NoteLock{}, false
+	}
+	if lock.Expired(now) {
+		delete(st.locks, noteID)
+		return model.NoteLock{}, false
+	}
+	return lock, true
+}
+
+// sweepExpired удаляет истекшие блокировки по расписанию SweepLocks
+func (st *lockStore) sweepExpired() {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	st.sweep(st.now())
+}
+
+// sweep удаляет истекшие блокировки не чаще раза в LockSweepInterval. Вызывается под st.mu
+func (st *lockStore) sweep(now time.Time) {
+	if now.Sub(st.lastSweep) < LockSweepInterval {
+		return
+	}
+	st.lastSweep = now
+
+	for noteID, lock := range st.locks {
+		if lock.Expired(now) {
+			delete(st.locks, noteID)
+		}
+	}
+}
@@ -0,0 +1,200 @@
+package notes
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"notes-service/internal/auth"
+	"notes-service/internal/model"
+	"notes-service/internal/repository/memory"
+	svc "notes-service/internal/service"
+)
+
+func TestNoteService_Lock_BlocksOtherEditors(t *testing.T) {
+	service := NewNoteService(memory.NewRepository())
+	alice := auth.NewContext(context.Background(), auth.Principal{UserID: "alice"})
+	bob := auth.NewContext(context.Background(), auth.Principal{UserID: "bob"})
+
+	note, err := service.Create(alice, svc.CreateNoteInput{Title: "Shared note", Content: "Hello"})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if _, err := service.Share(alice, note.ID, "bob", model.SharePermissionWrite); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	lock, err := service.Lock(bob, note.ID, 0, false)
+	if err != nil {
+		t.Fatalf("Expected shared editor to lock the note, got: %v", err)
+	}
+	if lock.HolderID != "bob" || lock.ExpiresAt.Sub(lock.AcquiredAt) != DefaultLockTTL {
+		t.Errorf("Expected bob's lock with default ttl, got %+v", lock)
+	}
+
+	// Владелец не может изменить заметку, пока ее держит другой пользователь
+	_, err = service.Update(alice, svc.UpdateNoteInput{ID: note.ID, Content: "Owner edit"})
+	var lockedErr *LockedError
+	if !errors.As(err, &lockedErr) || lockedErr.Lock.HolderID != "bob" {
+		t.Fatalf("Expected LockedError held by bob, got: %v", err)
+	}
+	if _, err := service.Lock(alice, note.ID, 0, false); !errors.Is(err, ErrNoteLocked) {
+		t.Errorf("Expected ErrNoteLocked for second locker, got: %v", err)
+	}
+	if err := service.Unlock(alice, note.ID, false); !errors.Is(err, ErrNoteLocked) {
+		t.Errorf("Expected ErrNoteLocked when unlocking another user's lock, got: %v", err)
+	}
+
+	// Держатель блокировки редактирует заметку
+	if _, err := service.Update(bob, svc.UpdateNoteInput{ID: note.ID, Content: "Bob edit"}); err != nil {
+		t.Fatalf("Expected lock holder to update, got: %v", err)
+	}
+
+	if err := service.Unlock(bob, note.ID, false); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if _, err := service.Update(alice, svc.UpdateNoteInput{ID: note.ID, Content: "Owner edit"}); err != nil {
+		t.Errorf("Expected update after unlock, got: %v", err)
+	}
+}
+
+func TestNoteService_Lock_LeaseExpires(t *testing.T) {
+	service := NewNoteService(memory.NewRepository()).(*service)
+	now := time.Now()
+	service.locks.now = func() time.Time { return now }
+	alice := auth.NewContext(context.Background(), auth.Principal{UserID: "alice"})
+
+	note, err := service.Create(alice, svc.CreateNoteInput{Title: "Note", Content: "Hello"})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	// Блокировать заметку может только аутентифицированный пользователь на ограниченное время
+	if _, err := service.Lock(context.Background(), note.ID, 0, false); !errors.Is(err, auth.ErrPermissionDenied) {
+		t.Errorf("Expected ErrPermissionDenied without principal, got: %v", err)
+	}
+	if _, err := service.Lock(alice, note.ID, 2*MaxLockTTL, false); err == nil {
+		t.Error("Expected error for ttl above MaxLockTTL")
+	}
+
+	if _, err := service.locks.acquire(note.ID, "bob", time.Minute, false); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if _, err := service.Update(alice, svc.UpdateNoteInput{ID: note.ID, Content: "Edit"}); !errors.Is(err, ErrNoteLocked) {
+		t.Fatalf("Expected ErrNoteLocked, got: %v", err)
+	}
+
+	now = now.Add(time.Minute)
+	if _, err := service.Update(alice, svc.UpdateNoteInput{ID: note.ID, Content: "Edit"}); err != nil {
+		t.Errorf("Expected update after lease expiry, got: %v", err)
+	}
+	if len(service.locks.locks) != 0 {
+		t.Errorf("Expected expired lock to be removed, got %d locks", len(service.locks.locks))
+	}
+}
+
+func TestNoteService_Lock_ForceRequiresAdmin(t *testing.T) {
+	service := NewNoteService(memory.NewRepository())
+	alice := auth.NewContext(context.Background(), auth.Principal{UserID: "alice"})
+	bob := auth.NewContext(context.Background(), auth.Principal{UserID: "bob"})
+	admin := auth.NewContext(context.Background(), auth.Principal{UserID: "root", Roles: []string{auth.RoleAdmin}})
+
+	note, err := service.Create(alice, svc.CreateNoteInput{Title: "Note", Content: "Hello"})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if _, err := service.Lock(alice, note.ID, time.Minute, false); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if _, err := service.Lock(bob, note.ID, 0, true); !errors.Is(err, auth.ErrPermissionDenied) {
+		t.Errorf("Expected ErrPermissionDenied for force without admin role, got: %v", err)
+	}
+
+	// Администратор перехватывает блокировку чужой заметки
+	lock, err := service.Lock(admin, note.ID, 0, true)
+	if err != nil {
+		t.Fatalf("Expected admin to steal the lock, got: %v", err)
+	}
+	if lock.HolderID != "root" {
+		t.Errorf("Expected lock holder root, got %q", lock.HolderID)
+	}
+	if _, err := service.Update(alice, svc.UpdateNoteInput{ID: note.ID, Content: "Edit"}); !errors.Is(err, ErrNoteLocked) {
+		t.Errorf("Expected ErrNoteLocked after steal, got: %v", err)
+	}
+
+	if err := service.Unlock(admin, note.ID, true); err != nil {
+		t.Fatalf("Expected admin to force unlock, got: %v", err)
+	}
+	if _, err := service.Update(alice, svc.UpdateNoteInput{ID: note.ID, Content: "Edit"}); err != nil {
+		t.Errorf("Expected update after force unlock, got: %v", err)
+	}
+}
+
+func TestNoteService_Lock_BlocksAllMutations(t *testing.T) {
+	service := NewNoteService(memory.NewRepository())
+	alice := auth.NewContext(context.Background(), auth.Principal{UserID: "alice"})
+	bob := auth.NewContext(context.Background(), auth.Principal{UserID: "bob"})
+
+	note, err := service.Create(alice, svc.CreateNoteInput{Title: "Shared note", Content: "Hello"})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if _, err := service.Share(alice, note.ID, "bob", model.SharePermissionWrite); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if _, err := service.Lock(bob, note.ID, 0, false); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	// Пока заметку держит bob, владелец не может ее удалить, закрепить, архивировать или защитить
+	mutations := map[string]func() error{
+		"Delete":        func() error { return service.Delete(alice, note.ID) },
+		"Pin":           func() error { _, err := service.Pin(alice, note.ID); return err },
+		"Archive":       func() error { _, err := service.Archive(alice, note.ID); return err },
+		"SetPassphrase": func() error { _, err := service.SetPassphrase(alice, note.ID, "correct horse"); return err },
+		"BatchDelete": func() error {
+			_, err := service.BatchDelete(alice, []string{note.ID}, true)
+			return err
+		},
+	}
+	for name, mutate := range mutations {
+		if err := mutate(); !errors.Is(err, ErrNoteLocked) {
+			t.Errorf("Expected ErrNoteLocked from %s, got: %v", name, err)
+		}
+	}
+
+	if err := service.Unlock(bob, note.ID, false); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if err := service.Delete(alice, note.ID); err != nil {
+		t.Errorf("Expected delete after unlock, got: %v", err)
+	}
+}
+
+func TestNoteService_SweepLocks(t *testing.T) {
+	service := NewNoteService(memory.NewRepository()).(*service)
+	now := time.Now()
+	service.locks.now = func() time.Time { return now }
+
+	if _, err := service.locks.acquire("abandoned", "bob", time.Minute, false); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if _, err := service.locks.acquire("held", "bob", time.Hour, false); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	// Брошенная блокировка удаляется очисткой без обращения к заметке
+	now = now.Add(LockSweepInterval + time.Minute)
+	service.locks.sweepExpired()
+	if _, ok := service.locks.locks["abandoned"]; ok || len(service.locks.locks) != 1 {
+		t.Errorf("Expected only the expired lock removed, got %v", service.locks.locks)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := service.SweepLocks(ctx); err != nil {
+		t.Errorf("Expected nil after cancel, got: %v", err)
+	}
+}
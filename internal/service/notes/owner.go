@@ -0,0 +1,54 @@
+package notes
+
+import (
+	"context"
+	"errors"
+
+	"notes-service/internal/auth"
+	"notes-service/internal/model"
+	"notes-service/internal/repository"
+	"notes-service/internal/repository/memory"
+	"notes-service/internal/telemetry"
+)
+
+// ErrNoteAccessDenied возвращается, когда заметка существует, но принадлежит другому пользователю
+// и не открыта вызывающему. Сообщать ли клиенту об отказе в доступе или скрыть существование
+// заметки, решает обработчик (см. grpc.AccessPolicy)
+var ErrNoteAccessDenied = errors.New("note belongs to another user")
+
+// ownerScope ограничивает операции хранилища заметками пользователя запроса
+// Запросы без аутентифицированного пользователя (внутренние вызовы) не ограничиваются
+func ownerScope(ctx context.Context) context.Context {
+	if principal, ok := auth.FromContext(ctx); ok {
+		return repository.WithOwner(ctx, principal.UserID)
+	}
+	return ctx
+}
+
+// accessError уточняет ErrNoteNotFound, полученную в рамках владельца запроса:
+// если заметка id существует у другого пользователя, возвращается ErrNoteAccessDenied
+func accessError(ctx context.Context, notes repository.NoteRepository, id string, err error) error {
+	if !errors.Is(err, memory.ErrNoteNotFound) {
+		return err
+	}
+	if _, scoped := repository.OwnerFromContext(ctx); !scoped {
+		return err
+	}
+
+	if _, getErr := notes.GetByID(repository.WithoutOwner(ctx), id); getErr == nil {
+		return ErrNoteAccessDenied
+	}
+	return err
+}
+
+// ListAll возвращает заметки всех пользователей, доступно только администраторам
+func (s *service) ListAll(ctx context.Context) (_ []model.Note, err error) {
+	ctx, span := startSpan(ctx, "ListAll")
+	defer func() { telemetry.EndSpan(span, err) }()
+	principal, ok := auth.FromContext(ctx)
+	if !ok || !principal.HasRole(auth.RoleAdmin) {
+		return nil, auth.ErrPermissionDenied
+	}
+
+	return s.noteRepository.List(ctx)
+}
@@ -0,0 +1,86 @@
+package notes
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"notes-service/internal/auth"
+	"notes-service/internal/repository/memory"
+	svc "notes-service/internal/service"
+)
+
+func TestNoteService_ScopesNotesToOwner(t *testing.T) {
+	service := NewNoteService(memory.NewRepository())
+	alice := auth.NewContext(context.Background(), auth.Principal{UserID: "alice", Roles: []string{auth.RoleUser}})
+	bob := auth.NewContext(context.Background(), auth.Principal{UserID: "bob", Roles: []string{auth.RoleUser}})
+
+	note, err := service.Create(alice, svc.CreateNoteInput{Title: "Alice note"})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if note.OwnerID != "alice" {
+		t.Errorf("Expected owner alice, got %q", note.OwnerID)
+	}
+
+	if _, err := service.Get(bob, note.ID); !errors.Is(err, ErrNoteAccessDenied) {
+		t.Errorf("Expected ErrNoteAccessDenied for another user, got: %v", err)
+	}
+	if _, err := service.ListRevisions(bob, note.ID); !errors.Is(err, ErrNoteAccessDenied) {
+		t.Errorf("Expected ErrNoteAccessDenied for another user's revisions, got: %v", err)
+	}
+	if err := service.Delete(bob, note.ID); !errors.Is(err, ErrNoteAccessDenied) {
+		t.Errorf("Expected ErrNoteAccessDenied on delete by another user, got: %v", err)
+	}
+
+	notes, err := service.List(bob, svc.ListOptions{})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(notes) != 0 {
+		t.Errorf("Expected no notes for bob, got %d", len(notes))
+	}
+
+	if _, err := service.Get(alice, note.ID); err != nil {
+		t.Errorf("Expected owner to read the note, got: %v", err)
+	}
+}
+
+func TestNoteService_ListAll_RequiresAdmin(t *testing.T) {
+	service := NewNoteService(memory.NewRepository())
+	user := auth.NewContext(context.Background(), auth.Principal{UserID: "alice", Roles: []string{auth.RoleUser}})
+	admin := auth.NewContext(context.Background(), auth.Principal{UserID: "root", Roles: []string{auth.RoleAdmin}})
+
+	for _, title := range []string{"First", "Second"} {
+		if _, err := service.Create(user, svc.CreateNoteInput{Title: title}); err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+	}
+
+	if _, err := service.ListAll(user); !errors.Is(err, auth.ErrPermissionDenied) {
+		t.Errorf("Expected ErrPermissionDenied for non-admin, got: %v", err)
+	}
+	if _, err := service.ListAll(context.Background()); !errors.Is(err, auth.ErrPermissionDenied) {
+		t.Errorf("Expected ErrPermissionDenied without principal, got: %v", err)
+	}
+
+	notes, err := service.ListAll(admin)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(notes) != 2 {
+		t.Errorf("Expected 2 notes, got %d", len(notes))
+	}
+}
+
+func TestNoteService_MissingNoteIsNotAccessDenied(t *testing.T) {
+	service := NewNoteService(memory.NewRepository())
+	bob := auth.NewContext(context.Background(), auth.Principal{UserID: "bob", Roles: []string{auth.RoleUser}})
+
+	if _, err := service.Get(bob, "missing"); !errors.Is(err, memory.ErrNoteNotFound) {
+		t.Errorf("Expected ErrNoteNotFound for missing note, got: %v", err)
+	}
+	if err := service.Delete(bob, "missing"); !errors.Is(err, memory.ErrNoteNotFound) {
+		t.Errorf("Expected ErrNoteNotFound on delete of missing note, got: %v", err)
+	}
+}
@@ -0,0 +1,309 @@
+package notes
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"notes-service/internal/auth"
+	"notes-service/internal/model"
+	svc "notes-service/internal/service"
+	"notes-service/internal/telemetry"
+
+	"golang.org/x/crypto/argon2"
+)
+
+const (
+	// MinPassphraseLength минимальная длина парольной фразы заметки в символах
+	MinPassphraseLength = 8
+
+	// MaxPassphraseFailures количество неверных фраз подряд, после которого попытки блокируются
+	MaxPassphraseFailures = 5
+
+	// PassphraseLockout время, на которое блокируются попытки после MaxPassphraseFailures неверных фраз
+	PassphraseLockout = 15 * time.Minute
+)
+
+// Параметры argon2id для новых хэшей (рекомендация OWASP); при проверке используются параметры из хэша
+const (
+	argon2Time    = 2
+	argon2Memory  = 19 * 1024 // КиБ
+	argon2Threads = 1
+	argon2KeyLen  = 32
+	argon2SaltLen = 16
+)
+
+var (
+	// ErrPassphraseRequired возвращается при обращении к защищенной заметке без парольной фразы
+	ErrPassphraseRequired = errors.New("note is protected by a passphrase")
+
+	// ErrPassphraseMismatch возвращается при неверной парольной фразе заметки
+	ErrPassphraseMismatch = errors.New("note passphrase does not match")
+
+	// ErrPassphraseLocked возвращается, пока попытки ввода фразы заблокированы
+	ErrPassphraseLocked = errors.New("note passphrase attempts are locked")
+)
+
+// PassphraseLockedError сообщает о блокировке попыток ввода парольной фразы заметки
+// errors.Is(err, ErrPassphraseLocked) для такой ошибки возвращает true
+type PassphraseLockedError struct {
+	NoteID string
+	Until  time.Time // Время снятия блокировки
+}
+
+func (e *PassphraseLockedError) Error() string {
+	return fmt.Sprintf("note passphrase attempts are locked until %s", e.Until.UTC().Format(time.RFC3339))
+}
+
+func (e *PassphraseLockedError) Is(target error) bool {
+	return target == ErrPassphraseLocked
+}
+
+// AuditLog принимает записи аудита доступа к защищенным заметкам
+type AuditLog interface {
+	Record(ctx context.Context, record model.AuditRecord)
+}
+
+// logAuditLog записывает аудит в лог сервера (по умолчанию)
+type logAuditLog struct{}
+
+func (logAuditLog) Record(_ context.Context, r model.AuditRecord) {
+	log.Printf("Audit: action=%s note=%s user=%s outcome=%s", r.Action, r.NoteID, r.UserID, r.Outcome)
+}
+
+// WithAuditLog задает журнал аудита защищенных заметок (по умолчанию - лог сервера)
+func WithAuditLog(auditLog AuditLog) Option {
+	return func(s *service) {
+		s.audit = auditLog
+	}
+}
+
+// SetPassphrase защищает заметку владельца парольной фразой или снимает защиту (пустая passphrase)
+// Смена и снятие защиты требуют текущую фразу (svc.WithNotePassphrase). Хранится только хэш argon2id;
+// защита не меняет содержимое, поэтому не создает ревизию
+func (s *service) SetPassphrase(ctx context.Context, id, passphrase string) (_ model.Note, err error) {
+	ctx, span := startSpan(ctx, "SetPassphrase", noteIDAttribute(id))
+	defer func() { telemetry.EndSpan(span, err) }()
+	ctx = ownerScope(ctx)
+	if id == "" {
+		return model.Note{}, errors.New("id cannot be empty")
+	}
+	if passphrase != "" && utf8.RuneCountInString(passphrase) < MinPassphraseLength {
+		return model.Note{}, fmt.Errorf("invalid passphrase: must be at least %d characters", MinPassphraseLength)
+	}
+
+	note, err := s.noteRepository.GetByID(ctx, id)
+	if err != nil {
+		return model.Note{}, accessError(ctx, s.noteRepository, id, err)
+	}
+
+	action := model.AuditActionSetPassphrase
+	if passphrase == "" {
+		action = model.AuditActionRemovePassphrase
+	}
+	if err := s.checkPassphrase(ctx, note, action); err != nil {
+		return model.Note{}, err
+	}
+	if err := s.checkLock(ctx, id); err != nil {
+		return model.Note{}, err
+	}
+	wasProtected := note.PassphraseProtected()
+	if passphrase == "" && !wasProtected {
+		return note, nil
+	}
+
+	note.PassphraseHash = ""
+	if passphrase != "" {
+		if note.PassphraseHash, err = hashPassphrase(passphrase); err != nil {
+			return model.Note{}, err
+		}
+	}
+	note, err = s.noteRepository.Update(ctx, note)
+	if err != nil {
+		return model.Note{}, err
+	}
+	s.passphrases.reset(id)
+	// Смена и снятие защиты уже записаны в аудит при проверке текущей фразы
+	if !wasProtected {
+		s.recordAudit(ctx, action, id, model.AuditOutcomeGranted)
+	}
+	s.eventService.Publish(Event{Type: EventNoteUpdated, Note: note})
+
+	return note, nil
+}
+
+// checkPassphrase проверяет парольную фразу защищенной заметки из контекста и записывает результат в аудит
+// Незащищенные заметки и внутренние вызовы без пользователя не проверяются
+func (s *service) checkPassphrase(ctx context.Context, note model.Note, action string) error {
+	if !note.PassphraseProtected() {
+		return nil
+	}
+	if _, ok := auth.FromContext(ctx); !ok {
+		return nil
+	}
+
+	err := s.passphrases.verify(note, svc.NotePassphrase(ctx))
+	outcome := model.AuditOutcomeGranted
+	switch {
+	case errors.Is(err, ErrPassphraseRequired):
+		outcome = model.AuditOutcomeMissing
+	case errors.Is(err, ErrPassphraseMismatch):
+		outcome = model.AuditOutcomeInvalid
+	case errors.Is(err, ErrPassphraseLocked):
+		outcome = model.AuditOutcomeLockedOut
+	case err != nil:
+		return err
+	}
+	s.recordAudit(ctx, action, note.ID, outcome)
+	return err
+}
+
+// recordAudit записывает в аудит результат операции с защищенной заметкой
+func (s *service) recordAudit(ctx context.Context, action, noteID, outcome string) {
+	principal, _ := auth.FromContext(ctx)
+	s.audit.Record(ctx, model.AuditRecord{
+		Time:    s.now(),
+		Action:  action,
+		NoteID:  noteID,
+		UserID:  principal.UserID,
+		Outcome: outcome,
+	})
+}
+
+// redactProtected убирает содержимое защищенных заметок из списка: списки не принимают парольную фразу
+func redactProtected(notes []model.Note) []model.Note {
+	for i := range notes {
+		notes[i] = RedactNote(notes[i])
+	}
+	return notes
+}
+
+// RedactNote убирает содержимое заметки, если она защищена парольной фразой
+// Так защищенные заметки отдаются везде, где фраза не проверяется: в списках, обходах и событиях
+func RedactNote(note model.Note) model.Note {
+	if note.PassphraseProtected() {
+		note.Content = ""
+		note.ContentEncrypted = nil
+	}
+	return note
+}
+
+// passphraseStore считает неверные парольные фразы заметок и блокирует попытки после серии ошибок
+// Счетчики хранятся в памяти: блокировка защищает от перебора, а не переживает перезапуск
+type passphraseStore struct {
+	mu       sync.Mutex
+	now      func() time.Time
+	failures map[string]int       // Неверных фраз подряд по ID заметки
+	locked   map[string]time.Time // Время снятия блокировки по ID заметки
+}
+
+func newPassphraseStore() *passphraseStore {
+	return &passphraseStore{
+		now:      time.Now,
+		failures: make(map[string]int),
+		locked:   make(map[string]time.Time),
+	}
+}
+
+// verify проверяет фразу заметки: ErrPassphraseRequired без фразы, ErrPassphraseMismatch при неверной,
+// PassphraseLockedError, пока попытки заблокированы (фраза при этом не проверяется)
+func (st *passphraseStore) verify(note model.Note, passphrase string) error {
+	if err := st.checkLocked(note.ID); err != nil {
+		return err
+	}
+	if passphrase == "" {
+		return ErrPassphraseRequired
+	}
+
+	ok, err := verifyPassphrase(note.PassphraseHash, passphrase)
+	if err != nil {
+		return err
+	}
+	if ok {
+		st.reset(note.ID)
+		return nil
+	}
+	return st.fail(note.ID)
+}
+
+// checkLocked возвращает PassphraseLockedError, если попытки заметки заблокированы
+func (st *passphraseStore) checkLocked(noteID string) error {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	until, ok := st.locked[noteID]
+	if !ok {
+		return nil
+	}
+	if !st.now().Before(until) {
+		delete(st.locked, noteID)
+		return nil
+	}
+	return &PassphraseLockedError{NoteID: noteID, Until: until}
+}
+
+// fail учитывает неверную фразу и блокирует попытки после MaxPassphraseFailures ошибок подряд
+func (st *passphraseStore) fail(noteID string) error {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	st.failures[noteID]++
+	if st.failures[noteID] < MaxPassphraseFailures {
+		return ErrPassphraseMismatch
+	}
+	delete(st.failures, noteID)
+	until := st.now().Add(PassphraseLockout)
+	st.locked[noteID] = until
+	return &PassphraseLockedError{NoteID: noteID, Until: until}
+}
+
+// reset сбрасывает счетчик ошибок заметки после верной фразы или смены защиты
+func (st *passphraseStore) reset(noteID string) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	delete(st.failures, noteID)
+	delete(st.locked, noteID)
+}
+
+// hashPassphrase возвращает хэш argon2id фразы в формате PHC: $argon2id$v=19$m=...,t=...,p=...$<соль>$<хэш>
+func hashPassphrase(passphrase string) (string, error) {
+	salt := make([]byte, argon2SaltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return "", fmt.Errorf("generate passphrase salt: %w", err)
+	}
+	key := argon2.IDKey([]byte(passphrase), salt, argon2Time, argon2Memory, argon2Threads, argon2KeyLen)
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s", argon2.Version, argon2Memory, argon2Time, argon2Threads,
+		base64.RawStdEncoding.EncodeToString(salt), base64.RawStdEncoding.EncodeToString(key)), nil
+}
+
+// verifyPassphrase сравнивает фразу с хэшем hashPassphrase за постоянное время
+func verifyPassphrase(encoded, passphrase string) (bool, error) {
+	parts := strings.Split(encoded, "$")
+	if len(parts) != 6 || parts[1] != "argon2id" || parts[2] != fmt.Sprintf("v=%d", argon2.Version) {
+		return false, errors.New("unsupported passphrase hash format")
+	}
+	var memory, iterations uint32
+	var threads uint8
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &memory, &iterations, &threads); err != nil {
+		return false, fmt.Errorf("parse passphrase hash parameters: %w", err)
+	}
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return false, fmt.Errorf("decode passphrase salt: %w", err)
+	}
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil {
+		return false, fmt.Errorf("decode passphrase hash: %w", err)
+	}
+
+	actual := argon2.IDKey([]byte(passphrase), salt, iterations, memory, threads, uint32(len(key)))
+	return subtle.ConstantTimeCompare(actual, key) == 1, nil
+}
@@ -0,0 +1,218 @@
+package notes
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"notes-service/internal/auth"
+	"notes-service/internal/model"
+	"notes-service/internal/repository/memory"
+	svc "notes-service/internal/service"
+)
+
+// recordingAuditLog сохраняет записи аудита для проверки в тестах
+type recordingAuditLog struct {
+	records []model.AuditRecord
+}
+
+func (l *recordingAuditLog) Record(_ context.Context, record model.AuditRecord) {
+	l.records = append(l.records, record)
+}
+
+func TestNoteService_SetPassphrase_ProtectsNote(t *testing.T) {
+	audit := &recordingAuditLog{}
+	service := NewNoteService(memory.NewRepository(), WithAuditLog(audit))
+	alice := auth.NewContext(context.Background(), auth.Principal{UserID: "alice"})
+
+	note, err := service.Create(alice, svc.CreateNoteInput{Title: "Diary", Content: "Secret"})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if _, err := service.SetPassphrase(alice, note.ID, "short"); err == nil {
+		t.Error("Expected error for a passphrase shorter than MinPassphraseLength")
+	}
+	protected, err := service.SetPassphrase(alice, note.ID, "correct horse")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if !protected.PassphraseProtected() || protected.PassphraseHash == "correct horse" {
+		t.Fatalf("Expected an argon2id hash instead of the passphrase, got %q", protected.PassphraseHash)
+	}
+
+	// Чтение и изменение требуют фразу, списки возвращают заметку без содержимого
+	if _, err := service.Get(alice, note.ID); !errors.Is(err, ErrPassphraseRequired) {
+		t.Errorf("Expected ErrPassphraseRequired without passphrase, got: %v", err)
+	}
+	if _, err := service.Update(svc.WithNotePassphrase(alice, "wrong horse"), svc.UpdateNoteInput{ID: note.ID, Content: "Leak"}); !errors.Is(err, ErrPassphraseMismatch) {
+		t.Errorf("Expected ErrPassphraseMismatch for a wrong passphrase, got: %v", err)
+	}
+	unlocked := svc.WithNotePassphrase(alice, "correct horse")
+	if got, err := service.Get(unlocked, note.ID); err != nil || got.Content != "Secret" {
+		t.Errorf("Expected the note with the correct passphrase, got %+v, %v", got, err)
+	}
+	list, err := service.List(alice, svc.ListOptions{})
+	if err != nil || len(list) != 1 || list[0].Content != "" {
+		t.Errorf("Expected the protected note without content in List, got %+v, %v", list, err)
+	}
+
+	// Снятие защиты требует текущую фразу
+	if _, err := service.SetPassphrase(alice, note.ID, ""); !errors.Is(err, ErrPassphraseRequired) {
+		t.Errorf("Expected ErrPassphraseRequired when removing protection, got: %v", err)
+	}
+	if removed, err := service.SetPassphrase(unlocked, note.ID, ""); err != nil || removed.PassphraseProtected() {
+		t.Fatalf("Expected protection removed, got %+v, %v", removed, err)
+	}
+	if _, err := service.Get(alice, note.ID); err != nil {
+		t.Errorf("Expected access without passphrase after removal, got: %v", err)
+	}
+
+	var outcomes []string
+	for _, record := range audit.records {
+		if record.NoteID != note.ID || record.UserID != "alice" {
+			t.Errorf("Unexpected audit record %+v", record)
+		}
+		outcomes = append(outcomes, record.Action+":"+record.Outcome)
+	}
+	want := []string{"set_passphrase:granted", "get:missing", "update:invalid", "get:granted",
+		"remove_passphrase:missing", "remove_passphrase:granted"}
+	if len(outcomes) != len(want) {
+		t.Fatalf("Expected audit %v, got %v", want, outcomes)
+	}
+	for i := range want {
+		if outcomes[i] != want[i] {
+			t.Errorf("Expected audit %v, got %v", want, outcomes)
+			break
+		}
+	}
+}
+
+func TestNoteService_Passphrase_Lockout(t *testing.T) {
+	service := NewNoteService(memory.NewRepository(), WithAuditLog(&recordingAuditLog{})).(*service)
+	now := time.Now()
+	service.passphrases.now = func() time.Time { return now }
+	alice := auth.NewContext(context.Background(), auth.Principal{UserID: "alice"})
+
+	note, err := service.Create(alice, svc.CreateNoteInput{Title: "Diary", Content: "Secret"})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if _, err := service.SetPassphrase(alice, note.ID, "correct horse"); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	wrong := svc.WithNotePassphrase(alice, "wrong horse")
+	for range MaxPassphraseFailures - 1 {
+		if _, err := service.Get(wrong, note.ID); !errors.Is(err, ErrPassphraseMismatch) {
+			t.Fatalf("Expected ErrPassphraseMismatch, got: %v", err)
+		}
+	}
+	_, err = service.Get(wrong, note.ID)
+	var lockedErr *PassphraseLockedError
+	if !errors.As(err, &lockedErr) || !lockedErr.Until.Equal(now.Add(PassphraseLockout)) {
+		t.Fatalf("Expected PassphraseLockedError after %d failures, got: %v", MaxPassphraseFailures, err)
+	}
+
+	// Во время блокировки отклоняется и верная фраза, после нее счетчик начинается заново
+	correct := svc.WithNotePassphrase(alice, "correct horse")
+	if _, err := service.Get(correct, note.ID); !errors.Is(err, ErrPassphraseLocked) {
+		t.Errorf("Expected ErrPassphraseLocked for the correct passphrase during lockout, got: %v", err)
+	}
+	now = now.Add(PassphraseLockout)
+	if _, err := service.Get(correct, note.ID); err != nil {
+		t.Errorf("Expected access after the lockout, got: %v", err)
+	}
+	if _, err := service.Get(wrong, note.ID); !errors.Is(err, ErrPassphraseMismatch) {
+		t.Errorf("Expected a fresh failure counter after the lockout, got: %v", err)
+	}
+}
+
+func TestNoteService_Passphrase_ForEachRedacts(t *testing.T) {
+	service := NewNoteService(memory.NewRepository(), WithAuditLog(&recordingAuditLog{}))
+	alice := auth.NewContext(context.Background(), auth.Principal{UserID: "alice"})
+
+	note, err := service.Create(alice, svc.CreateNoteInput{Title: "Diary", Content: "Secret"})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if _, err := service.Create(alice, svc.CreateNoteInput{Title: "Groceries", Content: "Milk"}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if _, err := service.SetPassphrase(alice, note.ID, "correct horse"); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	// Обход не принимает фразу: содержимое защищенной заметки не выдается даже с верной фразой
+	contents := make(map[string]string)
+	err = service.ForEach(svc.WithNotePassphrase(alice, "correct horse"), 1, func(n model.Note) error {
+		contents[n.Title] = n.Content
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(contents) != 2 || contents["Diary"] != "" || contents["Groceries"] != "Milk" {
+		t.Errorf("Expected the protected note without content, got %v", contents)
+	}
+}
+
+func TestNoteService_Passphrase_Mutations(t *testing.T) {
+	audit := &recordingAuditLog{}
+	service := NewNoteService(memory.NewRepository(), WithAuditLog(audit))
+	alice := auth.NewContext(context.Background(), auth.Principal{UserID: "alice"})
+	unlocked := svc.WithNotePassphrase(alice, "correct horse")
+
+	note, err := service.Create(alice, svc.CreateNoteInput{Title: "Diary", Content: "Secret"})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if _, err := service.SetPassphrase(alice, note.ID, "correct horse"); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	// Без фразы заметку нельзя закрепить, архивировать или удалить
+	if _, err := service.Pin(alice, note.ID); !errors.Is(err, ErrPassphraseRequired) {
+		t.Errorf("Expected ErrPassphraseRequired from Pin, got: %v", err)
+	}
+	if _, err := service.Archive(alice, note.ID); !errors.Is(err, ErrPassphraseRequired) {
+		t.Errorf("Expected ErrPassphraseRequired from Archive, got: %v", err)
+	}
+	if err := service.Delete(alice, note.ID); !errors.Is(err, ErrPassphraseRequired) {
+		t.Errorf("Expected ErrPassphraseRequired from Delete, got: %v", err)
+	}
+	if _, err := service.BatchDelete(alice, []string{note.ID}, true); !errors.Is(err, ErrPassphraseRequired) {
+		t.Errorf("Expected ErrPassphraseRequired from atomic BatchDelete, got: %v", err)
+	}
+	results, err := service.BatchDelete(alice, []string{note.ID}, false)
+	if err != nil || len(results) != 1 || !errors.Is(results[0].Err, ErrPassphraseRequired) {
+		t.Errorf("Expected ErrPassphraseRequired for the BatchDelete item, got %+v, %v", results, err)
+	}
+	if got, err := service.Get(unlocked, note.ID); err != nil || got.Pinned || got.Archived {
+		t.Fatalf("Expected the note unchanged, got %+v, %v", got, err)
+	}
+
+	// С фразой изменения выполняются, а заметка возвращается без содержимого
+	pinned, err := service.Pin(unlocked, note.ID)
+	if err != nil || !pinned.Pinned || pinned.Content != "" {
+		t.Errorf("Expected the pinned note without content, got %+v, %v", pinned, err)
+	}
+	archived, err := service.Archive(unlocked, note.ID)
+	if err != nil || !archived.Archived || archived.Content != "" {
+		t.Errorf("Expected the archived note without content, got %+v, %v", archived, err)
+	}
+	if err := service.Delete(unlocked, note.ID); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	var outcomes []string
+	for _, record := range audit.records {
+		outcomes = append(outcomes, record.Action+":"+record.Outcome)
+	}
+	want := []string{"set_passphrase:granted", "pin:missing", "archive:missing", "delete:missing", "delete:missing",
+		"delete:missing", "get:granted", "pin:granted", "archive:granted", "delete:granted"}
+	if strings.Join(outcomes, ",") != strings.Join(want, ",") {
+		t.Errorf("Expected audit %v, got %v", want, outcomes)
+	}
+}
@@ -0,0 +1,78 @@
+package notes
+
+import (
+	"context"
+	"errors"
+
+	"notes-service/internal/model"
+	"notes-service/internal/repository"
+	"notes-service/internal/telemetry"
+)
+
+// Pin закрепляет заметку: закрепленные заметки выводятся в начале ListNotes
+func (s *service) Pin(ctx context.Context, id string) (_ model.Note, err error) {
+	ctx, span := startSpan(ctx, "Pin", noteIDAttribute(id))
+	defer func() { telemetry.EndSpan(span, err) }()
+	return s.setPinned(ctx, id, true)
+}
+
+// Unpin открепляет заметку
+func (s *service) Unpin(ctx context.Context, id string) (_ model.Note, err error) {
+	ctx, span := startSpan(ctx, "Unpin", noteIDAttribute(id))
+	defer func() { telemetry.EndSpan(span, err) }()
+	return s.setPinned(ctx, id, false)
+}
+
+// setPinned закрепляет или открепляет заметку владельца
+// Закрепление не меняет содержимое, поэтому не создает ревизию. Защищенную заметку
+// можно закрепить только с парольной фразой, возвращается она без содержимого
+func (s *service) setPinned(ctx context.Context, id string, pinned bool) (model.Note, error) {
+	ctx = ownerScope(ctx)
+	if id == "" {
+		return model.Note{}, errors.New("id cannot be empty")
+	}
+
+	note, err := s.noteRepository.GetByID(ctx, id)
+	if err != nil {
+		return model.Note{}, accessError(ctx, s.noteRepository, id, err)
+	}
+	if err := s.checkPassphrase(ctx, note, model.AuditActionPin); err != nil {
+		return model.Note{}, err
+	}
+	if err := s.checkLock(ctx, id); err != nil {
+		return model.Note{}, err
+	}
+
+	if pinner, ok := s.noteRepository.(repository.NotePinner); ok {
+		note, err := pinner.SetPinned(ctx, id, pinned)
+		if err != nil {
+			return model.Note{}, accessError(ctx, s.noteRepository, id, err)
+		}
+		s.eventService.Publish(Event{Type: EventNoteUpdated, Note: note})
+		return RedactNote(note), nil
+	}
+
+	if note.Pinned == pinned {
+		return RedactNote(note), nil
+	}
+
+	note.Pinned = pinned
+	note, err = s.noteRepository.Update(ctx, note)
+	if err != nil {
+		return model.Note{}, err
+	}
+	s.eventService.Publish(Event{Type: EventNoteUpdated, Note: note})
+	return RedactNote(note), nil
+}
+
+// pinnedFirst упорядочивает закрепленные заметки перед остальными
+func pinnedFirst(a, b model.Note) int {
+	switch {
+	case a.Pinned == b.Pinned:
+		return 0
+	case a.Pinned:
+		return -1
+	default:
+		return 1
+	}
+}
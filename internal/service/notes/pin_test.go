@@ -0,0 +1,104 @@
+package notes
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"notes-service/internal/repository"
+	"notes-service/internal/repository/memory"
+	svc "notes-service/internal/service"
+)
+
+func TestNoteService_PinnedNotesListedFirst(t *testing.T) {
+	ctx := context.Background()
+
+	// Проверяем и закрепление в in-memory хранилище, и запасной вариант через Update
+	repos := map[string]repository.NoteRepository{
+		"pinner":   memory.NewRepository(),
+		"fallback": newMockRepository(),
+	}
+
+	for name, repo := range repos {
+		t.Run(name, func(t *testing.T) {
+			service := NewNoteService(repo)
+
+			var ids []string
+			for _, title := range []string{"Alpha", "Bravo", "Charlie"} {
+				note, err := service.Create(ctx, svc.CreateNoteInput{Title: title})
+				if err != nil {
+					t.Fatalf("Expected no error, got: %v", err)
+				}
+				ids = append(ids, note.ID)
+			}
+
+			pinned, err := service.Pin(ctx, ids[2])
+			if err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+			if !pinned.Pinned {
+				t.Error("Expected note to be pinned")
+			}
+
+			for _, opts := range []svc.ListOptions{{}, {TitleCollation: "en"}} {
+				notes, err := service.List(ctx, opts)
+				if err != nil {
+					t.Fatalf("Expected no error, got: %v", err)
+				}
+				if notes[0].ID != ids[2] {
+					t.Errorf("Expected pinned note first (collation %q), got %q", opts.TitleCollation, notes[0].Title)
+				}
+			}
+
+			unpinned, err := service.Unpin(ctx, ids[2])
+			if err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+			if unpinned.Pinned {
+				t.Error("Expected note to be unpinned")
+			}
+
+			notes, err := service.List(ctx, svc.ListOptions{TitleCollation: "en"})
+			if err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+			if notes[0].Title != "Alpha" {
+				t.Errorf("Expected title order after unpin, got %q first", notes[0].Title)
+			}
+		})
+	}
+}
+
+func TestNoteService_PinDoesNotCreateRevision(t *testing.T) {
+	ctx := context.Background()
+	service := NewNoteService(memory.NewRepository())
+
+	note, err := service.Create(ctx, svc.CreateNoteInput{Title: "Pinned note"})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	pinned, err := service.Pin(ctx, note.ID)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if !pinned.UpdatedAt.Equal(note.UpdatedAt) {
+		t.Error("Expected pinning to keep UpdatedAt")
+	}
+
+	revisions, err := service.ListRevisions(ctx, note.ID)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(revisions) != 1 {
+		t.Errorf("Expected 1 revision, got %d", len(revisions))
+	}
+}
+
+func TestNoteService_PinNotFound(t *testing.T) {
+	service := NewNoteService(memory.NewRepository())
+
+	if _, err := service.Pin(context.Background(), "missing"); !errors.Is(err, memory.ErrNoteNotFound) {
+		t.Errorf("Expected ErrNoteNotFound, got: %v", err)
+	}
+}
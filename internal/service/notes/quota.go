@@ -0,0 +1,75 @@
+package notes
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"notes-service/internal/model"
+	svc "notes-service/internal/service"
+	"notes-service/internal/tenant"
+)
+
+// ErrNoteQuotaExceeded возвращается, когда тенант достиг квоты заметок
+var ErrNoteQuotaExceeded = errors.New("note quota exceeded")
+
+// remainingQuota возвращает, сколько заметок тенант еще может создать
+// -1 означает отсутствие квоты. ctx должен быть ограничен владельцем (см. ownerScope)
+func (s *service) remainingQuota(ctx context.Context) (int, error) {
+	settings, ok := tenant.FromContext(ctx)
+	if !ok || settings.MaxNotes <= 0 {
+		return -1, nil
+	}
+
+	notes, err := s.noteRepository.List(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	return max(settings.MaxNotes-len(notes), 0), nil
+}
+
+// quotaError возвращает ошибку превышения квоты с ее значением из настроек тенанта
+func quotaError(ctx context.Context) error {
+	settings, _ := tenant.FromContext(ctx)
+	return fmt.Errorf("%w: limit is %d notes", ErrNoteQuotaExceeded, settings.MaxNotes)
+}
+
+// checkSoftQuota проверяет мягкие пороги квоты тенанта после создания заметок created
+// remaining - остаток квоты до создания (см. remainingQuota). Пока использование не ниже
+// наименьшего порога, ответ содержит предупреждение QUOTA_SOFT_LIMIT; при пересечении порога
+// владельцу публикуется событие EventQuotaWarning с наибольшим пересеченным порогом
+func (s *service) checkSoftQuota(ctx context.Context, remaining int, created []model.Note) {
+	settings, _ := tenant.FromContext(ctx)
+	if remaining < 0 || len(created) == 0 || len(settings.QuotaWarningPercents) == 0 {
+		return
+	}
+
+	before := settings.MaxNotes - remaining
+	usage := model.QuotaUsage{Used: before + len(created), Limit: settings.MaxNotes}
+	reached := false
+	trigger := created[len(created)-1]
+	for _, percent := range settings.QuotaWarningPercents {
+		// Порог достигнут, когда заметок не меньше percent% квоты (с округлением вверх)
+		threshold := (usage.Limit*percent + 99) / 100
+		if percent <= 0 || usage.Used < threshold {
+			continue
+		}
+		reached = true
+		if before < threshold && percent > usage.ThresholdPercent {
+			usage.ThresholdPercent = percent
+			trigger = created[threshold-before-1]
+		}
+	}
+	if !reached {
+		return
+	}
+
+	svc.AddWarnings(ctx, model.Warning{
+		Code:    model.WarningQuotaSoftLimit,
+		Message: fmt.Sprintf("%d of %d notes used; new notes are rejected at the limit", usage.Used, usage.Limit),
+	})
+	if usage.ThresholdPercent > 0 {
+		s.eventService.Publish(Event{Type: EventQuotaWarning, Note: trigger, Quota: usage})
+	}
+}
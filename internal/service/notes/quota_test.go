@@ -0,0 +1,122 @@
+package notes
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"notes-service/internal/auth"
+	"notes-service/internal/model"
+	"notes-service/internal/repository/memory"
+	svc "notes-service/internal/service"
+	"notes-service/internal/tenant"
+)
+
+func TestNoteService_EnforcesTenantNoteQuota(t *testing.T) {
+	service := NewNoteService(memory.NewRepository())
+	ctx := auth.NewContext(context.Background(), auth.Principal{UserID: "alice"})
+	ctx = tenant.NewContext(ctx, tenant.Settings{MaxNotes: 2})
+
+	if _, err := service.Create(ctx, svc.CreateNoteInput{Title: "First"}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	// Атомарный пакет сверх квоты отклоняется целиком
+	batch := []model.Note{{Title: "Second"}, {Title: "Third"}}
+	if _, err := service.BatchCreate(ctx, batch, true); !errors.Is(err, ErrNoteQuotaExceeded) {
+		t.Errorf("Expected ErrNoteQuotaExceeded for atomic batch, got: %v", err)
+	}
+
+	// Неатомарный пакет создает заметки в пределах квоты
+	results, err := service.BatchCreate(ctx, batch, false)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if results[0].Err != nil {
+		t.Errorf("Expected first note within quota, got: %v", results[0].Err)
+	}
+	if !errors.Is(results[1].Err, ErrNoteQuotaExceeded) {
+		t.Errorf("Expected ErrNoteQuotaExceeded for second note, got: %v", results[1].Err)
+	}
+
+	if _, err := service.Create(ctx, svc.CreateNoteInput{Title: "Fourth"}); !errors.Is(err, ErrNoteQuotaExceeded) {
+		t.Errorf("Expected ErrNoteQuotaExceeded, got: %v", err)
+	}
+
+	// Квота считается по заметкам тенанта
+	bob := auth.NewContext(context.Background(), auth.Principal{UserID: "bob"})
+	bob = tenant.NewContext(bob, tenant.Settings{MaxNotes: 1})
+	if _, err := service.Create(bob, svc.CreateNoteInput{Title: "Bob note"}); err != nil {
+		t.Errorf("Expected bob to have a separate quota, got: %v", err)
+	}
+}
+
+func TestNoteService_WarnsAtSoftQuotaThresholds(t *testing.T) {
+	events := NewEventService()
+	service := NewNoteService(memory.NewRepository(), WithEventService(events))
+	ch := events.Subscribe()
+	defer events.Unsubscribe(ch)
+
+	alice := auth.NewContext(context.Background(), auth.Principal{UserID: "alice"})
+	alice = tenant.NewContext(alice, tenant.Settings{MaxNotes: 10, QuotaWarningPercents: []int{80, 95}})
+	create := func(titles ...string) []model.Warning {
+		ctx, warnings := svc.WithWarnings(alice)
+		batch := make([]model.Note, len(titles))
+		for i, title := range titles {
+			batch[i] = model.Note{Title: title}
+		}
+		results, err := service.BatchCreate(ctx, batch, false)
+		if err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+		for _, result := range results {
+			if result.Err != nil {
+				t.Fatalf("Expected note within quota, got: %v", result.Err)
+			}
+		}
+		return warnings.List()
+	}
+	quotaEvents := func() []Event {
+		var result []Event
+		for {
+			select {
+			case event := <-ch:
+				if event.Type == EventQuotaWarning {
+					result = append(result, event)
+				}
+			default:
+				return result
+			}
+		}
+	}
+
+	if warnings := create("1", "2", "3", "4", "5", "6", "7"); len(warnings) != 0 {
+		t.Errorf("Expected no warnings below the soft limit, got %+v", warnings)
+	}
+
+	// Пакет пересекает порог 80%: событие относится к восьмой заметке
+	warnings := create("8", "9")
+	if len(warnings) != 1 || warnings[0].Code != model.WarningQuotaSoftLimit {
+		t.Errorf("Expected QUOTA_SOFT_LIMIT warning, got %+v", warnings)
+	}
+	got := quotaEvents()
+	if len(got) != 1 || got[0].Quota != (model.QuotaUsage{Used: 9, Limit: 10, ThresholdPercent: 80}) || got[0].Note.Title != "8" {
+		t.Fatalf("Expected one quota warning event for 80%%, got %+v", got)
+	}
+
+	// Предупреждение повторяется выше порога, событие - только при пересечении следующего
+	ctx, collected := svc.WithWarnings(alice)
+	if _, err := service.Create(ctx, svc.CreateNoteInput{Title: "10"}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(collected.List()) != 1 {
+		t.Errorf("Expected QUOTA_SOFT_LIMIT warning, got %+v", collected.List())
+	}
+	if got := quotaEvents(); len(got) != 1 || got[0].Quota.ThresholdPercent != 95 || got[0].OwnerID() != "alice" {
+		t.Errorf("Expected one quota warning event for 95%%, got %+v", got)
+	}
+
+	if _, err := service.Create(alice, svc.CreateNoteInput{Title: "11"}); !errors.Is(err, ErrNoteQuotaExceeded) {
+		t.Errorf("Expected ErrNoteQuotaExceeded at the hard limit, got: %v", err)
+	}
+}
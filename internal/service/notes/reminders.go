@@ -0,0 +1,37 @@
+package notes
+
+import (
+	"notes-service/internal/model"
+)
+
+// ReminderScheduler планирует напоминания заметок (реализация - пакет reminders)
+// Сервис сообщает планировщику о каждом изменении заметки, чтобы тот не опрашивал хранилище
+type ReminderScheduler interface {
+	// Schedule планирует напоминание на note.RemindAt, заменяя прежнее
+	// Нулевое RemindAt отменяет напоминание заметки
+	Schedule(note model.Note)
+
+	// Cancel отменяет напоминание удаленной заметки
+	Cancel(noteID string)
+}
+
+// WithEventService задает сервис событий, общий с другими подсистемами (например, планировщиком напоминаний)
+func WithEventService(eventService EventBus) Option {
+	return func(s *service) {
+		s.eventService = eventService
+	}
+}
+
+// WithReminderScheduler подключает планировщик напоминаний
+func WithReminderScheduler(scheduler ReminderScheduler) Option {
+	return func(s *service) {
+		s.reminders = scheduler
+	}
+}
+
+// scheduleReminder передает планировщику актуальное время напоминания заметки
+func (s *service) scheduleReminder(note model.Note) {
+	if s.reminders != nil {
+		s.reminders.Schedule(note)
+	}
+}
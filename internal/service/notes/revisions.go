@@ -0,0 +1,151 @@
+package notes
+
+import (
+	"context"
+	"errors"
+
+	"notes-service/internal/diff"
+	"notes-service/internal/model"
+	svc "notes-service/internal/service"
+	"notes-service/internal/telemetry"
+)
+
+const (
+	// DefaultDiffContextLines количество строк контекста вокруг изменений в DiffRevisions по умолчанию
+	DefaultDiffContextLines = 3
+
+	// maxDiffLines максимальное количество строк содержимого каждой из сравниваемых ревизий
+	maxDiffLines = 20000
+
+	// maxDiffEdits максимальное количество добавленных и удаленных строк в диффе ревизий
+	maxDiffEdits = 2000
+)
+
+var (
+	// ErrDiffTooLarge возвращается, когда ревизии слишком велики или слишком различаются для построчного сравнения
+	ErrDiffTooLarge = errors.New("revisions are too large to diff")
+
+	// ErrEncryptedDiff возвращается при сравнении ревизий e2e заметки: сервер не видит их содержимое
+	ErrEncryptedDiff = errors.New("end-to-end encrypted revisions cannot be compared on the server")
+)
+
+// ListRevisions возвращает историю изменений заметки
+func (s *service) ListRevisions(ctx context.Context, id string) (_ []model.NoteRevision, err error) {
+	ctx, span := startSpan(ctx, "ListRevisions", noteIDAttribute(id))
+	defer func() { telemetry.EndSpan(span, err) }()
+	ctx = ownerScope(ctx)
+	if id == "" {
+		return nil, errors.New("id cannot be empty")
+	}
+
+	// Проверяем существование заметки, чтобы отличать "нет заметки" от "нет истории"
+	note, err := s.noteRepository.GetByID(ctx, id)
+	if err != nil {
+		return nil, accessError(ctx, s.noteRepository, id, err)
+	}
+	if err := s.checkPassphrase(ctx, note, model.AuditActionRevisions); err != nil {
+		return nil, err
+	}
+
+	revisions, err := s.revisionRepository.List(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return revisions, nil
+}
+
+// GetRevision возвращает конкретную ревизию заметки
+func (s *service) GetRevision(ctx context.Context, id string, revision int64) (_ model.NoteRevision, err error) {
+	ctx, span := startSpan(ctx, "GetRevision", noteIDAttribute(id))
+	defer func() { telemetry.EndSpan(span, err) }()
+	ctx = ownerScope(ctx)
+	if id == "" {
+		return model.NoteRevision{}, errors.New("id cannot be empty")
+	}
+	if revision < 1 {
+		return model.NoteRevision{}, errors.New("invalid revision number")
+	}
+
+	note, err := s.noteRepository.GetByID(ctx, id)
+	if err != nil {
+		return model.NoteRevision{}, accessError(ctx, s.noteRepository, id, err)
+	}
+	if err := s.checkPassphrase(ctx, note, model.AuditActionRevisions); err != nil {
+		return model.NoteRevision{}, err
+	}
+
+	rev, err := s.revisionRepository.Get(ctx, id, revision)
+	if err != nil {
+		return model.NoteRevision{}, err
+	}
+
+	return rev, nil
+}
+
+// DiffRevisions сравнивает содержимое двух ревизий заметки построчно
+func (s *service) DiffRevisions(ctx context.Context, input svc.DiffRevisionsInput) (_ svc.RevisionDiff, err error) {
+	ctx, span := startSpan(ctx, "DiffRevisions")
+	defer func() { telemetry.EndSpan(span, err) }()
+	ctx = ownerScope(ctx)
+	if input.ID == "" {
+		return svc.RevisionDiff{}, errors.New("id cannot be empty")
+	}
+	if input.FromRevision < 1 || input.ToRevision < 1 {
+		return svc.RevisionDiff{}, errors.New("invalid revision number")
+	}
+	if input.ContextLines < 0 {
+		return svc.RevisionDiff{}, errors.New("invalid number of context lines")
+	}
+
+	note, err := s.noteRepository.GetByID(ctx, input.ID)
+	if err != nil {
+		return svc.RevisionDiff{}, accessError(ctx, s.noteRepository, input.ID, err)
+	}
+	if err := s.checkPassphrase(ctx, note, model.AuditActionRevisions); err != nil {
+		return svc.RevisionDiff{}, err
+	}
+
+	from, err := s.revisionRepository.Get(ctx, input.ID, input.FromRevision)
+	if err != nil {
+		return svc.RevisionDiff{}, err
+	}
+	to, err := s.revisionRepository.Get(ctx, input.ID, input.ToRevision)
+	if err != nil {
+		return svc.RevisionDiff{}, err
+	}
+	if len(from.ContentEncrypted) > 0 || len(to.ContentEncrypted) > 0 {
+		return svc.RevisionDiff{}, ErrEncryptedDiff
+	}
+
+	fromLines, toLines := diff.Split(from.Content), diff.Split(to.Content)
+	if len(fromLines) > maxDiffLines || len(toLines) > maxDiffLines {
+		return svc.RevisionDiff{}, ErrDiffTooLarge
+	}
+	lines, err := diff.Lines(fromLines, toLines, maxDiffEdits)
+	if errors.Is(err, diff.ErrTooManyChanges) {
+		return svc.RevisionDiff{}, ErrDiffTooLarge
+	}
+	if err != nil {
+		return svc.RevisionDiff{}, err
+	}
+
+	result := svc.RevisionDiff{
+		NoteID:       input.ID,
+		FromRevision: from.Revision,
+		ToRevision:   to.Revision,
+		FromTitle:    from.Title,
+		ToTitle:      to.Title,
+		Hunks:        diff.Hunks(lines, input.ContextLines),
+	}
+	for _, line := range lines {
+		switch line.Op {
+		case diff.Insert:
+			result.LinesAdded++
+		case diff.Delete:
+			result.LinesRemoved++
+		}
+	}
+
+	return result, nil
+}
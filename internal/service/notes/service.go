@@ -3,48 +3,145 @@ package notes
 import (
 	"context"
 	"errors"
+	"fmt"
+	"slices"
 	"strings"
 	"time"
 
+	"notes-service/internal/collation"
 	"notes-service/internal/model"
 	"notes-service/internal/repository"
+	"notes-service/internal/repository/memory"
 	svc "notes-service/internal/service"
+	"notes-service/internal/telemetry"
 )
 
 var _ svc.NoteService = (*service)(nil)
 
 type service struct {
-	noteRepository repository.NoteRepository
-	eventService   *EventService
+	noteRepository       repository.NoteRepository
+	revisionRepository   repository.RevisionRepository
+	attachmentRepository repository.AttachmentRepository
+	shareRepository      repository.ShareRepository
+	users                UserDirectory // nil, если пользователи, получающие доступ, не проверяются
+	eventService         EventBus
+	idempotency          *idempotencyStore
+	reminders            ReminderScheduler // nil, если напоминания не планируются
+	locks                *lockStore
+	passphrases          *passphraseStore
+	audit                AuditLog
+	now                  func() time.Time
+}
+
+// Option настраивает дополнительные зависимости сервиса заметок
+type Option func(*service)
+
+// WithRevisionRepository задает хранилище истории изменений заметок
+func WithRevisionRepository(revisionRepository repository.RevisionRepository) Option {
+	return func(s *service) {
+		s.revisionRepository = revisionRepository
+	}
+}
+
+// WithAttachmentRepository задает хранилище вложений, очищаемое при удалении заметок
+func WithAttachmentRepository(attachmentRepository repository.AttachmentRepository) Option {
+	return func(s *service) {
+		s.attachmentRepository = attachmentRepository
+	}
+}
+
+// WithShareRepository задает хранилище доступов к заметкам других пользователей
+func WithShareRepository(shareRepository repository.ShareRepository) Option {
+	return func(s *service) {
+		s.shareRepository = shareRepository
+	}
+}
+
+// WithClock задает источник текущего времени сервиса (по умолчанию time.Now): время создания
+// и изменения заметок, доступов, аренда блокировок и срок хранения ключей идемпотентности
+// Хранилище может выставлять собственные метки (memory.Repository обновляет UpdatedAt само)
+func WithClock(now func() time.Time) Option {
+	return func(s *service) {
+		s.now = now
+		s.locks.now = now
+		s.passphrases.now = now
+		s.idempotency.now = now
+	}
 }
 
 // NewNoteService создает новый экземпляр сервиса для работы с заметками
-func NewNoteService(noteRepository repository.NoteRepository) svc.NoteService {
-	return &service{
+// Если хранилища ревизий и доступов не переданы через опции, используются in-memory реализации
+func NewNoteService(noteRepository repository.NoteRepository, opts ...Option) svc.NoteService {
+	s := &service{
 		noteRepository: noteRepository,
 		eventService:   NewEventService(),
+		idempotency:    newIdempotencyStore(),
+		locks:          newLockStore(),
+		passphrases:    newPassphraseStore(),
+		audit:          logAuditLog{},
+		now:            time.Now,
+	}
+	for _, opt := range opts {
+		opt(s)
 	}
+
+	if s.revisionRepository == nil {
+		s.revisionRepository = memory.NewRevisionRepository()
+	}
+	if s.shareRepository == nil {
+		s.shareRepository = memory.NewShareRepository()
+	}
+
+	return s
 }
 
 // GetEventService возвращает сервис событий для подписки
-func (s *service) GetEventService() *EventService {
+func (s *service) GetEventService() EventBus {
 	return s.eventService
 }
 
-// Create создает новую заметку с указанными title и content
-func (s *service) Create(ctx context.Context, title, content string) (model.Note, error) {
-	// Валидация: title не должен быть пустым
-	title = strings.TrimSpace(title)
-	if title == "" {
-		return model.Note{}, errors.New("title cannot be empty")
+// Create создает новую заметку согласно параметрам CreateNoteInput
+// Если задан IdempotencyKey, повтор запроса с тем же ключом возвращает исходную заметку
+func (s *service) Create(ctx context.Context, input svc.CreateNoteInput) (_ model.Note, err error) {
+	ctx, span := startSpan(ctx, "Create")
+	defer func() { telemetry.EndSpan(span, err) }()
+	ctx = ownerScope(ctx)
+	note, err := newNote(s.now(), model.Note{
+		Title:            input.Title,
+		Content:          input.Content,
+		Tags:             input.Tags,
+		IsE2E:            input.IsE2E,
+		E2EScheme:        input.E2EScheme,
+		ContentEncrypted: input.ContentEncrypted,
+		RemindAt:         input.RemindAt,
+	})
+	if err != nil {
+		return model.Note{}, err
 	}
+	svc.AddWarnings(ctx, noteWarnings(noteFields{
+		Title:    input.Title,
+		Content:  input.Content,
+		Tags:     input.Tags,
+		RemindAt: input.RemindAt,
+	}, note.CreatedAt)...)
 
-	// Создаем новую заметку
-	note := model.Note{
-		Title:     title,
-		Content:   strings.TrimSpace(content),
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
+	if input.IdempotencyKey != "" {
+		return s.idempotency.do(ctx, input.IdempotencyKey, note.ContentHash(), func() (model.Note, error) {
+			return s.create(ctx, note)
+		})
+	}
+
+	return s.create(ctx, note)
+}
+
+// create сохраняет подготовленную newNote заметку с учетом квоты тенанта
+func (s *service) create(ctx context.Context, note model.Note) (model.Note, error) {
+	remaining, err := s.remainingQuota(ctx)
+	if err != nil {
+		return model.Note{}, err
+	}
+	if remaining == 0 {
+		return model.Note{}, quotaError(ctx)
 	}
 
 	// Сохраняем через репозиторий (UUID будет сгенерирован в репозитории)
@@ -53,19 +150,84 @@ func (s *service) Create(ctx context.Context, title, content string) (model.Note
 		return model.Note{}, err
 	}
 
-	// Публикуем событие о создании заметки для подписчиков
-	s.eventService.Publish(createdNote)
+	if err := s.afterCreate(ctx, createdNote); err != nil {
+		return model.Note{}, err
+	}
+	s.checkSoftQuota(ctx, remaining, []model.Note{createdNote})
 
 	return createdNote, nil
 }
 
+// newNote валидирует входные данные и подготавливает новую заметку к сохранению
+// Зашифрованное содержимое e2e заметок сохраняется как есть, без обработки
+func newNote(now time.Time, input model.Note) (model.Note, error) {
+	// Создаем новую заметку
+	note := model.Note{
+		Title:            strings.TrimSpace(input.Title),
+		Content:          strings.TrimSpace(input.Content),
+		Tags:             model.NormalizeTags(input.Tags),
+		IsE2E:            input.IsE2E,
+		E2EScheme:        input.E2EScheme,
+		ContentEncrypted: input.ContentEncrypted,
+		RemindAt:         input.RemindAt,
+		CreatedAt:        now,
+		UpdatedAt:        now,
+	}
+
+	// Валидация: title не должен быть пустым, e2e заметка содержит только шифротекст
+	if err := note.Validate(); err != nil {
+		return model.Note{}, err
+	}
+	// Статистика считается до записи: хранилище может зашифровать содержание
+	note.UpdateTextStats()
+
+	return note, nil
+}
+
+// afterCreate выполняет побочные действия после сохранения новой заметки
+func (s *service) afterCreate(ctx context.Context, createdNote model.Note) error {
+	// Первая ревизия - исходное состояние заметки
+	if _, err := s.revisionRepository.Add(ctx, model.NewRevision(createdNote)); err != nil {
+		return err
+	}
+
+	// Публикуем событие о создании заметки для подписчиков
+	s.eventService.Publish(Event{Type: EventNoteCreated, Note: createdNote})
+	s.scheduleReminder(createdNote)
+
+	return nil
+}
+
 // Get возвращает заметку по её ID
-func (s *service) Get(ctx context.Context, id string) (model.Note, error) {
+// Заметку другого пользователя можно получить, если он поделился ею (см. Share)
+// Защищенная заметка возвращается только с верной парольной фразой (см. SetPassphrase)
+func (s *service) Get(ctx context.Context, id string) (_ model.Note, err error) {
+	ctx, span := startSpan(ctx, "Get", noteIDAttribute(id))
+	defer func() { telemetry.EndSpan(span, err) }()
+	note, err := s.get(ctx, id)
+	if err != nil {
+		return model.Note{}, err
+	}
+	if err := s.checkPassphrase(ctx, note, model.AuditActionGet); err != nil {
+		return model.Note{}, err
+	}
+
+	return note, nil
+}
+
+// get возвращает собственную или открытую на чтение заметку без проверки парольной фразы
+func (s *service) get(ctx context.Context, id string) (model.Note, error) {
+	ctx = ownerScope(ctx)
 	if id == "" {
 		return model.Note{}, errors.New("id cannot be empty")
 	}
 
 	note, err := s.noteRepository.GetByID(ctx, id)
+	if errors.Is(err, memory.ErrNoteNotFound) {
+		if ctx, err = s.sharedScope(ctx, id, model.SharePermissionRead); err == nil {
+			note, err = s.noteRepository.GetByID(ctx, id)
+		}
+	}
 	if err != nil {
 		return model.Note{}, err
 	}
@@ -73,44 +235,158 @@ func (s *service) Get(ctx context.Context, id string) (model.Note, error) {
 	return note, nil
 }
 
-// List возвращает список всех заметок
-func (s *service) List(ctx context.Context) ([]model.Note, error) {
-	notes, err := s.noteRepository.List(ctx)
+// List возвращает список всех заметок, закрепленные заметки идут первыми
+// Если задан opts.TitleCollation, заметки сортируются по заголовку с учетом правил языка,
+// opts.Order сортирует их по длине, а MinWordCount и MaxWordCount отбирают заметки по длине
+// Архивные заметки возвращаются только с opts.IncludeArchived
+func (s *service) List(ctx context.Context, opts svc.ListOptions) (_ []model.Note, err error) {
+	ctx, span := startSpan(ctx, "List")
+	defer func() { telemetry.EndSpan(span, err) }()
+	ctx = ownerScope(ctx)
+	comparators := []func(a, b model.Note) int{pinnedFirst}
+	switch opts.Order {
+	case svc.ListOrderWordCountAsc:
+		comparators = append(comparators, byWordCount)
+	case svc.ListOrderWordCountDesc:
+		comparators = append(comparators, func(a, b model.Note) int { return byWordCount(b, a) })
+	}
+	if opts.TitleCollation != "" {
+		byTitle, err := collation.TitleComparator(opts.TitleCollation)
+		if err != nil {
+			return nil, err
+		}
+		comparators = append(comparators, byTitle)
+	}
+	cmp := func(a, b model.Note) int {
+		for _, compare := range comparators {
+			if c := compare(a, b); c != 0 {
+				return c
+			}
+		}
+		return 0
+	}
+
+	var notes []model.Note
+	if lister, ok := s.noteRepository.(repository.SortedNoteLister); ok && len(comparators) > 1 {
+		notes, err = lister.ListSorted(ctx, cmp)
+	} else if notes, err = s.noteRepository.List(ctx); err == nil {
+		slices.SortStableFunc(notes, cmp)
+	}
 	if err != nil {
 		return nil, err
 	}
 
-	return notes, nil
+	if !opts.IncludeArchived {
+		notes = slices.DeleteFunc(notes, func(note model.Note) bool { return note.Archived })
+	}
+	return redactProtected(filterByWordCount(notes, opts.MinWordCount, opts.MaxWordCount)), nil
 }
 
-// Update обновляет заметку с указанным ID (title и content опциональны)
-func (s *service) Update(ctx context.Context, id, title, content string) (model.Note, error) {
-	if id == "" {
+// byWordCount упорядочивает заметки по возрастанию количества слов
+func byWordCount(a, b model.Note) int {
+	return a.WordCount - b.WordCount
+}
+
+// filterByWordCount оставляет заметки, количество слов которых в границах [minWords, maxWords] (0 - без ограничения)
+func filterByWordCount(notes []model.Note, minWords, maxWords int) []model.Note {
+	if minWords <= 0 && maxWords <= 0 {
+		return notes
+	}
+	return slices.DeleteFunc(notes, func(note model.Note) bool {
+		return (minWords > 0 && note.WordCount < minWords) || (maxWords > 0 && note.WordCount > maxWords)
+	})
+}
+
+// ForEach обходит все заметки порциями по batchSize, не загружая весь список в память
+// Если хранилище не поддерживает постраничный обход, используется List
+func (s *service) ForEach(ctx context.Context, batchSize int, fn func(model.Note) error) error {
+	return s.ForEachAfter(ctx, "", batchSize, fn)
+}
+
+// ForEachAfter обходит заметки в порядке возрастания ID, начиная после курсора after
+// Курсор - ID последней полученной клиентом заметки, по нему можно продолжить прерванный обход.
+// Как и List, обход не принимает парольную фразу: содержимое защищенных заметок убирается
+func (s *service) ForEachAfter(ctx context.Context, after string, batchSize int, fn func(model.Note) error) error {
+	ctx = ownerScope(ctx)
+	visit := fn
+	fn = func(note model.Note) error { return visit(RedactNote(note)) }
+	if iterator, ok := s.noteRepository.(repository.NoteIterator); ok {
+		return iterator.ForEachAfter(ctx, after, batchSize, fn)
+	}
+
+	notes, err := s.noteRepository.List(ctx)
+	if err != nil {
+		return err
+	}
+	slices.SortFunc(notes, func(a, b model.Note) int {
+		return strings.Compare(a.ID, b.ID)
+	})
+
+	for _, note := range notes {
+		if note.ID <= after {
+			continue
+		}
+		if err := fn(note); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Update обновляет заметку согласно параметрам UpdateNoteInput
+// Заметку другого пользователя можно изменить при доступе на запись (см. Share)
+func (s *service) Update(ctx context.Context, input svc.UpdateNoteInput) (_ model.Note, err error) {
+	ctx, span := startSpan(ctx, "Update", noteIDAttribute(input.ID))
+	defer func() { telemetry.EndSpan(span, err) }()
+	ctx = ownerScope(ctx)
+	if input.ID == "" {
 		return model.Note{}, errors.New("id cannot be empty")
 	}
 
 	// Получаем существующую заметку
-	existingNote, err := s.noteRepository.GetByID(ctx, id)
+	ctx, existingNote, err := s.writableNote(ctx, input.ID)
 	if err != nil {
 		return model.Note{}, err
 	}
 
-	// Обновляем поля только если они переданы (не пустые после TrimSpace)
-	titleTrimmed := strings.TrimSpace(title)
-	if titleTrimmed != "" {
-		existingNote.Title = titleTrimmed
+	if err := s.checkPassphrase(ctx, existingNote, model.AuditActionUpdate); err != nil {
+		return model.Note{}, err
 	}
 
-	// Content всегда обновляется, даже если пустой
-	existingNote.Content = strings.TrimSpace(content)
+	// Заметку, заблокированную другим пользователем, изменять нельзя
+	if err := s.checkLock(ctx, input.ID); err != nil {
+		return model.Note{}, err
+	}
+
+	originalNote := existingNote
+	if err := applyUpdate(&existingNote, input); err != nil {
+		return model.Note{}, err
+	}
 
 	// Валидация обновленной заметки
 	if err := existingNote.Validate(); err != nil {
 		return model.Note{}, err
 	}
+	existingNote.UpdateTextStats()
+	svc.AddWarnings(ctx, noteWarnings(updateFields(input), s.now())...)
+
+	// Обновление без изменений не записывается и не создает ревизию, если не запрошено принудительно
+	if !input.Force && existingNote.ContentHash() == originalNote.ContentHash() {
+		if input.Version != 0 && input.Version != originalNote.Version {
+			return model.Note{}, fmt.Errorf("%w: expected version %d, current version %d",
+				memory.ErrVersionConflict, input.Version, originalNote.Version)
+		}
+		return originalNote, nil
+	}
 
 	// Обновляем временную метку
-	existingNote.UpdatedAt = time.Now()
+	existingNote.UpdatedAt = s.now()
+
+	// Передаем ожидаемую клиентом версию, репозиторий отклонит устаревшее обновление
+	if input.Version != 0 {
+		existingNote.Version = input.Version
+	}
 
 	// Сохраняем через репозиторий
 	updatedNote, err := s.noteRepository.Update(ctx, existingNote)
@@ -118,19 +394,146 @@ func (s *service) Update(ctx context.Context, id, title, content string) (model.
 		return model.Note{}, err
 	}
 
+	// Сохраняем новое состояние заметки в истории изменений
+	if _, err := s.revisionRepository.Add(ctx, model.NewRevision(updatedNote)); err != nil {
+		return model.Note{}, err
+	}
+	s.scheduleReminder(updatedNote)
+	s.eventService.Publish(Event{Type: EventNoteUpdated, Note: updatedNote, Previous: originalNote})
+
 	return updatedNote, nil
 }
 
+// writableNote возвращает заметку, которую вызывающий пользователь может изменять:
+// собственную или открытую ему на запись. Возвращаемый контекст ограничен владельцем заметки
+func (s *service) writableNote(ctx context.Context, id string) (context.Context, model.Note, error) {
+	note, err := s.noteRepository.GetByID(ctx, id)
+	if errors.Is(err, memory.ErrNoteNotFound) {
+		if ctx, err = s.sharedScope(ctx, id, model.SharePermissionWrite); err == nil {
+			note, err = s.noteRepository.GetByID(ctx, id)
+		}
+	}
+	if err != nil {
+		return nil, model.Note{}, err
+	}
+
+	return ctx, note, nil
+}
+
+// applyUpdate применяет изменения из input к заметке
+// С маской обновляются ровно перечисленные поля: так можно явно очистить content или теги
+// или изменить только title. Без маски сохраняется прежнее поведение
+func applyUpdate(note *model.Note, input svc.UpdateNoteInput) error {
+	if len(input.UpdateMask) == 0 {
+		// Обновляем поля только если они переданы (не пустые после TrimSpace)
+		titleTrimmed := strings.TrimSpace(input.Title)
+		if titleTrimmed != "" {
+			note.Title = titleTrimmed
+		}
+
+		// Content всегда обновляется, даже если пустой
+		note.Content = strings.TrimSpace(input.Content)
+
+		// Теги заменяются только если переданы
+		if len(input.Tags) > 0 {
+			note.Tags = model.NormalizeTags(input.Tags)
+		}
+
+		// Зашифрованное содержимое заменяется только если передано
+		if len(input.ContentEncrypted) > 0 {
+			note.ContentEncrypted = input.ContentEncrypted
+		}
+
+		// Время напоминания заменяется только если передано
+		if !input.RemindAt.IsZero() {
+			note.RemindAt = input.RemindAt
+		}
+		return nil
+	}
+
+	for _, path := range input.UpdateMask {
+		switch path {
+		case svc.UpdateMaskTitle:
+			note.Title = strings.TrimSpace(input.Title)
+		case svc.UpdateMaskContent:
+			note.Content = strings.TrimSpace(input.Content)
+		case svc.UpdateMaskTags:
+			note.Tags = model.NormalizeTags(input.Tags)
+		case svc.UpdateMaskContentEncrypted:
+			note.ContentEncrypted = input.ContentEncrypted
+		case svc.UpdateMaskRemindAt:
+			note.RemindAt = input.RemindAt
+		default:
+			return fmt.Errorf("invalid update_mask path %q", path)
+		}
+	}
+
+	return nil
+}
+
 // Delete удаляет заметку по ID
-func (s *service) Delete(ctx context.Context, id string) error {
+// Защищенную заметку можно удалить только с парольной фразой
+func (s *service) Delete(ctx context.Context, id string) (err error) {
+	ctx, span := startSpan(ctx, "Delete", noteIDAttribute(id))
+	defer func() { telemetry.EndSpan(span, err) }()
+	ctx = ownerScope(ctx)
 	if id == "" {
 		return errors.New("id cannot be empty")
 	}
 
-	err := s.noteRepository.Delete(ctx, id)
+	if err := s.checkDelete(ctx, id); err != nil {
+		return err
+	}
+
+	err = s.noteRepository.Delete(ctx, id)
 	if err != nil {
+		return accessError(ctx, s.noteRepository, id, err)
+	}
+
+	return s.afterDelete(ctx, id)
+}
+
+// checkDelete проверяет, что вызывающий пользователь может удалить заметку владельца:
+// фразу защищенной заметки и блокировку другого пользователя
+func (s *service) checkDelete(ctx context.Context, id string) error {
+	note, err := s.noteRepository.GetByID(ctx, id)
+	if err != nil {
+		return accessError(ctx, s.noteRepository, id, err)
+	}
+	if err := s.checkPassphrase(ctx, note, model.AuditActionDelete); err != nil {
+		return err
+	}
+	return s.checkLock(ctx, id)
+}
+
+// afterDelete удаляет данные, связанные с удаленной заметкой
+func (s *service) afterDelete(ctx context.Context, id string) error {
+	// История изменений удаленной заметки больше не нужна
+	if err := s.revisionRepository.DeleteByNoteID(ctx, id); err != nil {
 		return err
 	}
 
+	// Доступы других пользователей к удаленной заметке отзываются
+	if err := s.shareRepository.DeleteByNoteID(ctx, id); err != nil {
+		return err
+	}
+
+	if s.reminders != nil {
+		s.reminders.Cancel(id)
+	}
+	s.locks.drop(id)
+	s.passphrases.reset(id)
+
+	// Удаление возможно только в рамках владельца, поэтому событие адресуется ему
+	ownerID, _ := repository.OwnerFromContext(ctx)
+	s.eventService.Publish(Event{Type: EventNoteDeleted, Note: model.Note{ID: id, OwnerID: ownerID}})
+
+	// Вложения удаляются, только если хранилище вложений подключено
+	if s.attachmentRepository != nil {
+		if err := s.attachmentRepository.DeleteByNoteID(ctx, id); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
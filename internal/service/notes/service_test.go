@@ -3,12 +3,16 @@ package notes
 import (
 	"context"
 	"errors"
+	"fmt"
+	"slices"
+	"strings"
 	"testing"
 	"time"
 
 	"notes-service/internal/model"
 	"notes-service/internal/repository"
 	"notes-service/internal/repository/memory"
+	svc "notes-service/internal/service"
 )
 
 // mockRepository - простой mock репозитория для тестирования
@@ -21,6 +25,7 @@ type mockRepository struct {
 	deleteError    error
 	shouldFailGet  bool
 	shouldFailList bool
+	createdCount   int
 }
 
 func newMockRepository() *mockRepository {
@@ -35,8 +40,10 @@ func (m *mockRepository) Create(ctx context.Context, note model.Note) (model.Not
 	}
 
 	// Генерируем ID если его нет (для тестов)
+	// Счетчик исключает совпадение ID при нескольких созданиях в пределах секунды
+	m.createdCount++
 	if note.ID == "" {
-		note.ID = "test-id-" + time.Now().Format("20060102150405")
+		note.ID = fmt.Sprintf("test-id-%s-%d", time.Now().Format("20060102150405"), m.createdCount)
 	}
 
 	note.CreatedAt = time.Now()
@@ -117,7 +124,7 @@ func TestNoteService_Create_Success(t *testing.T) {
 	title := "Test Note"
 	content := "Test Content"
 
-	note, err := service.Create(ctx, title, content)
+	note, err := service.Create(ctx, svc.CreateNoteInput{Title: title, Content: content})
 	if err != nil {
 		t.Fatalf("Expected no error, got: %v", err)
 	}
@@ -148,7 +155,7 @@ func TestNoteService_Create_EmptyTitle(t *testing.T) {
 	mockRepo := newMockRepository()
 	service := NewNoteService(mockRepo)
 
-	note, err := service.Create(ctx, "", "content")
+	note, err := service.Create(ctx, svc.CreateNoteInput{Content: "content"})
 
 	if err == nil {
 		t.Error("Expected error for empty title")
@@ -168,7 +175,7 @@ func TestNoteService_Create_WhitespaceTitle(t *testing.T) {
 	mockRepo := newMockRepository()
 	service := NewNoteService(mockRepo)
 
-	note, err := service.Create(ctx, "   ", "content")
+	note, err := service.Create(ctx, svc.CreateNoteInput{Title: "   ", Content: "content"})
 
 	if err == nil {
 		t.Error("Expected error for whitespace-only title")
@@ -187,7 +194,7 @@ func TestNoteService_Create_TrimsContent(t *testing.T) {
 	title := "Test Note"
 	content := "  Test Content  "
 
-	note, err := service.Create(ctx, title, content)
+	note, err := service.Create(ctx, svc.CreateNoteInput{Title: title, Content: content})
 	if err != nil {
 		t.Fatalf("Expected no error, got: %v", err)
 	}
@@ -289,7 +296,7 @@ func TestNoteService_List_Success(t *testing.T) {
 	mockRepo.notes["id-1"] = note1
 	mockRepo.notes["id-2"] = note2
 
-	notes, err := service.List(ctx)
+	notes, err := service.List(ctx, svc.ListOptions{})
 	if err != nil {
 		t.Fatalf("Expected no error, got: %v", err)
 	}
@@ -304,7 +311,7 @@ func TestNoteService_List_Empty(t *testing.T) {
 	mockRepo := newMockRepository()
 	service := NewNoteService(mockRepo)
 
-	notes, err := service.List(ctx)
+	notes, err := service.List(ctx, svc.ListOptions{})
 	if err != nil {
 		t.Fatalf("Expected no error, got: %v", err)
 	}
@@ -333,7 +340,7 @@ func TestNoteService_Update_Success(t *testing.T) {
 	newTitle := "Updated Title"
 	newContent := "Updated Content"
 
-	updatedNote, err := service.Update(ctx, "test-id", newTitle, newContent)
+	updatedNote, err := service.Update(ctx, svc.UpdateNoteInput{ID: "test-id", Title: newTitle, Content: newContent})
 	if err != nil {
 		t.Fatalf("Expected no error, got: %v", err)
 	}
@@ -360,7 +367,7 @@ func TestNoteService_Update_EmptyID(t *testing.T) {
 	mockRepo := newMockRepository()
 	service := NewNoteService(mockRepo)
 
-	note, err := service.Update(ctx, "", "title", "content")
+	note, err := service.Update(ctx, svc.UpdateNoteInput{ID: "", Title: "title", Content: "content"})
 
 	if err == nil {
 		t.Error("Expected error for empty ID")
@@ -380,7 +387,7 @@ func TestNoteService_Update_NotFound(t *testing.T) {
 	mockRepo := newMockRepository()
 	service := NewNoteService(mockRepo)
 
-	note, err := service.Update(ctx, "non-existent-id", "title", "content")
+	note, err := service.Update(ctx, svc.UpdateNoteInput{ID: "non-existent-id", Title: "title", Content: "content"})
 
 	if err == nil {
 		t.Error("Expected error for non-existent note")
@@ -413,7 +420,7 @@ func TestNoteService_Update_PartialUpdate(t *testing.T) {
 	// Обновляем только title, content оставляем пустым
 	newTitle := "Updated Title"
 
-	updatedNote, err := service.Update(ctx, "test-id", newTitle, "")
+	updatedNote, err := service.Update(ctx, svc.UpdateNoteInput{ID: "test-id", Title: newTitle})
 	if err != nil {
 		t.Fatalf("Expected no error, got: %v", err)
 	}
@@ -444,7 +451,7 @@ func TestNoteService_Update_EmptyTitleAfterTrim(t *testing.T) {
 	mockRepo.notes["test-id"] = testNote
 
 	// Пытаемся обновить с пустым title после trim (только пробелы)
-	note, err := service.Update(ctx, "test-id", "   ", "content")
+	note, err := service.Update(ctx, svc.UpdateNoteInput{ID: "test-id", Title: "   ", Content: "content"})
 	// Это должно пройти, так как пустой title не обновляется, остается оригинальный
 	// Но если мы передадим только пробелы как title и это приведет к пустому title после trim,
 	// то валидация должна сработать
@@ -484,7 +491,7 @@ func TestNoteService_Update_OnlyContent(t *testing.T) {
 	// Обновляем только content (передаем пустой title, который не обновится)
 	newContent := "Only Content Updated"
 
-	updatedNote, err := service.Update(ctx, "test-id", "", newContent)
+	updatedNote, err := service.Update(ctx, svc.UpdateNoteInput{ID: "test-id", Content: newContent})
 	if err != nil {
 		t.Fatalf("Expected no error, got: %v", err)
 	}
@@ -559,3 +566,589 @@ func TestNoteService_Delete_NotFound(t *testing.T) {
 		t.Errorf("Expected ErrNoteNotFound, got: %v", err)
 	}
 }
+
+func TestNoteService_Revisions_RecordedOnCreateAndUpdate(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := newMockRepository()
+	service := NewNoteService(mockRepo, WithRevisionRepository(memory.NewRevisionRepository()))
+
+	note, err := service.Create(ctx, svc.CreateNoteInput{Title: "Original Title", Content: "Original Content"})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if _, err := service.Update(ctx, svc.UpdateNoteInput{ID: note.ID, Title: "Updated Title", Content: "Updated Content"}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	revisions, err := service.ListRevisions(ctx, note.ID)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if len(revisions) != 2 {
+		t.Fatalf("Expected 2 revisions, got %d", len(revisions))
+	}
+
+	if revisions[0].Revision != 1 || revisions[0].Title != "Original Title" {
+		t.Errorf("Expected first revision to hold original title, got %+v", revisions[0])
+	}
+
+	if revisions[1].Revision != 2 || revisions[1].Content != "Updated Content" {
+		t.Errorf("Expected second revision to hold updated content, got %+v", revisions[1])
+	}
+
+	rev, err := service.GetRevision(ctx, note.ID, 1)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if rev.Content != "Original Content" {
+		t.Errorf("Expected content %q, got %q", "Original Content", rev.Content)
+	}
+}
+
+func TestNoteService_GetRevision_NotFound(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := newMockRepository()
+	service := NewNoteService(mockRepo)
+
+	note, err := service.Create(ctx, svc.CreateNoteInput{Title: "Test Note", Content: "Test Content"})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	_, err = service.GetRevision(ctx, note.ID, 5)
+	if !errors.Is(err, memory.ErrRevisionNotFound) {
+		t.Errorf("Expected ErrRevisionNotFound, got: %v", err)
+	}
+}
+
+func TestNoteService_ListRevisions_NoteNotFound(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := newMockRepository()
+	service := NewNoteService(mockRepo)
+
+	_, err := service.ListRevisions(ctx, "non-existent-id")
+	if !errors.Is(err, memory.ErrNoteNotFound) {
+		t.Errorf("Expected ErrNoteNotFound, got: %v", err)
+	}
+}
+
+func TestNoteService_DiffRevisions(t *testing.T) {
+	ctx := context.Background()
+	service := NewNoteService(newMockRepository(), WithRevisionRepository(memory.NewRevisionRepository()))
+
+	note, err := service.Create(ctx, svc.CreateNoteInput{Title: "Shopping list", Content: "milk\nbread\neggs\ncheese"})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if _, err := service.Update(ctx, svc.UpdateNoteInput{ID: note.ID, Title: "Groceries", Content: "milk\nbutter\neggs\ncheese\napples"}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	result, err := service.DiffRevisions(ctx, svc.DiffRevisionsInput{ID: note.ID, FromRevision: 1, ToRevision: 2})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if result.FromTitle != "Shopping list" || result.ToTitle != "Groceries" {
+		t.Errorf("Expected titles of both revisions, got %q and %q", result.FromTitle, result.ToTitle)
+	}
+	if result.LinesAdded != 2 || result.LinesRemoved != 1 {
+		t.Errorf("Expected 2 added and 1 removed lines, got %d and %d", result.LinesAdded, result.LinesRemoved)
+	}
+	if len(result.Hunks) != 2 {
+		t.Errorf("Expected 2 hunks without context lines, got %+v", result.Hunks)
+	}
+
+	// В обратную сторону добавленные строки становятся удаленными
+	reverse, err := service.DiffRevisions(ctx, svc.DiffRevisionsInput{ID: note.ID, FromRevision: 2, ToRevision: 1})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if reverse.LinesAdded != 1 || reverse.LinesRemoved != 2 {
+		t.Errorf("Expected 1 added and 2 removed lines, got %d and %d", reverse.LinesAdded, reverse.LinesRemoved)
+	}
+
+	if _, err := service.DiffRevisions(ctx, svc.DiffRevisionsInput{ID: note.ID, FromRevision: 1, ToRevision: 3}); !errors.Is(err, memory.ErrRevisionNotFound) {
+		t.Errorf("Expected ErrRevisionNotFound, got: %v", err)
+	}
+	if _, err := service.DiffRevisions(ctx, svc.DiffRevisionsInput{ID: note.ID, FromRevision: 0, ToRevision: 1}); err == nil {
+		t.Error("Expected error for invalid revision number")
+	}
+
+	// Слишком много изменений
+	long := strings.Repeat("line\n", maxDiffEdits+1)
+	if _, err := service.Update(ctx, svc.UpdateNoteInput{ID: note.ID, Content: long}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if _, err := service.DiffRevisions(ctx, svc.DiffRevisionsInput{ID: note.ID, FromRevision: 1, ToRevision: 3}); !errors.Is(err, ErrDiffTooLarge) {
+		t.Errorf("Expected ErrDiffTooLarge, got: %v", err)
+	}
+}
+
+func TestNoteService_Delete_RemovesRevisions(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := newMockRepository()
+	revisionRepo := memory.NewRevisionRepository()
+	service := NewNoteService(mockRepo, WithRevisionRepository(revisionRepo))
+
+	note, err := service.Create(ctx, svc.CreateNoteInput{Title: "Test Note", Content: "Test Content"})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if err := service.Delete(ctx, note.ID); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	revisions, err := revisionRepo.List(ctx, note.ID)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if len(revisions) != 0 {
+		t.Errorf("Expected revisions to be deleted, got %d", len(revisions))
+	}
+}
+
+func TestNoteService_Update_VersionConflict(t *testing.T) {
+	ctx := context.Background()
+	service := NewNoteService(memory.NewRepository())
+
+	note, err := service.Create(ctx, svc.CreateNoteInput{Title: "Original Title", Content: "Original Content"})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if note.Version != 1 {
+		t.Fatalf("Expected initial version 1, got %d", note.Version)
+	}
+
+	// Первый клиент обновляет заметку, зная актуальную версию
+	updated, err := service.Update(ctx, svc.UpdateNoteInput{ID: note.ID, Title: "First Writer", Content: "Content", Version: note.Version})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if updated.Version != 2 {
+		t.Errorf("Expected version 2 after update, got %d", updated.Version)
+	}
+
+	// Второй клиент пытается обновить заметку по устаревшей версии
+	_, err = service.Update(ctx, svc.UpdateNoteInput{ID: note.ID, Title: "Second Writer", Content: "Content", Version: note.Version})
+	if !errors.Is(err, memory.ErrVersionConflict) {
+		t.Fatalf("Expected ErrVersionConflict, got: %v", err)
+	}
+
+	current, err := service.Get(ctx, note.ID)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if current.Title != "First Writer" {
+		t.Errorf("Expected stale update to be rejected, got title %q", current.Title)
+	}
+}
+
+func TestNoteService_BatchCreate_PartialFailure(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := newMockRepository()
+	service := NewNoteService(mockRepo)
+
+	results, err := service.BatchCreate(ctx, []model.Note{
+		{Title: "First Note", Content: "First Content"},
+		{Title: "   ", Content: "Invalid"},
+	}, false)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if len(results) != 2 {
+		t.Fatalf("Expected 2 results, got %d", len(results))
+	}
+
+	if results[0].Err != nil || results[0].Note.Title != "First Note" {
+		t.Errorf("Expected first note to be created, got %+v", results[0])
+	}
+
+	if results[1].Err == nil {
+		t.Error("Expected second note to fail validation")
+	}
+
+	if len(mockRepo.notes) != 1 {
+		t.Errorf("Expected 1 stored note, got %d", len(mockRepo.notes))
+	}
+}
+
+func TestNoteService_BatchCreate_AtomicRejectsWholeBatch(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.NewRepository()
+	service := NewNoteService(repo)
+
+	_, err := service.BatchCreate(ctx, []model.Note{
+		{Title: "First Note", Content: "First Content"},
+		{Title: "", Content: "Invalid"},
+	}, true)
+	if err == nil {
+		t.Fatal("Expected atomic batch to fail")
+	}
+
+	notes, _ := repo.List(ctx)
+	if len(notes) != 0 {
+		t.Errorf("Expected no notes to be stored, got %d", len(notes))
+	}
+}
+
+func TestNoteService_BatchCreate_AtomicNotSupported(t *testing.T) {
+	ctx := context.Background()
+	service := NewNoteService(newMockRepository())
+
+	_, err := service.BatchCreate(ctx, []model.Note{{Title: "Note", Content: "Content"}}, true)
+	if !errors.Is(err, ErrAtomicBatchNotSupported) {
+		t.Errorf("Expected ErrAtomicBatchNotSupported, got: %v", err)
+	}
+}
+
+func TestNoteService_BatchDelete_Atomic(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.NewRepository()
+	service := NewNoteService(repo)
+
+	note, err := service.Create(ctx, svc.CreateNoteInput{Title: "Test Note", Content: "Test Content"})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	// Одна из заметок не существует - атомарное удаление не должно затронуть остальные
+	_, err = service.BatchDelete(ctx, []string{note.ID, "non-existent-id"}, true)
+	if !errors.Is(err, memory.ErrNoteNotFound) {
+		t.Fatalf("Expected ErrNoteNotFound, got: %v", err)
+	}
+
+	if _, err := service.Get(ctx, note.ID); err != nil {
+		t.Errorf("Expected note to survive failed atomic delete, got: %v", err)
+	}
+
+	results, err := service.BatchDelete(ctx, []string{note.ID}, true)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if len(results) != 1 || results[0].Err != nil {
+		t.Errorf("Expected successful delete result, got %+v", results)
+	}
+}
+
+func TestNoteService_Update_WithMaskClearsContent(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := newMockRepository()
+	service := NewNoteService(mockRepo)
+
+	mockRepo.notes["test-id"] = model.Note{
+		ID:      "test-id",
+		Title:   "Original Title",
+		Content: "Original Content",
+	}
+
+	// Маска только с content: пустая строка явно очищает содержание, title не меняется
+	updatedNote, err := service.Update(ctx, svc.UpdateNoteInput{
+		ID:         "test-id",
+		Title:      "Ignored Title",
+		UpdateMask: []string{svc.UpdateMaskContent},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if updatedNote.Title != "Original Title" {
+		t.Errorf("Expected title to remain 'Original Title', got %q", updatedNote.Title)
+	}
+
+	if updatedNote.Content != "" {
+		t.Errorf("Expected content to be cleared, got %q", updatedNote.Content)
+	}
+}
+
+func TestNoteService_Update_WithMaskKeepsContent(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := newMockRepository()
+	service := NewNoteService(mockRepo)
+
+	mockRepo.notes["test-id"] = model.Note{
+		ID:      "test-id",
+		Title:   "Original Title",
+		Content: "Original Content",
+	}
+
+	// Маска только с title: content не передан и не должен быть очищен
+	updatedNote, err := service.Update(ctx, svc.UpdateNoteInput{
+		ID:         "test-id",
+		Title:      "Updated Title",
+		UpdateMask: []string{svc.UpdateMaskTitle},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if updatedNote.Title != "Updated Title" {
+		t.Errorf("Expected title %q, got %q", "Updated Title", updatedNote.Title)
+	}
+
+	if updatedNote.Content != "Original Content" {
+		t.Errorf("Expected content to remain 'Original Content', got %q", updatedNote.Content)
+	}
+}
+
+func TestNoteService_Update_WithMaskRejectsEmptyTitle(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := newMockRepository()
+	service := NewNoteService(mockRepo)
+
+	mockRepo.notes["test-id"] = model.Note{ID: "test-id", Title: "Original Title"}
+
+	_, err := service.Update(ctx, svc.UpdateNoteInput{
+		ID:         "test-id",
+		UpdateMask: []string{svc.UpdateMaskTitle},
+	})
+	if err == nil || err.Error() != "title cannot be empty" {
+		t.Errorf("Expected 'title cannot be empty', got: %v", err)
+	}
+}
+
+func TestNoteService_Update_WithMaskUnknownPath(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := newMockRepository()
+	service := NewNoteService(mockRepo)
+
+	mockRepo.notes["test-id"] = model.Note{ID: "test-id", Title: "Original Title"}
+
+	_, err := service.Update(ctx, svc.UpdateNoteInput{
+		ID:         "test-id",
+		UpdateMask: []string{"created_at"},
+	})
+	if err == nil {
+		t.Fatal("Expected error for unknown update_mask path")
+	}
+}
+
+func TestNoteService_Update_NoOpSkipsWrite(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := newMockRepository()
+	revisions := memory.NewRevisionRepository()
+	service := NewNoteService(mockRepo, WithRevisionRepository(revisions))
+
+	note, err := service.Create(ctx, svc.CreateNoteInput{Title: "Title", Content: "Content"})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	// Те же title и content (с учетом TrimSpace) - запись не выполняется
+	updatedNote, err := service.Update(ctx, svc.UpdateNoteInput{ID: note.ID, Title: " Title ", Content: "Content"})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if !updatedNote.UpdatedAt.Equal(note.UpdatedAt) {
+		t.Errorf("Expected UpdatedAt to stay %v, got %v", note.UpdatedAt, updatedNote.UpdatedAt)
+	}
+
+	history, err := revisions.List(ctx, note.ID)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(history) != 1 {
+		t.Errorf("Expected 1 revision after no-op update, got %d", len(history))
+	}
+}
+
+func TestNoteService_Update_ForceTouch(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := newMockRepository()
+	revisions := memory.NewRevisionRepository()
+	service := NewNoteService(mockRepo, WithRevisionRepository(revisions))
+
+	note, err := service.Create(ctx, svc.CreateNoteInput{Title: "Title", Content: "Content"})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	_, err = service.Update(ctx, svc.UpdateNoteInput{ID: note.ID, Title: "Title", Content: "Content", Force: true})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	history, err := revisions.List(ctx, note.ID)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(history) != 2 {
+		t.Errorf("Expected 2 revisions after forced update, got %d", len(history))
+	}
+}
+
+func TestNoteService_List_TitleCollation(t *testing.T) {
+	ctx := context.Background()
+	service := NewNoteService(memory.NewRepository())
+
+	for _, title := range []string{"Жук", "арбуз", "Елена"} {
+		if _, err := service.Create(ctx, svc.CreateNoteInput{Title: title}); err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+	}
+
+	notes, err := service.List(ctx, svc.ListOptions{TitleCollation: "ru"})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	expected := []string{"арбуз", "Елена", "Жук"}
+	for i, note := range notes {
+		if note.Title != expected[i] {
+			t.Errorf("Expected title %q at position %d, got %q", expected[i], i, note.Title)
+		}
+	}
+}
+
+func TestNoteService_List_WordCount(t *testing.T) {
+	ctx := context.Background()
+	service := NewNoteService(memory.NewRepository())
+
+	contents := map[string]string{
+		"short":  "one",
+		"medium": "one two\nthree",
+		"long":   strings.Repeat("word ", 400),
+	}
+	for title, content := range contents {
+		if _, err := service.Create(ctx, svc.CreateNoteInput{Title: title, Content: content}); err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+	}
+
+	notes, err := service.List(ctx, svc.ListOptions{Order: svc.ListOrderWordCountDesc, MinWordCount: 2})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(notes) != 2 || notes[0].Title != "long" || notes[1].Title != "medium" {
+		t.Fatalf("Expected long and medium notes, got %+v", notes)
+	}
+	if notes[0].WordCount != 400 || notes[0].ReadingTime != 2*time.Minute {
+		t.Errorf("Expected 400 words and 2m reading time, got %d and %v", notes[0].WordCount, notes[0].ReadingTime)
+	}
+	if notes[1].WordCount != 3 || notes[1].ReadingTime != time.Second {
+		t.Errorf("Expected 3 words and 1s reading time, got %d and %v", notes[1].WordCount, notes[1].ReadingTime)
+	}
+
+	// Статистика пересчитывается при изменении содержания
+	updated, err := service.Update(ctx, svc.UpdateNoteInput{ID: notes[1].ID, Content: "one two three four five"})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if updated.WordCount != 5 {
+		t.Errorf("Expected 5 words after update, got %d", updated.WordCount)
+	}
+}
+
+func TestNoteService_List_InvalidCollation(t *testing.T) {
+	ctx := context.Background()
+	service := NewNoteService(newMockRepository())
+
+	if _, err := service.List(ctx, svc.ListOptions{TitleCollation: "not a locale!"}); err == nil {
+		t.Error("Expected error for invalid collation locale")
+	}
+}
+
+func TestNoteService_Create_NormalizesTags(t *testing.T) {
+	ctx := context.Background()
+	service := NewNoteService(newMockRepository())
+
+	note, err := service.Create(ctx, svc.CreateNoteInput{
+		Title: "Tagged",
+		Tags:  []string{" Work ", "go", "work", ""},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	expected := []string{"go", "work"}
+	if !slices.Equal(note.Tags, expected) {
+		t.Errorf("Expected tags %v, got %v", expected, note.Tags)
+	}
+}
+
+func TestNoteService_ListByTag(t *testing.T) {
+	ctx := context.Background()
+	service := NewNoteService(memory.NewRepository())
+
+	work, err := service.Create(ctx, svc.CreateNoteInput{Title: "Work note", Tags: []string{"work"}})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if _, err := service.Create(ctx, svc.CreateNoteInput{Title: "Home note", Tags: []string{"home"}}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	notes, err := service.ListByTag(ctx, "WORK")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(notes) != 1 || notes[0].ID != work.ID {
+		t.Fatalf("Expected only work note, got %v", notes)
+	}
+
+	// После замены тегов через маску заметка пропадает из индекса
+	if _, err := service.Update(ctx, svc.UpdateNoteInput{
+		ID:         work.ID,
+		UpdateMask: []string{svc.UpdateMaskTags},
+	}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	notes, err = service.ListByTag(ctx, "work")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(notes) != 0 {
+		t.Errorf("Expected no notes with tag after update, got %d", len(notes))
+	}
+}
+
+func TestNoteService_ListTags(t *testing.T) {
+	ctx := context.Background()
+
+	// Проверяем и индекс in-memory хранилища, и запасной вариант с полным просмотром
+	repos := map[string]repository.NoteRepository{
+		"indexed":  memory.NewRepository(),
+		"fallback": newMockRepository(),
+	}
+
+	for name, repo := range repos {
+		t.Run(name, func(t *testing.T) {
+			service := NewNoteService(repo)
+			for _, tags := range [][]string{{"go", "work"}, {"work"}} {
+				if _, err := service.Create(ctx, svc.CreateNoteInput{Title: "Tagged note", Tags: tags}); err != nil {
+					t.Fatalf("Expected no error, got: %v", err)
+				}
+			}
+
+			counts, err := service.ListTags(ctx)
+			if err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+
+			expected := []model.TagCount{{Tag: "go", Count: 1}, {Tag: "work", Count: 2}}
+			if !slices.Equal(counts, expected) {
+				t.Errorf("Expected %v, got %v", expected, counts)
+			}
+		})
+	}
+}
+
+func TestNoteService_ListByTag_EmptyTag(t *testing.T) {
+	ctx := context.Background()
+	service := NewNoteService(newMockRepository())
+
+	if _, err := service.ListByTag(ctx, "  "); err == nil || err.Error() != "tag cannot be empty" {
+		t.Errorf("Expected 'tag cannot be empty', got: %v", err)
+	}
+}
@@ -0,0 +1,152 @@
+package notes
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"notes-service/internal/auth"
+	"notes-service/internal/model"
+	"notes-service/internal/repository"
+	"notes-service/internal/repository/memory"
+	"notes-service/internal/telemetry"
+)
+
+// UserDirectory хранилище пользователей, которым можно предоставить доступ к заметкам
+type UserDirectory interface {
+	// Exists проверяет, что пользователь с ID userID существует
+	Exists(ctx context.Context, userID string) (bool, error)
+}
+
+// WithUserDirectory включает проверку, что пользователь, получающий доступ к заметке, существует
+// Без опции доступ можно предоставить любому ID пользователя
+func WithUserDirectory(users UserDirectory) Option {
+	return func(s *service) {
+		s.users = users
+	}
+}
+
+// Share предоставляет пользователю userID доступ к заметке вызывающего пользователя
+// Повторный вызов заменяет уровень доступа
+func (s *service) Share(ctx context.Context, noteID, userID string, permission model.SharePermission) (_ model.Share, err error) {
+	ctx, span := startSpan(ctx, "Share", noteIDAttribute(noteID))
+	defer func() { telemetry.EndSpan(span, err) }()
+	principal, ok := auth.FromContext(ctx)
+	if !ok {
+		return model.Share{}, auth.ErrPermissionDenied
+	}
+	ctx = ownerScope(ctx)
+
+	userID = strings.TrimSpace(userID)
+	if noteID == "" || userID == "" {
+		return model.Share{}, errors.New("note id and user id cannot be empty")
+	}
+	if !permission.Valid() {
+		return model.Share{}, fmt.Errorf("invalid share permission %d", permission)
+	}
+	if userID == principal.UserID {
+		return model.Share{}, errors.New("invalid share: note owner already has full access")
+	}
+	if s.users != nil {
+		exists, err := s.users.Exists(ctx, userID)
+		if err != nil {
+			return model.Share{}, err
+		}
+		if !exists {
+			return model.Share{}, fmt.Errorf("%w: %s", memory.ErrUserNotFound, userID)
+		}
+	}
+
+	// Поделиться можно только своей заметкой
+	note, err := s.noteRepository.GetByID(ctx, noteID)
+	if err != nil {
+		return model.Share{}, accessError(ctx, s.noteRepository, noteID, err)
+	}
+
+	share := model.Share{
+		NoteID:     noteID,
+		OwnerID:    principal.UserID,
+		UserID:     userID,
+		Permission: permission,
+		CreatedAt:  s.now(),
+	}
+	if err := s.shareRepository.Save(ctx, share); err != nil {
+		return model.Share{}, err
+	}
+	s.eventService.Publish(Event{Type: EventNoteShared, Note: note, Share: share})
+
+	return share, nil
+}
+
+// Unshare отзывает доступ пользователя userID к заметке вызывающего пользователя
+func (s *service) Unshare(ctx context.Context, noteID, userID string) (err error) {
+	ctx, span := startSpan(ctx, "Unshare", noteIDAttribute(noteID))
+	defer func() { telemetry.EndSpan(span, err) }()
+	if _, ok := auth.FromContext(ctx); !ok {
+		return auth.ErrPermissionDenied
+	}
+	ctx = ownerScope(ctx)
+
+	if noteID == "" || userID == "" {
+		return errors.New("note id and user id cannot be empty")
+	}
+
+	if _, err := s.noteRepository.GetByID(ctx, noteID); err != nil {
+		return accessError(ctx, s.noteRepository, noteID, err)
+	}
+
+	return s.shareRepository.Delete(ctx, noteID, userID)
+}
+
+// ListShared возвращает заметки других пользователей, доступные вызывающему пользователю
+func (s *service) ListShared(ctx context.Context) (_ []model.SharedNote, err error) {
+	ctx, span := startSpan(ctx, "ListShared")
+	defer func() { telemetry.EndSpan(span, err) }()
+	principal, ok := auth.FromContext(ctx)
+	if !ok {
+		return nil, auth.ErrPermissionDenied
+	}
+
+	shares, err := s.shareRepository.ListByUser(ctx, principal.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	notes := make([]model.SharedNote, 0, len(shares))
+	for _, share := range shares {
+		note, err := s.noteRepository.GetByID(repository.WithOwner(ctx, share.OwnerID), share.NoteID)
+		if errors.Is(err, memory.ErrNoteNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		notes = append(notes, model.SharedNote{Note: note, Permission: share.Permission})
+	}
+
+	return notes, nil
+}
+
+// sharedScope возвращает контекст владельца заметки, если вызывающий пользователь
+// получил к ней доступ уровня required. Без доступа заметка считается несуществующей,
+// а недостаточный уровень доступа возвращает auth.ErrPermissionDenied
+func (s *service) sharedScope(ctx context.Context, noteID string, required model.SharePermission) (context.Context, error) {
+	principal, ok := auth.FromContext(ctx)
+	if !ok {
+		return nil, memory.ErrNoteNotFound
+	}
+
+	share, err := s.shareRepository.Get(ctx, noteID, principal.UserID)
+	if errors.Is(err, memory.ErrShareNotFound) {
+		return nil, accessError(ctx, s.noteRepository, noteID, memory.ErrNoteNotFound)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !share.Permission.Allows(required) {
+		return nil, auth.ErrPermissionDenied
+	}
+
+	return repository.WithOwner(ctx, share.OwnerID), nil
+}
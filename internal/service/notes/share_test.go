@@ -0,0 +1,123 @@
+package notes
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"notes-service/internal/auth"
+	"notes-service/internal/model"
+	"notes-service/internal/repository/memory"
+	svc "notes-service/internal/service"
+)
+
+func TestNoteService_Share_GrantsAccess(t *testing.T) {
+	service := NewNoteService(memory.NewRepository())
+	alice := auth.NewContext(context.Background(), auth.Principal{UserID: "alice"})
+	bob := auth.NewContext(context.Background(), auth.Principal{UserID: "bob"})
+
+	note, err := service.Create(alice, svc.CreateNoteInput{Title: "Shared note", Content: "Hello"})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	// Чужую заметку нельзя расшарить
+	if _, err := service.Share(bob, note.ID, "carol", model.SharePermissionRead); !errors.Is(err, ErrNoteAccessDenied) {
+		t.Errorf("Expected ErrNoteAccessDenied when sharing another user's note, got: %v", err)
+	}
+
+	if _, err := service.Share(alice, note.ID, "bob", model.SharePermissionRead); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	got, err := service.Get(bob, note.ID)
+	if err != nil {
+		t.Fatalf("Expected bob to read the shared note, got: %v", err)
+	}
+	if got.Title != note.Title {
+		t.Errorf("Expected title %q, got %q", note.Title, got.Title)
+	}
+
+	// Доступ на чтение не разрешает изменение
+	if _, err := service.Update(bob, svc.UpdateNoteInput{ID: note.ID, Content: "Changed"}); !errors.Is(err, auth.ErrPermissionDenied) {
+		t.Errorf("Expected ErrPermissionDenied for read-only share, got: %v", err)
+	}
+
+	if _, err := service.Share(alice, note.ID, "bob", model.SharePermissionWrite); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	updated, err := service.Update(bob, svc.UpdateNoteInput{ID: note.ID, Content: "Changed"})
+	if err != nil {
+		t.Fatalf("Expected bob to update the shared note, got: %v", err)
+	}
+	if updated.OwnerID != "alice" {
+		t.Errorf("Expected owner to stay alice, got %q", updated.OwnerID)
+	}
+
+	shared, err := service.ListShared(bob)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(shared) != 1 || shared[0].Permission != model.SharePermissionWrite {
+		t.Errorf("Expected one note shared with write permission, got %+v", shared)
+	}
+
+	// Удаление доступно только владельцу
+	if err := service.Delete(bob, note.ID); !errors.Is(err, ErrNoteAccessDenied) {
+		t.Errorf("Expected ErrNoteAccessDenied on delete by shared user, got: %v", err)
+	}
+
+	if err := service.Unshare(alice, note.ID, "bob"); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if _, err := service.Get(bob, note.ID); !errors.Is(err, ErrNoteAccessDenied) {
+		t.Errorf("Expected ErrNoteAccessDenied after unshare, got: %v", err)
+	}
+}
+
+func TestNoteService_Share_Validation(t *testing.T) {
+	service := NewNoteService(memory.NewRepository())
+	alice := auth.NewContext(context.Background(), auth.Principal{UserID: "alice"})
+
+	note, err := service.Create(alice, svc.CreateNoteInput{Title: "Note"})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if _, err := service.Share(alice, note.ID, "alice", model.SharePermissionRead); err == nil {
+		t.Error("Expected error when sharing a note with its owner")
+	}
+	if _, err := service.Share(alice, note.ID, "bob", 0); err == nil {
+		t.Error("Expected error for unspecified permission")
+	}
+	if _, err := service.Share(context.Background(), note.ID, "bob", model.SharePermissionRead); !errors.Is(err, auth.ErrPermissionDenied) {
+		t.Errorf("Expected ErrPermissionDenied without principal, got: %v", err)
+	}
+	if err := service.Unshare(alice, note.ID, "bob"); !errors.Is(err, memory.ErrShareNotFound) {
+		t.Errorf("Expected ErrShareNotFound, got: %v", err)
+	}
+}
+
+// userDirectory справочник пользователей для тестов
+type userDirectory map[string]bool
+
+func (d userDirectory) Exists(_ context.Context, userID string) (bool, error) {
+	return d[userID], nil
+}
+
+func TestNoteService_Share_RequiresKnownUser(t *testing.T) {
+	service := NewNoteService(memory.NewRepository(), WithUserDirectory(userDirectory{"alice": true, "bob": true}))
+	alice := auth.NewContext(context.Background(), auth.Principal{UserID: "alice"})
+
+	note, err := service.Create(alice, svc.CreateNoteInput{Title: "Shared note"})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if _, err := service.Share(alice, note.ID, "mallory", model.SharePermissionRead); !errors.Is(err, memory.ErrUserNotFound) {
+		t.Errorf("Expected ErrUserNotFound for unknown user, got: %v", err)
+	}
+	if _, err := service.Share(alice, note.ID, "bob", model.SharePermissionRead); err != nil {
+		t.Errorf("Expected no error for known user, got: %v", err)
+	}
+}
@@ -0,0 +1,75 @@
+package notes
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+
+	"notes-service/internal/model"
+	"notes-service/internal/repository"
+	"notes-service/internal/telemetry"
+)
+
+// ListByTag возвращает заметки с указанным тегом
+// Если хранилище не поддерживает индекс тегов, заметки отбираются полным просмотром
+func (s *service) ListByTag(ctx context.Context, tag string) (_ []model.Note, err error) {
+	ctx, span := startSpan(ctx, "ListByTag")
+	defer func() { telemetry.EndSpan(span, err) }()
+	ctx = ownerScope(ctx)
+	tag = model.NormalizeTag(tag)
+	if tag == "" {
+		return nil, errors.New("tag cannot be empty")
+	}
+
+	if index, ok := s.noteRepository.(repository.TagIndex); ok {
+		notes, err := index.ListByTag(ctx, tag)
+		return redactProtected(notes), err
+	}
+
+	notes, err := s.noteRepository.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Зашифрованные (e2e) заметки не участвуют в выборке по тегам
+	return redactProtected(slices.DeleteFunc(notes, func(note model.Note) bool {
+		_, found := slices.BinarySearch(note.Tags, tag)
+		return !found || note.IsE2E
+	})), nil
+}
+
+// ListTags возвращает все теги с количеством заметок, упорядоченные по тегу
+func (s *service) ListTags(ctx context.Context) (_ []model.TagCount, err error) {
+	ctx, span := startSpan(ctx, "ListTags")
+	defer func() { telemetry.EndSpan(span, err) }()
+	ctx = ownerScope(ctx)
+	if index, ok := s.noteRepository.(repository.TagIndex); ok {
+		return index.ListTags(ctx)
+	}
+
+	notes, err := s.noteRepository.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[string]int)
+	for _, note := range notes {
+		if note.IsE2E {
+			continue
+		}
+		for _, tag := range note.Tags {
+			counts[tag]++
+		}
+	}
+
+	result := make([]model.TagCount, 0, len(counts))
+	for tag, count := range counts {
+		result = append(result, model.TagCount{Tag: tag, Count: count})
+	}
+	slices.SortFunc(result, func(a, b model.TagCount) int {
+		return strings.Compare(a.Tag, b.Tag)
+	})
+
+	return result, nil
+}
@@ -0,0 +1,22 @@
+package notes
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracer spans методов сервиса заметок; без провайдера трасс (otel.SetTracerProvider) ничего не записывает
+var tracer = otel.Tracer("notes-service/internal/service/notes")
+
+// startSpan начинает span метода сервиса notes.<method>; завершается через telemetry.EndSpan
+func startSpan(ctx context.Context, method string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return tracer.Start(ctx, "notes."+method, trace.WithAttributes(attrs...))
+}
+
+// noteIDAttribute ID заметки в атрибутах span
+func noteIDAttribute(id string) attribute.KeyValue {
+	return attribute.String("note.id", id)
+}
@@ -0,0 +1,82 @@
+package notes
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"notes-service/internal/model"
+	svc "notes-service/internal/service"
+)
+
+// noteFields значения полей заметки из запроса, к которым сервис применяет нормализацию
+// Пустое поле не проверяется
+type noteFields struct {
+	Title    string
+	Content  string
+	Tags     []string
+	RemindAt time.Time
+}
+
+// noteWarnings возвращает предупреждения о значениях, которые сервис изменит при сохранении
+func noteWarnings(fields noteFields, now time.Time) []model.Warning {
+	var warnings []model.Warning
+
+	for _, field := range []struct{ name, value string }{
+		{svc.UpdateMaskTitle, fields.Title},
+		{svc.UpdateMaskContent, fields.Content},
+	} {
+		if field.value != strings.TrimSpace(field.value) {
+			warnings = append(warnings, model.Warning{
+				Code:    model.WarningWhitespaceTrimmed,
+				Message: field.name + " leading and trailing whitespace was removed",
+				Field:   field.name,
+			})
+		}
+	}
+
+	// Порядок тегов не сохраняется, поэтому сравниваются отсортированные теги
+	if normalized := model.NormalizeTags(fields.Tags); !slices.Equal(slices.Sorted(slices.Values(fields.Tags)), normalized) {
+		warnings = append(warnings, model.Warning{
+			Code:    model.WarningTagsNormalized,
+			Message: fmt.Sprintf("tags were normalized to %q", normalized),
+			Field:   svc.UpdateMaskTags,
+		})
+	}
+
+	if !fields.RemindAt.IsZero() && fields.RemindAt.Before(now) {
+		warnings = append(warnings, model.Warning{
+			Code:    model.WarningRemindAtInPast,
+			Message: "remind_at is in the past, the reminder fires immediately",
+			Field:   svc.UpdateMaskRemindAt,
+		})
+	}
+
+	return warnings
+}
+
+// updateFields возвращает поля запроса обновления, которые будут применены к заметке (см. applyUpdate)
+func updateFields(input svc.UpdateNoteInput) noteFields {
+	applied := func(path string, unmasked bool) bool {
+		if len(input.UpdateMask) == 0 {
+			return unmasked
+		}
+		return slices.Contains(input.UpdateMask, path)
+	}
+
+	var fields noteFields
+	if applied(svc.UpdateMaskTitle, strings.TrimSpace(input.Title) != "") {
+		fields.Title = input.Title
+	}
+	if applied(svc.UpdateMaskContent, true) {
+		fields.Content = input.Content
+	}
+	if applied(svc.UpdateMaskTags, len(input.Tags) > 0) {
+		fields.Tags = input.Tags
+	}
+	if applied(svc.UpdateMaskRemindAt, !input.RemindAt.IsZero()) {
+		fields.RemindAt = input.RemindAt
+	}
+	return fields
+}
@@ -0,0 +1,83 @@
+package notes
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"notes-service/internal/auth"
+	"notes-service/internal/model"
+	"notes-service/internal/repository/memory"
+	svc "notes-service/internal/service"
+)
+
+// warningCodes возвращает поля и коды предупреждений в виде field -> code
+func warningCodes(warnings []model.Warning) map[string]string {
+	codes := make(map[string]string, len(warnings))
+	for _, w := range warnings {
+		codes[w.Field] = w.Code
+	}
+	return codes
+}
+
+func TestNoteService_Create_Warnings(t *testing.T) {
+	service := NewNoteService(memory.NewRepository())
+	ctx, warnings := svc.WithWarnings(auth.NewContext(context.Background(), auth.Principal{UserID: "alice"}))
+
+	note, err := service.Create(ctx, svc.CreateNoteInput{
+		Title:    "  Padded title ",
+		Content:  "Plain content",
+		Tags:     []string{"Go", "go", "notes"},
+		RemindAt: time.Now().Add(-time.Hour),
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if note.Title != "Padded title" {
+		t.Errorf("Expected trimmed title, got %q", note.Title)
+	}
+
+	got := warningCodes(warnings.List())
+	want := map[string]string{
+		"title":     model.WarningWhitespaceTrimmed,
+		"tags":      model.WarningTagsNormalized,
+		"remind_at": model.WarningRemindAtInPast,
+	}
+	if len(got) != len(want) {
+		t.Fatalf("Expected warnings %v, got %v", want, got)
+	}
+	for field, code := range want {
+		if got[field] != code {
+			t.Errorf("Expected %s warning for %s, got %q", code, field, got[field])
+		}
+	}
+}
+
+func TestNoteService_Update_WarningsOnlyForAppliedFields(t *testing.T) {
+	service := NewNoteService(memory.NewRepository())
+	alice := auth.NewContext(context.Background(), auth.Principal{UserID: "alice"})
+
+	note, err := service.Create(alice, svc.CreateNoteInput{Title: "Title", Content: "Content", Tags: []string{"b", "a"}})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	// Неотсортированные, но уже канонические теги не считаются нормализацией,
+	// а content вне маски не проверяется
+	ctx, warnings := svc.WithWarnings(alice)
+	_, err = service.Update(ctx, svc.UpdateNoteInput{
+		ID:         note.ID,
+		Title:      "New title ",
+		Content:    " ignored ",
+		Tags:       []string{"z", "a"},
+		UpdateMask: []string{svc.UpdateMaskTitle, svc.UpdateMaskTags},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	got := warningCodes(warnings.List())
+	if len(got) != 1 || got["title"] != model.WarningWhitespaceTrimmed {
+		t.Errorf("Expected only title warning, got %v", got)
+	}
+}
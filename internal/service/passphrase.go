@@ -0,0 +1,20 @@
+package service
+
+import "context"
+
+type notePassphraseKey struct{}
+
+// WithNotePassphrase передает сервису парольную фразу защищенной заметки
+// Транспортный слой берет ее из метаданных x-note-passphrase (заголовок X-Note-Passphrase)
+func WithNotePassphrase(ctx context.Context, passphrase string) context.Context {
+	if passphrase == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, notePassphraseKey{}, passphrase)
+}
+
+// NotePassphrase возвращает парольную фразу заметки из контекста или пустую строку
+func NotePassphrase(ctx context.Context) string {
+	passphrase, _ := ctx.Value(notePassphraseKey{}).(string)
+	return passphrase
+}
@@ -0,0 +1,236 @@
+package reminders
+
+import (
+	"container/heap"
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"notes-service/internal/model"
+	"notes-service/internal/repository"
+	"notes-service/internal/repository/memory"
+	"notes-service/internal/service/notes"
+)
+
+// loadBatchSize размер порции при загрузке напоминаний из хранилища
+const loadBatchSize = 500
+
+// Scheduler отслеживает время напоминаний заметок (Note.RemindAt) и, когда оно наступает,
+// публикует событие EventNoteReminderDue через EventService подписчикам SubscribeToEvents
+//
+// Напоминания хранятся в очереди по времени срабатывания, поэтому планировщик не опрашивает
+// хранилище: об изменениях заметок ему сообщает сервис заметок (см. notes.WithReminderScheduler)
+type Scheduler struct {
+	noteRepository repository.NoteRepository
+	events         notes.EventBus
+	now            func() time.Time
+
+	mu     sync.Mutex
+	queue  reminderQueue
+	byNote map[string]*reminder
+	wake   chan struct{} // Сигнал циклу Run, что ближайшее напоминание изменилось
+}
+
+// reminder запланированное напоминание заметки
+type reminder struct {
+	noteID string
+	at     time.Time
+	index  int // Позиция в очереди, поддерживается container/heap
+}
+
+// Option настраивает планировщик напоминаний
+type Option func(*Scheduler)
+
+// WithClock задает источник текущего времени, с которым сравнивается RemindAt (по умолчанию time.Now)
+func WithClock(now func() time.Time) Option {
+	return func(s *Scheduler) {
+		s.now = now
+	}
+}
+
+// NewScheduler создает планировщик напоминаний
+// Заметки перечитываются из noteRepository в момент срабатывания, события публикуются в events
+func NewScheduler(noteRepository repository.NoteRepository, events notes.EventBus, opts ...Option) *Scheduler {
+	s := &Scheduler{
+		noteRepository: noteRepository,
+		events:         events,
+		now:            time.Now,
+		byNote:         make(map[string]*reminder),
+		wake:           make(chan struct{}, 1),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Schedule планирует напоминание на note.RemindAt, заменяя прежнее напоминание заметки
+// Нулевое RemindAt отменяет напоминание
+func (s *Scheduler) Schedule(note model.Note) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if r, ok := s.byNote[note.ID]; ok {
+		if r.at.Equal(note.RemindAt) {
+			return
+		}
+		heap.Remove(&s.queue, r.index)
+		delete(s.byNote, note.ID)
+	}
+
+	if !note.RemindAt.IsZero() {
+		r := &reminder{noteID: note.ID, at: note.RemindAt}
+		heap.Push(&s.queue, r)
+		s.byNote[note.ID] = r
+	}
+	s.notify()
+}
+
+// Cancel отменяет напоминание заметки
+func (s *Scheduler) Cancel(noteID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if r, ok := s.byNote[noteID]; ok {
+		heap.Remove(&s.queue, r.index)
+		delete(s.byNote, noteID)
+		s.notify()
+	}
+}
+
+// notify будит цикл Run, вызывается под блокировкой
+func (s *Scheduler) notify() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Load планирует напоминания всех заметок хранилища
+// Напоминания, время которых прошло, пока сервер был остановлен, сработают сразу
+func (s *Scheduler) Load(ctx context.Context) error {
+	fn := func(note model.Note) error {
+		if !note.RemindAt.IsZero() {
+			s.Schedule(note)
+		}
+		return nil
+	}
+
+	if iterator, ok := s.noteRepository.(repository.NoteIterator); ok {
+		return iterator.ForEach(ctx, loadBatchSize, fn)
+	}
+
+	all, err := s.noteRepository.List(ctx)
+	if err != nil {
+		return err
+	}
+	for _, note := range all {
+		_ = fn(note)
+	}
+	return nil
+}
+
+// Run загружает напоминания из хранилища и публикует события по мере их наступления
+// Блокируется до отмены ctx
+func (s *Scheduler) Run(ctx context.Context) error {
+	if err := s.Load(ctx); err != nil {
+		return err
+	}
+
+	timer := time.NewTimer(time.Hour)
+	defer timer.Stop()
+
+	for {
+		for _, r := range s.popDue() {
+			s.fire(ctx, r)
+		}
+
+		s.resetTimer(timer)
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-s.wake:
+		case <-timer.C:
+		}
+	}
+}
+
+// popDue извлекает из очереди напоминания, время которых наступило
+func (s *Scheduler) popDue() []*reminder {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	var due []*reminder
+	for s.queue.Len() > 0 && !s.queue[0].at.After(now) {
+		r := heap.Pop(&s.queue).(*reminder)
+		delete(s.byNote, r.noteID)
+		due = append(due, r)
+	}
+	return due
+}
+
+// resetTimer переводит таймер на ближайшее напоминание
+func (s *Scheduler) resetTimer(timer *time.Timer) {
+	s.mu.Lock()
+	wait := time.Hour
+	if s.queue.Len() > 0 {
+		wait = max(s.queue[0].at.Sub(s.now()), 0)
+	}
+	s.mu.Unlock()
+
+	if !timer.Stop() {
+		select {
+		case <-timer.C:
+		default:
+		}
+	}
+	timer.Reset(wait)
+}
+
+// fire перечитывает заметку и публикует событие напоминания
+// Если заметку удалили или время напоминания изменилось, событие не публикуется
+func (s *Scheduler) fire(ctx context.Context, r *reminder) {
+	// Планировщик работает от имени сервера и видит заметки всех владельцев
+	note, err := s.noteRepository.GetByID(repository.WithoutOwner(ctx), r.noteID)
+	if errors.Is(err, memory.ErrNoteNotFound) {
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to load note %s for reminder: %v", r.noteID, err)
+		return
+	}
+	if !note.RemindAt.Equal(r.at) {
+		return
+	}
+
+	s.events.Publish(notes.Event{Type: notes.EventNoteReminderDue, Note: note})
+}
+
+// reminderQueue очередь напоминаний по времени срабатывания (container/heap)
+type reminderQueue []*reminder
+
+func (q reminderQueue) Len() int           { return len(q) }
+func (q reminderQueue) Less(i, j int) bool { return q[i].at.Before(q[j].at) }
+
+func (q reminderQueue) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+	q[i].index = i
+	q[j].index = j
+}
+
+func (q *reminderQueue) Push(x any) {
+	r := x.(*reminder)
+	r.index = len(*q)
+	*q = append(*q, r)
+}
+
+func (q *reminderQueue) Pop() any {
+	old := *q
+	r := old[len(old)-1]
+	old[len(old)-1] = nil
+	*q = old[:len(old)-1]
+	return r
+}
@@ -0,0 +1,143 @@
+package reminders
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"notes-service/internal/auth"
+	"notes-service/internal/model"
+	"notes-service/internal/repository/memory"
+	svc "notes-service/internal/service"
+	"notes-service/internal/service/notes"
+)
+
+// newTestScheduler создает сервис заметок с подключенным и запущенным планировщиком
+func newTestScheduler(t *testing.T) (svc.NoteService, chan notes.Event) {
+	t.Helper()
+
+	repo := memory.NewRepository()
+	events := notes.NewEventService()
+	scheduler := NewScheduler(repo, events)
+	service := notes.NewNoteService(repo, notes.WithEventService(events), notes.WithReminderScheduler(scheduler))
+
+	ch := events.Subscribe()
+	t.Cleanup(func() { events.Unsubscribe(ch) })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- scheduler.Run(ctx) }()
+	t.Cleanup(func() {
+		cancel()
+		if err := <-done; err != nil {
+			t.Errorf("Expected Run to stop without error, got: %v", err)
+		}
+	})
+
+	return service, ch
+}
+
+// waitReminder ожидает событие напоминания, пропуская события создания
+func waitReminder(ch chan notes.Event, timeout time.Duration) (model.Note, bool) {
+	deadline := time.After(timeout)
+	for {
+		select {
+		case event := <-ch:
+			if event.Type == notes.EventNoteReminderDue {
+				return event.Note, true
+			}
+		case <-deadline:
+			return model.Note{}, false
+		}
+	}
+}
+
+func TestScheduler_PublishesReminderDue(t *testing.T) {
+	service, ch := newTestScheduler(t)
+	ctx := auth.NewContext(context.Background(), auth.Principal{UserID: "alice", Roles: []string{auth.RoleUser}})
+
+	remindAt := time.Now().Add(50 * time.Millisecond)
+	note, err := service.Create(ctx, svc.CreateNoteInput{Title: "Call Bob", Content: "About the report", RemindAt: remindAt})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	got, ok := waitReminder(ch, 2*time.Second)
+	if !ok {
+		t.Fatal("Expected NoteReminderDue event")
+	}
+	if got.ID != note.ID || got.OwnerID != "alice" {
+		t.Errorf("Expected reminder for note %s of alice, got note %s of %q", note.ID, got.ID, got.OwnerID)
+	}
+	if !got.RemindAt.Equal(remindAt) {
+		t.Errorf("Expected remind_at %v, got %v", remindAt, got.RemindAt)
+	}
+}
+
+func TestScheduler_RescheduleAndCancel(t *testing.T) {
+	service, ch := newTestScheduler(t)
+	ctx := context.Background()
+
+	rescheduled, err := service.Create(ctx, svc.CreateNoteInput{Title: "Rescheduled", Content: "Moved to later", RemindAt: time.Now().Add(50 * time.Millisecond)})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	deleted, err := service.Create(ctx, svc.CreateNoteInput{Title: "Deleted", Content: "Never fires", RemindAt: time.Now().Add(50 * time.Millisecond)})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	cleared, err := service.Create(ctx, svc.CreateNoteInput{Title: "Cleared", Content: "Reminder removed", RemindAt: time.Now().Add(50 * time.Millisecond)})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	later := time.Now().Add(300 * time.Millisecond)
+	if _, err := service.Update(ctx, svc.UpdateNoteInput{ID: rescheduled.ID, Content: rescheduled.Content, RemindAt: later}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if err := service.Delete(ctx, deleted.ID); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if _, err := service.Update(ctx, svc.UpdateNoteInput{ID: cleared.ID, UpdateMask: []string{svc.UpdateMaskRemindAt}}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	got, ok := waitReminder(ch, 2*time.Second)
+	if !ok {
+		t.Fatal("Expected NoteReminderDue event for rescheduled note")
+	}
+	if got.ID != rescheduled.ID {
+		t.Errorf("Expected first reminder for rescheduled note %s, got %s", rescheduled.ID, got.ID)
+	}
+	if time.Now().Before(later) {
+		t.Errorf("Expected reminder not earlier than %v", later)
+	}
+
+	if got, ok := waitReminder(ch, 200*time.Millisecond); ok {
+		t.Errorf("Expected no more reminders, got note %s", got.ID)
+	}
+}
+
+func TestScheduler_LoadFiresOverdueReminders(t *testing.T) {
+	repo := memory.NewRepository()
+	overdue, err := repo.Create(context.Background(), model.Note{Title: "Overdue", RemindAt: time.Now().Add(-time.Hour)})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	events := notes.NewEventService()
+	ch := events.Subscribe()
+	defer events.Unsubscribe(ch)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() { _ = NewScheduler(repo, events).Run(ctx) }()
+
+	got, ok := waitReminder(ch, 2*time.Second)
+	if !ok {
+		t.Fatal("Expected overdue reminder to fire after load")
+	}
+	if got.ID != overdue.ID {
+		t.Errorf("Expected reminder for note %s, got %s", overdue.ID, got.ID)
+	}
+}
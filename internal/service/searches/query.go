@@ -0,0 +1,189 @@
+package searches
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"notes-service/internal/model"
+)
+
+// Filter разобранное выражение сохраненного поиска
+// Заметка подходит, если выполнены все условия выражения
+type Filter struct {
+	terms        []string // Подстроки, которые должны быть в заголовке или содержании
+	excluded     []string // Подстроки, которых не должно быть ни в заголовке, ни в содержании
+	tags         []string // Теги, которые должны быть у заметки
+	excludedTags []string // Теги, которых не должно быть у заметки
+	minWords     int      // Минимальное количество слов (0 - без ограничения)
+	maxWords     int      // Максимальное количество слов (-1 - без ограничения)
+}
+
+// term условие выражения
+type term struct {
+	text    string
+	quoted  bool // Фраза в кавычках: всегда ищется как текст
+	negated bool // Условие с минусом в начале
+}
+
+// Parse разбирает выражение поиска: условия через пробел, все должны выполняться
+//   - слово или "фраза в кавычках" - подстрока заголовка или содержания без учета регистра
+//   - tag:work - у заметки есть тег work
+//   - words>100, words>=100, words<100, words<=100 - количество слов в содержании
+//
+// Условие с минусом в начале (-draft, -"old version", -tag:archive) должно не выполняться
+func Parse(query string) (Filter, error) {
+	terms, err := tokenize(query)
+	if err != nil {
+		return Filter{}, err
+	}
+	if len(terms) == 0 {
+		return Filter{}, fmt.Errorf("invalid saved search query: query cannot be empty")
+	}
+
+	filter := Filter{maxWords: -1}
+	for _, t := range terms {
+		if err := filter.add(t); err != nil {
+			return Filter{}, err
+		}
+	}
+	return filter, nil
+}
+
+// add добавляет условие в фильтр
+func (f *Filter) add(t term) error {
+	lower := strings.ToLower(t.text)
+	if !t.quoted {
+		if tag, ok := strings.CutPrefix(lower, "tag:"); ok {
+			if tag = model.NormalizeTag(tag); tag == "" {
+				return fmt.Errorf("invalid saved search query: empty tag in %q", t.text)
+			}
+			if t.negated {
+				f.excludedTags = append(f.excludedTags, tag)
+			} else {
+				f.tags = append(f.tags, tag)
+			}
+			return nil
+		}
+		if comparison, ok := strings.CutPrefix(lower, "words"); ok && strings.IndexAny(comparison, "<>") == 0 {
+			if t.negated {
+				return fmt.Errorf("invalid saved search query: %q cannot be negated, use the opposite comparison", t.text)
+			}
+			return f.addWordCount(comparison)
+		}
+	}
+
+	if t.negated {
+		f.excluded = append(f.excluded, lower)
+	} else {
+		f.terms = append(f.terms, lower)
+	}
+	return nil
+}
+
+// addWordCount добавляет условие на количество слов; comparison - часть после "words" (">=100")
+func (f *Filter) addWordCount(comparison string) error {
+	operator, value := comparison[:1], comparison[1:]
+	if rest, ok := strings.CutPrefix(value, "="); ok {
+		operator, value = operator+"=", rest
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 {
+		return fmt.Errorf("invalid saved search query: word count must be a non-negative integer in words%s", comparison)
+	}
+
+	switch operator {
+	case ">":
+		f.minWords = max(f.minWords, n+1)
+	case ">=":
+		f.minWords = max(f.minWords, n)
+	case "<":
+		if n == 0 {
+			return fmt.Errorf("invalid saved search query: words<0 never matches")
+		}
+		f.setMaxWords(n - 1)
+	case "<=":
+		f.setMaxWords(n)
+	}
+	return nil
+}
+
+// setMaxWords сужает верхнюю границу количества слов
+func (f *Filter) setMaxWords(n int) {
+	if f.maxWords < 0 || n < f.maxWords {
+		f.maxWords = n
+	}
+}
+
+// Matches проверяет, подходит ли заметка под фильтр
+// Содержимое e2e заметок серверу недоступно, поэтому текст ищется только в их заголовке
+func (f Filter) Matches(note model.Note) bool {
+	for _, tag := range f.tags {
+		if !slices.Contains(note.Tags, tag) {
+			return false
+		}
+	}
+	if slices.ContainsFunc(f.excludedTags, func(tag string) bool { return slices.Contains(note.Tags, tag) }) {
+		return false
+	}
+	if note.WordCount < f.minWords || (f.maxWords >= 0 && note.WordCount > f.maxWords) {
+		return false
+	}
+
+	title, content := strings.ToLower(note.Title), strings.ToLower(note.Content)
+	contains := func(text string) bool {
+		return strings.Contains(title, text) || strings.Contains(content, text)
+	}
+	for _, text := range f.terms {
+		if !contains(text) {
+			return false
+		}
+	}
+	return !slices.ContainsFunc(f.excluded, contains)
+}
+
+// tokenize делит выражение на условия по пробельным символам, фразы в кавычках остаются целыми
+func tokenize(query string) ([]term, error) {
+	var terms []term
+	var current term
+	var text strings.Builder
+	inQuotes := false
+	flush := func() {
+		if text.Len() > 0 || current.quoted {
+			current.text = text.String()
+			if current.text != "" {
+				terms = append(terms, current)
+			}
+		} else if current.negated {
+			// Одиночный минус - обычное слово
+			terms = append(terms, term{text: "-"})
+		}
+		current = term{}
+		text.Reset()
+	}
+
+	for _, r := range query {
+		switch {
+		case inQuotes && r == '"':
+			inQuotes = false
+			flush()
+		case inQuotes:
+			text.WriteRune(r)
+		case r == '-' && text.Len() == 0 && !current.negated:
+			current.negated = true
+		case r == '"' && text.Len() == 0:
+			current.quoted, inQuotes = true, true
+		case unicode.IsSpace(r):
+			flush()
+		default:
+			text.WriteRune(r)
+		}
+	}
+	if inQuotes {
+		return nil, fmt.Errorf("invalid saved search query: unterminated quote")
+	}
+	flush()
+	return terms, nil
+}
@@ -0,0 +1,223 @@
+// Package searches хранит сохраненные поиски (умные папки) пользователей, выполняет их
+// и сообщает подписчикам событий, когда заметка начинает подходить под сохраненный поиск
+package searches
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+	"time"
+
+	"notes-service/internal/auth"
+	"notes-service/internal/model"
+	"notes-service/internal/repository"
+	"notes-service/internal/repository/memory"
+	svc "notes-service/internal/service"
+	"notes-service/internal/service/notes"
+
+	"github.com/google/uuid"
+)
+
+// maxSearchesPerOwner количество поисков, которое может сохранить пользователь
+const maxSearchesPerOwner = 100
+
+// ErrTooManySavedSearches возвращается, когда пользователь сохранил maxSearchesPerOwner поисков
+var ErrTooManySavedSearches = errors.New("too many saved searches")
+
+// Service хранит сохраненные поиски пользователей и выполняет их по заметкам владельца
+type Service struct {
+	repo        repository.SavedSearchRepository
+	noteService svc.NoteService
+	now         func() time.Time
+}
+
+// Option настраивает сервис сохраненных поисков
+type Option func(*Service)
+
+// WithClock задает источник времени создания и изменения поисков (по умолчанию time.Now)
+func WithClock(now func() time.Time) Option {
+	return func(s *Service) {
+		s.now = now
+	}
+}
+
+// NewService создает сервис сохраненных поисков, выполняющий поиски через noteService
+func NewService(repo repository.SavedSearchRepository, noteService svc.NoteService, opts ...Option) *Service {
+	s := &Service{
+		repo:        repo,
+		noteService: noteService,
+		now:         time.Now,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Save сохраняет поиск вызывающего пользователя под именем name
+// Поиск с тем же именем заменяется: его ID и время создания сохраняются
+func (s *Service) Save(ctx context.Context, name, query string) (model.SavedSearch, error) {
+	principal, ok := auth.FromContext(ctx)
+	if !ok {
+		return model.SavedSearch{}, auth.ErrPermissionDenied
+	}
+
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return model.SavedSearch{}, errors.New("saved search name cannot be empty")
+	}
+	query = strings.TrimSpace(query)
+	if _, err := Parse(query); err != nil {
+		return model.SavedSearch{}, err
+	}
+
+	existing, err := s.repo.ListByOwner(ctx, principal.UserID)
+	if err != nil {
+		return model.SavedSearch{}, err
+	}
+	now := s.now()
+	search := model.SavedSearch{
+		ID:        uuid.New().String(),
+		OwnerID:   principal.UserID,
+		Name:      name,
+		Query:     query,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if i := slices.IndexFunc(existing, func(other model.SavedSearch) bool { return other.Name == name }); i >= 0 {
+		search.ID, search.CreatedAt = existing[i].ID, existing[i].CreatedAt
+	} else if len(existing) >= maxSearchesPerOwner {
+		return model.SavedSearch{}, fmt.Errorf("%w: at most %d saved searches per user", ErrTooManySavedSearches, maxSearchesPerOwner)
+	}
+
+	if err := s.repo.Save(ctx, search); err != nil {
+		return model.SavedSearch{}, err
+	}
+	return search, nil
+}
+
+// List возвращает поиски вызывающего пользователя в порядке создания
+func (s *Service) List(ctx context.Context) ([]model.SavedSearch, error) {
+	principal, ok := auth.FromContext(ctx)
+	if !ok {
+		return nil, auth.ErrPermissionDenied
+	}
+	return s.repo.ListByOwner(ctx, principal.UserID)
+}
+
+// Delete удаляет поиск вызывающего пользователя
+// Чужой поиск не существует для вызывающего (memory.ErrSavedSearchNotFound)
+func (s *Service) Delete(ctx context.Context, id string) error {
+	if _, err := s.get(ctx, id); err != nil {
+		return err
+	}
+	return s.repo.Delete(ctx, id)
+}
+
+// Execute возвращает заметки вызывающего пользователя, подходящие под сохраненный поиск,
+// в порядке opts (как List сервиса заметок)
+func (s *Service) Execute(ctx context.Context, id string, opts svc.ListOptions) (model.SavedSearch, []model.Note, error) {
+	search, err := s.get(ctx, id)
+	if err != nil {
+		return model.SavedSearch{}, nil, err
+	}
+	filter, err := Parse(search.Query)
+	if err != nil {
+		return model.SavedSearch{}, nil, err
+	}
+
+	list, err := s.noteService.List(ctx, opts)
+	if err != nil {
+		return model.SavedSearch{}, nil, err
+	}
+	matched := list[:0]
+	for _, note := range list {
+		if filter.Matches(note) {
+			matched = append(matched, note)
+		}
+	}
+	return search, matched, nil
+}
+
+// get возвращает поиск вызывающего пользователя
+func (s *Service) get(ctx context.Context, id string) (model.SavedSearch, error) {
+	principal, ok := auth.FromContext(ctx)
+	if !ok {
+		return model.SavedSearch{}, auth.ErrPermissionDenied
+	}
+
+	search, err := s.repo.Get(ctx, id)
+	if err != nil {
+		return model.SavedSearch{}, err
+	}
+	if search.OwnerID != principal.UserID {
+		return model.SavedSearch{}, memory.ErrSavedSearchNotFound
+	}
+	return search, nil
+}
+
+// NewBus возвращает шину событий поверх events, которая после публикации создания или изменения
+// заметки публикует EventSavedSearchMatched для каждого поиска владельца из repo, под который
+// заметка начала подходить
+//
+// Поиски проверяются на реплике, где заметка изменена, до пересылки событий брокером:
+// поэтому каждое совпадение публикуется один раз, а другие реплики получают его как обычное событие
+func NewBus(events notes.EventBus, repo repository.SavedSearchRepository) notes.EventBus {
+	return &matchingBus{EventBus: events, repo: repo}
+}
+
+// matchingBus шина событий, дополняющая события заметок совпадениями сохраненных поисков
+type matchingBus struct {
+	notes.EventBus
+	repo repository.SavedSearchRepository
+}
+
+// Publish публикует событие и совпадения сохраненных поисков, вызванные им
+func (b *matchingBus) Publish(event notes.Event) {
+	b.EventBus.Publish(event)
+
+	switch event.Type {
+	case notes.EventNoteCreated:
+	case notes.EventNoteUpdated:
+		// Закрепление не меняет полей, по которым отбирают поиски
+		if event.Previous.ID == "" {
+			return
+		}
+	default:
+		return
+	}
+
+	for _, search := range b.startedMatching(event) {
+		b.EventBus.Publish(notes.Event{Type: notes.EventSavedSearchMatched, Note: event.Note, Search: search})
+	}
+}
+
+// startedMatching возвращает поиски владельца заметки события, под которые заметка начала подходить:
+// новая заметка подходит под поиск, а измененная подходит после изменения, но не подходила до него
+func (b *matchingBus) startedMatching(event notes.Event) []model.SavedSearch {
+	searches, err := b.repo.ListByOwner(context.Background(), event.Note.OwnerID)
+	if err != nil {
+		log.Printf("Failed to list saved searches of user %s: %v", event.Note.OwnerID, err)
+		return nil
+	}
+
+	var matched []model.SavedSearch
+	for _, search := range searches {
+		filter, err := Parse(search.Query)
+		if err != nil {
+			log.Printf("Skipping saved search %s with invalid query: %v", search.ID, err)
+			continue
+		}
+		if !filter.Matches(event.Note) {
+			continue
+		}
+		if event.Type == notes.EventNoteUpdated && filter.Matches(event.Previous) {
+			continue
+		}
+		matched = append(matched, search)
+	}
+	return matched
+}
@@ -0,0 +1,177 @@
+package searches
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"notes-service/internal/auth"
+	"notes-service/internal/model"
+	"notes-service/internal/repository/memory"
+	svc "notes-service/internal/service"
+	"notes-service/internal/service/notes"
+)
+
+var (
+	alice = auth.NewContext(context.Background(), auth.Principal{UserID: "alice"})
+	bob   = auth.NewContext(context.Background(), auth.Principal{UserID: "bob"})
+)
+
+func TestParse(t *testing.T) {
+	note := model.Note{Title: "Release notes", Content: "Final version of the plan", Tags: []string{"work"}, WordCount: 5}
+
+	tests := []struct {
+		query string
+		match bool
+	}{
+		{"release", true},
+		{"RELEASE plan", true},
+		{"release missing", false},
+		{`"version of"`, true},
+		{`-"old version"`, true},
+		{"-plan", false},
+		{"tag:work", true},
+		{"tag:Work -tag:archive", true},
+		{"-tag:work", false},
+		{"words>=5 words<=5", true},
+		{"words>5", false},
+		{"words<5", false},
+	}
+	for _, tt := range tests {
+		filter, err := Parse(tt.query)
+		if err != nil {
+			t.Errorf("Parse(%q) = %v", tt.query, err)
+			continue
+		}
+		if got := filter.Matches(note); got != tt.match {
+			t.Errorf("Parse(%q).Matches = %v, want %v", tt.query, got, tt.match)
+		}
+	}
+
+	for _, query := range []string{"", "   ", `"unterminated`, "tag:", "words>many", "-words>5", "words<0"} {
+		if _, err := Parse(query); err == nil {
+			t.Errorf("Parse(%q): expected error", query)
+		}
+	}
+}
+
+func TestService_SaveAndExecute(t *testing.T) {
+	noteService := notes.NewNoteService(memory.NewRepository())
+	s := NewService(memory.NewSavedSearchRepository(), noteService)
+
+	for _, input := range []svc.CreateNoteInput{
+		{Title: "Plan", Content: "quarterly plan", Tags: []string{"work"}},
+		{Title: "Groceries", Content: "milk", Tags: []string{"home"}},
+	} {
+		if _, err := noteService.Create(alice, input); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+	}
+	if _, err := noteService.Create(bob, svc.CreateNoteInput{Title: "Bob plan", Content: "plan", Tags: []string{"work"}}); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	search, err := s.Save(alice, "Work", "tag:home")
+	if err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	// Поиск с тем же именем заменяется
+	replaced, err := s.Save(alice, "Work", "tag:work")
+	if err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	if replaced.ID != search.ID || replaced.Query != "tag:work" {
+		t.Errorf("Expected search %s to be replaced, got %+v", search.ID, replaced)
+	}
+	if list, _ := s.List(alice); len(list) != 1 {
+		t.Errorf("Expected 1 saved search, got %d", len(list))
+	}
+
+	_, matched, err := s.Execute(alice, search.ID, svc.ListOptions{})
+	if err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if len(matched) != 1 || matched[0].Title != "Plan" {
+		t.Errorf("Expected only alice's work note, got %+v", matched)
+	}
+
+	if _, _, err := s.Execute(bob, search.ID, svc.ListOptions{}); !errors.Is(err, memory.ErrSavedSearchNotFound) {
+		t.Errorf("Expected ErrSavedSearchNotFound for another user's search, got: %v", err)
+	}
+	if _, err := s.Save(alice, "Broken", "tag:"); err == nil {
+		t.Error("Expected error for invalid query")
+	}
+	if err := s.Delete(alice, search.ID); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if list, _ := s.List(alice); len(list) != 0 {
+		t.Errorf("Expected no saved searches after delete, got %d", len(list))
+	}
+}
+
+func TestBus_PublishesMatches(t *testing.T) {
+	events := notes.NewEventService()
+	repo := memory.NewSavedSearchRepository()
+	noteService := notes.NewNoteService(memory.NewRepository(), notes.WithEventService(NewBus(events, repo)))
+	s := NewService(repo, noteService)
+
+	search, err := s.Save(alice, "Urgent", "urgent -tag:done")
+	if err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	ch := events.Subscribe()
+	defer events.Unsubscribe(ch)
+
+	matches := func() []notes.Event {
+		var matched []notes.Event
+		for {
+			select {
+			case event := <-ch:
+				if event.Type == notes.EventSavedSearchMatched {
+					matched = append(matched, event)
+				}
+			default:
+				return matched
+			}
+		}
+	}
+
+	note, err := noteService.Create(alice, svc.CreateNoteInput{Title: "Call", Content: "later"})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if got := matches(); len(got) != 0 {
+		t.Fatalf("Expected no matches for a non-matching note, got %d", len(got))
+	}
+
+	// Заметка начинает подходить под поиск
+	if note, err = noteService.Update(alice, svc.UpdateNoteInput{ID: note.ID, Content: "urgent"}); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	got := matches()
+	if len(got) != 1 || got[0].Search.ID != search.ID || got[0].Note.ID != note.ID {
+		t.Fatalf("Expected one match of search %s, got %+v", search.ID, got)
+	}
+
+	// Заметка уже подходила: совпадение не повторяется
+	if _, err := noteService.Update(alice, svc.UpdateNoteInput{ID: note.ID, Title: "Call now", Content: "urgent!"}); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if _, err := noteService.Pin(alice, note.ID); err != nil {
+		t.Fatalf("Pin: %v", err)
+	}
+	if got := matches(); len(got) != 0 {
+		t.Errorf("Expected no repeated match, got %d", len(got))
+	}
+
+	// Новая подходящая заметка и заметка другого пользователя
+	if _, err := noteService.Create(alice, svc.CreateNoteInput{Title: "Urgent", Content: "now"}); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if _, err := noteService.Create(bob, svc.CreateNoteInput{Title: "Urgent", Content: "bob"}); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if got := matches(); len(got) != 1 || got[0].Note.OwnerID != "alice" {
+		t.Errorf("Expected one match for alice's new note, got %+v", got)
+	}
+}
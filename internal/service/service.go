@@ -2,24 +2,202 @@ package service
 
 import (
 	"context"
+	"io"
+	"time"
 
+	"notes-service/internal/diff"
 	"notes-service/internal/model"
 )
 
+// Пути полей заметки, допустимые в маске обновления UpdateNoteInput.UpdateMask
+const (
+	UpdateMaskTitle   = "title"
+	UpdateMaskContent = "content"
+	UpdateMaskTags    = "tags"
+
+	UpdateMaskContentEncrypted = "content_encrypted"
+	UpdateMaskRemindAt         = "remind_at"
+)
+
+// CreateNoteInput параметры создания заметки
+type CreateNoteInput struct {
+	Title   string   // Заголовок заметки
+	Content string   // Содержание заметки
+	Tags    []string // Теги заметки (нормализуются сервисом)
+
+	// Сквозное шифрование: Content должен быть пуст, содержимое передается в ContentEncrypted
+	IsE2E            bool
+	E2EScheme        string
+	ContentEncrypted []byte
+
+	RemindAt time.Time // Время напоминания (нулевое - без напоминания)
+
+	// IdempotencyKey - ключ идемпотентности клиента: повтор с тем же ключом не создает
+	// новую заметку, а возвращает исходную
+	IdempotencyKey string
+}
+
+// ListOptions параметры получения списка заметок
+type ListOptions struct {
+	// TitleCollation - язык сортировки по заголовку (BCP 47, например "ru")
+	// Если пуст, заметки возвращаются в порядке хранилища
+	TitleCollation string
+
+	// Order - порядок по длине содержания; при равной длине заметки сортируются по TitleCollation
+	Order ListOrder
+
+	// MinWordCount и MaxWordCount - границы количества слов в содержании (0 - без ограничения)
+	MinWordCount int
+	MaxWordCount int
+
+	// IncludeArchived - включить в список архивные заметки (по умолчанию они скрыты)
+	IncludeArchived bool
+}
+
+// ListOrder порядок заметок по длине содержания в List
+type ListOrder int
+
+const (
+	ListOrderDefault       ListOrder = iota // Без сортировки по длине
+	ListOrderWordCountAsc                   // Сначала короткие заметки
+	ListOrderWordCountDesc                  // Сначала длинные заметки
+)
+
+// UpdateNoteInput параметры обновления заметки
+type UpdateNoteInput struct {
+	ID      string   // UUID заметки
+	Title   string   // Новый заголовок
+	Content string   // Новое содержание
+	Tags    []string // Новые теги (без маски пустой список не меняет теги)
+	Version int64    // Ожидаемая версия заметки (0 - без проверки конкурентных изменений)
+	Force   bool     // Записать обновление, даже если поля заметки не изменились
+
+	ContentEncrypted []byte    // Новое зашифрованное содержимое e2e заметки (без маски пустое не меняет его)
+	RemindAt         time.Time // Новое время напоминания (без маски нулевое не меняет его, с маской снимает напоминание)
+
+	// UpdateMask - список обновляемых полей (UpdateMaskTitle, UpdateMaskContent, UpdateMaskTags,
+	// UpdateMaskContentEncrypted, UpdateMaskRemindAt)
+	// Если маска пуста, действует прежнее поведение: пустой title не меняет заголовок,
+	// а content обновляется всегда (в том числе очищается пустой строкой)
+	UpdateMask []string
+}
+
+// DiffRevisionsInput параметры сравнения ревизий заметки
+type DiffRevisionsInput struct {
+	ID           string // UUID заметки
+	FromRevision int64  // Исходная ревизия
+	ToRevision   int64  // Ревизия, с которой сравнивается исходная (может быть и раньше нее)
+	ContextLines int    // Строк контекста вокруг изменений
+}
+
+// RevisionDiff построчные различия содержимого двух ревизий заметки
+type RevisionDiff struct {
+	NoteID       string
+	FromRevision int64
+	ToRevision   int64
+	FromTitle    string
+	ToTitle      string
+	Hunks        []diff.Hunk // Блоки изменений (пусто, если содержимое не менялось)
+	LinesAdded   int
+	LinesRemoved int
+}
+
 // NoteService интерфейс для бизнес-логики работы с заметками
 type NoteService interface {
-	// Create создает новую заметку с указанными title и content
-	Create(ctx context.Context, title, content string) (model.Note, error)
+	// Create создает новую заметку согласно параметрам CreateNoteInput
+	Create(ctx context.Context, input CreateNoteInput) (model.Note, error)
 
 	// Get возвращает заметку по её ID
 	Get(ctx context.Context, id string) (model.Note, error)
 
-	// List возвращает список всех заметок
-	List(ctx context.Context) ([]model.Note, error)
+	// List возвращает список всех заметок с учетом параметров сортировки
+	List(ctx context.Context, opts ListOptions) ([]model.Note, error)
+
+	// ForEach обходит все заметки порциями по batchSize, не загружая весь список в память
+	ForEach(ctx context.Context, batchSize int, fn func(model.Note) error) error
 
-	// Update обновляет заметку с указанным ID (title и content опциональны)
-	Update(ctx context.Context, id, title, content string) (model.Note, error)
+	// ForEachAfter обходит заметки в порядке возрастания ID, начиная после курсора after
+	ForEachAfter(ctx context.Context, after string, batchSize int, fn func(model.Note) error) error
+
+	// Pin закрепляет заметку, закрепленные заметки выводятся в начале List
+	Pin(ctx context.Context, id string) (model.Note, error)
+
+	// Unpin открепляет заметку
+	Unpin(ctx context.Context, id string) (model.Note, error)
+
+	// Archive перемещает заметку в архив: архивные заметки не выводятся в List без IncludeArchived
+	Archive(ctx context.Context, id string) (model.Note, error)
+
+	// Unarchive возвращает заметку из архива
+	Unarchive(ctx context.Context, id string) (model.Note, error)
+
+	// Lock захватывает блокировку заметки для монопольного редактирования на время ttl
+	// (0 - значение по умолчанию); force перехватывает чужую блокировку (только для администраторов)
+	Lock(ctx context.Context, id string, ttl time.Duration, force bool) (model.NoteLock, error)
+
+	// Unlock снимает блокировку заметки; force снимает чужую блокировку (только для администраторов)
+	Unlock(ctx context.Context, id string, force bool) error
+
+	// SetPassphrase защищает заметку парольной фразой, меняет ее или снимает защиту (пустая passphrase)
+	// Смена и снятие защиты требуют текущую фразу в контексте (WithNotePassphrase)
+	SetPassphrase(ctx context.Context, id, passphrase string) (model.Note, error)
+
+	// Update обновляет заметку согласно параметрам UpdateNoteInput
+	Update(ctx context.Context, input UpdateNoteInput) (model.Note, error)
 
 	// Delete удаляет заметку по ID
 	Delete(ctx context.Context, id string) error
+
+	// BatchCreate создает несколько заметок (atomic - все или ничего)
+	BatchCreate(ctx context.Context, notes []model.Note, atomic bool) ([]model.BatchResult, error)
+
+	// BatchGet возвращает несколько заметок по списку ID
+	BatchGet(ctx context.Context, ids []string) ([]model.BatchResult, error)
+
+	// BatchDelete удаляет несколько заметок (atomic - все или ничего)
+	BatchDelete(ctx context.Context, ids []string, atomic bool) ([]model.BatchResult, error)
+
+	// ListRevisions возвращает историю изменений заметки
+	ListRevisions(ctx context.Context, id string) ([]model.NoteRevision, error)
+
+	// GetRevision возвращает конкретную ревизию заметки
+	GetRevision(ctx context.Context, id string, revision int64) (model.NoteRevision, error)
+
+	// DiffRevisions сравнивает содержимое двух ревизий заметки построчно
+	DiffRevisions(ctx context.Context, input DiffRevisionsInput) (RevisionDiff, error)
+
+	// ListByTag возвращает заметки с указанным тегом
+	ListByTag(ctx context.Context, tag string) ([]model.Note, error)
+
+	// ListTags возвращает все теги с количеством заметок, упорядоченные по тегу
+	ListTags(ctx context.Context) ([]model.TagCount, error)
+
+	// ListAll возвращает заметки всех пользователей (только для роли admin)
+	ListAll(ctx context.Context) ([]model.Note, error)
+
+	// Share предоставляет пользователю userID доступ к своей заметке
+	Share(ctx context.Context, noteID, userID string, permission model.SharePermission) (model.Share, error)
+
+	// Unshare отзывает доступ пользователя userID к своей заметке
+	Unshare(ctx context.Context, noteID, userID string) error
+
+	// ListShared возвращает заметки других пользователей, доступные вызывающему пользователю
+	ListShared(ctx context.Context) ([]model.SharedNote, error)
+}
+
+// UploadAttachmentInput параметры загрузки вложения
+type UploadAttachmentInput struct {
+	NoteID      string // UUID заметки
+	FileName    string // Имя файла
+	ContentType string // MIME тип (по умолчанию application/octet-stream)
+}
+
+// AttachmentService интерфейс для работы с вложениями заметок
+type AttachmentService interface {
+	// Upload сохраняет содержимое data как вложение заметки
+	Upload(ctx context.Context, input UploadAttachmentInput, data io.Reader) (model.Attachment, error)
+
+	// Download возвращает метаданные вложения и поток его содержимого
+	// Вызывающая сторона обязана закрыть поток
+	Download(ctx context.Context, noteID, id string) (model.Attachment, io.ReadCloser, error)
 }
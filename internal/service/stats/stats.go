@@ -0,0 +1,97 @@
+package stats
+
+import (
+	"context"
+	"unicode/utf8"
+
+	"notes-service/internal/model"
+	svc "notes-service/internal/service"
+)
+
+// batchSize количество заметок, читаемых из хранилища за раз при подсчете статистики пользователя
+const batchSize = 500
+
+// Service вычисляет статистику заметок
+// Заметки читаются через сервис заметок, поэтому действуют те же правила доступа
+type Service struct {
+	noteService svc.NoteService
+}
+
+// NewService создает сервис статистики поверх сервиса заметок
+func NewService(noteService svc.NoteService) *Service {
+	return &Service{noteService: noteService}
+}
+
+// NoteStats возвращает статистику заметки id, доступной вызывающему пользователю
+func (s *Service) NoteStats(ctx context.Context, id string) (model.NoteStats, error) {
+	note, err := s.noteService.Get(ctx, id)
+	if err != nil {
+		return model.NoteStats{}, err
+	}
+
+	stats := model.NoteStats{
+		NoteID:    note.ID,
+		UpdatedAt: note.UpdatedAt,
+		IsE2E:     note.IsE2E,
+	}
+	if note.IsE2E {
+		return stats, nil
+	}
+
+	stats.WordCount, stats.CharacterCount = countText(note.Content)
+	stats.ReadingTime = model.ReadingTime(int(stats.WordCount))
+
+	revisions, err := s.noteService.ListRevisions(ctx, id)
+	if err != nil {
+		return model.NoteStats{}, err
+	}
+	stats.LastEdit = lastEdit(revisions)
+
+	return stats, nil
+}
+
+// AccountStats возвращает сводную статистику собственных заметок вызывающего пользователя
+func (s *Service) AccountStats(ctx context.Context) (model.AccountStats, error) {
+	var stats model.AccountStats
+	err := s.noteService.ForEach(ctx, batchSize, func(note model.Note) error {
+		stats.TotalNotes++
+		if !note.IsE2E {
+			words, characters := countText(note.Content)
+			stats.TotalWords += words
+			stats.TotalCharacters += characters
+		}
+		return nil
+	})
+	if err != nil {
+		return model.AccountStats{}, err
+	}
+
+	if stats.NotesPerTag, err = s.noteService.ListTags(ctx); err != nil {
+		return model.AccountStats{}, err
+	}
+
+	return stats, nil
+}
+
+// countText возвращает количество слов (разделенных пробельными символами) и символов текста
+func countText(text string) (words, characters int64) {
+	return int64(model.CountWords(text)), int64(utf8.RuneCountInString(text))
+}
+
+// lastEdit сравнивает две последние ревизии заметки
+// Возвращает nil, если ревизия одна (заметку не правили после создания)
+func lastEdit(revisions []model.NoteRevision) *model.NoteEditDelta {
+	if len(revisions) < 2 {
+		return nil
+	}
+
+	previous, last := revisions[len(revisions)-2], revisions[len(revisions)-1]
+	previousWords, previousCharacters := countText(previous.Content)
+	lastWords, lastCharacters := countText(last.Content)
+
+	return &model.NoteEditDelta{
+		Revision:        last.Revision,
+		WordsDelta:      lastWords - previousWords,
+		CharactersDelta: lastCharacters - previousCharacters,
+	}
+}
@@ -0,0 +1,86 @@
+package stats
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"notes-service/internal/auth"
+	"notes-service/internal/model"
+	"notes-service/internal/repository/memory"
+	svc "notes-service/internal/service"
+	"notes-service/internal/service/notes"
+)
+
+func TestService_NoteStats(t *testing.T) {
+	noteService := notes.NewNoteService(memory.NewRepository())
+	stats := NewService(noteService)
+	alice := auth.NewContext(context.Background(), auth.Principal{UserID: "alice"})
+
+	note, err := noteService.Create(alice, svc.CreateNoteInput{Title: "Draft", Content: "one two three"})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	got, err := stats.NoteStats(alice, note.ID)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if got.WordCount != 3 || got.CharacterCount != 13 || got.LastEdit != nil {
+		t.Errorf("Expected 3 words, 13 characters and no edits, got %+v", got)
+	}
+	if got.ReadingTime != time.Second {
+		t.Errorf("Expected reading time rounded up to 1s, got %s", got.ReadingTime)
+	}
+
+	// Последняя правка удаляет одно слово и добавляет кириллицу
+	if _, err := noteService.Update(alice, svc.UpdateNoteInput{ID: note.ID, Content: "один два"}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	got, err = stats.NoteStats(alice, note.ID)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	want := model.NoteEditDelta{Revision: 2, WordsDelta: -1, CharactersDelta: 8 - 13}
+	if got.LastEdit == nil || *got.LastEdit != want {
+		t.Errorf("Expected last edit %+v, got %+v", want, got.LastEdit)
+	}
+
+	bob := auth.NewContext(context.Background(), auth.Principal{UserID: "bob"})
+	if _, err := stats.NoteStats(bob, note.ID); !errors.Is(err, notes.ErrNoteAccessDenied) {
+		t.Errorf("Expected ErrNoteAccessDenied for another user's note, got: %v", err)
+	}
+}
+
+func TestService_AccountStats(t *testing.T) {
+	noteService := notes.NewNoteService(memory.NewRepository())
+	stats := NewService(noteService)
+	alice := auth.NewContext(context.Background(), auth.Principal{UserID: "alice"})
+	bob := auth.NewContext(context.Background(), auth.Principal{UserID: "bob"})
+
+	for _, input := range []svc.CreateNoteInput{
+		{Title: "First", Content: "hello world", Tags: []string{"work"}},
+		{Title: "Second", Content: "one two three", Tags: []string{"work", "home"}},
+		{Title: "Secret", IsE2E: true, E2EScheme: model.SupportedE2ESchemes()[0], ContentEncrypted: []byte{1, 2, 3}},
+	} {
+		if _, err := noteService.Create(alice, input); err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+	}
+	if _, err := noteService.Create(bob, svc.CreateNoteInput{Title: "Bob", Content: "not counted", Tags: []string{"work"}}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	got, err := stats.AccountStats(alice)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if got.TotalNotes != 3 || got.TotalWords != 5 || got.TotalCharacters != 24 {
+		t.Errorf("Expected 3 notes, 5 words and 24 characters, got %+v", got)
+	}
+	wantTags := []model.TagCount{{Tag: "home", Count: 1}, {Tag: "work", Count: 2}}
+	if len(got.NotesPerTag) != len(wantTags) || got.NotesPerTag[0] != wantTags[0] || got.NotesPerTag[1] != wantTags[1] {
+		t.Errorf("Expected notes per tag %v, got %v", wantTags, got.NotesPerTag)
+	}
+}
@@ -0,0 +1,364 @@
+// Package suggest подсказывает заголовки заметок по мере ввода (SuggestNotes): индекс префиксов
+// заголовков хранится в памяти и поддерживается по событиям заметок, поэтому подсказка не обращается
+// к хранилищу и укладывается в единицы миллисекунд
+package suggest
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"log"
+	"math"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"notes-service/internal/auth"
+	"notes-service/internal/model"
+	"notes-service/internal/repository"
+	"notes-service/internal/service/notes"
+)
+
+const (
+	// DefaultLimit количество подсказок по умолчанию
+	DefaultLimit = 10
+
+	// MaxLimit наибольшее количество подсказок за запрос
+	MaxLimit = 50
+
+	// maxIndexedWords количество слов заголовка, с которых может начинаться префикс
+	maxIndexedWords = 16
+
+	// recencyHalfLife время, за которое вклад давности изменения или просмотра в ранг уменьшается вдвое
+	recencyHalfLife = 7 * 24 * time.Hour
+
+	// recencyWeight вклад в ранг только что измененной или просмотренной заметки
+	recencyWeight = 4
+
+	// loadBatchSize размер порции при загрузке заголовков из хранилища
+	loadBatchSize = 500
+)
+
+// ErrEmptyPrefix возвращается, когда префикс не содержит букв и цифр
+var ErrEmptyPrefix = errors.New("suggestion prefix cannot be empty")
+
+// Index индекс префиксов заголовков заметок
+//
+// Для каждого владельца хранится отсортированный срез ключей: заголовок в нижнем регистре, начиная
+// с каждого из первых maxIndexedWords слов. Подсказка находит первый подходящий ключ двоичным поиском
+// и просматривает ключи, пока они начинаются с префикса, поэтому "plan" находит и "Plan for Q3",
+// и "Trip plan". Просмотры заметок считаются только в памяти реплики и обнуляются при перезапуске
+type Index struct {
+	noteRepository repository.NoteRepository
+	events         notes.EventBus
+	now            func() time.Time
+
+	mu     sync.RWMutex
+	notes  map[string]*entry // Заметки по ID
+	owners map[string][]key  // Ключи заметок владельца, упорядоченные по text и noteID
+}
+
+// entry заголовок заметки в индексе
+type entry struct {
+	ownerID   string
+	title     string
+	keys      []string
+	version   int64
+	updatedAt time.Time
+	viewedAt  time.Time
+	views     int64
+}
+
+// key ключ индекса: нормализованный заголовок, начиная с одного из его слов
+type key struct {
+	text   string
+	noteID string
+}
+
+// Option настраивает индекс подсказок
+type Option func(*Index)
+
+// WithClock задает источник текущего времени, от которого считается давность заметок (по умолчанию time.Now)
+func WithClock(now func() time.Time) Option {
+	return func(x *Index) {
+		x.now = now
+	}
+}
+
+// NewIndex создает пустой индекс подсказок
+// Run загружает заголовки из noteRepository и поддерживает индекс по событиям events
+func NewIndex(noteRepository repository.NoteRepository, events notes.EventBus, opts ...Option) *Index {
+	x := &Index{
+		noteRepository: noteRepository,
+		events:         events,
+		now:            time.Now,
+		notes:          make(map[string]*entry),
+		owners:         make(map[string][]key),
+	}
+	for _, opt := range opts {
+		opt(x)
+	}
+	return x
+}
+
+// Run загружает заголовки из хранилища и применяет события заметок, пока не отменен ctx
+// Если Run не успевал читать события и был отключен от шины, пропущенные события запрашиваются
+// из журнала, а если они уже вытеснены - индекс загружается из хранилища заново
+func (x *Index) Run(ctx context.Context) error {
+	// Подписываемся до загрузки, чтобы не потерять изменения, сделанные во время нее
+	ch := x.events.Subscribe()
+	defer func() { x.events.Unsubscribe(ch) }()
+	if err := x.Load(ctx); err != nil {
+		return err
+	}
+
+	var lastEventID uint64
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event, ok := <-ch:
+			if ok {
+				lastEventID = event.ID
+				x.Apply(event)
+				continue
+			}
+
+			var missed []notes.Event
+			var err error
+			if ch, missed, err = x.events.SubscribeAfter(lastEventID); err != nil {
+				log.Printf("Suggestion index fell behind, reloading titles: %v", err)
+				ch = x.events.Subscribe()
+				if err := x.Load(ctx); err != nil {
+					log.Printf("Failed to reload suggestion index: %v", err)
+				}
+			}
+			for _, event := range missed {
+				lastEventID = event.ID
+				x.Apply(event)
+			}
+		}
+	}
+}
+
+// Load заменяет содержимое индекса заголовками заметок из хранилища
+// Счетчики просмотров сохранившихся заметок переносятся в новый индекс
+func (x *Index) Load(ctx context.Context) error {
+	loaded := make(map[string]*entry)
+	owners := make(map[string][]key)
+	fn := func(note model.Note) error {
+		e := newEntry(note)
+		loaded[note.ID] = e
+		for _, text := range e.keys {
+			owners[note.OwnerID] = append(owners[note.OwnerID], key{text: text, noteID: note.ID})
+		}
+		return nil
+	}
+
+	if iterator, ok := x.noteRepository.(repository.NoteIterator); ok {
+		if err := iterator.ForEach(ctx, loadBatchSize, fn); err != nil {
+			return err
+		}
+	} else {
+		all, err := x.noteRepository.List(ctx)
+		if err != nil {
+			return err
+		}
+		for _, note := range all {
+			_ = fn(note)
+		}
+	}
+	for _, keys := range owners {
+		slices.SortFunc(keys, compareKeys)
+	}
+
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	for id, e := range loaded {
+		if old, ok := x.notes[id]; ok {
+			e.views, e.viewedAt = old.views, old.viewedAt
+		}
+	}
+	x.notes, x.owners = loaded, owners
+	return nil
+}
+
+// Apply обновляет индекс по событию заметки
+// События с версией заметки старше проиндексированной пропускаются
+func (x *Index) Apply(event notes.Event) {
+	switch event.Type {
+	case notes.EventNoteCreated, notes.EventNoteUpdated:
+		x.upsert(event.Note)
+	case notes.EventNoteDeleted:
+		x.remove(event.Note.ID)
+	}
+}
+
+// RecordView учитывает просмотр заметки в ранге ее подсказки
+func (x *Index) RecordView(noteID string) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	if e, ok := x.notes[noteID]; ok {
+		e.views++
+		e.viewedAt = x.now()
+	}
+}
+
+// Suggest возвращает до limit заметок вызывающего пользователя, заголовок или одно из слов заголовка
+// которых начинается с prefix (без учета регистра), от более просматриваемых и недавних к остальным
+// limit 0 означает DefaultLimit
+func (x *Index) Suggest(ctx context.Context, prefix string, limit int) ([]model.NoteSuggestion, error) {
+	principal, ok := auth.FromContext(ctx)
+	if !ok {
+		return nil, auth.ErrPermissionDenied
+	}
+	prefix = normalize(prefix)
+	if prefix == "" {
+		return nil, ErrEmptyPrefix
+	}
+	if limit <= 0 {
+		limit = DefaultLimit
+	}
+	limit = min(limit, MaxLimit)
+
+	type candidate struct {
+		model.NoteSuggestion
+		score float64
+	}
+
+	x.mu.RLock()
+	now := x.now()
+	keys := x.owners[principal.UserID]
+	start, _ := slices.BinarySearchFunc(keys, prefix, func(k key, prefix string) int {
+		return strings.Compare(k.text, prefix)
+	})
+	seen := make(map[string]bool)
+	var candidates []candidate
+	for _, k := range keys[start:] {
+		if !strings.HasPrefix(k.text, prefix) {
+			break
+		}
+		if seen[k.noteID] {
+			continue
+		}
+		seen[k.noteID] = true
+		e := x.notes[k.noteID]
+		candidates = append(candidates, candidate{
+			NoteSuggestion: model.NoteSuggestion{NoteID: k.noteID, Title: e.title, UpdatedAt: e.updatedAt, Views: e.views},
+			score:          e.score(now),
+		})
+	}
+	x.mu.RUnlock()
+
+	slices.SortFunc(candidates, func(a, b candidate) int {
+		if c := cmp.Compare(b.score, a.score); c != 0 {
+			return c
+		}
+		if c := b.UpdatedAt.Compare(a.UpdatedAt); c != 0 {
+			return c
+		}
+		return cmp.Or(strings.Compare(a.Title, b.Title), strings.Compare(a.NoteID, b.NoteID))
+	})
+
+	suggestions := make([]model.NoteSuggestion, 0, min(limit, len(candidates)))
+	for _, c := range candidates[:min(limit, len(candidates))] {
+		suggestions = append(suggestions, c.NoteSuggestion)
+	}
+	return suggestions, nil
+}
+
+// upsert добавляет заметку в индекс или обновляет ее заголовок
+func (x *Index) upsert(note model.Note) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	old, ok := x.notes[note.ID]
+	if ok && note.Version < old.version {
+		return
+	}
+	e := newEntry(note)
+	if ok {
+		e.views, e.viewedAt = old.views, old.viewedAt
+		if old.title == e.title && old.ownerID == e.ownerID {
+			x.notes[note.ID] = e
+			return
+		}
+		x.removeKeys(note.ID, old)
+	}
+	x.notes[note.ID] = e
+
+	keys := x.owners[note.OwnerID]
+	for _, text := range e.keys {
+		k := key{text: text, noteID: note.ID}
+		i, _ := slices.BinarySearchFunc(keys, k, compareKeys)
+		keys = slices.Insert(keys, i, k)
+	}
+	x.owners[note.OwnerID] = keys
+}
+
+// remove удаляет заметку из индекса
+func (x *Index) remove(noteID string) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	if e, ok := x.notes[noteID]; ok {
+		x.removeKeys(noteID, e)
+		delete(x.notes, noteID)
+	}
+}
+
+// removeKeys удаляет ключи заметки из среза ее владельца; вызывается под x.mu
+func (x *Index) removeKeys(noteID string, e *entry) {
+	keys := x.owners[e.ownerID]
+	for _, text := range e.keys {
+		if i, found := slices.BinarySearchFunc(keys, key{text: text, noteID: noteID}, compareKeys); found {
+			keys = slices.Delete(keys, i, i+1)
+		}
+	}
+	if len(keys) == 0 {
+		delete(x.owners, e.ownerID)
+		return
+	}
+	x.owners[e.ownerID] = keys
+}
+
+// newEntry создает запись индекса для заметки
+func newEntry(note model.Note) *entry {
+	words := strings.Fields(normalize(note.Title))
+	keys := make([]string, 0, min(len(words), maxIndexedWords))
+	for i := range min(len(words), maxIndexedWords) {
+		text := strings.Join(words[i:], " ")
+		if !slices.Contains(keys, text) {
+			keys = append(keys, text)
+		}
+	}
+	return &entry{
+		ownerID:   note.OwnerID,
+		title:     note.Title,
+		keys:      keys,
+		version:   note.Version,
+		updatedAt: note.UpdatedAt,
+	}
+}
+
+// score ранг подсказки: логарифм числа просмотров плюс вклад давности последнего изменения или просмотра,
+// убывающий вдвое за recencyHalfLife
+func (e *entry) score(now time.Time) float64 {
+	last := e.updatedAt
+	if e.viewedAt.After(last) {
+		last = e.viewedAt
+	}
+	age := max(now.Sub(last), 0)
+	return math.Log2(1+float64(e.views)) + recencyWeight*math.Exp2(-float64(age)/float64(recencyHalfLife))
+}
+
+// normalize приводит текст к нижнему регистру и заменяет последовательности пробелов одним пробелом
+func normalize(text string) string {
+	return strings.Join(strings.Fields(strings.ToLower(text)), " ")
+}
+
+// compareKeys упорядочивает ключи по тексту, затем по ID заметки
+func compareKeys(a, b key) int {
+	return cmp.Or(strings.Compare(a.text, b.text), strings.Compare(a.noteID, b.noteID))
+}
@@ -0,0 +1,107 @@
+package suggest
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"notes-service/internal/auth"
+	"notes-service/internal/model"
+	"notes-service/internal/repository/memory"
+	"notes-service/internal/service/notes"
+)
+
+func suggestTitles(t *testing.T, x *Index, userID, prefix string, limit int) []string {
+	t.Helper()
+	ctx := auth.NewContext(context.Background(), auth.Principal{UserID: userID})
+	suggestions, err := x.Suggest(ctx, prefix, limit)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	titles := make([]string, 0, len(suggestions))
+	for _, s := range suggestions {
+		titles = append(titles, s.Title)
+	}
+	return titles
+}
+
+func TestIndex_Suggest(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	x := NewIndex(nil, nil, WithClock(func() time.Time { return now }))
+	put := func(id, owner, title string, updatedAt time.Time, version int64) {
+		x.Apply(notes.Event{Type: notes.EventNoteUpdated, Note: model.Note{
+			ID: id, OwnerID: owner, Title: title, UpdatedAt: updatedAt, Version: version,
+		}})
+	}
+	put("1", "alice", "Plan for Q3", now.Add(-30*24*time.Hour), 1)
+	put("2", "alice", "Trip  PLAN", now.Add(-time.Hour), 1)
+	put("3", "alice", "Planets", now.Add(-60*24*time.Hour), 1)
+	put("4", "alice", "Shopping list", now, 1)
+	put("5", "bob", "Plan of bob", now, 1)
+
+	// Префикс совпадает с началом любого слова, недавно измененные заметки выше
+	if got := suggestTitles(t, x, "alice", "plan", 0); !slices.Equal(got, []string{"Trip  PLAN", "Plan for Q3", "Planets"}) {
+		t.Errorf("Expected alice's plan notes by recency, got %v", got)
+	}
+	if got := suggestTitles(t, x, "alice", "TRIP pl", 0); !slices.Equal(got, []string{"Trip  PLAN"}) {
+		t.Errorf("Expected a multi-word prefix to match, got %v", got)
+	}
+
+	// Просмотры поднимают заметку выше недавно измененных
+	for range 20 {
+		x.RecordView("3")
+	}
+	if got := suggestTitles(t, x, "alice", "plan", 2); !slices.Equal(got, []string{"Planets", "Trip  PLAN"}) {
+		t.Errorf("Expected viewed note first and limit 2, got %v", got)
+	}
+
+	// Событие со старой версией не меняет индекс, переименование и удаление - меняют
+	put("2", "alice", "Old title", now, 0)
+	put("1", "alice", "Budget", now, 2)
+	x.Apply(notes.Event{Type: notes.EventNoteDeleted, Note: model.Note{ID: "3", OwnerID: "alice"}})
+	if got := suggestTitles(t, x, "alice", "plan", 0); !slices.Equal(got, []string{"Trip  PLAN"}) {
+		t.Errorf("Expected only the trip plan after rename and delete, got %v", got)
+	}
+	if got := suggestTitles(t, x, "alice", "bud", 0); !slices.Equal(got, []string{"Budget"}) {
+		t.Errorf("Expected the renamed note, got %v", got)
+	}
+
+	if _, err := x.Suggest(context.Background(), "plan", 0); !errors.Is(err, auth.ErrPermissionDenied) {
+		t.Errorf("Expected permission denied without a principal, got: %v", err)
+	}
+	if _, err := x.Suggest(auth.NewContext(context.Background(), auth.Principal{UserID: "alice"}), "  ", 0); !errors.Is(err, ErrEmptyPrefix) {
+		t.Errorf("Expected empty prefix error, got: %v", err)
+	}
+}
+
+func TestIndex_Load(t *testing.T) {
+	repo := memory.NewRepository()
+	ctx := context.Background()
+	first, err := repo.Create(ctx, model.Note{Title: "Meeting notes", OwnerID: "alice"})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	x := NewIndex(repo, nil)
+	if err := x.Load(ctx); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	x.RecordView(first.ID)
+
+	// Повторная загрузка добавляет новые заметки и сохраняет просмотры
+	if _, err := repo.Create(ctx, model.Note{Title: "Meetup ideas", OwnerID: "alice"}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if err := x.Load(ctx); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	suggestions, err := x.Suggest(auth.NewContext(ctx, auth.Principal{UserID: "alice"}), "meet", 0)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(suggestions) != 2 || suggestions[0].NoteID != first.ID || suggestions[0].Views != 1 {
+		t.Errorf("Expected the viewed note first with its view kept, got %+v", suggestions)
+	}
+}
@@ -0,0 +1,301 @@
+// Package usage собирает обезличенную статистику использования сервиса: количество вызовов
+// gRPC методов и использование отдельных функций. Пользователи и данные запросов не собираются
+package usage
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"notes-service/internal/auth"
+	"notes-service/internal/buildinfo"
+	"notes-service/internal/model"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// DefaultReportInterval интервал отправки статистики на внешний адрес по умолчанию
+	DefaultReportInterval = 24 * time.Hour
+
+	// DefaultTimeout время ожидания ответа внешнего адреса по умолчанию
+	DefaultTimeout = 10 * time.Second
+)
+
+// counter счетчики вызовов метода
+type counter struct {
+	calls  int64
+	errors int64
+}
+
+// Collector накапливает статистику использования в памяти и, если задан адрес,
+// периодически отправляет на него приращения счетчиков (см. Run)
+type Collector struct {
+	installationID string
+	endpoint       string
+	interval       time.Duration
+	client         *http.Client
+	now            func() time.Time
+
+	mu       sync.Mutex
+	since    time.Time
+	methods  map[string]counter // Счетчики с момента запуска
+	features map[string]int64
+
+	unsentSince    time.Time // Начало периода, приращения которого еще не отправлены
+	unsentMethods  map[string]counter
+	unsentFeatures map[string]int64
+	reporting      model.UsageReporting
+}
+
+// Option настраивает сборщик статистики
+type Option func(*Collector)
+
+// WithEndpoint задает адрес, на который POST запросом отправляется статистика
+// (по умолчанию статистика только накапливается локально)
+func WithEndpoint(endpoint string) Option {
+	return func(c *Collector) {
+		c.endpoint = endpoint
+	}
+}
+
+// WithReportInterval задает интервал отправки статистики (по умолчанию DefaultReportInterval)
+func WithReportInterval(interval time.Duration) Option {
+	return func(c *Collector) {
+		if interval > 0 {
+			c.interval = interval
+		}
+	}
+}
+
+// WithHTTPClient задает клиент, которым отправляется статистика
+func WithHTTPClient(client *http.Client) Option {
+	return func(c *Collector) {
+		c.client = client
+	}
+}
+
+// WithClock задает источник времени (по умолчанию time.Now)
+func WithClock(now func() time.Time) Option {
+	return func(c *Collector) {
+		c.now = now
+	}
+}
+
+// NewCollector создает сборщик статистики со случайным ID реплики
+func NewCollector(opts ...Option) *Collector {
+	c := &Collector{
+		installationID: uuid.New().String(),
+		interval:       DefaultReportInterval,
+		client:         &http.Client{Timeout: DefaultTimeout},
+		now:            time.Now,
+		methods:        make(map[string]counter),
+		features:       make(map[string]int64),
+		unsentMethods:  make(map[string]counter),
+		unsentFeatures: make(map[string]int64),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	c.since = c.now()
+	c.unsentSince = c.since
+	c.reporting.Enabled = c.endpoint != ""
+	return c
+}
+
+// RecordCall учитывает вызов gRPC метода method (полное имя); failed - вызов завершился ошибкой
+func (c *Collector) RecordCall(method string, failed bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.methods[method] = c.methods[method].add(failed)
+	c.unsentMethods[method] = c.unsentMethods[method].add(failed)
+}
+
+// RecordFeature учитывает использование функции feature
+func (c *Collector) RecordFeature(feature string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.features[feature]++
+	c.unsentFeatures[feature]++
+}
+
+func (cnt counter) add(failed bool) counter {
+	cnt.calls++
+	if failed {
+		cnt.errors++
+	}
+	return cnt
+}
+
+// Stats возвращает статистику с момента запуска. Доступно только администраторам
+func (c *Collector) Stats(ctx context.Context) (model.UsageStats, error) {
+	principal, ok := auth.FromContext(ctx)
+	if !ok || !principal.HasRole(auth.RoleAdmin) {
+		return model.UsageStats{}, auth.ErrPermissionDenied
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return model.UsageStats{
+		InstallationID: c.installationID,
+		Since:          c.since,
+		Methods:        methodUsage(c.methods),
+		Features:       featureUsage(c.features),
+		Reporting:      c.reporting,
+	}, nil
+}
+
+// Run отправляет статистику на внешний адрес раз в интервал, пока не отменен ctx
+// При остановке отправляет накопленное с последней отправки. Без адреса сразу возвращает nil
+func (c *Collector) Run(ctx context.Context) error {
+	if c.endpoint == "" {
+		return nil
+	}
+
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			// Контекст сервера уже отменен, последняя отправка ограничена своим таймаутом
+			flushCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), DefaultTimeout)
+			defer cancel()
+			c.Report(flushCtx)
+			return nil
+		case <-ticker.C:
+			c.Report(ctx)
+		}
+	}
+}
+
+// report тело запроса отправки статистики
+type report struct {
+	InstallationID string          `json:"installation_id"`
+	Version        string          `json:"version"`
+	From           time.Time       `json:"from"`
+	To             time.Time       `json:"to"`
+	Methods        []methodReport  `json:"methods"`
+	Features       []featureReport `json:"features"`
+}
+
+type methodReport struct {
+	Method string `json:"method"`
+	Calls  int64  `json:"calls"`
+	Errors int64  `json:"errors"`
+}
+
+type featureReport struct {
+	Feature string `json:"feature"`
+	Count   int64  `json:"count"`
+}
+
+// Report отправляет приращения счетчиков с последней успешной отправки
+// При ошибке приращения сохраняются и отправляются вместе со следующими
+func (c *Collector) Report(ctx context.Context) error {
+	c.mu.Lock()
+	if len(c.unsentMethods) == 0 && len(c.unsentFeatures) == 0 {
+		c.mu.Unlock()
+		return nil
+	}
+	now := c.now()
+	body := report{
+		InstallationID: c.installationID,
+		Version:        buildinfo.Version(),
+		From:           c.unsentSince,
+		To:             now,
+	}
+	for _, m := range methodUsage(c.unsentMethods) {
+		body.Methods = append(body.Methods, methodReport{Method: m.Method, Calls: m.Calls, Errors: m.Errors})
+	}
+	for _, f := range featureUsage(c.unsentFeatures) {
+		body.Features = append(body.Features, featureReport{Feature: f.Feature, Count: f.Count})
+	}
+	methods, features := c.unsentMethods, c.unsentFeatures
+	c.unsentMethods, c.unsentFeatures = make(map[string]counter), make(map[string]int64)
+	c.mu.Unlock()
+
+	err := c.send(ctx, body)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.reporting.LastError = err
+	if err != nil {
+		// Вызовы, учтенные во время отправки, складываются с неотправленными
+		for method, cnt := range methods {
+			pending := c.unsentMethods[method]
+			c.unsentMethods[method] = counter{calls: pending.calls + cnt.calls, errors: pending.errors + cnt.errors}
+		}
+		for feature, count := range features {
+			c.unsentFeatures[feature] += count
+		}
+		log.Printf("Failed to report usage stats: %v", err)
+		return err
+	}
+	c.unsentSince = now
+	c.reporting.LastReportAt = now
+	c.reporting.Reports++
+	return nil
+}
+
+// send отправляет статистику POST запросом в JSON
+func (c *Collector) send(ctx context.Context, body report) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "notes-service/"+buildinfo.Version())
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("usage endpoint responded with %s", resp.Status)
+	}
+	return nil
+}
+
+// methodUsage возвращает счетчики методов, упорядоченные по имени метода
+func methodUsage(counters map[string]counter) []model.MethodUsage {
+	usage := make([]model.MethodUsage, 0, len(counters))
+	for _, method := range slices.Sorted(maps.Keys(counters)) {
+		cnt := counters[method]
+		usage = append(usage, model.MethodUsage{Method: method, Calls: cnt.calls, Errors: cnt.errors})
+	}
+	return usage
+}
+
+// featureUsage возвращает счетчики функций, упорядоченные по имени функции
+func featureUsage(counters map[string]int64) []model.FeatureUsage {
+	usage := make([]model.FeatureUsage, 0, len(counters))
+	for _, feature := range slices.Sorted(maps.Keys(counters)) {
+		usage = append(usage, model.FeatureUsage{Feature: feature, Count: counters[feature]})
+	}
+	return usage
+}
+
+// OptedOut сообщает, отказался ли оператор от сбора статистики через переменную окружения
+// DO_NOT_TRACK (https://consoledonottrack.com): любое значение, кроме пустого, "0" и "false"
+func OptedOut(doNotTrack string) bool {
+	switch strings.ToLower(strings.TrimSpace(doNotTrack)) {
+	case "", "0", "false":
+		return false
+	default:
+		return true
+	}
+}
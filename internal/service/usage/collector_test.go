@@ -0,0 +1,131 @@
+package usage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"notes-service/internal/auth"
+)
+
+var admin = auth.NewContext(context.Background(), auth.Principal{UserID: "admin", Roles: []string{auth.RoleAdmin}})
+
+// endpoint тестовый адрес отправки статистики, отвечающий status
+type endpoint struct {
+	mu      sync.Mutex
+	status  int
+	reports []report
+}
+
+func (e *endpoint) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var body report
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.status != http.StatusOK {
+		w.WriteHeader(e.status)
+		return
+	}
+	e.reports = append(e.reports, body)
+}
+
+func TestCollector_Stats(t *testing.T) {
+	collector := NewCollector()
+	collector.RecordCall("/notes.v1.NotesService/ListNotes", false)
+	collector.RecordCall("/notes.v1.NotesService/CreateNote", true)
+	collector.RecordCall("/notes.v1.NotesService/CreateNote", false)
+	collector.RecordFeature("update.field_mask")
+	collector.RecordFeature("notes.e2e")
+	collector.RecordFeature("notes.e2e")
+
+	if _, err := collector.Stats(context.Background()); !errors.Is(err, auth.ErrPermissionDenied) {
+		t.Errorf("Expected ErrPermissionDenied without admin role, got: %v", err)
+	}
+
+	stats, err := collector.Stats(admin)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(stats.Methods) != 2 || stats.Methods[0].Method != "/notes.v1.NotesService/CreateNote" ||
+		stats.Methods[0].Calls != 2 || stats.Methods[0].Errors != 1 || stats.Methods[1].Calls != 1 {
+		t.Errorf("Unexpected methods: %+v", stats.Methods)
+	}
+	if len(stats.Features) != 2 || stats.Features[0].Feature != "notes.e2e" || stats.Features[0].Count != 2 {
+		t.Errorf("Unexpected features: %+v", stats.Features)
+	}
+	if stats.Reporting.Enabled {
+		t.Error("Expected reporting to be disabled without endpoint")
+	}
+	if err := collector.Run(context.Background()); err != nil {
+		t.Errorf("Expected Run without endpoint to return immediately, got: %v", err)
+	}
+}
+
+func TestCollector_Report(t *testing.T) {
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	remote := &endpoint{status: http.StatusServiceUnavailable}
+	server := httptest.NewServer(remote)
+	defer server.Close()
+
+	collector := NewCollector(WithEndpoint(server.URL), WithClock(func() time.Time { return now }))
+	if err := collector.Report(context.Background()); err != nil || len(remote.reports) != 0 {
+		t.Fatalf("Expected nothing to be reported without usage, got: %v", err)
+	}
+
+	// При ошибке приращения сохраняются до следующей отправки
+	collector.RecordCall("/notes.v1.NotesService/GetNote", false)
+	if err := collector.Report(context.Background()); err == nil {
+		t.Fatal("Expected report error")
+	}
+	collector.RecordCall("/notes.v1.NotesService/GetNote", true)
+	collector.RecordFeature("chat.typing")
+
+	remote.status = http.StatusOK
+	now = now.Add(time.Hour)
+	if err := collector.Report(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(remote.reports) != 1 {
+		t.Fatalf("Expected 1 report, got %d", len(remote.reports))
+	}
+	first := remote.reports[0]
+	if len(first.Methods) != 1 || first.Methods[0].Calls != 2 || first.Methods[0].Errors != 1 ||
+		len(first.Features) != 1 || first.Features[0].Count != 1 || first.InstallationID == "" || !first.To.Equal(now) {
+		t.Errorf("Unexpected report: %+v", first)
+	}
+
+	// Следующая отправка содержит только новые вызовы
+	collector.RecordCall("/notes.v1.NotesService/GetNote", false)
+	now = now.Add(time.Hour)
+	if err := collector.Report(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	second := remote.reports[1]
+	if len(second.Methods) != 1 || second.Methods[0].Calls != 1 || len(second.Features) != 0 || !second.From.Equal(first.To) {
+		t.Errorf("Unexpected report: %+v", second)
+	}
+
+	stats, err := collector.Stats(admin)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if stats.Methods[0].Calls != 3 || stats.Reporting.Reports != 2 || stats.Reporting.LastError != nil || !stats.Reporting.LastReportAt.Equal(now) {
+		t.Errorf("Unexpected stats: %+v", stats)
+	}
+}
+
+func TestOptedOut(t *testing.T) {
+	for value, want := range map[string]bool{"": false, "0": false, "false": false, "1": true, "true": true, " yes ": true} {
+		if got := OptedOut(value); got != want {
+			t.Errorf("OptedOut(%q) = %v, want %v", value, got, want)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package users
+
+import (
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	// passwordIterations число итераций PBKDF2-SHA256 для новых паролей (рекомендация OWASP)
+	passwordIterations = 600_000
+
+	// passwordSaltSize размер соли пароля в байтах
+	passwordSaltSize = 16
+
+	// pbkdf2Prefix префикс хэша PBKDF2: "pbkdf2-sha256$<итерации>$<соль>$<хэш>" (соль и хэш в base64)
+	pbkdf2Prefix = "pbkdf2-sha256$"
+
+	// sha256Prefix префикс хэша SHA-256 в hex, как в паролях auth.sessions.users конфигурации
+	sha256Prefix = "sha256:"
+)
+
+// dummyPasswordHash хэш, с которым сравнивается пароль неизвестного пользователя,
+// чтобы время ответа не выдавало существующие имена. Вычисляется при первом входе
+var dummyPasswordHash = sync.OnceValue(func() string {
+	hash, err := hashPassword("dummy-password")
+	if err != nil {
+		panic(err)
+	}
+	return hash
+})
+
+// hashPassword возвращает хэш PBKDF2-SHA256 пароля со случайной солью
+func hashPassword(password string) (string, error) {
+	salt := make([]byte, passwordSaltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+	key, err := pbkdf2.Key(sha256.New, password, salt, passwordIterations, sha256.Size)
+	if err != nil {
+		return "", err
+	}
+	return pbkdf2Prefix + strconv.Itoa(passwordIterations) + "$" +
+		base64.RawStdEncoding.EncodeToString(salt) + "$" + base64.RawStdEncoding.EncodeToString(key), nil
+}
+
+// validatePasswordHash проверяет формат заранее вычисленного хэша пароля
+func validatePasswordHash(hash string) error {
+	if _, _, _, err := parsePBKDF2(hash); err == nil {
+		return nil
+	}
+	if sum, ok := strings.CutPrefix(hash, sha256Prefix); ok {
+		if decoded, err := hex.DecodeString(sum); err == nil && len(decoded) == sha256.Size {
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid password hash: expected %s<hex> or %s<iterations>$<salt>$<hash>", sha256Prefix, pbkdf2Prefix)
+}
+
+// checkPassword сравнивает пароль с хэшем за постоянное время
+func checkPassword(hash, password string) bool {
+	if sum, ok := strings.CutPrefix(hash, sha256Prefix); ok {
+		want, _ := hex.DecodeString(sum)
+		got := sha256.Sum256([]byte(password))
+		return subtle.ConstantTimeCompare(want, got[:]) == 1
+	}
+
+	iterations, salt, want, err := parsePBKDF2(hash)
+	if err != nil {
+		return false
+	}
+	got, err := pbkdf2.Key(sha256.New, password, salt, iterations, len(want))
+	if err != nil {
+		return false
+	}
+	return subtle.ConstantTimeCompare(want, got) == 1
+}
+
+// parsePBKDF2 разбирает хэш "pbkdf2-sha256$<итерации>$<соль>$<хэш>"
+func parsePBKDF2(hash string) (iterations int, salt, key []byte, err error) {
+	rest, ok := strings.CutPrefix(hash, pbkdf2Prefix)
+	parts := strings.Split(rest, "$")
+	if !ok || len(parts) != 3 {
+		return 0, nil, nil, fmt.Errorf("invalid pbkdf2 hash")
+	}
+	if iterations, err = strconv.Atoi(parts[0]); err != nil || iterations < 1 {
+		return 0, nil, nil, fmt.Errorf("invalid pbkdf2 iterations %q", parts[0])
+	}
+	if salt, err = base64.RawStdEncoding.DecodeString(parts[1]); err != nil {
+		return 0, nil, nil, fmt.Errorf("invalid pbkdf2 salt: %w", err)
+	}
+	if key, err = base64.RawStdEncoding.DecodeString(parts[2]); err != nil || len(key) == 0 {
+		return 0, nil, nil, fmt.Errorf("invalid pbkdf2 hash")
+	}
+	return iterations, salt, key, nil
+}
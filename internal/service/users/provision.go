@@ -0,0 +1,50 @@
+package users
+
+import (
+	"context"
+	"errors"
+	"log"
+	"slices"
+
+	"notes-service/internal/auth"
+	"notes-service/internal/repository/memory"
+)
+
+// provisioningAuthenticator добавляет в хранилище пользователей, впервые предъявивших токен
+type provisioningAuthenticator struct {
+	next  auth.Authenticator
+	users *Service
+}
+
+// Provisioning оборачивает проверку токенов next: пользователь, которого еще нет в хранилище
+// (например, из JWT или OIDC провайдера), добавляется в него с ролями из токена без пароля.
+// Так на любого аутентифицированного пользователя можно сослаться при предоставлении доступа
+func (s *Service) Provisioning(next auth.Authenticator) auth.Authenticator {
+	return &provisioningAuthenticator{next: next, users: s}
+}
+
+// Authenticate проверяет токен через next и добавляет нового пользователя в хранилище
+// Ошибка хранилища не отклоняет токен, а только логируется
+func (a *provisioningAuthenticator) Authenticate(ctx context.Context, token string) (auth.Principal, error) {
+	principal, err := a.next.Authenticate(ctx, token)
+	if err != nil {
+		return auth.Principal{}, err
+	}
+
+	exists, err := a.users.Exists(ctx, principal.UserID)
+	if err == nil && !exists {
+		// Роли внешнего провайдера, неизвестные сервису, не сохраняются
+		roles := slices.DeleteFunc(slices.Clone(principal.Roles), func(role string) bool {
+			return !slices.Contains(knownRoles, role)
+		})
+		_, err = a.users.create(ctx, CreateUserInput{ID: principal.UserID, Roles: roles})
+		if err == nil {
+			log.Printf("Provisioned user %s on first authentication", principal.UserID)
+		}
+	}
+	if err != nil && !errors.Is(err, memory.ErrUserExists) {
+		log.Printf("Failed to provision user %s: %v", principal.UserID, err)
+	}
+
+	return principal, nil
+}
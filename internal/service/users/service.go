@@ -0,0 +1,192 @@
+// Package users реализует хранилище пользователей сервиса: на них ссылаются владельцы
+// заметок и доступы, по ним проверяются пароли при входе через AuthService
+package users
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+	"time"
+
+	"notes-service/internal/auth"
+	"notes-service/internal/model"
+	"notes-service/internal/repository"
+	"notes-service/internal/repository/memory"
+)
+
+// knownRoles роли, которые можно назначить пользователю
+var knownRoles = []string{auth.RoleUser, auth.RoleAdmin, auth.RoleReader, auth.RoleWriter}
+
+// CreateUserInput параметры создания пользователя
+type CreateUserInput struct {
+	ID           string   // Идентификатор пользователя (по умолчанию Username)
+	Username     string   // Имя для входа по паролю (опционально)
+	Password     string   // Пароль открытым текстом, хранится только его хэш
+	PasswordHash string   // Заранее вычисленный хэш пароля (sha256:<hex> из конфигурации) вместо Password
+	Roles        []string // Роли пользователя (auth.RoleUser добавляется всегда)
+}
+
+// Service управляет пользователями
+// Создавать пользователей и просматривать чужие записи может только администратор
+type Service struct {
+	repository repository.UserRepository
+	now        func() time.Time
+}
+
+// Option настраивает сервис пользователей
+type Option func(*Service)
+
+// WithClock задает источник текущего времени (по умолчанию time.Now)
+func WithClock(now func() time.Time) Option {
+	return func(s *Service) {
+		s.now = now
+	}
+}
+
+var _ auth.UserStore = (*Service)(nil)
+
+// NewService создает сервис пользователей поверх хранилища repository
+func NewService(repository repository.UserRepository, opts ...Option) *Service {
+	s := &Service{repository: repository, now: time.Now}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Create создает пользователя (только для администратора)
+func (s *Service) Create(ctx context.Context, input CreateUserInput) (model.User, error) {
+	principal, ok := auth.FromContext(ctx)
+	if !ok || !principal.HasRole(auth.RoleAdmin) {
+		return model.User{}, auth.ErrPermissionDenied
+	}
+
+	user, err := s.create(ctx, input)
+	if err != nil {
+		return model.User{}, err
+	}
+	log.Printf("User %s created by %s", user.ID, principal.UserID)
+	return user, nil
+}
+
+// Ensure создает пользователя без проверки прав, если пользователя с таким ID еще нет,
+// и возвращает сохраненного пользователя. Используется для пользователей из конфигурации
+func (s *Service) Ensure(ctx context.Context, input CreateUserInput) (model.User, error) {
+	user, err := s.repository.GetByID(ctx, strings.TrimSpace(cmp.Or(input.ID, input.Username)))
+	if !errors.Is(err, memory.ErrUserNotFound) {
+		return user, err
+	}
+
+	return s.create(ctx, input)
+}
+
+// Get возвращает пользователя по ID: свою запись или любую для администратора
+// Чужой пользователь для остальных неотличим от несуществующего
+func (s *Service) Get(ctx context.Context, id string) (model.User, error) {
+	principal, ok := auth.FromContext(ctx)
+	if !ok || (principal.UserID != id && !principal.HasRole(auth.RoleAdmin)) {
+		return model.User{}, memory.ErrUserNotFound
+	}
+	if id == "" {
+		return model.User{}, errors.New("user id cannot be empty")
+	}
+
+	return s.repository.GetByID(ctx, id)
+}
+
+// List возвращает всех пользователей (только для администратора)
+func (s *Service) List(ctx context.Context) ([]model.User, error) {
+	principal, ok := auth.FromContext(ctx)
+	if !ok || !principal.HasRole(auth.RoleAdmin) {
+		return nil, auth.ErrPermissionDenied
+	}
+
+	return s.repository.List(ctx)
+}
+
+// Exists проверяет, что пользователь с ID id существует
+func (s *Service) Exists(ctx context.Context, id string) (bool, error) {
+	_, err := s.repository.GetByID(ctx, id)
+	if errors.Is(err, memory.ErrUserNotFound) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// VerifyPassword проверяет пароль пользователя username для входа через auth.Sessions
+func (s *Service) VerifyPassword(ctx context.Context, username, password string) (auth.Principal, error) {
+	user, err := s.repository.GetByUsername(ctx, username)
+	if err != nil && !errors.Is(err, memory.ErrUserNotFound) {
+		return auth.Principal{}, err
+	}
+
+	hash := user.PasswordHash
+	if hash == "" {
+		// Пароль сравнивается и для неизвестного пользователя, чтобы время ответа не выдавало имена
+		hash = dummyPasswordHash()
+	}
+	if !checkPassword(hash, password) || user.PasswordHash == "" {
+		return auth.Principal{}, auth.ErrInvalidCredentials
+	}
+
+	return auth.Principal{UserID: user.ID, Roles: slices.Clone(user.Roles)}, nil
+}
+
+// create проверяет параметры и сохраняет нового пользователя
+func (s *Service) create(ctx context.Context, input CreateUserInput) (model.User, error) {
+	user := model.User{
+		ID:        strings.TrimSpace(cmp.Or(input.ID, input.Username)),
+		Username:  strings.TrimSpace(input.Username),
+		CreatedAt: s.now(),
+	}
+	if user.ID == "" {
+		return model.User{}, errors.New("user id or username cannot be empty")
+	}
+
+	roles, err := normalizeRoles(input.Roles)
+	if err != nil {
+		return model.User{}, err
+	}
+	user.Roles = roles
+
+	switch {
+	case user.Username == "" && (input.Password != "" || input.PasswordHash != ""):
+		return model.User{}, errors.New("invalid user: password requires a username")
+	case user.Username == "":
+	case input.PasswordHash != "":
+		if err := validatePasswordHash(input.PasswordHash); err != nil {
+			return model.User{}, fmt.Errorf("user %q: %w", user.Username, err)
+		}
+		user.PasswordHash = input.PasswordHash
+	case input.Password == "":
+		return model.User{}, errors.New("password cannot be empty for a user with username")
+	default:
+		if user.PasswordHash, err = hashPassword(input.Password); err != nil {
+			return model.User{}, err
+		}
+	}
+
+	if err := s.repository.Create(ctx, user); err != nil {
+		return model.User{}, err
+	}
+	return user, nil
+}
+
+// normalizeRoles проверяет роли, добавляет auth.RoleUser и удаляет повторы
+func normalizeRoles(roles []string) ([]string, error) {
+	result := []string{auth.RoleUser}
+	for _, role := range roles {
+		role = strings.TrimSpace(role)
+		if !slices.Contains(knownRoles, role) {
+			return nil, fmt.Errorf("invalid role %q (expected %s)", role, strings.Join(knownRoles, " or "))
+		}
+		if !slices.Contains(result, role) {
+			result = append(result, role)
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,146 @@
+package users
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"notes-service/internal/auth"
+	"notes-service/internal/repository/memory"
+)
+
+func TestService_CreateAndVerifyPassword(t *testing.T) {
+	service := NewService(memory.NewUserRepository())
+	admin := auth.NewContext(context.Background(), auth.Principal{UserID: "admin", Roles: []string{auth.RoleUser, auth.RoleAdmin}})
+	alice := auth.NewContext(context.Background(), auth.Principal{UserID: "alice", Roles: []string{auth.RoleUser}})
+
+	// Создавать пользователей может только администратор
+	if _, err := service.Create(alice, CreateUserInput{Username: "bob", Password: "secret"}); !errors.Is(err, auth.ErrPermissionDenied) {
+		t.Errorf("Expected ErrPermissionDenied for non-admin, got %v", err)
+	}
+
+	user, err := service.Create(admin, CreateUserInput{Username: "bob", Password: "secret", Roles: []string{auth.RoleAdmin}})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if user.ID != "bob" || !strings.HasPrefix(user.PasswordHash, pbkdf2Prefix) || strings.Contains(user.PasswordHash, "secret") {
+		t.Errorf("Expected user bob with pbkdf2 password hash, got %+v", user)
+	}
+	if !slices.Equal(user.Roles, []string{auth.RoleUser, auth.RoleAdmin}) {
+		t.Errorf("Expected roles [user admin], got %v", user.Roles)
+	}
+
+	if _, err := service.Create(admin, CreateUserInput{ID: "bob"}); !errors.Is(err, memory.ErrUserExists) {
+		t.Errorf("Expected ErrUserExists for duplicate id, got %v", err)
+	}
+	invalid := []CreateUserInput{
+		{},
+		{Username: "carol"},
+		{ID: "carol", Password: "secret"},
+		{Username: "carol", Password: "secret", Roles: []string{"root"}},
+		{Username: "carol", PasswordHash: "sha256:zz"},
+	}
+	for _, input := range invalid {
+		if _, err := service.Create(admin, input); err == nil {
+			t.Errorf("Expected error for %+v", input)
+		}
+	}
+
+	principal, err := service.VerifyPassword(context.Background(), "bob", "secret")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if principal.UserID != "bob" || !principal.HasRole(auth.RoleAdmin) {
+		t.Errorf("Expected admin bob, got %+v", principal)
+	}
+	for _, credentials := range [][2]string{{"bob", "wrong"}, {"mallory", "secret"}, {"", ""}} {
+		if _, err := service.VerifyPassword(context.Background(), credentials[0], credentials[1]); !errors.Is(err, auth.ErrInvalidCredentials) {
+			t.Errorf("Expected ErrInvalidCredentials for %v, got %v", credentials, err)
+		}
+	}
+}
+
+func TestService_EnsureAcceptsConfigHashes(t *testing.T) {
+	service := NewService(memory.NewUserRepository())
+	ctx := context.Background()
+
+	// sha256("hunter2")
+	hash := "sha256:f52fbd32b2b3b86ff88ef6c490628285f482af15ddcb29541f94bcf526a3f6c7"
+	if _, err := service.Ensure(ctx, CreateUserInput{ID: "user-bob", Username: "bob", PasswordHash: hash}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	// Повторное добавление пользователя с тем же ID (например, владельца статического токена) ничего не меняет
+	user, err := service.Ensure(ctx, CreateUserInput{ID: "user-bob", Roles: []string{auth.RoleAdmin}})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if user.Username != "bob" || user.PasswordHash != hash || slices.Contains(user.Roles, auth.RoleAdmin) {
+		t.Errorf("Expected existing user to be kept, got %+v", user)
+	}
+
+	if _, err := service.VerifyPassword(ctx, "bob", "hunter2"); err != nil {
+		t.Errorf("Expected sha256 password to be accepted, got %v", err)
+	}
+}
+
+func TestService_GetAndListVisibility(t *testing.T) {
+	service := NewService(memory.NewUserRepository())
+	ctx := context.Background()
+	for _, id := range []string{"bob", "alice"} {
+		if _, err := service.Ensure(ctx, CreateUserInput{ID: id}); err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+	}
+	admin := auth.NewContext(ctx, auth.Principal{UserID: "admin", Roles: []string{auth.RoleAdmin}})
+	alice := auth.NewContext(ctx, auth.Principal{UserID: "alice", Roles: []string{auth.RoleUser}})
+
+	if user, err := service.Get(alice, "alice"); err != nil || user.ID != "alice" {
+		t.Errorf("Expected alice to read herself, got %+v, %v", user, err)
+	}
+	// Чужая запись неотличима от несуществующей
+	if _, err := service.Get(alice, "bob"); !errors.Is(err, memory.ErrUserNotFound) {
+		t.Errorf("Expected ErrUserNotFound for another user, got %v", err)
+	}
+	if _, err := service.Get(admin, "bob"); err != nil {
+		t.Errorf("Expected admin to read bob, got %v", err)
+	}
+
+	if _, err := service.List(alice); !errors.Is(err, auth.ErrPermissionDenied) {
+		t.Errorf("Expected ErrPermissionDenied for non-admin list, got %v", err)
+	}
+	list, err := service.List(admin)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(list) != 2 || list[0].ID != "alice" || list[1].ID != "bob" {
+		t.Errorf("Expected [alice bob] ordered by id, got %+v", list)
+	}
+}
+
+func TestService_ProvisionsAuthenticatedUsers(t *testing.T) {
+	service := NewService(memory.NewUserRepository())
+	authenticator := service.Provisioning(auth.StaticTokens{
+		"token": {UserID: "oidc-user", Roles: []string{auth.RoleUser, "editor"}},
+	})
+	ctx := context.Background()
+
+	for range 2 {
+		if _, err := authenticator.Authenticate(ctx, "token"); err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+	}
+	if _, err := authenticator.Authenticate(ctx, "unknown"); !errors.Is(err, auth.ErrInvalidToken) {
+		t.Errorf("Expected ErrInvalidToken, got %v", err)
+	}
+
+	exists, err := service.Exists(ctx, "oidc-user")
+	if err != nil || !exists {
+		t.Fatalf("Expected provisioned user, got %v, %v", exists, err)
+	}
+	user, _ := service.repository.GetByID(ctx, "oidc-user")
+	if user.PasswordHash != "" || !slices.Equal(user.Roles, []string{auth.RoleUser}) {
+		t.Errorf("Expected user without password and unknown roles, got %+v", user)
+	}
+}
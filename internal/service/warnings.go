@@ -0,0 +1,48 @@
+package service
+
+import (
+	"context"
+	"slices"
+	"sync"
+
+	"notes-service/internal/model"
+)
+
+type warningsKey struct{}
+
+// Warnings собирает предупреждения, выданные сервисом при обработке запроса
+type Warnings struct {
+	mu   sync.Mutex
+	list []model.Warning
+}
+
+// WithWarnings подключает к контексту сборщик предупреждений
+// Транспортный слой вызывает его перед обращением к сервису и возвращает собранное клиенту
+func WithWarnings(ctx context.Context) (context.Context, *Warnings) {
+	w := &Warnings{}
+	return context.WithValue(ctx, warningsKey{}, w), w
+}
+
+// AddWarnings добавляет предупреждения в сборщик контекста, одинаковые предупреждения добавляются один раз
+// Без сборщика (внутренние вызовы) предупреждения отбрасываются
+func AddWarnings(ctx context.Context, warnings ...model.Warning) {
+	w, ok := ctx.Value(warningsKey{}).(*Warnings)
+	if !ok || len(warnings) == 0 {
+		return
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for _, warning := range warnings {
+		if !slices.Contains(w.list, warning) {
+			w.list = append(w.list, warning)
+		}
+	}
+}
+
+// List возвращает собранные предупреждения
+func (w *Warnings) List() []model.Warning {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return append([]model.Warning(nil), w.list...)
+}
@@ -0,0 +1,415 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"notes-service/internal/egress"
+	"notes-service/internal/model"
+	"notes-service/internal/repository/memory"
+	"notes-service/internal/service/notes"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// queueSize количество доставок, ожидающих свободного обработчика
+	queueSize = 1024
+
+	// maxErrorBody количество байт тела ответа с ошибкой, сохраняемых в недоставленном событии
+	maxErrorBody = 256
+)
+
+// Заголовки запросов к вебхукам
+const (
+	HeaderWebhookID = "X-Webhook-Id"        // ID вебхука
+	HeaderDelivery  = "X-Webhook-Delivery"  // ID доставки, одинаковый во всех попытках
+	HeaderEvent     = "X-Webhook-Event"     // Тип события (например, note_created)
+	HeaderTimestamp = "X-Webhook-Timestamp" // Время отправки попытки (Unix, секунды)
+	HeaderSignature = "X-Webhook-Signature" // sha256=<hex HMAC-SHA256(secret, timestamp + "." + тело)>
+)
+
+// errPrivateAddress возвращается при попытке отправить событие на адрес внутренней сети
+var errPrivateAddress = egress.ErrPrivateAddress
+
+// delivery событие, отправляемое одному вебхуку
+type delivery struct {
+	id        string
+	webhook   model.Webhook
+	eventType string
+	payload   []byte
+	attempts  int
+	lastError string
+}
+
+// payload тело запроса к вебхуку
+type payload struct {
+	ID      string          `json:"id"`       // ID доставки
+	EventID uint64          `json:"event_id"` // Порядковый номер события в журнале реплики
+	Type    string          `json:"type"`
+	Time    time.Time       `json:"time"`
+	Note    *notePayload    `json:"note,omitempty"`
+	Share   *sharePayload   `json:"share,omitempty"`
+	Export  *exportPayload  `json:"export,omitempty"`
+	Search  *searchPayload  `json:"saved_search,omitempty"`
+	Quota   *quotaPayload   `json:"quota,omitempty"`
+	Webhook webhookMetadata `json:"webhook"`
+}
+
+type webhookMetadata struct {
+	ID      string `json:"id"`
+	OwnerID string `json:"owner_id"`
+}
+
+type notePayload struct {
+	ID               string    `json:"id"`
+	OwnerID          string    `json:"owner_id"`
+	Title            string    `json:"title,omitempty"`
+	Content          string    `json:"content,omitempty"`
+	Tags             []string  `json:"tags,omitempty"`
+	Version          int64     `json:"version,omitempty"`
+	Pinned           bool      `json:"pinned,omitempty"`
+	Archived         bool      `json:"archived,omitempty"`
+	CreatedAt        time.Time `json:"created_at,omitzero"`
+	UpdatedAt        time.Time `json:"updated_at,omitzero"`
+	RemindAt         time.Time `json:"remind_at,omitzero"`
+	IsE2E            bool      `json:"is_e2e,omitempty"`
+	E2EScheme        string    `json:"e2e_scheme,omitempty"`
+	ContentEncrypted []byte    `json:"content_encrypted,omitempty"`
+}
+
+type sharePayload struct {
+	UserID     string `json:"user_id"`
+	Permission string `json:"permission"`
+}
+
+type searchPayload struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Query string `json:"query"`
+}
+
+type quotaPayload struct {
+	Used             int `json:"used"`
+	Limit            int `json:"limit"`
+	ThresholdPercent int `json:"threshold_percent"`
+}
+
+type exportPayload struct {
+	ID            string `json:"id"`
+	Succeeded     bool   `json:"succeeded"`
+	Location      string `json:"location,omitempty"`
+	ExportedNotes int64  `json:"exported_notes"`
+	Error         string `json:"error,omitempty"`
+}
+
+// Run доставляет события вебхукам, пока не отменен ctx
+// Доставки, ожидающие повторной попытки при остановке, сохраняются как недоставленные
+func (s *Service) Run(ctx context.Context) error {
+	queue := make(chan *delivery, queueSize)
+
+	var workers sync.WaitGroup
+	for range s.workers {
+		workers.Go(func() {
+			for d := range queue {
+				s.attempt(ctx, d, queue)
+			}
+		})
+	}
+
+	s.dispatch(ctx, queue)
+
+	// Новые попытки больше не планируются: отменяем ожидающие и дожидаемся обработчиков
+	s.mu.Lock()
+	interrupted := make([]*delivery, 0, len(s.pending))
+	for d, timer := range s.pending {
+		if timer.Stop() {
+			interrupted = append(interrupted, d)
+		}
+	}
+	s.pending = nil
+	s.mu.Unlock()
+	s.retrying.Wait()
+	close(queue)
+	workers.Wait()
+
+	for _, d := range interrupted {
+		s.deadLetter(d, "delivery interrupted by server shutdown: "+d.lastError)
+	}
+	return nil
+}
+
+// dispatch читает события и ставит их доставку в очередь, пока не отменен ctx
+// Если dispatch не успевал читать события и был отключен от шины, пропущенные события
+// запрашиваются повторно из журнала
+func (s *Service) dispatch(ctx context.Context, queue chan<- *delivery) {
+	ch := s.events.Subscribe()
+	defer func() { s.events.Unsubscribe(ch) }()
+
+	var lastEventID uint64
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event, ok := <-ch:
+			if ok {
+				lastEventID = event.ID
+				s.enqueue(ctx, event, queue)
+				continue
+			}
+
+			var missed []notes.Event
+			var err error
+			if ch, missed, err = s.events.SubscribeAfter(lastEventID); err != nil {
+				log.Printf("Webhook dispatcher fell behind, some events will not be delivered: %v", err)
+				ch = s.events.Subscribe()
+			}
+			for _, event := range missed {
+				lastEventID = event.ID
+				s.enqueue(ctx, event, queue)
+			}
+		}
+	}
+}
+
+// enqueue ставит в очередь доставку события всем вебхукам, которым оно адресовано
+func (s *Service) enqueue(ctx context.Context, event notes.Event, queue chan<- *delivery) {
+	recipients := []string{event.OwnerID()}
+	if event.Type == notes.EventNoteShared && event.Share.UserID != event.OwnerID() {
+		recipients = append(recipients, event.Share.UserID)
+	}
+
+	for _, userID := range recipients {
+		webhooks, err := s.repo.ListByOwner(ctx, userID)
+		if err != nil {
+			log.Printf("Failed to list webhooks of user %s: %v", userID, err)
+			continue
+		}
+		for _, webhook := range webhooks {
+			if !webhook.Accepts(event.Type.String()) {
+				continue
+			}
+			d, err := newDelivery(webhook, event)
+			if err != nil {
+				log.Printf("Failed to encode event %d for webhook %s: %v", event.ID, webhook.ID, err)
+				continue
+			}
+			select {
+			case queue <- d:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}
+}
+
+// attempt отправляет событие и при ошибке планирует повторную попытку или сохраняет
+// событие как недоставленное
+func (s *Service) attempt(ctx context.Context, d *delivery, queue chan<- *delivery) {
+	// Удаленному вебхуку события больше не отправляются
+	if d.attempts > 0 {
+		if _, err := s.repo.Get(ctx, d.webhook.ID); errors.Is(err, memory.ErrWebhookNotFound) {
+			return
+		}
+	}
+
+	d.attempts++
+	err := s.send(ctx, d)
+	if err == nil {
+		return
+	}
+	d.lastError = err.Error()
+
+	if d.attempts >= s.maxAttempts {
+		s.deadLetter(d, d.lastError)
+		return
+	}
+
+	delay := s.backoff(d.attempts)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.pending == nil {
+		// Run завершается, попытки больше не планируются
+		s.deadLetter(d, "delivery interrupted by server shutdown: "+d.lastError)
+		return
+	}
+	s.pending[d] = time.AfterFunc(delay, func() {
+		s.mu.Lock()
+		if s.pending == nil {
+			s.mu.Unlock()
+			return
+		}
+		delete(s.pending, d)
+		s.retrying.Add(1)
+		s.mu.Unlock()
+		defer s.retrying.Done()
+
+		select {
+		case queue <- d:
+		case <-ctx.Done():
+			s.deadLetter(d, "delivery interrupted by server shutdown: "+d.lastError)
+		}
+	})
+}
+
+// send выполняет одну попытку доставки: успехом считается ответ 2xx
+func (s *Service) send(ctx context.Context, d *delivery) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.webhook.URL, bytes.NewReader(d.payload))
+	if err != nil {
+		return err
+	}
+	timestamp := strconv.FormatInt(s.now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "notes-service-webhooks")
+	req.Header.Set(HeaderWebhookID, d.webhook.ID)
+	req.Header.Set(HeaderDelivery, d.id)
+	req.Header.Set(HeaderEvent, d.eventType)
+	req.Header.Set(HeaderTimestamp, timestamp)
+	req.Header.Set(HeaderSignature, Sign(d.webhook.Secret, timestamp, d.payload))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+		return fmt.Errorf("webhook responded with %s: %s", resp.Status, bytes.TrimSpace(body))
+	}
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxErrorBody))
+	return nil
+}
+
+// backoff возвращает паузу перед попыткой attempts+1
+func (s *Service) backoff(attempts int) time.Duration {
+	delay := s.baseDelay
+	for range attempts - 1 {
+		if delay >= s.maxDelay/2 {
+			return s.maxDelay
+		}
+		delay *= 2
+	}
+	return min(delay, s.maxDelay)
+}
+
+// deadLetter сохраняет событие, которое не удалось доставить
+func (s *Service) deadLetter(d *delivery, reason string) {
+	log.Printf("Failed to deliver %s event to webhook %s after %d attempts: %s", d.eventType, d.webhook.ID, d.attempts, reason)
+
+	letter := model.WebhookDeadLetter{
+		ID:        d.id,
+		WebhookID: d.webhook.ID,
+		OwnerID:   d.webhook.OwnerID,
+		URL:       d.webhook.URL,
+		EventType: d.eventType,
+		Payload:   d.payload,
+		Attempts:  d.attempts,
+		LastError: reason,
+		FailedAt:  s.now(),
+	}
+	if err := s.repo.AddDeadLetter(context.Background(), letter); err != nil {
+		log.Printf("Failed to save undelivered webhook event %s: %v", d.id, err)
+	}
+}
+
+// newDelivery создает доставку события вебхуку
+func newDelivery(webhook model.Webhook, event notes.Event) (*delivery, error) {
+	d := &delivery{id: uuid.New().String(), webhook: webhook, eventType: event.Type.String()}
+
+	body := payload{
+		ID:      d.id,
+		EventID: event.ID,
+		Type:    d.eventType,
+		Time:    event.Time,
+		Webhook: webhookMetadata{ID: webhook.ID, OwnerID: webhook.OwnerID},
+	}
+	switch event.Type {
+	case notes.EventExportCompleted:
+		op := event.Export
+		body.Export = &exportPayload{
+			ID:            op.ID,
+			Succeeded:     op.State == model.ExportStateSucceeded,
+			Location:      op.Location,
+			ExportedNotes: op.ExportedNotes,
+		}
+		if op.Err != nil {
+			body.Export.Error = op.Err.Error()
+		}
+	default:
+		note := event.Note
+		body.Note = &notePayload{
+			ID:               note.ID,
+			OwnerID:          note.OwnerID,
+			Title:            note.Title,
+			Content:          note.Content,
+			Tags:             note.Tags,
+			Version:          note.Version,
+			Pinned:           note.Pinned,
+			Archived:         note.Archived,
+			CreatedAt:        note.CreatedAt,
+			UpdatedAt:        note.UpdatedAt,
+			RemindAt:         note.RemindAt,
+			IsE2E:            note.IsE2E,
+			E2EScheme:        note.E2EScheme,
+			ContentEncrypted: note.ContentEncrypted,
+		}
+		if event.Type == notes.EventNoteShared {
+			permission := "read"
+			if event.Share.Permission == model.SharePermissionWrite {
+				permission = "write"
+			}
+			body.Share = &sharePayload{UserID: event.Share.UserID, Permission: permission}
+		}
+		if event.Type == notes.EventSavedSearchMatched {
+			body.Search = &searchPayload{ID: event.Search.ID, Name: event.Search.Name, Query: event.Search.Query}
+		}
+		if event.Type == notes.EventQuotaWarning {
+			body.Quota = &quotaPayload{Used: event.Quota.Used, Limit: event.Quota.Limit, ThresholdPercent: event.Quota.ThresholdPercent}
+		}
+	}
+
+	var err error
+	d.payload, err = json.Marshal(body)
+	return d, err
+}
+
+// Sign возвращает подпись запроса к вебхуку: sha256=<hex HMAC-SHA256(secret, timestamp + "." + body)>
+// Получатель вычисляет подпись тем же ключом и сравнивает с заголовком X-Webhook-Signature,
+// а по X-Webhook-Timestamp отклоняет устаревшие (повторно отправленные злоумышленником) запросы
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// NewHTTPClient создает клиент для отправки событий с таймаутом timeout по политике исходящих
+// подключений policy (nil - напрямую, без ограничений)
+// Если allowPrivate = false, клиент не подключается к адресам внутренних сетей (loopback,
+// частные и link-local адреса), чтобы через вебхуки нельзя было обращаться к внутренним сервисам
+// (см. egress.WithoutPrivateNetworks)
+func NewHTTPClient(policy *egress.Policy, timeout time.Duration, allowPrivate bool) *http.Client {
+	opts := []egress.ClientOption{
+		egress.WithTimeout(timeout),
+		// Перенаправление могло бы увести запрос на другой адрес в обход проверок регистрации
+		egress.WithoutRedirects(),
+	}
+	if !allowPrivate {
+		opts = append(opts, egress.WithoutPrivateNetworks())
+	}
+	return policy.HTTPClient(opts...)
+}
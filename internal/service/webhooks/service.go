@@ -0,0 +1,247 @@
+// Package webhooks отправляет события заметок на адреса, зарегистрированные пользователями
+package webhooks
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"sync"
+	"time"
+
+	"notes-service/internal/auth"
+	"notes-service/internal/model"
+	"notes-service/internal/repository"
+	"notes-service/internal/repository/memory"
+	"notes-service/internal/service/notes"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// DefaultMaxAttempts количество попыток доставки события по умолчанию
+	DefaultMaxAttempts = 6
+
+	// DefaultTimeout время ожидания ответа вебхука по умолчанию
+	DefaultTimeout = 10 * time.Second
+
+	// maxWebhooksPerOwner количество вебхуков, которое может зарегистрировать пользователь
+	maxWebhooksPerOwner = 20
+
+	// minSecretLength минимальная длина ключа подписи, заданного пользователем
+	minSecretLength = 16
+)
+
+// ErrTooManyWebhooks возвращается, когда пользователь зарегистрировал maxWebhooksPerOwner вебхуков
+var ErrTooManyWebhooks = errors.New("too many webhooks")
+
+// RegisterInput параметры регистрации вебхука
+type RegisterInput struct {
+	URL        string            // Адрес для POST запросов (http или https)
+	Secret     string            // Ключ подписи, пусто - сгенерировать
+	EventTypes []notes.EventType // Отправляемые события, пусто - все
+}
+
+// Service хранит вебхуки пользователей и доставляет им события заметок
+// Доставку выполняет Run: каждое событие отправляется вебхукам его владельца
+// (для EventNoteShared - и получившего доступ) с HMAC подписью и повторными попытками
+type Service struct {
+	repo   repository.WebhookRepository
+	events notes.EventBus
+	client *http.Client
+	now    func() time.Time
+
+	maxAttempts int
+	baseDelay   time.Duration // Пауза перед второй попыткой, далее удваивается
+	maxDelay    time.Duration // Максимальная пауза между попытками
+	workers     int
+
+	mu       sync.Mutex
+	pending  map[*delivery]*time.Timer // Доставки, ожидающие повторной попытки (nil после остановки Run)
+	retrying sync.WaitGroup            // Повторные попытки, возвращаемые в очередь
+}
+
+// Option настраивает сервис вебхуков
+type Option func(*Service)
+
+// WithClock задает источник времени событий и недоставленных событий (по умолчанию time.Now)
+func WithClock(now func() time.Time) Option {
+	return func(s *Service) {
+		s.now = now
+	}
+}
+
+// WithHTTPClient задает клиент, которым отправляются события (по умолчанию см. NewHTTPClient)
+func WithHTTPClient(client *http.Client) Option {
+	return func(s *Service) {
+		s.client = client
+	}
+}
+
+// WithRetryPolicy задает количество попыток доставки и паузу перед второй попыткой
+// Пауза удваивается с каждой попыткой, но не превышает maxDelay
+func WithRetryPolicy(maxAttempts int, baseDelay, maxDelay time.Duration) Option {
+	return func(s *Service) {
+		if maxAttempts > 0 {
+			s.maxAttempts = maxAttempts
+		}
+		if baseDelay > 0 {
+			s.baseDelay = baseDelay
+		}
+		if maxDelay > 0 {
+			s.maxDelay = maxDelay
+		}
+	}
+}
+
+// NewService создает сервис вебхуков, доставляющий события из events
+func NewService(repo repository.WebhookRepository, events notes.EventBus, opts ...Option) *Service {
+	s := &Service{
+		repo:        repo,
+		events:      events,
+		client:      NewHTTPClient(nil, DefaultTimeout, false),
+		now:         time.Now,
+		maxAttempts: DefaultMaxAttempts,
+		baseDelay:   time.Second,
+		maxDelay:    5 * time.Minute,
+		workers:     4,
+		pending:     make(map[*delivery]*time.Timer),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Register регистрирует вебхук вызывающего пользователя
+// Возвращенный вебхук содержит ключ подписи; List и Delete его не возвращают
+func (s *Service) Register(ctx context.Context, input RegisterInput) (model.Webhook, error) {
+	principal, ok := auth.FromContext(ctx)
+	if !ok {
+		return model.Webhook{}, auth.ErrPermissionDenied
+	}
+
+	if err := validateURL(input.URL); err != nil {
+		return model.Webhook{}, err
+	}
+	secret := input.Secret
+	if secret == "" {
+		var err error
+		if secret, err = generateSecret(); err != nil {
+			return model.Webhook{}, err
+		}
+	} else if len(secret) < minSecretLength {
+		return model.Webhook{}, fmt.Errorf("invalid webhook secret: must be at least %d characters", minSecretLength)
+	}
+
+	eventTypes := make([]string, 0, len(input.EventTypes))
+	for _, eventType := range input.EventTypes {
+		if name := eventType.String(); !slices.Contains(eventTypes, name) {
+			eventTypes = append(eventTypes, name)
+		}
+	}
+
+	existing, err := s.repo.ListByOwner(ctx, principal.UserID)
+	if err != nil {
+		return model.Webhook{}, err
+	}
+	if len(existing) >= maxWebhooksPerOwner {
+		return model.Webhook{}, fmt.Errorf("%w: at most %d webhooks per user", ErrTooManyWebhooks, maxWebhooksPerOwner)
+	}
+
+	webhook := model.Webhook{
+		ID:         uuid.New().String(),
+		OwnerID:    principal.UserID,
+		URL:        input.URL,
+		Secret:     secret,
+		EventTypes: eventTypes,
+		CreatedAt:  s.now(),
+	}
+	if err := s.repo.Create(ctx, webhook); err != nil {
+		return model.Webhook{}, err
+	}
+	return webhook, nil
+}
+
+// List возвращает вебхуки вызывающего пользователя без ключей подписи
+func (s *Service) List(ctx context.Context) ([]model.Webhook, error) {
+	principal, ok := auth.FromContext(ctx)
+	if !ok {
+		return nil, auth.ErrPermissionDenied
+	}
+
+	webhooks, err := s.repo.ListByOwner(ctx, principal.UserID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range webhooks {
+		webhooks[i].Secret = ""
+	}
+	return webhooks, nil
+}
+
+// Delete удаляет вебхук вызывающего пользователя
+// Чужой вебхук не существует для вызывающего (memory.ErrWebhookNotFound)
+func (s *Service) Delete(ctx context.Context, id string) error {
+	principal, ok := auth.FromContext(ctx)
+	if !ok {
+		return auth.ErrPermissionDenied
+	}
+
+	webhook, err := s.repo.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+	if webhook.OwnerID != principal.UserID {
+		return memory.ErrWebhookNotFound
+	}
+	return s.repo.Delete(ctx, id)
+}
+
+// ListDeadLetters возвращает события, которые не удалось доставить вебхукам вызывающего
+// пользователя, начиная с последнего. webhookID оставляет события одного вебхука
+func (s *Service) ListDeadLetters(ctx context.Context, webhookID string) ([]model.WebhookDeadLetter, error) {
+	principal, ok := auth.FromContext(ctx)
+	if !ok {
+		return nil, auth.ErrPermissionDenied
+	}
+
+	letters, err := s.repo.ListDeadLetters(ctx, principal.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if webhookID != "" {
+		letters = slices.DeleteFunc(letters, func(letter model.WebhookDeadLetter) bool {
+			return letter.WebhookID != webhookID
+		})
+	}
+	return letters, nil
+}
+
+// validateURL проверяет адрес вебхука: абсолютный http или https адрес без учетных данных
+func validateURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return fmt.Errorf("invalid webhook url %q", raw)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("invalid webhook url %q: scheme must be http or https", raw)
+	}
+	if u.User != nil {
+		return fmt.Errorf("invalid webhook url %q: credentials are not allowed, use the signature instead", raw)
+	}
+	return nil
+}
+
+// generateSecret создает случайный ключ подписи
+func generateSecret() (string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(secret), nil
+}
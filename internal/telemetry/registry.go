@@ -0,0 +1,192 @@
+// Package telemetry собирает метрики работы сервера для /metrics: запросы и время выполнения gRPC
+// методов и HTTP маршрутов Gateway, открытые стримы, отклонения лимитами запросов и время операций
+// хранилища заметок. Метрики хранятся в реестре prometheus/client_golang и отдаются через promhttp
+// вместе с метриками других компонентов (Register). Трассы OpenTelemetry (NewTracerProvider)
+// отправляются в OTLP коллектор, операции хранилища записываются в них оберткой NewNoteRepository
+package telemetry
+
+import (
+	"errors"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// DefaultBuckets границы корзин гистограмм времени выполнения по умолчанию, в секундах
+var DefaultBuckets = []float64{0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// Уровни, на которых запрос может быть отклонен лимитом
+const (
+	LayerGRPC = "grpc"
+	LayerHTTP = "http"
+)
+
+// Лимиты запросов для notes_rate_limited_total
+const (
+	LimiterClient         = "client"          // Лимит пользователя или адреса клиента (RateLimit интерцептор)
+	LimiterAPIKey         = "api_key"         // Лимит ключа API
+	LimiterTenant         = "tenant"          // Лимит тенанта
+	LimiterStreamMessages = "stream_messages" // Лимит входящих сообщений стрима
+	LimiterGateway        = "gateway"         // Общий лимит HTTP Gateway
+)
+
+// Registry метрики сервера в реестре Prometheus
+// Все методы безопасны для конкурентного вызова; nil Registry ничего не учитывает
+type Registry struct {
+	registry *prometheus.Registry
+
+	grpcRequests   *prometheus.CounterVec   // service, method, code
+	grpcLatency    *prometheus.HistogramVec // service, method
+	streamsOpen    *prometheus.GaugeVec     // service, method
+	rateLimited    *prometheus.CounterVec   // layer, limiter
+	httpRequests   *prometheus.CounterVec   // method, route, code
+	httpLatency    *prometheus.HistogramVec // route
+	repositoryTime *prometheus.HistogramVec // operation, result
+}
+
+// options параметры Registry
+type options struct {
+	buckets []float64
+}
+
+// Option настраивает сбор метрик
+type Option func(*options)
+
+// WithBuckets задает границы корзин гистограмм в секундах (по умолчанию DefaultBuckets)
+func WithBuckets(buckets []float64) Option {
+	return func(o *options) {
+		if len(buckets) > 0 {
+			o.buckets = slices.Sorted(slices.Values(buckets))
+		}
+	}
+}
+
+// NewRegistry создает реестр с метриками сервера без значений
+func NewRegistry(opts ...Option) *Registry {
+	o := options{buckets: DefaultBuckets}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	r := &Registry{
+		registry: prometheus.NewRegistry(),
+		grpcRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "notes_grpc_requests_total",
+			Help: "Completed gRPC requests and streams by method and status code.",
+		}, []string{"service", "method", "code"}),
+		grpcLatency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "notes_grpc_request_duration_seconds",
+			Help:    "Duration of unary gRPC requests by method.",
+			Buckets: o.buckets,
+		}, []string{"service", "method"}),
+		streamsOpen: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "notes_grpc_streams_in_flight",
+			Help: "Open gRPC streams by method.",
+		}, []string{"service", "method"}),
+		rateLimited: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "notes_rate_limited_total",
+			Help: "Requests and stream messages rejected by rate limits by layer and limit.",
+		}, []string{"layer", "limiter"}),
+		httpRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "notes_http_requests_total",
+			Help: "HTTP Gateway requests by method, route and status code.",
+		}, []string{"method", "route", "code"}),
+		httpLatency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "notes_http_request_duration_seconds",
+			Help:    "Duration of HTTP Gateway requests by route.",
+			Buckets: o.buckets,
+		}, []string{"route"}),
+		repositoryTime: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "notes_repository_operation_duration_seconds",
+			Help:    "Duration of note repository operations by operation and result.",
+			Buckets: o.buckets,
+		}, []string{"operation", "result"}),
+	}
+	r.registry.MustRegister(r.grpcRequests, r.grpcLatency, r.streamsOpen, r.rateLimited,
+		r.httpRequests, r.httpLatency, r.repositoryTime)
+	return r
+}
+
+// Register добавляет в реестр метрики других компонентов (резервное копирование, режим деградации и т.д.)
+func (r *Registry) Register(collectors ...prometheus.Collector) error {
+	var errs []error
+	for _, c := range collectors {
+		if err := r.registry.Register(c); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Handler возвращает обработчик /metrics в текстовом формате Prometheus (promhttp)
+func (r *Registry) Handler() http.Handler {
+	return promhttp.HandlerFor(r.registry, promhttp.HandlerOpts{})
+}
+
+// ObserveUnary учитывает завершенный unary запрос метода с кодом статуса code и временем выполнения
+func (r *Registry) ObserveUnary(method, code string, elapsed time.Duration) {
+	if r == nil {
+		return
+	}
+	service, name := splitMethod(method)
+	r.grpcRequests.WithLabelValues(service, name, code).Inc()
+	r.grpcLatency.WithLabelValues(service, name).Observe(elapsed.Seconds())
+}
+
+// StreamOpened учитывает открытый стрим метода
+func (r *Registry) StreamOpened(method string) {
+	if r == nil {
+		return
+	}
+	r.streamsOpen.WithLabelValues(splitMethod(method)).Inc()
+}
+
+// StreamClosed учитывает завершение стрима метода с кодом статуса code
+func (r *Registry) StreamClosed(method, code string) {
+	if r == nil {
+		return
+	}
+	service, name := splitMethod(method)
+	r.streamsOpen.WithLabelValues(service, name).Dec()
+	r.grpcRequests.WithLabelValues(service, name, code).Inc()
+}
+
+// RateLimited учитывает запрос или сообщение стрима, отклоненное лимитом limiter на уровне layer
+func (r *Registry) RateLimited(layer, limiter string) {
+	if r == nil {
+		return
+	}
+	r.rateLimited.WithLabelValues(layer, limiter).Inc()
+}
+
+// ObserveHTTP учитывает HTTP запрос маршрута route (шаблон пути, а не сам путь) с кодом ответа code
+func (r *Registry) ObserveHTTP(method, route string, code int, elapsed time.Duration) {
+	if r == nil {
+		return
+	}
+	r.httpRequests.WithLabelValues(method, route, strconv.Itoa(code)).Inc()
+	r.httpLatency.WithLabelValues(route).Observe(elapsed.Seconds())
+}
+
+// ObserveRepository учитывает время операции хранилища заметок
+func (r *Registry) ObserveRepository(operation string, err error, elapsed time.Duration) {
+	if r == nil {
+		return
+	}
+	result := "ok"
+	if err != nil {
+		result = "error"
+	}
+	r.repositoryTime.WithLabelValues(operation, result).Observe(elapsed.Seconds())
+}
+
+// splitMethod делит полное имя метода /notes.v1.NotesService/GetNote на сервис и метод
+func splitMethod(fullMethod string) (service, method string) {
+	service, method, _ = strings.Cut(strings.TrimPrefix(fullMethod, "/"), "/")
+	return service, method
+}
@@ -0,0 +1,84 @@
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"notes-service/internal/model"
+	"notes-service/internal/repository"
+	"notes-service/internal/repository/memory"
+)
+
+// scrape возвращает ответ /metrics реестра
+func scrape(t *testing.T, r *Registry) string {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	r.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200 from /metrics, got %d", rec.Code)
+	}
+	return rec.Body.String()
+}
+
+func TestRegistry_Handler(t *testing.T) {
+	r := NewRegistry(WithBuckets([]float64{0.1, 0.01}))
+	r.ObserveUnary("/notes.v1.NotesService/GetNote", "OK", 5*time.Millisecond)
+	r.ObserveUnary("/notes.v1.NotesService/GetNote", "NotFound", 50*time.Millisecond)
+	r.ObserveUnary("/notes.v1.NotesService/GetNote", "OK", time.Second)
+	r.StreamOpened("/notes.v1.NotesService/Chat")
+	r.StreamOpened("/notes.v1.NotesService/Chat")
+	r.StreamClosed("/notes.v1.NotesService/Chat", "Canceled")
+	r.RateLimited(LayerGRPC, LimiterTenant)
+	r.ObserveHTTP("GET", "/api/v1/notes/v1/{id=*}", 200, 20*time.Millisecond)
+	r.ObserveRepository("get", errors.New("not found"), time.Millisecond)
+
+	out := scrape(t, r)
+	for _, want := range []string{
+		`notes_grpc_requests_total{code="OK",method="GetNote",service="notes.v1.NotesService"} 2`,
+		`notes_grpc_requests_total{code="Canceled",method="Chat",service="notes.v1.NotesService"} 1`,
+		// Корзины накопительные, границы упорядочены
+		`notes_grpc_request_duration_seconds_bucket{method="GetNote",service="notes.v1.NotesService",le="0.01"} 1`,
+		`notes_grpc_request_duration_seconds_bucket{method="GetNote",service="notes.v1.NotesService",le="0.1"} 2`,
+		`notes_grpc_request_duration_seconds_bucket{method="GetNote",service="notes.v1.NotesService",le="+Inf"} 3`,
+		`notes_grpc_request_duration_seconds_count{method="GetNote",service="notes.v1.NotesService"} 3`,
+		`notes_grpc_streams_in_flight{method="Chat",service="notes.v1.NotesService"} 1`,
+		`notes_rate_limited_total{layer="grpc",limiter="tenant"} 1`,
+		`notes_http_requests_total{code="200",method="GET",route="/api/v1/notes/v1/{id=*}"} 1`,
+		`notes_repository_operation_duration_seconds_count{operation="get",result="error"} 1`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Expected %s in metrics, got:\n%s", want, out)
+		}
+	}
+}
+
+func TestNewNoteRepository(t *testing.T) {
+	r := NewRegistry()
+	repo := NewNoteRepository(memory.NewRepository(), r)
+	if _, ok := repo.(repository.BatchNoteRepository); !ok {
+		t.Error("Expected batch operations of the inner repository to be kept")
+	}
+
+	note, err := repo.Create(context.Background(), model.Note{Title: "Title"})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if _, err := repo.GetByID(context.Background(), note.ID); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	out := scrape(t, r)
+	for _, want := range []string{
+		`notes_repository_operation_duration_seconds_count{operation="create",result="ok"} 1`,
+		`notes_repository_operation_duration_seconds_count{operation="get",result="ok"} 1`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Expected %s in metrics, got:\n%s", want, out)
+		}
+	}
+}
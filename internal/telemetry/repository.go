@@ -0,0 +1,126 @@
+package telemetry
+
+import (
+	"context"
+	"time"
+
+	"notes-service/internal/model"
+	"notes-service/internal/repository"
+)
+
+var (
+	_ repository.NoteRepository      = (*noteRepo)(nil)
+	_ repository.NoteIterator        = (*noteRepo)(nil)
+	_ repository.SortedNoteLister    = (*noteRepo)(nil)
+	_ repository.TagIndex            = (*noteRepo)(nil)
+	_ repository.NotePinner          = (*noteRepo)(nil)
+	_ repository.BatchNoteRepository = (*batchNoteRepo)(nil)
+)
+
+// extendedNoteRepository хранилище заметок со всеми расширениями, которые использует сервис заметок
+type extendedNoteRepository interface {
+	repository.NoteRepository
+	repository.NoteIterator
+	repository.SortedNoteLister
+	repository.TagIndex
+	repository.NotePinner
+}
+
+// noteRepo учитывает время операций вложенного хранилища в notes_repository_operation_duration_seconds
+// и записывает их в трассу запроса (span repository.<операция>)
+type noteRepo struct {
+	inner    extendedNoteRepository
+	registry *Registry
+}
+
+// batchNoteRepo добавляет атомарные пакетные операции, если их поддерживает вложенное хранилище
+type batchNoteRepo struct {
+	*noteRepo
+	batch repository.BatchNoteRepository
+}
+
+// NewNoteRepository оборачивает хранилище заметок учетом времени операций в registry (nil - только трассировка)
+// Оборачиваются только хранилища с NoteIterator, SortedNoteLister, TagIndex и NotePinner (встроенные
+// хранилища, режим деградации и шифрование), чтобы обертка не скрывала расширения; остальные
+// возвращаются как есть
+func NewNoteRepository(inner repository.NoteRepository, registry *Registry) repository.NoteRepository {
+	extended, ok := inner.(extendedNoteRepository)
+	if !ok {
+		return inner
+	}
+	r := &noteRepo{inner: extended, registry: registry}
+	if batch, ok := inner.(repository.BatchNoteRepository); ok {
+		return &batchNoteRepo{noteRepo: r, batch: batch}
+	}
+	return r
+}
+
+// timed выполняет операцию operation в span и учитывает ее время
+func timed[T any](ctx context.Context, r *noteRepo, operation string, fn func(context.Context) (T, error)) (T, error) {
+	ctx, span := startSpan(ctx, "repository."+operation)
+	start := time.Now()
+	result, err := fn(ctx)
+	r.registry.ObserveRepository(operation, err, time.Since(start))
+	EndSpan(span, err)
+	return result, err
+}
+
+func (r *noteRepo) Create(ctx context.Context, note model.Note) (model.Note, error) {
+	return timed(ctx, r, "create", func(ctx context.Context) (model.Note, error) { return r.inner.Create(ctx, note) })
+}
+
+func (r *noteRepo) GetByID(ctx context.Context, id string) (model.Note, error) {
+	return timed(ctx, r, "get", func(ctx context.Context) (model.Note, error) { return r.inner.GetByID(ctx, id) })
+}
+
+func (r *noteRepo) List(ctx context.Context) ([]model.Note, error) {
+	return timed(ctx, r, "list", func(ctx context.Context) ([]model.Note, error) { return r.inner.List(ctx) })
+}
+
+func (r *noteRepo) Update(ctx context.Context, note model.Note) (model.Note, error) {
+	return timed(ctx, r, "update", func(ctx context.Context) (model.Note, error) { return r.inner.Update(ctx, note) })
+}
+
+func (r *noteRepo) Delete(ctx context.Context, id string) error {
+	_, err := timed(ctx, r, "delete", func(ctx context.Context) (struct{}, error) { return struct{}{}, r.inner.Delete(ctx, id) })
+	return err
+}
+
+// ForEach обходит заметки; учитывается время всего обхода вместе с fn
+func (r *noteRepo) ForEach(ctx context.Context, batchSize int, fn func(model.Note) error) error {
+	_, err := timed(ctx, r, "for_each", func(ctx context.Context) (struct{}, error) { return struct{}{}, r.inner.ForEach(ctx, batchSize, fn) })
+	return err
+}
+
+// ForEachAfter обходит заметки после курсора after, как ForEach
+func (r *noteRepo) ForEachAfter(ctx context.Context, after string, batchSize int, fn func(model.Note) error) error {
+	_, err := timed(ctx, r, "for_each", func(ctx context.Context) (struct{}, error) {
+		return struct{}{}, r.inner.ForEachAfter(ctx, after, batchSize, fn)
+	})
+	return err
+}
+
+func (r *noteRepo) ListSorted(ctx context.Context, cmp repository.NoteComparator) ([]model.Note, error) {
+	return timed(ctx, r, "list_sorted", func(ctx context.Context) ([]model.Note, error) { return r.inner.ListSorted(ctx, cmp) })
+}
+
+func (r *noteRepo) ListByTag(ctx context.Context, tag string) ([]model.Note, error) {
+	return timed(ctx, r, "list_by_tag", func(ctx context.Context) ([]model.Note, error) { return r.inner.ListByTag(ctx, tag) })
+}
+
+func (r *noteRepo) ListTags(ctx context.Context) ([]model.TagCount, error) {
+	return timed(ctx, r, "list_tags", func(ctx context.Context) ([]model.TagCount, error) { return r.inner.ListTags(ctx) })
+}
+
+func (r *noteRepo) SetPinned(ctx context.Context, id string, pinned bool) (model.Note, error) {
+	return timed(ctx, r, "set_pinned", func(ctx context.Context) (model.Note, error) { return r.inner.SetPinned(ctx, id, pinned) })
+}
+
+func (r *batchNoteRepo) CreateBatch(ctx context.Context, notes []model.Note) ([]model.Note, error) {
+	return timed(ctx, r.noteRepo, "create_batch", func(ctx context.Context) ([]model.Note, error) { return r.batch.CreateBatch(ctx, notes) })
+}
+
+func (r *batchNoteRepo) DeleteBatch(ctx context.Context, ids []string) error {
+	_, err := timed(ctx, r.noteRepo, "delete_batch", func(ctx context.Context) (struct{}, error) { return struct{}{}, r.batch.DeleteBatch(ctx, ids) })
+	return err
+}
@@ -0,0 +1,83 @@
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+)
+
+// DefaultServiceName имя сервиса в трассах по умолчанию
+const DefaultServiceName = "notes-service"
+
+// Propagator передает контекст трассы между Gateway, gRPC сервером и внешними клиентами
+// (заголовки traceparent, tracestate и baggage)
+var Propagator propagation.TextMapPropagator = propagation.NewCompositeTextMapPropagator(
+	propagation.TraceContext{}, propagation.Baggage{},
+)
+
+// TracingConfig настройки экспорта трасс
+type TracingConfig struct {
+	Endpoint    string            // Адрес OTLP/gRPC коллектора, например otel-collector:4317
+	DialOptions []grpc.DialOption // Подключение к коллектору (политика исходящих подключений)
+	ServiceName string            // Имя сервиса в трассах (по умолчанию DefaultServiceName)
+	SampleRatio float64           // Доля новых трасс, которые записываются (0-1); продолжение трассы клиента следует его решению
+}
+
+// NewTracerProvider создает провайдер трасс с пакетной отправкой в OTLP/gRPC коллектор
+// Провайдер нужно остановить через Shutdown, чтобы отправить накопленные трассы
+func NewTracerProvider(ctx context.Context, cfg TracingConfig) (*sdktrace.TracerProvider, error) {
+	if cfg.Endpoint == "" {
+		return nil, errors.New("tracing endpoint cannot be empty")
+	}
+	if cfg.SampleRatio < 0 || cfg.SampleRatio > 1 {
+		return nil, fmt.Errorf("invalid tracing sample ratio %g: must be between 0 and 1", cfg.SampleRatio)
+	}
+	serviceName := cfg.ServiceName
+	if serviceName == "" {
+		serviceName = DefaultServiceName
+	}
+
+	conn, err := grpc.NewClient(cfg.Endpoint, cfg.DialOptions...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to tracing collector: %w", err)
+	}
+	// Экспортер закрывает переданное ему соединение при остановке провайдера
+	exporter, err := otlptracegrpc.New(ctx, otlptracegrpc.WithGRPCConn(conn))
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to create tracing exporter: %w", err)
+	}
+
+	return sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRatio))),
+		sdktrace.WithResource(resource.NewSchemaless(attribute.String("service.name", serviceName))),
+	), nil
+}
+
+// tracer spans операций хранилища заметок; без провайдера трасс (otel.SetTracerProvider) ничего не записывает
+var tracer = otel.Tracer("notes-service/internal/telemetry")
+
+// startSpan начинает span операции, EndSpan завершает его
+func startSpan(ctx context.Context, name string) (context.Context, trace.Span) {
+	return tracer.Start(ctx, name, trace.WithSpanKind(trace.SpanKindInternal))
+}
+
+// EndSpan завершает span и отмечает в нем ошибку err (nil - успешное завершение)
+func EndSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
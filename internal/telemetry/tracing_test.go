@@ -0,0 +1,58 @@
+package telemetry
+
+import (
+	"context"
+	"testing"
+
+	"notes-service/internal/model"
+	"notes-service/internal/repository/memory"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestNoteRepository_Spans(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+
+	// Без Registry операции только трассируются
+	repo := NewNoteRepository(memory.NewRepository(), nil)
+	ctx, parent := provider.Tracer("test").Start(context.Background(), "request")
+	note, err := repo.Create(ctx, model.Note{Title: "Title"})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if _, err := repo.GetByID(ctx, "missing"); err == nil {
+		t.Fatal("Expected error for a missing note")
+	}
+	parent.End()
+
+	spans := recorder.Ended()
+	if len(spans) != 3 {
+		t.Fatalf("Expected 3 spans, got %d", len(spans))
+	}
+	create, get := spans[0], spans[1]
+	if create.Name() != "repository.create" || create.Parent().SpanID() != parent.SpanContext().SpanID() {
+		t.Errorf("Expected repository.create span inside the request, got %s", create.Name())
+	}
+	if create.Status().Code == codes.Error || note.ID == "" {
+		t.Errorf("Expected successful create span, got %v", create.Status())
+	}
+	if get.Name() != "repository.get" || get.Status().Code != codes.Error {
+		t.Errorf("Expected failed repository.get span, got %s with %v", get.Name(), get.Status())
+	}
+}
+
+func TestNewTracerProvider(t *testing.T) {
+	if _, err := NewTracerProvider(context.Background(), TracingConfig{}); err == nil {
+		t.Error("Expected error for an empty endpoint")
+	}
+	if _, err := NewTracerProvider(context.Background(), TracingConfig{Endpoint: "localhost:4317", SampleRatio: 2}); err == nil {
+		t.Error("Expected error for sample ratio above 1")
+	}
+}
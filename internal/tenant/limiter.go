@@ -0,0 +1,49 @@
+package tenant
+
+import (
+	"sync"
+
+	"golang.org/x/time/rate"
+)
+
+// Limiters хранит отдельный rate limiter для каждого тенанта
+type Limiters struct {
+	mu       sync.Mutex
+	limiters map[string]*rate.Limiter
+}
+
+// NewLimiters создает пустой набор rate limiter'ов
+func NewLimiters() *Limiters {
+	return &Limiters{limiters: make(map[string]*rate.Limiter)}
+}
+
+// Allow проверяет, может ли тенант выполнить еще один запрос по лимитам из settings
+// Изменение лимитов в настройках применяется к уже созданному limiter'у тенанта
+func (l *Limiters) Allow(tenantID string, settings Settings) bool {
+	if settings.RateLimitRPS <= 0 {
+		return true
+	}
+
+	burst := settings.RateLimitBurst
+	if burst <= 0 {
+		burst = settings.RateLimitRPS
+	}
+	limit := rate.Limit(settings.RateLimitRPS)
+
+	l.mu.Lock()
+	limiter, ok := l.limiters[tenantID]
+	if !ok {
+		limiter = rate.NewLimiter(limit, burst)
+		l.limiters[tenantID] = limiter
+	}
+	l.mu.Unlock()
+
+	if limiter.Limit() != limit {
+		limiter.SetLimit(limit)
+	}
+	if limiter.Burst() != burst {
+		limiter.SetBurst(burst)
+	}
+
+	return limiter.Allow()
+}
@@ -0,0 +1,89 @@
+package tenant
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Source источник переопределений настроек тенантов (секция конфигурации, хранилище и т.п.)
+type Source interface {
+	// Override возвращает переопределение для тенанта, ok = false если его нет
+	Override(ctx context.Context, tenantID string) (override Override, ok bool, err error)
+}
+
+// StaticSource переопределения, заданные заранее (например, в config.yml)
+type StaticSource map[string]Override
+
+// Override возвращает переопределение тенанта из таблицы
+func (s StaticSource) Override(_ context.Context, tenantID string) (Override, bool, error) {
+	override, ok := s[tenantID]
+	return override, ok, nil
+}
+
+// cachedSettings настройки тенанта в кэше резолвера
+type cachedSettings struct {
+	settings  Settings
+	expiresAt time.Time
+}
+
+// Resolver определяет действующие настройки тенанта и кэширует их на ttl
+type Resolver struct {
+	defaults Settings
+	source   Source
+	ttl      time.Duration
+	now      func() time.Time
+
+	mu    sync.Mutex
+	cache map[string]cachedSettings
+}
+
+// NewResolver создает резолвер настроек тенантов
+// source может быть nil - тогда всем тенантам назначаются настройки по умолчанию
+// При ttl <= 0 настройки не кэшируются и запрашиваются из source при каждом вызове
+func NewResolver(defaults Settings, source Source, ttl time.Duration) *Resolver {
+	return &Resolver{
+		defaults: defaults,
+		source:   source,
+		ttl:      ttl,
+		now:      time.Now,
+		cache:    make(map[string]cachedSettings),
+	}
+}
+
+// Resolve возвращает действующие настройки тенанта: настройки по умолчанию
+// с примененным переопределением тенанта из source
+func (r *Resolver) Resolve(ctx context.Context, tenantID string) (Settings, error) {
+	r.mu.Lock()
+	cached, ok := r.cache[tenantID]
+	r.mu.Unlock()
+	if ok && r.now().Before(cached.expiresAt) {
+		return cached.settings, nil
+	}
+
+	settings := r.defaults
+	if r.source != nil {
+		override, found, err := r.source.Override(ctx, tenantID)
+		if err != nil {
+			return Settings{}, err
+		}
+		if found {
+			settings = settings.Apply(override)
+		}
+	}
+
+	if r.ttl > 0 {
+		r.mu.Lock()
+		r.cache[tenantID] = cachedSettings{settings: settings, expiresAt: r.now().Add(r.ttl)}
+		r.mu.Unlock()
+	}
+
+	return settings, nil
+}
+
+// Invalidate сбрасывает кэшированные настройки тенанта, например после изменения в source
+func (r *Resolver) Invalidate(tenantID string) {
+	r.mu.Lock()
+	delete(r.cache, tenantID)
+	r.mu.Unlock()
+}
@@ -0,0 +1,82 @@
+package tenant
+
+import (
+	"context"
+	"maps"
+)
+
+// Флаги функциональности, которые можно отключить для тенанта
+const (
+	FeatureAttachments = "attachments" // Загрузка и скачивание вложений
+	FeatureEvents      = "events"      // Подписка на события заметок (SubscribeToEvents)
+)
+
+// Settings действующие настройки тенанта
+// Тенант - аутентифицированный пользователь запроса (auth.Principal.UserID)
+type Settings struct {
+	RateLimitRPS   int             // Лимит запросов в секунду (0 - без ограничения)
+	RateLimitBurst int             // Допустимый всплеск запросов
+	MaxNotes       int             // Квота заметок (0 - без ограничения)
+	Features       map[string]bool // Флаги функциональности
+
+	// QuotaWarningPercents - мягкие пороги квоты заметок в процентах (например, 80 и 95):
+	// при их пересечении клиент получает предупреждение, а владелец - событие quota_warning
+	QuotaWarningPercents []int
+}
+
+// Override переопределяет часть настроек тенанта
+// Незаданные (nil) поля наследуются из настроек по умолчанию
+type Override struct {
+	RateLimitRPS   *int
+	RateLimitBurst *int
+	MaxNotes       *int
+	Features       map[string]bool // Переопределяются только перечисленные флаги
+
+	QuotaWarningPercents []int // Пустой список отключает пороги, nil - наследует
+}
+
+// Apply возвращает настройки с примененным переопределением
+// Исходные настройки не изменяются
+func (s Settings) Apply(o Override) Settings {
+	if o.RateLimitRPS != nil {
+		s.RateLimitRPS = *o.RateLimitRPS
+	}
+	if o.RateLimitBurst != nil {
+		s.RateLimitBurst = *o.RateLimitBurst
+	}
+	if o.MaxNotes != nil {
+		s.MaxNotes = *o.MaxNotes
+	}
+	if o.QuotaWarningPercents != nil {
+		s.QuotaWarningPercents = o.QuotaWarningPercents
+	}
+	if len(o.Features) > 0 {
+		features := maps.Clone(s.Features)
+		if features == nil {
+			features = make(map[string]bool, len(o.Features))
+		}
+		maps.Copy(features, o.Features)
+		s.Features = features
+	}
+	return s
+}
+
+// FeatureEnabled проверяет, включена ли функциональность name
+// Функциональность включена, пока флаг не выключен явно
+func (s Settings) FeatureEnabled(name string) bool {
+	enabled, ok := s.Features[name]
+	return !ok || enabled
+}
+
+type settingsKey struct{}
+
+// NewContext возвращает контекст с настройками тенанта
+func NewContext(ctx context.Context, settings Settings) context.Context {
+	return context.WithValue(ctx, settingsKey{}, settings)
+}
+
+// FromContext возвращает настройки тенанта запроса, если они были определены
+func FromContext(ctx context.Context) (Settings, bool) {
+	settings, ok := ctx.Value(settingsKey{}).(Settings)
+	return settings, ok
+}
@@ -0,0 +1,107 @@
+package tenant
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// countingSource считает обращения к источнику переопределений
+type countingSource struct {
+	StaticSource
+	calls int
+}
+
+func (s *countingSource) Override(ctx context.Context, tenantID string) (Override, bool, error) {
+	s.calls++
+	return s.StaticSource.Override(ctx, tenantID)
+}
+
+func intPtr(v int) *int {
+	return &v
+}
+
+func TestResolver_AppliesOverrideOnDefaults(t *testing.T) {
+	defaults := Settings{RateLimitRPS: 100, MaxNotes: 1000, Features: map[string]bool{FeatureEvents: true}}
+	source := StaticSource{
+		"acme": {MaxNotes: intPtr(10), Features: map[string]bool{FeatureAttachments: false}},
+	}
+	resolver := NewResolver(defaults, source, time.Minute)
+
+	settings, err := resolver.Resolve(context.Background(), "acme")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if settings.RateLimitRPS != 100 {
+		t.Errorf("Expected inherited RateLimitRPS 100, got %d", settings.RateLimitRPS)
+	}
+	if settings.MaxNotes != 10 {
+		t.Errorf("Expected overridden MaxNotes 10, got %d", settings.MaxNotes)
+	}
+	if settings.FeatureEnabled(FeatureAttachments) {
+		t.Error("Expected attachments to be disabled for acme")
+	}
+	if !settings.FeatureEnabled(FeatureEvents) {
+		t.Error("Expected events to stay enabled for acme")
+	}
+
+	// Переопределение не должно менять настройки по умолчанию
+	other, _ := resolver.Resolve(context.Background(), "other")
+	if other.MaxNotes != 1000 || !other.FeatureEnabled(FeatureAttachments) {
+		t.Errorf("Expected defaults for tenant without override, got %+v", other)
+	}
+}
+
+func TestResolver_CachesUntilTTL(t *testing.T) {
+	source := &countingSource{StaticSource: StaticSource{}}
+	resolver := NewResolver(Settings{}, source, time.Minute)
+	now := time.Now()
+	resolver.now = func() time.Time { return now }
+
+	for range 3 {
+		if _, err := resolver.Resolve(context.Background(), "acme"); err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+	}
+	if source.calls != 1 {
+		t.Errorf("Expected 1 source call within TTL, got %d", source.calls)
+	}
+
+	now = now.Add(2 * time.Minute)
+	resolver.Resolve(context.Background(), "acme")
+	if source.calls != 2 {
+		t.Errorf("Expected source to be queried after TTL, got %d calls", source.calls)
+	}
+
+	resolver.Invalidate("acme")
+	resolver.Resolve(context.Background(), "acme")
+	if source.calls != 3 {
+		t.Errorf("Expected source to be queried after Invalidate, got %d calls", source.calls)
+	}
+}
+
+func TestLimiters_PerTenant(t *testing.T) {
+	limiters := NewLimiters()
+	settings := Settings{RateLimitRPS: 1, RateLimitBurst: 2}
+
+	for i := range 2 {
+		if !limiters.Allow("acme", settings) {
+			t.Fatalf("Expected request %d within burst to be allowed", i+1)
+		}
+	}
+	if limiters.Allow("acme", settings) {
+		t.Error("Expected request over burst to be rejected")
+	}
+
+	// Лимит одного тенанта не влияет на другого
+	if !limiters.Allow("other", settings) {
+		t.Error("Expected another tenant to have its own limit")
+	}
+
+	// Без лимита запросы не ограничиваются
+	for range 10 {
+		if !limiters.Allow("unlimited", Settings{}) {
+			t.Fatal("Expected unlimited tenant to be allowed")
+		}
+	}
+}
@@ -0,0 +1,109 @@
+// Package tlsconfig загружает настройки TLS gRPC сервера: сертификат сервера, удостоверяющий
+// центр клиентских сертификатов (mTLS) и парные им настройки подключения к серверу для Gateway
+// и клиентов (cmd/client)
+package tlsconfig
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+)
+
+// Config настройки TLS gRPC сервера и подключения к нему
+type Config struct {
+	CertFile     string // PEM с сертификатом сервера
+	KeyFile      string // PEM с ключом сертификата сервера
+	ClientCAFile string // PEM с удостоверяющими центрами сертификатов клиентов: задан - сервер требует сертификат (mTLS)
+	MinVersion   string // Минимальная версия TLS: 1.2 (по умолчанию) или 1.3
+
+	// Подключение к серверу
+	CAFile         string // PEM с удостоверяющими центрами сертификата сервера вместо системных
+	ClientCertFile string // PEM с сертификатом клиента для mTLS (по умолчанию CertFile)
+	ClientKeyFile  string // PEM с ключом сертификата клиента (по умолчанию KeyFile)
+	ServerName     string // Имя сервера для проверки сертификата вместо имени из адреса
+}
+
+// Enabled сообщает, что сервер принимает подключения по TLS
+func (c Config) Enabled() bool {
+	return c.CertFile != "" || c.KeyFile != ""
+}
+
+// Server возвращает настройки TLS сервера
+// С ClientCAFile сервер отклоняет подключения без сертификата клиента, подписанного этими центрами
+func (c Config) Server() (*tls.Config, error) {
+	minVersion, err := parseMinVersion(c.MinVersion)
+	if err != nil {
+		return nil, err
+	}
+	cert, err := tls.LoadX509KeyPair(c.CertFile, c.KeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load tls server certificate: %w", err)
+	}
+
+	config := &tls.Config{Certificates: []tls.Certificate{cert}, MinVersion: minVersion}
+	if c.ClientCAFile != "" {
+		config.ClientCAs, err = loadCertPool(c.ClientCAFile)
+		if err != nil {
+			return nil, err
+		}
+		config.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	return config, nil
+}
+
+// Client возвращает настройки TLS подключения к серверу
+// Сертификат клиента отправляется, если он задан или сервер требует его (ClientCAFile):
+// тогда по умолчанию используется сертификат самого сервера
+func (c Config) Client() (*tls.Config, error) {
+	minVersion, err := parseMinVersion(c.MinVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	config := &tls.Config{ServerName: c.ServerName, MinVersion: minVersion}
+	if c.CAFile != "" {
+		config.RootCAs, err = loadCertPool(c.CAFile)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	certFile, keyFile := c.ClientCertFile, c.ClientKeyFile
+	if certFile == "" && keyFile == "" && c.ClientCAFile != "" {
+		certFile, keyFile = c.CertFile, c.KeyFile
+	}
+	if certFile != "" || keyFile != "" {
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load tls client certificate: %w", err)
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+	return config, nil
+}
+
+// parseMinVersion возвращает минимальную версию TLS по ее записи в конфигурации
+func parseMinVersion(version string) (uint16, error) {
+	switch version {
+	case "", "1.2":
+		return tls.VersionTLS12, nil
+	case "1.3":
+		return tls.VersionTLS13, nil
+	default:
+		return 0, fmt.Errorf("unsupported tls min_version %q (expected 1.2 or 1.3)", version)
+	}
+}
+
+// loadCertPool загружает сертификаты удостоверяющих центров из PEM файла
+func loadCertPool(path string) (*x509.CertPool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read tls ca file: %w", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(data) {
+		return nil, fmt.Errorf("no certificates found in tls ca file %s", path)
+	}
+	return pool, nil
+}
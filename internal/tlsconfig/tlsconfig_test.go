@@ -0,0 +1,123 @@
+package tlsconfig
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// writeCert создает сертификат, подписанный parent (nil - самоподписанный), и сохраняет его и ключ в dir
+func writeCert(t *testing.T, dir, name string, template *x509.Certificate, parent *x509.Certificate, parentKey *ecdsa.PrivateKey) (*x509.Certificate, *ecdsa.PrivateKey) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parent == nil {
+		parent, parentKey = template, key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, parentKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+	if err := os.WriteFile(filepath.Join(dir, name+".pem"), certPEM, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, name+"-key.pem"), keyPEM, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cert, key
+}
+
+// handshake выполняет TLS рукопожатие клиента и сервера и возвращает ошибку сервера
+func handshake(server, client *tls.Config) error {
+	serverConn, clientConn := net.Pipe()
+	defer serverConn.Close()
+	defer clientConn.Close()
+
+	go func() {
+		_ = tls.Client(clientConn, client).Handshake()
+		clientConn.Close()
+	}()
+	return tls.Server(serverConn, server).Handshake()
+}
+
+func TestConfig_MutualTLS(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Now()
+	ca, caKey := writeCert(t, dir, "ca", &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "notes test ca"},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}, nil, nil)
+	writeCert(t, dir, "server", &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		DNSNames:     []string{"localhost"},
+		NotBefore:    now.Add(-time.Hour),
+		NotAfter:     now.Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}, ca, caKey)
+
+	cfg := Config{
+		CertFile:     filepath.Join(dir, "server.pem"),
+		KeyFile:      filepath.Join(dir, "server-key.pem"),
+		ClientCAFile: filepath.Join(dir, "ca.pem"),
+		CAFile:       filepath.Join(dir, "ca.pem"),
+		ServerName:   "localhost",
+	}
+	serverTLS, err := cfg.Server()
+	if err != nil {
+		t.Fatalf("Server() error: %v", err)
+	}
+	if serverTLS.ClientAuth != tls.RequireAndVerifyClientCert {
+		t.Errorf("Expected client certificates to be required with client_ca_file")
+	}
+
+	// Gateway по умолчанию предъявляет сертификат сервера
+	clientTLS, err := cfg.Client()
+	if err != nil {
+		t.Fatalf("Client() error: %v", err)
+	}
+	if err := handshake(serverTLS, clientTLS); err != nil {
+		t.Errorf("Expected mTLS handshake to succeed, got: %v", err)
+	}
+
+	// Клиент без сертификата отклоняется
+	anonymous, err := Config{CAFile: cfg.CAFile, ServerName: "localhost"}.Client()
+	if err != nil {
+		t.Fatalf("Client() error: %v", err)
+	}
+	if err := handshake(serverTLS, anonymous); err == nil {
+		t.Errorf("Expected handshake without a client certificate to fail")
+	}
+
+	if _, err := (Config{CertFile: cfg.CertFile, KeyFile: cfg.KeyFile, MinVersion: "1.1"}).Server(); err == nil {
+		t.Errorf("Expected error for unsupported min_version")
+	}
+}
@@ -0,0 +1,120 @@
+// Package client предоставляет клиент NotesService для Go приложений: соединение,
+// авторизацию токеном, предварительную валидацию запросов на стороне клиента
+// и доступ к предупреждениям ответов
+package client
+
+import (
+	"context"
+
+	notesv1 "notes-service/pkg/proto/notes/v1"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+)
+
+// Client клиент NotesService поверх одного gRPC соединения
+// Методы NotesService доступны напрямую через встроенный NotesServiceClient
+type Client struct {
+	notesv1.NotesServiceClient
+
+	conn *grpc.ClientConn
+}
+
+// Option настраивает клиент
+type Option func(*options)
+
+type options struct {
+	token          string
+	validate       bool
+	warningHandler WarningHandler
+	dialOptions    []grpc.DialOption
+}
+
+// WithToken добавляет к каждому запросу заголовок authorization: Bearer <token>
+func WithToken(token string) Option {
+	return func(o *options) {
+		o.token = token
+	}
+}
+
+// WithPreflightValidation включает проверку запросов по правилам protovalidate перед отправкой
+// Невалидный запрос не отправляется на сервер: вызов сразу возвращает ошибку InvalidArgument
+// в том же формате, что и серверная валидация. Для отдельного вызова проверку отключает SkipValidation
+func WithPreflightValidation() Option {
+	return func(o *options) {
+		o.validate = true
+	}
+}
+
+// WithDialOptions добавляет параметры соединения, например TLS вместо соединения без шифрования
+func WithDialOptions(dialOptions ...grpc.DialOption) Option {
+	return func(o *options) {
+		o.dialOptions = append(o.dialOptions, dialOptions...)
+	}
+}
+
+// New создает клиент сервера target
+// По умолчанию соединение не шифруется, TLS подключается через WithDialOptions
+func New(target string, opts ...Option) (*Client, error) {
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	var unary []grpc.UnaryClientInterceptor
+	var stream []grpc.StreamClientInterceptor
+	if o.token != "" {
+		unary = append(unary, tokenUnaryInterceptor(o.token))
+		stream = append(stream, tokenStreamInterceptor(o.token))
+	}
+	if o.validate {
+		unary = append(unary, ValidateUnaryInterceptor)
+		stream = append(stream, ValidateStreamInterceptor)
+	}
+	if o.warningHandler != nil {
+		unary = append(unary, warningUnaryInterceptor(o.warningHandler))
+	}
+
+	dialOptions := []grpc.DialOption{
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithChainUnaryInterceptor(unary...),
+		grpc.WithChainStreamInterceptor(stream...),
+	}
+	conn, err := grpc.NewClient(target, append(dialOptions, o.dialOptions...)...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Client{
+		NotesServiceClient: notesv1.NewNotesServiceClient(conn),
+		conn:               conn,
+	}, nil
+}
+
+// Conn возвращает соединение клиента, например для клиентов других сервисов того же сервера
+func (c *Client) Conn() *grpc.ClientConn {
+	return c.conn
+}
+
+// Close закрывает соединение
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+// withToken добавляет токен авторизации в исходящие метаданные
+func withToken(ctx context.Context, token string) context.Context {
+	return metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+token)
+}
+
+func tokenUnaryInterceptor(token string) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		return invoker(withToken(ctx, token), method, req, reply, cc, opts...)
+	}
+}
+
+func tokenStreamInterceptor(token string) grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		return streamer(withToken(ctx, token), desc, cc, method, opts...)
+	}
+}
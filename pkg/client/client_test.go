@@ -0,0 +1,140 @@
+package client
+
+import (
+	"context"
+	"net"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	notesv1 "notes-service/pkg/proto/notes/v1"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// countingServer считает вызовы, дошедшие до сервера, и запоминает заголовок авторизации
+type countingServer struct {
+	notesv1.UnimplementedNotesServiceServer
+
+	calls         atomic.Int32
+	authorization atomic.Value
+}
+
+func (s *countingServer) CreateNote(ctx context.Context, req *notesv1.CreateNoteRequest) (*notesv1.CreateNoteResponse, error) {
+	s.calls.Add(1)
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		s.authorization.Store(md.Get("authorization"))
+	}
+	resp := &notesv1.CreateNoteResponse{Note: &notesv1.Note{Id: "note-1", Title: req.GetTitle()}}
+	if req.GetTitle() != strings.TrimSpace(req.GetTitle()) {
+		resp.Warnings = []*notesv1.Warning{{Code: "WHITESPACE_TRIMMED", Field: "title"}}
+	}
+	return resp, nil
+}
+
+func (s *countingServer) ExportNotes(req *notesv1.ExportNotesRequest, stream grpc.ServerStreamingServer[notesv1.ExportNotesResponse]) error {
+	s.calls.Add(1)
+	return nil
+}
+
+// newTestClient запускает сервер в памяти и создает клиент с опциями opts
+func newTestClient(t *testing.T, opts ...Option) (*Client, *countingServer) {
+	t.Helper()
+
+	listener := bufconn.Listen(1 << 20)
+	server := grpc.NewServer()
+	srv := &countingServer{}
+	notesv1.RegisterNotesServiceServer(server, srv)
+	go func() { _ = server.Serve(listener) }()
+	t.Cleanup(server.Stop)
+
+	dialer := grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+		return listener.DialContext(ctx)
+	})
+	c, err := New("passthrough:///bufnet", append(opts, WithDialOptions(dialer))...)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	t.Cleanup(func() { _ = c.Close() })
+
+	return c, srv
+}
+
+func TestPreflightValidation_RejectsLocally(t *testing.T) {
+	c, srv := newTestClient(t, WithPreflightValidation())
+	ctx := context.Background()
+
+	_, err := c.CreateNote(ctx, &notesv1.CreateNoteRequest{Title: "No", Content: "short"})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("Expected InvalidArgument, got: %v", err)
+	}
+	if srv.calls.Load() != 0 {
+		t.Errorf("Expected invalid request not to reach the server, got %d calls", srv.calls.Load())
+	}
+
+	if _, err := c.CreateNote(ctx, &notesv1.CreateNoteRequest{Title: "Valid title", Content: "Long enough content"}); err != nil {
+		t.Fatalf("Expected valid request to succeed, got: %v", err)
+	}
+	if srv.calls.Load() != 1 {
+		t.Errorf("Expected valid request to reach the server, got %d calls", srv.calls.Load())
+	}
+}
+
+func TestPreflightValidation_SkipValidation(t *testing.T) {
+	c, srv := newTestClient(t, WithPreflightValidation())
+
+	_, err := c.CreateNote(context.Background(), &notesv1.CreateNoteRequest{Title: "No"}, SkipValidation())
+	if err != nil {
+		t.Fatalf("Expected request to reach the test server, got: %v", err)
+	}
+	if srv.calls.Load() != 1 {
+		t.Errorf("Expected skipped validation to send the request, got %d calls", srv.calls.Load())
+	}
+}
+
+func TestPreflightValidation_ServerStream(t *testing.T) {
+	c, srv := newTestClient(t, WithPreflightValidation())
+
+	_, err := c.ExportNotes(context.Background(), &notesv1.ExportNotesRequest{})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("Expected InvalidArgument for unspecified format, got: %v", err)
+	}
+	if srv.calls.Load() != 0 {
+		t.Errorf("Expected invalid stream request not to reach the handler, got %d calls", srv.calls.Load())
+	}
+}
+
+func TestClient_WithoutValidationAndToken(t *testing.T) {
+	c, srv := newTestClient(t, WithToken("secret"))
+
+	if _, err := c.CreateNote(context.Background(), &notesv1.CreateNoteRequest{Title: "No"}); err != nil {
+		t.Fatalf("Expected request without preflight validation to be sent, got: %v", err)
+	}
+	got, _ := srv.authorization.Load().([]string)
+	if len(got) != 1 || got[0] != "Bearer secret" {
+		t.Errorf("Expected bearer token in metadata, got %v", got)
+	}
+}
+
+func TestClient_WithWarningHandler(t *testing.T) {
+	var method string
+	var received []*notesv1.Warning
+	c, _ := newTestClient(t, WithWarningHandler(func(_ context.Context, m string, warnings []*notesv1.Warning) {
+		method, received = m, warnings
+	}))
+
+	resp, err := c.CreateNote(context.Background(), &notesv1.CreateNoteRequest{Title: " Padded title", Content: "Long enough content"})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(Warnings(resp)) != 1 || Warnings(resp)[0].GetCode() != "WHITESPACE_TRIMMED" {
+		t.Errorf("Expected warning in response, got %v", Warnings(resp))
+	}
+	if method != notesv1.NotesService_CreateNote_FullMethodName || len(received) != 1 {
+		t.Errorf("Expected handler to receive CreateNote warning, got %q %v", method, received)
+	}
+}
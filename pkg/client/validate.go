@@ -0,0 +1,99 @@
+package client
+
+import (
+	"context"
+
+	"buf.build/go/protovalidate"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// skipValidation CallOption, отключающий предварительную валидацию запроса
+type skipValidation struct {
+	grpc.EmptyCallOption
+}
+
+// SkipValidation отключает предварительную валидацию для одного вызова
+// Используется, чтобы отправить невалидный запрос и проверить ответ самого сервера:
+//
+//	_, err := c.CreateNote(ctx, &notesv1.CreateNoteRequest{}, client.SkipValidation())
+func SkipValidation() grpc.CallOption {
+	return skipValidation{}
+}
+
+// validationSkipped проверяет, передан ли вызову SkipValidation
+func validationSkipped(opts []grpc.CallOption) bool {
+	for _, opt := range opts {
+		if _, ok := opt.(skipValidation); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// validate проверяет сообщение по правилам protovalidate из proto файлов
+// Ошибка имеет тот же код и формат, что и ошибка валидации на сервере
+func validate(m any) error {
+	msg, ok := m.(proto.Message)
+	if !ok {
+		return nil
+	}
+	if err := protovalidate.Validate(msg); err != nil {
+		return status.Errorf(codes.InvalidArgument, "validation failed: %v", err)
+	}
+	return nil
+}
+
+// ValidateUnaryInterceptor проверяет запрос перед отправкой и не отправляет невалидный запрос
+// Подключается через WithPreflightValidation или напрямую к grpc.ClientConn
+func ValidateUnaryInterceptor(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	if !validationSkipped(opts) {
+		if err := validate(req); err != nil {
+			return err
+		}
+	}
+	return invoker(ctx, method, req, reply, cc, opts...)
+}
+
+// validatingClientStream оборачивает grpc.ClientStream для валидации исходящих сообщений
+type validatingClientStream struct {
+	grpc.ClientStream
+	cancel context.CancelFunc
+}
+
+// SendMsg проверяет сообщение перед отправкой
+// Невалидное сообщение не отправляется, а стрим отменяется, как после ошибки отправки
+func (s *validatingClientStream) SendMsg(m any) error {
+	if err := validate(m); err != nil {
+		s.cancel()
+		return err
+	}
+	return s.ClientStream.SendMsg(m)
+}
+
+// RecvMsg освобождает контекст стрима после его завершения
+func (s *validatingClientStream) RecvMsg(m any) error {
+	err := s.ClientStream.RecvMsg(m)
+	if err != nil {
+		s.cancel()
+	}
+	return err
+}
+
+// ValidateStreamInterceptor проверяет каждое исходящее сообщение стрима
+// Для server-side streaming проверяется единственный запрос клиента
+func ValidateStreamInterceptor(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	if validationSkipped(opts) {
+		return streamer(ctx, desc, cc, method, opts...)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	stream, err := streamer(ctx, desc, cc, method, opts...)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	return &validatingClientStream{ClientStream: stream, cancel: cancel}, nil
+}
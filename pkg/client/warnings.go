@@ -0,0 +1,40 @@
+package client
+
+import (
+	"context"
+
+	notesv1 "notes-service/pkg/proto/notes/v1"
+
+	"google.golang.org/grpc"
+)
+
+// WarningHandler получает предупреждения успешного вызова method (например, "/notes.v1.NotesService/CreateNote")
+type WarningHandler func(ctx context.Context, method string, warnings []*notesv1.Warning)
+
+// WithWarningHandler вызывает handler для каждого ответа с предупреждениями,
+// например чтобы записать их в лог, не проверяя каждый ответ отдельно
+func WithWarningHandler(handler WarningHandler) Option {
+	return func(o *options) {
+		o.warningHandler = handler
+	}
+}
+
+// Warnings возвращает предупреждения ответа (nil, если ответ их не поддерживает или их нет)
+func Warnings(resp any) []*notesv1.Warning {
+	if withWarnings, ok := resp.(interface{ GetWarnings() []*notesv1.Warning }); ok {
+		return withWarnings.GetWarnings()
+	}
+	return nil
+}
+
+func warningUnaryInterceptor(handler WarningHandler) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		if err := invoker(ctx, method, req, reply, cc, opts...); err != nil {
+			return err
+		}
+		if warnings := Warnings(reply); len(warnings) > 0 {
+			handler(ctx, method, warnings)
+		}
+		return nil
+	}
+}
@@ -11,8 +11,12 @@ package notesv1
 import (
 	_ "buf.build/gen/go/bufbuild/protovalidate/protocolbuffers/go/buf/validate"
 	_ "google.golang.org/genproto/googleapis/api/annotations"
+	status "google.golang.org/genproto/googleapis/rpc/status"
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	descriptorpb "google.golang.org/protobuf/types/descriptorpb"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
+	fieldmaskpb "google.golang.org/protobuf/types/known/fieldmaskpb"
 	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 	reflect "reflect"
 	sync "sync"
@@ -26,84 +30,9534 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+// Порядок заметок по длине содержания в ListNotes
+type NoteOrder int32
+
+const (
+	NoteOrder_NOTE_ORDER_UNSPECIFIED     NoteOrder = 0 // Без сортировки по длине
+	NoteOrder_NOTE_ORDER_WORD_COUNT_ASC  NoteOrder = 1 // Сначала короткие заметки
+	NoteOrder_NOTE_ORDER_WORD_COUNT_DESC NoteOrder = 2 // Сначала длинные заметки
+)
+
+// Enum value maps for NoteOrder.
+var (
+	NoteOrder_name = map[int32]string{
+		0: "NOTE_ORDER_UNSPECIFIED",
+		1: "NOTE_ORDER_WORD_COUNT_ASC",
+		2: "NOTE_ORDER_WORD_COUNT_DESC",
+	}
+	NoteOrder_value = map[string]int32{
+		"NOTE_ORDER_UNSPECIFIED":     0,
+		"NOTE_ORDER_WORD_COUNT_ASC":  1,
+		"NOTE_ORDER_WORD_COUNT_DESC": 2,
+	}
+)
+
+func (x NoteOrder) Enum() *NoteOrder {
+	p := new(NoteOrder)
+	*p = x
+	return p
+}
+
+func (x NoteOrder) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (NoteOrder) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_notes_v1_notes_proto_enumTypes[0].Descriptor()
+}
+
+func (NoteOrder) Type() protoreflect.EnumType {
+	return &file_proto_notes_v1_notes_proto_enumTypes[0]
+}
+
+func (x NoteOrder) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use NoteOrder.Descriptor instead.
+func (NoteOrder) EnumDescriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{0}
+}
+
+// DiffFormat формат ответа DiffNoteRevisions
+type DiffFormat int32
+
+const (
+	DiffFormat_DIFF_FORMAT_UNSPECIFIED DiffFormat = 0 // Не указан (как DIFF_FORMAT_HUNKS)
+	DiffFormat_DIFF_FORMAT_HUNKS       DiffFormat = 1 // Структурированные блоки в hunks
+	DiffFormat_DIFF_FORMAT_UNIFIED     DiffFormat = 2 // Текст в формате diff -u в unified_diff
+)
+
+// Enum value maps for DiffFormat.
+var (
+	DiffFormat_name = map[int32]string{
+		0: "DIFF_FORMAT_UNSPECIFIED",
+		1: "DIFF_FORMAT_HUNKS",
+		2: "DIFF_FORMAT_UNIFIED",
+	}
+	DiffFormat_value = map[string]int32{
+		"DIFF_FORMAT_UNSPECIFIED": 0,
+		"DIFF_FORMAT_HUNKS":       1,
+		"DIFF_FORMAT_UNIFIED":     2,
+	}
+)
+
+func (x DiffFormat) Enum() *DiffFormat {
+	p := new(DiffFormat)
+	*p = x
+	return p
+}
+
+func (x DiffFormat) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (DiffFormat) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_notes_v1_notes_proto_enumTypes[1].Descriptor()
+}
+
+func (DiffFormat) Type() protoreflect.EnumType {
+	return &file_proto_notes_v1_notes_proto_enumTypes[1]
+}
+
+func (x DiffFormat) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use DiffFormat.Descriptor instead.
+func (DiffFormat) EnumDescriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{1}
+}
+
+// DiffLineKind вид строки диффа
+type DiffLineKind int32
+
+const (
+	DiffLineKind_DIFF_LINE_KIND_UNSPECIFIED DiffLineKind = 0 // Не указан
+	DiffLineKind_DIFF_LINE_KIND_CONTEXT     DiffLineKind = 1 // Строка есть в обеих ревизиях
+	DiffLineKind_DIFF_LINE_KIND_ADDED       DiffLineKind = 2 // Строка добавлена
+	DiffLineKind_DIFF_LINE_KIND_REMOVED     DiffLineKind = 3 // Строка удалена
+)
+
+// Enum value maps for DiffLineKind.
+var (
+	DiffLineKind_name = map[int32]string{
+		0: "DIFF_LINE_KIND_UNSPECIFIED",
+		1: "DIFF_LINE_KIND_CONTEXT",
+		2: "DIFF_LINE_KIND_ADDED",
+		3: "DIFF_LINE_KIND_REMOVED",
+	}
+	DiffLineKind_value = map[string]int32{
+		"DIFF_LINE_KIND_UNSPECIFIED": 0,
+		"DIFF_LINE_KIND_CONTEXT":     1,
+		"DIFF_LINE_KIND_ADDED":       2,
+		"DIFF_LINE_KIND_REMOVED":     3,
+	}
+)
+
+func (x DiffLineKind) Enum() *DiffLineKind {
+	p := new(DiffLineKind)
+	*p = x
+	return p
+}
+
+func (x DiffLineKind) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (DiffLineKind) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_notes_v1_notes_proto_enumTypes[2].Descriptor()
+}
+
+func (DiffLineKind) Type() protoreflect.EnumType {
+	return &file_proto_notes_v1_notes_proto_enumTypes[2]
+}
+
+func (x DiffLineKind) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use DiffLineKind.Descriptor instead.
+func (DiffLineKind) EnumDescriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{2}
+}
+
+// SharePermission уровень доступа к чужой заметке
+type SharePermission int32
+
+const (
+	SharePermission_SHARE_PERMISSION_UNSPECIFIED SharePermission = 0 // Не указан (недопустим в запросах)
+	SharePermission_SHARE_PERMISSION_READ        SharePermission = 1 // Чтение заметки
+	SharePermission_SHARE_PERMISSION_WRITE       SharePermission = 2 // Чтение и изменение заметки
+)
+
+// Enum value maps for SharePermission.
+var (
+	SharePermission_name = map[int32]string{
+		0: "SHARE_PERMISSION_UNSPECIFIED",
+		1: "SHARE_PERMISSION_READ",
+		2: "SHARE_PERMISSION_WRITE",
+	}
+	SharePermission_value = map[string]int32{
+		"SHARE_PERMISSION_UNSPECIFIED": 0,
+		"SHARE_PERMISSION_READ":        1,
+		"SHARE_PERMISSION_WRITE":       2,
+	}
+)
+
+func (x SharePermission) Enum() *SharePermission {
+	p := new(SharePermission)
+	*p = x
+	return p
+}
+
+func (x SharePermission) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (SharePermission) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_notes_v1_notes_proto_enumTypes[3].Descriptor()
+}
+
+func (SharePermission) Type() protoreflect.EnumType {
+	return &file_proto_notes_v1_notes_proto_enumTypes[3]
+}
+
+func (x SharePermission) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use SharePermission.Descriptor instead.
+func (SharePermission) EnumDescriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{3}
+}
+
+// ExportFormat формат выгрузки заметок
+type ExportFormat int32
+
+const (
+	ExportFormat_EXPORT_FORMAT_UNSPECIFIED ExportFormat = 0 // Не указан (недопустим в запросах)
+	ExportFormat_EXPORT_FORMAT_JSONL       ExportFormat = 1 // JSON Lines: одна заметка на строку (выгрузка и загрузка)
+	ExportFormat_EXPORT_FORMAT_MARKDOWN    ExportFormat = 2 // Markdown документ для чтения (только выгрузка)
+	ExportFormat_EXPORT_FORMAT_CSV         ExportFormat = 3 // CSV с заголовком (выгрузка и загрузка)
+)
+
+// Enum value maps for ExportFormat.
+var (
+	ExportFormat_name = map[int32]string{
+		0: "EXPORT_FORMAT_UNSPECIFIED",
+		1: "EXPORT_FORMAT_JSONL",
+		2: "EXPORT_FORMAT_MARKDOWN",
+		3: "EXPORT_FORMAT_CSV",
+	}
+	ExportFormat_value = map[string]int32{
+		"EXPORT_FORMAT_UNSPECIFIED": 0,
+		"EXPORT_FORMAT_JSONL":       1,
+		"EXPORT_FORMAT_MARKDOWN":    2,
+		"EXPORT_FORMAT_CSV":         3,
+	}
+)
+
+func (x ExportFormat) Enum() *ExportFormat {
+	p := new(ExportFormat)
+	*p = x
+	return p
+}
+
+func (x ExportFormat) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ExportFormat) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_notes_v1_notes_proto_enumTypes[4].Descriptor()
+}
+
+func (ExportFormat) Type() protoreflect.EnumType {
+	return &file_proto_notes_v1_notes_proto_enumTypes[4]
+}
+
+func (x ExportFormat) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ExportFormat.Descriptor instead.
+func (ExportFormat) EnumDescriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{4}
+}
+
+// Формат файла выгрузки в хранилище
+type ExportArchive int32
+
+const (
+	ExportArchive_EXPORT_ARCHIVE_UNSPECIFIED ExportArchive = 0 // Не указан (недопустим в запросах)
+	ExportArchive_EXPORT_ARCHIVE_NDJSON      ExportArchive = 1 // JSON Lines (.jsonl): одна заметка на строку, загружается ImportNotes
+	ExportArchive_EXPORT_ARCHIVE_ZIP         ExportArchive = 2 // ZIP архив (.zip) с файлом notes.jsonl
+)
+
+// Enum value maps for ExportArchive.
+var (
+	ExportArchive_name = map[int32]string{
+		0: "EXPORT_ARCHIVE_UNSPECIFIED",
+		1: "EXPORT_ARCHIVE_NDJSON",
+		2: "EXPORT_ARCHIVE_ZIP",
+	}
+	ExportArchive_value = map[string]int32{
+		"EXPORT_ARCHIVE_UNSPECIFIED": 0,
+		"EXPORT_ARCHIVE_NDJSON":      1,
+		"EXPORT_ARCHIVE_ZIP":         2,
+	}
+)
+
+func (x ExportArchive) Enum() *ExportArchive {
+	p := new(ExportArchive)
+	*p = x
+	return p
+}
+
+func (x ExportArchive) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ExportArchive) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_notes_v1_notes_proto_enumTypes[5].Descriptor()
+}
+
+func (ExportArchive) Type() protoreflect.EnumType {
+	return &file_proto_notes_v1_notes_proto_enumTypes[5]
+}
+
+func (x ExportArchive) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ExportArchive.Descriptor instead.
+func (ExportArchive) EnumDescriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{5}
+}
+
+// Состояние операции выгрузки
+type ExportOperationState int32
+
+const (
+	ExportOperationState_EXPORT_OPERATION_STATE_UNSPECIFIED ExportOperationState = 0
+	ExportOperationState_EXPORT_OPERATION_STATE_RUNNING     ExportOperationState = 1 // Выгрузка выполняется
+	ExportOperationState_EXPORT_OPERATION_STATE_SUCCEEDED   ExportOperationState = 2 // Файл выгрузки сохранен в location
+	ExportOperationState_EXPORT_OPERATION_STATE_FAILED      ExportOperationState = 3 // Выгрузка завершилась ошибкой (error)
+)
+
+// Enum value maps for ExportOperationState.
+var (
+	ExportOperationState_name = map[int32]string{
+		0: "EXPORT_OPERATION_STATE_UNSPECIFIED",
+		1: "EXPORT_OPERATION_STATE_RUNNING",
+		2: "EXPORT_OPERATION_STATE_SUCCEEDED",
+		3: "EXPORT_OPERATION_STATE_FAILED",
+	}
+	ExportOperationState_value = map[string]int32{
+		"EXPORT_OPERATION_STATE_UNSPECIFIED": 0,
+		"EXPORT_OPERATION_STATE_RUNNING":     1,
+		"EXPORT_OPERATION_STATE_SUCCEEDED":   2,
+		"EXPORT_OPERATION_STATE_FAILED":      3,
+	}
+)
+
+func (x ExportOperationState) Enum() *ExportOperationState {
+	p := new(ExportOperationState)
+	*p = x
+	return p
+}
+
+func (x ExportOperationState) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ExportOperationState) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_notes_v1_notes_proto_enumTypes[6].Descriptor()
+}
+
+func (ExportOperationState) Type() protoreflect.EnumType {
+	return &file_proto_notes_v1_notes_proto_enumTypes[6]
+}
+
+func (x ExportOperationState) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ExportOperationState.Descriptor instead.
+func (ExportOperationState) EnumDescriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{6}
+}
+
+// Состояние операции смены ключей
+type KeyRotationState int32
+
+const (
+	KeyRotationState_KEY_ROTATION_STATE_UNSPECIFIED KeyRotationState = 0
+	KeyRotationState_KEY_ROTATION_STATE_RUNNING     KeyRotationState = 1 // Заметки перешифровываются
+	KeyRotationState_KEY_ROTATION_STATE_SUCCEEDED   KeyRotationState = 2 // Все заметки зашифрованы новыми ключами
+	KeyRotationState_KEY_ROTATION_STATE_FAILED      KeyRotationState = 3 // Смена ключей завершилась ошибкой (error)
+)
+
+// Enum value maps for KeyRotationState.
+var (
+	KeyRotationState_name = map[int32]string{
+		0: "KEY_ROTATION_STATE_UNSPECIFIED",
+		1: "KEY_ROTATION_STATE_RUNNING",
+		2: "KEY_ROTATION_STATE_SUCCEEDED",
+		3: "KEY_ROTATION_STATE_FAILED",
+	}
+	KeyRotationState_value = map[string]int32{
+		"KEY_ROTATION_STATE_UNSPECIFIED": 0,
+		"KEY_ROTATION_STATE_RUNNING":     1,
+		"KEY_ROTATION_STATE_SUCCEEDED":   2,
+		"KEY_ROTATION_STATE_FAILED":      3,
+	}
+)
+
+func (x KeyRotationState) Enum() *KeyRotationState {
+	p := new(KeyRotationState)
+	*p = x
+	return p
+}
+
+func (x KeyRotationState) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (KeyRotationState) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_notes_v1_notes_proto_enumTypes[7].Descriptor()
+}
+
+func (KeyRotationState) Type() protoreflect.EnumType {
+	return &file_proto_notes_v1_notes_proto_enumTypes[7]
+}
+
+func (x KeyRotationState) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use KeyRotationState.Descriptor instead.
+func (KeyRotationState) EnumDescriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{7}
+}
+
+// Поведение при восстановлении заметки, которая уже есть в хранилище
+type BackupConflictStrategy int32
+
+const (
+	BackupConflictStrategy_BACKUP_CONFLICT_STRATEGY_UNSPECIFIED BackupConflictStrategy = 0 // Как SKIP
+	BackupConflictStrategy_BACKUP_CONFLICT_STRATEGY_SKIP        BackupConflictStrategy = 1 // Оставить существующую заметку
+	BackupConflictStrategy_BACKUP_CONFLICT_STRATEGY_OVERWRITE   BackupConflictStrategy = 2 // Заменить заметку копией вместе с ревизиями и доступами
+	BackupConflictStrategy_BACKUP_CONFLICT_STRATEGY_FAIL        BackupConflictStrategy = 3 // Отменить восстановление целиком (FAILED_PRECONDITION)
+)
+
+// Enum value maps for BackupConflictStrategy.
+var (
+	BackupConflictStrategy_name = map[int32]string{
+		0: "BACKUP_CONFLICT_STRATEGY_UNSPECIFIED",
+		1: "BACKUP_CONFLICT_STRATEGY_SKIP",
+		2: "BACKUP_CONFLICT_STRATEGY_OVERWRITE",
+		3: "BACKUP_CONFLICT_STRATEGY_FAIL",
+	}
+	BackupConflictStrategy_value = map[string]int32{
+		"BACKUP_CONFLICT_STRATEGY_UNSPECIFIED": 0,
+		"BACKUP_CONFLICT_STRATEGY_SKIP":        1,
+		"BACKUP_CONFLICT_STRATEGY_OVERWRITE":   2,
+		"BACKUP_CONFLICT_STRATEGY_FAIL":        3,
+	}
+)
+
+func (x BackupConflictStrategy) Enum() *BackupConflictStrategy {
+	p := new(BackupConflictStrategy)
+	*p = x
+	return p
+}
+
+func (x BackupConflictStrategy) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (BackupConflictStrategy) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_notes_v1_notes_proto_enumTypes[8].Descriptor()
+}
+
+func (BackupConflictStrategy) Type() protoreflect.EnumType {
+	return &file_proto_notes_v1_notes_proto_enumTypes[8]
+}
+
+func (x BackupConflictStrategy) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use BackupConflictStrategy.Descriptor instead.
+func (BackupConflictStrategy) EnumDescriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{8}
+}
+
+// Тип события стрима SubscribeToEvents (для фильтра event_types)
+type EventType int32
+
+const (
+	EventType_EVENT_TYPE_UNSPECIFIED          EventType = 0 // Не указан (недопустим в фильтре)
+	EventType_EVENT_TYPE_NOTE_CREATED         EventType = 1 // Создана заметка (note_created)
+	EventType_EVENT_TYPE_NOTE_UPDATED         EventType = 2 // Изменена заметка (note_updated)
+	EventType_EVENT_TYPE_NOTE_DELETED         EventType = 3 // Удалена заметка (note_deleted)
+	EventType_EVENT_TYPE_NOTE_SHARED          EventType = 4 // Открыт доступ к заметке (note_shared)
+	EventType_EVENT_TYPE_NOTE_REMINDER_DUE    EventType = 5 // Наступило время напоминания (note_reminder_due)
+	EventType_EVENT_TYPE_EXPORT_COMPLETED     EventType = 6 // Завершилась выгрузка (export_completed)
+	EventType_EVENT_TYPE_SAVED_SEARCH_MATCHED EventType = 7 // Заметка начала подходить под сохраненный поиск (saved_search_matched)
+	EventType_EVENT_TYPE_QUOTA_WARNING        EventType = 8 // Использование квоты заметок превысило мягкий порог (quota_warning)
+	EventType_EVENT_TYPE_NOTE_ARCHIVED        EventType = 9 // Заметка перемещена в архив (note_archived)
+)
+
+// Enum value maps for EventType.
+var (
+	EventType_name = map[int32]string{
+		0: "EVENT_TYPE_UNSPECIFIED",
+		1: "EVENT_TYPE_NOTE_CREATED",
+		2: "EVENT_TYPE_NOTE_UPDATED",
+		3: "EVENT_TYPE_NOTE_DELETED",
+		4: "EVENT_TYPE_NOTE_SHARED",
+		5: "EVENT_TYPE_NOTE_REMINDER_DUE",
+		6: "EVENT_TYPE_EXPORT_COMPLETED",
+		7: "EVENT_TYPE_SAVED_SEARCH_MATCHED",
+		8: "EVENT_TYPE_QUOTA_WARNING",
+		9: "EVENT_TYPE_NOTE_ARCHIVED",
+	}
+	EventType_value = map[string]int32{
+		"EVENT_TYPE_UNSPECIFIED":          0,
+		"EVENT_TYPE_NOTE_CREATED":         1,
+		"EVENT_TYPE_NOTE_UPDATED":         2,
+		"EVENT_TYPE_NOTE_DELETED":         3,
+		"EVENT_TYPE_NOTE_SHARED":          4,
+		"EVENT_TYPE_NOTE_REMINDER_DUE":    5,
+		"EVENT_TYPE_EXPORT_COMPLETED":     6,
+		"EVENT_TYPE_SAVED_SEARCH_MATCHED": 7,
+		"EVENT_TYPE_QUOTA_WARNING":        8,
+		"EVENT_TYPE_NOTE_ARCHIVED":        9,
+	}
+)
+
+func (x EventType) Enum() *EventType {
+	p := new(EventType)
+	*p = x
+	return p
+}
+
+func (x EventType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (EventType) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_notes_v1_notes_proto_enumTypes[9].Descriptor()
+}
+
+func (EventType) Type() protoreflect.EnumType {
+	return &file_proto_notes_v1_notes_proto_enumTypes[9]
+}
+
+func (x EventType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use EventType.Descriptor instead.
+func (EventType) EnumDescriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{9}
+}
+
+// MetricAggregation агрегат значений метрики в QueryMetrics
+type MetricAggregation int32
+
+const (
+	MetricAggregation_METRIC_AGGREGATION_UNSPECIFIED MetricAggregation = 0 // Значения без агрегации
+	MetricAggregation_METRIC_AGGREGATION_COUNT       MetricAggregation = 1 // Количество значений
+	MetricAggregation_METRIC_AGGREGATION_SUM         MetricAggregation = 2 // Сумма
+	MetricAggregation_METRIC_AGGREGATION_AVG         MetricAggregation = 3 // Среднее
+	MetricAggregation_METRIC_AGGREGATION_MIN         MetricAggregation = 4 // Минимум
+	MetricAggregation_METRIC_AGGREGATION_MAX         MetricAggregation = 5 // Максимум
+	MetricAggregation_METRIC_AGGREGATION_P95         MetricAggregation = 6 // 95-й перцентиль (по ближайшему рангу; после 10000 значений - по случайной выборке)
+)
+
+// Enum value maps for MetricAggregation.
+var (
+	MetricAggregation_name = map[int32]string{
+		0: "METRIC_AGGREGATION_UNSPECIFIED",
+		1: "METRIC_AGGREGATION_COUNT",
+		2: "METRIC_AGGREGATION_SUM",
+		3: "METRIC_AGGREGATION_AVG",
+		4: "METRIC_AGGREGATION_MIN",
+		5: "METRIC_AGGREGATION_MAX",
+		6: "METRIC_AGGREGATION_P95",
+	}
+	MetricAggregation_value = map[string]int32{
+		"METRIC_AGGREGATION_UNSPECIFIED": 0,
+		"METRIC_AGGREGATION_COUNT":       1,
+		"METRIC_AGGREGATION_SUM":         2,
+		"METRIC_AGGREGATION_AVG":         3,
+		"METRIC_AGGREGATION_MIN":         4,
+		"METRIC_AGGREGATION_MAX":         5,
+		"METRIC_AGGREGATION_P95":         6,
+	}
+)
+
+func (x MetricAggregation) Enum() *MetricAggregation {
+	p := new(MetricAggregation)
+	*p = x
+	return p
+}
+
+func (x MetricAggregation) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (MetricAggregation) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_notes_v1_notes_proto_enumTypes[10].Descriptor()
+}
+
+func (MetricAggregation) Type() protoreflect.EnumType {
+	return &file_proto_notes_v1_notes_proto_enumTypes[10]
+}
+
+func (x MetricAggregation) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use MetricAggregation.Descriptor instead.
+func (MetricAggregation) EnumDescriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{10}
+}
+
+// Изменение присутствия пользователя в комнате
+type PresenceState int32
+
+const (
+	PresenceState_PRESENCE_STATE_UNSPECIFIED PresenceState = 0
+	PresenceState_PRESENCE_STATE_JOINED      PresenceState = 1 // Первый стрим пользователя вошел в комнату
+	PresenceState_PRESENCE_STATE_LEFT        PresenceState = 2 // Последний стрим пользователя вышел из комнаты или отключился
+)
+
+// Enum value maps for PresenceState.
+var (
+	PresenceState_name = map[int32]string{
+		0: "PRESENCE_STATE_UNSPECIFIED",
+		1: "PRESENCE_STATE_JOINED",
+		2: "PRESENCE_STATE_LEFT",
+	}
+	PresenceState_value = map[string]int32{
+		"PRESENCE_STATE_UNSPECIFIED": 0,
+		"PRESENCE_STATE_JOINED":      1,
+		"PRESENCE_STATE_LEFT":        2,
+	}
+)
+
+func (x PresenceState) Enum() *PresenceState {
+	p := new(PresenceState)
+	*p = x
+	return p
+}
+
+func (x PresenceState) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (PresenceState) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_notes_v1_notes_proto_enumTypes[11].Descriptor()
+}
+
+func (PresenceState) Type() protoreflect.EnumType {
+	return &file_proto_notes_v1_notes_proto_enumTypes[11]
+}
+
+func (x PresenceState) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use PresenceState.Descriptor instead.
+func (PresenceState) EnumDescriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{11}
+}
+
 // ChatErrorCode определяет детерминированные коды ошибок для чата
 // Подробности: см. README.md раздел "ChatError: использование enum"
 type ChatErrorCode int32
 
-const (
-	ChatErrorCode_CHAT_ERROR_CODE_UNSPECIFIED      ChatErrorCode = 0 // Не указан (использовать не рекомендуется)
-	ChatErrorCode_CHAT_ERROR_CODE_VALIDATION_ERROR ChatErrorCode = 1 // Ошибка валидации сообщения
-	ChatErrorCode_CHAT_ERROR_CODE_RATE_LIMIT       ChatErrorCode = 2 // Превышен лимит запросов
-	ChatErrorCode_CHAT_ERROR_CODE_INVALID_MESSAGE  ChatErrorCode = 3 // Некорректное сообщение
-)
+const (
+	ChatErrorCode_CHAT_ERROR_CODE_UNSPECIFIED      ChatErrorCode = 0 // Не указан (использовать не рекомендуется)
+	ChatErrorCode_CHAT_ERROR_CODE_VALIDATION_ERROR ChatErrorCode = 1 // Ошибка валидации сообщения
+	ChatErrorCode_CHAT_ERROR_CODE_RATE_LIMIT       ChatErrorCode = 2 // Превышен лимит запросов
+	ChatErrorCode_CHAT_ERROR_CODE_INVALID_MESSAGE  ChatErrorCode = 3 // Некорректное сообщение
+	ChatErrorCode_CHAT_ERROR_CODE_NOT_IN_ROOM      ChatErrorCode = 4 // Отправитель не находится в комнате room_id
+	ChatErrorCode_CHAT_ERROR_CODE_TOO_MANY_ROOMS   ChatErrorCode = 5 // Превышено количество комнат одного стрима
+)
+
+// Enum value maps for ChatErrorCode.
+var (
+	ChatErrorCode_name = map[int32]string{
+		0: "CHAT_ERROR_CODE_UNSPECIFIED",
+		1: "CHAT_ERROR_CODE_VALIDATION_ERROR",
+		2: "CHAT_ERROR_CODE_RATE_LIMIT",
+		3: "CHAT_ERROR_CODE_INVALID_MESSAGE",
+		4: "CHAT_ERROR_CODE_NOT_IN_ROOM",
+		5: "CHAT_ERROR_CODE_TOO_MANY_ROOMS",
+	}
+	ChatErrorCode_value = map[string]int32{
+		"CHAT_ERROR_CODE_UNSPECIFIED":      0,
+		"CHAT_ERROR_CODE_VALIDATION_ERROR": 1,
+		"CHAT_ERROR_CODE_RATE_LIMIT":       2,
+		"CHAT_ERROR_CODE_INVALID_MESSAGE":  3,
+		"CHAT_ERROR_CODE_NOT_IN_ROOM":      4,
+		"CHAT_ERROR_CODE_TOO_MANY_ROOMS":   5,
+	}
+)
+
+func (x ChatErrorCode) Enum() *ChatErrorCode {
+	p := new(ChatErrorCode)
+	*p = x
+	return p
+}
+
+func (x ChatErrorCode) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ChatErrorCode) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_notes_v1_notes_proto_enumTypes[12].Descriptor()
+}
+
+func (ChatErrorCode) Type() protoreflect.EnumType {
+	return &file_proto_notes_v1_notes_proto_enumTypes[12]
+}
+
+func (x ChatErrorCode) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ChatErrorCode.Descriptor instead.
+func (ChatErrorCode) EnumDescriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{12}
+}
+
+// MethodPolicy политика вызова метода, объявленная рядом с методом опцией (notes.v1.policy)
+// Сервер читает политики из дескрипторов при запуске и настраивает по ним интерцепторы
+// (internal/api/grpc/interceptors/policy.go); методы без опции используют политику по умолчанию
+type MethodPolicy struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Требуется действительный токен (по умолчанию да). Без требования метод вызывается и без токена,
+	// а действительный токен все равно передает пользователя в контекст
+	RequiresAuth *bool `protobuf:"varint,1,opt,name=requires_auth,json=requiresAuth,proto3,oneof" json:"requires_auth,omitempty"`
+	// Роли, любая из которых разрешает вызов; пусто - любой аутентифицированный пользователь
+	Roles []string `protobuf:"bytes,2,rep,name=roles,proto3" json:"roles,omitempty"`
+	// Лимит входящих сообщений одного стрима (client-side и bidirectional методы)
+	// Перекрывается server.stream_rate_limits конфигурации
+	RateLimit *StreamRateLimitPolicy `protobuf:"bytes,3,opt,name=rate_limit,json=rateLimit,proto3" json:"rate_limit,omitempty"`
+	// Максимальное время выполнения unary запроса: более поздний срок клиента сокращается до него
+	Timeout *durationpb.Duration `protobuf:"bytes,4,opt,name=timeout,proto3" json:"timeout,omitempty"`
+	// Повторный вызов безопасен: Gateway повторяет его при UNAVAILABLE
+	Idempotent bool `protobuf:"varint,5,opt,name=idempotent,proto3" json:"idempotent,omitempty"`
+	// Метод устарел: ответ содержит заголовок deprecation, первый вызов записывается в лог
+	Deprecated    bool `protobuf:"varint,6,opt,name=deprecated,proto3" json:"deprecated,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MethodPolicy) Reset() {
+	*x = MethodPolicy{}
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MethodPolicy) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MethodPolicy) ProtoMessage() {}
+
+func (x *MethodPolicy) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MethodPolicy.ProtoReflect.Descriptor instead.
+func (*MethodPolicy) Descriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *MethodPolicy) GetRequiresAuth() bool {
+	if x != nil && x.RequiresAuth != nil {
+		return *x.RequiresAuth
+	}
+	return false
+}
+
+func (x *MethodPolicy) GetRoles() []string {
+	if x != nil {
+		return x.Roles
+	}
+	return nil
+}
+
+func (x *MethodPolicy) GetRateLimit() *StreamRateLimitPolicy {
+	if x != nil {
+		return x.RateLimit
+	}
+	return nil
+}
+
+func (x *MethodPolicy) GetTimeout() *durationpb.Duration {
+	if x != nil {
+		return x.Timeout
+	}
+	return nil
+}
+
+func (x *MethodPolicy) GetIdempotent() bool {
+	if x != nil {
+		return x.Idempotent
+	}
+	return false
+}
+
+func (x *MethodPolicy) GetDeprecated() bool {
+	if x != nil {
+		return x.Deprecated
+	}
+	return false
+}
+
+// StreamRateLimitPolicy лимит входящих сообщений стрима (token bucket)
+type StreamRateLimitPolicy struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	MessagesPerSecond float64                `protobuf:"fixed64,1,opt,name=messages_per_second,json=messagesPerSecond,proto3" json:"messages_per_second,omitempty"` // Скорость пополнения бюджета
+	Burst             int32                  `protobuf:"varint,2,opt,name=burst,proto3" json:"burst,omitempty"`                                                     // Размер бюджета: столько сообщений можно отправить подряд
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *StreamRateLimitPolicy) Reset() {
+	*x = StreamRateLimitPolicy{}
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamRateLimitPolicy) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamRateLimitPolicy) ProtoMessage() {}
+
+func (x *StreamRateLimitPolicy) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamRateLimitPolicy.ProtoReflect.Descriptor instead.
+func (*StreamRateLimitPolicy) Descriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *StreamRateLimitPolicy) GetMessagesPerSecond() float64 {
+	if x != nil {
+		return x.MessagesPerSecond
+	}
+	return 0
+}
+
+func (x *StreamRateLimitPolicy) GetBurst() int32 {
+	if x != nil {
+		return x.Burst
+	}
+	return 0
+}
+
+// Запрос на создание заметки
+type CreateNoteRequest struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Title            string                 `protobuf:"bytes,1,opt,name=title,proto3" json:"title,omitempty"`                                               // Заголовок заметки (обязательное, минимум 5 символов, максимум 255)
+	Content          string                 `protobuf:"bytes,2,opt,name=content,proto3" json:"content,omitempty"`                                           // Содержание заметки (обязательное, минимум 10 символов; пустое для e2e заметок)
+	Tags             []string               `protobuf:"bytes,3,rep,name=tags,proto3" json:"tags,omitempty"`                                                 // Теги заметки (до 20, регистр не учитывается)
+	IsE2E            bool                   `protobuf:"varint,4,opt,name=is_e2e,json=isE2e,proto3" json:"is_e2e,omitempty"`                                 // Заметка зашифрована на клиенте (сквозное шифрование)
+	E2EScheme        string                 `protobuf:"bytes,5,opt,name=e2e_scheme,json=e2eScheme,proto3" json:"e2e_scheme,omitempty"`                      // Схема шифрования из GetServerInfo (для e2e заметок)
+	ContentEncrypted []byte                 `protobuf:"bytes,6,opt,name=content_encrypted,json=contentEncrypted,proto3" json:"content_encrypted,omitempty"` // Зашифрованное содержимое (для e2e заметок, до 1 МБ), сервер хранит его как есть
+	IdempotencyKey   string                 `protobuf:"bytes,7,opt,name=idempotency_key,json=idempotencyKey,proto3" json:"idempotency_key,omitempty"`       // Ключ идемпотентности (или метаданные x-idempotency-key): повтор с тем же ключом возвращает исходную заметку
+	RemindAt         *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=remind_at,json=remindAt,proto3" json:"remind_at,omitempty"`                         // Время напоминания (опционально): в этот момент подписчики получат NoteReminderDue
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *CreateNoteRequest) Reset() {
+	*x = CreateNoteRequest{}
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateNoteRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateNoteRequest) ProtoMessage() {}
+
+func (x *CreateNoteRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateNoteRequest.ProtoReflect.Descriptor instead.
+func (*CreateNoteRequest) Descriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *CreateNoteRequest) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *CreateNoteRequest) GetContent() string {
+	if x != nil {
+		return x.Content
+	}
+	return ""
+}
+
+func (x *CreateNoteRequest) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *CreateNoteRequest) GetIsE2E() bool {
+	if x != nil {
+		return x.IsE2E
+	}
+	return false
+}
+
+func (x *CreateNoteRequest) GetE2EScheme() string {
+	if x != nil {
+		return x.E2EScheme
+	}
+	return ""
+}
+
+func (x *CreateNoteRequest) GetContentEncrypted() []byte {
+	if x != nil {
+		return x.ContentEncrypted
+	}
+	return nil
+}
+
+func (x *CreateNoteRequest) GetIdempotencyKey() string {
+	if x != nil {
+		return x.IdempotencyKey
+	}
+	return ""
+}
+
+func (x *CreateNoteRequest) GetRemindAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.RemindAt
+	}
+	return nil
+}
+
+// Ответ с созданной заметкой
+type CreateNoteResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Note          *Note                  `protobuf:"bytes,1,opt,name=note,proto3" json:"note,omitempty"`
+	Warnings      []*Warning             `protobuf:"bytes,2,rep,name=warnings,proto3" json:"warnings,omitempty"` // Некритичные замечания к запросу (также в HTTP заголовке Warning)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateNoteResponse) Reset() {
+	*x = CreateNoteResponse{}
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateNoteResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateNoteResponse) ProtoMessage() {}
+
+func (x *CreateNoteResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateNoteResponse.ProtoReflect.Descriptor instead.
+func (*CreateNoteResponse) Descriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *CreateNoteResponse) GetNote() *Note {
+	if x != nil {
+		return x.Note
+	}
+	return nil
+}
+
+func (x *CreateNoteResponse) GetWarnings() []*Warning {
+	if x != nil {
+		return x.Warnings
+	}
+	return nil
+}
+
+// Предупреждение: сервер выполнил запрос, но изменил или проигнорировал часть переданных значений
+type Warning struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Code          string                 `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`       // Код предупреждения (WHITESPACE_TRIMMED, TAGS_NORMALIZED, REMIND_AT_IN_PAST, STALE_READ)
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"` // Описание для человека
+	Field         string                 `protobuf:"bytes,3,opt,name=field,proto3" json:"field,omitempty"`     // Поле запроса, к которому относится предупреждение
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Warning) Reset() {
+	*x = Warning{}
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Warning) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Warning) ProtoMessage() {}
+
+func (x *Warning) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Warning.ProtoReflect.Descriptor instead.
+func (*Warning) Descriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *Warning) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *Warning) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *Warning) GetField() string {
+	if x != nil {
+		return x.Field
+	}
+	return ""
+}
+
+// Запрос на получение заметки по UUID
+type GetNoteRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"` // UUID заметки
+	// Возвращаемые поля заметки. Без маски возвращаются все поля, кроме вычисляемых
+	// word_count и reading_time: они возвращаются, только если перечислены в маске
+	ReadMask      *fieldmaskpb.FieldMask `protobuf:"bytes,2,opt,name=read_mask,json=readMask,proto3" json:"read_mask,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetNoteRequest) Reset() {
+	*x = GetNoteRequest{}
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetNoteRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetNoteRequest) ProtoMessage() {}
+
+func (x *GetNoteRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetNoteRequest.ProtoReflect.Descriptor instead.
+func (*GetNoteRequest) Descriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *GetNoteRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *GetNoteRequest) GetReadMask() *fieldmaskpb.FieldMask {
+	if x != nil {
+		return x.ReadMask
+	}
+	return nil
+}
+
+// Ответ с заметкой
+type GetNoteResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Note          *Note                  `protobuf:"bytes,1,opt,name=note,proto3" json:"note,omitempty"`
+	Warnings      []*Warning             `protobuf:"bytes,2,rep,name=warnings,proto3" json:"warnings,omitempty"` // Предупреждения (STALE_READ, если хранилище недоступно и данные прочитаны из снимка)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetNoteResponse) Reset() {
+	*x = GetNoteResponse{}
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetNoteResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetNoteResponse) ProtoMessage() {}
+
+func (x *GetNoteResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetNoteResponse.ProtoReflect.Descriptor instead.
+func (*GetNoteResponse) Descriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *GetNoteResponse) GetNote() *Note {
+	if x != nil {
+		return x.Note
+	}
+	return nil
+}
+
+func (x *GetNoteResponse) GetWarnings() []*Warning {
+	if x != nil {
+		return x.Warnings
+	}
+	return nil
+}
+
+// Запрос на получение списка заметок
+type ListNotesRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Язык сортировки заметок по заголовку (BCP 47, например "ru" или "en-US")
+	// Если не задан, используется Accept-Language запроса; без обоих порядок не гарантируется
+	TitleCollation string `protobuf:"bytes,1,opt,name=title_collation,json=titleCollation,proto3" json:"title_collation,omitempty"`
+	// Порядок по длине содержания (после закрепленных заметок); при равной длине - по title_collation
+	OrderBy      NoteOrder `protobuf:"varint,2,opt,name=order_by,json=orderBy,proto3,enum=notes.v1.NoteOrder" json:"order_by,omitempty"`
+	MinWordCount int32     `protobuf:"varint,3,opt,name=min_word_count,json=minWordCount,proto3" json:"min_word_count,omitempty"` // Минимальное количество слов в содержании (0 - без ограничения)
+	MaxWordCount int32     `protobuf:"varint,4,opt,name=max_word_count,json=maxWordCount,proto3" json:"max_word_count,omitempty"` // Максимальное количество слов в содержании (0 - без ограничения)
+	// Возвращаемые поля заметок (как read_mask в GetNoteRequest)
+	ReadMask *fieldmaskpb.FieldMask `protobuf:"bytes,5,opt,name=read_mask,json=readMask,proto3" json:"read_mask,omitempty"`
+	// Включить в список архивные заметки (см. ArchiveNote)
+	IncludeArchived bool `protobuf:"varint,6,opt,name=include_archived,json=includeArchived,proto3" json:"include_archived,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *ListNotesRequest) Reset() {
+	*x = ListNotesRequest{}
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListNotesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListNotesRequest) ProtoMessage() {}
+
+func (x *ListNotesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListNotesRequest.ProtoReflect.Descriptor instead.
+func (*ListNotesRequest) Descriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ListNotesRequest) GetTitleCollation() string {
+	if x != nil {
+		return x.TitleCollation
+	}
+	return ""
+}
+
+func (x *ListNotesRequest) GetOrderBy() NoteOrder {
+	if x != nil {
+		return x.OrderBy
+	}
+	return NoteOrder_NOTE_ORDER_UNSPECIFIED
+}
+
+func (x *ListNotesRequest) GetMinWordCount() int32 {
+	if x != nil {
+		return x.MinWordCount
+	}
+	return 0
+}
+
+func (x *ListNotesRequest) GetMaxWordCount() int32 {
+	if x != nil {
+		return x.MaxWordCount
+	}
+	return 0
+}
+
+func (x *ListNotesRequest) GetReadMask() *fieldmaskpb.FieldMask {
+	if x != nil {
+		return x.ReadMask
+	}
+	return nil
+}
+
+func (x *ListNotesRequest) GetIncludeArchived() bool {
+	if x != nil {
+		return x.IncludeArchived
+	}
+	return false
+}
+
+// Ответ со списком заметок
+type ListNotesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Notes         []*Note                `protobuf:"bytes,1,rep,name=notes,proto3" json:"notes,omitempty"`
+	Warnings      []*Warning             `protobuf:"bytes,2,rep,name=warnings,proto3" json:"warnings,omitempty"` // Предупреждения (STALE_READ, если хранилище недоступно и данные прочитаны из снимка)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListNotesResponse) Reset() {
+	*x = ListNotesResponse{}
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListNotesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListNotesResponse) ProtoMessage() {}
+
+func (x *ListNotesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListNotesResponse.ProtoReflect.Descriptor instead.
+func (*ListNotesResponse) Descriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ListNotesResponse) GetNotes() []*Note {
+	if x != nil {
+		return x.Notes
+	}
+	return nil
+}
+
+func (x *ListNotesResponse) GetWarnings() []*Warning {
+	if x != nil {
+		return x.Warnings
+	}
+	return nil
+}
+
+// Запрос потока заметок
+type StreamNotesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	BatchSize     int32                  `protobuf:"varint,1,opt,name=batch_size,json=batchSize,proto3" json:"batch_size,omitempty"` // Количество заметок, читаемых из хранилища за раз (0 - по умолчанию, 100)
+	Cursor        string                 `protobuf:"bytes,2,opt,name=cursor,proto3" json:"cursor,omitempty"`                         // ID последней полученной заметки; поток продолжается со следующей
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamNotesRequest) Reset() {
+	*x = StreamNotesRequest{}
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamNotesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamNotesRequest) ProtoMessage() {}
+
+func (x *StreamNotesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamNotesRequest.ProtoReflect.Descriptor instead.
+func (*StreamNotesRequest) Descriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *StreamNotesRequest) GetBatchSize() int32 {
+	if x != nil {
+		return x.BatchSize
+	}
+	return 0
+}
+
+func (x *StreamNotesRequest) GetCursor() string {
+	if x != nil {
+		return x.Cursor
+	}
+	return ""
+}
+
+// Запрос на обновление заметки
+type UpdateNoteRequest struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Id      string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`            // UUID заметки
+	Title   string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`      // Новый заголовок (опционально)
+	Content string                 `protobuf:"bytes,3,opt,name=content,proto3" json:"content,omitempty"`  // Новое содержание (опционально)
+	Version int64                  `protobuf:"varint,4,opt,name=version,proto3" json:"version,omitempty"` // Ожидаемая версия заметки (0 - без проверки, иначе обновление отклоняется при несовпадении)
+	// Список обновляемых полей ("title", "content", "tags", "content_encrypted", "remind_at"). Если маска задана, обновляются ровно эти поля:
+	// например, content = "" с маской "content" очищает содержание. Без маски пустой title
+	// не меняет заголовок, а content обновляется всегда
+	UpdateMask *fieldmaskpb.FieldMask `protobuf:"bytes,5,opt,name=update_mask,json=updateMask,proto3" json:"update_mask,omitempty"`
+	// Принудительно записать обновление (новая версия, updated_at и ревизия),
+	// даже если title и content не изменились
+	Force            bool                   `protobuf:"varint,6,opt,name=force,proto3" json:"force,omitempty"`
+	Tags             []string               `protobuf:"bytes,7,rep,name=tags,proto3" json:"tags,omitempty"`                                                 // Новые теги (без маски пустой список не меняет теги)
+	ContentEncrypted []byte                 `protobuf:"bytes,8,opt,name=content_encrypted,json=contentEncrypted,proto3" json:"content_encrypted,omitempty"` // Новое зашифрованное содержимое e2e заметки (без маски пустое не меняет его)
+	RemindAt         *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=remind_at,json=remindAt,proto3" json:"remind_at,omitempty"`                         // Новое время напоминания (без маски не переданное не меняет его, с маской "remind_at" пустое снимает напоминание)
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *UpdateNoteRequest) Reset() {
+	*x = UpdateNoteRequest{}
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateNoteRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateNoteRequest) ProtoMessage() {}
+
+func (x *UpdateNoteRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateNoteRequest.ProtoReflect.Descriptor instead.
+func (*UpdateNoteRequest) Descriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *UpdateNoteRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *UpdateNoteRequest) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *UpdateNoteRequest) GetContent() string {
+	if x != nil {
+		return x.Content
+	}
+	return ""
+}
+
+func (x *UpdateNoteRequest) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *UpdateNoteRequest) GetUpdateMask() *fieldmaskpb.FieldMask {
+	if x != nil {
+		return x.UpdateMask
+	}
+	return nil
+}
+
+func (x *UpdateNoteRequest) GetForce() bool {
+	if x != nil {
+		return x.Force
+	}
+	return false
+}
+
+func (x *UpdateNoteRequest) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *UpdateNoteRequest) GetContentEncrypted() []byte {
+	if x != nil {
+		return x.ContentEncrypted
+	}
+	return nil
+}
+
+func (x *UpdateNoteRequest) GetRemindAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.RemindAt
+	}
+	return nil
+}
+
+// Ответ с обновленной заметкой
+type UpdateNoteResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Note          *Note                  `protobuf:"bytes,1,opt,name=note,proto3" json:"note,omitempty"`
+	Warnings      []*Warning             `protobuf:"bytes,2,rep,name=warnings,proto3" json:"warnings,omitempty"` // Некритичные замечания к запросу (также в HTTP заголовке Warning)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateNoteResponse) Reset() {
+	*x = UpdateNoteResponse{}
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateNoteResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateNoteResponse) ProtoMessage() {}
+
+func (x *UpdateNoteResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateNoteResponse.ProtoReflect.Descriptor instead.
+func (*UpdateNoteResponse) Descriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *UpdateNoteResponse) GetNote() *Note {
+	if x != nil {
+		return x.Note
+	}
+	return nil
+}
+
+func (x *UpdateNoteResponse) GetWarnings() []*Warning {
+	if x != nil {
+		return x.Warnings
+	}
+	return nil
+}
+
+// Запрос на удаление заметки
+type DeleteNoteRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"` // UUID заметки
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteNoteRequest) Reset() {
+	*x = DeleteNoteRequest{}
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteNoteRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteNoteRequest) ProtoMessage() {}
+
+func (x *DeleteNoteRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteNoteRequest.ProtoReflect.Descriptor instead.
+func (*DeleteNoteRequest) Descriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *DeleteNoteRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+// Ответ на удаление заметки
+type DeleteNoteResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteNoteResponse) Reset() {
+	*x = DeleteNoteResponse{}
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteNoteResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteNoteResponse) ProtoMessage() {}
+
+func (x *DeleteNoteResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteNoteResponse.ProtoReflect.Descriptor instead.
+func (*DeleteNoteResponse) Descriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{13}
+}
+
+// Запрос на закрепление заметки
+type PinNoteRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"` // UUID заметки
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PinNoteRequest) Reset() {
+	*x = PinNoteRequest{}
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PinNoteRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PinNoteRequest) ProtoMessage() {}
+
+func (x *PinNoteRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PinNoteRequest.ProtoReflect.Descriptor instead.
+func (*PinNoteRequest) Descriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *PinNoteRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+// Ответ с закрепленной заметкой
+type PinNoteResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Note          *Note                  `protobuf:"bytes,1,opt,name=note,proto3" json:"note,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PinNoteResponse) Reset() {
+	*x = PinNoteResponse{}
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PinNoteResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PinNoteResponse) ProtoMessage() {}
+
+func (x *PinNoteResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PinNoteResponse.ProtoReflect.Descriptor instead.
+func (*PinNoteResponse) Descriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *PinNoteResponse) GetNote() *Note {
+	if x != nil {
+		return x.Note
+	}
+	return nil
+}
+
+// Запрос на открепление заметки
+type UnpinNoteRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"` // UUID заметки
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UnpinNoteRequest) Reset() {
+	*x = UnpinNoteRequest{}
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UnpinNoteRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UnpinNoteRequest) ProtoMessage() {}
+
+func (x *UnpinNoteRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UnpinNoteRequest.ProtoReflect.Descriptor instead.
+func (*UnpinNoteRequest) Descriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *UnpinNoteRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+// Ответ с открепленной заметкой
+type UnpinNoteResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Note          *Note                  `protobuf:"bytes,1,opt,name=note,proto3" json:"note,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UnpinNoteResponse) Reset() {
+	*x = UnpinNoteResponse{}
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UnpinNoteResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UnpinNoteResponse) ProtoMessage() {}
+
+func (x *UnpinNoteResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UnpinNoteResponse.ProtoReflect.Descriptor instead.
+func (*UnpinNoteResponse) Descriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *UnpinNoteResponse) GetNote() *Note {
+	if x != nil {
+		return x.Note
+	}
+	return nil
+}
+
+// Запрос на архивирование заметки
+type ArchiveNoteRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"` // UUID заметки
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ArchiveNoteRequest) Reset() {
+	*x = ArchiveNoteRequest{}
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ArchiveNoteRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ArchiveNoteRequest) ProtoMessage() {}
+
+func (x *ArchiveNoteRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ArchiveNoteRequest.ProtoReflect.Descriptor instead.
+func (*ArchiveNoteRequest) Descriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *ArchiveNoteRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+// Ответ с архивированной заметкой
+type ArchiveNoteResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Note          *Note                  `protobuf:"bytes,1,opt,name=note,proto3" json:"note,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ArchiveNoteResponse) Reset() {
+	*x = ArchiveNoteResponse{}
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ArchiveNoteResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ArchiveNoteResponse) ProtoMessage() {}
+
+func (x *ArchiveNoteResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ArchiveNoteResponse.ProtoReflect.Descriptor instead.
+func (*ArchiveNoteResponse) Descriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *ArchiveNoteResponse) GetNote() *Note {
+	if x != nil {
+		return x.Note
+	}
+	return nil
+}
+
+// Запрос на возврат заметки из архива
+type UnarchiveNoteRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"` // UUID заметки
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UnarchiveNoteRequest) Reset() {
+	*x = UnarchiveNoteRequest{}
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UnarchiveNoteRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UnarchiveNoteRequest) ProtoMessage() {}
+
+func (x *UnarchiveNoteRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UnarchiveNoteRequest.ProtoReflect.Descriptor instead.
+func (*UnarchiveNoteRequest) Descriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *UnarchiveNoteRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+// Ответ с возвращенной из архива заметкой
+type UnarchiveNoteResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Note          *Note                  `protobuf:"bytes,1,opt,name=note,proto3" json:"note,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UnarchiveNoteResponse) Reset() {
+	*x = UnarchiveNoteResponse{}
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UnarchiveNoteResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UnarchiveNoteResponse) ProtoMessage() {}
+
+func (x *UnarchiveNoteResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UnarchiveNoteResponse.ProtoReflect.Descriptor instead.
+func (*UnarchiveNoteResponse) Descriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *UnarchiveNoteResponse) GetNote() *Note {
+	if x != nil {
+		return x.Note
+	}
+	return nil
+}
+
+// Запрос на блокировку заметки
+type LockNoteRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`                                    // UUID заметки
+	TtlSeconds    int32                  `protobuf:"varint,2,opt,name=ttl_seconds,json=ttlSeconds,proto3" json:"ttl_seconds,omitempty"` // Время аренды блокировки (0 - 300 секунд по умолчанию, максимум час); повторный вызов владельцем продлевает аренду
+	Force         bool                   `protobuf:"varint,3,opt,name=force,proto3" json:"force,omitempty"`                             // Перехватить блокировку другого пользователя (только для роли admin)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LockNoteRequest) Reset() {
+	*x = LockNoteRequest{}
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LockNoteRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LockNoteRequest) ProtoMessage() {}
+
+func (x *LockNoteRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LockNoteRequest.ProtoReflect.Descriptor instead.
+func (*LockNoteRequest) Descriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *LockNoteRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *LockNoteRequest) GetTtlSeconds() int32 {
+	if x != nil {
+		return x.TtlSeconds
+	}
+	return 0
+}
+
+func (x *LockNoteRequest) GetForce() bool {
+	if x != nil {
+		return x.Force
+	}
+	return false
+}
+
+// Ответ с действующей блокировкой
+type LockNoteResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Lock          *NoteLock              `protobuf:"bytes,1,opt,name=lock,proto3" json:"lock,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LockNoteResponse) Reset() {
+	*x = LockNoteResponse{}
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LockNoteResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LockNoteResponse) ProtoMessage() {}
+
+func (x *LockNoteResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LockNoteResponse.ProtoReflect.Descriptor instead.
+func (*LockNoteResponse) Descriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *LockNoteResponse) GetLock() *NoteLock {
+	if x != nil {
+		return x.Lock
+	}
+	return nil
+}
+
+// Запрос на снятие блокировки
+type UnlockNoteRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`        // UUID заметки
+	Force         bool                   `protobuf:"varint,2,opt,name=force,proto3" json:"force,omitempty"` // Снять блокировку другого пользователя (только для роли admin)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UnlockNoteRequest) Reset() {
+	*x = UnlockNoteRequest{}
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UnlockNoteRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UnlockNoteRequest) ProtoMessage() {}
+
+func (x *UnlockNoteRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UnlockNoteRequest.ProtoReflect.Descriptor instead.
+func (*UnlockNoteRequest) Descriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *UnlockNoteRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *UnlockNoteRequest) GetForce() bool {
+	if x != nil {
+		return x.Force
+	}
+	return false
+}
+
+// Ответ на снятие блокировки
+type UnlockNoteResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UnlockNoteResponse) Reset() {
+	*x = UnlockNoteResponse{}
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UnlockNoteResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UnlockNoteResponse) ProtoMessage() {}
+
+func (x *UnlockNoteResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UnlockNoteResponse.ProtoReflect.Descriptor instead.
+func (*UnlockNoteResponse) Descriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{25}
+}
+
+// Запрос на установку парольной фразы заметки
+type SetNotePassphraseRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`                 // UUID заметки
+	Passphrase    string                 `protobuf:"bytes,2,opt,name=passphrase,proto3" json:"passphrase,omitempty"` // Новая парольная фраза (не короче 8 символов), пустая снимает защиту
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetNotePassphraseRequest) Reset() {
+	*x = SetNotePassphraseRequest{}
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetNotePassphraseRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetNotePassphraseRequest) ProtoMessage() {}
+
+func (x *SetNotePassphraseRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetNotePassphraseRequest.ProtoReflect.Descriptor instead.
+func (*SetNotePassphraseRequest) Descriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *SetNotePassphraseRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *SetNotePassphraseRequest) GetPassphrase() string {
+	if x != nil {
+		return x.Passphrase
+	}
+	return ""
+}
+
+// Ответ с заметкой после смены защиты
+type SetNotePassphraseResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Note          *Note                  `protobuf:"bytes,1,opt,name=note,proto3" json:"note,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetNotePassphraseResponse) Reset() {
+	*x = SetNotePassphraseResponse{}
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetNotePassphraseResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetNotePassphraseResponse) ProtoMessage() {}
+
+func (x *SetNotePassphraseResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetNotePassphraseResponse.ProtoReflect.Descriptor instead.
+func (*SetNotePassphraseResponse) Descriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *SetNotePassphraseResponse) GetNote() *Note {
+	if x != nil {
+		return x.Note
+	}
+	return nil
+}
+
+// Блокировка заметки для монопольного редактирования
+type NoteLock struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	NoteId        string                 `protobuf:"bytes,1,opt,name=note_id,json=noteId,proto3" json:"note_id,omitempty"`             // UUID заметки
+	HolderId      string                 `protobuf:"bytes,2,opt,name=holder_id,json=holderId,proto3" json:"holder_id,omitempty"`       // Пользователь, удерживающий блокировку
+	AcquiredAt    *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=acquired_at,json=acquiredAt,proto3" json:"acquired_at,omitempty"` // Время захвата или последнего продления
+	ExpiresAt     *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`    // Время истечения аренды
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NoteLock) Reset() {
+	*x = NoteLock{}
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NoteLock) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NoteLock) ProtoMessage() {}
+
+func (x *NoteLock) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NoteLock.ProtoReflect.Descriptor instead.
+func (*NoteLock) Descriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *NoteLock) GetNoteId() string {
+	if x != nil {
+		return x.NoteId
+	}
+	return ""
+}
+
+func (x *NoteLock) GetHolderId() string {
+	if x != nil {
+		return x.HolderId
+	}
+	return ""
+}
+
+func (x *NoteLock) GetAcquiredAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.AcquiredAt
+	}
+	return nil
+}
+
+func (x *NoteLock) GetExpiresAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return nil
+}
+
+// Запрос на пакетное создание заметок
+type BatchCreateNotesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Notes         []*CreateNoteRequest   `protobuf:"bytes,1,rep,name=notes,proto3" json:"notes,omitempty"`    // Заметки для создания (от 1 до 100)
+	Atomic        bool                   `protobuf:"varint,2,opt,name=atomic,proto3" json:"atomic,omitempty"` // Все или ничего: при ошибке в любой заметке не создается ни одна
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchCreateNotesRequest) Reset() {
+	*x = BatchCreateNotesRequest{}
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchCreateNotesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchCreateNotesRequest) ProtoMessage() {}
+
+func (x *BatchCreateNotesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchCreateNotesRequest.ProtoReflect.Descriptor instead.
+func (*BatchCreateNotesRequest) Descriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *BatchCreateNotesRequest) GetNotes() []*CreateNoteRequest {
+	if x != nil {
+		return x.Notes
+	}
+	return nil
+}
+
+func (x *BatchCreateNotesRequest) GetAtomic() bool {
+	if x != nil {
+		return x.Atomic
+	}
+	return false
+}
+
+// Ответ на пакетное создание заметок
+type BatchCreateNotesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Results       []*BatchNoteResult     `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"` // Результаты в порядке заметок из запроса
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchCreateNotesResponse) Reset() {
+	*x = BatchCreateNotesResponse{}
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchCreateNotesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchCreateNotesResponse) ProtoMessage() {}
+
+func (x *BatchCreateNotesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchCreateNotesResponse.ProtoReflect.Descriptor instead.
+func (*BatchCreateNotesResponse) Descriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *BatchCreateNotesResponse) GetResults() []*BatchNoteResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+// Запрос на пакетное получение заметок
+type BatchGetNotesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Ids           []string               `protobuf:"bytes,1,rep,name=ids,proto3" json:"ids,omitempty"` // UUID заметок (от 1 до 100)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchGetNotesRequest) Reset() {
+	*x = BatchGetNotesRequest{}
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchGetNotesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchGetNotesRequest) ProtoMessage() {}
+
+func (x *BatchGetNotesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchGetNotesRequest.ProtoReflect.Descriptor instead.
+func (*BatchGetNotesRequest) Descriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *BatchGetNotesRequest) GetIds() []string {
+	if x != nil {
+		return x.Ids
+	}
+	return nil
+}
+
+// Ответ на пакетное получение заметок
+type BatchGetNotesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Results       []*BatchNoteResult     `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`   // Результаты в порядке UUID из запроса
+	Warnings      []*Warning             `protobuf:"bytes,2,rep,name=warnings,proto3" json:"warnings,omitempty"` // Предупреждения (STALE_READ, если хранилище недоступно и данные прочитаны из снимка)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchGetNotesResponse) Reset() {
+	*x = BatchGetNotesResponse{}
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchGetNotesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchGetNotesResponse) ProtoMessage() {}
+
+func (x *BatchGetNotesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchGetNotesResponse.ProtoReflect.Descriptor instead.
+func (*BatchGetNotesResponse) Descriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *BatchGetNotesResponse) GetResults() []*BatchNoteResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+func (x *BatchGetNotesResponse) GetWarnings() []*Warning {
+	if x != nil {
+		return x.Warnings
+	}
+	return nil
+}
+
+// Запрос на пакетное удаление заметок
+type BatchDeleteNotesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Ids           []string               `protobuf:"bytes,1,rep,name=ids,proto3" json:"ids,omitempty"`        // UUID заметок (от 1 до 100)
+	Atomic        bool                   `protobuf:"varint,2,opt,name=atomic,proto3" json:"atomic,omitempty"` // Все или ничего: если любая заметка не найдена, не удаляется ни одна
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchDeleteNotesRequest) Reset() {
+	*x = BatchDeleteNotesRequest{}
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchDeleteNotesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchDeleteNotesRequest) ProtoMessage() {}
+
+func (x *BatchDeleteNotesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchDeleteNotesRequest.ProtoReflect.Descriptor instead.
+func (*BatchDeleteNotesRequest) Descriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *BatchDeleteNotesRequest) GetIds() []string {
+	if x != nil {
+		return x.Ids
+	}
+	return nil
+}
+
+func (x *BatchDeleteNotesRequest) GetAtomic() bool {
+	if x != nil {
+		return x.Atomic
+	}
+	return false
+}
+
+// Ответ на пакетное удаление заметок
+type BatchDeleteNotesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Results       []*BatchNoteResult     `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"` // Результаты в порядке UUID из запроса
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchDeleteNotesResponse) Reset() {
+	*x = BatchDeleteNotesResponse{}
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchDeleteNotesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchDeleteNotesResponse) ProtoMessage() {}
+
+func (x *BatchDeleteNotesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchDeleteNotesResponse.ProtoReflect.Descriptor instead.
+func (*BatchDeleteNotesResponse) Descriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *BatchDeleteNotesResponse) GetResults() []*BatchNoteResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+// Результат операции над одной заметкой в пакетном запросе
+type BatchNoteResult struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`         // UUID заметки (для созданных - назначенный сервером)
+	Note          *Note                  `protobuf:"bytes,2,opt,name=note,proto3" json:"note,omitempty"`     // Заметка (для create/get при успехе)
+	Status        *status.Status         `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"` // Статус операции (code = OK при успехе, иначе ошибка с ErrorDetails)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchNoteResult) Reset() {
+	*x = BatchNoteResult{}
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchNoteResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchNoteResult) ProtoMessage() {}
+
+func (x *BatchNoteResult) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchNoteResult.ProtoReflect.Descriptor instead.
+func (*BatchNoteResult) Descriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *BatchNoteResult) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *BatchNoteResult) GetNote() *Note {
+	if x != nil {
+		return x.Note
+	}
+	return nil
+}
+
+func (x *BatchNoteResult) GetStatus() *status.Status {
+	if x != nil {
+		return x.Status
+	}
+	return nil
+}
+
+// Запрос на получение истории изменений заметки
+type ListNoteRevisionsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"` // UUID заметки
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListNoteRevisionsRequest) Reset() {
+	*x = ListNoteRevisionsRequest{}
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListNoteRevisionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListNoteRevisionsRequest) ProtoMessage() {}
+
+func (x *ListNoteRevisionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListNoteRevisionsRequest.ProtoReflect.Descriptor instead.
+func (*ListNoteRevisionsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *ListNoteRevisionsRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+// Ответ со списком ревизий заметки (от старых к новым)
+type ListNoteRevisionsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Revisions     []*NoteRevision        `protobuf:"bytes,1,rep,name=revisions,proto3" json:"revisions,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListNoteRevisionsResponse) Reset() {
+	*x = ListNoteRevisionsResponse{}
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListNoteRevisionsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListNoteRevisionsResponse) ProtoMessage() {}
+
+func (x *ListNoteRevisionsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListNoteRevisionsResponse.ProtoReflect.Descriptor instead.
+func (*ListNoteRevisionsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *ListNoteRevisionsResponse) GetRevisions() []*NoteRevision {
+	if x != nil {
+		return x.Revisions
+	}
+	return nil
+}
+
+// Запрос на получение конкретной ревизии заметки
+type GetNoteRevisionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`              // UUID заметки
+	Revision      int64                  `protobuf:"varint,2,opt,name=revision,proto3" json:"revision,omitempty"` // Номер ревизии (начиная с 1)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetNoteRevisionRequest) Reset() {
+	*x = GetNoteRevisionRequest{}
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[38]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetNoteRevisionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetNoteRevisionRequest) ProtoMessage() {}
+
+func (x *GetNoteRevisionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[38]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetNoteRevisionRequest.ProtoReflect.Descriptor instead.
+func (*GetNoteRevisionRequest) Descriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *GetNoteRevisionRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *GetNoteRevisionRequest) GetRevision() int64 {
+	if x != nil {
+		return x.Revision
+	}
+	return 0
+}
+
+// Ответ с ревизией заметки
+type GetNoteRevisionResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Revision      *NoteRevision          `protobuf:"bytes,1,opt,name=revision,proto3" json:"revision,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetNoteRevisionResponse) Reset() {
+	*x = GetNoteRevisionResponse{}
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetNoteRevisionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetNoteRevisionResponse) ProtoMessage() {}
+
+func (x *GetNoteRevisionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[39]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetNoteRevisionResponse.ProtoReflect.Descriptor instead.
+func (*GetNoteRevisionResponse) Descriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *GetNoteRevisionResponse) GetRevision() *NoteRevision {
+	if x != nil {
+		return x.Revision
+	}
+	return nil
+}
+
+// Запрос на сравнение двух ревизий заметки
+type DiffNoteRevisionsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`                                                // UUID заметки
+	FromRevision  int64                  `protobuf:"varint,2,opt,name=from_revision,json=fromRevision,proto3" json:"from_revision,omitempty"`       // Исходная ревизия
+	ToRevision    int64                  `protobuf:"varint,3,opt,name=to_revision,json=toRevision,proto3" json:"to_revision,omitempty"`             // Ревизия, с которой сравнивается исходная (может быть и раньше нее)
+	ContextLines  *int32                 `protobuf:"varint,4,opt,name=context_lines,json=contextLines,proto3,oneof" json:"context_lines,omitempty"` // Строк контекста вокруг изменений (по умолчанию 3)
+	Format        DiffFormat             `protobuf:"varint,5,opt,name=format,proto3,enum=notes.v1.DiffFormat" json:"format,omitempty"`              // Формат ответа (по умолчанию блоки hunks)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DiffNoteRevisionsRequest) Reset() {
+	*x = DiffNoteRevisionsRequest{}
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[40]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DiffNoteRevisionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DiffNoteRevisionsRequest) ProtoMessage() {}
+
+func (x *DiffNoteRevisionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[40]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DiffNoteRevisionsRequest.ProtoReflect.Descriptor instead.
+func (*DiffNoteRevisionsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *DiffNoteRevisionsRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *DiffNoteRevisionsRequest) GetFromRevision() int64 {
+	if x != nil {
+		return x.FromRevision
+	}
+	return 0
+}
+
+func (x *DiffNoteRevisionsRequest) GetToRevision() int64 {
+	if x != nil {
+		return x.ToRevision
+	}
+	return 0
+}
+
+func (x *DiffNoteRevisionsRequest) GetContextLines() int32 {
+	if x != nil && x.ContextLines != nil {
+		return *x.ContextLines
+	}
+	return 0
+}
+
+func (x *DiffNoteRevisionsRequest) GetFormat() DiffFormat {
+	if x != nil {
+		return x.Format
+	}
+	return DiffFormat_DIFF_FORMAT_UNSPECIFIED
+}
+
+// Ответ с различиями двух ревизий заметки
+type DiffNoteRevisionsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	NoteId        string                 `protobuf:"bytes,1,opt,name=note_id,json=noteId,proto3" json:"note_id,omitempty"`                    // UUID заметки
+	FromRevision  int64                  `protobuf:"varint,2,opt,name=from_revision,json=fromRevision,proto3" json:"from_revision,omitempty"` // Исходная ревизия
+	ToRevision    int64                  `protobuf:"varint,3,opt,name=to_revision,json=toRevision,proto3" json:"to_revision,omitempty"`       // Ревизия, с которой сравнивается исходная
+	FromTitle     string                 `protobuf:"bytes,4,opt,name=from_title,json=fromTitle,proto3" json:"from_title,omitempty"`           // Заголовок исходной ревизии
+	ToTitle       string                 `protobuf:"bytes,5,opt,name=to_title,json=toTitle,proto3" json:"to_title,omitempty"`                 // Заголовок второй ревизии
+	Hunks         []*DiffHunk            `protobuf:"bytes,6,rep,name=hunks,proto3" json:"hunks,omitempty"`                                    // Блоки изменений (для DIFF_FORMAT_HUNKS; пусто, если содержимое не менялось)
+	UnifiedDiff   string                 `protobuf:"bytes,7,opt,name=unified_diff,json=unifiedDiff,proto3" json:"unified_diff,omitempty"`     // Дифф в формате diff -u (для DIFF_FORMAT_UNIFIED)
+	LinesAdded    int32                  `protobuf:"varint,8,opt,name=lines_added,json=linesAdded,proto3" json:"lines_added,omitempty"`       // Количество добавленных строк
+	LinesRemoved  int32                  `protobuf:"varint,9,opt,name=lines_removed,json=linesRemoved,proto3" json:"lines_removed,omitempty"` // Количество удаленных строк
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DiffNoteRevisionsResponse) Reset() {
+	*x = DiffNoteRevisionsResponse{}
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[41]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DiffNoteRevisionsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DiffNoteRevisionsResponse) ProtoMessage() {}
+
+func (x *DiffNoteRevisionsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[41]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DiffNoteRevisionsResponse.ProtoReflect.Descriptor instead.
+func (*DiffNoteRevisionsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *DiffNoteRevisionsResponse) GetNoteId() string {
+	if x != nil {
+		return x.NoteId
+	}
+	return ""
+}
+
+func (x *DiffNoteRevisionsResponse) GetFromRevision() int64 {
+	if x != nil {
+		return x.FromRevision
+	}
+	return 0
+}
+
+func (x *DiffNoteRevisionsResponse) GetToRevision() int64 {
+	if x != nil {
+		return x.ToRevision
+	}
+	return 0
+}
+
+func (x *DiffNoteRevisionsResponse) GetFromTitle() string {
+	if x != nil {
+		return x.FromTitle
+	}
+	return ""
+}
+
+func (x *DiffNoteRevisionsResponse) GetToTitle() string {
+	if x != nil {
+		return x.ToTitle
+	}
+	return ""
+}
+
+func (x *DiffNoteRevisionsResponse) GetHunks() []*DiffHunk {
+	if x != nil {
+		return x.Hunks
+	}
+	return nil
+}
+
+func (x *DiffNoteRevisionsResponse) GetUnifiedDiff() string {
+	if x != nil {
+		return x.UnifiedDiff
+	}
+	return ""
+}
+
+func (x *DiffNoteRevisionsResponse) GetLinesAdded() int32 {
+	if x != nil {
+		return x.LinesAdded
+	}
+	return 0
+}
+
+func (x *DiffNoteRevisionsResponse) GetLinesRemoved() int32 {
+	if x != nil {
+		return x.LinesRemoved
+	}
+	return 0
+}
+
+// DiffHunk блок изменений с окружающими строками контекста
+// Для пустого диапазона start - номер строки перед ним, как в diff -u
+type DiffHunk struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	FromStart     int32                  `protobuf:"varint,1,opt,name=from_start,json=fromStart,proto3" json:"from_start,omitempty"` // Первая строка блока в исходной ревизии (с 1)
+	FromLines     int32                  `protobuf:"varint,2,opt,name=from_lines,json=fromLines,proto3" json:"from_lines,omitempty"` // Количество строк блока в исходной ревизии
+	ToStart       int32                  `protobuf:"varint,3,opt,name=to_start,json=toStart,proto3" json:"to_start,omitempty"`       // Первая строка блока во второй ревизии (с 1)
+	ToLines       int32                  `protobuf:"varint,4,opt,name=to_lines,json=toLines,proto3" json:"to_lines,omitempty"`       // Количество строк блока во второй ревизии
+	Lines         []*DiffLine            `protobuf:"bytes,5,rep,name=lines,proto3" json:"lines,omitempty"`                           // Строки блока по порядку
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DiffHunk) Reset() {
+	*x = DiffHunk{}
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[42]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DiffHunk) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DiffHunk) ProtoMessage() {}
+
+func (x *DiffHunk) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[42]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DiffHunk.ProtoReflect.Descriptor instead.
+func (*DiffHunk) Descriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{42}
+}
+
+func (x *DiffHunk) GetFromStart() int32 {
+	if x != nil {
+		return x.FromStart
+	}
+	return 0
+}
+
+func (x *DiffHunk) GetFromLines() int32 {
+	if x != nil {
+		return x.FromLines
+	}
+	return 0
+}
+
+func (x *DiffHunk) GetToStart() int32 {
+	if x != nil {
+		return x.ToStart
+	}
+	return 0
+}
+
+func (x *DiffHunk) GetToLines() int32 {
+	if x != nil {
+		return x.ToLines
+	}
+	return 0
+}
+
+func (x *DiffHunk) GetLines() []*DiffLine {
+	if x != nil {
+		return x.Lines
+	}
+	return nil
+}
+
+// DiffLine строка блока изменений
+type DiffLine struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Kind          DiffLineKind           `protobuf:"varint,1,opt,name=kind,proto3,enum=notes.v1.DiffLineKind" json:"kind,omitempty"` // Вид строки
+	Text          string                 `protobuf:"bytes,2,opt,name=text,proto3" json:"text,omitempty"`                             // Текст строки без перевода строки
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DiffLine) Reset() {
+	*x = DiffLine{}
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[43]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DiffLine) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DiffLine) ProtoMessage() {}
+
+func (x *DiffLine) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[43]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DiffLine.ProtoReflect.Descriptor instead.
+func (*DiffLine) Descriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{43}
+}
+
+func (x *DiffLine) GetKind() DiffLineKind {
+	if x != nil {
+		return x.Kind
+	}
+	return DiffLineKind_DIFF_LINE_KIND_UNSPECIFIED
+}
+
+func (x *DiffLine) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+// NoteRevision представляет сохраненное состояние заметки после создания или обновления
+type NoteRevision struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	NoteId           string                 `protobuf:"bytes,1,opt,name=note_id,json=noteId,proto3" json:"note_id,omitempty"`                               // UUID заметки
+	Revision         int64                  `protobuf:"varint,2,opt,name=revision,proto3" json:"revision,omitempty"`                                        // Номер ревизии (начиная с 1)
+	Title            string                 `protobuf:"bytes,3,opt,name=title,proto3" json:"title,omitempty"`                                               // Заголовок на момент ревизии
+	Content          string                 `protobuf:"bytes,4,opt,name=content,proto3" json:"content,omitempty"`                                           // Содержание на момент ревизии
+	CreatedAt        *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`                      // Время создания ревизии
+	ContentEncrypted []byte                 `protobuf:"bytes,6,opt,name=content_encrypted,json=contentEncrypted,proto3" json:"content_encrypted,omitempty"` // Зашифрованное содержимое на момент ревизии (для e2e заметок)
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *NoteRevision) Reset() {
+	*x = NoteRevision{}
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[44]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NoteRevision) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NoteRevision) ProtoMessage() {}
+
+func (x *NoteRevision) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[44]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NoteRevision.ProtoReflect.Descriptor instead.
+func (*NoteRevision) Descriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{44}
+}
+
+func (x *NoteRevision) GetNoteId() string {
+	if x != nil {
+		return x.NoteId
+	}
+	return ""
+}
+
+func (x *NoteRevision) GetRevision() int64 {
+	if x != nil {
+		return x.Revision
+	}
+	return 0
+}
+
+func (x *NoteRevision) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *NoteRevision) GetContent() string {
+	if x != nil {
+		return x.Content
+	}
+	return ""
+}
+
+func (x *NoteRevision) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *NoteRevision) GetContentEncrypted() []byte {
+	if x != nil {
+		return x.ContentEncrypted
+	}
+	return nil
+}
+
+// Запрос на получение заметок по тегу
+type ListNotesByTagRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Tag           string                 `protobuf:"bytes,1,opt,name=tag,proto3" json:"tag,omitempty"` // Тег (регистр не учитывается)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListNotesByTagRequest) Reset() {
+	*x = ListNotesByTagRequest{}
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[45]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListNotesByTagRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListNotesByTagRequest) ProtoMessage() {}
+
+func (x *ListNotesByTagRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[45]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListNotesByTagRequest.ProtoReflect.Descriptor instead.
+func (*ListNotesByTagRequest) Descriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{45}
+}
+
+func (x *ListNotesByTagRequest) GetTag() string {
+	if x != nil {
+		return x.Tag
+	}
+	return ""
+}
+
+// Ответ со списком заметок с тегом
+type ListNotesByTagResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Notes         []*Note                `protobuf:"bytes,1,rep,name=notes,proto3" json:"notes,omitempty"`
+	Warnings      []*Warning             `protobuf:"bytes,2,rep,name=warnings,proto3" json:"warnings,omitempty"` // Предупреждения (STALE_READ, если хранилище недоступно и данные прочитаны из снимка)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListNotesByTagResponse) Reset() {
+	*x = ListNotesByTagResponse{}
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[46]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListNotesByTagResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListNotesByTagResponse) ProtoMessage() {}
+
+func (x *ListNotesByTagResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[46]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListNotesByTagResponse.ProtoReflect.Descriptor instead.
+func (*ListNotesByTagResponse) Descriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{46}
+}
+
+func (x *ListNotesByTagResponse) GetNotes() []*Note {
+	if x != nil {
+		return x.Notes
+	}
+	return nil
+}
+
+func (x *ListNotesByTagResponse) GetWarnings() []*Warning {
+	if x != nil {
+		return x.Warnings
+	}
+	return nil
+}
+
+// Запрос на получение списка тегов
+type ListTagsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTagsRequest) Reset() {
+	*x = ListTagsRequest{}
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[47]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTagsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTagsRequest) ProtoMessage() {}
+
+func (x *ListTagsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[47]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTagsRequest.ProtoReflect.Descriptor instead.
+func (*ListTagsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{47}
+}
+
+// Ответ со списком тегов
+type ListTagsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Tags          []*TagCount            `protobuf:"bytes,1,rep,name=tags,proto3" json:"tags,omitempty"`         // Теги по алфавиту
+	Warnings      []*Warning             `protobuf:"bytes,2,rep,name=warnings,proto3" json:"warnings,omitempty"` // Предупреждения (STALE_READ, если хранилище недоступно и данные прочитаны из снимка)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTagsResponse) Reset() {
+	*x = ListTagsResponse{}
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[48]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTagsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTagsResponse) ProtoMessage() {}
+
+func (x *ListTagsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[48]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTagsResponse.ProtoReflect.Descriptor instead.
+func (*ListTagsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{48}
+}
+
+func (x *ListTagsResponse) GetTags() []*TagCount {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *ListTagsResponse) GetWarnings() []*Warning {
+	if x != nil {
+		return x.Warnings
+	}
+	return nil
+}
+
+// Запрос подсказок заголовков заметок
+type SuggestNotesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Prefix        string                 `protobuf:"bytes,1,opt,name=prefix,proto3" json:"prefix,omitempty"` // Начало заголовка или слова заголовка (без учета регистра)
+	Limit         int32                  `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`  // Количество подсказок (0 - по умолчанию, 10)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SuggestNotesRequest) Reset() {
+	*x = SuggestNotesRequest{}
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[49]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SuggestNotesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SuggestNotesRequest) ProtoMessage() {}
+
+func (x *SuggestNotesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[49]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SuggestNotesRequest.ProtoReflect.Descriptor instead.
+func (*SuggestNotesRequest) Descriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{49}
+}
+
+func (x *SuggestNotesRequest) GetPrefix() string {
+	if x != nil {
+		return x.Prefix
+	}
+	return ""
+}
+
+func (x *SuggestNotesRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+// Ответ с подсказками заголовков заметок
+type SuggestNotesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Suggestions   []*NoteSuggestion      `protobuf:"bytes,1,rep,name=suggestions,proto3" json:"suggestions,omitempty"` // Подсказки от наиболее подходящей
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SuggestNotesResponse) Reset() {
+	*x = SuggestNotesResponse{}
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[50]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SuggestNotesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SuggestNotesResponse) ProtoMessage() {}
+
+func (x *SuggestNotesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[50]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SuggestNotesResponse.ProtoReflect.Descriptor instead.
+func (*SuggestNotesResponse) Descriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{50}
+}
+
+func (x *SuggestNotesResponse) GetSuggestions() []*NoteSuggestion {
+	if x != nil {
+		return x.Suggestions
+	}
+	return nil
+}
+
+// Подсказка заголовка заметки
+type NoteSuggestion struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`                                // UUID заметки
+	Title         string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`                          // Заголовок заметки
+	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"` // Время последнего изменения
+	Views         int64                  `protobuf:"varint,4,opt,name=views,proto3" json:"views,omitempty"`                         // Количество открытий заметки (GetNote) с запуска сервера
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NoteSuggestion) Reset() {
+	*x = NoteSuggestion{}
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[51]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NoteSuggestion) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NoteSuggestion) ProtoMessage() {}
+
+func (x *NoteSuggestion) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[51]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NoteSuggestion.ProtoReflect.Descriptor instead.
+func (*NoteSuggestion) Descriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{51}
+}
+
+func (x *NoteSuggestion) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *NoteSuggestion) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *NoteSuggestion) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+func (x *NoteSuggestion) GetViews() int64 {
+	if x != nil {
+		return x.Views
+	}
+	return 0
+}
+
+// Запрос статистики заметки
+type GetNoteStatsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"` // UUID заметки
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetNoteStatsRequest) Reset() {
+	*x = GetNoteStatsRequest{}
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[52]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetNoteStatsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetNoteStatsRequest) ProtoMessage() {}
+
+func (x *GetNoteStatsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[52]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetNoteStatsRequest.ProtoReflect.Descriptor instead.
+func (*GetNoteStatsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{52}
+}
+
+func (x *GetNoteStatsRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+// Ответ со статистикой заметки
+type GetNoteStatsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Stats         *NoteStats             `protobuf:"bytes,1,opt,name=stats,proto3" json:"stats,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetNoteStatsResponse) Reset() {
+	*x = GetNoteStatsResponse{}
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[53]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetNoteStatsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetNoteStatsResponse) ProtoMessage() {}
+
+func (x *GetNoteStatsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[53]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetNoteStatsResponse.ProtoReflect.Descriptor instead.
+func (*GetNoteStatsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{53}
+}
+
+func (x *GetNoteStatsResponse) GetStats() *NoteStats {
+	if x != nil {
+		return x.Stats
+	}
+	return nil
+}
+
+// Статистика содержимого заметки
+// У e2e заметок содержимое недоступно серверу, поэтому счетчики равны нулю
+type NoteStats struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	NoteId             string                 `protobuf:"bytes,1,opt,name=note_id,json=noteId,proto3" json:"note_id,omitempty"`
+	WordCount          int64                  `protobuf:"varint,2,opt,name=word_count,json=wordCount,proto3" json:"word_count,omitempty"`                              // Количество слов в содержании
+	CharacterCount     int64                  `protobuf:"varint,3,opt,name=character_count,json=characterCount,proto3" json:"character_count,omitempty"`               // Количество символов в содержании
+	ReadingTimeSeconds int64                  `protobuf:"varint,4,opt,name=reading_time_seconds,json=readingTimeSeconds,proto3" json:"reading_time_seconds,omitempty"` // Время чтения при 200 словах в минуту
+	UpdatedAt          *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`                               // Время последней правки
+	LastEdit           *NoteEditDelta         `protobuf:"bytes,6,opt,name=last_edit,json=lastEdit,proto3" json:"last_edit,omitempty"`                                  // Изменение последней правки (нет у заметки без правок)
+	IsE2E              bool                   `protobuf:"varint,7,opt,name=is_e2e,json=isE2e,proto3" json:"is_e2e,omitempty"`                                          // Содержимое зашифровано на клиенте, счетчики не вычисляются
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *NoteStats) Reset() {
+	*x = NoteStats{}
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[54]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NoteStats) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NoteStats) ProtoMessage() {}
+
+func (x *NoteStats) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[54]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NoteStats.ProtoReflect.Descriptor instead.
+func (*NoteStats) Descriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{54}
+}
+
+func (x *NoteStats) GetNoteId() string {
+	if x != nil {
+		return x.NoteId
+	}
+	return ""
+}
+
+func (x *NoteStats) GetWordCount() int64 {
+	if x != nil {
+		return x.WordCount
+	}
+	return 0
+}
+
+func (x *NoteStats) GetCharacterCount() int64 {
+	if x != nil {
+		return x.CharacterCount
+	}
+	return 0
+}
+
+func (x *NoteStats) GetReadingTimeSeconds() int64 {
+	if x != nil {
+		return x.ReadingTimeSeconds
+	}
+	return 0
+}
+
+func (x *NoteStats) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+func (x *NoteStats) GetLastEdit() *NoteEditDelta {
+	if x != nil {
+		return x.LastEdit
+	}
+	return nil
+}
+
+func (x *NoteStats) GetIsE2E() bool {
+	if x != nil {
+		return x.IsE2E
+	}
+	return false
+}
+
+// Изменение содержания последней правкой относительно предыдущей ревизии
+type NoteEditDelta struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Revision        int64                  `protobuf:"varint,1,opt,name=revision,proto3" json:"revision,omitempty"`                                      // Номер ревизии последней правки
+	WordsDelta      int64                  `protobuf:"varint,2,opt,name=words_delta,json=wordsDelta,proto3" json:"words_delta,omitempty"`                // Изменение количества слов (отрицательное - слова удалены)
+	CharactersDelta int64                  `protobuf:"varint,3,opt,name=characters_delta,json=charactersDelta,proto3" json:"characters_delta,omitempty"` // Изменение количества символов
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *NoteEditDelta) Reset() {
+	*x = NoteEditDelta{}
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[55]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NoteEditDelta) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NoteEditDelta) ProtoMessage() {}
+
+func (x *NoteEditDelta) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[55]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NoteEditDelta.ProtoReflect.Descriptor instead.
+func (*NoteEditDelta) Descriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{55}
+}
+
+func (x *NoteEditDelta) GetRevision() int64 {
+	if x != nil {
+		return x.Revision
+	}
+	return 0
+}
+
+func (x *NoteEditDelta) GetWordsDelta() int64 {
+	if x != nil {
+		return x.WordsDelta
+	}
+	return 0
+}
+
+func (x *NoteEditDelta) GetCharactersDelta() int64 {
+	if x != nil {
+		return x.CharactersDelta
+	}
+	return 0
+}
+
+// Запрос сводной статистики пользователя
+type GetAccountStatsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetAccountStatsRequest) Reset() {
+	*x = GetAccountStatsRequest{}
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[56]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetAccountStatsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetAccountStatsRequest) ProtoMessage() {}
+
+func (x *GetAccountStatsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[56]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetAccountStatsRequest.ProtoReflect.Descriptor instead.
+func (*GetAccountStatsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{56}
+}
+
+// Ответ со сводной статистикой пользователя
+type GetAccountStatsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Stats         *AccountStats          `protobuf:"bytes,1,opt,name=stats,proto3" json:"stats,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetAccountStatsResponse) Reset() {
+	*x = GetAccountStatsResponse{}
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[57]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetAccountStatsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetAccountStatsResponse) ProtoMessage() {}
+
+func (x *GetAccountStatsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[57]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetAccountStatsResponse.ProtoReflect.Descriptor instead.
+func (*GetAccountStatsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{57}
+}
+
+func (x *GetAccountStatsResponse) GetStats() *AccountStats {
+	if x != nil {
+		return x.Stats
+	}
+	return nil
+}
+
+// Сводная статистика заметок пользователя (только собственные заметки)
+type AccountStats struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	TotalNotes      int64                  `protobuf:"varint,1,opt,name=total_notes,json=totalNotes,proto3" json:"total_notes,omitempty"`                // Количество заметок
+	TotalWords      int64                  `protobuf:"varint,2,opt,name=total_words,json=totalWords,proto3" json:"total_words,omitempty"`                // Количество слов во всех заметках (без e2e заметок)
+	TotalCharacters int64                  `protobuf:"varint,3,opt,name=total_characters,json=totalCharacters,proto3" json:"total_characters,omitempty"` // Количество символов во всех заметках (без e2e заметок)
+	NotesPerTag     []*TagCount            `protobuf:"bytes,4,rep,name=notes_per_tag,json=notesPerTag,proto3" json:"notes_per_tag,omitempty"`            // Количество заметок по тегам
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *AccountStats) Reset() {
+	*x = AccountStats{}
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[58]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AccountStats) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AccountStats) ProtoMessage() {}
+
+func (x *AccountStats) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[58]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AccountStats.ProtoReflect.Descriptor instead.
+func (*AccountStats) Descriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{58}
+}
+
+func (x *AccountStats) GetTotalNotes() int64 {
+	if x != nil {
+		return x.TotalNotes
+	}
+	return 0
+}
+
+func (x *AccountStats) GetTotalWords() int64 {
+	if x != nil {
+		return x.TotalWords
+	}
+	return 0
+}
+
+func (x *AccountStats) GetTotalCharacters() int64 {
+	if x != nil {
+		return x.TotalCharacters
+	}
+	return 0
+}
+
+func (x *AccountStats) GetNotesPerTag() []*TagCount {
+	if x != nil {
+		return x.NotesPerTag
+	}
+	return nil
+}
+
+// Доступ пользователя к заметке
+type Share struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	NoteId        string                 `protobuf:"bytes,1,opt,name=note_id,json=noteId,proto3" json:"note_id,omitempty"`                          // UUID заметки
+	OwnerId       string                 `protobuf:"bytes,2,opt,name=owner_id,json=ownerId,proto3" json:"owner_id,omitempty"`                       // Владелец заметки
+	UserId        string                 `protobuf:"bytes,3,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`                          // Пользователь, получивший доступ
+	Permission    SharePermission        `protobuf:"varint,4,opt,name=permission,proto3,enum=notes.v1.SharePermission" json:"permission,omitempty"` // Уровень доступа
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`                 // Время предоставления доступа
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Share) Reset() {
+	*x = Share{}
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[59]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Share) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Share) ProtoMessage() {}
+
+func (x *Share) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[59]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Share.ProtoReflect.Descriptor instead.
+func (*Share) Descriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{59}
+}
+
+func (x *Share) GetNoteId() string {
+	if x != nil {
+		return x.NoteId
+	}
+	return ""
+}
+
+func (x *Share) GetOwnerId() string {
+	if x != nil {
+		return x.OwnerId
+	}
+	return ""
+}
+
+func (x *Share) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *Share) GetPermission() SharePermission {
+	if x != nil {
+		return x.Permission
+	}
+	return SharePermission_SHARE_PERMISSION_UNSPECIFIED
+}
+
+func (x *Share) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+// Запрос на предоставление доступа к заметке
+type ShareNoteRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	NoteId        string                 `protobuf:"bytes,1,opt,name=note_id,json=noteId,proto3" json:"note_id,omitempty"`                          // UUID заметки
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`                          // Пользователь, которому предоставляется доступ
+	Permission    SharePermission        `protobuf:"varint,3,opt,name=permission,proto3,enum=notes.v1.SharePermission" json:"permission,omitempty"` // Уровень доступа
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ShareNoteRequest) Reset() {
+	*x = ShareNoteRequest{}
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[60]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ShareNoteRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ShareNoteRequest) ProtoMessage() {}
+
+func (x *ShareNoteRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[60]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ShareNoteRequest.ProtoReflect.Descriptor instead.
+func (*ShareNoteRequest) Descriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{60}
+}
+
+func (x *ShareNoteRequest) GetNoteId() string {
+	if x != nil {
+		return x.NoteId
+	}
+	return ""
+}
+
+func (x *ShareNoteRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *ShareNoteRequest) GetPermission() SharePermission {
+	if x != nil {
+		return x.Permission
+	}
+	return SharePermission_SHARE_PERMISSION_UNSPECIFIED
+}
+
+// Ответ с предоставленным доступом
+type ShareNoteResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Share         *Share                 `protobuf:"bytes,1,opt,name=share,proto3" json:"share,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ShareNoteResponse) Reset() {
+	*x = ShareNoteResponse{}
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[61]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ShareNoteResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ShareNoteResponse) ProtoMessage() {}
+
+func (x *ShareNoteResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[61]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ShareNoteResponse.ProtoReflect.Descriptor instead.
+func (*ShareNoteResponse) Descriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{61}
+}
+
+func (x *ShareNoteResponse) GetShare() *Share {
+	if x != nil {
+		return x.Share
+	}
+	return nil
+}
+
+// Запрос на отзыв доступа к заметке
+type UnshareNoteRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	NoteId        string                 `protobuf:"bytes,1,opt,name=note_id,json=noteId,proto3" json:"note_id,omitempty"` // UUID заметки
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"` // Пользователь, у которого отзывается доступ
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UnshareNoteRequest) Reset() {
+	*x = UnshareNoteRequest{}
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[62]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UnshareNoteRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UnshareNoteRequest) ProtoMessage() {}
+
+func (x *UnshareNoteRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[62]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UnshareNoteRequest.ProtoReflect.Descriptor instead.
+func (*UnshareNoteRequest) Descriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{62}
+}
+
+func (x *UnshareNoteRequest) GetNoteId() string {
+	if x != nil {
+		return x.NoteId
+	}
+	return ""
+}
+
+func (x *UnshareNoteRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+// Ответ на отзыв доступа
+type UnshareNoteResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UnshareNoteResponse) Reset() {
+	*x = UnshareNoteResponse{}
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[63]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UnshareNoteResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UnshareNoteResponse) ProtoMessage() {}
+
+func (x *UnshareNoteResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[63]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UnshareNoteResponse.ProtoReflect.Descriptor instead.
+func (*UnshareNoteResponse) Descriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{63}
+}
+
+// Запрос на получение доступных заметок других пользователей
+type ListSharedNotesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListSharedNotesRequest) Reset() {
+	*x = ListSharedNotesRequest{}
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[64]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSharedNotesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSharedNotesRequest) ProtoMessage() {}
+
+func (x *ListSharedNotesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[64]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSharedNotesRequest.ProtoReflect.Descriptor instead.
+func (*ListSharedNotesRequest) Descriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{64}
+}
+
+// Заметка другого пользователя с уровнем доступа к ней
+type SharedNote struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Note          *Note                  `protobuf:"bytes,1,opt,name=note,proto3" json:"note,omitempty"`                                            // Заметка
+	Permission    SharePermission        `protobuf:"varint,2,opt,name=permission,proto3,enum=notes.v1.SharePermission" json:"permission,omitempty"` // Уровень доступа вызывающего пользователя
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SharedNote) Reset() {
+	*x = SharedNote{}
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[65]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SharedNote) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SharedNote) ProtoMessage() {}
+
+func (x *SharedNote) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[65]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SharedNote.ProtoReflect.Descriptor instead.
+func (*SharedNote) Descriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{65}
+}
+
+func (x *SharedNote) GetNote() *Note {
+	if x != nil {
+		return x.Note
+	}
+	return nil
+}
+
+func (x *SharedNote) GetPermission() SharePermission {
+	if x != nil {
+		return x.Permission
+	}
+	return SharePermission_SHARE_PERMISSION_UNSPECIFIED
+}
+
+// Ответ с доступными заметками других пользователей
+type ListSharedNotesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Notes         []*SharedNote          `protobuf:"bytes,1,rep,name=notes,proto3" json:"notes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListSharedNotesResponse) Reset() {
+	*x = ListSharedNotesResponse{}
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[66]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSharedNotesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSharedNotesResponse) ProtoMessage() {}
+
+func (x *ListSharedNotesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[66]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSharedNotesResponse.ProtoReflect.Descriptor instead.
+func (*ListSharedNotesResponse) Descriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{66}
+}
+
+func (x *ListSharedNotesResponse) GetNotes() []*SharedNote {
+	if x != nil {
+		return x.Notes
+	}
+	return nil
+}
+
+// Запрос на выгрузку заметок
+type ExportNotesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Format        ExportFormat           `protobuf:"varint,1,opt,name=format,proto3,enum=notes.v1.ExportFormat" json:"format,omitempty"` // Формат выгрузки
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExportNotesRequest) Reset() {
+	*x = ExportNotesRequest{}
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[67]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportNotesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportNotesRequest) ProtoMessage() {}
+
+func (x *ExportNotesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[67]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportNotesRequest.ProtoReflect.Descriptor instead.
+func (*ExportNotesRequest) Descriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{67}
+}
+
+func (x *ExportNotesRequest) GetFormat() ExportFormat {
+	if x != nil {
+		return x.Format
+	}
+	return ExportFormat_EXPORT_FORMAT_UNSPECIFIED
+}
+
+// Часть файла выгрузки
+type ExportNotesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Data          []byte                 `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"` // Часть содержимого файла
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExportNotesResponse) Reset() {
+	*x = ExportNotesResponse{}
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[68]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportNotesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportNotesResponse) ProtoMessage() {}
+
+func (x *ExportNotesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[68]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportNotesResponse.ProtoReflect.Descriptor instead.
+func (*ExportNotesResponse) Descriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{68}
+}
+
+func (x *ExportNotesResponse) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+// Запрос на выгрузку заметок в хранилище
+type ExportToDestinationRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Archive       ExportArchive          `protobuf:"varint,1,opt,name=archive,proto3,enum=notes.v1.ExportArchive" json:"archive,omitempty"` // Формат файла выгрузки
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExportToDestinationRequest) Reset() {
+	*x = ExportToDestinationRequest{}
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[69]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportToDestinationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportToDestinationRequest) ProtoMessage() {}
+
+func (x *ExportToDestinationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[69]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportToDestinationRequest.ProtoReflect.Descriptor instead.
+func (*ExportToDestinationRequest) Descriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{69}
+}
+
+func (x *ExportToDestinationRequest) GetArchive() ExportArchive {
+	if x != nil {
+		return x.Archive
+	}
+	return ExportArchive_EXPORT_ARCHIVE_UNSPECIFIED
+}
+
+// Запрос состояния операции выгрузки
+type GetExportOperationRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"` // ID операции из ExportToDestination
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetExportOperationRequest) Reset() {
+	*x = GetExportOperationRequest{}
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[70]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetExportOperationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetExportOperationRequest) ProtoMessage() {}
+
+func (x *GetExportOperationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[70]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetExportOperationRequest.ProtoReflect.Descriptor instead.
+func (*GetExportOperationRequest) Descriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{70}
+}
+
+func (x *GetExportOperationRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+// Длительная операция выгрузки заметок в хранилище
+type ExportOperation struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`                                             // ID операции
+	State         ExportOperationState   `protobuf:"varint,2,opt,name=state,proto3,enum=notes.v1.ExportOperationState" json:"state,omitempty"`   // Состояние
+	Archive       ExportArchive          `protobuf:"varint,3,opt,name=archive,proto3,enum=notes.v1.ExportArchive" json:"archive,omitempty"`      // Формат файла выгрузки
+	ExportedNotes int64                  `protobuf:"varint,4,opt,name=exported_notes,json=exportedNotes,proto3" json:"exported_notes,omitempty"` // Количество выгруженных заметок
+	TotalNotes    int64                  `protobuf:"varint,5,opt,name=total_notes,json=totalNotes,proto3" json:"total_notes,omitempty"`          // Количество заметок на момент запуска (для оценки прогресса)
+	Location      string                 `protobuf:"bytes,6,opt,name=location,proto3" json:"location,omitempty"`                                 // Адрес файла выгрузки (s3://bucket/key или путь к файлу), после успешного завершения
+	Error         *status.Status         `protobuf:"bytes,7,opt,name=error,proto3" json:"error,omitempty"`                                       // Ошибка выгрузки (для state = FAILED)
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`              // Время запуска
+	CompletedAt   *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=completed_at,json=completedAt,proto3" json:"completed_at,omitempty"`        // Время завершения
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExportOperation) Reset() {
+	*x = ExportOperation{}
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[71]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportOperation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportOperation) ProtoMessage() {}
+
+func (x *ExportOperation) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[71]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportOperation.ProtoReflect.Descriptor instead.
+func (*ExportOperation) Descriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{71}
+}
+
+func (x *ExportOperation) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *ExportOperation) GetState() ExportOperationState {
+	if x != nil {
+		return x.State
+	}
+	return ExportOperationState_EXPORT_OPERATION_STATE_UNSPECIFIED
+}
+
+func (x *ExportOperation) GetArchive() ExportArchive {
+	if x != nil {
+		return x.Archive
+	}
+	return ExportArchive_EXPORT_ARCHIVE_UNSPECIFIED
+}
+
+func (x *ExportOperation) GetExportedNotes() int64 {
+	if x != nil {
+		return x.ExportedNotes
+	}
+	return 0
+}
+
+func (x *ExportOperation) GetTotalNotes() int64 {
+	if x != nil {
+		return x.TotalNotes
+	}
+	return 0
+}
+
+func (x *ExportOperation) GetLocation() string {
+	if x != nil {
+		return x.Location
+	}
+	return ""
+}
+
+func (x *ExportOperation) GetError() *status.Status {
+	if x != nil {
+		return x.Error
+	}
+	return nil
+}
+
+func (x *ExportOperation) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *ExportOperation) GetCompletedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CompletedAt
+	}
+	return nil
+}
+
+// Запрос смены ключей шифрования
+type RotateKeysRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OwnerId       string                 `protobuf:"bytes,1,opt,name=owner_id,json=ownerId,proto3" json:"owner_id,omitempty"` // Владелец, ключ которого сменяется (пусто - все владельцы)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RotateKeysRequest) Reset() {
+	*x = RotateKeysRequest{}
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[72]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RotateKeysRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RotateKeysRequest) ProtoMessage() {}
+
+func (x *RotateKeysRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[72]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RotateKeysRequest.ProtoReflect.Descriptor instead.
+func (*RotateKeysRequest) Descriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{72}
+}
+
+func (x *RotateKeysRequest) GetOwnerId() string {
+	if x != nil {
+		return x.OwnerId
+	}
+	return ""
+}
+
+// Запрос состояния операции смены ключей
+type GetKeyRotationOperationRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"` // ID операции из RotateKeys
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetKeyRotationOperationRequest) Reset() {
+	*x = GetKeyRotationOperationRequest{}
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[73]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetKeyRotationOperationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetKeyRotationOperationRequest) ProtoMessage() {}
+
+func (x *GetKeyRotationOperationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[73]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetKeyRotationOperationRequest.ProtoReflect.Descriptor instead.
+func (*GetKeyRotationOperationRequest) Descriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{73}
+}
+
+func (x *GetKeyRotationOperationRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+// Длительная операция смены ключей шифрования заметок
+type KeyRotationOperation struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Id               string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`                                                      // ID операции
+	State            KeyRotationState       `protobuf:"varint,2,opt,name=state,proto3,enum=notes.v1.KeyRotationState" json:"state,omitempty"`                // Состояние
+	OwnerId          string                 `protobuf:"bytes,3,opt,name=owner_id,json=ownerId,proto3" json:"owner_id,omitempty"`                             // Владелец, ключ которого сменяется (пусто - все владельцы)
+	StartedBy        string                 `protobuf:"bytes,4,opt,name=started_by,json=startedBy,proto3" json:"started_by,omitempty"`                       // Администратор, запустивший операцию
+	RotatedKeys      int64                  `protobuf:"varint,5,opt,name=rotated_keys,json=rotatedKeys,proto3" json:"rotated_keys,omitempty"`                // Количество созданных ключей данных
+	RewrappedKeys    int64                  `protobuf:"varint,6,opt,name=rewrapped_keys,json=rewrappedKeys,proto3" json:"rewrapped_keys,omitempty"`          // Количество ключей данных, перешифрованных текущим мастер-ключом
+	TotalNotes       int64                  `protobuf:"varint,7,opt,name=total_notes,json=totalNotes,proto3" json:"total_notes,omitempty"`                   // Количество проверяемых заметок (для оценки прогресса)
+	ProcessedNotes   int64                  `protobuf:"varint,8,opt,name=processed_notes,json=processedNotes,proto3" json:"processed_notes,omitempty"`       // Количество проверенных заметок
+	ReencryptedNotes int64                  `protobuf:"varint,9,opt,name=reencrypted_notes,json=reencryptedNotes,proto3" json:"reencrypted_notes,omitempty"` // Количество перешифрованных заметок
+	Error            *status.Status         `protobuf:"bytes,10,opt,name=error,proto3" json:"error,omitempty"`                                               // Ошибка (для state = FAILED)
+	CreatedAt        *timestamppb.Timestamp `protobuf:"bytes,11,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`                      // Время запуска
+	CompletedAt      *timestamppb.Timestamp `protobuf:"bytes,12,opt,name=completed_at,json=completedAt,proto3" json:"completed_at,omitempty"`                // Время завершения
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *KeyRotationOperation) Reset() {
+	*x = KeyRotationOperation{}
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[74]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *KeyRotationOperation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*KeyRotationOperation) ProtoMessage() {}
+
+func (x *KeyRotationOperation) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[74]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use KeyRotationOperation.ProtoReflect.Descriptor instead.
+func (*KeyRotationOperation) Descriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{74}
+}
+
+func (x *KeyRotationOperation) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *KeyRotationOperation) GetState() KeyRotationState {
+	if x != nil {
+		return x.State
+	}
+	return KeyRotationState_KEY_ROTATION_STATE_UNSPECIFIED
+}
+
+func (x *KeyRotationOperation) GetOwnerId() string {
+	if x != nil {
+		return x.OwnerId
+	}
+	return ""
+}
+
+func (x *KeyRotationOperation) GetStartedBy() string {
+	if x != nil {
+		return x.StartedBy
+	}
+	return ""
+}
+
+func (x *KeyRotationOperation) GetRotatedKeys() int64 {
+	if x != nil {
+		return x.RotatedKeys
+	}
+	return 0
+}
+
+func (x *KeyRotationOperation) GetRewrappedKeys() int64 {
+	if x != nil {
+		return x.RewrappedKeys
+	}
+	return 0
+}
+
+func (x *KeyRotationOperation) GetTotalNotes() int64 {
+	if x != nil {
+		return x.TotalNotes
+	}
+	return 0
+}
+
+func (x *KeyRotationOperation) GetProcessedNotes() int64 {
+	if x != nil {
+		return x.ProcessedNotes
+	}
+	return 0
+}
+
+func (x *KeyRotationOperation) GetReencryptedNotes() int64 {
+	if x != nil {
+		return x.ReencryptedNotes
+	}
+	return 0
+}
+
+func (x *KeyRotationOperation) GetError() *status.Status {
+	if x != nil {
+		return x.Error
+	}
+	return nil
+}
+
+func (x *KeyRotationOperation) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *KeyRotationOperation) GetCompletedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CompletedAt
+	}
+	return nil
+}
+
+// Событие завершения выгрузки в хранилище (успешного или с ошибкой)
+type ExportCompletedEvent struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Operation     *ExportOperation       `protobuf:"bytes,1,opt,name=operation,proto3" json:"operation,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExportCompletedEvent) Reset() {
+	*x = ExportCompletedEvent{}
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[75]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportCompletedEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportCompletedEvent) ProtoMessage() {}
+
+func (x *ExportCompletedEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[75]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportCompletedEvent.ProtoReflect.Descriptor instead.
+func (*ExportCompletedEvent) Descriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{75}
+}
+
+func (x *ExportCompletedEvent) GetOperation() *ExportOperation {
+	if x != nil {
+		return x.Operation
+	}
+	return nil
+}
+
+// Часть загружаемого файла заметок
+type ImportNotesRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Payload:
+	//
+	//	*ImportNotesRequest_Format
+	//	*ImportNotesRequest_Data
+	Payload       isImportNotesRequest_Payload `protobuf_oneof:"payload"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ImportNotesRequest) Reset() {
+	*x = ImportNotesRequest{}
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[76]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImportNotesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportNotesRequest) ProtoMessage() {}
+
+func (x *ImportNotesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[76]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportNotesRequest.ProtoReflect.Descriptor instead.
+func (*ImportNotesRequest) Descriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{76}
+}
+
+func (x *ImportNotesRequest) GetPayload() isImportNotesRequest_Payload {
+	if x != nil {
+		return x.Payload
+	}
+	return nil
+}
+
+func (x *ImportNotesRequest) GetFormat() ExportFormat {
+	if x != nil {
+		if x, ok := x.Payload.(*ImportNotesRequest_Format); ok {
+			return x.Format
+		}
+	}
+	return ExportFormat_EXPORT_FORMAT_UNSPECIFIED
+}
+
+func (x *ImportNotesRequest) GetData() []byte {
+	if x != nil {
+		if x, ok := x.Payload.(*ImportNotesRequest_Data); ok {
+			return x.Data
+		}
+	}
+	return nil
+}
+
+type isImportNotesRequest_Payload interface {
+	isImportNotesRequest_Payload()
+}
+
+type ImportNotesRequest_Format struct {
+	Format ExportFormat `protobuf:"varint,1,opt,name=format,proto3,enum=notes.v1.ExportFormat,oneof"` // Формат файла (только в первом сообщении)
+}
+
+type ImportNotesRequest_Data struct {
+	Data []byte `protobuf:"bytes,2,opt,name=data,proto3,oneof"` // Часть содержимого файла
+}
+
+func (*ImportNotesRequest_Format) isImportNotesRequest_Payload() {}
+
+func (*ImportNotesRequest_Data) isImportNotesRequest_Payload() {}
+
+// Результат загрузки заметок
+type ImportNotesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Imported      int64                  `protobuf:"varint,1,opt,name=imported,proto3" json:"imported,omitempty"` // Количество созданных заметок
+	Failed        int64                  `protobuf:"varint,2,opt,name=failed,proto3" json:"failed,omitempty"`     // Количество отклоненных заметок
+	Errors        []string               `protobuf:"bytes,3,rep,name=errors,proto3" json:"errors,omitempty"`      // Ошибки отклоненных заметок (не более 100)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ImportNotesResponse) Reset() {
+	*x = ImportNotesResponse{}
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[77]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImportNotesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportNotesResponse) ProtoMessage() {}
+
+func (x *ImportNotesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[77]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportNotesResponse.ProtoReflect.Descriptor instead.
+func (*ImportNotesResponse) Descriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{77}
+}
+
+func (x *ImportNotesResponse) GetImported() int64 {
+	if x != nil {
+		return x.Imported
+	}
+	return 0
+}
+
+func (x *ImportNotesResponse) GetFailed() int64 {
+	if x != nil {
+		return x.Failed
+	}
+	return 0
+}
+
+func (x *ImportNotesResponse) GetErrors() []string {
+	if x != nil {
+		return x.Errors
+	}
+	return nil
+}
+
+// Запрос на получение информации о сервере
+type GetServerInfoRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetServerInfoRequest) Reset() {
+	*x = GetServerInfoRequest{}
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[78]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetServerInfoRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetServerInfoRequest) ProtoMessage() {}
+
+func (x *GetServerInfoRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[78]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetServerInfoRequest.ProtoReflect.Descriptor instead.
+func (*GetServerInfoRequest) Descriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{78}
+}
+
+// Информация о возможностях сервера
+type GetServerInfoResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	E2ESchemes    []string               `protobuf:"bytes,1,rep,name=e2e_schemes,json=e2eSchemes,proto3" json:"e2e_schemes,omitempty"` // Поддерживаемые схемы сквозного шифрования (в порядке предпочтения)
+	Backup        *BackupStatus          `protobuf:"bytes,2,opt,name=backup,proto3" json:"backup,omitempty"`                           // Состояние резервного копирования (только для роли admin, если копирование настроено)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetServerInfoResponse) Reset() {
+	*x = GetServerInfoResponse{}
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[79]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetServerInfoResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetServerInfoResponse) ProtoMessage() {}
+
+func (x *GetServerInfoResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[79]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetServerInfoResponse.ProtoReflect.Descriptor instead.
+func (*GetServerInfoResponse) Descriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{79}
+}
+
+func (x *GetServerInfoResponse) GetE2ESchemes() []string {
+	if x != nil {
+		return x.E2ESchemes
+	}
+	return nil
+}
+
+func (x *GetServerInfoResponse) GetBackup() *BackupStatus {
+	if x != nil {
+		return x.Backup
+	}
+	return nil
+}
+
+// Состояние резервного копирования хранилища
+type BackupStatus struct {
+	state               protoimpl.MessageState `protogen:"open.v1"`
+	LastBackup          string                 `protobuf:"bytes,1,opt,name=last_backup,json=lastBackup,proto3" json:"last_backup,omitempty"`                                 // Имя последней успешной копии (пусто, если копий еще не было)
+	LastBackupLocation  string                 `protobuf:"bytes,2,opt,name=last_backup_location,json=lastBackupLocation,proto3" json:"last_backup_location,omitempty"`       // Адрес архива последней копии
+	LastBackupTime      *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=last_backup_time,json=lastBackupTime,proto3" json:"last_backup_time,omitempty"`                   // Время последней успешной копии
+	LastBackupSizeBytes int64                  `protobuf:"varint,4,opt,name=last_backup_size_bytes,json=lastBackupSizeBytes,proto3" json:"last_backup_size_bytes,omitempty"` // Размер архива последней копии
+	LastBackupNotes     int64                  `protobuf:"varint,5,opt,name=last_backup_notes,json=lastBackupNotes,proto3" json:"last_backup_notes,omitempty"`               // Количество заметок в последней копии
+	LastAttemptTime     *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=last_attempt_time,json=lastAttemptTime,proto3" json:"last_attempt_time,omitempty"`                // Время последнего запуска копирования
+	LastError           *status.Status         `protobuf:"bytes,7,opt,name=last_error,json=lastError,proto3" json:"last_error,omitempty"`                                    // Ошибка последнего копирования (пусто, если оно успешно)
+	NextBackupTime      *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=next_backup_time,json=nextBackupTime,proto3" json:"next_backup_time,omitempty"`                   // Время следующего копирования по расписанию
+	Retained            int32                  `protobuf:"varint,9,opt,name=retained,proto3" json:"retained,omitempty"`                                                      // Количество хранимых копий
+	Succeeded           int64                  `protobuf:"varint,10,opt,name=succeeded,proto3" json:"succeeded,omitempty"`                                                   // Успешных копирований с запуска сервера
+	Failed              int64                  `protobuf:"varint,11,opt,name=failed,proto3" json:"failed,omitempty"`                                                         // Неудачных копирований с запуска сервера
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *BackupStatus) Reset() {
+	*x = BackupStatus{}
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[80]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BackupStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BackupStatus) ProtoMessage() {}
+
+func (x *BackupStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[80]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BackupStatus.ProtoReflect.Descriptor instead.
+func (*BackupStatus) Descriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{80}
+}
+
+func (x *BackupStatus) GetLastBackup() string {
+	if x != nil {
+		return x.LastBackup
+	}
+	return ""
+}
+
+func (x *BackupStatus) GetLastBackupLocation() string {
+	if x != nil {
+		return x.LastBackupLocation
+	}
+	return ""
+}
+
+func (x *BackupStatus) GetLastBackupTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastBackupTime
+	}
+	return nil
+}
+
+func (x *BackupStatus) GetLastBackupSizeBytes() int64 {
+	if x != nil {
+		return x.LastBackupSizeBytes
+	}
+	return 0
+}
+
+func (x *BackupStatus) GetLastBackupNotes() int64 {
+	if x != nil {
+		return x.LastBackupNotes
+	}
+	return 0
+}
+
+func (x *BackupStatus) GetLastAttemptTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastAttemptTime
+	}
+	return nil
+}
+
+func (x *BackupStatus) GetLastError() *status.Status {
+	if x != nil {
+		return x.LastError
+	}
+	return nil
+}
+
+func (x *BackupStatus) GetNextBackupTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.NextBackupTime
+	}
+	return nil
+}
+
+func (x *BackupStatus) GetRetained() int32 {
+	if x != nil {
+		return x.Retained
+	}
+	return 0
+}
+
+func (x *BackupStatus) GetSucceeded() int64 {
+	if x != nil {
+		return x.Succeeded
+	}
+	return 0
+}
+
+func (x *BackupStatus) GetFailed() int64 {
+	if x != nil {
+		return x.Failed
+	}
+	return 0
+}
+
+// Запрос восстановления из резервной копии
+type RestoreBackupRequest struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Backup           string                 `protobuf:"bytes,1,opt,name=backup,proto3" json:"backup,omitempty"`                                                                                   // Имя копии backup-<время>.zip (пусто - последняя копия)
+	ConflictStrategy BackupConflictStrategy `protobuf:"varint,2,opt,name=conflict_strategy,json=conflictStrategy,proto3,enum=notes.v1.BackupConflictStrategy" json:"conflict_strategy,omitempty"` // Поведение для заметок, которые уже есть в хранилище
+	DryRun           bool                   `protobuf:"varint,3,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"`                                                                    // Только посчитать изменения, не изменяя хранилище
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *RestoreBackupRequest) Reset() {
+	*x = RestoreBackupRequest{}
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[81]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RestoreBackupRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RestoreBackupRequest) ProtoMessage() {}
+
+func (x *RestoreBackupRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[81]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RestoreBackupRequest.ProtoReflect.Descriptor instead.
+func (*RestoreBackupRequest) Descriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{81}
+}
+
+func (x *RestoreBackupRequest) GetBackup() string {
+	if x != nil {
+		return x.Backup
+	}
+	return ""
+}
+
+func (x *RestoreBackupRequest) GetConflictStrategy() BackupConflictStrategy {
+	if x != nil {
+		return x.ConflictStrategy
+	}
+	return BackupConflictStrategy_BACKUP_CONFLICT_STRATEGY_UNSPECIFIED
+}
+
+func (x *RestoreBackupRequest) GetDryRun() bool {
+	if x != nil {
+		return x.DryRun
+	}
+	return false
+}
+
+// Результат восстановления (для dry_run - изменения, которые были бы внесены)
+type RestoreBackupResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Backup        string                 `protobuf:"bytes,1,opt,name=backup,proto3" json:"backup,omitempty"`                // Имя восстановленной копии
+	DryRun        bool                   `protobuf:"varint,2,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"` // Хранилище не изменялось
+	Notes         int64                  `protobuf:"varint,3,opt,name=notes,proto3" json:"notes,omitempty"`                 // Количество заметок в копии
+	Created       int64                  `protobuf:"varint,4,opt,name=created,proto3" json:"created,omitempty"`             // Восстановлено отсутствовавших заметок
+	Overwritten   int64                  `protobuf:"varint,5,opt,name=overwritten,proto3" json:"overwritten,omitempty"`     // Заменено существующих заметок
+	Skipped       int64                  `protobuf:"varint,6,opt,name=skipped,proto3" json:"skipped,omitempty"`             // Пропущено существующих заметок
+	Revisions     int64                  `protobuf:"varint,7,opt,name=revisions,proto3" json:"revisions,omitempty"`         // Восстановлено ревизий
+	Shares        int64                  `protobuf:"varint,8,opt,name=shares,proto3" json:"shares,omitempty"`               // Восстановлено доступов
+	Conflicts     []string               `protobuf:"bytes,9,rep,name=conflicts,proto3" json:"conflicts,omitempty"`          // ID заметок из копии, которые уже есть в хранилище (не более 100)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RestoreBackupResponse) Reset() {
+	*x = RestoreBackupResponse{}
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[82]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RestoreBackupResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RestoreBackupResponse) ProtoMessage() {}
+
+func (x *RestoreBackupResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[82]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RestoreBackupResponse.ProtoReflect.Descriptor instead.
+func (*RestoreBackupResponse) Descriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{82}
+}
+
+func (x *RestoreBackupResponse) GetBackup() string {
+	if x != nil {
+		return x.Backup
+	}
+	return ""
+}
+
+func (x *RestoreBackupResponse) GetDryRun() bool {
+	if x != nil {
+		return x.DryRun
+	}
+	return false
+}
+
+func (x *RestoreBackupResponse) GetNotes() int64 {
+	if x != nil {
+		return x.Notes
+	}
+	return 0
+}
+
+func (x *RestoreBackupResponse) GetCreated() int64 {
+	if x != nil {
+		return x.Created
+	}
+	return 0
+}
+
+func (x *RestoreBackupResponse) GetOverwritten() int64 {
+	if x != nil {
+		return x.Overwritten
+	}
+	return 0
+}
+
+func (x *RestoreBackupResponse) GetSkipped() int64 {
+	if x != nil {
+		return x.Skipped
+	}
+	return 0
+}
+
+func (x *RestoreBackupResponse) GetRevisions() int64 {
+	if x != nil {
+		return x.Revisions
+	}
+	return 0
+}
+
+func (x *RestoreBackupResponse) GetShares() int64 {
+	if x != nil {
+		return x.Shares
+	}
+	return 0
+}
+
+func (x *RestoreBackupResponse) GetConflicts() []string {
+	if x != nil {
+		return x.Conflicts
+	}
+	return nil
+}
+
+// Запрос статистики использования
+type GetUsageStatsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetUsageStatsRequest) Reset() {
+	*x = GetUsageStatsRequest{}
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[83]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetUsageStatsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetUsageStatsRequest) ProtoMessage() {}
+
+func (x *GetUsageStatsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[83]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetUsageStatsRequest.ProtoReflect.Descriptor instead.
+func (*GetUsageStatsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{83}
+}
+
+// Статистика использования реплики с момента запуска
+type GetUsageStatsResponse struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	InstallationId string                 `protobuf:"bytes,1,opt,name=installation_id,json=installationId,proto3" json:"installation_id,omitempty"` // Случайный ID реплики, созданный при запуске
+	Since          *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=since,proto3" json:"since,omitempty"`                                         // Начало сбора статистики
+	Methods        []*MethodUsage         `protobuf:"bytes,3,rep,name=methods,proto3" json:"methods,omitempty"`                                     // Вызовы методов, по имени метода
+	Features       []*FeatureUsage        `protobuf:"bytes,4,rep,name=features,proto3" json:"features,omitempty"`                                   // Использование функций, по имени функции
+	Reporting      *UsageReporting        `protobuf:"bytes,5,opt,name=reporting,proto3" json:"reporting,omitempty"`                                 // Отправка статистики на внешний адрес
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *GetUsageStatsResponse) Reset() {
+	*x = GetUsageStatsResponse{}
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[84]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetUsageStatsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetUsageStatsResponse) ProtoMessage() {}
+
+func (x *GetUsageStatsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[84]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetUsageStatsResponse.ProtoReflect.Descriptor instead.
+func (*GetUsageStatsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{84}
+}
+
+func (x *GetUsageStatsResponse) GetInstallationId() string {
+	if x != nil {
+		return x.InstallationId
+	}
+	return ""
+}
+
+func (x *GetUsageStatsResponse) GetSince() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Since
+	}
+	return nil
+}
+
+func (x *GetUsageStatsResponse) GetMethods() []*MethodUsage {
+	if x != nil {
+		return x.Methods
+	}
+	return nil
+}
+
+func (x *GetUsageStatsResponse) GetFeatures() []*FeatureUsage {
+	if x != nil {
+		return x.Features
+	}
+	return nil
+}
+
+func (x *GetUsageStatsResponse) GetReporting() *UsageReporting {
+	if x != nil {
+		return x.Reporting
+	}
+	return nil
+}
+
+// Количество вызовов метода
+type MethodUsage struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Method        string                 `protobuf:"bytes,1,opt,name=method,proto3" json:"method,omitempty"`  // Полное имя метода (/notes.v1.NotesService/CreateNote)
+	Calls         int64                  `protobuf:"varint,2,opt,name=calls,proto3" json:"calls,omitempty"`   // Количество вызовов
+	Errors        int64                  `protobuf:"varint,3,opt,name=errors,proto3" json:"errors,omitempty"` // Количество вызовов, завершившихся ошибкой
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MethodUsage) Reset() {
+	*x = MethodUsage{}
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[85]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MethodUsage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MethodUsage) ProtoMessage() {}
+
+func (x *MethodUsage) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[85]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MethodUsage.ProtoReflect.Descriptor instead.
+func (*MethodUsage) Descriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{85}
+}
+
+func (x *MethodUsage) GetMethod() string {
+	if x != nil {
+		return x.Method
+	}
+	return ""
+}
+
+func (x *MethodUsage) GetCalls() int64 {
+	if x != nil {
+		return x.Calls
+	}
+	return 0
+}
+
+func (x *MethodUsage) GetErrors() int64 {
+	if x != nil {
+		return x.Errors
+	}
+	return 0
+}
+
+// Количество использований функции (notes.e2e, update.field_mask, chat.typing и т.п.)
+type FeatureUsage struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Feature       string                 `protobuf:"bytes,1,opt,name=feature,proto3" json:"feature,omitempty"`
+	Count         int64                  `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FeatureUsage) Reset() {
+	*x = FeatureUsage{}
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[86]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FeatureUsage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FeatureUsage) ProtoMessage() {}
+
+func (x *FeatureUsage) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[86]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FeatureUsage.ProtoReflect.Descriptor instead.
+func (*FeatureUsage) Descriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{86}
+}
+
+func (x *FeatureUsage) GetFeature() string {
+	if x != nil {
+		return x.Feature
+	}
+	return ""
+}
+
+func (x *FeatureUsage) GetCount() int64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+// Состояние отправки статистики на внешний адрес
+type UsageReporting struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Enabled        bool                   `protobuf:"varint,1,opt,name=enabled,proto3" json:"enabled,omitempty"`                                      // Адрес задан в конфигурации
+	LastReportTime *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=last_report_time,json=lastReportTime,proto3" json:"last_report_time,omitempty"` // Время последней успешной отправки
+	LastError      *status.Status         `protobuf:"bytes,3,opt,name=last_error,json=lastError,proto3" json:"last_error,omitempty"`                  // Ошибка последней отправки
+	Reports        int64                  `protobuf:"varint,4,opt,name=reports,proto3" json:"reports,omitempty"`                                      // Количество успешных отправок
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *UsageReporting) Reset() {
+	*x = UsageReporting{}
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[87]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UsageReporting) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UsageReporting) ProtoMessage() {}
+
+func (x *UsageReporting) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[87]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UsageReporting.ProtoReflect.Descriptor instead.
+func (*UsageReporting) Descriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{87}
+}
+
+func (x *UsageReporting) GetEnabled() bool {
+	if x != nil {
+		return x.Enabled
+	}
+	return false
+}
+
+func (x *UsageReporting) GetLastReportTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastReportTime
+	}
+	return nil
+}
+
+func (x *UsageReporting) GetLastError() *status.Status {
+	if x != nil {
+		return x.LastError
+	}
+	return nil
+}
+
+func (x *UsageReporting) GetReports() int64 {
+	if x != nil {
+		return x.Reports
+	}
+	return 0
+}
+
+// Запрос на получение заметок всех пользователей
+type AdminListAllNotesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AdminListAllNotesRequest) Reset() {
+	*x = AdminListAllNotesRequest{}
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[88]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AdminListAllNotesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AdminListAllNotesRequest) ProtoMessage() {}
+
+func (x *AdminListAllNotesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[88]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AdminListAllNotesRequest.ProtoReflect.Descriptor instead.
+func (*AdminListAllNotesRequest) Descriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{88}
+}
+
+// Ответ с заметками всех пользователей
+type AdminListAllNotesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Notes         []*Note                `protobuf:"bytes,1,rep,name=notes,proto3" json:"notes,omitempty"` // Заметки всех пользователей
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AdminListAllNotesResponse) Reset() {
+	*x = AdminListAllNotesResponse{}
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[89]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AdminListAllNotesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AdminListAllNotesResponse) ProtoMessage() {}
+
+func (x *AdminListAllNotesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[89]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AdminListAllNotesResponse.ProtoReflect.Descriptor instead.
+func (*AdminListAllNotesResponse) Descriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{89}
+}
+
+func (x *AdminListAllNotesResponse) GetNotes() []*Note {
+	if x != nil {
+		return x.Notes
+	}
+	return nil
+}
+
+// TagCount количество заметок с тегом
+type TagCount struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Tag           string                 `protobuf:"bytes,1,opt,name=tag,proto3" json:"tag,omitempty"`      // Тег
+	Count         int64                  `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"` // Количество заметок с тегом
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TagCount) Reset() {
+	*x = TagCount{}
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[90]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TagCount) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TagCount) ProtoMessage() {}
+
+func (x *TagCount) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[90]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TagCount.ProtoReflect.Descriptor instead.
+func (*TagCount) Descriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{90}
+}
+
+func (x *TagCount) GetTag() string {
+	if x != nil {
+		return x.Tag
+	}
+	return ""
+}
+
+func (x *TagCount) GetCount() int64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+// Часть загружаемого вложения
+type AttachmentChunk struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Payload:
+	//
+	//	*AttachmentChunk_Metadata
+	//	*AttachmentChunk_Data
+	Payload       isAttachmentChunk_Payload `protobuf_oneof:"payload"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AttachmentChunk) Reset() {
+	*x = AttachmentChunk{}
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[91]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AttachmentChunk) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AttachmentChunk) ProtoMessage() {}
+
+func (x *AttachmentChunk) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[91]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AttachmentChunk.ProtoReflect.Descriptor instead.
+func (*AttachmentChunk) Descriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{91}
+}
+
+func (x *AttachmentChunk) GetPayload() isAttachmentChunk_Payload {
+	if x != nil {
+		return x.Payload
+	}
+	return nil
+}
+
+func (x *AttachmentChunk) GetMetadata() *AttachmentMetadata {
+	if x != nil {
+		if x, ok := x.Payload.(*AttachmentChunk_Metadata); ok {
+			return x.Metadata
+		}
+	}
+	return nil
+}
+
+func (x *AttachmentChunk) GetData() []byte {
+	if x != nil {
+		if x, ok := x.Payload.(*AttachmentChunk_Data); ok {
+			return x.Data
+		}
+	}
+	return nil
+}
+
+type isAttachmentChunk_Payload interface {
+	isAttachmentChunk_Payload()
+}
+
+type AttachmentChunk_Metadata struct {
+	Metadata *AttachmentMetadata `protobuf:"bytes,1,opt,name=metadata,proto3,oneof"` // Метаданные (только в первом сообщении)
+}
+
+type AttachmentChunk_Data struct {
+	Data []byte `protobuf:"bytes,2,opt,name=data,proto3,oneof"` // Часть содержимого файла (до 1 МБ)
+}
+
+func (*AttachmentChunk_Metadata) isAttachmentChunk_Payload() {}
+
+func (*AttachmentChunk_Data) isAttachmentChunk_Payload() {}
+
+// Метаданные загружаемого вложения
+type AttachmentMetadata struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	NoteId        string                 `protobuf:"bytes,1,opt,name=note_id,json=noteId,proto3" json:"note_id,omitempty"`                // UUID заметки
+	FileName      string                 `protobuf:"bytes,2,opt,name=file_name,json=fileName,proto3" json:"file_name,omitempty"`          // Имя файла
+	ContentType   string                 `protobuf:"bytes,3,opt,name=content_type,json=contentType,proto3" json:"content_type,omitempty"` // MIME тип (по умолчанию application/octet-stream)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AttachmentMetadata) Reset() {
+	*x = AttachmentMetadata{}
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[92]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AttachmentMetadata) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AttachmentMetadata) ProtoMessage() {}
+
+func (x *AttachmentMetadata) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[92]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AttachmentMetadata.ProtoReflect.Descriptor instead.
+func (*AttachmentMetadata) Descriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{92}
+}
+
+func (x *AttachmentMetadata) GetNoteId() string {
+	if x != nil {
+		return x.NoteId
+	}
+	return ""
+}
+
+func (x *AttachmentMetadata) GetFileName() string {
+	if x != nil {
+		return x.FileName
+	}
+	return ""
+}
+
+func (x *AttachmentMetadata) GetContentType() string {
+	if x != nil {
+		return x.ContentType
+	}
+	return ""
+}
+
+// Attachment представляет вложение заметки
+type Attachment struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`                                      // UUID вложения
+	NoteId        string                 `protobuf:"bytes,2,opt,name=note_id,json=noteId,proto3" json:"note_id,omitempty"`                // UUID заметки
+	FileName      string                 `protobuf:"bytes,3,opt,name=file_name,json=fileName,proto3" json:"file_name,omitempty"`          // Имя файла
+	ContentType   string                 `protobuf:"bytes,4,opt,name=content_type,json=contentType,proto3" json:"content_type,omitempty"` // MIME тип
+	SizeBytes     int64                  `protobuf:"varint,5,opt,name=size_bytes,json=sizeBytes,proto3" json:"size_bytes,omitempty"`      // Размер в байтах
+	Sha256        string                 `protobuf:"bytes,6,opt,name=sha256,proto3" json:"sha256,omitempty"`                              // Контрольная сумма содержимого (hex)
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`       // Дата загрузки
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Attachment) Reset() {
+	*x = Attachment{}
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[93]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Attachment) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Attachment) ProtoMessage() {}
+
+func (x *Attachment) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[93]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Attachment.ProtoReflect.Descriptor instead.
+func (*Attachment) Descriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{93}
+}
+
+func (x *Attachment) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Attachment) GetNoteId() string {
+	if x != nil {
+		return x.NoteId
+	}
+	return ""
+}
+
+func (x *Attachment) GetFileName() string {
+	if x != nil {
+		return x.FileName
+	}
+	return ""
+}
+
+func (x *Attachment) GetContentType() string {
+	if x != nil {
+		return x.ContentType
+	}
+	return ""
+}
+
+func (x *Attachment) GetSizeBytes() int64 {
+	if x != nil {
+		return x.SizeBytes
+	}
+	return 0
+}
+
+func (x *Attachment) GetSha256() string {
+	if x != nil {
+		return x.Sha256
+	}
+	return ""
+}
+
+func (x *Attachment) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+// Запрос на скачивание вложения
+type DownloadAttachmentRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	NoteId        string                 `protobuf:"bytes,1,opt,name=note_id,json=noteId,proto3" json:"note_id,omitempty"` // UUID заметки
+	Id            string                 `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`                       // UUID вложения
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DownloadAttachmentRequest) Reset() {
+	*x = DownloadAttachmentRequest{}
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[94]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DownloadAttachmentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DownloadAttachmentRequest) ProtoMessage() {}
+
+func (x *DownloadAttachmentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[94]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DownloadAttachmentRequest.ProtoReflect.Descriptor instead.
+func (*DownloadAttachmentRequest) Descriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{94}
+}
+
+func (x *DownloadAttachmentRequest) GetNoteId() string {
+	if x != nil {
+		return x.NoteId
+	}
+	return ""
+}
+
+func (x *DownloadAttachmentRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+// Часть скачиваемого вложения
+type DownloadAttachmentResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Payload:
+	//
+	//	*DownloadAttachmentResponse_Attachment
+	//	*DownloadAttachmentResponse_Data
+	Payload       isDownloadAttachmentResponse_Payload `protobuf_oneof:"payload"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DownloadAttachmentResponse) Reset() {
+	*x = DownloadAttachmentResponse{}
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[95]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DownloadAttachmentResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DownloadAttachmentResponse) ProtoMessage() {}
+
+func (x *DownloadAttachmentResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[95]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DownloadAttachmentResponse.ProtoReflect.Descriptor instead.
+func (*DownloadAttachmentResponse) Descriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{95}
+}
+
+func (x *DownloadAttachmentResponse) GetPayload() isDownloadAttachmentResponse_Payload {
+	if x != nil {
+		return x.Payload
+	}
+	return nil
+}
+
+func (x *DownloadAttachmentResponse) GetAttachment() *Attachment {
+	if x != nil {
+		if x, ok := x.Payload.(*DownloadAttachmentResponse_Attachment); ok {
+			return x.Attachment
+		}
+	}
+	return nil
+}
+
+func (x *DownloadAttachmentResponse) GetData() []byte {
+	if x != nil {
+		if x, ok := x.Payload.(*DownloadAttachmentResponse_Data); ok {
+			return x.Data
+		}
+	}
+	return nil
+}
+
+type isDownloadAttachmentResponse_Payload interface {
+	isDownloadAttachmentResponse_Payload()
+}
+
+type DownloadAttachmentResponse_Attachment struct {
+	Attachment *Attachment `protobuf:"bytes,1,opt,name=attachment,proto3,oneof"` // Метаданные (только в первом сообщении)
+}
+
+type DownloadAttachmentResponse_Data struct {
+	Data []byte `protobuf:"bytes,2,opt,name=data,proto3,oneof"` // Часть содержимого файла
+}
+
+func (*DownloadAttachmentResponse_Attachment) isDownloadAttachmentResponse_Payload() {}
+
+func (*DownloadAttachmentResponse_Data) isDownloadAttachmentResponse_Payload() {}
+
+// Note представляет заметку
+type Note struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Id               string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`                                                      // UUID заметки
+	Title            string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`                                                // Заголовок заметки
+	Content          string                 `protobuf:"bytes,3,opt,name=content,proto3" json:"content,omitempty"`                                            // Содержание заметки
+	CreatedAt        *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`                       // Дата создания
+	UpdatedAt        *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`                       // Дата последнего обновления
+	Version          int64                  `protobuf:"varint,6,opt,name=version,proto3" json:"version,omitempty"`                                           // Версия заметки (увеличивается при каждом обновлении)
+	Tags             []string               `protobuf:"bytes,7,rep,name=tags,proto3" json:"tags,omitempty"`                                                  // Теги заметки (в нижнем регистре, по алфавиту)
+	OwnerId          string                 `protobuf:"bytes,8,opt,name=owner_id,json=ownerId,proto3" json:"owner_id,omitempty"`                             // Идентификатор пользователя-владельца
+	IsE2E            bool                   `protobuf:"varint,9,opt,name=is_e2e,json=isE2e,proto3" json:"is_e2e,omitempty"`                                  // Заметка зашифрована на клиенте (content пуст)
+	E2EScheme        string                 `protobuf:"bytes,10,opt,name=e2e_scheme,json=e2eScheme,proto3" json:"e2e_scheme,omitempty"`                      // Схема сквозного шифрования
+	ContentEncrypted []byte                 `protobuf:"bytes,11,opt,name=content_encrypted,json=contentEncrypted,proto3" json:"content_encrypted,omitempty"` // Зашифрованное содержимое (непрозрачно для сервера)
+	Pinned           bool                   `protobuf:"varint,12,opt,name=pinned,proto3" json:"pinned,omitempty"`                                            // Заметка закреплена (выводится в начале ListNotes)
+	RemindAt         *timestamppb.Timestamp `protobuf:"bytes,13,opt,name=remind_at,json=remindAt,proto3" json:"remind_at,omitempty"`                         // Время напоминания (не задано, если напоминания нет)
+	EncryptionKeyId  string                 `protobuf:"bytes,14,opt,name=encryption_key_id,json=encryptionKeyId,proto3" json:"encryption_key_id,omitempty"`  // ID ключа, которым содержимое зашифровано в хранилище (пусто без шифрования)
+	// Вычисляемые поля: пересчитываются при изменении содержания; GetNote и ListNotes
+	// возвращают их, только если они перечислены в read_mask запроса
+	WordCount   int32                `protobuf:"varint,15,opt,name=word_count,json=wordCount,proto3" json:"word_count,omitempty"`      // Количество слов в содержании (0 у e2e заметок)
+	ReadingTime *durationpb.Duration `protobuf:"bytes,16,opt,name=reading_time,json=readingTime,proto3" json:"reading_time,omitempty"` // Оценка времени чтения содержания (200 слов в минуту)
+	// Заметка защищена парольной фразой (см. SetNotePassphrase); в списках содержимое таких заметок не возвращается
+	PassphraseProtected bool `protobuf:"varint,17,opt,name=passphrase_protected,json=passphraseProtected,proto3" json:"passphrase_protected,omitempty"`
+	Archived            bool `protobuf:"varint,18,opt,name=archived,proto3" json:"archived,omitempty"` // Заметка в архиве (не выводится в ListNotes без include_archived)
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *Note) Reset() {
+	*x = Note{}
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[96]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Note) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Note) ProtoMessage() {}
+
+func (x *Note) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[96]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Note.ProtoReflect.Descriptor instead.
+func (*Note) Descriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{96}
+}
+
+func (x *Note) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Note) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *Note) GetContent() string {
+	if x != nil {
+		return x.Content
+	}
+	return ""
+}
+
+func (x *Note) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Note) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+func (x *Note) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *Note) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *Note) GetOwnerId() string {
+	if x != nil {
+		return x.OwnerId
+	}
+	return ""
+}
+
+func (x *Note) GetIsE2E() bool {
+	if x != nil {
+		return x.IsE2E
+	}
+	return false
+}
+
+func (x *Note) GetE2EScheme() string {
+	if x != nil {
+		return x.E2EScheme
+	}
+	return ""
+}
+
+func (x *Note) GetContentEncrypted() []byte {
+	if x != nil {
+		return x.ContentEncrypted
+	}
+	return nil
+}
+
+func (x *Note) GetPinned() bool {
+	if x != nil {
+		return x.Pinned
+	}
+	return false
+}
+
+func (x *Note) GetRemindAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.RemindAt
+	}
+	return nil
+}
+
+func (x *Note) GetEncryptionKeyId() string {
+	if x != nil {
+		return x.EncryptionKeyId
+	}
+	return ""
+}
+
+func (x *Note) GetWordCount() int32 {
+	if x != nil {
+		return x.WordCount
+	}
+	return 0
+}
+
+func (x *Note) GetReadingTime() *durationpb.Duration {
+	if x != nil {
+		return x.ReadingTime
+	}
+	return nil
+}
+
+func (x *Note) GetPassphraseProtected() bool {
+	if x != nil {
+		return x.PassphraseProtected
+	}
+	return false
+}
+
+func (x *Note) GetArchived() bool {
+	if x != nil {
+		return x.Archived
+	}
+	return false
+}
+
+// ErrorDetails содержит детальную информацию об ошибке
+type ErrorDetails struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	Reason            string                 `protobuf:"bytes,1,opt,name=reason,proto3" json:"reason,omitempty"`                                                  // Причина ошибки
+	InternalErrorCode string                 `protobuf:"bytes,2,opt,name=internal_error_code,json=internalErrorCode,proto3" json:"internal_error_code,omitempty"` // Внутренний код ошибки
+	NoteId            string                 `protobuf:"bytes,3,opt,name=note_id,json=noteId,proto3" json:"note_id,omitempty"`                                    // ID заметки, связанной с ошибкой
+	RequestId         string                 `protobuf:"bytes,4,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`                           // Идентификатор запроса (метаданные x-request-id, заголовок X-Request-Id)
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *ErrorDetails) Reset() {
+	*x = ErrorDetails{}
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[97]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ErrorDetails) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ErrorDetails) ProtoMessage() {}
+
+func (x *ErrorDetails) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[97]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ErrorDetails.ProtoReflect.Descriptor instead.
+func (*ErrorDetails) Descriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{97}
+}
+
+func (x *ErrorDetails) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *ErrorDetails) GetInternalErrorCode() string {
+	if x != nil {
+		return x.InternalErrorCode
+	}
+	return ""
+}
+
+func (x *ErrorDetails) GetNoteId() string {
+	if x != nil {
+		return x.NoteId
+	}
+	return ""
+}
+
+func (x *ErrorDetails) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
+// Вебхук: адрес, на который отправляются события заметок пользователя
+type Webhook struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`                                                                   // ID вебхука (заголовок X-Webhook-Id)
+	Url           string                 `protobuf:"bytes,2,opt,name=url,proto3" json:"url,omitempty"`                                                                 // Адрес для POST запросов
+	EventTypes    []EventType            `protobuf:"varint,3,rep,packed,name=event_types,json=eventTypes,proto3,enum=notes.v1.EventType" json:"event_types,omitempty"` // Отправляемые события (пусто - все)
+	Secret        string                 `protobuf:"bytes,4,opt,name=secret,proto3" json:"secret,omitempty"`                                                           // Ключ HMAC подписи (только в ответе RegisterWebhook)
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`                                    // Время регистрации
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Webhook) Reset() {
+	*x = Webhook{}
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[98]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Webhook) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Webhook) ProtoMessage() {}
+
+func (x *Webhook) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[98]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Webhook.ProtoReflect.Descriptor instead.
+func (*Webhook) Descriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{98}
+}
+
+func (x *Webhook) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Webhook) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *Webhook) GetEventTypes() []EventType {
+	if x != nil {
+		return x.EventTypes
+	}
+	return nil
+}
+
+func (x *Webhook) GetSecret() string {
+	if x != nil {
+		return x.Secret
+	}
+	return ""
+}
+
+func (x *Webhook) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+// Запрос на регистрацию вебхука
+type RegisterWebhookRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Url    string                 `protobuf:"bytes,1,opt,name=url,proto3" json:"url,omitempty"`       // Адрес http или https
+	Secret string                 `protobuf:"bytes,2,opt,name=secret,proto3" json:"secret,omitempty"` // Ключ подписи (не короче 16 символов), пусто - сгенерировать
+	// Отправляемые события, пустой список - все события
+	EventTypes    []EventType `protobuf:"varint,3,rep,packed,name=event_types,json=eventTypes,proto3,enum=notes.v1.EventType" json:"event_types,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RegisterWebhookRequest) Reset() {
+	*x = RegisterWebhookRequest{}
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[99]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RegisterWebhookRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RegisterWebhookRequest) ProtoMessage() {}
+
+func (x *RegisterWebhookRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[99]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RegisterWebhookRequest.ProtoReflect.Descriptor instead.
+func (*RegisterWebhookRequest) Descriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{99}
+}
+
+func (x *RegisterWebhookRequest) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *RegisterWebhookRequest) GetSecret() string {
+	if x != nil {
+		return x.Secret
+	}
+	return ""
+}
+
+func (x *RegisterWebhookRequest) GetEventTypes() []EventType {
+	if x != nil {
+		return x.EventTypes
+	}
+	return nil
+}
+
+// Запрос списка вебхуков
+type ListWebhooksRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListWebhooksRequest) Reset() {
+	*x = ListWebhooksRequest{}
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[100]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListWebhooksRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListWebhooksRequest) ProtoMessage() {}
+
+func (x *ListWebhooksRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[100]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListWebhooksRequest.ProtoReflect.Descriptor instead.
+func (*ListWebhooksRequest) Descriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{100}
+}
+
+// Ответ со списком вебхуков
+type ListWebhooksResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Webhooks      []*Webhook             `protobuf:"bytes,1,rep,name=webhooks,proto3" json:"webhooks,omitempty"` // Вебхуки в порядке регистрации
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListWebhooksResponse) Reset() {
+	*x = ListWebhooksResponse{}
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[101]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListWebhooksResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListWebhooksResponse) ProtoMessage() {}
+
+func (x *ListWebhooksResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[101]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListWebhooksResponse.ProtoReflect.Descriptor instead.
+func (*ListWebhooksResponse) Descriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{101}
+}
+
+func (x *ListWebhooksResponse) GetWebhooks() []*Webhook {
+	if x != nil {
+		return x.Webhooks
+	}
+	return nil
+}
+
+// Запрос на удаление вебхука
+type DeleteWebhookRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"` // ID вебхука
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteWebhookRequest) Reset() {
+	*x = DeleteWebhookRequest{}
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[102]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteWebhookRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteWebhookRequest) ProtoMessage() {}
+
+func (x *DeleteWebhookRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[102]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteWebhookRequest.ProtoReflect.Descriptor instead.
+func (*DeleteWebhookRequest) Descriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{102}
+}
+
+func (x *DeleteWebhookRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+// Ответ на удаление вебхука
+type DeleteWebhookResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteWebhookResponse) Reset() {
+	*x = DeleteWebhookResponse{}
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[103]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteWebhookResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteWebhookResponse) ProtoMessage() {}
+
+func (x *DeleteWebhookResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[103]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteWebhookResponse.ProtoReflect.Descriptor instead.
+func (*DeleteWebhookResponse) Descriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{103}
+}
+
+// Запрос недоставленных событий
+type ListWebhookDeadLettersRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	WebhookId     string                 `protobuf:"bytes,1,opt,name=webhook_id,json=webhookId,proto3" json:"webhook_id,omitempty"` // Только события этого вебхука (пусто - всех вебхуков пользователя)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListWebhookDeadLettersRequest) Reset() {
+	*x = ListWebhookDeadLettersRequest{}
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[104]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListWebhookDeadLettersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListWebhookDeadLettersRequest) ProtoMessage() {}
+
+func (x *ListWebhookDeadLettersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[104]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListWebhookDeadLettersRequest.ProtoReflect.Descriptor instead.
+func (*ListWebhookDeadLettersRequest) Descriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{104}
+}
+
+func (x *ListWebhookDeadLettersRequest) GetWebhookId() string {
+	if x != nil {
+		return x.WebhookId
+	}
+	return ""
+}
+
+// Ответ с недоставленными событиями
+type ListWebhookDeadLettersResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DeadLetters   []*WebhookDeadLetter   `protobuf:"bytes,1,rep,name=dead_letters,json=deadLetters,proto3" json:"dead_letters,omitempty"` // События, начиная с последнего
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListWebhookDeadLettersResponse) Reset() {
+	*x = ListWebhookDeadLettersResponse{}
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[105]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListWebhookDeadLettersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListWebhookDeadLettersResponse) ProtoMessage() {}
+
+func (x *ListWebhookDeadLettersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[105]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListWebhookDeadLettersResponse.ProtoReflect.Descriptor instead.
+func (*ListWebhookDeadLettersResponse) Descriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{105}
+}
+
+func (x *ListWebhookDeadLettersResponse) GetDeadLetters() []*WebhookDeadLetter {
+	if x != nil {
+		return x.DeadLetters
+	}
+	return nil
+}
+
+// Сохраненный поиск (умная папка)
+type SavedSearch struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`                                // ID поиска
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`                            // Имя, уникальное у пользователя
+	Query         string                 `protobuf:"bytes,3,opt,name=query,proto3" json:"query,omitempty"`                          // Выражение фильтра
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"` // Время создания
+	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"` // Время последнего изменения выражения
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SavedSearch) Reset() {
+	*x = SavedSearch{}
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[106]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SavedSearch) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SavedSearch) ProtoMessage() {}
+
+func (x *SavedSearch) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[106]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SavedSearch.ProtoReflect.Descriptor instead.
+func (*SavedSearch) Descriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{106}
+}
+
+func (x *SavedSearch) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *SavedSearch) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *SavedSearch) GetQuery() string {
+	if x != nil {
+		return x.Query
+	}
+	return ""
+}
+
+func (x *SavedSearch) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *SavedSearch) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+// Запрос на сохранение поиска
+type SaveSearchRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Name  string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"` // Имя поиска
+	// Выражение: условия через пробел, все должны выполняться - слова и "фразы" (в заголовке или
+	// содержании), tag:work, words>100 / words<=500; минус в начале отрицает условие (-tag:archive)
+	Query         string `protobuf:"bytes,2,opt,name=query,proto3" json:"query,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SaveSearchRequest) Reset() {
+	*x = SaveSearchRequest{}
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[107]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SaveSearchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SaveSearchRequest) ProtoMessage() {}
+
+func (x *SaveSearchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[107]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SaveSearchRequest.ProtoReflect.Descriptor instead.
+func (*SaveSearchRequest) Descriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{107}
+}
+
+func (x *SaveSearchRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *SaveSearchRequest) GetQuery() string {
+	if x != nil {
+		return x.Query
+	}
+	return ""
+}
+
+// Запрос списка сохраненных поисков
+type ListSavedSearchesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListSavedSearchesRequest) Reset() {
+	*x = ListSavedSearchesRequest{}
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[108]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSavedSearchesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSavedSearchesRequest) ProtoMessage() {}
+
+func (x *ListSavedSearchesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[108]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSavedSearchesRequest.ProtoReflect.Descriptor instead.
+func (*ListSavedSearchesRequest) Descriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{108}
+}
+
+// Ответ со списком сохраненных поисков
+type ListSavedSearchesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SavedSearches []*SavedSearch         `protobuf:"bytes,1,rep,name=saved_searches,json=savedSearches,proto3" json:"saved_searches,omitempty"` // Поиски в порядке создания
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListSavedSearchesResponse) Reset() {
+	*x = ListSavedSearchesResponse{}
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[109]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSavedSearchesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSavedSearchesResponse) ProtoMessage() {}
+
+func (x *ListSavedSearchesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[109]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSavedSearchesResponse.ProtoReflect.Descriptor instead.
+func (*ListSavedSearchesResponse) Descriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{109}
+}
+
+func (x *ListSavedSearchesResponse) GetSavedSearches() []*SavedSearch {
+	if x != nil {
+		return x.SavedSearches
+	}
+	return nil
+}
+
+// Запрос на удаление сохраненного поиска
+type DeleteSavedSearchRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"` // ID поиска
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteSavedSearchRequest) Reset() {
+	*x = DeleteSavedSearchRequest{}
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[110]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteSavedSearchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteSavedSearchRequest) ProtoMessage() {}
+
+func (x *DeleteSavedSearchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[110]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteSavedSearchRequest.ProtoReflect.Descriptor instead.
+func (*DeleteSavedSearchRequest) Descriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{110}
+}
+
+func (x *DeleteSavedSearchRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+// Ответ на удаление сохраненного поиска
+type DeleteSavedSearchResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteSavedSearchResponse) Reset() {
+	*x = DeleteSavedSearchResponse{}
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[111]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteSavedSearchResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteSavedSearchResponse) ProtoMessage() {}
+
+func (x *DeleteSavedSearchResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[111]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteSavedSearchResponse.ProtoReflect.Descriptor instead.
+func (*DeleteSavedSearchResponse) Descriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{111}
+}
+
+// Запрос на выполнение сохраненного поиска
+type ExecuteSavedSearchRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"` // ID поиска
+	// Порядок заметок (как в ListNotesRequest)
+	TitleCollation string    `protobuf:"bytes,2,opt,name=title_collation,json=titleCollation,proto3" json:"title_collation,omitempty"`
+	OrderBy        NoteOrder `protobuf:"varint,3,opt,name=order_by,json=orderBy,proto3,enum=notes.v1.NoteOrder" json:"order_by,omitempty"`
+	// Возвращаемые поля заметок (как read_mask в GetNoteRequest)
+	ReadMask      *fieldmaskpb.FieldMask `protobuf:"bytes,4,opt,name=read_mask,json=readMask,proto3" json:"read_mask,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExecuteSavedSearchRequest) Reset() {
+	*x = ExecuteSavedSearchRequest{}
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[112]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExecuteSavedSearchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExecuteSavedSearchRequest) ProtoMessage() {}
+
+func (x *ExecuteSavedSearchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[112]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExecuteSavedSearchRequest.ProtoReflect.Descriptor instead.
+func (*ExecuteSavedSearchRequest) Descriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{112}
+}
+
+func (x *ExecuteSavedSearchRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *ExecuteSavedSearchRequest) GetTitleCollation() string {
+	if x != nil {
+		return x.TitleCollation
+	}
+	return ""
+}
+
+func (x *ExecuteSavedSearchRequest) GetOrderBy() NoteOrder {
+	if x != nil {
+		return x.OrderBy
+	}
+	return NoteOrder_NOTE_ORDER_UNSPECIFIED
+}
+
+func (x *ExecuteSavedSearchRequest) GetReadMask() *fieldmaskpb.FieldMask {
+	if x != nil {
+		return x.ReadMask
+	}
+	return nil
+}
+
+// Результат сохраненного поиска
+type ExecuteSavedSearchResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SavedSearch   *SavedSearch           `protobuf:"bytes,1,opt,name=saved_search,json=savedSearch,proto3" json:"saved_search,omitempty"` // Выполненный поиск
+	Notes         []*Note                `protobuf:"bytes,2,rep,name=notes,proto3" json:"notes,omitempty"`                                // Подходящие заметки
+	Warnings      []*Warning             `protobuf:"bytes,3,rep,name=warnings,proto3" json:"warnings,omitempty"`                          // Предупреждения (STALE_READ, как в ListNotesResponse)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExecuteSavedSearchResponse) Reset() {
+	*x = ExecuteSavedSearchResponse{}
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[113]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExecuteSavedSearchResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExecuteSavedSearchResponse) ProtoMessage() {}
+
+func (x *ExecuteSavedSearchResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[113]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExecuteSavedSearchResponse.ProtoReflect.Descriptor instead.
+func (*ExecuteSavedSearchResponse) Descriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{113}
+}
+
+func (x *ExecuteSavedSearchResponse) GetSavedSearch() *SavedSearch {
+	if x != nil {
+		return x.SavedSearch
+	}
+	return nil
+}
+
+func (x *ExecuteSavedSearchResponse) GetNotes() []*Note {
+	if x != nil {
+		return x.Notes
+	}
+	return nil
+}
+
+func (x *ExecuteSavedSearchResponse) GetWarnings() []*Warning {
+	if x != nil {
+		return x.Warnings
+	}
+	return nil
+}
+
+// Событие, которое не удалось доставить вебхуку
+type WebhookDeadLetter struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`                                                         // ID доставки (заголовок X-Webhook-Delivery)
+	WebhookId     string                 `protobuf:"bytes,2,opt,name=webhook_id,json=webhookId,proto3" json:"webhook_id,omitempty"`                          // ID вебхука (вебхук мог быть удален)
+	Url           string                 `protobuf:"bytes,3,opt,name=url,proto3" json:"url,omitempty"`                                                       // Адрес вебхука на момент доставки
+	EventType     EventType              `protobuf:"varint,4,opt,name=event_type,json=eventType,proto3,enum=notes.v1.EventType" json:"event_type,omitempty"` // Тип события
+	Payload       string                 `protobuf:"bytes,5,opt,name=payload,proto3" json:"payload,omitempty"`                                               // Тело запроса (JSON), можно отправить повторно
+	Attempts      int32                  `protobuf:"varint,6,opt,name=attempts,proto3" json:"attempts,omitempty"`                                            // Количество выполненных попыток
+	LastError     string                 `protobuf:"bytes,7,opt,name=last_error,json=lastError,proto3" json:"last_error,omitempty"`                          // Ошибка последней попытки
+	FailedAt      *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=failed_at,json=failedAt,proto3" json:"failed_at,omitempty"`                             // Время последней попытки
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WebhookDeadLetter) Reset() {
+	*x = WebhookDeadLetter{}
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[114]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WebhookDeadLetter) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WebhookDeadLetter) ProtoMessage() {}
+
+func (x *WebhookDeadLetter) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[114]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WebhookDeadLetter.ProtoReflect.Descriptor instead.
+func (*WebhookDeadLetter) Descriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{114}
+}
+
+func (x *WebhookDeadLetter) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *WebhookDeadLetter) GetWebhookId() string {
+	if x != nil {
+		return x.WebhookId
+	}
+	return ""
+}
+
+func (x *WebhookDeadLetter) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *WebhookDeadLetter) GetEventType() EventType {
+	if x != nil {
+		return x.EventType
+	}
+	return EventType_EVENT_TYPE_UNSPECIFIED
+}
+
+func (x *WebhookDeadLetter) GetPayload() string {
+	if x != nil {
+		return x.Payload
+	}
+	return ""
+}
+
+func (x *WebhookDeadLetter) GetAttempts() int32 {
+	if x != nil {
+		return x.Attempts
+	}
+	return 0
+}
+
+func (x *WebhookDeadLetter) GetLastError() string {
+	if x != nil {
+		return x.LastError
+	}
+	return ""
+}
+
+func (x *WebhookDeadLetter) GetFailedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.FailedAt
+	}
+	return nil
+}
+
+// Запрос на подписку на события
+type SubscribeToEventsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Типы событий, которые получит клиент; пустой список - все события
+	// Health-check сообщения отправляются независимо от фильтра
+	EventTypes []EventType `protobuf:"varint,1,rep,packed,name=event_types,json=eventTypes,proto3,enum=notes.v1.EventType" json:"event_types,omitempty"`
+	// Повторная доставка событий, пропущенных во время отключения (журнал последних событий сервера)
+	// Если журнал уже не содержит всех запрошенных событий, стрим завершается с OUT_OF_RANGE
+	//
+	// Types that are valid to be assigned to Since:
+	//
+	//	*SubscribeToEventsRequest_SinceEventId
+	//	*SubscribeToEventsRequest_SinceTimestamp
+	//	*SubscribeToEventsRequest_ResumeToken
+	Since isSubscribeToEventsRequest_Since `protobuf_oneof:"since"`
+	// Не отправлять периодические health-check сообщения (приветственное сообщение отправляется всегда)
+	DisableHeartbeats bool `protobuf:"varint,4,opt,name=disable_heartbeats,json=disableHeartbeats,proto3" json:"disable_heartbeats,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *SubscribeToEventsRequest) Reset() {
+	*x = SubscribeToEventsRequest{}
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[115]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubscribeToEventsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubscribeToEventsRequest) ProtoMessage() {}
+
+func (x *SubscribeToEventsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[115]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubscribeToEventsRequest.ProtoReflect.Descriptor instead.
+func (*SubscribeToEventsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{115}
+}
+
+func (x *SubscribeToEventsRequest) GetEventTypes() []EventType {
+	if x != nil {
+		return x.EventTypes
+	}
+	return nil
+}
+
+func (x *SubscribeToEventsRequest) GetSince() isSubscribeToEventsRequest_Since {
+	if x != nil {
+		return x.Since
+	}
+	return nil
+}
+
+func (x *SubscribeToEventsRequest) GetSinceEventId() uint64 {
+	if x != nil {
+		if x, ok := x.Since.(*SubscribeToEventsRequest_SinceEventId); ok {
+			return x.SinceEventId
+		}
+	}
+	return 0
+}
+
+func (x *SubscribeToEventsRequest) GetSinceTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		if x, ok := x.Since.(*SubscribeToEventsRequest_SinceTimestamp); ok {
+			return x.SinceTimestamp
+		}
+	}
+	return nil
+}
+
+func (x *SubscribeToEventsRequest) GetResumeToken() string {
+	if x != nil {
+		if x, ok := x.Since.(*SubscribeToEventsRequest_ResumeToken); ok {
+			return x.ResumeToken
+		}
+	}
+	return ""
+}
+
+func (x *SubscribeToEventsRequest) GetDisableHeartbeats() bool {
+	if x != nil {
+		return x.DisableHeartbeats
+	}
+	return false
+}
+
+type isSubscribeToEventsRequest_Since interface {
+	isSubscribeToEventsRequest_Since()
+}
+
+type SubscribeToEventsRequest_SinceEventId struct {
+	SinceEventId uint64 `protobuf:"varint,2,opt,name=since_event_id,json=sinceEventId,proto3,oneof"` // event_id последнего полученного события
+}
+
+type SubscribeToEventsRequest_SinceTimestamp struct {
+	SinceTimestamp *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=since_timestamp,json=sinceTimestamp,proto3,oneof"` // События, опубликованные после этого времени
+}
+
+type SubscribeToEventsRequest_ResumeToken struct {
+	ResumeToken string `protobuf:"bytes,5,opt,name=resume_token,json=resumeToken,proto3,oneof"` // resume_token из StreamGoAway (работает на любой реплике)
+}
+
+func (*SubscribeToEventsRequest_SinceEventId) isSubscribeToEventsRequest_Since() {}
+
+func (*SubscribeToEventsRequest_SinceTimestamp) isSubscribeToEventsRequest_Since() {}
+
+func (*SubscribeToEventsRequest_ResumeToken) isSubscribeToEventsRequest_Since() {}
+
+// Ответ со стримом событий
+type EventResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Event:
+	//
+	//	*EventResponse_HealthCheck
+	//	*EventResponse_NoteCreated
+	//	*EventResponse_NoteReminderDue
+	//	*EventResponse_ExportCompleted
+	//	*EventResponse_NoteUpdated
+	//	*EventResponse_NoteDeleted
+	//	*EventResponse_NoteShared
+	//	*EventResponse_NoteArchived
+	//	*EventResponse_SavedSearchMatched
+	//	*EventResponse_QuotaWarning
+	//	*EventResponse_GoAway
+	Event         isEventResponse_Event  `protobuf_oneof:"event"`
+	EventId       uint64                 `protobuf:"varint,8,opt,name=event_id,json=eventId,proto3" json:"event_id,omitempty"`      // Номер события в журнале (since_event_id для переподключения), 0 у health-check
+	EventTime     *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=event_time,json=eventTime,proto3" json:"event_time,omitempty"` // Время публикации события
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EventResponse) Reset() {
+	*x = EventResponse{}
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[116]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EventResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EventResponse) ProtoMessage() {}
+
+func (x *EventResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[116]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EventResponse.ProtoReflect.Descriptor instead.
+func (*EventResponse) Descriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{116}
+}
+
+func (x *EventResponse) GetEvent() isEventResponse_Event {
+	if x != nil {
+		return x.Event
+	}
+	return nil
+}
+
+func (x *EventResponse) GetHealthCheck() *HealthCheck {
+	if x != nil {
+		if x, ok := x.Event.(*EventResponse_HealthCheck); ok {
+			return x.HealthCheck
+		}
+	}
+	return nil
+}
+
+func (x *EventResponse) GetNoteCreated() *NoteCreatedEvent {
+	if x != nil {
+		if x, ok := x.Event.(*EventResponse_NoteCreated); ok {
+			return x.NoteCreated
+		}
+	}
+	return nil
+}
+
+func (x *EventResponse) GetNoteReminderDue() *NoteReminderDue {
+	if x != nil {
+		if x, ok := x.Event.(*EventResponse_NoteReminderDue); ok {
+			return x.NoteReminderDue
+		}
+	}
+	return nil
+}
+
+func (x *EventResponse) GetExportCompleted() *ExportCompletedEvent {
+	if x != nil {
+		if x, ok := x.Event.(*EventResponse_ExportCompleted); ok {
+			return x.ExportCompleted
+		}
+	}
+	return nil
+}
+
+func (x *EventResponse) GetNoteUpdated() *NoteUpdatedEvent {
+	if x != nil {
+		if x, ok := x.Event.(*EventResponse_NoteUpdated); ok {
+			return x.NoteUpdated
+		}
+	}
+	return nil
+}
+
+func (x *EventResponse) GetNoteDeleted() *NoteDeletedEvent {
+	if x != nil {
+		if x, ok := x.Event.(*EventResponse_NoteDeleted); ok {
+			return x.NoteDeleted
+		}
+	}
+	return nil
+}
+
+func (x *EventResponse) GetNoteShared() *NoteSharedEvent {
+	if x != nil {
+		if x, ok := x.Event.(*EventResponse_NoteShared); ok {
+			return x.NoteShared
+		}
+	}
+	return nil
+}
+
+func (x *EventResponse) GetNoteArchived() *NoteArchivedEvent {
+	if x != nil {
+		if x, ok := x.Event.(*EventResponse_NoteArchived); ok {
+			return x.NoteArchived
+		}
+	}
+	return nil
+}
+
+func (x *EventResponse) GetSavedSearchMatched() *SavedSearchMatchedEvent {
+	if x != nil {
+		if x, ok := x.Event.(*EventResponse_SavedSearchMatched); ok {
+			return x.SavedSearchMatched
+		}
+	}
+	return nil
+}
+
+func (x *EventResponse) GetQuotaWarning() *QuotaWarningEvent {
+	if x != nil {
+		if x, ok := x.Event.(*EventResponse_QuotaWarning); ok {
+			return x.QuotaWarning
+		}
+	}
+	return nil
+}
+
+func (x *EventResponse) GetGoAway() *StreamGoAway {
+	if x != nil {
+		if x, ok := x.Event.(*EventResponse_GoAway); ok {
+			return x.GoAway
+		}
+	}
+	return nil
+}
+
+func (x *EventResponse) GetEventId() uint64 {
+	if x != nil {
+		return x.EventId
+	}
+	return 0
+}
+
+func (x *EventResponse) GetEventTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EventTime
+	}
+	return nil
+}
+
+type isEventResponse_Event interface {
+	isEventResponse_Event()
+}
+
+type EventResponse_HealthCheck struct {
+	// Приветственное сообщение или health-check
+	HealthCheck *HealthCheck `protobuf:"bytes,1,opt,name=health_check,json=healthCheck,proto3,oneof"`
+}
+
+type EventResponse_NoteCreated struct {
+	// Событие создания новой заметки
+	NoteCreated *NoteCreatedEvent `protobuf:"bytes,2,opt,name=note_created,json=noteCreated,proto3,oneof"`
+}
+
+type EventResponse_NoteReminderDue struct {
+	// Наступило время напоминания заметки
+	NoteReminderDue *NoteReminderDue `protobuf:"bytes,3,opt,name=note_reminder_due,json=noteReminderDue,proto3,oneof"`
+}
+
+type EventResponse_ExportCompleted struct {
+	// Завершилась выгрузка заметок в хранилище
+	ExportCompleted *ExportCompletedEvent `protobuf:"bytes,4,opt,name=export_completed,json=exportCompleted,proto3,oneof"`
+}
+
+type EventResponse_NoteUpdated struct {
+	// Изменена заметка (в том числе закрепление)
+	NoteUpdated *NoteUpdatedEvent `protobuf:"bytes,5,opt,name=note_updated,json=noteUpdated,proto3,oneof"`
+}
+
+type EventResponse_NoteDeleted struct {
+	// Удалена заметка
+	NoteDeleted *NoteDeletedEvent `protobuf:"bytes,6,opt,name=note_deleted,json=noteDeleted,proto3,oneof"`
+}
+
+type EventResponse_NoteShared struct {
+	// Владелец открыл доступ к заметке другому пользователю
+	NoteShared *NoteSharedEvent `protobuf:"bytes,7,opt,name=note_shared,json=noteShared,proto3,oneof"`
+}
+
+type EventResponse_NoteArchived struct {
+	// Заметка перемещена в архив
+	NoteArchived *NoteArchivedEvent `protobuf:"bytes,13,opt,name=note_archived,json=noteArchived,proto3,oneof"`
+}
+
+type EventResponse_SavedSearchMatched struct {
+	// Заметка начала подходить под сохраненный поиск владельца
+	SavedSearchMatched *SavedSearchMatchedEvent `protobuf:"bytes,11,opt,name=saved_search_matched,json=savedSearchMatched,proto3,oneof"`
+}
+
+type EventResponse_QuotaWarning struct {
+	// Использование квоты заметок тенанта превысило мягкий порог
+	QuotaWarning *QuotaWarningEvent `protobuf:"bytes,12,opt,name=quota_warning,json=quotaWarning,proto3,oneof"`
+}
+
+type EventResponse_GoAway struct {
+	// Последнее сообщение стрима перед его закрытием сервером
+	GoAway *StreamGoAway `protobuf:"bytes,10,opt,name=go_away,json=goAway,proto3,oneof"`
+}
+
+func (*EventResponse_HealthCheck) isEventResponse_Event() {}
+
+func (*EventResponse_NoteCreated) isEventResponse_Event() {}
+
+func (*EventResponse_NoteReminderDue) isEventResponse_Event() {}
+
+func (*EventResponse_ExportCompleted) isEventResponse_Event() {}
+
+func (*EventResponse_NoteUpdated) isEventResponse_Event() {}
+
+func (*EventResponse_NoteDeleted) isEventResponse_Event() {}
+
+func (*EventResponse_NoteShared) isEventResponse_Event() {}
+
+func (*EventResponse_NoteArchived) isEventResponse_Event() {}
+
+func (*EventResponse_SavedSearchMatched) isEventResponse_Event() {}
+
+func (*EventResponse_QuotaWarning) isEventResponse_Event() {}
+
+func (*EventResponse_GoAway) isEventResponse_Event() {}
+
+// HealthCheck сообщение для поддержания соединения
+type HealthCheck struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Message       string                 `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`     // Сообщение health-check
+	Timestamp     *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=timestamp,proto3" json:"timestamp,omitempty"` // Временная метка
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HealthCheck) Reset() {
+	*x = HealthCheck{}
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[117]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HealthCheck) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HealthCheck) ProtoMessage() {}
+
+func (x *HealthCheck) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[117]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HealthCheck.ProtoReflect.Descriptor instead.
+func (*HealthCheck) Descriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{117}
+}
+
+func (x *HealthCheck) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *HealthCheck) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+// StreamGoAway сообщает, что сервер завершает стрим (например, при остановке)
+// Клиент переподключается, передав resume_token, и получает события, опубликованные после закрытия
+type StreamGoAway struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Reason        string                 `protobuf:"bytes,1,opt,name=reason,proto3" json:"reason,omitempty"`                                 // Причина закрытия
+	ResumeToken   string                 `protobuf:"bytes,2,opt,name=resume_token,json=resumeToken,proto3" json:"resume_token,omitempty"`    // Значение для SubscribeToEventsRequest.resume_token
+	LastEventId   uint64                 `protobuf:"varint,3,opt,name=last_event_id,json=lastEventId,proto3" json:"last_event_id,omitempty"` // event_id последнего обработанного события (0 - не было)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamGoAway) Reset() {
+	*x = StreamGoAway{}
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[118]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamGoAway) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamGoAway) ProtoMessage() {}
+
+func (x *StreamGoAway) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[118]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamGoAway.ProtoReflect.Descriptor instead.
+func (*StreamGoAway) Descriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{118}
+}
+
+func (x *StreamGoAway) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *StreamGoAway) GetResumeToken() string {
+	if x != nil {
+		return x.ResumeToken
+	}
+	return ""
+}
+
+func (x *StreamGoAway) GetLastEventId() uint64 {
+	if x != nil {
+		return x.LastEventId
+	}
+	return 0
+}
+
+// Заметка начала подходить под сохраненный поиск: создана подходящая заметка
+// или изменена заметка, которая до изменения не подходила
+type SavedSearchMatchedEvent struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SavedSearch   *SavedSearch           `protobuf:"bytes,1,opt,name=saved_search,json=savedSearch,proto3" json:"saved_search,omitempty"` // Поиск
+	Note          *Note                  `protobuf:"bytes,2,opt,name=note,proto3" json:"note,omitempty"`                                  // Заметка
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SavedSearchMatchedEvent) Reset() {
+	*x = SavedSearchMatchedEvent{}
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[119]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SavedSearchMatchedEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SavedSearchMatchedEvent) ProtoMessage() {}
+
+func (x *SavedSearchMatchedEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[119]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SavedSearchMatchedEvent.ProtoReflect.Descriptor instead.
+func (*SavedSearchMatchedEvent) Descriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{119}
+}
+
+func (x *SavedSearchMatchedEvent) GetSavedSearch() *SavedSearch {
+	if x != nil {
+		return x.SavedSearch
+	}
+	return nil
+}
+
+func (x *SavedSearchMatchedEvent) GetNote() *Note {
+	if x != nil {
+		return x.Note
+	}
+	return nil
+}
+
+// Использование квоты заметок превысило мягкий порог (tenants.*.quota_warning_percents)
+// Отправляется один раз при пересечении порога; при 100% создание заметок отклоняется (RESOURCE_EXHAUSTED)
+type QuotaWarningEvent struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Used             int32                  `protobuf:"varint,1,opt,name=used,proto3" json:"used,omitempty"`                                                 // Заметок тенанта после создания
+	Limit            int32                  `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`                                               // Квота заметок (max_notes)
+	ThresholdPercent int32                  `protobuf:"varint,3,opt,name=threshold_percent,json=thresholdPercent,proto3" json:"threshold_percent,omitempty"` // Пересеченный порог в процентах
+	Note             *Note                  `protobuf:"bytes,4,opt,name=note,proto3" json:"note,omitempty"`                                                  // Заметка, создание которой пересекло порог
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *QuotaWarningEvent) Reset() {
+	*x = QuotaWarningEvent{}
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[120]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *QuotaWarningEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QuotaWarningEvent) ProtoMessage() {}
+
+func (x *QuotaWarningEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[120]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QuotaWarningEvent.ProtoReflect.Descriptor instead.
+func (*QuotaWarningEvent) Descriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{120}
+}
+
+func (x *QuotaWarningEvent) GetUsed() int32 {
+	if x != nil {
+		return x.Used
+	}
+	return 0
+}
+
+func (x *QuotaWarningEvent) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *QuotaWarningEvent) GetThresholdPercent() int32 {
+	if x != nil {
+		return x.ThresholdPercent
+	}
+	return 0
+}
+
+func (x *QuotaWarningEvent) GetNote() *Note {
+	if x != nil {
+		return x.Note
+	}
+	return nil
+}
+
+// Событие создания новой заметки
+// Подробности об использовании oneof: см. README.md раздел "NoteCreatedEvent: oneof"
+type NoteCreatedEvent struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Payload:
+	//
+	//	*NoteCreatedEvent_NoteId
+	//	*NoteCreatedEvent_Note
+	Payload       isNoteCreatedEvent_Payload `protobuf_oneof:"payload"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NoteCreatedEvent) Reset() {
+	*x = NoteCreatedEvent{}
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[121]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NoteCreatedEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NoteCreatedEvent) ProtoMessage() {}
+
+func (x *NoteCreatedEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[121]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NoteCreatedEvent.ProtoReflect.Descriptor instead.
+func (*NoteCreatedEvent) Descriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{121}
+}
+
+func (x *NoteCreatedEvent) GetPayload() isNoteCreatedEvent_Payload {
+	if x != nil {
+		return x.Payload
+	}
+	return nil
+}
+
+func (x *NoteCreatedEvent) GetNoteId() string {
+	if x != nil {
+		if x, ok := x.Payload.(*NoteCreatedEvent_NoteId); ok {
+			return x.NoteId
+		}
+	}
+	return ""
+}
+
+func (x *NoteCreatedEvent) GetNote() *Note {
+	if x != nil {
+		if x, ok := x.Payload.(*NoteCreatedEvent_Note); ok {
+			return x.Note
+		}
+	}
+	return nil
+}
+
+type isNoteCreatedEvent_Payload interface {
+	isNoteCreatedEvent_Payload()
+}
+
+type NoteCreatedEvent_NoteId struct {
+	NoteId string `protobuf:"bytes,1,opt,name=note_id,json=noteId,proto3,oneof"` // ID созданной заметки (легковесный вариант)
+}
+
+type NoteCreatedEvent_Note struct {
+	Note *Note `protobuf:"bytes,2,opt,name=note,proto3,oneof"` // Полная заметка (подробный вариант)
+}
+
+func (*NoteCreatedEvent_NoteId) isNoteCreatedEvent_Payload() {}
+
+func (*NoteCreatedEvent_Note) isNoteCreatedEvent_Payload() {}
+
+// Событие изменения заметки: содержимое, теги, напоминание или закрепление
+type NoteUpdatedEvent struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Note          *Note                  `protobuf:"bytes,1,opt,name=note,proto3" json:"note,omitempty"` // Заметка после изменения
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NoteUpdatedEvent) Reset() {
+	*x = NoteUpdatedEvent{}
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[122]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NoteUpdatedEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NoteUpdatedEvent) ProtoMessage() {}
+
+func (x *NoteUpdatedEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[122]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NoteUpdatedEvent.ProtoReflect.Descriptor instead.
+func (*NoteUpdatedEvent) Descriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{122}
+}
+
+func (x *NoteUpdatedEvent) GetNote() *Note {
+	if x != nil {
+		return x.Note
+	}
+	return nil
+}
+
+// Событие удаления заметки
+type NoteDeletedEvent struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	NoteId        string                 `protobuf:"bytes,1,opt,name=note_id,json=noteId,proto3" json:"note_id,omitempty"` // ID удаленной заметки
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NoteDeletedEvent) Reset() {
+	*x = NoteDeletedEvent{}
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[123]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NoteDeletedEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NoteDeletedEvent) ProtoMessage() {}
+
+func (x *NoteDeletedEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[123]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NoteDeletedEvent.ProtoReflect.Descriptor instead.
+func (*NoteDeletedEvent) Descriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{123}
+}
+
+func (x *NoteDeletedEvent) GetNoteId() string {
+	if x != nil {
+		return x.NoteId
+	}
+	return ""
+}
+
+// Событие архивирования заметки (возврат из архива публикуется как NoteUpdatedEvent)
+type NoteArchivedEvent struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Note          *Note                  `protobuf:"bytes,1,opt,name=note,proto3" json:"note,omitempty"` // Заметка после архивирования
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NoteArchivedEvent) Reset() {
+	*x = NoteArchivedEvent{}
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[124]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NoteArchivedEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NoteArchivedEvent) ProtoMessage() {}
+
+func (x *NoteArchivedEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[124]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NoteArchivedEvent.ProtoReflect.Descriptor instead.
+func (*NoteArchivedEvent) Descriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{124}
+}
+
+func (x *NoteArchivedEvent) GetNote() *Note {
+	if x != nil {
+		return x.Note
+	}
+	return nil
+}
+
+// Событие предоставления доступа к заметке
+// Доставляется владельцу и пользователю, получившему доступ
+type NoteSharedEvent struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Note          *Note                  `protobuf:"bytes,1,opt,name=note,proto3" json:"note,omitempty"`   // Заметка, к которой открыт доступ
+	Share         *Share                 `protobuf:"bytes,2,opt,name=share,proto3" json:"share,omitempty"` // Предоставленный доступ
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NoteSharedEvent) Reset() {
+	*x = NoteSharedEvent{}
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[125]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NoteSharedEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NoteSharedEvent) ProtoMessage() {}
+
+func (x *NoteSharedEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[125]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NoteSharedEvent.ProtoReflect.Descriptor instead.
+func (*NoteSharedEvent) Descriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{125}
+}
+
+func (x *NoteSharedEvent) GetNote() *Note {
+	if x != nil {
+		return x.Note
+	}
+	return nil
+}
+
+func (x *NoteSharedEvent) GetShare() *Share {
+	if x != nil {
+		return x.Share
+	}
+	return nil
+}
+
+// Событие напоминания: наступило время remind_at заметки
+type NoteReminderDue struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Note          *Note                  `protobuf:"bytes,1,opt,name=note,proto3" json:"note,omitempty"`                         // Заметка с напоминанием
+	RemindAt      *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=remind_at,json=remindAt,proto3" json:"remind_at,omitempty"` // Время напоминания, которое сработало
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NoteReminderDue) Reset() {
+	*x = NoteReminderDue{}
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[126]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NoteReminderDue) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NoteReminderDue) ProtoMessage() {}
+
+func (x *NoteReminderDue) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[126]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NoteReminderDue.ProtoReflect.Descriptor instead.
+func (*NoteReminderDue) Descriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{126}
+}
+
+func (x *NoteReminderDue) GetNote() *Note {
+	if x != nil {
+		return x.Note
+	}
+	return nil
+}
+
+func (x *NoteReminderDue) GetRemindAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.RemindAt
+	}
+	return nil
+}
+
+// Запрос на загрузку метрики (клиентский стриминг)
+type MetricRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Value         float64                `protobuf:"fixed64,1,opt,name=value,proto3" json:"value,omitempty"` // Значение метрики
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`     // Опционально: название метрики
+	Time          *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=time,proto3" json:"time,omitempty"`     // Опционально: время значения (по умолчанию время получения сервером)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MetricRequest) Reset() {
+	*x = MetricRequest{}
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[127]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MetricRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MetricRequest) ProtoMessage() {}
+
+func (x *MetricRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[127]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MetricRequest.ProtoReflect.Descriptor instead.
+func (*MetricRequest) Descriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{127}
+}
+
+func (x *MetricRequest) GetValue() float64 {
+	if x != nil {
+		return x.Value
+	}
+	return 0
+}
+
+func (x *MetricRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *MetricRequest) GetTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Time
+	}
+	return nil
+}
+
+// Ответ со статистикой по метрикам
+type SummaryResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Sum           float64                `protobuf:"fixed64,1,opt,name=sum,proto3" json:"sum,omitempty"`         // Сумма всех метрик
+	Average       float64                `protobuf:"fixed64,2,opt,name=average,proto3" json:"average,omitempty"` // Среднее значение
+	Count         int64                  `protobuf:"varint,3,opt,name=count,proto3" json:"count,omitempty"`      // Количество метрик
+	Metrics       []*MetricSummary       `protobuf:"bytes,4,rep,name=metrics,proto3" json:"metrics,omitempty"`   // Статистика по каждому названию метрики (по алфавиту)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SummaryResponse) Reset() {
+	*x = SummaryResponse{}
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[128]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SummaryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SummaryResponse) ProtoMessage() {}
+
+func (x *SummaryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[128]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SummaryResponse.ProtoReflect.Descriptor instead.
+func (*SummaryResponse) Descriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{128}
+}
+
+func (x *SummaryResponse) GetSum() float64 {
+	if x != nil {
+		return x.Sum
+	}
+	return 0
+}
+
+func (x *SummaryResponse) GetAverage() float64 {
+	if x != nil {
+		return x.Average
+	}
+	return 0
+}
+
+func (x *SummaryResponse) GetCount() int64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *SummaryResponse) GetMetrics() []*MetricSummary {
+	if x != nil {
+		return x.Metrics
+	}
+	return nil
+}
+
+// Статистика значений одной метрики
+type MetricSummary struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`         // Название метрики (пусто для значений без названия)
+	Count         int64                  `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`      // Количество значений
+	Sum           float64                `protobuf:"fixed64,3,opt,name=sum,proto3" json:"sum,omitempty"`         // Сумма значений
+	Average       float64                `protobuf:"fixed64,4,opt,name=average,proto3" json:"average,omitempty"` // Среднее значение
+	Min           float64                `protobuf:"fixed64,5,opt,name=min,proto3" json:"min,omitempty"`         // Минимальное значение
+	Max           float64                `protobuf:"fixed64,6,opt,name=max,proto3" json:"max,omitempty"`         // Максимальное значение
+	P95           float64                `protobuf:"fixed64,7,opt,name=p95,proto3" json:"p95,omitempty"`         // 95-й перцентиль (по ближайшему рангу; после 10000 значений - по случайной выборке)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MetricSummary) Reset() {
+	*x = MetricSummary{}
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[129]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MetricSummary) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MetricSummary) ProtoMessage() {}
+
+func (x *MetricSummary) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[129]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MetricSummary.ProtoReflect.Descriptor instead.
+func (*MetricSummary) Descriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{129}
+}
+
+func (x *MetricSummary) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *MetricSummary) GetCount() int64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *MetricSummary) GetSum() float64 {
+	if x != nil {
+		return x.Sum
+	}
+	return 0
+}
+
+func (x *MetricSummary) GetAverage() float64 {
+	if x != nil {
+		return x.Average
+	}
+	return 0
+}
+
+func (x *MetricSummary) GetMin() float64 {
+	if x != nil {
+		return x.Min
+	}
+	return 0
+}
+
+func (x *MetricSummary) GetMax() float64 {
+	if x != nil {
+		return x.Max
+	}
+	return 0
+}
+
+func (x *MetricSummary) GetP95() float64 {
+	if x != nil {
+		return x.P95
+	}
+	return 0
+}
+
+// Сообщение клиента StreamMetrics
+type StreamMetricsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Payload:
+	//
+	//	*StreamMetricsRequest_Options
+	//	*StreamMetricsRequest_Metric
+	Payload       isStreamMetricsRequest_Payload `protobuf_oneof:"payload"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamMetricsRequest) Reset() {
+	*x = StreamMetricsRequest{}
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[130]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamMetricsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamMetricsRequest) ProtoMessage() {}
+
+func (x *StreamMetricsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[130]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamMetricsRequest.ProtoReflect.Descriptor instead.
+func (*StreamMetricsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{130}
+}
+
+func (x *StreamMetricsRequest) GetPayload() isStreamMetricsRequest_Payload {
+	if x != nil {
+		return x.Payload
+	}
+	return nil
+}
+
+func (x *StreamMetricsRequest) GetOptions() *StreamMetricsOptions {
+	if x != nil {
+		if x, ok := x.Payload.(*StreamMetricsRequest_Options); ok {
+			return x.Options
+		}
+	}
+	return nil
+}
+
+func (x *StreamMetricsRequest) GetMetric() *MetricRequest {
+	if x != nil {
+		if x, ok := x.Payload.(*StreamMetricsRequest_Metric); ok {
+			return x.Metric
+		}
+	}
+	return nil
+}
+
+type isStreamMetricsRequest_Payload interface {
+	isStreamMetricsRequest_Payload()
+}
+
+type StreamMetricsRequest_Options struct {
+	Options *StreamMetricsOptions `protobuf:"bytes,1,opt,name=options,proto3,oneof"` // Параметры агрегации (только в первом сообщении)
+}
+
+type StreamMetricsRequest_Metric struct {
+	Metric *MetricRequest `protobuf:"bytes,2,opt,name=metric,proto3,oneof"` // Значение метрики
+}
+
+func (*StreamMetricsRequest_Options) isStreamMetricsRequest_Payload() {}
+
+func (*StreamMetricsRequest_Metric) isStreamMetricsRequest_Payload() {}
+
+// Параметры агрегации StreamMetrics
+type StreamMetricsOptions struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	WindowSeconds uint32                 `protobuf:"varint,1,opt,name=window_seconds,json=windowSeconds,proto3" json:"window_seconds,omitempty"` // Длительность окна промежуточной статистики (0 - только итоговая статистика)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamMetricsOptions) Reset() {
+	*x = StreamMetricsOptions{}
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[131]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamMetricsOptions) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamMetricsOptions) ProtoMessage() {}
+
+func (x *StreamMetricsOptions) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[131]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamMetricsOptions.ProtoReflect.Descriptor instead.
+func (*StreamMetricsOptions) Descriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{131}
+}
+
+func (x *StreamMetricsOptions) GetWindowSeconds() uint32 {
+	if x != nil {
+		return x.WindowSeconds
+	}
+	return 0
+}
+
+// Статистика StreamMetrics: окна или всего стрима
+type StreamMetricsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Summary       *SummaryResponse       `protobuf:"bytes,1,opt,name=summary,proto3" json:"summary,omitempty"`                            // Статистика метрик окна (для final - всего стрима)
+	WindowStart   *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=window_start,json=windowStart,proto3" json:"window_start,omitempty"` // Начало окна (для final - начало стрима)
+	WindowEnd     *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=window_end,json=windowEnd,proto3" json:"window_end,omitempty"`       // Конец окна (для final - закрытие отправки клиентом)
+	Final         bool                   `protobuf:"varint,4,opt,name=final,proto3" json:"final,omitempty"`                               // Итоговая статистика, после нее стрим завершается
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamMetricsResponse) Reset() {
+	*x = StreamMetricsResponse{}
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[132]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamMetricsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamMetricsResponse) ProtoMessage() {}
+
+func (x *StreamMetricsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[132]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamMetricsResponse.ProtoReflect.Descriptor instead.
+func (*StreamMetricsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{132}
+}
+
+func (x *StreamMetricsResponse) GetSummary() *SummaryResponse {
+	if x != nil {
+		return x.Summary
+	}
+	return nil
+}
+
+func (x *StreamMetricsResponse) GetWindowStart() *timestamppb.Timestamp {
+	if x != nil {
+		return x.WindowStart
+	}
+	return nil
+}
+
+func (x *StreamMetricsResponse) GetWindowEnd() *timestamppb.Timestamp {
+	if x != nil {
+		return x.WindowEnd
+	}
+	return nil
+}
+
+func (x *StreamMetricsResponse) GetFinal() bool {
+	if x != nil {
+		return x.Final
+	}
+	return false
+}
+
+// Запрос значений метрики
+type QueryMetricsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`                                                // Название метрики (пусто для значений без названия)
+	From          *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=from,proto3" json:"from,omitempty"`                                                // Начало периода включительно (не задано - без ограничения)
+	To            *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=to,proto3" json:"to,omitempty"`                                                    // Конец периода не включительно (не задано - без ограничения)
+	Aggregation   MetricAggregation      `protobuf:"varint,4,opt,name=aggregation,proto3,enum=notes.v1.MetricAggregation" json:"aggregation,omitempty"` // Агрегат (не задан - значения без агрегации)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *QueryMetricsRequest) Reset() {
+	*x = QueryMetricsRequest{}
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[133]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *QueryMetricsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QueryMetricsRequest) ProtoMessage() {}
+
+func (x *QueryMetricsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[133]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QueryMetricsRequest.ProtoReflect.Descriptor instead.
+func (*QueryMetricsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{133}
+}
+
+func (x *QueryMetricsRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *QueryMetricsRequest) GetFrom() *timestamppb.Timestamp {
+	if x != nil {
+		return x.From
+	}
+	return nil
+}
+
+func (x *QueryMetricsRequest) GetTo() *timestamppb.Timestamp {
+	if x != nil {
+		return x.To
+	}
+	return nil
+}
+
+func (x *QueryMetricsRequest) GetAggregation() MetricAggregation {
+	if x != nil {
+		return x.Aggregation
+	}
+	return MetricAggregation_METRIC_AGGREGATION_UNSPECIFIED
+}
+
+// Значение метрики
+type MetricPoint struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Value         float64                `protobuf:"fixed64,1,opt,name=value,proto3" json:"value,omitempty"`
+	Time          *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=time,proto3" json:"time,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MetricPoint) Reset() {
+	*x = MetricPoint{}
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[134]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MetricPoint) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MetricPoint) ProtoMessage() {}
+
+func (x *MetricPoint) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[134]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MetricPoint.ProtoReflect.Descriptor instead.
+func (*MetricPoint) Descriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{134}
+}
+
+func (x *MetricPoint) GetValue() float64 {
+	if x != nil {
+		return x.Value
+	}
+	return 0
+}
+
+func (x *MetricPoint) GetTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Time
+	}
+	return nil
+}
+
+// Значения метрики или их агрегат
+type QueryMetricsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Points        []*MetricPoint         `protobuf:"bytes,1,rep,name=points,proto3" json:"points,omitempty"`        // Значения в порядке времени (только без агрегации, не более 10000 первых)
+	Truncated     bool                   `protobuf:"varint,2,opt,name=truncated,proto3" json:"truncated,omitempty"` // За период значений больше, чем возвращено в points
+	Value         float64                `protobuf:"fixed64,3,opt,name=value,proto3" json:"value,omitempty"`        // Агрегат (без агрегации - 0)
+	Count         int64                  `protobuf:"varint,4,opt,name=count,proto3" json:"count,omitempty"`         // Количество значений за период
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *QueryMetricsResponse) Reset() {
+	*x = QueryMetricsResponse{}
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[135]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *QueryMetricsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QueryMetricsResponse) ProtoMessage() {}
+
+func (x *QueryMetricsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[135]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QueryMetricsResponse.ProtoReflect.Descriptor instead.
+func (*QueryMetricsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{135}
+}
+
+func (x *QueryMetricsResponse) GetPoints() []*MetricPoint {
+	if x != nil {
+		return x.Points
+	}
+	return nil
+}
+
+func (x *QueryMetricsResponse) GetTruncated() bool {
+	if x != nil {
+		return x.Truncated
+	}
+	return false
+}
+
+func (x *QueryMetricsResponse) GetValue() float64 {
+	if x != nil {
+		return x.Value
+	}
+	return 0
+}
+
+func (x *QueryMetricsResponse) GetCount() int64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+// Сообщение в чате (bidirectional streaming)
+type ChatMessage struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CorrelationId string                 `protobuf:"bytes,1,opt,name=correlation_id,json=correlationId,proto3" json:"correlation_id,omitempty"` // ID для корреляции запросов и ответов
+	// Types that are valid to be assigned to Content:
+	//
+	//	*ChatMessage_TextMessage
+	//	*ChatMessage_Error
+	//	*ChatMessage_JoinRoom
+	//	*ChatMessage_LeaveRoom
+	//	*ChatMessage_TypingIndicator
+	//	*ChatMessage_PresenceUpdate
+	Content       isChatMessage_Content `protobuf_oneof:"content"`
+	RoomId        string                `protobuf:"bytes,4,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"` // Комната сообщения; пусто у уведомлений сервера
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ChatMessage) Reset() {
+	*x = ChatMessage{}
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[136]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChatMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChatMessage) ProtoMessage() {}
+
+func (x *ChatMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[136]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChatMessage.ProtoReflect.Descriptor instead.
+func (*ChatMessage) Descriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{136}
+}
+
+func (x *ChatMessage) GetCorrelationId() string {
+	if x != nil {
+		return x.CorrelationId
+	}
+	return ""
+}
+
+func (x *ChatMessage) GetContent() isChatMessage_Content {
+	if x != nil {
+		return x.Content
+	}
+	return nil
+}
+
+func (x *ChatMessage) GetTextMessage() *ChatTextMessage {
+	if x != nil {
+		if x, ok := x.Content.(*ChatMessage_TextMessage); ok {
+			return x.TextMessage
+		}
+	}
+	return nil
+}
+
+func (x *ChatMessage) GetError() *ChatError {
+	if x != nil {
+		if x, ok := x.Content.(*ChatMessage_Error); ok {
+			return x.Error
+		}
+	}
+	return nil
+}
+
+func (x *ChatMessage) GetJoinRoom() *ChatJoinRoom {
+	if x != nil {
+		if x, ok := x.Content.(*ChatMessage_JoinRoom); ok {
+			return x.JoinRoom
+		}
+	}
+	return nil
+}
+
+func (x *ChatMessage) GetLeaveRoom() *ChatLeaveRoom {
+	if x != nil {
+		if x, ok := x.Content.(*ChatMessage_LeaveRoom); ok {
+			return x.LeaveRoom
+		}
+	}
+	return nil
+}
+
+func (x *ChatMessage) GetTypingIndicator() *TypingIndicator {
+	if x != nil {
+		if x, ok := x.Content.(*ChatMessage_TypingIndicator); ok {
+			return x.TypingIndicator
+		}
+	}
+	return nil
+}
+
+func (x *ChatMessage) GetPresenceUpdate() *PresenceUpdate {
+	if x != nil {
+		if x, ok := x.Content.(*ChatMessage_PresenceUpdate); ok {
+			return x.PresenceUpdate
+		}
+	}
+	return nil
+}
+
+func (x *ChatMessage) GetRoomId() string {
+	if x != nil {
+		return x.RoomId
+	}
+	return ""
+}
+
+type isChatMessage_Content interface {
+	isChatMessage_Content()
+}
+
+type ChatMessage_TextMessage struct {
+	// Обычное текстовое сообщение
+	TextMessage *ChatTextMessage `protobuf:"bytes,2,opt,name=text_message,json=textMessage,proto3,oneof"`
+}
+
+type ChatMessage_Error struct {
+	// Бизнесовая ошибка (не разрывающая соединение)
+	Error *ChatError `protobuf:"bytes,3,opt,name=error,proto3,oneof"`
+}
+
+type ChatMessage_JoinRoom struct {
+	// Вход в комнату room_id (сервер подтверждает тем же сообщением)
+	JoinRoom *ChatJoinRoom `protobuf:"bytes,5,opt,name=join_room,json=joinRoom,proto3,oneof"`
+}
+
+type ChatMessage_LeaveRoom struct {
+	// Выход из комнаты room_id (сервер подтверждает тем же сообщением)
+	LeaveRoom *ChatLeaveRoom `protobuf:"bytes,6,opt,name=leave_room,json=leaveRoom,proto3,oneof"`
+}
+
+type ChatMessage_TypingIndicator struct {
+	// Пользователь начал или закончил набирать сообщение в комнате room_id
+	// (сервер пересылает остальным участникам комнаты, не подтверждая отправителю)
+	TypingIndicator *TypingIndicator `protobuf:"bytes,7,opt,name=typing_indicator,json=typingIndicator,proto3,oneof"`
+}
+
+type ChatMessage_PresenceUpdate struct {
+	// Пользователь вошел в комнату room_id или вышел из нее (отправляет только сервер)
+	PresenceUpdate *PresenceUpdate `protobuf:"bytes,8,opt,name=presence_update,json=presenceUpdate,proto3,oneof"`
+}
+
+func (*ChatMessage_TextMessage) isChatMessage_Content() {}
+
+func (*ChatMessage_Error) isChatMessage_Content() {}
+
+func (*ChatMessage_JoinRoom) isChatMessage_Content() {}
+
+func (*ChatMessage_LeaveRoom) isChatMessage_Content() {}
+
+func (*ChatMessage_TypingIndicator) isChatMessage_Content() {}
+
+func (*ChatMessage_PresenceUpdate) isChatMessage_Content() {}
+
+// Текстовое сообщение в чате
+type ChatTextMessage struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Text          string                 `protobuf:"bytes,1,opt,name=text,proto3" json:"text,omitempty"`                         // Текст сообщения
+	Timestamp     *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=timestamp,proto3" json:"timestamp,omitempty"`               // Временная метка сообщения
+	SenderId      string                 `protobuf:"bytes,3,opt,name=sender_id,json=senderId,proto3" json:"sender_id,omitempty"` // Автор сообщения (заполняет сервер; пусто у уведомлений сервера)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ChatTextMessage) Reset() {
+	*x = ChatTextMessage{}
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[137]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChatTextMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChatTextMessage) ProtoMessage() {}
+
+func (x *ChatTextMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[137]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChatTextMessage.ProtoReflect.Descriptor instead.
+func (*ChatTextMessage) Descriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{137}
+}
+
+func (x *ChatTextMessage) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+func (x *ChatTextMessage) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+func (x *ChatTextMessage) GetSenderId() string {
+	if x != nil {
+		return x.SenderId
+	}
+	return ""
+}
+
+// Управляющее сообщение: войти в комнату ChatMessage.room_id
+type ChatJoinRoom struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Participants  []string               `protobuf:"bytes,1,rep,name=participants,proto3" json:"participants,omitempty"` // Пользователи в комнате после входа (заполняет сервер в подтверждении)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ChatJoinRoom) Reset() {
+	*x = ChatJoinRoom{}
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[138]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChatJoinRoom) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChatJoinRoom) ProtoMessage() {}
+
+func (x *ChatJoinRoom) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[138]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChatJoinRoom.ProtoReflect.Descriptor instead.
+func (*ChatJoinRoom) Descriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{138}
+}
+
+func (x *ChatJoinRoom) GetParticipants() []string {
+	if x != nil {
+		return x.Participants
+	}
+	return nil
+}
+
+// Управляющее сообщение: выйти из комнаты ChatMessage.room_id
+type ChatLeaveRoom struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ChatLeaveRoom) Reset() {
+	*x = ChatLeaveRoom{}
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[139]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChatLeaveRoom) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChatLeaveRoom) ProtoMessage() {}
+
+func (x *ChatLeaveRoom) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[139]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChatLeaveRoom.ProtoReflect.Descriptor instead.
+func (*ChatLeaveRoom) Descriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{139}
+}
+
+// Индикатор набора текста
+type TypingIndicator struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Typing        bool                   `protobuf:"varint,1,opt,name=typing,proto3" json:"typing,omitempty"`              // true - пользователь набирает сообщение, false - закончил
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"` // Пользователь (заполняет сервер)
+	Timestamp     *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=timestamp,proto3" json:"timestamp,omitempty"`         // Время события (заполняет сервер)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TypingIndicator) Reset() {
+	*x = TypingIndicator{}
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[140]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TypingIndicator) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TypingIndicator) ProtoMessage() {}
 
-// Enum value maps for ChatErrorCode.
-var (
-	ChatErrorCode_name = map[int32]string{
-		0: "CHAT_ERROR_CODE_UNSPECIFIED",
-		1: "CHAT_ERROR_CODE_VALIDATION_ERROR",
-		2: "CHAT_ERROR_CODE_RATE_LIMIT",
-		3: "CHAT_ERROR_CODE_INVALID_MESSAGE",
+func (x *TypingIndicator) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[140]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	ChatErrorCode_value = map[string]int32{
-		"CHAT_ERROR_CODE_UNSPECIFIED":      0,
-		"CHAT_ERROR_CODE_VALIDATION_ERROR": 1,
-		"CHAT_ERROR_CODE_RATE_LIMIT":       2,
-		"CHAT_ERROR_CODE_INVALID_MESSAGE":  3,
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TypingIndicator.ProtoReflect.Descriptor instead.
+func (*TypingIndicator) Descriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{140}
+}
+
+func (x *TypingIndicator) GetTyping() bool {
+	if x != nil {
+		return x.Typing
 	}
-)
+	return false
+}
 
-func (x ChatErrorCode) Enum() *ChatErrorCode {
-	p := new(ChatErrorCode)
-	*p = x
-	return p
+func (x *TypingIndicator) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
 }
 
-func (x ChatErrorCode) String() string {
-	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+func (x *TypingIndicator) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
 }
 
-func (ChatErrorCode) Descriptor() protoreflect.EnumDescriptor {
-	return file_proto_notes_v1_notes_proto_enumTypes[0].Descriptor()
+// Событие присутствия: пользователь вошел в комнату или вышел из нее
+type PresenceUpdate struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`              // Пользователь
+	State         PresenceState          `protobuf:"varint,2,opt,name=state,proto3,enum=notes.v1.PresenceState" json:"state,omitempty"` // Вход или выход
+	Timestamp     *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=timestamp,proto3" json:"timestamp,omitempty"`                      // Время события
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-func (ChatErrorCode) Type() protoreflect.EnumType {
-	return &file_proto_notes_v1_notes_proto_enumTypes[0]
+func (x *PresenceUpdate) Reset() {
+	*x = PresenceUpdate{}
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[141]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
-func (x ChatErrorCode) Number() protoreflect.EnumNumber {
-	return protoreflect.EnumNumber(x)
+func (x *PresenceUpdate) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
 
-// Deprecated: Use ChatErrorCode.Descriptor instead.
-func (ChatErrorCode) EnumDescriptor() ([]byte, []int) {
-	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{0}
+func (*PresenceUpdate) ProtoMessage() {}
+
+func (x *PresenceUpdate) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[141]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
 }
 
-// Запрос на создание заметки
-type CreateNoteRequest struct {
+// Deprecated: Use PresenceUpdate.ProtoReflect.Descriptor instead.
+func (*PresenceUpdate) Descriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{141}
+}
+
+func (x *PresenceUpdate) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *PresenceUpdate) GetState() PresenceState {
+	if x != nil {
+		return x.State
+	}
+	return PresenceState_PRESENCE_STATE_UNSPECIFIED
+}
+
+func (x *PresenceUpdate) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+// Ошибка в чате (бизнесовая, не разрывающая соединение)
+type ChatError struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Title         string                 `protobuf:"bytes,1,opt,name=title,proto3" json:"title,omitempty"`     // Заголовок заметки (обязательное, минимум 5 символов, максимум 255)
-	Content       string                 `protobuf:"bytes,2,opt,name=content,proto3" json:"content,omitempty"` // Содержание заметки (обязательное, минимум 10 символов)
+	Code          ChatErrorCode          `protobuf:"varint,1,opt,name=code,proto3,enum=notes.v1.ChatErrorCode" json:"code,omitempty"` // Детерминированный код ошибки (enum)
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`                        // Сообщение об ошибке
+	Details       string                 `protobuf:"bytes,3,opt,name=details,proto3" json:"details,omitempty"`                        // Дополнительные детали ошибки
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *CreateNoteRequest) Reset() {
-	*x = CreateNoteRequest{}
-	mi := &file_proto_notes_v1_notes_proto_msgTypes[0]
+func (x *ChatError) Reset() {
+	*x = ChatError{}
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[142]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CreateNoteRequest) String() string {
+func (x *ChatError) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CreateNoteRequest) ProtoMessage() {}
+func (*ChatError) ProtoMessage() {}
 
-func (x *CreateNoteRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_notes_v1_notes_proto_msgTypes[0]
+func (x *ChatError) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[142]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -114,48 +9568,236 @@ func (x *CreateNoteRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CreateNoteRequest.ProtoReflect.Descriptor instead.
-func (*CreateNoteRequest) Descriptor() ([]byte, []int) {
-	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{0}
+// Deprecated: Use ChatError.ProtoReflect.Descriptor instead.
+func (*ChatError) Descriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{142}
 }
 
-func (x *CreateNoteRequest) GetTitle() string {
+func (x *ChatError) GetCode() ChatErrorCode {
 	if x != nil {
-		return x.Title
+		return x.Code
+	}
+	return ChatErrorCode_CHAT_ERROR_CODE_UNSPECIFIED
+}
+
+func (x *ChatError) GetMessage() string {
+	if x != nil {
+		return x.Message
 	}
 	return ""
 }
 
-func (x *CreateNoteRequest) GetContent() string {
+func (x *ChatError) GetDetails() string {
 	if x != nil {
-		return x.Content
+		return x.Details
 	}
 	return ""
 }
 
-// Ответ с созданной заметкой
-type CreateNoteResponse struct {
+// Запрос входа по имени пользователя и паролю
+type LoginRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Note          *Note                  `protobuf:"bytes,1,opt,name=note,proto3" json:"note,omitempty"`
+	Username      string                 `protobuf:"bytes,1,opt,name=username,proto3" json:"username,omitempty"` // Имя пользователя
+	Password      string                 `protobuf:"bytes,2,opt,name=password,proto3" json:"password,omitempty"` // Пароль
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *CreateNoteResponse) Reset() {
-	*x = CreateNoteResponse{}
-	mi := &file_proto_notes_v1_notes_proto_msgTypes[1]
+func (x *LoginRequest) Reset() {
+	*x = LoginRequest{}
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[143]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CreateNoteResponse) String() string {
+func (x *LoginRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CreateNoteResponse) ProtoMessage() {}
+func (*LoginRequest) ProtoMessage() {}
 
-func (x *CreateNoteResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_notes_v1_notes_proto_msgTypes[1]
+func (x *LoginRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[143]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LoginRequest.ProtoReflect.Descriptor instead.
+func (*LoginRequest) Descriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{143}
+}
+
+func (x *LoginRequest) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *LoginRequest) GetPassword() string {
+	if x != nil {
+		return x.Password
+	}
+	return ""
+}
+
+// Запрос обновления токенов
+type RefreshTokenRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RefreshToken  string                 `protobuf:"bytes,1,opt,name=refresh_token,json=refreshToken,proto3" json:"refresh_token,omitempty"` // Refresh токен (или cookie, выставленная Gateway при входе)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RefreshTokenRequest) Reset() {
+	*x = RefreshTokenRequest{}
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[144]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RefreshTokenRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RefreshTokenRequest) ProtoMessage() {}
+
+func (x *RefreshTokenRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[144]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RefreshTokenRequest.ProtoReflect.Descriptor instead.
+func (*RefreshTokenRequest) Descriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{144}
+}
+
+func (x *RefreshTokenRequest) GetRefreshToken() string {
+	if x != nil {
+		return x.RefreshToken
+	}
+	return ""
+}
+
+// Запрос завершения сессии
+type LogoutRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RefreshToken  string                 `protobuf:"bytes,1,opt,name=refresh_token,json=refreshToken,proto3" json:"refresh_token,omitempty"` // Refresh токен сессии (опционально, иначе закрывается сессия access токена запроса)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LogoutRequest) Reset() {
+	*x = LogoutRequest{}
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[145]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LogoutRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LogoutRequest) ProtoMessage() {}
+
+func (x *LogoutRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[145]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LogoutRequest.ProtoReflect.Descriptor instead.
+func (*LogoutRequest) Descriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{145}
+}
+
+func (x *LogoutRequest) GetRefreshToken() string {
+	if x != nil {
+		return x.RefreshToken
+	}
+	return ""
+}
+
+// Ответ на запрос завершения сессии
+type LogoutResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LogoutResponse) Reset() {
+	*x = LogoutResponse{}
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[146]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LogoutResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LogoutResponse) ProtoMessage() {}
+
+func (x *LogoutResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[146]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LogoutResponse.ProtoReflect.Descriptor instead.
+func (*LogoutResponse) Descriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{146}
+}
+
+// Запрос билета стрима
+type IssueStreamTicketRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Полное имя стримингового метода, например "/notes.v1.NotesService/SubscribeToEvents"
+	Method        string `protobuf:"bytes,1,opt,name=method,proto3" json:"method,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *IssueStreamTicketRequest) Reset() {
+	*x = IssueStreamTicketRequest{}
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[147]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *IssueStreamTicketRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IssueStreamTicketRequest) ProtoMessage() {}
+
+func (x *IssueStreamTicketRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[147]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -166,41 +9808,42 @@ func (x *CreateNoteResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CreateNoteResponse.ProtoReflect.Descriptor instead.
-func (*CreateNoteResponse) Descriptor() ([]byte, []int) {
-	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{1}
+// Deprecated: Use IssueStreamTicketRequest.ProtoReflect.Descriptor instead.
+func (*IssueStreamTicketRequest) Descriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{147}
 }
 
-func (x *CreateNoteResponse) GetNote() *Note {
+func (x *IssueStreamTicketRequest) GetMethod() string {
 	if x != nil {
-		return x.Note
+		return x.Method
 	}
-	return nil
+	return ""
 }
 
-// Запрос на получение заметки по UUID
-type GetNoteRequest struct {
+// Билет стрима
+type StreamTicket struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"` // UUID заметки
+	Ticket        string                 `protobuf:"bytes,1,opt,name=ticket,proto3" json:"ticket,omitempty"`                        // Значение параметра ticket в URL стрима
+	ExpiresAt     *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"` // Время, до которого билет можно использовать
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetNoteRequest) Reset() {
-	*x = GetNoteRequest{}
-	mi := &file_proto_notes_v1_notes_proto_msgTypes[2]
+func (x *StreamTicket) Reset() {
+	*x = StreamTicket{}
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[148]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetNoteRequest) String() string {
+func (x *StreamTicket) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetNoteRequest) ProtoMessage() {}
+func (*StreamTicket) ProtoMessage() {}
 
-func (x *GetNoteRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_notes_v1_notes_proto_msgTypes[2]
+func (x *StreamTicket) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[148]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -211,41 +9854,53 @@ func (x *GetNoteRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetNoteRequest.ProtoReflect.Descriptor instead.
-func (*GetNoteRequest) Descriptor() ([]byte, []int) {
-	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{2}
+// Deprecated: Use StreamTicket.ProtoReflect.Descriptor instead.
+func (*StreamTicket) Descriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{148}
 }
 
-func (x *GetNoteRequest) GetId() string {
+func (x *StreamTicket) GetTicket() string {
 	if x != nil {
-		return x.Id
+		return x.Ticket
 	}
 	return ""
 }
 
-// Ответ с заметкой
-type GetNoteResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Note          *Note                  `protobuf:"bytes,1,opt,name=note,proto3" json:"note,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+func (x *StreamTicket) GetExpiresAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return nil
 }
 
-func (x *GetNoteResponse) Reset() {
-	*x = GetNoteResponse{}
-	mi := &file_proto_notes_v1_notes_proto_msgTypes[3]
+// Токены сессии
+type AuthTokens struct {
+	state                 protoimpl.MessageState `protogen:"open.v1"`
+	AccessToken           string                 `protobuf:"bytes,1,opt,name=access_token,json=accessToken,proto3" json:"access_token,omitempty"`                                   // Токен для заголовка Authorization: Bearer
+	AccessTokenExpiresAt  *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=access_token_expires_at,json=accessTokenExpiresAt,proto3" json:"access_token_expires_at,omitempty"`    // Время истечения access токена
+	RefreshToken          string                 `protobuf:"bytes,3,opt,name=refresh_token,json=refreshToken,proto3" json:"refresh_token,omitempty"`                                // Токен для RefreshToken (одноразовый)
+	RefreshTokenExpiresAt *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=refresh_token_expires_at,json=refreshTokenExpiresAt,proto3" json:"refresh_token_expires_at,omitempty"` // Время истечения сессии
+	TokenType             string                 `protobuf:"bytes,5,opt,name=token_type,json=tokenType,proto3" json:"token_type,omitempty"`                                         // Тип токена (Bearer)
+	UserId                string                 `protobuf:"bytes,6,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`                                                  // Пользователь сессии
+	unknownFields         protoimpl.UnknownFields
+	sizeCache             protoimpl.SizeCache
+}
+
+func (x *AuthTokens) Reset() {
+	*x = AuthTokens{}
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[149]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetNoteResponse) String() string {
+func (x *AuthTokens) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetNoteResponse) ProtoMessage() {}
+func (*AuthTokens) ProtoMessage() {}
 
-func (x *GetNoteResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_notes_v1_notes_proto_msgTypes[3]
+func (x *AuthTokens) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[149]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -256,40 +9911,80 @@ func (x *GetNoteResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetNoteResponse.ProtoReflect.Descriptor instead.
-func (*GetNoteResponse) Descriptor() ([]byte, []int) {
-	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{3}
+// Deprecated: Use AuthTokens.ProtoReflect.Descriptor instead.
+func (*AuthTokens) Descriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{149}
 }
 
-func (x *GetNoteResponse) GetNote() *Note {
+func (x *AuthTokens) GetAccessToken() string {
 	if x != nil {
-		return x.Note
+		return x.AccessToken
+	}
+	return ""
+}
+
+func (x *AuthTokens) GetAccessTokenExpiresAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.AccessTokenExpiresAt
 	}
 	return nil
 }
 
-// Запрос на получение списка заметок
-type ListNotesRequest struct {
+func (x *AuthTokens) GetRefreshToken() string {
+	if x != nil {
+		return x.RefreshToken
+	}
+	return ""
+}
+
+func (x *AuthTokens) GetRefreshTokenExpiresAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.RefreshTokenExpiresAt
+	}
+	return nil
+}
+
+func (x *AuthTokens) GetTokenType() string {
+	if x != nil {
+		return x.TokenType
+	}
+	return ""
+}
+
+func (x *AuthTokens) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+// Пользователь сервиса
+type User struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`                                       // Идентификатор пользователя (владельца заметок)
+	Username      string                 `protobuf:"bytes,2,opt,name=username,proto3" json:"username,omitempty"`                           // Имя для входа (пусто, если пользователь не входит по паролю)
+	Roles         []string               `protobuf:"bytes,3,rep,name=roles,proto3" json:"roles,omitempty"`                                 // Роли пользователя
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`        // Время создания
+	HasPassword   bool                   `protobuf:"varint,5,opt,name=has_password,json=hasPassword,proto3" json:"has_password,omitempty"` // Разрешен ли вход по паролю
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ListNotesRequest) Reset() {
-	*x = ListNotesRequest{}
-	mi := &file_proto_notes_v1_notes_proto_msgTypes[4]
+func (x *User) Reset() {
+	*x = User{}
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[150]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListNotesRequest) String() string {
+func (x *User) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListNotesRequest) ProtoMessage() {}
+func (*User) ProtoMessage() {}
 
-func (x *ListNotesRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_notes_v1_notes_proto_msgTypes[4]
+func (x *User) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[150]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -300,34 +9995,72 @@ func (x *ListNotesRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListNotesRequest.ProtoReflect.Descriptor instead.
-func (*ListNotesRequest) Descriptor() ([]byte, []int) {
-	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{4}
+// Deprecated: Use User.ProtoReflect.Descriptor instead.
+func (*User) Descriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{150}
 }
 
-// Ответ со списком заметок
-type ListNotesResponse struct {
+func (x *User) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *User) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *User) GetRoles() []string {
+	if x != nil {
+		return x.Roles
+	}
+	return nil
+}
+
+func (x *User) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *User) GetHasPassword() bool {
+	if x != nil {
+		return x.HasPassword
+	}
+	return false
+}
+
+// Запрос создания пользователя
+type CreateUserRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Notes         []*Note                `protobuf:"bytes,1,rep,name=notes,proto3" json:"notes,omitempty"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`             // ID пользователя (по умолчанию username)
+	Username      string                 `protobuf:"bytes,2,opt,name=username,proto3" json:"username,omitempty"` // Имя для входа (опционально)
+	Password      string                 `protobuf:"bytes,3,opt,name=password,proto3" json:"password,omitempty"` // Пароль (обязателен вместе с username)
+	Roles         []string               `protobuf:"bytes,4,rep,name=roles,proto3" json:"roles,omitempty"`       // Роли (user добавляется всегда)
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ListNotesResponse) Reset() {
-	*x = ListNotesResponse{}
-	mi := &file_proto_notes_v1_notes_proto_msgTypes[5]
+func (x *CreateUserRequest) Reset() {
+	*x = CreateUserRequest{}
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[151]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListNotesResponse) String() string {
+func (x *CreateUserRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListNotesResponse) ProtoMessage() {}
+func (*CreateUserRequest) ProtoMessage() {}
 
-func (x *ListNotesResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_notes_v1_notes_proto_msgTypes[5]
+func (x *CreateUserRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[151]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -338,43 +10071,62 @@ func (x *ListNotesResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListNotesResponse.ProtoReflect.Descriptor instead.
-func (*ListNotesResponse) Descriptor() ([]byte, []int) {
-	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{5}
+// Deprecated: Use CreateUserRequest.ProtoReflect.Descriptor instead.
+func (*CreateUserRequest) Descriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{151}
 }
 
-func (x *ListNotesResponse) GetNotes() []*Note {
+func (x *CreateUserRequest) GetId() string {
 	if x != nil {
-		return x.Notes
+		return x.Id
+	}
+	return ""
+}
+
+func (x *CreateUserRequest) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *CreateUserRequest) GetPassword() string {
+	if x != nil {
+		return x.Password
+	}
+	return ""
+}
+
+func (x *CreateUserRequest) GetRoles() []string {
+	if x != nil {
+		return x.Roles
 	}
 	return nil
 }
 
-// Запрос на обновление заметки
-type UpdateNoteRequest struct {
+// Запрос пользователя по ID
+type GetUserRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`           // UUID заметки
-	Title         string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`     // Новый заголовок (опционально)
-	Content       string                 `protobuf:"bytes,3,opt,name=content,proto3" json:"content,omitempty"` // Новое содержание (опционально)
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"` // ID пользователя
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *UpdateNoteRequest) Reset() {
-	*x = UpdateNoteRequest{}
-	mi := &file_proto_notes_v1_notes_proto_msgTypes[6]
+func (x *GetUserRequest) Reset() {
+	*x = GetUserRequest{}
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[152]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *UpdateNoteRequest) String() string {
+func (x *GetUserRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*UpdateNoteRequest) ProtoMessage() {}
+func (*GetUserRequest) ProtoMessage() {}
 
-func (x *UpdateNoteRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_notes_v1_notes_proto_msgTypes[6]
+func (x *GetUserRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[152]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -385,55 +10137,78 @@ func (x *UpdateNoteRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use UpdateNoteRequest.ProtoReflect.Descriptor instead.
-func (*UpdateNoteRequest) Descriptor() ([]byte, []int) {
-	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{6}
+// Deprecated: Use GetUserRequest.ProtoReflect.Descriptor instead.
+func (*GetUserRequest) Descriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{152}
 }
 
-func (x *UpdateNoteRequest) GetId() string {
+func (x *GetUserRequest) GetId() string {
 	if x != nil {
 		return x.Id
 	}
 	return ""
 }
 
-func (x *UpdateNoteRequest) GetTitle() string {
-	if x != nil {
-		return x.Title
-	}
-	return ""
+// Запрос списка пользователей
+type ListUsersRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *UpdateNoteRequest) GetContent() string {
+func (x *ListUsersRequest) Reset() {
+	*x = ListUsersRequest{}
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[153]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListUsersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListUsersRequest) ProtoMessage() {}
+
+func (x *ListUsersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[153]
 	if x != nil {
-		return x.Content
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return ""
+	return mi.MessageOf(x)
 }
 
-// Ответ с обновленной заметкой
-type UpdateNoteResponse struct {
+// Deprecated: Use ListUsersRequest.ProtoReflect.Descriptor instead.
+func (*ListUsersRequest) Descriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{153}
+}
+
+// Список пользователей
+type ListUsersResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Note          *Note                  `protobuf:"bytes,1,opt,name=note,proto3" json:"note,omitempty"`
+	Users         []*User                `protobuf:"bytes,1,rep,name=users,proto3" json:"users,omitempty"` // Пользователи, упорядоченные по ID
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *UpdateNoteResponse) Reset() {
-	*x = UpdateNoteResponse{}
-	mi := &file_proto_notes_v1_notes_proto_msgTypes[7]
+func (x *ListUsersResponse) Reset() {
+	*x = ListUsersResponse{}
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[154]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *UpdateNoteResponse) String() string {
+func (x *ListUsersResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*UpdateNoteResponse) ProtoMessage() {}
+func (*ListUsersResponse) ProtoMessage() {}
 
-func (x *UpdateNoteResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_notes_v1_notes_proto_msgTypes[7]
+func (x *ListUsersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[154]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -444,41 +10219,50 @@ func (x *UpdateNoteResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use UpdateNoteResponse.ProtoReflect.Descriptor instead.
-func (*UpdateNoteResponse) Descriptor() ([]byte, []int) {
-	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{7}
+// Deprecated: Use ListUsersResponse.ProtoReflect.Descriptor instead.
+func (*ListUsersResponse) Descriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{154}
 }
 
-func (x *UpdateNoteResponse) GetNote() *Note {
+func (x *ListUsersResponse) GetUsers() []*User {
 	if x != nil {
-		return x.Note
+		return x.Users
 	}
 	return nil
 }
 
-// Запрос на удаление заметки
-type DeleteNoteRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"` // UUID заметки
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
-}
-
-func (x *DeleteNoteRequest) Reset() {
-	*x = DeleteNoteRequest{}
-	mi := &file_proto_notes_v1_notes_proto_msgTypes[8]
+// Ключ API (без секрета)
+type APIKey struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Id             string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`                                                   // Открытая часть ключа
+	Name           string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`                                               // Описание ключа
+	UserId         string                 `protobuf:"bytes,3,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`                             // Пользователь, от имени которого выполняются запросы
+	Roles          []string               `protobuf:"bytes,4,rep,name=roles,proto3" json:"roles,omitempty"`                                             // Роли запросов с ключом
+	CreatedBy      string                 `protobuf:"bytes,5,opt,name=created_by,json=createdBy,proto3" json:"created_by,omitempty"`                    // Администратор, создавший ключ
+	CreatedAt      *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`                    // Время создания
+	ExpiresAt      *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`                    // Время истечения (не задано у бессрочного ключа)
+	RevokedAt      *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=revoked_at,json=revokedAt,proto3" json:"revoked_at,omitempty"`                    // Время отзыва (не задано у действующего ключа)
+	RateLimitRps   float64                `protobuf:"fixed64,9,opt,name=rate_limit_rps,json=rateLimitRps,proto3" json:"rate_limit_rps,omitempty"`       // Лимит запросов в секунду (0 - по умолчанию из auth.api_keys)
+	RateLimitBurst int32                  `protobuf:"varint,10,opt,name=rate_limit_burst,json=rateLimitBurst,proto3" json:"rate_limit_burst,omitempty"` // Размер бюджета запросов (0 - по умолчанию)
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *APIKey) Reset() {
+	*x = APIKey{}
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[155]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *DeleteNoteRequest) String() string {
+func (x *APIKey) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DeleteNoteRequest) ProtoMessage() {}
+func (*APIKey) ProtoMessage() {}
 
-func (x *DeleteNoteRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_notes_v1_notes_proto_msgTypes[8]
+func (x *APIKey) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[155]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -489,82 +10273,109 @@ func (x *DeleteNoteRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DeleteNoteRequest.ProtoReflect.Descriptor instead.
-func (*DeleteNoteRequest) Descriptor() ([]byte, []int) {
-	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{8}
+// Deprecated: Use APIKey.ProtoReflect.Descriptor instead.
+func (*APIKey) Descriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{155}
 }
 
-func (x *DeleteNoteRequest) GetId() string {
+func (x *APIKey) GetId() string {
 	if x != nil {
 		return x.Id
 	}
 	return ""
 }
 
-// Ответ на удаление заметки
-type DeleteNoteResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+func (x *APIKey) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
 }
 
-func (x *DeleteNoteResponse) Reset() {
-	*x = DeleteNoteResponse{}
-	mi := &file_proto_notes_v1_notes_proto_msgTypes[9]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
+func (x *APIKey) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
 }
 
-func (x *DeleteNoteResponse) String() string {
-	return protoimpl.X.MessageStringOf(x)
+func (x *APIKey) GetRoles() []string {
+	if x != nil {
+		return x.Roles
+	}
+	return nil
 }
 
-func (*DeleteNoteResponse) ProtoMessage() {}
+func (x *APIKey) GetCreatedBy() string {
+	if x != nil {
+		return x.CreatedBy
+	}
+	return ""
+}
+
+func (x *APIKey) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *APIKey) GetExpiresAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return nil
+}
+
+func (x *APIKey) GetRevokedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.RevokedAt
+	}
+	return nil
+}
 
-func (x *DeleteNoteResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_notes_v1_notes_proto_msgTypes[9]
+func (x *APIKey) GetRateLimitRps() float64 {
 	if x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
+		return x.RateLimitRps
 	}
-	return mi.MessageOf(x)
+	return 0
 }
 
-// Deprecated: Use DeleteNoteResponse.ProtoReflect.Descriptor instead.
-func (*DeleteNoteResponse) Descriptor() ([]byte, []int) {
-	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{9}
+func (x *APIKey) GetRateLimitBurst() int32 {
+	if x != nil {
+		return x.RateLimitBurst
+	}
+	return 0
 }
 
-// Note представляет заметку
-type Note struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`                                // UUID заметки
-	Title         string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`                          // Заголовок заметки
-	Content       string                 `protobuf:"bytes,3,opt,name=content,proto3" json:"content,omitempty"`                      // Содержание заметки
-	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"` // Дата создания
-	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"` // Дата последнего обновления
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+// Запрос создания ключа API
+type CreateAPIKeyRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Name           string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`                                              // Описание ключа
+	UserId         string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`                            // Пользователь ключа
+	Roles          []string               `protobuf:"bytes,3,rep,name=roles,proto3" json:"roles,omitempty"`                                            // Роли (user добавляется всегда)
+	TtlSeconds     int64                  `protobuf:"varint,4,opt,name=ttl_seconds,json=ttlSeconds,proto3" json:"ttl_seconds,omitempty"`               // Время действия ключа (0 - бессрочный)
+	RateLimitRps   float64                `protobuf:"fixed64,5,opt,name=rate_limit_rps,json=rateLimitRps,proto3" json:"rate_limit_rps,omitempty"`      // Лимит запросов в секунду (0 - по умолчанию)
+	RateLimitBurst int32                  `protobuf:"varint,6,opt,name=rate_limit_burst,json=rateLimitBurst,proto3" json:"rate_limit_burst,omitempty"` // Размер бюджета запросов (0 - по умолчанию)
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
 }
 
-func (x *Note) Reset() {
-	*x = Note{}
-	mi := &file_proto_notes_v1_notes_proto_msgTypes[10]
+func (x *CreateAPIKeyRequest) Reset() {
+	*x = CreateAPIKeyRequest{}
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[156]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *Note) String() string {
+func (x *CreateAPIKeyRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Note) ProtoMessage() {}
+func (*CreateAPIKeyRequest) ProtoMessage() {}
 
-func (x *Note) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_notes_v1_notes_proto_msgTypes[10]
+func (x *CreateAPIKeyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[156]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -575,71 +10386,77 @@ func (x *Note) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Note.ProtoReflect.Descriptor instead.
-func (*Note) Descriptor() ([]byte, []int) {
-	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{10}
+// Deprecated: Use CreateAPIKeyRequest.ProtoReflect.Descriptor instead.
+func (*CreateAPIKeyRequest) Descriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{156}
 }
 
-func (x *Note) GetId() string {
+func (x *CreateAPIKeyRequest) GetName() string {
 	if x != nil {
-		return x.Id
+		return x.Name
 	}
 	return ""
 }
 
-func (x *Note) GetTitle() string {
+func (x *CreateAPIKeyRequest) GetUserId() string {
 	if x != nil {
-		return x.Title
+		return x.UserId
 	}
 	return ""
 }
 
-func (x *Note) GetContent() string {
+func (x *CreateAPIKeyRequest) GetRoles() []string {
 	if x != nil {
-		return x.Content
+		return x.Roles
 	}
-	return ""
+	return nil
 }
 
-func (x *Note) GetCreatedAt() *timestamppb.Timestamp {
+func (x *CreateAPIKeyRequest) GetTtlSeconds() int64 {
 	if x != nil {
-		return x.CreatedAt
+		return x.TtlSeconds
 	}
-	return nil
+	return 0
 }
 
-func (x *Note) GetUpdatedAt() *timestamppb.Timestamp {
+func (x *CreateAPIKeyRequest) GetRateLimitRps() float64 {
 	if x != nil {
-		return x.UpdatedAt
+		return x.RateLimitRps
 	}
-	return nil
+	return 0
 }
 
-// ErrorDetails содержит детальную информацию об ошибке
-type ErrorDetails struct {
-	state             protoimpl.MessageState `protogen:"open.v1"`
-	Reason            string                 `protobuf:"bytes,1,opt,name=reason,proto3" json:"reason,omitempty"`                                                  // Причина ошибки
-	InternalErrorCode string                 `protobuf:"bytes,2,opt,name=internal_error_code,json=internalErrorCode,proto3" json:"internal_error_code,omitempty"` // Внутренний код ошибки
-	NoteId            string                 `protobuf:"bytes,3,opt,name=note_id,json=noteId,proto3" json:"note_id,omitempty"`                                    // ID заметки, связанной с ошибкой
-	unknownFields     protoimpl.UnknownFields
-	sizeCache         protoimpl.SizeCache
+func (x *CreateAPIKeyRequest) GetRateLimitBurst() int32 {
+	if x != nil {
+		return x.RateLimitBurst
+	}
+	return 0
 }
 
-func (x *ErrorDetails) Reset() {
-	*x = ErrorDetails{}
-	mi := &file_proto_notes_v1_notes_proto_msgTypes[11]
+// Созданный ключ API
+type CreateAPIKeyResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ApiKey        *APIKey                `protobuf:"bytes,1,opt,name=api_key,json=apiKey,proto3" json:"api_key,omitempty"` // Ключ
+	Key           string                 `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`                     // Значение ключа для Authorization: Bearer или x-api-key (возвращается один раз)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateAPIKeyResponse) Reset() {
+	*x = CreateAPIKeyResponse{}
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[157]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ErrorDetails) String() string {
+func (x *CreateAPIKeyResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ErrorDetails) ProtoMessage() {}
+func (*CreateAPIKeyResponse) ProtoMessage() {}
 
-func (x *ErrorDetails) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_notes_v1_notes_proto_msgTypes[11]
+func (x *CreateAPIKeyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[157]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -650,54 +10467,48 @@ func (x *ErrorDetails) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ErrorDetails.ProtoReflect.Descriptor instead.
-func (*ErrorDetails) Descriptor() ([]byte, []int) {
-	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{11}
-}
-
-func (x *ErrorDetails) GetReason() string {
-	if x != nil {
-		return x.Reason
-	}
-	return ""
+// Deprecated: Use CreateAPIKeyResponse.ProtoReflect.Descriptor instead.
+func (*CreateAPIKeyResponse) Descriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{157}
 }
 
-func (x *ErrorDetails) GetInternalErrorCode() string {
+func (x *CreateAPIKeyResponse) GetApiKey() *APIKey {
 	if x != nil {
-		return x.InternalErrorCode
+		return x.ApiKey
 	}
-	return ""
+	return nil
 }
 
-func (x *ErrorDetails) GetNoteId() string {
+func (x *CreateAPIKeyResponse) GetKey() string {
 	if x != nil {
-		return x.NoteId
+		return x.Key
 	}
 	return ""
 }
 
-// Запрос на подписку на события
-type SubscribeToEventsRequest struct {
+// Запрос отзыва ключа API
+type RevokeAPIKeyRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"` // Открытая часть ключа
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *SubscribeToEventsRequest) Reset() {
-	*x = SubscribeToEventsRequest{}
-	mi := &file_proto_notes_v1_notes_proto_msgTypes[12]
+func (x *RevokeAPIKeyRequest) Reset() {
+	*x = RevokeAPIKeyRequest{}
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[158]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *SubscribeToEventsRequest) String() string {
+func (x *RevokeAPIKeyRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SubscribeToEventsRequest) ProtoMessage() {}
+func (*RevokeAPIKeyRequest) ProtoMessage() {}
 
-func (x *SubscribeToEventsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_notes_v1_notes_proto_msgTypes[12]
+func (x *RevokeAPIKeyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[158]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -708,38 +10519,40 @@ func (x *SubscribeToEventsRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SubscribeToEventsRequest.ProtoReflect.Descriptor instead.
-func (*SubscribeToEventsRequest) Descriptor() ([]byte, []int) {
-	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{12}
+// Deprecated: Use RevokeAPIKeyRequest.ProtoReflect.Descriptor instead.
+func (*RevokeAPIKeyRequest) Descriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{158}
 }
 
-// Ответ со стримом событий
-type EventResponse struct {
-	state protoimpl.MessageState `protogen:"open.v1"`
-	// Types that are valid to be assigned to Event:
-	//
-	//	*EventResponse_HealthCheck
-	//	*EventResponse_NoteCreated
-	Event         isEventResponse_Event `protobuf_oneof:"event"`
+func (x *RevokeAPIKeyRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+// Запрос списка ключей API
+type ListAPIKeysRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *EventResponse) Reset() {
-	*x = EventResponse{}
-	mi := &file_proto_notes_v1_notes_proto_msgTypes[13]
+func (x *ListAPIKeysRequest) Reset() {
+	*x = ListAPIKeysRequest{}
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[159]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *EventResponse) String() string {
+func (x *ListAPIKeysRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*EventResponse) ProtoMessage() {}
+func (*ListAPIKeysRequest) ProtoMessage() {}
 
-func (x *EventResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_notes_v1_notes_proto_msgTypes[13]
+func (x *ListAPIKeysRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[159]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -750,78 +10563,78 @@ func (x *EventResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use EventResponse.ProtoReflect.Descriptor instead.
-func (*EventResponse) Descriptor() ([]byte, []int) {
-	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{13}
+// Deprecated: Use ListAPIKeysRequest.ProtoReflect.Descriptor instead.
+func (*ListAPIKeysRequest) Descriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{159}
 }
 
-func (x *EventResponse) GetEvent() isEventResponse_Event {
-	if x != nil {
-		return x.Event
-	}
-	return nil
+// Список ключей API
+type ListAPIKeysResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ApiKeys       []*APIKey              `protobuf:"bytes,1,rep,name=api_keys,json=apiKeys,proto3" json:"api_keys,omitempty"` // Ключи в порядке создания
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *EventResponse) GetHealthCheck() *HealthCheck {
-	if x != nil {
-		if x, ok := x.Event.(*EventResponse_HealthCheck); ok {
-			return x.HealthCheck
-		}
-	}
-	return nil
+func (x *ListAPIKeysResponse) Reset() {
+	*x = ListAPIKeysResponse{}
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[160]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
-func (x *EventResponse) GetNoteCreated() *NoteCreatedEvent {
+func (x *ListAPIKeysResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAPIKeysResponse) ProtoMessage() {}
+
+func (x *ListAPIKeysResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[160]
 	if x != nil {
-		if x, ok := x.Event.(*EventResponse_NoteCreated); ok {
-			return x.NoteCreated
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
 		}
+		return ms
 	}
-	return nil
-}
-
-type isEventResponse_Event interface {
-	isEventResponse_Event()
+	return mi.MessageOf(x)
 }
 
-type EventResponse_HealthCheck struct {
-	// Приветственное сообщение или health-check
-	HealthCheck *HealthCheck `protobuf:"bytes,1,opt,name=health_check,json=healthCheck,proto3,oneof"`
+// Deprecated: Use ListAPIKeysResponse.ProtoReflect.Descriptor instead.
+func (*ListAPIKeysResponse) Descriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{160}
 }
 
-type EventResponse_NoteCreated struct {
-	// Событие создания новой заметки
-	NoteCreated *NoteCreatedEvent `protobuf:"bytes,2,opt,name=note_created,json=noteCreated,proto3,oneof"`
+func (x *ListAPIKeysResponse) GetApiKeys() []*APIKey {
+	if x != nil {
+		return x.ApiKeys
+	}
+	return nil
 }
 
-func (*EventResponse_HealthCheck) isEventResponse_Event() {}
-
-func (*EventResponse_NoteCreated) isEventResponse_Event() {}
-
-// HealthCheck сообщение для поддержания соединения
-type HealthCheck struct {
+// Запрос цепочек интерцепторов и middleware
+type GetPipelineRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Message       string                 `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`     // Сообщение health-check
-	Timestamp     *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=timestamp,proto3" json:"timestamp,omitempty"` // Временная метка
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *HealthCheck) Reset() {
-	*x = HealthCheck{}
-	mi := &file_proto_notes_v1_notes_proto_msgTypes[14]
+func (x *GetPipelineRequest) Reset() {
+	*x = GetPipelineRequest{}
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[161]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *HealthCheck) String() string {
+func (x *GetPipelineRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*HealthCheck) ProtoMessage() {}
+func (*GetPipelineRequest) ProtoMessage() {}
 
-func (x *HealthCheck) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_notes_v1_notes_proto_msgTypes[14]
+func (x *GetPipelineRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[161]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -832,53 +10645,35 @@ func (x *HealthCheck) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use HealthCheck.ProtoReflect.Descriptor instead.
-func (*HealthCheck) Descriptor() ([]byte, []int) {
-	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{14}
-}
-
-func (x *HealthCheck) GetMessage() string {
-	if x != nil {
-		return x.Message
-	}
-	return ""
-}
-
-func (x *HealthCheck) GetTimestamp() *timestamppb.Timestamp {
-	if x != nil {
-		return x.Timestamp
-	}
-	return nil
+// Deprecated: Use GetPipelineRequest.ProtoReflect.Descriptor instead.
+func (*GetPipelineRequest) Descriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{161}
 }
 
-// Событие создания новой заметки
-// Подробности об использовании oneof: см. README.md раздел "NoteCreatedEvent: oneof"
-type NoteCreatedEvent struct {
-	state protoimpl.MessageState `protogen:"open.v1"`
-	// Types that are valid to be assigned to Payload:
-	//
-	//	*NoteCreatedEvent_NoteId
-	//	*NoteCreatedEvent_Note
-	Payload       isNoteCreatedEvent_Payload `protobuf_oneof:"payload"`
+// Звено цепочки: интерцептор или middleware
+type PipelineStage struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`                                                                                   // Имя звена (auth, policy, cors, ...)
+	Settings      map[string]string      `protobuf:"bytes,2,rep,name=settings,proto3" json:"settings,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // Настройки звена из конфигурации и proto
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *NoteCreatedEvent) Reset() {
-	*x = NoteCreatedEvent{}
-	mi := &file_proto_notes_v1_notes_proto_msgTypes[15]
+func (x *PipelineStage) Reset() {
+	*x = PipelineStage{}
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[162]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *NoteCreatedEvent) String() string {
+func (x *PipelineStage) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*NoteCreatedEvent) ProtoMessage() {}
+func (*PipelineStage) ProtoMessage() {}
 
-func (x *NoteCreatedEvent) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_notes_v1_notes_proto_msgTypes[15]
+func (x *PipelineStage) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[162]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -889,76 +10684,49 @@ func (x *NoteCreatedEvent) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use NoteCreatedEvent.ProtoReflect.Descriptor instead.
-func (*NoteCreatedEvent) Descriptor() ([]byte, []int) {
-	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{15}
-}
-
-func (x *NoteCreatedEvent) GetPayload() isNoteCreatedEvent_Payload {
-	if x != nil {
-		return x.Payload
-	}
-	return nil
+// Deprecated: Use PipelineStage.ProtoReflect.Descriptor instead.
+func (*PipelineStage) Descriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{162}
 }
 
-func (x *NoteCreatedEvent) GetNoteId() string {
+func (x *PipelineStage) GetName() string {
 	if x != nil {
-		if x, ok := x.Payload.(*NoteCreatedEvent_NoteId); ok {
-			return x.NoteId
-		}
+		return x.Name
 	}
 	return ""
 }
 
-func (x *NoteCreatedEvent) GetNote() *Note {
+func (x *PipelineStage) GetSettings() map[string]string {
 	if x != nil {
-		if x, ok := x.Payload.(*NoteCreatedEvent_Note); ok {
-			return x.Note
-		}
+		return x.Settings
 	}
 	return nil
 }
 
-type isNoteCreatedEvent_Payload interface {
-	isNoteCreatedEvent_Payload()
-}
-
-type NoteCreatedEvent_NoteId struct {
-	NoteId string `protobuf:"bytes,1,opt,name=note_id,json=noteId,proto3,oneof"` // ID созданной заметки (легковесный вариант)
-}
-
-type NoteCreatedEvent_Note struct {
-	Note *Note `protobuf:"bytes,2,opt,name=note,proto3,oneof"` // Полная заметка (подробный вариант)
-}
-
-func (*NoteCreatedEvent_NoteId) isNoteCreatedEvent_Payload() {}
-
-func (*NoteCreatedEvent_Note) isNoteCreatedEvent_Payload() {}
-
-// Запрос на загрузку метрики (клиентский стриминг)
-type MetricRequest struct {
+// Цепочка в порядке выполнения
+type PipelineChain struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Value         float64                `protobuf:"fixed64,1,opt,name=value,proto3" json:"value,omitempty"` // Значение метрики
-	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`     // Опционально: название метрики
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`     // grpc_unary, grpc_stream или http
+	Stages        []*PipelineStage       `protobuf:"bytes,2,rep,name=stages,proto3" json:"stages,omitempty"` // Звенья от внешнего к внутреннему
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *MetricRequest) Reset() {
-	*x = MetricRequest{}
-	mi := &file_proto_notes_v1_notes_proto_msgTypes[16]
+func (x *PipelineChain) Reset() {
+	*x = PipelineChain{}
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[163]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *MetricRequest) String() string {
+func (x *PipelineChain) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*MetricRequest) ProtoMessage() {}
+func (*PipelineChain) ProtoMessage() {}
 
-func (x *MetricRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_notes_v1_notes_proto_msgTypes[16]
+func (x *PipelineChain) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[163]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -969,50 +10737,48 @@ func (x *MetricRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use MetricRequest.ProtoReflect.Descriptor instead.
-func (*MetricRequest) Descriptor() ([]byte, []int) {
-	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{16}
+// Deprecated: Use PipelineChain.ProtoReflect.Descriptor instead.
+func (*PipelineChain) Descriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{163}
 }
 
-func (x *MetricRequest) GetValue() float64 {
+func (x *PipelineChain) GetName() string {
 	if x != nil {
-		return x.Value
+		return x.Name
 	}
-	return 0
+	return ""
 }
 
-func (x *MetricRequest) GetName() string {
+func (x *PipelineChain) GetStages() []*PipelineStage {
 	if x != nil {
-		return x.Name
+		return x.Stages
 	}
-	return ""
+	return nil
 }
 
-// Ответ со статистикой по метрикам
-type SummaryResponse struct {
+// Действующие цепочки сервера
+type GetPipelineResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Sum           float64                `protobuf:"fixed64,1,opt,name=sum,proto3" json:"sum,omitempty"`         // Сумма всех метрик
-	Average       float64                `protobuf:"fixed64,2,opt,name=average,proto3" json:"average,omitempty"` // Среднее значение
-	Count         int64                  `protobuf:"varint,3,opt,name=count,proto3" json:"count,omitempty"`      // Количество метрик
+	Chains        []*PipelineChain       `protobuf:"bytes,1,rep,name=chains,proto3" json:"chains,omitempty"` // Цепочки в порядке имен
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *SummaryResponse) Reset() {
-	*x = SummaryResponse{}
-	mi := &file_proto_notes_v1_notes_proto_msgTypes[17]
+func (x *GetPipelineResponse) Reset() {
+	*x = GetPipelineResponse{}
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[164]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *SummaryResponse) String() string {
+func (x *GetPipelineResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SummaryResponse) ProtoMessage() {}
+func (*GetPipelineResponse) ProtoMessage() {}
 
-func (x *SummaryResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_notes_v1_notes_proto_msgTypes[17]
+func (x *GetPipelineResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[164]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1023,60 +10789,49 @@ func (x *SummaryResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SummaryResponse.ProtoReflect.Descriptor instead.
-func (*SummaryResponse) Descriptor() ([]byte, []int) {
-	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{17}
-}
-
-func (x *SummaryResponse) GetSum() float64 {
-	if x != nil {
-		return x.Sum
-	}
-	return 0
-}
-
-func (x *SummaryResponse) GetAverage() float64 {
-	if x != nil {
-		return x.Average
-	}
-	return 0
+// Deprecated: Use GetPipelineResponse.ProtoReflect.Descriptor instead.
+func (*GetPipelineResponse) Descriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{164}
 }
 
-func (x *SummaryResponse) GetCount() int64 {
+func (x *GetPipelineResponse) GetChains() []*PipelineChain {
 	if x != nil {
-		return x.Count
+		return x.Chains
 	}
-	return 0
+	return nil
 }
 
-// Сообщение в чате (bidirectional streaming)
-type ChatMessage struct {
+// Запись журнала аудита
+type AuditEvent struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	CorrelationId string                 `protobuf:"bytes,1,opt,name=correlation_id,json=correlationId,proto3" json:"correlation_id,omitempty"` // ID для корреляции запросов и ответов
-	// Types that are valid to be assigned to Content:
-	//
-	//	*ChatMessage_TextMessage
-	//	*ChatMessage_Error
-	Content       isChatMessage_Content `protobuf_oneof:"content"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`                                          // Порядковый номер записи
+	Time          *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=time,proto3" json:"time,omitempty"`                                       // Время операции
+	Action        string                 `protobuf:"bytes,3,opt,name=action,proto3" json:"action,omitempty"`                                   // Операция: create, update, delete, share, unshare, get, revisions, set_passphrase, remove_passphrase
+	Method        string                 `protobuf:"bytes,4,opt,name=method,proto3" json:"method,omitempty"`                                   // Полное имя gRPC метода (пусто для проверок парольной фразы)
+	NoteId        string                 `protobuf:"bytes,5,opt,name=note_id,json=noteId,proto3" json:"note_id,omitempty"`                     // UUID заметки (пусто, если заметка не была создана)
+	UserId        string                 `protobuf:"bytes,6,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`                     // Вызывающий пользователь
+	TargetUserId  string                 `protobuf:"bytes,7,opt,name=target_user_id,json=targetUserId,proto3" json:"target_user_id,omitempty"` // Пользователь, которому предоставлен или у которого отозван доступ
+	RequestId     string                 `protobuf:"bytes,8,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`            // Идентификатор запроса (x-request-id)
+	Outcome       string                 `protobuf:"bytes,9,opt,name=outcome,proto3" json:"outcome,omitempty"`                                 // Код статуса gRPC (OK, NotFound, ...) или результат проверки парольной фразы
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ChatMessage) Reset() {
-	*x = ChatMessage{}
-	mi := &file_proto_notes_v1_notes_proto_msgTypes[18]
+func (x *AuditEvent) Reset() {
+	*x = AuditEvent{}
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[165]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ChatMessage) String() string {
+func (x *AuditEvent) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ChatMessage) ProtoMessage() {}
+func (*AuditEvent) ProtoMessage() {}
 
-func (x *ChatMessage) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_notes_v1_notes_proto_msgTypes[18]
+func (x *AuditEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[165]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1087,85 +10842,103 @@ func (x *ChatMessage) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ChatMessage.ProtoReflect.Descriptor instead.
-func (*ChatMessage) Descriptor() ([]byte, []int) {
-	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{18}
+// Deprecated: Use AuditEvent.ProtoReflect.Descriptor instead.
+func (*AuditEvent) Descriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{165}
 }
 
-func (x *ChatMessage) GetCorrelationId() string {
+func (x *AuditEvent) GetId() int64 {
 	if x != nil {
-		return x.CorrelationId
+		return x.Id
 	}
-	return ""
+	return 0
 }
 
-func (x *ChatMessage) GetContent() isChatMessage_Content {
+func (x *AuditEvent) GetTime() *timestamppb.Timestamp {
 	if x != nil {
-		return x.Content
+		return x.Time
 	}
 	return nil
 }
 
-func (x *ChatMessage) GetTextMessage() *ChatTextMessage {
+func (x *AuditEvent) GetAction() string {
 	if x != nil {
-		if x, ok := x.Content.(*ChatMessage_TextMessage); ok {
-			return x.TextMessage
-		}
+		return x.Action
 	}
-	return nil
+	return ""
 }
 
-func (x *ChatMessage) GetError() *ChatError {
+func (x *AuditEvent) GetMethod() string {
 	if x != nil {
-		if x, ok := x.Content.(*ChatMessage_Error); ok {
-			return x.Error
-		}
+		return x.Method
 	}
-	return nil
+	return ""
 }
 
-type isChatMessage_Content interface {
-	isChatMessage_Content()
+func (x *AuditEvent) GetNoteId() string {
+	if x != nil {
+		return x.NoteId
+	}
+	return ""
 }
 
-type ChatMessage_TextMessage struct {
-	// Обычное текстовое сообщение
-	TextMessage *ChatTextMessage `protobuf:"bytes,2,opt,name=text_message,json=textMessage,proto3,oneof"`
+func (x *AuditEvent) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
 }
 
-type ChatMessage_Error struct {
-	// Бизнесовая ошибка (не разрывающая соединение)
-	Error *ChatError `protobuf:"bytes,3,opt,name=error,proto3,oneof"`
+func (x *AuditEvent) GetTargetUserId() string {
+	if x != nil {
+		return x.TargetUserId
+	}
+	return ""
 }
 
-func (*ChatMessage_TextMessage) isChatMessage_Content() {}
+func (x *AuditEvent) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
 
-func (*ChatMessage_Error) isChatMessage_Content() {}
+func (x *AuditEvent) GetOutcome() string {
+	if x != nil {
+		return x.Outcome
+	}
+	return ""
+}
 
-// Текстовое сообщение в чате
-type ChatTextMessage struct {
+// Запрос записей журнала аудита; пустые поля не ограничивают выборку
+type ListAuditEventsRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Text          string                 `protobuf:"bytes,1,opt,name=text,proto3" json:"text,omitempty"`           // Текст сообщения
-	Timestamp     *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=timestamp,proto3" json:"timestamp,omitempty"` // Временная метка сообщения
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`        // Вызывающий пользователь
+	NoteId        string                 `protobuf:"bytes,2,opt,name=note_id,json=noteId,proto3" json:"note_id,omitempty"`        // UUID заметки
+	Action        string                 `protobuf:"bytes,3,opt,name=action,proto3" json:"action,omitempty"`                      // Операция (см. AuditEvent.action)
+	From          *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=from,proto3" json:"from,omitempty"`                          // Записи не раньше from
+	To            *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=to,proto3" json:"to,omitempty"`                              // Записи раньше to
+	BeforeId      int64                  `protobuf:"varint,6,opt,name=before_id,json=beforeId,proto3" json:"before_id,omitempty"` // Записи с id меньше before_id (next_before_id предыдущего ответа)
+	Limit         int32                  `protobuf:"varint,7,opt,name=limit,proto3" json:"limit,omitempty"`                       // Количество записей (0 - 100)
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ChatTextMessage) Reset() {
-	*x = ChatTextMessage{}
-	mi := &file_proto_notes_v1_notes_proto_msgTypes[19]
+func (x *ListAuditEventsRequest) Reset() {
+	*x = ListAuditEventsRequest{}
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[166]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ChatTextMessage) String() string {
+func (x *ListAuditEventsRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ChatTextMessage) ProtoMessage() {}
+func (*ListAuditEventsRequest) ProtoMessage() {}
 
-func (x *ChatTextMessage) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_notes_v1_notes_proto_msgTypes[19]
+func (x *ListAuditEventsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[166]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1176,50 +10949,84 @@ func (x *ChatTextMessage) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ChatTextMessage.ProtoReflect.Descriptor instead.
-func (*ChatTextMessage) Descriptor() ([]byte, []int) {
-	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{19}
+// Deprecated: Use ListAuditEventsRequest.ProtoReflect.Descriptor instead.
+func (*ListAuditEventsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{166}
 }
 
-func (x *ChatTextMessage) GetText() string {
+func (x *ListAuditEventsRequest) GetUserId() string {
 	if x != nil {
-		return x.Text
+		return x.UserId
 	}
 	return ""
 }
 
-func (x *ChatTextMessage) GetTimestamp() *timestamppb.Timestamp {
+func (x *ListAuditEventsRequest) GetNoteId() string {
 	if x != nil {
-		return x.Timestamp
+		return x.NoteId
+	}
+	return ""
+}
+
+func (x *ListAuditEventsRequest) GetAction() string {
+	if x != nil {
+		return x.Action
+	}
+	return ""
+}
+
+func (x *ListAuditEventsRequest) GetFrom() *timestamppb.Timestamp {
+	if x != nil {
+		return x.From
 	}
 	return nil
 }
 
-// Ошибка в чате (бизнесовая, не разрывающая соединение)
-type ChatError struct {
+func (x *ListAuditEventsRequest) GetTo() *timestamppb.Timestamp {
+	if x != nil {
+		return x.To
+	}
+	return nil
+}
+
+func (x *ListAuditEventsRequest) GetBeforeId() int64 {
+	if x != nil {
+		return x.BeforeId
+	}
+	return 0
+}
+
+func (x *ListAuditEventsRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+// Записи журнала аудита, начиная с последней
+type ListAuditEventsResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Code          ChatErrorCode          `protobuf:"varint,1,opt,name=code,proto3,enum=notes.v1.ChatErrorCode" json:"code,omitempty"` // Детерминированный код ошибки (enum)
-	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`                        // Сообщение об ошибке
-	Details       string                 `protobuf:"bytes,3,opt,name=details,proto3" json:"details,omitempty"`                        // Дополнительные детали ошибки
+	Events        []*AuditEvent          `protobuf:"bytes,1,rep,name=events,proto3" json:"events,omitempty"`
+	NextBeforeId  int64                  `protobuf:"varint,2,opt,name=next_before_id,json=nextBeforeId,proto3" json:"next_before_id,omitempty"` // before_id следующей страницы (0 - записей больше нет)
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ChatError) Reset() {
-	*x = ChatError{}
-	mi := &file_proto_notes_v1_notes_proto_msgTypes[20]
+func (x *ListAuditEventsResponse) Reset() {
+	*x = ListAuditEventsResponse{}
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[167]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ChatError) String() string {
+func (x *ListAuditEventsResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ChatError) ProtoMessage() {}
+func (*ListAuditEventsResponse) ProtoMessage() {}
 
-func (x *ChatError) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_notes_v1_notes_proto_msgTypes[20]
+func (x *ListAuditEventsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notes_v1_notes_proto_msgTypes[167]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1230,60 +11037,459 @@ func (x *ChatError) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ChatError.ProtoReflect.Descriptor instead.
-func (*ChatError) Descriptor() ([]byte, []int) {
-	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{20}
+// Deprecated: Use ListAuditEventsResponse.ProtoReflect.Descriptor instead.
+func (*ListAuditEventsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_notes_v1_notes_proto_rawDescGZIP(), []int{167}
 }
 
-func (x *ChatError) GetCode() ChatErrorCode {
+func (x *ListAuditEventsResponse) GetEvents() []*AuditEvent {
 	if x != nil {
-		return x.Code
+		return x.Events
 	}
-	return ChatErrorCode_CHAT_ERROR_CODE_UNSPECIFIED
+	return nil
 }
 
-func (x *ChatError) GetMessage() string {
+func (x *ListAuditEventsResponse) GetNextBeforeId() int64 {
 	if x != nil {
-		return x.Message
+		return x.NextBeforeId
 	}
-	return ""
+	return 0
 }
 
-func (x *ChatError) GetDetails() string {
-	if x != nil {
-		return x.Details
-	}
-	return ""
+var file_proto_notes_v1_notes_proto_extTypes = []protoimpl.ExtensionInfo{
+	{
+		ExtendedType:  (*descriptorpb.MethodOptions)(nil),
+		ExtensionType: (*MethodPolicy)(nil),
+		Field:         50100,
+		Name:          "notes.v1.policy",
+		Tag:           "bytes,50100,opt,name=policy",
+		Filename:      "proto/notes/v1/notes.proto",
+	},
 }
 
+// Extension fields to descriptorpb.MethodOptions.
+var (
+	// optional notes.v1.MethodPolicy policy = 50100;
+	E_Policy = &file_proto_notes_v1_notes_proto_extTypes[0] // Политика вызова метода
+)
+
 var File_proto_notes_v1_notes_proto protoreflect.FileDescriptor
 
 const file_proto_notes_v1_notes_proto_rawDesc = "" +
 	"\n" +
-	"\x1aproto/notes/v1/notes.proto\x12\bnotes.v1\x1a\x1fgoogle/protobuf/timestamp.proto\x1a\x1bbuf/validate/validate.proto\x1a\x1cgoogle/api/annotations.proto\"X\n" +
+	"\x1aproto/notes/v1/notes.proto\x12\bnotes.v1\x1a\x1fgoogle/protobuf/timestamp.proto\x1a\x1egoogle/protobuf/duration.proto\x1a google/protobuf/descriptor.proto\x1a google/protobuf/field_mask.proto\x1a\x1bbuf/validate/validate.proto\x1a\x1cgoogle/api/annotations.proto\x1a\x17google/rpc/status.proto\"\x95\x02\n" +
+	"\fMethodPolicy\x12(\n" +
+	"\rrequires_auth\x18\x01 \x01(\bH\x00R\frequiresAuth\x88\x01\x01\x12\x14\n" +
+	"\x05roles\x18\x02 \x03(\tR\x05roles\x12>\n" +
+	"\n" +
+	"rate_limit\x18\x03 \x01(\v2\x1f.notes.v1.StreamRateLimitPolicyR\trateLimit\x123\n" +
+	"\atimeout\x18\x04 \x01(\v2\x19.google.protobuf.DurationR\atimeout\x12\x1e\n" +
+	"\n" +
+	"idempotent\x18\x05 \x01(\bR\n" +
+	"idempotent\x12\x1e\n" +
+	"\n" +
+	"deprecated\x18\x06 \x01(\bR\n" +
+	"deprecatedB\x10\n" +
+	"\x0e_requires_auth\"]\n" +
+	"\x15StreamRateLimitPolicy\x12.\n" +
+	"\x13messages_per_second\x18\x01 \x01(\x01R\x11messagesPerSecond\x12\x14\n" +
+	"\x05burst\x18\x02 \x01(\x05R\x05burst\"\xb8\x03\n" +
 	"\x11CreateNoteRequest\x12 \n" +
 	"\x05title\x18\x01 \x01(\tB\n" +
-	"\xbaH\ar\x05\x10\x05\x18\xff\x01R\x05title\x12!\n" +
-	"\acontent\x18\x02 \x01(\tB\a\xbaH\x04r\x02\x10\n" +
-	"R\acontent\"8\n" +
+	"\xbaH\ar\x05\x10\x05\x18\xff\x01R\x05title\x12\x18\n" +
+	"\acontent\x18\x02 \x01(\tR\acontent\x12$\n" +
+	"\x04tags\x18\x03 \x03(\tB\x10\xbaH\r\x92\x01\n" +
+	"\x10\x14\"\x06r\x04\x10\x01\x182R\x04tags\x12\x15\n" +
+	"\x06is_e2e\x18\x04 \x01(\bR\x05isE2e\x12\x1d\n" +
+	"\n" +
+	"e2e_scheme\x18\x05 \x01(\tR\te2eScheme\x126\n" +
+	"\x11content_encrypted\x18\x06 \x01(\fB\t\xbaH\x06z\x04\x18\x80\x80@R\x10contentEncrypted\x121\n" +
+	"\x0fidempotency_key\x18\a \x01(\tB\b\xbaH\x05r\x03\x18\x80\x01R\x0eidempotencyKey\x127\n" +
+	"\tremind_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\bremindAt:g\xbaHd\x1ab\n" +
+	"\x0fcontent_min_len\x12&content must be at least 10 characters\x1a'this.is_e2e || size(this.content) >= 10\"g\n" +
 	"\x12CreateNoteResponse\x12\"\n" +
-	"\x04note\x18\x01 \x01(\v2\x0e.notes.v1.NoteR\x04note\" \n" +
+	"\x04note\x18\x01 \x01(\v2\x0e.notes.v1.NoteR\x04note\x12-\n" +
+	"\bwarnings\x18\x02 \x03(\v2\x11.notes.v1.WarningR\bwarnings\"M\n" +
+	"\aWarning\x12\x12\n" +
+	"\x04code\x18\x01 \x01(\tR\x04code\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12\x14\n" +
+	"\x05field\x18\x03 \x01(\tR\x05field\"Y\n" +
 	"\x0eGetNoteRequest\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\tR\x02id\"5\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x127\n" +
+	"\tread_mask\x18\x02 \x01(\v2\x1a.google.protobuf.FieldMaskR\breadMask\"d\n" +
 	"\x0fGetNoteResponse\x12\"\n" +
-	"\x04note\x18\x01 \x01(\v2\x0e.notes.v1.NoteR\x04note\"\x12\n" +
-	"\x10ListNotesRequest\"9\n" +
+	"\x04note\x18\x01 \x01(\v2\x0e.notes.v1.NoteR\x04note\x12-\n" +
+	"\bwarnings\x18\x02 \x03(\v2\x11.notes.v1.WarningR\bwarnings\"\xc0\x02\n" +
+	"\x10ListNotesRequest\x120\n" +
+	"\x0ftitle_collation\x18\x01 \x01(\tB\a\xbaH\x04r\x02\x18#R\x0etitleCollation\x128\n" +
+	"\border_by\x18\x02 \x01(\x0e2\x13.notes.v1.NoteOrderB\b\xbaH\x05\x82\x01\x02\x10\x01R\aorderBy\x12-\n" +
+	"\x0emin_word_count\x18\x03 \x01(\x05B\a\xbaH\x04\x1a\x02(\x00R\fminWordCount\x12-\n" +
+	"\x0emax_word_count\x18\x04 \x01(\x05B\a\xbaH\x04\x1a\x02(\x00R\fmaxWordCount\x127\n" +
+	"\tread_mask\x18\x05 \x01(\v2\x1a.google.protobuf.FieldMaskR\breadMask\x12)\n" +
+	"\x10include_archived\x18\x06 \x01(\bR\x0fincludeArchived\"h\n" +
 	"\x11ListNotesResponse\x12$\n" +
-	"\x05notes\x18\x01 \x03(\v2\x0e.notes.v1.NoteR\x05notes\"S\n" +
+	"\x05notes\x18\x01 \x03(\v2\x0e.notes.v1.NoteR\x05notes\x12-\n" +
+	"\bwarnings\x18\x02 \x03(\v2\x11.notes.v1.WarningR\bwarnings\"W\n" +
+	"\x12StreamNotesRequest\x12)\n" +
+	"\n" +
+	"batch_size\x18\x01 \x01(\x05B\n" +
+	"\xbaH\a\x1a\x05\x18\xe8\a(\x00R\tbatchSize\x12\x16\n" +
+	"\x06cursor\x18\x02 \x01(\tR\x06cursor\"\xe0\x02\n" +
 	"\x11UpdateNoteRequest\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x14\n" +
 	"\x05title\x18\x02 \x01(\tR\x05title\x12\x18\n" +
-	"\acontent\x18\x03 \x01(\tR\acontent\"8\n" +
+	"\acontent\x18\x03 \x01(\tR\acontent\x12!\n" +
+	"\aversion\x18\x04 \x01(\x03B\a\xbaH\x04\"\x02(\x00R\aversion\x12;\n" +
+	"\vupdate_mask\x18\x05 \x01(\v2\x1a.google.protobuf.FieldMaskR\n" +
+	"updateMask\x12\x14\n" +
+	"\x05force\x18\x06 \x01(\bR\x05force\x12$\n" +
+	"\x04tags\x18\a \x03(\tB\x10\xbaH\r\x92\x01\n" +
+	"\x10\x14\"\x06r\x04\x10\x01\x182R\x04tags\x126\n" +
+	"\x11content_encrypted\x18\b \x01(\fB\t\xbaH\x06z\x04\x18\x80\x80@R\x10contentEncrypted\x127\n" +
+	"\tremind_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\bremindAt\"g\n" +
 	"\x12UpdateNoteResponse\x12\"\n" +
-	"\x04note\x18\x01 \x01(\v2\x0e.notes.v1.NoteR\x04note\"#\n" +
+	"\x04note\x18\x01 \x01(\v2\x0e.notes.v1.NoteR\x04note\x12-\n" +
+	"\bwarnings\x18\x02 \x03(\v2\x11.notes.v1.WarningR\bwarnings\"#\n" +
 	"\x11DeleteNoteRequest\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\"\x14\n" +
-	"\x12DeleteNoteResponse\"\xbc\x01\n" +
+	"\x12DeleteNoteResponse\" \n" +
+	"\x0ePinNoteRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"5\n" +
+	"\x0fPinNoteResponse\x12\"\n" +
+	"\x04note\x18\x01 \x01(\v2\x0e.notes.v1.NoteR\x04note\"\"\n" +
+	"\x10UnpinNoteRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"7\n" +
+	"\x11UnpinNoteResponse\x12\"\n" +
+	"\x04note\x18\x01 \x01(\v2\x0e.notes.v1.NoteR\x04note\"$\n" +
+	"\x12ArchiveNoteRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"9\n" +
+	"\x13ArchiveNoteResponse\x12\"\n" +
+	"\x04note\x18\x01 \x01(\v2\x0e.notes.v1.NoteR\x04note\"&\n" +
+	"\x14UnarchiveNoteRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\";\n" +
+	"\x15UnarchiveNoteResponse\x12\"\n" +
+	"\x04note\x18\x01 \x01(\v2\x0e.notes.v1.NoteR\x04note\"d\n" +
+	"\x0fLockNoteRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12+\n" +
+	"\vttl_seconds\x18\x02 \x01(\x05B\n" +
+	"\xbaH\a\x1a\x05\x18\x90\x1c(\x00R\n" +
+	"ttlSeconds\x12\x14\n" +
+	"\x05force\x18\x03 \x01(\bR\x05force\":\n" +
+	"\x10LockNoteResponse\x12&\n" +
+	"\x04lock\x18\x01 \x01(\v2\x12.notes.v1.NoteLockR\x04lock\"9\n" +
+	"\x11UnlockNoteRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x14\n" +
+	"\x05force\x18\x02 \x01(\bR\x05force\"\x14\n" +
+	"\x12UnlockNoteResponse\"T\n" +
+	"\x18SetNotePassphraseRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12(\n" +
+	"\n" +
+	"passphrase\x18\x02 \x01(\tB\b\xbaH\x05r\x03\x18\x80\bR\n" +
+	"passphrase\"?\n" +
+	"\x19SetNotePassphraseResponse\x12\"\n" +
+	"\x04note\x18\x01 \x01(\v2\x0e.notes.v1.NoteR\x04note\"\xb8\x01\n" +
+	"\bNoteLock\x12\x17\n" +
+	"\anote_id\x18\x01 \x01(\tR\x06noteId\x12\x1b\n" +
+	"\tholder_id\x18\x02 \x01(\tR\bholderId\x12;\n" +
+	"\vacquired_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"acquiredAt\x129\n" +
+	"\n" +
+	"expires_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\texpiresAt\"p\n" +
+	"\x17BatchCreateNotesRequest\x12=\n" +
+	"\x05notes\x18\x01 \x03(\v2\x1b.notes.v1.CreateNoteRequestB\n" +
+	"\xbaH\a\x92\x01\x04\b\x01\x10dR\x05notes\x12\x16\n" +
+	"\x06atomic\x18\x02 \x01(\bR\x06atomic\"O\n" +
+	"\x18BatchCreateNotesResponse\x123\n" +
+	"\aresults\x18\x01 \x03(\v2\x19.notes.v1.BatchNoteResultR\aresults\"4\n" +
+	"\x14BatchGetNotesRequest\x12\x1c\n" +
+	"\x03ids\x18\x01 \x03(\tB\n" +
+	"\xbaH\a\x92\x01\x04\b\x01\x10dR\x03ids\"{\n" +
+	"\x15BatchGetNotesResponse\x123\n" +
+	"\aresults\x18\x01 \x03(\v2\x19.notes.v1.BatchNoteResultR\aresults\x12-\n" +
+	"\bwarnings\x18\x02 \x03(\v2\x11.notes.v1.WarningR\bwarnings\"O\n" +
+	"\x17BatchDeleteNotesRequest\x12\x1c\n" +
+	"\x03ids\x18\x01 \x03(\tB\n" +
+	"\xbaH\a\x92\x01\x04\b\x01\x10dR\x03ids\x12\x16\n" +
+	"\x06atomic\x18\x02 \x01(\bR\x06atomic\"O\n" +
+	"\x18BatchDeleteNotesResponse\x123\n" +
+	"\aresults\x18\x01 \x03(\v2\x19.notes.v1.BatchNoteResultR\aresults\"q\n" +
+	"\x0fBatchNoteResult\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\"\n" +
+	"\x04note\x18\x02 \x01(\v2\x0e.notes.v1.NoteR\x04note\x12*\n" +
+	"\x06status\x18\x03 \x01(\v2\x12.google.rpc.StatusR\x06status\"*\n" +
+	"\x18ListNoteRevisionsRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"Q\n" +
+	"\x19ListNoteRevisionsResponse\x124\n" +
+	"\trevisions\x18\x01 \x03(\v2\x16.notes.v1.NoteRevisionR\trevisions\"M\n" +
+	"\x16GetNoteRevisionRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12#\n" +
+	"\brevision\x18\x02 \x01(\x03B\a\xbaH\x04\"\x02 \x00R\brevision\"M\n" +
+	"\x17GetNoteRevisionResponse\x122\n" +
+	"\brevision\x18\x01 \x01(\v2\x16.notes.v1.NoteRevisionR\brevision\"\xf7\x01\n" +
+	"\x18DiffNoteRevisionsRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12,\n" +
+	"\rfrom_revision\x18\x02 \x01(\x03B\a\xbaH\x04\"\x02 \x00R\ffromRevision\x12(\n" +
+	"\vto_revision\x18\x03 \x01(\x03B\a\xbaH\x04\"\x02 \x00R\n" +
+	"toRevision\x123\n" +
+	"\rcontext_lines\x18\x04 \x01(\x05B\t\xbaH\x06\x1a\x04\x18\x14(\x00H\x00R\fcontextLines\x88\x01\x01\x12,\n" +
+	"\x06format\x18\x05 \x01(\x0e2\x14.notes.v1.DiffFormatR\x06formatB\x10\n" +
+	"\x0e_context_lines\"\xc7\x02\n" +
+	"\x19DiffNoteRevisionsResponse\x12\x17\n" +
+	"\anote_id\x18\x01 \x01(\tR\x06noteId\x12#\n" +
+	"\rfrom_revision\x18\x02 \x01(\x03R\ffromRevision\x12\x1f\n" +
+	"\vto_revision\x18\x03 \x01(\x03R\n" +
+	"toRevision\x12\x1d\n" +
+	"\n" +
+	"from_title\x18\x04 \x01(\tR\tfromTitle\x12\x19\n" +
+	"\bto_title\x18\x05 \x01(\tR\atoTitle\x12(\n" +
+	"\x05hunks\x18\x06 \x03(\v2\x12.notes.v1.DiffHunkR\x05hunks\x12!\n" +
+	"\funified_diff\x18\a \x01(\tR\vunifiedDiff\x12\x1f\n" +
+	"\vlines_added\x18\b \x01(\x05R\n" +
+	"linesAdded\x12#\n" +
+	"\rlines_removed\x18\t \x01(\x05R\flinesRemoved\"\xa8\x01\n" +
+	"\bDiffHunk\x12\x1d\n" +
+	"\n" +
+	"from_start\x18\x01 \x01(\x05R\tfromStart\x12\x1d\n" +
+	"\n" +
+	"from_lines\x18\x02 \x01(\x05R\tfromLines\x12\x19\n" +
+	"\bto_start\x18\x03 \x01(\x05R\atoStart\x12\x19\n" +
+	"\bto_lines\x18\x04 \x01(\x05R\atoLines\x12(\n" +
+	"\x05lines\x18\x05 \x03(\v2\x12.notes.v1.DiffLineR\x05lines\"J\n" +
+	"\bDiffLine\x12*\n" +
+	"\x04kind\x18\x01 \x01(\x0e2\x16.notes.v1.DiffLineKindR\x04kind\x12\x12\n" +
+	"\x04text\x18\x02 \x01(\tR\x04text\"\xdb\x01\n" +
+	"\fNoteRevision\x12\x17\n" +
+	"\anote_id\x18\x01 \x01(\tR\x06noteId\x12\x1a\n" +
+	"\brevision\x18\x02 \x01(\x03R\brevision\x12\x14\n" +
+	"\x05title\x18\x03 \x01(\tR\x05title\x12\x18\n" +
+	"\acontent\x18\x04 \x01(\tR\acontent\x129\n" +
+	"\n" +
+	"created_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x12+\n" +
+	"\x11content_encrypted\x18\x06 \x01(\fR\x10contentEncrypted\"4\n" +
+	"\x15ListNotesByTagRequest\x12\x1b\n" +
+	"\x03tag\x18\x01 \x01(\tB\t\xbaH\x06r\x04\x10\x01\x182R\x03tag\"m\n" +
+	"\x16ListNotesByTagResponse\x12$\n" +
+	"\x05notes\x18\x01 \x03(\v2\x0e.notes.v1.NoteR\x05notes\x12-\n" +
+	"\bwarnings\x18\x02 \x03(\v2\x11.notes.v1.WarningR\bwarnings\"\x11\n" +
+	"\x0fListTagsRequest\"i\n" +
+	"\x10ListTagsResponse\x12&\n" +
+	"\x04tags\x18\x01 \x03(\v2\x12.notes.v1.TagCountR\x04tags\x12-\n" +
+	"\bwarnings\x18\x02 \x03(\v2\x11.notes.v1.WarningR\bwarnings\"Z\n" +
+	"\x13SuggestNotesRequest\x12\"\n" +
+	"\x06prefix\x18\x01 \x01(\tB\n" +
+	"\xbaH\ar\x05\x10\x01\x18\xc8\x01R\x06prefix\x12\x1f\n" +
+	"\x05limit\x18\x02 \x01(\x05B\t\xbaH\x06\x1a\x04\x182(\x00R\x05limit\"R\n" +
+	"\x14SuggestNotesResponse\x12:\n" +
+	"\vsuggestions\x18\x01 \x03(\v2\x18.notes.v1.NoteSuggestionR\vsuggestions\"\x87\x01\n" +
+	"\x0eNoteSuggestion\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x14\n" +
+	"\x05title\x18\x02 \x01(\tR\x05title\x129\n" +
+	"\n" +
+	"updated_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x12\x14\n" +
+	"\x05views\x18\x04 \x01(\x03R\x05views\"%\n" +
+	"\x13GetNoteStatsRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"A\n" +
+	"\x14GetNoteStatsResponse\x12)\n" +
+	"\x05stats\x18\x01 \x01(\v2\x13.notes.v1.NoteStatsR\x05stats\"\xa6\x02\n" +
+	"\tNoteStats\x12\x17\n" +
+	"\anote_id\x18\x01 \x01(\tR\x06noteId\x12\x1d\n" +
+	"\n" +
+	"word_count\x18\x02 \x01(\x03R\twordCount\x12'\n" +
+	"\x0fcharacter_count\x18\x03 \x01(\x03R\x0echaracterCount\x120\n" +
+	"\x14reading_time_seconds\x18\x04 \x01(\x03R\x12readingTimeSeconds\x129\n" +
+	"\n" +
+	"updated_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x124\n" +
+	"\tlast_edit\x18\x06 \x01(\v2\x17.notes.v1.NoteEditDeltaR\blastEdit\x12\x15\n" +
+	"\x06is_e2e\x18\a \x01(\bR\x05isE2e\"w\n" +
+	"\rNoteEditDelta\x12\x1a\n" +
+	"\brevision\x18\x01 \x01(\x03R\brevision\x12\x1f\n" +
+	"\vwords_delta\x18\x02 \x01(\x03R\n" +
+	"wordsDelta\x12)\n" +
+	"\x10characters_delta\x18\x03 \x01(\x03R\x0fcharactersDelta\"\x18\n" +
+	"\x16GetAccountStatsRequest\"G\n" +
+	"\x17GetAccountStatsResponse\x12,\n" +
+	"\x05stats\x18\x01 \x01(\v2\x16.notes.v1.AccountStatsR\x05stats\"\xb3\x01\n" +
+	"\fAccountStats\x12\x1f\n" +
+	"\vtotal_notes\x18\x01 \x01(\x03R\n" +
+	"totalNotes\x12\x1f\n" +
+	"\vtotal_words\x18\x02 \x01(\x03R\n" +
+	"totalWords\x12)\n" +
+	"\x10total_characters\x18\x03 \x01(\x03R\x0ftotalCharacters\x126\n" +
+	"\rnotes_per_tag\x18\x04 \x03(\v2\x12.notes.v1.TagCountR\vnotesPerTag\"\xca\x01\n" +
+	"\x05Share\x12\x17\n" +
+	"\anote_id\x18\x01 \x01(\tR\x06noteId\x12\x19\n" +
+	"\bowner_id\x18\x02 \x01(\tR\aownerId\x12\x17\n" +
+	"\auser_id\x18\x03 \x01(\tR\x06userId\x129\n" +
+	"\n" +
+	"permission\x18\x04 \x01(\x0e2\x19.notes.v1.SharePermissionR\n" +
+	"permission\x129\n" +
+	"\n" +
+	"created_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"\xa1\x01\n" +
+	"\x10ShareNoteRequest\x12!\n" +
+	"\anote_id\x18\x01 \x01(\tB\b\xbaH\x05r\x03\xb0\x01\x01R\x06noteId\x12#\n" +
+	"\auser_id\x18\x02 \x01(\tB\n" +
+	"\xbaH\ar\x05\x10\x01\x18\xff\x01R\x06userId\x12E\n" +
+	"\n" +
+	"permission\x18\x03 \x01(\x0e2\x19.notes.v1.SharePermissionB\n" +
+	"\xbaH\a\x82\x01\x04\x10\x01 \x00R\n" +
+	"permission\":\n" +
+	"\x11ShareNoteResponse\x12%\n" +
+	"\x05share\x18\x01 \x01(\v2\x0f.notes.v1.ShareR\x05share\"Y\n" +
+	"\x12UnshareNoteRequest\x12!\n" +
+	"\anote_id\x18\x01 \x01(\tB\b\xbaH\x05r\x03\xb0\x01\x01R\x06noteId\x12 \n" +
+	"\auser_id\x18\x02 \x01(\tB\a\xbaH\x04r\x02\x10\x01R\x06userId\"\x15\n" +
+	"\x13UnshareNoteResponse\"\x18\n" +
+	"\x16ListSharedNotesRequest\"k\n" +
+	"\n" +
+	"SharedNote\x12\"\n" +
+	"\x04note\x18\x01 \x01(\v2\x0e.notes.v1.NoteR\x04note\x129\n" +
+	"\n" +
+	"permission\x18\x02 \x01(\x0e2\x19.notes.v1.SharePermissionR\n" +
+	"permission\"E\n" +
+	"\x17ListSharedNotesResponse\x12*\n" +
+	"\x05notes\x18\x01 \x03(\v2\x14.notes.v1.SharedNoteR\x05notes\"P\n" +
+	"\x12ExportNotesRequest\x12:\n" +
+	"\x06format\x18\x01 \x01(\x0e2\x16.notes.v1.ExportFormatB\n" +
+	"\xbaH\a\x82\x01\x04\x10\x01 \x00R\x06format\")\n" +
+	"\x13ExportNotesResponse\x12\x12\n" +
+	"\x04data\x18\x01 \x01(\fR\x04data\"[\n" +
+	"\x1aExportToDestinationRequest\x12=\n" +
+	"\aarchive\x18\x01 \x01(\x0e2\x17.notes.v1.ExportArchiveB\n" +
+	"\xbaH\a\x82\x01\x04\x10\x01 \x00R\aarchive\"+\n" +
+	"\x19GetExportOperationRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\x92\x03\n" +
+	"\x0fExportOperation\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x124\n" +
+	"\x05state\x18\x02 \x01(\x0e2\x1e.notes.v1.ExportOperationStateR\x05state\x121\n" +
+	"\aarchive\x18\x03 \x01(\x0e2\x17.notes.v1.ExportArchiveR\aarchive\x12%\n" +
+	"\x0eexported_notes\x18\x04 \x01(\x03R\rexportedNotes\x12\x1f\n" +
+	"\vtotal_notes\x18\x05 \x01(\x03R\n" +
+	"totalNotes\x12\x1a\n" +
+	"\blocation\x18\x06 \x01(\tR\blocation\x12(\n" +
+	"\x05error\x18\a \x01(\v2\x12.google.rpc.StatusR\x05error\x129\n" +
+	"\n" +
+	"created_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x12=\n" +
+	"\fcompleted_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\vcompletedAt\"8\n" +
+	"\x11RotateKeysRequest\x12#\n" +
+	"\bowner_id\x18\x01 \x01(\tB\b\xbaH\x05r\x03\x18\xff\x01R\aownerId\"0\n" +
+	"\x1eGetKeyRotationOperationRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\xf7\x03\n" +
+	"\x14KeyRotationOperation\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x120\n" +
+	"\x05state\x18\x02 \x01(\x0e2\x1a.notes.v1.KeyRotationStateR\x05state\x12\x19\n" +
+	"\bowner_id\x18\x03 \x01(\tR\aownerId\x12\x1d\n" +
+	"\n" +
+	"started_by\x18\x04 \x01(\tR\tstartedBy\x12!\n" +
+	"\frotated_keys\x18\x05 \x01(\x03R\vrotatedKeys\x12%\n" +
+	"\x0erewrapped_keys\x18\x06 \x01(\x03R\rrewrappedKeys\x12\x1f\n" +
+	"\vtotal_notes\x18\a \x01(\x03R\n" +
+	"totalNotes\x12'\n" +
+	"\x0fprocessed_notes\x18\b \x01(\x03R\x0eprocessedNotes\x12+\n" +
+	"\x11reencrypted_notes\x18\t \x01(\x03R\x10reencryptedNotes\x12(\n" +
+	"\x05error\x18\n" +
+	" \x01(\v2\x12.google.rpc.StatusR\x05error\x129\n" +
+	"\n" +
+	"created_at\x18\v \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x12=\n" +
+	"\fcompleted_at\x18\f \x01(\v2\x1a.google.protobuf.TimestampR\vcompletedAt\"O\n" +
+	"\x14ExportCompletedEvent\x127\n" +
+	"\toperation\x18\x01 \x01(\v2\x19.notes.v1.ExportOperationR\toperation\"g\n" +
+	"\x12ImportNotesRequest\x120\n" +
+	"\x06format\x18\x01 \x01(\x0e2\x16.notes.v1.ExportFormatH\x00R\x06format\x12\x14\n" +
+	"\x04data\x18\x02 \x01(\fH\x00R\x04dataB\t\n" +
+	"\apayload\"a\n" +
+	"\x13ImportNotesResponse\x12\x1a\n" +
+	"\bimported\x18\x01 \x01(\x03R\bimported\x12\x16\n" +
+	"\x06failed\x18\x02 \x01(\x03R\x06failed\x12\x16\n" +
+	"\x06errors\x18\x03 \x03(\tR\x06errors\"\x16\n" +
+	"\x14GetServerInfoRequest\"h\n" +
+	"\x15GetServerInfoResponse\x12\x1f\n" +
+	"\ve2e_schemes\x18\x01 \x03(\tR\n" +
+	"e2eSchemes\x12.\n" +
+	"\x06backup\x18\x02 \x01(\v2\x16.notes.v1.BackupStatusR\x06backup\"\x9b\x04\n" +
+	"\fBackupStatus\x12\x1f\n" +
+	"\vlast_backup\x18\x01 \x01(\tR\n" +
+	"lastBackup\x120\n" +
+	"\x14last_backup_location\x18\x02 \x01(\tR\x12lastBackupLocation\x12D\n" +
+	"\x10last_backup_time\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\x0elastBackupTime\x123\n" +
+	"\x16last_backup_size_bytes\x18\x04 \x01(\x03R\x13lastBackupSizeBytes\x12*\n" +
+	"\x11last_backup_notes\x18\x05 \x01(\x03R\x0flastBackupNotes\x12F\n" +
+	"\x11last_attempt_time\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\x0flastAttemptTime\x121\n" +
+	"\n" +
+	"last_error\x18\a \x01(\v2\x12.google.rpc.StatusR\tlastError\x12D\n" +
+	"\x10next_backup_time\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\x0enextBackupTime\x12\x1a\n" +
+	"\bretained\x18\t \x01(\x05R\bretained\x12\x1c\n" +
+	"\tsucceeded\x18\n" +
+	" \x01(\x03R\tsucceeded\x12\x16\n" +
+	"\x06failed\x18\v \x01(\x03R\x06failed\"\xaa\x01\n" +
+	"\x14RestoreBackupRequest\x12 \n" +
+	"\x06backup\x18\x01 \x01(\tB\b\xbaH\x05r\x03\x18\xff\x01R\x06backup\x12W\n" +
+	"\x11conflict_strategy\x18\x02 \x01(\x0e2 .notes.v1.BackupConflictStrategyB\b\xbaH\x05\x82\x01\x02\x10\x01R\x10conflictStrategy\x12\x17\n" +
+	"\adry_run\x18\x03 \x01(\bR\x06dryRun\"\x88\x02\n" +
+	"\x15RestoreBackupResponse\x12\x16\n" +
+	"\x06backup\x18\x01 \x01(\tR\x06backup\x12\x17\n" +
+	"\adry_run\x18\x02 \x01(\bR\x06dryRun\x12\x14\n" +
+	"\x05notes\x18\x03 \x01(\x03R\x05notes\x12\x18\n" +
+	"\acreated\x18\x04 \x01(\x03R\acreated\x12 \n" +
+	"\voverwritten\x18\x05 \x01(\x03R\voverwritten\x12\x18\n" +
+	"\askipped\x18\x06 \x01(\x03R\askipped\x12\x1c\n" +
+	"\trevisions\x18\a \x01(\x03R\trevisions\x12\x16\n" +
+	"\x06shares\x18\b \x01(\x03R\x06shares\x12\x1c\n" +
+	"\tconflicts\x18\t \x03(\tR\tconflicts\"\x16\n" +
+	"\x14GetUsageStatsRequest\"\x8f\x02\n" +
+	"\x15GetUsageStatsResponse\x12'\n" +
+	"\x0finstallation_id\x18\x01 \x01(\tR\x0einstallationId\x120\n" +
+	"\x05since\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\x05since\x12/\n" +
+	"\amethods\x18\x03 \x03(\v2\x15.notes.v1.MethodUsageR\amethods\x122\n" +
+	"\bfeatures\x18\x04 \x03(\v2\x16.notes.v1.FeatureUsageR\bfeatures\x126\n" +
+	"\treporting\x18\x05 \x01(\v2\x18.notes.v1.UsageReportingR\treporting\"S\n" +
+	"\vMethodUsage\x12\x16\n" +
+	"\x06method\x18\x01 \x01(\tR\x06method\x12\x14\n" +
+	"\x05calls\x18\x02 \x01(\x03R\x05calls\x12\x16\n" +
+	"\x06errors\x18\x03 \x01(\x03R\x06errors\">\n" +
+	"\fFeatureUsage\x12\x18\n" +
+	"\afeature\x18\x01 \x01(\tR\afeature\x12\x14\n" +
+	"\x05count\x18\x02 \x01(\x03R\x05count\"\xbd\x01\n" +
+	"\x0eUsageReporting\x12\x18\n" +
+	"\aenabled\x18\x01 \x01(\bR\aenabled\x12D\n" +
+	"\x10last_report_time\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\x0elastReportTime\x121\n" +
+	"\n" +
+	"last_error\x18\x03 \x01(\v2\x12.google.rpc.StatusR\tlastError\x12\x18\n" +
+	"\areports\x18\x04 \x01(\x03R\areports\"\x1a\n" +
+	"\x18AdminListAllNotesRequest\"A\n" +
+	"\x19AdminListAllNotesResponse\x12$\n" +
+	"\x05notes\x18\x01 \x03(\v2\x0e.notes.v1.NoteR\x05notes\"2\n" +
+	"\bTagCount\x12\x10\n" +
+	"\x03tag\x18\x01 \x01(\tR\x03tag\x12\x14\n" +
+	"\x05count\x18\x02 \x01(\x03R\x05count\"y\n" +
+	"\x0fAttachmentChunk\x12:\n" +
+	"\bmetadata\x18\x01 \x01(\v2\x1c.notes.v1.AttachmentMetadataH\x00R\bmetadata\x12\x1f\n" +
+	"\x04data\x18\x02 \x01(\fB\t\xbaH\x06z\x04\x18\x80\x80@H\x00R\x04dataB\t\n" +
+	"\apayload\"\x8d\x01\n" +
+	"\x12AttachmentMetadata\x12!\n" +
+	"\anote_id\x18\x01 \x01(\tB\b\xbaH\x05r\x03\xb0\x01\x01R\x06noteId\x12'\n" +
+	"\tfile_name\x18\x02 \x01(\tB\n" +
+	"\xbaH\ar\x05\x10\x01\x18\xff\x01R\bfileName\x12+\n" +
+	"\fcontent_type\x18\x03 \x01(\tB\b\xbaH\x05r\x03\x18\xff\x01R\vcontentType\"\xe7\x01\n" +
+	"\n" +
+	"Attachment\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x17\n" +
+	"\anote_id\x18\x02 \x01(\tR\x06noteId\x12\x1b\n" +
+	"\tfile_name\x18\x03 \x01(\tR\bfileName\x12!\n" +
+	"\fcontent_type\x18\x04 \x01(\tR\vcontentType\x12\x1d\n" +
+	"\n" +
+	"size_bytes\x18\x05 \x01(\x03R\tsizeBytes\x12\x16\n" +
+	"\x06sha256\x18\x06 \x01(\tR\x06sha256\x129\n" +
+	"\n" +
+	"created_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"X\n" +
+	"\x19DownloadAttachmentRequest\x12!\n" +
+	"\anote_id\x18\x01 \x01(\tB\b\xbaH\x05r\x03\xb0\x01\x01R\x06noteId\x12\x18\n" +
+	"\x02id\x18\x02 \x01(\tB\b\xbaH\x05r\x03\xb0\x01\x01R\x02id\"u\n" +
+	"\x1aDownloadAttachmentResponse\x126\n" +
+	"\n" +
+	"attachment\x18\x01 \x01(\v2\x14.notes.v1.AttachmentH\x00R\n" +
+	"attachment\x12\x14\n" +
+	"\x04data\x18\x02 \x01(\fH\x00R\x04dataB\t\n" +
+	"\apayload\"\x91\x05\n" +
 	"\x04Note\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x14\n" +
 	"\x05title\x18\x02 \x01(\tR\x05title\x12\x18\n" +
@@ -1291,59 +11497,484 @@ const file_proto_notes_v1_notes_proto_rawDesc = "" +
 	"\n" +
 	"created_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
 	"\n" +
-	"updated_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"o\n" +
+	"updated_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x12\x18\n" +
+	"\aversion\x18\x06 \x01(\x03R\aversion\x12\x12\n" +
+	"\x04tags\x18\a \x03(\tR\x04tags\x12\x19\n" +
+	"\bowner_id\x18\b \x01(\tR\aownerId\x12\x15\n" +
+	"\x06is_e2e\x18\t \x01(\bR\x05isE2e\x12\x1d\n" +
+	"\n" +
+	"e2e_scheme\x18\n" +
+	" \x01(\tR\te2eScheme\x12+\n" +
+	"\x11content_encrypted\x18\v \x01(\fR\x10contentEncrypted\x12\x16\n" +
+	"\x06pinned\x18\f \x01(\bR\x06pinned\x127\n" +
+	"\tremind_at\x18\r \x01(\v2\x1a.google.protobuf.TimestampR\bremindAt\x12*\n" +
+	"\x11encryption_key_id\x18\x0e \x01(\tR\x0fencryptionKeyId\x12\x1d\n" +
+	"\n" +
+	"word_count\x18\x0f \x01(\x05R\twordCount\x12<\n" +
+	"\freading_time\x18\x10 \x01(\v2\x19.google.protobuf.DurationR\vreadingTime\x121\n" +
+	"\x14passphrase_protected\x18\x11 \x01(\bR\x13passphraseProtected\x12\x1a\n" +
+	"\barchived\x18\x12 \x01(\bR\barchived\"\x8e\x01\n" +
 	"\fErrorDetails\x12\x16\n" +
 	"\x06reason\x18\x01 \x01(\tR\x06reason\x12.\n" +
 	"\x13internal_error_code\x18\x02 \x01(\tR\x11internalErrorCode\x12\x17\n" +
-	"\anote_id\x18\x03 \x01(\tR\x06noteId\"\x1a\n" +
-	"\x18SubscribeToEventsRequest\"\x95\x01\n" +
+	"\anote_id\x18\x03 \x01(\tR\x06noteId\x12\x1d\n" +
+	"\n" +
+	"request_id\x18\x04 \x01(\tR\trequestId\"\xb4\x01\n" +
+	"\aWebhook\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x10\n" +
+	"\x03url\x18\x02 \x01(\tR\x03url\x124\n" +
+	"\vevent_types\x18\x03 \x03(\x0e2\x13.notes.v1.EventTypeR\n" +
+	"eventTypes\x12\x16\n" +
+	"\x06secret\x18\x04 \x01(\tR\x06secret\x129\n" +
+	"\n" +
+	"created_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"\xa0\x01\n" +
+	"\x16RegisterWebhookRequest\x12\x1d\n" +
+	"\x03url\x18\x01 \x01(\tB\v\xbaH\br\x06\x18\x80\x10\x88\x01\x01R\x03url\x12 \n" +
+	"\x06secret\x18\x02 \x01(\tB\b\xbaH\x05r\x03\x18\x80\x02R\x06secret\x12E\n" +
+	"\vevent_types\x18\x03 \x03(\x0e2\x13.notes.v1.EventTypeB\x0f\xbaH\f\x92\x01\t\"\a\x82\x01\x04\x10\x01 \x00R\n" +
+	"eventTypes\"\x15\n" +
+	"\x13ListWebhooksRequest\"E\n" +
+	"\x14ListWebhooksResponse\x12-\n" +
+	"\bwebhooks\x18\x01 \x03(\v2\x11.notes.v1.WebhookR\bwebhooks\"/\n" +
+	"\x14DeleteWebhookRequest\x12\x17\n" +
+	"\x02id\x18\x01 \x01(\tB\a\xbaH\x04r\x02\x10\x01R\x02id\"\x17\n" +
+	"\x15DeleteWebhookResponse\">\n" +
+	"\x1dListWebhookDeadLettersRequest\x12\x1d\n" +
+	"\n" +
+	"webhook_id\x18\x01 \x01(\tR\twebhookId\"`\n" +
+	"\x1eListWebhookDeadLettersResponse\x12>\n" +
+	"\fdead_letters\x18\x01 \x03(\v2\x1b.notes.v1.WebhookDeadLetterR\vdeadLetters\"\xbd\x01\n" +
+	"\vSavedSearch\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x14\n" +
+	"\x05query\x18\x03 \x01(\tR\x05query\x129\n" +
+	"\n" +
+	"created_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"U\n" +
+	"\x11SaveSearchRequest\x12\x1e\n" +
+	"\x04name\x18\x01 \x01(\tB\n" +
+	"\xbaH\ar\x05\x10\x01\x18\xff\x01R\x04name\x12 \n" +
+	"\x05query\x18\x02 \x01(\tB\n" +
+	"\xbaH\ar\x05\x10\x01\x18\x80\bR\x05query\"\x1a\n" +
+	"\x18ListSavedSearchesRequest\"Y\n" +
+	"\x19ListSavedSearchesResponse\x12<\n" +
+	"\x0esaved_searches\x18\x01 \x03(\v2\x15.notes.v1.SavedSearchR\rsavedSearches\"3\n" +
+	"\x18DeleteSavedSearchRequest\x12\x17\n" +
+	"\x02id\x18\x01 \x01(\tB\a\xbaH\x04r\x02\x10\x01R\x02id\"\x1b\n" +
+	"\x19DeleteSavedSearchResponse\"\xd9\x01\n" +
+	"\x19ExecuteSavedSearchRequest\x12\x17\n" +
+	"\x02id\x18\x01 \x01(\tB\a\xbaH\x04r\x02\x10\x01R\x02id\x120\n" +
+	"\x0ftitle_collation\x18\x02 \x01(\tB\a\xbaH\x04r\x02\x18#R\x0etitleCollation\x128\n" +
+	"\border_by\x18\x03 \x01(\x0e2\x13.notes.v1.NoteOrderB\b\xbaH\x05\x82\x01\x02\x10\x01R\aorderBy\x127\n" +
+	"\tread_mask\x18\x04 \x01(\v2\x1a.google.protobuf.FieldMaskR\breadMask\"\xab\x01\n" +
+	"\x1aExecuteSavedSearchResponse\x128\n" +
+	"\fsaved_search\x18\x01 \x01(\v2\x15.notes.v1.SavedSearchR\vsavedSearch\x12$\n" +
+	"\x05notes\x18\x02 \x03(\v2\x0e.notes.v1.NoteR\x05notes\x12-\n" +
+	"\bwarnings\x18\x03 \x03(\v2\x11.notes.v1.WarningR\bwarnings\"\x96\x02\n" +
+	"\x11WebhookDeadLetter\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1d\n" +
+	"\n" +
+	"webhook_id\x18\x02 \x01(\tR\twebhookId\x12\x10\n" +
+	"\x03url\x18\x03 \x01(\tR\x03url\x122\n" +
+	"\n" +
+	"event_type\x18\x04 \x01(\x0e2\x13.notes.v1.EventTypeR\teventType\x12\x18\n" +
+	"\apayload\x18\x05 \x01(\tR\apayload\x12\x1a\n" +
+	"\battempts\x18\x06 \x01(\x05R\battempts\x12\x1d\n" +
+	"\n" +
+	"last_error\x18\a \x01(\tR\tlastError\x127\n" +
+	"\tfailed_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\bfailedAt\"\xad\x02\n" +
+	"\x18SubscribeToEventsRequest\x12E\n" +
+	"\vevent_types\x18\x01 \x03(\x0e2\x13.notes.v1.EventTypeB\x0f\xbaH\f\x92\x01\t\"\a\x82\x01\x04\x10\x01 \x00R\n" +
+	"eventTypes\x12&\n" +
+	"\x0esince_event_id\x18\x02 \x01(\x04H\x00R\fsinceEventId\x12E\n" +
+	"\x0fsince_timestamp\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampH\x00R\x0esinceTimestamp\x12#\n" +
+	"\fresume_token\x18\x05 \x01(\tH\x00R\vresumeToken\x12-\n" +
+	"\x12disable_heartbeats\x18\x04 \x01(\bR\x11disableHeartbeatsB\a\n" +
+	"\x05since\"\xd3\x06\n" +
 	"\rEventResponse\x12:\n" +
 	"\fhealth_check\x18\x01 \x01(\v2\x15.notes.v1.HealthCheckH\x00R\vhealthCheck\x12?\n" +
-	"\fnote_created\x18\x02 \x01(\v2\x1a.notes.v1.NoteCreatedEventH\x00R\vnoteCreatedB\a\n" +
+	"\fnote_created\x18\x02 \x01(\v2\x1a.notes.v1.NoteCreatedEventH\x00R\vnoteCreated\x12G\n" +
+	"\x11note_reminder_due\x18\x03 \x01(\v2\x19.notes.v1.NoteReminderDueH\x00R\x0fnoteReminderDue\x12K\n" +
+	"\x10export_completed\x18\x04 \x01(\v2\x1e.notes.v1.ExportCompletedEventH\x00R\x0fexportCompleted\x12?\n" +
+	"\fnote_updated\x18\x05 \x01(\v2\x1a.notes.v1.NoteUpdatedEventH\x00R\vnoteUpdated\x12?\n" +
+	"\fnote_deleted\x18\x06 \x01(\v2\x1a.notes.v1.NoteDeletedEventH\x00R\vnoteDeleted\x12<\n" +
+	"\vnote_shared\x18\a \x01(\v2\x19.notes.v1.NoteSharedEventH\x00R\n" +
+	"noteShared\x12B\n" +
+	"\rnote_archived\x18\r \x01(\v2\x1b.notes.v1.NoteArchivedEventH\x00R\fnoteArchived\x12U\n" +
+	"\x14saved_search_matched\x18\v \x01(\v2!.notes.v1.SavedSearchMatchedEventH\x00R\x12savedSearchMatched\x12B\n" +
+	"\rquota_warning\x18\f \x01(\v2\x1b.notes.v1.QuotaWarningEventH\x00R\fquotaWarning\x121\n" +
+	"\ago_away\x18\n" +
+	" \x01(\v2\x16.notes.v1.StreamGoAwayH\x00R\x06goAway\x12\x19\n" +
+	"\bevent_id\x18\b \x01(\x04R\aeventId\x129\n" +
+	"\n" +
+	"event_time\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\teventTimeB\a\n" +
 	"\x05event\"a\n" +
 	"\vHealthCheck\x12\x18\n" +
 	"\amessage\x18\x01 \x01(\tR\amessage\x128\n" +
-	"\ttimestamp\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\"^\n" +
+	"\ttimestamp\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\"m\n" +
+	"\fStreamGoAway\x12\x16\n" +
+	"\x06reason\x18\x01 \x01(\tR\x06reason\x12!\n" +
+	"\fresume_token\x18\x02 \x01(\tR\vresumeToken\x12\"\n" +
+	"\rlast_event_id\x18\x03 \x01(\x04R\vlastEventId\"w\n" +
+	"\x17SavedSearchMatchedEvent\x128\n" +
+	"\fsaved_search\x18\x01 \x01(\v2\x15.notes.v1.SavedSearchR\vsavedSearch\x12\"\n" +
+	"\x04note\x18\x02 \x01(\v2\x0e.notes.v1.NoteR\x04note\"\x8e\x01\n" +
+	"\x11QuotaWarningEvent\x12\x12\n" +
+	"\x04used\x18\x01 \x01(\x05R\x04used\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\x05R\x05limit\x12+\n" +
+	"\x11threshold_percent\x18\x03 \x01(\x05R\x10thresholdPercent\x12\"\n" +
+	"\x04note\x18\x04 \x01(\v2\x0e.notes.v1.NoteR\x04note\"^\n" +
 	"\x10NoteCreatedEvent\x12\x19\n" +
 	"\anote_id\x18\x01 \x01(\tH\x00R\x06noteId\x12$\n" +
 	"\x04note\x18\x02 \x01(\v2\x0e.notes.v1.NoteH\x00R\x04noteB\t\n" +
-	"\apayload\"9\n" +
+	"\apayload\"6\n" +
+	"\x10NoteUpdatedEvent\x12\"\n" +
+	"\x04note\x18\x01 \x01(\v2\x0e.notes.v1.NoteR\x04note\"+\n" +
+	"\x10NoteDeletedEvent\x12\x17\n" +
+	"\anote_id\x18\x01 \x01(\tR\x06noteId\"7\n" +
+	"\x11NoteArchivedEvent\x12\"\n" +
+	"\x04note\x18\x01 \x01(\v2\x0e.notes.v1.NoteR\x04note\"\\\n" +
+	"\x0fNoteSharedEvent\x12\"\n" +
+	"\x04note\x18\x01 \x01(\v2\x0e.notes.v1.NoteR\x04note\x12%\n" +
+	"\x05share\x18\x02 \x01(\v2\x0f.notes.v1.ShareR\x05share\"n\n" +
+	"\x0fNoteReminderDue\x12\"\n" +
+	"\x04note\x18\x01 \x01(\v2\x0e.notes.v1.NoteR\x04note\x127\n" +
+	"\tremind_at\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\bremindAt\"i\n" +
 	"\rMetricRequest\x12\x14\n" +
 	"\x05value\x18\x01 \x01(\x01R\x05value\x12\x12\n" +
-	"\x04name\x18\x02 \x01(\tR\x04name\"S\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12.\n" +
+	"\x04time\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\x04time\"\x86\x01\n" +
 	"\x0fSummaryResponse\x12\x10\n" +
 	"\x03sum\x18\x01 \x01(\x01R\x03sum\x12\x18\n" +
 	"\aaverage\x18\x02 \x01(\x01R\aaverage\x12\x14\n" +
-	"\x05count\x18\x03 \x01(\x03R\x05count\"\xac\x01\n" +
+	"\x05count\x18\x03 \x01(\x03R\x05count\x121\n" +
+	"\ametrics\x18\x04 \x03(\v2\x17.notes.v1.MetricSummaryR\ametrics\"\x9b\x01\n" +
+	"\rMetricSummary\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x14\n" +
+	"\x05count\x18\x02 \x01(\x03R\x05count\x12\x10\n" +
+	"\x03sum\x18\x03 \x01(\x01R\x03sum\x12\x18\n" +
+	"\aaverage\x18\x04 \x01(\x01R\aaverage\x12\x10\n" +
+	"\x03min\x18\x05 \x01(\x01R\x03min\x12\x10\n" +
+	"\x03max\x18\x06 \x01(\x01R\x03max\x12\x10\n" +
+	"\x03p95\x18\a \x01(\x01R\x03p95\"\x90\x01\n" +
+	"\x14StreamMetricsRequest\x12:\n" +
+	"\aoptions\x18\x01 \x01(\v2\x1e.notes.v1.StreamMetricsOptionsH\x00R\aoptions\x121\n" +
+	"\x06metric\x18\x02 \x01(\v2\x17.notes.v1.MetricRequestH\x00R\x06metricB\t\n" +
+	"\apayload\"G\n" +
+	"\x14StreamMetricsOptions\x12/\n" +
+	"\x0ewindow_seconds\x18\x01 \x01(\rB\b\xbaH\x05*\x03\x18\x90\x1cR\rwindowSeconds\"\xdc\x01\n" +
+	"\x15StreamMetricsResponse\x123\n" +
+	"\asummary\x18\x01 \x01(\v2\x19.notes.v1.SummaryResponseR\asummary\x12=\n" +
+	"\fwindow_start\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\vwindowStart\x129\n" +
+	"\n" +
+	"window_end\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\twindowEnd\x12\x14\n" +
+	"\x05final\x18\x04 \x01(\bR\x05final\"\xc0\x02\n" +
+	"\x13QueryMetricsRequest\x12\x1c\n" +
+	"\x04name\x18\x01 \x01(\tB\b\xbaH\x05r\x03\x18\x80\x02R\x04name\x12.\n" +
+	"\x04from\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\x04from\x12*\n" +
+	"\x02to\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\x02to\x12G\n" +
+	"\vaggregation\x18\x04 \x01(\x0e2\x1b.notes.v1.MetricAggregationB\b\xbaH\x05\x82\x01\x02\x10\x01R\vaggregation:f\xbaHc\x1aa\n" +
+	"\x0emetrics_period\x12\x16from must be before to\x1a7!has(this.from) || !has(this.to) || this.from < this.to\"S\n" +
+	"\vMetricPoint\x12\x14\n" +
+	"\x05value\x18\x01 \x01(\x01R\x05value\x12.\n" +
+	"\x04time\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\x04time\"\x8f\x01\n" +
+	"\x14QueryMetricsResponse\x12-\n" +
+	"\x06points\x18\x01 \x03(\v2\x15.notes.v1.MetricPointR\x06points\x12\x1c\n" +
+	"\ttruncated\x18\x02 \x01(\bR\ttruncated\x12\x14\n" +
+	"\x05value\x18\x03 \x01(\x01R\x05value\x12\x14\n" +
+	"\x05count\x18\x04 \x01(\x03R\x05count\"\xc3\x03\n" +
 	"\vChatMessage\x12%\n" +
 	"\x0ecorrelation_id\x18\x01 \x01(\tR\rcorrelationId\x12>\n" +
 	"\ftext_message\x18\x02 \x01(\v2\x19.notes.v1.ChatTextMessageH\x00R\vtextMessage\x12+\n" +
-	"\x05error\x18\x03 \x01(\v2\x13.notes.v1.ChatErrorH\x00R\x05errorB\t\n" +
-	"\acontent\"_\n" +
+	"\x05error\x18\x03 \x01(\v2\x13.notes.v1.ChatErrorH\x00R\x05error\x125\n" +
+	"\tjoin_room\x18\x05 \x01(\v2\x16.notes.v1.ChatJoinRoomH\x00R\bjoinRoom\x128\n" +
+	"\n" +
+	"leave_room\x18\x06 \x01(\v2\x17.notes.v1.ChatLeaveRoomH\x00R\tleaveRoom\x12F\n" +
+	"\x10typing_indicator\x18\a \x01(\v2\x19.notes.v1.TypingIndicatorH\x00R\x0ftypingIndicator\x12C\n" +
+	"\x0fpresence_update\x18\b \x01(\v2\x18.notes.v1.PresenceUpdateH\x00R\x0epresenceUpdate\x12\x17\n" +
+	"\aroom_id\x18\x04 \x01(\tR\x06roomIdB\t\n" +
+	"\acontent\"|\n" +
 	"\x0fChatTextMessage\x12\x12\n" +
 	"\x04text\x18\x01 \x01(\tR\x04text\x128\n" +
-	"\ttimestamp\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\"l\n" +
+	"\ttimestamp\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\x12\x1b\n" +
+	"\tsender_id\x18\x03 \x01(\tR\bsenderId\"2\n" +
+	"\fChatJoinRoom\x12\"\n" +
+	"\fparticipants\x18\x01 \x03(\tR\fparticipants\"\x0f\n" +
+	"\rChatLeaveRoom\"|\n" +
+	"\x0fTypingIndicator\x12\x16\n" +
+	"\x06typing\x18\x01 \x01(\bR\x06typing\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\x128\n" +
+	"\ttimestamp\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\"\x92\x01\n" +
+	"\x0ePresenceUpdate\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12-\n" +
+	"\x05state\x18\x02 \x01(\x0e2\x17.notes.v1.PresenceStateR\x05state\x128\n" +
+	"\ttimestamp\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\"l\n" +
 	"\tChatError\x12+\n" +
 	"\x04code\x18\x01 \x01(\x0e2\x17.notes.v1.ChatErrorCodeR\x04code\x12\x18\n" +
 	"\amessage\x18\x02 \x01(\tR\amessage\x12\x18\n" +
-	"\adetails\x18\x03 \x01(\tR\adetails*\x9b\x01\n" +
+	"\adetails\x18\x03 \x01(\tR\adetails\"^\n" +
+	"\fLoginRequest\x12&\n" +
+	"\busername\x18\x01 \x01(\tB\n" +
+	"\xbaH\ar\x05\x10\x01\x18\xff\x01R\busername\x12&\n" +
+	"\bpassword\x18\x02 \x01(\tB\n" +
+	"\xbaH\ar\x05\x10\x01\x18\x80\bR\bpassword\":\n" +
+	"\x13RefreshTokenRequest\x12#\n" +
+	"\rrefresh_token\x18\x01 \x01(\tR\frefreshToken\"4\n" +
+	"\rLogoutRequest\x12#\n" +
+	"\rrefresh_token\x18\x01 \x01(\tR\frefreshToken\"\x10\n" +
+	"\x0eLogoutResponse\">\n" +
+	"\x18IssueStreamTicketRequest\x12\"\n" +
+	"\x06method\x18\x01 \x01(\tB\n" +
+	"\xbaH\ar\x05\x10\x01\x18\xff\x01R\x06method\"a\n" +
+	"\fStreamTicket\x12\x16\n" +
+	"\x06ticket\x18\x01 \x01(\tR\x06ticket\x129\n" +
+	"\n" +
+	"expires_at\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\texpiresAt\"\xb4\x02\n" +
+	"\n" +
+	"AuthTokens\x12!\n" +
+	"\faccess_token\x18\x01 \x01(\tR\vaccessToken\x12Q\n" +
+	"\x17access_token_expires_at\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\x14accessTokenExpiresAt\x12#\n" +
+	"\rrefresh_token\x18\x03 \x01(\tR\frefreshToken\x12S\n" +
+	"\x18refresh_token_expires_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\x15refreshTokenExpiresAt\x12\x1d\n" +
+	"\n" +
+	"token_type\x18\x05 \x01(\tR\ttokenType\x12\x17\n" +
+	"\auser_id\x18\x06 \x01(\tR\x06userId\"\xa6\x01\n" +
+	"\x04User\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1a\n" +
+	"\busername\x18\x02 \x01(\tR\busername\x12\x14\n" +
+	"\x05roles\x18\x03 \x03(\tR\x05roles\x129\n" +
+	"\n" +
+	"created_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x12!\n" +
+	"\fhas_password\x18\x05 \x01(\bR\vhasPassword\"\xba\x01\n" +
+	"\x11CreateUserRequest\x12\x18\n" +
+	"\x02id\x18\x01 \x01(\tB\b\xbaH\x05r\x03\x18\xff\x01R\x02id\x12$\n" +
+	"\busername\x18\x02 \x01(\tB\b\xbaH\x05r\x03\x18\xff\x01R\busername\x12$\n" +
+	"\bpassword\x18\x03 \x01(\tB\b\xbaH\x05r\x03\x18\x80\bR\bpassword\x12?\n" +
+	"\x05roles\x18\x04 \x03(\tB)\xbaH&\x92\x01#\x10\x10\"\x1fr\x1dR\x04userR\x05adminR\x06readerR\x06writerR\x05roles\",\n" +
+	"\x0eGetUserRequest\x12\x1a\n" +
+	"\x02id\x18\x01 \x01(\tB\n" +
+	"\xbaH\ar\x05\x10\x01\x18\xff\x01R\x02id\"\x12\n" +
+	"\x10ListUsersRequest\"9\n" +
+	"\x11ListUsersResponse\x12$\n" +
+	"\x05users\x18\x01 \x03(\v2\x0e.notes.v1.UserR\x05users\"\xfb\x02\n" +
+	"\x06APIKey\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x17\n" +
+	"\auser_id\x18\x03 \x01(\tR\x06userId\x12\x14\n" +
+	"\x05roles\x18\x04 \x03(\tR\x05roles\x12\x1d\n" +
+	"\n" +
+	"created_by\x18\x05 \x01(\tR\tcreatedBy\x129\n" +
+	"\n" +
+	"created_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"expires_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\texpiresAt\x129\n" +
+	"\n" +
+	"revoked_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\trevokedAt\x12$\n" +
+	"\x0erate_limit_rps\x18\t \x01(\x01R\frateLimitRps\x12(\n" +
+	"\x10rate_limit_burst\x18\n" +
+	" \x01(\x05R\x0erateLimitBurst\"\xac\x02\n" +
+	"\x13CreateAPIKeyRequest\x12\x1c\n" +
+	"\x04name\x18\x01 \x01(\tB\b\xbaH\x05r\x03\x18\xff\x01R\x04name\x12#\n" +
+	"\auser_id\x18\x02 \x01(\tB\n" +
+	"\xbaH\ar\x05\x10\x01\x18\xff\x01R\x06userId\x12?\n" +
+	"\x05roles\x18\x03 \x03(\tB)\xbaH&\x92\x01#\x10\x10\"\x1fr\x1dR\x04userR\x05adminR\x06readerR\x06writerR\x05roles\x12(\n" +
+	"\vttl_seconds\x18\x04 \x01(\x03B\a\xbaH\x04\"\x02(\x00R\n" +
+	"ttlSeconds\x124\n" +
+	"\x0erate_limit_rps\x18\x05 \x01(\x01B\x0e\xbaH\v\x12\t)\x00\x00\x00\x00\x00\x00\x00\x00R\frateLimitRps\x121\n" +
+	"\x10rate_limit_burst\x18\x06 \x01(\x05B\a\xbaH\x04\x1a\x02(\x00R\x0erateLimitBurst\"S\n" +
+	"\x14CreateAPIKeyResponse\x12)\n" +
+	"\aapi_key\x18\x01 \x01(\v2\x10.notes.v1.APIKeyR\x06apiKey\x12\x10\n" +
+	"\x03key\x18\x02 \x01(\tR\x03key\"0\n" +
+	"\x13RevokeAPIKeyRequest\x12\x19\n" +
+	"\x02id\x18\x01 \x01(\tB\t\xbaH\x06r\x04\x10\x01\x18@R\x02id\"\x14\n" +
+	"\x12ListAPIKeysRequest\"B\n" +
+	"\x13ListAPIKeysResponse\x12+\n" +
+	"\bapi_keys\x18\x01 \x03(\v2\x10.notes.v1.APIKeyR\aapiKeys\"\x14\n" +
+	"\x12GetPipelineRequest\"\xa3\x01\n" +
+	"\rPipelineStage\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12A\n" +
+	"\bsettings\x18\x02 \x03(\v2%.notes.v1.PipelineStage.SettingsEntryR\bsettings\x1a;\n" +
+	"\rSettingsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"T\n" +
+	"\rPipelineChain\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12/\n" +
+	"\x06stages\x18\x02 \x03(\v2\x17.notes.v1.PipelineStageR\x06stages\"F\n" +
+	"\x13GetPipelineResponse\x12/\n" +
+	"\x06chains\x18\x01 \x03(\v2\x17.notes.v1.PipelineChainR\x06chains\"\x8d\x02\n" +
+	"\n" +
+	"AuditEvent\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12.\n" +
+	"\x04time\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\x04time\x12\x16\n" +
+	"\x06action\x18\x03 \x01(\tR\x06action\x12\x16\n" +
+	"\x06method\x18\x04 \x01(\tR\x06method\x12\x17\n" +
+	"\anote_id\x18\x05 \x01(\tR\x06noteId\x12\x17\n" +
+	"\auser_id\x18\x06 \x01(\tR\x06userId\x12$\n" +
+	"\x0etarget_user_id\x18\a \x01(\tR\ftargetUserId\x12\x1d\n" +
+	"\n" +
+	"request_id\x18\b \x01(\tR\trequestId\x12\x18\n" +
+	"\aoutcome\x18\t \x01(\tR\aoutcome\"\xa3\x02\n" +
+	"\x16ListAuditEventsRequest\x12!\n" +
+	"\auser_id\x18\x01 \x01(\tB\b\xbaH\x05r\x03\x18\xff\x01R\x06userId\x12!\n" +
+	"\anote_id\x18\x02 \x01(\tB\b\xbaH\x05r\x03\x18\xff\x01R\x06noteId\x12\x1f\n" +
+	"\x06action\x18\x03 \x01(\tB\a\xbaH\x04r\x02\x18@R\x06action\x12.\n" +
+	"\x04from\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\x04from\x12*\n" +
+	"\x02to\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\x02to\x12$\n" +
+	"\tbefore_id\x18\x06 \x01(\x03B\a\xbaH\x04\"\x02(\x00R\bbeforeId\x12 \n" +
+	"\x05limit\x18\a \x01(\x05B\n" +
+	"\xbaH\a\x1a\x05\x18\xe8\a(\x00R\x05limit\"m\n" +
+	"\x17ListAuditEventsResponse\x12,\n" +
+	"\x06events\x18\x01 \x03(\v2\x14.notes.v1.AuditEventR\x06events\x12$\n" +
+	"\x0enext_before_id\x18\x02 \x01(\x03R\fnextBeforeId*f\n" +
+	"\tNoteOrder\x12\x1a\n" +
+	"\x16NOTE_ORDER_UNSPECIFIED\x10\x00\x12\x1d\n" +
+	"\x19NOTE_ORDER_WORD_COUNT_ASC\x10\x01\x12\x1e\n" +
+	"\x1aNOTE_ORDER_WORD_COUNT_DESC\x10\x02*Y\n" +
+	"\n" +
+	"DiffFormat\x12\x1b\n" +
+	"\x17DIFF_FORMAT_UNSPECIFIED\x10\x00\x12\x15\n" +
+	"\x11DIFF_FORMAT_HUNKS\x10\x01\x12\x17\n" +
+	"\x13DIFF_FORMAT_UNIFIED\x10\x02*\x80\x01\n" +
+	"\fDiffLineKind\x12\x1e\n" +
+	"\x1aDIFF_LINE_KIND_UNSPECIFIED\x10\x00\x12\x1a\n" +
+	"\x16DIFF_LINE_KIND_CONTEXT\x10\x01\x12\x18\n" +
+	"\x14DIFF_LINE_KIND_ADDED\x10\x02\x12\x1a\n" +
+	"\x16DIFF_LINE_KIND_REMOVED\x10\x03*j\n" +
+	"\x0fSharePermission\x12 \n" +
+	"\x1cSHARE_PERMISSION_UNSPECIFIED\x10\x00\x12\x19\n" +
+	"\x15SHARE_PERMISSION_READ\x10\x01\x12\x1a\n" +
+	"\x16SHARE_PERMISSION_WRITE\x10\x02*y\n" +
+	"\fExportFormat\x12\x1d\n" +
+	"\x19EXPORT_FORMAT_UNSPECIFIED\x10\x00\x12\x17\n" +
+	"\x13EXPORT_FORMAT_JSONL\x10\x01\x12\x1a\n" +
+	"\x16EXPORT_FORMAT_MARKDOWN\x10\x02\x12\x15\n" +
+	"\x11EXPORT_FORMAT_CSV\x10\x03*b\n" +
+	"\rExportArchive\x12\x1e\n" +
+	"\x1aEXPORT_ARCHIVE_UNSPECIFIED\x10\x00\x12\x19\n" +
+	"\x15EXPORT_ARCHIVE_NDJSON\x10\x01\x12\x16\n" +
+	"\x12EXPORT_ARCHIVE_ZIP\x10\x02*\xab\x01\n" +
+	"\x14ExportOperationState\x12&\n" +
+	"\"EXPORT_OPERATION_STATE_UNSPECIFIED\x10\x00\x12\"\n" +
+	"\x1eEXPORT_OPERATION_STATE_RUNNING\x10\x01\x12$\n" +
+	" EXPORT_OPERATION_STATE_SUCCEEDED\x10\x02\x12!\n" +
+	"\x1dEXPORT_OPERATION_STATE_FAILED\x10\x03*\x97\x01\n" +
+	"\x10KeyRotationState\x12\"\n" +
+	"\x1eKEY_ROTATION_STATE_UNSPECIFIED\x10\x00\x12\x1e\n" +
+	"\x1aKEY_ROTATION_STATE_RUNNING\x10\x01\x12 \n" +
+	"\x1cKEY_ROTATION_STATE_SUCCEEDED\x10\x02\x12\x1d\n" +
+	"\x19KEY_ROTATION_STATE_FAILED\x10\x03*\xb0\x01\n" +
+	"\x16BackupConflictStrategy\x12(\n" +
+	"$BACKUP_CONFLICT_STRATEGY_UNSPECIFIED\x10\x00\x12!\n" +
+	"\x1dBACKUP_CONFLICT_STRATEGY_SKIP\x10\x01\x12&\n" +
+	"\"BACKUP_CONFLICT_STRATEGY_OVERWRITE\x10\x02\x12!\n" +
+	"\x1dBACKUP_CONFLICT_STRATEGY_FAIL\x10\x03*\xbe\x02\n" +
+	"\tEventType\x12\x1a\n" +
+	"\x16EVENT_TYPE_UNSPECIFIED\x10\x00\x12\x1b\n" +
+	"\x17EVENT_TYPE_NOTE_CREATED\x10\x01\x12\x1b\n" +
+	"\x17EVENT_TYPE_NOTE_UPDATED\x10\x02\x12\x1b\n" +
+	"\x17EVENT_TYPE_NOTE_DELETED\x10\x03\x12\x1a\n" +
+	"\x16EVENT_TYPE_NOTE_SHARED\x10\x04\x12 \n" +
+	"\x1cEVENT_TYPE_NOTE_REMINDER_DUE\x10\x05\x12\x1f\n" +
+	"\x1bEVENT_TYPE_EXPORT_COMPLETED\x10\x06\x12#\n" +
+	"\x1fEVENT_TYPE_SAVED_SEARCH_MATCHED\x10\a\x12\x1c\n" +
+	"\x18EVENT_TYPE_QUOTA_WARNING\x10\b\x12\x1c\n" +
+	"\x18EVENT_TYPE_NOTE_ARCHIVED\x10\t*\xe1\x01\n" +
+	"\x11MetricAggregation\x12\"\n" +
+	"\x1eMETRIC_AGGREGATION_UNSPECIFIED\x10\x00\x12\x1c\n" +
+	"\x18METRIC_AGGREGATION_COUNT\x10\x01\x12\x1a\n" +
+	"\x16METRIC_AGGREGATION_SUM\x10\x02\x12\x1a\n" +
+	"\x16METRIC_AGGREGATION_AVG\x10\x03\x12\x1a\n" +
+	"\x16METRIC_AGGREGATION_MIN\x10\x04\x12\x1a\n" +
+	"\x16METRIC_AGGREGATION_MAX\x10\x05\x12\x1a\n" +
+	"\x16METRIC_AGGREGATION_P95\x10\x06*c\n" +
+	"\rPresenceState\x12\x1e\n" +
+	"\x1aPRESENCE_STATE_UNSPECIFIED\x10\x00\x12\x19\n" +
+	"\x15PRESENCE_STATE_JOINED\x10\x01\x12\x17\n" +
+	"\x13PRESENCE_STATE_LEFT\x10\x02*\xe0\x01\n" +
 	"\rChatErrorCode\x12\x1f\n" +
 	"\x1bCHAT_ERROR_CODE_UNSPECIFIED\x10\x00\x12$\n" +
 	" CHAT_ERROR_CODE_VALIDATION_ERROR\x10\x01\x12\x1e\n" +
 	"\x1aCHAT_ERROR_CODE_RATE_LIMIT\x10\x02\x12#\n" +
-	"\x1fCHAT_ERROR_CODE_INVALID_MESSAGE\x10\x032\xb8\x05\n" +
+	"\x1fCHAT_ERROR_CODE_INVALID_MESSAGE\x10\x03\x12\x1f\n" +
+	"\x1bCHAT_ERROR_CODE_NOT_IN_ROOM\x10\x04\x12\"\n" +
+	"\x1eCHAT_ERROR_CODE_TOO_MANY_ROOMS\x10\x052\xf6/\n" +
 	"\fNotesService\x12]\n" +
 	"\n" +
-	"CreateNote\x12\x1b.notes.v1.CreateNoteRequest\x1a\x1c.notes.v1.CreateNoteResponse\"\x14\x82\xd3\xe4\x93\x02\x0e:\x01*\"\t/notes/v1\x12V\n" +
-	"\aGetNote\x12\x18.notes.v1.GetNoteRequest\x1a\x19.notes.v1.GetNoteResponse\"\x16\x82\xd3\xe4\x93\x02\x10\x12\x0e/notes/v1/{id}\x12W\n" +
-	"\tListNotes\x12\x1a.notes.v1.ListNotesRequest\x1a\x1b.notes.v1.ListNotesResponse\"\x11\x82\xd3\xe4\x93\x02\v\x12\t/notes/v1\x12b\n" +
+	"CreateNote\x12\x1b.notes.v1.CreateNoteRequest\x1a\x1c.notes.v1.CreateNoteResponse\"\x14\x82\xd3\xe4\x93\x02\x0e:\x01*\"\t/notes/v1\x12\\\n" +
+	"\aGetNote\x12\x18.notes.v1.GetNoteRequest\x1a\x19.notes.v1.GetNoteResponse\"\x1c\xa2\xbb\x18\x02(\x01\x82\xd3\xe4\x93\x02\x10\x12\x0e/notes/v1/{id}\x12]\n" +
+	"\tListNotes\x12\x1a.notes.v1.ListNotesRequest\x1a\x1b.notes.v1.ListNotesResponse\"\x17\xa2\xbb\x18\x02(\x01\x82\xd3\xe4\x93\x02\v\x12\t/notes/v1\x12W\n" +
+	"\vStreamNotes\x12\x1c.notes.v1.StreamNotesRequest\x1a\x0e.notes.v1.Note\"\x18\x82\xd3\xe4\x93\x02\x12\x12\x10/notes/v1:stream0\x01\x12w\n" +
+	"\n" +
+	"UpdateNote\x12\x1b.notes.v1.UpdateNoteRequest\x1a\x1c.notes.v1.UpdateNoteResponse\".\x82\xd3\xe4\x93\x02(:\x01*Z\x13:\x01*2\x0e/notes/v1/{id}\x1a\x0e/notes/v1/{id}\x12_\n" +
 	"\n" +
-	"UpdateNote\x12\x1b.notes.v1.UpdateNoteRequest\x1a\x1c.notes.v1.UpdateNoteResponse\"\x19\x82\xd3\xe4\x93\x02\x13:\x01*\x1a\x0e/notes/v1/{id}\x12_\n" +
+	"DeleteNote\x12\x1b.notes.v1.DeleteNoteRequest\x1a\x1c.notes.v1.DeleteNoteResponse\"\x16\x82\xd3\xe4\x93\x02\x10*\x0e/notes/v1/{id}\x12Z\n" +
+	"\aPinNote\x12\x18.notes.v1.PinNoteRequest\x1a\x19.notes.v1.PinNoteResponse\"\x1a\x82\xd3\xe4\x93\x02\x14\"\x12/notes/v1/{id}:pin\x12b\n" +
+	"\tUnpinNote\x12\x1a.notes.v1.UnpinNoteRequest\x1a\x1b.notes.v1.UnpinNoteResponse\"\x1c\x82\xd3\xe4\x93\x02\x16\"\x14/notes/v1/{id}:unpin\x12j\n" +
+	"\vArchiveNote\x12\x1c.notes.v1.ArchiveNoteRequest\x1a\x1d.notes.v1.ArchiveNoteResponse\"\x1e\x82\xd3\xe4\x93\x02\x18\"\x16/notes/v1/{id}:archive\x12r\n" +
+	"\rUnarchiveNote\x12\x1e.notes.v1.UnarchiveNoteRequest\x1a\x1f.notes.v1.UnarchiveNoteResponse\" \x82\xd3\xe4\x93\x02\x1a\"\x18/notes/v1/{id}:unarchive\x12a\n" +
+	"\bLockNote\x12\x19.notes.v1.LockNoteRequest\x1a\x1a.notes.v1.LockNoteResponse\"\x1e\x82\xd3\xe4\x93\x02\x18:\x01*\"\x13/notes/v1/{id}:lock\x12i\n" +
 	"\n" +
-	"DeleteNote\x12\x1b.notes.v1.DeleteNoteRequest\x1a\x1c.notes.v1.DeleteNoteResponse\"\x16\x82\xd3\xe4\x93\x02\x10*\x0e/notes/v1/{id}\x12R\n" +
-	"\x11SubscribeToEvents\x12\".notes.v1.SubscribeToEventsRequest\x1a\x17.notes.v1.EventResponse0\x01\x12E\n" +
-	"\rUploadMetrics\x12\x17.notes.v1.MetricRequest\x1a\x19.notes.v1.SummaryResponse(\x01\x128\n" +
-	"\x04Chat\x12\x15.notes.v1.ChatMessage\x1a\x15.notes.v1.ChatMessage(\x010\x01B\x12Z\x10notes/v1;notesv1b\x06proto3"
+	"UnlockNote\x12\x1b.notes.v1.UnlockNoteRequest\x1a\x1c.notes.v1.UnlockNoteResponse\" \x82\xd3\xe4\x93\x02\x1a:\x01*\"\x15/notes/v1/{id}:unlock\x12\x85\x01\n" +
+	"\x11SetNotePassphrase\x12\".notes.v1.SetNotePassphraseRequest\x1a#.notes.v1.SetNotePassphraseResponse\"'\x82\xd3\xe4\x93\x02!:\x01*\"\x1c/notes/v1/{id}:setPassphrase\x12\x83\x01\n" +
+	"\x10BatchCreateNotes\x12!.notes.v1.BatchCreateNotesRequest\x1a\".notes.v1.BatchCreateNotesResponse\"(\xa2\xbb\x18\x04\"\x02\b\x1e\x82\xd3\xe4\x93\x02\x1a:\x01*\"\x15/notes/v1:batchCreate\x12v\n" +
+	"\rBatchGetNotes\x12\x1e.notes.v1.BatchGetNotesRequest\x1a\x1f.notes.v1.BatchGetNotesResponse\"$\xa2\xbb\x18\x06\"\x02\b\x1e(\x01\x82\xd3\xe4\x93\x02\x14\x12\x12/notes/v1:batchGet\x12\x83\x01\n" +
+	"\x10BatchDeleteNotes\x12!.notes.v1.BatchDeleteNotesRequest\x1a\".notes.v1.BatchDeleteNotesResponse\"(\xa2\xbb\x18\x04\"\x02\b\x1e\x82\xd3\xe4\x93\x02\x1a:\x01*\"\x15/notes/v1:batchDelete\x12\x84\x01\n" +
+	"\x11ListNoteRevisions\x12\".notes.v1.ListNoteRevisionsRequest\x1a#.notes.v1.ListNoteRevisionsResponse\"&\xa2\xbb\x18\x02(\x01\x82\xd3\xe4\x93\x02\x1a\x12\x18/notes/v1/{id}/revisions\x12\x89\x01\n" +
+	"\x0fGetNoteRevision\x12 .notes.v1.GetNoteRevisionRequest\x1a!.notes.v1.GetNoteRevisionResponse\"1\xa2\xbb\x18\x02(\x01\x82\xd3\xe4\x93\x02%\x12#/notes/v1/{id}/revisions/{revision}\x12\x89\x01\n" +
+	"\x11DiffNoteRevisions\x12\".notes.v1.DiffNoteRevisionsRequest\x1a#.notes.v1.DiffNoteRevisionsResponse\"+\xa2\xbb\x18\x02(\x01\x82\xd3\xe4\x93\x02\x1f\x12\x1d/notes/v1/{id}/revisions:diff\x12w\n" +
+	"\x0eListNotesByTag\x12\x1f.notes.v1.ListNotesByTagRequest\x1a .notes.v1.ListNotesByTagResponse\"\"\xa2\xbb\x18\x02(\x01\x82\xd3\xe4\x93\x02\x16\x12\x14/notes/v1/tags/{tag}\x12_\n" +
+	"\bListTags\x12\x19.notes.v1.ListTagsRequest\x1a\x1a.notes.v1.ListTagsResponse\"\x1c\xa2\xbb\x18\x02(\x01\x82\xd3\xe4\x93\x02\x10\x12\x0e/notes/v1/tags\x12r\n" +
+	"\fSuggestNotes\x12\x1d.notes.v1.SuggestNotesRequest\x1a\x1e.notes.v1.SuggestNotesResponse\"#\xa2\xbb\x18\x02(\x01\x82\xd3\xe4\x93\x02\x17\x12\x15/notes/v1/suggestions\x12q\n" +
+	"\fGetNoteStats\x12\x1d.notes.v1.GetNoteStatsRequest\x1a\x1e.notes.v1.GetNoteStatsResponse\"\"\xa2\xbb\x18\x02(\x01\x82\xd3\xe4\x93\x02\x16\x12\x14/notes/v1/{id}/stats\x12u\n" +
+	"\x0fGetAccountStats\x12 .notes.v1.GetAccountStatsRequest\x1a!.notes.v1.GetAccountStatsResponse\"\x1d\xa2\xbb\x18\x02(\x01\x82\xd3\xe4\x93\x02\x11\x12\x0f/notes/v1/stats\x12k\n" +
+	"\tShareNote\x12\x1a.notes.v1.ShareNoteRequest\x1a\x1b.notes.v1.ShareNoteResponse\"%\x82\xd3\xe4\x93\x02\x1f:\x01*\"\x1a/notes/v1/{note_id}/shares\x12x\n" +
+	"\vUnshareNote\x12\x1c.notes.v1.UnshareNoteRequest\x1a\x1d.notes.v1.UnshareNoteResponse\",\x82\xd3\xe4\x93\x02&*$/notes/v1/{note_id}/shares/{user_id}\x12v\n" +
+	"\x0fListSharedNotes\x12 .notes.v1.ListSharedNotesRequest\x1a!.notes.v1.ListSharedNotesResponse\"\x1e\xa2\xbb\x18\x02(\x01\x82\xd3\xe4\x93\x02\x12\x12\x10/notes/v1/shared\x12l\n" +
+	"\vExportNotes\x12\x1c.notes.v1.ExportNotesRequest\x1a\x1d.notes.v1.ExportNotesResponse\"\x1e\x82\xd3\xe4\x93\x02\x18\x12\x16/notes/v1/notes:export0\x01\x12\x86\x01\n" +
+	"\x13ExportToDestination\x12$.notes.v1.ExportToDestinationRequest\x1a\x19.notes.v1.ExportOperation\".\x82\xd3\xe4\x93\x02(:\x01*\"#/notes/v1/notes:exportToDestination\x12z\n" +
+	"\x12GetExportOperation\x12#.notes.v1.GetExportOperationRequest\x1a\x19.notes.v1.ExportOperation\"$\xa2\xbb\x18\x02(\x01\x82\xd3\xe4\x93\x02\x18\x12\x16/notes/v1/exports/{id}\x12o\n" +
+	"\vImportNotes\x12\x1c.notes.v1.ImportNotesRequest\x1a\x1d.notes.v1.ImportNotesResponse\"!\x82\xd3\xe4\x93\x02\x1b:\x01*\"\x16/notes/v1/notes:import(\x01\x12u\n" +
+	"\rGetServerInfo\x12\x1e.notes.v1.GetServerInfoRequest\x1a\x1f.notes.v1.GetServerInfoResponse\"#\xa2\xbb\x18\x02(\x01\x82\xd3\xe4\x93\x02\x17\x12\x15/notes/v1/server-info\x12\x8c\x01\n" +
+	"\x11AdminListAllNotes\x12\".notes.v1.AdminListAllNotesRequest\x1a#.notes.v1.AdminListAllNotesResponse\".\xa2\xbb\x18\r\x12\x05admin\"\x02\b<(\x01\x82\xd3\xe4\x93\x02\x17\x12\x15/notes/v1/admin/notes\x12|\n" +
+	"\n" +
+	"RotateKeys\x12\x1b.notes.v1.RotateKeysRequest\x1a\x1e.notes.v1.KeyRotationOperation\"1\xa2\xbb\x18\a\x12\x05admin\x82\xd3\xe4\x93\x02 :\x01*\"\x1b/notes/v1/admin/keys:rotate\x12\x9e\x01\n" +
+	"\x17GetKeyRotationOperation\x12(.notes.v1.GetKeyRotationOperationRequest\x1a\x1e.notes.v1.KeyRotationOperation\"9\xa2\xbb\x18\t\x12\x05admin(\x01\x82\xd3\xe4\x93\x02&\x12$/notes/v1/admin/keys/operations/{id}\x12\x8c\x01\n" +
+	"\rRestoreBackup\x12\x1e.notes.v1.RestoreBackupRequest\x1a\x1f.notes.v1.RestoreBackupResponse\":\xa2\xbb\x18\f\x12\x05admin\"\x03\b\xd8\x04\x82\xd3\xe4\x93\x02$:\x01*\"\x1f/notes/v1/admin/backups:restore\x12|\n" +
+	"\rGetUsageStats\x12\x1e.notes.v1.GetUsageStatsRequest\x1a\x1f.notes.v1.GetUsageStatsResponse\"*\xa2\xbb\x18\t\x12\x05admin(\x01\x82\xd3\xe4\x93\x02\x17\x12\x15/notes/v1/admin/usage\x12e\n" +
+	"\x0fRegisterWebhook\x12 .notes.v1.RegisterWebhookRequest\x1a\x11.notes.v1.Webhook\"\x1d\x82\xd3\xe4\x93\x02\x17:\x01*\"\x12/notes/v1/webhooks\x12o\n" +
+	"\fListWebhooks\x12\x1d.notes.v1.ListWebhooksRequest\x1a\x1e.notes.v1.ListWebhooksResponse\" \xa2\xbb\x18\x02(\x01\x82\xd3\xe4\x93\x02\x14\x12\x12/notes/v1/webhooks\x12q\n" +
+	"\rDeleteWebhook\x12\x1e.notes.v1.DeleteWebhookRequest\x1a\x1f.notes.v1.DeleteWebhookResponse\"\x1f\x82\xd3\xe4\x93\x02\x19*\x17/notes/v1/webhooks/{id}\x12\x9a\x01\n" +
+	"\x16ListWebhookDeadLetters\x12'.notes.v1.ListWebhookDeadLettersRequest\x1a(.notes.v1.ListWebhookDeadLettersResponse\"-\xa2\xbb\x18\x02(\x01\x82\xd3\xe4\x93\x02!\x12\x1f/notes/v1/webhooks/dead-letters\x12k\n" +
+	"\n" +
+	"SaveSearch\x12\x1b.notes.v1.SaveSearchRequest\x1a\x15.notes.v1.SavedSearch\")\xa2\xbb\x18\x02(\x01\x82\xd3\xe4\x93\x02\x1d:\x01*\"\x18/notes/v1/saved-searches\x12\x84\x01\n" +
+	"\x11ListSavedSearches\x12\".notes.v1.ListSavedSearchesRequest\x1a#.notes.v1.ListSavedSearchesResponse\"&\xa2\xbb\x18\x02(\x01\x82\xd3\xe4\x93\x02\x1a\x12\x18/notes/v1/saved-searches\x12\x83\x01\n" +
+	"\x11DeleteSavedSearch\x12\".notes.v1.DeleteSavedSearchRequest\x1a#.notes.v1.DeleteSavedSearchResponse\"%\x82\xd3\xe4\x93\x02\x1f*\x1d/notes/v1/saved-searches/{id}\x12\x92\x01\n" +
+	"\x12ExecuteSavedSearch\x12#.notes.v1.ExecuteSavedSearchRequest\x1a$.notes.v1.ExecuteSavedSearchResponse\"1\xa2\xbb\x18\x02(\x01\x82\xd3\xe4\x93\x02%\x12#/notes/v1/saved-searches/{id}/notes\x12n\n" +
+	"\x10UploadAttachment\x12\x19.notes.v1.AttachmentChunk\x1a\x14.notes.v1.Attachment\"'\x82\xd3\xe4\x93\x02!:\x01*\"\x1c/notes/v1/attachments:upload(\x01\x12\x8f\x01\n" +
+	"\x12DownloadAttachment\x12#.notes.v1.DownloadAttachmentRequest\x1a$.notes.v1.DownloadAttachmentResponse\",\x82\xd3\xe4\x93\x02&\x12$/notes/v1/{note_id}/attachments/{id}0\x01\x12R\n" +
+	"\x11SubscribeToEvents\x12\".notes.v1.SubscribeToEventsRequest\x1a\x17.notes.v1.EventResponse0\x01\x12Y\n" +
+	"\rUploadMetrics\x12\x17.notes.v1.MetricRequest\x1a\x19.notes.v1.SummaryResponse\"\x12\xa2\xbb\x18\x0e\x1a\f\t\x00\x00\x00\x00\x00\x00Y@\x10\xc8\x01(\x01\x12h\n" +
+	"\rStreamMetrics\x12\x1e.notes.v1.StreamMetricsRequest\x1a\x1f.notes.v1.StreamMetricsResponse\"\x12\xa2\xbb\x18\x0e\x1a\f\t\x00\x00\x00\x00\x00\x00Y@\x10\xc8\x01(\x010\x01\x12r\n" +
+	"\fQueryMetrics\x12\x1d.notes.v1.QueryMetricsRequest\x1a\x1e.notes.v1.QueryMetricsResponse\"#\xa2\xbb\x18\x06\"\x02\b\x1e(\x01\x82\xd3\xe4\x93\x02\x13\x12\x11/notes/v1/metrics\x12K\n" +
+	"\x04Chat\x12\x15.notes.v1.ChatMessage\x1a\x15.notes.v1.ChatMessage\"\x11\xa2\xbb\x18\r\x1a\v\t\x00\x00\x00\x00\x00\x00$@\x10\x14(\x010\x012\xa7\x03\n" +
+	"\vAuthService\x12V\n" +
+	"\x05Login\x12\x16.notes.v1.LoginRequest\x1a\x14.notes.v1.AuthTokens\"\x1f\xa2\xbb\x18\x02\b\x00\x82\xd3\xe4\x93\x02\x13:\x01*\"\x0e/auth/v1/login\x12l\n" +
+	"\fRefreshToken\x12\x1d.notes.v1.RefreshTokenRequest\x1a\x14.notes.v1.AuthTokens\"'\xa2\xbb\x18\x02\b\x00\x82\xd3\xe4\x93\x02\x1b:\x01*\"\x16/auth/v1/token:refresh\x12]\n" +
+	"\x06Logout\x12\x17.notes.v1.LogoutRequest\x1a\x18.notes.v1.LogoutResponse\" \xa2\xbb\x18\x02\b\x00\x82\xd3\xe4\x93\x02\x14:\x01*\"\x0f/auth/v1/logout\x12s\n" +
+	"\x11IssueStreamTicket\x12\".notes.v1.IssueStreamTicketRequest\x1a\x16.notes.v1.StreamTicket\"\"\x82\xd3\xe4\x93\x02\x1c:\x01*\"\x17/auth/v1/stream-tickets2\xb4\x02\n" +
+	"\vUserService\x12`\n" +
+	"\n" +
+	"CreateUser\x12\x1b.notes.v1.CreateUserRequest\x1a\x0e.notes.v1.User\"%\xa2\xbb\x18\a\x12\x05admin\x82\xd3\xe4\x93\x02\x14:\x01*\"\x0f/users/v1/users\x12W\n" +
+	"\aGetUser\x12\x18.notes.v1.GetUserRequest\x1a\x0e.notes.v1.User\"\"\xa2\xbb\x18\x02(\x01\x82\xd3\xe4\x93\x02\x16\x12\x14/users/v1/users/{id}\x12j\n" +
+	"\tListUsers\x12\x1a.notes.v1.ListUsersRequest\x1a\x1b.notes.v1.ListUsersResponse\"$\xa2\xbb\x18\t\x12\x05admin(\x01\x82\xd3\xe4\x93\x02\x11\x12\x0f/users/v1/users2\xf0\x04\n" +
+	"\fAdminService\x12w\n" +
+	"\fCreateAPIKey\x12\x1d.notes.v1.CreateAPIKeyRequest\x1a\x1e.notes.v1.CreateAPIKeyResponse\"(\xa2\xbb\x18\a\x12\x05admin\x82\xd3\xe4\x93\x02\x17:\x01*\"\x12/admin/v1/api-keys\x12w\n" +
+	"\fRevokeAPIKey\x12\x1d.notes.v1.RevokeAPIKeyRequest\x1a\x10.notes.v1.APIKey\"6\xa2\xbb\x18\t\x12\x05admin(\x01\x82\xd3\xe4\x93\x02#:\x01*\"\x1e/admin/v1/api-keys/{id}:revoke\x12s\n" +
+	"\vListAPIKeys\x12\x1c.notes.v1.ListAPIKeysRequest\x1a\x1d.notes.v1.ListAPIKeysResponse\"'\xa2\xbb\x18\t\x12\x05admin(\x01\x82\xd3\xe4\x93\x02\x14\x12\x12/admin/v1/api-keys\x12s\n" +
+	"\vGetPipeline\x12\x1c.notes.v1.GetPipelineRequest\x1a\x1d.notes.v1.GetPipelineResponse\"'\xa2\xbb\x18\t\x12\x05admin(\x01\x82\xd3\xe4\x93\x02\x14\x12\x12/admin/v1/pipeline\x12\x83\x01\n" +
+	"\x0fListAuditEvents\x12 .notes.v1.ListAuditEventsRequest\x1a!.notes.v1.ListAuditEventsResponse\"+\xa2\xbb\x18\t\x12\x05admin(\x01\x82\xd3\xe4\x93\x02\x18\x12\x16/admin/v1/audit-events:P\n" +
+	"\x06policy\x12\x1e.google.protobuf.MethodOptions\x18\xb4\x87\x03 \x01(\v2\x16.notes.v1.MethodPolicyR\x06policyB\x12Z\x10notes/v1;notesv1b\x06proto3"
 
 var (
 	file_proto_notes_v1_notes_proto_rawDescOnce sync.Once
@@ -1357,69 +11988,502 @@ func file_proto_notes_v1_notes_proto_rawDescGZIP() []byte {
 	return file_proto_notes_v1_notes_proto_rawDescData
 }
 
-var file_proto_notes_v1_notes_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_proto_notes_v1_notes_proto_msgTypes = make([]protoimpl.MessageInfo, 21)
+var file_proto_notes_v1_notes_proto_enumTypes = make([]protoimpl.EnumInfo, 13)
+var file_proto_notes_v1_notes_proto_msgTypes = make([]protoimpl.MessageInfo, 169)
 var file_proto_notes_v1_notes_proto_goTypes = []any{
-	(ChatErrorCode)(0),               // 0: notes.v1.ChatErrorCode
-	(*CreateNoteRequest)(nil),        // 1: notes.v1.CreateNoteRequest
-	(*CreateNoteResponse)(nil),       // 2: notes.v1.CreateNoteResponse
-	(*GetNoteRequest)(nil),           // 3: notes.v1.GetNoteRequest
-	(*GetNoteResponse)(nil),          // 4: notes.v1.GetNoteResponse
-	(*ListNotesRequest)(nil),         // 5: notes.v1.ListNotesRequest
-	(*ListNotesResponse)(nil),        // 6: notes.v1.ListNotesResponse
-	(*UpdateNoteRequest)(nil),        // 7: notes.v1.UpdateNoteRequest
-	(*UpdateNoteResponse)(nil),       // 8: notes.v1.UpdateNoteResponse
-	(*DeleteNoteRequest)(nil),        // 9: notes.v1.DeleteNoteRequest
-	(*DeleteNoteResponse)(nil),       // 10: notes.v1.DeleteNoteResponse
-	(*Note)(nil),                     // 11: notes.v1.Note
-	(*ErrorDetails)(nil),             // 12: notes.v1.ErrorDetails
-	(*SubscribeToEventsRequest)(nil), // 13: notes.v1.SubscribeToEventsRequest
-	(*EventResponse)(nil),            // 14: notes.v1.EventResponse
-	(*HealthCheck)(nil),              // 15: notes.v1.HealthCheck
-	(*NoteCreatedEvent)(nil),         // 16: notes.v1.NoteCreatedEvent
-	(*MetricRequest)(nil),            // 17: notes.v1.MetricRequest
-	(*SummaryResponse)(nil),          // 18: notes.v1.SummaryResponse
-	(*ChatMessage)(nil),              // 19: notes.v1.ChatMessage
-	(*ChatTextMessage)(nil),          // 20: notes.v1.ChatTextMessage
-	(*ChatError)(nil),                // 21: notes.v1.ChatError
-	(*timestamppb.Timestamp)(nil),    // 22: google.protobuf.Timestamp
+	(NoteOrder)(0),                         // 0: notes.v1.NoteOrder
+	(DiffFormat)(0),                        // 1: notes.v1.DiffFormat
+	(DiffLineKind)(0),                      // 2: notes.v1.DiffLineKind
+	(SharePermission)(0),                   // 3: notes.v1.SharePermission
+	(ExportFormat)(0),                      // 4: notes.v1.ExportFormat
+	(ExportArchive)(0),                     // 5: notes.v1.ExportArchive
+	(ExportOperationState)(0),              // 6: notes.v1.ExportOperationState
+	(KeyRotationState)(0),                  // 7: notes.v1.KeyRotationState
+	(BackupConflictStrategy)(0),            // 8: notes.v1.BackupConflictStrategy
+	(EventType)(0),                         // 9: notes.v1.EventType
+	(MetricAggregation)(0),                 // 10: notes.v1.MetricAggregation
+	(PresenceState)(0),                     // 11: notes.v1.PresenceState
+	(ChatErrorCode)(0),                     // 12: notes.v1.ChatErrorCode
+	(*MethodPolicy)(nil),                   // 13: notes.v1.MethodPolicy
+	(*StreamRateLimitPolicy)(nil),          // 14: notes.v1.StreamRateLimitPolicy
+	(*CreateNoteRequest)(nil),              // 15: notes.v1.CreateNoteRequest
+	(*CreateNoteResponse)(nil),             // 16: notes.v1.CreateNoteResponse
+	(*Warning)(nil),                        // 17: notes.v1.Warning
+	(*GetNoteRequest)(nil),                 // 18: notes.v1.GetNoteRequest
+	(*GetNoteResponse)(nil),                // 19: notes.v1.GetNoteResponse
+	(*ListNotesRequest)(nil),               // 20: notes.v1.ListNotesRequest
+	(*ListNotesResponse)(nil),              // 21: notes.v1.ListNotesResponse
+	(*StreamNotesRequest)(nil),             // 22: notes.v1.StreamNotesRequest
+	(*UpdateNoteRequest)(nil),              // 23: notes.v1.UpdateNoteRequest
+	(*UpdateNoteResponse)(nil),             // 24: notes.v1.UpdateNoteResponse
+	(*DeleteNoteRequest)(nil),              // 25: notes.v1.DeleteNoteRequest
+	(*DeleteNoteResponse)(nil),             // 26: notes.v1.DeleteNoteResponse
+	(*PinNoteRequest)(nil),                 // 27: notes.v1.PinNoteRequest
+	(*PinNoteResponse)(nil),                // 28: notes.v1.PinNoteResponse
+	(*UnpinNoteRequest)(nil),               // 29: notes.v1.UnpinNoteRequest
+	(*UnpinNoteResponse)(nil),              // 30: notes.v1.UnpinNoteResponse
+	(*ArchiveNoteRequest)(nil),             // 31: notes.v1.ArchiveNoteRequest
+	(*ArchiveNoteResponse)(nil),            // 32: notes.v1.ArchiveNoteResponse
+	(*UnarchiveNoteRequest)(nil),           // 33: notes.v1.UnarchiveNoteRequest
+	(*UnarchiveNoteResponse)(nil),          // 34: notes.v1.UnarchiveNoteResponse
+	(*LockNoteRequest)(nil),                // 35: notes.v1.LockNoteRequest
+	(*LockNoteResponse)(nil),               // 36: notes.v1.LockNoteResponse
+	(*UnlockNoteRequest)(nil),              // 37: notes.v1.UnlockNoteRequest
+	(*UnlockNoteResponse)(nil),             // 38: notes.v1.UnlockNoteResponse
+	(*SetNotePassphraseRequest)(nil),       // 39: notes.v1.SetNotePassphraseRequest
+	(*SetNotePassphraseResponse)(nil),      // 40: notes.v1.SetNotePassphraseResponse
+	(*NoteLock)(nil),                       // 41: notes.v1.NoteLock
+	(*BatchCreateNotesRequest)(nil),        // 42: notes.v1.BatchCreateNotesRequest
+	(*BatchCreateNotesResponse)(nil),       // 43: notes.v1.BatchCreateNotesResponse
+	(*BatchGetNotesRequest)(nil),           // 44: notes.v1.BatchGetNotesRequest
+	(*BatchGetNotesResponse)(nil),          // 45: notes.v1.BatchGetNotesResponse
+	(*BatchDeleteNotesRequest)(nil),        // 46: notes.v1.BatchDeleteNotesRequest
+	(*BatchDeleteNotesResponse)(nil),       // 47: notes.v1.BatchDeleteNotesResponse
+	(*BatchNoteResult)(nil),                // 48: notes.v1.BatchNoteResult
+	(*ListNoteRevisionsRequest)(nil),       // 49: notes.v1.ListNoteRevisionsRequest
+	(*ListNoteRevisionsResponse)(nil),      // 50: notes.v1.ListNoteRevisionsResponse
+	(*GetNoteRevisionRequest)(nil),         // 51: notes.v1.GetNoteRevisionRequest
+	(*GetNoteRevisionResponse)(nil),        // 52: notes.v1.GetNoteRevisionResponse
+	(*DiffNoteRevisionsRequest)(nil),       // 53: notes.v1.DiffNoteRevisionsRequest
+	(*DiffNoteRevisionsResponse)(nil),      // 54: notes.v1.DiffNoteRevisionsResponse
+	(*DiffHunk)(nil),                       // 55: notes.v1.DiffHunk
+	(*DiffLine)(nil),                       // 56: notes.v1.DiffLine
+	(*NoteRevision)(nil),                   // 57: notes.v1.NoteRevision
+	(*ListNotesByTagRequest)(nil),          // 58: notes.v1.ListNotesByTagRequest
+	(*ListNotesByTagResponse)(nil),         // 59: notes.v1.ListNotesByTagResponse
+	(*ListTagsRequest)(nil),                // 60: notes.v1.ListTagsRequest
+	(*ListTagsResponse)(nil),               // 61: notes.v1.ListTagsResponse
+	(*SuggestNotesRequest)(nil),            // 62: notes.v1.SuggestNotesRequest
+	(*SuggestNotesResponse)(nil),           // 63: notes.v1.SuggestNotesResponse
+	(*NoteSuggestion)(nil),                 // 64: notes.v1.NoteSuggestion
+	(*GetNoteStatsRequest)(nil),            // 65: notes.v1.GetNoteStatsRequest
+	(*GetNoteStatsResponse)(nil),           // 66: notes.v1.GetNoteStatsResponse
+	(*NoteStats)(nil),                      // 67: notes.v1.NoteStats
+	(*NoteEditDelta)(nil),                  // 68: notes.v1.NoteEditDelta
+	(*GetAccountStatsRequest)(nil),         // 69: notes.v1.GetAccountStatsRequest
+	(*GetAccountStatsResponse)(nil),        // 70: notes.v1.GetAccountStatsResponse
+	(*AccountStats)(nil),                   // 71: notes.v1.AccountStats
+	(*Share)(nil),                          // 72: notes.v1.Share
+	(*ShareNoteRequest)(nil),               // 73: notes.v1.ShareNoteRequest
+	(*ShareNoteResponse)(nil),              // 74: notes.v1.ShareNoteResponse
+	(*UnshareNoteRequest)(nil),             // 75: notes.v1.UnshareNoteRequest
+	(*UnshareNoteResponse)(nil),            // 76: notes.v1.UnshareNoteResponse
+	(*ListSharedNotesRequest)(nil),         // 77: notes.v1.ListSharedNotesRequest
+	(*SharedNote)(nil),                     // 78: notes.v1.SharedNote
+	(*ListSharedNotesResponse)(nil),        // 79: notes.v1.ListSharedNotesResponse
+	(*ExportNotesRequest)(nil),             // 80: notes.v1.ExportNotesRequest
+	(*ExportNotesResponse)(nil),            // 81: notes.v1.ExportNotesResponse
+	(*ExportToDestinationRequest)(nil),     // 82: notes.v1.ExportToDestinationRequest
+	(*GetExportOperationRequest)(nil),      // 83: notes.v1.GetExportOperationRequest
+	(*ExportOperation)(nil),                // 84: notes.v1.ExportOperation
+	(*RotateKeysRequest)(nil),              // 85: notes.v1.RotateKeysRequest
+	(*GetKeyRotationOperationRequest)(nil), // 86: notes.v1.GetKeyRotationOperationRequest
+	(*KeyRotationOperation)(nil),           // 87: notes.v1.KeyRotationOperation
+	(*ExportCompletedEvent)(nil),           // 88: notes.v1.ExportCompletedEvent
+	(*ImportNotesRequest)(nil),             // 89: notes.v1.ImportNotesRequest
+	(*ImportNotesResponse)(nil),            // 90: notes.v1.ImportNotesResponse
+	(*GetServerInfoRequest)(nil),           // 91: notes.v1.GetServerInfoRequest
+	(*GetServerInfoResponse)(nil),          // 92: notes.v1.GetServerInfoResponse
+	(*BackupStatus)(nil),                   // 93: notes.v1.BackupStatus
+	(*RestoreBackupRequest)(nil),           // 94: notes.v1.RestoreBackupRequest
+	(*RestoreBackupResponse)(nil),          // 95: notes.v1.RestoreBackupResponse
+	(*GetUsageStatsRequest)(nil),           // 96: notes.v1.GetUsageStatsRequest
+	(*GetUsageStatsResponse)(nil),          // 97: notes.v1.GetUsageStatsResponse
+	(*MethodUsage)(nil),                    // 98: notes.v1.MethodUsage
+	(*FeatureUsage)(nil),                   // 99: notes.v1.FeatureUsage
+	(*UsageReporting)(nil),                 // 100: notes.v1.UsageReporting
+	(*AdminListAllNotesRequest)(nil),       // 101: notes.v1.AdminListAllNotesRequest
+	(*AdminListAllNotesResponse)(nil),      // 102: notes.v1.AdminListAllNotesResponse
+	(*TagCount)(nil),                       // 103: notes.v1.TagCount
+	(*AttachmentChunk)(nil),                // 104: notes.v1.AttachmentChunk
+	(*AttachmentMetadata)(nil),             // 105: notes.v1.AttachmentMetadata
+	(*Attachment)(nil),                     // 106: notes.v1.Attachment
+	(*DownloadAttachmentRequest)(nil),      // 107: notes.v1.DownloadAttachmentRequest
+	(*DownloadAttachmentResponse)(nil),     // 108: notes.v1.DownloadAttachmentResponse
+	(*Note)(nil),                           // 109: notes.v1.Note
+	(*ErrorDetails)(nil),                   // 110: notes.v1.ErrorDetails
+	(*Webhook)(nil),                        // 111: notes.v1.Webhook
+	(*RegisterWebhookRequest)(nil),         // 112: notes.v1.RegisterWebhookRequest
+	(*ListWebhooksRequest)(nil),            // 113: notes.v1.ListWebhooksRequest
+	(*ListWebhooksResponse)(nil),           // 114: notes.v1.ListWebhooksResponse
+	(*DeleteWebhookRequest)(nil),           // 115: notes.v1.DeleteWebhookRequest
+	(*DeleteWebhookResponse)(nil),          // 116: notes.v1.DeleteWebhookResponse
+	(*ListWebhookDeadLettersRequest)(nil),  // 117: notes.v1.ListWebhookDeadLettersRequest
+	(*ListWebhookDeadLettersResponse)(nil), // 118: notes.v1.ListWebhookDeadLettersResponse
+	(*SavedSearch)(nil),                    // 119: notes.v1.SavedSearch
+	(*SaveSearchRequest)(nil),              // 120: notes.v1.SaveSearchRequest
+	(*ListSavedSearchesRequest)(nil),       // 121: notes.v1.ListSavedSearchesRequest
+	(*ListSavedSearchesResponse)(nil),      // 122: notes.v1.ListSavedSearchesResponse
+	(*DeleteSavedSearchRequest)(nil),       // 123: notes.v1.DeleteSavedSearchRequest
+	(*DeleteSavedSearchResponse)(nil),      // 124: notes.v1.DeleteSavedSearchResponse
+	(*ExecuteSavedSearchRequest)(nil),      // 125: notes.v1.ExecuteSavedSearchRequest
+	(*ExecuteSavedSearchResponse)(nil),     // 126: notes.v1.ExecuteSavedSearchResponse
+	(*WebhookDeadLetter)(nil),              // 127: notes.v1.WebhookDeadLetter
+	(*SubscribeToEventsRequest)(nil),       // 128: notes.v1.SubscribeToEventsRequest
+	(*EventResponse)(nil),                  // 129: notes.v1.EventResponse
+	(*HealthCheck)(nil),                    // 130: notes.v1.HealthCheck
+	(*StreamGoAway)(nil),                   // 131: notes.v1.StreamGoAway
+	(*SavedSearchMatchedEvent)(nil),        // 132: notes.v1.SavedSearchMatchedEvent
+	(*QuotaWarningEvent)(nil),              // 133: notes.v1.QuotaWarningEvent
+	(*NoteCreatedEvent)(nil),               // 134: notes.v1.NoteCreatedEvent
+	(*NoteUpdatedEvent)(nil),               // 135: notes.v1.NoteUpdatedEvent
+	(*NoteDeletedEvent)(nil),               // 136: notes.v1.NoteDeletedEvent
+	(*NoteArchivedEvent)(nil),              // 137: notes.v1.NoteArchivedEvent
+	(*NoteSharedEvent)(nil),                // 138: notes.v1.NoteSharedEvent
+	(*NoteReminderDue)(nil),                // 139: notes.v1.NoteReminderDue
+	(*MetricRequest)(nil),                  // 140: notes.v1.MetricRequest
+	(*SummaryResponse)(nil),                // 141: notes.v1.SummaryResponse
+	(*MetricSummary)(nil),                  // 142: notes.v1.MetricSummary
+	(*StreamMetricsRequest)(nil),           // 143: notes.v1.StreamMetricsRequest
+	(*StreamMetricsOptions)(nil),           // 144: notes.v1.StreamMetricsOptions
+	(*StreamMetricsResponse)(nil),          // 145: notes.v1.StreamMetricsResponse
+	(*QueryMetricsRequest)(nil),            // 146: notes.v1.QueryMetricsRequest
+	(*MetricPoint)(nil),                    // 147: notes.v1.MetricPoint
+	(*QueryMetricsResponse)(nil),           // 148: notes.v1.QueryMetricsResponse
+	(*ChatMessage)(nil),                    // 149: notes.v1.ChatMessage
+	(*ChatTextMessage)(nil),                // 150: notes.v1.ChatTextMessage
+	(*ChatJoinRoom)(nil),                   // 151: notes.v1.ChatJoinRoom
+	(*ChatLeaveRoom)(nil),                  // 152: notes.v1.ChatLeaveRoom
+	(*TypingIndicator)(nil),                // 153: notes.v1.TypingIndicator
+	(*PresenceUpdate)(nil),                 // 154: notes.v1.PresenceUpdate
+	(*ChatError)(nil),                      // 155: notes.v1.ChatError
+	(*LoginRequest)(nil),                   // 156: notes.v1.LoginRequest
+	(*RefreshTokenRequest)(nil),            // 157: notes.v1.RefreshTokenRequest
+	(*LogoutRequest)(nil),                  // 158: notes.v1.LogoutRequest
+	(*LogoutResponse)(nil),                 // 159: notes.v1.LogoutResponse
+	(*IssueStreamTicketRequest)(nil),       // 160: notes.v1.IssueStreamTicketRequest
+	(*StreamTicket)(nil),                   // 161: notes.v1.StreamTicket
+	(*AuthTokens)(nil),                     // 162: notes.v1.AuthTokens
+	(*User)(nil),                           // 163: notes.v1.User
+	(*CreateUserRequest)(nil),              // 164: notes.v1.CreateUserRequest
+	(*GetUserRequest)(nil),                 // 165: notes.v1.GetUserRequest
+	(*ListUsersRequest)(nil),               // 166: notes.v1.ListUsersRequest
+	(*ListUsersResponse)(nil),              // 167: notes.v1.ListUsersResponse
+	(*APIKey)(nil),                         // 168: notes.v1.APIKey
+	(*CreateAPIKeyRequest)(nil),            // 169: notes.v1.CreateAPIKeyRequest
+	(*CreateAPIKeyResponse)(nil),           // 170: notes.v1.CreateAPIKeyResponse
+	(*RevokeAPIKeyRequest)(nil),            // 171: notes.v1.RevokeAPIKeyRequest
+	(*ListAPIKeysRequest)(nil),             // 172: notes.v1.ListAPIKeysRequest
+	(*ListAPIKeysResponse)(nil),            // 173: notes.v1.ListAPIKeysResponse
+	(*GetPipelineRequest)(nil),             // 174: notes.v1.GetPipelineRequest
+	(*PipelineStage)(nil),                  // 175: notes.v1.PipelineStage
+	(*PipelineChain)(nil),                  // 176: notes.v1.PipelineChain
+	(*GetPipelineResponse)(nil),            // 177: notes.v1.GetPipelineResponse
+	(*AuditEvent)(nil),                     // 178: notes.v1.AuditEvent
+	(*ListAuditEventsRequest)(nil),         // 179: notes.v1.ListAuditEventsRequest
+	(*ListAuditEventsResponse)(nil),        // 180: notes.v1.ListAuditEventsResponse
+	nil,                                    // 181: notes.v1.PipelineStage.SettingsEntry
+	(*durationpb.Duration)(nil),            // 182: google.protobuf.Duration
+	(*timestamppb.Timestamp)(nil),          // 183: google.protobuf.Timestamp
+	(*fieldmaskpb.FieldMask)(nil),          // 184: google.protobuf.FieldMask
+	(*status.Status)(nil),                  // 185: google.rpc.Status
+	(*descriptorpb.MethodOptions)(nil),     // 186: google.protobuf.MethodOptions
 }
 var file_proto_notes_v1_notes_proto_depIdxs = []int32{
-	11, // 0: notes.v1.CreateNoteResponse.note:type_name -> notes.v1.Note
-	11, // 1: notes.v1.GetNoteResponse.note:type_name -> notes.v1.Note
-	11, // 2: notes.v1.ListNotesResponse.notes:type_name -> notes.v1.Note
-	11, // 3: notes.v1.UpdateNoteResponse.note:type_name -> notes.v1.Note
-	22, // 4: notes.v1.Note.created_at:type_name -> google.protobuf.Timestamp
-	22, // 5: notes.v1.Note.updated_at:type_name -> google.protobuf.Timestamp
-	15, // 6: notes.v1.EventResponse.health_check:type_name -> notes.v1.HealthCheck
-	16, // 7: notes.v1.EventResponse.note_created:type_name -> notes.v1.NoteCreatedEvent
-	22, // 8: notes.v1.HealthCheck.timestamp:type_name -> google.protobuf.Timestamp
-	11, // 9: notes.v1.NoteCreatedEvent.note:type_name -> notes.v1.Note
-	20, // 10: notes.v1.ChatMessage.text_message:type_name -> notes.v1.ChatTextMessage
-	21, // 11: notes.v1.ChatMessage.error:type_name -> notes.v1.ChatError
-	22, // 12: notes.v1.ChatTextMessage.timestamp:type_name -> google.protobuf.Timestamp
-	0,  // 13: notes.v1.ChatError.code:type_name -> notes.v1.ChatErrorCode
-	1,  // 14: notes.v1.NotesService.CreateNote:input_type -> notes.v1.CreateNoteRequest
-	3,  // 15: notes.v1.NotesService.GetNote:input_type -> notes.v1.GetNoteRequest
-	5,  // 16: notes.v1.NotesService.ListNotes:input_type -> notes.v1.ListNotesRequest
-	7,  // 17: notes.v1.NotesService.UpdateNote:input_type -> notes.v1.UpdateNoteRequest
-	9,  // 18: notes.v1.NotesService.DeleteNote:input_type -> notes.v1.DeleteNoteRequest
-	13, // 19: notes.v1.NotesService.SubscribeToEvents:input_type -> notes.v1.SubscribeToEventsRequest
-	17, // 20: notes.v1.NotesService.UploadMetrics:input_type -> notes.v1.MetricRequest
-	19, // 21: notes.v1.NotesService.Chat:input_type -> notes.v1.ChatMessage
-	2,  // 22: notes.v1.NotesService.CreateNote:output_type -> notes.v1.CreateNoteResponse
-	4,  // 23: notes.v1.NotesService.GetNote:output_type -> notes.v1.GetNoteResponse
-	6,  // 24: notes.v1.NotesService.ListNotes:output_type -> notes.v1.ListNotesResponse
-	8,  // 25: notes.v1.NotesService.UpdateNote:output_type -> notes.v1.UpdateNoteResponse
-	10, // 26: notes.v1.NotesService.DeleteNote:output_type -> notes.v1.DeleteNoteResponse
-	14, // 27: notes.v1.NotesService.SubscribeToEvents:output_type -> notes.v1.EventResponse
-	18, // 28: notes.v1.NotesService.UploadMetrics:output_type -> notes.v1.SummaryResponse
-	19, // 29: notes.v1.NotesService.Chat:output_type -> notes.v1.ChatMessage
-	22, // [22:30] is the sub-list for method output_type
-	14, // [14:22] is the sub-list for method input_type
-	14, // [14:14] is the sub-list for extension type_name
-	14, // [14:14] is the sub-list for extension extendee
-	0,  // [0:14] is the sub-list for field type_name
+	14,  // 0: notes.v1.MethodPolicy.rate_limit:type_name -> notes.v1.StreamRateLimitPolicy
+	182, // 1: notes.v1.MethodPolicy.timeout:type_name -> google.protobuf.Duration
+	183, // 2: notes.v1.CreateNoteRequest.remind_at:type_name -> google.protobuf.Timestamp
+	109, // 3: notes.v1.CreateNoteResponse.note:type_name -> notes.v1.Note
+	17,  // 4: notes.v1.CreateNoteResponse.warnings:type_name -> notes.v1.Warning
+	184, // 5: notes.v1.GetNoteRequest.read_mask:type_name -> google.protobuf.FieldMask
+	109, // 6: notes.v1.GetNoteResponse.note:type_name -> notes.v1.Note
+	17,  // 7: notes.v1.GetNoteResponse.warnings:type_name -> notes.v1.Warning
+	0,   // 8: notes.v1.ListNotesRequest.order_by:type_name -> notes.v1.NoteOrder
+	184, // 9: notes.v1.ListNotesRequest.read_mask:type_name -> google.protobuf.FieldMask
+	109, // 10: notes.v1.ListNotesResponse.notes:type_name -> notes.v1.Note
+	17,  // 11: notes.v1.ListNotesResponse.warnings:type_name -> notes.v1.Warning
+	184, // 12: notes.v1.UpdateNoteRequest.update_mask:type_name -> google.protobuf.FieldMask
+	183, // 13: notes.v1.UpdateNoteRequest.remind_at:type_name -> google.protobuf.Timestamp
+	109, // 14: notes.v1.UpdateNoteResponse.note:type_name -> notes.v1.Note
+	17,  // 15: notes.v1.UpdateNoteResponse.warnings:type_name -> notes.v1.Warning
+	109, // 16: notes.v1.PinNoteResponse.note:type_name -> notes.v1.Note
+	109, // 17: notes.v1.UnpinNoteResponse.note:type_name -> notes.v1.Note
+	109, // 18: notes.v1.ArchiveNoteResponse.note:type_name -> notes.v1.Note
+	109, // 19: notes.v1.UnarchiveNoteResponse.note:type_name -> notes.v1.Note
+	41,  // 20: notes.v1.LockNoteResponse.lock:type_name -> notes.v1.NoteLock
+	109, // 21: notes.v1.SetNotePassphraseResponse.note:type_name -> notes.v1.Note
+	183, // 22: notes.v1.NoteLock.acquired_at:type_name -> google.protobuf.Timestamp
+	183, // 23: notes.v1.NoteLock.expires_at:type_name -> google.protobuf.Timestamp
+	15,  // 24: notes.v1.BatchCreateNotesRequest.notes:type_name -> notes.v1.CreateNoteRequest
+	48,  // 25: notes.v1.BatchCreateNotesResponse.results:type_name -> notes.v1.BatchNoteResult
+	48,  // 26: notes.v1.BatchGetNotesResponse.results:type_name -> notes.v1.BatchNoteResult
+	17,  // 27: notes.v1.BatchGetNotesResponse.warnings:type_name -> notes.v1.Warning
+	48,  // 28: notes.v1.BatchDeleteNotesResponse.results:type_name -> notes.v1.BatchNoteResult
+	109, // 29: notes.v1.BatchNoteResult.note:type_name -> notes.v1.Note
+	185, // 30: notes.v1.BatchNoteResult.status:type_name -> google.rpc.Status
+	57,  // 31: notes.v1.ListNoteRevisionsResponse.revisions:type_name -> notes.v1.NoteRevision
+	57,  // 32: notes.v1.GetNoteRevisionResponse.revision:type_name -> notes.v1.NoteRevision
+	1,   // 33: notes.v1.DiffNoteRevisionsRequest.format:type_name -> notes.v1.DiffFormat
+	55,  // 34: notes.v1.DiffNoteRevisionsResponse.hunks:type_name -> notes.v1.DiffHunk
+	56,  // 35: notes.v1.DiffHunk.lines:type_name -> notes.v1.DiffLine
+	2,   // 36: notes.v1.DiffLine.kind:type_name -> notes.v1.DiffLineKind
+	183, // 37: notes.v1.NoteRevision.created_at:type_name -> google.protobuf.Timestamp
+	109, // 38: notes.v1.ListNotesByTagResponse.notes:type_name -> notes.v1.Note
+	17,  // 39: notes.v1.ListNotesByTagResponse.warnings:type_name -> notes.v1.Warning
+	103, // 40: notes.v1.ListTagsResponse.tags:type_name -> notes.v1.TagCount
+	17,  // 41: notes.v1.ListTagsResponse.warnings:type_name -> notes.v1.Warning
+	64,  // 42: notes.v1.SuggestNotesResponse.suggestions:type_name -> notes.v1.NoteSuggestion
+	183, // 43: notes.v1.NoteSuggestion.updated_at:type_name -> google.protobuf.Timestamp
+	67,  // 44: notes.v1.GetNoteStatsResponse.stats:type_name -> notes.v1.NoteStats
+	183, // 45: notes.v1.NoteStats.updated_at:type_name -> google.protobuf.Timestamp
+	68,  // 46: notes.v1.NoteStats.last_edit:type_name -> notes.v1.NoteEditDelta
+	71,  // 47: notes.v1.GetAccountStatsResponse.stats:type_name -> notes.v1.AccountStats
+	103, // 48: notes.v1.AccountStats.notes_per_tag:type_name -> notes.v1.TagCount
+	3,   // 49: notes.v1.Share.permission:type_name -> notes.v1.SharePermission
+	183, // 50: notes.v1.Share.created_at:type_name -> google.protobuf.Timestamp
+	3,   // 51: notes.v1.ShareNoteRequest.permission:type_name -> notes.v1.SharePermission
+	72,  // 52: notes.v1.ShareNoteResponse.share:type_name -> notes.v1.Share
+	109, // 53: notes.v1.SharedNote.note:type_name -> notes.v1.Note
+	3,   // 54: notes.v1.SharedNote.permission:type_name -> notes.v1.SharePermission
+	78,  // 55: notes.v1.ListSharedNotesResponse.notes:type_name -> notes.v1.SharedNote
+	4,   // 56: notes.v1.ExportNotesRequest.format:type_name -> notes.v1.ExportFormat
+	5,   // 57: notes.v1.ExportToDestinationRequest.archive:type_name -> notes.v1.ExportArchive
+	6,   // 58: notes.v1.ExportOperation.state:type_name -> notes.v1.ExportOperationState
+	5,   // 59: notes.v1.ExportOperation.archive:type_name -> notes.v1.ExportArchive
+	185, // 60: notes.v1.ExportOperation.error:type_name -> google.rpc.Status
+	183, // 61: notes.v1.ExportOperation.created_at:type_name -> google.protobuf.Timestamp
+	183, // 62: notes.v1.ExportOperation.completed_at:type_name -> google.protobuf.Timestamp
+	7,   // 63: notes.v1.KeyRotationOperation.state:type_name -> notes.v1.KeyRotationState
+	185, // 64: notes.v1.KeyRotationOperation.error:type_name -> google.rpc.Status
+	183, // 65: notes.v1.KeyRotationOperation.created_at:type_name -> google.protobuf.Timestamp
+	183, // 66: notes.v1.KeyRotationOperation.completed_at:type_name -> google.protobuf.Timestamp
+	84,  // 67: notes.v1.ExportCompletedEvent.operation:type_name -> notes.v1.ExportOperation
+	4,   // 68: notes.v1.ImportNotesRequest.format:type_name -> notes.v1.ExportFormat
+	93,  // 69: notes.v1.GetServerInfoResponse.backup:type_name -> notes.v1.BackupStatus
+	183, // 70: notes.v1.BackupStatus.last_backup_time:type_name -> google.protobuf.Timestamp
+	183, // 71: notes.v1.BackupStatus.last_attempt_time:type_name -> google.protobuf.Timestamp
+	185, // 72: notes.v1.BackupStatus.last_error:type_name -> google.rpc.Status
+	183, // 73: notes.v1.BackupStatus.next_backup_time:type_name -> google.protobuf.Timestamp
+	8,   // 74: notes.v1.RestoreBackupRequest.conflict_strategy:type_name -> notes.v1.BackupConflictStrategy
+	183, // 75: notes.v1.GetUsageStatsResponse.since:type_name -> google.protobuf.Timestamp
+	98,  // 76: notes.v1.GetUsageStatsResponse.methods:type_name -> notes.v1.MethodUsage
+	99,  // 77: notes.v1.GetUsageStatsResponse.features:type_name -> notes.v1.FeatureUsage
+	100, // 78: notes.v1.GetUsageStatsResponse.reporting:type_name -> notes.v1.UsageReporting
+	183, // 79: notes.v1.UsageReporting.last_report_time:type_name -> google.protobuf.Timestamp
+	185, // 80: notes.v1.UsageReporting.last_error:type_name -> google.rpc.Status
+	109, // 81: notes.v1.AdminListAllNotesResponse.notes:type_name -> notes.v1.Note
+	105, // 82: notes.v1.AttachmentChunk.metadata:type_name -> notes.v1.AttachmentMetadata
+	183, // 83: notes.v1.Attachment.created_at:type_name -> google.protobuf.Timestamp
+	106, // 84: notes.v1.DownloadAttachmentResponse.attachment:type_name -> notes.v1.Attachment
+	183, // 85: notes.v1.Note.created_at:type_name -> google.protobuf.Timestamp
+	183, // 86: notes.v1.Note.updated_at:type_name -> google.protobuf.Timestamp
+	183, // 87: notes.v1.Note.remind_at:type_name -> google.protobuf.Timestamp
+	182, // 88: notes.v1.Note.reading_time:type_name -> google.protobuf.Duration
+	9,   // 89: notes.v1.Webhook.event_types:type_name -> notes.v1.EventType
+	183, // 90: notes.v1.Webhook.created_at:type_name -> google.protobuf.Timestamp
+	9,   // 91: notes.v1.RegisterWebhookRequest.event_types:type_name -> notes.v1.EventType
+	111, // 92: notes.v1.ListWebhooksResponse.webhooks:type_name -> notes.v1.Webhook
+	127, // 93: notes.v1.ListWebhookDeadLettersResponse.dead_letters:type_name -> notes.v1.WebhookDeadLetter
+	183, // 94: notes.v1.SavedSearch.created_at:type_name -> google.protobuf.Timestamp
+	183, // 95: notes.v1.SavedSearch.updated_at:type_name -> google.protobuf.Timestamp
+	119, // 96: notes.v1.ListSavedSearchesResponse.saved_searches:type_name -> notes.v1.SavedSearch
+	0,   // 97: notes.v1.ExecuteSavedSearchRequest.order_by:type_name -> notes.v1.NoteOrder
+	184, // 98: notes.v1.ExecuteSavedSearchRequest.read_mask:type_name -> google.protobuf.FieldMask
+	119, // 99: notes.v1.ExecuteSavedSearchResponse.saved_search:type_name -> notes.v1.SavedSearch
+	109, // 100: notes.v1.ExecuteSavedSearchResponse.notes:type_name -> notes.v1.Note
+	17,  // 101: notes.v1.ExecuteSavedSearchResponse.warnings:type_name -> notes.v1.Warning
+	9,   // 102: notes.v1.WebhookDeadLetter.event_type:type_name -> notes.v1.EventType
+	183, // 103: notes.v1.WebhookDeadLetter.failed_at:type_name -> google.protobuf.Timestamp
+	9,   // 104: notes.v1.SubscribeToEventsRequest.event_types:type_name -> notes.v1.EventType
+	183, // 105: notes.v1.SubscribeToEventsRequest.since_timestamp:type_name -> google.protobuf.Timestamp
+	130, // 106: notes.v1.EventResponse.health_check:type_name -> notes.v1.HealthCheck
+	134, // 107: notes.v1.EventResponse.note_created:type_name -> notes.v1.NoteCreatedEvent
+	139, // 108: notes.v1.EventResponse.note_reminder_due:type_name -> notes.v1.NoteReminderDue
+	88,  // 109: notes.v1.EventResponse.export_completed:type_name -> notes.v1.ExportCompletedEvent
+	135, // 110: notes.v1.EventResponse.note_updated:type_name -> notes.v1.NoteUpdatedEvent
+	136, // 111: notes.v1.EventResponse.note_deleted:type_name -> notes.v1.NoteDeletedEvent
+	138, // 112: notes.v1.EventResponse.note_shared:type_name -> notes.v1.NoteSharedEvent
+	137, // 113: notes.v1.EventResponse.note_archived:type_name -> notes.v1.NoteArchivedEvent
+	132, // 114: notes.v1.EventResponse.saved_search_matched:type_name -> notes.v1.SavedSearchMatchedEvent
+	133, // 115: notes.v1.EventResponse.quota_warning:type_name -> notes.v1.QuotaWarningEvent
+	131, // 116: notes.v1.EventResponse.go_away:type_name -> notes.v1.StreamGoAway
+	183, // 117: notes.v1.EventResponse.event_time:type_name -> google.protobuf.Timestamp
+	183, // 118: notes.v1.HealthCheck.timestamp:type_name -> google.protobuf.Timestamp
+	119, // 119: notes.v1.SavedSearchMatchedEvent.saved_search:type_name -> notes.v1.SavedSearch
+	109, // 120: notes.v1.SavedSearchMatchedEvent.note:type_name -> notes.v1.Note
+	109, // 121: notes.v1.QuotaWarningEvent.note:type_name -> notes.v1.Note
+	109, // 122: notes.v1.NoteCreatedEvent.note:type_name -> notes.v1.Note
+	109, // 123: notes.v1.NoteUpdatedEvent.note:type_name -> notes.v1.Note
+	109, // 124: notes.v1.NoteArchivedEvent.note:type_name -> notes.v1.Note
+	109, // 125: notes.v1.NoteSharedEvent.note:type_name -> notes.v1.Note
+	72,  // 126: notes.v1.NoteSharedEvent.share:type_name -> notes.v1.Share
+	109, // 127: notes.v1.NoteReminderDue.note:type_name -> notes.v1.Note
+	183, // 128: notes.v1.NoteReminderDue.remind_at:type_name -> google.protobuf.Timestamp
+	183, // 129: notes.v1.MetricRequest.time:type_name -> google.protobuf.Timestamp
+	142, // 130: notes.v1.SummaryResponse.metrics:type_name -> notes.v1.MetricSummary
+	144, // 131: notes.v1.StreamMetricsRequest.options:type_name -> notes.v1.StreamMetricsOptions
+	140, // 132: notes.v1.StreamMetricsRequest.metric:type_name -> notes.v1.MetricRequest
+	141, // 133: notes.v1.StreamMetricsResponse.summary:type_name -> notes.v1.SummaryResponse
+	183, // 134: notes.v1.StreamMetricsResponse.window_start:type_name -> google.protobuf.Timestamp
+	183, // 135: notes.v1.StreamMetricsResponse.window_end:type_name -> google.protobuf.Timestamp
+	183, // 136: notes.v1.QueryMetricsRequest.from:type_name -> google.protobuf.Timestamp
+	183, // 137: notes.v1.QueryMetricsRequest.to:type_name -> google.protobuf.Timestamp
+	10,  // 138: notes.v1.QueryMetricsRequest.aggregation:type_name -> notes.v1.MetricAggregation
+	183, // 139: notes.v1.MetricPoint.time:type_name -> google.protobuf.Timestamp
+	147, // 140: notes.v1.QueryMetricsResponse.points:type_name -> notes.v1.MetricPoint
+	150, // 141: notes.v1.ChatMessage.text_message:type_name -> notes.v1.ChatTextMessage
+	155, // 142: notes.v1.ChatMessage.error:type_name -> notes.v1.ChatError
+	151, // 143: notes.v1.ChatMessage.join_room:type_name -> notes.v1.ChatJoinRoom
+	152, // 144: notes.v1.ChatMessage.leave_room:type_name -> notes.v1.ChatLeaveRoom
+	153, // 145: notes.v1.ChatMessage.typing_indicator:type_name -> notes.v1.TypingIndicator
+	154, // 146: notes.v1.ChatMessage.presence_update:type_name -> notes.v1.PresenceUpdate
+	183, // 147: notes.v1.ChatTextMessage.timestamp:type_name -> google.protobuf.Timestamp
+	183, // 148: notes.v1.TypingIndicator.timestamp:type_name -> google.protobuf.Timestamp
+	11,  // 149: notes.v1.PresenceUpdate.state:type_name -> notes.v1.PresenceState
+	183, // 150: notes.v1.PresenceUpdate.timestamp:type_name -> google.protobuf.Timestamp
+	12,  // 151: notes.v1.ChatError.code:type_name -> notes.v1.ChatErrorCode
+	183, // 152: notes.v1.StreamTicket.expires_at:type_name -> google.protobuf.Timestamp
+	183, // 153: notes.v1.AuthTokens.access_token_expires_at:type_name -> google.protobuf.Timestamp
+	183, // 154: notes.v1.AuthTokens.refresh_token_expires_at:type_name -> google.protobuf.Timestamp
+	183, // 155: notes.v1.User.created_at:type_name -> google.protobuf.Timestamp
+	163, // 156: notes.v1.ListUsersResponse.users:type_name -> notes.v1.User
+	183, // 157: notes.v1.APIKey.created_at:type_name -> google.protobuf.Timestamp
+	183, // 158: notes.v1.APIKey.expires_at:type_name -> google.protobuf.Timestamp
+	183, // 159: notes.v1.APIKey.revoked_at:type_name -> google.protobuf.Timestamp
+	168, // 160: notes.v1.CreateAPIKeyResponse.api_key:type_name -> notes.v1.APIKey
+	168, // 161: notes.v1.ListAPIKeysResponse.api_keys:type_name -> notes.v1.APIKey
+	181, // 162: notes.v1.PipelineStage.settings:type_name -> notes.v1.PipelineStage.SettingsEntry
+	175, // 163: notes.v1.PipelineChain.stages:type_name -> notes.v1.PipelineStage
+	176, // 164: notes.v1.GetPipelineResponse.chains:type_name -> notes.v1.PipelineChain
+	183, // 165: notes.v1.AuditEvent.time:type_name -> google.protobuf.Timestamp
+	183, // 166: notes.v1.ListAuditEventsRequest.from:type_name -> google.protobuf.Timestamp
+	183, // 167: notes.v1.ListAuditEventsRequest.to:type_name -> google.protobuf.Timestamp
+	178, // 168: notes.v1.ListAuditEventsResponse.events:type_name -> notes.v1.AuditEvent
+	186, // 169: notes.v1.policy:extendee -> google.protobuf.MethodOptions
+	13,  // 170: notes.v1.policy:type_name -> notes.v1.MethodPolicy
+	15,  // 171: notes.v1.NotesService.CreateNote:input_type -> notes.v1.CreateNoteRequest
+	18,  // 172: notes.v1.NotesService.GetNote:input_type -> notes.v1.GetNoteRequest
+	20,  // 173: notes.v1.NotesService.ListNotes:input_type -> notes.v1.ListNotesRequest
+	22,  // 174: notes.v1.NotesService.StreamNotes:input_type -> notes.v1.StreamNotesRequest
+	23,  // 175: notes.v1.NotesService.UpdateNote:input_type -> notes.v1.UpdateNoteRequest
+	25,  // 176: notes.v1.NotesService.DeleteNote:input_type -> notes.v1.DeleteNoteRequest
+	27,  // 177: notes.v1.NotesService.PinNote:input_type -> notes.v1.PinNoteRequest
+	29,  // 178: notes.v1.NotesService.UnpinNote:input_type -> notes.v1.UnpinNoteRequest
+	31,  // 179: notes.v1.NotesService.ArchiveNote:input_type -> notes.v1.ArchiveNoteRequest
+	33,  // 180: notes.v1.NotesService.UnarchiveNote:input_type -> notes.v1.UnarchiveNoteRequest
+	35,  // 181: notes.v1.NotesService.LockNote:input_type -> notes.v1.LockNoteRequest
+	37,  // 182: notes.v1.NotesService.UnlockNote:input_type -> notes.v1.UnlockNoteRequest
+	39,  // 183: notes.v1.NotesService.SetNotePassphrase:input_type -> notes.v1.SetNotePassphraseRequest
+	42,  // 184: notes.v1.NotesService.BatchCreateNotes:input_type -> notes.v1.BatchCreateNotesRequest
+	44,  // 185: notes.v1.NotesService.BatchGetNotes:input_type -> notes.v1.BatchGetNotesRequest
+	46,  // 186: notes.v1.NotesService.BatchDeleteNotes:input_type -> notes.v1.BatchDeleteNotesRequest
+	49,  // 187: notes.v1.NotesService.ListNoteRevisions:input_type -> notes.v1.ListNoteRevisionsRequest
+	51,  // 188: notes.v1.NotesService.GetNoteRevision:input_type -> notes.v1.GetNoteRevisionRequest
+	53,  // 189: notes.v1.NotesService.DiffNoteRevisions:input_type -> notes.v1.DiffNoteRevisionsRequest
+	58,  // 190: notes.v1.NotesService.ListNotesByTag:input_type -> notes.v1.ListNotesByTagRequest
+	60,  // 191: notes.v1.NotesService.ListTags:input_type -> notes.v1.ListTagsRequest
+	62,  // 192: notes.v1.NotesService.SuggestNotes:input_type -> notes.v1.SuggestNotesRequest
+	65,  // 193: notes.v1.NotesService.GetNoteStats:input_type -> notes.v1.GetNoteStatsRequest
+	69,  // 194: notes.v1.NotesService.GetAccountStats:input_type -> notes.v1.GetAccountStatsRequest
+	73,  // 195: notes.v1.NotesService.ShareNote:input_type -> notes.v1.ShareNoteRequest
+	75,  // 196: notes.v1.NotesService.UnshareNote:input_type -> notes.v1.UnshareNoteRequest
+	77,  // 197: notes.v1.NotesService.ListSharedNotes:input_type -> notes.v1.ListSharedNotesRequest
+	80,  // 198: notes.v1.NotesService.ExportNotes:input_type -> notes.v1.ExportNotesRequest
+	82,  // 199: notes.v1.NotesService.ExportToDestination:input_type -> notes.v1.ExportToDestinationRequest
+	83,  // 200: notes.v1.NotesService.GetExportOperation:input_type -> notes.v1.GetExportOperationRequest
+	89,  // 201: notes.v1.NotesService.ImportNotes:input_type -> notes.v1.ImportNotesRequest
+	91,  // 202: notes.v1.NotesService.GetServerInfo:input_type -> notes.v1.GetServerInfoRequest
+	101, // 203: notes.v1.NotesService.AdminListAllNotes:input_type -> notes.v1.AdminListAllNotesRequest
+	85,  // 204: notes.v1.NotesService.RotateKeys:input_type -> notes.v1.RotateKeysRequest
+	86,  // 205: notes.v1.NotesService.GetKeyRotationOperation:input_type -> notes.v1.GetKeyRotationOperationRequest
+	94,  // 206: notes.v1.NotesService.RestoreBackup:input_type -> notes.v1.RestoreBackupRequest
+	96,  // 207: notes.v1.NotesService.GetUsageStats:input_type -> notes.v1.GetUsageStatsRequest
+	112, // 208: notes.v1.NotesService.RegisterWebhook:input_type -> notes.v1.RegisterWebhookRequest
+	113, // 209: notes.v1.NotesService.ListWebhooks:input_type -> notes.v1.ListWebhooksRequest
+	115, // 210: notes.v1.NotesService.DeleteWebhook:input_type -> notes.v1.DeleteWebhookRequest
+	117, // 211: notes.v1.NotesService.ListWebhookDeadLetters:input_type -> notes.v1.ListWebhookDeadLettersRequest
+	120, // 212: notes.v1.NotesService.SaveSearch:input_type -> notes.v1.SaveSearchRequest
+	121, // 213: notes.v1.NotesService.ListSavedSearches:input_type -> notes.v1.ListSavedSearchesRequest
+	123, // 214: notes.v1.NotesService.DeleteSavedSearch:input_type -> notes.v1.DeleteSavedSearchRequest
+	125, // 215: notes.v1.NotesService.ExecuteSavedSearch:input_type -> notes.v1.ExecuteSavedSearchRequest
+	104, // 216: notes.v1.NotesService.UploadAttachment:input_type -> notes.v1.AttachmentChunk
+	107, // 217: notes.v1.NotesService.DownloadAttachment:input_type -> notes.v1.DownloadAttachmentRequest
+	128, // 218: notes.v1.NotesService.SubscribeToEvents:input_type -> notes.v1.SubscribeToEventsRequest
+	140, // 219: notes.v1.NotesService.UploadMetrics:input_type -> notes.v1.MetricRequest
+	143, // 220: notes.v1.NotesService.StreamMetrics:input_type -> notes.v1.StreamMetricsRequest
+	146, // 221: notes.v1.NotesService.QueryMetrics:input_type -> notes.v1.QueryMetricsRequest
+	149, // 222: notes.v1.NotesService.Chat:input_type -> notes.v1.ChatMessage
+	156, // 223: notes.v1.AuthService.Login:input_type -> notes.v1.LoginRequest
+	157, // 224: notes.v1.AuthService.RefreshToken:input_type -> notes.v1.RefreshTokenRequest
+	158, // 225: notes.v1.AuthService.Logout:input_type -> notes.v1.LogoutRequest
+	160, // 226: notes.v1.AuthService.IssueStreamTicket:input_type -> notes.v1.IssueStreamTicketRequest
+	164, // 227: notes.v1.UserService.CreateUser:input_type -> notes.v1.CreateUserRequest
+	165, // 228: notes.v1.UserService.GetUser:input_type -> notes.v1.GetUserRequest
+	166, // 229: notes.v1.UserService.ListUsers:input_type -> notes.v1.ListUsersRequest
+	169, // 230: notes.v1.AdminService.CreateAPIKey:input_type -> notes.v1.CreateAPIKeyRequest
+	171, // 231: notes.v1.AdminService.RevokeAPIKey:input_type -> notes.v1.RevokeAPIKeyRequest
+	172, // 232: notes.v1.AdminService.ListAPIKeys:input_type -> notes.v1.ListAPIKeysRequest
+	174, // 233: notes.v1.AdminService.GetPipeline:input_type -> notes.v1.GetPipelineRequest
+	179, // 234: notes.v1.AdminService.ListAuditEvents:input_type -> notes.v1.ListAuditEventsRequest
+	16,  // 235: notes.v1.NotesService.CreateNote:output_type -> notes.v1.CreateNoteResponse
+	19,  // 236: notes.v1.NotesService.GetNote:output_type -> notes.v1.GetNoteResponse
+	21,  // 237: notes.v1.NotesService.ListNotes:output_type -> notes.v1.ListNotesResponse
+	109, // 238: notes.v1.NotesService.StreamNotes:output_type -> notes.v1.Note
+	24,  // 239: notes.v1.NotesService.UpdateNote:output_type -> notes.v1.UpdateNoteResponse
+	26,  // 240: notes.v1.NotesService.DeleteNote:output_type -> notes.v1.DeleteNoteResponse
+	28,  // 241: notes.v1.NotesService.PinNote:output_type -> notes.v1.PinNoteResponse
+	30,  // 242: notes.v1.NotesService.UnpinNote:output_type -> notes.v1.UnpinNoteResponse
+	32,  // 243: notes.v1.NotesService.ArchiveNote:output_type -> notes.v1.ArchiveNoteResponse
+	34,  // 244: notes.v1.NotesService.UnarchiveNote:output_type -> notes.v1.UnarchiveNoteResponse
+	36,  // 245: notes.v1.NotesService.LockNote:output_type -> notes.v1.LockNoteResponse
+	38,  // 246: notes.v1.NotesService.UnlockNote:output_type -> notes.v1.UnlockNoteResponse
+	40,  // 247: notes.v1.NotesService.SetNotePassphrase:output_type -> notes.v1.SetNotePassphraseResponse
+	43,  // 248: notes.v1.NotesService.BatchCreateNotes:output_type -> notes.v1.BatchCreateNotesResponse
+	45,  // 249: notes.v1.NotesService.BatchGetNotes:output_type -> notes.v1.BatchGetNotesResponse
+	47,  // 250: notes.v1.NotesService.BatchDeleteNotes:output_type -> notes.v1.BatchDeleteNotesResponse
+	50,  // 251: notes.v1.NotesService.ListNoteRevisions:output_type -> notes.v1.ListNoteRevisionsResponse
+	52,  // 252: notes.v1.NotesService.GetNoteRevision:output_type -> notes.v1.GetNoteRevisionResponse
+	54,  // 253: notes.v1.NotesService.DiffNoteRevisions:output_type -> notes.v1.DiffNoteRevisionsResponse
+	59,  // 254: notes.v1.NotesService.ListNotesByTag:output_type -> notes.v1.ListNotesByTagResponse
+	61,  // 255: notes.v1.NotesService.ListTags:output_type -> notes.v1.ListTagsResponse
+	63,  // 256: notes.v1.NotesService.SuggestNotes:output_type -> notes.v1.SuggestNotesResponse
+	66,  // 257: notes.v1.NotesService.GetNoteStats:output_type -> notes.v1.GetNoteStatsResponse
+	70,  // 258: notes.v1.NotesService.GetAccountStats:output_type -> notes.v1.GetAccountStatsResponse
+	74,  // 259: notes.v1.NotesService.ShareNote:output_type -> notes.v1.ShareNoteResponse
+	76,  // 260: notes.v1.NotesService.UnshareNote:output_type -> notes.v1.UnshareNoteResponse
+	79,  // 261: notes.v1.NotesService.ListSharedNotes:output_type -> notes.v1.ListSharedNotesResponse
+	81,  // 262: notes.v1.NotesService.ExportNotes:output_type -> notes.v1.ExportNotesResponse
+	84,  // 263: notes.v1.NotesService.ExportToDestination:output_type -> notes.v1.ExportOperation
+	84,  // 264: notes.v1.NotesService.GetExportOperation:output_type -> notes.v1.ExportOperation
+	90,  // 265: notes.v1.NotesService.ImportNotes:output_type -> notes.v1.ImportNotesResponse
+	92,  // 266: notes.v1.NotesService.GetServerInfo:output_type -> notes.v1.GetServerInfoResponse
+	102, // 267: notes.v1.NotesService.AdminListAllNotes:output_type -> notes.v1.AdminListAllNotesResponse
+	87,  // 268: notes.v1.NotesService.RotateKeys:output_type -> notes.v1.KeyRotationOperation
+	87,  // 269: notes.v1.NotesService.GetKeyRotationOperation:output_type -> notes.v1.KeyRotationOperation
+	95,  // 270: notes.v1.NotesService.RestoreBackup:output_type -> notes.v1.RestoreBackupResponse
+	97,  // 271: notes.v1.NotesService.GetUsageStats:output_type -> notes.v1.GetUsageStatsResponse
+	111, // 272: notes.v1.NotesService.RegisterWebhook:output_type -> notes.v1.Webhook
+	114, // 273: notes.v1.NotesService.ListWebhooks:output_type -> notes.v1.ListWebhooksResponse
+	116, // 274: notes.v1.NotesService.DeleteWebhook:output_type -> notes.v1.DeleteWebhookResponse
+	118, // 275: notes.v1.NotesService.ListWebhookDeadLetters:output_type -> notes.v1.ListWebhookDeadLettersResponse
+	119, // 276: notes.v1.NotesService.SaveSearch:output_type -> notes.v1.SavedSearch
+	122, // 277: notes.v1.NotesService.ListSavedSearches:output_type -> notes.v1.ListSavedSearchesResponse
+	124, // 278: notes.v1.NotesService.DeleteSavedSearch:output_type -> notes.v1.DeleteSavedSearchResponse
+	126, // 279: notes.v1.NotesService.ExecuteSavedSearch:output_type -> notes.v1.ExecuteSavedSearchResponse
+	106, // 280: notes.v1.NotesService.UploadAttachment:output_type -> notes.v1.Attachment
+	108, // 281: notes.v1.NotesService.DownloadAttachment:output_type -> notes.v1.DownloadAttachmentResponse
+	129, // 282: notes.v1.NotesService.SubscribeToEvents:output_type -> notes.v1.EventResponse
+	141, // 283: notes.v1.NotesService.UploadMetrics:output_type -> notes.v1.SummaryResponse
+	145, // 284: notes.v1.NotesService.StreamMetrics:output_type -> notes.v1.StreamMetricsResponse
+	148, // 285: notes.v1.NotesService.QueryMetrics:output_type -> notes.v1.QueryMetricsResponse
+	149, // 286: notes.v1.NotesService.Chat:output_type -> notes.v1.ChatMessage
+	162, // 287: notes.v1.AuthService.Login:output_type -> notes.v1.AuthTokens
+	162, // 288: notes.v1.AuthService.RefreshToken:output_type -> notes.v1.AuthTokens
+	159, // 289: notes.v1.AuthService.Logout:output_type -> notes.v1.LogoutResponse
+	161, // 290: notes.v1.AuthService.IssueStreamTicket:output_type -> notes.v1.StreamTicket
+	163, // 291: notes.v1.UserService.CreateUser:output_type -> notes.v1.User
+	163, // 292: notes.v1.UserService.GetUser:output_type -> notes.v1.User
+	167, // 293: notes.v1.UserService.ListUsers:output_type -> notes.v1.ListUsersResponse
+	170, // 294: notes.v1.AdminService.CreateAPIKey:output_type -> notes.v1.CreateAPIKeyResponse
+	168, // 295: notes.v1.AdminService.RevokeAPIKey:output_type -> notes.v1.APIKey
+	173, // 296: notes.v1.AdminService.ListAPIKeys:output_type -> notes.v1.ListAPIKeysResponse
+	177, // 297: notes.v1.AdminService.GetPipeline:output_type -> notes.v1.GetPipelineResponse
+	180, // 298: notes.v1.AdminService.ListAuditEvents:output_type -> notes.v1.ListAuditEventsResponse
+	235, // [235:299] is the sub-list for method output_type
+	171, // [171:235] is the sub-list for method input_type
+	170, // [170:171] is the sub-list for extension type_name
+	169, // [169:170] is the sub-list for extension extendee
+	0,   // [0:169] is the sub-list for field type_name
 }
 
 func init() { file_proto_notes_v1_notes_proto_init() }
@@ -1427,32 +12491,69 @@ func file_proto_notes_v1_notes_proto_init() {
 	if File_proto_notes_v1_notes_proto != nil {
 		return
 	}
-	file_proto_notes_v1_notes_proto_msgTypes[13].OneofWrappers = []any{
+	file_proto_notes_v1_notes_proto_msgTypes[0].OneofWrappers = []any{}
+	file_proto_notes_v1_notes_proto_msgTypes[40].OneofWrappers = []any{}
+	file_proto_notes_v1_notes_proto_msgTypes[76].OneofWrappers = []any{
+		(*ImportNotesRequest_Format)(nil),
+		(*ImportNotesRequest_Data)(nil),
+	}
+	file_proto_notes_v1_notes_proto_msgTypes[91].OneofWrappers = []any{
+		(*AttachmentChunk_Metadata)(nil),
+		(*AttachmentChunk_Data)(nil),
+	}
+	file_proto_notes_v1_notes_proto_msgTypes[95].OneofWrappers = []any{
+		(*DownloadAttachmentResponse_Attachment)(nil),
+		(*DownloadAttachmentResponse_Data)(nil),
+	}
+	file_proto_notes_v1_notes_proto_msgTypes[115].OneofWrappers = []any{
+		(*SubscribeToEventsRequest_SinceEventId)(nil),
+		(*SubscribeToEventsRequest_SinceTimestamp)(nil),
+		(*SubscribeToEventsRequest_ResumeToken)(nil),
+	}
+	file_proto_notes_v1_notes_proto_msgTypes[116].OneofWrappers = []any{
 		(*EventResponse_HealthCheck)(nil),
 		(*EventResponse_NoteCreated)(nil),
+		(*EventResponse_NoteReminderDue)(nil),
+		(*EventResponse_ExportCompleted)(nil),
+		(*EventResponse_NoteUpdated)(nil),
+		(*EventResponse_NoteDeleted)(nil),
+		(*EventResponse_NoteShared)(nil),
+		(*EventResponse_NoteArchived)(nil),
+		(*EventResponse_SavedSearchMatched)(nil),
+		(*EventResponse_QuotaWarning)(nil),
+		(*EventResponse_GoAway)(nil),
 	}
-	file_proto_notes_v1_notes_proto_msgTypes[15].OneofWrappers = []any{
+	file_proto_notes_v1_notes_proto_msgTypes[121].OneofWrappers = []any{
 		(*NoteCreatedEvent_NoteId)(nil),
 		(*NoteCreatedEvent_Note)(nil),
 	}
-	file_proto_notes_v1_notes_proto_msgTypes[18].OneofWrappers = []any{
+	file_proto_notes_v1_notes_proto_msgTypes[130].OneofWrappers = []any{
+		(*StreamMetricsRequest_Options)(nil),
+		(*StreamMetricsRequest_Metric)(nil),
+	}
+	file_proto_notes_v1_notes_proto_msgTypes[136].OneofWrappers = []any{
 		(*ChatMessage_TextMessage)(nil),
 		(*ChatMessage_Error)(nil),
+		(*ChatMessage_JoinRoom)(nil),
+		(*ChatMessage_LeaveRoom)(nil),
+		(*ChatMessage_TypingIndicator)(nil),
+		(*ChatMessage_PresenceUpdate)(nil),
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_notes_v1_notes_proto_rawDesc), len(file_proto_notes_v1_notes_proto_rawDesc)),
-			NumEnums:      1,
-			NumMessages:   21,
-			NumExtensions: 0,
-			NumServices:   1,
+			NumEnums:      13,
+			NumMessages:   169,
+			NumExtensions: 1,
+			NumServices:   4,
 		},
 		GoTypes:           file_proto_notes_v1_notes_proto_goTypes,
 		DependencyIndexes: file_proto_notes_v1_notes_proto_depIdxs,
 		EnumInfos:         file_proto_notes_v1_notes_proto_enumTypes,
 		MessageInfos:      file_proto_notes_v1_notes_proto_msgTypes,
+		ExtensionInfos:    file_proto_notes_v1_notes_proto_extTypes,
 	}.Build()
 	File_proto_notes_v1_notes_proto = out.File
 	file_proto_notes_v1_notes_proto_goTypes = nil
@@ -21,14 +21,58 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	NotesService_CreateNote_FullMethodName        = "/notes.v1.NotesService/CreateNote"
-	NotesService_GetNote_FullMethodName           = "/notes.v1.NotesService/GetNote"
-	NotesService_ListNotes_FullMethodName         = "/notes.v1.NotesService/ListNotes"
-	NotesService_UpdateNote_FullMethodName        = "/notes.v1.NotesService/UpdateNote"
-	NotesService_DeleteNote_FullMethodName        = "/notes.v1.NotesService/DeleteNote"
-	NotesService_SubscribeToEvents_FullMethodName = "/notes.v1.NotesService/SubscribeToEvents"
-	NotesService_UploadMetrics_FullMethodName     = "/notes.v1.NotesService/UploadMetrics"
-	NotesService_Chat_FullMethodName              = "/notes.v1.NotesService/Chat"
+	NotesService_CreateNote_FullMethodName              = "/notes.v1.NotesService/CreateNote"
+	NotesService_GetNote_FullMethodName                 = "/notes.v1.NotesService/GetNote"
+	NotesService_ListNotes_FullMethodName               = "/notes.v1.NotesService/ListNotes"
+	NotesService_StreamNotes_FullMethodName             = "/notes.v1.NotesService/StreamNotes"
+	NotesService_UpdateNote_FullMethodName              = "/notes.v1.NotesService/UpdateNote"
+	NotesService_DeleteNote_FullMethodName              = "/notes.v1.NotesService/DeleteNote"
+	NotesService_PinNote_FullMethodName                 = "/notes.v1.NotesService/PinNote"
+	NotesService_UnpinNote_FullMethodName               = "/notes.v1.NotesService/UnpinNote"
+	NotesService_ArchiveNote_FullMethodName             = "/notes.v1.NotesService/ArchiveNote"
+	NotesService_UnarchiveNote_FullMethodName           = "/notes.v1.NotesService/UnarchiveNote"
+	NotesService_LockNote_FullMethodName                = "/notes.v1.NotesService/LockNote"
+	NotesService_UnlockNote_FullMethodName              = "/notes.v1.NotesService/UnlockNote"
+	NotesService_SetNotePassphrase_FullMethodName       = "/notes.v1.NotesService/SetNotePassphrase"
+	NotesService_BatchCreateNotes_FullMethodName        = "/notes.v1.NotesService/BatchCreateNotes"
+	NotesService_BatchGetNotes_FullMethodName           = "/notes.v1.NotesService/BatchGetNotes"
+	NotesService_BatchDeleteNotes_FullMethodName        = "/notes.v1.NotesService/BatchDeleteNotes"
+	NotesService_ListNoteRevisions_FullMethodName       = "/notes.v1.NotesService/ListNoteRevisions"
+	NotesService_GetNoteRevision_FullMethodName         = "/notes.v1.NotesService/GetNoteRevision"
+	NotesService_DiffNoteRevisions_FullMethodName       = "/notes.v1.NotesService/DiffNoteRevisions"
+	NotesService_ListNotesByTag_FullMethodName          = "/notes.v1.NotesService/ListNotesByTag"
+	NotesService_ListTags_FullMethodName                = "/notes.v1.NotesService/ListTags"
+	NotesService_SuggestNotes_FullMethodName            = "/notes.v1.NotesService/SuggestNotes"
+	NotesService_GetNoteStats_FullMethodName            = "/notes.v1.NotesService/GetNoteStats"
+	NotesService_GetAccountStats_FullMethodName         = "/notes.v1.NotesService/GetAccountStats"
+	NotesService_ShareNote_FullMethodName               = "/notes.v1.NotesService/ShareNote"
+	NotesService_UnshareNote_FullMethodName             = "/notes.v1.NotesService/UnshareNote"
+	NotesService_ListSharedNotes_FullMethodName         = "/notes.v1.NotesService/ListSharedNotes"
+	NotesService_ExportNotes_FullMethodName             = "/notes.v1.NotesService/ExportNotes"
+	NotesService_ExportToDestination_FullMethodName     = "/notes.v1.NotesService/ExportToDestination"
+	NotesService_GetExportOperation_FullMethodName      = "/notes.v1.NotesService/GetExportOperation"
+	NotesService_ImportNotes_FullMethodName             = "/notes.v1.NotesService/ImportNotes"
+	NotesService_GetServerInfo_FullMethodName           = "/notes.v1.NotesService/GetServerInfo"
+	NotesService_AdminListAllNotes_FullMethodName       = "/notes.v1.NotesService/AdminListAllNotes"
+	NotesService_RotateKeys_FullMethodName              = "/notes.v1.NotesService/RotateKeys"
+	NotesService_GetKeyRotationOperation_FullMethodName = "/notes.v1.NotesService/GetKeyRotationOperation"
+	NotesService_RestoreBackup_FullMethodName           = "/notes.v1.NotesService/RestoreBackup"
+	NotesService_GetUsageStats_FullMethodName           = "/notes.v1.NotesService/GetUsageStats"
+	NotesService_RegisterWebhook_FullMethodName         = "/notes.v1.NotesService/RegisterWebhook"
+	NotesService_ListWebhooks_FullMethodName            = "/notes.v1.NotesService/ListWebhooks"
+	NotesService_DeleteWebhook_FullMethodName           = "/notes.v1.NotesService/DeleteWebhook"
+	NotesService_ListWebhookDeadLetters_FullMethodName  = "/notes.v1.NotesService/ListWebhookDeadLetters"
+	NotesService_SaveSearch_FullMethodName              = "/notes.v1.NotesService/SaveSearch"
+	NotesService_ListSavedSearches_FullMethodName       = "/notes.v1.NotesService/ListSavedSearches"
+	NotesService_DeleteSavedSearch_FullMethodName       = "/notes.v1.NotesService/DeleteSavedSearch"
+	NotesService_ExecuteSavedSearch_FullMethodName      = "/notes.v1.NotesService/ExecuteSavedSearch"
+	NotesService_UploadAttachment_FullMethodName        = "/notes.v1.NotesService/UploadAttachment"
+	NotesService_DownloadAttachment_FullMethodName      = "/notes.v1.NotesService/DownloadAttachment"
+	NotesService_SubscribeToEvents_FullMethodName       = "/notes.v1.NotesService/SubscribeToEvents"
+	NotesService_UploadMetrics_FullMethodName           = "/notes.v1.NotesService/UploadMetrics"
+	NotesService_StreamMetrics_FullMethodName           = "/notes.v1.NotesService/StreamMetrics"
+	NotesService_QueryMetrics_FullMethodName            = "/notes.v1.NotesService/QueryMetrics"
+	NotesService_Chat_FullMethodName                    = "/notes.v1.NotesService/Chat"
 )
 
 // NotesServiceClient is the client API for NotesService service.
@@ -43,15 +87,135 @@ type NotesServiceClient interface {
 	GetNote(ctx context.Context, in *GetNoteRequest, opts ...grpc.CallOption) (*GetNoteResponse, error)
 	// ListNotes возвращает список всех заметок
 	ListNotes(ctx context.Context, in *ListNotesRequest, opts ...grpc.CallOption) (*ListNotesResponse, error)
+	// StreamNotes - server-side streaming списка заметок в порядке возрастания ID
+	// Прерванный поток можно продолжить, передав в cursor ID последней полученной заметки
+	StreamNotes(ctx context.Context, in *StreamNotesRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Note], error)
 	// UpdateNote обновляет существующую заметку
 	UpdateNote(ctx context.Context, in *UpdateNoteRequest, opts ...grpc.CallOption) (*UpdateNoteResponse, error)
 	// DeleteNote удаляет заметку по UUID
 	DeleteNote(ctx context.Context, in *DeleteNoteRequest, opts ...grpc.CallOption) (*DeleteNoteResponse, error)
-	// SubscribeToEvents подписывается на события создания заметок
+	// PinNote закрепляет заметку: закрепленные заметки выводятся в начале ListNotes
+	PinNote(ctx context.Context, in *PinNoteRequest, opts ...grpc.CallOption) (*PinNoteResponse, error)
+	// UnpinNote открепляет заметку
+	UnpinNote(ctx context.Context, in *UnpinNoteRequest, opts ...grpc.CallOption) (*UnpinNoteResponse, error)
+	// ArchiveNote архивирует заметку: архивные заметки не выводятся в ListNotes без include_archived
+	ArchiveNote(ctx context.Context, in *ArchiveNoteRequest, opts ...grpc.CallOption) (*ArchiveNoteResponse, error)
+	// UnarchiveNote возвращает заметку из архива
+	UnarchiveNote(ctx context.Context, in *UnarchiveNoteRequest, opts ...grpc.CallOption) (*UnarchiveNoteResponse, error)
+	// LockNote захватывает блокировку заметки для монопольного редактирования на время аренды (ttl)
+	// Пока блокировка действует, UpdateNote других пользователей возвращает FailedPrecondition
+	LockNote(ctx context.Context, in *LockNoteRequest, opts ...grpc.CallOption) (*LockNoteResponse, error)
+	// UnlockNote снимает блокировку заметки
+	UnlockNote(ctx context.Context, in *UnlockNoteRequest, opts ...grpc.CallOption) (*UnlockNoteResponse, error)
+	// SetNotePassphrase защищает заметку парольной фразой, меняет или снимает ее (только владелец)
+	// GetNote и UpdateNote защищенной заметки требуют фразу в метаданных x-note-passphrase
+	// (заголовок X-Note-Passphrase); смена и снятие защиты - текущую фразу там же
+	SetNotePassphrase(ctx context.Context, in *SetNotePassphraseRequest, opts ...grpc.CallOption) (*SetNotePassphraseResponse, error)
+	// BatchCreateNotes создает несколько заметок за один запрос
+	BatchCreateNotes(ctx context.Context, in *BatchCreateNotesRequest, opts ...grpc.CallOption) (*BatchCreateNotesResponse, error)
+	// BatchGetNotes возвращает несколько заметок по списку UUID
+	BatchGetNotes(ctx context.Context, in *BatchGetNotesRequest, opts ...grpc.CallOption) (*BatchGetNotesResponse, error)
+	// BatchDeleteNotes удаляет несколько заметок за один запрос
+	BatchDeleteNotes(ctx context.Context, in *BatchDeleteNotesRequest, opts ...grpc.CallOption) (*BatchDeleteNotesResponse, error)
+	// ListNoteRevisions возвращает историю изменений заметки
+	ListNoteRevisions(ctx context.Context, in *ListNoteRevisionsRequest, opts ...grpc.CallOption) (*ListNoteRevisionsResponse, error)
+	// GetNoteRevision возвращает конкретную ревизию заметки
+	GetNoteRevision(ctx context.Context, in *GetNoteRevisionRequest, opts ...grpc.CallOption) (*GetNoteRevisionResponse, error)
+	// DiffNoteRevisions возвращает построчные различия содержимого двух ревизий заметки
+	// Дифф вычисляется на сервере, клиенту не нужно загружать обе ревизии целиком
+	DiffNoteRevisions(ctx context.Context, in *DiffNoteRevisionsRequest, opts ...grpc.CallOption) (*DiffNoteRevisionsResponse, error)
+	// ListNotesByTag возвращает заметки с указанным тегом
+	ListNotesByTag(ctx context.Context, in *ListNotesByTagRequest, opts ...grpc.CallOption) (*ListNotesByTagResponse, error)
+	// ListTags возвращает все теги с количеством заметок
+	ListTags(ctx context.Context, in *ListTagsRequest, opts ...grpc.CallOption) (*ListTagsResponse, error)
+	// SuggestNotes возвращает заметки пользователя, заголовок или слово заголовка которых начинается
+	// с prefix, - для автодополнения. Часто открываемые и недавно измененные заметки идут первыми
+	SuggestNotes(ctx context.Context, in *SuggestNotesRequest, opts ...grpc.CallOption) (*SuggestNotesResponse, error)
+	// GetNoteStats возвращает статистику заметки: слова, символы, время чтения и изменение последней правки
+	GetNoteStats(ctx context.Context, in *GetNoteStatsRequest, opts ...grpc.CallOption) (*GetNoteStatsResponse, error)
+	// GetAccountStats возвращает сводную статистику заметок пользователя
+	GetAccountStats(ctx context.Context, in *GetAccountStatsRequest, opts ...grpc.CallOption) (*GetAccountStatsResponse, error)
+	// ShareNote предоставляет пользователю доступ к своей заметке (повторный вызов меняет уровень доступа)
+	ShareNote(ctx context.Context, in *ShareNoteRequest, opts ...grpc.CallOption) (*ShareNoteResponse, error)
+	// UnshareNote отзывает доступ пользователя к своей заметке
+	UnshareNote(ctx context.Context, in *UnshareNoteRequest, opts ...grpc.CallOption) (*UnshareNoteResponse, error)
+	// ListSharedNotes возвращает заметки других пользователей, доступные вызывающему пользователю
+	ListSharedNotes(ctx context.Context, in *ListSharedNotesRequest, opts ...grpc.CallOption) (*ListSharedNotesResponse, error)
+	// ExportNotes выгружает заметки пользователя файлом в выбранном формате (server-side streaming)
+	ExportNotes(ctx context.Context, in *ExportNotesRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ExportNotesResponse], error)
+	// ExportToDestination запускает выгрузку всех заметок пользователя в настроенное хранилище
+	// (S3/MinIO или локальный каталог) как длительную операцию. Ход выполнения возвращает
+	// GetExportOperation, по завершении подписчики SubscribeToEvents получают ExportCompletedEvent
+	ExportToDestination(ctx context.Context, in *ExportToDestinationRequest, opts ...grpc.CallOption) (*ExportOperation, error)
+	// GetExportOperation возвращает состояние операции выгрузки
+	GetExportOperation(ctx context.Context, in *GetExportOperationRequest, opts ...grpc.CallOption) (*ExportOperation, error)
+	// ImportNotes загружает заметки из файла выгрузки (client-side streaming)
+	// Первое сообщение содержит формат, последующие - части файла. Заметки создаются заново
+	ImportNotes(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[ImportNotesRequest, ImportNotesResponse], error)
+	// GetServerInfo возвращает возможности сервера (поддерживаемые схемы сквозного шифрования)
+	GetServerInfo(ctx context.Context, in *GetServerInfoRequest, opts ...grpc.CallOption) (*GetServerInfoResponse, error)
+	// AdminListAllNotes возвращает заметки всех пользователей (только для роли admin)
+	AdminListAllNotes(ctx context.Context, in *AdminListAllNotesRequest, opts ...grpc.CallOption) (*AdminListAllNotesResponse, error)
+	// RotateKeys запускает смену ключей шифрования заметок (только для роли admin) как длительную
+	// операцию: создает новые ключи данных владельцев, перешифровывает ключи данных текущим
+	// мастер-ключом и в фоне перешифровывает затронутые заметки. Ход выполнения возвращает
+	// GetKeyRotationOperation. Без настроенного шифрования возвращает UNIMPLEMENTED
+	RotateKeys(ctx context.Context, in *RotateKeysRequest, opts ...grpc.CallOption) (*KeyRotationOperation, error)
+	// GetKeyRotationOperation возвращает состояние операции смены ключей (только для роли admin)
+	GetKeyRotationOperation(ctx context.Context, in *GetKeyRotationOperationRequest, opts ...grpc.CallOption) (*KeyRotationOperation, error)
+	// RestoreBackup восстанавливает заметки вместе с их ревизиями и доступами из резервной копии
+	// хранилища (только для роли admin). Заметки, которые уже есть в хранилище, обрабатываются
+	// согласно conflict_strategy, а dry_run только считает изменения. Состояние копирования
+	// возвращает GetServerInfo. Без настроенного резервного копирования возвращает UNIMPLEMENTED
+	RestoreBackup(ctx context.Context, in *RestoreBackupRequest, opts ...grpc.CallOption) (*RestoreBackupResponse, error)
+	// GetUsageStats возвращает обезличенную статистику использования реплики с момента запуска:
+	// количество вызовов методов и использование функций (только для роли admin). Пользователи
+	// и данные запросов не собираются. Если сбор статистики выключен, возвращает UNIMPLEMENTED
+	GetUsageStats(ctx context.Context, in *GetUsageStatsRequest, opts ...grpc.CallOption) (*GetUsageStatsResponse, error)
+	// RegisterWebhook регистрирует адрес, на который сервер отправляет POST запросами события
+	// заметок вызывающего пользователя (как в SubscribeToEvents). Запросы подписаны HMAC-SHA256
+	// (заголовок X-Webhook-Signature), недоставленные после всех попыток события
+	// возвращает ListWebhookDeadLetters. Ключ подписи возвращается только в ответе RegisterWebhook
+	RegisterWebhook(ctx context.Context, in *RegisterWebhookRequest, opts ...grpc.CallOption) (*Webhook, error)
+	// ListWebhooks возвращает вебхуки вызывающего пользователя (без ключей подписи)
+	ListWebhooks(ctx context.Context, in *ListWebhooksRequest, opts ...grpc.CallOption) (*ListWebhooksResponse, error)
+	// DeleteWebhook удаляет вебхук вызывающего пользователя
+	DeleteWebhook(ctx context.Context, in *DeleteWebhookRequest, opts ...grpc.CallOption) (*DeleteWebhookResponse, error)
+	// ListWebhookDeadLetters возвращает события, которые не удалось доставить вебхукам
+	// вызывающего пользователя за все попытки, начиная с последнего
+	ListWebhookDeadLetters(ctx context.Context, in *ListWebhookDeadLettersRequest, opts ...grpc.CallOption) (*ListWebhookDeadLettersResponse, error)
+	// SaveSearch сохраняет поиск (умную папку) вызывающего пользователя: именованное выражение фильтра
+	// заметок (см. README, раздел "Сохраненные поиски"). Поиск с тем же именем заменяется
+	SaveSearch(ctx context.Context, in *SaveSearchRequest, opts ...grpc.CallOption) (*SavedSearch, error)
+	// ListSavedSearches возвращает сохраненные поиски вызывающего пользователя
+	ListSavedSearches(ctx context.Context, in *ListSavedSearchesRequest, opts ...grpc.CallOption) (*ListSavedSearchesResponse, error)
+	// DeleteSavedSearch удаляет сохраненный поиск вызывающего пользователя
+	DeleteSavedSearch(ctx context.Context, in *DeleteSavedSearchRequest, opts ...grpc.CallOption) (*DeleteSavedSearchResponse, error)
+	// ExecuteSavedSearch возвращает заметки вызывающего пользователя, подходящие под сохраненный поиск
+	// Когда заметка начинает подходить под поиск, подписчикам SubscribeToEvents и вебхукам
+	// отправляется SavedSearchMatchedEvent
+	ExecuteSavedSearch(ctx context.Context, in *ExecuteSavedSearchRequest, opts ...grpc.CallOption) (*ExecuteSavedSearchResponse, error)
+	// UploadAttachment загружает вложение заметки (client-side streaming)
+	// Первое сообщение содержит метаданные, последующие - части содержимого файла
+	UploadAttachment(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[AttachmentChunk, Attachment], error)
+	// DownloadAttachment скачивает вложение заметки (server-side streaming)
+	// Первое сообщение содержит метаданные, последующие - части содержимого файла
+	DownloadAttachment(ctx context.Context, in *DownloadAttachmentRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[DownloadAttachmentResponse], error)
+	// SubscribeToEvents подписывается на события заметок: создание и напоминания
 	SubscribeToEvents(ctx context.Context, in *SubscribeToEventsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[EventResponse], error)
-	// UploadMetrics принимает поток метрик и возвращает агрегированную статистику
+	// UploadMetrics принимает поток метрик и возвращает агрегированную статистику,
+	// общую и по каждому названию метрики
 	UploadMetrics(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[MetricRequest, SummaryResponse], error)
-	// Chat - двунаправленный стрим для асинхронного обмена сообщениями
+	// StreamMetrics - двунаправленный вариант UploadMetrics: с window_seconds в первом сообщении
+	// сервер отправляет статистику каждого окна по мере поступления метрик, а после закрытия
+	// отправки клиентом - итоговую статистику всего стрима (final = true)
+	StreamMetrics(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[StreamMetricsRequest, StreamMetricsResponse], error)
+	// QueryMetrics возвращает значения метрики вызывающего пользователя, загруженные UploadMetrics
+	// и StreamMetrics, за период [from, to) или их агрегат. Если хранилище метрик выключено,
+	// возвращает UNIMPLEMENTED
+	QueryMetrics(ctx context.Context, in *QueryMetricsRequest, opts ...grpc.CallOption) (*QueryMetricsResponse, error)
+	// Chat - двунаправленный стрим для обмена сообщениями в комнатах
+	// Клиент входит в комнаты (join_room) и получает сообщения всех участников комнат, в которых находится
 	Chat(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[ChatMessage, ChatMessage], error)
 }
 
@@ -93,6 +257,25 @@ func (c *notesServiceClient) ListNotes(ctx context.Context, in *ListNotesRequest
 	return out, nil
 }
 
+func (c *notesServiceClient) StreamNotes(ctx context.Context, in *StreamNotesRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Note], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &NotesService_ServiceDesc.Streams[0], NotesService_StreamNotes_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[StreamNotesRequest, Note]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type NotesService_StreamNotesClient = grpc.ServerStreamingClient[Note]
+
 func (c *notesServiceClient) UpdateNote(ctx context.Context, in *UpdateNoteRequest, opts ...grpc.CallOption) (*UpdateNoteResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(UpdateNoteResponse)
@@ -103,298 +286,2632 @@ func (c *notesServiceClient) UpdateNote(ctx context.Context, in *UpdateNoteReque
 	return out, nil
 }
 
-func (c *notesServiceClient) DeleteNote(ctx context.Context, in *DeleteNoteRequest, opts ...grpc.CallOption) (*DeleteNoteResponse, error) {
+func (c *notesServiceClient) DeleteNote(ctx context.Context, in *DeleteNoteRequest, opts ...grpc.CallOption) (*DeleteNoteResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteNoteResponse)
+	err := c.cc.Invoke(ctx, NotesService_DeleteNote_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *notesServiceClient) PinNote(ctx context.Context, in *PinNoteRequest, opts ...grpc.CallOption) (*PinNoteResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PinNoteResponse)
+	err := c.cc.Invoke(ctx, NotesService_PinNote_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *notesServiceClient) UnpinNote(ctx context.Context, in *UnpinNoteRequest, opts ...grpc.CallOption) (*UnpinNoteResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UnpinNoteResponse)
+	err := c.cc.Invoke(ctx, NotesService_UnpinNote_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *notesServiceClient) ArchiveNote(ctx context.Context, in *ArchiveNoteRequest, opts ...grpc.CallOption) (*ArchiveNoteResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ArchiveNoteResponse)
+	err := c.cc.Invoke(ctx, NotesService_ArchiveNote_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *notesServiceClient) UnarchiveNote(ctx context.Context, in *UnarchiveNoteRequest, opts ...grpc.CallOption) (*UnarchiveNoteResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UnarchiveNoteResponse)
+	err := c.cc.Invoke(ctx, NotesService_UnarchiveNote_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *notesServiceClient) LockNote(ctx context.Context, in *LockNoteRequest, opts ...grpc.CallOption) (*LockNoteResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(LockNoteResponse)
+	err := c.cc.Invoke(ctx, NotesService_LockNote_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *notesServiceClient) UnlockNote(ctx context.Context, in *UnlockNoteRequest, opts ...grpc.CallOption) (*UnlockNoteResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UnlockNoteResponse)
+	err := c.cc.Invoke(ctx, NotesService_UnlockNote_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *notesServiceClient) SetNotePassphrase(ctx context.Context, in *SetNotePassphraseRequest, opts ...grpc.CallOption) (*SetNotePassphraseResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SetNotePassphraseResponse)
+	err := c.cc.Invoke(ctx, NotesService_SetNotePassphrase_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *notesServiceClient) BatchCreateNotes(ctx context.Context, in *BatchCreateNotesRequest, opts ...grpc.CallOption) (*BatchCreateNotesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BatchCreateNotesResponse)
+	err := c.cc.Invoke(ctx, NotesService_BatchCreateNotes_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *notesServiceClient) BatchGetNotes(ctx context.Context, in *BatchGetNotesRequest, opts ...grpc.CallOption) (*BatchGetNotesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BatchGetNotesResponse)
+	err := c.cc.Invoke(ctx, NotesService_BatchGetNotes_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *notesServiceClient) BatchDeleteNotes(ctx context.Context, in *BatchDeleteNotesRequest, opts ...grpc.CallOption) (*BatchDeleteNotesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BatchDeleteNotesResponse)
+	err := c.cc.Invoke(ctx, NotesService_BatchDeleteNotes_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *notesServiceClient) ListNoteRevisions(ctx context.Context, in *ListNoteRevisionsRequest, opts ...grpc.CallOption) (*ListNoteRevisionsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListNoteRevisionsResponse)
+	err := c.cc.Invoke(ctx, NotesService_ListNoteRevisions_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *notesServiceClient) GetNoteRevision(ctx context.Context, in *GetNoteRevisionRequest, opts ...grpc.CallOption) (*GetNoteRevisionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetNoteRevisionResponse)
+	err := c.cc.Invoke(ctx, NotesService_GetNoteRevision_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *notesServiceClient) DiffNoteRevisions(ctx context.Context, in *DiffNoteRevisionsRequest, opts ...grpc.CallOption) (*DiffNoteRevisionsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DiffNoteRevisionsResponse)
+	err := c.cc.Invoke(ctx, NotesService_DiffNoteRevisions_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *notesServiceClient) ListNotesByTag(ctx context.Context, in *ListNotesByTagRequest, opts ...grpc.CallOption) (*ListNotesByTagResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListNotesByTagResponse)
+	err := c.cc.Invoke(ctx, NotesService_ListNotesByTag_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *notesServiceClient) ListTags(ctx context.Context, in *ListTagsRequest, opts ...grpc.CallOption) (*ListTagsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListTagsResponse)
+	err := c.cc.Invoke(ctx, NotesService_ListTags_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *notesServiceClient) SuggestNotes(ctx context.Context, in *SuggestNotesRequest, opts ...grpc.CallOption) (*SuggestNotesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SuggestNotesResponse)
+	err := c.cc.Invoke(ctx, NotesService_SuggestNotes_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *notesServiceClient) GetNoteStats(ctx context.Context, in *GetNoteStatsRequest, opts ...grpc.CallOption) (*GetNoteStatsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetNoteStatsResponse)
+	err := c.cc.Invoke(ctx, NotesService_GetNoteStats_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *notesServiceClient) GetAccountStats(ctx context.Context, in *GetAccountStatsRequest, opts ...grpc.CallOption) (*GetAccountStatsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetAccountStatsResponse)
+	err := c.cc.Invoke(ctx, NotesService_GetAccountStats_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *notesServiceClient) ShareNote(ctx context.Context, in *ShareNoteRequest, opts ...grpc.CallOption) (*ShareNoteResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ShareNoteResponse)
+	err := c.cc.Invoke(ctx, NotesService_ShareNote_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *notesServiceClient) UnshareNote(ctx context.Context, in *UnshareNoteRequest, opts ...grpc.CallOption) (*UnshareNoteResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UnshareNoteResponse)
+	err := c.cc.Invoke(ctx, NotesService_UnshareNote_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *notesServiceClient) ListSharedNotes(ctx context.Context, in *ListSharedNotesRequest, opts ...grpc.CallOption) (*ListSharedNotesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListSharedNotesResponse)
+	err := c.cc.Invoke(ctx, NotesService_ListSharedNotes_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *notesServiceClient) ExportNotes(ctx context.Context, in *ExportNotesRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ExportNotesResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &NotesService_ServiceDesc.Streams[1], NotesService_ExportNotes_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ExportNotesRequest, ExportNotesResponse]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type NotesService_ExportNotesClient = grpc.ServerStreamingClient[ExportNotesResponse]
+
+func (c *notesServiceClient) ExportToDestination(ctx context.Context, in *ExportToDestinationRequest, opts ...grpc.CallOption) (*ExportOperation, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ExportOperation)
+	err := c.cc.Invoke(ctx, NotesService_ExportToDestination_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *notesServiceClient) GetExportOperation(ctx context.Context, in *GetExportOperationRequest, opts ...grpc.CallOption) (*ExportOperation, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ExportOperation)
+	err := c.cc.Invoke(ctx, NotesService_GetExportOperation_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *notesServiceClient) ImportNotes(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[ImportNotesRequest, ImportNotesResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &NotesService_ServiceDesc.Streams[2], NotesService_ImportNotes_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ImportNotesRequest, ImportNotesResponse]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type NotesService_ImportNotesClient = grpc.ClientStreamingClient[ImportNotesRequest, ImportNotesResponse]
+
+func (c *notesServiceClient) GetServerInfo(ctx context.Context, in *GetServerInfoRequest, opts ...grpc.CallOption) (*GetServerInfoResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetServerInfoResponse)
+	err := c.cc.Invoke(ctx, NotesService_GetServerInfo_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *notesServiceClient) AdminListAllNotes(ctx context.Context, in *AdminListAllNotesRequest, opts ...grpc.CallOption) (*AdminListAllNotesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AdminListAllNotesResponse)
+	err := c.cc.Invoke(ctx, NotesService_AdminListAllNotes_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *notesServiceClient) RotateKeys(ctx context.Context, in *RotateKeysRequest, opts ...grpc.CallOption) (*KeyRotationOperation, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(KeyRotationOperation)
+	err := c.cc.Invoke(ctx, NotesService_RotateKeys_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *notesServiceClient) GetKeyRotationOperation(ctx context.Context, in *GetKeyRotationOperationRequest, opts ...grpc.CallOption) (*KeyRotationOperation, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(KeyRotationOperation)
+	err := c.cc.Invoke(ctx, NotesService_GetKeyRotationOperation_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *notesServiceClient) RestoreBackup(ctx context.Context, in *RestoreBackupRequest, opts ...grpc.CallOption) (*RestoreBackupResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RestoreBackupResponse)
+	err := c.cc.Invoke(ctx, NotesService_RestoreBackup_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *notesServiceClient) GetUsageStats(ctx context.Context, in *GetUsageStatsRequest, opts ...grpc.CallOption) (*GetUsageStatsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetUsageStatsResponse)
+	err := c.cc.Invoke(ctx, NotesService_GetUsageStats_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *notesServiceClient) RegisterWebhook(ctx context.Context, in *RegisterWebhookRequest, opts ...grpc.CallOption) (*Webhook, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Webhook)
+	err := c.cc.Invoke(ctx, NotesService_RegisterWebhook_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *notesServiceClient) ListWebhooks(ctx context.Context, in *ListWebhooksRequest, opts ...grpc.CallOption) (*ListWebhooksResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListWebhooksResponse)
+	err := c.cc.Invoke(ctx, NotesService_ListWebhooks_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *notesServiceClient) DeleteWebhook(ctx context.Context, in *DeleteWebhookRequest, opts ...grpc.CallOption) (*DeleteWebhookResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteWebhookResponse)
+	err := c.cc.Invoke(ctx, NotesService_DeleteWebhook_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *notesServiceClient) ListWebhookDeadLetters(ctx context.Context, in *ListWebhookDeadLettersRequest, opts ...grpc.CallOption) (*ListWebhookDeadLettersResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListWebhookDeadLettersResponse)
+	err := c.cc.Invoke(ctx, NotesService_ListWebhookDeadLetters_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *notesServiceClient) SaveSearch(ctx context.Context, in *SaveSearchRequest, opts ...grpc.CallOption) (*SavedSearch, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SavedSearch)
+	err := c.cc.Invoke(ctx, NotesService_SaveSearch_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *notesServiceClient) ListSavedSearches(ctx context.Context, in *ListSavedSearchesRequest, opts ...grpc.CallOption) (*ListSavedSearchesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListSavedSearchesResponse)
+	err := c.cc.Invoke(ctx, NotesService_ListSavedSearches_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *notesServiceClient) DeleteSavedSearch(ctx context.Context, in *DeleteSavedSearchRequest, opts ...grpc.CallOption) (*DeleteSavedSearchResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteSavedSearchResponse)
+	err := c.cc.Invoke(ctx, NotesService_DeleteSavedSearch_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *notesServiceClient) ExecuteSavedSearch(ctx context.Context, in *ExecuteSavedSearchRequest, opts ...grpc.CallOption) (*ExecuteSavedSearchResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ExecuteSavedSearchResponse)
+	err := c.cc.Invoke(ctx, NotesService_ExecuteSavedSearch_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *notesServiceClient) UploadAttachment(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[AttachmentChunk, Attachment], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &NotesService_ServiceDesc.Streams[3], NotesService_UploadAttachment_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[AttachmentChunk, Attachment]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type NotesService_UploadAttachmentClient = grpc.ClientStreamingClient[AttachmentChunk, Attachment]
+
+func (c *notesServiceClient) DownloadAttachment(ctx context.Context, in *DownloadAttachmentRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[DownloadAttachmentResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &NotesService_ServiceDesc.Streams[4], NotesService_DownloadAttachment_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[DownloadAttachmentRequest, DownloadAttachmentResponse]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type NotesService_DownloadAttachmentClient = grpc.ServerStreamingClient[DownloadAttachmentResponse]
+
+func (c *notesServiceClient) SubscribeToEvents(ctx context.Context, in *SubscribeToEventsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[EventResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &NotesService_ServiceDesc.Streams[5], NotesService_SubscribeToEvents_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[SubscribeToEventsRequest, EventResponse]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type NotesService_SubscribeToEventsClient = grpc.ServerStreamingClient[EventResponse]
+
+func (c *notesServiceClient) UploadMetrics(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[MetricRequest, SummaryResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &NotesService_ServiceDesc.Streams[6], NotesService_UploadMetrics_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[MetricRequest, SummaryResponse]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type NotesService_UploadMetricsClient = grpc.ClientStreamingClient[MetricRequest, SummaryResponse]
+
+func (c *notesServiceClient) StreamMetrics(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[StreamMetricsRequest, StreamMetricsResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &NotesService_ServiceDesc.Streams[7], NotesService_StreamMetrics_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[StreamMetricsRequest, StreamMetricsResponse]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type NotesService_StreamMetricsClient = grpc.BidiStreamingClient[StreamMetricsRequest, StreamMetricsResponse]
+
+func (c *notesServiceClient) QueryMetrics(ctx context.Context, in *QueryMetricsRequest, opts ...grpc.CallOption) (*QueryMetricsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(QueryMetricsResponse)
+	err := c.cc.Invoke(ctx, NotesService_QueryMetrics_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *notesServiceClient) Chat(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[ChatMessage, ChatMessage], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &NotesService_ServiceDesc.Streams[8], NotesService_Chat_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ChatMessage, ChatMessage]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type NotesService_ChatClient = grpc.BidiStreamingClient[ChatMessage, ChatMessage]
+
+// NotesServiceServer is the server API for NotesService service.
+// All implementations must embed UnimplementedNotesServiceServer
+// for forward compatibility.
+//
+// NotesService предоставляет методы для управления заметками
+type NotesServiceServer interface {
+	// CreateNote создает новую заметку
+	CreateNote(context.Context, *CreateNoteRequest) (*CreateNoteResponse, error)
+	// GetNote возвращает заметку по её UUID
+	GetNote(context.Context, *GetNoteRequest) (*GetNoteResponse, error)
+	// ListNotes возвращает список всех заметок
+	ListNotes(context.Context, *ListNotesRequest) (*ListNotesResponse, error)
+	// StreamNotes - server-side streaming списка заметок в порядке возрастания ID
+	// Прерванный поток можно продолжить, передав в cursor ID последней полученной заметки
+	StreamNotes(*StreamNotesRequest, grpc.ServerStreamingServer[Note]) error
+	// UpdateNote обновляет существующую заметку
+	UpdateNote(context.Context, *UpdateNoteRequest) (*UpdateNoteResponse, error)
+	// DeleteNote удаляет заметку по UUID
+	DeleteNote(context.Context, *DeleteNoteRequest) (*DeleteNoteResponse, error)
+	// PinNote закрепляет заметку: закрепленные заметки выводятся в начале ListNotes
+	PinNote(context.Context, *PinNoteRequest) (*PinNoteResponse, error)
+	// UnpinNote открепляет заметку
+	UnpinNote(context.Context, *UnpinNoteRequest) (*UnpinNoteResponse, error)
+	// ArchiveNote архивирует заметку: архивные заметки не выводятся в ListNotes без include_archived
+	ArchiveNote(context.Context, *ArchiveNoteRequest) (*ArchiveNoteResponse, error)
+	// UnarchiveNote возвращает заметку из архива
+	UnarchiveNote(context.Context, *UnarchiveNoteRequest) (*UnarchiveNoteResponse, error)
+	// LockNote захватывает блокировку заметки для монопольного редактирования на время аренды (ttl)
+	// Пока блокировка действует, UpdateNote других пользователей возвращает FailedPrecondition
+	LockNote(context.Context, *LockNoteRequest) (*LockNoteResponse, error)
+	// UnlockNote снимает блокировку заметки
+	UnlockNote(context.Context, *UnlockNoteRequest) (*UnlockNoteResponse, error)
+	// SetNotePassphrase защищает заметку парольной фразой, меняет или снимает ее (только владелец)
+	// GetNote и UpdateNote защищенной заметки требуют фразу в метаданных x-note-passphrase
+	// (заголовок X-Note-Passphrase); смена и снятие защиты - текущую фразу там же
+	SetNotePassphrase(context.Context, *SetNotePassphraseRequest) (*SetNotePassphraseResponse, error)
+	// BatchCreateNotes создает несколько заметок за один запрос
+	BatchCreateNotes(context.Context, *BatchCreateNotesRequest) (*BatchCreateNotesResponse, error)
+	// BatchGetNotes возвращает несколько заметок по списку UUID
+	BatchGetNotes(context.Context, *BatchGetNotesRequest) (*BatchGetNotesResponse, error)
+	// BatchDeleteNotes удаляет несколько заметок за один запрос
+	BatchDeleteNotes(context.Context, *BatchDeleteNotesRequest) (*BatchDeleteNotesResponse, error)
+	// ListNoteRevisions возвращает историю изменений заметки
+	ListNoteRevisions(context.Context, *ListNoteRevisionsRequest) (*ListNoteRevisionsResponse, error)
+	// GetNoteRevision возвращает конкретную ревизию заметки
+	GetNoteRevision(context.Context, *GetNoteRevisionRequest) (*GetNoteRevisionResponse, error)
+	// DiffNoteRevisions возвращает построчные различия содержимого двух ревизий заметки
+	// Дифф вычисляется на сервере, клиенту не нужно загружать обе ревизии целиком
+	DiffNoteRevisions(context.Context, *DiffNoteRevisionsRequest) (*DiffNoteRevisionsResponse, error)
+	// ListNotesByTag возвращает заметки с указанным тегом
+	ListNotesByTag(context.Context, *ListNotesByTagRequest) (*ListNotesByTagResponse, error)
+	// ListTags возвращает все теги с количеством заметок
+	ListTags(context.Context, *ListTagsRequest) (*ListTagsResponse, error)
+	// SuggestNotes возвращает заметки пользователя, заголовок или слово заголовка которых начинается
+	// с prefix, - для автодополнения. Часто открываемые и недавно измененные заметки идут первыми
+	SuggestNotes(context.Context, *SuggestNotesRequest) (*SuggestNotesResponse, error)
+	// GetNoteStats возвращает статистику заметки: слова, символы, время чтения и изменение последней правки
+	GetNoteStats(context.Context, *GetNoteStatsRequest) (*GetNoteStatsResponse, error)
+	// GetAccountStats возвращает сводную статистику заметок пользователя
+	GetAccountStats(context.Context, *GetAccountStatsRequest) (*GetAccountStatsResponse, error)
+	// ShareNote предоставляет пользователю доступ к своей заметке (повторный вызов меняет уровень доступа)
+	ShareNote(context.Context, *ShareNoteRequest) (*ShareNoteResponse, error)
+	// UnshareNote отзывает доступ пользователя к своей заметке
+	UnshareNote(context.Context, *UnshareNoteRequest) (*UnshareNoteResponse, error)
+	// ListSharedNotes возвращает заметки других пользователей, доступные вызывающему пользователю
+	ListSharedNotes(context.Context, *ListSharedNotesRequest) (*ListSharedNotesResponse, error)
+	// ExportNotes выгружает заметки пользователя файлом в выбранном формате (server-side streaming)
+	ExportNotes(*ExportNotesRequest, grpc.ServerStreamingServer[ExportNotesResponse]) error
+	// ExportToDestination запускает выгрузку всех заметок пользователя в настроенное хранилище
+	// (S3/MinIO или локальный каталог) как длительную операцию. Ход выполнения возвращает
+	// GetExportOperation, по завершении подписчики SubscribeToEvents получают ExportCompletedEvent
+	ExportToDestination(context.Context, *ExportToDestinationRequest) (*ExportOperation, error)
+	// GetExportOperation возвращает состояние операции выгрузки
+	GetExportOperation(context.Context, *GetExportOperationRequest) (*ExportOperation, error)
+	// ImportNotes загружает заметки из файла выгрузки (client-side streaming)
+	// Первое сообщение содержит формат, последующие - части файла. Заметки создаются заново
+	ImportNotes(grpc.ClientStreamingServer[ImportNotesRequest, ImportNotesResponse]) error
+	// GetServerInfo возвращает возможности сервера (поддерживаемые схемы сквозного шифрования)
+	GetServerInfo(context.Context, *GetServerInfoRequest) (*GetServerInfoResponse, error)
+	// AdminListAllNotes возвращает заметки всех пользователей (только для роли admin)
+	AdminListAllNotes(context.Context, *AdminListAllNotesRequest) (*AdminListAllNotesResponse, error)
+	// RotateKeys запускает смену ключей шифрования заметок (только для роли admin) как длительную
+	// операцию: создает новые ключи данных владельцев, перешифровывает ключи данных текущим
+	// мастер-ключом и в фоне перешифровывает затронутые заметки. Ход выполнения возвращает
+	// GetKeyRotationOperation. Без настроенного шифрования возвращает UNIMPLEMENTED
+	RotateKeys(context.Context, *RotateKeysRequest) (*KeyRotationOperation, error)
+	// GetKeyRotationOperation возвращает состояние операции смены ключей (только для роли admin)
+	GetKeyRotationOperation(context.Context, *GetKeyRotationOperationRequest) (*KeyRotationOperation, error)
+	// RestoreBackup восстанавливает заметки вместе с их ревизиями и доступами из резервной копии
+	// хранилища (только для роли admin). Заметки, которые уже есть в хранилище, обрабатываются
+	// согласно conflict_strategy, а dry_run только считает изменения. Состояние копирования
+	// возвращает GetServerInfo. Без настроенного резервного копирования возвращает UNIMPLEMENTED
+	RestoreBackup(context.Context, *RestoreBackupRequest) (*RestoreBackupResponse, error)
+	// GetUsageStats возвращает обезличенную статистику использования реплики с момента запуска:
+	// количество вызовов методов и использование функций (только для роли admin). Пользователи
+	// и данные запросов не собираются. Если сбор статистики выключен, возвращает UNIMPLEMENTED
+	GetUsageStats(context.Context, *GetUsageStatsRequest) (*GetUsageStatsResponse, error)
+	// RegisterWebhook регистрирует адрес, на который сервер отправляет POST запросами события
+	// заметок вызывающего пользователя (как в SubscribeToEvents). Запросы подписаны HMAC-SHA256
+	// (заголовок X-Webhook-Signature), недоставленные после всех попыток события
+	// возвращает ListWebhookDeadLetters. Ключ подписи возвращается только в ответе RegisterWebhook
+	RegisterWebhook(context.Context, *RegisterWebhookRequest) (*Webhook, error)
+	// ListWebhooks возвращает вебхуки вызывающего пользователя (без ключей подписи)
+	ListWebhooks(context.Context, *ListWebhooksRequest) (*ListWebhooksResponse, error)
+	// DeleteWebhook удаляет вебхук вызывающего пользователя
+	DeleteWebhook(context.Context, *DeleteWebhookRequest) (*DeleteWebhookResponse, error)
+	// ListWebhookDeadLetters возвращает события, которые не удалось доставить вебхукам
+	// вызывающего пользователя за все попытки, начиная с последнего
+	ListWebhookDeadLetters(context.Context, *ListWebhookDeadLettersRequest) (*ListWebhookDeadLettersResponse, error)
+	// SaveSearch сохраняет поиск (умную папку) вызывающего пользователя: именованное выражение фильтра
+	// заметок (см. README, раздел "Сохраненные поиски"). Поиск с тем же именем заменяется
+	SaveSearch(context.Context, *SaveSearchRequest) (*SavedSearch, error)
+	// ListSavedSearches возвращает сохраненные поиски вызывающего пользователя
+	ListSavedSearches(context.Context, *ListSavedSearchesRequest) (*ListSavedSearchesResponse, error)
+	// DeleteSavedSearch удаляет сохраненный поиск вызывающего пользователя
+	DeleteSavedSearch(context.Context, *DeleteSavedSearchRequest) (*DeleteSavedSearchResponse, error)
+	// ExecuteSavedSearch возвращает заметки вызывающего пользователя, подходящие под сохраненный поиск
+	// Когда заметка начинает подходить под поиск, подписчикам SubscribeToEvents и вебхукам
+	// отправляется SavedSearchMatchedEvent
+	ExecuteSavedSearch(context.Context, *ExecuteSavedSearchRequest) (*ExecuteSavedSearchResponse, error)
+	// UploadAttachment загружает вложение заметки (client-side streaming)
+	// Первое сообщение содержит метаданные, последующие - части содержимого файла
+	UploadAttachment(grpc.ClientStreamingServer[AttachmentChunk, Attachment]) error
+	// DownloadAttachment скачивает вложение заметки (server-side streaming)
+	// Первое сообщение содержит метаданные, последующие - части содержимого файла
+	DownloadAttachment(*DownloadAttachmentRequest, grpc.ServerStreamingServer[DownloadAttachmentResponse]) error
+	// SubscribeToEvents подписывается на события заметок: создание и напоминания
+	SubscribeToEvents(*SubscribeToEventsRequest, grpc.ServerStreamingServer[EventResponse]) error
+	// UploadMetrics принимает поток метрик и возвращает агрегированную статистику,
+	// общую и по каждому названию метрики
+	UploadMetrics(grpc.ClientStreamingServer[MetricRequest, SummaryResponse]) error
+	// StreamMetrics - двунаправленный вариант UploadMetrics: с window_seconds в первом сообщении
+	// сервер отправляет статистику каждого окна по мере поступления метрик, а после закрытия
+	// отправки клиентом - итоговую статистику всего стрима (final = true)
+	StreamMetrics(grpc.BidiStreamingServer[StreamMetricsRequest, StreamMetricsResponse]) error
+	// QueryMetrics возвращает значения метрики вызывающего пользователя, загруженные UploadMetrics
+	// и StreamMetrics, за период [from, to) или их агрегат. Если хранилище метрик выключено,
+	// возвращает UNIMPLEMENTED
+	QueryMetrics(context.Context, *QueryMetricsRequest) (*QueryMetricsResponse, error)
+	// Chat - двунаправленный стрим для обмена сообщениями в комнатах
+	// Клиент входит в комнаты (join_room) и получает сообщения всех участников комнат, в которых находится
+	Chat(grpc.BidiStreamingServer[ChatMessage, ChatMessage]) error
+	mustEmbedUnimplementedNotesServiceServer()
+}
+
+// UnimplementedNotesServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedNotesServiceServer struct{}
+
+func (UnimplementedNotesServiceServer) CreateNote(context.Context, *CreateNoteRequest) (*CreateNoteResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method CreateNote not implemented")
+}
+func (UnimplementedNotesServiceServer) GetNote(context.Context, *GetNoteRequest) (*GetNoteResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetNote not implemented")
+}
+func (UnimplementedNotesServiceServer) ListNotes(context.Context, *ListNotesRequest) (*ListNotesResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListNotes not implemented")
+}
+func (UnimplementedNotesServiceServer) StreamNotes(*StreamNotesRequest, grpc.ServerStreamingServer[Note]) error {
+	return status.Error(codes.Unimplemented, "method StreamNotes not implemented")
+}
+func (UnimplementedNotesServiceServer) UpdateNote(context.Context, *UpdateNoteRequest) (*UpdateNoteResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method UpdateNote not implemented")
+}
+func (UnimplementedNotesServiceServer) DeleteNote(context.Context, *DeleteNoteRequest) (*DeleteNoteResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method DeleteNote not implemented")
+}
+func (UnimplementedNotesServiceServer) PinNote(context.Context, *PinNoteRequest) (*PinNoteResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method PinNote not implemented")
+}
+func (UnimplementedNotesServiceServer) UnpinNote(context.Context, *UnpinNoteRequest) (*UnpinNoteResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method UnpinNote not implemented")
+}
+func (UnimplementedNotesServiceServer) ArchiveNote(context.Context, *ArchiveNoteRequest) (*ArchiveNoteResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ArchiveNote not implemented")
+}
+func (UnimplementedNotesServiceServer) UnarchiveNote(context.Context, *UnarchiveNoteRequest) (*UnarchiveNoteResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method UnarchiveNote not implemented")
+}
+func (UnimplementedNotesServiceServer) LockNote(context.Context, *LockNoteRequest) (*LockNoteResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method LockNote not implemented")
+}
+func (UnimplementedNotesServiceServer) UnlockNote(context.Context, *UnlockNoteRequest) (*UnlockNoteResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method UnlockNote not implemented")
+}
+func (UnimplementedNotesServiceServer) SetNotePassphrase(context.Context, *SetNotePassphraseRequest) (*SetNotePassphraseResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method SetNotePassphrase not implemented")
+}
+func (UnimplementedNotesServiceServer) BatchCreateNotes(context.Context, *BatchCreateNotesRequest) (*BatchCreateNotesResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method BatchCreateNotes not implemented")
+}
+func (UnimplementedNotesServiceServer) BatchGetNotes(context.Context, *BatchGetNotesRequest) (*BatchGetNotesResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method BatchGetNotes not implemented")
+}
+func (UnimplementedNotesServiceServer) BatchDeleteNotes(context.Context, *BatchDeleteNotesRequest) (*BatchDeleteNotesResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method BatchDeleteNotes not implemented")
+}
+func (UnimplementedNotesServiceServer) ListNoteRevisions(context.Context, *ListNoteRevisionsRequest) (*ListNoteRevisionsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListNoteRevisions not implemented")
+}
+func (UnimplementedNotesServiceServer) GetNoteRevision(context.Context, *GetNoteRevisionRequest) (*GetNoteRevisionResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetNoteRevision not implemented")
+}
+func (UnimplementedNotesServiceServer) DiffNoteRevisions(context.Context, *DiffNoteRevisionsRequest) (*DiffNoteRevisionsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method DiffNoteRevisions not implemented")
+}
+func (UnimplementedNotesServiceServer) ListNotesByTag(context.Context, *ListNotesByTagRequest) (*ListNotesByTagResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListNotesByTag not implemented")
+}
+func (UnimplementedNotesServiceServer) ListTags(context.Context, *ListTagsRequest) (*ListTagsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListTags not implemented")
+}
+func (UnimplementedNotesServiceServer) SuggestNotes(context.Context, *SuggestNotesRequest) (*SuggestNotesResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method SuggestNotes not implemented")
+}
+func (UnimplementedNotesServiceServer) GetNoteStats(context.Context, *GetNoteStatsRequest) (*GetNoteStatsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetNoteStats not implemented")
+}
+func (UnimplementedNotesServiceServer) GetAccountStats(context.Context, *GetAccountStatsRequest) (*GetAccountStatsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetAccountStats not implemented")
+}
+func (UnimplementedNotesServiceServer) ShareNote(context.Context, *ShareNoteRequest) (*ShareNoteResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ShareNote not implemented")
+}
+func (UnimplementedNotesServiceServer) UnshareNote(context.Context, *UnshareNoteRequest) (*UnshareNoteResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method UnshareNote not implemented")
+}
+func (UnimplementedNotesServiceServer) ListSharedNotes(context.Context, *ListSharedNotesRequest) (*ListSharedNotesResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListSharedNotes not implemented")
+}
+func (UnimplementedNotesServiceServer) ExportNotes(*ExportNotesRequest, grpc.ServerStreamingServer[ExportNotesResponse]) error {
+	return status.Error(codes.Unimplemented, "method ExportNotes not implemented")
+}
+func (UnimplementedNotesServiceServer) ExportToDestination(context.Context, *ExportToDestinationRequest) (*ExportOperation, error) {
+	return nil, status.Error(codes.Unimplemented, "method ExportToDestination not implemented")
+}
+func (UnimplementedNotesServiceServer) GetExportOperation(context.Context, *GetExportOperationRequest) (*ExportOperation, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetExportOperation not implemented")
+}
+func (UnimplementedNotesServiceServer) ImportNotes(grpc.ClientStreamingServer[ImportNotesRequest, ImportNotesResponse]) error {
+	return status.Error(codes.Unimplemented, "method ImportNotes not implemented")
+}
+func (UnimplementedNotesServiceServer) GetServerInfo(context.Context, *GetServerInfoRequest) (*GetServerInfoResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetServerInfo not implemented")
+}
+func (UnimplementedNotesServiceServer) AdminListAllNotes(context.Context, *AdminListAllNotesRequest) (*AdminListAllNotesResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method AdminListAllNotes not implemented")
+}
+func (UnimplementedNotesServiceServer) RotateKeys(context.Context, *RotateKeysRequest) (*KeyRotationOperation, error) {
+	return nil, status.Error(codes.Unimplemented, "method RotateKeys not implemented")
+}
+func (UnimplementedNotesServiceServer) GetKeyRotationOperation(context.Context, *GetKeyRotationOperationRequest) (*KeyRotationOperation, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetKeyRotationOperation not implemented")
+}
+func (UnimplementedNotesServiceServer) RestoreBackup(context.Context, *RestoreBackupRequest) (*RestoreBackupResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method RestoreBackup not implemented")
+}
+func (UnimplementedNotesServiceServer) GetUsageStats(context.Context, *GetUsageStatsRequest) (*GetUsageStatsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetUsageStats not implemented")
+}
+func (UnimplementedNotesServiceServer) RegisterWebhook(context.Context, *RegisterWebhookRequest) (*Webhook, error) {
+	return nil, status.Error(codes.Unimplemented, "method RegisterWebhook not implemented")
+}
+func (UnimplementedNotesServiceServer) ListWebhooks(context.Context, *ListWebhooksRequest) (*ListWebhooksResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListWebhooks not implemented")
+}
+func (UnimplementedNotesServiceServer) DeleteWebhook(context.Context, *DeleteWebhookRequest) (*DeleteWebhookResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method DeleteWebhook not implemented")
+}
+func (UnimplementedNotesServiceServer) ListWebhookDeadLetters(context.Context, *ListWebhookDeadLettersRequest) (*ListWebhookDeadLettersResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListWebhookDeadLetters not implemented")
+}
+func (UnimplementedNotesServiceServer) SaveSearch(context.Context, *SaveSearchRequest) (*SavedSearch, error) {
+	return nil, status.Error(codes.Unimplemented, "method SaveSearch not implemented")
+}
+func (UnimplementedNotesServiceServer) ListSavedSearches(context.Context, *ListSavedSearchesRequest) (*ListSavedSearchesResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListSavedSearches not implemented")
+}
+func (UnimplementedNotesServiceServer) DeleteSavedSearch(context.Context, *DeleteSavedSearchRequest) (*DeleteSavedSearchResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method DeleteSavedSearch not implemented")
+}
+func (UnimplementedNotesServiceServer) ExecuteSavedSearch(context.Context, *ExecuteSavedSearchRequest) (*ExecuteSavedSearchResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ExecuteSavedSearch not implemented")
+}
+func (UnimplementedNotesServiceServer) UploadAttachment(grpc.ClientStreamingServer[AttachmentChunk, Attachment]) error {
+	return status.Error(codes.Unimplemented, "method UploadAttachment not implemented")
+}
+func (UnimplementedNotesServiceServer) DownloadAttachment(*DownloadAttachmentRequest, grpc.ServerStreamingServer[DownloadAttachmentResponse]) error {
+	return status.Error(codes.Unimplemented, "method DownloadAttachment not implemented")
+}
+func (UnimplementedNotesServiceServer) SubscribeToEvents(*SubscribeToEventsRequest, grpc.ServerStreamingServer[EventResponse]) error {
+	return status.Error(codes.Unimplemented, "method SubscribeToEvents not implemented")
+}
+func (UnimplementedNotesServiceServer) UploadMetrics(grpc.ClientStreamingServer[MetricRequest, SummaryResponse]) error {
+	return status.Error(codes.Unimplemented, "method UploadMetrics not implemented")
+}
+func (UnimplementedNotesServiceServer) StreamMetrics(grpc.BidiStreamingServer[StreamMetricsRequest, StreamMetricsResponse]) error {
+	return status.Error(codes.Unimplemented, "method StreamMetrics not implemented")
+}
+func (UnimplementedNotesServiceServer) QueryMetrics(context.Context, *QueryMetricsRequest) (*QueryMetricsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method QueryMetrics not implemented")
+}
+func (UnimplementedNotesServiceServer) Chat(grpc.BidiStreamingServer[ChatMessage, ChatMessage]) error {
+	return status.Error(codes.Unimplemented, "method Chat not implemented")
+}
+func (UnimplementedNotesServiceServer) mustEmbedUnimplementedNotesServiceServer() {}
+func (UnimplementedNotesServiceServer) testEmbeddedByValue()                      {}
+
+// UnsafeNotesServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to NotesServiceServer will
+// result in compilation errors.
+type UnsafeNotesServiceServer interface {
+	mustEmbedUnimplementedNotesServiceServer()
+}
+
+func RegisterNotesServiceServer(s grpc.ServiceRegistrar, srv NotesServiceServer) {
+	// If the following call panics, it indicates UnimplementedNotesServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&NotesService_ServiceDesc, srv)
+}
+
+func _NotesService_CreateNote_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateNoteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NotesServiceServer).CreateNote(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NotesService_CreateNote_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NotesServiceServer).CreateNote(ctx, req.(*CreateNoteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NotesService_GetNote_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetNoteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NotesServiceServer).GetNote(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NotesService_GetNote_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NotesServiceServer).GetNote(ctx, req.(*GetNoteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NotesService_ListNotes_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListNotesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NotesServiceServer).ListNotes(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NotesService_ListNotes_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NotesServiceServer).ListNotes(ctx, req.(*ListNotesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NotesService_StreamNotes_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamNotesRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(NotesServiceServer).StreamNotes(m, &grpc.GenericServerStream[StreamNotesRequest, Note]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type NotesService_StreamNotesServer = grpc.ServerStreamingServer[Note]
+
+func _NotesService_UpdateNote_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateNoteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NotesServiceServer).UpdateNote(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NotesService_UpdateNote_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NotesServiceServer).UpdateNote(ctx, req.(*UpdateNoteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NotesService_DeleteNote_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteNoteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NotesServiceServer).DeleteNote(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NotesService_DeleteNote_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NotesServiceServer).DeleteNote(ctx, req.(*DeleteNoteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NotesService_PinNote_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PinNoteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NotesServiceServer).PinNote(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NotesService_PinNote_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NotesServiceServer).PinNote(ctx, req.(*PinNoteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NotesService_UnpinNote_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UnpinNoteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NotesServiceServer).UnpinNote(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NotesService_UnpinNote_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NotesServiceServer).UnpinNote(ctx, req.(*UnpinNoteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NotesService_ArchiveNote_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ArchiveNoteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NotesServiceServer).ArchiveNote(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NotesService_ArchiveNote_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NotesServiceServer).ArchiveNote(ctx, req.(*ArchiveNoteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NotesService_UnarchiveNote_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UnarchiveNoteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NotesServiceServer).UnarchiveNote(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NotesService_UnarchiveNote_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NotesServiceServer).UnarchiveNote(ctx, req.(*UnarchiveNoteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NotesService_LockNote_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LockNoteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NotesServiceServer).LockNote(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NotesService_LockNote_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NotesServiceServer).LockNote(ctx, req.(*LockNoteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NotesService_UnlockNote_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UnlockNoteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NotesServiceServer).UnlockNote(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NotesService_UnlockNote_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NotesServiceServer).UnlockNote(ctx, req.(*UnlockNoteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NotesService_SetNotePassphrase_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetNotePassphraseRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NotesServiceServer).SetNotePassphrase(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NotesService_SetNotePassphrase_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NotesServiceServer).SetNotePassphrase(ctx, req.(*SetNotePassphraseRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NotesService_BatchCreateNotes_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchCreateNotesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NotesServiceServer).BatchCreateNotes(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NotesService_BatchCreateNotes_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NotesServiceServer).BatchCreateNotes(ctx, req.(*BatchCreateNotesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NotesService_BatchGetNotes_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchGetNotesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NotesServiceServer).BatchGetNotes(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NotesService_BatchGetNotes_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NotesServiceServer).BatchGetNotes(ctx, req.(*BatchGetNotesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NotesService_BatchDeleteNotes_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchDeleteNotesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NotesServiceServer).BatchDeleteNotes(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NotesService_BatchDeleteNotes_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NotesServiceServer).BatchDeleteNotes(ctx, req.(*BatchDeleteNotesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NotesService_ListNoteRevisions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListNoteRevisionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NotesServiceServer).ListNoteRevisions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NotesService_ListNoteRevisions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NotesServiceServer).ListNoteRevisions(ctx, req.(*ListNoteRevisionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NotesService_GetNoteRevision_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetNoteRevisionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NotesServiceServer).GetNoteRevision(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NotesService_GetNoteRevision_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NotesServiceServer).GetNoteRevision(ctx, req.(*GetNoteRevisionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NotesService_DiffNoteRevisions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DiffNoteRevisionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NotesServiceServer).DiffNoteRevisions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NotesService_DiffNoteRevisions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NotesServiceServer).DiffNoteRevisions(ctx, req.(*DiffNoteRevisionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NotesService_ListNotesByTag_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListNotesByTagRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NotesServiceServer).ListNotesByTag(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NotesService_ListNotesByTag_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NotesServiceServer).ListNotesByTag(ctx, req.(*ListNotesByTagRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NotesService_ListTags_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListTagsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NotesServiceServer).ListTags(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NotesService_ListTags_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NotesServiceServer).ListTags(ctx, req.(*ListTagsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NotesService_SuggestNotes_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SuggestNotesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NotesServiceServer).SuggestNotes(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NotesService_SuggestNotes_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NotesServiceServer).SuggestNotes(ctx, req.(*SuggestNotesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NotesService_GetNoteStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetNoteStatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NotesServiceServer).GetNoteStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NotesService_GetNoteStats_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NotesServiceServer).GetNoteStats(ctx, req.(*GetNoteStatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NotesService_GetAccountStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetAccountStatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NotesServiceServer).GetAccountStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NotesService_GetAccountStats_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NotesServiceServer).GetAccountStats(ctx, req.(*GetAccountStatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NotesService_ShareNote_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ShareNoteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NotesServiceServer).ShareNote(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NotesService_ShareNote_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NotesServiceServer).ShareNote(ctx, req.(*ShareNoteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NotesService_UnshareNote_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UnshareNoteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NotesServiceServer).UnshareNote(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NotesService_UnshareNote_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NotesServiceServer).UnshareNote(ctx, req.(*UnshareNoteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NotesService_ListSharedNotes_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListSharedNotesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NotesServiceServer).ListSharedNotes(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NotesService_ListSharedNotes_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NotesServiceServer).ListSharedNotes(ctx, req.(*ListSharedNotesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NotesService_ExportNotes_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ExportNotesRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(NotesServiceServer).ExportNotes(m, &grpc.GenericServerStream[ExportNotesRequest, ExportNotesResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type NotesService_ExportNotesServer = grpc.ServerStreamingServer[ExportNotesResponse]
+
+func _NotesService_ExportToDestination_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExportToDestinationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NotesServiceServer).ExportToDestination(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NotesService_ExportToDestination_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NotesServiceServer).ExportToDestination(ctx, req.(*ExportToDestinationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NotesService_GetExportOperation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetExportOperationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NotesServiceServer).GetExportOperation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NotesService_GetExportOperation_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NotesServiceServer).GetExportOperation(ctx, req.(*GetExportOperationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NotesService_ImportNotes_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(NotesServiceServer).ImportNotes(&grpc.GenericServerStream[ImportNotesRequest, ImportNotesResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type NotesService_ImportNotesServer = grpc.ClientStreamingServer[ImportNotesRequest, ImportNotesResponse]
+
+func _NotesService_GetServerInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetServerInfoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NotesServiceServer).GetServerInfo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NotesService_GetServerInfo_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NotesServiceServer).GetServerInfo(ctx, req.(*GetServerInfoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NotesService_AdminListAllNotes_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AdminListAllNotesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NotesServiceServer).AdminListAllNotes(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NotesService_AdminListAllNotes_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NotesServiceServer).AdminListAllNotes(ctx, req.(*AdminListAllNotesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NotesService_RotateKeys_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RotateKeysRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NotesServiceServer).RotateKeys(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NotesService_RotateKeys_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NotesServiceServer).RotateKeys(ctx, req.(*RotateKeysRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NotesService_GetKeyRotationOperation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetKeyRotationOperationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NotesServiceServer).GetKeyRotationOperation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NotesService_GetKeyRotationOperation_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NotesServiceServer).GetKeyRotationOperation(ctx, req.(*GetKeyRotationOperationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NotesService_RestoreBackup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RestoreBackupRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NotesServiceServer).RestoreBackup(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NotesService_RestoreBackup_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NotesServiceServer).RestoreBackup(ctx, req.(*RestoreBackupRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NotesService_GetUsageStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetUsageStatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NotesServiceServer).GetUsageStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NotesService_GetUsageStats_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NotesServiceServer).GetUsageStats(ctx, req.(*GetUsageStatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NotesService_RegisterWebhook_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RegisterWebhookRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NotesServiceServer).RegisterWebhook(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NotesService_RegisterWebhook_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NotesServiceServer).RegisterWebhook(ctx, req.(*RegisterWebhookRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NotesService_ListWebhooks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListWebhooksRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NotesServiceServer).ListWebhooks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NotesService_ListWebhooks_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NotesServiceServer).ListWebhooks(ctx, req.(*ListWebhooksRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NotesService_DeleteWebhook_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteWebhookRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NotesServiceServer).DeleteWebhook(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NotesService_DeleteWebhook_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NotesServiceServer).DeleteWebhook(ctx, req.(*DeleteWebhookRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NotesService_ListWebhookDeadLetters_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListWebhookDeadLettersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NotesServiceServer).ListWebhookDeadLetters(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NotesService_ListWebhookDeadLetters_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NotesServiceServer).ListWebhookDeadLetters(ctx, req.(*ListWebhookDeadLettersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NotesService_SaveSearch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SaveSearchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NotesServiceServer).SaveSearch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NotesService_SaveSearch_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NotesServiceServer).SaveSearch(ctx, req.(*SaveSearchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NotesService_ListSavedSearches_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListSavedSearchesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NotesServiceServer).ListSavedSearches(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NotesService_ListSavedSearches_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NotesServiceServer).ListSavedSearches(ctx, req.(*ListSavedSearchesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NotesService_DeleteSavedSearch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteSavedSearchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NotesServiceServer).DeleteSavedSearch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NotesService_DeleteSavedSearch_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NotesServiceServer).DeleteSavedSearch(ctx, req.(*DeleteSavedSearchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NotesService_ExecuteSavedSearch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExecuteSavedSearchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NotesServiceServer).ExecuteSavedSearch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NotesService_ExecuteSavedSearch_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NotesServiceServer).ExecuteSavedSearch(ctx, req.(*ExecuteSavedSearchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NotesService_UploadAttachment_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(NotesServiceServer).UploadAttachment(&grpc.GenericServerStream[AttachmentChunk, Attachment]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type NotesService_UploadAttachmentServer = grpc.ClientStreamingServer[AttachmentChunk, Attachment]
+
+func _NotesService_DownloadAttachment_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(DownloadAttachmentRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(NotesServiceServer).DownloadAttachment(m, &grpc.GenericServerStream[DownloadAttachmentRequest, DownloadAttachmentResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type NotesService_DownloadAttachmentServer = grpc.ServerStreamingServer[DownloadAttachmentResponse]
+
+func _NotesService_SubscribeToEvents_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SubscribeToEventsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(NotesServiceServer).SubscribeToEvents(m, &grpc.GenericServerStream[SubscribeToEventsRequest, EventResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type NotesService_SubscribeToEventsServer = grpc.ServerStreamingServer[EventResponse]
+
+func _NotesService_UploadMetrics_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(NotesServiceServer).UploadMetrics(&grpc.GenericServerStream[MetricRequest, SummaryResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type NotesService_UploadMetricsServer = grpc.ClientStreamingServer[MetricRequest, SummaryResponse]
+
+func _NotesService_StreamMetrics_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(NotesServiceServer).StreamMetrics(&grpc.GenericServerStream[StreamMetricsRequest, StreamMetricsResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type NotesService_StreamMetricsServer = grpc.BidiStreamingServer[StreamMetricsRequest, StreamMetricsResponse]
+
+func _NotesService_QueryMetrics_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QueryMetricsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NotesServiceServer).QueryMetrics(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NotesService_QueryMetrics_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NotesServiceServer).QueryMetrics(ctx, req.(*QueryMetricsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NotesService_Chat_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(NotesServiceServer).Chat(&grpc.GenericServerStream[ChatMessage, ChatMessage]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type NotesService_ChatServer = grpc.BidiStreamingServer[ChatMessage, ChatMessage]
+
+// NotesService_ServiceDesc is the grpc.ServiceDesc for NotesService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var NotesService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "notes.v1.NotesService",
+	HandlerType: (*NotesServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreateNote",
+			Handler:    _NotesService_CreateNote_Handler,
+		},
+		{
+			MethodName: "GetNote",
+			Handler:    _NotesService_GetNote_Handler,
+		},
+		{
+			MethodName: "ListNotes",
+			Handler:    _NotesService_ListNotes_Handler,
+		},
+		{
+			MethodName: "UpdateNote",
+			Handler:    _NotesService_UpdateNote_Handler,
+		},
+		{
+			MethodName: "DeleteNote",
+			Handler:    _NotesService_DeleteNote_Handler,
+		},
+		{
+			MethodName: "PinNote",
+			Handler:    _NotesService_PinNote_Handler,
+		},
+		{
+			MethodName: "UnpinNote",
+			Handler:    _NotesService_UnpinNote_Handler,
+		},
+		{
+			MethodName: "ArchiveNote",
+			Handler:    _NotesService_ArchiveNote_Handler,
+		},
+		{
+			MethodName: "UnarchiveNote",
+			Handler:    _NotesService_UnarchiveNote_Handler,
+		},
+		{
+			MethodName: "LockNote",
+			Handler:    _NotesService_LockNote_Handler,
+		},
+		{
+			MethodName: "UnlockNote",
+			Handler:    _NotesService_UnlockNote_Handler,
+		},
+		{
+			MethodName: "SetNotePassphrase",
+			Handler:    _NotesService_SetNotePassphrase_Handler,
+		},
+		{
+			MethodName: "BatchCreateNotes",
+			Handler:    _NotesService_BatchCreateNotes_Handler,
+		},
+		{
+			MethodName: "BatchGetNotes",
+			Handler:    _NotesService_BatchGetNotes_Handler,
+		},
+		{
+			MethodName: "BatchDeleteNotes",
+			Handler:    _NotesService_BatchDeleteNotes_Handler,
+		},
+		{
+			MethodName: "ListNoteRevisions",
+			Handler:    _NotesService_ListNoteRevisions_Handler,
+		},
+		{
+			MethodName: "GetNoteRevision",
+			Handler:    _NotesService_GetNoteRevision_Handler,
+		},
+		{
+			MethodName: "DiffNoteRevisions",
+			Handler:    _NotesService_DiffNoteRevisions_Handler,
+		},
+		{
+			MethodName: "ListNotesByTag",
+			Handler:    _NotesService_ListNotesByTag_Handler,
+		},
+		{
+			MethodName: "ListTags",
+			Handler:    _NotesService_ListTags_Handler,
+		},
+		{
+			MethodName: "SuggestNotes",
+			Handler:    _NotesService_SuggestNotes_Handler,
+		},
+		{
+			MethodName: "GetNoteStats",
+			Handler:    _NotesService_GetNoteStats_Handler,
+		},
+		{
+			MethodName: "GetAccountStats",
+			Handler:    _NotesService_GetAccountStats_Handler,
+		},
+		{
+			MethodName: "ShareNote",
+			Handler:    _NotesService_ShareNote_Handler,
+		},
+		{
+			MethodName: "UnshareNote",
+			Handler:    _NotesService_UnshareNote_Handler,
+		},
+		{
+			MethodName: "ListSharedNotes",
+			Handler:    _NotesService_ListSharedNotes_Handler,
+		},
+		{
+			MethodName: "ExportToDestination",
+			Handler:    _NotesService_ExportToDestination_Handler,
+		},
+		{
+			MethodName: "GetExportOperation",
+			Handler:    _NotesService_GetExportOperation_Handler,
+		},
+		{
+			MethodName: "GetServerInfo",
+			Handler:    _NotesService_GetServerInfo_Handler,
+		},
+		{
+			MethodName: "AdminListAllNotes",
+			Handler:    _NotesService_AdminListAllNotes_Handler,
+		},
+		{
+			MethodName: "RotateKeys",
+			Handler:    _NotesService_RotateKeys_Handler,
+		},
+		{
+			MethodName: "GetKeyRotationOperation",
+			Handler:    _NotesService_GetKeyRotationOperation_Handler,
+		},
+		{
+			MethodName: "RestoreBackup",
+			Handler:    _NotesService_RestoreBackup_Handler,
+		},
+		{
+			MethodName: "GetUsageStats",
+			Handler:    _NotesService_GetUsageStats_Handler,
+		},
+		{
+			MethodName: "RegisterWebhook",
+			Handler:    _NotesService_RegisterWebhook_Handler,
+		},
+		{
+			MethodName: "ListWebhooks",
+			Handler:    _NotesService_ListWebhooks_Handler,
+		},
+		{
+			MethodName: "DeleteWebhook",
+			Handler:    _NotesService_DeleteWebhook_Handler,
+		},
+		{
+			MethodName: "ListWebhookDeadLetters",
+			Handler:    _NotesService_ListWebhookDeadLetters_Handler,
+		},
+		{
+			MethodName: "SaveSearch",
+			Handler:    _NotesService_SaveSearch_Handler,
+		},
+		{
+			MethodName: "ListSavedSearches",
+			Handler:    _NotesService_ListSavedSearches_Handler,
+		},
+		{
+			MethodName: "DeleteSavedSearch",
+			Handler:    _NotesService_DeleteSavedSearch_Handler,
+		},
+		{
+			MethodName: "ExecuteSavedSearch",
+			Handler:    _NotesService_ExecuteSavedSearch_Handler,
+		},
+		{
+			MethodName: "QueryMetrics",
+			Handler:    _NotesService_QueryMetrics_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamNotes",
+			Handler:       _NotesService_StreamNotes_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "ExportNotes",
+			Handler:       _NotesService_ExportNotes_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "ImportNotes",
+			Handler:       _NotesService_ImportNotes_Handler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "UploadAttachment",
+			Handler:       _NotesService_UploadAttachment_Handler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "DownloadAttachment",
+			Handler:       _NotesService_DownloadAttachment_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "SubscribeToEvents",
+			Handler:       _NotesService_SubscribeToEvents_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "UploadMetrics",
+			Handler:       _NotesService_UploadMetrics_Handler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "StreamMetrics",
+			Handler:       _NotesService_StreamMetrics_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "Chat",
+			Handler:       _NotesService_Chat_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "proto/notes/v1/notes.proto",
+}
+
+const (
+	AuthService_Login_FullMethodName             = "/notes.v1.AuthService/Login"
+	AuthService_RefreshToken_FullMethodName      = "/notes.v1.AuthService/RefreshToken"
+	AuthService_Logout_FullMethodName            = "/notes.v1.AuthService/Logout"
+	AuthService_IssueStreamTicket_FullMethodName = "/notes.v1.AuthService/IssueStreamTicket"
+)
+
+// AuthServiceClient is the client API for AuthService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// AuthService выдает токены пользователям из конфигурации (auth.sessions.users)
+// Login и RefreshToken доступны без токена, токены привязаны к сессии и отзываются через Logout
+type AuthServiceClient interface {
+	// Login проверяет имя пользователя и пароль и открывает сессию
+	// Gateway дополнительно сохраняет токены в HttpOnly cookie
+	Login(ctx context.Context, in *LoginRequest, opts ...grpc.CallOption) (*AuthTokens, error)
+	// RefreshToken выдает новую пару токенов по refresh токену сессии
+	// Использованный refresh токен больше не принимается, его повторное предъявление закрывает сессию
+	RefreshToken(ctx context.Context, in *RefreshTokenRequest, opts ...grpc.CallOption) (*AuthTokens, error)
+	// Logout закрывает сессию: ее refresh токен и выданные access токены больше не принимаются
+	Logout(ctx context.Context, in *LogoutRequest, opts ...grpc.CallOption) (*LogoutResponse, error)
+	// IssueStreamTicket выдает короткоживущий одноразовый билет на открытие стрима из браузера
+	// Билет передается в URL параметром ticket вместо токена: WebSocket и EventSource
+	// не позволяют задать заголовок Authorization, а долгоживущий токен в URL попадает в логи
+	IssueStreamTicket(ctx context.Context, in *IssueStreamTicketRequest, opts ...grpc.CallOption) (*StreamTicket, error)
+}
+
+type authServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewAuthServiceClient(cc grpc.ClientConnInterface) AuthServiceClient {
+	return &authServiceClient{cc}
+}
+
+func (c *authServiceClient) Login(ctx context.Context, in *LoginRequest, opts ...grpc.CallOption) (*AuthTokens, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AuthTokens)
+	err := c.cc.Invoke(ctx, AuthService_Login_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authServiceClient) RefreshToken(ctx context.Context, in *RefreshTokenRequest, opts ...grpc.CallOption) (*AuthTokens, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AuthTokens)
+	err := c.cc.Invoke(ctx, AuthService_RefreshToken_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authServiceClient) Logout(ctx context.Context, in *LogoutRequest, opts ...grpc.CallOption) (*LogoutResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(LogoutResponse)
+	err := c.cc.Invoke(ctx, AuthService_Logout_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authServiceClient) IssueStreamTicket(ctx context.Context, in *IssueStreamTicketRequest, opts ...grpc.CallOption) (*StreamTicket, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(StreamTicket)
+	err := c.cc.Invoke(ctx, AuthService_IssueStreamTicket_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// AuthServiceServer is the server API for AuthService service.
+// All implementations must embed UnimplementedAuthServiceServer
+// for forward compatibility.
+//
+// AuthService выдает токены пользователям из конфигурации (auth.sessions.users)
+// Login и RefreshToken доступны без токена, токены привязаны к сессии и отзываются через Logout
+type AuthServiceServer interface {
+	// Login проверяет имя пользователя и пароль и открывает сессию
+	// Gateway дополнительно сохраняет токены в HttpOnly cookie
+	Login(context.Context, *LoginRequest) (*AuthTokens, error)
+	// RefreshToken выдает новую пару токенов по refresh токену сессии
+	// Использованный refresh токен больше не принимается, его повторное предъявление закрывает сессию
+	RefreshToken(context.Context, *RefreshTokenRequest) (*AuthTokens, error)
+	// Logout закрывает сессию: ее refresh токен и выданные access токены больше не принимаются
+	Logout(context.Context, *LogoutRequest) (*LogoutResponse, error)
+	// IssueStreamTicket выдает короткоживущий одноразовый билет на открытие стрима из браузера
+	// Билет передается в URL параметром ticket вместо токена: WebSocket и EventSource
+	// не позволяют задать заголовок Authorization, а долгоживущий токен в URL попадает в логи
+	IssueStreamTicket(context.Context, *IssueStreamTicketRequest) (*StreamTicket, error)
+	mustEmbedUnimplementedAuthServiceServer()
+}
+
+// UnimplementedAuthServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedAuthServiceServer struct{}
+
+func (UnimplementedAuthServiceServer) Login(context.Context, *LoginRequest) (*AuthTokens, error) {
+	return nil, status.Error(codes.Unimplemented, "method Login not implemented")
+}
+func (UnimplementedAuthServiceServer) RefreshToken(context.Context, *RefreshTokenRequest) (*AuthTokens, error) {
+	return nil, status.Error(codes.Unimplemented, "method RefreshToken not implemented")
+}
+func (UnimplementedAuthServiceServer) Logout(context.Context, *LogoutRequest) (*LogoutResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method Logout not implemented")
+}
+func (UnimplementedAuthServiceServer) IssueStreamTicket(context.Context, *IssueStreamTicketRequest) (*StreamTicket, error) {
+	return nil, status.Error(codes.Unimplemented, "method IssueStreamTicket not implemented")
+}
+func (UnimplementedAuthServiceServer) mustEmbedUnimplementedAuthServiceServer() {}
+func (UnimplementedAuthServiceServer) testEmbeddedByValue()                     {}
+
+// UnsafeAuthServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to AuthServiceServer will
+// result in compilation errors.
+type UnsafeAuthServiceServer interface {
+	mustEmbedUnimplementedAuthServiceServer()
+}
+
+func RegisterAuthServiceServer(s grpc.ServiceRegistrar, srv AuthServiceServer) {
+	// If the following call panics, it indicates UnimplementedAuthServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&AuthService_ServiceDesc, srv)
+}
+
+func _AuthService_Login_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LoginRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServiceServer).Login(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AuthService_Login_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServiceServer).Login(ctx, req.(*LoginRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AuthService_RefreshToken_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RefreshTokenRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServiceServer).RefreshToken(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AuthService_RefreshToken_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServiceServer).RefreshToken(ctx, req.(*RefreshTokenRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AuthService_Logout_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LogoutRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServiceServer).Logout(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AuthService_Logout_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServiceServer).Logout(ctx, req.(*LogoutRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AuthService_IssueStreamTicket_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(IssueStreamTicketRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServiceServer).IssueStreamTicket(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AuthService_IssueStreamTicket_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServiceServer).IssueStreamTicket(ctx, req.(*IssueStreamTicketRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// AuthService_ServiceDesc is the grpc.ServiceDesc for AuthService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var AuthService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "notes.v1.AuthService",
+	HandlerType: (*AuthServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Login",
+			Handler:    _AuthService_Login_Handler,
+		},
+		{
+			MethodName: "RefreshToken",
+			Handler:    _AuthService_RefreshToken_Handler,
+		},
+		{
+			MethodName: "Logout",
+			Handler:    _AuthService_Logout_Handler,
+		},
+		{
+			MethodName: "IssueStreamTicket",
+			Handler:    _AuthService_IssueStreamTicket_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "proto/notes/v1/notes.proto",
+}
+
+const (
+	UserService_CreateUser_FullMethodName = "/notes.v1.UserService/CreateUser"
+	UserService_GetUser_FullMethodName    = "/notes.v1.UserService/GetUser"
+	UserService_ListUsers_FullMethodName  = "/notes.v1.UserService/ListUsers"
+)
+
+// UserServiceClient is the client API for UserService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Сервис пользователей: хранилище пользователей, на которых ссылаются владельцы заметок и доступы
+// Пользователей создает и просматривает администратор, пользователь видит только себя
+type UserServiceClient interface {
+	// CreateUser создает пользователя (только для администратора)
+	// Пользователь с паролем может входить через AuthService.Login
+	CreateUser(ctx context.Context, in *CreateUserRequest, opts ...grpc.CallOption) (*User, error)
+	// GetUser возвращает пользователя по ID (свою запись или любую для администратора)
+	GetUser(ctx context.Context, in *GetUserRequest, opts ...grpc.CallOption) (*User, error)
+	// ListUsers возвращает всех пользователей, упорядоченных по ID (только для администратора)
+	ListUsers(ctx context.Context, in *ListUsersRequest, opts ...grpc.CallOption) (*ListUsersResponse, error)
+}
+
+type userServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewUserServiceClient(cc grpc.ClientConnInterface) UserServiceClient {
+	return &userServiceClient{cc}
+}
+
+func (c *userServiceClient) CreateUser(ctx context.Context, in *CreateUserRequest, opts ...grpc.CallOption) (*User, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(User)
+	err := c.cc.Invoke(ctx, UserService_CreateUser_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) GetUser(ctx context.Context, in *GetUserRequest, opts ...grpc.CallOption) (*User, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
-	out := new(DeleteNoteResponse)
-	err := c.cc.Invoke(ctx, NotesService_DeleteNote_FullMethodName, in, out, cOpts...)
+	out := new(User)
+	err := c.cc.Invoke(ctx, UserService_GetUser_FullMethodName, in, out, cOpts...)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
-func (c *notesServiceClient) SubscribeToEvents(ctx context.Context, in *SubscribeToEventsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[EventResponse], error) {
+func (c *userServiceClient) ListUsers(ctx context.Context, in *ListUsersRequest, opts ...grpc.CallOption) (*ListUsersResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
-	stream, err := c.cc.NewStream(ctx, &NotesService_ServiceDesc.Streams[0], NotesService_SubscribeToEvents_FullMethodName, cOpts...)
+	out := new(ListUsersResponse)
+	err := c.cc.Invoke(ctx, UserService_ListUsers_FullMethodName, in, out, cOpts...)
 	if err != nil {
 		return nil, err
 	}
-	x := &grpc.GenericClientStream[SubscribeToEventsRequest, EventResponse]{ClientStream: stream}
-	if err := x.ClientStream.SendMsg(in); err != nil {
+	return out, nil
+}
+
+// UserServiceServer is the server API for UserService service.
+// All implementations must embed UnimplementedUserServiceServer
+// for forward compatibility.
+//
+// Сервис пользователей: хранилище пользователей, на которых ссылаются владельцы заметок и доступы
+// Пользователей создает и просматривает администратор, пользователь видит только себя
+type UserServiceServer interface {
+	// CreateUser создает пользователя (только для администратора)
+	// Пользователь с паролем может входить через AuthService.Login
+	CreateUser(context.Context, *CreateUserRequest) (*User, error)
+	// GetUser возвращает пользователя по ID (свою запись или любую для администратора)
+	GetUser(context.Context, *GetUserRequest) (*User, error)
+	// ListUsers возвращает всех пользователей, упорядоченных по ID (только для администратора)
+	ListUsers(context.Context, *ListUsersRequest) (*ListUsersResponse, error)
+	mustEmbedUnimplementedUserServiceServer()
+}
+
+// UnimplementedUserServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedUserServiceServer struct{}
+
+func (UnimplementedUserServiceServer) CreateUser(context.Context, *CreateUserRequest) (*User, error) {
+	return nil, status.Error(codes.Unimplemented, "method CreateUser not implemented")
+}
+func (UnimplementedUserServiceServer) GetUser(context.Context, *GetUserRequest) (*User, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetUser not implemented")
+}
+func (UnimplementedUserServiceServer) ListUsers(context.Context, *ListUsersRequest) (*ListUsersResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListUsers not implemented")
+}
+func (UnimplementedUserServiceServer) mustEmbedUnimplementedUserServiceServer() {}
+func (UnimplementedUserServiceServer) testEmbeddedByValue()                     {}
+
+// UnsafeUserServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to UserServiceServer will
+// result in compilation errors.
+type UnsafeUserServiceServer interface {
+	mustEmbedUnimplementedUserServiceServer()
+}
+
+func RegisterUserServiceServer(s grpc.ServiceRegistrar, srv UserServiceServer) {
+	// If the following call panics, it indicates UnimplementedUserServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&UserService_ServiceDesc, srv)
+}
+
+func _UserService_CreateUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateUserRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	if err := x.ClientStream.CloseSend(); err != nil {
+	if interceptor == nil {
+		return srv.(UserServiceServer).CreateUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserService_CreateUser_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).CreateUser(ctx, req.(*CreateUserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_GetUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetUserRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return x, nil
+	if interceptor == nil {
+		return srv.(UserServiceServer).GetUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserService_GetUser_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).GetUser(ctx, req.(*GetUserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
-type NotesService_SubscribeToEventsClient = grpc.ServerStreamingClient[EventResponse]
+func _UserService_ListUsers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListUsersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).ListUsers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserService_ListUsers_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).ListUsers(ctx, req.(*ListUsersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
 
-func (c *notesServiceClient) UploadMetrics(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[MetricRequest, SummaryResponse], error) {
+// UserService_ServiceDesc is the grpc.ServiceDesc for UserService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var UserService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "notes.v1.UserService",
+	HandlerType: (*UserServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreateUser",
+			Handler:    _UserService_CreateUser_Handler,
+		},
+		{
+			MethodName: "GetUser",
+			Handler:    _UserService_GetUser_Handler,
+		},
+		{
+			MethodName: "ListUsers",
+			Handler:    _UserService_ListUsers_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "proto/notes/v1/notes.proto",
+}
+
+const (
+	AdminService_CreateAPIKey_FullMethodName    = "/notes.v1.AdminService/CreateAPIKey"
+	AdminService_RevokeAPIKey_FullMethodName    = "/notes.v1.AdminService/RevokeAPIKey"
+	AdminService_ListAPIKeys_FullMethodName     = "/notes.v1.AdminService/ListAPIKeys"
+	AdminService_GetPipeline_FullMethodName     = "/notes.v1.AdminService/GetPipeline"
+	AdminService_ListAuditEvents_FullMethodName = "/notes.v1.AdminService/ListAuditEvents"
+)
+
+// AdminServiceClient is the client API for AdminService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Сервис администрирования: ключи API для сервисных клиентов (провайдер apikey в auth.providers)
+// Все методы доступны только администратору
+type AdminServiceClient interface {
+	// CreateAPIKey создает ключ API; секрет ключа возвращается только в ответе
+	CreateAPIKey(ctx context.Context, in *CreateAPIKeyRequest, opts ...grpc.CallOption) (*CreateAPIKeyResponse, error)
+	// RevokeAPIKey отзывает ключ API: запросы с ним больше не принимаются
+	RevokeAPIKey(ctx context.Context, in *RevokeAPIKeyRequest, opts ...grpc.CallOption) (*APIKey, error)
+	// ListAPIKeys возвращает все ключи API в порядке создания, включая отозванные
+	ListAPIKeys(ctx context.Context, in *ListAPIKeysRequest, opts ...grpc.CallOption) (*ListAPIKeysResponse, error)
+	// GetPipeline возвращает действующие цепочки gRPC интерцепторов и HTTP middleware
+	// в порядке выполнения вместе с их настройками из конфигурации
+	GetPipeline(ctx context.Context, in *GetPipelineRequest, opts ...grpc.CallOption) (*GetPipelineResponse, error)
+	// ListAuditEvents возвращает записи журнала аудита, начиная с последней: создание, изменение,
+	// удаление заметок и изменение доступа к ним, обращения к заметкам с парольной фразой
+	ListAuditEvents(ctx context.Context, in *ListAuditEventsRequest, opts ...grpc.CallOption) (*ListAuditEventsResponse, error)
+}
+
+type adminServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewAdminServiceClient(cc grpc.ClientConnInterface) AdminServiceClient {
+	return &adminServiceClient{cc}
+}
+
+func (c *adminServiceClient) CreateAPIKey(ctx context.Context, in *CreateAPIKeyRequest, opts ...grpc.CallOption) (*CreateAPIKeyResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
-	stream, err := c.cc.NewStream(ctx, &NotesService_ServiceDesc.Streams[1], NotesService_UploadMetrics_FullMethodName, cOpts...)
+	out := new(CreateAPIKeyResponse)
+	err := c.cc.Invoke(ctx, AdminService_CreateAPIKey_FullMethodName, in, out, cOpts...)
 	if err != nil {
 		return nil, err
 	}
-	x := &grpc.GenericClientStream[MetricRequest, SummaryResponse]{ClientStream: stream}
-	return x, nil
+	return out, nil
 }
 
-// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
-type NotesService_UploadMetricsClient = grpc.ClientStreamingClient[MetricRequest, SummaryResponse]
+func (c *adminServiceClient) RevokeAPIKey(ctx context.Context, in *RevokeAPIKeyRequest, opts ...grpc.CallOption) (*APIKey, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(APIKey)
+	err := c.cc.Invoke(ctx, AdminService_RevokeAPIKey_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
 
-func (c *notesServiceClient) Chat(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[ChatMessage, ChatMessage], error) {
+func (c *adminServiceClient) ListAPIKeys(ctx context.Context, in *ListAPIKeysRequest, opts ...grpc.CallOption) (*ListAPIKeysResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
-	stream, err := c.cc.NewStream(ctx, &NotesService_ServiceDesc.Streams[2], NotesService_Chat_FullMethodName, cOpts...)
+	out := new(ListAPIKeysResponse)
+	err := c.cc.Invoke(ctx, AdminService_ListAPIKeys_FullMethodName, in, out, cOpts...)
 	if err != nil {
 		return nil, err
 	}
-	x := &grpc.GenericClientStream[ChatMessage, ChatMessage]{ClientStream: stream}
-	return x, nil
+	return out, nil
 }
 
-// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
-type NotesService_ChatClient = grpc.BidiStreamingClient[ChatMessage, ChatMessage]
+func (c *adminServiceClient) GetPipeline(ctx context.Context, in *GetPipelineRequest, opts ...grpc.CallOption) (*GetPipelineResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetPipelineResponse)
+	err := c.cc.Invoke(ctx, AdminService_GetPipeline_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
 
-// NotesServiceServer is the server API for NotesService service.
-// All implementations must embed UnimplementedNotesServiceServer
+func (c *adminServiceClient) ListAuditEvents(ctx context.Context, in *ListAuditEventsRequest, opts ...grpc.CallOption) (*ListAuditEventsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListAuditEventsResponse)
+	err := c.cc.Invoke(ctx, AdminService_ListAuditEvents_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// AdminServiceServer is the server API for AdminService service.
+// All implementations must embed UnimplementedAdminServiceServer
 // for forward compatibility.
 //
-// NotesService предоставляет методы для управления заметками
-type NotesServiceServer interface {
-	// CreateNote создает новую заметку
-	CreateNote(context.Context, *CreateNoteRequest) (*CreateNoteResponse, error)
-	// GetNote возвращает заметку по её UUID
-	GetNote(context.Context, *GetNoteRequest) (*GetNoteResponse, error)
-	// ListNotes возвращает список всех заметок
-	ListNotes(context.Context, *ListNotesRequest) (*ListNotesResponse, error)
-	// UpdateNote обновляет существующую заметку
-	UpdateNote(context.Context, *UpdateNoteRequest) (*UpdateNoteResponse, error)
-	// DeleteNote удаляет заметку по UUID
-	DeleteNote(context.Context, *DeleteNoteRequest) (*DeleteNoteResponse, error)
-	// SubscribeToEvents подписывается на события создания заметок
-	SubscribeToEvents(*SubscribeToEventsRequest, grpc.ServerStreamingServer[EventResponse]) error
-	// UploadMetrics принимает поток метрик и возвращает агрегированную статистику
-	UploadMetrics(grpc.ClientStreamingServer[MetricRequest, SummaryResponse]) error
-	// Chat - двунаправленный стрим для асинхронного обмена сообщениями
-	Chat(grpc.BidiStreamingServer[ChatMessage, ChatMessage]) error
-	mustEmbedUnimplementedNotesServiceServer()
+// Сервис администрирования: ключи API для сервисных клиентов (провайдер apikey в auth.providers)
+// Все методы доступны только администратору
+type AdminServiceServer interface {
+	// CreateAPIKey создает ключ API; секрет ключа возвращается только в ответе
+	CreateAPIKey(context.Context, *CreateAPIKeyRequest) (*CreateAPIKeyResponse, error)
+	// RevokeAPIKey отзывает ключ API: запросы с ним больше не принимаются
+	RevokeAPIKey(context.Context, *RevokeAPIKeyRequest) (*APIKey, error)
+	// ListAPIKeys возвращает все ключи API в порядке создания, включая отозванные
+	ListAPIKeys(context.Context, *ListAPIKeysRequest) (*ListAPIKeysResponse, error)
+	// GetPipeline возвращает действующие цепочки gRPC интерцепторов и HTTP middleware
+	// в порядке выполнения вместе с их настройками из конфигурации
+	GetPipeline(context.Context, *GetPipelineRequest) (*GetPipelineResponse, error)
+	// ListAuditEvents возвращает записи журнала аудита, начиная с последней: создание, изменение,
+	// удаление заметок и изменение доступа к ним, обращения к заметкам с парольной фразой
+	ListAuditEvents(context.Context, *ListAuditEventsRequest) (*ListAuditEventsResponse, error)
+	mustEmbedUnimplementedAdminServiceServer()
 }
 
-// UnimplementedNotesServiceServer must be embedded to have
+// UnimplementedAdminServiceServer must be embedded to have
 // forward compatible implementations.
 //
 // NOTE: this should be embedded by value instead of pointer to avoid a nil
 // pointer dereference when methods are called.
-type UnimplementedNotesServiceServer struct{}
+type UnimplementedAdminServiceServer struct{}
 
-func (UnimplementedNotesServiceServer) CreateNote(context.Context, *CreateNoteRequest) (*CreateNoteResponse, error) {
-	return nil, status.Error(codes.Unimplemented, "method CreateNote not implemented")
-}
-func (UnimplementedNotesServiceServer) GetNote(context.Context, *GetNoteRequest) (*GetNoteResponse, error) {
-	return nil, status.Error(codes.Unimplemented, "method GetNote not implemented")
-}
-func (UnimplementedNotesServiceServer) ListNotes(context.Context, *ListNotesRequest) (*ListNotesResponse, error) {
-	return nil, status.Error(codes.Unimplemented, "method ListNotes not implemented")
-}
-func (UnimplementedNotesServiceServer) UpdateNote(context.Context, *UpdateNoteRequest) (*UpdateNoteResponse, error) {
-	return nil, status.Error(codes.Unimplemented, "method UpdateNote not implemented")
+func (UnimplementedAdminServiceServer) CreateAPIKey(context.Context, *CreateAPIKeyRequest) (*CreateAPIKeyResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method CreateAPIKey not implemented")
 }
-func (UnimplementedNotesServiceServer) DeleteNote(context.Context, *DeleteNoteRequest) (*DeleteNoteResponse, error) {
-	return nil, status.Error(codes.Unimplemented, "method DeleteNote not implemented")
+func (UnimplementedAdminServiceServer) RevokeAPIKey(context.Context, *RevokeAPIKeyRequest) (*APIKey, error) {
+	return nil, status.Error(codes.Unimplemented, "method RevokeAPIKey not implemented")
 }
-func (UnimplementedNotesServiceServer) SubscribeToEvents(*SubscribeToEventsRequest, grpc.ServerStreamingServer[EventResponse]) error {
-	return status.Error(codes.Unimplemented, "method SubscribeToEvents not implemented")
+func (UnimplementedAdminServiceServer) ListAPIKeys(context.Context, *ListAPIKeysRequest) (*ListAPIKeysResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListAPIKeys not implemented")
 }
-func (UnimplementedNotesServiceServer) UploadMetrics(grpc.ClientStreamingServer[MetricRequest, SummaryResponse]) error {
-	return status.Error(codes.Unimplemented, "method UploadMetrics not implemented")
+func (UnimplementedAdminServiceServer) GetPipeline(context.Context, *GetPipelineRequest) (*GetPipelineResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetPipeline not implemented")
 }
-func (UnimplementedNotesServiceServer) Chat(grpc.BidiStreamingServer[ChatMessage, ChatMessage]) error {
-	return status.Error(codes.Unimplemented, "method Chat not implemented")
+func (UnimplementedAdminServiceServer) ListAuditEvents(context.Context, *ListAuditEventsRequest) (*ListAuditEventsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListAuditEvents not implemented")
 }
-func (UnimplementedNotesServiceServer) mustEmbedUnimplementedNotesServiceServer() {}
-func (UnimplementedNotesServiceServer) testEmbeddedByValue()                      {}
+func (UnimplementedAdminServiceServer) mustEmbedUnimplementedAdminServiceServer() {}
+func (UnimplementedAdminServiceServer) testEmbeddedByValue()                      {}
 
-// UnsafeNotesServiceServer may be embedded to opt out of forward compatibility for this service.
-// Use of this interface is not recommended, as added methods to NotesServiceServer will
+// UnsafeAdminServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to AdminServiceServer will
 // result in compilation errors.
-type UnsafeNotesServiceServer interface {
-	mustEmbedUnimplementedNotesServiceServer()
+type UnsafeAdminServiceServer interface {
+	mustEmbedUnimplementedAdminServiceServer()
 }
 
-func RegisterNotesServiceServer(s grpc.ServiceRegistrar, srv NotesServiceServer) {
-	// If the following call panics, it indicates UnimplementedNotesServiceServer was
+func RegisterAdminServiceServer(s grpc.ServiceRegistrar, srv AdminServiceServer) {
+	// If the following call panics, it indicates UnimplementedAdminServiceServer was
 	// embedded by pointer and is nil.  This will cause panics if an
 	// unimplemented method is ever invoked, so we test this at initialization
 	// time to prevent it from happening at runtime later due to I/O.
 	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
 		t.testEmbeddedByValue()
 	}
-	s.RegisterService(&NotesService_ServiceDesc, srv)
+	s.RegisterService(&AdminService_ServiceDesc, srv)
 }
 
-func _NotesService_CreateNote_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(CreateNoteRequest)
+func _AdminService_CreateAPIKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateAPIKeyRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(NotesServiceServer).CreateNote(ctx, in)
+		return srv.(AdminServiceServer).CreateAPIKey(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: NotesService_CreateNote_FullMethodName,
+		FullMethod: AdminService_CreateAPIKey_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(NotesServiceServer).CreateNote(ctx, req.(*CreateNoteRequest))
+		return srv.(AdminServiceServer).CreateAPIKey(ctx, req.(*CreateAPIKeyRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _NotesService_GetNote_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(GetNoteRequest)
+func _AdminService_RevokeAPIKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RevokeAPIKeyRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(NotesServiceServer).GetNote(ctx, in)
+		return srv.(AdminServiceServer).RevokeAPIKey(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: NotesService_GetNote_FullMethodName,
+		FullMethod: AdminService_RevokeAPIKey_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(NotesServiceServer).GetNote(ctx, req.(*GetNoteRequest))
+		return srv.(AdminServiceServer).RevokeAPIKey(ctx, req.(*RevokeAPIKeyRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _NotesService_ListNotes_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ListNotesRequest)
+func _AdminService_ListAPIKeys_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListAPIKeysRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(NotesServiceServer).ListNotes(ctx, in)
+		return srv.(AdminServiceServer).ListAPIKeys(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: NotesService_ListNotes_FullMethodName,
+		FullMethod: AdminService_ListAPIKeys_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(NotesServiceServer).ListNotes(ctx, req.(*ListNotesRequest))
+		return srv.(AdminServiceServer).ListAPIKeys(ctx, req.(*ListAPIKeysRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _NotesService_UpdateNote_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(UpdateNoteRequest)
+func _AdminService_GetPipeline_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetPipelineRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(NotesServiceServer).UpdateNote(ctx, in)
+		return srv.(AdminServiceServer).GetPipeline(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: NotesService_UpdateNote_FullMethodName,
+		FullMethod: AdminService_GetPipeline_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(NotesServiceServer).UpdateNote(ctx, req.(*UpdateNoteRequest))
+		return srv.(AdminServiceServer).GetPipeline(ctx, req.(*GetPipelineRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _NotesService_DeleteNote_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(DeleteNoteRequest)
+func _AdminService_ListAuditEvents_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListAuditEventsRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(NotesServiceServer).DeleteNote(ctx, in)
+		return srv.(AdminServiceServer).ListAuditEvents(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: NotesService_DeleteNote_FullMethodName,
+		FullMethod: AdminService_ListAuditEvents_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(NotesServiceServer).DeleteNote(ctx, req.(*DeleteNoteRequest))
+		return srv.(AdminServiceServer).ListAuditEvents(ctx, req.(*ListAuditEventsRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _NotesService_SubscribeToEvents_Handler(srv interface{}, stream grpc.ServerStream) error {
-	m := new(SubscribeToEventsRequest)
-	if err := stream.RecvMsg(m); err != nil {
-		return err
-	}
-	return srv.(NotesServiceServer).SubscribeToEvents(m, &grpc.GenericServerStream[SubscribeToEventsRequest, EventResponse]{ServerStream: stream})
-}
-
-// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
-type NotesService_SubscribeToEventsServer = grpc.ServerStreamingServer[EventResponse]
-
-func _NotesService_UploadMetrics_Handler(srv interface{}, stream grpc.ServerStream) error {
-	return srv.(NotesServiceServer).UploadMetrics(&grpc.GenericServerStream[MetricRequest, SummaryResponse]{ServerStream: stream})
-}
-
-// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
-type NotesService_UploadMetricsServer = grpc.ClientStreamingServer[MetricRequest, SummaryResponse]
-
-func _NotesService_Chat_Handler(srv interface{}, stream grpc.ServerStream) error {
-	return srv.(NotesServiceServer).Chat(&grpc.GenericServerStream[ChatMessage, ChatMessage]{ServerStream: stream})
-}
-
-// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
-type NotesService_ChatServer = grpc.BidiStreamingServer[ChatMessage, ChatMessage]
-
-// NotesService_ServiceDesc is the grpc.ServiceDesc for NotesService service.
+// AdminService_ServiceDesc is the grpc.ServiceDesc for AdminService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
-var NotesService_ServiceDesc = grpc.ServiceDesc{
-	ServiceName: "notes.v1.NotesService",
-	HandlerType: (*NotesServiceServer)(nil),
+var AdminService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "notes.v1.AdminService",
+	HandlerType: (*AdminServiceServer)(nil),
 	Methods: []grpc.MethodDesc{
 		{
-			MethodName: "CreateNote",
-			Handler:    _NotesService_CreateNote_Handler,
-		},
-		{
-			MethodName: "GetNote",
-			Handler:    _NotesService_GetNote_Handler,
-		},
-		{
-			MethodName: "ListNotes",
-			Handler:    _NotesService_ListNotes_Handler,
-		},
-		{
-			MethodName: "UpdateNote",
-			Handler:    _NotesService_UpdateNote_Handler,
+			MethodName: "CreateAPIKey",
+			Handler:    _AdminService_CreateAPIKey_Handler,
 		},
 		{
-			MethodName: "DeleteNote",
-			Handler:    _NotesService_DeleteNote_Handler,
+			MethodName: "RevokeAPIKey",
+			Handler:    _AdminService_RevokeAPIKey_Handler,
 		},
-	},
-	Streams: []grpc.StreamDesc{
 		{
-			StreamName:    "SubscribeToEvents",
-			Handler:       _NotesService_SubscribeToEvents_Handler,
-			ServerStreams: true,
+			MethodName: "ListAPIKeys",
+			Handler:    _AdminService_ListAPIKeys_Handler,
 		},
 		{
-			StreamName:    "UploadMetrics",
-			Handler:       _NotesService_UploadMetrics_Handler,
-			ClientStreams: true,
+			MethodName: "GetPipeline",
+			Handler:    _AdminService_GetPipeline_Handler,
 		},
 		{
-			StreamName:    "Chat",
-			Handler:       _NotesService_Chat_Handler,
-			ServerStreams: true,
-			ClientStreams: true,
+			MethodName: "ListAuditEvents",
+			Handler:    _AdminService_ListAuditEvents_Handler,
 		},
 	},
+	Streams:  []grpc.StreamDesc{},
 	Metadata: "proto/notes/v1/notes.proto",
 }
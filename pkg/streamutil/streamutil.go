@@ -0,0 +1,210 @@
+// Package streamutil содержит общие помощники gRPC стримов для хэндлеров сервера и клиентов:
+// цикл чтения сообщений до io.EOF, отправку с таймаутом, тикер heartbeat и группу горутин
+// чтения и отправки, которая возвращает первую ошибку
+//
+//	group, ctx := streamutil.WithContext(stream.Context())
+//	group.Go(func() error {
+//		return streamutil.RecvLoop(ctx, stream, func(msg *notesv1.ChatMessage) error {
+//			log.Printf("Received: %s", msg.GetCorrelationId())
+//			return nil
+//		})
+//	})
+//	group.Go(func() error {
+//		defer stream.CloseSend()
+//		return stream.Send(msg)
+//	})
+//	err := group.Wait()
+package streamutil
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ErrStop возвращается обработчиком RecvLoop, чтобы закончить чтение без ошибки
+var ErrStop = errors.New("stop receiving")
+
+// Receiver читающая сторона стрима (сгенерированные серверные и клиентские стримы)
+type Receiver[T any] interface {
+	Recv() (T, error)
+}
+
+// Sender отправляющая сторона стрима
+type Sender[T any] interface {
+	Send(T) error
+}
+
+// RecvFunc адаптер функции к Receiver, например для обработки отдельных ошибок Recv до RecvLoop
+type RecvFunc[T any] func() (T, error)
+
+// Recv вызывает f
+func (f RecvFunc[T]) Recv() (T, error) {
+	return f()
+}
+
+// RecvLoop читает сообщения и передает каждое в handle, пока отправитель не закроет стрим (io.EOF)
+// Возвращает nil после io.EOF или ErrStop от handle, ошибку handle и ошибку Recv без изменений,
+// а отмену ctx - статусом CANCELED или DEADLINE_EXCEEDED. ctx проверяется перед каждым Recv:
+// заблокированный Recv прерывает только отмена контекста самого стрима
+func RecvLoop[T any](ctx context.Context, r Receiver[T], handle func(T) error) error {
+	for {
+		if err := ctx.Err(); err != nil {
+			return contextError(err)
+		}
+		msg, err := r.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := handle(msg); err != nil {
+			if errors.Is(err, ErrStop) {
+				return nil
+			}
+			return err
+		}
+	}
+}
+
+// Received результат одного Recv из RecvChan
+type Received[T any] struct {
+	Msg T
+	Err error
+}
+
+// RecvChan читает сообщения в отдельной горутине и передает их в канал, чтобы ожидать их в select
+// вместе с тикерами и контекстами. Канал закрывается после io.EOF и после другой ошибки Recv,
+// которая передается последним значением. При отмене ctx горутина завершается без закрытия канала
+func RecvChan[T any](ctx context.Context, r Receiver[T]) <-chan Received[T] {
+	received := make(chan Received[T])
+	go func() {
+		for {
+			msg, err := r.Recv()
+			if err == io.EOF {
+				close(received)
+				return
+			}
+			select {
+			case received <- Received[T]{Msg: msg, Err: err}:
+			case <-ctx.Done():
+				return
+			}
+			if err != nil {
+				close(received)
+				return
+			}
+		}
+	}()
+	return received
+}
+
+// SendWithTimeout отправляет msg и ждет завершения Send не дольше timeout (timeout <= 0 - без ограничения)
+// Send не прерывается: после ошибки таймаута стрим нельзя использовать для отправки,
+// хэндлер или клиент должен завершить его, тогда зависший Send вернется
+func SendWithTimeout[T any](ctx context.Context, s Sender[T], msg T, timeout time.Duration) error {
+	if timeout <= 0 {
+		return s.Send(msg)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- s.Send(msg)
+	}()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case err := <-done:
+		return err
+	case <-timer.C:
+		return status.Errorf(codes.DeadlineExceeded, "stream send timed out after %s", timeout)
+	case <-ctx.Done():
+		return contextError(ctx.Err())
+	}
+}
+
+// Heartbeat возвращает канал тикера с периодом interval и функцию его остановки
+// При interval <= 0 канал nil: в select он никогда не срабатывает, и heartbeat отключается без отдельной ветки
+func Heartbeat(interval time.Duration) (<-chan time.Time, func()) {
+	if interval <= 0 {
+		return nil, func() {}
+	}
+	ticker := time.NewTicker(interval)
+	return ticker.C, ticker.Stop
+}
+
+// Merge возвращает контекст ctx, который отменяется также при отмене other
+// (например, контекст стрима и контекст сервера, отменяемый при graceful shutdown)
+func Merge(ctx, other context.Context) (context.Context, context.CancelFunc) {
+	merged, cancel := context.WithCancel(ctx)
+	stop := context.AfterFunc(other, cancel)
+	return merged, func() {
+		stop()
+		cancel()
+	}
+}
+
+// Group запускает горутины чтения и отправки одного стрима
+// Wait возвращает первую ошибку сразу, не дожидаясь остальных горутин, а без ошибок - после завершения всех
+type Group struct {
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+	once   sync.Once
+	err    error
+	failed chan struct{}
+}
+
+// WithContext создает группу и контекст, который отменяется при первой ошибке или после Wait
+func WithContext(ctx context.Context) (*Group, context.Context) {
+	ctx, cancel := context.WithCancel(ctx)
+	return &Group{cancel: cancel, failed: make(chan struct{})}, ctx
+}
+
+// Go запускает fn в отдельной горутине
+func (g *Group) Go(fn func() error) {
+	g.wg.Add(1)
+	go func() {
+		defer g.wg.Done()
+		if err := fn(); err != nil {
+			g.once.Do(func() {
+				g.err = err
+				g.cancel()
+				close(g.failed)
+			})
+		}
+	}()
+}
+
+// Wait ожидает первую ошибку или завершение всех горутин группы
+func (g *Group) Wait() error {
+	done := make(chan struct{})
+	go func() {
+		g.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-g.failed:
+	case <-done:
+	}
+	g.cancel()
+
+	select {
+	case <-g.failed:
+		return g.err
+	default:
+		return nil
+	}
+}
+
+// contextError переводит ошибку контекста в статус gRPC
+func contextError(err error) error {
+	return status.FromContextError(err).Err()
+}
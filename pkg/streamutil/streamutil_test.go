@@ -0,0 +1,177 @@
+package streamutil
+
+import (
+	"context"
+	"errors"
+	"io"
+	"slices"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// sliceReceiver возвращает сообщения по порядку, затем err (по умолчанию io.EOF)
+type sliceReceiver struct {
+	msgs []int
+	err  error
+}
+
+func (r *sliceReceiver) Recv() (int, error) {
+	if len(r.msgs) == 0 {
+		if r.err != nil {
+			return 0, r.err
+		}
+		return 0, io.EOF
+	}
+	msg := r.msgs[0]
+	r.msgs = r.msgs[1:]
+	return msg, nil
+}
+
+// blockingSender блокирует Send до закрытия release
+type blockingSender struct {
+	release chan struct{}
+}
+
+func (s blockingSender) Send(int) error {
+	<-s.release
+	return nil
+}
+
+func TestRecvLoop(t *testing.T) {
+	ctx := context.Background()
+
+	var got []int
+	collect := func(msg int) error {
+		got = append(got, msg)
+		return nil
+	}
+	if err := RecvLoop(ctx, &sliceReceiver{msgs: []int{1, 2, 3}}, collect); err != nil || !slices.Equal(got, []int{1, 2, 3}) {
+		t.Errorf("Expected all messages until EOF, got %v, %v", got, err)
+	}
+
+	got = nil
+	err := RecvLoop(ctx, &sliceReceiver{msgs: []int{1, 2, 3}}, func(msg int) error {
+		got = append(got, msg)
+		if msg == 2 {
+			return ErrStop
+		}
+		return nil
+	})
+	if err != nil || !slices.Equal(got, []int{1, 2}) {
+		t.Errorf("Expected ErrStop to end the loop without error, got %v, %v", got, err)
+	}
+
+	handlerErr := status.Error(codes.InvalidArgument, "bad message")
+	if err := RecvLoop(ctx, &sliceReceiver{msgs: []int{1}}, func(int) error { return handlerErr }); err != handlerErr {
+		t.Errorf("Expected handler error unchanged, got %v", err)
+	}
+
+	recvErr := status.Error(codes.Unavailable, "connection lost")
+	if err := RecvLoop(ctx, &sliceReceiver{err: recvErr}, collect); err != recvErr {
+		t.Errorf("Expected Recv error unchanged, got %v", err)
+	}
+
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	if err := RecvLoop(canceled, &sliceReceiver{msgs: []int{1}}, collect); status.Code(err) != codes.Canceled {
+		t.Errorf("Expected Canceled for a canceled context, got %v", err)
+	}
+}
+
+func TestRecvChan(t *testing.T) {
+	var got []int
+	for received := range RecvChan(context.Background(), &sliceReceiver{msgs: []int{1, 2}}) {
+		if received.Err != nil {
+			t.Fatalf("Unexpected error: %v", received.Err)
+		}
+		got = append(got, received.Msg)
+	}
+	if !slices.Equal(got, []int{1, 2}) {
+		t.Errorf("Expected messages until the channel is closed on EOF, got %v", got)
+	}
+
+	recvErr := errors.New("connection lost")
+	var last Received[int]
+	for received := range RecvChan(context.Background(), &sliceReceiver{msgs: []int{1}, err: recvErr}) {
+		last = received
+	}
+	if last.Err != recvErr {
+		t.Errorf("Expected Recv error as the last value, got %+v", last)
+	}
+}
+
+func TestSendWithTimeout(t *testing.T) {
+	sender := blockingSender{release: make(chan struct{})}
+	defer close(sender.release)
+
+	err := SendWithTimeout(context.Background(), sender, 1, 10*time.Millisecond)
+	if status.Code(err) != codes.DeadlineExceeded {
+		t.Errorf("Expected DeadlineExceeded for a blocked Send, got %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := SendWithTimeout(ctx, sender, 1, time.Minute); status.Code(err) != codes.Canceled {
+		t.Errorf("Expected Canceled for a canceled context, got %v", err)
+	}
+}
+
+func TestHeartbeat(t *testing.T) {
+	if ticks, stop := Heartbeat(0); ticks != nil {
+		t.Error("Expected nil channel for a disabled heartbeat")
+	} else {
+		stop()
+	}
+
+	ticks, stop := Heartbeat(time.Millisecond)
+	defer stop()
+	select {
+	case <-ticks:
+	case <-time.After(time.Second):
+		t.Error("Expected a heartbeat tick")
+	}
+}
+
+func TestGroup(t *testing.T) {
+	group, ctx := WithContext(context.Background())
+	group.Go(func() error { return nil })
+	if err := group.Wait(); err != nil {
+		t.Errorf("Expected nil without errors, got %v", err)
+	}
+	if ctx.Err() == nil {
+		t.Error("Expected the group context to be canceled after Wait")
+	}
+
+	// Первая ошибка возвращается, не дожидаясь горутины, заблокированной в Recv
+	blocked := make(chan struct{})
+	defer close(blocked)
+	sendErr := errors.New("send failed")
+	group, ctx = WithContext(context.Background())
+	group.Go(func() error {
+		<-blocked
+		return nil
+	})
+	group.Go(func() error { return sendErr })
+	if err := group.Wait(); err != sendErr {
+		t.Errorf("Expected the first error, got %v", err)
+	}
+	if ctx.Err() == nil {
+		t.Error("Expected the group context to be canceled by the error")
+	}
+}
+
+func TestMerge(t *testing.T) {
+	server, stopServer := context.WithCancel(context.Background())
+	ctx, cancel := Merge(context.Background(), server)
+	defer cancel()
+
+	stopServer()
+	select {
+	case <-ctx.Done():
+	case <-time.After(time.Second):
+		t.Error("Expected the merged context to be canceled with the other context")
+	}
+}